@@ -130,16 +130,17 @@ type ActionHelpOptions struct {
 type RootLevelHelpOption string
 
 const (
-	CmdGroupNone    RootLevelHelpOption = ""
-	CmdGroupConfig  RootLevelHelpOption = "Configure and develop your app"
-	CmdGroupManage  RootLevelHelpOption = "Manage Azure resources and app deployments"
-	CmdGroupMonitor RootLevelHelpOption = "Monitor, test and release your app"
-	CmdGroupAbout   RootLevelHelpOption = "About, help and upgrade"
+	CmdGroupNone       RootLevelHelpOption = ""
+	CmdGroupConfig     RootLevelHelpOption = "Configure and develop your app"
+	CmdGroupManage     RootLevelHelpOption = "Manage Azure resources and app deployments"
+	CmdGroupMonitor    RootLevelHelpOption = "Monitor, test and release your app"
+	CmdGroupAbout      RootLevelHelpOption = "About, help and upgrade"
+	CmdGroupExtensions RootLevelHelpOption = "Extensions"
 )
 
 func GetGroupAnnotations() []RootLevelHelpOption {
 	return []RootLevelHelpOption{
-		CmdGroupConfig, CmdGroupManage, CmdGroupMonitor, CmdGroupAbout,
+		CmdGroupConfig, CmdGroupManage, CmdGroupMonitor, CmdGroupAbout, CmdGroupExtensions,
 	}
 }
 
@@ -182,6 +183,8 @@ type ActionDescriptorOptions struct {
 	DefaultFormat output.Format
 	// Whether or not telemetry should be disabled for the current action
 	DisableTelemetry bool
+	// Whether the action can't run without reaching Azure or another service, it fails in offline mode
+	RequiresNetwork bool
 	// The logic that produces the command help
 	HelpOptions ActionHelpOptions
 	// Defines grouping options for the command
@@ -18,19 +18,25 @@ func authActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 	})
 
 	group.Add("token", &actions.ActionDescriptorOptions{
-		Command:        newAuthTokenCmd(),
-		FlagsResolver:  newAuthTokenFlags,
-		ActionResolver: newAuthTokenAction,
-		OutputFormats:  []output.Format{output.JsonFormat},
-		DefaultFormat:  output.NoneFormat,
+		Command:         newAuthTokenCmd(),
+		FlagsResolver:   newAuthTokenFlags,
+		ActionResolver:  newAuthTokenAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.RawFormat},
+		DefaultFormat:   output.JsonFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdAuthTokenHelpDescription,
+			Footer:      getCmdAuthTokenHelpFooter,
+		},
 	})
 
 	group.Add("login", &actions.ActionDescriptorOptions{
-		Command:        newLoginCmd("auth"),
-		FlagsResolver:  newAuthLoginFlags,
-		ActionResolver: newAuthLoginAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-		DefaultFormat:  output.NoneFormat,
+		Command:         newLoginCmd("auth"),
+		FlagsResolver:   newAuthLoginFlags,
+		ActionResolver:  newAuthLoginAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:   output.NoneFormat,
 	})
 
 	group.Add("logout", &actions.ActionDescriptorOptions{
@@ -132,7 +132,9 @@ func (lf *loginFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandO
 		&lf.federatedTokenProvider,
 		cFederatedCredentialProviderFlagName,
 		"",
-		"The provider to use to acquire a federated token to authenticate with.")
+		"The provider to use to acquire a federated token to authenticate with. Supports github, "+
+			"within GitHub Actions workflows with the id-token: write permission, and gitlab, within GitLab CI/CD jobs "+
+			"declaring a GITLAB_OIDC_TOKEN ID token.")
 	local.StringVar(
 		&lf.tenantID,
 		"tenant-id",
@@ -172,6 +174,11 @@ func newLoginCmd(parent string) *cobra.Command {
 		
 		To log in as a service principal, pass --client-id and --tenant-id as well as one of: --client-secret, 
 		--client-certificate, or --federated-credential-provider.
+
+		To log in from a GitHub Actions workflow without storing a secret, configure a federated credential for
+		the workflow on the service principal and pass --federated-credential-provider github. From a GitLab CI/CD
+		job, declare a GITLAB_OIDC_TOKEN ID token with the api://AzureADTokenExchange audience and pass
+		--federated-credential-provider gitlab.
 		`),
 		Annotations: map[string]string{
 			loginCmdParentAnnotation: parent,
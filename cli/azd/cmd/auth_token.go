@@ -37,14 +37,21 @@ func newAuthTokenFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions
 
 func newAuthTokenCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:    "token --output json",
-		Hidden: true,
+		Use:   "token",
+		Short: "Print an access token of the logged in account.",
+		Args:  cobra.NoArgs,
 	}
 }
 
 func (f *authTokenFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	f.global = global
-	local.StringArrayVar(&f.scopes, "scope", nil, "The scope to use when requesting an access token")
+	local.StringArrayVar(
+		&f.scopes,
+		"scope",
+		nil,
+		"The scope to use when requesting an access token, ex. https://graph.microsoft.com/.default."+
+			" (Default: Azure Resource Manager)",
+	)
 	local.StringVar(&f.tenantID, "tenant-id", "", "The tenant id to use when requesting an access token.")
 }
 
@@ -164,6 +171,11 @@ func (a *authTokenAction) Run(ctx context.Context) (*actions.ActionResult, error
 		return nil, fmt.Errorf("fetching token: %w", err)
 	}
 
+	// The raw token can be captured by scripts as is, ex. TOKEN=$(azd auth token --output raw)
+	if a.formatter.Kind() == output.RawFormat {
+		return nil, a.formatter.Format(token.Token, a.writer, nil)
+	}
+
 	res := contracts.AuthTokenResult{
 		Token:     token.Token,
 		ExpiresOn: contracts.RFC3339Time(token.ExpiresOn),
@@ -171,3 +183,29 @@ func (a *authTokenAction) Run(ctx context.Context) (*actions.ActionResult, error
 
 	return nil, a.formatter.Format(res, a.writer, nil)
 }
+
+func getCmdAuthTokenHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Print an access token of the account logged in with `azd auth login`, so scripts can call Azure APIs"+
+			" without logging in with another tool.",
+		[]string{
+			formatHelpNote("The token is requested for the tenant of the subscription of the current environment, or" +
+				" of AZURE_SUBSCRIPTION_ID, unless --tenant-id is set."),
+			formatHelpNote(fmt.Sprintf("Use %s to print the token only, and %s to print its expiration as well.",
+				output.WithHighLightFormat("--output raw"),
+				output.WithHighLightFormat("--output json"),
+			)),
+		})
+}
+
+func getCmdAuthTokenHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Print an access token for Azure Resource Manager.": output.WithHighLightFormat(
+			"azd auth token --output raw"),
+		"Print an access token for Microsoft Graph.": output.WithHighLightFormat(
+			"azd auth token --scope https://graph.microsoft.com/.default --output raw"),
+		"Print an access token of a tenant with its expiration.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd auth token --tenant-id"),
+			output.WithWarningFormat("<tenantID>")),
+	})
+}
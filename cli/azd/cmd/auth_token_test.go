@@ -296,6 +296,34 @@ func TestAuthTokenCustomScopes(t *testing.T) {
 	require.True(t, wasCalled, "GetToken was not called on the credential")
 }
 
+func TestAuthTokenRaw(t *testing.T) {
+	buf := &bytes.Buffer{}
+	scopes := []string{"https://graph.microsoft.com/.default"}
+
+	token := authTokenFn(func(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+		require.ElementsMatch(t, scopes, options.Scopes)
+		return azcore.AccessToken{
+			Token:     "ABC123",
+			ExpiresOn: time.Unix(1669153000, 0).UTC(),
+		}, nil
+	})
+
+	a := newAuthTokenAction(
+		credentialProviderForTokenFn(token),
+		&output.RawFormatter{},
+		buf,
+		&authTokenFlags{
+			scopes: scopes,
+		},
+		func() (*environment.Environment, error) { return nil, fmt.Errorf("not an azd env directory") },
+		&mockSubscriptionTenantResolver{},
+	)
+
+	_, err := a.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ABC123\n", buf.String())
+}
+
 func TestAuthTokenFailure(t *testing.T) {
 	token := authTokenFn(func(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
 		return azcore.AccessToken{}, errors.New("could not fetch token")
@@ -9,6 +9,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -68,6 +69,7 @@ type buildAction struct {
 	writer                   io.Writer
 	middlewareRunner         middleware.MiddlewareContext
 	restoreActionInitializer actions.ActionInitializer[*restoreAction]
+	lifecycleEvents          *input.LifecycleEvents
 }
 
 func newBuildAction(
@@ -81,7 +83,7 @@ func newBuildAction(
 	writer io.Writer,
 	middlewareRunner middleware.MiddlewareContext,
 	restoreActionInitializer actions.ActionInitializer[*restoreAction],
-
+	lifecycleEvents *input.LifecycleEvents,
 ) actions.Action {
 	return &buildAction{
 		flags:                    flags,
@@ -94,6 +96,7 @@ func newBuildAction(
 		writer:                   writer,
 		middlewareRunner:         middlewareRunner,
 		restoreActionInitializer: restoreActionInitializer,
+		lifecycleEvents:          lifecycleEvents,
 	}
 }
 
@@ -148,10 +151,6 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, err
 	}
 
-	if err := ba.projectManager.Initialize(ctx, ba.projectConfig); err != nil {
-		return nil, err
-	}
-
 	buildResults := map[string]*project.ServiceBuildResult{}
 
 	for _, svc := range ba.projectConfig.GetServicesStable() {
@@ -163,24 +162,37 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		// 2. This service is not the one the user specified
 		if targetServiceName != "" && targetServiceName != svc.Name {
 			ba.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			ba.lifecycleEvents.Emit(
+				"service build", contracts.LifecyclePhaseSkipped, contracts.LifecycleEvent{Service: svc.Name})
 			continue
 		}
 
+		ba.lifecycleEvents.Emit("service build", contracts.LifecyclePhaseStart, contracts.LifecycleEvent{Service: svc.Name})
 		buildTask := ba.serviceManager.Build(ctx, svc, nil)
 		go func() {
 			for buildProgress := range buildTask.Progress() {
 				progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
 				ba.console.ShowSpinner(ctx, progressMessage, input.Step)
+				ba.lifecycleEvents.Emit("service build", contracts.LifecyclePhaseProgress, contracts.LifecycleEvent{
+					Service: svc.Name,
+					Message: buildProgress.Message,
+					Percent: buildProgress.Percent,
+				})
 			}
 		}()
 
 		buildResult, err := buildTask.Await()
 		if err != nil {
 			ba.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			ba.lifecycleEvents.Emit("service build", contracts.LifecyclePhaseFailed, contracts.LifecycleEvent{
+				Service: svc.Name,
+				Error:   err.Error(),
+			})
 			return nil, err
 		}
 
 		ba.console.StopSpinner(ctx, stepMessage, input.StepDone)
+		ba.lifecycleEvents.Emit("service build", contracts.LifecyclePhaseDone, contracts.LifecycleEvent{Service: svc.Name})
 		buildResults[svc.Name] = buildResult
 
 		// report build outputs
@@ -74,6 +74,18 @@ func generateCmdHelp(
 	)
 }
 
+// setCmdHelp sets the help of the command to the one from `generateCmdHelp`. The help template is only generated when
+// the help is displayed, so building the command tree doesn't pay for generating the help of every command.
+func setCmdHelp(cmd *cobra.Command, options generateCmdHelpOptions) {
+	// A command without a parent nor a help func returns the default cobra help func
+	defaultHelpFunc := (&cobra.Command{}).HelpFunc()
+
+	cmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		cmd.SetHelpTemplate(generateCmdHelp(cmd, options))
+		defaultHelpFunc(c, args)
+	})
+}
+
 // getCmdHelpDefaultDescription provides the default implementation for displaying the help description section.
 func getCmdHelpDefaultDescription(cmd *cobra.Command) string {
 	return generateCmdHelpDescription(cmd.Short, nil)
@@ -83,7 +95,8 @@ func getCmdHelpDefaultDescription(cmd *cobra.Command) string {
 func getCmdHelpDefaultUsage(cmd *cobra.Command) string {
 	return fmt.Sprintf("%s\n  %s\n\n",
 		output.WithBold(output.WithUnderline("Usage")),
-		"{{if .Runnable}}{{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}{{.CommandPath}} [command]{{end}}",
+		"{{if .Runnable}}{{.UseLine}}{{end}}{{if and .Runnable .HasAvailableSubCommands}}\n  {{end}}"+
+			"{{if .HasAvailableSubCommands}}{{.CommandPath}} [command]{{end}}",
 	)
 }
 
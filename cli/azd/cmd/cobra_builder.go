@@ -58,8 +58,8 @@ func (cb *CobraBuilder) BuildCommand(descriptor *actions.ActionDescriptor) (*cob
 		}
 	}
 
-	// Configure action resolver for leaf commands
-	if !cmd.HasSubCommands() {
+	// Configure action resolver for leaf commands & for commands that run an action besides grouping sub commands
+	if !cmd.HasSubCommands() || descriptor.Options.ActionResolver != nil {
 		if err := cb.configureActionResolver(cmd, descriptor); err != nil {
 			return nil, err
 		}
@@ -131,6 +131,7 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 			Aliases:     cmd.Aliases,
 			Flags:       cmd.Flags(),
 			Args:        args,
+			Annotations: cmd.Annotations,
 		}
 
 		// Run the middleware chain with action
@@ -142,6 +143,11 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 
 		// TODO: Consider refactoring to move the UX writing to a middleware
 		invokeErr := cb.container.Invoke(func(console input.Console) {
+			// With events output the result of the action is written by the output middleware
+			if formatter := console.GetFormatter(); formatter != nil && formatter.Kind() == output.EventsFormat {
+				return
+			}
+
 			var displayResult *ux.ActionResult
 			if actionResult != nil && actionResult.Message != nil {
 				displayResult = &ux.ActionResult{
@@ -197,8 +203,11 @@ func (cb *CobraBuilder) bindCommand(cmd *cobra.Command, descriptor *actions.Acti
 	cmd.Flags().BoolP("help", "h", false, fmt.Sprintf("Gets help for %s.", cmd.Name()))
 
 	// Consistently registers output formats for the descriptor
+	// Every action supports JSON output, with the result of the action written as the final event
 	if len(descriptor.Options.OutputFormats) > 0 {
 		output.AddOutputParam(cmd, descriptor.Options.OutputFormats, descriptor.Options.DefaultFormat)
+	} else if descriptor.Options.ActionResolver != nil {
+		output.AddOutputParam(cmd, []output.Format{output.JsonFormat, output.NoneFormat}, output.NoneFormat)
 	}
 
 	// Create, register and bind flags when required
@@ -258,13 +267,13 @@ func (cb *CobraBuilder) bindCommand(cmd *cobra.Command, descriptor *actions.Acti
 
 	// `generateCmdHelp` sets a default help section when `descriptor.Options.HelpOptions` is nil.
 	// This call ensures all commands gets the same help formatting.
-	cmd.SetHelpTemplate(generateCmdHelp(cmd, generateCmdHelpOptions{
+	setCmdHelp(cmd, generateCmdHelpOptions{
 		Description: cmdHelpGenerator(descriptor.Options.HelpOptions.Description),
 		Usage:       cmdHelpGenerator(descriptor.Options.HelpOptions.Usage),
 		Commands:    cmdHelpGenerator(descriptor.Options.HelpOptions.Commands),
 		Flags:       cmdHelpGenerator(descriptor.Options.HelpOptions.Flags),
 		Footer:      cmdHelpGenerator(descriptor.Options.HelpOptions.Footer),
-	}))
+	})
 
 	return nil
 }
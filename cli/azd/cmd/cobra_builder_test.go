@@ -176,6 +176,42 @@ func Test_BuildAndRunActionWithNestedAndConditionalMiddleware(t *testing.T) {
 	require.False(t, middlewareBRan)
 }
 
+func Test_BuildAndRunActionWithSubCommands(t *testing.T) {
+	container := ioc.NewNestedContainer(nil)
+	setup(container)
+
+	root := actions.NewActionDescriptor("root", &actions.ActionDescriptorOptions{
+		ActionResolver: newTestAction,
+		FlagsResolver:  newTestFlags,
+	})
+
+	childRan := false
+	root.Add("child", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			RunE: func(cmd *cobra.Command, args []string) error {
+				childRan = true
+				return nil
+			},
+		},
+	})
+
+	builder := NewCobraBuilder(container)
+	cmd, err := builder.BuildCommand(root)
+	require.NoError(t, err)
+
+	actionRan := false
+	ctx := context.WithValue(context.Background(), actionName, &actionRan)
+
+	cmd.SetArgs([]string{"-r"})
+	require.NoError(t, cmd.ExecuteContext(ctx))
+	require.True(t, actionRan)
+	require.False(t, childRan)
+
+	cmd.SetArgs([]string{"child"})
+	require.NoError(t, cmd.ExecuteContext(ctx))
+	require.True(t, childRan)
+}
+
 func Test_BuildCommandsWithAutomaticHelpAndOutputFlags(t *testing.T) {
 	container := ioc.NewNestedContainer(nil)
 
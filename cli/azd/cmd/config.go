@@ -346,6 +346,22 @@ func getCmdConfigHelpFooter(c *cobra.Command) string {
 		"Set the default Azure deployment location.": fmt.Sprintf("%s %s",
 			output.WithHighLightFormat("azd config set defaults.location"),
 			output.WithWarningFormat("<location>")),
+		"Reuse cached Azure Resource Manager read calls between commands.": output.WithHighLightFormat(
+			"azd config set cache.arm.persist true"),
+		"Only send the telemetry of the commands that failed.": output.WithHighLightFormat(
+			"azd config set telemetry.level errors-only"),
+		"Export telemetry to your OpenTelemetry collector as well as Microsoft.": output.WithHighLightFormat(
+			"azd config set telemetry.exporter azd,otlp-grpc=https://collector.contoso.com:4317"),
+		"Send 10% of the HTTP request and tool spans of the commands, ex. in heavy CI usage.": output.WithHighLightFormat(
+			"azd config set telemetry.sampling.rate 0.1"),
+		"Retry commands failing with transient Azure errors up to 5 times.": output.WithHighLightFormat(
+			"azd config set retry.maxAttempts 5"),
+		"Fail `azd provision` if it doesn't complete within an hour.": output.WithHighLightFormat(
+			"azd config set timeout.provision 1h"),
+		"Trace the HTTP requests sent to Azure by every command.": output.WithHighLightFormat(
+			"azd config set debug.http true"),
+		"Disable all network calls and telemetry, ex. in air-gapped environments or CI.": output.WithHighLightFormat(
+			"azd config set network.offline true"),
 	})
 }
 
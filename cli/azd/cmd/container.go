@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
@@ -13,28 +16,38 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/devbox"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/installer"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cargo"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/devtunnel"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
@@ -91,11 +104,18 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		formatter output.Formatter,
 		cmd *cobra.Command) input.Console {
 		writer := cmd.OutOrStdout()
+		stdout := cmd.OutOrStdout()
 		// When using JSON formatting, we want to ensure we always write messages from the console to stderr.
 		if formatter != nil && formatter.Kind() == output.JsonFormat {
 			writer = cmd.ErrOrStderr()
 		}
 
+		// The events of `--output events` are the only lines written to stdout, read by IDE extensions. The output of
+		// the tools run by azd is written to stderr instead.
+		if formatter != nil && formatter.Kind() == output.EventsFormat {
+			stdout = cmd.ErrOrStderr()
+		}
+
 		if os.Getenv("NO_COLOR") != "" {
 			writer = colorable.NewNonColorable(writer)
 		}
@@ -104,13 +124,18 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			cmd.InOrStdin() == os.Stdin && isatty.IsTerminal(os.Stdin.Fd()) &&
 			isatty.IsTerminal(os.Stdout.Fd())
 
-		return input.NewConsole(rootOptions.NoPrompt, isTerminal, writer, input.ConsoleHandles{
+		// Prompts can't be answered by the scripts consuming JSON output
+		noPrompt := rootOptions.NoPrompt || output.IsEventStream(formatter)
+
+		return input.NewConsole(noPrompt, isTerminal, writer, input.ConsoleHandles{
 			Stdin:  cmd.InOrStdin(),
-			Stdout: cmd.OutOrStdout(),
+			Stdout: stdout,
 			Stderr: cmd.ErrOrStderr(),
 		}, formatter)
 	})
 
+	container.RegisterSingleton(input.NewLifecycleEvents)
+
 	container.RegisterSingleton(func(console input.Console, rootOptions *internal.GlobalCommandOptions) exec.CommandRunner {
 		return exec.NewCommandRunner(
 			&exec.RunnerOptions{
@@ -122,7 +147,10 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	})
 	container.RegisterSingleton(input.NewConsoleMessaging)
 
-	container.RegisterSingleton(func() httputil.HttpClient { return &http.Client{} })
+	// ARM read calls are cached for the duration of the command & optionally between commands
+	container.RegisterSingleton(func(userConfigManager config.UserConfigManager) httputil.HttpClient {
+		return azsdk.NewReadCache(&http.Client{}, armReadCacheOptions(userConfigManager))
+	})
 
 	// Auth
 	container.RegisterSingleton(auth.NewLoggedInGuard)
@@ -259,11 +287,26 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(project.NewResourceManager)
 	container.RegisterSingleton(project.NewProjectManager)
 	container.RegisterSingleton(project.NewServiceManager)
+	container.RegisterSingleton(project.NewServiceHealthChecker)
+	container.RegisterSingleton(keyvault.NewSecretsProvider)
+	container.RegisterSingleton(project.NewServiceTester)
+	container.RegisterSingleton(project.NewServiceSmokeTester)
+	container.RegisterSingleton(project.NewServiceCustomDomainBinder)
+	container.RegisterSingleton(project.NewServiceMetricsReader)
+	container.RegisterSingleton(infra.NewCostManager)
+	container.RegisterSingleton(infra.NewBudgetManager)
+	container.RegisterSingleton(infra.NewRoleAssignmentManager)
+	container.RegisterSingleton(infra.NewResourceInventory)
+	container.RegisterSingleton(infra.NewReleaseAnnotator)
+	container.RegisterSingleton(devbox.NewManager)
+	container.RegisterSingleton(project.NewPackageCache)
+	container.RegisterSingleton(project.NewPackageInspector)
 	container.RegisterSingleton(repository.NewInitializer)
 	container.RegisterSingleton(config.NewUserConfigManager)
 	container.RegisterSingleton(alpha.NewFeaturesManager)
 	container.RegisterSingleton(config.NewManager)
 	container.RegisterSingleton(templates.NewTemplateManager)
+	container.RegisterSingleton(templates.NewSourceManager)
 	container.RegisterSingleton(auth.NewManager)
 	container.RegisterSingleton(azcli.NewUserProfileService)
 	container.RegisterSingleton(account.NewSubscriptionsService)
@@ -273,8 +316,20 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(azcli.NewManagedClustersService)
 	container.RegisterSingleton(azcli.NewContainerRegistryService)
 	container.RegisterSingleton(containerapps.NewContainerAppService)
+	container.RegisterSingleton(containerapps.NewContainerAppJobService)
 	container.RegisterSingleton(project.NewContainerHelper)
 	container.RegisterSingleton(azcli.NewSpringService)
+	container.RegisterSingleton(func() (*extensions.Manager, error) {
+		extensionsDir, err := extensions.GetExtensionsDir()
+		if err != nil {
+			return nil, err
+		}
+
+		return extensions.NewManager(extensionsDir), nil
+	})
+	container.RegisterSingleton(extensions.NewRunner)
+	container.RegisterSingleton(extensions.NewRegistry)
+	container.RegisterSingleton(installer.NewUpgrader)
 	container.RegisterSingleton(func() ioc.ServiceLocator {
 		return ioc.NewServiceLocator(container)
 	})
@@ -295,14 +350,18 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		})
 	})
 	container.RegisterSingleton(bicep.NewBicepCli)
+	container.RegisterSingleton(cargo.NewCargoCli)
+	container.RegisterSingleton(devtunnel.NewDevTunnelCli)
 	container.RegisterSingleton(docker.NewDocker)
 	container.RegisterSingleton(dotnet.NewDotNetCli)
 	container.RegisterSingleton(git.NewGitCli)
 	container.RegisterSingleton(github.NewGitHubCli)
+	container.RegisterSingleton(gradle.NewGradleCli)
 	container.RegisterSingleton(javac.NewCli)
 	container.RegisterSingleton(kubectl.NewKubectl)
 	container.RegisterSingleton(maven.NewMavenCli)
 	container.RegisterSingleton(npm.NewNpmCli)
+	container.RegisterSingleton(pulumi.NewPulumiCli)
 	container.RegisterSingleton(python.NewPythonCli)
 	container.RegisterSingleton(swa.NewSwaCli)
 	container.RegisterSingleton(terraform.NewTerraformCli)
@@ -312,13 +371,14 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	// Service Targets
 	serviceTargetMap := map[project.ServiceTargetKind]any{
-		"":                          project.NewAppServiceTarget,
-		project.AppServiceTarget:    project.NewAppServiceTarget,
-		project.AzureFunctionTarget: project.NewFunctionAppTarget,
-		project.ContainerAppTarget:  project.NewContainerAppTarget,
-		project.StaticWebAppTarget:  project.NewStaticWebAppTarget,
-		project.AksTarget:           project.NewAksTarget,
-		project.SpringAppTarget:     project.NewSpringAppTarget,
+		"":                            project.NewAppServiceTarget,
+		project.AppServiceTarget:      project.NewAppServiceTarget,
+		project.AzureFunctionTarget:   project.NewFunctionAppTarget,
+		project.ContainerAppTarget:    project.NewContainerAppTarget,
+		project.ContainerAppJobTarget: project.NewContainerAppJobTarget,
+		project.StaticWebAppTarget:    project.NewStaticWebAppTarget,
+		project.AksTarget:             project.NewAksTarget,
+		project.SpringAppTarget:       project.NewSpringAppTarget,
 	}
 
 	for target, constructor := range serviceTargetMap {
@@ -327,6 +387,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		}
 	}
 
+	// Service targets & infrastructure providers implemented by extensions
+	registerExtensionContributions(container)
+
 	// Languages
 	frameworkServiceMap := map[project.ServiceLanguageKind]any{
 		"":                                project.NewDotNetProject,
@@ -337,6 +400,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.ServiceLanguageJavaScript: project.NewNpmProject,
 		project.ServiceLanguageTypeScript: project.NewNpmProject,
 		project.ServiceLanguageJava:       project.NewMavenProject,
+		project.GradleFrameworkName:       project.NewGradleProject,
+		project.ServiceLanguageRust:       project.NewRustProject,
 		project.ServiceLanguageDocker:     project.NewDockerProject,
 	}
 
@@ -357,3 +422,36 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	registerAction[*provisionAction](container, "azd-provision-action")
 	registerAction[*downAction](container, "azd-down-action")
 }
+
+// Gets the ARM read cache options from the user config.
+// `cache.arm.ttl` sets how long responses are cached & `cache.arm.persist` reuses responses between commands.
+func armReadCacheOptions(userConfigManager config.UserConfigManager) azsdk.ReadCacheOptions {
+	options := azsdk.ReadCacheOptions{}
+
+	azdConfig, err := userConfigManager.Load()
+	if err != nil {
+		log.Printf("failed loading user config for ARM read cache: %v\n", err)
+		return options
+	}
+
+	if value, has := azdConfig.Get("cache.arm.ttl"); has {
+		ttl, err := time.ParseDuration(fmt.Sprint(value))
+		if err != nil {
+			log.Printf("ignoring invalid 'cache.arm.ttl' value '%v': %v\n", value, err)
+		} else {
+			options.TTL = ttl
+		}
+	}
+
+	if value, has := azdConfig.Get("cache.arm.persist"); has && fmt.Sprint(value) == "true" {
+		configDir, err := config.GetUserConfigDir()
+		if err != nil {
+			log.Printf("failed resolving ARM read cache directory: %v\n", err)
+			return options
+		}
+
+		options.Dir = filepath.Join(configDir, "cache", "arm")
+	}
+
+	return options
+}
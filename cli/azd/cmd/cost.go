@@ -0,0 +1,253 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func costActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("cost", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "cost",
+			Short: "Report the cost of the environment's resources.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdCostHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	group.Add("report", &actions.ActionDescriptorOptions{
+		Command:         newCostReportCmd(),
+		FlagsResolver:   newCostReportFlags,
+		ActionResolver:  newCostReportAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.TableFormat, output.CsvFormat},
+		DefaultFormat:   output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdCostReportHelpFooter,
+		},
+	})
+
+	return group
+}
+
+type costReportFlags struct {
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *costReportFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newCostReportFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *costReportFlags {
+	flags := &costReportFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newCostReportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "report",
+		Short: "Show the month-to-date and forecasted cost of the environment by service and resource.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type costReportAction struct {
+	env         *environment.Environment
+	costManager *infra.CostManager
+	console     input.Console
+	formatter   output.Formatter
+	writer      io.Writer
+}
+
+func newCostReportAction(
+	env *environment.Environment,
+	costManager *infra.CostManager,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	_ *costReportFlags,
+) actions.Action {
+	return &costReportAction{
+		env:         env,
+		costManager: costManager,
+		console:     console,
+		formatter:   formatter,
+		writer:      writer,
+	}
+}
+
+// The row of each resource in the table & csv output of `azd cost report`
+type costReportRow struct {
+	contracts.ResourceCost
+	DisplayType string
+	Currency    string
+}
+
+func (a *costReportAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	spinnerMessage := "Querying cost of environment resources"
+	if a.formatter.Kind() == output.TableFormat {
+		a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	}
+
+	report, err := a.costManager.Report(ctx, a.env.GetSubscriptionId(), a.env.GetEnvName())
+	if a.formatter.Kind() == output.TableFormat {
+		a.console.StopSpinner(ctx, "", input.Step)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := newCostReportContract(report)
+
+	switch a.formatter.Kind() {
+	case output.JsonFormat:
+		return nil, a.formatter.Format(result, a.writer, nil)
+	case output.CsvFormat:
+		return nil, a.formatter.Format(costReportRows(result), a.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "Service", ValueTemplate: "{{.Service}}"},
+				{Heading: "Resource", ValueTemplate: "{{.Name}}"},
+				{Heading: "Type", ValueTemplate: "{{.Type}}"},
+				{Heading: "ResourceGroup", ValueTemplate: "{{.ResourceGroup}}"},
+				{Heading: "MonthToDate", ValueTemplate: `{{printf "%.2f" .MonthToDate}}`},
+				{Heading: "Forecast", ValueTemplate: `{{printf "%.2f" .Forecast}}`},
+				{Heading: "Currency", ValueTemplate: "{{.Currency}}"},
+			},
+		})
+	}
+
+	if len(result.Resources) == 0 {
+		fmt.Fprintln(a.writer, "No cost was reported for the environment this month.")
+		return nil, nil
+	}
+
+	if err := a.formatter.Format(costReportRows(result), a.writer, output.TableFormatterOptions{
+		Columns: []output.Column{
+			{Heading: "SERVICE", ValueTemplate: "{{.Service}}"},
+			{Heading: "RESOURCE", ValueTemplate: "{{.Name}}"},
+			{Heading: "TYPE", ValueTemplate: "{{.DisplayType}}"},
+			{Heading: "MONTH TO DATE", ValueTemplate: `{{printf "%.2f" .MonthToDate}} {{.Currency}}`},
+			{Heading: "FORECAST", ValueTemplate: `{{printf "%.2f" .Forecast}} {{.Currency}}`},
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Month-to-date cost: %.2f %s, forecasted cost: %.2f %s",
+				result.MonthToDate, result.Currency, result.Forecast, result.Currency),
+			FollowUp: "Cost Management data is usually updated every 8 to 24 hours.",
+		},
+	}, nil
+}
+
+func newCostReportContract(report *infra.CostReport) contracts.CostReport {
+	result := contracts.CostReport{
+		Currency:    report.Currency,
+		MonthToDate: report.MonthToDate,
+		Forecast:    report.Forecast,
+		Services:    []contracts.ServiceCost{},
+		Resources:   make([]contracts.ResourceCost, 0, len(report.Resources)),
+	}
+
+	services := map[string]*contracts.ServiceCost{}
+	for _, resource := range report.Resources {
+		result.Resources = append(result.Resources, contracts.ResourceCost{
+			Id:            resource.Id,
+			Name:          resource.Name,
+			Type:          resource.Type,
+			ResourceGroup: resource.ResourceGroup,
+			Service:       resource.ServiceName,
+			MonthToDate:   resource.MonthToDate,
+			Forecast:      resource.Forecast,
+		})
+
+		if resource.ServiceName == "" {
+			continue
+		}
+
+		service, has := services[resource.ServiceName]
+		if !has {
+			service = &contracts.ServiceCost{Name: resource.ServiceName}
+			services[resource.ServiceName] = service
+		}
+
+		service.MonthToDate += resource.MonthToDate
+		service.Forecast += resource.Forecast
+	}
+
+	for _, service := range services {
+		result.Services = append(result.Services, *service)
+	}
+
+	sort.Slice(result.Services, func(i, j int) bool {
+		return result.Services[i].Name < result.Services[j].Name
+	})
+
+	return result
+}
+
+func costReportRows(report contracts.CostReport) []costReportRow {
+	rows := make([]costReportRow, 0, len(report.Resources))
+	for _, resource := range report.Resources {
+		displayType := infra.GetResourceTypeDisplayName(infra.AzureResourceType(resource.Type))
+		if displayType == "" {
+			displayType = resource.Type
+		}
+
+		rows = append(rows, costReportRow{ResourceCost: resource, DisplayType: displayType, Currency: report.Currency})
+	}
+
+	return rows
+}
+
+func getCmdCostHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Report the cost of the resources of the environment from Azure Cost Management.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("The resources are the resources of the resource groups tagged with %s.",
+				output.WithHighLightFormat("azd-env-name"))),
+			formatHelpNote("Cost Management requires the Cost Management Reader role on the subscription or resource" +
+				" groups."),
+		})
+}
+
+func getCmdCostReportHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the month-to-date and forecasted cost of the environment.": output.WithHighLightFormat("azd cost report"),
+		"Export the cost of each resource for reporting.": output.WithHighLightFormat(
+			"azd cost report --output csv > cost.csv"),
+	})
+}
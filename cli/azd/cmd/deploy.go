@@ -8,16 +8,27 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
@@ -25,19 +36,52 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type deployFlags struct {
 	serviceName string
 	all         bool
 	fromPackage string
-	global      *internal.GlobalCommandOptions
+	image       string
+	forceBuild  bool
+	noCache     bool
+	watch       bool
+	// The glob patterns of the files not watched by --watch
+	watchIgnore   []string
+	watchDebounce time.Duration
+	global        *internal.GlobalCommandOptions
 	*envFlag
 }
 
 func (d *deployFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	d.bindNonCommon(local, global)
 	d.bindCommon(local, global)
+	local.Bool(
+		middleware.DryRunFlagName,
+		false,
+		"Displays how each service would be packaged and the resource it would be deployed to, without deploying it.",
+	)
+	local.BoolVar(
+		&d.watch,
+		"watch",
+		false,
+		"Redeploys the services when their source files change, until the command is stopped.",
+	)
+	local.StringArrayVar(
+		&d.watchIgnore,
+		"watch-ignore",
+		nil,
+		"A glob pattern of the files and directories not watched by --watch, ex. *.log or docs. Can be repeated.",
+	)
+	local.DurationVar(
+		&d.watchDebounce,
+		"watch-debounce",
+		time.Second,
+		"The time without changes waited by --watch before redeploying, so files saved together redeploy once.",
+	)
 }
 
 func (d *deployFlags) bindNonCommon(
@@ -52,6 +96,12 @@ func (d *deployFlags) bindNonCommon(
 	)
 	//deprecate:flag hide --service
 	_ = local.MarkHidden("service")
+	local.Int(
+		middleware.ParallelismFlagName,
+		0,
+		"The maximum number of services packaged and deployed at the same time, 1 deploys services sequentially"+
+			" (default: the services.parallelism config, or 4).",
+	)
 	d.global = global
 }
 
@@ -69,7 +119,26 @@ func (d *deployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 		&d.fromPackage,
 		"from-package",
 		"",
-		"Deploys the application from an existing package.",
+		"Deploys the service from a prebuilt zip archive, directory or container image, skipping its build and package.",
+	)
+	local.StringVar(
+		&d.image,
+		"image",
+		"",
+		"Deploys the container-hosted service from the container image of a registry as is, skipping its build and "+
+			"package.",
+	)
+	local.BoolVar(
+		&d.forceBuild,
+		"force-build",
+		false,
+		"Packages services even when their source hasn't changed since they were last packaged.",
+	)
+	local.BoolVar(
+		&d.noCache,
+		"no-cache",
+		false,
+		"Packages services without reusing or recording their packages in the package cache.",
 	)
 }
 
@@ -102,6 +171,10 @@ type deployAction struct {
 	env                      *environment.Environment
 	projectManager           project.ProjectManager
 	serviceManager           project.ServiceManager
+	packageCache             *project.PackageCache
+	deployments              *project.ServiceDeployments
+	smokeTester              *project.ServiceSmokeTester
+	customDomainBinder       *project.ServiceCustomDomainBinder
 	resourceManager          project.ResourceManager
 	accountManager           account.Manager
 	azCli                    azcli.AzCli
@@ -112,6 +185,12 @@ type deployAction struct {
 	middlewareRunner         middleware.MiddlewareContext
 	packageActionInitializer actions.ActionInitializer[*packageAction]
 	alphaFeatureManager      *alpha.FeatureManager
+	userConfigManager        config.UserConfigManager
+	releaseAnnotator         *infra.ReleaseAnnotator
+	lifecycleEvents          *input.LifecycleEvents
+
+	// Set when --from-package or --image is set
+	packageReference *project.PackageReference
 }
 
 func newDeployAction(
@@ -120,6 +199,9 @@ func newDeployAction(
 	projectConfig *project.ProjectConfig,
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
+	packageCache *project.PackageCache,
+	smokeTester *project.ServiceSmokeTester,
+	customDomainBinder *project.ServiceCustomDomainBinder,
 	resourceManager project.ResourceManager,
 	azdCtx *azdcontext.AzdContext,
 	environment *environment.Environment,
@@ -132,6 +214,9 @@ func newDeployAction(
 	middlewareRunner middleware.MiddlewareContext,
 	packageActionInitializer actions.ActionInitializer[*packageAction],
 	alphaFeatureManager *alpha.FeatureManager,
+	userConfigManager config.UserConfigManager,
+	releaseAnnotator *infra.ReleaseAnnotator,
+	lifecycleEvents *input.LifecycleEvents,
 ) actions.Action {
 	return &deployAction{
 		flags:                    flags,
@@ -141,6 +226,10 @@ func newDeployAction(
 		env:                      environment,
 		projectManager:           projectManager,
 		serviceManager:           serviceManager,
+		packageCache:             packageCache,
+		deployments:              project.NewServiceDeployments(environment),
+		smokeTester:              smokeTester,
+		customDomainBinder:       customDomainBinder,
 		resourceManager:          resourceManager,
 		accountManager:           accountManager,
 		azCli:                    azCli,
@@ -151,6 +240,9 @@ func newDeployAction(
 		middlewareRunner:         middlewareRunner,
 		packageActionInitializer: packageActionInitializer,
 		alphaFeatureManager:      alphaFeatureManager,
+		userConfigManager:        userConfigManager,
+		releaseAnnotator:         releaseAnnotator,
+		lifecycleEvents:          lifecycleEvents,
 	}
 }
 
@@ -197,11 +289,39 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		)
 	}
 
-	if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
-		return nil, err
+	if da.flags.image != "" {
+		if err := da.validateImage(targetServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	if da.flags.watch {
+		if err := da.validateWatch(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := da.projectManager.EnsureServiceTargetTools(ctx, da.projectConfig, func(svc *project.ServiceConfig) bool {
+	// Framework tools are only needed when the services are packaged as part of the deployment
+	ensureTools := da.projectManager.EnsureAllTools
+	if da.flags.fromPackage != "" {
+		reference, err := project.NewPackageReference(da.flags.fromPackage)
+		if err != nil {
+			return nil, fmt.Errorf("'--from-package': %w", err)
+		}
+
+		da.packageReference = reference
+		ensureTools = da.projectManager.EnsureServiceTargetTools
+	} else if da.flags.image != "" {
+		reference, err := project.NewImageReference(da.flags.image)
+		if err != nil {
+			return nil, fmt.Errorf("'--image': %w", err)
+		}
+
+		da.packageReference = reference
+		ensureTools = da.projectManager.EnsureServiceTargetTools
+	}
+
+	if err := ensureTools(ctx, da.projectConfig, func(svc *project.ServiceConfig) bool {
 		return targetServiceName == "" || svc.Name == targetServiceName
 	}); err != nil {
 		return nil, err
@@ -214,11 +334,14 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	startTime := time.Now()
 
-	deployResults := map[string]*project.ServiceDeployResult{}
+	parallelism, err := serviceParallelism(ctx, da.userConfigManager)
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetUsageAttributes(fields.ProjectServiceParallelismKey.Int(parallelism))
 
+	targetServices := []*project.ServiceConfig{}
 	for _, svc := range da.projectConfig.GetServicesStable() {
-		stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
-
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
 		// 2. This service is not the one the user specified
@@ -232,70 +355,530 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			da.console.WarnForFeature(ctx, alphaFeatureId)
 		}
 
-		da.console.ShowSpinner(ctx, stepMessage, input.Step)
-		var packageResult *project.ServicePackageResult
-		if da.flags.fromPackage != "" {
-			// --from-package set, skip packaging
-			packageResult = &project.ServicePackageResult{
-				PackagePath: da.flags.fromPackage,
-			}
-		} else {
-			//  --from-package not set, package the application
-			packageTask := da.serviceManager.Package(ctx, svc, nil)
-			go func() {
-				for packageProgress := range packageTask.Progress() {
-					progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, packageProgress.Message)
-					da.console.ShowSpinner(ctx, progressMessage, input.Step)
-				}
-			}()
-
-			packageResult, err = packageTask.Await()
+		targetServices = append(targetServices, svc)
+	}
+
+	if middleware.IsDryRun(ctx) {
+		return da.dryRun(ctx, targetServices)
+	}
+
+	parallelism = hooksParallelism(targetServices, parallelism)
+
+	deployResults, err := da.deployServices(ctx, targetServices, parallelism)
+	if err == nil {
+		err = da.bindCustomDomains(ctx, targetServices, deployResults)
+	}
+
+	if da.flags.watch {
+		return da.watchServices(ctx, targetServices, parallelism, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	followUp := getResourceGroupFollowUp(ctx, da.formatter, da.projectConfig, da.resourceManager, da.env)
+	if traceId, annotated := da.annotateRelease(ctx, targetServices); annotated {
+		followUp = strings.TrimSpace(fmt.Sprintf(
+			"%s\n%s", followUp, fmt.Sprintf("To view the application telemetry of this deployment, run %s.",
+				output.WithHighLightFormat("azd monitor --trace %s", traceId))))
+	}
+
+	if da.formatter.Kind() == output.JsonFormat {
+		deployResult := DeploymentResult{
+			Timestamp: time.Now(),
+			Services:  deployResults,
+		}
+
+		if fmtErr := da.formatter.Format(deployResult, da.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("deploy result could not be displayed: %w", fmtErr)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Your application was deployed to Azure in %s.", ux.DurationAsText(time.Since(startTime))),
+			FollowUp: followUp,
+		},
+	}, nil
+}
+
+// deployServices packages and deploys the services, up to parallelism at a time
+func (da *deployAction) deployServices(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	parallelism int,
+) (map[string]*project.ServiceDeployResult, error) {
+	// Services are independent of each other and are packaged & deployed concurrently
+	progress := newServiceProgress(da.console, "Deploying").withEvents(da.lifecycleEvents, "deploy")
+	results, errs := async.RunParallel(
+		ctx,
+		services,
+		parallelism,
+		func(ctx context.Context, svc *project.ServiceConfig) (_ *project.ServiceDeployResult, err error) {
+			ctx, span := tracing.Start(ctx, events.ServiceDeployEvent, trace.WithAttributes(
+				fields.StringHashed(fields.ProjectServiceNameKey, svc.Name),
+				fields.ProjectServiceHostKey.String(string(svc.Host)),
+			))
+			defer func() { span.EndWithStatus(err) }()
+
+			progress.Start(ctx, svc.Name)
+
+			deployResult, err := da.deployService(ctx, svc, progress)
 			if err != nil {
-				da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+				progress.Stop(ctx, svc.Name, input.StepFailed, nil)
 				return nil, err
 			}
+
+			da.lifecycleEvents.AddEndpoints(svc.Name, deployResult.Endpoints)
+			if err := da.deployments.Record(svc.Name, deployResult); err != nil {
+				log.Printf("failed recording deployment of service '%s': %v\n", svc.Name, err)
+			}
+
+			progress.Stop(ctx, svc.Name, input.StepDone, func() {
+				// report deploy outputs
+				da.console.MessageUxItem(ctx, deployResult)
+			})
+
+			return deployResult, nil
+		},
+	)
+
+	if err := joinServiceErrors(services, errs); err != nil {
+		return nil, err
+	}
+
+	deployResults := map[string]*project.ServiceDeployResult{}
+	for i, svc := range services {
+		deployResults[svc.Name] = results[i]
+	}
+
+	return deployResults, nil
+}
+
+// bindCustomDomains binds the custom domains of the deployed services, one service at a time. The DNS records of the
+// domains that don't resolve yet are displayed, and awaited until they resolve or the DNS timeout of the domain.
+func (da *deployAction) bindCustomDomains(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	deployResults map[string]*project.ServiceDeployResult,
+) error {
+	for _, svc := range services {
+		if svc.CustomDomain == nil {
+			continue
 		}
 
-		deployTask := da.serviceManager.Deploy(ctx, svc, packageResult)
-		go func() {
-			for deployProgress := range deployTask.Progress() {
-				progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, deployProgress.Message)
-				da.console.ShowSpinner(ctx, progressMessage, input.Step)
+		domain, err := da.customDomainBinder.Resolve(ctx, svc)
+		if err != nil {
+			return err
+		}
+
+		if missing := da.customDomainBinder.MissingRecords(ctx, domain); len(missing) > 0 {
+			da.console.Message(ctx, formatDnsRecords(domain.HostName, missing))
+
+			waitMessage := fmt.Sprintf("Waiting for the DNS records of %s", domain.HostName)
+			da.console.ShowSpinner(ctx, waitMessage, input.Step)
+			if err := da.customDomainBinder.WaitForRecords(ctx, domain); err != nil {
+				da.console.StopSpinner(ctx, waitMessage, input.StepFailed)
+				return err
 			}
-		}()
+			da.console.StopSpinner(ctx, waitMessage, input.StepDone)
+		}
 
-		deployResult, err := deployTask.Await()
+		stepMessage := fmt.Sprintf("Binding custom domain %s to service %s", domain.HostName, svc.Name)
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+		err = da.customDomainBinder.Bind(ctx, domain, func(message string) {
+			da.console.ShowSpinner(ctx, fmt.Sprintf("%s (%s)", stepMessage, message), input.Step)
+		})
 		if err != nil {
 			da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
-			return nil, err
+			return fmt.Errorf("binding custom domain of service '%s': %w", svc.Name, err)
 		}
-
 		da.console.StopSpinner(ctx, stepMessage, input.StepDone)
-		deployResults[svc.Name] = deployResult
+		da.console.Message(ctx, fmt.Sprintf("  - Custom domain: %s", output.WithLinkFormat(domain.Endpoint())))
+
+		if deployResult, has := deployResults[svc.Name]; has {
+			deployResult.CustomDomain = domain
+			deployResult.Endpoints = append(deployResult.Endpoints, domain.Endpoint())
+		}
+		da.lifecycleEvents.AddEndpoints(svc.Name, []string{domain.Endpoint()})
+	}
+
+	return nil
+}
+
+// formatDnsRecords describes the DNS records the owner of the custom domain must create
+func formatDnsRecords(hostName string, records []project.DnsRecord) string {
+	nameWidth := 0
+	for _, record := range records {
+		if len(record.Name) > nameWidth {
+			nameWidth = len(record.Name)
+		}
+	}
 
-		// report deploy outputs
-		da.console.MessageUxItem(ctx, deployResult)
+	lines := []string{fmt.Sprintf("Create the following DNS records of %s with your DNS provider:",
+		output.WithHighLightFormat(hostName))}
+	for _, record := range records {
+		lines = append(lines, fmt.Sprintf("  %-5s  %-*s  %s", record.Type, nameWidth, record.Name, record.Value))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// validateWatch validates --watch redeploys the services built from their source
+func (da *deployAction) validateWatch(ctx context.Context) error {
+	if da.flags.fromPackage != "" || da.flags.image != "" {
+		return errors.New("'--watch' cannot be specified with '--from-package' or '--image'")
+	}
+
+	if middleware.IsDryRun(ctx) {
+		return errors.New("'--watch' cannot be specified with '--dry-run'")
 	}
 
 	if da.formatter.Kind() == output.JsonFormat {
-		deployResult := DeploymentResult{
-			Timestamp: time.Now(),
-			Services:  deployResults,
+		return errors.New("'--watch' doesn't support the JSON output")
+	}
+
+	if da.flags.watchDebounce < 0 {
+		return fmt.Errorf("--watch-debounce must be a positive duration, got '%s'", da.flags.watchDebounce)
+	}
+
+	return nil
+}
+
+// watchServices redeploys the services whose source files change until Ctrl+C. Only the changed services are
+// redeployed, from a package rebuilt from their source. Failed deployments are reported and watching continues.
+func (da *deployAction) watchServices(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	parallelism int,
+	deployErr error,
+) (*actions.ActionResult, error) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	if deployErr != nil {
+		da.console.Message(ctx, output.WithErrorFormat("\nERROR: %s", deployErr.Error()))
+	}
+
+	da.console.Message(ctx, "\nWatching the source files of the services for changes, press Ctrl+C to stop.")
+
+	err := project.WatchServices(ctx, services, project.WatchOptions{
+		Debounce: da.flags.watchDebounce,
+		Ignore:   da.flags.watchIgnore,
+	}, func(ctx context.Context, changed []*project.ServiceConfig) error {
+		names := make([]string, len(changed))
+		for i, svc := range changed {
+			names[i] = svc.Name
 		}
 
-		if fmtErr := da.formatter.Format(deployResult, da.writer, nil); fmtErr != nil {
-			return nil, fmt.Errorf("deploy result could not be displayed: %w", fmtErr)
+		da.console.Message(ctx, fmt.Sprintf("\nChanges detected in %s, redeploying.",
+			output.WithHighLightFormat(strings.Join(names, ", "))))
+
+		startTime := time.Now()
+		if _, err := da.deployServices(ctx, changed, parallelism); err != nil {
+			// Deployments are canceled by Ctrl+C
+			if ctx.Err() == nil {
+				da.console.Message(ctx, output.WithErrorFormat("ERROR: %s", err.Error()))
+			}
+
+			return nil
 		}
+
+		da.console.Message(ctx, fmt.Sprintf("Redeployed in %s.", ux.DurationAsText(time.Since(startTime))))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header:   fmt.Sprintf("Your application was deployed to Azure in %s.", ux.DurationAsText(time.Since(startTime))),
-			FollowUp: getResourceGroupFollowUp(ctx, da.formatter, da.projectConfig, da.resourceManager, da.env),
+			Header: "Stopped watching the services.",
 		},
 	}, nil
 }
 
+// ServiceDeploymentPlan describes the operations the deployment of a service would run
+type ServiceDeploymentPlan struct {
+	Service string `json:"service"`
+	Host    string `json:"host"`
+	// The packaging of the service, ex. building its docker image
+	Package        string `json:"package"`
+	ResourceGroup  string `json:"resourceGroup,omitempty"`
+	TargetResource string `json:"targetResource,omitempty"`
+	SmokeTests     bool   `json:"smokeTests"`
+	// The names of the environment variables set on the host of the service, their values aren't displayed
+	Env []string `json:"env,omitempty"`
+	// The custom domain bound to the service after it is deployed
+	CustomDomain string `json:"customDomain,omitempty"`
+}
+
+// dryRun displays the packaging and the target resource of each service, without packaging or deploying them
+func (da *deployAction) dryRun(ctx context.Context, services []*project.ServiceConfig) (*actions.ActionResult, error) {
+	plans := make([]ServiceDeploymentPlan, 0, len(services))
+	for _, svc := range services {
+		targetResource, err := da.resourceManager.GetTargetResource(ctx, da.env.GetSubscriptionId(), svc)
+		if err != nil {
+			return nil, fmt.Errorf("getting target resource of service '%s': %w", svc.Name, err)
+		}
+
+		plan := ServiceDeploymentPlan{
+			Service:        svc.Name,
+			Host:           string(svc.Host),
+			Package:        da.packagePlan(ctx, svc),
+			ResourceGroup:  targetResource.ResourceGroupName(),
+			TargetResource: targetResource.ResourceName(),
+			SmokeTests:     svc.Smoke != nil,
+		}
+
+		if len(svc.Env) > 0 {
+			plan.Env = maps.Keys(svc.Env)
+			slices.Sort(plan.Env)
+		}
+
+		if svc.CustomDomain != nil {
+			if plan.CustomDomain, err = svc.CustomDomain.Name.Envsubst(da.env.Getenv); err != nil {
+				return nil, fmt.Errorf("expanding custom domain of service '%s': %w", svc.Name, err)
+			}
+		}
+
+		plans = append(plans, plan)
+	}
+
+	if da.formatter.Kind() == output.JsonFormat {
+		if err := da.formatter.Format(plans, da.writer, nil); err != nil {
+			return nil, fmt.Errorf("deployment plan could not be displayed: %w", err)
+		}
+	} else {
+		for _, plan := range plans {
+			lines := []string{
+				fmt.Sprintf("  %s (%s)", output.WithHighLightFormat(plan.Service), plan.Host),
+				fmt.Sprintf("    Package: %s", plan.Package),
+			}
+
+			target := plan.TargetResource
+			if target == "" {
+				target = "(resolved during deployment)"
+			}
+			if plan.ResourceGroup != "" {
+				target = fmt.Sprintf("%s in resource group %s", target, plan.ResourceGroup)
+			}
+			lines = append(lines, fmt.Sprintf("    Deploy to: %s", target))
+
+			if len(plan.Env) > 0 {
+				lines = append(lines, fmt.Sprintf("    Set environment variables: %s", strings.Join(plan.Env, ", ")))
+			}
+
+			if plan.SmokeTests {
+				lines = append(lines, "    Run smoke tests")
+			}
+
+			if plan.CustomDomain != "" {
+				lines = append(lines, fmt.Sprintf("    Bind custom domain: %s", plan.CustomDomain))
+			}
+
+			da.console.Message(ctx, strings.Join(lines, "\n"))
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{Header: "Dry run: no services were deployed."},
+	}, nil
+}
+
+// validateImage validates --image deploys a single container-hosted service
+func (da *deployAction) validateImage(targetServiceName string) error {
+	if da.flags.fromPackage != "" {
+		return errors.New("'--image' and '--from-package' cannot be specified together")
+	}
+
+	if da.flags.all || targetServiceName == "" {
+		return errors.New(
+			"'--image' cannot be specified when deploying all services. Specify a specific service by passing a <service>")
+	}
+
+	svc, has := da.projectConfig.Services[targetServiceName]
+	if has && !svc.Host.RequiresContainer() {
+		return fmt.Errorf(
+			"'--image' requires a container-hosted service, service '%s' is hosted by %s", targetServiceName, svc.Host)
+	}
+
+	return nil
+}
+
+// packagePlan describes how the service would be packaged by the deployment
+func (da *deployAction) packagePlan(ctx context.Context, svc *project.ServiceConfig) string {
+	if da.flags.image != "" {
+		return fmt.Sprintf("deploy the published container image %s as is", da.packageReference)
+	}
+
+	if da.flags.fromPackage != "" {
+		return fmt.Sprintf("use the prebuilt package %s", da.packageReference)
+	}
+
+	if !da.flags.forceBuild && !da.flags.noCache {
+		if fingerprint, err := da.packageCache.Fingerprint(svc); err == nil && fingerprint != "" {
+			if packageResult, has := da.packageCache.Get(ctx, svc, fingerprint); has {
+				return fmt.Sprintf("reuse the up-to-date package %s", packageResult.PackagePath)
+			}
+		}
+	}
+
+	if svc.Host.RequiresContainer() || svc.Language == project.ServiceLanguageDocker {
+		dockerfile := svc.Docker.Path
+		if dockerfile == "" {
+			dockerfile = "./Dockerfile"
+		}
+
+		return fmt.Sprintf("build the docker image of %s", filepath.Join(svc.RelativePath, dockerfile))
+	}
+
+	return fmt.Sprintf("build the %s project %s", svc.Language, svc.RelativePath)
+}
+
+// annotateRelease records a deployment marker with the trace id of the operation in the Application Insights
+// components of the environment, if any. Deployment markers are best effort and never fail the deployment.
+func (da *deployAction) annotateRelease(ctx context.Context, services []*project.ServiceConfig) (string, bool) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return "", false
+	}
+
+	names := make([]string, len(services))
+	for i, svc := range services {
+		names[i] = svc.Name
+	}
+
+	traceId := spanCtx.TraceID().String()
+	annotated, err := da.releaseAnnotator.Annotate(ctx, da.env.GetSubscriptionId(), infra.ReleaseAnnotation{
+		Time:     time.Now(),
+		TraceId:  traceId,
+		EnvName:  da.env.GetEnvName(),
+		Services: names,
+	})
+	if err != nil {
+		log.Printf("failed recording deployment marker: %v", err)
+	}
+
+	return traceId, len(annotated) > 0
+}
+
+// Packages, unless --from-package or --image is set, and deploys the service
+func (da *deployAction) deployService(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	progress *serviceProgress,
+) (*project.ServiceDeployResult, error) {
+	var packageResult *project.ServicePackageResult
+	if da.packageReference != nil {
+		// --from-package or --image set, skip building and packaging
+		packageTask := da.serviceManager.PackageFromReference(ctx, svc, da.packageReference)
+		progressDone := make(chan struct{})
+		go func() {
+			defer close(progressDone)
+			progress.Track(ctx, svc.Name, packageTask.Progress())
+		}()
+
+		result, err := packageTask.Await()
+		<-progressDone
+		if err != nil {
+			return nil, err
+		}
+
+		packageResult = result
+	} else {
+		//  --from-package and --image not set, package the application
+		result, upToDate, err := packageService(
+			ctx, da.serviceManager, da.packageCache, svc, da.flags.forceBuild, da.flags.noCache, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		if upToDate {
+			progress.Report(ctx, svc.Name, project.NewServiceProgress("Package up-to-date"))
+		}
+
+		packageResult = result
+	}
+
+	rollback := svc.Smoke != nil && svc.Smoke.Rollback
+	if rollback {
+		// Deployments consume package files, the package is recorded before it is deployed
+		if err := da.packageCache.SetCandidate(svc, packageResult); err != nil {
+			log.Printf("failed recording package of service '%s' for rollback: %v\n", svc.Name, err)
+		}
+	}
+
+	deployResult, err := da.deployPackage(ctx, svc, packageResult, progress)
+	if err != nil || svc.Smoke == nil {
+		return deployResult, err
+	}
+
+	progress.Report(ctx, svc.Name, project.NewServiceProgress("Running smoke tests"))
+	smokeResult, err := da.smokeTester.Run(ctx, svc, deployResult.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	if smokeResult.Passed {
+		if rollback {
+			if err := da.packageCache.PromoteCandidate(svc); err != nil {
+				log.Printf("failed recording deployed package of service '%s': %v\n", svc.Name, err)
+			}
+		}
+
+		deployResult.SmokeTest = smokeResult
+		return deployResult, nil
+	}
+
+	smokeErr := smokeTestError(smokeResult)
+	if !rollback {
+		return nil, smokeErr
+	}
+
+	previous, has := da.packageCache.GetDeployed(ctx, svc)
+	if !has {
+		return nil, fmt.Errorf("%w\nno previously deployed package is available to roll back to", smokeErr)
+	}
+
+	progress.Report(ctx, svc.Name, project.NewServiceProgress("Rolling back to the previously deployed package"))
+	if _, err := da.deployPackage(ctx, svc, previous, progress); err != nil {
+		return nil, fmt.Errorf("%w\nrolling back to the previously deployed package: %v", smokeErr, err)
+	}
+
+	return nil, fmt.Errorf("%w\nrolled back to the previously deployed package", smokeErr)
+}
+
+func (da *deployAction) deployPackage(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	packageResult *project.ServicePackageResult,
+	progress *serviceProgress,
+) (*project.ServiceDeployResult, error) {
+	deployTask := da.serviceManager.Deploy(ctx, svc, packageResult)
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		progress.Track(ctx, svc.Name, deployTask.Progress())
+	}()
+
+	deployResult, err := deployTask.Await()
+	<-progressDone
+
+	return deployResult, err
+}
+
+// smokeTestError describes the failed checks of the smoke tests
+func smokeTestError(result *project.SmokeTestResult) error {
+	lines := []string{fmt.Sprintf("smoke tests failed after %d attempt(s):", result.Attempts)}
+	for _, check := range result.Failures() {
+		lines = append(lines, fmt.Sprintf("  - %s: %s", check.Name, check.Message))
+	}
+
+	return errors.New(strings.Join(lines, "\n"))
+}
+
 func getCmdDeployHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription("Deploy application to Azure.", []string{
 		formatHelpNote(
@@ -303,6 +886,32 @@ func getCmdDeployHelpDescription(*cobra.Command) string {
 				" or the service described in the project that matches the current directory."),
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is deployed.", output.WithHighLightFormat("<service>"))),
+		formatHelpNote(fmt.Sprintf("Services are deployed concurrently, up to %d at a time. Use %s to change the limit."+
+			" Services are deployed one at a time when any of them has an interactive hook.",
+			defaultServiceParallelism,
+			output.WithHighLightFormat("azd config set %s <number>", serviceParallelismConfigPath))),
+		formatHelpNote(fmt.Sprintf("Services whose sources, Dockerfile and build settings match a recent package"+
+			" are deployed from that package. Use %s to package them again, or %s to bypass the package cache.",
+			output.WithHighLightFormat("--force-build"), output.WithHighLightFormat("--no-cache"))),
+		formatHelpNote(fmt.Sprintf("The variables of the %s of a service in 'azure.yaml' are set on its host when it's"+
+			" deployed, ex. in the app settings of an App Service. Their values can reference the outputs of provisioning, ex. %s.",
+			output.WithHighLightFormat("env"), output.WithHighLightFormat("${AZURE_SQL_CONNECTION_STRING}"))),
+		formatHelpNote(fmt.Sprintf("Services that define %s in 'azure.yaml' are smoke tested after they are deployed."+
+			" The deployment fails, and is rolled back when configured, if the smoke tests fail.",
+			output.WithHighLightFormat("smoke"))),
+		formatHelpNote(fmt.Sprintf("Services that define %s in 'azure.yaml' are bound to their custom domain after they"+
+			" are deployed, with a free managed certificate unless a certificate is set. The DNS records of the domain"+
+			" are displayed and awaited when they don't resolve yet.",
+			output.WithHighLightFormat("customDomain"))),
+		formatHelpNote(fmt.Sprintf("Use %s to deploy a service from an artifact built outside of azd: a zip archive,"+
+			" a directory or a container image, pulled when it isn't available locally.",
+			output.WithHighLightFormat("--from-package"))),
+		formatHelpNote(fmt.Sprintf("Use %s to deploy a container-hosted service from a container image published to a"+
+			" registry, ex. to promote an image between environments: the image isn't pulled or pushed by azd.",
+			output.WithHighLightFormat("--image"))),
+		formatHelpNote(fmt.Sprintf("Use %s to redeploy the services whose source files change, until Ctrl+C. Only the"+
+			" changed services are packaged and deployed again. Ignore files with %s.",
+			output.WithHighLightFormat("--watch"), output.WithHighLightFormat("--watch-ignore"))),
 		formatHelpNote("After the deployment is complete, the endpoint is printed. To start the service, select" +
 			" the endpoint or paste it in a browser."),
 	})
@@ -322,5 +931,20 @@ func getCmdDeployHelpFooter(*cobra.Command) string {
 		"Deploy the service named 'api' to Azure from a previously generated package.": output.WithHighLightFormat(
 			"azd deploy api --from-package <package-path>",
 		),
+		"Deploy the service named 'web' to Azure from a container image built by another pipeline.": output.WithHighLightFormat(
+			"azd deploy web --from-package contoso.azurecr.io/web:1.2.0",
+		),
+		"Deploy the service named 'api' to Azure from a container image published to a registry.": output.WithHighLightFormat(
+			"azd deploy api --image contoso.azurecr.io/api:1.2.3",
+		),
+		"Display how all services would be packaged and deployed, without deploying them.": output.WithHighLightFormat(
+			"azd deploy --all --dry-run",
+		),
+		"Redeploy the service named 'api' each time its source files change.": output.WithHighLightFormat(
+			"azd deploy api --watch --watch-ignore '*.md'",
+		),
+		"Deploy all services, 8 services at a time.": output.WithHighLightFormat(
+			"azd deploy --all --parallelism 8",
+		),
 	})
 }
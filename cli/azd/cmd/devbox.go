@@ -0,0 +1,421 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/devbox"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/devcenter"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func devboxActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("devbox", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "devbox",
+			Short: "Create and connect to a Microsoft Dev Box configured for the project.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDevboxHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	group.Add("up", &actions.ActionDescriptorOptions{
+		Command:         newDevboxUpCmd(),
+		FlagsResolver:   newDevboxUpFlags,
+		ActionResolver:  newDevboxUpAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:   output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdDevboxUpHelpFooter,
+		},
+	})
+
+	group.Add("connect", &actions.ActionDescriptorOptions{
+		Command:         newDevboxConnectCmd(),
+		FlagsResolver:   newDevboxConnectFlags,
+		ActionResolver:  newDevboxConnectAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:   output.NoneFormat,
+	})
+
+	return group
+}
+
+type devboxConnectFlags struct {
+	global *internal.GlobalCommandOptions
+	name   string
+	envFlag
+}
+
+func (f *devboxConnectFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.name,
+		"name",
+		"",
+		fmt.Sprintf("The name of the dev box. (Default: %s or <project>-<environment>)", devbox.DevBoxNameEnvVarName),
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newDevboxConnectFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *devboxConnectFlags {
+	flags := &devboxConnectFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type devboxUpFlags struct {
+	devboxConnectFlags
+	pool string
+}
+
+func (f *devboxUpFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.devboxConnectFlags.Bind(local, global)
+	local.StringVar(
+		&f.pool,
+		"pool",
+		"",
+		fmt.Sprintf("The dev box pool of the dev center project. (Default: %s)", devbox.DevBoxPoolEnvVarName),
+	)
+}
+
+func newDevboxUpFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *devboxUpFlags {
+	flags := &devboxUpFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newDevboxUpCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Create or start the dev box of the project and configure it for development.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+func newDevboxConnectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "connect",
+		Short: "Connect to the dev box of the project in the browser.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type devboxUpAction struct {
+	flags          *devboxUpFlags
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	azdCtx         *azdcontext.AzdContext
+	accountManager account.Manager
+	devboxManager  *devbox.Manager
+	gitCli         git.GitCli
+	console        input.Console
+	formatter      output.Formatter
+	writer         io.Writer
+}
+
+func newDevboxUpAction(
+	flags *devboxUpFlags,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	azdCtx *azdcontext.AzdContext,
+	accountManager account.Manager,
+	devboxManager *devbox.Manager,
+	gitCli git.GitCli,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &devboxUpAction{
+		flags:          flags,
+		env:            env,
+		projectConfig:  projectConfig,
+		azdCtx:         azdCtx,
+		accountManager: accountManager,
+		devboxManager:  devboxManager,
+		gitCli:         gitCli,
+		console:        console,
+		formatter:      formatter,
+		writer:         writer,
+	}
+}
+
+func (a *devboxUpAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if err := provisioning.EnsureEnv(ctx, a.console, a.env, a.accountManager); err != nil {
+		return nil, err
+	}
+
+	pool := a.flags.pool
+	if pool == "" {
+		pool = a.env.Getenv(devbox.DevBoxPoolEnvVarName)
+	}
+
+	config, err := ensureDevBoxConfig(ctx, a.console, a.env, a.projectConfig, a.flags.name, pool, true)
+	if err != nil {
+		return nil, err
+	}
+
+	bootstrap := devbox.Bootstrap{
+		EnvName:        a.env.GetEnvName(),
+		SubscriptionId: a.env.GetSubscriptionId(),
+		Location:       a.env.GetLocation(),
+	}
+
+	projectDir := a.azdCtx.ProjectDirectory()
+	if remoteUrl, err := a.gitCli.GetRemoteUrl(ctx, projectDir, "origin"); err == nil {
+		bootstrap.RepositoryUrl = remoteUrl
+		if branch, err := a.gitCli.GetCurrentBranch(ctx, projectDir); err == nil {
+			bootstrap.Branch = branch
+		}
+	} else {
+		log.Printf("not cloning the repository on the dev box, looking up the remote url: %v", err)
+	}
+
+	spinnerMessage := fmt.Sprintf("Bringing up dev box %s", output.WithHighLightFormat(config.Name))
+	if a.formatter.Kind() == output.NoneFormat {
+		a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	}
+
+	result, err := a.devboxManager.Up(
+		ctx, a.env.GetSubscriptionId(), config, devbox.CustomizationTasks(a.projectConfig, bootstrap))
+	if a.formatter.Kind() == output.NoneFormat {
+		a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		devBoxResult := contracts.DevBoxResult{
+			Name:             config.Name,
+			Project:          config.Project,
+			Pool:             result.DevBox.PoolName,
+			PowerState:       result.DevBox.PowerState,
+			Created:          result.Created,
+			WebUrl:           result.Connection.WebUrl,
+			RdpConnectionUrl: result.Connection.RdpConnectionUrl,
+		}
+		if result.Customization != nil {
+			devBoxResult.CustomizationTasks = len(result.Customization.Tasks)
+		}
+
+		return nil, a.formatter.Format(devBoxResult, a.writer, nil)
+	}
+
+	followUp := []string{
+		fmt.Sprintf("Connect in the browser: %s", output.WithLinkFormat(result.Connection.WebUrl)),
+	}
+	if result.Connection.RdpConnectionUrl != "" {
+		followUp = append(followUp, fmt.Sprintf(
+			"Connect with Remote Desktop: %s", output.WithLinkFormat(result.Connection.RdpConnectionUrl)))
+	}
+
+	if result.Customization != nil {
+		followUp = append(followUp,
+			fmt.Sprintf("The dev box is being configured for the project (%d tasks).", len(result.Customization.Tasks)),
+			fmt.Sprintf("Once configured, run %s in the dev box to sign in to Azure.",
+				output.WithHighLightFormat("azd auth login")),
+		)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Dev box %s is running.", config.Name),
+			FollowUp: strings.Join(followUp, "\n"),
+		},
+	}, nil
+}
+
+type devboxConnectAction struct {
+	flags         *devboxConnectFlags
+	env           *environment.Environment
+	projectConfig *project.ProjectConfig
+	devboxManager *devbox.Manager
+	console       input.Console
+	formatter     output.Formatter
+	writer        io.Writer
+}
+
+func newDevboxConnectAction(
+	flags *devboxConnectFlags,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	devboxManager *devbox.Manager,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &devboxConnectAction{
+		flags:         flags,
+		env:           env,
+		projectConfig: projectConfig,
+		devboxManager: devboxManager,
+		console:       console,
+		formatter:     formatter,
+		writer:        writer,
+	}
+}
+
+func (a *devboxConnectAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.env.GetSubscriptionId() == "" {
+		return nil, fmt.Errorf("the dev box has not been created. Please run `azd devbox up`")
+	}
+
+	config, err := ensureDevBoxConfig(ctx, a.console, a.env, a.projectConfig, a.flags.name, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := a.devboxManager.Connect(ctx, a.env.GetSubscriptionId(), config)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(contracts.DevBoxResult{
+			Name:             config.Name,
+			Project:          config.Project,
+			WebUrl:           connection.WebUrl,
+			RdpConnectionUrl: connection.RdpConnectionUrl,
+		}, a.writer, nil)
+	}
+
+	openWithDefaultBrowser(a.console, connection.WebUrl)
+	return nil, nil
+}
+
+// Dev box names start with a letter or digit, followed by up to 62 letters, digits, '-', '_' or '.'
+var invalidDevBoxNameChars = regexp.MustCompile(`[^a-zA-Z0-9-_.]`)
+
+// ensureDevBoxConfig resolves the dev center project & the name of the dev box of the project, prompting for the
+// values missing from the environment and azure.yaml. The pool is only required when creating the dev box.
+func ensureDevBoxConfig(
+	ctx context.Context,
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	name string,
+	pool string,
+	requirePool bool,
+) (devbox.Config, error) {
+	config := devbox.Config{
+		Endpoint: env.Getenv(devcenter.DevCenterEndpointEnvVarName),
+		Project:  env.Getenv(devcenter.DevCenterProjectEnvVarName),
+		Pool:     pool,
+		Name:     name,
+	}
+
+	if options := projectConfig.Infra.DevCenter; options != nil {
+		if config.Endpoint == "" {
+			config.Endpoint = options.Endpoint
+		}
+		if config.Project == "" {
+			config.Project = options.Project
+		}
+	}
+
+	if config.Name == "" {
+		config.Name = env.Getenv(devbox.DevBoxNameEnvVarName)
+	}
+	if config.Name == "" {
+		config.Name = invalidDevBoxNameChars.ReplaceAllString(
+			fmt.Sprintf("%s-%s", projectConfig.Name, env.GetEnvName()), "-")
+		if len(config.Name) > 63 {
+			config.Name = config.Name[:63]
+		}
+	}
+
+	settings := []struct {
+		envVarName string
+		value      *string
+		message    string
+	}{
+		{devcenter.DevCenterEndpointEnvVarName, &config.Endpoint, "Enter the endpoint of the dev center"},
+		{devcenter.DevCenterProjectEnvVarName, &config.Project, "Enter the name of the dev center project"},
+	}
+	if requirePool {
+		settings = append(settings, struct {
+			envVarName string
+			value      *string
+			message    string
+		}{devbox.DevBoxPoolEnvVarName, &config.Pool, "Enter the dev box pool"})
+	}
+
+	for _, setting := range settings {
+		if *setting.value == "" {
+			value, err := console.Prompt(ctx, input.ConsoleOptions{Message: setting.message})
+			if err != nil {
+				return config, fmt.Errorf("prompting for %s: %w", setting.envVarName, err)
+			}
+
+			*setting.value = strings.TrimSpace(value)
+			if *setting.value == "" {
+				return config, fmt.Errorf("%s is required", setting.envVarName)
+			}
+		}
+
+		if env.Getenv(setting.envVarName) != *setting.value {
+			env.DotenvSet(setting.envVarName, *setting.value)
+		}
+	}
+
+	if env.Getenv(devbox.DevBoxNameEnvVarName) != config.Name {
+		env.DotenvSet(devbox.DevBoxNameEnvVarName, config.Name)
+	}
+
+	if err := env.Save(); err != nil {
+		return config, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return config, nil
+}
+
+func getCmdDevboxHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Create and connect to a Microsoft Dev Box configured for the project.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"New dev boxes are configured with the tools of the project and azd, the repository of the project is"+
+					" cloned in %s and the azd environment is created in it.",
+				output.WithHighLightFormat(`C:\Workspaces`))),
+			formatHelpNote(fmt.Sprintf("The dev center is configured by %s and %s, or the %s section of azure.yaml.",
+				output.WithHighLightFormat(devcenter.DevCenterEndpointEnvVarName),
+				output.WithHighLightFormat(devcenter.DevCenterProjectEnvVarName),
+				output.WithHighLightFormat("infra.devCenter"))),
+		})
+}
+
+func getCmdDevboxUpHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Create a dev box for the project from a pool.": output.WithHighLightFormat(
+			"azd devbox up --pool <pool>"),
+		"Start the dev box of the project.": output.WithHighLightFormat("azd devbox up"),
+	})
+}
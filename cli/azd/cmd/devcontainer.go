@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type devcontainerFlags struct {
+	global *internal.GlobalCommandOptions
+	force  bool
+}
+
+func (f *devcontainerFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.force, "force", false, "Overwrites the existing devcontainer.json of the project.")
+	f.global = global
+}
+
+func newDevcontainerFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *devcontainerFlags {
+	flags := &devcontainerFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newDevcontainerCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "devcontainer",
+		Short: "Generate a dev container configuration with the tools the project needs.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type devcontainerAction struct {
+	flags         *devcontainerFlags
+	azdContext    *azdcontext.AzdContext
+	projectConfig *project.ProjectConfig
+}
+
+func newDevcontainerAction(
+	flags *devcontainerFlags,
+	azdContext *azdcontext.AzdContext,
+	projectConfig *project.ProjectConfig,
+) actions.Action {
+	return &devcontainerAction{
+		flags:         flags,
+		azdContext:    azdContext,
+		projectConfig: projectConfig,
+	}
+}
+
+func (a *devcontainerAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	path, err := devcontainer.Write(
+		a.azdContext.ProjectDirectory(), devcontainer.Generate(a.projectConfig), a.flags.force)
+	if errors.Is(err, devcontainer.ErrConfigExists) {
+		return nil, fmt.Errorf("%w: %s. Use --force to overwrite it", err, path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Created %s", output.WithLinkFormat(path)),
+			FollowUp: devcontainerFollowUp(),
+		},
+	}, nil
+}
+
+// devcontainerFollowUp explains how to open the project in a dev container and sign in to Azure from it
+func devcontainerFollowUp() string {
+	return heredoc.Docf(`
+		Commit the configuration and open the repository in GitHub Codespaces, or reopen it in a container with the
+		Dev Containers extension of VS Code. The container has azd and the tools of the project installed.
+		Credentials are not forwarded to the container, sign in to Azure inside it with %s.`,
+		output.WithHighLightFormat("azd auth login"))
+}
+
+func getCmdDevcontainerHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Generate .devcontainer/devcontainer.json, configuring a development container for GitHub Codespaces and VS Code"+
+			" with azd and the tools required by the services and infrastructure of the project.",
+		[]string{
+			formatHelpNote("In Codespaces, azd signs in to Azure with a device code when the browser can't be opened."),
+			formatHelpNote(fmt.Sprintf("Generate the configuration when initializing a project with %s.",
+				output.WithHighLightFormat("azd init --devcontainer"))),
+		})
+}
+
+func getCmdDevcontainerHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Generate the dev container configuration of the project.": output.WithHighLightFormat("azd devcontainer"),
+		"Regenerate the configuration after adding services.": output.WithHighLightFormat(
+			"azd devcontainer --force"),
+	})
+}
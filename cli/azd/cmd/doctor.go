@@ -0,0 +1,378 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/doctor"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/network"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type doctorFlags struct {
+	checks []string
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *doctorFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	local.StringSliceVar(
+		&f.checks,
+		"check",
+		nil,
+		"Runs only the named checks: config, auth, tools, network or the checks of the service targets.",
+	)
+	f.global = global
+}
+
+func newDoctorFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *doctorFlags {
+	flags := &doctorFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the machine, the configuration and the project azd runs with.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type doctorAction struct {
+	flags             *doctorFlags
+	userConfigManager config.UserConfigManager
+	authManager       *auth.Manager
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig]
+	lazyEnv           *lazy.Lazy[*environment.Environment]
+	serviceLocator    ioc.ServiceLocator
+	console           input.Console
+	formatter         output.Formatter
+	writer            io.Writer
+}
+
+func newDoctorAction(
+	flags *doctorFlags,
+	userConfigManager config.UserConfigManager,
+	authManager *auth.Manager,
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	serviceLocator ioc.ServiceLocator,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &doctorAction{
+		flags:             flags,
+		userConfigManager: userConfigManager,
+		authManager:       authManager,
+		lazyProjectConfig: lazyProjectConfig,
+		lazyEnv:           lazyEnv,
+		serviceLocator:    serviceLocator,
+		console:           console,
+		formatter:         formatter,
+		writer:            writer,
+	}
+}
+
+func (a *doctorAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	checks, err := a.selectChecks(a.checks(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	stepMessage := "Running checks"
+	a.console.ShowSpinner(ctx, stepMessage, input.Step)
+	report := doctor.Run(ctx, checks)
+	a.console.StopSpinner(ctx, stepMessage, input.StepDone)
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(report, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("doctor report could not be displayed: %w", err)
+		}
+	} else {
+		a.console.Message(ctx, "")
+		if err := a.formatter.Format(report.Results, a.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "CHECK", ValueTemplate: "{{.Check}}"},
+				{Heading: "NAME", ValueTemplate: "{{.Name}}"},
+				{Heading: "STATUS", ValueTemplate: "{{.Status}}"},
+				{Heading: "DETAILS", ValueTemplate: "{{.Message}}"},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("doctor report could not be displayed: %w", err)
+		}
+	}
+
+	if failures := report.Failures(); len(failures) > 0 {
+		lines := []string{fmt.Sprintf("%d check(s) failed:", len(failures))}
+		for _, failure := range failures {
+			lines = append(lines, fmt.Sprintf("  - %s %s: %s", failure.Check, failure.Name, failure.Message))
+		}
+
+		return nil, errors.New(strings.Join(lines, "\n"))
+	}
+
+	return nil, nil
+}
+
+// Returns the checks selected by --check, all the checks when unset
+func (a *doctorAction) selectChecks(checks []doctor.Check) ([]doctor.Check, error) {
+	if len(a.flags.checks) == 0 {
+		return checks, nil
+	}
+
+	selected := []doctor.Check{}
+	for _, name := range a.flags.checks {
+		found := false
+		for _, check := range checks {
+			if strings.EqualFold(check.Name(), name) {
+				selected = append(selected, check)
+				found = true
+			}
+		}
+
+		if !found {
+			names := make([]string, 0, len(checks))
+			for _, check := range checks {
+				names = append(names, check.Name())
+			}
+
+			return nil, fmt.Errorf("unknown check '%s', the checks are: %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	return selected, nil
+}
+
+// Returns the built-in checks, followed by the checks of the service targets of the project
+func (a *doctorAction) checks(ctx context.Context) []doctor.Check {
+	// The services are only known with a project and an environment
+	var serviceManager project.ServiceManager
+	projectConfig, projectErr := a.lazyProjectConfig.GetValue()
+	_, envErr := a.lazyEnv.GetValue()
+	if projectErr == nil && envErr == nil {
+		if err := a.serviceLocator.Resolve(&serviceManager); err != nil {
+			serviceManager = nil
+		}
+	}
+
+	checks := []doctor.Check{
+		doctor.NewCheck("config", func(ctx context.Context) []doctor.Result {
+			return a.configResults(projectErr, envErr)
+		}),
+		doctor.NewCheck("auth", a.authResults),
+		doctor.NewCheck("tools", func(ctx context.Context) []doctor.Result {
+			return toolResults(ctx, projectConfig, serviceManager)
+		}),
+		doctor.NewCheck("network", a.networkResults),
+	}
+
+	if serviceManager == nil {
+		return checks
+	}
+
+	for _, svc := range projectConfig.GetServicesStable() {
+		serviceTarget, err := serviceManager.GetServiceTarget(ctx, svc)
+		if err != nil {
+			continue
+		}
+
+		if checker, ok := serviceTarget.(project.DoctorChecker); ok {
+			checks = append(checks, checker.DoctorChecks(svc)...)
+		}
+	}
+
+	return checks
+}
+
+// Validates the user config, the project and the environment can be loaded
+func (a *doctorAction) configResults(projectErr error, envErr error) []doctor.Result {
+	results := []doctor.Result{}
+
+	azdConfig, err := a.userConfigManager.Load()
+	if err != nil {
+		results = append(results, doctor.Result{Name: "user config", Status: doctor.StatusFailed, Message: err.Error()})
+	} else {
+		results = append(results, doctor.Result{Name: "user config", Status: doctor.StatusPassed, Message: "Valid"})
+
+		if _, err := network.LoadOptions(azdConfig); err != nil {
+			results = append(results, doctor.Result{
+				Name: "network config", Status: doctor.StatusFailed, Message: err.Error()})
+		}
+	}
+
+	switch {
+	case errors.Is(projectErr, azdcontext.ErrNoProject):
+		results = append(results, doctor.Result{
+			Name:    azdcontext.ProjectFileName,
+			Status:  doctor.StatusSkipped,
+			Message: "No project in the current directory",
+		})
+	case projectErr != nil:
+		results = append(results, doctor.Result{
+			Name: azdcontext.ProjectFileName, Status: doctor.StatusFailed, Message: projectErr.Error()})
+	default:
+		results = append(results, doctor.Result{
+			Name: azdcontext.ProjectFileName, Status: doctor.StatusPassed, Message: "Valid"})
+
+		if envErr != nil {
+			results = append(results, doctor.Result{
+				Name:    "environment",
+				Status:  doctor.StatusWarning,
+				Message: fmt.Sprintf("No environment is selected, run 'azd env new': %v", envErr),
+			})
+		} else {
+			results = append(results, doctor.Result{Name: "environment", Status: doctor.StatusPassed, Message: "Valid"})
+		}
+	}
+
+	return results
+}
+
+// Validates a token for Azure Resource Manager is issued to the current user
+func (a *doctorAction) authResults(ctx context.Context) []doctor.Result {
+	if network.Current().Offline {
+		return []doctor.Result{{Name: "token", Status: doctor.StatusSkipped, Message: "azd is in offline mode"}}
+	}
+
+	cred, err := a.authManager.CredentialForCurrentUser(ctx, nil)
+	if err != nil {
+		return []doctor.Result{{Name: "token", Status: doctor.StatusFailed, Message: err.Error()}}
+	}
+
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: auth.LoginScopes})
+	if err != nil {
+		return []doctor.Result{{
+			Name:    "token",
+			Status:  doctor.StatusFailed,
+			Message: fmt.Sprintf("fetching token, run 'azd auth login': %v", err),
+		}}
+	}
+
+	return []doctor.Result{{
+		Name:    "token",
+		Status:  doctor.StatusPassed,
+		Message: fmt.Sprintf("Signed in, the token expires at %s", token.ExpiresOn.Local().Format("15:04:05")),
+	}}
+}
+
+// Validates the network configuration, see network.Diagnose
+func (a *doctorAction) networkResults(ctx context.Context) []doctor.Result {
+	azdConfig, err := a.userConfigManager.Load()
+	if err != nil {
+		return []doctor.Result{{Name: "network config", Status: doctor.StatusFailed, Message: err.Error()}}
+	}
+
+	options, err := network.LoadOptions(azdConfig)
+	if err != nil {
+		return []doctor.Result{{Name: "network config", Status: doctor.StatusFailed, Message: err.Error()}}
+	}
+
+	results := []doctor.Result{}
+	for _, diagnostic := range network.Diagnose(ctx, options) {
+		results = append(results, doctor.Result{
+			Name:    diagnostic.Name,
+			Status:  diagnostic.Status,
+			Message: diagnostic.Message,
+		})
+	}
+
+	return results
+}
+
+// Validates the tools required by the services of the project are installed, in a supported version
+func toolResults(
+	ctx context.Context,
+	projectConfig *project.ProjectConfig,
+	serviceManager project.ServiceManager,
+) []doctor.Result {
+	if serviceManager == nil {
+		return []doctor.Result{{
+			Name:    "required tools",
+			Status:  doctor.StatusSkipped,
+			Message: "The tools are required by the services of a project with an environment",
+		}}
+	}
+
+	results := []doctor.Result{}
+	checked := map[string]struct{}{}
+	for _, svc := range projectConfig.GetServicesStable() {
+		requiredTools, err := serviceManager.GetRequiredTools(ctx, svc)
+		if err != nil {
+			results = append(results, doctor.Result{Name: svc.Name, Status: doctor.StatusFailed, Message: err.Error()})
+			continue
+		}
+
+		for _, tool := range tools.Unique(requiredTools) {
+			if _, has := checked[tool.Name()]; has {
+				continue
+			}
+			checked[tool.Name()] = struct{}{}
+
+			if err := tool.CheckInstalled(ctx); err != nil {
+				results = append(results, doctor.Result{
+					Name:    tool.Name(),
+					Status:  doctor.StatusFailed,
+					Message: fmt.Sprintf("%v, see %s", err, tool.InstallUrl()),
+				})
+				continue
+			}
+
+			results = append(results, doctor.Result{Name: tool.Name(), Status: doctor.StatusPassed, Message: "Installed"})
+		}
+	}
+
+	return results
+}
+
+func getCmdDoctorHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Check the machine, the configuration and the project azd runs with: the user config and the project load,"+
+			" the current user can get a token for Azure, the tools of the services are installed and Azure Resource"+
+			" Manager and Microsoft Entra ID can be reached with the network configuration of azd, including"+
+			" air-gapped mode, custom DNS servers and private endpoints. Service targets add checks of their own.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Enable air-gapped mode with %s, azd then only calls ARM, Microsoft Entra ID"+
+				" and Azure resources, initializes templates from the template cache and uses installed tools.",
+				output.WithHighLightFormat("azd config set network.airGapped true"))),
+			formatHelpNote(fmt.Sprintf("Resolve private endpoints with %s or %s.",
+				output.WithHighLightFormat("azd config set network.dnsServer <address>"),
+				output.WithHighLightFormat("azd config set network.hosts <host>=<address>,..."))),
+			formatHelpNote(fmt.Sprintf("Allow other hosts in air-gapped mode with %s.",
+				output.WithHighLightFormat("azd config set network.allowedHosts <host>,..."))),
+			formatHelpNote(fmt.Sprintf("Disable all network calls with %s, commands requiring the network then fail"+
+				" and templates are initialized from the template cache.",
+				output.WithHighLightFormat("azd config set network.offline true"))),
+		})
+}
+
+func getCmdDoctorHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run all the checks.":                     output.WithHighLightFormat("azd doctor"),
+		"Check the network configuration of azd.": output.WithHighLightFormat("azd doctor --check network"),
+		"Report the checks as JSON.":              output.WithHighLightFormat("azd doctor --output json"),
+	})
+}
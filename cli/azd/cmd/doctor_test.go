@@ -0,0 +1,45 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+// newCommandTestProject creates a project with a default environment named dev, and isolates the user config
+func newCommandTestProject(t *testing.T, projectFile string) string {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, azdcontext.ProjectFileName), []byte(projectFile), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	azdCtx := azdcontext.NewAzdContextWithDirectory(dir)
+	require.NoError(t, azdCtx.NewEnvironment("dev"))
+	require.NoError(t, azdCtx.SetDefaultEnvironmentName("dev"))
+
+	return dir
+}
+
+// runRootCommand resolves the action of the command and its dependencies from the container of the root command,
+// and runs it
+func runRootCommand(t *testing.T, args ...string) {
+	rootCmd := NewRootCmd(false, nil)
+	rootCmd.SetArgs(append(args, "--no-prompt"))
+	require.NoError(t, rootCmd.ExecuteContext(context.Background()), args)
+}
+
+func TestDoctorRun(t *testing.T) {
+	dir := newCommandTestProject(t, "name: test")
+
+	runRootCommand(t, "doctor", "--check", "config", "--cwd", dir)
+	runRootCommand(t, "doctor", "--check", "config", "-e", "dev", "--cwd", dir, "--output", "json")
+}
@@ -2,18 +2,29 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
@@ -25,6 +36,7 @@ import (
 type downFlags struct {
 	forceDelete bool
 	purgeDelete bool
+	removeLocks bool
 	global      *internal.GlobalCommandOptions
 	envFlag
 }
@@ -38,6 +50,18 @@ func (i *downFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		//nolint:lll
 		"Does not require confirmation before it permanently deletes resources that are soft-deleted by default (for example, key vaults).",
 	)
+	local.BoolVar(
+		&i.removeLocks,
+		"remove-locks",
+		false,
+		"Removes the management locks created by the templates of the environment before it deletes resources.",
+	)
+	local.Bool(
+		middleware.DryRunFlagName,
+		false,
+		//nolint:lll
+		"Lists the resources and role assignments that would be deleted, with the locks and purge protections, without deleting them.",
+	)
 	i.envFlag.Bind(local, global)
 	i.global = global
 }
@@ -63,11 +87,15 @@ type downAction struct {
 	azdCtx              *azdcontext.AzdContext
 	env                 *environment.Environment
 	console             input.Console
+	formatter           output.Formatter
+	writer              io.Writer
 	commandRunner       exec.CommandRunner
 	projectConfig       *project.ProjectConfig
 	userProfileService  *azcli.UserProfileService
 	subResolver         account.SubscriptionTenantResolver
 	alphaFeatureManager *alpha.FeatureManager
+	resourceManager     project.ResourceManager
+	roleManager         *infra.RoleAssignmentManager
 }
 
 func newDownAction(
@@ -78,10 +106,14 @@ func newDownAction(
 	env *environment.Environment,
 	projectConfig *project.ProjectConfig,
 	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
 	commandRunner exec.CommandRunner,
 	userProfileService *azcli.UserProfileService,
 	subResolver account.SubscriptionTenantResolver,
 	alphaFeatureManager *alpha.FeatureManager,
+	resourceManager project.ResourceManager,
+	roleManager *infra.RoleAssignmentManager,
 ) actions.Action {
 	return &downAction{
 		flags:               flags,
@@ -90,11 +122,15 @@ func newDownAction(
 		azdCtx:              azdCtx,
 		env:                 env,
 		console:             console,
+		formatter:           formatter,
+		writer:              writer,
 		commandRunner:       commandRunner,
 		projectConfig:       projectConfig,
 		userProfileService:  userProfileService,
 		subResolver:         subResolver,
 		alphaFeatureManager: alphaFeatureManager,
+		resourceManager:     resourceManager,
+		roleManager:         roleManager,
 	}
 }
 
@@ -113,18 +149,188 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	startTime := time.Now()
 
-	destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete)
-	if _, err = infraManager.Destroy(ctx, destroyOptions); err != nil {
+	destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete).
+		WithRemoveLocks(a.flags.removeLocks)
+	if middleware.IsDryRun(ctx) {
+		return a.preview(ctx, infraManager, destroyOptions)
+	}
+
+	// The role assignments are resolved before the deletion, their values can be outputs of the infrastructure
+	var roleAssignments []infra.RoleAssignment
+	if len(a.projectConfig.Infra.RoleAssignments) > 0 {
+		roleAssignments, err = resolveRoleAssignments(
+			ctx, a.roleManager, a.resourceManager, a.projectConfig, a.env, a.userProfileService, a.subResolver)
+		if err != nil {
+			a.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: the role assignments of infra.roleAssignments won't be removed: %v", err))
+		}
+	}
+
+	destroyResult, err := infraManager.Destroy(ctx, destroyOptions)
+	if err != nil {
 		return nil, fmt.Errorf("deleting infrastructure: %w", err)
 	}
 
+	if err := project.NewServiceDeployments(a.env).Clear(); err != nil {
+		log.Printf("failed clearing service deployments: %v\n", err)
+	}
+
+	// The role assignments left are removed once the resources are deleted, the deletion already succeeded
+	if len(roleAssignments) > 0 {
+		spinnerMessage := "Removing role assignments"
+		a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+		err := a.roleManager.Delete(ctx, a.env.GetSubscriptionId(), roleAssignments)
+		a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			a.console.Message(ctx, output.WithWarningFormat("WARNING: removing the role assignments: %v", err))
+		}
+	}
+
+	message := &actions.ResultMessage{
+		Header: fmt.Sprintf("Your application was removed from Azure in %s.", ux.DurationAsText(time.Since(startTime))),
+	}
+
+	if destroyResult.Deleted != nil {
+		report := &downReport{
+			Environment:    a.env.GetEnvName(),
+			SubscriptionId: a.env.GetSubscriptionId(),
+			StartTime:      startTime.UTC(),
+			EndTime:        time.Now().UTC(),
+			DestroyPreview: *destroyResult.Deleted,
+			Purged:         destroyResult.Purged,
+		}
+
+		// The resources are already deleted, failing to write the report doesn't fail the command
+		reportPath, err := a.writeReport(report)
+		if err != nil {
+			log.Printf("failed writing deletion report: %v\n", err)
+			a.console.Message(ctx, output.WithWarningFormat("WARNING: writing the deletion report: %v", err))
+		} else {
+			message.FollowUp = fmt.Sprintf("The deletion report was written to %s", output.WithLinkFormat(reportPath))
+		}
+
+		if a.formatter.Kind() == output.JsonFormat {
+			if err := a.formatter.Format(report, a.writer, nil); err != nil {
+				return nil, fmt.Errorf("deletion report could not be displayed: %w", err)
+			}
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: message,
+	}, nil
+}
+
+// downReport is the machine-readable report of the resources deleted by `azd down`, kept for audit purposes
+type downReport struct {
+	Environment    string    `json:"environment"`
+	SubscriptionId string    `json:"subscriptionId"`
+	StartTime      time.Time `json:"startTime"`
+	EndTime        time.Time `json:"endTime"`
+	provisioning.DestroyPreview
+	// The names of the soft-deleted resources that were purged
+	Purged []string `json:"purged"`
+}
+
+// writeReport writes the deletion report to the down directory of the environment, named after the time of the
+// deletion, and returns its path
+func (a *downAction) writeReport(report *downReport) (string, error) {
+	reportDir := filepath.Join(a.azdCtx.EnvironmentRoot(a.env.GetEnvName()), "down")
+	if err := os.MkdirAll(reportDir, osutil.PermissionDirectory); err != nil {
+		return "", err
+	}
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	reportPath := filepath.Join(reportDir, report.StartTime.Format("20060102T150405Z")+".json")
+	if err := os.WriteFile(reportPath, content, osutil.PermissionFile); err != nil {
+		return "", err
+	}
+
+	return reportPath, nil
+}
+
+// preview lists the resources the destruction of the infrastructure would delete, for --dry-run
+func (a *downAction) preview(
+	ctx context.Context,
+	infraManager *provisioning.Manager,
+	destroyOptions provisioning.DestroyOptions,
+) (*actions.ActionResult, error) {
+	preview, err := infraManager.PreviewDestroy(ctx, destroyOptions)
+	if errors.Is(err, provisioning.ErrPreviewNotSupported) {
+		a.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The provisioning provider can't list the resources it would delete."))
+
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{Header: "Dry run: no Azure resources were deleted."},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("previewing deletion of infrastructure: %w", err)
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(preview, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("deletion preview could not be displayed: %w", err)
+		}
+	} else {
+		a.console.Message(ctx, formatDestroyPreview(preview))
+	}
+
 	return &actions.ActionResult{
-		Message: &actions.ResultMessage{
-			Header: fmt.Sprintf("Your application was removed from Azure in %s.", ux.DurationAsText(time.Since(startTime))),
-		},
+		Message: &actions.ResultMessage{Header: "Dry run: no Azure resources were deleted."},
 	}, nil
 }
 
+// formatDestroyPreview lists the resources to delete by resource group, followed by the protections of the resources
+func formatDestroyPreview(preview *provisioning.DestroyPreview) string {
+	if len(preview.Resources) == 0 {
+		return "\n  No resources would be deleted.\n"
+	}
+
+	lines := []string{}
+	for _, resource := range preview.Resources {
+		if resource.Type == "Microsoft.Resources/resourceGroups" {
+			lines = append(lines, fmt.Sprintf("  Resource group %s", output.WithHighLightFormat(resource.Name)))
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("    %s %s", resource.Type, resource.Name))
+	}
+
+	if len(preview.RoleAssignments) > 0 {
+		lines = append(lines, "", "  Role assignments:")
+		for _, assignment := range preview.RoleAssignments {
+			role := assignment.RoleName
+			if role == "" {
+				role = "Role"
+			}
+
+			lines = append(lines, fmt.Sprintf(
+				"    %s for principal %s on %s", role, assignment.PrincipalId, path.Base(assignment.Scope)))
+		}
+	}
+
+	if len(preview.Locks) > 0 {
+		lines = append(lines, "", "  Management locks:")
+		for _, lock := range preview.Locks {
+			lines = append(lines, fmt.Sprintf("    %s", lock))
+		}
+	}
+
+	if len(preview.PurgeProtected) > 0 {
+		lines = append(lines, "", "  Purge protected, kept soft-deleted after their deletion:")
+		for _, name := range preview.PurgeProtected {
+			lines = append(lines, fmt.Sprintf("    %s", name))
+		}
+	}
+
+	return fmt.Sprintf("\n%s\n", strings.Join(lines, "\n"))
+}
+
 func createProvisioningManager(ctx context.Context, a *downAction, console input.Console) (*provisioning.Manager, error) {
 	infraManager, err := provisioning.NewManager(
 		ctx,
@@ -146,7 +352,14 @@ func createProvisioningManager(ctx context.Context, a *downAction, console input
 func getCmdDownHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Delete Azure resources for an application. Running %s will not delete application"+
-			" files on your local machine.", output.WithHighLightFormat("azd down")), nil)
+			" files on your local machine.", output.WithHighLightFormat("azd down")), []string{
+		formatHelpNote("The resources, role assignments and purge protected resources to delete are listed before" +
+			" anything is deleted. A deletion report is written to .azure/<environment name>/down afterward."),
+		formatHelpNote("The role assignments of infra.roleAssignments in azure.yaml are removed after the resources" +
+			" are deleted."),
+		formatHelpNote("Management locks are detected before anything is deleted. Locks created by the templates of" +
+			" the environment, with the notes azd-env-name=<environment name>, are removed with --remove-locks."),
+	})
 }
 
 func getCmdDownHelpFooter(*cobra.Command) string {
@@ -156,5 +369,9 @@ func getCmdDownHelpFooter(*cobra.Command) string {
 		"Forcibly delete all applications resources without confirmation.": output.WithHighLightFormat("azd down --force"),
 		"Permanently delete resources that are soft-deleted by default," +
 			" without confirmation.": output.WithHighLightFormat("azd down --purge"),
+		"Remove the locks created by the environment and delete its resources.": output.WithHighLightFormat(
+			"azd down --remove-locks"),
+		"List the resources that would be deleted, without deleting them.": output.WithHighLightFormat(
+			"azd down --dry-run"),
 	})
 }
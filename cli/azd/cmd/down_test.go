@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_downAction_writeReport(t *testing.T) {
+	action := &downAction{
+		azdCtx: azdcontext.NewAzdContextWithDirectory(t.TempDir()),
+		env:    environment.EphemeralWithValues("envA", nil),
+	}
+
+	report := &downReport{
+		Environment: "envA",
+		StartTime:   time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		DestroyPreview: provisioning.DestroyPreview{
+			Resources: []provisioning.DestroyResource{{Name: "rg-envA", Type: "Microsoft.Resources/resourceGroups"}},
+			RoleAssignments: []provisioning.DestroyRoleAssignment{
+				{Id: "assignment", Scope: "/subscriptions/SUB/resourceGroups/rg-envA", PrincipalId: "PRINCIPAL"},
+			},
+		},
+		Purged: []string{"kv-envA"},
+	}
+
+	reportPath, err := action.writeReport(report)
+	require.NoError(t, err)
+	require.Equal(t,
+		filepath.Join(action.azdCtx.EnvironmentRoot("envA"), "down", "20240301T100000Z.json"), reportPath)
+
+	content, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+
+	written := map[string]any{}
+	require.NoError(t, json.Unmarshal(content, &written))
+	require.Equal(t, "envA", written["environment"])
+	require.Len(t, written["resources"], 1)
+	require.Len(t, written["roleAssignments"], 1)
+	require.Equal(t, []any{"kv-envA"}, written["purged"])
+}
+
+func Test_formatDestroyPreview_roleAssignments(t *testing.T) {
+	formatted := formatDestroyPreview(&provisioning.DestroyPreview{
+		Resources: []provisioning.DestroyResource{{Name: "rg-envA", Type: "Microsoft.Resources/resourceGroups"}},
+		RoleAssignments: []provisioning.DestroyRoleAssignment{{
+			Scope:       "/subscriptions/SUB/resourceGroups/rg-envA/providers/Microsoft.Web/sites/app",
+			PrincipalId: "PRINCIPAL",
+			RoleName:    "Contributor",
+		}},
+	})
+
+	require.Contains(t, formatted, "Role assignments:")
+	require.Contains(t, formatted, "Contributor for principal PRINCIPAL on app")
+}
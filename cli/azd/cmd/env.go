@@ -13,6 +13,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -45,6 +47,27 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvSetAction,
 	})
 
+	group.Add("set-secret", &actions.ActionDescriptorOptions{
+		Command:         newEnvSetSecretCmd(),
+		FlagsResolver:   newEnvSetSecretFlags,
+		ActionResolver:  newEnvSetSecretAction,
+		RequiresNetwork: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvSetSecretHelpDescription,
+			Footer:      getCmdEnvSetSecretHelpFooter,
+		},
+	})
+
+	group.Add("edit", &actions.ActionDescriptorOptions{
+		Command:        newEnvEditCmd(),
+		FlagsResolver:  newEnvEditFlags,
+		ActionResolver: newEnvEditAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvEditHelpDescription,
+			Footer:      getCmdEnvEditHelpFooter,
+		},
+	})
+
 	group.Add("select", &actions.ActionDescriptorOptions{
 		Command:        newEnvSelectCmd(),
 		ActionResolver: newEnvSelectAction,
@@ -64,11 +87,12 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 	})
 
 	group.Add("refresh", &actions.ActionDescriptorOptions{
-		Command:        newEnvRefreshCmd(),
-		FlagsResolver:  newEnvRefreshFlags,
-		ActionResolver: newEnvRefreshAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-		DefaultFormat:  output.NoneFormat,
+		Command:         newEnvRefreshCmd(),
+		FlagsResolver:   newEnvRefreshFlags,
+		ActionResolver:  newEnvRefreshAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:   output.NoneFormat,
 	})
 
 	group.Add("get-values", &actions.ActionDescriptorOptions{
@@ -79,6 +103,33 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		DefaultFormat:  output.EnvVarsFormat,
 	})
 
+	group.Add("promote", &actions.ActionDescriptorOptions{
+		Command:        newEnvPromoteCmd(),
+		FlagsResolver:  newEnvPromoteFlags,
+		ActionResolver: newEnvPromoteAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvPromoteHelpDescription,
+			Footer:      getCmdEnvPromoteHelpFooter,
+		},
+	})
+
+	group.Add("sync", &actions.ActionDescriptorOptions{
+		Command:         newEnvSyncCmd(),
+		FlagsResolver:   newEnvSyncFlags,
+		ActionResolver:  newEnvSyncAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:   output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvSyncHelpDescription,
+			Footer:      getCmdEnvSyncHelpFooter,
+		},
+	})
+
+	envStateActions(group)
+
 	return group
 }
 
@@ -304,6 +355,7 @@ func (en *envNewAction) Run(ctx context.Context) (*actions.ActionResult, error)
 }
 
 type envRefreshFlags struct {
+	all    bool
 	global *internal.GlobalCommandOptions
 	envFlag
 }
@@ -311,6 +363,13 @@ type envRefreshFlags struct {
 func (er *envRefreshFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	er.envFlag.Bind(local, global)
 	er.global = global
+
+	local.BoolVar(
+		&er.all,
+		"all",
+		false,
+		"Refreshes all the environments of the project concurrently.",
+	)
 }
 
 func newEnvRefreshFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envRefreshFlags {
@@ -333,6 +392,12 @@ func newEnvRefreshCmd() *cobra.Command {
 				return err
 			}
 
+			if all, err := cmd.Flags().GetBool("all"); err == nil && all {
+				if len(args) > 0 || cmd.Flags().Changed(environmentNameFlag) {
+					return errors.New("an environment name and the --all flag may not be used together")
+				}
+			}
+
 			if len(args) == 0 {
 				return nil
 			}
@@ -412,12 +477,132 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		return nil, err
 	}
 
+	if ef.flags.all {
+		return ef.refreshAll(ctx)
+	}
+
+	infraManager, err := ef.newInfraManager(ctx, ef.env, !ef.flags.global.NoPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	getStateResult, err := ef.refresh(ctx, ef.env, infraManager)
+	if err != nil {
+		return nil, err
+	}
+
+	ef.console.Message(ctx, "Environments setting refresh completed")
+
+	if ef.formatter.Kind() == output.JsonFormat {
+		err = ef.formatter.Format(provisioning.NewEnvRefreshResultFromState(getStateResult.State), ef.writer, nil)
+		if err != nil {
+			return nil, fmt.Errorf("writing deployment result in JSON format: %w", err)
+		}
+	}
+
+	if err := ef.raiseEnvUpdated(ctx, getStateResult); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Refreshes every environment of the project concurrently. Prompting is disabled since environments are refreshed
+// at the same time, the service `environment updated` events are only raised for the current environment.
+func (ef *envRefreshAction) refreshAll(ctx context.Context) (*actions.ActionResult, error) {
+	envList, err := ef.azdCtx.ListEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("listing environments: %w", err)
+	}
+
+	targets := make([]*envRefreshTarget, len(envList))
+	names := make([]string, len(envList))
+
+	// Providers are created sequentially since they may install the tools required to read the state
+	for i, envInfo := range envList {
+		env := ef.env
+		if envInfo.Name != ef.env.GetEnvName() {
+			env, err = environment.GetEnvironment(ef.azdCtx, envInfo.Name)
+			if err != nil {
+				return nil, fmt.Errorf("loading environment '%s': %w", envInfo.Name, err)
+			}
+		}
+
+		infraManager, err := ef.newInfraManager(ctx, env, false)
+		if err != nil {
+			return nil, fmt.Errorf("environment '%s': %w", envInfo.Name, err)
+		}
+
+		targets[i] = &envRefreshTarget{name: envInfo.Name, env: env, infraManager: infraManager}
+		names[i] = envInfo.Name
+	}
+
+	progress := newItemProgress(ef.console, "Refreshing", "environment")
+	results, errs := async.RunParallel(
+		ctx,
+		targets,
+		0,
+		func(ctx context.Context, target *envRefreshTarget) (*provisioning.StateResult, error) {
+			progress.Start(ctx, target.name)
+
+			result, err := ef.refresh(ctx, target.env, target.infraManager)
+			if err != nil {
+				progress.Stop(ctx, target.name, input.StepFailed, nil)
+				return nil, err
+			}
+
+			progress.Stop(ctx, target.name, input.StepDone, nil)
+			return result, nil
+		},
+	)
+
+	if err := joinItemErrors("environment", names, errs); err != nil {
+		return nil, err
+	}
+
+	if ef.formatter.Kind() == output.JsonFormat {
+		refreshResults := map[string]contracts.EnvRefreshResult{}
+		for i, result := range results {
+			refreshResults[names[i]] = provisioning.NewEnvRefreshResultFromState(result.State)
+		}
+
+		if err := ef.formatter.Format(refreshResults, ef.writer, nil); err != nil {
+			return nil, fmt.Errorf("writing deployment result in JSON format: %w", err)
+		}
+	}
+
+	for i, result := range results {
+		if targets[i].env == ef.env {
+			if err := ef.raiseEnvUpdated(ctx, result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Refreshed %d environments.", len(targets)),
+		},
+	}, nil
+}
+
+type envRefreshTarget struct {
+	name         string
+	env          *environment.Environment
+	infraManager *provisioning.Manager
+}
+
+func (ef *envRefreshAction) newInfraManager(
+	ctx context.Context,
+	env *environment.Environment,
+	interactive bool,
+) (*provisioning.Manager, error) {
 	infraManager, err := provisioning.NewManager(
 		ctx,
-		ef.env,
+		env,
 		ef.projectConfig.Path,
 		ef.projectConfig.Infra,
-		!ef.flags.global.NoPrompt,
+		interactive,
 		ef.azCli,
 		ef.console,
 		ef.commandRunner,
@@ -430,24 +615,28 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		return nil, fmt.Errorf("creating provisioning manager: %w", err)
 	}
 
+	return infraManager, nil
+}
+
+// Reads the latest state of the environment infrastructure and saves the outputs to the environment
+func (ef *envRefreshAction) refresh(
+	ctx context.Context,
+	env *environment.Environment,
+	infraManager *provisioning.Manager,
+) (*provisioning.StateResult, error) {
 	getStateResult, err := infraManager.State(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("getting deployment: %w", err)
 	}
 
-	if err := provisioning.UpdateEnvironment(ef.env, getStateResult.State.Outputs); err != nil {
+	if err := provisioning.UpdateEnvironment(env, getStateResult.State.Outputs); err != nil {
 		return nil, err
 	}
 
-	ef.console.Message(ctx, "Environments setting refresh completed")
-
-	if ef.formatter.Kind() == output.JsonFormat {
-		err = ef.formatter.Format(provisioning.NewEnvRefreshResultFromState(getStateResult.State), ef.writer, nil)
-		if err != nil {
-			return nil, fmt.Errorf("writing deployment result in JSON format: %w", err)
-		}
-	}
+	return getStateResult, nil
+}
 
+func (ef *envRefreshAction) raiseEnvUpdated(ctx context.Context, getStateResult *provisioning.StateResult) error {
 	for _, svc := range ef.projectConfig.Services {
 		eventArgs := project.ServiceLifecycleEventArgs{
 			Project: ef.projectConfig,
@@ -458,11 +647,11 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		}
 
 		if err := svc.RaiseEvent(ctx, project.ServiceEventEnvUpdated, eventArgs); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return nil, nil
+	return nil
 }
 
 func newEnvGetValuesFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envGetValuesFlags {
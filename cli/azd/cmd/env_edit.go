@@ -0,0 +1,375 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/commands/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The value written in place of the values of secrets, a value left masked isn't changed
+const envEditMask = "********"
+
+// The kinds of changes made to the values of the environment
+const (
+	envEditAdded   = "added"
+	envEditChanged = "changed"
+	envEditRemoved = "removed"
+)
+
+var envEditKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type envEditFlags struct {
+	envFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envEditFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvEditFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envEditFlags {
+	flags := &envEditFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the values of an environment in your editor.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envEditAction struct {
+	flags         *envEditFlags
+	env           *environment.Environment
+	console       input.Console
+	commandRunner exec.CommandRunner
+}
+
+func newEnvEditAction(
+	flags *envEditFlags,
+	env *environment.Environment,
+	console input.Console,
+	commandRunner exec.CommandRunner,
+) actions.Action {
+	return &envEditAction{
+		flags:         flags,
+		env:           env,
+		console:       console,
+		commandRunner: commandRunner,
+	}
+}
+
+// envEditChange is a value of the environment added, changed or removed in the editor
+type envEditChange struct {
+	Key string
+	// added, changed or removed
+	Kind  string
+	Value string
+	// Whether the value is a secret, masked when the change is shown
+	Secret bool
+}
+
+func (a *envEditAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.global.NoPrompt {
+		return nil, fmt.Errorf(
+			"%s is interactive, use %s when prompting is disabled",
+			output.WithHighLightFormat("azd env edit"),
+			output.WithHighLightFormat("azd env set <key> <value>"))
+	}
+
+	original := a.env.Dotenv()
+	content, err := newEnvEditFile(a.env.GetEnvName(), original)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp("", "azd-env-*.env")
+	if err != nil {
+		return nil, fmt.Errorf("creating the file edited: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("writing the file edited: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("writing the file edited: %w", err)
+	}
+
+	var edited map[string]string
+	for {
+		if err := a.openEditor(ctx, file.Name()); err != nil {
+			return nil, err
+		}
+
+		editedContent, err := os.ReadFile(file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading the file edited: %w", err)
+		}
+
+		edited, err = parseEnvEditFile(a.env.GetEnvName(), string(editedContent), original)
+		if err == nil {
+			break
+		}
+
+		a.console.Message(ctx, output.WithErrorFormat("The values are invalid: %v", err))
+		retry, promptErr := a.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      "Do you want to fix the values in the editor?",
+			DefaultValue: true,
+		})
+		if promptErr != nil {
+			return nil, fmt.Errorf("prompting to fix the values: %w", promptErr)
+		}
+
+		if !retry {
+			return nil, err
+		}
+	}
+
+	changes, err := newEnvEditChanges(original, edited)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("No values of %s were changed.", a.env.GetEnvName()),
+			},
+		}, nil
+	}
+
+	a.console.Message(ctx, fmt.Sprintf("Changes to %s:\n", output.WithHighLightFormat(a.env.GetEnvName())))
+	for _, change := range changes {
+		value := change.Value
+		if change.Secret {
+			value = envEditMask
+		}
+
+		switch change.Kind {
+		case envEditAdded:
+			a.console.Message(ctx, output.WithSuccessFormat("  + %s=%s", change.Key, value))
+		case envEditChanged:
+			a.console.Message(ctx, output.WithWarningFormat("  ~ %s=%s", change.Key, value))
+		case envEditRemoved:
+			a.console.Message(ctx, output.WithErrorFormat("  - %s", change.Key))
+		}
+	}
+	a.console.Message(ctx, "")
+
+	save, err := a.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Save the changes to %s?", a.env.GetEnvName()),
+		DefaultValue: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prompting to save the changes: %w", err)
+	}
+
+	if !save {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "The changes were discarded.",
+			},
+		}, nil
+	}
+
+	for _, change := range changes {
+		if change.Kind == envEditRemoved {
+			a.env.DotenvDelete(change.Key)
+		} else {
+			a.env.DotenvSet(change.Key, change.Value)
+		}
+	}
+
+	if err := a.env.Save(); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Saved %d change(s) to %s.", len(changes), a.env.GetEnvName()),
+			FollowUp: fmt.Sprintf("Run %s to apply the values to the infrastructure.",
+				output.WithHighLightFormat("azd provision")),
+		},
+	}, nil
+}
+
+// openEditor opens the file in the editor of $VISUAL or $EDITOR, and waits for the editor to exit
+func (a *envEditAction) openEditor(ctx context.Context, path string) error {
+	editor := envEditor()
+	runArgs := exec.NewRunArgs(editor[0], append(editor[1:], path)...).WithInteractive(true)
+	if _, err := a.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf(
+			"running editor '%s', set VISUAL or EDITOR to the editor to use: %w", strings.Join(editor, " "), err)
+	}
+
+	return nil
+}
+
+// envEditor returns the command and arguments of the editor of $VISUAL or $EDITOR, ex. `code --wait`. Notepad is
+// used by default on Windows, and vi on other platforms.
+func envEditor() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(name)); len(editor) > 0 {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+
+	return []string{"vi"}
+}
+
+// newEnvEditFile returns the content of the file edited, the values sorted by key with the values of secrets masked.
+// The values are formatted like the `.env` file of the environment.
+func newEnvEditFile(envName string, values map[string]string) (string, error) {
+	masked := map[string]string{}
+	for key, value := range values {
+		masked[key] = value
+
+		secret, err := matchesEnvPattern(key, pipeline.DefaultEnvSyncSecrets)
+		if err != nil {
+			return "", err
+		}
+
+		if secret {
+			masked[key] = envEditMask
+		}
+	}
+
+	lines, err := godotenv.Marshal(masked)
+	if err != nil {
+		return "", fmt.Errorf("formatting the values: %w", err)
+	}
+
+	return fmt.Sprintf(
+		"# The values of environment '%s', one KEY=value by line. Lines starting with # are ignored.\n"+
+			"# The values of secrets are masked, replace %s to change them.\n%s\n",
+		envName, envEditMask, lines), nil
+}
+
+// parseEnvEditFile returns the values of the file edited, the masked values of secrets replaced by their original
+// value. Keys must be valid names of environment variables, and the name of the environment can't be changed.
+func parseEnvEditFile(envName string, content string, original map[string]string) (map[string]string, error) {
+	edited, err := godotenv.Unmarshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the values: %w", err)
+	}
+
+	invalid := []string{}
+	for key, value := range edited {
+		if !envEditKeyRegexp.MatchString(key) {
+			invalid = append(invalid, key)
+			continue
+		}
+
+		if previous, has := original[key]; has && value == envEditMask {
+			edited[key] = previous
+		}
+	}
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, fmt.Errorf(
+			"invalid key(s) %s, keys must start with a letter or _ and contain letters, digits and _ only",
+			strings.Join(invalid, ", "))
+	}
+
+	originalName, has := original[environment.EnvNameEnvVarName]
+	if has && edited[environment.EnvNameEnvVarName] != originalName {
+		return nil, fmt.Errorf("%s can't be changed, it must be '%s'", environment.EnvNameEnvVarName, envName)
+	}
+
+	return edited, nil
+}
+
+// newEnvEditChanges returns the values added, changed or removed in the editor, sorted by key
+func newEnvEditChanges(original map[string]string, edited map[string]string) ([]envEditChange, error) {
+	changes := []envEditChange{}
+	add := func(key string, kind string, value string) error {
+		secret, err := matchesEnvPattern(key, pipeline.DefaultEnvSyncSecrets)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, envEditChange{Key: key, Kind: kind, Value: value, Secret: secret})
+		return nil
+	}
+
+	for key, value := range edited {
+		previous, has := original[key]
+		if !has {
+			if err := add(key, envEditAdded, value); err != nil {
+				return nil, err
+			}
+		} else if previous != value {
+			if err := add(key, envEditChanged, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for key := range original {
+		if _, has := edited[key]; !has {
+			if err := add(key, envEditRemoved, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes, nil
+}
+
+func getCmdEnvEditHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Edit the values of an environment in your editor, instead of setting them one by one with `azd env set`."+
+			" The changes are shown before they are saved.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("The editor of %s or %s is used, ex. %s.",
+				output.WithHighLightFormat("VISUAL"),
+				output.WithHighLightFormat("EDITOR"),
+				output.WithHighLightFormat("code --wait"))),
+			formatHelpNote(fmt.Sprintf("The values of secrets are masked, replace %s to change them.", envEditMask)),
+			formatHelpNote("Remove a line to remove its value from the environment."),
+		})
+}
+
+func getCmdEnvEditHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Edit the values of the default environment.": output.WithHighLightFormat("azd env edit"),
+		"Edit the values of an environment in Visual Studio Code.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("EDITOR=\"code --wait\" azd env edit -e"),
+			output.WithWarningFormat("<environment>")),
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_envEdit(t *testing.T) {
+	original := map[string]string{
+		"AZURE_ENV_NAME": "dev",
+		"API_BASE_URL":   "https://api.contoso.com",
+		"DB_PASSWORD":    "p@ssw0rd",
+		"LOG_LEVEL":      "info",
+		"API_TOKEN":      "abc123",
+	}
+
+	content, err := newEnvEditFile("dev", original)
+	require.NoError(t, err)
+	require.Contains(t, content, `DB_PASSWORD="********"`)
+	require.Contains(t, content, `LOG_LEVEL="info"`)
+	require.NotContains(t, content, "p@ssw0rd")
+
+	t.Run("Changes", func(t *testing.T) {
+		// Change a value and a secret, add a value and remove a value, the masked secrets are kept
+		edited := strings.ReplaceAll(content, `LOG_LEVEL="info"`, `LOG_LEVEL="debug"`)
+		edited = strings.ReplaceAll(edited, `API_TOKEN="********"`, `API_TOKEN="def456"`)
+		edited = strings.ReplaceAll(edited, `API_BASE_URL="https://api.contoso.com"`, "")
+		edited += "FEATURE_FLAGS=search,checkout\n"
+
+		values, err := parseEnvEditFile("dev", edited, original)
+		require.NoError(t, err)
+		require.Equal(t, "p@ssw0rd", values["DB_PASSWORD"])
+
+		changes, err := newEnvEditChanges(original, values)
+		require.NoError(t, err)
+		require.Equal(t, []envEditChange{
+			{Key: "API_BASE_URL", Kind: envEditRemoved},
+			{Key: "API_TOKEN", Kind: envEditChanged, Value: "def456", Secret: true},
+			{Key: "FEATURE_FLAGS", Kind: envEditAdded, Value: "search,checkout"},
+			{Key: "LOG_LEVEL", Kind: envEditChanged, Value: "debug"},
+		}, changes)
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		values, err := parseEnvEditFile("dev", content, original)
+		require.NoError(t, err)
+		require.Equal(t, original, values)
+
+		changes, err := newEnvEditChanges(original, values)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		_, err := parseEnvEditFile("dev", content+"1_REPLICAS=2\n", original)
+		require.ErrorContains(t, err, "invalid key(s) 1_REPLICAS")
+	})
+
+	t.Run("EnvName", func(t *testing.T) {
+		edited := strings.ReplaceAll(content, `AZURE_ENV_NAME="dev"`, `AZURE_ENV_NAME="prod"`)
+		_, err := parseEnvEditFile("dev", edited, original)
+		require.ErrorContains(t, err, "AZURE_ENV_NAME can't be changed")
+	})
+}
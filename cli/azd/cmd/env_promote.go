@@ -0,0 +1,367 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azdo"
+	"github.com/azure/azure-dev/cli/azd/pkg/commands/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The keys of environment values tied to the environment, its Azure target or the machine, never promoted
+var envPromoteExcluded = []string{
+	environment.EnvNameEnvVarName,
+	environment.SubscriptionIdEnvVarName,
+	environment.TenantIdEnvVarName,
+	environment.LocationEnvVarName,
+	environment.PrincipalIdEnvVarName,
+	environment.ResourceGroupEnvVarName,
+	azdo.AzDoPatName,
+	"AZURE_DEVOPS_*",
+	"AZD_PIPELINE_PROVIDER",
+}
+
+// The kinds of changes promoting a value makes to the target environment
+const (
+	envPromoteAdded   = "added"
+	envPromoteChanged = "changed"
+)
+
+type envPromoteFlags struct {
+	global  *internal.GlobalCommandOptions
+	include []string
+	exclude []string
+	yes     bool
+	dryRun  bool
+}
+
+func (f *envPromoteFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringArrayVar(&f.include, "include", nil, "Promotes only the values matching the pattern, ex. SERVICE_*.")
+	local.StringArrayVar(&f.exclude, "exclude", nil, "Doesn't promote the values matching the pattern.")
+	local.BoolVar(&f.yes, "yes", false, "Promotes all the changes without prompting for each of them.")
+	local.BoolVar(&f.dryRun, "dry-run", false, "Shows the changes that would be promoted without promoting them.")
+	f.global = global
+}
+
+func newEnvPromoteFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envPromoteFlags {
+	flags := &envPromoteFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvPromoteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "promote <source> <target>",
+		Short: "Copy environment values from an environment to another.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+type envPromoteAction struct {
+	flags     *envPromoteFlags
+	azdCtx    *azdcontext.AzdContext
+	console   input.Console
+	formatter output.Formatter
+	writer    io.Writer
+	args      []string
+}
+
+func newEnvPromoteAction(
+	flags *envPromoteFlags,
+	azdCtx *azdcontext.AzdContext,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	args []string,
+) actions.Action {
+	return &envPromoteAction{
+		flags:     flags,
+		azdCtx:    azdCtx,
+		console:   console,
+		formatter: formatter,
+		writer:    writer,
+		args:      args,
+	}
+}
+
+// EnvPromoteChange is a value of the source environment that differs in the target environment
+type EnvPromoteChange struct {
+	Key string `json:"key"`
+	// added or changed
+	Kind     string `json:"kind"`
+	Value    string `json:"value"`
+	Previous string `json:"previous,omitempty"`
+	Promoted bool   `json:"promoted"`
+}
+
+// EnvPromoteResult lists the changes between the environments, and the keys of the secrets that weren't promoted
+type EnvPromoteResult struct {
+	Source  string             `json:"source"`
+	Target  string             `json:"target"`
+	DryRun  bool               `json:"dryRun"`
+	Changes []EnvPromoteChange `json:"changes"`
+	Secrets []string           `json:"secrets"`
+}
+
+func (a *envPromoteAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	sourceName, targetName := a.args[0], a.args[1]
+	if sourceName == targetName {
+		return nil, errors.New("the source and target environments must be different")
+	}
+
+	source, err := a.loadEnvironment(sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := a.loadEnvironment(targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, secrets, err := newEnvPromoteChanges(source.Dotenv(), target.Dotenv(), a.flags.include, a.flags.exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	interactive := !a.flags.yes && !a.flags.dryRun
+	if interactive && len(changes) > 0 && (a.flags.global.NoPrompt || a.formatter.Kind() == output.JsonFormat) {
+		return nil, errors.New("--yes or --dry-run must be set when prompting is disabled")
+	}
+
+	jsonOutput := a.formatter.Kind() == output.JsonFormat
+	if !jsonOutput {
+		a.showChanges(ctx, sourceName, targetName, changes, secrets)
+	}
+
+	promoted := 0
+	for i, change := range changes {
+		if a.flags.dryRun {
+			break
+		}
+
+		if interactive {
+			confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+				Message:      fmt.Sprintf("Promote %s to %s?", change.Key, targetName),
+				DefaultValue: true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("prompting to promote %s: %w", change.Key, err)
+			}
+
+			if !confirm {
+				continue
+			}
+		}
+
+		target.DotenvSet(change.Key, change.Value)
+		changes[i].Promoted = true
+		promoted++
+	}
+
+	if promoted > 0 {
+		if err := target.Save(); err != nil {
+			return nil, fmt.Errorf("saving environment: %w", err)
+		}
+	}
+
+	if jsonOutput {
+		result := EnvPromoteResult{
+			Source:  sourceName,
+			Target:  targetName,
+			DryRun:  a.flags.dryRun,
+			Changes: changes,
+			Secrets: secrets,
+		}
+		if err := a.formatter.Format(result, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("promote result could not be displayed: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	if len(changes) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("%s is already up to date with %s.", targetName, sourceName),
+			},
+		}, nil
+	}
+
+	if a.flags.dryRun {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("Dry run, nothing was promoted to %s.", targetName),
+			},
+		}, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Promoted %d value(s) from %s to %s.", promoted, sourceName, targetName),
+			FollowUp: fmt.Sprintf("Run %s to apply the values to the infrastructure of %s.",
+				output.WithHighLightFormat("azd provision -e %s", targetName), targetName),
+		},
+	}, nil
+}
+
+func (a *envPromoteAction) loadEnvironment(name string) (*environment.Environment, error) {
+	if !environment.IsValidEnvironmentName(name) {
+		return nil, fmt.Errorf("'%s' is not a valid environment name", name)
+	}
+
+	env, err := environment.GetEnvironment(a.azdCtx, name)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"environment '%s' does not exist, create it with %s: %w",
+			name, output.WithHighLightFormat("azd env new %s", name), err)
+	}
+
+	return env, nil
+}
+
+// showChanges writes the preview of the changes promoted to the target environment
+func (a *envPromoteAction) showChanges(
+	ctx context.Context,
+	sourceName string,
+	targetName string,
+	changes []EnvPromoteChange,
+	secrets []string,
+) {
+	if len(changes) > 0 {
+		a.console.Message(ctx, fmt.Sprintf("Changes from %s to %s:\n",
+			output.WithHighLightFormat(sourceName), output.WithHighLightFormat(targetName)))
+	}
+
+	for _, change := range changes {
+		switch change.Kind {
+		case envPromoteAdded:
+			a.console.Message(ctx, output.WithSuccessFormat("  + %s=%s", change.Key, change.Value))
+		case envPromoteChanged:
+			a.console.Message(ctx, output.WithWarningFormat(
+				"  ~ %s=%s (was %s)", change.Key, change.Value, change.Previous))
+		}
+	}
+
+	if len(secrets) > 0 {
+		a.console.Message(ctx, output.WithGrayFormat("\n  Secrets are not promoted: %v", secrets))
+	}
+
+	a.console.Message(ctx, "")
+}
+
+// newEnvPromoteChanges returns the changes promoting the source values makes to the target values, sorted by key.
+// Values only set in the target are kept. Excluded values are skipped, and the keys of the secrets skipped are
+// returned: secrets reference the vault of the source, or hold a value the target shouldn't share.
+func newEnvPromoteChanges(
+	source map[string]string,
+	target map[string]string,
+	include []string,
+	exclude []string,
+) ([]EnvPromoteChange, []string, error) {
+	excludes := append(append([]string{}, envPromoteExcluded...), exclude...)
+
+	changes := []EnvPromoteChange{}
+	secrets := []string{}
+	for key, value := range source {
+		excluded, err := matchesEnvPattern(key, excludes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		included := true
+		if len(include) > 0 {
+			included, err = matchesEnvPattern(key, include)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		previous, has := target[key]
+		if excluded || !included || (has && previous == value) {
+			continue
+		}
+
+		secret, err := matchesEnvPattern(key, pipeline.DefaultEnvSyncSecrets)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if secret || keyvault.IsSecretReference(value) {
+			secrets = append(secrets, key)
+			continue
+		}
+
+		change := EnvPromoteChange{Key: key, Kind: envPromoteAdded, Value: value}
+		if has {
+			change.Kind = envPromoteChanged
+			change.Previous = previous
+		}
+
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	sort.Strings(secrets)
+
+	return changes, secrets, nil
+}
+
+func matchesEnvPattern(key string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func getCmdEnvPromoteHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Copy the values of an environment to another, for example to promote the configuration of dev to staging."+
+			" The changes are shown before they are copied.",
+		[]string{
+			formatHelpNote("Values only set in the target environment are kept."),
+			formatHelpNote(fmt.Sprintf("Secrets are never copied, nor values tied to an environment such as %s and %s.",
+				output.WithHighLightFormat(environment.SubscriptionIdEnvVarName),
+				output.WithHighLightFormat(environment.LocationEnvVarName))),
+			formatHelpNote(fmt.Sprintf("Each change is confirmed unless %s is set.",
+				output.WithHighLightFormat("--yes"))),
+		})
+}
+
+func getCmdEnvPromoteHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Promote the values of dev to staging, confirming each change.": output.WithHighLightFormat(
+			"azd env promote dev staging"),
+		"Promote all the changes to the values of services without prompting.": output.WithHighLightFormat(
+			"azd env promote dev staging --include SERVICE_* --yes"),
+		"Show the changes between dev and staging.": output.WithHighLightFormat(
+			"azd env promote dev staging --dry-run"),
+	})
+}
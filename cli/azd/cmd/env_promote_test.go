@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newEnvPromoteChanges(t *testing.T) {
+	source := map[string]string{
+		"AZURE_ENV_NAME":        "dev",
+		"AZURE_LOCATION":        "eastus2",
+		"API_BASE_URL":          "https://api.contoso.com",
+		"FEATURE_FLAGS":         "search,checkout",
+		"LOG_LEVEL":             "debug",
+		"DB_PASSWORD":           "p@ssw0rd",
+		"STRIPE_API":            "akvs://dev-vault/stripe-api",
+		"SERVICE_WEB_REPLICAS":  "2",
+		"SERVICE_API_REPLICAS":  "3",
+		"SERVICE_API_IMAGE_TAG": "1.2.0",
+	}
+	target := map[string]string{
+		"AZURE_ENV_NAME":       "staging",
+		"AZURE_LOCATION":       "westus3",
+		"API_BASE_URL":         "https://api.contoso.com",
+		"LOG_LEVEL":            "info",
+		"STAGING_ONLY":         "true",
+		"SERVICE_API_REPLICAS": "1",
+	}
+
+	t.Run("All", func(t *testing.T) {
+		changes, secrets, err := newEnvPromoteChanges(source, target, nil, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []EnvPromoteChange{
+			{Key: "FEATURE_FLAGS", Kind: envPromoteAdded, Value: "search,checkout"},
+			{Key: "LOG_LEVEL", Kind: envPromoteChanged, Value: "debug", Previous: "info"},
+			{Key: "SERVICE_API_IMAGE_TAG", Kind: envPromoteAdded, Value: "1.2.0"},
+			{Key: "SERVICE_API_REPLICAS", Kind: envPromoteChanged, Value: "3", Previous: "1"},
+			{Key: "SERVICE_WEB_REPLICAS", Kind: envPromoteAdded, Value: "2"},
+		}, changes)
+		require.Equal(t, []string{"DB_PASSWORD", "STRIPE_API"}, secrets)
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		changes, _, err := newEnvPromoteChanges(source, target, []string{"SERVICE_*"}, []string{"*_IMAGE_TAG"})
+		require.NoError(t, err)
+
+		require.Equal(t, []EnvPromoteChange{
+			{Key: "SERVICE_API_REPLICAS", Kind: envPromoteChanged, Value: "3", Previous: "1"},
+			{Key: "SERVICE_WEB_REPLICAS", Kind: envPromoteAdded, Value: "2"},
+		}, changes)
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		_, _, err := newEnvPromoteChanges(source, target, []string{"["}, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The names allowed for Key Vault secrets
+var keyVaultSecretNameRegexp = regexp.MustCompile(`^[0-9a-zA-Z-]{1,127}$`)
+
+type envSetSecretFlags struct {
+	global     *internal.GlobalCommandOptions
+	vault      string
+	secretName string
+	existing   bool
+	envFlag
+}
+
+func (f *envSetSecretFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.vault,
+		"vault",
+		"",
+		fmt.Sprintf("The name of the Key Vault storing the secret. (Default: %s)", environment.KeyVaultNameEnvVarName),
+	)
+	local.StringVar(
+		&f.secretName,
+		"secret-name",
+		"",
+		"The name of the secret in the Key Vault. (Default: the key, ex. DB_PASSWORD is stored as db-password)",
+	)
+	local.BoolVar(
+		&f.existing,
+		"existing",
+		false,
+		"References an existing secret of the Key Vault without setting its value.",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvSetSecretFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSetSecretFlags {
+	flags := &envSetSecretFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvSetSecretCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-secret <key>",
+		Short: "Store a secret in Key Vault and reference it from the environment.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type envSetSecretAction struct {
+	flags           *envSetSecretFlags
+	env             *environment.Environment
+	secretsProvider keyvault.SecretsProvider
+	console         input.Console
+	args            []string
+}
+
+func newEnvSetSecretAction(
+	flags *envSetSecretFlags,
+	env *environment.Environment,
+	secretsProvider keyvault.SecretsProvider,
+	console input.Console,
+	args []string,
+) actions.Action {
+	return &envSetSecretAction{
+		flags:           flags,
+		env:             env,
+		secretsProvider: secretsProvider,
+		console:         console,
+		args:            args,
+	}
+}
+
+func (e *envSetSecretAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	key := e.args[0]
+
+	vaultName := e.flags.vault
+	if vaultName == "" {
+		vaultName = e.env.Getenv(environment.KeyVaultNameEnvVarName)
+	}
+	if vaultName == "" {
+		return nil, fmt.Errorf(
+			"no Key Vault to store the secret in, set the vault with --vault or provision a Key Vault output as %s",
+			environment.KeyVaultNameEnvVarName)
+	}
+
+	secretName := e.flags.secretName
+	if secretName == "" {
+		secretName = strings.ReplaceAll(strings.ToLower(key), "_", "-")
+	}
+	if !keyVaultSecretNameRegexp.MatchString(secretName) {
+		return nil, fmt.Errorf(
+			"invalid secret name '%s', Key Vault secret names contain only letters, numbers and hyphens", secretName)
+	}
+
+	reference := keyvault.SecretReference{VaultName: vaultName, SecretName: secretName}
+
+	if e.flags.existing {
+		// Reading the secret validates the reference before the environment uses it
+		if _, err := e.secretsProvider.GetSecret(ctx, e.env.GetSubscriptionId(), reference); err != nil {
+			return nil, err
+		}
+	} else {
+		value, err := e.console.Prompt(ctx, input.ConsoleOptions{
+			Message:    fmt.Sprintf("Enter the value of secret '%s'", secretName),
+			IsPassword: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("prompting for the value of the secret: %w", err)
+		}
+		if value == "" {
+			return nil, errors.New("the value of the secret can't be empty")
+		}
+
+		e.console.ShowSpinner(ctx, fmt.Sprintf("Storing secret in vault %s", vaultName), input.Step)
+		err = e.secretsProvider.SetSecret(ctx, e.env.GetSubscriptionId(), reference, value)
+		e.console.StopSpinner(ctx, "", input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the reference to the secret is written to the .env file
+	e.env.DotenvSet(key, reference.String())
+	if err := e.env.Save(); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("%s now references secret %s", key, output.WithHighLightFormat(reference.String())),
+			FollowUp: "The secret is read from the vault when azd provisions, deploys or runs hooks.",
+		},
+	}, nil
+}
+
+func getCmdEnvSetSecretHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Store a secret in Key Vault and set the environment value to a reference to the secret. The value of the"+
+			" secret is read from the vault when used and is never written to the .env file.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("References have the form %s, the vault is in the subscription of the"+
+				" environment unless the reference sets a subscription.",
+				output.WithHighLightFormat("akvs://[<subscriptionId>/]<vault>/<secret>"))),
+			formatHelpNote("Secrets are resolved when azd provisions, deploys or runs hooks, values referencing" +
+				" secrets can be set with azd env set as well."),
+		})
+}
+
+func getCmdEnvSetSecretHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Store a secret in the Key Vault of the environment, prompting for its value.": output.WithHighLightFormat(
+			"azd env set-secret DB_PASSWORD"),
+		"Store a secret in another vault.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd env set-secret DB_PASSWORD --vault"),
+			output.WithWarningFormat("[Vault name]")),
+		"Reference an existing secret of a vault.": fmt.Sprintf("%s %s %s",
+			output.WithHighLightFormat("azd env set-secret API_KEY --existing --vault"),
+			output.WithWarningFormat("[Vault name]"),
+			output.WithHighLightFormat("--secret-name api-key")),
+	})
+}
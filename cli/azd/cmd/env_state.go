@@ -0,0 +1,411 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func envStateActions(env *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := env.Add("state", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "state",
+			Short: "Manage the infrastructure state of the environment.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvStateHelpDescription,
+		},
+	})
+
+	group.Add("show", &actions.ActionDescriptorOptions{
+		Command:        newEnvStateShowCmd(),
+		FlagsResolver:  newEnvStateFlags,
+		ActionResolver: newEnvStateShowAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("pull", &actions.ActionDescriptorOptions{
+		Command:        newEnvStatePullCmd(),
+		FlagsResolver:  newEnvStatePullFlags,
+		ActionResolver: newEnvStatePullAction,
+	})
+
+	group.Add("unlock", &actions.ActionDescriptorOptions{
+		Command:        newEnvStateUnlockCmd(),
+		FlagsResolver:  newEnvStateFlags,
+		ActionResolver: newEnvStateUnlockAction,
+	})
+
+	return group
+}
+
+type envStateFlags struct {
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *envStateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvStateFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envStateFlags {
+	flags := &envStateFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type envStatePullFlags struct {
+	file string
+	envStateFlags
+}
+
+func (f *envStatePullFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envStateFlags.Bind(local, global)
+	local.StringVar(&f.file, "file", "", "Writes the state to the file instead of the standard output.")
+}
+
+func newEnvStatePullFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envStatePullFlags {
+	flags := &envStatePullFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvStateShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the state backend of the environment and the resources tracked by its state.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+func newEnvStatePullCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull",
+		Short: "Write the infrastructure state of the environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+func newEnvStateUnlockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock <lock-id>",
+		Short: "Remove the lock of the state held by an interrupted provisioning.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+// envStateAction creates the provisioning manager of the environment for the `azd env state` actions
+type envStateAction struct {
+	projectConfig              *project.ProjectConfig
+	projectManager             project.ProjectManager
+	azCli                      azcli.AzCli
+	accountManager             account.Manager
+	env                        *environment.Environment
+	commandRunner              exec.CommandRunner
+	console                    input.Console
+	alphaFeatureManager        *alpha.FeatureManager
+	userProfileService         *azcli.UserProfileService
+	subscriptionTenantResolver account.SubscriptionTenantResolver
+	noPrompt                   bool
+}
+
+func newEnvStateActionBase(
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	azCli azcli.AzCli,
+	accountManager account.Manager,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	alphaFeatureManager *alpha.FeatureManager,
+	userProfileService *azcli.UserProfileService,
+	subscriptionTenantResolver account.SubscriptionTenantResolver,
+	flags *envStateFlags,
+) envStateAction {
+	return envStateAction{
+		projectConfig:              projectConfig,
+		projectManager:             projectManager,
+		azCli:                      azCli,
+		accountManager:             accountManager,
+		env:                        env,
+		commandRunner:              commandRunner,
+		console:                    console,
+		alphaFeatureManager:        alphaFeatureManager,
+		userProfileService:         userProfileService,
+		subscriptionTenantResolver: subscriptionTenantResolver,
+		noPrompt:                   flags.global.NoPrompt,
+	}
+}
+
+func (a *envStateAction) newInfraManager(ctx context.Context) (*provisioning.Manager, error) {
+	if err := a.projectManager.Initialize(ctx, a.projectConfig); err != nil {
+		return nil, err
+	}
+
+	infraManager, err := provisioning.NewManager(
+		ctx,
+		a.env,
+		a.projectConfig.Path,
+		a.projectConfig.Infra,
+		!a.noPrompt,
+		a.azCli,
+		a.console,
+		a.commandRunner,
+		a.accountManager,
+		a.userProfileService,
+		a.subscriptionTenantResolver,
+		a.alphaFeatureManager,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating provisioning manager: %w", err)
+	}
+
+	return infraManager, nil
+}
+
+// stateError describes the errors of the providers without state backend
+func stateError(projectConfig *project.ProjectConfig, err error) error {
+	if errors.Is(err, provisioning.ErrStateBackendNotSupported) {
+		provider := projectConfig.Infra.Provider
+		if provider == "" {
+			provider = provisioning.Bicep
+		}
+
+		return fmt.Errorf(
+			"the %s provider doesn't store an infrastructure state, `azd env state` is supported by the terraform provider",
+			provider,
+		)
+	}
+
+	return err
+}
+
+type envStateShowAction struct {
+	envStateAction
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newEnvStateShowAction(
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	azCli azcli.AzCli,
+	accountManager account.Manager,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	alphaFeatureManager *alpha.FeatureManager,
+	userProfileService *azcli.UserProfileService,
+	subscriptionTenantResolver account.SubscriptionTenantResolver,
+	flags *envStateFlags,
+) actions.Action {
+	return &envStateShowAction{
+		envStateAction: newEnvStateActionBase(
+			projectConfig,
+			projectManager,
+			azCli,
+			accountManager,
+			env,
+			commandRunner,
+			console,
+			alphaFeatureManager,
+			userProfileService,
+			subscriptionTenantResolver,
+			flags,
+		),
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *envStateShowAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	infraManager, err := a.newInfraManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := infraManager.StateBackendInfo(ctx)
+	if err != nil {
+		return nil, stateError(a.projectConfig, err)
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(info, a.writer, nil)
+	}
+
+	fmt.Fprintf(a.writer, "Backend: %s\n", info.Kind)
+	fmt.Fprintf(a.writer, "Location: %s\n", info.Location)
+	if len(info.Resources) == 0 {
+		fmt.Fprintln(a.writer, "\nThe state doesn't track resources, the environment wasn't provisioned.")
+		return nil, nil
+	}
+
+	fmt.Fprintf(a.writer, "\nResources (%d):\n", len(info.Resources))
+	for _, resource := range info.Resources {
+		fmt.Fprintf(a.writer, "  %s\n", resource)
+	}
+
+	return nil, nil
+}
+
+type envStatePullAction struct {
+	envStateAction
+	file   string
+	writer io.Writer
+}
+
+func newEnvStatePullAction(
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	azCli azcli.AzCli,
+	accountManager account.Manager,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	writer io.Writer,
+	alphaFeatureManager *alpha.FeatureManager,
+	userProfileService *azcli.UserProfileService,
+	subscriptionTenantResolver account.SubscriptionTenantResolver,
+	flags *envStatePullFlags,
+) actions.Action {
+	return &envStatePullAction{
+		envStateAction: newEnvStateActionBase(
+			projectConfig,
+			projectManager,
+			azCli,
+			accountManager,
+			env,
+			commandRunner,
+			console,
+			alphaFeatureManager,
+			userProfileService,
+			subscriptionTenantResolver,
+			&flags.envStateFlags,
+		),
+		file:   flags.file,
+		writer: writer,
+	}
+}
+
+func (a *envStatePullAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	infraManager, err := a.newInfraManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := infraManager.PullState(ctx)
+	if err != nil {
+		return nil, stateError(a.projectConfig, err)
+	}
+
+	if a.file == "" {
+		_, err := a.writer.Write(state)
+		return nil, err
+	}
+
+	// The state contains the values of the outputs and attributes of the resources, which may be secrets
+	if err := os.WriteFile(a.file, state, 0600); err != nil {
+		return nil, fmt.Errorf("writing state: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Wrote the state of environment %s to %s", a.env.GetEnvName(), a.file),
+		},
+	}, nil
+}
+
+type envStateUnlockAction struct {
+	envStateAction
+	args []string
+}
+
+func newEnvStateUnlockAction(
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	azCli azcli.AzCli,
+	accountManager account.Manager,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	alphaFeatureManager *alpha.FeatureManager,
+	userProfileService *azcli.UserProfileService,
+	subscriptionTenantResolver account.SubscriptionTenantResolver,
+	flags *envStateFlags,
+	args []string,
+) actions.Action {
+	return &envStateUnlockAction{
+		envStateAction: newEnvStateActionBase(
+			projectConfig,
+			projectManager,
+			azCli,
+			accountManager,
+			env,
+			commandRunner,
+			console,
+			alphaFeatureManager,
+			userProfileService,
+			subscriptionTenantResolver,
+			flags,
+		),
+		args: args,
+	}
+}
+
+func (a *envStateUnlockAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	infraManager, err := a.newInfraManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = infraManager.UnlockState(ctx, a.args[0])
+	if errors.Is(err, provisioning.ErrStateNotLockable) {
+		return nil, fmt.Errorf("the state of environment %s is stored in a local file, which isn't locked by azd: %w",
+			a.env.GetEnvName(), err)
+	}
+	if err != nil {
+		return nil, stateError(a.projectConfig, err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Unlocked the state of environment %s", a.env.GetEnvName()),
+		},
+	}, nil
+}
+
+func getCmdEnvStateHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Show, pull and unlock the infrastructure state of the environment, for the terraform provider.",
+		[]string{
+			formatHelpNote(
+				"With infra.remoteState, the state of each environment is stored in an Azure Storage account " +
+					"created by azd, the state is locked during provisioning."),
+		},
+	)
+}
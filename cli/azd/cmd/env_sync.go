@@ -0,0 +1,235 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/commands/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type envSyncFlags struct {
+	global            *internal.GlobalCommandOptions
+	target            string
+	remote            string
+	gitHubEnvironment string
+	variableGroup     string
+	include           []string
+	exclude           []string
+	secrets           []string
+	dryRun            bool
+	envFlag
+}
+
+func (f *envSyncFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.target,
+		"target",
+		"",
+		"The CI system the values are synced to: github or azdo. (Default: the pipeline provider of the project)",
+	)
+	local.StringVar(&f.remote, "remote", "origin", "The git remote of the GitHub repository.")
+	local.StringVar(
+		&f.gitHubEnvironment,
+		"github-environment",
+		"",
+		"The GitHub deployment environment the values are synced to, instead of the repository.",
+	)
+	local.StringVar(
+		&f.variableGroup,
+		"variable-group",
+		"",
+		"The Azure DevOps variable group the values are synced to. (Default: azd-<environment>)",
+	)
+	local.StringArrayVar(&f.include, "include", nil, "Syncs only the values matching the pattern, ex. SERVICE_*.")
+	local.StringArrayVar(&f.exclude, "exclude", nil, "Doesn't sync the values matching the pattern.")
+	local.StringArrayVar(&f.secrets, "secret", nil, "Syncs the values matching the pattern as secrets.")
+	local.BoolVar(&f.dryRun, "dry-run", false, "Lists the values that would be synced without syncing them.")
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvSyncFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSyncFlags {
+	flags := &envSyncFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Sync environment values to the secrets and variables of GitHub or Azure DevOps.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envSyncAction struct {
+	flags         *envSyncFlags
+	azdCtx        *azdcontext.AzdContext
+	env           *environment.Environment
+	projectConfig *project.ProjectConfig
+	commandRunner exec.CommandRunner
+	console       input.Console
+	formatter     output.Formatter
+	writer        io.Writer
+}
+
+func newEnvSyncAction(
+	flags *envSyncFlags,
+	azdCtx *azdcontext.AzdContext,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &envSyncAction{
+		flags:         flags,
+		azdCtx:        azdCtx,
+		env:           env,
+		projectConfig: projectConfig,
+		commandRunner: commandRunner,
+		console:       console,
+		formatter:     formatter,
+		writer:        writer,
+	}
+}
+
+// EnvSyncResult lists the environment values synced to the CI system, never their values
+type EnvSyncResult struct {
+	Target string                  `json:"target"`
+	DryRun bool                    `json:"dryRun"`
+	Values []pipeline.EnvSyncValue `json:"values"`
+}
+
+type envSyncRow struct {
+	Key  string
+	Name string
+	Kind string
+}
+
+func (a *envSyncAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Flags extend the rules of azure.yaml
+	options := project.EnvSyncOptions{}
+	if a.projectConfig.Pipeline.Sync != nil {
+		options = *a.projectConfig.Pipeline.Sync
+	}
+	options.Include = append(options.Include, a.flags.include...)
+	options.Exclude = append(options.Exclude, a.flags.exclude...)
+	options.Secrets = append(options.Secrets, a.flags.secrets...)
+
+	values, err := pipeline.EnvSyncValues(a.env, &options)
+	if err != nil {
+		return nil, fmt.Errorf("selecting environment values: %w", err)
+	}
+
+	targetName := a.flags.target
+	if targetName == "" {
+		targetName = a.projectConfig.Pipeline.Provider
+	}
+	if targetName == "" {
+		targetName = pipeline.EnvSyncTargetGitHub
+	}
+
+	target, err := pipeline.NewEnvSyncTarget(ctx, pipeline.EnvSyncTargetOptions{
+		Target:            targetName,
+		RemoteName:        a.flags.remote,
+		GitHubEnvironment: a.flags.gitHubEnvironment,
+		VariableGroup:     a.flags.variableGroup,
+	}, a.env, a.azdCtx.ProjectDirectory(), a.commandRunner, a.console)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.flags.dryRun && len(values) > 0 {
+		stepMessage := fmt.Sprintf("Syncing %d value(s) to %s", len(values), target.Description())
+		a.console.ShowSpinner(ctx, stepMessage, input.Step)
+		err := target.Sync(ctx, values)
+		a.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := EnvSyncResult{Target: target.Description(), DryRun: a.flags.dryRun, Values: values}
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(result, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("sync result could not be displayed: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	if len(values) == 0 {
+		a.console.Message(ctx, "No environment values match the sync rules.")
+		return nil, nil
+	}
+
+	rows := make([]envSyncRow, 0, len(values))
+	for _, value := range values {
+		kind := "variable"
+		if value.Secret {
+			kind = "secret"
+		}
+
+		rows = append(rows, envSyncRow{Key: value.Key, Name: value.Name, Kind: kind})
+	}
+
+	a.console.Message(ctx, "")
+	if err := a.formatter.Format(rows, a.writer, output.TableFormatterOptions{
+		Columns: []output.Column{
+			{Heading: "KEY", ValueTemplate: "{{.Key}}"},
+			{Heading: "NAME", ValueTemplate: "{{.Name}}"},
+			{Heading: "TYPE", ValueTemplate: "{{.Kind}}"},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("sync result could not be displayed: %w", err)
+	}
+
+	if a.flags.dryRun {
+		a.console.Message(ctx, fmt.Sprintf("\nDry run, nothing was synced to %s.", target.Description()))
+	}
+
+	return nil, nil
+}
+
+func getCmdEnvSyncHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Push the values of the environment to the secrets and variables of the GitHub repository or the Azure DevOps"+
+			" variable group of the project, keeping CI aligned with the local environment.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Select the values with %s in azure.yaml: include, exclude, secrets and map"+
+				" patterns, ex. SERVICE_*.", output.WithHighLightFormat("pipeline.sync"))),
+			formatHelpNote("Keys naming secrets, passwords, tokens, keys and connection strings are synced as secrets" +
+				" unless azure.yaml sets the secrets patterns."),
+			formatHelpNote(fmt.Sprintf("Azure DevOps projects are configured by %s.",
+				output.WithHighLightFormat("azd pipeline config --provider azdo"))),
+		})
+}
+
+func getCmdEnvSyncHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Sync the environment to the secrets and variables of the GitHub repository.": output.WithHighLightFormat(
+			"azd env sync --target github"),
+		"Sync the environment to a GitHub deployment environment.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd env sync --github-environment"),
+			output.WithWarningFormat("[Environment name]")),
+		"List the values synced to the Azure DevOps variable group without syncing them.": output.WithHighLightFormat(
+			"azd env sync --target azdo --dry-run"),
+	})
+}
@@ -0,0 +1,1181 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/blang/semver/v4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/propagation"
+	"golang.org/x/exp/slices"
+)
+
+func extensionActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("extension", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:     "extension",
+			Short:   fmt.Sprintf("Manage azd extensions. %s", output.WithWarningFormat("(Beta)")),
+			Aliases: []string{"ext"},
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdExtensionHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	group.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newExtensionListCmd(),
+		ActionResolver: newExtensionListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	group.Add("install", &actions.ActionDescriptorOptions{
+		Command:         newExtensionInstallCmd(),
+		FlagsResolver:   newExtensionInstallFlags,
+		ActionResolver:  newExtensionInstallAction,
+		RequiresNetwork: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdExtensionInstallHelpFooter,
+		},
+	})
+
+	group.Add("upgrade", &actions.ActionDescriptorOptions{
+		Command:         newExtensionUpgradeCmd(),
+		FlagsResolver:   newExtensionUpgradeFlags,
+		ActionResolver:  newExtensionUpgradeAction,
+		RequiresNetwork: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdExtensionUpgradeHelpFooter,
+		},
+	})
+
+	group.Add("remove", &actions.ActionDescriptorOptions{
+		Command:        newExtensionRemoveCmd(),
+		ActionResolver: newExtensionRemoveAction,
+	})
+
+	sourceGroup := group.Add("source", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "source",
+			Short: "Manage the registries extensions are installed from.",
+		},
+	})
+
+	sourceGroup.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newExtensionSourceListCmd(),
+		ActionResolver: newExtensionSourceListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	sourceGroup.Add("add", &actions.ActionDescriptorOptions{
+		Command:        newExtensionSourceAddCmd(),
+		FlagsResolver:  newExtensionSourceAddFlags,
+		ActionResolver: newExtensionSourceAddAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdExtensionSourceAddHelpFooter,
+		},
+	})
+
+	sourceGroup.Add("remove", &actions.ActionDescriptorOptions{
+		Command:        newExtensionSourceRemoveCmd(),
+		ActionResolver: newExtensionSourceRemoveAction,
+	})
+
+	return group
+}
+
+// Registers a root command for each installed extension.
+// Extension commands run through the same middleware pipeline as built-in commands.
+func extensionCommands(root *actions.ActionDescriptor, container *ioc.NestedContainer) {
+	var manager *extensions.Manager
+	if err := container.Resolve(&manager); err != nil {
+		log.Printf("failed resolving extensions manager: %v\n", err)
+		return
+	}
+
+	installed, err := manager.List()
+	if err != nil {
+		log.Printf("failed listing installed extensions: %v\n", err)
+		installed = []*extensions.Extension{}
+	}
+
+	// Installed extensions take precedence over the commands declared within the config, which take precedence over
+	// the azd-<name> executables on PATH
+	commands := append(installed, declaredExtensionCommands(container)...)
+	commands = append(commands, extensions.DiscoverPathCommands(os.Getenv("PATH"))...)
+
+	registered := map[string]struct{}{}
+	for _, child := range root.Children() {
+		registered[child.Name] = struct{}{}
+	}
+
+	for _, extension := range commands {
+		if _, has := registered[extension.Name]; has {
+			log.Printf(
+				"skipping extension '%s' at '%s' since it conflicts with another command\n",
+				extension.Name,
+				extension.EntryPointPath(),
+			)
+			continue
+		}
+
+		registered[extension.Name] = struct{}{}
+		root.Add(extension.Name, &actions.ActionDescriptorOptions{
+			Command:        newExtensionCmd(extension),
+			ActionResolver: newExtensionActionResolver(extension),
+			GroupingOptions: actions.CommandGroupOptions{
+				RootLevelHelp: actions.CmdGroupExtensions,
+			},
+		})
+	}
+}
+
+// Gets the extension commands declared within the extensions.commands user config
+func declaredExtensionCommands(container *ioc.NestedContainer) []*extensions.Extension {
+	var userConfigManager config.UserConfigManager
+	if err := container.Resolve(&userConfigManager); err != nil {
+		log.Printf("failed resolving user config manager: %v\n", err)
+		return nil
+	}
+
+	azdConfig, err := userConfigManager.Load()
+	if err != nil {
+		log.Printf("failed loading user config, skipping declared extension commands: %v\n", err)
+		return nil
+	}
+
+	value, has := azdConfig.Get(extensions.CommandsConfigPath)
+	if !has {
+		return nil
+	}
+
+	declared, ok := value.(map[string]any)
+	if !ok {
+		log.Printf("skipping declared extension commands: %s must be an object\n", extensions.CommandsConfigPath)
+		return nil
+	}
+
+	return extensions.DeclaredCommands(declared)
+}
+
+// Registers the service targets & infrastructure providers implemented by installed extensions.
+// Service targets are registered by their host kind so services within azure.yaml can reference them.
+func registerExtensionContributions(container *ioc.NestedContainer) {
+	var manager *extensions.Manager
+	if err := container.Resolve(&manager); err != nil {
+		log.Printf("failed resolving extensions manager: %v\n", err)
+		return
+	}
+
+	installed, err := manager.List()
+	if err != nil {
+		log.Printf("failed listing installed extensions: %v\n", err)
+		return
+	}
+
+	extensions.RegisterProviders(installed)
+
+	serviceTargets, err := manager.ServiceTargets()
+	if err != nil {
+		log.Printf("failed listing extension service targets: %v\n", err)
+		return
+	}
+
+	for kind, extension := range serviceTargets {
+		targetKind := project.ServiceTargetKind(kind)
+		if err := project.RegisterExternalServiceTarget(targetKind); err != nil {
+			log.Printf("skipping service target from extension '%s': %v\n", extension.Name, err)
+			continue
+		}
+
+		extension := extension
+		constructor := func(runner *extensions.Runner, env *environment.Environment) project.ServiceTarget {
+			return extensions.NewServiceTarget(extension, targetKind, runner, env)
+		}
+
+		if err := container.RegisterNamedSingleton(kind, constructor); err != nil {
+			panic(fmt.Errorf("registering service target %s: %w", kind, err))
+		}
+	}
+}
+
+func newExtensionCmd(extension *extensions.Extension) *cobra.Command {
+	use := extension.Name
+	if extension.Usage != "" {
+		use = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(extension.Usage, "azd "), extension.Name))
+		use = strings.TrimSpace(fmt.Sprintf("%s %s", extension.Name, use))
+	}
+
+	short := extension.Description
+	if short == "" {
+		short = fmt.Sprintf("Runs the %s extension.", extension.Name)
+	}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		// All arguments and flags are forwarded to the extension, including help
+		DisableFlagParsing: true,
+		Annotations: map[string]string{
+			middleware.ExtensionCommandAnnotation: extension.Name,
+		},
+	}
+
+	if extension.Completion {
+		cmd.ValidArgsFunction = func(
+			cmd *cobra.Command,
+			args []string,
+			toComplete string,
+		) ([]string, cobra.ShellCompDirective) {
+			var runner *extensions.Runner
+			if err := ioc.Global.Resolve(&runner); err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return runner.Complete(cmd.Context(), extension, args, toComplete)
+		}
+	}
+
+	return cmd
+}
+
+// Creates the action resolver for the specified extension
+func newExtensionActionResolver(extension *extensions.Extension) any {
+	return func(
+		runner *extensions.Runner,
+		console input.Console,
+		envResolver environment.EnvironmentResolver,
+		credentialProvider CredentialProviderFn,
+		subResolver account.SubscriptionTenantResolver,
+		rootOptions *internal.GlobalCommandOptions,
+		cmd *cobra.Command,
+		args []string,
+	) actions.Action {
+		return &extensionAction{
+			extension:          extension,
+			runner:             runner,
+			console:            console,
+			envResolver:        envResolver,
+			credentialProvider: credentialProvider,
+			subResolver:        subResolver,
+			rootOptions:        rootOptions,
+			cmd:                cmd,
+			args:               args,
+		}
+	}
+}
+
+type extensionAction struct {
+	extension          *extensions.Extension
+	runner             *extensions.Runner
+	console            input.Console
+	envResolver        environment.EnvironmentResolver
+	credentialProvider CredentialProviderFn
+	subResolver        account.SubscriptionTenantResolver
+	rootOptions        *internal.GlobalCommandOptions
+	cmd                *cobra.Command
+	args               []string
+}
+
+func (a *extensionAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	origin := a.extension.Origin
+	if origin == extensions.OriginInstalled {
+		origin = "installed"
+	}
+
+	tracing.SetUsageAttributes(
+		fields.StringHashed(fields.ExtensionNameKey, a.extension.Name),
+		fields.ExtensionOriginKey.String(origin),
+	)
+
+	// The values of the default azd environment are available to extensions with the environment.read permission
+	env, err := a.envResolver()
+	if err != nil {
+		env = nil
+	}
+
+	envVars := a.extension.Environ(env)
+
+	// Extensions call back into azd through the extension host while they run.
+	// The host only grants the capabilities allowed by the permissions of the extension.
+	host := extensions.NewHost(a.extension, a.console, env, extensions.HostOptions{
+		AllowedCapabilities: a.extension.AllowedCapabilities(),
+		Credential: func(ctx context.Context) (azcore.TokenCredential, error) {
+			return a.credentialProvider(ctx, nil)
+		},
+	})
+	hostEnv, err := host.Start()
+	if err != nil {
+		return nil, err
+	}
+	defer host.Stop()
+
+	envVars = append(envVars, hostEnv...)
+
+	handshakeEnv, removeHandshake, err := extensions.WriteHandshake(a.handshake(ctx, env, hostEnv))
+	if err != nil {
+		return nil, err
+	}
+	defer removeHandshake()
+
+	envVars = append(envVars, handshakeEnv)
+
+	log.Printf(
+		"running extension '%s' from '%s' (origin: %s)\n", a.extension.Name, a.extension.EntryPointPath(), origin)
+
+	_, err = a.runner.Invoke(ctx, a.extension, &extensions.InvokeOptions{
+		Args:        a.args,
+		Env:         envVars,
+		Interactive: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("extension '%s' failed: %w", a.extension.Name, err)
+	}
+
+	return nil, nil
+}
+
+// Creates the handshake describing the context the extension runs in.
+// The environment and the account are only described to extensions with the corresponding permissions.
+func (a *extensionAction) handshake(
+	ctx context.Context,
+	env *environment.Environment,
+	hostEnv []string,
+) *extensions.Handshake {
+	// Flags aren't parsed for extension commands, --debug and --no-prompt are forwarded to the extension
+	handshake := &extensions.Handshake{
+		Version:  extensions.HandshakeVersion,
+		Command:  a.cmd.CommandPath(),
+		Debug:    a.rootOptions.EnableDebugLogging || slices.Contains(a.args, "--debug"),
+		NoPrompt: a.rootOptions.NoPrompt || slices.Contains(a.args, "--no-prompt"),
+		Args:     append([]string{}, a.args...),
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	handshake.TraceParent = carrier.Get("traceparent")
+
+	for _, value := range hostEnv {
+		name, value, _ := strings.Cut(value, "=")
+		switch name {
+		case extensions.HostAddressEnvVarName:
+			handshake.Host.Address = value
+		case extensions.HostAccessTokenEnvVarName:
+			handshake.Host.AccessToken = value
+		}
+	}
+
+	if env == nil {
+		return handshake
+	}
+
+	if a.extension.HasPermission(extensions.PermissionEnvironmentRead) {
+		handshake.Environment = &extensions.HandshakeEnvironment{
+			Name:   env.GetEnvName(),
+			Values: env.Dotenv(),
+		}
+	}
+
+	if a.extension.HasPermission(extensions.PermissionTokens) && env.GetSubscriptionId() != "" {
+		handshake.Auth = &extensions.HandshakeAuth{SubscriptionId: env.GetSubscriptionId()}
+
+		// Extensions can still acquire tokens from the host when the tenant can't be resolved, ex. offline
+		tenantId, err := a.subResolver.LookupTenant(ctx, env.GetSubscriptionId())
+		if err != nil {
+			log.Printf("failed resolving the tenant of the subscription for the extension handshake: %v\n", err)
+		}
+		handshake.Auth.TenantId = tenantId
+	}
+
+	return handshake
+}
+
+func newExtensionListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List installed extensions.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+type extensionListAction struct {
+	manager   *extensions.Manager
+	console   input.Console
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newExtensionListAction(
+	manager *extensions.Manager,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &extensionListAction{
+		manager:   manager,
+		console:   console,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *extensionListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	installed, err := a.manager.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() != output.TableFormat {
+		return nil, a.formatter.Format(installed, a.writer, nil)
+	}
+
+	if len(installed) == 0 {
+		a.console.Message(ctx, "No extensions are installed.")
+		return nil, nil
+	}
+
+	columns := []output.Column{
+		{
+			Heading:       "NAME",
+			ValueTemplate: "{{.Name}}",
+		},
+		{
+			Heading:       "VERSION",
+			ValueTemplate: "{{.Version}}",
+		},
+		{
+			Heading:       "PERMISSIONS",
+			ValueTemplate: `{{range $i, $p := .Permissions}}{{if $i}}, {{end}}{{$p}}{{end}}`,
+		},
+		{
+			Heading:       "DESCRIPTION",
+			ValueTemplate: "{{.Description}}",
+		},
+	}
+
+	return nil, a.formatter.Format(installed, a.writer, output.TableFormatterOptions{
+		Columns: columns,
+	})
+}
+
+type extensionInstallFlags struct {
+	force             bool
+	version           string
+	source            string
+	channel           string
+	acceptPermissions bool
+	global            *internal.GlobalCommandOptions
+}
+
+func (f *extensionInstallFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.force, "force", false, "Replaces the extension when it is already installed.")
+	local.StringVar(&f.version, "version", "", "The version of the extension to install from a registry.")
+	local.StringVar(&f.source, "source", "", "The name of the extension source to install from.")
+	local.StringVar(
+		&f.channel,
+		"channel",
+		extensions.ChannelStable,
+		"The update channel used to select the latest version. (stable, preview)",
+	)
+	local.BoolVar(
+		&f.acceptPermissions,
+		"accept-permissions",
+		false,
+		"Accepts the permissions requested by the extension without prompting.",
+	)
+	f.global = global
+}
+
+func newExtensionInstallFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *extensionInstallFlags {
+	flags := &extensionInstallFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newExtensionInstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "install <name|path>",
+		Short: "Installs an extension from an extension source or a local directory.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type extensionInstallAction struct {
+	manager  *extensions.Manager
+	registry *extensions.Registry
+	console  input.Console
+	cmd      *cobra.Command
+	flags    *extensionInstallFlags
+	args     []string
+}
+
+func newExtensionInstallAction(
+	manager *extensions.Manager,
+	registry *extensions.Registry,
+	console input.Console,
+	cmd *cobra.Command,
+	flags *extensionInstallFlags,
+	args []string,
+) actions.Action {
+	return &extensionInstallAction{
+		manager:  manager,
+		registry: registry,
+		console:  console,
+		cmd:      cmd,
+		flags:    flags,
+		args:     args,
+	}
+}
+
+func (a *extensionInstallAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Command title
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Installing extension (azd extension install)",
+	})
+
+	var extension *extensions.Extension
+	var err error
+
+	// Local paths are installed directly, otherwise the extension is installed from the configured sources
+	if _, statErr := os.Stat(a.args[0]); statErr == nil {
+		extension, err = a.installFromPath(ctx)
+	} else {
+		extension, err = a.installFromRegistry(ctx)
+	}
+
+	if errors.Is(err, extensions.ErrExtensionAlreadyInstalled) {
+		return nil, fmt.Errorf("%w. Use --force to replace the installed extension", err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := ensureNoBuiltInConflict(a.cmd, a.manager, extension); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Extension '%s' version '%s' was installed.", extension.Name, extension.Version),
+			FollowUp: fmt.Sprintf("Run %s to get started.",
+				output.WithHighLightFormat(fmt.Sprintf("azd %s --help", extension.Name))),
+		},
+	}, nil
+}
+
+func (a *extensionInstallAction) installFromPath(ctx context.Context) (*extensions.Extension, error) {
+	extension, err := extensions.LoadSource(a.args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.confirmPermissions(ctx, extension); err != nil {
+		return nil, err
+	}
+
+	return a.manager.Install(a.args[0], a.flags.force)
+}
+
+func (a *extensionInstallAction) installFromRegistry(ctx context.Context) (*extensions.Extension, error) {
+	match, err := a.registry.Find(ctx, a.args[0], a.flags.version, a.flags.channel, a.flags.source)
+	if err != nil {
+		return nil, err
+	}
+
+	return downloadAndInstall(ctx, a.console, a.manager, a.registry, match, a.flags.force, a.confirmPermissions)
+}
+
+// Permissions are confirmed for new installs. Replacing an installed extension only confirms added permissions.
+func (a *extensionInstallAction) confirmPermissions(ctx context.Context, extension *extensions.Extension) error {
+	var previous *extensions.Manifest
+	if installed, err := a.manager.Get(extension.Name); err == nil {
+		previous = &installed.Manifest
+	}
+
+	return confirmExtensionPermissions(ctx, a.console, extension, previous, a.flags.acceptPermissions)
+}
+
+// Downloads, verifies and installs the matched extension version.
+// The permissions of the downloaded extension are confirmed before it is installed.
+func downloadAndInstall(
+	ctx context.Context,
+	console input.Console,
+	manager *extensions.Manager,
+	registry *extensions.Registry,
+	match *extensions.RegistryMatch,
+	force bool,
+	confirm func(ctx context.Context, extension *extensions.Extension) error,
+) (*extensions.Extension, error) {
+	stepMessage := fmt.Sprintf("Downloading %s (%s)", match.Extension.Name, match.Version.Version)
+	console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	dir, metadata, err := registry.Download(ctx, match)
+	console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	downloaded, err := extensions.LoadSource(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := confirm(ctx, downloaded); err != nil {
+		return nil, err
+	}
+
+	return manager.InstallDownloaded(dir, metadata, force)
+}
+
+// Shows the permissions requested by the extension that weren't granted to the previous version and confirms them.
+// Declining the permissions cancels the install.
+func confirmExtensionPermissions(
+	ctx context.Context,
+	console input.Console,
+	extension *extensions.Extension,
+	previous *extensions.Manifest,
+	accept bool,
+) error {
+	permissions := extension.NewPermissions(previous)
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("Extension '%s' requests the following permissions:", extension.Name),
+	}
+	advisory := false
+	for _, permission := range permissions {
+		description := extensions.DescribePermission(permission)
+		if extensions.IsAdvisoryPermission(permission) {
+			description += ", advisory"
+			advisory = true
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"  - %s %s",
+			output.WithHighLightFormat(permission),
+			output.WithGrayFormat("(%s)", description),
+		))
+	}
+	if advisory {
+		lines = append(lines, output.WithGrayFormat(
+			"Advisory permissions aren't enforced by azd, the extension runs as a normal process with your access."))
+	}
+	console.Message(ctx, strings.Join(lines, "\n"))
+
+	if accept {
+		return nil
+	}
+
+	confirmed, err := console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Do you want to grant these permissions?",
+		DefaultValue: false,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return fmt.Errorf(
+			"permissions for extension '%s' were not granted. Use --accept-permissions to grant them without prompting",
+			extension.Name,
+		)
+	}
+
+	return nil
+}
+
+// Extensions that conflict with built-in commands would never be reachable
+func ensureNoBuiltInConflict(cmd *cobra.Command, manager *extensions.Manager, extension *extensions.Extension) error {
+	for _, command := range cmd.Root().Commands() {
+		group, _ := actions.GetGroupCommandAnnotation(command)
+		if command.Name() == extension.Name && group != string(actions.CmdGroupExtensions) {
+			if err := manager.Remove(extension.Name); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("extension '%s' conflicts with the built-in '%s' command", extension.Name, command.Name())
+		}
+	}
+
+	return nil
+}
+
+type extensionUpgradeFlags struct {
+	all               bool
+	channel           string
+	acceptPermissions bool
+	global            *internal.GlobalCommandOptions
+}
+
+func (f *extensionUpgradeFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.all, "all", false, "Upgrades all extensions installed from an extension source.")
+	local.StringVar(
+		&f.channel,
+		"channel",
+		"",
+		"The update channel used to select the latest version. Defaults to the channel the extension was installed from.",
+	)
+	local.BoolVar(
+		&f.acceptPermissions,
+		"accept-permissions",
+		false,
+		"Accepts new permissions requested by upgraded extensions without prompting.",
+	)
+	f.global = global
+}
+
+func newExtensionUpgradeFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *extensionUpgradeFlags {
+	flags := &extensionUpgradeFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newExtensionUpgradeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade [name]",
+		Short: "Upgrades installed extensions to the latest version.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type extensionUpgradeAction struct {
+	manager  *extensions.Manager
+	registry *extensions.Registry
+	console  input.Console
+	flags    *extensionUpgradeFlags
+	args     []string
+}
+
+func newExtensionUpgradeAction(
+	manager *extensions.Manager,
+	registry *extensions.Registry,
+	console input.Console,
+	flags *extensionUpgradeFlags,
+	args []string,
+) actions.Action {
+	return &extensionUpgradeAction{
+		manager:  manager,
+		registry: registry,
+		console:  console,
+		flags:    flags,
+		args:     args,
+	}
+}
+
+func (a *extensionUpgradeAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.all == (len(a.args) == 1) {
+		return nil, errors.New("specify the name of an extension or --all")
+	}
+
+	// Command title
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Upgrading extensions (azd extension upgrade)",
+	})
+
+	var targets []*extensions.Extension
+	if a.flags.all {
+		installed, err := a.manager.List()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, extension := range installed {
+			if extension.Install != nil {
+				targets = append(targets, extension)
+			}
+		}
+	} else {
+		extension, err := a.manager.Get(a.args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if extension.Install == nil {
+			return nil, fmt.Errorf(
+				"extension '%s' was installed from a local directory and can't be upgraded from an extension source",
+				extension.Name,
+			)
+		}
+
+		targets = append(targets, extension)
+	}
+
+	upgraded := 0
+	// With --all a failing extension doesn't prevent the remaining extensions from being upgraded
+	failures := []error{}
+	for _, extension := range targets {
+		channel := a.flags.channel
+		if channel == "" {
+			channel = extension.Install.Channel
+		}
+
+		match, err := a.registry.Find(ctx, extension.Name, "", channel, extension.Install.Source)
+		if err != nil {
+			if !a.flags.all {
+				return nil, err
+			}
+
+			failures = append(failures, fmt.Errorf("extension '%s': %w", extension.Name, err))
+			continue
+		}
+
+		if !isNewerVersion(match.Version.Version, extension.Version) {
+			a.console.Message(ctx, fmt.Sprintf("Extension '%s' is up to date (%s).", extension.Name, extension.Version))
+			continue
+		}
+
+		// Only permissions added by the new version need to be granted
+		previous := &extension.Manifest
+		confirm := func(ctx context.Context, downloaded *extensions.Extension) error {
+			return confirmExtensionPermissions(ctx, a.console, downloaded, previous, a.flags.acceptPermissions)
+		}
+
+		if _, err := downloadAndInstall(ctx, a.console, a.manager, a.registry, match, true, confirm); err != nil {
+			if !a.flags.all {
+				return nil, err
+			}
+
+			failures = append(failures, fmt.Errorf("extension '%s': %w", extension.Name, err))
+			continue
+		}
+
+		a.console.Message(ctx, fmt.Sprintf(
+			"Extension '%s' was upgraded from %s to %s.",
+			extension.Name,
+			extension.Version,
+			match.Version.Version,
+		))
+		upgraded++
+	}
+
+	if len(failures) > 0 {
+		return nil, fmt.Errorf(
+			"%d extension(s) upgraded, %d extension(s) failed to upgrade:\n%w",
+			upgraded,
+			len(failures),
+			errors.Join(failures...),
+		)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("%d extension(s) upgraded.", upgraded),
+		},
+	}, nil
+}
+
+// Returns true when the available version is newer than the installed version
+func isNewerVersion(available string, installed string) bool {
+	availableVersion, err := semver.ParseTolerant(available)
+	if err != nil {
+		return false
+	}
+
+	installedVersion, err := semver.ParseTolerant(installed)
+	if err != nil {
+		return true
+	}
+
+	return availableVersion.GT(installedVersion)
+}
+
+func newExtensionRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Removes an installed extension.",
+		Aliases: []string{"uninstall"},
+		Args:    cobra.ExactArgs(1),
+	}
+}
+
+type extensionRemoveAction struct {
+	manager *extensions.Manager
+	args    []string
+}
+
+func newExtensionRemoveAction(manager *extensions.Manager, args []string) actions.Action {
+	return &extensionRemoveAction{
+		manager: manager,
+		args:    args,
+	}
+}
+
+func (a *extensionRemoveAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if err := a.manager.Remove(a.args[0]); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Extension '%s' was removed.", a.args[0]),
+		},
+	}, nil
+}
+
+func newExtensionSourceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the configured extension sources.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+type extensionSourceListAction struct {
+	registry  *extensions.Registry
+	console   input.Console
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newExtensionSourceListAction(
+	registry *extensions.Registry,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &extensionSourceListAction{
+		registry:  registry,
+		console:   console,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *extensionSourceListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	sources, err := a.registry.Sources()
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() != output.TableFormat {
+		return nil, a.formatter.Format(sources, a.writer, nil)
+	}
+
+	if len(sources) == 0 {
+		a.console.Message(ctx, "No extension sources are configured.")
+		return nil, nil
+	}
+
+	columns := []output.Column{
+		{
+			Heading:       "NAME",
+			ValueTemplate: "{{.Name}}",
+		},
+		{
+			Heading:       "URL",
+			ValueTemplate: "{{.Url}}",
+		},
+	}
+
+	return nil, a.formatter.Format(sources, a.writer, output.TableFormatterOptions{
+		Columns: columns,
+	})
+}
+
+type extensionSourceAddFlags struct {
+	publicKey string
+	global    *internal.GlobalCommandOptions
+}
+
+func (f *extensionSourceAddFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.publicKey,
+		"public-key",
+		"",
+		"The base64 encoded ed25519 public key used to verify the signatures of extensions from the source.",
+	)
+	f.global = global
+}
+
+func newExtensionSourceAddFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *extensionSourceAddFlags {
+	flags := &extensionSourceAddFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newExtensionSourceAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <name> <url>",
+		Short: "Adds an extension source.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+type extensionSourceAddAction struct {
+	registry *extensions.Registry
+	flags    *extensionSourceAddFlags
+	args     []string
+}
+
+func newExtensionSourceAddAction(
+	registry *extensions.Registry,
+	flags *extensionSourceAddFlags,
+	args []string,
+) actions.Action {
+	return &extensionSourceAddAction{
+		registry: registry,
+		flags:    flags,
+		args:     args,
+	}
+}
+
+func (a *extensionSourceAddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.publicKey == "" {
+		return nil, errors.New(
+			"--public-key is required, the public key used to verify the signatures of extensions from the source",
+		)
+	}
+
+	err := a.registry.AddSource(&extensions.Source{
+		Name:      a.args[0],
+		Url:       a.args[1],
+		PublicKey: a.flags.publicKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Extension source '%s' was added.", a.args[0]),
+		},
+	}, nil
+}
+
+func newExtensionSourceRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Removes an extension source.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type extensionSourceRemoveAction struct {
+	registry *extensions.Registry
+	args     []string
+}
+
+func newExtensionSourceRemoveAction(registry *extensions.Registry, args []string) actions.Action {
+	return &extensionSourceRemoveAction{
+		registry: registry,
+		args:     args,
+	}
+}
+
+func (a *extensionSourceRemoveAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if err := a.registry.RemoveSource(a.args[0]); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Extension source '%s' was removed.", a.args[0]),
+		},
+	}, nil
+}
+
+func getCmdExtensionHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf("Manage azd extensions. %s", output.WithWarningFormat("(Beta)")),
+		[]string{
+			formatHelpNote(fmt.Sprintf("Extensions are described by an %s manifest and add new commands to azd.",
+				output.WithLinkFormat(extensions.ManifestFileName))),
+			formatHelpNote(fmt.Sprintf("Extensions declare the %s they require within their manifest."+
+				" Permissions are shown for review when an extension is installed or upgraded.",
+				output.WithHighLightFormat("permissions"))),
+			formatHelpNote(fmt.Sprintf("Extensions with the %s permission receive the values of the current azd"+
+				" environment as environment variables. azd enforces the environment and token permissions,"+
+				" the network and tools permissions are advisory and aren't enforced since the extension runs as"+
+				" a normal process.",
+				output.WithHighLightFormat(extensions.PermissionEnvironmentRead))),
+			formatHelpNote(fmt.Sprintf("Extensions can call back into azd over gRPC using the address in %s to"+
+				" report progress, prompt and access environment values.",
+				output.WithHighLightFormat(extensions.HostAddressEnvVarName))),
+			formatHelpNote("Extensions installed from an extension source are verified against the public key" +
+				" of the source before they are installed."),
+			formatHelpNote(fmt.Sprintf("Extensions can provide service hosts by listing them under %s."+
+				" Services using those hosts are packaged and deployed by the extension.",
+				output.WithHighLightFormat("serviceTargets"))),
+			formatHelpNote(fmt.Sprintf("Extensions can provide infrastructure providers by listing them under %s."+
+				" Set %s within azure.yaml to provision with the extension.",
+				output.WithHighLightFormat("providers"),
+				output.WithHighLightFormat("infra.provider"))),
+		})
+}
+
+func getCmdExtensionInstallHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Installs the extension from a local directory.": output.WithHighLightFormat(
+			"azd extension install ./my-extension",
+		),
+		"Replaces an installed extension with a new version.": output.WithHighLightFormat(
+			"azd extension install ./my-extension --force",
+		),
+		"Installs the latest stable version of an extension from the configured sources.": output.WithHighLightFormat(
+			"azd extension install hello",
+		),
+		"Installs a specific version of an extension from an extension source.": output.WithHighLightFormat(
+			"azd extension install hello --version 1.2.0 --source contoso",
+		),
+		"Installs an extension and grants its permissions without prompting.": output.WithHighLightFormat(
+			"azd extension install hello --accept-permissions",
+		),
+	})
+}
+
+func getCmdExtensionUpgradeHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Upgrades an extension to the latest version.": output.WithHighLightFormat(
+			"azd extension upgrade hello",
+		),
+		"Upgrades all extensions installed from an extension source.": output.WithHighLightFormat(
+			"azd extension upgrade --all",
+		),
+		"Upgrades all extensions including preview versions.": output.WithHighLightFormat(
+			"azd extension upgrade --all --channel preview",
+		),
+	})
+}
+
+func getCmdExtensionSourceAddHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Adds an extension source published by your organization.": output.WithHighLightFormat(
+			"azd extension source add contoso https://extensions.contoso.com/index.json --public-key <key>",
+		),
+	})
+}
@@ -0,0 +1,226 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type healthFlags struct {
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *healthFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newHealthFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *healthFlags {
+	flags := &healthFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newHealthCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "health [<service>]",
+		Short: "Evaluate the health of the application's services.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type healthAction struct {
+	args          []string
+	console       input.Console
+	formatter     output.Formatter
+	writer        io.Writer
+	env           *environment.Environment
+	projectConfig *project.ProjectConfig
+	healthChecker *project.ServiceHealthChecker
+}
+
+func newHealthAction(
+	args []string,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	healthChecker *project.ServiceHealthChecker,
+	_ *healthFlags,
+) actions.Action {
+	return &healthAction{
+		args:          args,
+		console:       console,
+		formatter:     formatter,
+		writer:        writer,
+		env:           env,
+		projectConfig: projectConfig,
+		healthChecker: healthChecker,
+	}
+}
+
+// The row of each check in the table output of `azd health`
+type healthCheckRow struct {
+	Service string
+	contracts.HealthCheck
+}
+
+func (a *healthAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	services := a.projectConfig.GetServicesStable()
+	if len(a.args) == 1 {
+		if !a.projectConfig.HasService(a.args[0]) {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", a.args[0])
+		}
+
+		services = []*project.ServiceConfig{a.projectConfig.Services[a.args[0]]}
+	}
+
+	progress := newServiceProgress(a.console, "Checking")
+	results, errs := async.RunParallel(ctx, services, len(services),
+		func(ctx context.Context, svc *project.ServiceConfig) (*project.ServiceHealth, error) {
+			progress.Start(ctx, svc.Name)
+			health, err := a.healthChecker.Check(ctx, svc)
+
+			switch {
+			case err != nil:
+				progress.Stop(ctx, svc.Name, input.StepFailed, nil)
+			case health.Status == project.HealthStatusHealthy:
+				progress.Stop(ctx, svc.Name, input.StepDone, nil)
+			case health.Status == project.HealthStatusUnhealthy:
+				progress.Stop(ctx, svc.Name, input.StepFailed, nil)
+			default:
+				progress.Stop(ctx, svc.Name, input.StepWarning, nil)
+			}
+
+			return health, err
+		})
+
+	healthResult := contracts.HealthResult{
+		Status:   string(project.HealthStatusHealthy),
+		Services: make([]contracts.ServiceHealth, 0, len(services)),
+	}
+	rows := []healthCheckRow{}
+	unhealthy := 0
+
+	for i, svc := range services {
+		serviceHealth := contracts.ServiceHealth{
+			Name:   svc.Name,
+			Status: string(project.HealthStatusUnknown),
+			Checks: []contracts.HealthCheck{},
+		}
+
+		if errs[i] != nil {
+			serviceHealth.Status = string(project.HealthStatusUnhealthy)
+			serviceHealth.Error = errs[i].Error()
+			rows = append(rows, healthCheckRow{
+				Service: svc.Name,
+				HealthCheck: contracts.HealthCheck{
+					Name:    "-",
+					Status:  serviceHealth.Status,
+					Message: serviceHealth.Error,
+				},
+			})
+		} else {
+			serviceHealth.Status = string(results[i].Status)
+			for _, check := range results[i].Checks {
+				healthCheck := contracts.HealthCheck{
+					Name:    check.Name,
+					Status:  string(check.Status),
+					Message: check.Message,
+				}
+
+				serviceHealth.Checks = append(serviceHealth.Checks, healthCheck)
+				rows = append(rows, healthCheckRow{Service: svc.Name, HealthCheck: healthCheck})
+			}
+		}
+
+		switch serviceHealth.Status {
+		case string(project.HealthStatusUnhealthy):
+			unhealthy++
+			healthResult.Status = serviceHealth.Status
+		case string(project.HealthStatusUnknown):
+			if healthResult.Status == string(project.HealthStatusHealthy) {
+				healthResult.Status = serviceHealth.Status
+			}
+		}
+
+		healthResult.Services = append(healthResult.Services, serviceHealth)
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(healthResult, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("health result could not be displayed: %w", err)
+		}
+	} else if len(rows) > 0 {
+		a.console.Message(ctx, "")
+		if err := a.formatter.Format(rows, a.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "SERVICE", ValueTemplate: "{{.Service}}"},
+				{Heading: "CHECK", ValueTemplate: "{{.Name}}"},
+				{Heading: "STATUS", ValueTemplate: "{{.Status}}"},
+				{Heading: "DETAILS", ValueTemplate: "{{.Message}}"},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("health result could not be displayed: %w", err)
+		}
+	}
+
+	// Unhealthy services fail the command so it can gate CI pipelines & deployment strategies
+	if unhealthy > 0 {
+		return nil, fmt.Errorf("%d of %d services are unhealthy", unhealthy, len(services))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Health of %d services: %s", len(services), healthResult.Status),
+		},
+	}, nil
+}
+
+func getCmdHealthHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Evaluate the health of the application's services from the health signals of the hosting platform and the "+
+			"health endpoints declared in your project file.",
+		[]string{
+			formatHelpNote("Container Apps are unhealthy when the latest revision is unhealthy or failed provisioning." +
+				" App Services and Function Apps are unhealthy when they aren't running or their availability is degraded."),
+			formatHelpNote(fmt.Sprintf("Declare the health endpoint of a service with %s in azure.yaml.",
+				output.WithHighLightFormat("health: { path: /health }"))),
+			formatHelpNote("The command exits with a non-zero exit code when any service is unhealthy, so it can be used" +
+				" as a CI gate after a deployment."),
+		})
+}
+
+func getCmdHealthHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Evaluate the health of all services.": output.WithHighLightFormat("azd health"),
+		"Evaluate the health of a specific service.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd health <service>"),
+			output.WithWarningFormat("[Service name]")),
+		"Evaluate the health of all services as JSON.": output.WithHighLightFormat("azd health --output json"),
+	})
+}
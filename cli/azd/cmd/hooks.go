@@ -0,0 +1,446 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The scope of hooks defined at the project level
+const projectHookScope = "project"
+
+func hooksActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("hooks", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "hooks",
+			Short: "Develop, test and run hooks for an application.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdHooksHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	group.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newHooksListCmd(),
+		FlagsResolver:  newHooksListFlags,
+		ActionResolver: newHooksListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	group.
+		Add("run", &actions.ActionDescriptorOptions{
+			Command:        newHooksRunCmd(),
+			FlagsResolver:  newHooksRunFlags,
+			ActionResolver: newHooksRunAction,
+			HelpOptions: actions.ActionHelpOptions{
+				Footer: getCmdHooksRunHelpFooter,
+			},
+		}).
+		UseMiddleware("dryrun", middleware.NewDryRunMiddleware).
+		UseMiddleware("secrets", middleware.NewSecretsMiddleware)
+
+	return group
+}
+
+type hooksListFlags struct {
+	envFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *hooksListFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newHooksListFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *hooksListFlags {
+	flags := &hooksListFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newHooksListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the hooks configured for the project and its services.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+// A hook configured within azure.yaml as resolved for the current operating system
+type hookListItem struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+	// Whether the hook runs before or after the event: pre or post
+	Timing string `json:"timing"`
+	// The event of the hook, ex. provision
+	Event string `json:"event"`
+	Shell string `json:"shell"`
+	Run   string `json:"run"`
+	// The run command with the environment variables it references interpolated, as the shell would
+	Command          string   `json:"command"`
+	When             string   `json:"when,omitempty"`
+	WorkingDirectory string   `json:"workingDirectory,omitempty"`
+	Container        string   `json:"container,omitempty"`
+	DependsOn        []string `json:"dependsOn,omitempty"`
+}
+
+type hooksListAction struct {
+	projectConfig *project.ProjectConfig
+	lazyEnv       *lazy.Lazy[*environment.Environment]
+	console       input.Console
+	formatter     output.Formatter
+	writer        io.Writer
+}
+
+func newHooksListAction(
+	projectConfig *project.ProjectConfig,
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &hooksListAction{
+		projectConfig: projectConfig,
+		lazyEnv:       lazyEnv,
+		console:       console,
+		formatter:     formatter,
+		writer:        writer,
+	}
+}
+
+func (a *hooksListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Commands are interpolated with the values of the environment when one is available
+	var env *environment.Environment
+	if lazyEnv, err := a.lazyEnv.GetValue(); err == nil {
+		env = lazyEnv
+	}
+
+	hooks := hookListItems(projectHookScope, a.projectConfig.Hooks, hookLookupEnv(env))
+	for _, service := range a.projectConfig.GetServicesStable() {
+		hooks = append(hooks, hookListItems(service.Name, service.Hooks, hookLookupEnv(env))...)
+	}
+
+	if a.formatter.Kind() != output.TableFormat {
+		return nil, a.formatter.Format(hooks, a.writer, nil)
+	}
+
+	if len(hooks) == 0 {
+		a.console.Message(ctx, "No hooks are configured for this project.")
+		return nil, nil
+	}
+
+	columns := []output.Column{
+		{
+			Heading:       "NAME",
+			ValueTemplate: "{{.Name}}",
+		},
+		{
+			Heading:       "SCOPE",
+			ValueTemplate: "{{.Scope}}",
+		},
+		{
+			Heading:       "TIMING",
+			ValueTemplate: "{{.Timing}}",
+		},
+		{
+			Heading:       "SHELL",
+			ValueTemplate: "{{.Shell}}",
+		},
+		{
+			Heading:       "COMMAND",
+			ValueTemplate: "{{.Command}}",
+		},
+		{
+			Heading:       "WHEN",
+			ValueTemplate: "{{.When}}",
+		},
+	}
+
+	return nil, a.formatter.Format(hooks, a.writer, output.TableFormatterOptions{
+		Columns: columns,
+	})
+}
+
+// Gets the list items for the hooks in the specified scope sorted by name. The commands of the hooks are interpolated
+// with the variables found by lookupEnv.
+func hookListItems(
+	scope string,
+	hooks ext.HooksConfig,
+	lookupEnv func(key string) (string, bool),
+) []hookListItem {
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := []hookListItem{}
+	for _, name := range names {
+		// Hook names are validated when the project is loaded
+		hookType, eventName, _ := ext.InferHookType(name)
+
+		for _, hookConfig := range hooks[name] {
+			if hookConfig == nil {
+				continue
+			}
+
+			hookConfig = hookConfig.ForCurrentOS()
+			shell := hookConfig.ResolvedShell()
+			run := strings.TrimSpace(hookConfig.Run)
+			item := hookListItem{
+				Name:             name,
+				Scope:            scope,
+				Timing:           string(hookType),
+				Event:            eventName,
+				Shell:            string(shell),
+				Run:              run,
+				Command:          interpolateHookCommand(shell, run, lookupEnv),
+				When:             hookConfig.When,
+				WorkingDirectory: hookConfig.WorkingDirectory,
+				DependsOn:        hookConfig.DependsOn,
+			}
+
+			if hookConfig.Container != nil {
+				item.Container = hookConfig.Container.Image
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// The references to environment variables of the shells, ex. ${API_URL} or $env:API_URL
+var (
+	shEnvReferenceRegexp   = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+	pwshEnvReferenceRegexp = regexp.MustCompile(`(?i)\$env:([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// interpolateHookCommand replaces the references to environment variables of the run command of a hook by their
+// values, as the shell of the hook would. Variables that aren't found are kept as is.
+func interpolateHookCommand(shell ext.ShellType, run string, lookupEnv func(key string) (string, bool)) string {
+	var expression *regexp.Regexp
+	switch shell {
+	case ext.ShellTypeBash:
+		expression = shEnvReferenceRegexp
+	case ext.ShellTypePowershell:
+		expression = pwshEnvReferenceRegexp
+	default:
+		return run
+	}
+
+	return expression.ReplaceAllStringFunc(run, func(reference string) string {
+		match := expression.FindStringSubmatch(reference)
+		key := match[1]
+		if key == "" && len(match) > 2 {
+			key = match[2]
+		}
+
+		if value, has := lookupEnv(key); has {
+			return value
+		}
+
+		return reference
+	})
+}
+
+// hookLookupEnv looks up the variables of hooks as they run: the values of the environment take precedence over the
+// variables of the process. Values referencing secrets are kept as references so secrets are never displayed.
+func hookLookupEnv(env *environment.Environment) func(key string) (string, bool) {
+	var values map[string]string
+	if env != nil {
+		values = env.Dotenv()
+	}
+
+	return func(key string) (string, bool) {
+		if value, has := values[key]; has {
+			return value, true
+		}
+
+		return os.LookupEnv(key)
+	}
+}
+
+type hooksRunFlags struct {
+	envFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *hooksRunFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	local.Bool(
+		middleware.DryRunFlagName,
+		false,
+		"Lists the hooks that would run, with their interpolated commands, without running them.",
+	)
+	f.global = global
+}
+
+func newHooksRunFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *hooksRunFlags {
+	flags := &hooksRunFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newHooksRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <event> [service]",
+		Short: "Runs the hooks configured for the specified event.",
+		Args:  cobra.RangeArgs(1, 2),
+	}
+}
+
+type hooksRunAction struct {
+	projectConfig *project.ProjectConfig
+	env           *environment.Environment
+	commandRunner exec.CommandRunner
+	console       input.Console
+	flags         *hooksRunFlags
+	args          []string
+}
+
+func newHooksRunAction(
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	flags *hooksRunFlags,
+	args []string,
+) actions.Action {
+	return &hooksRunAction{
+		projectConfig: projectConfig,
+		env:           env,
+		commandRunner: commandRunner,
+		console:       console,
+		flags:         flags,
+		args:          args,
+	}
+}
+
+func (a *hooksRunAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	hookName := a.args[0]
+
+	// Command title
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: fmt.Sprintf("Running %s hooks (azd hooks run)", hookName),
+	})
+
+	hookType, eventName, err := ext.InferHookType(hookName)
+	if err != nil {
+		return nil, fmt.Errorf("%w. Hook names must start with 'pre' or 'post'. Examples: preprovision, postdeploy", err)
+	}
+
+	cwd := a.projectConfig.Path
+	hooks := a.projectConfig.Hooks
+	scope := "the project"
+
+	if len(a.args) == 2 {
+		service, has := a.projectConfig.Services[a.args[1]]
+		if !has {
+			return nil, fmt.Errorf("service '%s' is not defined in azure.yaml", a.args[1])
+		}
+
+		cwd = service.Path()
+		hooks = service.Hooks
+		scope = fmt.Sprintf("service '%s'", service.Name)
+	}
+
+	if _, has := hooks[hookName]; !has {
+		return nil, fmt.Errorf("hook '%s' is not configured for %s", hookName, scope)
+	}
+
+	if middleware.IsDryRun(ctx) {
+		return a.dryRun(ctx, hookName, scope, hooks)
+	}
+
+	hooksManager := ext.NewHooksManager(cwd)
+	hooksRunner := ext.NewHooksRunner(hooksManager, a.commandRunner, a.console, cwd, hooks, a.env)
+
+	if err := hooksRunner.RunHooks(ctx, hookType, eventName); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your '%s' hooks for %s ran successfully.", hookName, scope),
+		},
+	}, nil
+}
+
+// dryRun displays the hooks that would run, in the order they would run, without running them
+func (a *hooksRunAction) dryRun(
+	ctx context.Context,
+	hookName string,
+	scope string,
+	hooks ext.HooksConfig,
+) (*actions.ActionResult, error) {
+	items := hookListItems(scope, ext.HooksConfig{hookName: hooks[hookName]}, hookLookupEnv(a.env))
+	for _, item := range items {
+		details := item.Shell
+		if item.When != "" {
+			details = fmt.Sprintf("%s, when %s", details, item.When)
+		}
+
+		a.console.Message(ctx, fmt.Sprintf("  %s (%s)", output.WithHighLightFormat(item.Name), details))
+		for _, line := range strings.Split(item.Command, "\n") {
+			a.console.Message(ctx, output.WithGrayFormat("    %s", line))
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Dry run: %d '%s' hooks of %s would run.", len(items), hookName, scope),
+		},
+	}, nil
+}
+
+func getCmdHooksHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Develop, test and run hooks for an application.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Hooks are configured within the %s file of the project and its services.",
+				output.WithLinkFormat("azure.yaml"))),
+			formatHelpNote("Hooks run with the same environment values azd provides during a command, which makes it" +
+				" possible to iterate on hook scripts without running the full command."),
+		})
+}
+
+func getCmdHooksRunHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Runs the project level preprovision hooks.": output.WithHighLightFormat("azd hooks run preprovision"),
+		"Runs the postdeploy hooks of a specific service.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd hooks run postdeploy <service>"),
+			output.WithWarningFormat("[Service name]")),
+		"Lists the preprovision hooks that would run, with their interpolated commands.": output.WithHighLightFormat(
+			"azd hooks run preprovision --dry-run"),
+	})
+}
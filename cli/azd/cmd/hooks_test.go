@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_hookListItems(t *testing.T) {
+	hooks := ext.HooksConfig{
+		"predeploy": {
+			{
+				Run:  "scripts/predeploy.sh",
+				When: "ci",
+			},
+			{
+				Shell: ext.ShellTypePython,
+				Run:   "print('hello')\n",
+			},
+		},
+		"postdeploy": {{
+			Windows: &ext.HookConfig{
+				Run: "scripts/postdeploy.ps1",
+			},
+			Posix: &ext.HookConfig{
+				Run: "scripts/postdeploy.sh",
+			},
+		}},
+	}
+
+	lookupEnv := func(key string) (string, bool) {
+		return "", false
+	}
+
+	items := hookListItems("web", hooks, lookupEnv)
+	require.Len(t, items, 3)
+
+	// Hooks are sorted by name and keep the order they are defined in
+	require.Equal(t, "postdeploy", items[0].Name)
+	require.Equal(t, "predeploy", items[1].Name)
+	require.Equal(t, "predeploy", items[2].Name)
+
+	if runtime.GOOS == "windows" {
+		require.Equal(t, "scripts/postdeploy.ps1", items[0].Run)
+		require.Equal(t, string(ext.ShellTypePowershell), items[0].Shell)
+	} else {
+		require.Equal(t, "scripts/postdeploy.sh", items[0].Run)
+		require.Equal(t, string(ext.ShellTypeBash), items[0].Shell)
+	}
+
+	require.Equal(t, "post", items[0].Timing)
+	require.Equal(t, "deploy", items[0].Event)
+	require.Equal(t, "web", items[1].Scope)
+	require.Equal(t, "pre", items[1].Timing)
+	require.Equal(t, string(ext.ShellTypeBash), items[1].Shell)
+	require.Equal(t, "ci", items[1].When)
+	require.Equal(t, string(ext.ShellTypePython), items[2].Shell)
+	require.Equal(t, "print('hello')", items[2].Run)
+}
+
+func Test_interpolateHookCommand(t *testing.T) {
+	values := map[string]string{
+		"API_URL":     "https://api.azurewebsites.net",
+		"DB_PASSWORD": "akvs://kv-todo/db-password",
+	}
+	lookupEnv := func(key string) (string, bool) {
+		value, has := values[key]
+		return value, has
+	}
+
+	require.Equal(t,
+		"curl https://api.azurewebsites.net/health && echo https://api.azurewebsites.net $UNKNOWN",
+		interpolateHookCommand(ext.ShellTypeBash, "curl ${API_URL}/health && echo $API_URL $UNKNOWN", lookupEnv))
+	require.Equal(t,
+		"Invoke-WebRequest https://api.azurewebsites.net; Write-Host $name",
+		interpolateHookCommand(ext.ShellTypePowershell, "Invoke-WebRequest $env:API_URL; Write-Host $name", lookupEnv))
+	// Secrets are displayed as references
+	require.Equal(t, "login akvs://kv-todo/db-password", interpolateHookCommand(
+		ext.ShellTypeBash, "login $DB_PASSWORD", lookupEnv))
+	// Python scripts read variables from os.environ and aren't interpolated
+	require.Equal(t, "print(os.environ['API_URL'])", interpolateHookCommand(
+		ext.ShellTypePython, "print(os.environ['API_URL'])", lookupEnv))
+}
+
+func TestHooksListRun(t *testing.T) {
+	dir := newCommandTestProject(t, `
+name: test
+hooks:
+  preprovision:
+    shell: sh
+    run: echo ${AZURE_ENV_NAME}
+`)
+
+	runRootCommand(t, "hooks", "list", "--cwd", dir)
+	runRootCommand(t, "hooks", "list", "-e", "dev", "--cwd", dir, "--output", "json")
+}
@@ -21,23 +21,46 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 
 	group.
 		Add("create", &actions.ActionDescriptorOptions{
-			Command:        newInfraCreateCmd(),
-			FlagsResolver:  newInfraCreateFlags,
-			ActionResolver: newInfraCreateAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-			DefaultFormat:  output.NoneFormat,
+			Command:         newInfraCreateCmd(),
+			FlagsResolver:   newInfraCreateFlags,
+			ActionResolver:  newInfraCreateAction,
+			RequiresNetwork: true,
+			OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+			DefaultFormat:   output.NoneFormat,
 		}).
+		UseMiddleware("secrets", middleware.NewSecretsMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	group.
 		Add("delete", &actions.ActionDescriptorOptions{
-			Command:        newInfraDeleteCmd(),
-			FlagsResolver:  newInfraDeleteFlags,
-			ActionResolver: newInfraDeleteAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-			DefaultFormat:  output.NoneFormat,
+			Command:         newInfraDeleteCmd(),
+			FlagsResolver:   newInfraDeleteFlags,
+			ActionResolver:  newInfraDeleteAction,
+			RequiresNetwork: true,
+			OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+			DefaultFormat:   output.NoneFormat,
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
+	group.Add("publish", &actions.ActionDescriptorOptions{
+		Command:        newInfraPublishCmd(),
+		FlagsResolver:  newInfraPublishFlags,
+		ActionResolver: newInfraPublishAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdInfraPublishHelpDescription,
+			Footer:      getCmdInfraPublishHelpFooter,
+		},
+	})
+
+	group.Add("diagram", &actions.ActionDescriptorOptions{
+		Command:        newInfraDiagramCmd(),
+		FlagsResolver:  newInfraDiagramFlags,
+		ActionResolver: newInfraDiagramAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdInfraDiagramHelpDescription,
+			Footer:      getCmdInfraDiagramHelpFooter,
+		},
+	})
+
 	return group
 }
@@ -0,0 +1,227 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	bicepcli "github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type infraDiagramFlags struct {
+	global     *internal.GlobalCommandOptions
+	format     string
+	outputFile string
+}
+
+func (f *infraDiagramFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.format,
+		"format",
+		"",
+		fmt.Sprintf(
+			"The format of the diagram: %s. (Default: inferred from the output file, or mermaid)",
+			strings.Join(infra.DiagramFormats(), ", ")),
+	)
+	local.StringVar(
+		&f.outputFile,
+		"output-file",
+		"",
+		"The file the diagram is written to. (Default: the infra.diagram file of azure.yaml, or the console)",
+	)
+	f.global = global
+}
+
+func newInfraDiagramFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraDiagramFlags {
+	flags := &infraDiagramFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newInfraDiagramCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diagram",
+		Short: "Render a diagram of the infrastructure of the project.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type infraDiagramAction struct {
+	flags         *infraDiagramFlags
+	projectConfig *project.ProjectConfig
+	azdCtx        *azdcontext.AzdContext
+	bicepCli      bicepcli.BicepCli
+	commandRunner exec.CommandRunner
+	console       input.Console
+}
+
+func newInfraDiagramAction(
+	flags *infraDiagramFlags,
+	projectConfig *project.ProjectConfig,
+	azdCtx *azdcontext.AzdContext,
+	bicepCli bicepcli.BicepCli,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+) actions.Action {
+	return &infraDiagramAction{
+		flags:         flags,
+		projectConfig: projectConfig,
+		azdCtx:        azdCtx,
+		bicepCli:      bicepCli,
+		commandRunner: commandRunner,
+		console:       console,
+	}
+}
+
+func (a *infraDiagramAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	outputFile := a.flags.outputFile
+	if outputFile == "" && a.projectConfig.Infra.Diagram != "" {
+		outputFile = filepath.Join(a.azdCtx.ProjectDirectory(), a.projectConfig.Infra.Diagram)
+	}
+
+	format := a.flags.format
+	if format == "" && outputFile != "" {
+		inferred, err := infra.DiagramFormatFromPath(outputFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w, or set the format with --format", err)
+		}
+
+		format = inferred
+	}
+	if format == "" {
+		format = infra.DiagramFormatMermaid
+	}
+
+	markdown := strings.EqualFold(filepath.Ext(outputFile), ".md")
+	content, err := renderInfraDiagram(
+		ctx, a.bicepCli, a.commandRunner, a.azdCtx.ProjectDirectory(), a.projectConfig.Infra, format, markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	if outputFile == "" {
+		fmt.Fprint(a.console.Handles().Stdout, string(content))
+		return nil, nil
+	}
+
+	if err := writeInfraDiagram(outputFile, content); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Wrote the diagram of the infrastructure to %s.", output.WithHighLightFormat(outputFile)),
+		},
+	}, nil
+}
+
+// renderInfraDiagram compiles the Bicep infrastructure of the project and renders its diagram in the format
+func renderInfraDiagram(
+	ctx context.Context,
+	bicepCli bicepcli.BicepCli,
+	commandRunner exec.CommandRunner,
+	projectDirectory string,
+	infraOptions provisioning.Options,
+	format string,
+	markdown bool,
+) ([]byte, error) {
+	if infraOptions.Provider != "" && infraOptions.Provider != provisioning.Bicep {
+		return nil, fmt.Errorf(
+			"diagrams are rendered from Bicep infrastructure, the project uses '%s'", infraOptions.Provider)
+	}
+
+	if infraOptions.Module == "" {
+		infraOptions.Module = bicep.DefaultModule
+	}
+
+	modulePath := filepath.Join(projectDirectory, infraOptions.Path, fmt.Sprintf("%s.bicep", infraOptions.Module))
+	compiled, err := bicepCli.Build(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile bicep template: %w", err)
+	}
+
+	diagram, err := infra.NewDiagram([]byte(compiled))
+	if err != nil {
+		return nil, err
+	}
+
+	return diagram.Render(ctx, commandRunner, format, markdown)
+}
+
+func writeInfraDiagram(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating directory of diagram: %w", err)
+	}
+
+	if err := os.WriteFile(path, content, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing diagram: %w", err)
+	}
+
+	return nil
+}
+
+// updateInfraDiagram renders the infra.diagram file of azure.yaml again, to keep it current with the infrastructure
+// provisioned by the project
+func updateInfraDiagram(
+	ctx context.Context,
+	bicepCli bicepcli.BicepCli,
+	commandRunner exec.CommandRunner,
+	projectDirectory string,
+	infraOptions provisioning.Options,
+) error {
+	path := filepath.Join(projectDirectory, infraOptions.Diagram)
+	format, err := infra.DiagramFormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	markdown := strings.EqualFold(filepath.Ext(path), ".md")
+	content, err := renderInfraDiagram(ctx, bicepCli, commandRunner, projectDirectory, infraOptions, format, markdown)
+	if err != nil {
+		return err
+	}
+
+	return writeInfraDiagram(path, content)
+}
+
+func getCmdInfraDiagramHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Render the resources of the compiled infrastructure, their dependencies and the services hosted on them"+
+			" as a Mermaid or Graphviz diagram, or an SVG image, for documentation and review.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Set %s in azure.yaml to write the diagram to a file, updated by each %s.",
+				output.WithHighLightFormat("infra.diagram"),
+				output.WithHighLightFormat("azd provision"))),
+			formatHelpNote("Markdown files embed a Mermaid diagram, rendered by GitHub and Azure DevOps."),
+			formatHelpNote("SVG images are rendered with the Graphviz dot command."),
+		})
+}
+
+func getCmdInfraDiagramHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Print the Mermaid diagram of the infrastructure.": output.WithHighLightFormat("azd infra diagram"),
+		"Write the diagram to a markdown file.": output.WithHighLightFormat(
+			"azd infra diagram --output-file docs/architecture.md"),
+		"Render the diagram as an SVG image.": output.WithHighLightFormat(
+			"azd infra diagram --format svg --output-file docs/architecture.svg"),
+	})
+}
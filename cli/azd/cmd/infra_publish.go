@@ -0,0 +1,245 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/devcenter"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	bicepcli "github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The version of environment definitions published from projects without a template version
+const defaultEnvironmentDefinitionVersion = "1.0.0"
+
+type infraPublishFlags struct {
+	global  *internal.GlobalCommandOptions
+	catalog string
+	name    string
+	version string
+	push    bool
+}
+
+func (f *infraPublishFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.catalog, "catalog", "", "The local clone of the catalog repository to publish to.")
+	local.StringVar(
+		&f.name,
+		"name",
+		"",
+		"The name of the environment definition. (Default: the name of the project)",
+	)
+	local.StringVar(
+		&f.version,
+		"version",
+		"",
+		"The version of the environment definition. (Default: the version of the template or 1.0.0)",
+	)
+	local.BoolVar(&f.push, "push", false, "Commit the environment definition and push it to the catalog repository.")
+	f.global = global
+}
+
+func newInfraPublishFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraPublishFlags {
+	flags := &infraPublishFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newInfraPublishCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "publish",
+		Short: "Publish the infrastructure of the project as an Azure Deployment Environments catalog item.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type infraPublishAction struct {
+	flags         *infraPublishFlags
+	projectConfig *project.ProjectConfig
+	azdCtx        *azdcontext.AzdContext
+	bicepCli      bicepcli.BicepCli
+	gitCli        git.GitCli
+	console       input.Console
+}
+
+func newInfraPublishAction(
+	flags *infraPublishFlags,
+	projectConfig *project.ProjectConfig,
+	azdCtx *azdcontext.AzdContext,
+	bicepCli bicepcli.BicepCli,
+	gitCli git.GitCli,
+	console input.Console,
+) actions.Action {
+	return &infraPublishAction{
+		flags:         flags,
+		projectConfig: projectConfig,
+		azdCtx:        azdCtx,
+		bicepCli:      bicepCli,
+		gitCli:        gitCli,
+		console:       console,
+	}
+}
+
+func (a *infraPublishAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.catalog == "" {
+		return nil, errors.New("--catalog is required, the local clone of the catalog repository to publish to")
+	}
+
+	infraOptions := a.projectConfig.Infra
+	if infraOptions.Provider != "" && infraOptions.Provider != provisioning.Bicep {
+		return nil, fmt.Errorf(
+			"only Bicep infrastructure can be published as an environment definition, the project uses '%s'",
+			infraOptions.Provider)
+	}
+
+	if infraOptions.Module == "" {
+		infraOptions.Module = bicep.DefaultModule
+	}
+
+	name := a.flags.name
+	if name == "" {
+		name = a.projectConfig.Name
+	}
+
+	catalogPath, err := filepath.Abs(a.flags.catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	directory := filepath.Join(catalogPath, name)
+	modulePath := filepath.Join(
+		a.azdCtx.ProjectDirectory(), infraOptions.Path, fmt.Sprintf("%s.bicep", infraOptions.Module))
+
+	spinnerMessage := fmt.Sprintf("Packaging environment definition %s", output.WithHighLightFormat(name))
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	definition, err := a.packageEnvironmentDefinition(ctx, name, modulePath, directory)
+	a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	followUp := fmt.Sprintf(
+		"Commit and push %s to the catalog repository, then sync the catalog in the dev center.",
+		output.WithHighLightFormat(directory))
+
+	if a.flags.push {
+		if err := a.push(ctx, catalogPath, name); err != nil {
+			return nil, err
+		}
+
+		followUp = "Sync the catalog in the dev center to make the environment definition available."
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Published environment definition %s %s.", name, definition.Manifest.Version),
+			FollowUp: followUp,
+		},
+	}, nil
+}
+
+// packageEnvironmentDefinition compiles the infrastructure of the project & writes it as an environment definition
+func (a *infraPublishAction) packageEnvironmentDefinition(
+	ctx context.Context,
+	name string,
+	modulePath string,
+	directory string,
+) (*devcenter.EnvironmentDefinition, error) {
+	compiled, err := a.bicepCli.Build(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile bicep template: %w", err)
+	}
+
+	definition, err := devcenter.NewEnvironmentDefinition(devcenter.EnvironmentDefinitionManifest{
+		Name:        name,
+		Version:     a.environmentDefinitionVersion(),
+		Summary:     fmt.Sprintf("The infrastructure of %s", a.projectConfig.Name),
+		Description: fmt.Sprintf("Published from the azd project %s.", a.projectConfig.Name),
+	}, azure.RawArmTemplate(compiled))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := definition.Write(directory); err != nil {
+		return nil, err
+	}
+
+	return definition, nil
+}
+
+// push commits the environment definition to the catalog repository and pushes the current branch
+func (a *infraPublishAction) push(ctx context.Context, catalogPath string, name string) error {
+	branch, err := a.gitCli.GetCurrentBranch(ctx, catalogPath)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a git repository: %w", catalogPath, err)
+	}
+
+	spinnerMessage := fmt.Sprintf("Pushing to branch %s of the catalog repository", output.WithHighLightFormat(branch))
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	err = a.gitCli.AddFile(ctx, catalogPath, name)
+	if err == nil {
+		err = a.gitCli.Commit(ctx, catalogPath, fmt.Sprintf("Publish %s environment definition", name))
+	}
+	if err == nil {
+		err = a.gitCli.PushUpstream(ctx, catalogPath, "origin", branch)
+	}
+
+	a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	return err
+}
+
+// environmentDefinitionVersion returns the version of the --version flag or the template metadata, ex.
+// todo-nodejs-mongo@0.0.1-beta
+func (a *infraPublishAction) environmentDefinitionVersion() string {
+	if a.flags.version != "" {
+		return a.flags.version
+	}
+
+	if a.projectConfig.Metadata != nil {
+		if _, version, has := strings.Cut(a.projectConfig.Metadata.Template, "@"); has && version != "" {
+			return version
+		}
+	}
+
+	return defaultEnvironmentDefinitionVersion
+}
+
+func getCmdInfraPublishHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Package the infrastructure of the project as an Azure Deployment Environments environment definition"+
+			" and publish it to a dev center catalog repository, to offer it as a governed self-service environment.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Writes %s and %s to a directory named after the environment definition.",
+				output.WithHighLightFormat(devcenter.EnvironmentDefinitionManifestFileName),
+				output.WithHighLightFormat(devcenter.EnvironmentDefinitionTemplateFileName))),
+			formatHelpNote("The infrastructure must be Bicep targeting a resource group, which ADE creates for" +
+				" each environment."),
+		})
+}
+
+func getCmdInfraPublishHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Publish the infrastructure to a local clone of the catalog repository.": output.WithHighLightFormat(
+			"azd infra publish --catalog ../catalog/Environments"),
+		"Publish the infrastructure and push it to the catalog repository.": output.WithHighLightFormat(
+			"azd infra publish --catalog ../catalog/Environments --push"),
+	})
+}
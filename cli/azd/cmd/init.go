@@ -9,19 +9,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/repository"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/importer"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/network"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -42,11 +51,13 @@ func newInitCmd() *cobra.Command {
 }
 
 type initFlags struct {
-	templatePath   string
-	templateBranch string
-	subscription   string
-	location       string
-	global         *internal.GlobalCommandOptions
+	templatePath      string
+	templateBranch    string
+	subscription      string
+	location          string
+	devcontainer      bool
+	fromResourceGroup string
+	global            *internal.GlobalCommandOptions
 	envFlag
 }
 
@@ -67,6 +78,18 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		"Name or ID of an Azure subscription to use for the new environment",
 	)
 	local.StringVarP(&i.location, "location", "l", "", "Azure location for the new environment")
+	local.BoolVar(
+		&i.devcontainer,
+		"devcontainer",
+		false,
+		"Generates a dev container configuration with the tools the project needs, for GitHub Codespaces and VS Code.",
+	)
+	local.StringVar(
+		&i.fromResourceGroup,
+		"from-resource-group",
+		"",
+		"The existing resource group to initialize the project from, its resources are referenced rather than recreated.",
+	)
 	i.envFlag.Bind(local, global)
 
 	i.global = global
@@ -76,6 +99,8 @@ type initAction struct {
 	console         input.Console
 	cmdRun          exec.CommandRunner
 	gitCli          git.GitCli
+	azCli           azcli.AzCli
+	accountManager  account.Manager
 	flags           *initFlags
 	repoInitializer *repository.Initializer
 }
@@ -84,12 +109,16 @@ func newInitAction(
 	cmdRun exec.CommandRunner,
 	console input.Console,
 	gitCli git.GitCli,
+	azCli azcli.AzCli,
+	accountManager account.Manager,
 	flags *initFlags,
 	repoInitializer *repository.Initializer) actions.Action {
 	return &initAction{
 		console:         console,
 		cmdRun:          cmdRun,
 		gitCli:          gitCli,
+		azCli:           azCli,
+		accountManager:  accountManager,
 		flags:           flags,
 		repoInitializer: repoInitializer,
 	}
@@ -107,6 +136,10 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, errors.New("template required when specifying a branch name")
 	}
 
+	if i.flags.fromResourceGroup != "" && i.flags.templatePath != "" {
+		return nil, errors.New("--from-resource-group can't be used with --template")
+	}
+
 	// ensure that git is available
 	if err := tools.EnsureInstalled(ctx, []tools.ExternalTool{i.gitCli}...); err != nil {
 		return nil, err
@@ -130,13 +163,18 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("checking if project exists: %w", err)
 	}
 
+	if existingProject && i.flags.fromResourceGroup != "" {
+		return nil, fmt.Errorf(
+			"%s already exists, the project of the resource group can't be initialized", azdcontext.ProjectFileName)
+	}
+
 	if !existingProject {
 		err = i.repoInitializer.PromptIfNonEmpty(ctx, azdCtx)
 		if err != nil {
 			return nil, err
 		}
 
-		if i.flags.templatePath == "" {
+		if i.flags.templatePath == "" && i.flags.fromResourceGroup == "" {
 			template, err := templates.PromptTemplate(ctx, "Select a project template:", i.console)
 			i.flags.templatePath = template.RepositoryPath
 
@@ -152,11 +190,19 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			return nil, err
 		}
 
+		// Template repositories can't be reached in air-gapped & offline mode, the template is cloned from the cache
+		if network.Current().Restricted() {
+			gitUri, err = templates.Cached(i.flags.templatePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		err = i.repoInitializer.Initialize(ctx, azdCtx, gitUri, i.flags.templateBranch)
 		if err != nil {
 			return nil, fmt.Errorf("init from template repository: %w", err)
 		}
-	} else if !existingProject { // do not initialize for empty if azure.yaml is present
+	} else if !existingProject && i.flags.fromResourceGroup == "" { // do not initialize for empty if azure.yaml is present
 		err = i.repoInitializer.InitializeMinimal(ctx, azdCtx)
 		if err != nil {
 			return nil, fmt.Errorf("init empty repository: %w", err)
@@ -193,18 +239,146 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("saving default environment: %w", err)
 	}
 
+	followUp := heredoc.Docf(`
+		You can view the template code in your directory: %s
+		Learn more about running 3rd party code on our DevHub: %s`,
+		output.WithLinkFormat("%s", wd),
+		output.WithLinkFormat("%s", "https://aka.ms/azd-third-party-code-notice"))
+
+	if i.flags.fromResourceGroup != "" {
+		followUp, err = i.initFromResourceGroup(ctx, azdCtx, env)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if i.flags.devcontainer {
+		devcontainerFollowUp, err := i.initDevcontainer(ctx, azdCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		followUp += "\n\n" + devcontainerFollowUp
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: "New project initialized!",
-			FollowUp: heredoc.Docf(`
-			You can view the template code in your directory: %s
-			Learn more about running 3rd party code on our DevHub: %s`,
-				output.WithLinkFormat("%s", wd),
-				output.WithLinkFormat("%s", "https://aka.ms/azd-third-party-code-notice")),
+			Header:   "New project initialized!",
+			FollowUp: followUp,
 		},
 	}, nil
 }
 
+// initFromResourceGroup initializes the project of the existing resources of the resource group: the services hosted by
+// the resources, and the bicep templates referencing the resources
+func (i *initAction) initFromResourceGroup(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	env *environment.Environment,
+) (string, error) {
+	if err := provisioning.EnsureEnv(ctx, i.console, env, i.accountManager); err != nil {
+		return "", err
+	}
+
+	resourceGroup := i.flags.fromResourceGroup
+	subscriptionId := env.GetSubscriptionId()
+
+	i.console.ShowSpinner(ctx, fmt.Sprintf("Inspecting resources of resource group %s", resourceGroup), input.Step)
+	groupResources, err := i.inspectResources(ctx, subscriptionId, resourceGroup)
+	i.console.StopSpinner(ctx, "", input.GetStepResultFormat(err))
+	if err != nil {
+		return "", err
+	}
+
+	imported := importer.Inspect(resourceGroup, groupResources)
+	if err := i.repoInitializer.InitializeImport(ctx, azdCtx, imported); err != nil {
+		return "", fmt.Errorf("init from resource group: %w", err)
+	}
+
+	if len(imported.Undetected) > 0 {
+		i.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("The language of the web apps %s isn't detected, add their services to %s.",
+				ux.ListAsText(imported.Undetected), azdcontext.ProjectFileName),
+		})
+	}
+
+	if len(imported.Skipped) > 0 {
+		i.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("These resources aren't referenced by the generated infrastructure: %s",
+				strings.Join(imported.Skipped, ", ")),
+		})
+	}
+
+	env.DotenvSet(environment.ResourceGroupEnvVarName, resourceGroup)
+	if err := env.Save(); err != nil {
+		return "", fmt.Errorf("saving environment: %w", err)
+	}
+
+	return fmt.Sprintf(
+		"Move the code of each service to src/<service name>, then run %s to deploy it to the existing resources.\n"+
+			"The resources are referenced by %s, provisioning doesn't create or update them.",
+		output.WithHighLightFormat("azd deploy"),
+		output.WithLinkFormat(filepath.Join(azdCtx.ProjectDirectory(), "infra", "resources.bicep"))), nil
+}
+
+// inspectResources lists the resources of the resource group, with the kind and runtime stack of web sites
+func (i *initAction) inspectResources(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+) ([]importer.Resource, error) {
+	resources, err := i.azCli.ListResourceGroupResources(ctx, subscriptionId, resourceGroup, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing resources of resource group %s: %w", resourceGroup, err)
+	}
+
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("resource group %s doesn't have any resource", resourceGroup)
+	}
+
+	groupResources := make([]importer.Resource, 0, len(resources))
+	for _, resource := range resources {
+		groupResource := importer.Resource{Name: resource.Name, Type: resource.Type}
+
+		if strings.EqualFold(resource.Type, string(infra.AzureResourceTypeWebSite)) {
+			site, err := i.azCli.GetResource(ctx, subscriptionId, resource.Id, "2022-09-01")
+			if err != nil {
+				return nil, fmt.Errorf("getting web app %s: %w", resource.Name, err)
+			}
+
+			properties, err := i.azCli.GetAppServiceProperties(ctx, subscriptionId, resourceGroup, resource.Name)
+			if err != nil {
+				return nil, fmt.Errorf("getting web app %s: %w", resource.Name, err)
+			}
+
+			groupResource.Kind = site.Kind
+			groupResource.Runtime = properties.LinuxFxVersion
+		}
+
+		groupResources = append(groupResources, groupResource)
+	}
+
+	return groupResources, nil
+}
+
+// initDevcontainer generates the dev container configuration of the initialized project, keeping the configuration
+// of templates that have one
+func (i *initAction) initDevcontainer(ctx context.Context, azdCtx *azdcontext.AzdContext) (string, error) {
+	projectConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		return "", fmt.Errorf("loading project: %w", err)
+	}
+
+	path, err := devcontainer.Write(azdCtx.ProjectDirectory(), devcontainer.Generate(projectConfig), false)
+	if errors.Is(err, devcontainer.ErrConfigExists) {
+		return fmt.Sprintf("The project already has a dev container configuration: %s", output.WithLinkFormat(path)), nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Created %s\n%s", output.WithLinkFormat(path), devcontainerFollowUp()), nil
+}
+
 func getCmdInitHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription("Initialize a new application in your current directory.",
 		[]string{
@@ -216,6 +390,11 @@ func getCmdInitHelpDescription(*cobra.Command) string {
 			formatHelpNote(
 				"To view all available sample templates, including those submitted by the azd community, visit: " +
 					output.WithLinkFormat("https://azure.github.io/awesome-azd") + "."),
+			formatHelpNote("Minimal projects add the Java (Maven, Gradle) and Rust (Cargo) projects of the directory as" +
+				" services, hosted by App Service and Container Apps."),
+			formatHelpNote(fmt.Sprintf("Running %s detects the services hosted by the existing resources of the"+
+				" resource group, and generates infrastructure referencing the resources rather than recreating them.",
+				output.WithHighLightFormat("init --from-resource-group"))),
 		})
 }
 
@@ -231,5 +410,9 @@ func getCmdInitHelpFooter(*cobra.Command) string {
 			output.WithHighLightFormat("--branch"),
 			output.WithWarningFormat("[Branch name]"),
 		),
+		"Initialize the project of the existing resources of a resource group.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd init --from-resource-group"),
+			output.WithWarningFormat("[Resource group name]"),
+		),
 	})
 }
@@ -0,0 +1,292 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
+)
+
+type logsFlags struct {
+	follow bool
+	since  time.Duration
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *logsFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVarP(&f.follow, "follow", "f", false, "Streams new logs until the command is stopped.")
+	local.DurationVar(
+		&f.since,
+		"since",
+		10*time.Minute,
+		"Shows the logs more recent than the duration. (ex. 30m, 2h)",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newLogsFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *logsFlags {
+	flags := &logsFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newLogsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs [<service>]",
+		Short: "Show the runtime logs of the application's services.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type logsAction struct {
+	flags           *logsFlags
+	args            []string
+	console         input.Console
+	formatter       output.Formatter
+	writer          io.Writer
+	env             *environment.Environment
+	projectConfig   *project.ProjectConfig
+	serviceManager  project.ServiceManager
+	resourceManager project.ResourceManager
+}
+
+func newLogsAction(
+	flags *logsFlags,
+	args []string,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	serviceManager project.ServiceManager,
+	resourceManager project.ResourceManager,
+) actions.Action {
+	return &logsAction{
+		flags:           flags,
+		args:            args,
+		console:         console,
+		formatter:       formatter,
+		writer:          writer,
+		env:             env,
+		projectConfig:   projectConfig,
+		serviceManager:  serviceManager,
+		resourceManager: resourceManager,
+	}
+}
+
+// serviceLogEntry is a line of the logs of a service, in the JSON output of `azd logs`
+type serviceLogEntry struct {
+	Service string `json:"service"`
+	project.ServiceLog
+}
+
+// The log providers of a service
+type serviceLogSource struct {
+	service        *project.ServiceConfig
+	targetResource *environment.TargetResource
+	provider       project.LogProvider
+	streamer       project.LogStreamer
+}
+
+func (a *logsAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	if a.flags.since < 0 {
+		return nil, fmt.Errorf("--since must be a positive duration, got '%s'", a.flags.since)
+	}
+
+	services := a.projectConfig.GetServicesStable()
+	if len(a.args) == 1 {
+		if !a.projectConfig.HasService(a.args[0]) {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", a.args[0])
+		}
+
+		services = []*project.ServiceConfig{a.projectConfig.Services[a.args[0]]}
+	}
+
+	sources, err := a.logSources(ctx, services)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	encoder := json.NewEncoder(a.writer)
+	writeLog := func(service string, log project.ServiceLog) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if a.formatter.Kind() == output.JsonFormat {
+			return encoder.Encode(serviceLogEntry{Service: service, ServiceLog: log})
+		}
+
+		_, err := fmt.Fprintln(a.writer, formatServiceLog(service, log))
+		return err
+	}
+
+	if a.flags.follow {
+		if a.formatter.Kind() != output.JsonFormat {
+			a.console.Message(ctx, "Streaming logs, press Ctrl+C to stop.\n")
+		}
+
+		_, errs := async.RunParallel(ctx, sources, len(sources),
+			func(ctx context.Context, source serviceLogSource) (struct{}, error) {
+				return struct{}{}, source.streamer.StreamLogs(
+					ctx, source.service, source.targetResource, a.flags.since,
+					func(log project.ServiceLog) error {
+						return writeLog(source.service.Name, log)
+					})
+			})
+
+		// Streaming stops when the command is canceled
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+
+		return nil, joinLogSourceErrors(sources, errs)
+	}
+
+	results, errs := async.RunParallel(ctx, sources, len(sources),
+		func(ctx context.Context, source serviceLogSource) ([]project.ServiceLog, error) {
+			return source.provider.Logs(ctx, source.service, source.targetResource, a.flags.since)
+		})
+
+	entries := []serviceLogEntry{}
+	for i, source := range sources {
+		if errs[i] != nil {
+			continue
+		}
+
+		for _, log := range results[i] {
+			entries = append(entries, serviceLogEntry{Service: source.service.Name, ServiceLog: log})
+		}
+	}
+
+	// The logs of the services are merged in the order they were logged
+	slices.SortStableFunc(entries, func(a, b serviceLogEntry) bool {
+		return a.Timestamp.Before(b.Timestamp)
+	})
+
+	for _, entry := range entries {
+		if err := writeLog(entry.Service, entry.ServiceLog); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, joinLogSourceErrors(sources, errs)
+}
+
+// logSources returns the log providers of the services, the services hosted on hosts without logs are skipped with a
+// warning unless logs are requested for them only
+func (a *logsAction) logSources(ctx context.Context, services []*project.ServiceConfig) ([]serviceLogSource, error) {
+	sources := []serviceLogSource{}
+
+	for _, svc := range services {
+		serviceTarget, err := a.serviceManager.GetServiceTarget(ctx, svc)
+		if err != nil {
+			return nil, err
+		}
+
+		source := serviceLogSource{service: svc}
+		source.provider, _ = serviceTarget.(project.LogProvider)
+		source.streamer, _ = serviceTarget.(project.LogStreamer)
+
+		if source.provider == nil || (a.flags.follow && source.streamer == nil) {
+			reason := fmt.Sprintf("logs are not supported for services hosted on '%s'", svc.Host)
+			if source.provider != nil {
+				reason = fmt.Sprintf("--follow is not supported for services hosted on '%s'", svc.Host)
+			}
+
+			if len(services) == 1 {
+				return nil, fmt.Errorf("service '%s': %s", svc.Name, reason)
+			}
+
+			a.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf("Skipping service %s, %s", svc.Name, reason),
+			})
+			continue
+		}
+
+		source.targetResource, err = a.resourceManager.GetTargetResource(ctx, a.env.GetSubscriptionId(), svc)
+		if err != nil {
+			return nil, fmt.Errorf("getting target resource of service '%s': %w", svc.Name, err)
+		}
+
+		sources = append(sources, source)
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.New("none of the services support logs")
+	}
+
+	return sources, nil
+}
+
+func joinLogSourceErrors(sources []serviceLogSource, errs []error) error {
+	services := make([]*project.ServiceConfig, len(sources))
+	for i, source := range sources {
+		services[i] = source.service
+	}
+
+	return joinServiceErrors(services, errs)
+}
+
+// formatServiceLog formats the line of the logs of the service as a line of the terminal
+func formatServiceLog(service string, log project.ServiceLog) string {
+	timestamp := strings.Repeat(" ", 8)
+	if !log.Timestamp.IsZero() {
+		timestamp = log.Timestamp.Local().Format("15:04:05")
+	}
+
+	parts := []string{output.WithGrayFormat(timestamp), output.WithHighLightFormat(service)}
+	if log.Source != "" {
+		parts = append(parts, output.WithGrayFormat(log.Source))
+	}
+
+	return strings.Join(append(parts, strings.TrimSpace(log.Message)), "  ")
+}
+
+func getCmdLogsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Show the runtime logs of the resources hosting the application's services.",
+		[]string{
+			formatHelpNote("App Services and Function Apps show their container logs and log stream." +
+				" Container Apps show the console logs of their replicas, AKS the logs of the pods of their deployment."),
+			formatHelpNote("Use `azd monitor --logs` for the traces and exceptions sent to Application Insights."),
+		})
+}
+
+func getCmdLogsHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the logs of all services over the last 10 minutes.": output.WithHighLightFormat("azd logs"),
+		"Stream the logs of a specific service.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd logs <service> --follow"),
+			output.WithWarningFormat("[Service name]")),
+		"Show the logs of the last 2 hours as JSON lines.": output.WithHighLightFormat("azd logs --since 2h --output json"),
+	})
+}
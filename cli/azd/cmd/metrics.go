@@ -0,0 +1,306 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The characters of sparklines, from the lowest to the highest value
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+type metricsFlags struct {
+	window time.Duration
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *metricsFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.DurationVar(
+		&f.window,
+		"window",
+		time.Hour,
+		"The time window of the metrics, ending now. (ex. 30m, 6h, 24h)",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newMetricsFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *metricsFlags {
+	flags := &metricsFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newMetricsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "metrics [<service>]",
+		Short: "Show the key platform metrics of the application's services.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type metricsAction struct {
+	flags         *metricsFlags
+	args          []string
+	console       input.Console
+	formatter     output.Formatter
+	writer        io.Writer
+	env           *environment.Environment
+	projectConfig *project.ProjectConfig
+	metricsReader *project.ServiceMetricsReader
+}
+
+func newMetricsAction(
+	flags *metricsFlags,
+	args []string,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	metricsReader *project.ServiceMetricsReader,
+) actions.Action {
+	return &metricsAction{
+		flags:         flags,
+		args:          args,
+		console:       console,
+		formatter:     formatter,
+		writer:        writer,
+		env:           env,
+		projectConfig: projectConfig,
+		metricsReader: metricsReader,
+	}
+}
+
+// The row of each metric in the table output of `azd metrics`
+type metricRow struct {
+	Service string
+	Name    string
+	Value   string
+	Trend   string
+}
+
+func (a *metricsAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	if a.flags.window < time.Minute {
+		return nil, fmt.Errorf("--window must be at least 1m, got '%s'", a.flags.window)
+	}
+
+	services := a.projectConfig.GetServicesStable()
+	if len(a.args) == 1 {
+		if !a.projectConfig.HasService(a.args[0]) {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", a.args[0])
+		}
+
+		services = []*project.ServiceConfig{a.projectConfig.Services[a.args[0]]}
+	}
+
+	progress := newServiceProgress(a.console, "Reading metrics of")
+	results, errs := async.RunParallel(ctx, services, len(services),
+		func(ctx context.Context, svc *project.ServiceConfig) ([]project.ServiceMetric, error) {
+			if a.formatter.Kind() == output.TableFormat {
+				progress.Start(ctx, svc.Name)
+			}
+
+			metrics, err := a.metricsReader.Read(ctx, svc, a.flags.window)
+
+			if a.formatter.Kind() == output.TableFormat {
+				if err != nil {
+					progress.Stop(ctx, svc.Name, input.StepWarning, nil)
+				} else {
+					progress.Stop(ctx, svc.Name, input.StepDone, nil)
+				}
+			}
+
+			return metrics, err
+		})
+
+	serviceMetrics := make([]contracts.ServiceMetrics, 0, len(services))
+	rows := []metricRow{}
+	failures := 0
+
+	for i, svc := range services {
+		result := contracts.ServiceMetrics{
+			Service: svc.Name,
+			Metrics: []contracts.Metric{},
+		}
+
+		if errs[i] != nil {
+			failures++
+			result.Error = errs[i].Error()
+			serviceMetrics = append(serviceMetrics, result)
+
+			if a.formatter.Kind() == output.TableFormat {
+				a.console.Message(ctx, output.WithWarningFormat(
+					"WARNING: The metrics of service %s could not be read: %v", svc.Name, errs[i]))
+			}
+			continue
+		}
+
+		for _, metric := range results[i] {
+			contract := contracts.Metric{
+				Name:        metric.Name,
+				Unit:        metric.Unit,
+				Aggregation: metric.Aggregation,
+				Value:       metric.Summary,
+				Points:      make([]contracts.MetricPoint, 0, len(metric.Points)),
+			}
+
+			values := make([]*float64, 0, len(metric.Points))
+			for _, point := range metric.Points {
+				contract.Points = append(contract.Points, contracts.MetricPoint{
+					Timestamp: point.Timestamp,
+					Value:     point.Value,
+				})
+				values = append(values, point.Value)
+			}
+
+			result.Metrics = append(result.Metrics, contract)
+			rows = append(rows, metricRow{
+				Service: svc.Name,
+				Name:    fmt.Sprintf("%s (%s)", metric.Name, strings.ToLower(metric.Aggregation)),
+				Value:   formatMetricValue(metric.Summary, metric.Unit),
+				Trend:   sparkline(values),
+			})
+		}
+
+		serviceMetrics = append(serviceMetrics, result)
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(serviceMetrics, a.writer, nil); err != nil {
+			return nil, err
+		}
+	} else if len(rows) > 0 {
+		a.console.Message(ctx, "")
+		if err := a.formatter.Format(rows, a.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "SERVICE", ValueTemplate: "{{.Service}}"},
+				{Heading: "METRIC", ValueTemplate: "{{.Name}}"},
+				{Heading: "VALUE", ValueTemplate: "{{.Value}}"},
+				{Heading: fmt.Sprintf("LAST %s", a.flags.window), ValueTemplate: "{{.Trend}}"},
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if failures > 0 && failures == len(services) {
+		return nil, joinServiceErrors(services, errs)
+	}
+
+	return nil, nil
+}
+
+// formatMetricValue formats the value of a metric in the unit reported by Azure Monitor
+func formatMetricValue(value *float64, unit string) string {
+	if value == nil {
+		return "-"
+	}
+
+	switch unit {
+	case "Bytes":
+		units := []string{"B", "KB", "MB", "GB", "TB"}
+		size := *value
+		index := 0
+		for size >= 1024 && index < len(units)-1 {
+			size /= 1024
+			index++
+		}
+
+		return fmt.Sprintf("%.1f %s", size, units[index])
+	case "Seconds":
+		if *value < 1 {
+			return fmt.Sprintf("%.0f ms", *value*1000)
+		}
+
+		return fmt.Sprintf("%.2f s", *value)
+	case "NanoCores":
+		return fmt.Sprintf("%.3f cores", *value/1e9)
+	case "Percent":
+		return fmt.Sprintf("%.1f%%", *value)
+	default:
+		if *value == math.Trunc(*value) {
+			return fmt.Sprintf("%.0f", *value)
+		}
+
+		return fmt.Sprintf("%.2f", *value)
+	}
+}
+
+// sparkline renders the values as a line of block characters scaled between the lowest and highest value.
+// Missing values are rendered as spaces.
+func sparkline(values []*float64) string {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		if value != nil {
+			low = math.Min(low, *value)
+			high = math.Max(high, *value)
+		}
+	}
+
+	if math.IsInf(low, 1) {
+		return "-"
+	}
+
+	line := strings.Builder{}
+	for _, value := range values {
+		if value == nil {
+			line.WriteRune(' ')
+			continue
+		}
+
+		index := 0
+		if high > low {
+			index = int((*value - low) / (high - low) * float64(len(sparklineBlocks)-1))
+		}
+
+		line.WriteRune(sparklineBlocks[index])
+	}
+
+	return line.String()
+}
+
+func getCmdMetricsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Show the key platform metrics of the resources hosting the application's services from Azure Monitor.",
+		[]string{
+			formatHelpNote("Container Apps report requests, CPU, memory and replicas." +
+				" App Services and Function Apps report requests, latency, CPU and memory."),
+			formatHelpNote("Platform metrics are usually available within a few minutes."),
+		})
+}
+
+func getCmdMetricsHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the metrics of all services over the last hour.": output.WithHighLightFormat("azd metrics"),
+		"Show the metrics of a specific service over the last day.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd metrics <service> --window 24h"),
+			output.WithWarningFormat("[Service name]")),
+	})
+}
@@ -0,0 +1,25 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sparkline(t *testing.T) {
+	require.Equal(t, "-", sparkline(nil))
+	require.Equal(t, "▁ █▅", sparkline([]*float64{convert.RefOf(0.0), nil, convert.RefOf(7.0), convert.RefOf(4.0)}))
+	require.Equal(t, "▁▁", sparkline([]*float64{convert.RefOf(3.0), convert.RefOf(3.0)}))
+}
+
+func Test_formatMetricValue(t *testing.T) {
+	require.Equal(t, "-", formatMetricValue(nil, "Count"))
+	require.Equal(t, "12", formatMetricValue(convert.RefOf(12.0), "Count"))
+	require.Equal(t, "1.5 MB", formatMetricValue(convert.RefOf(1.5*1024*1024), "Bytes"))
+	require.Equal(t, "250 ms", formatMetricValue(convert.RefOf(0.25), "Seconds"))
+	require.Equal(t, "0.500 cores", formatMetricValue(convert.RefOf(5e8), "NanoCores"))
+}
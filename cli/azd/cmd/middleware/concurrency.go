@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+)
+
+// The flag of the commands running services concurrently
+const ParallelismFlagName = "parallelism"
+
+type parallelismContextKey struct{}
+
+// WithParallelism returns a context limiting the number of services run at the same time by the action and its child
+// actions
+func WithParallelism(ctx context.Context, parallelism int) context.Context {
+	return context.WithValue(ctx, parallelismContextKey{}, parallelism)
+}
+
+// Parallelism returns the maximum number of services run at the same time set by --parallelism, if any
+func Parallelism(ctx context.Context) (int, bool) {
+	parallelism, has := ctx.Value(parallelismContextKey{}).(int)
+	return parallelism, has
+}
+
+// ConcurrencyMiddleware limits the number of services packaged and deployed at the same time with --parallelism.
+// The limit applies to the child actions of the action, ex. the deployment run by `azd up`.
+type ConcurrencyMiddleware struct {
+	options *Options
+}
+
+// Creates a new Concurrency middleware instance
+func NewConcurrencyMiddleware(options *Options) Middleware {
+	return &ConcurrencyMiddleware{
+		options: options,
+	}
+}
+
+// Invokes the action with the parallelism of --parallelism, when set
+func (m *ConcurrencyMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if _, has := Parallelism(ctx); has || m.options.Flags == nil {
+		return next(ctx)
+	}
+
+	flag := m.options.Flags.Lookup(ParallelismFlagName)
+	if flag == nil || !flag.Changed {
+		return next(ctx)
+	}
+
+	parallelism, err := m.options.Flags.GetInt(ParallelismFlagName)
+	if err != nil {
+		return nil, err
+	}
+
+	if parallelism < 1 {
+		return nil, errors.New("--parallelism must be greater than 0, 1 runs services sequentially")
+	}
+
+	return next(WithParallelism(ctx, parallelism))
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Concurrency_Run(t *testing.T) {
+	run := func(ctx context.Context, args ...string) (int, bool, error) {
+		flags := pflag.NewFlagSet("deploy", pflag.ContinueOnError)
+		flags.Int(ParallelismFlagName, 0, "")
+		require.NoError(t, flags.Parse(args))
+
+		var parallelism int
+		var has bool
+		middleware := NewConcurrencyMiddleware(&Options{Flags: flags})
+		_, err := middleware.Run(ctx, func(ctx context.Context) (*actions.ActionResult, error) {
+			parallelism, has = Parallelism(ctx)
+			return nil, nil
+		})
+
+		return parallelism, has, err
+	}
+
+	t.Run("Flag", func(t *testing.T) {
+		parallelism, has, err := run(context.Background(), "--parallelism", "8")
+		require.NoError(t, err)
+		require.True(t, has)
+		require.Equal(t, 8, parallelism)
+	})
+
+	t.Run("NotSet", func(t *testing.T) {
+		_, has, err := run(context.Background())
+		require.NoError(t, err)
+		require.False(t, has)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, _, err := run(context.Background(), "--parallelism", "0")
+		require.Error(t, err)
+	})
+
+	t.Run("ChildAction", func(t *testing.T) {
+		// The parallelism of the parent action applies to its child actions
+		parallelism, has, err := run(WithParallelism(context.Background(), 2), "--parallelism", "8")
+		require.NoError(t, err)
+		require.True(t, has)
+		require.Equal(t, 2, parallelism)
+	})
+}
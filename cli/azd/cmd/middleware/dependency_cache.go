@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The flag of the directory of the dependency cache of `azd restore`
+const DependencyCacheFlagName = "cache-dir"
+
+// The config key of the directory of the dependency cache of the commands restoring services
+const DependencyCacheConfigKey = "restore.cacheDir"
+
+// DependencyCacheMiddleware shares the caches of the package managers restoring the services, ex. npm or NuGet, in the
+// directory of `--cache-dir` or the user config. The dependencies downloaded for a service are reused by the other
+// services, and CI pipelines can cache the directory between runs.
+type DependencyCacheMiddleware struct {
+	options *Options
+	// The directory of the user config, used when the flag isn't set
+	configDir string
+}
+
+// Creates a new DependencyCache middleware instance. The `--cache-dir` flag takes precedence over the user config.
+func NewDependencyCacheMiddleware(options *Options, userConfigManager config.UserConfigManager) Middleware {
+	configDir := ""
+	if azdConfig, err := userConfigManager.Load(); err != nil {
+		log.Printf("failed loading user config for the dependency cache: %v\n", err)
+	} else if value, has := azdConfig.Get(DependencyCacheConfigKey); has {
+		configDir = fmt.Sprint(value)
+	}
+
+	return &DependencyCacheMiddleware{
+		options:   options,
+		configDir: configDir,
+	}
+}
+
+// Invokes the action with the dependency cache of the directory, when set. Child actions use the dependency cache of
+// the command invoking them.
+func (m *DependencyCacheMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if _, has := tools.DependencyCacheDir(ctx, ""); has {
+		return next(ctx)
+	}
+
+	dir := m.configDir
+	if m.options.Flags != nil {
+		if flag := m.options.Flags.Lookup(DependencyCacheFlagName); flag != nil && flag.Changed {
+			dir = flag.Value.String()
+		}
+	}
+
+	if dir == "" {
+		return next(ctx)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving the dependency cache directory: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, osutil.PermissionDirectory); err != nil {
+		return nil, fmt.Errorf("creating the dependency cache directory: %w", err)
+	}
+
+	log.Printf("restoring dependencies with the dependency cache %s", dir)
+	return next(tools.WithDependencyCache(ctx, dir))
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DependencyCache_Run(t *testing.T) {
+	run := func(ctx context.Context, configDir string, args ...string) (string, bool, error) {
+		flags := pflag.NewFlagSet("restore", pflag.ContinueOnError)
+		flags.String(DependencyCacheFlagName, "", "")
+		require.NoError(t, flags.Parse(args))
+
+		var dir string
+		var has bool
+		middleware := &DependencyCacheMiddleware{options: &Options{Flags: flags}, configDir: configDir}
+		_, err := middleware.Run(ctx, func(ctx context.Context) (*actions.ActionResult, error) {
+			dir, has = tools.DependencyCacheDir(ctx, "npm")
+			return nil, nil
+		})
+
+		return dir, has, err
+	}
+
+	t.Run("Flag", func(t *testing.T) {
+		cacheDir := filepath.Join(t.TempDir(), "cache")
+		dir, has, err := run(context.Background(), filepath.Join(t.TempDir(), "config"), "--cache-dir", cacheDir)
+		require.NoError(t, err)
+		require.True(t, has)
+		require.Equal(t, filepath.Join(cacheDir, "npm"), dir)
+		require.DirExists(t, cacheDir)
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		configDir := filepath.Join(t.TempDir(), "config")
+		dir, has, err := run(context.Background(), configDir)
+		require.NoError(t, err)
+		require.True(t, has)
+		require.Equal(t, filepath.Join(configDir, "npm"), dir)
+	})
+
+	t.Run("NotSet", func(t *testing.T) {
+		_, has, err := run(context.Background(), "")
+		require.NoError(t, err)
+		require.False(t, has)
+	})
+
+	t.Run("ChildAction", func(t *testing.T) {
+		// The dependency cache of the parent action applies to its child actions
+		parentDir := t.TempDir()
+		dir, has, err := run(tools.WithDependencyCache(context.Background(), parentDir), "", "--cache-dir", t.TempDir())
+		require.NoError(t, err)
+		require.True(t, has)
+		require.Equal(t, filepath.Join(parentDir, "npm"), dir)
+	})
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// The flag of the commands supporting dry runs
+const DryRunFlagName = "dry-run"
+
+type dryRunContextKey struct{}
+
+// WithDryRun returns a context marking a dry run, operations are planned and displayed instead of executed
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, true)
+}
+
+// IsDryRun returns whether the context is the context of a dry run
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunContextKey{}).(bool)
+	return dryRun
+}
+
+// DryRunMiddleware marks the context of the actions run with --dry-run. The action, and its child actions, display the
+// operations they plan without executing them. Hooks aren't run during dry runs.
+type DryRunMiddleware struct {
+	options *Options
+	console input.Console
+}
+
+// Creates a new DryRun middleware instance
+func NewDryRunMiddleware(options *Options, console input.Console) Middleware {
+	return &DryRunMiddleware{
+		options: options,
+		console: console,
+	}
+}
+
+// Invokes the action with a dry run context when --dry-run is set
+func (m *DryRunMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	// Child actions of a dry run are dry runs as well
+	if IsDryRun(ctx) || m.options.Flags == nil {
+		return next(ctx)
+	}
+
+	dryRun, err := m.options.Flags.GetBool(DryRunFlagName)
+	if err != nil || !dryRun {
+		return next(ctx)
+	}
+
+	log.Printf("dry run of '%s'\n", m.options.CommandPath)
+	m.console.Message(ctx, output.WithGrayFormat(
+		"Dry run: the planned operations are displayed, no changes are made to Azure or the environment.\n"))
+
+	return next(WithDryRun(ctx))
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DryRun_Run(t *testing.T) {
+	newFlags := func(args ...string) *pflag.FlagSet {
+		flags := pflag.NewFlagSet("provision", pflag.ContinueOnError)
+		flags.Bool(DryRunFlagName, false, "")
+		require.NoError(t, flags.Parse(args))
+		return flags
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewDryRunMiddleware(
+			&Options{CommandPath: "azd provision", Flags: newFlags("--dry-run")}, mockContext.Console)
+
+		var dryRun bool
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			dryRun = IsDryRun(ctx)
+			return nil, nil
+		})
+
+		require.NoError(t, err)
+		require.True(t, dryRun)
+		require.Len(t, mockContext.Console.Output(), 1)
+	})
+
+	t.Run("NotDryRun", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewDryRunMiddleware(&Options{CommandPath: "azd provision", Flags: newFlags()}, mockContext.Console)
+
+		var dryRun bool
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			dryRun = IsDryRun(ctx)
+			return nil, nil
+		})
+
+		require.NoError(t, err)
+		require.False(t, dryRun)
+		require.Empty(t, mockContext.Console.Output())
+	})
+
+	t.Run("ChildOfDryRun", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewDryRunMiddleware(&Options{isChildAction: true}, mockContext.Console)
+
+		var dryRun bool
+		_, err := middleware.Run(WithDryRun(*mockContext.Context), func(ctx context.Context) (*actions.ActionResult, error) {
+			dryRun = IsDryRun(ctx)
+			return nil, nil
+		})
+
+		require.NoError(t, err)
+		require.True(t, dryRun)
+		require.Empty(t, mockContext.Console.Output())
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
@@ -12,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 )
 
@@ -46,6 +48,11 @@ func NewHooksMiddleware(
 
 // Runs the Hooks middleware
 func (m *HooksMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if IsDryRun(ctx) {
+		log.Println("dry run, skipping all hook registrations.")
+		return next(ctx)
+	}
+
 	ctx, _ = getServiceHooksRegistered(ctx)
 
 	env, err := m.lazyEnv.GetValue()
@@ -60,11 +67,36 @@ func (m *HooksMiddleware) Run(ctx context.Context, next NextFn) (*actions.Action
 		return next(ctx)
 	}
 
-	if err := m.registerServiceHooks(ctx, env, projectConfig); err != nil {
+	scheduler, err := m.registerServiceHooks(ctx, env, projectConfig)
+	if err != nil {
 		return nil, fmt.Errorf("failed registering service hooks, %w", err)
 	}
 
-	return m.registerCommandHooks(ctx, env, projectConfig, next)
+	return m.registerCommandHooks(ctx, env, projectConfig, func(ctx context.Context) (*actions.ActionResult, error) {
+		result, err := next(ctx)
+		if scheduler == nil {
+			return result, err
+		}
+
+		// Service hooks still waiting on dependencies that failed or did not run as part of the command are skipped
+		for _, skipped := range scheduler.Flush() {
+			reasons := []string{}
+			if len(skipped.Failed) > 0 {
+				reasons = append(reasons, fmt.Sprintf("'%s' failed", strings.Join(skipped.Failed, "', '")))
+			}
+			if len(skipped.Missing) > 0 {
+				reasons = append(reasons, fmt.Sprintf("'%s' did not run", strings.Join(skipped.Missing, "', '")))
+			}
+
+			m.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: Skipped hook '%s' since its dependencies did not complete: %s",
+				skipped.Key,
+				strings.Join(reasons, ", "),
+			))
+		}
+
+		return result, err
+	})
 }
 
 // Register command level hooks for the executing cobra command & action
@@ -75,6 +107,14 @@ func (m *HooksMiddleware) registerCommandHooks(
 	projectConfig *project.ProjectConfig,
 	next NextFn,
 ) (*actions.ActionResult, error) {
+	for hookName, hookConfigs := range projectConfig.Hooks {
+		for _, hookConfig := range hookConfigs {
+			if hookConfig != nil && len(hookConfig.DependsOn) > 0 {
+				return nil, fmt.Errorf("hook '%s' is invalid, 'dependsOn' is only supported for service hooks", hookName)
+			}
+		}
+	}
+
 	if projectConfig.Hooks == nil || len(projectConfig.Hooks) == 0 {
 		//nolint:lll
 		log.Println(
@@ -117,18 +157,48 @@ func (m *HooksMiddleware) registerCommandHooks(
 
 // Registers event handlers for all services within the project configuration
 // Runs hooks for each matching event handler
+// Returns the scheduler coordinating hook dependencies when the hooks were registered by this invocation
 func (m *HooksMiddleware) registerServiceHooks(
 	ctx context.Context,
 	env *environment.Environment,
 	projectConfig *project.ProjectConfig,
-) error {
+) (*ext.HooksScheduler, error) {
 	// Check if service hooks have already been registered higher up the chain
 	ctx, serviceHooksRegistered := getServiceHooksRegistered(ctx)
 	if *serviceHooksRegistered {
-		return nil
+		return nil, nil
 	}
 
+	dependencies, err := resolveServiceHookDependencies(projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler := ext.NewHooksScheduler()
+
 	for serviceName, service := range projectConfig.Services {
+		// Register markers for events other service hooks depend on when the service doesn't have a hook for it
+		for _, eventName := range dependedEvents(serviceName, dependencies) {
+			if _, has := service.Hooks[eventName]; has {
+				continue
+			}
+
+			eventKey := serviceHookKey(serviceName, eventName)
+			if err := service.AddHandler(
+				ext.Event(eventName),
+				func(ctx context.Context, eventArgs project.ServiceLifecycleEventArgs) error {
+					return scheduler.Complete(ctx, eventKey)
+				},
+			); err != nil {
+				return nil, fmt.Errorf(
+					"failed registering event handler for service '%s' and event '%s', %w",
+					serviceName,
+					eventName,
+					err,
+				)
+			}
+		}
+
 		// If the service hasn't configured any hooks we can continue on.
 		if service.Hooks == nil || len(service.Hooks) == 0 {
 			log.Printf("service '%s' does not require any command hooks.\n", serviceName)
@@ -145,10 +215,10 @@ func (m *HooksMiddleware) registerServiceHooks(
 			env,
 		)
 
-		for hookName, hookConfig := range service.Hooks {
-			hookType, eventName, err := inferHookType(hookName, hookConfig)
+		for hookName := range service.Hooks {
+			hookType, eventName, err := ext.InferHookType(hookName)
 			if err != nil {
-				return fmt.Errorf(
+				return nil, fmt.Errorf(
 					//nolint:lll
 					"%w for service '%s'. Hooks must start with 'pre' or 'post' and end in a valid service event name. Examples: restore, package, deploy",
 					err,
@@ -156,11 +226,20 @@ func (m *HooksMiddleware) registerServiceHooks(
 				)
 			}
 
+			hookKey := serviceHookKey(serviceName, hookName)
 			if err := service.AddHandler(
 				ext.Event(hookName),
-				m.createServiceEventHandler(ctx, hookType, eventName, serviceHooksRunner),
+				m.createServiceEventHandler(
+					ctx,
+					hookType,
+					eventName,
+					serviceHooksRunner,
+					scheduler,
+					hookKey,
+					dependencies[hookKey],
+				),
 			); err != nil {
-				return fmt.Errorf(
+				return nil, fmt.Errorf(
 					"failed registering event handler for service '%s' and event '%s', %w",
 					serviceName,
 					hookName,
@@ -173,34 +252,132 @@ func (m *HooksMiddleware) registerServiceHooks(
 	// Set context value that the service hooks have been registered
 	*serviceHooksRegistered = true
 
-	return nil
+	return scheduler, nil
+}
+
+// Resolves the `dependsOn` declarations of all service hooks into a graph of hook keys to their dependencies.
+// Dependencies are declared as `<service>` to depend on the same event of another service
+// or as `<service>.<event>` to depend on a specific event of another service. (ex. `worker.postdeploy`)
+func resolveServiceHookDependencies(projectConfig *project.ProjectConfig) (map[string][]string, error) {
+	dependencies := map[string][]string{}
+
+	for serviceName, service := range projectConfig.Services {
+		for hookName, hookConfigs := range service.Hooks {
+			hookKey := serviceHookKey(serviceName, hookName)
+			dependsOn := hookDependsOn(hookConfigs)
+			if len(dependsOn) == 0 {
+				continue
+			}
+
+			// Pre hooks guard the event they run before, deferring them would run them after the event instead
+			if hookType, _, err := ext.InferHookType(hookName); err == nil && hookType == ext.HookTypePre {
+				return nil, fmt.Errorf(
+					"hook '%s' for service '%s' is invalid, 'dependsOn' is only supported for post hooks",
+					hookName,
+					serviceName,
+				)
+			}
+
+			for _, dependency := range dependsOn {
+				dependencyService, dependencyEvent, found := strings.Cut(dependency, ".")
+				if !found {
+					dependencyEvent = hookName
+				}
+
+				if _, has := projectConfig.Services[dependencyService]; !has {
+					return nil, fmt.Errorf(
+						"hook '%s' for service '%s' depends on unknown service '%s'",
+						hookName,
+						serviceName,
+						dependencyService,
+					)
+				}
+
+				if _, _, err := ext.InferHookType(dependencyEvent); err != nil {
+					return nil, fmt.Errorf(
+						"hook '%s' for service '%s' has invalid dependency '%s', %w",
+						hookName,
+						serviceName,
+						dependency,
+						err,
+					)
+				}
+
+				dependencyKey := serviceHookKey(dependencyService, dependencyEvent)
+				if dependencyKey == hookKey {
+					return nil, fmt.Errorf("hook '%s' for service '%s' cannot depend on itself", hookName, serviceName)
+				}
+
+				dependencies[hookKey] = append(dependencies[hookKey], dependencyKey)
+			}
+		}
+	}
+
+	if err := ext.ValidateHookDependencies(dependencies); err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+// Gets the combined dependencies of all hooks configured for the same event
+func hookDependsOn(hookConfigs []*ext.HookConfig) []string {
+	dependsOn := []string{}
+	for _, hookConfig := range hookConfigs {
+		if hookConfig != nil {
+			dependsOn = append(dependsOn, hookConfig.DependsOn...)
+		}
+	}
+
+	return dependsOn
+}
+
+// Gets the event names of the specified service that other service hooks depend on
+func dependedEvents(serviceName string, dependencies map[string][]string) []string {
+	events := []string{}
+	seen := map[string]struct{}{}
+
+	for _, keys := range dependencies {
+		for _, key := range keys {
+			dependencyService, eventName, _ := strings.Cut(key, ".")
+			if dependencyService != serviceName {
+				continue
+			}
+
+			if _, has := seen[eventName]; has {
+				continue
+			}
+
+			seen[eventName] = struct{}{}
+			events = append(events, eventName)
+		}
+	}
+
+	return events
+}
+
+func serviceHookKey(serviceName string, hookName string) string {
+	return fmt.Sprintf("%s.%s", serviceName, hookName)
 }
 
 // Creates an event handler for the specified service config and event name
+// The hook is scheduled to run once all of its dependencies have completed
 func (m *HooksMiddleware) createServiceEventHandler(
 	ctx context.Context,
 	hookType ext.HookType,
 	hookName string,
 	hooksRunner *ext.HooksRunner,
+	scheduler *ext.HooksScheduler,
+	hookKey string,
+	dependsOn []string,
 ) ext.EventHandlerFn[project.ServiceLifecycleEventArgs] {
 	return func(ctx context.Context, eventArgs project.ServiceLifecycleEventArgs) error {
-		return hooksRunner.RunHooks(ctx, hookType, hookName)
+		return scheduler.Schedule(ctx, hookKey, dependsOn, func(ctx context.Context) error {
+			return hooksRunner.RunHooks(ctx, hookType, hookName)
+		})
 	}
 }
 
-func inferHookType(name string, config *ext.HookConfig) (ext.HookType, string, error) {
-	// Validate name length so go doesn't PANIC for string slicing below
-	if len(name) < 4 {
-		return "", "", fmt.Errorf("unable to infer hook '%s'", name)
-	} else if name[:3] == "pre" {
-		return ext.HookTypePre, name[3:], nil
-	} else if name[:4] == "post" {
-		return ext.HookTypePost, name[4:], nil
-	}
-
-	return "", "", fmt.Errorf("unable to infer hook '%s'", name)
-}
-
 // Gets a value that returns whether or not service hooks have already been registered
 // for the current project config
 // Optionally constructs a new go context that stores a pointer to this value
@@ -27,11 +27,11 @@ func Test_CommandHooks_Middleware_WithValidProjectAndMatchingCommand(t *testing.
 
 	projectConfig := project.ProjectConfig{
 		Name: envName,
-		Hooks: map[string]*ext.HookConfig{
-			"precommand": {
+		Hooks: ext.HooksConfig{
+			"precommand": {{
 				Run:   "echo 'hello'",
 				Shell: ext.ShellTypeBash,
-			},
+			}},
 		},
 	}
 
@@ -59,11 +59,11 @@ func Test_CommandHooks_Middleware_ValidProjectWithDifferentCommand(t *testing.T)
 
 	projectConfig := project.ProjectConfig{
 		Name: envName,
-		Hooks: map[string]*ext.HookConfig{
-			"precommand": {
+		Hooks: ext.HooksConfig{
+			"precommand": {{
 				Run:   "echo 'hello'",
 				Shell: ext.ShellTypeBash,
-			},
+			}},
 		},
 	}
 
@@ -117,11 +117,11 @@ func Test_CommandHooks_Middleware_PreHookWithError(t *testing.T) {
 
 	projectConfig := project.ProjectConfig{
 		Name: envName,
-		Hooks: map[string]*ext.HookConfig{
-			"precommand": {
+		Hooks: ext.HooksConfig{
+			"precommand": {{
 				Run:   "exit 1",
 				Shell: ext.ShellTypeBash,
-			},
+			}},
 		},
 	}
 
@@ -152,12 +152,12 @@ func Test_CommandHooks_Middleware_PreHookWithErrorAndContinue(t *testing.T) {
 
 	projectConfig := project.ProjectConfig{
 		Name: envName,
-		Hooks: map[string]*ext.HookConfig{
-			"precommand": {
+		Hooks: ext.HooksConfig{
+			"precommand": {{
 				Run:             "exit 1",
 				Shell:           ext.ShellTypeBash,
 				ContinueOnError: true,
-			},
+			}},
 		},
 	}
 
@@ -188,11 +188,11 @@ func Test_CommandHooks_Middleware_WithCmdAlias(t *testing.T) {
 
 	projectConfig := project.ProjectConfig{
 		Name: envName,
-		Hooks: map[string]*ext.HookConfig{
-			"prealias": {
+		Hooks: ext.HooksConfig{
+			"prealias": {{
 				Run:   "echo 'hello'",
 				Shell: ext.ShellTypeBash,
-			},
+			}},
 		},
 	}
 
@@ -228,11 +228,11 @@ func Test_ServiceHooks_Registered(t *testing.T) {
 		Language:        "ts",
 		RelativePath:    "./src/api",
 		Host:            "appservice",
-		Hooks: map[string]*ext.HookConfig{
-			"predeploy": {
+		Hooks: ext.HooksConfig{
+			"predeploy": {{
 				Shell: ext.ShellTypeBash,
 				Run:   "echo 'Hello'",
-			},
+			}},
 		},
 	}
 
@@ -270,6 +270,116 @@ func Test_ServiceHooks_Registered(t *testing.T) {
 	require.Equal(t, 1, preDeployCount)
 }
 
+func Test_ServiceHooks_DependsOn(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := createAzdContext(t)
+
+	envName := "test"
+	runOptions := Options{CommandPath: "deploy"}
+
+	projectConfig := project.ProjectConfig{
+		Name:     envName,
+		Services: map[string]*project.ServiceConfig{},
+	}
+
+	apiConfig := &project.ServiceConfig{
+		EventDispatcher: ext.NewEventDispatcher[project.ServiceLifecycleEventArgs](project.ServiceEvents...),
+		Language:        "ts",
+		RelativePath:    "./src/api",
+		Host:            "appservice",
+		Hooks: ext.HooksConfig{
+			"postdeploy": {{
+				Shell:     ext.ShellTypeBash,
+				Run:       "echo 'Hello'",
+				DependsOn: []string{"worker"},
+			}},
+		},
+	}
+
+	workerConfig := &project.ServiceConfig{
+		EventDispatcher: ext.NewEventDispatcher[project.ServiceLifecycleEventArgs](project.ServiceEvents...),
+		Language:        "ts",
+		RelativePath:    "./src/worker",
+		Host:            "appservice",
+	}
+
+	projectConfig.Services["api"] = apiConfig
+	projectConfig.Services["worker"] = workerConfig
+
+	log := []string{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "postdeploy")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		log = append(log, "api-postdeploy")
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	err := ensureAzdValid(*mockContext.Context, azdContext, envName, &projectConfig)
+	require.NoError(t, err)
+
+	apiConfig.Project = &projectConfig
+	workerConfig.Project = &projectConfig
+
+	nextFn := func(ctx context.Context) (*actions.ActionResult, error) {
+		for _, svc := range []*project.ServiceConfig{apiConfig, workerConfig} {
+			err := svc.Invoke(ctx, project.ServiceEventDeploy, project.ServiceLifecycleEventArgs{
+				Project: &projectConfig,
+				Service: svc,
+			}, func() error {
+				log = append(log, svc.RelativePath)
+				return nil
+			})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &actions.ActionResult{}, nil
+	}
+
+	result, err := runMiddleware(mockContext, azdContext, envName, &projectConfig, &runOptions, nextFn)
+
+	require.NotNil(t, result)
+	require.NoError(t, err)
+	require.Equal(t, []string{"./src/api", "./src/worker", "api-postdeploy"}, log)
+}
+
+func Test_ServiceHooks_DependsOn_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		hookName  string
+		dependsOn []string
+	}{
+		"UnknownService": {hookName: "postdeploy", dependsOn: []string{"unknown"}},
+		"InvalidEvent":   {hookName: "postdeploy", dependsOn: []string{"worker.deploy"}},
+		"Self":           {hookName: "postdeploy", dependsOn: []string{"api"}},
+		"PreHook":        {hookName: "predeploy", dependsOn: []string{"worker"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			projectConfig := &project.ProjectConfig{
+				Services: map[string]*project.ServiceConfig{
+					"api": {
+						Hooks: ext.HooksConfig{
+							test.hookName: {{
+								Shell:     ext.ShellTypeBash,
+								Run:       "echo 'Hello'",
+								DependsOn: test.dependsOn,
+							}},
+						},
+					},
+					"worker": {},
+				},
+			}
+
+			_, err := resolveServiceHookDependencies(projectConfig)
+			require.Error(t, err)
+		})
+	}
+}
+
 func createAzdContext(t *testing.T) *azdcontext.AzdContext {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
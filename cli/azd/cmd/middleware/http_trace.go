@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+)
+
+// The config key tracing the HTTP requests of every command, as `--debug-http` does
+const HttpTraceConfigKey = "debug.http"
+
+// HttpTraceMiddleware writes the traces of the HTTP requests sent by the Azure SDK clients of the action to stderr, and
+// reports the number of requests sent to each service with the telemetry of the command. The traces are written with
+// their own logger, so they're written whether or not the debug log is enabled.
+type HttpTraceMiddleware struct {
+	options *Options
+	enabled bool
+	logger  *log.Logger
+}
+
+// Creates a new HttpTrace middleware instance, enabled by `--debug-http` or `debug.http` in the user config
+func NewHttpTraceMiddleware(
+	options *Options,
+	globalOptions *internal.GlobalCommandOptions,
+	console input.Console,
+	userConfigManager config.UserConfigManager,
+) Middleware {
+	enabled := globalOptions.EnableHttpTracing
+	if !enabled {
+		azdConfig, err := userConfigManager.Load()
+		if err != nil {
+			log.Printf("failed loading user config for http tracing: %v\n", err)
+		} else if value, has := azdConfig.Get(HttpTraceConfigKey); has {
+			enabled, err = strconv.ParseBool(fmt.Sprint(value))
+			if err != nil {
+				log.Printf("ignoring invalid '%s' value '%v', it must be true or false\n", HttpTraceConfigKey, value)
+			}
+		}
+	}
+
+	return &HttpTraceMiddleware{
+		options: options,
+		enabled: enabled,
+		logger:  log.New(console.Handles().Stderr, "", log.LstdFlags),
+	}
+}
+
+// Invokes the action with a context tracing the HTTP requests. Child actions are traced by the command invoking them.
+func (m *HttpTraceMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if !m.enabled || m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	tracer := azsdk.NewHttpTracer(m.logger)
+	result, err := next(azsdk.WithHttpTracer(ctx, tracer))
+
+	// The hosts are reported as their well-known services, unknown hosts are counted as "other"
+	counts := map[string]int{}
+	for host, count := range tracer.RequestCounts() {
+		service, _ := mapService(host)
+		counts[service] += count
+	}
+
+	requests := make([]string, 0, len(counts))
+	for service, count := range counts {
+		requests = append(requests, fmt.Sprintf("%s:%d", service, count))
+	}
+	sort.Strings(requests)
+
+	m.logger.Printf("http: requests sent by '%s': %v", m.options.CommandPath, requests)
+	tracing.SetUsageAttributes(fields.HttpRequestCountsKey.StringSlice(requests))
+
+	return result, err
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HttpTrace_Run(t *testing.T) {
+	httpClient := mockhttp.NewMockHttpUtil()
+	httpClient.When(func(request *http.Request) bool {
+		return true
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	traces := &bytes.Buffer{}
+	middleware := &HttpTraceMiddleware{
+		options: &Options{CommandPath: "azd deploy"},
+		enabled: true,
+		logger:  log.New(traces, "", 0),
+	}
+
+	_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+		clientOptions := azsdk.NewClientOptionsBuilder().
+			WithTransport(httpClient).
+			WithPerRetryPolicy(azsdk.NewHttpTracePolicy(ctx)).
+			BuildCoreClientOptions()
+		pipeline := runtime.NewPipeline("test", "1.0.0", runtime.PipelineOptions{}, clientOptions)
+
+		for _, host := range []string{"management.azure.com", "api.scm.azurewebsites.net", "example.com"} {
+			request, err := runtime.NewRequest(ctx, http.MethodGet, "https://"+host)
+			require.NoError(t, err)
+
+			_, err = pipeline.Do(request)
+			require.NoError(t, err)
+		}
+
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	var requests []string
+	for _, attribute := range tracing.GetUsageAttributes() {
+		if attribute.Key == fields.HttpRequestCountsKey {
+			requests = attribute.Value.AsStringSlice()
+		}
+	}
+	require.Equal(t, []string{"arm:1", "kudu:1", "other:1"}, requests)
+	require.Contains(t, traces.String(), "http: GET https://management.azure.com 200")
+	require.Contains(t, traces.String(), "http: requests sent by 'azd deploy': [arm:1 kudu:1 other:1]")
+}
+
+func Test_NewHttpTraceMiddleware(t *testing.T) {
+	newConsole := func(stderr *bytes.Buffer) input.Console {
+		return input.NewConsole(false, false, &bytes.Buffer{}, input.ConsoleHandles{
+			Stdin:  strings.NewReader(""),
+			Stdout: &bytes.Buffer{},
+			Stderr: stderr,
+		}, nil)
+	}
+
+	t.Run("Config", func(t *testing.T) {
+		t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+		userConfigManager := config.NewUserConfigManager()
+		azdConfig := config.NewEmptyConfig()
+		require.NoError(t, azdConfig.Set(HttpTraceConfigKey, true))
+		require.NoError(t, userConfigManager.Save(azdConfig))
+
+		// The debug log is discarded, the traces are still written to stderr
+		log.SetOutput(io.Discard)
+		t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+		stderr := &bytes.Buffer{}
+		middleware := NewHttpTraceMiddleware(
+			&Options{CommandPath: "azd provision"},
+			&internal.GlobalCommandOptions{},
+			newConsole(stderr),
+			userConfigManager,
+		)
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Contains(t, stderr.String(), "http: requests sent by 'azd provision': []")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+		stderr := &bytes.Buffer{}
+		middleware := NewHttpTraceMiddleware(
+			&Options{CommandPath: "azd provision"},
+			&internal.GlobalCommandOptions{},
+			newConsole(stderr),
+			config.NewUserConfigManager(),
+		)
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Empty(t, stderr.String())
+	})
+}
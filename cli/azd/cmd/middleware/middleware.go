@@ -30,11 +30,13 @@ type MiddlewareContext interface {
 
 // Middleware Run options
 type Options struct {
-	CommandPath   string
-	Name          string
-	Aliases       []string
-	Flags         *pflag.FlagSet
-	Args          []string
+	CommandPath string
+	Name        string
+	Aliases     []string
+	Flags       *pflag.FlagSet
+	Args        []string
+	// The annotations of the cobra command, ex. ExtensionCommandAnnotation
+	Annotations   map[string]string
 	isChildAction bool
 }
 
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/network"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// OfflineMiddleware fails the commands requiring the network in offline mode, before they do any work. It's registered
+// for the actions whose descriptor sets `RequiresNetwork`.
+type OfflineMiddleware struct {
+	options        *Options
+	networkOptions *network.Options
+}
+
+// Creates a new Offline middleware instance
+func NewOfflineMiddleware(options *Options) Middleware {
+	return &OfflineMiddleware{
+		options:        options,
+		networkOptions: network.Current(),
+	}
+}
+
+// Fails the action when azd is offline
+func (m *OfflineMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if !m.networkOptions.Offline || m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	return nil, fmt.Errorf(
+		"%w, '%s' requires the network. Run it from a connected machine, or disable offline mode with %s",
+		network.ErrOffline, m.options.CommandPath, output.WithHighLightFormat("azd config unset network.offline"))
+}
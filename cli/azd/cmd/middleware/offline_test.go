@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/network"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Offline_Run(t *testing.T) {
+	run := func(options *Options, networkOptions *network.Options) (bool, error) {
+		middleware := &OfflineMiddleware{options: options, networkOptions: networkOptions}
+
+		ran := false
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			ran = true
+			return nil, nil
+		})
+
+		return ran, err
+	}
+
+	t.Run("Offline", func(t *testing.T) {
+		ran, err := run(&Options{CommandPath: "azd provision"}, &network.Options{Offline: true})
+		require.ErrorIs(t, err, network.ErrOffline)
+		require.ErrorContains(t, err, "azd provision")
+		require.False(t, ran)
+	})
+
+	t.Run("ChildAction", func(t *testing.T) {
+		// The command invoking the child action already required the network
+		ran, err := run(&Options{CommandPath: "provision", isChildAction: true}, &network.Options{Offline: true})
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+
+	t.Run("Online", func(t *testing.T) {
+		ran, err := run(&Options{CommandPath: "azd provision"}, &network.Options{})
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// OutputMiddleware makes the commands run with `--output json` usable from scripts by disabling prompts. With
+// `--output events`, the start and the completion of the command and of its child commands are also written as
+// lifecycle events, ex. provision.start and provision.done, and the result of the command is written as a final event.
+type OutputMiddleware struct {
+	options       *Options
+	globalOptions *internal.GlobalCommandOptions
+	console       input.Console
+	formatter     output.Formatter
+	events        *input.LifecycleEvents
+}
+
+// Creates a new Output middleware instance
+func NewOutputMiddleware(
+	options *Options,
+	globalOptions *internal.GlobalCommandOptions,
+	console input.Console,
+	formatter output.Formatter,
+	events *input.LifecycleEvents,
+) Middleware {
+	return &OutputMiddleware{
+		options:       options,
+		globalOptions: globalOptions,
+		console:       console,
+		formatter:     formatter,
+		events:        events,
+	}
+}
+
+// Invokes the action and writes its result when JSON or events output is selected
+func (m *OutputMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if !output.IsEventStream(m.formatter) {
+		return next(ctx)
+	}
+
+	// Prompts can't be answered by scripts, the default values are used instead
+	m.globalOptions.NoPrompt = true
+
+	// The subject of the events of the command, ex. provision for `azd provision` or the child action of `azd up`
+	subject := strings.TrimPrefix(m.options.CommandPath, "azd ")
+	m.events.Emit(subject, contracts.LifecyclePhaseStart, contracts.LifecycleEvent{})
+
+	result, err := next(ctx)
+	if err != nil {
+		m.events.Emit(subject, contracts.LifecyclePhaseFailed, contracts.LifecycleEvent{Error: err.Error()})
+	} else {
+		done := contracts.LifecycleEvent{Endpoints: m.events.TakeEndpoints()}
+		if result != nil && result.Message != nil {
+			done.Message = result.Message.Header
+		}
+
+		m.events.Emit(subject, contracts.LifecyclePhaseDone, done)
+	}
+
+	// With `--output json`, commands write their result to stdout and nothing else is written
+	if m.options.IsChildAction() || m.formatter.Kind() != output.EventsFormat {
+		return result, err
+	}
+
+	event := contracts.EventEnvelope{
+		Type:      contracts.CommandResultEventDataType,
+		Timestamp: time.Now(),
+		Data:      NewCommandResult(m.options.CommandPath, result, err, m.console),
+	}
+
+	// Written on a single line like the other events of the console
+	if writeErr := json.NewEncoder(m.console.GetWriter()).Encode(event); writeErr != nil {
+		log.Printf("failed writing command result: %v\n", writeErr)
+	}
+
+	return result, err
+}
+
+// NewCommandResult returns the result of the command, with the warnings recorded by the console
+func NewCommandResult(
+	command string,
+	result *actions.ActionResult,
+	err error,
+	console input.Console,
+) contracts.CommandResult {
+	commandResult := contracts.CommandResult{
+		Command:  command,
+		Success:  err == nil,
+		Warnings: []string{},
+		Errors:   []contracts.CommandError{},
+	}
+
+	if result != nil {
+		commandResult.TraceId = result.TraceID
+		if result.Message != nil {
+			commandResult.Message = &contracts.CommandResultMessage{
+				Header:   result.Message.Header,
+				FollowUp: result.Message.FollowUp,
+			}
+		}
+	}
+
+	if recorder, ok := console.(input.WarningsRecorder); ok && len(recorder.Warnings()) > 0 {
+		commandResult.Warnings = recorder.Warnings()
+	}
+
+	if err != nil {
+		// Joined errors, ex. from the services deployed concurrently, are reported individually
+		errs := []error{err}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+
+		for _, e := range errs {
+			if e == nil {
+				continue
+			}
+
+			code, _ := classifyError(e)
+			commandResult.Errors = append(commandResult.Errors, contracts.CommandError{Code: code, Message: e.Error()})
+		}
+	}
+
+	return commandResult
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Output_Run(t *testing.T) {
+	newConsole := func(buf *bytes.Buffer, formatter output.Formatter) input.Console {
+		return input.NewConsole(false, false, buf, input.ConsoleHandles{
+			Stdin:  strings.NewReader(""),
+			Stdout: buf,
+			Stderr: buf,
+		}, formatter)
+	}
+
+	t.Run("Json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := newConsole(buf, &output.JsonFormatter{})
+		globalOptions := &internal.GlobalCommandOptions{}
+		middleware := NewOutputMiddleware(
+			&Options{CommandPath: "azd version"},
+			globalOptions,
+			console,
+			&output.JsonFormatter{},
+			input.NewLifecycleEvents(console))
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			require.True(t, globalOptions.NoPrompt)
+			return nil, nil
+		})
+		require.NoError(t, err)
+
+		// The command writes its own result, the result event is only written with `--output events`
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("Result", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := newConsole(buf, &output.EventsFormatter{})
+		globalOptions := &internal.GlobalCommandOptions{}
+		middleware := NewOutputMiddleware(
+			&Options{CommandPath: "azd provision"},
+			globalOptions,
+			console,
+			&output.EventsFormatter{},
+			input.NewLifecycleEvents(console))
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			require.True(t, globalOptions.NoPrompt)
+			console.Message(ctx, output.WithWarningFormat("WARNING: The budget of the environment is exceeded."))
+
+			return &actions.ActionResult{
+				Message: &actions.ResultMessage{Header: "Your application was provisioned."},
+				TraceID: "trace",
+			}, errors.Join(responseError(http.StatusTooManyRequests, ""), errors.New("deployment canceled"))
+		})
+		require.Error(t, err)
+
+		// provision.start, the warning, provision.failed and the result
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 4)
+
+		var event struct {
+			Type contracts.EventDataType `json:"type"`
+			Data contracts.CommandResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[3]), &event))
+		require.Equal(t, contracts.CommandResultEventDataType, event.Type)
+		require.Equal(t, contracts.CommandResult{
+			Command:  "azd provision",
+			Success:  false,
+			Message:  &contracts.CommandResultMessage{Header: "Your application was provisioned."},
+			TraceId:  "trace",
+			Warnings: []string{"The budget of the environment is exceeded."},
+			Errors: []contracts.CommandError{
+				{Code: "service.arm.429", Message: responseError(http.StatusTooManyRequests, "").Error()},
+				{Code: "UnknownError", Message: "deployment canceled"},
+			},
+		}, event.Data)
+	})
+
+	t.Run("ChildAction", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := newConsole(buf, &output.JsonFormatter{})
+		middleware := NewOutputMiddleware(
+			&Options{isChildAction: true},
+			&internal.GlobalCommandOptions{},
+			console,
+			&output.JsonFormatter{},
+			input.NewLifecycleEvents(console))
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := newConsole(buf, &output.EventsFormatter{})
+		events := input.NewLifecycleEvents(console)
+		globalOptions := &internal.GlobalCommandOptions{}
+
+		deploy := NewOutputMiddleware(
+			&Options{CommandPath: "deploy", isChildAction: true},
+			globalOptions,
+			console,
+			&output.EventsFormatter{},
+			events)
+		up := NewOutputMiddleware(
+			&Options{CommandPath: "azd up"}, globalOptions, console, &output.EventsFormatter{}, events)
+
+		_, err := up.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			require.True(t, globalOptions.NoPrompt)
+
+			_, err := deploy.Run(ctx, func(ctx context.Context) (*actions.ActionResult, error) {
+				events.AddEndpoints("web", []string{"https://web.contoso.com/"})
+				return &actions.ActionResult{
+					Message: &actions.ResultMessage{Header: "Your application was deployed to Azure."},
+				}, nil
+			})
+			require.NoError(t, err)
+
+			return nil, errors.New("hook failed")
+		})
+		require.Error(t, err)
+
+		type event struct {
+			Type contracts.EventDataType  `json:"type"`
+			Data contracts.LifecycleEvent `json:"data"`
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 5)
+
+		actual := []event{}
+		for _, line := range lines[:4] {
+			var e event
+			require.NoError(t, json.Unmarshal([]byte(line), &e))
+			actual = append(actual, e)
+		}
+
+		require.Equal(t, []event{
+			{Type: "up.start"},
+			{Type: "deploy.start"},
+			{Type: "deploy.done", Data: contracts.LifecycleEvent{
+				Message:   "Your application was deployed to Azure.",
+				Endpoints: map[string][]string{"web": {"https://web.contoso.com/"}},
+			}},
+			{Type: "up.failed", Data: contracts.LifecycleEvent{Error: "hook failed"}},
+		}, actual)
+		require.Contains(t, lines[4], string(contracts.CommandResultEventDataType))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		globalOptions := &internal.GlobalCommandOptions{}
+		console := newConsole(buf, &output.NoneFormatter{})
+		middleware := NewOutputMiddleware(
+			&Options{}, globalOptions, console, &output.NoneFormatter{}, input.NewLifecycleEvents(console))
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.False(t, globalOptions.NoPrompt)
+		require.Empty(t, buf.String())
+	})
+}
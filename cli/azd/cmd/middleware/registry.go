@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"golang.org/x/exp/slices"
+)
+
+// Stage is a defined ordering point of the global middleware chain where registered middleware components are inserted
+type Stage string
+
+const (
+	// The first middleware components to run, before the output, debug and offline middleware
+	StageFirst Stage = "first"
+	// Runs before the telemetry middleware, outside of the span of the command
+	StageBeforeTelemetry Stage = "beforeTelemetry"
+	// Runs after the telemetry middleware, within the span of the command and before the timeout of the command starts
+	StageAfterTelemetry Stage = "afterTelemetry"
+	// The last global middleware components to run, after the retry middleware and before the middleware of the
+	// command groups and actions, ex. hooks
+	StageBeforeAction Stage = "beforeAction"
+)
+
+// The stages in the order their middleware components run
+var stages = []Stage{StageFirst, StageBeforeTelemetry, StageAfterTelemetry, StageBeforeAction}
+
+// The names of the middleware components registered by azd, which can't be reused by registered middleware
+var builtInMiddleware = map[string]bool{
+	"output":      true,
+	"debug":       true,
+	"offline":     true,
+	"telemetry":   true,
+	"httptrace":   true,
+	"timeout":     true,
+	"retry":       true,
+	"dryrun":      true,
+	"secrets":     true,
+	"concurrency": true,
+	"hooks":       true,
+}
+
+// Registry stores the middleware components inserted in the global middleware chain at the defined stages.
+// Middleware components registered at the same stage run in the order they were registered.
+type Registry struct {
+	mu            sync.Mutex
+	registrations map[Stage][]*actions.MiddlewareRegistration
+}
+
+// Creates a new middleware registry
+func NewRegistry() *Registry {
+	return &Registry{
+		registrations: map[Stage][]*actions.MiddlewareRegistration{},
+	}
+}
+
+// The registry of the middleware components inserted in the middleware chain of all azd commands
+var globalRegistry = NewRegistry()
+
+// Register registers the middleware component at the stage of the middleware chain of all azd commands. The
+// middleware components must be registered before the root command is created, ex. from an init function.
+func Register(stage Stage, registration *actions.MiddlewareRegistration) error {
+	return globalRegistry.Register(stage, registration)
+}
+
+// Registered returns the middleware components registered at the stage of the middleware chain of all azd commands
+func Registered(stage Stage) []*actions.MiddlewareRegistration {
+	return globalRegistry.Registered(stage)
+}
+
+// Register registers the middleware component at the stage. The resolver of the registration is resolved from the
+// container of the command like the built-in middleware components, the predicate is optional.
+func (r *Registry) Register(stage Stage, registration *actions.MiddlewareRegistration) error {
+	if !slices.Contains(stages, stage) {
+		return fmt.Errorf("middleware stage '%s' is not supported, supported stages are %v", stage, stages)
+	}
+
+	if registration == nil || registration.Name == "" {
+		return errors.New("middleware registration requires a name")
+	}
+
+	if registration.Resolver == nil || reflect.TypeOf(registration.Resolver).Kind() != reflect.Func {
+		return fmt.Errorf("middleware '%s' resolver must be a go function", registration.Name)
+	}
+
+	if builtInMiddleware[registration.Name] {
+		return fmt.Errorf("middleware '%s' is a built-in middleware and can't be registered", registration.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stageRegistrations := range r.registrations {
+		for _, existing := range stageRegistrations {
+			if existing.Name == registration.Name {
+				return fmt.Errorf("middleware '%s' is already registered", registration.Name)
+			}
+		}
+	}
+
+	r.registrations[stage] = append(r.registrations[stage], registration)
+
+	return nil
+}
+
+// Registered returns the middleware components registered at the stage, in the order they were registered
+func (r *Registry) Registered(stage Stage) []*actions.MiddlewareRegistration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	registrations := make([]*actions.MiddlewareRegistration, len(r.registrations[stage]))
+	copy(registrations, r.registrations[stage])
+
+	return registrations
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_Register(t *testing.T) {
+	newMiddleware := func() Middleware {
+		return &testMiddleware{}
+	}
+
+	t.Run("OrderedByStage", func(t *testing.T) {
+		registry := NewRegistry()
+		require.NoError(t, registry.Register(StageBeforeTelemetry, &actions.MiddlewareRegistration{
+			Name: "audit", Resolver: newMiddleware,
+		}))
+		require.NoError(t, registry.Register(StageBeforeTelemetry, &actions.MiddlewareRegistration{
+			Name: "policy", Resolver: newMiddleware,
+		}))
+		require.NoError(t, registry.Register(StageBeforeAction, &actions.MiddlewareRegistration{
+			Name: "quota", Resolver: newMiddleware,
+		}))
+
+		names := []string{}
+		for _, registration := range registry.Registered(StageBeforeTelemetry) {
+			names = append(names, registration.Name)
+		}
+		require.Equal(t, []string{"audit", "policy"}, names)
+		require.Len(t, registry.Registered(StageBeforeAction), 1)
+		require.Empty(t, registry.Registered(StageFirst))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		registry := NewRegistry()
+		require.NoError(t, registry.Register(StageFirst, &actions.MiddlewareRegistration{
+			Name: "audit", Resolver: newMiddleware,
+		}))
+
+		for name, test := range map[string]struct {
+			stage        Stage
+			registration *actions.MiddlewareRegistration
+		}{
+			"UnknownStage": {Stage("afterAuth"), &actions.MiddlewareRegistration{Name: "x", Resolver: newMiddleware}},
+			"NoName":       {StageFirst, &actions.MiddlewareRegistration{Resolver: newMiddleware}},
+			"NotFunction":  {StageFirst, &actions.MiddlewareRegistration{Name: "x", Resolver: &testMiddleware{}}},
+			"BuiltIn":      {StageFirst, &actions.MiddlewareRegistration{Name: "telemetry", Resolver: newMiddleware}},
+			"Duplicate":    {StageAfterTelemetry, &actions.MiddlewareRegistration{Name: "audit", Resolver: newMiddleware}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				require.Error(t, registry.Register(test.stage, test.registration))
+			})
+		}
+
+		require.Len(t, registry.Registered(StageFirst), 1)
+		require.Empty(t, registry.Registered(StageAfterTelemetry))
+	})
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// RetryOptions configures the retries of actions failing with transient Azure errors
+type RetryOptions struct {
+	// The maximum number of attempts of an action, retries are disabled when 1
+	MaxAttempts int
+	// The delay before the first retry, doubled for each following retry
+	InitialDelay time.Duration
+	// The maximum delay between two attempts, including the delays requested by Retry-After headers
+	MaxDelay time.Duration
+}
+
+// DefaultRetryOptions are used when the user config doesn't set `retry.*` values. Retries are opt-in with
+// `retry.maxAttempts`, since retrying a command runs its hooks & the steps that already succeeded again, and the Azure
+// SDK clients already retry the failed requests.
+var DefaultRetryOptions = RetryOptions{
+	MaxAttempts:  1,
+	InitialDelay: 5 * time.Second,
+	MaxDelay:     time.Minute,
+}
+
+// The status codes of transient Azure errors, the action may succeed when retried
+var transientStatusCodes = map[int]struct{}{
+	http.StatusRequestTimeout:      {},
+	http.StatusTooManyRequests:     {},
+	http.StatusInternalServerError: {},
+	http.StatusBadGateway:          {},
+	http.StatusServiceUnavailable:  {},
+	http.StatusGatewayTimeout:      {},
+}
+
+// LoadRetryOptions returns the retry options of the user config, `retry.maxAttempts`, `retry.initialDelay` and
+// `retry.maxDelay`. Invalid values are logged and the defaults are used instead.
+func LoadRetryOptions(azdConfig config.Config) RetryOptions {
+	options := DefaultRetryOptions
+
+	if value, has := azdConfig.Get("retry.maxAttempts"); has {
+		maxAttempts, err := strconv.Atoi(fmt.Sprint(value))
+		if err != nil || maxAttempts < 1 {
+			log.Printf("ignoring invalid 'retry.maxAttempts' value '%v', it must be a positive number\n", value)
+		} else {
+			options.MaxAttempts = maxAttempts
+		}
+	}
+
+	loadDuration := func(key string, duration *time.Duration) {
+		if value, has := azdConfig.Get(key); has {
+			parsed, err := time.ParseDuration(fmt.Sprint(value))
+			if err != nil || parsed < 0 {
+				log.Printf("ignoring invalid '%s' value '%v', it must be a duration, ex. 10s\n", key, value)
+			} else {
+				*duration = parsed
+			}
+		}
+	}
+
+	loadDuration("retry.initialDelay", &options.InitialDelay)
+	loadDuration("retry.maxDelay", &options.MaxDelay)
+
+	return options
+}
+
+// RetryMiddleware retries actions failing with transient Azure errors, ex. throttling, with an exponential backoff
+type RetryMiddleware struct {
+	options      *Options
+	console      input.Console
+	retryOptions RetryOptions
+}
+
+// Creates a new Retry middleware instance, configured by the user config
+func NewRetryMiddleware(
+	options *Options,
+	console input.Console,
+	userConfigManager config.UserConfigManager,
+) Middleware {
+	retryOptions := DefaultRetryOptions
+
+	azdConfig, err := userConfigManager.Load()
+	if err != nil {
+		log.Printf("failed loading user config for retries: %v\n", err)
+	} else {
+		retryOptions = LoadRetryOptions(azdConfig)
+	}
+
+	return &RetryMiddleware{
+		options:      options,
+		console:      console,
+		retryOptions: retryOptions,
+	}
+}
+
+// Invokes the action and retries it while it fails with a transient Azure error. Child actions aren't retried on their
+// own, the successful child actions of a retried command are cached and aren't run again.
+func (m *RetryMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	delay := m.retryOptions.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		result, err := next(ctx)
+		if err == nil || attempt >= m.retryOptions.MaxAttempts {
+			return result, err
+		}
+
+		respErr, transient := transientError(err)
+		if !transient {
+			return result, err
+		}
+
+		wait := delay
+		if retryAfter, has := retryAfterDelay(respErr); has {
+			wait = retryAfter
+		}
+		if m.retryOptions.MaxDelay > 0 && wait > m.retryOptions.MaxDelay {
+			wait = m.retryOptions.MaxDelay
+		}
+
+		log.Printf("action failed with transient error, retrying in %s: %v\n", wait, err)
+		m.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: Azure responded with %d %s, retrying in %s (attempt %d of %d).",
+			respErr.StatusCode,
+			http.StatusText(respErr.StatusCode),
+			ux.DurationAsText(wait),
+			attempt+1,
+			m.retryOptions.MaxAttempts,
+		))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+
+		delay *= 2
+	}
+}
+
+// transientError returns the Azure response error of err when it is transient
+func transientError(err error) (*azcore.ResponseError, bool) {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return nil, false
+	}
+
+	_, transient := transientStatusCodes[respErr.StatusCode]
+	return respErr, transient
+}
+
+// retryAfterDelay returns the delay requested by the Retry-After header of the response, in seconds or as a date
+func retryAfterDelay(respErr *azcore.ResponseError) (time.Duration, bool) {
+	if respErr.RawResponse == nil {
+		return 0, false
+	}
+
+	value := respErr.RawResponse.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+	}
+
+	return 0, false
+}
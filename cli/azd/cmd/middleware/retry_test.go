@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadRetryOptions(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		require.Equal(t, DefaultRetryOptions, LoadRetryOptions(config.NewEmptyConfig()))
+	})
+
+	t.Run("ConfigSetStrings", func(t *testing.T) {
+		options := LoadRetryOptions(config.NewConfig(map[string]any{
+			"retry": map[string]any{
+				"maxAttempts":  "5",
+				"initialDelay": "2s",
+				"maxDelay":     "10s",
+			},
+		}))
+		require.Equal(t, RetryOptions{MaxAttempts: 5, InitialDelay: 2 * time.Second, MaxDelay: 10 * time.Second}, options)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		options := LoadRetryOptions(config.NewConfig(map[string]any{
+			"retry": map[string]any{
+				"maxAttempts":  "0",
+				"initialDelay": "soon",
+			},
+		}))
+		require.Equal(t, DefaultRetryOptions, options)
+	})
+}
+
+func Test_Retry_Run(t *testing.T) {
+	retryOptions := RetryOptions{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	t.Run("TransientThenSuccess", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := &RetryMiddleware{options: &Options{}, console: mockContext.Console, retryOptions: retryOptions}
+
+		attempts := 0
+		result, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, responseError(http.StatusTooManyRequests, "")
+			}
+
+			return &actions.ActionResult{}, nil
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, 2, attempts)
+		require.Len(t, mockContext.Console.Output(), 1)
+		require.Contains(t, mockContext.Console.Output()[0], "429 Too Many Requests")
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := &RetryMiddleware{options: &Options{}, console: mockContext.Console, retryOptions: retryOptions}
+
+		attempts := 0
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			attempts++
+			return nil, fmt.Errorf("deploying: %w", responseError(http.StatusServiceUnavailable, "1"))
+		})
+
+		var respErr *azcore.ResponseError
+		require.ErrorAs(t, err, &respErr)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := &RetryMiddleware{
+			options:      &Options{},
+			console:      mockContext.Console,
+			retryOptions: DefaultRetryOptions,
+		}
+
+		attempts := 0
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			attempts++
+			return nil, responseError(http.StatusTooManyRequests, "")
+		})
+
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+		require.Empty(t, mockContext.Console.Output())
+	})
+
+	t.Run("NotTransient", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := &RetryMiddleware{options: &Options{}, console: mockContext.Console, retryOptions: retryOptions}
+
+		for _, actionErr := range []error{responseError(http.StatusConflict, ""), errors.New("invalid template")} {
+			attempts := 0
+			_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+				attempts++
+				return nil, actionErr
+			})
+
+			require.ErrorIs(t, err, actionErr)
+			require.Equal(t, 1, attempts)
+		}
+	})
+
+	t.Run("ChildAction", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := &RetryMiddleware{
+			options:      &Options{isChildAction: true},
+			console:      mockContext.Console,
+			retryOptions: retryOptions,
+		}
+
+		attempts := 0
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			attempts++
+			return nil, responseError(http.StatusTooManyRequests, "")
+		})
+
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+	})
+}
+
+func Test_RetryAfterDelay(t *testing.T) {
+	delay, has := retryAfterDelay(responseError(http.StatusTooManyRequests, "30"))
+	require.True(t, has)
+	require.Equal(t, 30*time.Second, delay)
+
+	_, has = retryAfterDelay(responseError(http.StatusTooManyRequests, ""))
+	require.False(t, has)
+}
+
+func responseError(statusCode int, retryAfter string) *azcore.ResponseError {
+	request, _ := http.NewRequest(http.MethodPut, "https://management.azure.com/subscriptions/SUBSCRIPTION_ID", nil)
+	response := &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    request,
+	}
+	if retryAfter != "" {
+		response.Header.Set("Retry-After", retryAfter)
+	}
+
+	return &azcore.ResponseError{StatusCode: statusCode, RawResponse: response}
+}
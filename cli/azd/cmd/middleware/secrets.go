@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+)
+
+// SecretsMiddleware resolves the values of the environment referencing Key Vault secrets before the action and its
+// hooks run. Secrets are resolved when used, their values are never written to the .env file.
+type SecretsMiddleware struct {
+	serviceLocator  ioc.ServiceLocator
+	secretsProvider keyvault.SecretsProvider
+}
+
+// Creates a new Secrets middleware instance
+func NewSecretsMiddleware(serviceLocator ioc.ServiceLocator, secretsProvider keyvault.SecretsProvider) Middleware {
+	return &SecretsMiddleware{
+		serviceLocator:  serviceLocator,
+		secretsProvider: secretsProvider,
+	}
+}
+
+// Resolves the secrets referenced by the environment of the action
+func (m *SecretsMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	// The environment is resolved, and created when needed, before the hooks middleware reads it lazily so the
+	// action and its hooks share the environment with the resolved secrets
+	var env *environment.Environment
+	if err := m.serviceLocator.Resolve(&env); err != nil {
+		log.Printf("azd environment is not available, skipping secret resolution: %v", err)
+		return next(ctx)
+	}
+
+	if err := keyvault.ResolveSecrets(ctx, m.secretsProvider, env); err != nil {
+		return nil, err
+	}
+
+	return next(ctx)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSecretsProvider struct {
+	secrets map[string]string
+	reads   int
+}
+
+func (p *fakeSecretsProvider) GetSecret(
+	ctx context.Context, subscriptionId string, reference keyvault.SecretReference,
+) (string, error) {
+	p.reads++
+	if reference.SubscriptionId != "" {
+		subscriptionId = reference.SubscriptionId
+	}
+
+	value, has := p.secrets[subscriptionId+"/"+reference.VaultName+"/"+reference.SecretName]
+	if !has {
+		return "", errors.New("secret not found")
+	}
+
+	return value, nil
+}
+
+func (p *fakeSecretsProvider) SetSecret(
+	ctx context.Context, subscriptionId string, reference keyvault.SecretReference, value string,
+) error {
+	return nil
+}
+
+func Test_Secrets_Run(t *testing.T) {
+	newMiddleware := func(env *environment.Environment, provider keyvault.SecretsProvider) Middleware {
+		container := ioc.NewNestedContainer(nil)
+		ioc.RegisterInstance(container, env)
+
+		return NewSecretsMiddleware(ioc.NewServiceLocator(container), provider)
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		env := environment.EphemeralWithValues("test", map[string]string{
+			environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			"DB_PASSWORD":                        "akvs://kv-todo/db-password",
+			"API_KEY":                            "akvs://OTHER_SUBSCRIPTION/kv-shared/api-key",
+			"API_URL":                            "https://api.azurewebsites.net",
+		})
+		provider := &fakeSecretsProvider{secrets: map[string]string{
+			"SUBSCRIPTION_ID/kv-todo/db-password":  "s3cr3t",
+			"OTHER_SUBSCRIPTION/kv-shared/api-key": "k3y",
+		}}
+		middleware := newMiddleware(env, provider)
+
+		var password string
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			password = env.Getenv("DB_PASSWORD")
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", password)
+		require.Equal(t, "k3y", env.Getenv("API_KEY"))
+		require.Equal(t, "https://api.azurewebsites.net", env.Getenv("API_URL"))
+		require.Equal(t, "akvs://kv-todo/db-password", env.Dotenv()["DB_PASSWORD"])
+
+		// Child actions don't read the secrets again
+		_, err = middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, provider.reads)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		env := environment.EphemeralWithValues("test", map[string]string{
+			"DB_PASSWORD": "akvs://kv-todo/db-password",
+		})
+		middleware := newMiddleware(env, &fakeSecretsProvider{})
+
+		ran := false
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			ran = true
+			return nil, nil
+		})
+		require.ErrorContains(t, err, "DB_PASSWORD")
+		require.False(t, ran)
+	})
+}
@@ -11,10 +11,12 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/cancellation"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/spf13/pflag"
@@ -23,15 +25,21 @@ import (
 	"go.opentelemetry.io/otel/codes"
 )
 
+// The annotation of the commands run by extensions, reported as events.ExtensionCommandEvent
+const ExtensionCommandAnnotation = "azd.extension"
+
 // Telemetry middleware tracks telemetry for the given action
 type TelemetryMiddleware struct {
 	options *Options
+	// The telemetry level, the usage attributes are only set at the usage level
+	level telemetry.Level
 }
 
 // Creates a new Telemetry middleware instance
 func NewTelemetryMiddleware(options *Options) Middleware {
 	return &TelemetryMiddleware{
 		options: options,
+		level:   telemetry.CurrentLevel(),
 	}
 }
 
@@ -40,6 +48,10 @@ func (m *TelemetryMiddleware) Run(ctx context.Context, next NextFn) (*actions.Ac
 	// Note: CommandPath is constructed using the Use member on each command up to the root.
 	// It does not contain user input, and is safe for telemetry emission.
 	cmdPath := events.GetCommandEventName(m.options.CommandPath)
+	if _, has := m.options.Annotations[ExtensionCommandAnnotation]; has {
+		// Extension commands are named by their authors and may contain user input
+		cmdPath = events.ExtensionCommandEvent
+	}
 	spanCtx, span := tracing.Start(ctx, cmdPath)
 
 	log.Printf("TraceID: %s", span.SpanContext().TraceID())
@@ -52,7 +64,10 @@ func (m *TelemetryMiddleware) Run(ctx context.Context, next NextFn) (*actions.Ac
 			fields.CmdEntry.String(cmdPath))
 	}
 
-	if m.options.Flags != nil {
+	// At the errors-only level, the spans only describe the failures of the commands
+	usage := m.level == telemetry.LevelUsage
+
+	if usage && m.options.Flags != nil {
 		changedFlags := []string{}
 		m.options.Flags.VisitAll(func(f *pflag.Flag) {
 			if f.Changed {
@@ -62,11 +77,15 @@ func (m *TelemetryMiddleware) Run(ctx context.Context, next NextFn) (*actions.Ac
 		span.SetAttributes(fields.CmdFlags.StringSlice(changedFlags))
 	}
 
-	span.SetAttributes(fields.CmdArgsCount.Int(len(m.options.Args)))
+	if usage {
+		span.SetAttributes(fields.CmdArgsCount.Int(len(m.options.Args)))
+	}
 
 	defer func() {
 		// Include any usage attributes set
-		span.SetAttributes(tracing.GetUsageAttributes()...)
+		if usage {
+			span.SetAttributes(tracing.GetUsageAttributes()...)
+		}
 		span.End()
 	}()
 
@@ -84,6 +103,21 @@ func (m *TelemetryMiddleware) Run(ctx context.Context, next NextFn) (*actions.Ac
 }
 
 func mapError(err error, span tracing.Span) {
+	errCode, errDetails := classifyError(err)
+
+	if len(errDetails) > 0 {
+		for i, detail := range errDetails {
+			errDetails[i].Key = fields.ErrorKey(detail.Key)
+		}
+
+		span.SetAttributes(errDetails...)
+	}
+
+	span.SetStatus(codes.Error, errCode)
+}
+
+// classifyError returns the error code of err, ex. service.arm.429, and the attributes detailing the error
+func classifyError(err error) (string, []attribute.KeyValue) {
 	errCode := "UnknownError"
 	var errDetails []attribute.KeyValue
 
@@ -91,7 +125,14 @@ func mapError(err error, span tracing.Span) {
 	var armDeployErr *azcli.AzureDeploymentError
 	var toolExecErr *exec.ExitError
 	var authFailedErr *auth.AuthFailedError
-	if errors.As(err, &respErr) {
+	if errors.Is(err, ErrCommandTimeout) {
+		// Commands stopped at the deadline set by the user, ex. a hung provisioning
+		errCode = "command.timeout"
+	} else if cancellation.IsCanceled(err) {
+		// Commands canceled by the user, ex. by Ctrl+C, didn't fail
+		errCode = "user.canceled"
+		errDetails = append(errDetails, fields.CancelInterrupted.Bool(errors.Is(err, cancellation.ErrInterrupted)))
+	} else if errors.As(err, &respErr) {
 		serviceName := "other"
 		statusCode := -1
 		errDetails = append(errDetails, fields.ServiceErrorCode.String(respErr.ErrorCode))
@@ -152,6 +193,15 @@ func mapError(err error, span tracing.Span) {
 			fields.ToolName.String(toolName))
 
 		errCode = fmt.Sprintf("tool.%s.failed", toolName)
+
+		// Terraform, Pulumi, Docker and kubectl failures are classified from the error output of the tool
+		class, errorId := classifyToolError(toolName, toolExecErr.Stderr())
+		if class != "" {
+			errCode = fmt.Sprintf("tool.%s.%s", toolName, class)
+		}
+		if errorId != "" {
+			errDetails = append(errDetails, fields.ToolErrorId.String(errorId))
+		}
 	} else if errors.As(err, &authFailedErr) {
 		errDetails = append(errDetails, fields.ServiceName.String("aad"))
 		if authFailedErr.Parsed != nil {
@@ -168,15 +218,7 @@ func mapError(err error, span tracing.Span) {
 		errCode = "service.aad.failed"
 	}
 
-	if len(errDetails) > 0 {
-		for i, detail := range errDetails {
-			errDetails[i].Key = fields.ErrorKey(detail.Key)
-		}
-
-		span.SetAttributes(errDetails...)
-	}
-
-	span.SetStatus(codes.Error, errCode)
+	return errCode, errDetails
 }
 
 type deploymentErrorCode struct {
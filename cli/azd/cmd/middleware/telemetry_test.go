@@ -4,13 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	osexec "os/exec"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/baggage"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/cancellation"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -79,6 +84,32 @@ func Test_Telemetry_Run(t *testing.T) {
 			"Context should be a different instance since telemetry creates a new context",
 		)
 	})
+
+	t.Run("WithExtensionCommand", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		options := &Options{
+			CommandPath: "azd hello",
+			Name:        "hello",
+			Annotations: map[string]string{ExtensionCommandAnnotation: "hello"},
+		}
+		middleware := NewTelemetryMiddleware(options)
+
+		var actualContext context.Context
+		nextFn := func(ctx context.Context) (*actions.ActionResult, error) {
+			actualContext = ctx
+			return nil, nil
+		}
+
+		_, _ = middleware.Run(*mockContext.Context, nextFn)
+
+		// The name of the extension command isn't reported
+		require.Equal(
+			t,
+			events.ExtensionCommandEvent,
+			baggage.BaggageFromContext(actualContext).Get(fields.CmdEntry).AsString(),
+		)
+	})
 }
 
 func Test_mapError(t *testing.T) {
@@ -100,6 +131,34 @@ func Test_mapError(t *testing.T) {
 			wantErrReason:  "UnknownError",
 			wantErrDetails: nil,
 		},
+		{
+			name:          "WithCanceledError",
+			err:           fmt.Errorf("deploying to Azure: %w", context.Canceled),
+			wantErrReason: "user.canceled",
+			wantErrDetails: []attribute.KeyValue{
+				fields.ErrorKey(fields.CancelInterrupted).Bool(false),
+			},
+		},
+		{
+			name: "WithInterruptedToolError",
+			err: fmt.Errorf("%w: %w", cancellation.ErrInterrupted, &exec.ExitError{
+				Cmd:      "terraform",
+				ExitCode: 1,
+			}),
+			wantErrReason: "user.canceled",
+			wantErrDetails: []attribute.KeyValue{
+				fields.ErrorKey(fields.CancelInterrupted).Bool(true),
+			},
+		},
+		{
+			name: "WithTimeoutError",
+			err: fmt.Errorf("%w: %w", ErrCommandTimeout, &exec.ExitError{
+				Cmd:      "terraform",
+				ExitCode: 1,
+			}),
+			wantErrReason:  "command.timeout",
+			wantErrDetails: nil,
+		},
 		{
 			name: "WithToolExitError",
 			err: &exec.ExitError{
@@ -112,6 +171,23 @@ func Test_mapError(t *testing.T) {
 				fields.ErrorKey(fields.ToolExitCode).Int(51),
 			},
 		},
+		{
+			name: "WithTerraformProviderError",
+			err: exec.NewExitError(
+				osexec.ExitError{},
+				"terraform",
+				"",
+				"Error: creating Resource Group: resources.GroupsClient#CreateOrUpdate: Failure responding to request: "+
+					"StatusCode=403 -- Original Error: Code=\"AuthorizationFailed\" Message=\"denied\"",
+				true,
+			),
+			wantErrReason: "tool.terraform.provider",
+			wantErrDetails: []attribute.KeyValue{
+				fields.ErrorKey(fields.ToolName).String("terraform"),
+				fields.ErrorKey(fields.ToolExitCode).Int(-1),
+				fields.ErrorKey(fields.ToolErrorId).String("AuthorizationFailed"),
+			},
+		},
 		{
 			name: "WithArmDeploymentError",
 			err: &azcli.AzureDeploymentError{
@@ -222,6 +298,49 @@ func Test_mapError(t *testing.T) {
 	}
 }
 
+func Test_classifyToolError(t *testing.T) {
+	tests := []struct {
+		name        string
+		tool        string
+		stderr      string
+		wantClass   string
+		wantErrorId string
+	}{
+		{"TerraformStateLock", "terraform", "Error: Error acquiring the state lock", "stateLock", ""},
+		{"TerraformInit", "terraform", "Error: Failed to query available provider packages", "init", ""},
+		{"TerraformConfig", "terraform", "Error: Unsupported argument\n\n  on main.tf line 3", "config", ""},
+		{"PulumiStackNotFound", "pulumi", "error: no stack named 'dev' found", "stackNotFound", ""},
+		{
+			"PulumiProvider",
+			"pulumi",
+			"error: autorest/azure: Service returned an error. Status=403 Code=\"AuthorizationFailed\"",
+			"provider",
+			"AuthorizationFailed",
+		},
+		{"DockerDaemon", "docker", "Cannot connect to the Docker daemon at unix:///var/run/docker.sock.", "daemon", ""},
+		{
+			"DockerBuild",
+			"docker",
+			"ERROR: failed to solve: process \"/bin/sh -c npm ci\" did not complete successfully: exit code: 127",
+			"build",
+			"127",
+		},
+		{"DockerAuth", "docker", "unauthorized: authentication required", "auth", ""},
+		{"KubectlConnection", "kubectl", "Unable to connect to the server: dial tcp: i/o timeout", "connection", ""},
+		{"KubectlNotFound", "kubectl", "Error from server (NotFound): namespaces \"app\" not found", "notFound", "NotFound"},
+		{"KubectlServer", "kubectl", "Error from server (Conflict): operation cannot be fulfilled", "server", "Conflict"},
+		{"Unrecognized", "docker", "something else", "", ""},
+		{"OtherTool", "node", "Error from server (NotFound)", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, errorId := classifyToolError(tt.tool, tt.stderr)
+			require.Equal(t, tt.wantClass, class)
+			require.Equal(t, tt.wantErrorId, errorId)
+		})
+	}
+}
+
 func Test_cmdAsName(t *testing.T) {
 	tests := []struct {
 		name string
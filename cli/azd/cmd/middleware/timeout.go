@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// ErrCommandTimeout is returned by the commands that didn't complete before their deadline
+var ErrCommandTimeout = errors.New("the command timed out")
+
+// The config key of the timeout of the commands without their own timeout
+const defaultTimeoutConfigKey = "timeout.default"
+
+// TimeoutConfigKey returns the config key of the timeout of the command, ex. `timeout.env.refresh` for
+// `azd env refresh`
+func TimeoutConfigKey(commandPath string) string {
+	words := strings.Fields(commandPath)
+	if len(words) > 0 && words[0] == "azd" {
+		words = words[1:]
+	}
+
+	return "timeout." + strings.Join(words, ".")
+}
+
+// LoadCommandTimeout returns the timeout of the command in the user config, `timeout.<command>` or else
+// `timeout.default`. Invalid values are logged and ignored, 0 when the command has no timeout.
+func LoadCommandTimeout(azdConfig config.Config, commandPath string) time.Duration {
+	for _, key := range []string{TimeoutConfigKey(commandPath), defaultTimeoutConfigKey} {
+		value, has := azdConfig.Get(key)
+		if !has {
+			continue
+		}
+
+		timeout, err := time.ParseDuration(fmt.Sprint(value))
+		if err != nil || timeout < 0 {
+			log.Printf("ignoring invalid '%s' value '%v', it must be a duration, ex. 30m\n", key, value)
+			continue
+		}
+
+		return timeout
+	}
+
+	return 0
+}
+
+// TimeoutMiddleware cancels the actions running longer than the timeout of the command, set by `--timeout` or the
+// user config, so a hung command fails instead of running forever
+type TimeoutMiddleware struct {
+	options *Options
+	timeout time.Duration
+}
+
+// Creates a new Timeout middleware instance. The `--timeout` flag takes precedence over the user config.
+func NewTimeoutMiddleware(
+	options *Options,
+	globalOptions *internal.GlobalCommandOptions,
+	userConfigManager config.UserConfigManager,
+) Middleware {
+	timeout := globalOptions.Timeout
+	if timeout == 0 {
+		azdConfig, err := userConfigManager.Load()
+		if err != nil {
+			log.Printf("failed loading user config for timeouts: %v\n", err)
+		} else {
+			timeout = LoadCommandTimeout(azdConfig, options.CommandPath)
+		}
+	}
+
+	return &TimeoutMiddleware{
+		options: options,
+		timeout: timeout,
+	}
+}
+
+// Invokes the action with a context canceled at the deadline of the command. Child actions run within the deadline of
+// the command invoking them.
+func (m *TimeoutMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if m.timeout <= 0 || m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	log.Printf("'%s' times out after %s", m.options.CommandPath, m.timeout)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	result, err := next(timeoutCtx)
+
+	// The errors of actions stopped by the deadline are the errors of their canceled operations, ex. killed tools.
+	// They're reported as timeouts, unless the command was canceled by the user first.
+	if err != nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return result, fmt.Errorf(
+			"%w, '%s' did not complete within %s. Increase the timeout with %s, or with %s: %w",
+			ErrCommandTimeout,
+			m.options.CommandPath,
+			m.timeout,
+			output.WithHighLightFormat("--timeout"),
+			output.WithHighLightFormat("azd config set %s <duration>", TimeoutConfigKey(m.options.CommandPath)),
+			err)
+	}
+
+	return result, err
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadCommandTimeout(t *testing.T) {
+	azdConfig := config.NewConfig(map[string]any{
+		"timeout": map[string]any{
+			"default":   "10m",
+			"provision": "1h",
+			"env": map[string]any{
+				"refresh": "30s",
+			},
+			"deploy": "soon",
+		},
+	})
+
+	require.Equal(t, time.Hour, LoadCommandTimeout(azdConfig, "azd provision"))
+	require.Equal(t, 30*time.Second, LoadCommandTimeout(azdConfig, "azd env refresh"))
+	require.Equal(t, 10*time.Minute, LoadCommandTimeout(azdConfig, "azd package"))
+	// Invalid timeouts are ignored
+	require.Equal(t, 10*time.Minute, LoadCommandTimeout(azdConfig, "azd deploy"))
+	require.Equal(t, time.Duration(0), LoadCommandTimeout(config.NewEmptyConfig(), "azd provision"))
+}
+
+func Test_Timeout_Run(t *testing.T) {
+	// Waits for the deadline, the way canceled operations fail
+	hang := func(ctx context.Context) (*actions.ActionResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	t.Run("TimedOut", func(t *testing.T) {
+		middleware := &TimeoutMiddleware{options: &Options{CommandPath: "azd provision"}, timeout: time.Millisecond}
+
+		_, err := middleware.Run(context.Background(), hang)
+		require.ErrorIs(t, err, ErrCommandTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "timeout.provision")
+	})
+
+	t.Run("CompletedInTime", func(t *testing.T) {
+		middleware := &TimeoutMiddleware{options: &Options{CommandPath: "azd provision"}, timeout: time.Minute}
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			_, hasDeadline := ctx.Deadline()
+			require.True(t, hasDeadline)
+			return nil, errors.New("failed")
+		})
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrCommandTimeout)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		middleware := &TimeoutMiddleware{options: &Options{CommandPath: "azd provision"}, timeout: time.Minute}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := middleware.Run(ctx, hang)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotErrorIs(t, err, ErrCommandTimeout)
+	})
+
+	t.Run("NoTimeout", func(t *testing.T) {
+		middleware := &TimeoutMiddleware{options: &Options{CommandPath: "azd provision"}}
+
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			_, hasDeadline := ctx.Deadline()
+			require.False(t, hasDeadline)
+			return nil, nil
+		})
+		require.NoError(t, err)
+	})
+}
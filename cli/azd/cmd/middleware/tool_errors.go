@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"regexp"
+	"strings"
+)
+
+// toolErrorClass is a class of failures of a tool, recognized from the standard error of the tool
+type toolErrorClass struct {
+	// The class of the error code, ex. tool.docker.daemon
+	name string
+	// Any of the markers in the standard error identifies the class, markers are matched case insensitively
+	markers []string
+}
+
+// The error classes of the tools, in the order they are matched. Tools without classes, and failures of no class,
+// are reported as tool.<name>.failed.
+var toolErrorClasses = map[string][]toolErrorClass{
+	"terraform": {
+		{name: "stateLock", markers: []string{"error acquiring the state lock"}},
+		{name: "init", markers: []string{
+			"failed to query available provider packages",
+			"failed to install provider",
+			"backend initialization required",
+			"please run \"terraform init\"",
+		}},
+		{name: "auth", markers: []string{"error building azurerm client", "error building account"}},
+		{name: "provider", markers: []string{"statuscode=", "code=\"", "unexpected status"}},
+		{name: "config", markers: []string{
+			"unsupported argument",
+			"missing required argument",
+			"reference to undeclared",
+			"invalid reference",
+			"argument or block definition required",
+		}},
+	},
+	"docker": {
+		{name: "daemon", markers: []string{
+			"cannot connect to the docker daemon",
+			"error during connect",
+			"is the docker daemon running",
+		}},
+		{name: "auth", markers: []string{"unauthorized", "denied:", "pull access denied", "authentication required"}},
+		{name: "build", markers: []string{
+			"failed to solve",
+			"executor failed running",
+			"did not complete successfully",
+			"failed to compute cache key",
+		}},
+		{name: "pull", markers: []string{"manifest unknown", "not found: manifest"}},
+		{name: "push", markers: []string{"error parsing http 404 response body", "name unknown"}},
+	},
+	"pulumi": {
+		{name: "stackNotFound", markers: []string{"no stack named"}},
+		{name: "stateLock", markers: []string{"the stack is currently locked", "[409] conflict: another update is currently"}},
+		{name: "auth", markers: []string{
+			"passphrase must be set",
+			"incorrect passphrase",
+			"pulumi_access_token",
+			"error building account",
+		}},
+		{name: "program", markers: []string{"running program", "an unhandled error occurred"}},
+		{name: "provider", markers: []string{"statuscode=", "code=\"", "status=4", "status=5"}},
+	},
+	"kubectl": {
+		{name: "connection", markers: []string{"unable to connect to the server", "connection refused"}},
+		{name: "auth", markers: []string{
+			"you must be logged in",
+			"error from server (unauthorized)",
+			"error from server (forbidden)",
+		}},
+		{name: "notFound", markers: []string{"error from server (notfound)", "the server doesn't have a resource type"}},
+		{name: "invalid", markers: []string{"error from server (invalid)", "error validating data", "error: error parsing"}},
+		{name: "server", markers: []string{"error from server"}},
+	},
+}
+
+// The identifiers of the errors reported by the tools. Identifiers are error codes, never values of the user.
+var toolErrorIds = map[string][]*regexp.Regexp{
+	"terraform": {
+		// The ARM error code reported by the azurerm provider, ex. Code="AuthorizationFailed"
+		regexp.MustCompile(`Code="([A-Za-z][A-Za-z0-9.]*)"`),
+		regexp.MustCompile(`"code":\s*"([A-Za-z][A-Za-z0-9.]*)"`),
+	},
+	"docker": {
+		// The exit code of the failing build step, ex. exit code: 127
+		regexp.MustCompile(`exit code: ([0-9]+)`),
+	},
+	"pulumi": {
+		// The ARM error code reported by the azure-native provider, ex. Code="AuthorizationFailed"
+		regexp.MustCompile(`Code="([A-Za-z][A-Za-z0-9.]*)"`),
+	},
+	"kubectl": {
+		// The reason of the Kubernetes API status, ex. Error from server (NotFound)
+		regexp.MustCompile(`Error from server \(([A-Za-z]+)\)`),
+	},
+}
+
+// classifyToolError returns the class of the failure of the tool with the standard error, and the identifier of the
+// error when the tool reported one. The class is empty when the failure isn't recognized.
+func classifyToolError(toolName string, stderr string) (class string, errorId string) {
+	lower := strings.ToLower(stderr)
+	for _, errorClass := range toolErrorClasses[toolName] {
+		for _, marker := range errorClass.markers {
+			if strings.Contains(lower, marker) {
+				class = errorClass.name
+				break
+			}
+		}
+
+		if class != "" {
+			break
+		}
+	}
+
+	for _, expression := range toolErrorIds[toolName] {
+		if match := expression.FindStringSubmatch(stderr); match != nil {
+			errorId = match[1]
+			break
+		}
+	}
+
+	return class, errorId
+}
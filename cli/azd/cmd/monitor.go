@@ -5,30 +5,50 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/cli/browser"
+	"github.com/drone/envsubst"
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 type monitorFlags struct {
 	monitorLive     bool
+	platformMetrics bool
 	monitorLogs     bool
 	monitorOverview bool
+	monitorWorkbook bool
+	browser         bool
+	service         string
+	severity        string
+	query           string
+	queryFile       string
+	timespan        string
+	trace           string
 	global          *internal.GlobalCommandOptions
 	envFlag
 }
@@ -40,8 +60,59 @@ func (m *monitorFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommand
 		false,
 		"Open a browser to Application Insights Live Metrics. Live Metrics is currently not supported for Python apps.",
 	)
-	local.BoolVar(&m.monitorLogs, "logs", false, "Open a browser to Application Insights Logs.")
+	local.BoolVar(
+		&m.platformMetrics,
+		"platform-metrics",
+		false,
+		"Poll the near-real-time platform metrics of Application Insights in the terminal: "+
+			"the request rate, failure rate and server health, reported with a delay of about a minute.",
+	)
+	local.BoolVar(
+		&m.browser,
+		"browser",
+		false,
+		"With --logs, open a browser to Application Insights Logs instead.",
+	)
+	local.BoolVar(
+		&m.monitorLogs,
+		"logs",
+		false,
+		"Stream the traces and exceptions of the application from the Log Analytics workspace in the terminal.",
+	)
+	local.StringVar(&m.service, "service", "", "With --logs, only stream the logs of the service.")
+	local.StringVar(
+		&m.severity,
+		"severity",
+		"",
+		"With --logs, only stream the logs with at least the severity. (verbose, information, warning, error, critical)",
+	)
 	local.BoolVar(&m.monitorOverview, "overview", false, "Open a browser to Application Insights Overview Dashboard.")
+	local.BoolVar(
+		&m.monitorWorkbook,
+		"workbook",
+		false,
+		"Open a browser to the Azure Monitor workbook of the environment, "+
+			"creating or updating it from the workbook template of the project.",
+	)
+	local.StringVar(
+		&m.query,
+		"query",
+		"",
+		"Runs the KQL query against the Log Analytics workspace of the environment and prints the results.",
+	)
+	local.StringVar(&m.queryFile, "query-file", "", "Runs the KQL query saved in the file, see --query.")
+	local.StringVar(
+		&m.timespan,
+		"timespan",
+		"P1D",
+		"The ISO 8601 duration of the most recent data included by --query (ex. PT1H). Empty includes all data.",
+	)
+	local.StringVar(
+		&m.trace,
+		"trace",
+		"",
+		"Shows the application telemetry related to the azd operation or application operation with the trace id.",
+	)
 	m.envFlag.Bind(local, global)
 	m.global = global
 }
@@ -61,34 +132,88 @@ func newMonitorCmd() *cobra.Command {
 }
 
 type monitorAction struct {
-	azdCtx      *azdcontext.AzdContext
-	env         *environment.Environment
-	subResolver account.SubscriptionTenantResolver
-	azCli       azcli.AzCli
-	console     input.Console
-	flags       *monitorFlags
+	azdCtx           *azdcontext.AzdContext
+	env              *environment.Environment
+	projectConfig    *project.ProjectConfig
+	subResolver      account.SubscriptionTenantResolver
+	azCli            azcli.AzCli
+	resourceManager  project.ResourceManager
+	releaseAnnotator *infra.ReleaseAnnotator
+	console          input.Console
+	formatter        output.Formatter
+	writer           io.Writer
+	flags            *monitorFlags
 }
 
 func newMonitorAction(
 	azdCtx *azdcontext.AzdContext,
 	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
 	subResolver account.SubscriptionTenantResolver,
 	azCli azcli.AzCli,
+	resourceManager project.ResourceManager,
+	releaseAnnotator *infra.ReleaseAnnotator,
 	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
 	flags *monitorFlags,
 ) actions.Action {
 	return &monitorAction{
-		azdCtx:      azdCtx,
-		env:         env,
-		azCli:       azCli,
-		console:     console,
-		flags:       flags,
-		subResolver: subResolver,
+		azdCtx:           azdCtx,
+		env:              env,
+		projectConfig:    projectConfig,
+		azCli:            azCli,
+		resourceManager:  resourceManager,
+		releaseAnnotator: releaseAnnotator,
+		console:          console,
+		formatter:        formatter,
+		writer:           writer,
+		flags:            flags,
+		subResolver:      subResolver,
 	}
 }
 
 func (m *monitorAction) Run(ctx context.Context) (*actions.ActionResult, error) {
-	if !m.flags.monitorLive && !m.flags.monitorLogs && !m.flags.monitorOverview {
+	query, err := m.loadQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	if m.flags.browser && !m.flags.monitorLogs {
+		return nil, errors.New("--browser can only be used with --logs")
+	}
+
+	streamLogs := m.flags.monitorLogs && !m.flags.browser
+	if (m.flags.service != "" || m.flags.severity != "") && !streamLogs {
+		return nil, errors.New("--service and --severity can only be used with --logs")
+	}
+
+	severity := 0
+	if m.flags.severity != "" {
+		severity, err = parseLogSeverity(m.flags.severity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.flags.service != "" {
+		if _, has := m.projectConfig.Services[m.flags.service]; !has {
+			return nil, fmt.Errorf("service '%s' is not defined in '%s'", m.flags.service, azdcontext.ProjectFileName)
+		}
+	}
+
+	if m.flags.trace != "" {
+		if query != "" {
+			return nil, errors.New("--trace cannot be used with --query or --query-file")
+		}
+
+		if !traceIdRegex.MatchString(m.flags.trace) {
+			return nil, fmt.Errorf("'%s' is not a valid trace id", m.flags.trace)
+		}
+	}
+
+	if query == "" && m.flags.trace == "" && !m.flags.monitorLive && !m.flags.platformMetrics &&
+		!m.flags.monitorLogs && !m.flags.monitorOverview && !m.flags.monitorWorkbook {
 		m.flags.monitorOverview = true
 	}
 
@@ -107,6 +232,11 @@ func (m *monitorAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	var insightsResources []azcli.AzCliResource
 	var portalResources []azcli.AzCliResource
+	var workbookResources []azcli.AzCliResource
+	var workspaceResources []azcli.AzCliResource
+
+	// The resource group hosting the application telemetry, where the workbook of the project is created
+	workbookGroup := resourceGroups[0]
 
 	for _, resourceGroup := range resourceGroups {
 		resources, err := m.azCli.ListResourceGroupResources(
@@ -120,43 +250,74 @@ func (m *monitorAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			case string(infra.AzureResourceTypePortalDashboard):
 				portalResources = append(portalResources, resource)
 			case string(infra.AzureResourceTypeAppInsightComponent):
+				if len(insightsResources) == 0 {
+					workbookGroup = resourceGroup
+				}
 				insightsResources = append(insightsResources, resource)
+			case string(infra.AzureResourceTypeLogAnalyticsWorkspace):
+				workspaceResources = append(workspaceResources, resource)
+			default:
+				if strings.EqualFold(resource.Type, string(infra.AzureResourceTypeWorkbook)) {
+					workbookResources = append(workbookResources, resource)
+				}
 			}
 		}
 	}
 
-	if len(insightsResources) == 0 && (m.flags.monitorLive || m.flags.monitorLogs) {
-		return nil, fmt.Errorf("application does not contain an Application Insights resource")
+	if query != "" {
+		return nil, m.runQuery(ctx, workspaceResources, query, m.flags.timespan)
 	}
 
-	if len(portalResources) == 0 && m.flags.monitorOverview {
-		return nil, fmt.Errorf("application does not contain an Application Insights dashboard")
+	if m.flags.trace != "" {
+		return nil, m.showTrace(ctx, workspaceResources)
 	}
 
-	openWithDefaultBrowser := func(url string) {
-		fmt.Fprintf(m.console.Handles().Stdout, "Opening %s in the default browser...\n", url)
+	if streamLogs {
+		roles, err := m.serviceRoles(ctx, resourceGroups)
+		if err != nil {
+			return nil, err
+		}
 
-		// In Codespaces and devcontainers a $BROWSER environment variable is
-		// present whose value is an executable that launches the browser when
-		// called with the form:
-		// $BROWSER <url>
+		return nil, m.streamLogs(ctx, workspaceResources, severity, roles)
+	}
 
-		const BrowserEnvVarName = "BROWSER"
+	if m.flags.monitorWorkbook && m.projectConfig.Monitor != nil && m.projectConfig.Monitor.Workbook != nil {
+		sourceId := workbookGroup.Id
+		if len(insightsResources) > 0 {
+			sourceId = insightsResources[0].Id
+		}
 
-		if envBrowser := os.Getenv(BrowserEnvVarName); len(envBrowser) > 0 {
-			if err := exec.Command(envBrowser, url).Run(); err != nil {
-				fmt.Fprintf(
-					m.console.Handles().Stderr,
-					"warning: failed to open browser configured by $BROWSER: %s\n",
-					err.Error(),
-				)
-			}
-			return
+		workbook, err := m.createOrUpdateWorkbook(ctx, workbookGroup, sourceId)
+		if err != nil {
+			return nil, err
 		}
 
-		if err := browser.OpenURL(url); err != nil {
-			fmt.Fprintf(m.console.Handles().Stderr, "warning: failed to open default browser: %s\n", err.Error())
+		workbookResources = []azcli.AzCliResource{workbook}
+	}
+
+	if len(workbookResources) == 0 && m.flags.monitorWorkbook {
+		return nil, fmt.Errorf(
+			"application does not contain an Azure Monitor workbook. Add a workbook template to '%s' with %s",
+			azdcontext.ProjectFileName,
+			output.WithHighLightFormat("monitor.workbook.path"),
+		)
+	}
+
+	if len(insightsResources) == 0 && (m.flags.monitorLive || m.flags.platformMetrics || m.flags.monitorLogs) {
+		return nil, fmt.Errorf("application does not contain an Application Insights resource")
+	}
+
+	if len(portalResources) == 0 && m.flags.monitorOverview {
+		return nil, fmt.Errorf("application does not contain an Application Insights dashboard")
+	}
+
+	if m.flags.platformMetrics {
+		if len(insightsResources) > 1 {
+			log.Printf(
+				"found %d application insights resources, polling '%s'", len(insightsResources), insightsResources[0].Name)
 		}
+
+		return nil, m.streamLiveMetrics(ctx, insightsResources[0])
 	}
 
 	tenantId, err := m.subResolver.LookupTenant(ctx, m.env.GetSubscriptionId())
@@ -167,26 +328,244 @@ func (m *monitorAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	for _, insightsResource := range insightsResources {
 		if m.flags.monitorLive {
 			openWithDefaultBrowser(
+				m.console,
 				fmt.Sprintf("https://app.azure.com/%s%s/quickPulse", tenantId, insightsResource.Id),
 			)
 		}
 
 		if m.flags.monitorLogs {
-			openWithDefaultBrowser(fmt.Sprintf("https://app.azure.com/%s%s/logs", tenantId, insightsResource.Id))
+			openWithDefaultBrowser(m.console, fmt.Sprintf("https://app.azure.com/%s%s/logs", tenantId, insightsResource.Id))
 		}
 	}
 
 	for _, portalResource := range portalResources {
 		if m.flags.monitorOverview {
 			openWithDefaultBrowser(
+				m.console,
 				fmt.Sprintf("https://portal.azure.com/#@%s/dashboard/arm%s", tenantId, portalResource.Id),
 			)
 		}
 	}
 
+	for _, workbookResource := range workbookResources {
+		if m.flags.monitorWorkbook {
+			openWithDefaultBrowser(
+				m.console,
+				fmt.Sprintf("https://portal.azure.com/#@%s/resource%s/workbook", tenantId, workbookResource.Id),
+			)
+		}
+	}
+
 	return nil, nil
 }
 
+// serviceRoles returns the cloud role names the service --service logs with, which are the names of the resources
+// hosting the service or the name of the service. No roles are returned when streaming the logs of all services.
+func (m *monitorAction) serviceRoles(ctx context.Context, resourceGroups []azcli.AzCliResource) ([]string, error) {
+	if m.flags.service == "" {
+		return nil, nil
+	}
+
+	serviceConfig := m.projectConfig.Services[m.flags.service]
+	roles := []string{serviceConfig.Name}
+	for _, resourceGroup := range resourceGroups {
+		resources, err := m.resourceManager.GetServiceResources(
+			ctx, azure.SubscriptionFromRID(resourceGroup.Id), resourceGroup.Name, serviceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("finding the resources of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		for _, resource := range resources {
+			roles = append(roles, resource.Name)
+		}
+	}
+
+	return roles, nil
+}
+
+// loadQuery returns the KQL query specified with --query or --query-file, if any
+func (m *monitorAction) loadQuery() (string, error) {
+	if m.flags.queryFile == "" {
+		return strings.TrimSpace(m.flags.query), nil
+	}
+
+	if m.flags.query != "" {
+		return "", errors.New("--query and --query-file cannot be used together")
+	}
+
+	contents, err := os.ReadFile(m.flags.queryFile)
+	if err != nil {
+		return "", fmt.Errorf("reading query file: %w", err)
+	}
+
+	query := strings.TrimSpace(string(contents))
+	if query == "" {
+		return "", fmt.Errorf("query file '%s' is empty", m.flags.queryFile)
+	}
+
+	return query, nil
+}
+
+// runQuery runs the KQL query against the Log Analytics workspace of the environment and writes the primary table of
+// results. JSON output lists each row as an object keyed by column name.
+func (m *monitorAction) runQuery(
+	ctx context.Context,
+	workspaces []azcli.AzCliResource,
+	query string,
+	timespan string,
+) error {
+	if len(workspaces) == 0 {
+		return fmt.Errorf("application does not contain a Log Analytics workspace")
+	}
+
+	if len(workspaces) > 1 {
+		log.Printf("found %d log analytics workspaces, querying '%s'", len(workspaces), workspaces[0].Name)
+	}
+
+	result, err := m.azCli.QueryLogAnalytics(
+		ctx, m.env.GetSubscriptionId(), workspaces[0].Id, query, timespan)
+	if err != nil {
+		return err
+	}
+
+	table := azsdk.LogAnalyticsTable{}
+	if len(result.Tables) > 0 {
+		table = result.Tables[0]
+	}
+
+	if m.formatter.Kind() == output.JsonFormat {
+		rows := make([]map[string]any, len(table.Rows))
+		for i, row := range table.Rows {
+			rows[i] = make(map[string]any, len(table.Columns))
+			for j, column := range table.Columns {
+				if j < len(row) {
+					rows[i][column.Name] = row[j]
+				}
+			}
+		}
+
+		return m.formatter.Format(rows, m.writer, nil)
+	}
+
+	columns := make([]output.Column, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = output.Column{
+			Heading:       column.Name,
+			ValueTemplate: fmt.Sprintf("{{index . %d}}", i),
+		}
+	}
+
+	rows := make([][]string, len(table.Rows))
+	for i, row := range table.Rows {
+		rows[i] = make([]string, len(table.Columns))
+		for j := range table.Columns {
+			if j < len(row) {
+				rows[i][j] = formatQueryValue(row[j])
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		m.console.Message(ctx, "No results.")
+		return nil
+	}
+
+	formatter := m.formatter
+	if formatter.Kind() != output.TableFormat {
+		formatter = &output.TableFormatter{}
+	}
+
+	return formatter.Format(rows, m.writer, output.TableFormatterOptions{Columns: columns})
+}
+
+// formatQueryValue formats a value of a Log Analytics table cell, dynamic values are formatted as JSON
+func formatQueryValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any, []any:
+		if contents, err := json.Marshal(v); err == nil {
+			return string(contents)
+		}
+	}
+
+	return fmt.Sprint(value)
+}
+
+// The version of the Microsoft.Insights/workbooks API used to create workbooks
+const cWorkbookApiVersion = "2022-04-01"
+
+// createOrUpdateWorkbook creates the workbook of the environment from the workbook template of the project, replacing
+// it when it already exists. The workbook name is derived from the resource group & environment so each environment
+// has a single workbook.
+func (m *monitorAction) createOrUpdateWorkbook(
+	ctx context.Context,
+	resourceGroup azcli.AzCliResource,
+	sourceId string,
+) (azcli.AzCliResource, error) {
+	options := m.projectConfig.Monitor.Workbook
+	templatePath := options.Path
+	if !filepath.IsAbs(templatePath) {
+		templatePath = filepath.Join(m.projectConfig.Path, templatePath)
+	}
+
+	template, err := os.ReadFile(templatePath)
+	if err != nil {
+		return azcli.AzCliResource{}, fmt.Errorf("reading workbook template: %w", err)
+	}
+
+	serializedData, err := envsubst.Eval(string(template), m.env.Getenv)
+	if err != nil {
+		return azcli.AzCliResource{}, fmt.Errorf("evaluating workbook template: %w", err)
+	}
+
+	if !json.Valid([]byte(serializedData)) {
+		return azcli.AzCliResource{}, fmt.Errorf("workbook template '%s' is not valid JSON", options.Path)
+	}
+
+	displayName, err := options.DisplayName.Envsubst(m.env.Getenv)
+	if err != nil {
+		return azcli.AzCliResource{}, fmt.Errorf("evaluating workbook display name: %w", err)
+	}
+
+	if displayName == "" {
+		displayName = fmt.Sprintf("%s (%s)", m.projectConfig.Name, m.env.GetEnvName())
+	}
+
+	workbookName := uuid.NewSHA1(uuid.NameSpaceURL, []byte(resourceGroup.Id+"/"+m.env.GetEnvName())).String()
+	workbookId := fmt.Sprintf("%s/providers/%s/%s", resourceGroup.Id, infra.AzureResourceTypeWorkbook, workbookName)
+
+	m.console.ShowSpinner(ctx, "Updating workbook", input.Step)
+	workbook, err := m.azCli.CreateOrUpdateResource(
+		ctx,
+		m.env.GetSubscriptionId(),
+		workbookId,
+		cWorkbookApiVersion,
+		armresources.GenericResource{
+			Location: &resourceGroup.Location,
+			Kind:     convert.RefOf("shared"),
+			Tags: map[string]*string{
+				azure.TagKeyAzdEnvName: convert.RefOf(m.env.GetEnvName()),
+				"hidden-title":         &displayName,
+			},
+			Properties: map[string]any{
+				"displayName":    displayName,
+				"category":       "workbook",
+				"sourceId":       sourceId,
+				"serializedData": serializedData,
+			},
+		},
+	)
+	m.console.StopSpinner(ctx, "Updating workbook", input.GetStepResultFormat(err))
+	if err != nil {
+		return azcli.AzCliResource{}, fmt.Errorf("updating workbook: %w", err)
+	}
+
+	return workbook, nil
+}
+
 func getCmdMonitorHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf("Monitor a deployed application %s. For more information, go to: %s.",
@@ -198,6 +577,51 @@ func getCmdMonitorHelpFooter(c *cobra.Command) string {
 	return generateCmdHelpSamplesBlock(map[string]string{
 		"Open Application Insights Overview Dashboard.": output.WithHighLightFormat("azd monitor --overview"),
 		"Open Application Insights Live Metrics.":       output.WithHighLightFormat("azd monitor --live"),
-		"Open Application Insights Logs.":               output.WithHighLightFormat("azd monitor --logs"),
+		"Poll the near-real-time platform metrics in the terminal.": output.WithHighLightFormat(
+			"azd monitor --platform-metrics",
+		),
+		"Open Application Insights Logs.": output.WithHighLightFormat("azd monitor --logs --browser"),
+		"Stream the warnings and errors of the api service in the terminal.": output.WithHighLightFormat(
+			"azd monitor --logs --service api --severity warning",
+		),
+		"Open the Azure Monitor workbook of the environment.": output.WithHighLightFormat(
+			"azd monitor --workbook",
+		),
+		"Show the 10 most recent failed requests of the last hour.": output.WithHighLightFormat(
+			"azd monitor --query \"requests | where success == false | take 10\" --timespan PT1H",
+		),
+		"Run a saved query and output the results as JSON.": output.WithHighLightFormat(
+			"azd monitor --query-file ./queries/errors.kql --output json",
+		),
+		"Show the application failures following the deployment with the trace id.": output.WithHighLightFormat(
+			"azd monitor --trace <trace-id>",
+		),
 	})
 }
+
+// openWithDefaultBrowser opens the url in the browser configured by $BROWSER or in the default browser
+func openWithDefaultBrowser(console input.Console, url string) {
+	fmt.Fprintf(console.Handles().Stdout, "Opening %s in the default browser...\n", url)
+
+	// In Codespaces and devcontainers a $BROWSER environment variable is
+	// present whose value is an executable that launches the browser when
+	// called with the form:
+	// $BROWSER <url>
+
+	const BrowserEnvVarName = "BROWSER"
+
+	if envBrowser := os.Getenv(BrowserEnvVarName); len(envBrowser) > 0 {
+		if err := exec.Command(envBrowser, url).Run(); err != nil {
+			fmt.Fprintf(
+				console.Handles().Stderr,
+				"warning: failed to open browser configured by $BROWSER: %s\n",
+				err.Error(),
+			)
+		}
+		return
+	}
+
+	if err := browser.OpenURL(url); err != nil {
+		fmt.Fprintf(console.Handles().Stderr, "warning: failed to open default browser: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/resources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
+)
+
+// The resource types of Azure Monitor alert rules
+var alertRuleResourceTypes = map[string]string{
+	"microsoft.insights/metricalerts":                    "Metric alert",
+	"microsoft.insights/scheduledqueryrules":             "Log search alert",
+	"microsoft.insights/activitylogalerts":               "Activity log alert",
+	"microsoft.alertsmanagement/smartdetectoralertrules": "Smart detector alert",
+}
+
+// The action group module scaffolded alongside the alert rules
+const alertActionGroupTemplate = "action-group"
+
+func monitorAlertsActions(monitor *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := monitor.Add("alerts", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "alerts",
+			Short: "Manage the alert rules of the environment.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdMonitorAlertsHelpDescription,
+		},
+	})
+
+	group.Add("list", &actions.ActionDescriptorOptions{
+		Command:         newMonitorAlertsListCmd(),
+		FlagsResolver:   newMonitorAlertsListFlags,
+		ActionResolver:  newMonitorAlertsListAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:   output.TableFormat,
+	})
+
+	group.Add("add", &actions.ActionDescriptorOptions{
+		Command:        newMonitorAlertsAddCmd(),
+		FlagsResolver:  newMonitorAlertsAddFlags,
+		ActionResolver: newMonitorAlertsAddAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdMonitorAlertsAddHelpFooter,
+		},
+	})
+
+	return group
+}
+
+type monitorAlertsListFlags struct {
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *monitorAlertsListFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newMonitorAlertsListFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *monitorAlertsListFlags {
+	flags := &monitorAlertsListFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newMonitorAlertsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the alert rules of the environment.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+type monitorAlertsListAction struct {
+	env       *environment.Environment
+	azCli     azcli.AzCli
+	console   input.Console
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newMonitorAlertsListAction(
+	env *environment.Environment,
+	azCli azcli.AzCli,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	_ *monitorAlertsListFlags,
+) actions.Action {
+	return &monitorAlertsListAction{
+		env:       env,
+		azCli:     azCli,
+		console:   console,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *monitorAlertsListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	resourceManager := infra.NewAzureResourceManager(a.azCli)
+	resourceGroups, err := resourceManager.GetResourceGroupsForEnvironment(
+		ctx, a.env.GetSubscriptionId(), a.env.GetEnvName())
+	if err != nil {
+		return nil, fmt.Errorf("discovering resource groups from deployment: %w", err)
+	}
+
+	rules := []contracts.AlertRule{}
+	for _, resourceGroup := range resourceGroups {
+		resources, err := a.azCli.ListResourceGroupResources(
+			ctx, azure.SubscriptionFromRID(resourceGroup.Id), resourceGroup.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing resources: %w", err)
+		}
+
+		for _, resource := range resources {
+			if kind, has := alertRuleResourceTypes[strings.ToLower(resource.Type)]; has {
+				rules = append(rules, contracts.AlertRule{
+					Id:            resource.Id,
+					Name:          resource.Name,
+					Type:          kind,
+					ResourceGroup: resourceGroup.Name,
+					Location:      resource.Location,
+				})
+			}
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+
+	if a.formatter.Kind() == output.TableFormat {
+		if len(rules) == 0 {
+			a.console.Message(ctx, fmt.Sprintf(
+				"No alert rules found. Scaffold common alert rules with %s.",
+				output.WithHighLightFormat("azd monitor alerts add")))
+			return nil, nil
+		}
+
+		return nil, a.formatter.Format(rules, a.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "NAME", ValueTemplate: "{{.Name}}"},
+				{Heading: "TYPE", ValueTemplate: "{{.Type}}"},
+				{Heading: "RESOURCE GROUP", ValueTemplate: "{{.ResourceGroup}}"},
+			},
+		})
+	}
+
+	return nil, a.formatter.Format(rules, a.writer, nil)
+}
+
+type monitorAlertsAddFlags struct {
+	force  bool
+	global *internal.GlobalCommandOptions
+}
+
+func (f *monitorAlertsAddFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.force, "force", false, "Overwrites alert rule modules that already exist.")
+	f.global = global
+}
+
+func newMonitorAlertsAddFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *monitorAlertsAddFlags {
+	flags := &monitorAlertsAddFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newMonitorAlertsAddCmd() *cobra.Command {
+	rules := alertRuleTemplates()
+
+	return &cobra.Command{
+		Use: "add <rule>...",
+		Short: fmt.Sprintf(
+			"Scaffold bicep modules for common alert rules (%s).", strings.Join(rules, ", ")),
+		Args:      cobra.MinimumNArgs(1),
+		ValidArgs: rules,
+	}
+}
+
+type monitorAlertsAddAction struct {
+	projectConfig *project.ProjectConfig
+	console       input.Console
+	flags         *monitorAlertsAddFlags
+	args          []string
+}
+
+func newMonitorAlertsAddAction(
+	projectConfig *project.ProjectConfig,
+	console input.Console,
+	flags *monitorAlertsAddFlags,
+	args []string,
+) actions.Action {
+	return &monitorAlertsAddAction{
+		projectConfig: projectConfig,
+		console:       console,
+		flags:         flags,
+		args:          args,
+	}
+}
+
+func (a *monitorAlertsAddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	available := alertRuleTemplates()
+	for _, rule := range a.args {
+		if rule == alertActionGroupTemplate || !slices.Contains(available, rule) {
+			return nil, fmt.Errorf(
+				"unknown alert rule '%s', supported alert rules are: %s", rule, strings.Join(available, ", "))
+		}
+	}
+
+	infraPath := a.projectConfig.Infra.Path
+	if !filepath.IsAbs(infraPath) {
+		infraPath = filepath.Join(a.projectConfig.Path, infraPath)
+	}
+
+	alertsPath := filepath.Join(infraPath, "alerts")
+	if err := os.MkdirAll(alertsPath, osutil.PermissionDirectory); err != nil {
+		return nil, fmt.Errorf("creating alerts directory: %w", err)
+	}
+
+	written := []string{}
+	for _, template := range append([]string{alertActionGroupTemplate}, a.args...) {
+		target := filepath.Join(alertsPath, template+".bicep")
+		if _, err := os.Stat(target); err == nil && !a.flags.force {
+			// The action group is shared by every alert rule and only scaffolded once
+			if template != alertActionGroupTemplate {
+				a.console.Message(ctx, fmt.Sprintf(
+					"%s already exists, use --force to overwrite it", output.WithHighLightFormat(target)))
+			}
+			continue
+		}
+
+		contents, err := resources.AlertTemplates.ReadFile(path.Join("alerts", template+".bicep"))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(target, contents, osutil.PermissionFile); err != nil {
+			return nil, fmt.Errorf("writing alert rule module: %w", err)
+		}
+
+		written = append(written, target)
+	}
+
+	if len(written) == 0 {
+		return nil, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Added %d alert rule modules to %s", len(written), alertsPath),
+			FollowUp: "Reference the modules from your main bicep file, for example:\n\n" +
+				alertModulesSample(a.args) +
+				"\nThen run " + output.WithHighLightFormat("azd provision") + " to create the alert rules.",
+		},
+	}, nil
+}
+
+// alertRuleTemplates returns the names of the alert rules that can be scaffolded
+func alertRuleTemplates() []string {
+	entries, err := fs.ReadDir(resources.AlertTemplates, "alerts")
+	if err != nil {
+		panic(fmt.Sprintf("reading alert templates: %v", err))
+	}
+
+	rules := []string{}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".bicep")
+		if name != alertActionGroupTemplate {
+			rules = append(rules, name)
+		}
+	}
+
+	return rules
+}
+
+// alertModulesSample returns the bicep declaring the action group & the alert rule modules
+func alertModulesSample(rules []string) string {
+	sample := strings.Builder{}
+	sample.WriteString(`module actionGroup 'alerts/action-group.bicep' = {
+  name: 'action-group'
+  scope: rg
+  params: {
+    environmentName: environmentName
+    emailAddress: alertEmailAddress
+  }
+}
+`)
+
+	for _, rule := range rules {
+		params := "    applicationInsightsId: monitoring.outputs.applicationInsightsId\n"
+		if rule == "cpu" {
+			params = "    targetResourceId: appServicePlan.outputs.id\n"
+		}
+
+		fmt.Fprintf(&sample, `
+module %sAlert 'alerts/%s.bicep' = {
+  name: '%s-alert'
+  scope: rg
+  params: {
+    environmentName: environmentName
+    actionGroupId: actionGroup.outputs.id
+%s  }
+}
+`, strings.ReplaceAll(rule, "-", ""), rule, rule, params)
+	}
+
+	return sample.String()
+}
+
+func getCmdMonitorAlertsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"List the alert rules of the environment and scaffold bicep modules for common alert rules.",
+		[]string{
+			formatHelpNote(
+				"Scaffolded alert rules notify an action group created for each environment. " +
+					"Provision the environment to create the alert rules."),
+		})
+}
+
+func getCmdMonitorAlertsAddHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Scaffold availability & 5xx rate alert rules.": output.WithHighLightFormat(
+			"azd monitor alerts add availability http-5xx",
+		),
+		"Scaffold a CPU alert rule, replacing an existing module.": output.WithHighLightFormat(
+			"azd monitor alerts add cpu --force",
+		),
+	})
+}
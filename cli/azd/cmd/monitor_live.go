@@ -0,0 +1,179 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// How often the live metrics are refreshed
+const liveMetricsRefreshInterval = 10 * time.Second
+
+// The failure rate & CPU usage above which the application is reported as degraded
+const (
+	liveMetricsFailureRateThreshold = 5.0
+	liveMetricsCpuThreshold         = 90.0
+)
+
+// The Application Insights platform metrics rendered by `azd monitor --platform-metrics`
+const (
+	liveMetricRequests = "requests/count"
+	liveMetricFailed   = "requests/failed"
+	liveMetricDuration = "requests/duration"
+	liveMetricCpu      = "performanceCounters/processCpuPercentage"
+)
+
+// liveMetricsSample is the most recent minute of the Application Insights metrics of the application
+type liveMetricsSample struct {
+	Timestamp time.Time
+	// Requests per second
+	RequestRate float64
+	// The percentage of failed requests
+	FailureRate float64
+	// The average duration of requests in milliseconds
+	Duration float64
+	// The average CPU usage of the servers, nil when not reported
+	Cpu *float64
+	// The number of requests of each minute of the window, oldest first
+	Requests []*float64
+}
+
+// Health returns the health of the servers computed from the failure rate & CPU usage of the sample
+func (s *liveMetricsSample) Health() string {
+	if s.FailureRate > liveMetricsFailureRateThreshold ||
+		(s.Cpu != nil && *s.Cpu > liveMetricsCpuThreshold) {
+		return "Degraded"
+	}
+
+	return "Healthy"
+}
+
+// streamLiveMetrics polls the near-real-time platform metrics of the application and renders its request rate, failure
+// rate & server health in the terminal until the command is cancelled. Unlike Live Metrics in the browser, platform
+// metrics are reported with a delay of about a minute.
+func (m *monitorAction) streamLiveMetrics(ctx context.Context, insights azcli.AzCliResource) error {
+	m.console.Message(ctx, fmt.Sprintf(
+		"Polling the near-real-time platform metrics of %s, press Ctrl+C to stop.\n",
+		output.WithHighLightFormat(insights.Name)))
+
+	ticker := time.NewTicker(liveMetricsRefreshInterval)
+	defer ticker.Stop()
+
+	var last time.Time
+	for {
+		end := time.Now().UTC().Truncate(time.Minute)
+		result, err := m.azCli.GetMetrics(ctx, m.env.GetSubscriptionId(), insights.Id, azsdk.MetricsQuery{
+			Names:        []string{liveMetricRequests, liveMetricFailed, liveMetricDuration, liveMetricCpu},
+			Timespan:     fmt.Sprintf("%s/%s", end.Add(-15*time.Minute).Format(time.RFC3339), end.Format(time.RFC3339)),
+			Interval:     "PT1M",
+			Aggregations: []string{"Count", "Average"},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if sample, has := newLiveMetricsSample(result); !has {
+			if last.IsZero() {
+				m.console.Message(ctx, output.WithGrayFormat("Waiting for telemetry..."))
+				last = end
+			}
+		} else if sample.Timestamp.After(last) {
+			m.console.Message(ctx, formatLiveMetricsSample(sample))
+			last = sample.Timestamp
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// newLiveMetricsSample returns the most recent minute with requests of the metrics
+func newLiveMetricsSample(result *azsdk.MetricsResult) (*liveMetricsSample, bool) {
+	series := map[string][]azsdk.MetricValue{}
+	for _, metric := range result.Value {
+		for _, timeseries := range metric.Timeseries {
+			series[metric.Name.Value] = append(series[metric.Name.Value], timeseries.Data...)
+		}
+	}
+
+	requests := series[liveMetricRequests]
+	sample := &liveMetricsSample{
+		Requests: make([]*float64, len(requests)),
+	}
+
+	latest := -1
+	for i, data := range requests {
+		sample.Requests[i] = data.Count
+		if data.Count != nil && *data.Count > 0 {
+			latest = i
+		}
+	}
+
+	if latest < 0 {
+		return nil, false
+	}
+
+	count := *requests[latest].Count
+	sample.Timestamp = requests[latest].TimeStamp
+	sample.RequestRate = count / 60
+
+	if value := liveMetricAt(series[liveMetricFailed], sample.Timestamp); value != nil && value.Count != nil {
+		sample.FailureRate = *value.Count / count * 100
+	}
+
+	if value := liveMetricAt(series[liveMetricDuration], sample.Timestamp); value != nil && value.Average != nil {
+		sample.Duration = *value.Average
+	}
+
+	if value := liveMetricAt(series[liveMetricCpu], sample.Timestamp); value != nil {
+		sample.Cpu = value.Average
+	}
+
+	return sample, true
+}
+
+func liveMetricAt(data []azsdk.MetricValue, timestamp time.Time) *azsdk.MetricValue {
+	for i := range data {
+		if data[i].TimeStamp.Equal(timestamp) {
+			return &data[i]
+		}
+	}
+
+	return nil
+}
+
+func formatLiveMetricsSample(sample *liveMetricsSample) string {
+	health := output.WithSuccessFormat(sample.Health())
+	if sample.Health() != "Healthy" {
+		health = output.WithErrorFormat(sample.Health())
+	}
+
+	cpu := "-"
+	if sample.Cpu != nil {
+		cpu = fmt.Sprintf("%.0f%%", *sample.Cpu)
+	}
+
+	return strings.Join([]string{
+		output.WithGrayFormat(sample.Timestamp.Local().Format("15:04")),
+		fmt.Sprintf("requests %6.2f/s %s", sample.RequestRate, sparkline(sample.Requests)),
+		fmt.Sprintf("failures %5.1f%%", sample.FailureRate),
+		fmt.Sprintf("latency %5.0f ms", sample.Duration),
+		fmt.Sprintf("cpu %4s", cpu),
+		health,
+	}, "  ")
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newLiveMetricsSample(t *testing.T) {
+	start := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	minute := func(i int) time.Time { return start.Add(time.Duration(i) * time.Minute) }
+
+	metric := func(name string, values ...azsdk.MetricValue) azsdk.Metric {
+		return azsdk.Metric{
+			Name:       azsdk.MetricName{Value: name},
+			Timeseries: []azsdk.MetricTimeseries{{Data: values}},
+		}
+	}
+
+	t.Run("NoRequests", func(t *testing.T) {
+		_, has := newLiveMetricsSample(&azsdk.MetricsResult{
+			Value: []azsdk.Metric{
+				metric(liveMetricRequests, azsdk.MetricValue{TimeStamp: minute(0)}),
+			},
+		})
+		require.False(t, has)
+	})
+
+	t.Run("LatestMinuteWithRequests", func(t *testing.T) {
+		sample, has := newLiveMetricsSample(&azsdk.MetricsResult{
+			Value: []azsdk.Metric{
+				metric(liveMetricRequests,
+					azsdk.MetricValue{TimeStamp: minute(0), Count: convert.RefOf(60.0)},
+					azsdk.MetricValue{TimeStamp: minute(1), Count: convert.RefOf(120.0)},
+					// Telemetry of the current minute hasn't been ingested yet
+					azsdk.MetricValue{TimeStamp: minute(2)},
+				),
+				metric(liveMetricFailed,
+					azsdk.MetricValue{TimeStamp: minute(1), Count: convert.RefOf(12.0)},
+				),
+				metric(liveMetricDuration,
+					azsdk.MetricValue{TimeStamp: minute(1), Average: convert.RefOf(250.0)},
+				),
+				metric(liveMetricCpu,
+					azsdk.MetricValue{TimeStamp: minute(1), Average: convert.RefOf(40.0)},
+				),
+			},
+		})
+		require.True(t, has)
+
+		require.Equal(t, minute(1), sample.Timestamp)
+		require.Equal(t, 2.0, sample.RequestRate)
+		require.Equal(t, 10.0, sample.FailureRate)
+		require.Equal(t, 250.0, sample.Duration)
+		require.Equal(t, 40.0, *sample.Cpu)
+		require.Len(t, sample.Requests, 3)
+		require.Equal(t, "Degraded", sample.Health())
+	})
+}
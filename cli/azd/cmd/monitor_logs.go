@@ -0,0 +1,222 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// How often new logs are queried
+const logsRefreshInterval = 5 * time.Second
+
+// How far back logs are shown when streaming starts
+const logsBacklog = 10 * time.Minute
+
+// The maximum number of logs returned by each query
+const logsBatchSize = 500
+
+// The Application Insights severity levels, in increasing order of severity
+var logSeverities = []string{"verbose", "information", "warning", "error", "critical"}
+
+// logEntry is a trace or an exception of the application, as logged in the Log Analytics workspace
+type logEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	// AppTraces or AppExceptions
+	Type string `json:"type"`
+	// The cloud role name of the application, ex. the name of the app service hosting the service
+	Role        string `json:"role"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	OperationId string `json:"operationId,omitempty"`
+
+	// When the entry was ingested, entries are streamed in the order they are ingested
+	ingested time.Time
+	id       string
+}
+
+// parseLogSeverity returns the Application Insights severity level of the severity, ex. 2 for warning
+func parseLogSeverity(severity string) (int, error) {
+	for level, name := range logSeverities {
+		if strings.EqualFold(severity, name) {
+			return level, nil
+		}
+	}
+
+	return 0, fmt.Errorf(
+		"'%s' is not a valid severity, the severity must be one of: %s", severity, strings.Join(logSeverities, ", "))
+}
+
+// newLogsQuery returns the KQL query of the traces & exceptions ingested since the cursor, with at least the severity
+// level and logged by one of the roles, when any.
+func newLogsQuery(since time.Time, severity int, roles []string) string {
+	filters := []string{
+		fmt.Sprintf("| where Ingested >= datetime(%s)", since.UTC().Format(time.RFC3339Nano)),
+		// Exceptions are logged without a severity level by some SDKs
+		"| extend SeverityLevel = iff(isnull(SeverityLevel) and Type == 'AppExceptions', 3, SeverityLevel)",
+	}
+
+	if severity > 0 {
+		filters = append(filters, fmt.Sprintf("| where SeverityLevel >= %d", severity))
+	}
+
+	if len(roles) > 0 {
+		quoted := make([]string, len(roles))
+		for i, role := range roles {
+			quoted[i] = fmt.Sprintf("'%s'", strings.ReplaceAll(role, "'", "\\'"))
+		}
+
+		filters = append(filters, fmt.Sprintf("| where AppRoleName in~ (%s)", strings.Join(quoted, ", ")))
+	}
+
+	return strings.Join(append([]string{
+		"union isfuzzy=true AppTraces, AppExceptions",
+		"| extend Ingested = ingestion_time()",
+	}, append(filters,
+		"| extend Message = coalesce(Message, OuterMessage, ProblemId, '')",
+		"| project TimeGenerated, Ingested, Type, AppRoleName, SeverityLevel, Message, OperationId, _ItemId",
+		"| order by Ingested asc",
+		fmt.Sprintf("| take %d", logsBatchSize),
+	)...), "\n")
+}
+
+// newLogEntries parses the log entries of the result of a logs query
+func newLogEntries(result *azsdk.LogAnalyticsQueryResult) []logEntry {
+	if len(result.Tables) == 0 {
+		return []logEntry{}
+	}
+
+	table := result.Tables[0]
+	columns := map[string]int{}
+	for i, column := range table.Columns {
+		columns[column.Name] = i
+	}
+
+	value := func(row []any, column string) any {
+		if i, has := columns[column]; has && i < len(row) {
+			return row[i]
+		}
+
+		return nil
+	}
+
+	entries := make([]logEntry, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		entry := logEntry{
+			Type:        formatQueryValue(value(row, "Type")),
+			Role:        formatQueryValue(value(row, "AppRoleName")),
+			Message:     formatQueryValue(value(row, "Message")),
+			OperationId: formatQueryValue(value(row, "OperationId")),
+			id:          formatQueryValue(value(row, "_ItemId")),
+		}
+
+		entry.Timestamp, _ = time.Parse(time.RFC3339Nano, formatQueryValue(value(row, "TimeGenerated")))
+		entry.ingested, _ = time.Parse(time.RFC3339Nano, formatQueryValue(value(row, "Ingested")))
+
+		if level, ok := value(row, "SeverityLevel").(float64); ok && int(level) >= 0 && int(level) < len(logSeverities) {
+			entry.Severity = logSeverities[int(level)]
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// formatLogEntry formats the entry as a line of the terminal, colored by severity
+func formatLogEntry(entry logEntry) string {
+	severity := fmt.Sprintf("%-11s", entry.Severity)
+	switch entry.Severity {
+	case "error", "critical":
+		severity = output.WithErrorFormat(severity)
+	case "warning":
+		severity = output.WithWarningFormat(severity)
+	default:
+		severity = output.WithGrayFormat(severity)
+	}
+
+	return strings.Join([]string{
+		output.WithGrayFormat(entry.Timestamp.Local().Format("15:04:05")),
+		severity,
+		output.WithHighLightFormat(entry.Role),
+		strings.TrimSpace(entry.Message),
+	}, "  ")
+}
+
+// streamLogs writes the traces & exceptions of the application logged in the Log Analytics workspace until the command
+// is cancelled, starting with the logs of the last minutes. Logs are ingested with a delay of a few minutes.
+func (m *monitorAction) streamLogs(
+	ctx context.Context,
+	workspaces []azcli.AzCliResource,
+	severity int,
+	roles []string,
+) error {
+	if len(workspaces) == 0 {
+		return fmt.Errorf("application does not contain a Log Analytics workspace")
+	}
+
+	if len(workspaces) > 1 {
+		log.Printf("found %d log analytics workspaces, streaming '%s'", len(workspaces), workspaces[0].Name)
+	}
+
+	jsonOutput := m.formatter.Kind() == output.JsonFormat
+	if !jsonOutput {
+		m.console.Message(ctx, fmt.Sprintf(
+			"Streaming logs of %s, press Ctrl+C to stop.\n", output.WithHighLightFormat(workspaces[0].Name)))
+	}
+
+	ticker := time.NewTicker(logsRefreshInterval)
+	defer ticker.Stop()
+
+	// Entries ingested at the cursor are queried again, the ids of those already written are skipped
+	cursor := time.Now().Add(-logsBacklog)
+	written := map[string]struct{}{}
+	encoder := json.NewEncoder(m.writer)
+
+	for {
+		result, err := m.azCli.QueryLogAnalytics(
+			ctx, m.env.GetSubscriptionId(), workspaces[0].Id, newLogsQuery(cursor, severity, roles), "PT1H")
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		for _, entry := range newLogEntries(result) {
+			if _, has := written[entry.id]; has {
+				continue
+			}
+
+			if entry.ingested.After(cursor) {
+				cursor = entry.ingested
+				written = map[string]struct{}{}
+			}
+			written[entry.id] = struct{}{}
+
+			if jsonOutput {
+				if err := encoder.Encode(entry); err != nil {
+					return err
+				}
+			} else {
+				m.console.Message(ctx, formatLogEntry(entry))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
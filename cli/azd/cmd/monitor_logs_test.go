@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseLogSeverity(t *testing.T) {
+	level, err := parseLogSeverity("Warning")
+	require.NoError(t, err)
+	require.Equal(t, 2, level)
+
+	level, err = parseLogSeverity("verbose")
+	require.NoError(t, err)
+	require.Equal(t, 0, level)
+
+	_, err = parseLogSeverity("fatal")
+	require.Error(t, err)
+}
+
+func Test_newLogsQuery(t *testing.T) {
+	since := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("AllLogs", func(t *testing.T) {
+		query := newLogsQuery(since, 0, nil)
+		require.Contains(t, query, "union isfuzzy=true AppTraces, AppExceptions")
+		require.Contains(t, query, "| where Ingested >= datetime(2023-10-01T12:00:00Z)")
+		require.NotContains(t, query, "SeverityLevel >=")
+		require.NotContains(t, query, "AppRoleName in~")
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		query := newLogsQuery(since, 3, []string{"api", "app-api-o'brien"})
+		require.Contains(t, query, "| where SeverityLevel >= 3")
+		require.Contains(t, query, "| where AppRoleName in~ ('api', 'app-api-o\\'brien')")
+	})
+}
+
+func Test_newLogEntries(t *testing.T) {
+	entries := newLogEntries(&azsdk.LogAnalyticsQueryResult{
+		Tables: []azsdk.LogAnalyticsTable{
+			{
+				Columns: []azsdk.LogAnalyticsColumn{
+					{Name: "TimeGenerated"},
+					{Name: "Ingested"},
+					{Name: "Type"},
+					{Name: "AppRoleName"},
+					{Name: "SeverityLevel"},
+					{Name: "Message"},
+					{Name: "OperationId"},
+					{Name: "_ItemId"},
+				},
+				Rows: [][]any{
+					{
+						"2023-10-01T12:00:00Z", "2023-10-01T12:02:00Z", "AppTraces", "app-api", 1.0, "started", "op1", "1",
+					},
+					{
+						"2023-10-01T12:01:00Z", "2023-10-01T12:03:00Z", "AppExceptions", "app-api", 3.0, "failed", nil, "2",
+					},
+				},
+			},
+		},
+	})
+
+	require.Len(t, entries, 2)
+	require.Equal(t, logEntry{
+		Timestamp:   time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC),
+		Type:        "AppTraces",
+		Role:        "app-api",
+		Severity:    "information",
+		Message:     "started",
+		OperationId: "op1",
+		ingested:    time.Date(2023, 10, 1, 12, 2, 0, 0, time.UTC),
+		id:          "1",
+	}, entries[0])
+	require.Equal(t, "error", entries[1].Severity)
+	require.Equal(t, "", entries[1].OperationId)
+
+	require.Empty(t, newLogEntries(&azsdk.LogAnalyticsQueryResult{}))
+}
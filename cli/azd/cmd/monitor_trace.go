@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// Trace ids are W3C trace ids or GUIDs, restricting them also keeps them safe to embed in KQL queries
+var traceIdRegex = regexp.MustCompile(`^[0-9a-fA-F-]{16,36}$`)
+
+// How far back deployment markers are searched
+const traceLookback = 30 * 24 * time.Hour
+
+// The time window following a deployment in which application failures are reported
+const traceDeploymentWindow = time.Hour
+
+// showTrace shows the application telemetry related to the trace id. When the trace id is the id of an azd deployment,
+// the failed requests & exceptions of the application following the deployment are shown along with any telemetry
+// of the operation.
+func (m *monitorAction) showTrace(ctx context.Context, workspaces []azcli.AzCliResource) error {
+	release, err := m.releaseAnnotator.FindByTraceId(
+		ctx, m.env.GetSubscriptionId(), m.env.GetEnvName(), m.flags.trace, time.Now().Add(-traceLookback))
+	if err != nil {
+		return fmt.Errorf("looking up deployment markers: %w", err)
+	}
+
+	filter := fmt.Sprintf("OperationId == '%s'", m.flags.trace)
+	if release != nil {
+		m.console.Message(ctx, fmt.Sprintf(
+			"Deployment of %s to %s at %s, showing the application failures of the following %s.\n",
+			output.WithHighLightFormat(strings.Join(release.Services, ", ")),
+			output.WithHighLightFormat(release.EnvName),
+			release.Time.Local().Format(time.RFC1123),
+			traceDeploymentWindow,
+		))
+
+		filter = fmt.Sprintf(
+			"%s or (TimeGenerated between (datetime(%s) .. datetime(%s)) and "+
+				"(Type == 'AppExceptions' or (Type == 'AppRequests' and Success == false)))",
+			filter,
+			release.Time.UTC().Format(time.RFC3339),
+			release.Time.Add(traceDeploymentWindow).UTC().Format(time.RFC3339),
+		)
+	}
+
+	query := strings.Join([]string{
+		"union isfuzzy=true AppRequests, AppDependencies, AppExceptions, AppTraces",
+		"| where " + filter,
+		"| extend Name = coalesce(Name, ProblemId, ''), Details = coalesce(OuterMessage, Message, ResultCode, '')",
+		"| project TimeGenerated, Type, Name, Details, OperationId",
+		"| order by TimeGenerated asc",
+		"| take 200",
+	}, "\n")
+
+	// The time range is part of the query
+	return m.runQuery(ctx, workspaces, query, "")
+}
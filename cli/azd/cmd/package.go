@@ -4,10 +4,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -15,11 +23,15 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type packageFlags struct {
-	all    bool
-	global *internal.GlobalCommandOptions
+	all        bool
+	forceBuild bool
+	noCache    bool
+	inspect    bool
+	global     *internal.GlobalCommandOptions
 	*envFlag
 }
 
@@ -43,6 +55,30 @@ func (pf *packageFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComman
 		false,
 		"Deploys all services that are listed in "+azdcontext.ProjectFileName,
 	)
+	local.BoolVar(
+		&pf.forceBuild,
+		"force-build",
+		false,
+		"Packages services even when their source hasn't changed since they were last packaged.",
+	)
+	local.BoolVar(
+		&pf.noCache,
+		"no-cache",
+		false,
+		"Packages services without reusing or recording their packages in the package cache.",
+	)
+	local.BoolVar(
+		&pf.inspect,
+		"inspect",
+		false,
+		"Inspects the packages after packaging, reporting their contents, embedded secrets and size limit violations.",
+	)
+	local.Int(
+		middleware.ParallelismFlagName,
+		0,
+		"The maximum number of services packaged at the same time, 1 packages services sequentially"+
+			" (default: the services.parallelism config, or 4).",
+	)
 }
 
 func newPackageCmd() *cobra.Command {
@@ -58,14 +94,18 @@ func newPackageCmd() *cobra.Command {
 }
 
 type packageAction struct {
-	flags          *packageFlags
-	args           []string
-	projectConfig  *project.ProjectConfig
-	projectManager project.ProjectManager
-	serviceManager project.ServiceManager
-	console        input.Console
-	formatter      output.Formatter
-	writer         io.Writer
+	flags             *packageFlags
+	args              []string
+	projectConfig     *project.ProjectConfig
+	projectManager    project.ProjectManager
+	serviceManager    project.ServiceManager
+	packageCache      *project.PackageCache
+	packageInspector  *project.PackageInspector
+	console           input.Console
+	formatter         output.Formatter
+	writer            io.Writer
+	userConfigManager config.UserConfigManager
+	lifecycleEvents   *input.LifecycleEvents
 }
 
 func newPackageAction(
@@ -74,25 +114,35 @@ func newPackageAction(
 	projectConfig *project.ProjectConfig,
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
+	packageCache *project.PackageCache,
+	packageInspector *project.PackageInspector,
 	console input.Console,
 	formatter output.Formatter,
 	writer io.Writer,
+	userConfigManager config.UserConfigManager,
+	lifecycleEvents *input.LifecycleEvents,
 ) actions.Action {
 	return &packageAction{
-		flags:          flags,
-		args:           args,
-		projectConfig:  projectConfig,
-		projectManager: projectManager,
-		serviceManager: serviceManager,
-		console:        console,
-		formatter:      formatter,
-		writer:         writer,
+		flags:             flags,
+		args:              args,
+		projectConfig:     projectConfig,
+		projectManager:    projectManager,
+		serviceManager:    serviceManager,
+		packageCache:      packageCache,
+		packageInspector:  packageInspector,
+		console:           console,
+		formatter:         formatter,
+		writer:            writer,
+		userConfigManager: userConfigManager,
+		lifecycleEvents:   lifecycleEvents,
 	}
 }
 
 type PackageResult struct {
 	Timestamp time.Time                                `json:"timestamp"`
 	Services  map[string]*project.ServicePackageResult `json:"services"`
+	// The inspections of the packages, set when the packages are inspected
+	Inspections map[string]*project.PackageInspection `json:"inspections,omitempty"`
 }
 
 func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error) {
@@ -120,55 +170,91 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
-	if err := pa.projectManager.Initialize(ctx, pa.projectConfig); err != nil {
-		return nil, err
-	}
-
 	if err := pa.projectManager.EnsureAllTools(ctx, pa.projectConfig, func(svc *project.ServiceConfig) bool {
 		return targetServiceName == "" || svc.Name == targetServiceName
 	}); err != nil {
 		return nil, err
 	}
 
-	packageResults := map[string]*project.ServicePackageResult{}
+	parallelism, err := serviceParallelism(ctx, pa.userConfigManager)
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetUsageAttributes(fields.ProjectServiceParallelismKey.Int(parallelism))
 
+	targetServices := []*project.ServiceConfig{}
 	for _, svc := range pa.projectConfig.GetServicesStable() {
-		stepMessage := fmt.Sprintf("Packaging service %s", svc.Name)
-		pa.console.ShowSpinner(ctx, stepMessage, input.Step)
-
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
 		// 2. This service is not the one the user specified
 		if targetServiceName != "" && targetServiceName != svc.Name {
+			stepMessage := fmt.Sprintf("Packaging service %s", svc.Name)
+			pa.console.ShowSpinner(ctx, stepMessage, input.Step)
 			pa.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
 			continue
 		}
 
-		packageTask := pa.serviceManager.Package(ctx, svc, nil)
-		go func() {
-			for packageProgress := range packageTask.Progress() {
-				progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
-				pa.console.ShowSpinner(ctx, progressMessage, input.Step)
+		targetServices = append(targetServices, svc)
+	}
+
+	parallelism = hooksParallelism(targetServices, parallelism)
+
+	// Services are independent of each other and are packaged concurrently
+	progress := newServiceProgress(pa.console, "Packaging").withEvents(pa.lifecycleEvents, "package")
+	results, errs := async.RunParallel(
+		ctx,
+		targetServices,
+		parallelism,
+		func(ctx context.Context, svc *project.ServiceConfig) (_ *project.ServicePackageResult, err error) {
+			ctx, span := tracing.Start(ctx, events.ServicePackageEvent, trace.WithAttributes(
+				fields.StringHashed(fields.ProjectServiceNameKey, svc.Name),
+				fields.ProjectServiceHostKey.String(string(svc.Host)),
+			))
+			defer func() { span.EndWithStatus(err) }()
+
+			progress.Start(ctx, svc.Name)
+
+			packageResult, upToDate, err := packageService(
+				ctx, pa.serviceManager, pa.packageCache, svc, pa.flags.forceBuild, pa.flags.noCache, progress)
+			if err != nil {
+				progress.Stop(ctx, svc.Name, input.StepFailed, nil)
+				return nil, err
 			}
-		}()
 
-		packageResult, err := packageTask.Await()
+			// report package output
+			complete := func() { pa.console.MessageUxItem(ctx, packageResult) }
+			if upToDate {
+				progress.Skip(ctx, svc.Name, "up-to-date", complete)
+			} else {
+				progress.Stop(ctx, svc.Name, input.StepDone, complete)
+			}
+
+			return packageResult, nil
+		},
+	)
+
+	if err := joinServiceErrors(targetServices, errs); err != nil {
+		return nil, err
+	}
+
+	packageResults := map[string]*project.ServicePackageResult{}
+	for i, svc := range targetServices {
+		packageResults[svc.Name] = results[i]
+	}
+
+	var inspections map[string]*project.PackageInspection
+	if pa.flags.inspect {
+		inspections, err = pa.inspect(ctx, targetServices, packageResults)
 		if err != nil {
-			pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
 			return nil, err
 		}
-
-		pa.console.StopSpinner(ctx, stepMessage, input.StepDone)
-		packageResults[svc.Name] = packageResult
-
-		// report package output
-		pa.console.MessageUxItem(ctx, packageResult)
 	}
 
 	if pa.formatter.Kind() == output.JsonFormat {
 		packageResult := PackageResult{
-			Timestamp: time.Now(),
-			Services:  packageResults,
+			Timestamp:   time.Now(),
+			Services:    packageResults,
+			Inspections: inspections,
 		}
 
 		if fmtErr := pa.formatter.Format(packageResult, pa.writer, nil); fmtErr != nil {
@@ -176,6 +262,18 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 	}
 
+	failed := []string{}
+	for _, svc := range targetServices {
+		if inspection, has := inspections[svc.Name]; has && !inspection.Passed() {
+			failed = append(failed, svc.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return nil, fmt.Errorf(
+			"package inspection found issues in the packages of the services: %s", strings.Join(failed, ", "))
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
 			Header: fmt.Sprintf("Your application was packaged for Azure in %s.", ux.DurationAsText(time.Since(startTime))),
@@ -183,6 +281,148 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}, nil
 }
 
+// Packages the service unless it hasn't changed since it was last packaged, unless force is set. The package cache
+// isn't read or written when noCache is set.
+// Returns true when the previous package of the service is up-to-date and was reused.
+func packageService(
+	ctx context.Context,
+	serviceManager project.ServiceManager,
+	packageCache *project.PackageCache,
+	svc *project.ServiceConfig,
+	force bool,
+	noCache bool,
+	progress *serviceProgress,
+) (*project.ServicePackageResult, bool, error) {
+	fingerprint := ""
+	if !noCache {
+		var err error
+		if fingerprint, err = packageCache.Fingerprint(svc); err != nil {
+			log.Printf("failed computing package fingerprint for service '%s': %v\n", svc.Name, err)
+		}
+	}
+
+	if !force && fingerprint != "" {
+		if packageResult, has := packageCache.Get(ctx, svc, fingerprint); has {
+			// The package hooks of the service run for reused packages like they do when the service is packaged
+			eventArgs := project.ServiceLifecycleEventArgs{Project: svc.Project, Service: svc}
+			if err := svc.Invoke(ctx, project.ServiceEventPackage, eventArgs, func() error { return nil }); err != nil {
+				return nil, false, fmt.Errorf("failed packaging service '%s': %w", svc.Name, err)
+			}
+
+			return packageResult, true, nil
+		}
+	}
+
+	packageTask := serviceManager.Package(ctx, svc, nil)
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		progress.Track(ctx, svc.Name, packageTask.Progress())
+	}()
+
+	packageResult, err := packageTask.Await()
+	<-progressDone
+	if err != nil {
+		return nil, false, err
+	}
+
+	if fingerprint != "" {
+		if err := packageCache.Set(svc, fingerprint, packageResult); err != nil {
+			log.Printf("failed caching package of service '%s': %v\n", svc.Name, err)
+		}
+	}
+
+	return packageResult, false, nil
+}
+
+// Inspects the packages of the services, reporting the inspection of each package to the console
+func (pa *packageAction) inspect(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	packageResults map[string]*project.ServicePackageResult,
+) (map[string]*project.PackageInspection, error) {
+	inspections := map[string]*project.PackageInspection{}
+	for _, svc := range services {
+		stepMessage := fmt.Sprintf("Inspecting package of service %s", svc.Name)
+		pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		inspection, err := pa.packageInspector.Inspect(ctx, svc, packageResults[svc.Name])
+		if err != nil {
+			pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return nil, fmt.Errorf("inspecting package of service '%s': %w", svc.Name, err)
+		}
+
+		if inspection.Passed() {
+			pa.console.StopSpinner(ctx, stepMessage, input.StepDone)
+		} else {
+			pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+		}
+
+		if pa.formatter.Kind() != output.JsonFormat {
+			pa.console.Message(ctx, formatPackageInspection(inspection))
+		}
+
+		inspections[svc.Name] = inspection
+	}
+
+	return inspections, nil
+}
+
+func formatPackageInspection(inspection *project.PackageInspection) string {
+	lines := []string{}
+	summary := fmt.Sprintf("  - Package: %s (%s, %s", output.WithLinkFormat(inspection.Path), inspection.Kind,
+		project.FormatBytes(inspection.SizeBytes))
+	if inspection.Kind != project.PackageKindContainer {
+		summary += fmt.Sprintf(", %d files", inspection.Files)
+	}
+	lines = append(lines, summary+")")
+
+	if inspection.SizeLimit != nil {
+		lines = append(lines, fmt.Sprintf("  - Size limit: %s (%s)",
+			project.FormatBytes(inspection.SizeLimit.MaxBytes), inspection.SizeLimit.Description))
+	}
+
+	if len(inspection.LargestFiles) > 0 {
+		lines = append(lines, "  - Largest files:")
+		for _, file := range inspection.LargestFiles {
+			lines = append(lines, fmt.Sprintf("      %s (%s)", file.Path, project.FormatBytes(file.SizeBytes)))
+		}
+	}
+
+	if inspection.Container != nil {
+		if inspection.Container.BaseImage != "" {
+			lines = append(lines, fmt.Sprintf("  - Base image: %s", inspection.Container.BaseImage))
+		}
+
+		lines = append(lines, fmt.Sprintf("  - Platform: %s", inspection.Container.Platform))
+		lines = append(lines, fmt.Sprintf("  - Layers: %d", len(inspection.Container.Layers)))
+		for _, layer := range inspection.Container.Layers {
+			createdBy := layer.CreatedBy
+			if len(createdBy) > 80 {
+				createdBy = createdBy[:77] + "..."
+			}
+			lines = append(lines, fmt.Sprintf("      %s (%s)", createdBy, project.FormatBytes(layer.SizeBytes)))
+		}
+	}
+
+	if len(inspection.Secrets) > 0 {
+		lines = append(lines, output.WithWarningFormat("  - Possible secrets:"))
+		for _, secret := range inspection.Secrets {
+			location := secret.Location
+			if secret.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, secret.Line)
+			}
+			lines = append(lines, output.WithWarningFormat("      %s (%s)", location, secret.Rule))
+		}
+	}
+
+	for _, issue := range inspection.Issues {
+		lines = append(lines, output.WithErrorFormat("  - %s", issue))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
 func getCmdPackageHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Packages application's code to be deployed to Azure. %s",
@@ -193,7 +433,16 @@ func getCmdPackageHelpDescription(*cobra.Command) string {
 				" or the service described in the project that matches the current directory."),
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is packaged.", output.WithHighLightFormat("<service>"))),
+		formatHelpNote(fmt.Sprintf("Services are packaged concurrently, up to %d at a time. Use %s to change the limit.",
+			defaultServiceParallelism,
+			output.WithHighLightFormat("azd config set %s <number>", serviceParallelismConfigPath))),
+		formatHelpNote(fmt.Sprintf("Services whose sources, Dockerfile and build settings match a recent package"+
+			" are skipped as up-to-date. Use %s to package them again, or %s to bypass the package cache.",
+			output.WithHighLightFormat("--force-build"), output.WithHighLightFormat("--no-cache"))),
 		formatHelpNote("After the packaging is complete, the package locations are printed."),
+		formatHelpNote(fmt.Sprintf("When %s is set, the contents of the packages are reported and scanned for"+
+			" embedded secrets and size limits of the target host. Packaging fails when issues are found.",
+			output.WithHighLightFormat("--inspect"))),
 	})
 }
 
@@ -202,5 +451,7 @@ func getCmdPackageHelpFooter(*cobra.Command) string {
 		"Packages all services in the current project to Azure.": output.WithHighLightFormat("azd package --all"),
 		"Packages the service named 'api' to Azure.":             output.WithHighLightFormat("azd package api"),
 		"Packages the service named 'web' to Azure.":             output.WithHighLightFormat("azd package web"),
+		"Packages all services and inspects the packages before deploy.": output.WithHighLightFormat(
+			"azd package --all --inspect"),
 	})
 }
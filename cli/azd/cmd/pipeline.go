@@ -49,7 +49,7 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 		&pc.PipelineAuthTypeName,
 		"auth-type",
 		"",
-		"The authentication type used between the pipeline provider and Azure for deployment (Only valid for GitHub provider). Valid values: federated, client-credentials.",
+		"The authentication type used between the pipeline provider and Azure for deployment (Only valid for GitHub and GitLab providers). Valid values: federated, client-credentials.",
 	)
 	//nolint:lll
 	local.StringArrayVar(
@@ -61,7 +61,7 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 	// default provider is empty because it can be set from azure.yaml. By letting default here be empty, we know that
 	// there no customer input using --provider
 	local.StringVar(&pc.PipelineProvider, "provider", "",
-		"The pipeline provider to use (github for Github Actions and azdo for Azure Pipelines).")
+		"The pipeline provider to use (github for Github Actions, azdo for Azure Pipelines and gitlab for GitLab CI/CD).")
 	pc.envFlag.Bind(local, global)
 	pc.global = global
 }
@@ -82,9 +82,10 @@ func pipelineActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 	})
 
 	group.Add("config", &actions.ActionDescriptorOptions{
-		Command:        newPipelineConfigCmd(),
-		FlagsResolver:  newPipelineConfigFlags,
-		ActionResolver: newPipelineConfigAction,
+		Command:         newPipelineConfigCmd(),
+		FlagsResolver:   newPipelineConfigFlags,
+		ActionResolver:  newPipelineConfigAction,
+		RequiresNetwork: true,
 		HelpOptions: actions.ActionHelpOptions{
 			Description: getCmdPipelineConfigHelpDescription,
 			Footer:      getCmdPipelineConfigHelpFooter,
@@ -228,7 +229,7 @@ func getCmdPipelineConfigHelpDescription(*cobra.Command) string {
 		"Configure your deployment pipeline to connect securely to Azure",
 		[]string{
 			formatHelpNote(
-				"Supports GitHub Actions and Azure Pipelines. To configure using a specific pipeline provider, " +
+				"Supports GitHub Actions, Azure Pipelines and GitLab CI/CD. To configure using a specific pipeline provider, " +
 					"provide a value for the '--provider' flag."),
 			formatHelpNote(
 				output.WithHighLightFormat("pipeline config") +
@@ -256,5 +257,9 @@ func getCmdPipelineConfigHelpFooter(c *cobra.Command) string {
 			output.WithWarningFormat("app-test"),
 			output.WithHighLightFormat("--provider azdo"),
 		),
+		"Configure a deployment pipeline on GitLab CI/CD, creating its .gitlab-ci.yml if missing.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd pipeline config --provider"),
+			output.WithWarningFormat("gitlab"),
+		),
 	})
 }
@@ -2,43 +2,90 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	bicepcli "github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.uber.org/multierr"
 )
 
+// errProvisionDeclined is returned when the user doesn't approve the changes previewed with --preview
+var errProvisionDeclined = errors.New("the changes were not approved, no Azure resources were provisioned")
+
 type provisionFlags struct {
-	noProgress bool
-	global     *internal.GlobalCommandOptions
+	noProgress     bool
+	force          bool
+	preview        bool
+	reconcile      bool
+	destroyOrphans bool
+	global         *internal.GlobalCommandOptions
 	*envFlag
 }
 
 func (i *provisionFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	i.bindNonCommon(local, global)
 	i.bindCommon(local, global)
+	local.Bool(
+		middleware.DryRunFlagName,
+		false,
+		"Previews the changes of the Azure resources (what-if) without provisioning them.",
+	)
 }
 
 func (i *provisionFlags) bindNonCommon(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	local.BoolVar(&i.noProgress, "no-progress", false, "Suppresses progress information.")
 	//deprecate:Flag hide --no-progress
 	_ = local.MarkHidden("no-progress")
+	local.BoolVar(
+		&i.force,
+		"force",
+		false,
+		"Provisions the Azure resources even when the infrastructure hasn't changed since it was last provisioned.",
+	)
+	local.BoolVar(
+		&i.preview,
+		"preview",
+		false,
+		"Previews the changes of the Azure resources (what-if) and asks for approval before provisioning them.",
+	)
+	local.BoolVar(
+		&i.reconcile,
+		"reconcile",
+		false,
+		"Reports the resources of the environment no longer defined by the infrastructure and asks to delete them.",
+	)
+	local.BoolVar(
+		&i.destroyOrphans,
+		"destroy-orphans",
+		false,
+		"Deletes the resources of the environment no longer defined by the infrastructure without asking, implies "+
+			"--reconcile.",
+	)
 	i.global = global
 }
 
@@ -81,6 +128,10 @@ type provisionAction struct {
 	userProfileService  *azcli.UserProfileService
 	subResolver         account.SubscriptionTenantResolver
 	alphaFeatureManager *alpha.FeatureManager
+	serviceLocator      ioc.ServiceLocator
+	budgetManager       *infra.BudgetManager
+	inventory           *infra.ResourceInventory
+	roleManager         *infra.RoleAssignmentManager
 }
 
 func newProvisionAction(
@@ -99,6 +150,10 @@ func newProvisionAction(
 	userProfileService *azcli.UserProfileService,
 	subResolver account.SubscriptionTenantResolver,
 	alphaFeatureManager *alpha.FeatureManager,
+	serviceLocator ioc.ServiceLocator,
+	budgetManager *infra.BudgetManager,
+	inventory *infra.ResourceInventory,
+	roleManager *infra.RoleAssignmentManager,
 ) actions.Action {
 	return &provisionAction{
 		flags:               flags,
@@ -116,6 +171,10 @@ func newProvisionAction(
 		userProfileService:  userProfileService,
 		subResolver:         subResolver,
 		alphaFeatureManager: alphaFeatureManager,
+		serviceLocator:      serviceLocator,
+		budgetManager:       budgetManager,
+		inventory:           inventory,
+		roleManager:         roleManager,
 	}
 }
 
@@ -159,7 +218,29 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	if err != nil {
 		return nil, fmt.Errorf("creating provisioning manager: %w", err)
 	}
+
+	if err := p.setServiceScopes(ctx); err != nil {
+		return nil, err
+	}
+
+	if middleware.IsDryRun(ctx) {
+		return p.preview(ctx, infraManager)
+	}
+
+	if p.flags.preview && p.flags.global.NoPrompt {
+		return nil, errors.New(
+			"--preview asks for approval and can't be used with --no-prompt, use --dry-run to only preview the changes")
+	}
+
+	if budget := p.projectConfig.Infra.Budget; budget != nil {
+		if err := p.checkBudget(ctx, *budget); err != nil {
+			return nil, err
+		}
+	}
+
 	var deployResult *provisioning.DeployResult
+	var skipped bool
+	var declined bool
 
 	projectEventArgs := project.ProjectLifecycleEventArgs{
 		Project: p.projectConfig,
@@ -171,6 +252,31 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 			return fmt.Errorf("planning deployment: %w", err)
 		}
 
+		if !p.flags.force {
+			// Failing to compare the fingerprints only costs a deployment
+			upToDate, err := infraManager.UpToDate(ctx, deploymentPlan)
+			if err != nil {
+				log.Printf("failed checking whether the infrastructure is up to date: %v", err)
+			}
+
+			if upToDate {
+				skipped = true
+				return nil
+			}
+		}
+
+		if p.flags.preview {
+			approved, err := p.approvePreview(ctx, infraManager, deploymentPlan)
+			if err != nil {
+				return err
+			}
+
+			if !approved {
+				declined = true
+				return nil
+			}
+		}
+
 		deployResult, err = infraManager.Deploy(ctx, deploymentPlan)
 
 		return err
@@ -198,6 +304,40 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		return nil, fmt.Errorf("deployment failed: %w", err)
 	}
 
+	provisionResult := fields.ProvisionResultExecuted
+	if skipped {
+		provisionResult = fields.ProvisionResultSkipped
+	} else if declined {
+		provisionResult = fields.ProvisionResultDeclined
+	}
+	tracing.SetUsageAttributes(
+		fields.ProvisionResultKey.String(provisionResult),
+		fields.ProvisionForcedKey.Bool(p.flags.force),
+	)
+
+	// Declining fails the command, so that `azd up` doesn't deploy the services to the unchanged infrastructure
+	if declined {
+		return nil, errProvisionDeclined
+	}
+
+	// The resources drift from the infrastructure even when the infrastructure is up to date with the last provisioning
+	if p.flags.reconcile || p.flags.destroyOrphans {
+		if err := p.reconcile(ctx, infraManager); err != nil {
+			return nil, err
+		}
+	}
+
+	// The role assignments are ensured even when provisioning is skipped, they aren't part of the infrastructure state
+	if len(p.projectConfig.Infra.RoleAssignments) > 0 {
+		if err := p.ensureRoleAssignments(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if skipped {
+		return p.skippedResult(ctx, infraManager)
+	}
+
 	for _, svc := range p.projectConfig.Services {
 		eventArgs := project.ServiceLifecycleEventArgs{
 			Project: p.projectConfig,
@@ -212,6 +352,27 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		}
 	}
 
+	// The budget already protects the environment when it can't be updated, it doesn't fail the provisioning
+	if budget := p.projectConfig.Infra.Budget; budget != nil {
+		if err := p.ensureBudget(ctx, *budget); err != nil {
+			p.console.Message(ctx, output.WithWarningFormat("WARNING: updating the budget of the environment: %v", err))
+		}
+	}
+
+	// A stale diagram doesn't fail the provisioning. The bicep CLI is only resolved, and installed, for the diagram.
+	if p.projectConfig.Infra.Diagram != "" {
+		var bicepCli bicepcli.BicepCli
+		err := p.serviceLocator.Resolve(&bicepCli)
+		if err == nil {
+			err = updateInfraDiagram(
+				ctx, bicepCli, p.commandRunner, p.azdCtx.ProjectDirectory(), p.projectConfig.Infra)
+		}
+
+		if err != nil {
+			p.console.Message(ctx, output.WithWarningFormat("WARNING: updating the infrastructure diagram: %v", err))
+		}
+	}
+
 	if p.formatter.Kind() == output.JsonFormat {
 		stateResult, err := infraManager.State(ctx)
 		if err != nil {
@@ -240,13 +401,412 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	}, nil
 }
 
+// setServiceScopes sets the subscriptions and resource groups of the services hosted outside of the subscription or the
+// resource group of the environment as the `SERVICE_<NAME>_SUBSCRIPTION_ID` and `SERVICE_<NAME>_RESOURCE_GROUP`
+// values of the environment, so that the infrastructure can deploy their resources there, ex. with a Bicep module
+// scoped to `resourceGroup(subscriptionId, resourceGroupName)`.
+//
+// The access to the subscriptions is verified before provisioning. A deployment can only reach the subscriptions of the
+// tenant of the environment, the resources of the services in the subscriptions of other tenants must already exist.
+func (p *provisionAction) setServiceScopes(ctx context.Context) error {
+	envTenantId := ""
+	changed := false
+
+	for _, svc := range p.projectConfig.GetServicesStable() {
+		if svc.Subscription.Empty() && svc.ResourceGroupName.Empty() {
+			continue
+		}
+
+		subscriptionId, err := p.resourceManager.GetServiceSubscriptionId(svc)
+		if err != nil {
+			return err
+		}
+
+		resourceGroupName, err := svc.ResourceGroupName.Envsubst(p.env.Getenv)
+		if err != nil {
+			return fmt.Errorf("expanding resource group of service '%s': %w", svc.Name, err)
+		}
+
+		if subscriptionId != p.env.GetSubscriptionId() {
+			tenantId, err := p.subResolver.LookupTenant(ctx, subscriptionId)
+			if err != nil {
+				return fmt.Errorf("resolving the tenant of the subscription of service '%s': %w", svc.Name, err)
+			}
+
+			if envTenantId == "" {
+				if envTenantId, err = p.subResolver.LookupTenant(ctx, p.env.GetSubscriptionId()); err != nil {
+					return fmt.Errorf("resolving the tenant of the environment subscription: %w", err)
+				}
+			}
+
+			if tenantId != envTenantId {
+				p.console.MessageUxItem(ctx, &ux.WarningMessage{
+					Description: fmt.Sprintf(
+						"Service %s is hosted in subscription %s of tenant %s. Its resources can't be provisioned "+
+							"with the resources of the environment and must already exist to deploy the service.",
+						svc.Name, subscriptionId, tenantId),
+				})
+			}
+		}
+
+		for key, value := range map[string]string{
+			"SUBSCRIPTION_ID": subscriptionId,
+			"RESOURCE_GROUP":  strings.TrimSpace(resourceGroupName),
+		} {
+			if value != "" && p.env.GetServiceProperty(svc.Name, key) != value {
+				p.env.SetServiceProperty(svc.Name, key, value)
+				changed = true
+			}
+		}
+	}
+
+	// Previews don't change the environment, the values are only used by the previewed deployment
+	if !changed || middleware.IsDryRun(ctx) {
+		return nil
+	}
+
+	return p.env.Save()
+}
+
+// reconcile reports the resources tagged with the environment which aren't part of the state of the infrastructure, ex.
+// the resources removed from the templates since they were provisioned, and offers to delete them. The resources are
+// deleted without asking with --destroy-orphans, they are only reported with --no-prompt.
+func (p *provisionAction) reconcile(ctx context.Context, infraManager *provisioning.Manager) error {
+	stateResult, err := infraManager.State(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the state of the infrastructure: %w", err)
+	}
+
+	// Without the resources of the state, every resource of the environment would be an orphan
+	if len(stateResult.State.Resources) == 0 {
+		return errors.New("the state of the infrastructure doesn't list its resources, the resources can't be reconciled")
+	}
+
+	definedIds := make([]string, len(stateResult.State.Resources))
+	for i, resource := range stateResult.State.Resources {
+		definedIds[i] = resource.Id
+	}
+
+	inventory, err := p.inventory.List(ctx, p.env.GetSubscriptionId(), p.env.GetEnvName())
+	if err != nil {
+		return fmt.Errorf("listing the resources of the environment: %w", err)
+	}
+
+	orphans := infra.Orphans(inventory, definedIds)
+	tracing.SetUsageAttributes(fields.ProvisionOrphansKey.Int(len(orphans)))
+
+	if len(orphans) == 0 {
+		p.console.Message(ctx, "No drift detected, the resources of the environment are defined by the infrastructure.")
+		return nil
+	}
+
+	p.console.Message(ctx, fmt.Sprintf(
+		"\nFound %d resource(s) of environment %s no longer defined by the infrastructure:",
+		len(orphans), p.env.GetEnvName()))
+	for _, orphan := range orphans {
+		p.console.Message(ctx, fmt.Sprintf("  - %s (%s)", orphan.Name, orphan.Type))
+	}
+	p.console.Message(ctx, "")
+
+	if !p.flags.destroyOrphans {
+		if p.flags.global.NoPrompt {
+			p.console.Message(ctx, fmt.Sprintf(
+				"Run %s to delete them.", output.WithHighLightFormat("azd provision --destroy-orphans")))
+			return nil
+		}
+
+		confirmed, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      fmt.Sprintf("Delete the %d resource(s)?", len(orphans)),
+			DefaultValue: false,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			return nil
+		}
+	}
+
+	p.console.ShowSpinner(ctx, "Deleting resources no longer defined by the infrastructure", input.Step)
+	err = p.inventory.Delete(ctx, p.env.GetSubscriptionId(), orphans)
+	p.console.StopSpinner(ctx, "Deleting resources no longer defined by the infrastructure", input.GetStepResultFormat(err))
+	if err != nil {
+		return fmt.Errorf("deleting orphaned resources: %w", err)
+	}
+
+	return nil
+}
+
+// skippedResult reports the provisioning skipped since the infrastructure is up to date with the last provisioning
+func (p *provisionAction) skippedResult(
+	ctx context.Context, infraManager *provisioning.Manager) (*actions.ActionResult, error) {
+	if p.formatter.Kind() == output.JsonFormat {
+		stateResult, err := infraManager.State(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("the deployment result is unavailable: %w", err)
+		}
+
+		if err := p.formatter.Format(
+			provisioning.NewEnvRefreshResultFromState(stateResult.State), p.writer, nil); err != nil {
+			return nil, fmt.Errorf("the deployment result could not be displayed: %w", err)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "There are no changes to provision, the infrastructure is up to date with the last provisioning.",
+			FollowUp: fmt.Sprintf(
+				"Run %s to provision the Azure resources again, ex. to revert changes made outside of azd.",
+				output.WithHighLightFormat("azd provision --force")),
+		},
+	}, nil
+}
+
+// preview displays the changes the deployment of the infrastructure would make to the Azure resources, for --dry-run
+func (p *provisionAction) preview(
+	ctx context.Context, infraManager *provisioning.Manager) (*actions.ActionResult, error) {
+	deploymentPlan, err := infraManager.Plan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("planning deployment: %w", err)
+	}
+
+	preview, err := infraManager.PreviewDeploy(ctx, deploymentPlan)
+	if errors.Is(err, provisioning.ErrPreviewNotSupported) {
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The provisioning provider can't preview the changes of the resources, only the plan was created."))
+
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{Header: "Dry run: no Azure resources were provisioned."},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("previewing deployment: %w", err)
+	}
+
+	setPreviewUsageAttributes(preview)
+
+	if p.formatter.Kind() == output.JsonFormat {
+		if err := p.formatter.Format(preview, p.writer, nil); err != nil {
+			return nil, fmt.Errorf("deployment preview could not be displayed: %w", err)
+		}
+	} else {
+		p.console.Message(ctx, formatDeploymentPreview(preview))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{Header: "Dry run: no Azure resources were provisioned."},
+	}, nil
+}
+
+// approvePreview displays the changes the deployment of the plan would make, for --preview, and asks the user to approve
+// them before the deployment starts
+func (p *provisionAction) approvePreview(
+	ctx context.Context, infraManager *provisioning.Manager, deploymentPlan *provisioning.DeploymentPlan) (bool, error) {
+	preview, err := infraManager.PreviewDeploy(ctx, deploymentPlan)
+	if errors.Is(err, provisioning.ErrPreviewNotSupported) {
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The provisioning provider can't preview the changes of the resources."))
+	} else if err != nil {
+		return false, fmt.Errorf("previewing deployment: %w", err)
+	} else {
+		setPreviewUsageAttributes(preview)
+		p.console.Message(ctx, formatDeploymentPreview(preview))
+	}
+
+	approved, err := p.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Do you want to provision these changes?",
+		DefaultValue: false,
+	})
+	if err != nil {
+		return false, fmt.Errorf("prompting to approve the changes: %w", err)
+	}
+
+	return approved, nil
+}
+
+// countDeploymentPreview counts the resources of the preview by change, changes that are ignored count as unchanged
+func countDeploymentPreview(preview *provisioning.DeploymentPreview) map[provisioning.ChangeType]int {
+	counts := map[provisioning.ChangeType]int{}
+	for _, change := range preview.Changes {
+		changeType := change.ChangeType
+		if changeType == provisioning.ChangeTypeIgnore {
+			changeType = provisioning.ChangeTypeNoChange
+		}
+
+		counts[changeType]++
+	}
+
+	return counts
+}
+
+func setPreviewUsageAttributes(preview *provisioning.DeploymentPreview) {
+	counts := countDeploymentPreview(preview)
+	tracing.SetUsageAttributes(
+		fields.ProvisionPreviewCreateKey.Int(counts[provisioning.ChangeTypeCreate]),
+		fields.ProvisionPreviewModifyKey.Int(counts[provisioning.ChangeTypeModify]),
+		fields.ProvisionPreviewDeleteKey.Int(counts[provisioning.ChangeTypeDelete]),
+		fields.ProvisionPreviewUnchangedKey.Int(counts[provisioning.ChangeTypeNoChange]),
+	)
+}
+
+// formatDeploymentPreview lists the resources a deployment would change, colored by change, followed by the number of
+// resources of each change. Resources without changes are only counted.
+func formatDeploymentPreview(preview *provisioning.DeploymentPreview) string {
+	lines := []string{}
+	for _, change := range preview.Changes {
+		if change.ChangeType == provisioning.ChangeTypeNoChange || change.ChangeType == provisioning.ChangeTypeIgnore {
+			continue
+		}
+
+		line := fmt.Sprintf("  %-8s %s %s", change.ChangeType, change.ResourceType, change.Name)
+		switch change.ChangeType {
+		case provisioning.ChangeTypeCreate:
+			line = output.WithSuccessFormat(line)
+		case provisioning.ChangeTypeModify:
+			line = output.WithWarningFormat(line)
+		case provisioning.ChangeTypeDelete:
+			line = output.WithErrorFormat(line)
+		}
+
+		lines = append(lines, line)
+		for _, property := range change.ChangedProperties {
+			lines = append(lines, fmt.Sprintf("             ~ %s", property))
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "  No resources would be changed.")
+	}
+
+	counts := countDeploymentPreview(preview)
+	lines = append(lines, "", fmt.Sprintf("  %s, %s, %s, %s",
+		output.WithSuccessFormat("%d to create", counts[provisioning.ChangeTypeCreate]),
+		output.WithWarningFormat("%d to modify", counts[provisioning.ChangeTypeModify]),
+		output.WithErrorFormat("%d to delete", counts[provisioning.ChangeTypeDelete]),
+		output.WithGrayFormat("%d unchanged", counts[provisioning.ChangeTypeNoChange]),
+	))
+
+	return fmt.Sprintf("\n%s\n", strings.Join(lines, "\n"))
+}
+
+// checkBudget warns, or requires a confirmation, when the spend of the environment this month exceeds its budget
+func (p *provisionAction) checkBudget(ctx context.Context, budget infra.BudgetOptions) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+
+	status, err := p.budgetManager.Status(ctx, p.env.GetSubscriptionId(), p.env.GetEnvName(), budget)
+	if err != nil {
+		// Reading the cost requires the Cost Management Reader role, which isn't required to provision
+		log.Printf("failed reading the spend of the environment, skipping the budget check: %v", err)
+		return nil
+	}
+
+	if status == nil || !status.Exceeded() {
+		return nil
+	}
+
+	p.console.Message(ctx, output.WithWarningFormat(
+		"WARNING: The spend of environment %s this month, %.2f %s, exceeds its budget of %.2f %s "+
+			"(forecast: %.2f %s). Run 'azd cost' for the cost of each resource.",
+		p.env.GetEnvName(), status.MonthToDate, status.Currency, status.Budget, status.Currency,
+		status.Forecast, status.Currency))
+
+	if budget.OnExceeded != infra.BudgetExceededConfirm {
+		return nil
+	}
+
+	confirmed, err := p.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Continue provisioning over budget?",
+		DefaultValue: false,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return fmt.Errorf(
+			"provisioning cancelled, the spend of environment %s exceeds its budget", p.env.GetEnvName())
+	}
+
+	return nil
+}
+
+// ensureBudget creates or updates the budget of the environment, scoped to the resource groups provisioned
+func (p *provisionAction) ensureBudget(ctx context.Context, budget infra.BudgetOptions) error {
+	spinnerMessage := fmt.Sprintf("Updating budget %s", output.WithHighLightFormat(infra.BudgetName(p.env.GetEnvName())))
+	p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	_, err := p.budgetManager.EnsureBudget(ctx, p.env.GetSubscriptionId(), p.env.GetEnvName(), budget)
+	p.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+
+	return err
+}
+
+// ensureRoleAssignments creates the role assignments of the infra.roleAssignments section that don't exist
+func (p *provisionAction) ensureRoleAssignments(ctx context.Context) error {
+	spinnerMessage := "Assigning roles"
+	p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	assignments, err := resolveRoleAssignments(
+		ctx, p.roleManager, p.resourceManager, p.projectConfig, p.env, p.userProfileService, p.subResolver)
+	if err == nil {
+		err = p.roleManager.Ensure(ctx, p.env.GetSubscriptionId(), assignments)
+	}
+	p.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+
+	return err
+}
+
+// resolveRoleAssignments returns the role assignments of the infra.roleAssignments section with the values of the
+// environment, the principal `deployer` being the principal logged in to azd
+func resolveRoleAssignments(
+	ctx context.Context,
+	roleManager *infra.RoleAssignmentManager,
+	resourceManager project.ResourceManager,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	userProfileService *azcli.UserProfileService,
+	subResolver account.SubscriptionTenantResolver,
+) ([]infra.RoleAssignment, error) {
+	subscriptionId := env.GetSubscriptionId()
+
+	// The assignments with a scope don't need the resource group, ex. when the infrastructure has several
+	resourceGroupName, err := resourceManager.GetResourceGroupName(ctx, subscriptionId, projectConfig)
+	if err != nil {
+		log.Printf("resolving the resource group of the role assignments: %v", err)
+		resourceGroupName = ""
+	}
+
+	deployerId := func(ctx context.Context) (string, error) {
+		tenantId, err := subResolver.LookupTenant(ctx, subscriptionId)
+		if err != nil {
+			return "", fmt.Errorf("resolving the tenant of the subscription: %w", err)
+		}
+
+		principalId, err := azureutil.GetCurrentPrincipalId(ctx, userProfileService, tenantId)
+		if err != nil {
+			return "", fmt.Errorf("resolving the principal of the deployer: %w", err)
+		}
+
+		return principalId, nil
+	}
+
+	return roleManager.Resolve(
+		ctx, subscriptionId, resourceGroupName, projectConfig.Infra.RoleAssignments, env.Getenv, deployerId)
+}
+
 func getCmdProvisionHelpDescription(c *cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Provision the Azure resources for an application."+
 			" This step may take a while depending on the resources provisioned."+
 			" You should run %s any time you update your Bicep or Terraform file."+
+			" Provisioning is skipped when the infrastructure and its parameters haven't changed since the last"+
+			" provisioning of the environment, run %s to provision the resources again."+
+			" Run %s to review the changes of the resources and approve them before they are provisioned."+
 			"\n\nThis command prompts you to input the following:",
-		output.WithHighLightFormat(c.CommandPath())), []string{
+		output.WithHighLightFormat(c.CommandPath()),
+		output.WithHighLightFormat("%s --force", c.CommandPath()),
+		output.WithHighLightFormat("%s --preview", c.CommandPath())), []string{
 		formatHelpNote("Azure location: The Azure location where your resources will be deployed."),
 		formatHelpNote("Azure subscription: The Azure subscription where your resources will be deployed."),
 	})
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_countDeploymentPreview(t *testing.T) {
+	preview := &provisioning.DeploymentPreview{
+		Changes: []provisioning.ResourceChange{
+			{ChangeType: provisioning.ChangeTypeCreate, Name: "web"},
+			{ChangeType: provisioning.ChangeTypeCreate, Name: "api"},
+			{ChangeType: provisioning.ChangeTypeModify, Name: "plan"},
+			{ChangeType: provisioning.ChangeTypeDelete, Name: "old"},
+			{ChangeType: provisioning.ChangeTypeNoChange, Name: "vault"},
+			{ChangeType: provisioning.ChangeTypeIgnore, Name: "logs"},
+		},
+	}
+
+	require.Equal(t, map[provisioning.ChangeType]int{
+		provisioning.ChangeTypeCreate:   2,
+		provisioning.ChangeTypeModify:   1,
+		provisioning.ChangeTypeDelete:   1,
+		provisioning.ChangeTypeNoChange: 2,
+	}, countDeploymentPreview(preview))
+
+	formatted := formatDeploymentPreview(preview)
+	require.Contains(t, formatted, "2 to create, 1 to modify, 1 to delete, 2 unchanged")
+	require.NotContains(t, formatted, "vault")
+}
+
+func Test_provisionAction_setServiceScopes(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  web:
+    project: src/web
+    language: js
+    host: appservice
+  api:
+    subscription: ${API_SUBSCRIPTION_ID}
+    resourceGroup: rg-shared
+    project: src/api
+    language: js
+    host: appservice
+`
+	mockContext := mocks.NewMockContext(context.Background())
+	projectConfig, err := project.Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	env := environment.EphemeralWithValues("envA", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"API_SUBSCRIPTION_ID":                "API_SUBSCRIPTION_ID",
+	})
+
+	action := &provisionAction{
+		env:             env,
+		projectConfig:   projectConfig,
+		resourceManager: project.NewResourceManager(env, nil),
+		subResolver:     &mockSubscriptionTenantResolver{TenantId: "TENANT_ID"},
+		console:         mockContext.Console,
+	}
+
+	require.NoError(t, action.setServiceScopes(*mockContext.Context))
+	require.Equal(t, "API_SUBSCRIPTION_ID", env.GetServiceProperty("api", "SUBSCRIPTION_ID"))
+	require.Equal(t, "rg-shared", env.GetServiceProperty("api", "RESOURCE_GROUP"))
+
+	// Services hosted with the environment are left to the infrastructure
+	require.Empty(t, env.GetServiceProperty("web", "SUBSCRIPTION_ID"))
+	require.Empty(t, env.GetServiceProperty("web", "RESOURCE_GROUP"))
+}
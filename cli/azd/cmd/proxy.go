@@ -0,0 +1,202 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type proxyFlags struct {
+	global     *internal.GlobalCommandOptions
+	port       int
+	remotePort int
+	scope      string
+	envFlag
+}
+
+func (f *proxyFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.IntVarP(&f.port, "port", "p", 0, "The local port of the proxy. (Default: a free port)")
+	local.IntVar(
+		&f.remotePort,
+		"remote-port",
+		0,
+		"The port of the k8s service forwarded to, for services hosted on AKS. (Default: the first port of the service)",
+	)
+	local.StringVar(
+		&f.scope,
+		"scope",
+		"",
+		"The scope of the access token added to the requests forwarded to Container Apps, ex. api://<client-id>/.default",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newProxyFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *proxyFlags {
+	flags := &proxyFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newProxyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "proxy <service>",
+		Short: "Forward a local port to a deployed service.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type proxyAction struct {
+	flags              *proxyFlags
+	args               []string
+	env                *environment.Environment
+	projectConfig      *project.ProjectConfig
+	serviceManager     project.ServiceManager
+	resourceManager    project.ResourceManager
+	credentialProvider account.SubscriptionCredentialProvider
+	console            input.Console
+}
+
+func newProxyAction(
+	flags *proxyFlags,
+	args []string,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	serviceManager project.ServiceManager,
+	resourceManager project.ResourceManager,
+	credentialProvider account.SubscriptionCredentialProvider,
+	console input.Console,
+) actions.Action {
+	return &proxyAction{
+		flags:              flags,
+		args:               args,
+		env:                env,
+		projectConfig:      projectConfig,
+		serviceManager:     serviceManager,
+		resourceManager:    resourceManager,
+		credentialProvider: credentialProvider,
+		console:            console,
+	}
+}
+
+func (a *proxyAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	serviceName := a.args[0]
+	svc, has := a.projectConfig.Services[serviceName]
+	if !has {
+		return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+	}
+
+	if a.flags.port < 0 || a.flags.port > 65535 {
+		return nil, fmt.Errorf("--port must be a valid port number, got %d", a.flags.port)
+	}
+
+	if a.flags.remotePort < 0 || a.flags.remotePort > 65535 {
+		return nil, fmt.Errorf("--remote-port must be a valid port number, got %d", a.flags.remotePort)
+	}
+
+	serviceTarget, err := a.serviceManager.GetServiceTarget(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	proxier, ok := serviceTarget.(project.Proxier)
+	if !ok {
+		return nil, fmt.Errorf("service '%s': proxy is not supported for services hosted on '%s'", svc.Name, svc.Host)
+	}
+
+	if a.flags.remotePort != 0 && svc.Host != project.AksTarget {
+		return nil, fmt.Errorf(
+			"service '%s': --remote-port is only supported for services hosted on '%s'", svc.Name, project.AksTarget)
+	}
+
+	if a.flags.scope != "" && svc.Host != project.ContainerAppTarget {
+		return nil, fmt.Errorf(
+			"service '%s': --scope is only supported for services hosted on '%s'", svc.Name, project.ContainerAppTarget)
+	}
+
+	targetResource, err := a.resourceManager.GetTargetResource(ctx, a.env.GetSubscriptionId(), svc)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource of service '%s': %w", svc.Name, err)
+	}
+
+	credential, err := a.credentialProvider.CredentialForSubscription(ctx, a.env.GetSubscriptionId())
+	if err != nil {
+		return nil, err
+	}
+
+	// The proxy runs until Ctrl+C
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	err = proxier.Proxy(ctx, svc, targetResource, project.ProxyOptions{
+		LocalPort:  a.flags.port,
+		RemotePort: a.flags.remotePort,
+		Credential: credential,
+		Scope:      a.flags.scope,
+		Ready: func(localUrl string) {
+			a.console.Message(ctx, fmt.Sprintf(
+				"Forwarding %s to %s %s, press Ctrl+C to stop.\n",
+				output.WithLinkFormat(localUrl),
+				output.WithHighLightFormat(serviceName),
+				output.WithGrayFormat("(%s)", targetResource.ResourceName()),
+			))
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("proxying service '%s': %w", serviceName, err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Stopped the proxy of service %s.", serviceName),
+		},
+	}, nil
+}
+
+func getCmdProxyHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Forward a local port to a deployed service, to reach the service without exposing it publicly.",
+		[]string{
+			formatHelpNote("Container Apps are reached at the FQDN of their ingress. Use --scope to add an access" +
+				" token of your account to the requests, for apps requiring authentication."),
+			formatHelpNote("App Services and Function Apps are reached at their Kudu (SCM) site, authenticated with" +
+				" your account."),
+			formatHelpNote("Services hosted on AKS are reached with `kubectl port-forward` to their k8s service."),
+		})
+}
+
+func getCmdProxyHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Forward local port 8080 to the service.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd proxy <service> --port 8080"),
+			output.WithWarningFormat("[Service name]")),
+		"Forward to port 9090 of the k8s service of a service hosted on AKS.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd proxy <service> --remote-port 9090"),
+			output.WithWarningFormat("[Service name]")),
+		"Forward authenticated requests to a container app.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd proxy <service> --scope api://<client-id>/.default"),
+			output.WithWarningFormat("[Service name]")),
+	})
+}
@@ -10,7 +10,13 @@ import (
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -20,6 +26,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 )
 
 type restoreFlags struct {
@@ -45,6 +52,18 @@ func (r *restoreFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommand
 	)
 	//deprecate:flag hide --service
 	_ = local.MarkHidden("service")
+	local.Int(
+		middleware.ParallelismFlagName,
+		0,
+		"The maximum number of services restored at the same time, 1 restores services sequentially"+
+			" (default: the services.parallelism config, or 4).",
+	)
+	local.String(
+		middleware.DependencyCacheFlagName,
+		"",
+		"The directory of the dependency caches shared by the package managers of the services"+
+			" (default: the "+middleware.DependencyCacheConfigKey+" config, or the caches of the package managers).",
+	)
 }
 
 func newRestoreFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *restoreFlags {
@@ -66,17 +85,19 @@ func newRestoreCmd() *cobra.Command {
 }
 
 type restoreAction struct {
-	flags          *restoreFlags
-	args           []string
-	console        input.Console
-	formatter      output.Formatter
-	writer         io.Writer
-	azdCtx         *azdcontext.AzdContext
-	env            *environment.Environment
-	projectConfig  *project.ProjectConfig
-	projectManager project.ProjectManager
-	serviceManager project.ServiceManager
-	commandRunner  exec.CommandRunner
+	flags             *restoreFlags
+	args              []string
+	console           input.Console
+	formatter         output.Formatter
+	writer            io.Writer
+	azdCtx            *azdcontext.AzdContext
+	env               *environment.Environment
+	projectConfig     *project.ProjectConfig
+	projectManager    project.ProjectManager
+	serviceManager    project.ServiceManager
+	commandRunner     exec.CommandRunner
+	userConfigManager config.UserConfigManager
+	lifecycleEvents   *input.LifecycleEvents
 }
 
 func newRestoreAction(
@@ -91,19 +112,23 @@ func newRestoreAction(
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
 	commandRunner exec.CommandRunner,
+	userConfigManager config.UserConfigManager,
+	lifecycleEvents *input.LifecycleEvents,
 ) actions.Action {
 	return &restoreAction{
-		flags:          flags,
-		args:           args,
-		console:        console,
-		formatter:      formatter,
-		writer:         writer,
-		azdCtx:         azdCtx,
-		projectConfig:  projectConfig,
-		projectManager: projectManager,
-		serviceManager: serviceManager,
-		env:            env,
-		commandRunner:  commandRunner,
+		flags:             flags,
+		args:              args,
+		console:           console,
+		formatter:         formatter,
+		writer:            writer,
+		azdCtx:            azdCtx,
+		projectConfig:     projectConfig,
+		projectManager:    projectManager,
+		serviceManager:    serviceManager,
+		env:               env,
+		commandRunner:     commandRunner,
+		userConfigManager: userConfigManager,
+		lifecycleEvents:   lifecycleEvents,
 	}
 }
 
@@ -139,46 +164,84 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
-	if err := ra.projectManager.Initialize(ctx, ra.projectConfig); err != nil {
-		return nil, err
-	}
-
 	if err := ra.projectManager.EnsureFrameworkTools(ctx, ra.projectConfig, func(svc *project.ServiceConfig) bool {
 		return targetServiceName == "" || svc.Name == targetServiceName
 	}); err != nil {
 		return nil, err
 	}
 
-	restoreResults := map[string]*project.ServiceRestoreResult{}
+	parallelism, err := serviceParallelism(ctx, ra.userConfigManager)
+	if err != nil {
+		return nil, err
+	}
+	tracing.SetUsageAttributes(fields.ProjectServiceParallelismKey.Int(parallelism))
 
+	targetServices := []*project.ServiceConfig{}
 	for _, svc := range ra.projectConfig.GetServicesStable() {
-		stepMessage := fmt.Sprintf("Restoring service %s", svc.Name)
-		ra.console.ShowSpinner(ctx, stepMessage, input.Step)
-
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
 		// 2. This service is not the one the user specified
 		if targetServiceName != "" && targetServiceName != svc.Name {
+			stepMessage := fmt.Sprintf("Restoring service %s", svc.Name)
+			ra.console.ShowSpinner(ctx, stepMessage, input.Step)
 			ra.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			ra.lifecycleEvents.Emit(
+				"service restore", contracts.LifecyclePhaseSkipped, contracts.LifecycleEvent{Service: svc.Name})
 			continue
 		}
 
-		restoreTask := ra.serviceManager.Restore(ctx, svc)
-		go func() {
-			for restoreProgress := range restoreTask.Progress() {
-				progressMessage := fmt.Sprintf("Restoring service %s (%s)", svc.Name, restoreProgress.Message)
-				ra.console.ShowSpinner(ctx, progressMessage, input.Step)
+		targetServices = append(targetServices, svc)
+	}
+
+	parallelism = hooksParallelism(targetServices, parallelism)
+
+	// Services are independent of each other and are restored concurrently, the package managers sharing the
+	// dependency cache of --cache-dir when set
+	progress := newServiceProgress(ra.console, "Restoring").withEvents(ra.lifecycleEvents, "restore")
+	durations := make([]time.Duration, len(targetServices))
+	results, errs := async.RunParallel(
+		ctx,
+		targetServices,
+		parallelism,
+		func(ctx context.Context, svc *project.ServiceConfig) (*project.ServiceRestoreResult, error) {
+			index := slices.Index(targetServices, svc)
+			serviceStart := time.Now()
+			defer func() { durations[index] = time.Since(serviceStart) }()
+
+			progress.Start(ctx, svc.Name)
+
+			restoreTask := ra.serviceManager.Restore(ctx, svc)
+			progressDone := make(chan struct{})
+			go func() {
+				defer close(progressDone)
+				progress.Track(ctx, svc.Name, restoreTask.Progress())
+			}()
+
+			restoreResult, err := restoreTask.Await()
+			<-progressDone
+			if err != nil {
+				progress.Stop(ctx, svc.Name, input.StepFailed, nil)
+				return nil, err
 			}
-		}()
 
-		restoreResult, err := restoreTask.Await()
-		if err != nil {
-			ra.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			progress.Stop(ctx, svc.Name, input.StepDone, nil)
+			return restoreResult, nil
+		},
+	)
+
+	if !output.IsEventStream(ra.formatter) && len(targetServices) > 0 {
+		if err := ra.writeSummary(ctx, targetServices, durations, errs); err != nil {
 			return nil, err
 		}
+	}
+
+	if err := joinServiceErrors(targetServices, errs); err != nil {
+		return nil, err
+	}
 
-		ra.console.StopSpinner(ctx, stepMessage, input.StepDone)
-		restoreResults[svc.Name] = restoreResult
+	restoreResults := map[string]*project.ServiceRestoreResult{}
+	for i, svc := range targetServices {
+		restoreResults[svc.Name] = results[i]
 	}
 
 	if ra.formatter.Kind() == output.JsonFormat {
@@ -200,6 +263,51 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}, nil
 }
 
+// restoreResultRow is a row of the summary of the services restored
+type restoreResultRow struct {
+	Service  string
+	Language string
+	Result   string
+	Duration string
+}
+
+// Writes the table summarizing the result and duration of the restore of each service
+func (ra *restoreAction) writeSummary(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	durations []time.Duration,
+	errs []error,
+) error {
+	rows := make([]restoreResultRow, len(services))
+	for i, svc := range services {
+		rows[i] = restoreResultRow{
+			Service:  svc.Name,
+			Language: string(svc.Language),
+			Result:   "Restored",
+			Duration: ux.DurationAsText(durations[i]),
+		}
+
+		if errs[i] != nil {
+			rows[i].Result = "Failed"
+		}
+	}
+
+	ra.console.Message(ctx, "")
+	formatter := &output.TableFormatter{}
+	if err := formatter.Format(rows, ra.writer, output.TableFormatterOptions{
+		Columns: []output.Column{
+			{Heading: "SERVICE", ValueTemplate: "{{.Service}}"},
+			{Heading: "LANGUAGE", ValueTemplate: "{{.Language}}"},
+			{Heading: "RESULT", ValueTemplate: "{{.Result}}"},
+			{Heading: "DURATION", ValueTemplate: "{{.Duration}}"},
+		},
+	}); err != nil {
+		return fmt.Errorf("restore summary could not be displayed: %w", err)
+	}
+
+	return nil
+}
+
 func getCmdRestoreHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf("Restore application dependencies. %s", output.WithWarningFormat("(Beta)")),
@@ -210,6 +318,12 @@ func getCmdRestoreHelpDescription(*cobra.Command) string {
 				"to use the Visual Studio Code extension.",
 				output.WithLinkFormat("https://aka.ms/azure-dev/vscode"),
 			)),
+			formatHelpNote(fmt.Sprintf("Services are restored at the same time, up to %s services at once.",
+				output.WithHighLightFormat("--parallelism"))),
+			formatHelpNote(fmt.Sprintf("Share the downloads of npm, NuGet, pip and Maven between services with %s,"+
+				" ex. a directory cached between CI runs. Run %s to use it for all the commands restoring services.",
+				output.WithHighLightFormat("--cache-dir"),
+				output.WithHighLightFormat("azd config set %s <directory>", middleware.DependencyCacheConfigKey))),
 		})
 }
 
@@ -220,5 +334,8 @@ func getCmdRestoreHelpFooter(*cobra.Command) string {
 			"dependency, Individual services are listed in your azure.yaml file.": fmt.Sprintf("%s %s",
 			output.WithHighLightFormat("azd restore <service>"),
 			output.WithWarningFormat("[Service name]")),
+		"Restores all application dependencies with a dependency cache shared by the services.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd restore --cache-dir"),
+			output.WithWarningFormat("<directory>")),
 	})
 }
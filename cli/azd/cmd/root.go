@@ -14,12 +14,15 @@ import (
 	// Importing for infrastructure provider plugin registrations
 
 	_ "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	_ "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/devcenter"
+	_ "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/pulumi"
 	_ "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/terraform"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -54,6 +57,27 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				}
 			}
 
+			if opts.Project != "" {
+				// The project is resolved from the working directory set by --cwd, if any
+				projectDir, err := workspace.ResolveProject(opts.Project)
+				if err != nil {
+					return fmt.Errorf("--project: %w", err)
+				}
+
+				if prevDir == "" {
+					current, err := os.Getwd()
+					if err != nil {
+						return err
+					}
+
+					prevDir = current
+				}
+
+				if err := os.Chdir(projectDir); err != nil {
+					return fmt.Errorf("failed to change directory to %s: %w", projectDir, err)
+				}
+			}
+
 			return nil
 		},
 		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
@@ -76,14 +100,27 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		Command: rootCmd,
 		FlagsResolver: func(cmd *cobra.Command) *internal.GlobalCommandOptions {
 			rootCmd.PersistentFlags().StringVarP(&opts.Cwd, "cwd", "C", "", "Sets the current working directory.")
+			rootCmd.PersistentFlags().StringVar(
+				&opts.Project,
+				"project",
+				"",
+				"Sets the project to use: its directory, or its name or path in the workspace.")
 			rootCmd.PersistentFlags().
 				BoolVar(&opts.EnableDebugLogging, "debug", false, "Enables debugging and diagnostics logging.")
+			rootCmd.PersistentFlags().
+				BoolVar(
+					&opts.EnableHttpTracing,
+					"debug-http",
+					false,
+					"Writes the method, URL, status and duration of the HTTP requests sent to Azure to stderr.")
 			rootCmd.PersistentFlags().
 				BoolVar(
 					&opts.NoPrompt,
 					"no-prompt",
 					false,
 					"Accepts the default value instead of prompting, or it fails if there is no default.")
+			rootCmd.PersistentFlags().
+				DurationVar(&opts.Timeout, "timeout", 0, "Fails the command if it doesn't complete within the duration, ex. 30m.")
 
 			// The telemetry system is responsible for reading these flags value and using it to configure the telemetry
 			// system, but we still need to add it to our flag set so that when we parse the command line with Cobra we
@@ -104,11 +141,14 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 
 	configActions(root, opts)
 	envActions(root)
+	hooksActions(root)
 	infraActions(root)
 	pipelineActions(root)
 	telemetryActions(root)
 	templatesActions(root)
 	authActions(root)
+	extensionActions(root)
+	workspaceActions(root)
 
 	root.Add("version", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
@@ -124,23 +164,60 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		},
 	})
 
+	root.Add("upgrade", &actions.ActionDescriptorOptions{
+		Command:         newUpgradeCmd(),
+		FlagsResolver:   newUpgradeFlags,
+		ActionResolver:  newUpgradeAction,
+		RequiresNetwork: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdUpgradeHelpDescription,
+			Footer:      getCmdUpgradeHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
+	root.Add("doctor", &actions.ActionDescriptorOptions{
+		Command:        newDoctorCmd(),
+		FlagsResolver:  newDoctorFlags,
+		ActionResolver: newDoctorAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDoctorHelpDescription,
+			Footer:      getCmdDoctorHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
 	root.Add("show", &actions.ActionDescriptorOptions{
 		Command:        newShowCmd(),
 		FlagsResolver:  newShowFlags,
 		ActionResolver: newShowAction,
-		OutputFormats:  []output.Format{output.JsonFormat},
-		DefaultFormat:  output.NoneFormat,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdShowHelpDescription,
+			Footer:      getCmdShowHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
 	})
 
 	//deprecate:cmd hide login
 	login := newLoginCmd("")
 	login.Hidden = true
 	root.Add("login", &actions.ActionDescriptorOptions{
-		Command:        login,
-		FlagsResolver:  newLoginFlags,
-		ActionResolver: newLoginAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-		DefaultFormat:  output.NoneFormat,
+		Command:         login,
+		FlagsResolver:   newLoginFlags,
+		ActionResolver:  newLoginAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:   output.NoneFormat,
 	})
 
 	//deprecate:cmd hide logout
@@ -169,7 +246,7 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			Command:        newRestoreCmd(),
 			FlagsResolver:  newRestoreFlags,
 			ActionResolver: newRestoreAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+			OutputFormats:  []output.Format{output.JsonFormat, output.EventsFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
 				Description: getCmdRestoreHelpDescription,
@@ -179,6 +256,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupConfig,
 			},
 		}).
+		UseMiddleware("concurrency", middleware.NewConcurrencyMiddleware).
+		UseMiddleware("dependencycache", middleware.NewDependencyCacheMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
@@ -186,18 +265,20 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			Command:        newBuildCmd(),
 			FlagsResolver:  newBuildFlags,
 			ActionResolver: newBuildAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+			OutputFormats:  []output.Format{output.JsonFormat, output.EventsFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 		}).
+		UseMiddleware("dependencycache", middleware.NewDependencyCacheMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
 		Add("provision", &actions.ActionDescriptorOptions{
-			Command:        newProvisionCmd(),
-			FlagsResolver:  newProvisionFlags,
-			ActionResolver: newProvisionAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-			DefaultFormat:  output.NoneFormat,
+			Command:         newProvisionCmd(),
+			FlagsResolver:   newProvisionFlags,
+			ActionResolver:  newProvisionAction,
+			RequiresNetwork: true,
+			OutputFormats:   []output.Format{output.JsonFormat, output.EventsFormat, output.NoneFormat},
+			DefaultFormat:   output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
 				Description: getCmdProvisionHelpDescription,
 				Footer:      getCmdHelpDefaultFooter,
@@ -206,6 +287,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("dryrun", middleware.NewDryRunMiddleware).
+		UseMiddleware("secrets", middleware.NewSecretsMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
@@ -213,7 +296,7 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			Command:        newPackageCmd(),
 			FlagsResolver:  newPackageFlags,
 			ActionResolver: newPackageAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+			OutputFormats:  []output.Format{output.JsonFormat, output.EventsFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
 				Description: getCmdPackageHelpDescription,
@@ -223,15 +306,18 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("concurrency", middleware.NewConcurrencyMiddleware).
+		UseMiddleware("dependencycache", middleware.NewDependencyCacheMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
 		Add("deploy", &actions.ActionDescriptorOptions{
-			Command:        newDeployCmd(),
-			FlagsResolver:  newDeployFlags,
-			ActionResolver: newDeployAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-			DefaultFormat:  output.NoneFormat,
+			Command:         newDeployCmd(),
+			FlagsResolver:   newDeployFlags,
+			ActionResolver:  newDeployAction,
+			RequiresNetwork: true,
+			OutputFormats:   []output.Format{output.JsonFormat, output.EventsFormat, output.NoneFormat},
+			DefaultFormat:   output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
 				Description: getCmdDeployHelpDescription,
 				Footer:      getCmdDeployHelpFooter,
@@ -240,15 +326,20 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("concurrency", middleware.NewConcurrencyMiddleware).
+		UseMiddleware("dependencycache", middleware.NewDependencyCacheMiddleware).
+		UseMiddleware("dryrun", middleware.NewDryRunMiddleware).
+		UseMiddleware("secrets", middleware.NewSecretsMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
 		Add("up", &actions.ActionDescriptorOptions{
-			Command:        newUpCmd(),
-			FlagsResolver:  newUpFlags,
-			ActionResolver: newUpAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-			DefaultFormat:  output.NoneFormat,
+			Command:         newUpCmd(),
+			FlagsResolver:   newUpFlags,
+			ActionResolver:  newUpAction,
+			RequiresNetwork: true,
+			OutputFormats:   []output.Format{output.JsonFormat, output.EventsFormat, output.NoneFormat},
+			DefaultFormat:   output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
 				Description: getCmdUpHelpDescription,
 			},
@@ -256,12 +347,18 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("concurrency", middleware.NewConcurrencyMiddleware).
+		UseMiddleware("dependencycache", middleware.NewDependencyCacheMiddleware).
+		UseMiddleware("secrets", middleware.NewSecretsMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
-	root.Add("monitor", &actions.ActionDescriptorOptions{
-		Command:        newMonitorCmd(),
-		FlagsResolver:  newMonitorFlags,
-		ActionResolver: newMonitorAction,
+	monitor := root.Add("monitor", &actions.ActionDescriptorOptions{
+		Command:         newMonitorCmd(),
+		FlagsResolver:   newMonitorFlags,
+		ActionResolver:  newMonitorAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.TableFormat, output.NoneFormat},
+		DefaultFormat:   output.NoneFormat,
 		HelpOptions: actions.ActionHelpOptions{
 			Description: getCmdMonitorHelpDescription,
 			Footer:      getCmdMonitorHelpFooter,
@@ -270,14 +367,125 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			RootLevelHelp: actions.CmdGroupMonitor,
 		},
 	})
+	monitorAlertsActions(monitor)
+
+	root.Add("metrics", &actions.ActionDescriptorOptions{
+		Command:         newMetricsCmd(),
+		FlagsResolver:   newMetricsFlags,
+		ActionResolver:  newMetricsAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:   output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdMetricsHelpDescription,
+			Footer:      getCmdMetricsHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	root.Add("logs", &actions.ActionDescriptorOptions{
+		Command:         newLogsCmd(),
+		FlagsResolver:   newLogsFlags,
+		ActionResolver:  newLogsAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:   output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdLogsHelpDescription,
+			Footer:      getCmdLogsHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	costActions(root)
+	devboxActions(root)
+
+	root.Add("devcontainer", &actions.ActionDescriptorOptions{
+		Command:        newDevcontainerCmd(),
+		FlagsResolver:  newDevcontainerFlags,
+		ActionResolver: newDevcontainerAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDevcontainerHelpDescription,
+			Footer:      getCmdDevcontainerHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	root.Add("tunnel", &actions.ActionDescriptorOptions{
+		Command:         newTunnelCmd(),
+		FlagsResolver:   newTunnelFlags,
+		ActionResolver:  newTunnelAction,
+		RequiresNetwork: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdTunnelHelpDescription,
+			Footer:      getCmdTunnelHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	root.Add("proxy", &actions.ActionDescriptorOptions{
+		Command:         newProxyCmd(),
+		FlagsResolver:   newProxyFlags,
+		ActionResolver:  newProxyAction,
+		RequiresNetwork: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdProxyHelpDescription,
+			Footer:      getCmdProxyHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	root.
+		Add("test", &actions.ActionDescriptorOptions{
+			Command:        newServiceTestCmd(),
+			FlagsResolver:  newServiceTestFlags,
+			ActionResolver: newServiceTestAction,
+			OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+			DefaultFormat:  output.TableFormat,
+			HelpOptions: actions.ActionHelpOptions{
+				Description: getCmdTestHelpDescription,
+				Footer:      getCmdTestHelpFooter,
+			},
+			GroupingOptions: actions.CommandGroupOptions{
+				RootLevelHelp: actions.CmdGroupMonitor,
+			},
+		}).
+		UseMiddleware("hooks", middleware.NewHooksMiddleware)
+
+	root.Add("health", &actions.ActionDescriptorOptions{
+		Command:         newHealthCmd(),
+		FlagsResolver:   newHealthFlags,
+		ActionResolver:  newHealthAction,
+		RequiresNetwork: true,
+		OutputFormats:   []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:   output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdHealthHelpDescription,
+			Footer:      getCmdHealthHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
 
 	root.
 		Add("down", &actions.ActionDescriptorOptions{
-			Command:        newDownCmd(),
-			FlagsResolver:  newDownFlags,
-			ActionResolver: newDownAction,
-			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
-			DefaultFormat:  output.NoneFormat,
+			Command:         newDownCmd(),
+			FlagsResolver:   newDownFlags,
+			ActionResolver:  newDownAction,
+			RequiresNetwork: true,
+			OutputFormats:   []output.Format{output.JsonFormat, output.EventsFormat, output.NoneFormat},
+			DefaultFormat:   output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
 				Description: getCmdDownHelpDescription,
 				Footer:      getCmdDownHelpFooter,
@@ -286,23 +494,39 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("dryrun", middleware.NewDryRunMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
+	// Container registrations are lazy, only the dependencies of the invoked command are constructed.
+	// The extensions manager is shared so installed extensions are only read once per invocation.
+	registerCommonDependencies(ioc.Global)
+
+	// Extension commands are registered after all built-in commands to detect name conflicts
+	extensionCommands(root, ioc.Global)
+
 	// Register any global middleware defined by the caller
-	if len(middlewareChain) > 0 {
-		for _, registration := range middlewareChain {
-			root.UseMiddlewareWhen(registration.Name, registration.Resolver, registration.Predicate)
-		}
-	}
+	useMiddlewareRegistrations(root, middlewareChain)
 
-	// Global middleware registration
+	// Global middleware registration, with the middleware of the registry inserted at their stages
+	useMiddlewareRegistrations(root, middleware.Registered(middleware.StageFirst))
 	root.
+		UseMiddleware("output", middleware.NewOutputMiddleware).
 		UseMiddleware("debug", middleware.NewDebugMiddleware).
+		UseMiddlewareWhen("offline", middleware.NewOfflineMiddleware, func(descriptor *actions.ActionDescriptor) bool {
+			return descriptor.Options.RequiresNetwork
+		})
+	useMiddlewareRegistrations(root, middleware.Registered(middleware.StageBeforeTelemetry))
+	root.
 		UseMiddlewareWhen("telemetry", middleware.NewTelemetryMiddleware, func(descriptor *actions.ActionDescriptor) bool {
 			return !descriptor.Options.DisableTelemetry
 		})
+	useMiddlewareRegistrations(root, middleware.Registered(middleware.StageAfterTelemetry))
+	root.
+		UseMiddleware("httptrace", middleware.NewHttpTraceMiddleware).
+		UseMiddleware("timeout", middleware.NewTimeoutMiddleware).
+		UseMiddleware("retry", middleware.NewRetryMiddleware)
+	useMiddlewareRegistrations(root, middleware.Registered(middleware.StageBeforeAction))
 
-	registerCommonDependencies(ioc.Global)
 	cobraBuilder := NewCobraBuilder(ioc.Global)
 
 	// Compose the hierarchy of action descriptions into cobra commands
@@ -315,13 +539,13 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 	}
 
 	// The help template has to be set after calling `BuildCommand()` to ensure the command tree is built
-	cmd.SetHelpTemplate(generateCmdHelp(
+	setCmdHelp(
 		cmd,
 		generateCmdHelpOptions{
 			Description: getCmdHelpDefaultDescription,
 			Commands:    func(c *cobra.Command) string { return getCmdHelpGroupedCommands(getCmdRootHelpCommands(c)) },
 			Footer:      getCmdRootHelpFooter,
-		}))
+		})
 
 	return cmd
 }
@@ -373,9 +597,21 @@ func getCmdRootHelpCommands(cmd *cobra.Command) (result string) {
 
 	var paragraph []string
 	for _, title := range groups {
+		// Skip groups without any commands. (ex. when no extensions are installed)
+		if len(commandGroups[string(title)]) == 0 {
+			continue
+		}
+
 		paragraph = append(paragraph, fmt.Sprintf("  %s\n    %s\n",
 			output.WithBold(string(title)),
 			strings.Join(commandGroups[string(title)], "\n    ")))
 	}
 	return strings.Join(paragraph, "\n")
 }
+
+// useMiddlewareRegistrations registers the middleware components to be run for all actions, in order
+func useMiddlewareRegistrations(root *actions.ActionDescriptor, registrations []*actions.MiddlewareRegistration) {
+	for _, registration := range registrations {
+		root.UseMiddlewareWhen(registration.Name, registration.Resolver, registration.Predicate)
+	}
+}
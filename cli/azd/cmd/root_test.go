@@ -0,0 +1,24 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+// BenchmarkNewRootCmd measures building the command tree, which azd does on every run before executing the command.
+func BenchmarkNewRootCmd(b *testing.B) {
+	b.Setenv("AZD_CONFIG_DIR", b.TempDir())
+
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewRootCmd(false, nil)
+	}
+}
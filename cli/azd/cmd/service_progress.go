@@ -0,0 +1,286 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// The user config path of the maximum number of services packaged or deployed at the same time
+const serviceParallelismConfigPath = "services.parallelism"
+
+// The number of services packaged or deployed at the same time when not configured
+const defaultServiceParallelism = 4
+
+// Gets the maximum number of services packaged or deployed at the same time, set by --parallelism or configured with
+// `azd config set services.parallelism <number>`. A value of 1 runs services sequentially.
+func serviceParallelism(ctx context.Context, userConfigManager config.UserConfigManager) (int, error) {
+	if parallelism, has := middleware.Parallelism(ctx); has {
+		return parallelism, nil
+	}
+
+	azdConfig, err := userConfigManager.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	value, has := azdConfig.Get(serviceParallelismConfigPath)
+	if !has {
+		return defaultServiceParallelism, nil
+	}
+
+	parallelism, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil || parallelism < 1 {
+		return 0, fmt.Errorf("'%s' must be a number greater than 0, got '%v'", serviceParallelismConfigPath, value)
+	}
+
+	return parallelism, nil
+}
+
+// Gets the parallelism to run the services with. Interactive hooks read from & write to the terminal directly, so
+// services are run one at a time when any of them has an interactive hook.
+func hooksParallelism(services []*project.ServiceConfig, parallelism int) int {
+	for _, svc := range services {
+		for _, hookConfigs := range svc.Hooks {
+			for _, hookConfig := range hookConfigs {
+				if hookConfig == nil {
+					continue
+				}
+
+				if hookConfig.Interactive ||
+					(hookConfig.Windows != nil && hookConfig.Windows.Interactive) ||
+					(hookConfig.Posix != nil && hookConfig.Posix.Interactive) {
+					log.Printf("running services sequentially, service '%s' has an interactive hook\n", svc.Name)
+					return 1
+				}
+			}
+		}
+	}
+
+	return parallelism
+}
+
+// serviceProgress multiplexes the progress of services running at the same time onto the progress display of the
+// console. A single service is displayed like a sequential run, multiple services are displayed as one step.
+type serviceProgress struct {
+	progress input.Progress
+	// The verb displayed for the operation. (ex. `Deploying`)
+	verb string
+	// The kind of items displayed. (ex. `service`)
+	kind string
+
+	mu       sync.Mutex
+	running  []string
+	messages map[string]project.ServiceProgress
+	// The title of the step displayed
+	title string
+
+	// The lifecycle events of the services with `--output events`, and the operation of their type. (ex. `deploy`)
+	events    *input.LifecycleEvents
+	operation string
+}
+
+func newServiceProgress(console input.Console, verb string) *serviceProgress {
+	return newItemProgress(console, verb, "service")
+}
+
+// Creates a progress display for other kinds of items running at the same time. (ex. `environment`)
+func newItemProgress(console input.Console, verb string, kind string) *serviceProgress {
+	return &serviceProgress{
+		progress: input.NewProgress(console),
+		verb:     verb,
+		kind:     kind,
+		messages: map[string]project.ServiceProgress{},
+	}
+}
+
+// Writes the lifecycle events of the services for the operation, ex. service.deploy.start for `deploy`
+func (p *serviceProgress) withEvents(events *input.LifecycleEvents, operation string) *serviceProgress {
+	p.events = events
+	p.operation = operation
+	return p
+}
+
+// Starts displaying the progress of the service
+func (p *serviceProgress) Start(ctx context.Context, serviceName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running = append(p.running, serviceName)
+	p.emit(contracts.LifecyclePhaseStart, contracts.LifecycleEvent{Service: serviceName})
+	p.refresh(ctx)
+}
+
+// Updates the latest progress message of the service
+func (p *serviceProgress) Report(ctx context.Context, serviceName string, progress project.ServiceProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages[serviceName] = progress
+	p.emit(contracts.LifecyclePhaseProgress, contracts.LifecycleEvent{
+		Service: serviceName,
+		Message: progress.Message,
+		Percent: progress.Percent,
+	})
+	p.refresh(ctx)
+}
+
+// Drains the progress reported by the service task until the channel is closed
+func (p *serviceProgress) Track(ctx context.Context, serviceName string, progress <-chan project.ServiceProgress) {
+	for message := range progress {
+		p.Report(ctx, serviceName, message)
+	}
+}
+
+// Stops displaying the progress of the service with the final status of the service.
+// The optional complete func is invoked while no spinner is running to display the service results.
+func (p *serviceProgress) Stop(
+	ctx context.Context,
+	serviceName string,
+	format input.SpinnerUxType,
+	complete func(),
+) {
+	p.stop(ctx, serviceName, p.stepMessage(serviceName), format, complete)
+}
+
+// Stops displaying the progress of the service that was skipped for the specified reason. (ex. `up-to-date`)
+func (p *serviceProgress) Skip(ctx context.Context, serviceName string, reason string, complete func()) {
+	p.stop(ctx, serviceName, fmt.Sprintf("%s (%s)", p.stepMessage(serviceName), reason), input.StepSkipped, complete)
+}
+
+func (p *serviceProgress) stop(
+	ctx context.Context,
+	serviceName string,
+	message string,
+	format input.SpinnerUxType,
+	complete func(),
+) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, name := range p.running {
+		if name == serviceName {
+			p.running = append(p.running[:i], p.running[i+1:]...)
+			break
+		}
+	}
+	delete(p.messages, serviceName)
+
+	phase := contracts.LifecyclePhaseDone
+	switch format {
+	case input.StepFailed:
+		phase = contracts.LifecyclePhaseFailed
+	case input.StepSkipped:
+		phase = contracts.LifecyclePhaseSkipped
+	}
+	p.emit(phase, contracts.LifecycleEvent{Service: serviceName, Message: message})
+
+	p.title = ""
+	p.progress.Stop(ctx, message, format)
+	if complete != nil {
+		complete()
+	}
+
+	if len(p.running) > 0 {
+		p.refresh(ctx)
+	}
+}
+
+// Writes the lifecycle event of the operation of a service, when the events are written. Must be called while holding
+// the lock.
+func (p *serviceProgress) emit(phase string, data contracts.LifecycleEvent) {
+	if p.events != nil {
+		p.events.Emit("service "+p.operation, phase, data)
+	}
+}
+
+func (p *serviceProgress) stepMessage(serviceName string) string {
+	return fmt.Sprintf("%s %s %s", p.verb, p.kind, serviceName)
+}
+
+// Displays the current progress of all running services. Must be called while holding the lock.
+func (p *serviceProgress) refresh(ctx context.Context) {
+	if len(p.running) == 0 {
+		return
+	}
+
+	if len(p.running) == 1 {
+		serviceName := p.running[0]
+		progress := p.messages[serviceName]
+		p.show(ctx, p.stepMessage(serviceName), progress.Message, progress.Percent)
+		return
+	}
+
+	// The completion displayed is the lowest completion known, so progress displays throttling the reports of
+	// percentages don't display each change of the combined message
+	services := make([]string, 0, len(p.running))
+	percent := 0
+	for _, serviceName := range p.running {
+		progress := p.messages[serviceName]
+		if progress.Message != "" {
+			services = append(services, fmt.Sprintf("%s: %s", serviceName, progress.Message))
+		} else {
+			services = append(services, serviceName)
+		}
+
+		if progress.Percent > 0 && (percent == 0 || progress.Percent < percent) {
+			percent = progress.Percent
+		}
+	}
+
+	p.show(ctx, fmt.Sprintf("%s %ss", p.verb, p.kind), strings.Join(services, ", "), percent)
+}
+
+// Displays the step with the title, starting it when the title changed. Must be called while holding the lock.
+func (p *serviceProgress) show(ctx context.Context, title string, message string, percent int) {
+	if title != p.title {
+		p.title = title
+		p.progress.Start(ctx, title)
+	}
+
+	if message != "" {
+		p.progress.Report(ctx, message, percent)
+	}
+}
+
+// Combines the errors of services run concurrently. A single failure is returned as is.
+func joinServiceErrors(services []*project.ServiceConfig, errs []error) error {
+	names := make([]string, len(services))
+	for i, svc := range services {
+		names[i] = svc.Name
+	}
+
+	return joinItemErrors("service", names, errs)
+}
+
+// Combines the errors of items of the specified kind run concurrently. A single failure is returned as is.
+func joinItemErrors(kind string, names []string, errs []error) error {
+	failures := []error{}
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s '%s': %w", kind, names[i], err))
+		}
+	}
+
+	switch len(failures) {
+	case 0:
+		return nil
+	case 1:
+		return errors.Unwrap(failures[0])
+	default:
+		return errors.Join(failures...)
+	}
+}
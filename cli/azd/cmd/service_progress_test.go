@@ -0,0 +1,48 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooksParallelism(t *testing.T) {
+	tests := map[string]struct {
+		hooks    ext.HooksConfig
+		expected int
+	}{
+		"NoHooks": {
+			expected: 4,
+		},
+		"NonInteractiveHook": {
+			hooks:    ext.HooksConfig{"predeploy": {{Run: "echo 'Hello'"}}},
+			expected: 4,
+		},
+		"InteractiveHook": {
+			hooks:    ext.HooksConfig{"predeploy": {{Run: "read name", Interactive: true}}},
+			expected: 1,
+		},
+		"InteractivePlatformHook": {
+			hooks: ext.HooksConfig{"postpackage": {{
+				Posix: &ext.HookConfig{Run: "read name", Interactive: true},
+			}}},
+			expected: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			services := []*project.ServiceConfig{
+				{Name: "api"},
+				{Name: "web", Hooks: test.hooks},
+			}
+
+			require.Equal(t, test.expected, hooksParallelism(services, 4))
+		})
+	}
+}
@@ -8,28 +8,41 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type showFlags struct {
-	global *internal.GlobalCommandOptions
+	global  *internal.GlobalCommandOptions
+	refresh bool
 	envFlag
 }
 
 func (s *showFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	s.envFlag.Bind(local, global)
+	local.BoolVar(
+		&s.refresh,
+		"refresh",
+		false,
+		"Queries Azure for the resources and the endpoints of the services instead of using the last known values.",
+	)
 	s.global = global
 }
 
@@ -42,24 +55,30 @@ func newShowFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *sh
 
 func newShowCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:    "show --output json",
-		Short:  "Display information about your app and its resources.",
-		Hidden: true,
+		Use:   "show",
+		Short: "Display the status of your project, its environment, resources and services.",
+		Args:  cobra.NoArgs,
 	}
 
 	return cmd
 }
 
 type showAction struct {
-	projectConfig   *project.ProjectConfig
-	resourceManager project.ResourceManager
-	console         input.Console
-	formatter       output.Formatter
-	writer          io.Writer
-	azCli           azcli.AzCli
-	azdCtx          *azdcontext.AzdContext
-	env             *environment.Environment
-	flags           *showFlags
+	projectConfig        *project.ProjectConfig
+	resourceManager      project.ResourceManager
+	serviceManager       project.ServiceManager
+	subscriptionsManager *account.SubscriptionsManager
+	console              input.Console
+	formatter            output.Formatter
+	writer               io.Writer
+	azCli                azcli.AzCli
+	azdCtx               *azdcontext.AzdContext
+	env                  *environment.Environment
+	flags                *showFlags
+
+	// The resource group of the environment, resolved once
+	resourceGroup    string
+	hasResourceGroup bool
 }
 
 func newShowAction(
@@ -69,29 +88,46 @@ func newShowAction(
 	azCli azcli.AzCli,
 	projectConfig *project.ProjectConfig,
 	resourceManager project.ResourceManager,
+	serviceManager project.ServiceManager,
+	subscriptionsManager *account.SubscriptionsManager,
 	azdCtx *azdcontext.AzdContext,
 	env *environment.Environment,
 	flags *showFlags,
 ) actions.Action {
 	return &showAction{
-		projectConfig:   projectConfig,
-		resourceManager: resourceManager,
-		console:         console,
-		formatter:       formatter,
-		writer:          writer,
-		azCli:           azCli,
-		azdCtx:          azdCtx,
-		env:             env,
-		flags:           flags,
+		projectConfig:        projectConfig,
+		resourceManager:      resourceManager,
+		serviceManager:       serviceManager,
+		subscriptionsManager: subscriptionsManager,
+		console:              console,
+		formatter:            formatter,
+		writer:               writer,
+		azCli:                azCli,
+		azdCtx:               azdCtx,
+		env:                  env,
+		flags:                flags,
 	}
 }
 
 func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	provider := s.projectConfig.Infra.Provider
+	if provider == "" {
+		provider = provisioning.Bicep
+	}
+
 	res := contracts.ShowResult{
-		Name:     s.projectConfig.Name,
-		Services: make(map[string]contracts.ShowService, len(s.projectConfig.Services)),
+		Name: s.projectConfig.Name,
+		Path: s.projectConfig.Path,
+		Infra: &contracts.ShowInfra{
+			Provider: string(provider),
+			Path:     s.projectConfig.Infra.Path,
+		},
+		Environment: s.environment(ctx),
+		Services:    make(map[string]contracts.ShowService, len(s.projectConfig.Services)),
 	}
+	res.Resources = s.resources(ctx)
 
+	deployments := project.NewServiceDeployments(s.env).Get()
 	for name, svc := range s.projectConfig.Services {
 		path, err := getFullPathToProjectForService(svc)
 		if err != nil {
@@ -103,6 +139,20 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 				Path: path,
 				Type: showTypeFromLanguage(svc.Language),
 			},
+			Host: string(svc.Host),
+		}
+
+		if deployment, has := deployments[name]; has {
+			showSvc.Endpoints = deployment.Endpoints
+			showSvc.LastDeployed = &deployment.DeployedOn
+		}
+
+		if s.flags.refresh && s.env.GetSubscriptionId() != "" {
+			if endpoints, err := s.serviceEndpoints(ctx, svc); err != nil {
+				log.Printf("ignoring error determining endpoints for service %s: %v", name, err)
+			} else {
+				showSvc.Endpoints = endpoints
+			}
 		}
 
 		res.Services[name] = showSvc
@@ -110,39 +160,295 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	// Add information about the target of each service, if we can determine it (if the infrastructure has
 	// not been deployed, for example, we'll just not include target information)
-	if subId := s.env.GetSubscriptionId(); subId != "" {
-		resourceManager := infra.NewAzureResourceManager(s.azCli)
-		envName := s.env.GetEnvName()
-
-		rgName, err := resourceManager.FindResourceGroupForEnvironment(ctx, subId, envName)
-		if err == nil {
-			for svcName, serviceConfig := range s.projectConfig.Services {
-				if resources, err := s.resourceManager.GetServiceResources(ctx, subId, rgName, serviceConfig); err == nil {
-					resourceIds := make([]string, len(resources))
-					for idx, res := range resources {
-						resourceIds[idx] = res.Id
-					}
-
-					resSvc := res.Services[svcName]
-					resSvc.Target = &contracts.ShowTargetArm{
-						ResourceIds: resourceIds,
-					}
-					res.Services[svcName] = resSvc
-				} else {
-					log.Printf("ignoring error determining resource id for service %s: %v", svcName, err)
-				}
+	for svcName, resourceIds := range s.serviceResources(ctx) {
+		if resSvc, has := res.Services[svcName]; has {
+			resSvc.Target = &contracts.ShowTargetArm{
+				ResourceIds: resourceIds,
 			}
-		} else {
+			res.Services[svcName] = resSvc
+		}
+	}
+
+	if s.formatter.Kind() == output.TableFormat {
+		return nil, s.writeTables(res)
+	}
+
+	return nil, s.formatter.Format(res, s.writer, nil)
+}
+
+// environment returns the selected environment, with the name of its subscription and its resource group once it's
+// provisioned
+func (s *showAction) environment(ctx context.Context) *contracts.ShowEnvironment {
+	showEnv := &contracts.ShowEnvironment{
+		Name:           s.env.GetEnvName(),
+		SubscriptionId: s.env.GetSubscriptionId(),
+		Location:       s.env.GetLocation(),
+	}
+
+	if showEnv.SubscriptionId == "" {
+		return showEnv
+	}
+
+	if subscription, err := s.subscriptionsManager.GetSubscription(ctx, showEnv.SubscriptionId); err != nil {
+		log.Printf("ignoring error getting subscription %s: %v", showEnv.SubscriptionId, err)
+	} else {
+		showEnv.SubscriptionName = subscription.Name
+	}
+
+	showEnv.ResourceGroup = s.resourceGroupName(ctx)
+	return showEnv
+}
+
+// resourceGroupName returns the resource group of the environment, or an empty string when it can't be determined
+func (s *showAction) resourceGroupName(ctx context.Context) string {
+	if !s.hasResourceGroup {
+		s.hasResourceGroup = true
+
+		rgName, err := s.resourceManager.GetResourceGroupName(ctx, s.env.GetSubscriptionId(), s.projectConfig)
+		if err != nil {
 			log.Printf(
 				"ignoring error determining resource group for environment %s, resource ids will not be available: %v",
 				s.env.GetEnvName(),
 				err)
 		}
+
+		s.resourceGroup = rgName
+	}
+
+	return s.resourceGroup
+}
+
+// resources summarizes the resources provisioned in the environment. The resources of the last provision are used
+// unless a refresh is requested, in which case the resources of the resource group are queried from Azure.
+func (s *showAction) resources(ctx context.Context) []contracts.ShowResourceSummary {
+	resourceIds := []string{}
+
+	cachedState, hasCache := provisioning.NewStateCache(s.env).Get()
+	if !s.flags.refresh && hasCache && len(cachedState.Resources) > 0 {
+		for _, resource := range cachedState.Resources {
+			resourceIds = append(resourceIds, resource.Id)
+		}
+	} else if subId := s.env.GetSubscriptionId(); subId != "" {
+		rgName := s.resourceGroupName(ctx)
+		if rgName == "" {
+			return nil
+		}
+
+		resources, err := s.azCli.ListResourceGroupResources(ctx, subId, rgName, nil)
+		if err != nil {
+			log.Printf("ignoring error listing resources of resource group %s: %v", rgName, err)
+			return nil
+		}
+
+		for _, resource := range resources {
+			resourceIds = append(resourceIds, resource.Id)
+		}
+	}
+
+	return summarizeResources(resourceIds)
+}
+
+// serviceEndpoints queries the endpoints of the service from its target resource
+func (s *showAction) serviceEndpoints(ctx context.Context, svc *project.ServiceConfig) ([]string, error) {
+	targetResource, err := s.resourceManager.GetTargetResource(ctx, s.env.GetSubscriptionId(), svc)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceTarget, err := s.serviceManager.GetServiceTarget(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	return serviceTarget.Endpoints(ctx, svc, targetResource)
+}
+
+// writeTables writes the project and its environment, followed by a table of the services and a table of the
+// resources provisioned
+func (s *showAction) writeTables(res contracts.ShowResult) error {
+	fmt.Fprintf(s.writer, "Project: %s (%s)\n", res.Name, res.Path)
+	fmt.Fprintf(s.writer, "Infrastructure: %s (%s)\n", res.Infra.Provider, res.Infra.Path)
+	fmt.Fprintf(s.writer, "Environment: %s\n", res.Environment.Name)
+
+	if res.Environment.SubscriptionId == "" {
+		fmt.Fprintf(s.writer, "\nThe environment hasn't been provisioned, run %s to provision it.\n",
+			output.WithHighLightFormat("azd provision"))
 	} else {
+		subscription := res.Environment.SubscriptionId
+		if res.Environment.SubscriptionName != "" {
+			subscription = fmt.Sprintf("%s (%s)", res.Environment.SubscriptionName, res.Environment.SubscriptionId)
+		}
+
+		fmt.Fprintf(s.writer, "Subscription: %s\n", subscription)
+		fmt.Fprintf(s.writer, "Location: %s\n", res.Environment.Location)
+		fmt.Fprintf(s.writer, "Resource group: %s\n", res.Environment.ResourceGroup)
+	}
+
+	if len(res.Services) > 0 {
+		fmt.Fprintln(s.writer)
+		if err := s.formatter.Format(showServiceRows(res.Services), s.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "SERVICE", ValueTemplate: "{{.Name}}"},
+				{Heading: "LANGUAGE", ValueTemplate: "{{.Language}}"},
+				{Heading: "HOST", ValueTemplate: "{{.Host}}"},
+				{Heading: "ENDPOINT", ValueTemplate: "{{.Endpoint}}"},
+				{Heading: "LAST DEPLOYED", ValueTemplate: "{{.LastDeployed}}"},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(res.Resources) > 0 {
+		fmt.Fprintln(s.writer)
+		if err := s.formatter.Format(res.Resources, s.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "RESOURCE TYPE", ValueTemplate: "{{if .DisplayName}}{{.DisplayName}}{{else}}{{.Type}}{{end}}"},
+				{Heading: "COUNT", ValueTemplate: "{{.Count}}"},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// showServiceRow is a row of the services table of `azd show`
+type showServiceRow struct {
+	Name         string
+	Language     string
+	Host         string
+	Endpoint     string
+	LastDeployed string
+}
+
+// showServiceRows returns the rows of the services table sorted by name, the services never deployed are marked as such
+func showServiceRows(services map[string]contracts.ShowService) []showServiceRow {
+	names := maps.Keys(services)
+	slices.Sort(names)
+
+	rows := make([]showServiceRow, 0, len(names))
+	for _, name := range names {
+		svc := services[name]
+		row := showServiceRow{
+			Name:         name,
+			Language:     string(svc.Project.Type),
+			Host:         svc.Host,
+			Endpoint:     strings.Join(svc.Endpoints, ", "),
+			LastDeployed: "never",
+		}
+
+		if row.Endpoint == "" {
+			row.Endpoint = "-"
+		}
+
+		if svc.LastDeployed != nil {
+			row.LastDeployed = svc.LastDeployed.Local().Format("2006-01-02 15:04:05")
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// summarizeResources counts the resources by type, sorted by type
+func summarizeResources(resourceIds []string) []contracts.ShowResourceSummary {
+	counts := map[string]int{}
+	for _, id := range resourceIds {
+		resourceId, err := arm.ParseResourceID(id)
+		if err != nil {
+			log.Printf("ignoring invalid resource id %s: %v", id, err)
+			continue
+		}
+
+		counts[resourceId.ResourceType.String()]++
+	}
+
+	types := maps.Keys(counts)
+	slices.Sort(types)
+
+	summary := make([]contracts.ShowResourceSummary, 0, len(types))
+	for _, resourceType := range types {
+		summary = append(summary, contracts.ShowResourceSummary{
+			Type:        resourceType,
+			DisplayName: infra.GetResourceTypeDisplayName(infra.AzureResourceType(resourceType)),
+			Count:       counts[resourceType],
+		})
+	}
+
+	return summary
+}
+
+// serviceResources returns the ids of the resources hosting each service. The last known resources are used unless
+// a refresh is requested, in which case the resources are queried from Azure and cached for subsequent runs.
+func (s *showAction) serviceResources(ctx context.Context) map[string][]string {
+	stateCache := provisioning.NewStateCache(s.env)
+	cachedState, hasCache := stateCache.Get()
+	if !s.flags.refresh && hasCache && cachedState.ServiceResources != nil {
+		log.Printf("using service resources cached on %s", cachedState.UpdatedOn.Format(time.RFC3339))
+		return cachedState.ServiceResources
+	}
+
+	subId := s.env.GetSubscriptionId()
+	if subId == "" {
 		log.Printf("provision has not been run, resource ids will not be available")
+		return nil
 	}
 
-	return nil, s.formatter.Format(res, s.writer, nil)
+	rgName := s.resourceGroupName(ctx)
+	if rgName == "" {
+		return nil
+	}
+
+	var err error
+	serviceResources := map[string][]string{}
+	complete := true
+	for svcName, serviceConfig := range s.projectConfig.Services {
+		svcSubId, svcRgName := subId, rgName
+		if !serviceConfig.Subscription.Empty() || !serviceConfig.ResourceGroupName.Empty() {
+			svcSubId, err = s.resourceManager.GetServiceSubscriptionId(serviceConfig)
+			if err == nil {
+				svcRgName, err = s.resourceManager.GetServiceResourceGroupName(ctx, svcSubId, serviceConfig)
+			}
+
+			if err != nil {
+				log.Printf("ignoring error determining resource group for service %s: %v", svcName, err)
+				complete = false
+				continue
+			}
+		}
+
+		resources, err := s.resourceManager.GetServiceResources(ctx, svcSubId, svcRgName, serviceConfig)
+		if err != nil {
+			log.Printf("ignoring error determining resource id for service %s: %v", svcName, err)
+			complete = false
+			continue
+		}
+
+		resourceIds := make([]string, len(resources))
+		for idx, res := range resources {
+			resourceIds[idx] = res.Id
+		}
+
+		serviceResources[svcName] = resourceIds
+	}
+
+	// Only cache complete results so services that failed to resolve are queried again next time
+	if complete {
+		if !hasCache {
+			cachedState = &provisioning.CachedState{}
+		}
+
+		cachedState.UpdatedOn = time.Time{}
+		cachedState.ServiceResources = serviceResources
+		if err := stateCache.Set(cachedState); err != nil {
+			log.Printf("failed caching service resources: %v", err)
+		}
+	}
+
+	return serviceResources
 }
 
 func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowType {
@@ -155,6 +461,8 @@ func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowTy
 		return contracts.ShowTypeNode
 	case project.ServiceLanguageJava:
 		return contracts.ShowTypeJava
+	case project.ServiceLanguageRust:
+		return contracts.ShowTypeRust
 	default:
 		panic(fmt.Sprintf("unknown language %s", language))
 	}
@@ -214,3 +522,25 @@ func getFullPathToProjectForService(svc *project.ServiceConfig) (string, error)
 
 	return svc.Path(), nil
 }
+
+func getCmdShowHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Display the status of your project: its services, the selected environment and its subscription, the"+
+			" resources provisioned, and the endpoints and last deployment of each service.",
+		[]string{
+			formatHelpNote("The resources of the last provision and the endpoints of the last deployment are displayed," +
+				" without querying Azure."),
+			formatHelpNote(fmt.Sprintf("Use %s to query the resources and the endpoints of the services from Azure.",
+				output.WithHighLightFormat("--refresh"))),
+		})
+}
+
+func getCmdShowHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Display the status of the project and its default environment.": output.WithHighLightFormat("azd show"),
+		"Display the status of an environment as JSON.": fmt.Sprintf("%s %s %s",
+			output.WithHighLightFormat("azd show -e"),
+			output.WithWarningFormat("<environment>"),
+			output.WithHighLightFormat("--output json")),
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_summarizeResources(t *testing.T) {
+	rg := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-dev"
+	summary := summarizeResources([]string{
+		rg + "/providers/Microsoft.Web/sites/api",
+		rg + "/providers/Microsoft.App/containerApps/web",
+		rg + "/providers/Microsoft.Web/sites/func",
+		rg + "/providers/Microsoft.Contoso/widgets/widget",
+		"invalid",
+	})
+
+	require.Equal(t, []contracts.ShowResourceSummary{
+		{Type: "Microsoft.App/containerApps", DisplayName: "Container App", Count: 1},
+		{Type: "Microsoft.Contoso/widgets", Count: 1},
+		{Type: "Microsoft.Web/sites", DisplayName: "Web App", Count: 2},
+	}, summary)
+}
+
+func Test_showServiceRows(t *testing.T) {
+	deployedOn := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	rows := showServiceRows(map[string]contracts.ShowService{
+		"web": {
+			Project: contracts.ShowServiceProject{Type: contracts.ShowTypeNode},
+			Host:    "staticwebapp",
+		},
+		"api": {
+			Project:      contracts.ShowServiceProject{Type: contracts.ShowTypePython},
+			Host:         "containerapp",
+			Endpoints:    []string{"https://api.contoso.com/", "https://api.internal/"},
+			LastDeployed: &deployedOn,
+		},
+	})
+
+	require.Equal(t, []showServiceRow{
+		{
+			Name:         "api",
+			Language:     "python",
+			Host:         "containerapp",
+			Endpoint:     "https://api.contoso.com/, https://api.internal/",
+			LastDeployed: deployedOn.Local().Format("2006-01-02 15:04:05"),
+		},
+		{Name: "web", Language: "node", Host: "staticwebapp", Endpoint: "-", LastDeployed: "never"},
+	}, rows)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/spf13/cobra"
 )
 
@@ -18,9 +19,22 @@ const TelemetryUploadCommandFlag = "upload"
 func telemetryActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 	group := root.Add(TelemetryCommandFlag, &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
-			Short:  "Manage telemetry",
-			Long:   "Manage telemetry",
-			Hidden: true,
+			Short: "Inspect the telemetry of azd.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
+	group.Add("show", &actions.ActionDescriptorOptions{
+		Command:        newTelemetryShowCmd(),
+		FlagsResolver:  newTelemetryShowFlags,
+		ActionResolver: newTelemetryShowAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdTelemetryShowHelpDescription,
+			Footer:      getCmdTelemetryShowHelpFooter,
 		},
 	})
 
@@ -31,6 +45,7 @@ func telemetryActions(root *actions.ActionDescriptor) *actions.ActionDescriptor
 			Hidden: true,
 		},
 		ActionResolver:   newUploadAction,
+		RequiresNetwork:  true,
 		DisableTelemetry: true,
 	})
 
@@ -0,0 +1,190 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type telemetryShowFlags struct {
+	global   *internal.GlobalCommandOptions
+	limit    int
+	failed   bool
+	commands bool
+}
+
+func (f *telemetryShowFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.IntVar(&f.limit, "limit", 20, "The maximum number of spans displayed, all the recorded spans when 0.")
+	local.BoolVar(&f.failed, "failed", false, "Only display spans that recorded an error.")
+	local.BoolVar(&f.commands, "commands", false, "Only display the spans of commands, excluding their operations.")
+	f.global = global
+}
+
+func newTelemetryShowFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *telemetryShowFlags {
+	flags := &telemetryShowFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTelemetryShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the telemetry recorded by recent commands.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+// The prefix of the names of the spans of commands, ex. cmd.provision
+const commandSpanPrefix = "cmd."
+
+type telemetrySpanRow struct {
+	Time       string
+	Name       string
+	Duration   string
+	Result     string
+	Attributes string
+}
+
+type telemetryShowAction struct {
+	flags     *telemetryShowFlags
+	console   input.Console
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newTelemetryShowAction(
+	flags *telemetryShowFlags,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &telemetryShowAction{
+		flags:     flags,
+		console:   console,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *telemetryShowAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	store, err := telemetry.LocalSpanStore()
+	if err != nil {
+		return nil, err
+	}
+
+	query := tracing.SpanQuery{Limit: a.flags.limit, Failed: a.flags.failed}
+	if a.flags.commands {
+		query.NamePrefix = commandSpanPrefix
+	}
+
+	spans, err := store.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(spans, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("spans could not be displayed: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	if !telemetry.IsTelemetryEnabled() {
+		a.console.Message(ctx, output.WithGrayFormat(
+			"Telemetry is disabled, these spans were only recorded locally and were not transmitted.\n"))
+	}
+
+	if len(spans) == 0 {
+		a.console.Message(ctx, "No telemetry was recorded yet.")
+		return nil, nil
+	}
+
+	rows := make([]telemetrySpanRow, 0, len(spans))
+	for _, span := range spans {
+		result := "Ok"
+		if span.Failed() {
+			result = span.ErrorCode
+		}
+
+		rows = append(rows, telemetrySpanRow{
+			Time:       span.StartTime.Local().Format(time.DateTime),
+			Name:       span.Name,
+			Duration:   ux.DurationAsText(span.Duration()),
+			Result:     result,
+			Attributes: formatSpanAttributes(span.Attributes),
+		})
+	}
+
+	if err := a.formatter.Format(rows, a.writer, output.TableFormatterOptions{
+		Columns: []output.Column{
+			{Heading: "TIME", ValueTemplate: "{{.Time}}"},
+			{Heading: "SPAN", ValueTemplate: "{{.Name}}"},
+			{Heading: "DURATION", ValueTemplate: "{{.Duration}}"},
+			{Heading: "RESULT", ValueTemplate: "{{.Result}}"},
+			{Heading: "ATTRIBUTES", ValueTemplate: "{{.Attributes}}"},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("spans could not be displayed: %w", err)
+	}
+
+	return nil, nil
+}
+
+// formatSpanAttributes formats the attributes of a span as key=value pairs sorted by key
+func formatSpanAttributes(attributes map[string]any) string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, attributes[key]))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+func getCmdTelemetryShowHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Show the spans recorded by recent commands, with their duration, error codes and usage attributes,"+
+			" to review the telemetry azd transmits.",
+		[]string{
+			formatHelpNote("Spans are recorded locally even when telemetry is disabled, they are only transmitted" +
+				" when telemetry is enabled."),
+			formatHelpNote(fmt.Sprintf("Use %s for the attributes of the process, shared by the spans of a command.",
+				output.WithHighLightFormat("--output json"))),
+			formatHelpNote(fmt.Sprintf("Choose the telemetry transmitted with %s, ex. %s, or disable it with %s.",
+				output.WithHighLightFormat("azd config set telemetry.level <off|errors-only|usage>"),
+				output.WithHighLightFormat("errors-only"),
+				output.WithHighLightFormat("AZURE_DEV_COLLECT_TELEMETRY=no"))),
+		})
+}
+
+func getCmdTelemetryShowHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the spans of recent commands.": output.WithHighLightFormat("azd telemetry show --commands"),
+		"Show the spans that failed.":        output.WithHighLightFormat("azd telemetry show --failed"),
+		"Show every recorded span as JSON.": output.WithHighLightFormat(
+			"azd telemetry show --limit 0 --output json"),
+	})
+}
@@ -5,13 +5,18 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func templateNameCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -57,6 +62,45 @@ func templatesActions(root *actions.ActionDescriptor) *actions.ActionDescriptor
 		DefaultFormat:  output.NoneFormat,
 	})
 
+	group.Add("search", &actions.ActionDescriptorOptions{
+		Command:        newTemplateSearchCmd(),
+		FlagsResolver:  newTemplateSearchFlags,
+		ActionResolver: newTemplateSearchAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdTemplateSearchHelpFooter,
+		},
+	})
+
+	sourceGroup := group.Add("source", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "source",
+			Short: "Manage the galleries templates are searched in.",
+		},
+	})
+
+	sourceGroup.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newTemplateSourceListCmd(),
+		ActionResolver: newTemplateSourceListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	sourceGroup.Add("add", &actions.ActionDescriptorOptions{
+		Command:        newTemplateSourceAddCmd(),
+		FlagsResolver:  newTemplateSourceAddFlags,
+		ActionResolver: newTemplateSourceAddAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdTemplateSourceAddHelpFooter,
+		},
+	})
+
+	sourceGroup.Add("remove", &actions.ActionDescriptorOptions{
+		Command:        newTemplateSourceRemoveCmd(),
+		ActionResolver: newTemplateSourceRemoveAction,
+	})
+
 	return group
 }
 
@@ -159,6 +203,336 @@ func newTemplateShowCmd() *cobra.Command {
 	}
 }
 
+type templateSearchFlags struct {
+	language    string
+	host        string
+	iacProvider string
+	sources     []string
+	global      *internal.GlobalCommandOptions
+}
+
+func (f *templateSearchFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.language, "language", "", "Only show the templates using the language, ex. python.")
+	local.StringVar(&f.host, "host", "", "Only show the templates hosted by the Azure service, ex. containerapp.")
+	local.StringVar(
+		&f.iacProvider, "iac", "", "Only show the templates using the infrastructure provider, bicep or terraform.")
+	local.StringArrayVar(&f.sources, "source", nil, "Only search the template source. Can be repeated.")
+	f.global = global
+}
+
+func newTemplateSearchFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *templateSearchFlags {
+	flags := &templateSearchFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTemplateSearchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use: "search [query]",
+		Short: fmt.Sprintf(
+			"Search the templates of the template sources. %s", output.WithWarningFormat("(Beta)")),
+		Args: cobra.MaximumNArgs(1),
+	}
+}
+
+type templateSearchAction struct {
+	sourceManager *templates.SourceManager
+	flags         *templateSearchFlags
+	console       input.Console
+	formatter     output.Formatter
+	writer        io.Writer
+	args          []string
+}
+
+func newTemplateSearchAction(
+	sourceManager *templates.SourceManager,
+	flags *templateSearchFlags,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	args []string,
+) actions.Action {
+	return &templateSearchAction{
+		sourceManager: sourceManager,
+		flags:         flags,
+		console:       console,
+		formatter:     formatter,
+		writer:        writer,
+		args:          args,
+	}
+}
+
+func (a *templateSearchAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	sources, err := a.sourceManager.Sources(a.flags.sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	options := templates.SearchOptions{
+		Language:    a.flags.language,
+		Host:        a.flags.host,
+		IacProvider: a.flags.iacProvider,
+	}
+	if len(a.args) > 0 {
+		options.Query = a.args[0]
+	}
+
+	// Repository paths listed by several sources are only shown once, from the first source
+	found := []templates.Template{}
+	paths := map[string]struct{}{}
+	failed := 0
+	for _, source := range sources {
+		sourceTemplates, err := source.ListTemplates(ctx)
+		if err != nil {
+			// A source that can't be reached doesn't prevent searching the other sources
+			failed++
+			a.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf("Skipping template source '%s': %v", source.Name(), err),
+			})
+			continue
+		}
+
+		for i := range sourceTemplates {
+			template := sourceTemplates[i]
+			if !options.Matches(&template) {
+				continue
+			}
+
+			path, err := templates.Absolute(template.RepositoryPath)
+			if err != nil {
+				path = template.RepositoryPath
+			}
+
+			if _, has := paths[path]; has {
+				continue
+			}
+
+			paths[path] = struct{}{}
+			found = append(found, template)
+		}
+	}
+
+	if failed > 0 && failed == len(sources) {
+		return nil, errors.New("none of the template sources could be searched")
+	}
+
+	if a.formatter.Kind() != output.TableFormat {
+		return nil, a.formatter.Format(found, a.writer, nil)
+	}
+
+	// Console messages aren't displayed with the table format
+	if len(found) == 0 {
+		_, err := fmt.Fprintln(a.writer, "No templates match the search.")
+		return nil, err
+	}
+
+	columns := []output.Column{
+		{
+			Heading:       "RepositoryPath",
+			ValueTemplate: "{{.RepositoryPath}}",
+		},
+		{
+			Heading:       "Name",
+			ValueTemplate: "{{.Name}}",
+		},
+		{
+			Heading:       "Source",
+			ValueTemplate: "{{.Source}}",
+		},
+	}
+
+	return nil, a.formatter.Format(found, a.writer, output.TableFormatterOptions{
+		Columns: columns,
+	})
+}
+
+func newTemplateSourceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the template sources.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+type templateSourceListAction struct {
+	sourceManager *templates.SourceManager
+	formatter     output.Formatter
+	writer        io.Writer
+}
+
+func newTemplateSourceListAction(
+	sourceManager *templates.SourceManager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &templateSourceListAction{
+		sourceManager: sourceManager,
+		formatter:     formatter,
+		writer:        writer,
+	}
+}
+
+func (a *templateSourceListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	sources, err := a.sourceManager.SourceConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() != output.TableFormat {
+		return nil, a.formatter.Format(sources, a.writer, nil)
+	}
+
+	columns := []output.Column{
+		{
+			Heading:       "NAME",
+			ValueTemplate: "{{.Name}}",
+		},
+		{
+			Heading:       "TYPE",
+			ValueTemplate: "{{.Type}}",
+		},
+		{
+			Heading:       "LOCATION",
+			ValueTemplate: "{{.Location}}",
+		},
+	}
+
+	return nil, a.formatter.Format(sources, a.writer, output.TableFormatterOptions{
+		Columns: columns,
+	})
+}
+
+type templateSourceAddFlags struct {
+	sourceType string
+	global     *internal.GlobalCommandOptions
+}
+
+func (f *templateSourceAddFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.sourceType,
+		"type",
+		"",
+		"The type of the source: url, file or git. Defaults to git for git repositories, url for other urls and "+
+			"file otherwise.",
+	)
+	f.global = global
+}
+
+func newTemplateSourceAddFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *templateSourceAddFlags {
+	flags := &templateSourceAddFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTemplateSourceAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <name> <location>",
+		Short: "Adds a template source.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+type templateSourceAddAction struct {
+	sourceManager *templates.SourceManager
+	flags         *templateSourceAddFlags
+	args          []string
+}
+
+func newTemplateSourceAddAction(
+	sourceManager *templates.SourceManager,
+	flags *templateSourceAddFlags,
+	args []string,
+) actions.Action {
+	return &templateSourceAddAction{
+		sourceManager: sourceManager,
+		flags:         flags,
+		args:          args,
+	}
+}
+
+func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	location := a.args[1]
+	sourceType := a.flags.sourceType
+	if sourceType == "" {
+		sourceType = templates.SourceTypeOf(location)
+	}
+
+	err := a.sourceManager.AddSource(&templates.SourceConfig{
+		Name:     a.args[0],
+		Type:     sourceType,
+		Location: location,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Template source '%s' was added.", a.args[0]),
+		},
+	}, nil
+}
+
+func newTemplateSourceRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Removes a template source.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type templateSourceRemoveAction struct {
+	sourceManager *templates.SourceManager
+	args          []string
+}
+
+func newTemplateSourceRemoveAction(sourceManager *templates.SourceManager, args []string) actions.Action {
+	return &templateSourceRemoveAction{
+		sourceManager: sourceManager,
+		args:          args,
+	}
+}
+
+func (a *templateSourceRemoveAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if err := a.sourceManager.RemoveSource(a.args[0]); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Template source '%s' was removed.", a.args[0]),
+		},
+	}, nil
+}
+
+func getCmdTemplateSearchHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Search the templates related to a word in all the template sources.": output.WithHighLightFormat(
+			"azd template search todo",
+		),
+		"Search the python templates hosted by Azure Container Apps.": output.WithHighLightFormat(
+			"azd template search --language python --host containerapp",
+		),
+		"Search the terraform templates of a private template source.": output.WithHighLightFormat(
+			"azd template search --iac terraform --source contoso",
+		),
+	})
+}
+
+func getCmdTemplateSourceAddHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Adds the templates listed by the templates.json of a git repository.": output.WithHighLightFormat(
+			"azd template source add contoso https://github.com/contoso/azd-templates.git",
+		),
+		"Adds the templates listed by a JSON file of a storage account.": output.WithHighLightFormat(
+			"azd template source add contoso https://contoso.blob.core.windows.net/templates/templates.json",
+		),
+	})
+}
+
 func getCmdTemplateHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf(
@@ -173,5 +547,9 @@ func getCmdTemplateHelpDescription(*cobra.Command) string {
 			formatHelpNote(fmt.Sprintf("Running %s without a template will prompt you to start with a minimal"+
 				" template or select from our curated list of samples.",
 				output.WithHighLightFormat("azd init"))),
+			formatHelpNote(fmt.Sprintf("To search the curated templates, the awesome-azd gallery and the template"+
+				" sources of your organization, run %s. Template sources are added with %s.",
+				output.WithHighLightFormat("azd template search"),
+				output.WithHighLightFormat("azd template source add"))),
 		})
 }
@@ -0,0 +1,292 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type serviceTestFlags struct {
+	global      *internal.GlobalCommandOptions
+	parallelism int
+	junit       string
+	envFlag
+}
+
+func (f *serviceTestFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.IntVar(
+		&f.parallelism,
+		"parallelism",
+		1,
+		"The maximum number of services tested at the same time. The output of the tests is streamed when 1.",
+	)
+	local.StringVar(&f.junit, "junit", "", "Write the results as a JUnit XML report to the file.")
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newServiceTestFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *serviceTestFlags {
+	flags := &serviceTestFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newServiceTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test [<service>]",
+		Short: "Run the tests of the application's services against the environment.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type serviceTestAction struct {
+	flags          *serviceTestFlags
+	args           []string
+	console        input.Console
+	formatter      output.Formatter
+	writer         io.Writer
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceTester  *project.ServiceTester
+}
+
+func newServiceTestAction(
+	flags *serviceTestFlags,
+	args []string,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceTester *project.ServiceTester,
+) actions.Action {
+	return &serviceTestAction{
+		flags:          flags,
+		args:           args,
+		console:        console,
+		formatter:      formatter,
+		writer:         writer,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceTester:  serviceTester,
+	}
+}
+
+// The row of each service in the table output of `azd test`
+type testResultRow struct {
+	Service  string
+	Result   string
+	Duration string
+	Details  string
+}
+
+func (a *serviceTestAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.parallelism < 1 {
+		return nil, errors.New("--parallelism must be greater than 0")
+	}
+
+	services := []*project.ServiceConfig{}
+	if len(a.args) == 1 {
+		if !a.projectConfig.HasService(a.args[0]) {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", a.args[0])
+		}
+
+		svc := a.projectConfig.Services[a.args[0]]
+		if svc.Test == nil || svc.Test.Run == "" {
+			return nil, fmt.Errorf("service '%s' does not define tests, add test.run to the service", svc.Name)
+		}
+
+		services = append(services, svc)
+	} else {
+		for _, svc := range a.projectConfig.GetServicesStable() {
+			if svc.Test != nil && svc.Test.Run != "" {
+				services = append(services, svc)
+			}
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, errors.New("no service defines tests, add test.run to the services in azure.yaml")
+	}
+
+	if err := a.projectManager.Initialize(ctx, a.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() != output.JsonFormat {
+		a.console.MessageUxItem(ctx, &ux.MessageTitle{
+			Title: "Testing services (azd test)",
+		})
+	}
+
+	// A single service at a time streams the output of its tests, concurrent tests are displayed on the spinner
+	stream := a.flags.parallelism == 1 && a.formatter.Kind() != output.JsonFormat
+	progress := newServiceProgress(a.console, "Testing")
+
+	results, errs := async.RunParallel(ctx, services, a.flags.parallelism,
+		func(ctx context.Context, svc *project.ServiceConfig) (*project.ServiceTestResult, error) {
+			if !stream {
+				progress.Start(ctx, svc.Name)
+				result, err := a.serviceTester.Test(ctx, svc, nil)
+				progress.Stop(ctx, svc.Name, testStepResult(result, err), nil)
+				return result, err
+			}
+
+			a.console.Message(ctx, output.WithHighLightFormat("Testing service %s", svc.Name))
+			result, err := a.serviceTester.Test(ctx, svc, a.console.Handles().Stdout)
+			a.console.Message(ctx, "")
+			return result, err
+		})
+
+	testResult := contracts.TestResult{
+		Passed:   true,
+		Services: make([]contracts.ServiceTestResult, 0, len(services)),
+	}
+	rows := make([]testResultRow, 0, len(services))
+	completed := []*project.ServiceTestResult{}
+	failed := 0
+
+	for i, svc := range services {
+		serviceResult := contracts.ServiceTestResult{Name: svc.Name}
+		row := testResultRow{Service: svc.Name, Result: "Failed", Duration: "-"}
+
+		if errs[i] != nil {
+			serviceResult.Error = errs[i].Error()
+			row.Details = serviceResult.Error
+		} else {
+			result := results[i]
+			completed = append(completed, result)
+
+			serviceResult.Passed = result.Passed
+			serviceResult.ExitCode = result.ExitCode
+			serviceResult.DurationSeconds = result.Duration.Seconds()
+			row.Duration = ux.DurationAsText(result.Duration)
+
+			if result.Passed {
+				row.Result = "Passed"
+			} else {
+				row.Details = fmt.Sprintf("exit code %d", result.ExitCode)
+
+				// The output of concurrent tests is only displayed when they fail
+				if !stream && a.formatter.Kind() != output.JsonFormat {
+					a.console.Message(ctx, fmt.Sprintf("\n%s\n%s", output.WithErrorFormat("Tests of service %s failed:", svc.Name),
+						result.Output))
+				}
+			}
+		}
+
+		if !serviceResult.Passed {
+			failed++
+			testResult.Passed = false
+		}
+
+		testResult.Services = append(testResult.Services, serviceResult)
+		rows = append(rows, row)
+	}
+
+	if a.flags.junit != "" {
+		if err := a.writeJUnitReport(completed); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(testResult, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("test result could not be displayed: %w", err)
+		}
+	} else {
+		a.console.Message(ctx, "")
+		if err := a.formatter.Format(rows, a.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{Heading: "SERVICE", ValueTemplate: "{{.Service}}"},
+				{Heading: "RESULT", ValueTemplate: "{{.Result}}"},
+				{Heading: "DURATION", ValueTemplate: "{{.Duration}}"},
+				{Heading: "DETAILS", ValueTemplate: "{{.Details}}"},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("test result could not be displayed: %w", err)
+		}
+	}
+
+	// Failing tests fail the command so `azd test` can gate CI pipelines after `azd deploy`
+	if failed > 0 {
+		return nil, fmt.Errorf("the tests of %d of %d services failed", failed, len(services))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("The tests of %d services passed.", len(services)),
+		},
+	}, nil
+}
+
+func (a *serviceTestAction) writeJUnitReport(results []*project.ServiceTestResult) error {
+	file, err := os.OpenFile(a.flags.junit, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, osutil.PermissionFile)
+	if err != nil {
+		return fmt.Errorf("creating JUnit report: %w", err)
+	}
+	defer file.Close()
+
+	if err := project.WriteJUnitReport(file, a.projectConfig.Name, results); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
+	}
+
+	return nil
+}
+
+func testStepResult(result *project.ServiceTestResult, err error) input.SpinnerUxType {
+	if err != nil || !result.Passed {
+		return input.StepFailed
+	}
+
+	return input.StepDone
+}
+
+func getCmdTestHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Run the unit and integration tests of the application's services with the values of the environment,"+
+			" after the application is deployed.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Declare the tests of a service with %s in azure.yaml. The tests run in the"+
+				" directory of the service.", output.WithHighLightFormat("test: { run: npm test }"))),
+			formatHelpNote(fmt.Sprintf("Values of %s can reference environment values, ex. %s, and Key Vault"+
+				" secrets, ex. %s.",
+				output.WithHighLightFormat("test.env"),
+				output.WithHighLightFormat("${API_URL}"),
+				output.WithHighLightFormat("akvs://<vault>/<secret>"))),
+			formatHelpNote("The command exits with a non-zero exit code when the tests of any service fail."),
+		})
+}
+
+func getCmdTestHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run the tests of all services.": output.WithHighLightFormat("azd test"),
+		"Run the tests of a specific service.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd test <service>"),
+			output.WithWarningFormat("[Service name]")),
+		"Run the tests of 4 services at a time and write a JUnit report.": output.WithHighLightFormat(
+			"azd test --parallelism 4 --junit test-results.xml"),
+	})
+}
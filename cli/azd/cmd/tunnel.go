@@ -0,0 +1,196 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/devtunnel"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The service property holding the public URL of the tunnel of the service, ex. SERVICE_API_TUNNEL_URL
+const tunnelUrlServiceProperty = "TUNNEL_URL"
+
+type tunnelFlags struct {
+	global         *internal.GlobalCommandOptions
+	port           int
+	allowAnonymous bool
+	envKey         string
+	envFlag
+}
+
+func (f *tunnelFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.IntVarP(&f.port, "port", "p", 0, "The local port the service is listening on.")
+	local.BoolVar(
+		&f.allowAnonymous,
+		"allow-anonymous",
+		false,
+		"Allow anonymous access to the tunnel, required by webhooks calling the service.",
+	)
+	local.StringVar(
+		&f.envKey,
+		"env-key",
+		"",
+		"The environment value set to the public URL of the tunnel. (Default: SERVICE_<service>_TUNNEL_URL)",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newTunnelFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *tunnelFlags {
+	flags := &tunnelFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTunnelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tunnel <service>",
+		Short: "Expose a locally running service publicly with Dev Tunnels.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type tunnelAction struct {
+	flags         *tunnelFlags
+	args          []string
+	env           *environment.Environment
+	projectConfig *project.ProjectConfig
+	devTunnelCli  devtunnel.DevTunnelCli
+	console       input.Console
+}
+
+func newTunnelAction(
+	flags *tunnelFlags,
+	args []string,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	devTunnelCli devtunnel.DevTunnelCli,
+	console input.Console,
+) actions.Action {
+	return &tunnelAction{
+		flags:         flags,
+		args:          args,
+		env:           env,
+		projectConfig: projectConfig,
+		devTunnelCli:  devTunnelCli,
+		console:       console,
+	}
+}
+
+func (a *tunnelAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	serviceName := a.args[0]
+	if _, has := a.projectConfig.Services[serviceName]; !has {
+		return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+	}
+
+	if a.flags.port <= 0 || a.flags.port > 65535 {
+		return nil, errors.New("--port is required and must be a valid port number")
+	}
+
+	if err := tools.EnsureInstalled(ctx, a.devTunnelCli); err != nil {
+		return nil, err
+	}
+
+	loggedIn, err := a.devTunnelCli.IsLoggedIn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !loggedIn {
+		if err := a.devTunnelCli.Login(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// The tunnel is hosted until Ctrl+C, then removed from the environment
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	var saveErr error
+	ready := false
+	err = a.devTunnelCli.Host(ctx, a.flags.port, a.flags.allowAnonymous, func(url string) {
+		a.env.DotenvSet(a.tunnelUrlKey(serviceName), url)
+		if saveErr = a.env.Save(); saveErr != nil {
+			stop()
+			return
+		}
+
+		ready = true
+		a.console.Message(ctx, fmt.Sprintf(
+			"Forwarding %s to %s\nSet %s in environment %s, press Ctrl+C to stop.\n",
+			output.WithLinkFormat(url),
+			output.WithHighLightFormat("localhost:%d", a.flags.port),
+			output.WithHighLightFormat(a.tunnelUrlKey(serviceName)),
+			output.WithHighLightFormat(a.env.GetEnvName()),
+		))
+	})
+
+	if ready {
+		a.env.DotenvDelete(a.tunnelUrlKey(serviceName))
+		if err := a.env.Save(); err != nil {
+			log.Printf("removing the tunnel url from the environment: %v", err)
+		}
+	}
+
+	if saveErr != nil {
+		return nil, fmt.Errorf("saving the tunnel url: %w", saveErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Stopped the tunnel of service %s.", serviceName),
+		},
+	}, nil
+}
+
+func (a *tunnelAction) tunnelUrlKey(serviceName string) string {
+	if a.flags.envKey != "" {
+		return a.flags.envKey
+	}
+
+	return environment.ServicePropertyKey(serviceName, tunnelUrlServiceProperty)
+}
+
+func getCmdTunnelHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Expose a service running on your machine at a public URL with Dev Tunnels, for developing against Azure"+
+			" services calling webhooks, such as Event Grid or Bot Framework.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("The public URL is set to %s in the environment while the tunnel is running,"+
+				" and removed when it stops.", output.WithHighLightFormat("SERVICE_<service>_TUNNEL_URL"))),
+			formatHelpNote(fmt.Sprintf("Requires the Dev Tunnels CLI: %s",
+				output.WithLinkFormat("https://aka.ms/devtunnels/cli"))),
+		})
+}
+
+func getCmdTunnelHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Expose the service listening on port 3000.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd tunnel <service> --port 3000"),
+			output.WithWarningFormat("[Service name]")),
+		"Expose the service to webhooks without authentication.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd tunnel <service> --port 3000 --allow-anonymous"),
+			output.WithWarningFormat("[Service name]")),
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/installer"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type upgradeFlags struct {
+	global   *internal.GlobalCommandOptions
+	channel  string
+	rollback bool
+	force    bool
+}
+
+func (f *upgradeFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.channel,
+		"channel",
+		installer.ChannelStable,
+		fmt.Sprintf("The release channel azd is upgraded from: %s.", strings.Join(installer.Channels(), ", ")),
+	)
+	local.BoolVar(&f.rollback, "rollback", false, "Restores the version of azd replaced by the last upgrade.")
+	local.BoolVar(&f.force, "force", false, "Reinstalls the latest version even when it is already installed.")
+	f.global = global
+}
+
+func newUpgradeFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *upgradeFlags {
+	flags := &upgradeFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newUpgradeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade azd to the latest version of a release channel.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type upgradeAction struct {
+	flags    *upgradeFlags
+	upgrader *installer.Upgrader
+	console  input.Console
+}
+
+func newUpgradeAction(flags *upgradeFlags, upgrader *installer.Upgrader, console input.Console) actions.Action {
+	return &upgradeAction{
+		flags:    flags,
+		upgrader: upgrader,
+		console:  console,
+	}
+}
+
+func (a *upgradeAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.rollback {
+		stepMessage := "Restoring the previous version of azd"
+		a.console.ShowSpinner(ctx, stepMessage, input.Step)
+		result, err := a.upgrader.Rollback(ctx)
+		a.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if errors.Is(err, installer.ErrNoPreviousVersion) {
+			return nil, fmt.Errorf("%w, azd wasn't upgraded by `azd upgrade`", err)
+		} else if err != nil {
+			return nil, err
+		}
+
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("Rolled back azd from %s to %s", result.PreviousVersion, result.Version),
+			},
+		}, nil
+	}
+
+	stepMessage := fmt.Sprintf("Upgrading azd from the %s channel", a.flags.channel)
+	a.console.ShowSpinner(ctx, stepMessage, input.Step)
+	result, err := a.upgrader.Upgrade(ctx, a.flags.channel, a.flags.force)
+	a.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Upgraded {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("azd %s is the latest version of the %s channel", result.Version, a.flags.channel),
+			},
+		}, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Upgraded azd from %s to %s", result.PreviousVersion, result.Version),
+			FollowUp: fmt.Sprintf("To restore %s, run %s.",
+				result.PreviousVersion, output.WithHighLightFormat("azd upgrade --rollback")),
+		},
+	}, nil
+}
+
+func getCmdUpgradeHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Replace the installed azd with the latest version of the stable, beta or daily release channel. The checksum"+
+			" and code signature of the release are verified before azd is replaced.",
+		[]string{
+			formatHelpNote("The replaced version is kept, restore it with --rollback."),
+			formatHelpNote("When azd was installed by a package manager (brew, winget, choco, apt or yum), upgrade it with" +
+				" the package manager instead."),
+		})
+}
+
+func getCmdUpgradeHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Upgrade azd to the latest stable version.":    output.WithHighLightFormat("azd upgrade"),
+		"Upgrade azd to the latest daily build.":       output.WithHighLightFormat("azd upgrade --channel daily"),
+		"Restore the version replaced by the upgrade.": output.WithHighLightFormat("azd upgrade --rollback"),
+	})
+}
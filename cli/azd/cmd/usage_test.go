@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"html/template"
+	"io"
 	"strings"
 	"testing"
 
@@ -28,6 +29,10 @@ func TestUsage(t *testing.T) {
 
 func usageSnapshot(t *testing.T, cmd *cobra.Command) {
 	t.Run(cmd.Name(), func(t *testing.T) {
+		// The help template is generated when the help is displayed
+		cmd.SetOut(io.Discard)
+		cmd.HelpFunc()(cmd, nil)
+
 		result, err := resolveTemplate(cmd.HelpTemplate(), cmd)
 		require.NoError(t, err)
 		snapshot.SnapshotT(t, result)
@@ -182,7 +182,13 @@ func loadOrCreateEnvironment(
 			return nil, false, err
 		}
 
-		return environment.EmptyWithRoot(azdCtx.EnvironmentRoot(environmentName)), true, nil
+		// The new environment inherits the values of the shared environment of the workspace, if any
+		env, err := environment.GetEnvironment(azdCtx, environmentName)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, false, fmt.Errorf("loading environment '%s': %w", environmentName, err)
+		}
+
+		return env, true, nil
 	}
 
 	env, isNew, err := loadOrCreateEnvironment()
@@ -259,6 +265,8 @@ func serviceNameWarningCheck(console input.Console, serviceNameFlag string, comm
 	fmt.Fprintf(console.Handles().Stderr, "Next time use `azd %s <service>`.\n\n", commandName)
 }
 
+// Initializes the project and resolves the service targeted by the command, empty when all services are targeted.
+// App hosts are expanded into their services during initialization, so it runs before the target service is resolved.
 func getTargetServiceName(
 	ctx context.Context,
 	projectManager project.ProjectManager,
@@ -271,6 +279,10 @@ func getTargetServiceName(
 		return "", fmt.Errorf("cannot specify both --all and <service>")
 	}
 
+	if err := projectManager.Initialize(ctx, projectConfig); err != nil {
+		return "", err
+	}
+
 	if !allFlagValue && targetServiceName == "" {
 		targetService, err := projectManager.DefaultServiceFromWd(ctx, projectConfig)
 		if errors.Is(err, project.ErrNoDefaultService) {
@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+func workspaceActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("workspace", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "workspace",
+			Short: "Manage the projects of a repository containing several azd projects.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdWorkspaceHelpDescription,
+			Footer:      getCmdWorkspaceHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	group.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newWorkspaceListCmd(),
+		ActionResolver: newWorkspaceListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	return group
+}
+
+func newWorkspaceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the projects of the workspace.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+// WorkspaceProject is a project of the workspace listed by `azd workspace list`
+type WorkspaceProject struct {
+	workspace.Project
+	// Whether the project is the project of the current directory
+	Current bool `json:"current"`
+}
+
+type workspaceListAction struct {
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newWorkspaceListAction(formatter output.Formatter, writer io.Writer) actions.Action {
+	return &workspaceListAction{
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *workspaceListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the current directory: %w", err)
+	}
+
+	ws, err := workspace.Find(wd)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := ws.Projects()
+	if err != nil {
+		return nil, err
+	}
+
+	// The current project is the project found from the current directory, if any
+	currentDir := ""
+	if azdCtx, err := azdcontext.NewAzdContext(); err == nil {
+		currentDir = azdCtx.ProjectDirectory()
+	}
+
+	results := make([]WorkspaceProject, len(projects))
+	for i, project := range projects {
+		results[i] = WorkspaceProject{
+			Project: project,
+			Current: filepath.Join(ws.Root, filepath.FromSlash(project.Path)) == currentDir,
+		}
+	}
+
+	if a.formatter.Kind() == output.TableFormat {
+		return nil, a.formatter.Format(results, a.writer, output.TableFormatterOptions{
+			Columns: []output.Column{
+				{
+					Heading:       "NAME",
+					ValueTemplate: "{{.Name}}",
+				},
+				{
+					Heading:       "PATH",
+					ValueTemplate: "{{.Path}}",
+				},
+				{
+					Heading:       "SHARED",
+					ValueTemplate: "{{.Shared}}",
+				},
+				{
+					Heading:       "CURRENT",
+					ValueTemplate: "{{.Current}}",
+				},
+			},
+		})
+	}
+
+	return nil, a.formatter.Format(results, a.writer, nil)
+}
+
+func getCmdWorkspaceHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage the projects of a workspace, a repository containing several azd projects, ex. a monorepo.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("The workspace is the nearest directory containing an %s file, otherwise the"+
+				" root of the git repository. All its %s files are projects, unless %s lists the directories of the"+
+				" projects.",
+				output.WithHighLightFormat(workspace.FileName),
+				output.WithHighLightFormat(azdcontext.ProjectFileName),
+				output.WithHighLightFormat("projects"))),
+			formatHelpNote(fmt.Sprintf("The environments of the project of %s are shared: the environments of the same"+
+				" name of the other projects inherit their values, unless they set them.",
+				output.WithHighLightFormat("sharedEnvironment"))),
+			formatHelpNote(fmt.Sprintf("Run the commands of a project from anywhere in the workspace with %s.",
+				output.WithHighLightFormat("--project <name>"))),
+		})
+}
+
+func getCmdWorkspaceHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"List the projects of the workspace.": output.WithHighLightFormat("azd workspace list"),
+		"Deploy a project of the workspace.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd deploy --project"),
+			output.WithWarningFormat("<project>")),
+	})
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "time"
+
 type GlobalCommandOptions struct {
 	// Cwd allows the user to override the current working directory, temporarily.
 	// The root command will take care of cd'ing into that folder before your command
@@ -7,14 +9,26 @@ type GlobalCommandOptions struct {
 	// easier)
 	Cwd string
 
+	// Project is the directory, the azure.yaml file, or the name or path in the workspace of the project the command
+	// runs for, set with `--project`. Like Cwd, the root command cd's into the directory of the project.
+	Project string
+
 	// EnableDebugLogging indicates you should turn on verbose/debug logging in your command any
 	// launched tools. It's enabled with `--debug`, for any command.
 	EnableDebugLogging bool
 
+	// EnableHttpTracing writes the metadata of the HTTP requests sent to Azure to stderr. It's enabled with
+	// `--debug-http`, or with `debug.http` in the user config.
+	EnableHttpTracing bool
+
 	// when true, interactive prompts should behave as if the user selected the default value.
 	// if there is no default value the prompt returns an error.
 	NoPrompt bool
 
+	// Timeout is the deadline of the command, set with `--timeout`. Commands without a timeout use the
+	// `timeout.<command>` or `timeout.default` of the user config, if any.
+	Timeout time.Duration
+
 	// EnableTelemetry indicates if telemetry should be sent.
 	// The rootCmd will disable this based if the environment variable
 	// AZURE_DEV_COLLECT_TELEMETRY is set to 'no'.
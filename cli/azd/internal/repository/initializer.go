@@ -3,6 +3,7 @@ package repository
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -11,8 +12,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"text/template"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/importer"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -293,6 +296,11 @@ func (i *Initializer) InitializeMinimal(ctx context.Context, azdCtx *azdcontext.
 		return err
 	}
 
+	err = i.addDetectedServices(ctx, azdCtx, projectConfig)
+	if err != nil {
+		return err
+	}
+
 	err = os.MkdirAll(projectConfig.Infra.Path, osutil.PermissionDirectory)
 	if err != nil {
 		return err
@@ -333,6 +341,108 @@ func (i *Initializer) InitializeMinimal(ctx context.Context, azdCtx *azdcontext.
 	return nil
 }
 
+// addDetectedServices adds the java & rust services detected in the project directory to azure.yaml. A Dockerfile is
+// scaffolded for the rust services without one, they're hosted on Container Apps.
+func (i *Initializer) addDetectedServices(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	projectConfig *project.ProjectConfig,
+) error {
+	detected, err := project.DetectServices(projectConfig.Path)
+	if err != nil {
+		return err
+	}
+
+	if len(detected) == 0 {
+		return nil
+	}
+
+	services := map[string]*project.ServiceConfig{}
+	for _, service := range detected {
+		services[service.Config.Name] = service.Config
+		i.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: fmt.Sprintf("Detected %s service %s at %s, hosted by %s",
+				service.BuildSystem, service.Config.Name, service.Config.RelativePath, service.Config.Host),
+		})
+
+		if service.BuildSystem != project.BuildSystemCargo {
+			continue
+		}
+
+		dockerfilePath := filepath.Join(projectConfig.Path, service.Config.RelativePath, "Dockerfile")
+		if _, err := os.Stat(dockerfilePath); err == nil {
+			continue
+		}
+
+		var dockerfile bytes.Buffer
+		if err := rustDockerfileTemplate.Execute(&dockerfile, struct{ Binary string }{service.PackageName}); err != nil {
+			return fmt.Errorf("generating Dockerfile of service %s: %w", service.Config.Name, err)
+		}
+
+		if err := os.WriteFile(dockerfilePath, dockerfile.Bytes(), osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing Dockerfile of service %s: %w", service.Config.Name, err)
+		}
+
+		i.console.MessageUxItem(ctx, &ux.DoneMessage{Message: fmt.Sprintf("Created %s", dockerfilePath)})
+	}
+
+	// azure.yaml of minimal projects only has the name of the project
+	return project.Save(ctx, &project.ProjectConfig{Name: projectConfig.Name, Services: services}, azdCtx.ProjectPath())
+}
+
+var rustDockerfileTemplate = template.Must(template.New("Dockerfile").Parse(string(resources.RustDockerfile)))
+
+// InitializeImport initializes the project of the existing resources of a resource group: azure.yaml with the
+// services hosted by the resources, and the bicep templates referencing the resources.
+func (i *Initializer) InitializeImport(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	imported *importer.Import,
+) error {
+	projectDir := azdCtx.ProjectDirectory()
+	var err error
+
+	projectFormatted := output.WithLinkFormat("%s", projectDir)
+	i.console.ShowSpinner(ctx,
+		fmt.Sprintf("Creating project files of resource group %s at: %s", imported.ResourceGroup, projectFormatted),
+		input.Step)
+	defer i.console.StopSpinner(ctx,
+		fmt.Sprintf("Created project files of resource group %s at: %s", imported.ResourceGroup, projectFormatted)+"\n",
+		input.GetStepResultFormat(err))
+
+	isEmpty, err := isEmptyDir(projectDir)
+	if err != nil {
+		return err
+	}
+
+	mainPath := filepath.Join(projectDir, "infra", bicep.DefaultModule+".bicep")
+	if _, err = os.Stat(mainPath); err == nil {
+		err = fmt.Errorf("%s already exists, move it to import the resources of the resource group", mainPath)
+		return err
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	projectConfig := imported.ProjectConfig(azdCtx.GetDefaultProjectName())
+	if err = project.Save(ctx, projectConfig, azdCtx.ProjectPath()); err != nil {
+		return err
+	}
+
+	i.console.MessageUxItem(ctx,
+		&ux.DoneMessage{Message: fmt.Sprintf("Created a new %s file", azdcontext.ProjectFileName)})
+
+	if err = i.writeCoreAssets(ctx, azdCtx); err != nil {
+		return err
+	}
+
+	if err = importer.GenerateInfra(imported, filepath.Dir(mainPath)); err != nil {
+		return err
+	}
+
+	err = i.gitInitialize(ctx, projectDir, []string{}, isEmpty)
+	return err
+}
+
 // writeFileSafe writes a file to path but only if it doesn't already exist.
 // If it does exist, an extra attempt is performed to write the file with the retryInfix appended to the filename,
 // before the file extension.
@@ -409,6 +409,47 @@ func verifyProjectFile(t *testing.T, azdCtx *azdcontext.AzdContext, content stri
 	require.NoError(t, err)
 }
 
+func Test_Initializer_AddDetectedServices(t *testing.T) {
+	projectDir := t.TempDir()
+	ctx := context.Background()
+	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+
+	files := map[string]string{
+		"src/api/pom.xml":       "<project/>",
+		"src/worker/Cargo.toml": "[package]\nname = \"order-worker\"\n",
+		"src/jobs/Cargo.toml":   "[package]\nname = \"jobs\"\n",
+		"src/jobs/Dockerfile":   "FROM scratch",
+	}
+	for path, contents := range files {
+		fullPath := filepath.Join(projectDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(fullPath, []byte(contents), osutil.PermissionFile))
+	}
+
+	i := NewInitializer(mockinput.NewMockConsole(), git.NewGitCli(mockexec.NewMockCommandRunner()))
+	projectConfig, err := project.New(ctx, azdCtx.ProjectPath(), "app")
+	require.NoError(t, err)
+	require.NoError(t, i.addDetectedServices(ctx, azdCtx, projectConfig))
+
+	projectConfig, err = project.Load(ctx, azdCtx.ProjectPath())
+	require.NoError(t, err)
+	require.Len(t, projectConfig.Services, 3)
+	require.Equal(t, project.AppServiceTarget, projectConfig.Services["api"].Host)
+	require.Equal(t, project.ServiceLanguageJava, projectConfig.Services["api"].Language)
+	require.Equal(t, project.ContainerAppTarget, projectConfig.Services["worker"].Host)
+	require.Equal(t, project.ServiceLanguageRust, projectConfig.Services["worker"].Language)
+	require.Equal(t, "src/worker", projectConfig.Services["worker"].RelativePath)
+
+	dockerfile, err := os.ReadFile(filepath.Join(projectDir, "src", "worker", "Dockerfile"))
+	require.NoError(t, err)
+	require.Contains(t, string(dockerfile), "/src/target/x86_64-unknown-linux-musl/release/order-worker /app")
+
+	// Existing Dockerfiles are kept
+	dockerfile, err = os.ReadFile(filepath.Join(projectDir, "src", "jobs", "Dockerfile"))
+	require.NoError(t, err)
+	require.Equal(t, "FROM scratch", string(dockerfile))
+}
+
 func Test_determineDuplicates(t *testing.T) {
 	type args struct {
 		sourceFiles []string
@@ -0,0 +1,344 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// The kinds of exporters set by `azd config set telemetry.exporter`
+const (
+	// The built-in exporter, uploading telemetry to Microsoft
+	ExporterAzd = "azd"
+	// OTLP over gRPC, to an OpenTelemetry collector, ex. otlp-grpc=https://collector.contoso.com:4317
+	ExporterOtlpGrpc = "otlp-grpc"
+	// OTLP over HTTP, to an OpenTelemetry collector, ex. otlp-http=https://collector.contoso.com:4318
+	ExporterOtlpHttp = "otlp-http"
+	// Spans as JSON, to the file of the endpoint or stderr, stdout being the output of commands
+	ExporterStdout = "stdout"
+)
+
+// ref: go.opentelemetry.io/otel/exporters/otlp/otlptrace/internal/otlpconfig/DefaultCollectorGRPCPort
+const cDefaultCollectorGRPCPort uint16 = 4317
+
+// How long an exporter may take to export a batch of spans or shut down before it is considered unreachable
+const exporterTimeout = 5 * time.Second
+
+// ExporterOptions configures an exporter of telemetry spans
+type ExporterOptions struct {
+	Kind     string
+	Endpoint string
+	// The headers sent to OTLP collectors, ex. for authentication
+	Headers map[string]string
+}
+
+// LoadExporterOptions returns the exporters of the `telemetry.exporter` config, a comma separated list of kinds and
+// their endpoints, ex. `azd,otlp-grpc=https://collector.contoso.com:4317`. The built-in exporter is the only exporter
+// by default, it must be listed to compose it with other exporters. `telemetry.headers` sets the headers of OTLP
+// collectors, ex. `authorization=Bearer <token>`.
+func LoadExporterOptions(azdConfig config.Config) ([]ExporterOptions, error) {
+	value, has := azdConfig.Get("telemetry.exporter")
+	if !has {
+		return []ExporterOptions{{Kind: ExporterAzd}}, nil
+	}
+
+	headers := map[string]string{}
+	if value, has := azdConfig.Get("telemetry.headers"); has {
+		for _, header := range configList(value) {
+			name, headerValue, found := strings.Cut(header, "=")
+			if !found {
+				return nil, fmt.Errorf("telemetry.headers: '%s' must be name=value", header)
+			}
+
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		}
+	}
+
+	exporters := []ExporterOptions{}
+	for _, item := range configList(value) {
+		kind, endpoint, _ := strings.Cut(item, "=")
+		options := ExporterOptions{Kind: strings.ToLower(strings.TrimSpace(kind)), Endpoint: strings.TrimSpace(endpoint)}
+
+		switch options.Kind {
+		case ExporterAzd, ExporterStdout:
+		case ExporterOtlpGrpc, ExporterOtlpHttp:
+			if options.Endpoint == "" {
+				return nil, fmt.Errorf("telemetry.exporter: %s requires the endpoint of the collector, ex. "+
+					"%s=http://localhost", options.Kind, options.Kind)
+			}
+			options.Headers = headers
+		default:
+			return nil, fmt.Errorf(
+				"telemetry.exporter: unsupported exporter '%s', supported exporters: %s",
+				options.Kind, strings.Join([]string{ExporterAzd, ExporterOtlpGrpc, ExporterOtlpHttp, ExporterStdout}, ", "))
+		}
+
+		exporters = append(exporters, options)
+	}
+
+	return exporters, nil
+}
+
+// Config values set by `azd config set` are strings, lists are comma separated
+func configList(value any) []string {
+	var items []string
+	if values, ok := value.([]any); ok {
+		for _, item := range values {
+			items = append(items, fmt.Sprint(item))
+		}
+	} else {
+		items = strings.Split(fmt.Sprint(value), ",")
+	}
+
+	list := []string{}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// newExporter creates the exporter of the options, other than the built-in exporter. The exporter is closed with the
+// returned closer, if any.
+func newExporter(ctx context.Context, options ExporterOptions) (trace.SpanExporter, io.Closer, error) {
+	switch options.Kind {
+	case ExporterStdout:
+		if options.Endpoint == "" {
+			exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+			return exporter, nil, err
+		}
+
+		file, err := os.Create(options.Endpoint)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create log file %s: %w", options.Endpoint, err)
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+
+		return exporter, file, nil
+	case ExporterOtlpHttp:
+		exporter, err := newOtlpHttpExporter(ctx, options.Endpoint, options.Headers)
+		return exporter, nil, err
+	case ExporterOtlpGrpc:
+		client, err := newGrpcClient(options.Endpoint, options.Headers)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		exporter, err := otlptrace.New(ctx, client)
+		return exporter, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported exporter '%s'", options.Kind)
+	}
+}
+
+// newOtlpHttpExporter exports spans to the OTLP/HTTP collector of the url, on the default port 4318 when the url
+// doesn't have a port
+func newOtlpHttpExporter(ctx context.Context, logUrl string, headers map[string]string) (trace.SpanExporter, error) {
+	u, err := parseCollectorUrl(logUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	traceOptions := []otlptracehttp.Option{}
+	if u.Scheme == "http" {
+		traceOptions = append(traceOptions, otlptracehttp.WithInsecure())
+	}
+
+	if u.Port() != "" {
+		traceOptions = append(traceOptions, otlptracehttp.WithEndpoint(u.Host))
+	} else {
+		hostWithDefaultPort := fmt.Sprintf("%s:%d", u.Host, cDefaultCollectorHTTPPort)
+		traceOptions = append(traceOptions, otlptracehttp.WithEndpoint(hostWithDefaultPort))
+	}
+
+	if u.Path != "" && u.Path != "/" {
+		traceOptions = append(traceOptions, otlptracehttp.WithURLPath(u.Path))
+	}
+
+	if len(headers) > 0 {
+		traceOptions = append(traceOptions, otlptracehttp.WithHeaders(headers))
+	}
+
+	httpExporter, err := otlptracehttp.New(ctx, traceOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http trace exporter: %w", err)
+	}
+
+	return httpExporter, nil
+}
+
+func parseCollectorUrl(collectorUrl string) (*url.URL, error) {
+	// As a convenience we allow using localhost as an alias for http://localhost so that
+	// --trace-log-url localhost behaves as expected (for folks who are running something like Jaeger's all-in-one
+	// Docker image locally.)
+	if collectorUrl == "localhost" {
+		collectorUrl = "http://localhost"
+	}
+
+	u, err := url.Parse(collectorUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse log url: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported log url scheme '%s', only http and https are supported.", u.Scheme)
+	}
+
+	return u, nil
+}
+
+// grpcClient uploads spans to an OTLP/gRPC collector
+type grpcClient struct {
+	target      string
+	credentials credentials.TransportCredentials
+	headers     map[string]string
+
+	mu     sync.Mutex
+	conn   *grpc.ClientConn
+	client coltracepb.TraceServiceClient
+}
+
+// newGrpcClient creates the client of the OTLP/gRPC collector of the url, on the default port 4317 when the url
+// doesn't have a port. Collectors are reached over TLS unless the scheme of the url is http.
+func newGrpcClient(collectorUrl string, headers map[string]string) (*grpcClient, error) {
+	u, err := parseCollectorUrl(collectorUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	target := u.Host
+	if u.Port() == "" {
+		target = fmt.Sprintf("%s:%d", u.Host, cDefaultCollectorGRPCPort)
+	}
+
+	transportCredentials := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if u.Scheme == "http" {
+		transportCredentials = insecure.NewCredentials()
+	}
+
+	return &grpcClient{target: target, credentials: transportCredentials, headers: headers}, nil
+}
+
+// Start connects to the collector in the background, an unreachable collector fails the uploads
+func (c *grpcClient) Start(ctx context.Context) error {
+	conn, err := grpc.DialContext(ctx, c.target, grpc.WithTransportCredentials(c.credentials))
+	if err != nil {
+		return fmt.Errorf("connecting to collector %s: %w", c.target, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn = conn
+	c.client = coltracepb.NewTraceServiceClient(conn)
+
+	return nil
+}
+
+func (c *grpcClient) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	c.client = nil
+	return err
+}
+
+func (c *grpcClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	c.mu.Lock()
+	client := c.client
+	c.mu.Unlock()
+
+	if client == nil {
+		return errors.New("the collector connection is closed")
+	}
+
+	if len(c.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(c.headers))
+	}
+
+	_, err := client.Export(ctx, &coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	return err
+}
+
+// resilientExporter bounds the time spent exporting spans, and stops exporting after the first failure. An unreachable
+// collector doesn't delay commands or affect the other exporters, and its errors are logged instead of displayed.
+type resilientExporter struct {
+	name     string
+	exporter trace.SpanExporter
+	closer   io.Closer
+	timeout  time.Duration
+	failed   atomic.Bool
+}
+
+func newResilientExporter(name string, exporter trace.SpanExporter, closer io.Closer) *resilientExporter {
+	return &resilientExporter{name: name, exporter: exporter, closer: closer, timeout: exporterTimeout}
+}
+
+func (e *resilientExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	if e.failed.Load() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	if err := e.exporter.ExportSpans(ctx, spans); err != nil {
+		log.Printf("telemetry exporter %s failed, dropping its spans: %v", e.name, err)
+		e.failed.Store(true)
+	}
+
+	return nil
+}
+
+func (e *resilientExporter) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	if err := e.exporter.Shutdown(ctx); err != nil {
+		log.Printf("failed shutting down telemetry exporter %s: %v", e.name, err)
+	}
+
+	if e.closer != nil {
+		if err := e.closer.Close(); err != nil {
+			log.Printf("failed closing telemetry exporter %s: %v", e.name, err)
+		}
+	}
+
+	return nil
+}
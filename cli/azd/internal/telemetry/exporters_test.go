@@ -0,0 +1,126 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func Test_LoadExporterOptions(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		exporters, err := LoadExporterOptions(config.NewEmptyConfig())
+		require.NoError(t, err)
+		require.Equal(t, []ExporterOptions{{Kind: ExporterAzd}}, exporters)
+	})
+
+	t.Run("ConfigSetStrings", func(t *testing.T) {
+		exporters, err := LoadExporterOptions(config.NewConfig(map[string]any{
+			"telemetry": map[string]any{
+				"exporter": "azd, otlp-grpc=https://collector.contoso.com:4317,stdout",
+				"headers":  "authorization=Bearer token",
+			},
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []ExporterOptions{
+			{Kind: ExporterAzd},
+			{
+				Kind:     ExporterOtlpGrpc,
+				Endpoint: "https://collector.contoso.com:4317",
+				Headers:  map[string]string{"authorization": "Bearer token"},
+			},
+			{Kind: ExporterStdout},
+		}, exporters)
+	})
+
+	t.Run("MissingEndpoint", func(t *testing.T) {
+		_, err := LoadExporterOptions(config.NewConfig(map[string]any{
+			"telemetry": map[string]any{"exporter": "otlp-http"},
+		}))
+		require.ErrorContains(t, err, "requires the endpoint")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := LoadExporterOptions(config.NewConfig(map[string]any{
+			"telemetry": map[string]any{"exporter": "zipkin=http://localhost:9411"},
+		}))
+		require.ErrorContains(t, err, "unsupported exporter 'zipkin'")
+	})
+}
+
+type collectorServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	requests      chan *coltracepb.ExportTraceServiceRequest
+	authorization []string
+}
+
+func (s *collectorServer) Export(
+	ctx context.Context,
+	request *coltracepb.ExportTraceServiceRequest,
+) (*coltracepb.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization = md.Get("authorization")
+	s.requests <- request
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func Test_OtlpGrpcExporter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	collector := &collectorServer{requests: make(chan *coltracepb.ExportTraceServiceRequest, 1)}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, collector)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	exporter, closer, err := newExporter(context.Background(), ExporterOptions{
+		Kind:     ExporterOtlpGrpc,
+		Endpoint: "http://" + listener.Addr().String(),
+		Headers:  map[string]string{"authorization": "Bearer token"},
+	})
+	require.NoError(t, err)
+	require.Nil(t, closer)
+
+	provider := trace.NewTracerProvider(trace.WithSyncer(newResilientExporter(ExporterOtlpGrpc, exporter, nil)))
+	_, span := provider.Tracer("test").Start(context.Background(), "cmd.provision")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	request := <-collector.requests
+	require.Equal(t, "cmd.provision", request.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	require.Equal(t, []string{"Bearer token"}, collector.authorization)
+}
+
+type failingExporter struct {
+	exports int
+}
+
+func (e *failingExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	e.exports++
+	return errors.New("collector unreachable")
+}
+
+func (e *failingExporter) Shutdown(ctx context.Context) error {
+	return errors.New("collector unreachable")
+}
+
+func Test_ResilientExporter(t *testing.T) {
+	failing := &failingExporter{}
+	exporter := newResilientExporter(ExporterOtlpHttp, failing, nil)
+
+	// Failures are logged, the other exporters and the command aren't affected
+	require.NoError(t, exporter.ExportSpans(context.Background(), nil))
+	require.NoError(t, exporter.ExportSpans(context.Background(), nil))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	// The exporter stops exporting once the collector is unreachable
+	require.Equal(t, 1, failing.exports)
+}
@@ -0,0 +1,163 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/network"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Level selects the telemetry transmitted by azd
+type Level string
+
+const (
+	// No telemetry is transmitted
+	LevelOff Level = "off"
+	// Only the spans of the commands and operations that failed are transmitted, without usage attributes
+	LevelErrorsOnly Level = "errors-only"
+	// The spans of all the commands are transmitted, with their usage attributes. The default level.
+	LevelUsage Level = "usage"
+)
+
+// The config set by `azd config set telemetry.level <level>`
+const LevelConfigKey = "telemetry.level"
+
+// The file recording the first-run notice was displayed, in the telemetry directory
+const noticeFileName = "notice"
+
+var levels = []Level{LevelOff, LevelErrorsOnly, LevelUsage}
+
+// ParseLevel returns the level of the value of the telemetry.level config
+func ParseLevel(value string) (Level, error) {
+	for _, level := range levels {
+		if strings.EqualFold(value, string(level)) {
+			return level, nil
+		}
+	}
+
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = string(level)
+	}
+
+	return "", fmt.Errorf(
+		"%s: unsupported level '%s', supported levels: %s", LevelConfigKey, value, strings.Join(names, ", "))
+}
+
+// CurrentLevel returns the telemetry level of the process. Telemetry is off when AZURE_DEV_COLLECT_TELEMETRY=no and in
+// air-gapped & offline mode, otherwise the level is set by the telemetry.level config.
+func CurrentLevel() Level {
+	azdConfig, err := config.NewUserConfigManager().Load()
+	if err != nil {
+		log.Printf("failed loading telemetry level: %v", err)
+		azdConfig = config.NewEmptyConfig()
+	}
+
+	return resolveLevel(os.Getenv(collectTelemetryEnvVar), network.Current().Restricted(), azdConfig)
+}
+
+func resolveLevel(collectTelemetry string, restricted bool, azdConfig config.Config) Level {
+	if collectTelemetry == "no" || restricted {
+		return LevelOff
+	}
+
+	value, has := azdConfig.Get(LevelConfigKey)
+	if !has {
+		return LevelUsage
+	}
+
+	level, err := ParseLevel(fmt.Sprint(value))
+	if err != nil {
+		// An unknown level transmits the least telemetry short of none
+		log.Printf("ignoring telemetry level: %v", err)
+		return LevelErrorsOnly
+	}
+
+	return level
+}
+
+// IsLevelConfigured returns whether the user chose the telemetry level, with telemetry.level or
+// AZURE_DEV_COLLECT_TELEMETRY
+func IsLevelConfigured() bool {
+	if _, has := os.LookupEnv(collectTelemetryEnvVar); has {
+		return true
+	}
+
+	azdConfig, err := config.NewUserConfigManager().Load()
+	if err != nil {
+		return false
+	}
+
+	_, has := azdConfig.Get(LevelConfigKey)
+	return has
+}
+
+// WriteFirstRunNotice writes the notice describing the telemetry levels the first time azd runs, unless the user
+// already chose the level. Later runs don't write the notice.
+func WriteFirstRunNotice(writer io.Writer) error {
+	telemetryDir, err := getTelemetryDirectory()
+	if err != nil {
+		return err
+	}
+
+	return writeFirstRunNotice(writer, telemetryDir, IsLevelConfigured())
+}
+
+func writeFirstRunNotice(writer io.Writer, telemetryDir string, configured bool) error {
+	noticePath := filepath.Join(telemetryDir, noticeFileName)
+	if _, err := os.Stat(noticePath); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if !configured {
+		fmt.Fprintf(writer, "Welcome to the Azure Developer CLI!\n\n"+
+			"azd collects usage data to help improve the product. Choose the data collected with"+
+			" `azd config set %s <level>`:\n"+
+			"  %-12s the commands run, their duration, usage and errors (default)\n"+
+			"  %-12s only the failures of commands\n"+
+			"  %-12s no data is collected\n\n",
+			LevelConfigKey, LevelUsage, LevelErrorsOnly, LevelOff)
+	}
+
+	if err := os.MkdirAll(telemetryDir, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	return os.WriteFile(noticePath, []byte{}, osutil.PermissionFile)
+}
+
+// errorsOnlyExporter exports the spans that failed only, for the errors-only level
+type errorsOnlyExporter struct {
+	trace.SpanExporter
+}
+
+func newErrorsOnlyExporter(exporter trace.SpanExporter) trace.SpanExporter {
+	return &errorsOnlyExporter{SpanExporter: exporter}
+}
+
+func (e *errorsOnlyExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	failed := []trace.ReadOnlySpan{}
+	for _, span := range spans {
+		if span.Status().Code == codes.Error {
+			failed = append(failed, span)
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return e.SpanExporter.ExportSpans(ctx, failed)
+}
@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_resolveLevel(t *testing.T) {
+	withLevel := func(level string) config.Config {
+		return config.NewConfig(map[string]any{"telemetry": map[string]any{"level": level}})
+	}
+
+	require.Equal(t, LevelUsage, resolveLevel("", false, config.NewEmptyConfig()))
+	require.Equal(t, LevelErrorsOnly, resolveLevel("", false, withLevel("Errors-Only")))
+	require.Equal(t, LevelOff, resolveLevel("", false, withLevel("off")))
+
+	// The environment variable and the restricted network mode turn telemetry off, whatever the level
+	require.Equal(t, LevelOff, resolveLevel("no", false, withLevel("usage")))
+	require.Equal(t, LevelOff, resolveLevel("", true, withLevel("usage")))
+
+	// Unknown levels don't transmit usage
+	require.Equal(t, LevelErrorsOnly, resolveLevel("", false, withLevel("verbose")))
+
+	_, err := ParseLevel("verbose")
+	require.ErrorContains(t, err, "supported levels: off, errors-only, usage")
+}
+
+func Test_writeFirstRunNotice(t *testing.T) {
+	t.Run("OnlyOnce", func(t *testing.T) {
+		telemetryDir := t.TempDir()
+
+		first := &bytes.Buffer{}
+		require.NoError(t, writeFirstRunNotice(first, telemetryDir, false))
+		require.Contains(t, first.String(), "azd config set telemetry.level <level>")
+
+		second := &bytes.Buffer{}
+		require.NoError(t, writeFirstRunNotice(second, telemetryDir, false))
+		require.Empty(t, second.String())
+	})
+
+	t.Run("LevelConfigured", func(t *testing.T) {
+		notice := &bytes.Buffer{}
+		require.NoError(t, writeFirstRunNotice(notice, t.TempDir(), true))
+		require.Empty(t, notice.String())
+	})
+}
+
+func Test_errorsOnlyExporter(t *testing.T) {
+	inMemory := tracetest.NewInMemoryExporter()
+	exporter := newErrorsOnlyExporter(inMemory)
+
+	spans := tracetest.SpanStubs{
+		{Name: "cmd.provision"},
+		{Name: "cmd.deploy", Status: trace.Status{Code: codes.Error, Description: "service.arm.429"}},
+	}.Snapshots()
+
+	require.NoError(t, exporter.ExportSpans(context.Background(), spans))
+	require.Len(t, inMemory.GetSpans(), 1)
+	require.Equal(t, "cmd.deploy", inMemory.GetSpans()[0].Name)
+}
@@ -0,0 +1,190 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// The configs of the sampling of the transmitted spans
+const (
+	// The fraction of the child spans transmitted, ex. 0.1
+	samplingRateConfigKey = "telemetry.sampling.rate"
+	// The fraction of the child spans transmitted by prefix of their name, ex. tools.=0.5,service.=1
+	samplingSpansConfigKey = "telemetry.sampling.spans"
+	// The maximum number of child spans transmitted for each command
+	samplingMaxSpansConfigKey = "telemetry.sampling.maxSpans"
+)
+
+// SamplingOptions selects the child spans transmitted, ex. the spans of the HTTP requests or tool invocations of a
+// command. The root spans of the commands and the spans that failed are always transmitted.
+type SamplingOptions struct {
+	// The fraction of the child spans transmitted, between 0 and 1
+	Rate float64
+	// The fractions of the child spans transmitted by prefix of their name, the longest matching prefix applies
+	SpanRates map[string]float64
+	// The maximum number of child spans transmitted for each trace, unlimited when 0
+	MaxSpans int
+}
+
+// Whether spans are dropped by the sampling
+func (o SamplingOptions) enabled() bool {
+	return o.Rate < 1 || len(o.SpanRates) > 0 || o.MaxSpans > 0
+}
+
+// rate returns the fraction of the spans of the name transmitted
+func (o SamplingOptions) rate(name string) float64 {
+	rate := o.Rate
+	matched := ""
+	for prefix, prefixRate := range o.SpanRates {
+		if strings.HasPrefix(name, prefix) && len(prefix) > len(matched) {
+			matched = prefix
+			rate = prefixRate
+		}
+	}
+
+	return rate
+}
+
+// LoadSamplingOptions returns the sampling of the `telemetry.sampling` config, all the spans are transmitted by
+// default. `telemetry.sampling.spans` is a comma separated list of name prefixes and their rates, ex. `tools.=0.5`.
+func LoadSamplingOptions(azdConfig config.Config) (SamplingOptions, error) {
+	options := SamplingOptions{Rate: 1}
+
+	if value, has := azdConfig.Get(samplingRateConfigKey); has {
+		rate, err := parseSamplingRate(fmt.Sprint(value))
+		if err != nil {
+			return options, fmt.Errorf("%s: %w", samplingRateConfigKey, err)
+		}
+
+		options.Rate = rate
+	}
+
+	if value, has := azdConfig.Get(samplingSpansConfigKey); has {
+		options.SpanRates = map[string]float64{}
+		for _, item := range configList(value) {
+			prefix, value, found := strings.Cut(item, "=")
+			if !found || strings.TrimSpace(prefix) == "" {
+				return options, fmt.Errorf("%s: '%s' must be prefix=rate", samplingSpansConfigKey, item)
+			}
+
+			rate, err := parseSamplingRate(value)
+			if err != nil {
+				return options, fmt.Errorf("%s: %s: %w", samplingSpansConfigKey, prefix, err)
+			}
+
+			options.SpanRates[strings.TrimSpace(prefix)] = rate
+		}
+	}
+
+	if value, has := azdConfig.Get(samplingMaxSpansConfigKey); has {
+		maxSpans, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(value)))
+		if err != nil || maxSpans < 0 {
+			return options, fmt.Errorf("%s: '%v' must be a positive number", samplingMaxSpansConfigKey, value)
+		}
+
+		options.MaxSpans = maxSpans
+	}
+
+	return options, nil
+}
+
+func parseSamplingRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("'%s' must be a rate between 0 and 1", value)
+	}
+
+	return rate, nil
+}
+
+// loadSamplingOptions returns the sampling of the user config, all the spans are transmitted when the config is invalid
+func loadSamplingOptions() SamplingOptions {
+	azdConfig, err := config.NewUserConfigManager().Load()
+	if err != nil {
+		log.Printf("failed loading telemetry sampling: %v", err)
+		return SamplingOptions{Rate: 1}
+	}
+
+	options, err := LoadSamplingOptions(azdConfig)
+	if err != nil {
+		log.Printf("ignoring telemetry sampling: %v", err)
+		return SamplingOptions{Rate: 1}
+	}
+
+	return options
+}
+
+// samplingExporter exports the root spans, the failed spans and the sampled child spans
+type samplingExporter struct {
+	trace.SpanExporter
+	options SamplingOptions
+
+	mu sync.Mutex
+	// The number of child spans exported by trace, for the maximum number of spans
+	exported map[oteltrace.TraceID]int
+}
+
+func newSamplingExporter(exporter trace.SpanExporter, options SamplingOptions) trace.SpanExporter {
+	return &samplingExporter{
+		SpanExporter: exporter,
+		options:      options,
+		exported:     map[oteltrace.TraceID]int{},
+	}
+}
+
+func (e *samplingExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	sampled := []trace.ReadOnlySpan{}
+	for _, span := range spans {
+		if e.sampled(span) {
+			sampled = append(sampled, span)
+		}
+	}
+
+	if len(sampled) == 0 {
+		return nil
+	}
+
+	return e.SpanExporter.ExportSpans(ctx, sampled)
+}
+
+func (e *samplingExporter) sampled(span trace.ReadOnlySpan) bool {
+	// The spans of commands can have the remote parent of the process running azd, ex. a CI pipeline
+	isRoot := !span.Parent().IsValid() || strings.HasPrefix(span.Name(), events.CommandEventPrefix)
+	if isRoot || span.Status().Code == codes.Error {
+		return true
+	}
+
+	// Spans are sampled from their ID, the exporters of a command sample the same spans
+	spanId := span.SpanContext().SpanID()
+	rate := e.options.rate(span.Name())
+	if rate < 1 && float64(binary.BigEndian.Uint64(spanId[:]))/math.MaxUint64 >= rate {
+		return false
+	}
+
+	if e.options.MaxSpans == 0 {
+		return true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	traceId := span.SpanContext().TraceID()
+	if e.exported[traceId] >= e.options.MaxSpans {
+		return false
+	}
+
+	e.exported[traceId]++
+	return true
+}
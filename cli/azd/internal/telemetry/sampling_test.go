@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func Test_LoadSamplingOptions(t *testing.T) {
+	options, err := LoadSamplingOptions(config.NewEmptyConfig())
+	require.NoError(t, err)
+	require.False(t, options.enabled())
+
+	options, err = LoadSamplingOptions(config.NewConfig(map[string]any{
+		"telemetry": map[string]any{
+			"sampling": map[string]any{
+				"rate":     "0.1",
+				"spans":    "tools.=0.5, tools.bicep.=1",
+				"maxSpans": "20",
+			},
+		},
+	}))
+	require.NoError(t, err)
+	require.True(t, options.enabled())
+	require.Equal(t, 0.1, options.rate("service.deploy"))
+	require.Equal(t, 0.5, options.rate("tools.gh.install"))
+	require.Equal(t, 1.0, options.rate("tools.bicep.install"))
+	require.Equal(t, 20, options.MaxSpans)
+
+	_, err = LoadSamplingOptions(config.NewConfig(map[string]any{
+		"telemetry": map[string]any{"sampling": map[string]any{"rate": "1.5"}},
+	}))
+	require.ErrorContains(t, err, "telemetry.sampling.rate: '1.5' must be a rate between 0 and 1")
+
+	_, err = LoadSamplingOptions(config.NewConfig(map[string]any{
+		"telemetry": map[string]any{"sampling": map[string]any{"spans": "tools."}},
+	}))
+	require.ErrorContains(t, err, "must be prefix=rate")
+}
+
+func Test_samplingExporter(t *testing.T) {
+	traceId := oteltrace.TraceID{1}
+	root := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: traceId, SpanID: oteltrace.SpanID{1}})
+
+	spans := tracetest.SpanStubs{{Name: "cmd.deploy", SpanContext: root}}
+	for i := 0; i < 100; i++ {
+		spanId := oteltrace.SpanID{byte(i * 2), byte(i)}
+		spans = append(spans, tracetest.SpanStub{
+			Name:        fmt.Sprintf("http.request.%d", i),
+			SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: traceId, SpanID: spanId}),
+			Parent:      root,
+		})
+	}
+	spans = append(spans, tracetest.SpanStub{
+		Name:        "tools.bicep.install",
+		SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: traceId, SpanID: oteltrace.SpanID{255}}),
+		Parent:      root,
+		Status:      trace.Status{Code: codes.Error},
+	})
+
+	exported := func(options SamplingOptions) map[string]bool {
+		inMemory := tracetest.NewInMemoryExporter()
+		exporter := newSamplingExporter(inMemory, options)
+		require.NoError(t, exporter.ExportSpans(context.Background(), spans.Snapshots()))
+
+		names := map[string]bool{}
+		for _, span := range inMemory.GetSpans() {
+			names[span.Name] = true
+		}
+
+		return names
+	}
+
+	// The root span and the failed spans are always exported
+	names := exported(SamplingOptions{Rate: 0})
+	require.Equal(t, map[string]bool{"cmd.deploy": true, "tools.bicep.install": true}, names)
+
+	// About half of the child spans are exported, the same spans for each exporter
+	names = exported(SamplingOptions{Rate: 0.5})
+	require.Greater(t, len(names), 30)
+	require.Less(t, len(names), 70)
+	require.Equal(t, names, exported(SamplingOptions{Rate: 0.5}))
+
+	// The number of child spans exported by trace is limited
+	names = exported(SamplingOptions{Rate: 1, MaxSpans: 10})
+	require.Len(t, names, 12)
+}
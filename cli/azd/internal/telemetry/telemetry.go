@@ -8,7 +8,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
@@ -16,16 +15,14 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	appinsightsexporter "github.com/azure/azure-dev/cli/azd/internal/telemetry/appinsights-exporter"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/benbjohnson/clock"
 	"github.com/gofrs/flock"
 	"github.com/spf13/pflag"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
-	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/sdk/trace"
-	"go.uber.org/multierr"
 )
 
 // the equivalent of AZURE_CORE_COLLECT_TELEMETRY
@@ -41,9 +38,14 @@ const (
 
 const appInsightsMaxIngestionDelay = time.Duration(48) * time.Hour
 
+// The local store of the spans of previous commands, inspected by `azd telemetry show`
+const spanStoreFileName = "spans.jsonl"
+
+// The number of spans kept by the local span store, the spans of the last few dozen commands
+const maxStoredSpans = 1000
+
 type TelemetrySystem struct {
 	storageQueue   *StorageQueue
-	logFile        *os.File
 	tracerProvider *trace.TracerProvider
 	exporter       *Exporter
 
@@ -64,8 +66,50 @@ func getTelemetryDirectory() (string, error) {
 	return telemetryDir, nil
 }
 
+func newSpanStore(telemetryDir string) *tracing.SpanStore {
+	return tracing.NewSpanStore(filepath.Join(telemetryDir, spanStoreFileName), maxStoredSpans)
+}
+
+// LocalSpanStore returns the store of the spans recorded by previous commands
+func LocalSpanStore() (*tracing.SpanStore, error) {
+	telemetryDir, err := getTelemetryDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine storage directory: %w", err)
+	}
+
+	return newSpanStore(telemetryDir), nil
+}
+
+// LocalRecorder records spans in the local span store only, for users to review the telemetry azd would transmit
+// before enabling it
+type LocalRecorder struct {
+	tracerProvider *trace.TracerProvider
+}
+
+// StartLocalRecorder records the spans of the command locally, when telemetry is disabled
+func StartLocalRecorder() (*LocalRecorder, error) {
+	spanStore, err := LocalSpanStore()
+	if err != nil {
+		return nil, err
+	}
+
+	tp := trace.NewTracerProvider(
+		trace.WithBatcher(tracing.NewSpanStoreExporter(spanStore)),
+		trace.WithResource(resource.New()),
+	)
+	otel.SetTracerProvider(tp)
+
+	return &LocalRecorder{tracerProvider: tp}, nil
+}
+
+// Shutdown records the pending spans
+func (r *LocalRecorder) Shutdown(ctx context.Context) error {
+	return r.tracerProvider.Shutdown(ctx)
+}
+
+// Telemetry is disabled by AZURE_DEV_COLLECT_TELEMETRY=no, telemetry.level=off and in air-gapped & offline mode
 func IsTelemetryEnabled() bool {
-	return os.Getenv(collectTelemetryEnvVar) != "no"
+	return CurrentLevel() != LevelOff
 }
 
 // Returns the singleton TelemetrySystem instance.
@@ -120,59 +164,60 @@ func initialize() (*TelemetrySystem, error) {
 	exporter := NewExporter(storageQueue, config.InstrumentationKey)
 
 	options := []trace.TracerProviderOption{
-		trace.WithBatcher(exporter),
+		// The spans are recorded locally for `azd telemetry show`
+		trace.WithBatcher(tracing.NewSpanStoreExporter(newSpanStore(telemetryDir))),
 		trace.WithResource(resource.New()),
 	}
 
-	logFile, logUrl := getTraceFlags()
-
-	if logFile != "" {
-		file, err := os.Create(logFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create log file %s: %w", logFile, err)
+	// At the errors-only level, the exporters transmit the failed spans only. The child spans transmitted are sampled
+	// by the telemetry.sampling config, the local span store records all the spans.
+	errorsOnly := CurrentLevel() == LevelErrorsOnly
+	sampling := loadSamplingOptions()
+	transmitted := func(exporter trace.SpanExporter) trace.SpanExporter {
+		if errorsOnly {
+			return newErrorsOnlyExporter(exporter)
 		}
 
-		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create log file exporter: %w", err)
+		if sampling.enabled() {
+			return newSamplingExporter(exporter, sampling)
 		}
 
-		options = append(options, trace.WithBatcher(stdoutExporter))
+		return exporter
 	}
 
-	if logUrl != "" {
-		traceOptions := []otlptracehttp.Option{}
-
-		// As a convenience we allow using localhost as an alias for http://localhost so that
-		// --trace-log-url localhost behaves as expected (for folks who are running something like Jaeger's all-in-one
-		// Docker image locally.)
-		if logUrl == "localhost" {
-			logUrl = "http://localhost"
+	// Exporters configured by the user are composed, each one failing independently of the others
+	for _, exporterOptions := range loadExporterOptions() {
+		if exporterOptions.Kind == ExporterAzd {
+			options = append(options, trace.WithBatcher(transmitted(exporter)))
+			continue
 		}
 
-		u, err := url.Parse(logUrl)
+		configured, closer, err := newExporter(context.Background(), exporterOptions)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse log url: %w", err)
+			log.Printf("skipping telemetry exporter %s: %v", exporterOptions.Kind, err)
+			continue
 		}
 
-		if u.Scheme != "http" && u.Scheme != "https" {
-			return nil, fmt.Errorf("unsupported log url scheme '%s', only http and https are supported.", u.Scheme)
-		}
+		options = append(options,
+			trace.WithBatcher(transmitted(newResilientExporter(exporterOptions.Kind, configured, closer))))
+	}
 
-		if u.Scheme == "http" {
-			traceOptions = append(traceOptions, otlptracehttp.WithInsecure())
-		}
+	logFile, logUrl := getTraceFlags()
 
-		if u.Port() != "" {
-			traceOptions = append(traceOptions, otlptracehttp.WithEndpoint(u.Host))
-		} else {
-			hostWithDefaultPort := fmt.Sprintf("%s:%d", u.Host, cDefaultCollectorHTTPPort)
-			traceOptions = append(traceOptions, otlptracehttp.WithEndpoint(hostWithDefaultPort))
+	if logFile != "" {
+		fileExporter, closer, err := newExporter(
+			context.Background(), ExporterOptions{Kind: ExporterStdout, Endpoint: logFile})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create log file exporter: %w", err)
 		}
 
-		httpExporter, err := otlptracehttp.New(context.Background(), traceOptions...)
+		options = append(options, trace.WithBatcher(newResilientExporter("trace-log-file", fileExporter, closer)))
+	}
+
+	if logUrl != "" {
+		httpExporter, err := newOtlpHttpExporter(context.Background(), logUrl, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create http trace exporter: %w", err)
+			return nil, err
 		}
 
 		options = append(options, trace.WithBatcher(httpExporter))
@@ -191,16 +236,29 @@ func initialize() (*TelemetrySystem, error) {
 	}, nil
 }
 
-// Flushes all ongoing telemetry and shuts down telemetry
-func (ts *TelemetrySystem) Shutdown(ctx context.Context) error {
-	shutdownErr := instance.tracerProvider.Shutdown(ctx)
+// loadExporterOptions returns the exporters of the user config, only the built-in exporter when the config is invalid
+func loadExporterOptions() []ExporterOptions {
+	defaultExporters := []ExporterOptions{{Kind: ExporterAzd}}
 
-	var logFileCloseErr error
-	if ts.logFile != nil {
-		logFileCloseErr = ts.logFile.Close()
+	azdConfig, err := config.NewUserConfigManager().Load()
+	if err != nil {
+		log.Printf("failed loading telemetry exporters: %v", err)
+		return defaultExporters
 	}
 
-	return multierr.Combine(shutdownErr, logFileCloseErr)
+	exporters, err := LoadExporterOptions(azdConfig)
+	if err != nil {
+		log.Printf("ignoring telemetry exporters: %v", err)
+		return defaultExporters
+	}
+
+	return exporters
+}
+
+// Flushes all ongoing telemetry and shuts down telemetry
+func (ts *TelemetrySystem) Shutdown(ctx context.Context) error {
+	// Exporters close their files when they shut down
+	return instance.tracerProvider.Shutdown(ctx)
 }
 
 // Returns the telemetry queue instance.
@@ -21,3 +21,16 @@ const GitHubCliInstallEvent = "tools.gh.install"
 // AccountSubscriptionsListEvent is the name of the event which tracks listing of account subscriptions .
 // See fields.AccountSubscriptionsListTenantsFound for additional event fields.
 const AccountSubscriptionsListEvent = "account.subscriptions.list"
+
+// ServicePackageEvent is the name of the event which tracks the packaging of a service, a child of the command event.
+// See fields.ProjectServiceNameKey for additional event fields.
+const ServicePackageEvent = "service.package"
+
+// ServiceDeployEvent is the name of the event which tracks the deployment of a service, a child of the command event.
+// See fields.ProjectServiceNameKey for additional event fields.
+const ServiceDeployEvent = "service.deploy"
+
+// ExtensionCommandEvent is the name of the event which tracks commands run by extensions, ex. `azd hello`.
+// The names of extension commands are chosen by their authors and aren't part of the event name.
+// See fields.ExtensionNameKey for additional event fields.
+const ExtensionCommandEvent = "cmd.extension"
@@ -79,6 +79,14 @@ const (
 	ProjectServiceHostsKey = attribute.Key("project.service.hosts")
 	// The collection of hashed service languages in the project.
 	ProjectServiceLanguagesKey = attribute.Key("project.service.languages")
+	// Hashed name of the service of a service event.
+	ProjectServiceNameKey = attribute.Key("project.service.name")
+	// The host of the service of a service event.
+	ProjectServiceHostKey = attribute.Key("project.service.host")
+	// The maximum number of services packaged or deployed at the same time.
+	ProjectServiceParallelismKey = attribute.Key("project.service.parallelism")
+	// The provisioning provider of the project, ex. bicep, terraform or pulumi.
+	ProjectInfraProviderKey = attribute.Key("project.infra.provider")
 )
 
 // Environment related attributes
@@ -196,6 +204,13 @@ const (
 	ServiceCorrelationId = attribute.Key("service.correlationId")
 )
 
+// HTTP tracing related fields
+const (
+	// The number of HTTP requests sent to each service, as `<service>:<count>` values, when tracing the HTTP requests
+	// with --debug-http. The list of services can be found in [Domains].
+	HttpRequestCountsKey = attribute.Key("http.requests")
+)
+
 // Tool related fields
 const (
 	// The name of the tool.
@@ -203,4 +218,54 @@ const (
 
 	// The exit code of the tool after invocation.
 	ToolExitCode = attribute.Key("tool.exitCode")
+
+	// The identifier of the error parsed from the output of the tool, ex. the ARM error code reported by a Terraform
+	// provider or the Kubernetes API status reason reported by kubectl.
+	ToolErrorId = attribute.Key("tool.errorId")
+)
+
+// Cancellation related fields
+const (
+	// Whether the canceled command was interrupted by the user, ex. by Ctrl+C.
+	CancelInterrupted = attribute.Key("cancel.interrupted")
+)
+
+// Provisioning related fields
+const (
+	// Whether provisioning deployed the infrastructure or skipped it, see the enumerations of ProvisionResultKey.
+	ProvisionResultKey = attribute.Key("provision.result")
+
+	// Whether provisioning was forced with --force, deploying the infrastructure even when it's up to date.
+	ProvisionForcedKey = attribute.Key("provision.forced")
+
+	// The number of resources the previewed deployment would create, with --preview or --dry-run.
+	ProvisionPreviewCreateKey = attribute.Key("provision.preview.create")
+	// The number of resources the previewed deployment would modify.
+	ProvisionPreviewModifyKey = attribute.Key("provision.preview.modify")
+	// The number of resources the previewed deployment would delete.
+	ProvisionPreviewDeleteKey = attribute.Key("provision.preview.delete")
+	// The number of resources the previewed deployment wouldn't change.
+	ProvisionPreviewUnchangedKey = attribute.Key("provision.preview.unchanged")
+
+	// The number of resources of the environment no longer defined by the infrastructure, with --reconcile.
+	ProvisionOrphansKey = attribute.Key("provision.orphans")
+)
+
+// All possible enumerations of ProvisionResultKey
+const (
+	// The infrastructure was deployed.
+	ProvisionResultExecuted = "executed"
+	// The infrastructure was already up to date with the last successful provisioning and wasn't deployed again.
+	ProvisionResultSkipped = "skipped"
+	// The user didn't approve the changes previewed with --preview, the infrastructure wasn't deployed.
+	ProvisionResultDeclined = "declined"
+)
+
+// Extension related fields
+const (
+	// Hashed name of the extension run as an azd command.
+	ExtensionNameKey = attribute.Key("extension.name")
+
+	// Where the extension run as an azd command was found: installed, path or config.
+	ExtensionOriginKey = attribute.Key("extension.origin")
 )
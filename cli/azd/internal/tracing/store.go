@@ -0,0 +1,240 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/gofrs/flock"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// StoredSpan is a span recorded by the SpanStore, with the attributes telemetry transmits
+type StoredSpan struct {
+	Name         string    `json:"name"`
+	TraceId      string    `json:"traceId"`
+	SpanId       string    `json:"spanId"`
+	ParentSpanId string    `json:"parentSpanId,omitempty"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	// Ok, Error or Unset
+	Status string `json:"status"`
+	// The error code of failed spans, ex. service.arm.deployment.failed
+	ErrorCode  string         `json:"errorCode,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// The attributes of the process, shared by the spans of a command
+	Resource map[string]any `json:"resource,omitempty"`
+}
+
+// Duration returns the duration of the span
+func (s *StoredSpan) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// Failed returns whether the span recorded an error
+func (s *StoredSpan) Failed() bool {
+	return s.Status == codes.Error.String()
+}
+
+// SpanQuery filters the spans returned by SpanStore.Query
+type SpanQuery struct {
+	// The maximum number of spans returned, all the spans when 0
+	Limit int
+	// Only spans with a name starting with the prefix, ex. cmd.
+	NamePrefix string
+	// Only failed spans
+	Failed bool
+}
+
+// SpanStore keeps the most recent spans in a local JSON lines file, to inspect the telemetry of previous commands.
+// Concurrent azd processes share the file through a file lock.
+type SpanStore struct {
+	path     string
+	maxSpans int
+}
+
+// NewSpanStore creates the store of the file, keeping at most maxSpans spans
+func NewSpanStore(path string, maxSpans int) *SpanStore {
+	return &SpanStore{path: path, maxSpans: maxSpans}
+}
+
+// Add records the spans, the oldest spans are removed once the store is full
+func (s *SpanStore) Add(spans []StoredSpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating span store directory: %w", err)
+	}
+
+	lock := flock.New(s.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking span store: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	stored, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	stored = append(stored, spans...)
+	if s.maxSpans > 0 && len(stored) > s.maxSpans {
+		stored = stored[len(stored)-s.maxSpans:]
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, span := range stored {
+		if err := encoder.Encode(span); err != nil {
+			return fmt.Errorf("encoding span: %w", err)
+		}
+	}
+
+	// Written to a temp file first, readers never observe a partially written store
+	temp := s.path + ".tmp"
+	if err := os.WriteFile(temp, buf.Bytes(), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing span store: %w", err)
+	}
+
+	return os.Rename(temp, s.path)
+}
+
+// Query returns the spans matching the query, most recent first
+func (s *SpanStore) Query(query SpanQuery) ([]StoredSpan, error) {
+	stored, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(stored, func(i, j int) bool {
+		return stored[i].EndTime.After(stored[j].EndTime)
+	})
+
+	spans := []StoredSpan{}
+	for _, span := range stored {
+		if query.NamePrefix != "" && !strings.HasPrefix(span.Name, query.NamePrefix) {
+			continue
+		}
+
+		if query.Failed && !span.Failed() {
+			continue
+		}
+
+		spans = append(spans, span)
+		if query.Limit > 0 && len(spans) == query.Limit {
+			break
+		}
+	}
+
+	return spans, nil
+}
+
+// Clear removes all the spans of the store
+func (s *SpanStore) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("clearing span store: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SpanStore) read() ([]StoredSpan, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []StoredSpan{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading span store: %w", err)
+	}
+	defer file.Close()
+
+	spans := []StoredSpan{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var span StoredSpan
+		// Lines that can't be read, ex. from another version of azd, are skipped
+		if err := json.Unmarshal(scanner.Bytes(), &span); err == nil {
+			spans = append(spans, span)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading span store: %w", err)
+	}
+
+	return spans, nil
+}
+
+// SpanStoreExporter is an implementation of trace.SpanExporter recording spans in a SpanStore
+type SpanStoreExporter struct {
+	store *SpanStore
+}
+
+func NewSpanStoreExporter(store *SpanStore) *SpanStoreExporter {
+	return &SpanStoreExporter{store: store}
+}
+
+// ExportSpans records the spans in the store
+func (e *SpanStoreExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	stored := make([]StoredSpan, 0, len(spans))
+	for _, span := range spans {
+		stored = append(stored, NewStoredSpan(span))
+	}
+
+	return e.store.Add(stored)
+}
+
+// Shutdown is called to stop the exporter, it performs no action.
+func (e *SpanStoreExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// NewStoredSpan converts the span to the representation recorded by the store
+func NewStoredSpan(span trace.ReadOnlySpan) StoredSpan {
+	stored := StoredSpan{
+		Name:       span.Name(),
+		TraceId:    span.SpanContext().TraceID().String(),
+		SpanId:     span.SpanContext().SpanID().String(),
+		StartTime:  span.StartTime(),
+		EndTime:    span.EndTime(),
+		Status:     span.Status().Code.String(),
+		Attributes: map[string]any{},
+		Resource:   map[string]any{},
+	}
+
+	if span.Parent().HasSpanID() {
+		stored.ParentSpanId = span.Parent().SpanID().String()
+	}
+
+	if span.Status().Code == codes.Error {
+		stored.ErrorCode = span.Status().Description
+	}
+
+	for _, attribute := range span.Attributes() {
+		stored.Attributes[string(attribute.Key)] = attribute.Value.AsInterface()
+	}
+
+	if span.Resource() != nil {
+		for _, attribute := range span.Resource().Attributes() {
+			stored.Resource[string(attribute.Key)] = attribute.Value.AsInterface()
+		}
+	}
+
+	return stored
+}
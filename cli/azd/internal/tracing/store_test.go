@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func Test_SpanStore(t *testing.T) {
+	start := time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC)
+	span := func(name string, minutes int, status codes.Code) StoredSpan {
+		return StoredSpan{
+			Name:      name,
+			StartTime: start.Add(time.Duration(minutes) * time.Minute),
+			EndTime:   start.Add(time.Duration(minutes)*time.Minute + time.Second),
+			Status:    status.String(),
+		}
+	}
+
+	t.Run("Query", func(t *testing.T) {
+		store := NewSpanStore(filepath.Join(t.TempDir(), "spans.jsonl"), 10)
+		require.NoError(t, store.Add([]StoredSpan{
+			span("cmd.provision", 1, codes.Error),
+			span("arm.deploy", 2, codes.Ok),
+		}))
+		require.NoError(t, store.Add([]StoredSpan{span("cmd.deploy", 3, codes.Ok)}))
+
+		spans, err := store.Query(SpanQuery{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"cmd.deploy", "arm.deploy", "cmd.provision"}, spanNames(spans))
+
+		spans, err = store.Query(SpanQuery{NamePrefix: "cmd.", Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"cmd.deploy"}, spanNames(spans))
+
+		spans, err = store.Query(SpanQuery{Failed: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"cmd.provision"}, spanNames(spans))
+	})
+
+	t.Run("MaxSpans", func(t *testing.T) {
+		store := NewSpanStore(filepath.Join(t.TempDir(), "spans.jsonl"), 2)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, store.Add([]StoredSpan{span("cmd.up", i, codes.Ok)}))
+		}
+
+		spans, err := store.Query(SpanQuery{})
+		require.NoError(t, err)
+		require.Len(t, spans, 2)
+		require.Equal(t, start.Add(2*time.Minute), spans[0].StartTime)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		store := NewSpanStore(filepath.Join(t.TempDir(), "spans.jsonl"), 2)
+
+		spans, err := store.Query(SpanQuery{})
+		require.NoError(t, err)
+		require.Empty(t, spans)
+		require.NoError(t, store.Clear())
+	})
+}
+
+func Test_SpanStoreExporter(t *testing.T) {
+	store := NewSpanStore(filepath.Join(t.TempDir(), "spans.jsonl"), 10)
+	provider := trace.NewTracerProvider(trace.WithSyncer(NewSpanStoreExporter(store)))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "cmd.provision")
+	_, child := provider.Tracer("test").Start(ctx, "arm.deploy")
+	child.SetAttributes(attribute.String("service.name", "arm"))
+	child.End()
+	parent.RecordError(errors.New("deployment failed"))
+	parent.SetStatus(codes.Error, "service.arm.deployment.failed")
+	parent.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	spans, err := store.Query(SpanQuery{})
+	require.NoError(t, err)
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "cmd.provision", spans[0].Name)
+	require.True(t, spans[0].Failed())
+	require.Equal(t, "service.arm.deployment.failed", spans[0].ErrorCode)
+	require.Empty(t, spans[0].ParentSpanId)
+
+	require.Equal(t, "arm.deploy", spans[1].Name)
+	require.Equal(t, spans[0].SpanId, spans[1].ParentSpanId)
+	require.Equal(t, "arm", spans[1].Attributes["service.name"])
+	require.NotEmpty(t, spans[1].Resource)
+}
+
+func spanNames(spans []StoredSpan) []string {
+	names := []string{}
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+
+	return names
+}
@@ -26,7 +26,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/cmd"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
+	"github.com/azure/azure-dev/cli/azd/pkg/cancellation"
 	"github.com/azure/azure-dev/cli/azd/pkg/installer"
+	"github.com/azure/azure-dev/cli/azd/pkg/network"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/blang/semver/v4"
@@ -52,12 +54,42 @@ func main() {
 
 	log.Printf("azd version: %s", internal.Version)
 
-	ts := telemetry.GetTelemetrySystem()
+	// Private endpoints, custom DNS & air-gapped mode apply to every request azd and the Azure SDK send
+	network.Configure(network.Current())
 
+	// Help & shell completion are invoked interactively (completion on every key press) and skip
+	// the update check & telemetry initialization to start as fast as possible
+	var ts *telemetry.TelemetrySystem
+	var localRecorder *telemetry.LocalRecorder
 	latest := make(chan semver.Version)
-	go fetchLatestVersion(latest)
+	if isHelpOrCompletion(os.Args[1:]) {
+		close(latest)
+	} else {
+		ts = telemetry.GetTelemetrySystem()
+		go fetchLatestVersion(latest)
+
+		// The telemetry levels are described the first time azd runs, stderr is kept structured for JSON output
+		if !isJsonOutput() {
+			if err := telemetry.WriteFirstRunNotice(os.Stderr); err != nil {
+				log.Printf("failed writing telemetry notice: %v\n", err)
+			}
+		}
+
+		// Spans are still recorded locally when telemetry is disabled, for `azd telemetry show`
+		if !telemetry.IsTelemetryEnabled() {
+			recorder, err := telemetry.StartLocalRecorder()
+			if err != nil {
+				log.Printf("failed to record telemetry locally: %v\n", err)
+			}
+			localRecorder = recorder
+		}
+	}
 
-	cmdErr := cmd.NewRootCmd(false, nil).ExecuteContext(ctx)
+	// Ctrl+C cancels the context of the command, letting it stop its operations and clean up, instead of exiting
+	cmdCtx, stopInterrupt := cancellation.NotifyInterrupt(ctx, os.Stderr)
+	cmdErr := cmd.NewRootCmd(false, nil).ExecuteContext(cmdCtx)
+	interrupted := cancellation.IsInterrupted(cmdCtx)
+	stopInterrupt()
 	latestVersion, ok := <-latest
 
 	// If we were able to fetch a latest version, check to see if we are up to date and
@@ -79,8 +111,7 @@ func main() {
 			if runtime.GOOS == "windows" {
 				switch installedBy {
 				case installer.InstallTypePs:
-					//nolint:lll
-					upgradeText = "run:\npowershell -ex AllSigned -c \"Invoke-RestMethod 'https://aka.ms/install-azd.ps1' | Invoke-Expression\"\n\nIf the install script was run with custom parameters, ensure that the same parameters are used for the upgrade. For advanced install instructions, see: https://aka.ms/azd/upgrade/windows"
+					upgradeText = "run:\nazd upgrade"
 				case installer.InstallTypeWinget:
 					upgradeText = "run:\nwinget upgrade Microsoft.Azd"
 				case installer.InstallTypeChoco:
@@ -93,8 +124,7 @@ func main() {
 			} else if runtime.GOOS == "linux" {
 				switch installedBy {
 				case installer.InstallTypeSh:
-					//nolint:lll
-					upgradeText = "run:\ncurl -fsSL https://aka.ms/install-azd.sh | bash\n\nIf the install script was run with custom parameters, ensure that the same parameters are used for the upgrade. For advanced install instructions, see: https://aka.ms/azd/upgrade/linux"
+					upgradeText = "run:\nazd upgrade"
 				default:
 					// Also covers "deb" and "rpm" cases which are currently
 					// documented. When package manager distribution support is
@@ -106,8 +136,7 @@ func main() {
 				case installer.InstallTypeBrew:
 					upgradeText = "run:\nbrew upgrade azd"
 				case installer.InstallTypeSh:
-					//nolint:lll
-					upgradeText = "run:\ncurl -fsSL https://aka.ms/install-azd.sh | bash\n\nIf the install script was run with custom parameters, ensure that the same parameters are used for the upgrade. For advanced install instructions, see: https://aka.ms/azd/upgrade/mac"
+					upgradeText = "run:\nazd upgrade"
 				default:
 					upgradeText = "visit https://aka.ms/azd/upgrade/mac"
 				}
@@ -129,6 +158,12 @@ func main() {
 		}
 	}
 
+	if localRecorder != nil {
+		if err := localRecorder.Shutdown(ctx); err != nil {
+			log.Printf("failed to record telemetry locally: %v\n", err)
+		}
+	}
+
 	if ts != nil {
 		err := ts.Shutdown(ctx)
 		if err != nil {
@@ -143,6 +178,10 @@ func main() {
 		}
 	}
 
+	if interrupted {
+		os.Exit(cancellation.ExitCode)
+	}
+
 	if cmdErr != nil {
 		os.Exit(1)
 	}
@@ -173,6 +212,11 @@ func fetchLatestVersion(version chan<- semver.Version) {
 		}
 	}
 
+	if network.Current().Restricted() {
+		log.Print("skipping update check in air-gapped or offline mode")
+		return
+	}
+
 	// To avoid fetching the latest version of the CLI on every invocation, we cache the result for a period
 	// of time, in the user's home directory.
 	homeDir, err := os.UserHomeDir()
@@ -320,6 +364,31 @@ func isDebugEnabled() bool {
 	return debug
 }
 
+// isHelpOrCompletion checks to see if the command line only displays help or generates shell completions.
+// Running `azd` without any arguments displays help.
+func isHelpOrCompletion(args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+
+	switch args[0] {
+	case "__complete", "__completeNoDesc", "completion", "help":
+		return true
+	}
+
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if arg == "-h" || arg == "--help" {
+			return true
+		}
+	}
+
+	return false
+}
+
 // isJsonOutput checks to see if `--output` was passed with the value `json`
 func isJsonOutput() bool {
 	output := ""
@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package apphost reads the manifest of .NET Aspire app hosts and generates the infrastructure hosting the resources of
+// the application model in Azure Container Apps.
+package apphost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+)
+
+// The types of the resources of the manifest supported by azd
+const (
+	ResourceTypeProject   = "project.v0"
+	ResourceTypeContainer = "container.v0"
+	ResourceTypeValue     = "value.v0"
+)
+
+// Manifest is the application model published by a .NET Aspire app host
+type Manifest struct {
+	Resources map[string]*Resource `json:"resources"`
+	// The directory of the manifest, paths of the resources are relative to this directory
+	Dir string `json:"-"`
+}
+
+// Resource is a project, container or value of the application model
+type Resource struct {
+	Type string `json:"type"`
+	// The path of the project file, set for project.v0 resources
+	Path string `json:"path,omitempty"`
+	// The container image, set for container.v0 resources
+	Image            string              `json:"image,omitempty"`
+	Env              map[string]string   `json:"env,omitempty"`
+	Bindings         map[string]*Binding `json:"bindings,omitempty"`
+	ConnectionString string              `json:"connectionString,omitempty"`
+}
+
+// Binding is an endpoint exposed by a resource
+type Binding struct {
+	Scheme        string `json:"scheme"`
+	Protocol      string `json:"protocol"`
+	Transport     string `json:"transport"`
+	ContainerPort *int   `json:"containerPort,omitempty"`
+	External      bool   `json:"external,omitempty"`
+}
+
+var appHostPropertyRegex = regexp.MustCompile(`(?i)<IsAspireHost>\s*true\s*</IsAspireHost>`)
+
+// IsAppHostProject returns whether the .NET project file is a .NET Aspire app host
+func IsAppHostProject(projectFile string) (bool, error) {
+	contents, err := os.ReadFile(projectFile)
+	if err != nil {
+		return false, err
+	}
+
+	return appHostPropertyRegex.Match(contents), nil
+}
+
+// GenerateManifest runs the app host project to publish its manifest
+func GenerateManifest(ctx context.Context, dotnetCli dotnet.DotNetCli, appHostProject string) (*Manifest, error) {
+	tempDir, err := os.MkdirTemp("", "azd-apphost")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	manifestPath := filepath.Join(tempDir, "manifest.json")
+	if err := dotnetCli.PublishAppHostManifest(ctx, appHostProject, manifestPath); err != nil {
+		return nil, err
+	}
+
+	contents, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest of app host '%s': %w", appHostProject, err)
+	}
+
+	return ParseManifest(contents, filepath.Dir(appHostProject))
+}
+
+// ParseManifest parses the manifest of the app host in the directory
+func ParseManifest(contents []byte, dir string) (*Manifest, error) {
+	manifest := &Manifest{}
+	if err := json.Unmarshal(contents, manifest); err != nil {
+		return nil, fmt.Errorf("parsing app host manifest: %w", err)
+	}
+
+	manifest.Dir = dir
+	for name, resource := range manifest.Resources {
+		if resource == nil {
+			return nil, fmt.Errorf("resource '%s' of the app host manifest is empty", name)
+		}
+	}
+
+	return manifest, nil
+}
+
+// ProjectPaths returns the absolute paths of the project files of the project resources of the manifest, by name
+func ProjectPaths(manifest *Manifest) map[string]string {
+	paths := map[string]string{}
+	for name, resource := range manifest.Resources {
+		if resource.Type != ResourceTypeProject {
+			continue
+		}
+
+		path := resource.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(manifest.Dir, path)
+		}
+
+		paths[name] = filepath.Clean(path)
+	}
+
+	return paths
+}
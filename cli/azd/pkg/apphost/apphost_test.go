@@ -0,0 +1,123 @@
+package apphost
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+  "resources": {
+    "cache": {
+      "type": "container.v0",
+      "image": "redis:latest",
+      "bindings": {
+        "tcp": {"scheme": "tcp", "protocol": "tcp", "transport": "tcp", "containerPort": 6379}
+      },
+      "connectionString": "{cache.bindings.tcp.host}:{cache.bindings.tcp.port}"
+    },
+    "apiservice": {
+      "type": "project.v0",
+      "path": "../AspireApp.ApiService/AspireApp.ApiService.csproj",
+      "bindings": {
+        "http": {"scheme": "http", "protocol": "tcp", "transport": "http"}
+      }
+    },
+    "webfrontend": {
+      "type": "project.v0",
+      "path": "../AspireApp.Web/AspireApp.Web.csproj",
+      "env": {
+        "ConnectionStrings__cache": "{cache.connectionString}",
+        "services__apiservice__0": "{apiservice.bindings.http.url}"
+      },
+      "bindings": {
+        "https": {"scheme": "https", "protocol": "tcp", "transport": "http", "external": true}
+      }
+    }
+  }
+}`
+
+func TestIsAppHostProject(t *testing.T) {
+	dir := t.TempDir()
+	appHost := filepath.Join(dir, "AppHost.csproj")
+	web := filepath.Join(dir, "Web.csproj")
+
+	require.NoError(t, os.WriteFile(appHost, []byte(
+		"<Project><PropertyGroup><IsAspireHost>true</IsAspireHost></PropertyGroup></Project>"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(web, []byte("<Project></Project>"), osutil.PermissionFile))
+
+	isAppHost, err := IsAppHostProject(appHost)
+	require.NoError(t, err)
+	require.True(t, isAppHost)
+
+	isAppHost, err = IsAppHostProject(web)
+	require.NoError(t, err)
+	require.False(t, isAppHost)
+}
+
+func TestProjectPaths(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "AspireApp.AppHost")
+	manifest, err := ParseManifest([]byte(testManifest), dir)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{
+		"apiservice":  filepath.Join(dir, "..", "AspireApp.ApiService", "AspireApp.ApiService.csproj"),
+		"webfrontend": filepath.Join(dir, "..", "AspireApp.Web", "AspireApp.Web.csproj"),
+	}, ProjectPaths(manifest))
+}
+
+func TestGenerateInfra(t *testing.T) {
+	manifest, err := ParseManifest([]byte(testManifest), t.TempDir())
+	require.NoError(t, err)
+
+	infra, err := buildInfra(manifest, "AppHost.csproj")
+	require.NoError(t, err)
+	require.Len(t, infra.Apps, 3)
+
+	web := infra.Apps[2]
+	require.Equal(t, "webfrontend", web.Name)
+	require.True(t, web.Service)
+	require.Equal(t, placeholderImage, web.Image)
+	require.Equal(t, &genIngress{External: true, TargetPort: projectTargetPort, Transport: "auto"}, web.Ingress)
+	require.Equal(t, "SERVICE_WEBFRONTEND_URI", web.Output)
+	require.Equal(t, []genEnvVar{
+		{Name: "ConnectionStrings__cache", Value: "cache:6379"},
+		{Name: "services__apiservice__0", Value: "http://apiservice"},
+	}, web.Env)
+
+	cache := infra.Apps[1]
+	require.False(t, cache.Service)
+	require.Equal(t, "redis:latest", cache.Image)
+	require.Equal(t, &genIngress{TargetPort: 6379, Transport: "tcp"}, cache.Ingress)
+
+	dir := filepath.Join(t.TempDir(), "infra")
+	require.NoError(t, GenerateInfra(manifest, "AppHost.csproj", dir))
+
+	resourcesBicep, err := os.ReadFile(filepath.Join(dir, "resources.bicep"))
+	require.NoError(t, err)
+	require.Contains(t, string(resourcesBicep), "resource app_webfrontend 'Microsoft.App/containerApps@2023-05-01'")
+	require.Contains(t, string(resourcesBicep), "tags: union(tags, { 'azd-service-name': 'webfrontend' })")
+	require.Contains(t, string(resourcesBicep),
+		"output SERVICE_WEBFRONTEND_URI string = 'https://${app_webfrontend.properties.configuration.ingress.fqdn}'")
+
+	mainBicep, err := os.ReadFile(filepath.Join(dir, "main.bicep"))
+	require.NoError(t, err)
+	require.Contains(t, string(mainBicep),
+		"output SERVICE_WEBFRONTEND_URI string = resources.outputs.SERVICE_WEBFRONTEND_URI")
+	require.FileExists(t, filepath.Join(dir, "main.parameters.json"))
+}
+
+func TestGenerateInfraUnsupportedResource(t *testing.T) {
+	manifest, err := ParseManifest([]byte(`{"resources": {"db": {"type": "postgres.server.v0"}}}`), t.TempDir())
+	require.NoError(t, err)
+
+	err = GenerateInfra(manifest, "AppHost.csproj", t.TempDir())
+	require.ErrorContains(t, err, "resource 'db' of the app host manifest has the type 'postgres.server.v0'")
+}
+
+func TestBicepString(t *testing.T) {
+	require.Equal(t, `'it\'s \${x} \\ a'`, bicepString(`it's ${x} \ a`))
+}
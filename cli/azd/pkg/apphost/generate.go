@@ -0,0 +1,258 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package apphost
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/resources"
+)
+
+// The image of the container apps of project resources until azd deploys the image of the project
+const placeholderImage = "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest"
+
+// The port ASP.NET Core containers published by the .NET SDK listen on
+const projectTargetPort = 8080
+
+type genIngress struct {
+	External   bool
+	TargetPort int
+	Transport  string
+}
+
+type genEnvVar struct {
+	Name  string
+	Value string
+}
+
+type genContainerApp struct {
+	Name       string
+	Identifier string
+	Image      string
+	// Whether the container app hosts a project, deployed by azd as a service
+	Service bool
+	Ingress *genIngress
+	Env     []genEnvVar
+	// The name of the output of the endpoint of the container app, set when the ingress is external
+	Output string
+}
+
+type genInfra struct {
+	AppHost string
+	Apps    []genContainerApp
+}
+
+var infraTemplates = template.Must(
+	template.New("apphost").
+		Funcs(template.FuncMap{"bicepString": bicepString}).
+		ParseFS(resources.AppHostTemplates, "apphost/*.bicept"),
+)
+
+// GenerateInfra writes the bicep templates provisioning the resources of the manifest to the directory: main.bicep,
+// resources.bicep & main.parameters.json. The container apps of project resources are tagged with the name of the
+// resource, the name of the azd service deploying the project.
+func GenerateInfra(manifest *Manifest, appHost string, dir string) error {
+	infra, err := buildInfra(manifest, appHost)
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		"main.parameters.json": resources.MinimalBicepParameters,
+	}
+
+	for _, name := range []string{"main", "resources"} {
+		buf := bytes.Buffer{}
+		if err := infraTemplates.ExecuteTemplate(&buf, name+".bicept", infra); err != nil {
+			return fmt.Errorf("generating %s.bicep: %w", name, err)
+		}
+
+		files[name+".bicep"] = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(dir, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating infra directory: %w", err)
+	}
+
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), contents, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func buildInfra(manifest *Manifest, appHost string) (*genInfra, error) {
+	names := make([]string, 0, len(manifest.Resources))
+	for name := range manifest.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	infra := &genInfra{AppHost: appHost}
+	for _, name := range names {
+		resource := manifest.Resources[name]
+
+		var app genContainerApp
+		switch resource.Type {
+		case ResourceTypeProject:
+			app = genContainerApp{Image: placeholderImage, Service: true}
+		case ResourceTypeContainer:
+			app = genContainerApp{Image: resource.Image}
+		case ResourceTypeValue:
+			// Values are resolved into the environment variables of the resources referencing them
+			continue
+		default:
+			return nil, fmt.Errorf(
+				"resource '%s' of the app host manifest has the type '%s', which is not supported by azd. "+
+					"Supported types: %s, %s, %s",
+				name, resource.Type, ResourceTypeProject, ResourceTypeContainer, ResourceTypeValue)
+		}
+
+		app.Name = name
+		app.Identifier = bicepIdentifier(name)
+		app.Ingress = ingress(resource)
+		if app.Ingress != nil && app.Ingress.External {
+			app.Output = fmt.Sprintf("SERVICE_%s_URI", strings.ToUpper(envIdentifier(name)))
+		}
+
+		envNames := make([]string, 0, len(resource.Env))
+		for envName := range resource.Env {
+			envNames = append(envNames, envName)
+		}
+		sort.Strings(envNames)
+
+		for _, envName := range envNames {
+			value, err := resolveExpressions(manifest, resource.Env[envName], 0)
+			if err != nil {
+				return nil, fmt.Errorf("resolving environment variable '%s' of resource '%s': %w", envName, name, err)
+			}
+
+			app.Env = append(app.Env, genEnvVar{Name: envName, Value: value})
+		}
+
+		infra.Apps = append(infra.Apps, app)
+	}
+
+	return infra, nil
+}
+
+// ingress returns the ingress of the container app of the resource, nil when the resource doesn't have bindings
+func ingress(resource *Resource) *genIngress {
+	if len(resource.Bindings) == 0 {
+		return nil
+	}
+
+	ingress := &genIngress{Transport: "auto", TargetPort: projectTargetPort}
+	for _, binding := range resource.Bindings {
+		ingress.External = ingress.External || binding.External
+		if binding.ContainerPort != nil {
+			ingress.TargetPort = *binding.ContainerPort
+		}
+		if binding.Transport == "http2" {
+			ingress.Transport = "http2"
+		}
+		if binding.Scheme == "tcp" {
+			ingress.Transport = "tcp"
+		}
+	}
+
+	return ingress
+}
+
+// The maximum depth of connection strings referencing other connection strings
+const maxExpressionDepth = 10
+
+var expressionRegex = regexp.MustCompile(`\{([^{}.]+)\.([^{}]+)\}`)
+
+// resolveExpressions replaces the expressions referencing other resources in the value, ex. {api.bindings.http.url}
+// or {db.connectionString}, with their values. Container apps reach each other by name within their environment.
+func resolveExpressions(manifest *Manifest, value string, depth int) (string, error) {
+	if depth > maxExpressionDepth {
+		return "", fmt.Errorf("connection strings reference each other more than %d times", maxExpressionDepth)
+	}
+
+	var resolveErr error
+	resolved := expressionRegex.ReplaceAllStringFunc(value, func(expression string) string {
+		parts := expressionRegex.FindStringSubmatch(expression)
+		resourceName, property := parts[1], parts[2]
+
+		resource, has := manifest.Resources[resourceName]
+		if !has {
+			resolveErr = fmt.Errorf("expression '%s' references the unknown resource '%s'", expression, resourceName)
+			return expression
+		}
+
+		if property == "connectionString" {
+			connectionString, err := resolveExpressions(manifest, resource.ConnectionString, depth+1)
+			if err != nil {
+				resolveErr = err
+			}
+			return connectionString
+		}
+
+		bindingProperty := strings.Split(property, ".")
+		if len(bindingProperty) != 3 || bindingProperty[0] != "bindings" {
+			resolveErr = fmt.Errorf("expression '%s' is not supported", expression)
+			return expression
+		}
+
+		binding, has := resource.Bindings[bindingProperty[1]]
+		if !has {
+			resolveErr = fmt.Errorf("expression '%s' references the unknown binding '%s'", expression, bindingProperty[1])
+			return expression
+		}
+
+		port := 80
+		switch {
+		case binding.Scheme == "tcp" && binding.ContainerPort != nil:
+			port = *binding.ContainerPort
+		case binding.Scheme == "https":
+			port = 443
+		}
+
+		switch bindingProperty[2] {
+		case "url":
+			return fmt.Sprintf("%s://%s", binding.Scheme, resourceName)
+		case "host":
+			return resourceName
+		case "port":
+			return strconv.Itoa(port)
+		default:
+			resolveErr = fmt.Errorf("expression '%s' is not supported", expression)
+			return expression
+		}
+	})
+
+	return resolved, resolveErr
+}
+
+var bicepStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `${`, `\${`, "\n", `\n`)
+
+// bicepString returns the value as a bicep string literal
+func bicepString(value string) string {
+	return "'" + bicepStringEscaper.Replace(value) + "'"
+}
+
+var nonIdentifierRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// bicepIdentifier returns the symbolic name of the bicep resource of the resource of the manifest
+func bicepIdentifier(name string) string {
+	return "app_" + nonIdentifierRegex.ReplaceAllString(name, "_")
+}
+
+// envIdentifier returns the name as part of the name of an environment variable
+func envIdentifier(name string) string {
+	return nonIdentifierRegex.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,53 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// RunParallel invokes fn for each item with at most parallelism invocations running at the same time.
+// Results & errors are returned in the same order as the items. A parallelism less than 1 runs all items at once.
+// Items that haven't started when the context is cancelled are not invoked and fail with the context error.
+func RunParallel[T any, R any](
+	ctx context.Context,
+	items []T,
+	parallelism int,
+	fn func(ctx context.Context, item T) (R, error),
+) ([]R, []error) {
+	if parallelism < 1 || parallelism > len(items) {
+		parallelism = len(items)
+	}
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	slots := make(chan struct{}, parallelism)
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, item T) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			results[i], errs[i] = fn(ctx, item)
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	return results, errs
+}
@@ -0,0 +1,70 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunParallel(t *testing.T) {
+	t.Run("BoundedParallelism", func(t *testing.T) {
+		var running int32
+		var maxRunning int32
+
+		items := []int{1, 2, 3, 4, 5, 6}
+		results, errs := RunParallel(context.Background(), items, 2, func(ctx context.Context, item int) (int, error) {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				previous := atomic.LoadInt32(&maxRunning)
+				if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+
+			return item * 10, nil
+		})
+
+		require.Equal(t, []int{10, 20, 30, 40, 50, 60}, results)
+		require.Equal(t, make([]error, len(items)), errs)
+		require.LessOrEqual(t, maxRunning, int32(2))
+	})
+
+	t.Run("ErrorsInItemOrder", func(t *testing.T) {
+		items := []string{"api", "web", "worker"}
+		_, errs := RunParallel(context.Background(), items, 0, func(ctx context.Context, item string) (string, error) {
+			if item == "web" {
+				return "", errors.New("web failed")
+			}
+
+			return item, nil
+		})
+
+		require.NoError(t, errs[0])
+		require.EqualError(t, errs[1], "web failed")
+		require.NoError(t, errs[2])
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var invoked int32
+		items := []int{1, 2, 3}
+		_, errs := RunParallel(ctx, items, 1, func(ctx context.Context, item int) (int, error) {
+			atomic.AddInt32(&invoked, 1)
+			return item, nil
+		})
+
+		require.Equal(t, int32(0), invoked)
+		for _, err := range errs {
+			require.ErrorIs(t, err, context.Canceled)
+		}
+	})
+}
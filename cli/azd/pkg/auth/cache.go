@@ -58,3 +58,14 @@ type Cache interface {
 }
 
 var errCacheKeyNotFound = errors.New("key not found")
+
+// envelopedData stores both the type of encryption used as well as the encrypted data (as a base64 encoded string),
+// allowing us to change the underlying encryption algorithm as needed (and then understand what we need to do decrypt)
+type envelopedData struct {
+	// The type of encryption that was used to store data.
+	Type encryptionType `json:"type"`
+	// The encrypted data, represented as a Base64 encoded string (using base64.StdEncoding)
+	Data string `json:"data"`
+}
+
+type encryptionType string
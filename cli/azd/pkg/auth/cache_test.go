@@ -6,6 +6,7 @@ package auth
 import (
 	"context"
 	"math/rand"
+	"path/filepath"
 	"testing"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
@@ -22,10 +23,19 @@ func randSeq(n int, rng rand.Rand) string {
 	return string(b)
 }
 
+// newTestCacheKey creates a cache key stored in a file within root, as the credential store of the OS isn't available
+// in tests.
+func newTestCacheKey(root string) *cacheKey {
+	return &cacheKey{
+		store:    &fileKeyStore{path: filepath.Join(root, "cache.key")},
+		lockPath: filepath.Join(root, "cache.key.lock"),
+	}
+}
+
 func TestCache(t *testing.T) {
 	root := t.TempDir()
 	ctx := context.Background()
-	c := newCache(root)
+	c := newCache(root, newTestCacheKey(root))
 	// weak rng is fine for testing
 	//nolint:gosec
 	rng := rand.New(rand.NewSource(0))
@@ -50,7 +60,7 @@ func TestCache(t *testing.T) {
 	require.Equal(t, data.val, reader.val)
 
 	// the data should be shared across instances.
-	c = newCache(root)
+	c = newCache(root, newTestCacheKey(root))
 	reader = fixedMarshaller{}
 	err = c.Replace(ctx, &reader, cache.ReplaceHints{PartitionKey: key()})
 	require.NoError(t, err)
@@ -73,7 +83,7 @@ func TestCache(t *testing.T) {
 func TestCredentialCache(t *testing.T) {
 	root := t.TempDir()
 
-	c := newCredentialCache(root)
+	c := newCredentialCache(root, newTestCacheKey(root))
 
 	d1 := []byte("some data")
 
@@ -96,7 +106,7 @@ func TestCredentialCache(t *testing.T) {
 	require.Equal(t, d2, r2)
 
 	// the data should be shared across instances.
-	c = newCredentialCache(root)
+	c = newCredentialCache(root, newTestCacheKey(root))
 
 	r1, err = c.Read("d1")
 	require.NoError(t, err)
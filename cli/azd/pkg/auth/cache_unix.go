@@ -7,21 +7,31 @@
 package auth
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
 )
 
+// cAesGcmEncryptionType is the encryption type that uses AES-256 in GCM mode, with a key kept in the credential store
+// of the OS (see [newCacheKey]). The encrypted data is prefixed with its nonce.
+const cAesGcmEncryptionType encryptionType = "AES-GCM"
+
 // newCache creates a cache implementation that satisfies [cache.ExportReplace] from the MSAL library.
 //
 // root must be created beforehand, and must point to a directory.
-func newCache(root string) cache.ExportReplace {
+func newCache(root string, key *cacheKey) cache.ExportReplace {
 	return &msalCacheAdapter{
 		cache: &memoryCache{
 			cache: make(map[string][]byte),
-			inner: &fileCache{
-				prefix: "cache",
-				root:   root,
-				ext:    "json",
-			},
+			inner: newEncryptedFileCache("cache", root, key),
 		},
 	}
 }
@@ -29,13 +39,188 @@ func newCache(root string) cache.ExportReplace {
 // newCredentialCache creates a cache implementation for storing credentials.
 //
 // root must be created beforehand, and must point to a directory.
-func newCredentialCache(root string) Cache {
+func newCredentialCache(root string, key *cacheKey) Cache {
 	return &memoryCache{
 		cache: make(map[string][]byte),
-		inner: &fileCache{
-			prefix: "cred",
+		inner: newEncryptedFileCache("cred", root, key),
+	}
+}
+
+// newEncryptedFileCache creates a cache storing encrypted files named [prefix][key].bin in root. Files stored in plain
+// text by earlier versions of azd, named [prefix][key].json, are encrypted the first time they are read.
+func newEncryptedFileCache(prefix string, root string, key *cacheKey) Cache {
+	return &migratingCache{
+		inner: &encryptedCache{
+			inner: &fileCache{
+				prefix: prefix,
+				root:   root,
+				ext:    "bin",
+			},
+			key: key,
+		},
+		legacy: &fileCache{
+			prefix: prefix,
 			root:   root,
 			ext:    "json",
 		},
 	}
 }
+
+// newCacheKey creates the key the caches in authRoot are encrypted with, kept in the configured credential store.
+// When no credential store is configured, the credential store of the OS is used when it's available, otherwise the key
+// is stored in a file within authRoot.
+func newCacheKey(authRoot string, credentialStore string) (*cacheKey, error) {
+	fileStore := &fileKeyStore{path: filepath.Join(authRoot, "cache.key")}
+	key := &cacheKey{
+		store:    fileStore,
+		lockPath: filepath.Join(authRoot, "cache.key.lock"),
+	}
+
+	if credentialStore == cCredentialStoreFile {
+		return key, nil
+	}
+
+	keychain, err := newKeychainKeyStore()
+	if err != nil && credentialStore == cCredentialStoreKeychain {
+		return nil, fmt.Errorf(
+			"%w. To store credentials in files instead, run `azd config set %s %s`",
+			err, cCredentialStoreKey, cCredentialStoreFile)
+	} else if err != nil {
+		log.Printf("%v, storing the cache key in a file", err)
+		return key, nil
+	}
+
+	key.store = keychain
+	key.previous = fileStore
+	return key, nil
+}
+
+// encryptedCache is a Cache that wraps an existing Cache, encrypting and decrypting the cached value with AES-GCM
+type encryptedCache struct {
+	inner Cache
+	key   *cacheKey
+}
+
+func (c *encryptedCache) Read(key string) ([]byte, error) {
+	val, err := c.inner.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(val) == 0 {
+		return val, nil
+	}
+
+	var data envelopedData
+	if err := json.Unmarshal(val, &data); err != nil {
+		return nil, fmt.Errorf("unmarshalling enveloped data: %w", err)
+	}
+
+	if data.Type != cAesGcmEncryptionType {
+		return nil, fmt.Errorf("unsupported encryption type: %s", data.Type)
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(data.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64 data: %w", err)
+	}
+
+	gcm, err := c.cipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(encrypted) < gcm.NonceSize() {
+		return nil, errors.New("failed to decrypt data: data is too short")
+	}
+
+	nonce, ciphertext := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		// The data was encrypted with a different key, for example when the credential store changed. The cached
+		// data can't be recovered, treat it as missing so the user is asked to log in again.
+		log.Printf("failed to decrypt cached data, ignoring it: %v", err)
+		return nil, errCacheKeyNotFound
+	}
+
+	return plaintext, nil
+}
+
+func (c *encryptedCache) Set(key string, val []byte) error {
+	if len(val) == 0 {
+		return c.inner.Set(key, val)
+	}
+
+	gcm, err := c.cipher()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	toStore, err := json.Marshal(envelopedData{
+		Type: cAesGcmEncryptionType,
+		Data: base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, val, nil)),
+	})
+
+	// We never expect the above to fail.
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal enveloped data: %s", err))
+	}
+
+	return c.inner.Set(key, toStore)
+}
+
+func (c *encryptedCache) cipher() (cipher.AEAD, error) {
+	key, err := c.key.get()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// migratingCache is a Cache that moves the values of a legacy cache into the inner cache, the first time each value is
+// read. Values set in the inner cache remove the legacy value, so stale values aren't left behind.
+type migratingCache struct {
+	inner  Cache
+	legacy *fileCache
+}
+
+func (c *migratingCache) Read(key string) ([]byte, error) {
+	val, err := c.inner.Read(key)
+	if !errors.Is(err, errCacheKeyNotFound) {
+		return val, err
+	}
+
+	val, err = c.legacy.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Set(key, val); err != nil {
+		return nil, fmt.Errorf("migrating cached data: %w", err)
+	}
+
+	return val, nil
+}
+
+func (c *migratingCache) Set(key string, val []byte) error {
+	if err := c.inner.Set(key, val); err != nil {
+		return err
+	}
+
+	if err := c.legacy.remove(key); err != nil {
+		log.Printf("failed to remove legacy cached data: %v", err)
+	}
+
+	return nil
+}
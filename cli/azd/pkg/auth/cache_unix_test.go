@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build unix
+// +build unix
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedCache(t *testing.T) {
+	root := t.TempDir()
+	c := newCredentialCache(root, newTestCacheKey(root))
+
+	require.NoError(t, c.Set("d1", []byte("some secret")))
+
+	// the data is not stored in plain text.
+	contents, err := os.ReadFile(filepath.Join(root, "credd1.bin"))
+	require.NoError(t, err)
+	require.NotContains(t, string(contents), "some secret")
+
+	// data encrypted with another key is treated as missing.
+	otherRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(otherRoot, "credd1.bin"), contents, 0600))
+
+	c = newCredentialCache(otherRoot, newTestCacheKey(otherRoot))
+	_, err = c.Read("d1")
+	require.ErrorIs(t, err, errCacheKeyNotFound)
+}
+
+func TestEncryptedCacheMigration(t *testing.T) {
+	root := t.TempDir()
+	legacyPath := filepath.Join(root, "credd1.json")
+	require.NoError(t, os.WriteFile(legacyPath, []byte("legacy data"), 0600))
+
+	c := newCredentialCache(root, newTestCacheKey(root))
+	val, err := c.Read("d1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("legacy data"), val)
+
+	// the legacy file is replaced by the encrypted file.
+	require.NoFileExists(t, legacyPath)
+	require.FileExists(t, filepath.Join(root, "credd1.bin"))
+
+	c = newCredentialCache(root, newTestCacheKey(root))
+	val, err = c.Read("d1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("legacy data"), val)
+
+	// setting a value removes a stale legacy file.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "credd2.json"), []byte("stale data"), 0600))
+	require.NoError(t, c.Set("d2", []byte("new data")))
+	require.NoFileExists(t, filepath.Join(root, "credd2.json"))
+}
+
+func TestCacheKey(t *testing.T) {
+	t.Run("Created", func(t *testing.T) {
+		root := t.TempDir()
+		key := newTestCacheKey(root)
+
+		k1, err := key.get()
+		require.NoError(t, err)
+		require.Len(t, k1, cCacheKeySize)
+
+		// the key is read back from the store by other processes.
+		k2, err := newTestCacheKey(root).get()
+		require.NoError(t, err)
+		require.Equal(t, k1, k2)
+	})
+
+	t.Run("MovedFromPrevious", func(t *testing.T) {
+		root := t.TempDir()
+		previous, err := newTestCacheKey(root).get()
+		require.NoError(t, err)
+
+		key := &cacheKey{
+			store:    &fileKeyStore{path: filepath.Join(root, "keychain.key")},
+			previous: &fileKeyStore{path: filepath.Join(root, "cache.key")},
+			lockPath: filepath.Join(root, "cache.key.lock"),
+		}
+
+		moved, err := key.get()
+		require.NoError(t, err)
+		require.Equal(t, previous, moved)
+		require.NoFileExists(t, filepath.Join(root, "cache.key"))
+	})
+
+	t.Run("FileStore", func(t *testing.T) {
+		root := t.TempDir()
+		key, err := newCacheKey(root, cCredentialStoreFile)
+		require.NoError(t, err)
+		require.Equal(t, &fileKeyStore{path: filepath.Join(root, "cache.key")}, key.store)
+	})
+}
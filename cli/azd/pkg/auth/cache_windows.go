@@ -16,23 +16,18 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-// envelopedData stores both the type of encryption used as well as the encrypted data (as a base64 encoded string),
-// allowing us to change the underlying encryption algorithm as needed (and then understand what we need to do decrypt)
-type envelopedData struct {
-	// The type of encryption that was used to store data.
-	Type encryptionType `json:"type"`
-	// The encrypted data, represented as a Base64 encoded string (using base64.StdEncoding)
-	Data string `json:"data"`
-}
-
-type encryptionType string
-
 // cCryptProtectDataEncryptionType is the encryption type that uses CryptProtectData/CryptUnprotectData for
 // encryption and decryption.  See https://learn.microsoft.com/windows/win32/api/dpapi/nf-dpapi-cryptprotectdata
 // for more information on these APIs.
 const cCryptProtectDataEncryptionType encryptionType = "CryptProtectData"
 
-func newCache(root string) cache.ExportReplace {
+// newCacheKey returns no key on Windows, where caches are encrypted with CryptProtectData, which is bound to the
+// credentials of the current user. The credential store in config doesn't apply.
+func newCacheKey(_ string, _ string) (*cacheKey, error) {
+	return nil, nil
+}
+
+func newCache(root string, _ *cacheKey) cache.ExportReplace {
 	return &msalCacheAdapter{
 		cache: &memoryCache{
 			cache: make(map[string][]byte),
@@ -47,7 +42,7 @@ func newCache(root string) cache.ExportReplace {
 	}
 }
 
-func newCredentialCache(root string) Cache {
+func newCredentialCache(root string, _ *cacheKey) Cache {
 	return &memoryCache{
 		cache: make(map[string][]byte),
 		inner: &encryptedCache{
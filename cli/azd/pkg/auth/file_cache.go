@@ -72,3 +72,25 @@ func (c *fileCache) pathForCache(key string) string {
 func (c *fileCache) pathForLock(key string) string {
 	return filepath.Join(c.root, fmt.Sprintf("%s%s.%s.lock", c.prefix, key, c.ext))
 }
+
+// remove deletes the stored object of key, if any.
+func (c *fileCache) remove(key string) error {
+	lockPath := c.pathForLock(key)
+
+	fl := flock.New(lockPath)
+
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking file %s: %w", lockPath, err)
+	}
+	defer func() {
+		if err := fl.Unlock(); err != nil {
+			log.Printf("failed to release file lock: %v", err)
+		}
+	}()
+
+	if err := os.Remove(c.pathForCache(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/gofrs/flock"
+)
+
+// The service and account the key is stored under in the credential store of the OS
+const cKeychainService = "azd"
+const cKeychainAccount = "cache-key"
+
+// The size of the keys encrypting the caches, in bytes. (AES-256)
+const cCacheKeySize = 32
+
+// keyStore stores the key the caches of azd are encrypted with.
+type keyStore interface {
+	// Read returns the stored key, or errCacheKeyNotFound when no key has been stored yet.
+	Read() ([]byte, error)
+	Write(key []byte) error
+}
+
+// fileKeyStore stores the key in a file only readable by the current user. It's used on systems without a credential
+// store, such as headless Linux machines.
+type fileKeyStore struct {
+	path string
+}
+
+func (s *fileKeyStore) Read() ([]byte, error) {
+	key, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errCacheKeyNotFound
+	}
+
+	return key, err
+}
+
+func (s *fileKeyStore) Write(key []byte) error {
+	return os.WriteFile(s.path, key, osutil.PermissionFileOwnerOnly)
+}
+
+// cacheKey lazily loads the key the caches are encrypted with from its store, creating the key on first use.
+// The same cacheKey is shared by all the caches of the process.
+type cacheKey struct {
+	store keyStore
+	// The store the key was kept in before azd could use the credential store of the OS, if any. The key is moved
+	// from it on first use, so that the caches it encrypted can still be read.
+	previous *fileKeyStore
+	lockPath string
+	key      []byte
+}
+
+func (k *cacheKey) get() ([]byte, error) {
+	if k.key != nil {
+		return k.key, nil
+	}
+
+	// Hold a lock while creating the key, so that concurrent azd processes don't each create a different key
+	fl := flock.New(k.lockPath)
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("locking file %s: %w", k.lockPath, err)
+	}
+	defer func() {
+		if err := fl.Unlock(); err != nil {
+			log.Printf("failed to release file lock: %v", err)
+		}
+	}()
+
+	key, err := k.store.Read()
+	if err == nil && len(key) == cCacheKeySize {
+		k.key = key
+		return key, nil
+	} else if err != nil && !errors.Is(err, errCacheKeyNotFound) {
+		return nil, fmt.Errorf("reading cache key: %w", err)
+	}
+
+	key, err = k.previousKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		key = make([]byte, cCacheKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generating cache key: %w", err)
+		}
+	}
+
+	if err := k.store.Write(key); err != nil {
+		return nil, fmt.Errorf("writing cache key: %w", err)
+	}
+
+	// Some credential stores don't report failures when writing, read the key back to ensure it was stored.
+	stored, err := k.store.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading cache key: %w", err)
+	}
+
+	if !bytes.Equal(stored, key) {
+		return nil, errors.New("the cache key could not be stored")
+	}
+
+	if k.previous != nil {
+		if err := os.Remove(k.previous.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed to remove previous cache key: %v", err)
+		}
+	}
+
+	k.key = key
+	return key, nil
+}
+
+// previousKey returns the key kept in the previous store, or nil when there is none.
+func (k *cacheKey) previousKey() ([]byte, error) {
+	if k.previous == nil {
+		return nil, nil
+	}
+
+	key, err := k.previous.Read()
+	if errors.Is(err, errCacheKeyNotFound) || (err == nil && len(key) != cCacheKeySize) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading previous cache key: %w", err)
+	}
+
+	log.Printf("moving the cache key to the credential store")
+	return key, nil
+}
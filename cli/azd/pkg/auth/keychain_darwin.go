@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build darwin
+// +build darwin
+
+package auth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// The exit code of `security find-generic-password` when the item doesn't exist (errSecItemNotFound)
+const cSecurityItemNotFoundExitCode = 44
+
+// keychainKeyStore stores the key as a generic password of the login keychain, using the `security` tool.
+type keychainKeyStore struct {
+	path string
+}
+
+func newKeychainKeyStore() (keyStore, error) {
+	path, err := exec.LookPath("security")
+	if err != nil {
+		return nil, fmt.Errorf("the macOS keychain is not available: %w", err)
+	}
+
+	return &keychainKeyStore{path: path}, nil
+}
+
+func (s *keychainKeyStore) Read() ([]byte, error) {
+	var stderr bytes.Buffer
+	//nolint:gosec
+	cmd := exec.Command(s.path, "find-generic-password", "-s", cKeychainService, "-a", cKeychainAccount, "-w")
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == cSecurityItemNotFoundExitCode {
+		return nil, errCacheKeyNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("reading from the keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (s *keychainKeyStore) Write(key []byte) error {
+	var stderr bytes.Buffer
+	// The command is passed on stdin, so the key isn't visible in the arguments of the process.
+	//nolint:gosec
+	cmd := exec.Command(s.path, "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf(
+		"add-generic-password -U -s %s -a %s -l %s -w %s\n",
+		cKeychainService, cKeychainAccount, cKeychainService, hex.EncodeToString(key)))
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("writing to the keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
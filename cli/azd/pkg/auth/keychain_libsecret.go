@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build unix && !darwin
+// +build unix,!darwin
+
+package auth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// keychainKeyStore stores the key in the Secret Service of the desktop (e.g. GNOME Keyring or KWallet) through
+// libsecret, using the `secret-tool` tool.
+type keychainKeyStore struct {
+	path string
+}
+
+func newKeychainKeyStore() (keyStore, error) {
+	// The Secret Service is reached over the D-Bus session bus, which headless systems usually don't run
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return nil, errors.New("the Secret Service is not available: no D-Bus session bus")
+	}
+
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return nil, fmt.Errorf("the Secret Service is not available: %w", err)
+	}
+
+	return &keychainKeyStore{path: path}, nil
+}
+
+func (s *keychainKeyStore) Read() ([]byte, error) {
+	var stderr bytes.Buffer
+	//nolint:gosec
+	cmd := exec.Command(s.path, "lookup", "service", cKeychainService, "account", cKeychainAccount)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+
+	// secret-tool exits with 1, without an error message, when no secret matches
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+		return nil, errCacheKeyNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("reading from the Secret Service: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (s *keychainKeyStore) Write(key []byte) error {
+	var stderr bytes.Buffer
+	// The secret is read from stdin, so the key isn't visible in the arguments of the process.
+	//nolint:gosec
+	cmd := exec.Command(
+		s.path, "store", "--label", cKeychainService, "service", cKeychainService, "account", cKeychainAccount)
+	cmd.Stdin = strings.NewReader(hex.EncodeToString(key))
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("writing to the Secret Service: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
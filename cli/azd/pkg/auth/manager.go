@@ -24,6 +24,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/github"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 )
@@ -41,6 +42,17 @@ const cCurrentUserKey = "auth.account.currentUser"
 // it ourselves. The value should be a string as specified by [strconv.ParseBool].
 const cUseAzCliAuthKey = "auth.useAzCliAuth"
 
+// cCredentialStoreKey is the key we use in config to choose where the key encrypting the auth caches is stored, on Linux
+// and macOS. The value should be [cCredentialStoreKeychain] or [cCredentialStoreFile]. When unset, the credential store of
+// the OS is used when it's available. On Windows, the caches are always encrypted with CryptProtectData.
+const cCredentialStoreKey = "auth.credentialStore"
+
+// Stores the key in the credential store of the OS: the macOS keychain or the Secret Service through libsecret.
+const cCredentialStoreKeychain = "keychain"
+
+// Stores the key in a file only readable by the current user, for headless systems without a credential store.
+const cCredentialStoreFile = "file"
+
 // cAuthConfigFileName is the name of the file we store in the user configuration directory which is used to persist
 // auth related configuration information (e.g. the home account id of the current user). This information is not secret.
 const cAuthConfigFileName = "auth.json"
@@ -63,7 +75,8 @@ var loginScopesMap = map[string]struct{}{
 // principal. Manager stores information so that the user can stay logged in across invocations of the CLI. When logged in
 // as a user (either interactively or via a device code flow), we provide a durable cache to MSAL which is used to cache
 // information to allow silent logins across process runs. This cache is stored inside the user's home directory, ACL'd such
-// that it can only be read by the current user.  In addition, on Windows, this cache is encrypted, using CryptProtectData.
+// that it can only be read by the current user. In addition, this cache is encrypted: on Windows using CryptProtectData, and
+// on Linux and macOS with a key kept in the credential store of the OS (see [cCredentialStoreKey]).
 // The home account id of the signed in user is stored as a property under [cCurrentUserKey]. This behavior matches the
 // AZ CLI.
 //
@@ -104,8 +117,23 @@ func NewManager(
 		return nil, fmt.Errorf("creating msal cache root: %w", err)
 	}
 
+	userConfig, err := userConfigManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	credentialStore, err := credentialStoreFromConfig(userConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newCacheKey(authRoot, credentialStore)
+	if err != nil {
+		return nil, err
+	}
+
 	options := []public.Option{
-		public.WithCache(newCache(cacheRoot)),
+		public.WithCache(newCache(cacheRoot, key)),
 		public.WithAuthority(cDefaultAuthority),
 	}
 
@@ -121,7 +149,7 @@ func NewManager(
 		publicClientOptions: options,
 		configManager:       configManager,
 		userConfigManager:   userConfigManager,
-		credentialCache:     newCredentialCache(authRoot),
+		credentialCache:     newCredentialCache(authRoot, key),
 		ghClient:            ghClient,
 		httpClient:          httpClient,
 	}, nil
@@ -252,6 +280,22 @@ func shouldUseLegacyAuth(cfg config.Config) bool {
 	return false
 }
 
+// credentialStoreFromConfig returns the credential store set in config, if any.
+func credentialStoreFromConfig(cfg config.Config) (string, error) {
+	value, has := cfg.Get(cCredentialStoreKey)
+	if !has {
+		return "", nil
+	}
+
+	if store, ok := value.(string); ok && (store == cCredentialStoreKeychain || store == cCredentialStoreFile) {
+		return store, nil
+	}
+
+	return "", fmt.Errorf(
+		"invalid value for %s: '%v'. The value must be '%s' or '%s'",
+		cCredentialStoreKey, value, cCredentialStoreKeychain, cCredentialStoreFile)
+}
+
 func shouldUseCloudShellAuth() bool {
 	if useCloudShellAuth, has := os.LookupEnv(cUseCloudShellAuthEnvVar); has {
 		if use, err := strconv.ParseBool(useCloudShellAuth); err == nil && use {
@@ -364,22 +408,34 @@ func (m *Manager) newCredentialFromFederatedTokenProvider(
 	clientID string,
 	provider federatedTokenProvider,
 ) (azcore.TokenCredential, error) {
-	if provider != gitHubFederatedAuth {
-		return nil, fmt.Errorf("unsupported federated token provider: '%s'", string(provider))
-	}
-
-	cred, err := azidentity.NewClientAssertionCredential(
-		tenantID,
-		clientID,
-		func(ctx context.Context) (string, error) {
+	var getAssertion func(ctx context.Context) (string, error)
+	switch provider {
+	case gitHubFederatedAuth:
+		getAssertion = func(ctx context.Context) (string, error) {
 			federatedToken, err := m.ghClient.TokenForAudience(ctx, "api://AzureADTokenExchange")
 			if err != nil {
 				return "", fmt.Errorf("fetching federated token: %w", err)
 			}
 
 			return federatedToken, nil
-		},
-		nil)
+		}
+	case gitLabFederatedAuth:
+		// GitLab jobs receive their ID tokens in the variables declared by the `id_tokens` of the job
+		getAssertion = func(ctx context.Context) (string, error) {
+			federatedToken := os.Getenv(gitlab.IdTokenEnvVarName)
+			if federatedToken == "" {
+				return "", fmt.Errorf(
+					"fetching federated token: %s is not set, declare it in the id_tokens of the GitLab job",
+					gitlab.IdTokenEnvVarName)
+			}
+
+			return federatedToken, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported federated token provider: '%s'", string(provider))
+	}
+
+	cred, err := azidentity.NewClientAssertionCredential(tenantID, clientID, getAssertion, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating credential: %w", err)
 	}
@@ -501,7 +557,8 @@ func (m *Manager) LoginWithServicePrincipalCertificate(
 func (m *Manager) LoginWithServicePrincipalFederatedTokenProvider(
 	ctx context.Context, tenantId, clientId, provider string,
 ) (azcore.TokenCredential, error) {
-	cred, err := m.newCredentialFromFederatedTokenProvider(tenantId, clientId, federatedTokenProvider(provider))
+	tokenProvider := federatedTokenProvider(provider)
+	cred, err := m.newCredentialFromFederatedTokenProvider(tenantId, clientId, tokenProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -511,7 +568,7 @@ func (m *Manager) LoginWithServicePrincipalFederatedTokenProvider(
 		clientId,
 		&persistedSecret{
 			FederatedAuth: &federatedAuth{
-				TokenProvider: &gitHubFederatedAuth,
+				TokenProvider: &tokenProvider,
 			},
 		},
 	); err != nil {
@@ -738,6 +795,7 @@ type persistedSecret struct {
 // federated auth token providers
 var (
 	gitHubFederatedAuth federatedTokenProvider = "github"
+	gitLabFederatedAuth federatedTokenProvider = "gitlab"
 )
 
 // token provider for federated auth
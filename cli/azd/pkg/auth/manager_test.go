@@ -24,6 +24,22 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestCredentialStoreFromConfig(t *testing.T) {
+	store, err := credentialStoreFromConfig(config.NewEmptyConfig())
+	require.NoError(t, err)
+	require.Equal(t, "", store)
+
+	cfg := config.NewEmptyConfig()
+	require.NoError(t, cfg.Set(cCredentialStoreKey, "file"))
+	store, err = credentialStoreFromConfig(cfg)
+	require.NoError(t, err)
+	require.Equal(t, cCredentialStoreFile, store)
+
+	require.NoError(t, cfg.Set(cCredentialStoreKey, "vault"))
+	_, err = credentialStoreFromConfig(cfg)
+	require.Error(t, err)
+}
+
 func TestReadUserProperties(t *testing.T) {
 	t.Run("homeID", func(t *testing.T) {
 		cfg := config.NewEmptyConfig()
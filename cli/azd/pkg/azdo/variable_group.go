@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azdo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/taskagent"
+)
+
+// The type of variable groups holding their variables, as opposed to groups linked to a Key Vault
+const variableGroupTypeVsts = "Vsts"
+
+// VariableGroupValue is a variable of a variable group of the pipeline library
+type VariableGroupValue struct {
+	Value    string
+	IsSecret bool
+}
+
+// SetVariableGroupVariables creates the variable group of the project, or updates the existing group, setting the
+// variables. Variables of an existing group that are not set are kept.
+func SetVariableGroupVariables(
+	ctx context.Context,
+	projectName string,
+	groupName string,
+	variables map[string]VariableGroupValue,
+	connection *azuredevops.Connection,
+) (*taskagent.VariableGroup, error) {
+	client, err := taskagent.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := client.GetVariableGroups(ctx, taskagent.GetVariableGroupsArgs{
+		Project:   &projectName,
+		GroupName: &groupName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting variable group '%s': %w", groupName, err)
+	}
+
+	groupVariables := map[string]interface{}{}
+	var existing *taskagent.VariableGroup
+	if groups != nil && len(*groups) > 0 {
+		existing = &(*groups)[0]
+		if existing.Variables != nil {
+			for name, value := range *existing.Variables {
+				groupVariables[name] = value
+			}
+		}
+	}
+
+	for name, variable := range variables {
+		value := variable.Value
+		isSecret := variable.IsSecret
+		groupVariables[name] = taskagent.VariableValue{
+			Value:    &value,
+			IsSecret: &isSecret,
+		}
+	}
+
+	groupType := variableGroupTypeVsts
+	description := "Values of the azd environment, synced by azd env sync"
+	parameters := &taskagent.VariableGroupParameters{
+		Name:        &groupName,
+		Description: &description,
+		Type:        &groupType,
+		Variables:   &groupVariables,
+	}
+
+	if existing == nil {
+		group, err := client.AddVariableGroup(ctx, taskagent.AddVariableGroupArgs{
+			Project: &projectName,
+			Group:   parameters,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating variable group '%s': %w", groupName, err)
+		}
+		return group, nil
+	}
+
+	group, err := client.UpdateVariableGroup(ctx, taskagent.UpdateVariableGroupArgs{
+		Project: &projectName,
+		GroupId: existing.Id,
+		Group:   parameters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("updating variable group '%s': %w", groupName, err)
+	}
+	return group, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package azdtest exports the fakes azd uses in its own unit tests, so extension and integration authors can unit-test
+// code depending on azd packages without running commands or calling Azure.
+//
+// The fakes are maintained with azd: when exec.CommandRunner, azcli.AzCli or the credential providers change, the
+// fakes exported here change with them.
+//
+//	mockContext := azdtest.NewContext(context.Background())
+//	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+//		return strings.HasPrefix(command, "docker build")
+//	}).Respond(exec.NewRunResult(0, "sha256:abc", ""))
+//
+//	azCli := azdtest.NewAzCliFromContext(mockContext)
+package azdtest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockhttp"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+)
+
+// Context bundles a fake of each of the common dependencies of azd packages, along with an IoC container resolving them
+type Context = mocks.MockContext
+
+// CommandRunner is a fake exec.CommandRunner responding to the commands matching the registered predicates
+type CommandRunner = mockexec.MockCommandRunner
+
+// HttpClient is a fake httputil.HttpClient responding to the requests matching the registered predicates, used as
+// the transport of the Azure SDK clients
+type HttpClient = mockhttp.MockHttpClient
+
+// Console is a fake input.Console answering prompts with the registered responses
+type Console = mockinput.MockConsole
+
+// Credential is a fake azcore.TokenCredential
+type Credential = mocks.MockCredentials
+
+// SubscriptionCredentialProvider is a fake account.SubscriptionCredentialProvider returning a Credential
+type SubscriptionCredentialProvider = mocks.MockSubscriptionCredentialProvider
+
+// MultiTenantCredentialProvider is a fake auth.MultiTenantCredentialProvider returning a Credential per tenant
+type MultiTenantCredentialProvider = mocks.MockMultiTenantCredentialProvider
+
+// NewContext creates a Context whose context.Context uses the fake HttpClient
+func NewContext(ctx context.Context) *Context {
+	return mocks.NewMockContext(ctx)
+}
+
+// NewCommandRunner creates a CommandRunner without any registered commands. Running an unregistered command panics.
+func NewCommandRunner() *CommandRunner {
+	return mockexec.NewMockCommandRunner()
+}
+
+// NewHttpClient creates a HttpClient without any registered requests. Sending an unregistered request panics.
+func NewHttpClient() *HttpClient {
+	return mockhttp.NewMockHttpUtil()
+}
+
+// NewConsole creates a Console without any registered prompt responses
+func NewConsole() *Console {
+	return mockinput.NewMockConsole()
+}
+
+// NewAzCli creates an azcli.AzCli sending its requests with the HttpClient, authenticated with the credential
+func NewAzCli(credential azcore.TokenCredential, httpClient httputil.HttpClient) azcli.AzCli {
+	return azcli.NewAzCli(
+		mockaccount.SubscriptionCredentialProviderFunc(func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return credential, nil
+		}),
+		httpClient,
+		azcli.NewAzCliArgs{},
+	)
+}
+
+// NewAzCliFromContext creates an azcli.AzCli using the credential & HttpClient of the Context
+func NewAzCliFromContext(mockContext *Context) azcli.AzCli {
+	return NewAzCli(mockContext.Credentials, mockContext.HttpClient)
+}
+
+// RespondWithJson creates an HTTP response to the request with the status code & the JSON of the body
+func RespondWithJson[T any](request *http.Request, statusCode int, body T) (*http.Response, error) {
+	return mocks.CreateHttpResponseWithBody(request, statusCode, body)
+}
+
+// RespondEmpty creates an HTTP response to the request with the status code & no body
+func RespondEmpty(request *http.Request, statusCode int) (*http.Response, error) {
+	return mocks.CreateEmptyHttpResponse(request, statusCode)
+}
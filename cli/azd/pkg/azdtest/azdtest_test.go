@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azdtest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/stretchr/testify/require"
+)
+
+// The fakes implement the azd interfaces they stand in for
+var (
+	_ exec.CommandRunner                     = (*CommandRunner)(nil)
+	_ httputil.HttpClient                    = (*HttpClient)(nil)
+	_ input.Console                          = (*Console)(nil)
+	_ azcore.TokenCredential                 = (*Credential)(nil)
+	_ account.SubscriptionCredentialProvider = (*SubscriptionCredentialProvider)(nil)
+	_ auth.MultiTenantCredentialProvider     = (*MultiTenantCredentialProvider)(nil)
+)
+
+func Test_CommandRunner(t *testing.T) {
+	runner := NewCommandRunner()
+	runner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "docker version")
+	}).Respond(exec.NewRunResult(0, "24.0.6", ""))
+
+	result, err := runner.Run(context.Background(), exec.NewRunArgs("docker", "version"))
+	require.NoError(t, err)
+	require.Equal(t, "24.0.6", result.Stdout)
+}
+
+func Test_AzCli(t *testing.T) {
+	mockContext := NewContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourceGroups/rg/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return RespondWithJson(request, http.StatusOK, armresources.ResourceListResult{
+			Value: []*armresources.GenericResourceExpanded{
+				{
+					ID:       convert.RefOf("/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg/providers/Microsoft.Web/sites/app"),
+					Name:     convert.RefOf("app"),
+					Type:     convert.RefOf("Microsoft.Web/sites"),
+					Location: convert.RefOf("eastus2"),
+				},
+			},
+		})
+	})
+
+	azCli := NewAzCliFromContext(mockContext)
+	resources, err := azCli.ListResourceGroupResources(*mockContext.Context, "SUBSCRIPTION_ID", "rg", nil)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Equal(t, "app", resources[0].Name)
+}
@@ -0,0 +1,376 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package recording records the HTTP interactions of a test with Azure and replays them in later runs, so tests
+// exercising Azure SDK clients or azcli.AzCli are hermetic.
+//
+// The mode is selected with the AZD_TEST_HTTP_MODE environment variable:
+//   - playback (default) replays the recording of the test, failing requests that were not recorded
+//   - record sends the requests to Azure and records them, replacing the recording of the test
+//   - live sends the requests to Azure without recording them
+//
+// Sanitizers remove secrets & identifiers from the interactions before they are saved:
+//
+//	recorder := recording.Start(t, recording.WithSanitizers(
+//		recording.ReplaceText(subscriptionId, "SUBSCRIPTION_ID"),
+//	))
+//	azCli := azcli.NewAzCli(credentialProvider, recorder, azcli.NewAzCliArgs{})
+package recording
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
+)
+
+// The environment variable selecting the Mode of the recorders
+const ModeEnvVarName = "AZD_TEST_HTTP_MODE"
+
+type Mode string
+
+const (
+	ModePlayback Mode = "playback"
+	ModeRecord   Mode = "record"
+	ModeLive     Mode = "live"
+)
+
+// The value replacing redacted text
+const Redacted = "REDACTED"
+
+// Interaction is a request sent to Azure and the response received
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+type Request struct {
+	Method  string      `yaml:"method"`
+	Url     string      `yaml:"url"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `yaml:"statusCode"`
+	Headers    http.Header `yaml:"headers,omitempty"`
+	Body       string      `yaml:"body,omitempty"`
+}
+
+// Cassette is the recording of the interactions of a test
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+// Sanitizer removes secrets & identifiers from an interaction. Sanitizers are applied to the interactions before they
+// are recorded, and to the requests before they are matched against the recording.
+type Sanitizer func(interaction *Interaction)
+
+// Matcher returns whether the request matches the recorded request
+type Matcher func(request Request, recorded Request) bool
+
+// DefaultMatcher matches the requests with the same method & URL
+func DefaultMatcher(request Request, recorded Request) bool {
+	return request.Method == recorded.Method && request.Url == recorded.Url
+}
+
+// The headers never recorded, they carry credentials
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+type options struct {
+	mode       Mode
+	path       string
+	sanitizers []Sanitizer
+	matcher    Matcher
+	transport  http.RoundTripper
+}
+
+type Option func(*options)
+
+// WithMode overrides the mode of AZD_TEST_HTTP_MODE
+func WithMode(mode Mode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithPath overrides the path of the recording, testdata/recordings/<test name>.yaml by default
+func WithPath(path string) Option {
+	return func(o *options) {
+		o.path = path
+	}
+}
+
+// WithSanitizers adds sanitizers, applied after the sanitizer redacting credential headers
+func WithSanitizers(sanitizers ...Sanitizer) Option {
+	return func(o *options) {
+		o.sanitizers = append(o.sanitizers, sanitizers...)
+	}
+}
+
+// WithMatcher overrides DefaultMatcher
+func WithMatcher(matcher Matcher) Option {
+	return func(o *options) {
+		o.matcher = matcher
+	}
+}
+
+// WithTransport overrides the transport sending the requests in record & live modes, http.DefaultTransport by default
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// Recorder records or replays the HTTP interactions of a test. Recorder implements both http.RoundTripper and
+// httputil.HttpClient, it can be used as the transport of an http.Client or of the Azure SDK clients.
+type Recorder struct {
+	t       testing.TB
+	options options
+
+	mu       sync.Mutex
+	cassette Cassette
+	// Whether each recorded interaction has been replayed
+	replayed []bool
+}
+
+// Start starts a recorder for the test. In record mode, the recording is saved when the test completes.
+func Start(t testing.TB, opts ...Option) *Recorder {
+	t.Helper()
+
+	o := options{
+		mode:      Mode(strings.ToLower(os.Getenv(ModeEnvVarName))),
+		path:      filepath.Join("testdata", "recordings", recordingName(t.Name())+".yaml"),
+		matcher:   DefaultMatcher,
+		transport: http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.mode == "" {
+		o.mode = ModePlayback
+	}
+
+	o.sanitizers = append([]Sanitizer{RedactHeaders(sensitiveHeaders...)}, o.sanitizers...)
+
+	r := &Recorder{
+		t:       t,
+		options: o,
+	}
+
+	switch o.mode {
+	case ModePlayback:
+		contents, err := os.ReadFile(o.path)
+		if err != nil {
+			t.Fatalf("reading recording: %v. Run the test with %s=%s to record it.", err, ModeEnvVarName, ModeRecord)
+		}
+
+		if err := yaml.Unmarshal(contents, &r.cassette); err != nil {
+			t.Fatalf("unmarshalling recording '%s': %v", o.path, err)
+		}
+
+		r.replayed = make([]bool, len(r.cassette.Interactions))
+	case ModeRecord:
+		t.Cleanup(func() {
+			if err := r.save(); err != nil {
+				t.Errorf("saving recording: %v", err)
+			}
+		})
+	case ModeLive:
+	default:
+		t.Fatalf("unknown %s '%s', expected one of playback, record or live", ModeEnvVarName, o.mode)
+	}
+
+	return r
+}
+
+// Mode returns the mode of the recorder, tests can skip assertions on values only known in live runs
+func (r *Recorder) Mode() Mode {
+	return r.options.mode
+}
+
+// Do implements httputil.HttpClient
+func (r *Recorder) Do(request *http.Request) (*http.Response, error) {
+	return r.RoundTrip(request)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.options.mode == ModePlayback {
+		return r.replay(request, requestBody)
+	}
+
+	response, err := r.options.transport.RoundTrip(request)
+	if err != nil || r.options.mode == ModeLive {
+		return response, err
+	}
+
+	responseBody, err := readBody(&response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  request.Method,
+			Url:     request.URL.String(),
+			Headers: request.Header.Clone(),
+			Body:    requestBody,
+		},
+		Response: Response{
+			StatusCode: response.StatusCode,
+			Headers:    response.Header.Clone(),
+			Body:       responseBody,
+		},
+	}
+	r.sanitize(&interaction)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return response, nil
+}
+
+func (r *Recorder) replay(request *http.Request, requestBody string) (*http.Response, error) {
+	interaction := Interaction{
+		Request: Request{
+			Method:  request.Method,
+			Url:     request.URL.String(),
+			Headers: request.Header.Clone(),
+			Body:    requestBody,
+		},
+	}
+	r.sanitize(&interaction)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Interactions are replayed in the order they were recorded, a request sent multiple times, for example when
+	// polling, receives each recorded response in turn
+	for i, recorded := range r.cassette.Interactions {
+		if r.replayed[i] || !r.options.matcher(interaction.Request, recorded.Request) {
+			continue
+		}
+
+		r.replayed[i] = true
+
+		return &http.Response{
+			StatusCode: recorded.Response.StatusCode,
+			Status:     fmt.Sprintf("%d %s", recorded.Response.StatusCode, http.StatusText(recorded.Response.StatusCode)),
+			Header:     recorded.Response.Headers.Clone(),
+			Body:       io.NopCloser(strings.NewReader(recorded.Response.Body)),
+			Request:    request,
+		}, nil
+	}
+
+	return nil, fmt.Errorf(
+		"no recorded interaction matches %s %s. Run the test with %s=%s to update the recording",
+		interaction.Request.Method, interaction.Request.Url, ModeEnvVarName, ModeRecord)
+}
+
+func (r *Recorder) sanitize(interaction *Interaction) {
+	for _, sanitizer := range r.options.sanitizers {
+		sanitizer(interaction)
+	}
+}
+
+func (r *Recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	contents, err := yaml.Marshal(r.cassette)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.options.path), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.options.path, contents, osutil.PermissionFile)
+}
+
+// RedactHeaders replaces the values of the request & response headers with Redacted
+func RedactHeaders(names ...string) Sanitizer {
+	return func(interaction *Interaction) {
+		for _, headers := range []http.Header{interaction.Request.Headers, interaction.Response.Headers} {
+			for _, name := range names {
+				if headers.Get(name) != "" {
+					headers.Set(name, Redacted)
+				}
+			}
+		}
+	}
+}
+
+// ReplaceText replaces the text in the URLs, headers and bodies of the interaction, ex. replacing a subscription id
+// with SUBSCRIPTION_ID
+func ReplaceText(old string, new string) Sanitizer {
+	return replace(func(s string) string {
+		return strings.ReplaceAll(s, old, new)
+	})
+}
+
+// ReplaceRegex replaces the matches of the expression in the URLs, headers and bodies of the interaction
+func ReplaceRegex(expression *regexp.Regexp, replacement string) Sanitizer {
+	return replace(func(s string) string {
+		return expression.ReplaceAllString(s, replacement)
+	})
+}
+
+func replace(fn func(string) string) Sanitizer {
+	return func(interaction *Interaction) {
+		interaction.Request.Url = fn(interaction.Request.Url)
+		interaction.Request.Body = fn(interaction.Request.Body)
+		interaction.Response.Body = fn(interaction.Response.Body)
+
+		for _, headers := range []http.Header{interaction.Request.Headers, interaction.Response.Headers} {
+			for name, values := range headers {
+				for i := range values {
+					values[i] = fn(values[i])
+				}
+				headers[name] = values
+			}
+		}
+	}
+}
+
+// readBody reads the body, replacing it with a copy so it can still be read by the caller
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+
+	contents, err := io.ReadAll(*body)
+	closeErr := (*body).Close()
+	if err := errors.Join(err, closeErr); err != nil {
+		return "", fmt.Errorf("reading body: %w", err)
+	}
+
+	*body = io.NopCloser(bytes.NewReader(contents))
+	return string(contents), nil
+}
+
+// Subtests are recorded in directories named after their parent tests
+var invalidRecordingNameChars = regexp.MustCompile(`[^a-zA-Z0-9_/\-.]`)
+
+func recordingName(testName string) string {
+	return invalidRecordingNameChars.ReplaceAllString(testName, "_")
+}
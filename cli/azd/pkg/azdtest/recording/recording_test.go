@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package recording
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordAndPlayback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.yaml")
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("x-ms-request-id", "1234")
+		_, _ = w.Write([]byte(`{"id":"/subscriptions/faa080af-c1d8-40ad-9cce-e1a450ca5b57/resourceGroups/rg"}`))
+	}))
+	defer server.Close()
+
+	sanitizers := WithSanitizers(
+		ReplaceText("faa080af-c1d8-40ad-9cce-e1a450ca5b57", "SUBSCRIPTION_ID"),
+		ReplaceText(server.URL, "https://management.azure.com"),
+	)
+
+	t.Run("Record", func(t *testing.T) {
+		recorder := Start(t, WithMode(ModeRecord), WithPath(path), sanitizers)
+
+		request, err := http.NewRequest(
+			http.MethodGet, server.URL+"/subscriptions/faa080af-c1d8-40ad-9cce-e1a450ca5b57/resourceGroups/rg", nil)
+		require.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer secret")
+
+		response, err := recorder.Do(request)
+		require.NoError(t, err)
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		// The caller receives the live response
+		require.Contains(t, string(body), "faa080af-c1d8-40ad-9cce-e1a450ca5b57")
+	})
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(contents), "secret")
+	require.NotContains(t, string(contents), "faa080af-c1d8-40ad-9cce-e1a450ca5b57")
+	require.NotContains(t, string(contents), server.URL)
+
+	t.Run("Playback", func(t *testing.T) {
+		recorder := Start(t, WithMode(ModePlayback), WithPath(path), sanitizers)
+		client := &http.Client{Transport: recorder}
+
+		response, err := client.Get("https://management.azure.com/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "1234", response.Header.Get("x-ms-request-id"))
+
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"id":"/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg"}`, string(body))
+
+		// Each recorded interaction is replayed once
+		_, err = client.Get("https://management.azure.com/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg")
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "no recorded interaction matches"))
+	})
+
+	require.Equal(t, 1, calls)
+}
+
+func Test_RecordingName(t *testing.T) {
+	require.Equal(t, "Test_Deploy/Container_App", recordingName("Test_Deploy/Container App"))
+}
@@ -0,0 +1,419 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package scenario runs end-to-end tests of azd templates, so template repositories can verify in their own CI that
+// the template provisions, deploys and serves requests.
+//
+// A scenario creates a new azd environment in the template directory and tears the environment down, along with its
+// Azure resources, when the test completes, even when the test fails:
+//
+//	func TestTemplate(t *testing.T) {
+//		s := scenario.New(t, scenario.WithDir(".."))
+//		s.Up()
+//
+//		endpoint := s.EnvValue("WEB_URI")
+//		s.PollEndpoint(endpoint, scenario.WithBodyContains("Todo"))
+//	}
+//
+// The azd binary is found on the PATH, or set with the AZD_TEST_AZD_PATH environment variable. The subscription &
+// location of the environment default to the AZURE_SUBSCRIPTION_ID & AZURE_LOCATION environment variables.
+package scenario
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/test/azdcli"
+)
+
+const (
+	// The environment variable setting the path of the azd binary, azd is found on the PATH by default
+	AzdPathEnvVarName = "AZD_TEST_AZD_PATH"
+	// The environment variable that, when true, keeps the environment & its Azure resources after the test
+	KeepResourcesEnvVarName = "AZD_TEST_KEEP_RESOURCES"
+)
+
+// The time given to tear down the environment of the scenario
+const teardownTimeout = 30 * time.Minute
+
+// Scenario is an azd environment of a template used by an end-to-end test
+type Scenario struct {
+	t            *testing.T
+	ctx          context.Context
+	cli          *azdcli.CLI
+	envName      string
+	subscription string
+	location     string
+	provisioned  bool
+}
+
+// Option configures a Scenario
+type Option func(s *Scenario)
+
+// WithDir sets the directory of the template, the directory containing azure.yaml. Defaults to the working directory.
+func WithDir(dir string) Option {
+	return func(s *Scenario) {
+		s.cli.WorkingDirectory = dir
+	}
+}
+
+// WithAzdPath sets the path of the azd binary
+func WithAzdPath(path string) Option {
+	return func(s *Scenario) {
+		s.cli.AzdPath = path
+	}
+}
+
+// WithEnvName sets the name of the azd environment. Defaults to a unique name derived from the name of the test.
+func WithEnvName(envName string) Option {
+	return func(s *Scenario) {
+		s.envName = envName
+	}
+}
+
+// WithSubscription sets the Azure subscription of the environment
+func WithSubscription(subscriptionId string) Option {
+	return func(s *Scenario) {
+		s.subscription = subscriptionId
+	}
+}
+
+// WithLocation sets the Azure location of the environment
+func WithLocation(location string) Option {
+	return func(s *Scenario) {
+		s.location = location
+	}
+}
+
+// WithEnv sets additional environment variables of the azd commands, ex. KEY=VALUE
+func WithEnv(env ...string) Option {
+	return func(s *Scenario) {
+		s.cli.Env = append(s.cli.Env, env...)
+	}
+}
+
+// New creates the azd environment of the scenario. The environment is torn down when the test completes.
+func New(t *testing.T, options ...Option) *Scenario {
+	s := &Scenario{
+		t: t,
+		cli: &azdcli.CLI{
+			T:       t,
+			AzdPath: os.Getenv(AzdPathEnvVarName),
+			Env:     os.Environ(),
+		},
+		envName:      uniqueEnvName(t.Name()),
+		subscription: os.Getenv("AZURE_SUBSCRIPTION_ID"),
+		location:     os.Getenv("AZURE_LOCATION"),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.cli.AzdPath == "" {
+		path, err := exec.LookPath("azd")
+		if err != nil {
+			t.Fatalf("azd was not found on the PATH, install azd or set %s: %v", AzdPathEnvVarName, err)
+		}
+
+		s.cli.AzdPath = path
+	}
+
+	// azd commands use the environment set by AZURE_ENV_NAME when --environment isn't set
+	s.cli.Env = append(s.cli.Env, "AZURE_ENV_NAME="+s.envName)
+
+	// Commands are cancelled at the deadline of the test, if any
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, has := t.Deadline(); has {
+		ctx, cancel = context.WithDeadline(context.Background(), deadline)
+	}
+	s.ctx = ctx
+
+	// Cleanups run in reverse order, the environment is torn down before the context is cancelled
+	t.Cleanup(cancel)
+	t.Cleanup(s.teardown)
+
+	args := []string{"env", "new", s.envName, "--no-prompt"}
+	if s.subscription != "" {
+		args = append(args, "--subscription", s.subscription)
+	}
+	if s.location != "" {
+		args = append(args, "--location", s.location)
+	}
+	s.Run(args...)
+
+	return s
+}
+
+// EnvName returns the name of the azd environment of the scenario
+func (s *Scenario) EnvName() string {
+	return s.envName
+}
+
+// Result is the outcome of an azd command
+type Result struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+// Run runs the azd command in the environment of the scenario, failing the test when the command fails
+func (s *Scenario) Run(args ...string) *Result {
+	s.t.Helper()
+
+	result, err := s.run(s.ctx, args...)
+	if err != nil {
+		s.t.Fatalf("azd %s: %v", strings.Join(args, " "), err)
+	}
+
+	return result
+}
+
+// RunExpectingFailure runs the azd command in the environment of the scenario, failing the test when the command
+// succeeds
+func (s *Scenario) RunExpectingFailure(args ...string) *Result {
+	s.t.Helper()
+
+	result, err := s.run(s.ctx, args...)
+	if err == nil {
+		s.t.Fatalf("azd %s: expected the command to fail", strings.Join(args, " "))
+	}
+
+	return result
+}
+
+// Up provisions the Azure resources of the template and deploys its services with `azd up`
+func (s *Scenario) Up(args ...string) *Result {
+	s.t.Helper()
+
+	// Resources may be created by a failed deployment, the environment is torn down from now on
+	s.provisioned = true
+	return s.Run(append([]string{"up", "--no-prompt"}, args...)...)
+}
+
+// Provision provisions the Azure resources of the template with `azd provision`
+func (s *Scenario) Provision(args ...string) *Result {
+	s.t.Helper()
+
+	s.provisioned = true
+	return s.Run(append([]string{"provision", "--no-prompt"}, args...)...)
+}
+
+// Deploy deploys the services of the template with `azd deploy`
+func (s *Scenario) Deploy(args ...string) *Result {
+	s.t.Helper()
+
+	return s.Run(append([]string{"deploy", "--all"}, args...)...)
+}
+
+// Down deletes the Azure resources of the environment with `azd down`
+func (s *Scenario) Down() *Result {
+	s.t.Helper()
+
+	result := s.Run("down", "--force", "--purge", "--no-prompt")
+	s.provisioned = false
+	return result
+}
+
+// EnvValues returns the values of the azd environment of the scenario
+func (s *Scenario) EnvValues() map[string]string {
+	s.t.Helper()
+
+	result := s.Run("env", "get-values", "--output", "json")
+
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(result.Stdout), &values); err != nil {
+		s.t.Fatalf("parsing environment values: %v", err)
+	}
+
+	return values
+}
+
+// EnvValue returns the value of the azd environment of the scenario, failing the test when the value is not set
+func (s *Scenario) EnvValue(key string) string {
+	s.t.Helper()
+
+	value, has := s.EnvValues()[key]
+	if !has {
+		s.t.Fatalf("environment value '%s' is not set", key)
+	}
+
+	return value
+}
+
+// RequireEnvValue fails the test when the value of the azd environment of the scenario is not the expected value
+func (s *Scenario) RequireEnvValue(key string, expected string) {
+	s.t.Helper()
+
+	if value := s.EnvValue(key); value != expected {
+		s.t.Fatalf("environment value '%s' is '%s', expected '%s'", key, value, expected)
+	}
+}
+
+type pollOptions struct {
+	timeout        time.Duration
+	interval       time.Duration
+	expectedStatus int
+	contains       string
+}
+
+// PollOption configures PollEndpoint
+type PollOption func(o *pollOptions)
+
+// WithPollTimeout sets the time the endpoint is polled for before the test fails. Defaults to 5 minutes.
+func WithPollTimeout(timeout time.Duration) PollOption {
+	return func(o *pollOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithPollInterval sets the delay between requests. Defaults to 10 seconds.
+func WithPollInterval(interval time.Duration) PollOption {
+	return func(o *pollOptions) {
+		o.interval = interval
+	}
+}
+
+// WithExpectedStatus sets the status code of the response. Defaults to any 2xx status code.
+func WithExpectedStatus(status int) PollOption {
+	return func(o *pollOptions) {
+		o.expectedStatus = status
+	}
+}
+
+// WithBodyContains sets text the body of the response must contain
+func WithBodyContains(text string) PollOption {
+	return func(o *pollOptions) {
+		o.contains = text
+	}
+}
+
+// PollEndpoint sends GET requests to the endpoint until it responds as expected, returning the body of the response.
+// The test fails when the endpoint doesn't respond as expected before the timeout.
+func (s *Scenario) PollEndpoint(endpoint string, options ...PollOption) string {
+	s.t.Helper()
+
+	o := pollOptions{
+		timeout:  5 * time.Minute,
+		interval: 10 * time.Second,
+	}
+	for _, option := range options {
+		option(&o)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, o.timeout)
+	defer cancel()
+
+	for {
+		body, err := poll(ctx, endpoint, o)
+		if err == nil {
+			return body
+		}
+
+		s.t.Logf("polling %s: %v", endpoint, err)
+
+		select {
+		case <-ctx.Done():
+			s.t.Fatalf("endpoint %s did not respond as expected within %s: %v", endpoint, o.timeout, err)
+		case <-time.After(o.interval):
+		}
+	}
+}
+
+func poll(ctx context.Context, endpoint string, o pollOptions) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	passed := res.StatusCode >= 200 && res.StatusCode < 300
+	if o.expectedStatus != 0 {
+		passed = res.StatusCode == o.expectedStatus
+	}
+
+	if !passed {
+		return "", fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	if !strings.Contains(string(body), o.contains) {
+		return "", fmt.Errorf("response does not contain '%s'", o.contains)
+	}
+
+	return string(body), nil
+}
+
+func (s *Scenario) run(ctx context.Context, args ...string) (*Result, error) {
+	result, err := s.cli.RunCommand(ctx, args...)
+	if result == nil {
+		return nil, err
+	}
+
+	return &Result{ExitCode: result.ExitCode, Stdout: result.Stdout, Stderr: result.Stderr}, err
+}
+
+// teardown deletes the Azure resources & the azd environment of the scenario, unless the resources are kept
+func (s *Scenario) teardown() {
+	if keep, _ := strconv.ParseBool(os.Getenv(KeepResourcesEnvVarName)); keep {
+		s.t.Logf("keeping environment %s, %s is set", s.envName, KeepResourcesEnvVarName)
+		return
+	}
+
+	// The test deadline may have passed, teardown has its own timeout
+	ctx, cancel := context.WithTimeout(context.Background(), teardownTimeout)
+	defer cancel()
+
+	if s.provisioned {
+		if _, err := s.run(ctx, "down", "--force", "--purge", "--no-prompt"); err != nil {
+			s.t.Errorf("tearing down environment %s: %v", s.envName, err)
+			return
+		}
+	}
+
+	envDir := filepath.Join(s.cli.WorkingDirectory, ".azure", s.envName)
+	if err := os.RemoveAll(envDir); err != nil {
+		s.t.Errorf("removing environment %s: %v", s.envName, err)
+	}
+}
+
+// uniqueEnvName returns a unique environment name starting with a prefix of the name of the test,
+// ex. azdtest-todo-1a2b3c
+func uniqueEnvName(testName string) string {
+	prefix := strings.Builder{}
+	for _, r := range strings.ToLower(testName) {
+		if prefix.Len() == 8 {
+			break
+		}
+
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			prefix.WriteRune(r)
+		}
+	}
+
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("azdtest-%s-%s", prefix.String(), hex.EncodeToString(suffix))
+}
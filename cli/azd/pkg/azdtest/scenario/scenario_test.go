@@ -0,0 +1,98 @@
+package scenario
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAzd writes a script standing in for azd, recording the commands it runs and the environment they run in
+func fakeAzd(t *testing.T, endpoint string) (string, string) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "azd.log")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$AZURE_ENV_NAME $@" >> %s
+if [ "$1 $2" = "env get-values" ]; then
+	echo '{"WEB_URI": "%s", "AZURE_LOCATION": "eastus2"}'
+fi
+`, logPath, endpoint)
+
+	azdPath := filepath.Join(dir, "azd")
+	require.NoError(t, os.WriteFile(azdPath, []byte(script), osutil.PermissionExecutableFile))
+
+	return azdPath, logPath
+}
+
+func TestScenario(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake azd is a shell script")
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte("<title>Todo</title>"))
+	}))
+	defer server.Close()
+
+	azdPath, logPath := fakeAzd(t, server.URL)
+	templateDir := t.TempDir()
+
+	t.Run("Up", func(t *testing.T) {
+		s := New(t,
+			WithAzdPath(azdPath),
+			WithDir(templateDir),
+			WithEnvName("azdtest-todo"),
+			WithLocation("eastus2"),
+			WithSubscription("SUBSCRIPTION_ID"),
+		)
+		s.Up()
+
+		s.RequireEnvValue("AZURE_LOCATION", "eastus2")
+		body := s.PollEndpoint(s.EnvValue("WEB_URI"), WithPollInterval(time.Millisecond), WithBodyContains("Todo"))
+		require.Equal(t, "<title>Todo</title>", body)
+	})
+
+	contents, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	commands := strings.Split(strings.TrimSpace(string(contents)), "\n")
+
+	require.Equal(t, "azdtest-todo env new azdtest-todo --no-prompt --subscription SUBSCRIPTION_ID --location eastus2",
+		commands[0])
+	require.Equal(t, "azdtest-todo up --no-prompt", commands[1])
+	// The environment is torn down when the test completes
+	require.Equal(t, "azdtest-todo down --force --purge --no-prompt", commands[len(commands)-1])
+}
+
+func TestScenarioNotProvisioned(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake azd is a shell script")
+	}
+
+	azdPath, logPath := fakeAzd(t, "")
+
+	t.Run("EnvOnly", func(t *testing.T) {
+		s := New(t, WithAzdPath(azdPath), WithDir(t.TempDir()))
+		require.True(t, strings.HasPrefix(s.EnvName(), "azdtest-testscen-"))
+	})
+
+	contents, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	// Nothing was provisioned, azd down isn't run
+	require.NotContains(t, string(contents), "down")
+}
@@ -0,0 +1,127 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	annotationsEndpoint   = "https://management.azure.com"
+	annotationsApiVersion = "2015-05-01"
+)
+
+// The category of release annotations displayed as deployment markers by Application Insights
+const AnnotationCategoryDeployment = "Deployment"
+
+// AnnotationsClient manages the annotations of Application Insights components, like release annotations
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/azure/azure-monitor/app/annotations
+type AnnotationsClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// Annotation is an event displayed on the charts of an Application Insights component
+type Annotation struct {
+	Id             string    `json:"Id,omitempty"`
+	AnnotationName string    `json:"AnnotationName"`
+	Category       string    `json:"Category"`
+	EventTime      time.Time `json:"EventTime"`
+	// The properties of the annotation, serialized as a JSON object
+	Properties string `json:"Properties,omitempty"`
+}
+
+type annotationsListResult struct {
+	Value []Annotation `json:"value"`
+}
+
+// Creates a new AnnotationsClient instance
+func NewAnnotationsClient(credential azcore.TokenCredential, options *arm.ClientOptions) (*AnnotationsClient, error) {
+	pipeline, err := armruntime.NewPipeline("annotations", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating annotations pipeline: %w", err)
+	}
+
+	return &AnnotationsClient{
+		pipeline: pipeline,
+		endpoint: annotationsEndpoint,
+	}, nil
+}
+
+// Create adds the annotation to the Application Insights component with the specified resource id
+func (c *AnnotationsClient) Create(ctx context.Context, componentId string, annotation Annotation) error {
+	req, err := c.newRequest(ctx, http.MethodPut, componentId)
+	if err != nil {
+		return err
+	}
+
+	if err := runtime.MarshalAsJSON(req, annotation); err != nil {
+		return fmt.Errorf("setting annotation request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// List returns the annotations of the Application Insights component between start & end
+func (c *AnnotationsClient) List(
+	ctx context.Context,
+	componentId string,
+	start time.Time,
+	end time.Time,
+) ([]Annotation, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, componentId)
+	if err != nil {
+		return nil, err
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("start", start.UTC().Format(time.RFC3339))
+	reqQP.Set("end", end.UTC().Format(time.RFC3339))
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var result annotationsListResult
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, fmt.Errorf("reading annotations response: %w", err)
+	}
+
+	return result.Value, nil
+}
+
+func (c *AnnotationsClient) newRequest(ctx context.Context, method string, componentId string) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, fmt.Sprintf("%s%s/Annotations", c.endpoint, componentId))
+	if err != nil {
+		return nil, fmt.Errorf("creating annotations request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", annotationsApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	return req, nil
+}
@@ -0,0 +1,218 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	containerAppJobsEndpoint   = "https://management.azure.com"
+	containerAppJobsApiVersion = "2023-05-01"
+)
+
+// ContainerAppJobsClient manages Azure Container Apps jobs, which the ARM SDK version used by azd doesn't support
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/containerapps/jobs
+type ContainerAppJobsClient struct {
+	pipeline       runtime.Pipeline
+	endpoint       string
+	subscriptionId string
+}
+
+// ContainerAppJob is a Container Apps job
+type ContainerAppJob struct {
+	Id         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	Location   string                    `json:"location"`
+	Properties ContainerAppJobProperties `json:"properties"`
+}
+
+type ContainerAppJobProperties struct {
+	ProvisioningState string                       `json:"provisioningState"`
+	EnvironmentId     string                       `json:"environmentId"`
+	Configuration     ContainerAppJobConfiguration `json:"configuration"`
+	// The template of the job, kept as is so updates don't drop the properties azd doesn't know
+	Template map[string]any `json:"template"`
+}
+
+type ContainerAppJobConfiguration struct {
+	// Manual, Schedule or Event
+	TriggerType           string                         `json:"triggerType"`
+	ReplicaTimeout        int                            `json:"replicaTimeout"`
+	ScheduleTriggerConfig *ContainerAppJobScheduleConfig `json:"scheduleTriggerConfig,omitempty"`
+	EventTriggerConfig    *ContainerAppJobEventConfig    `json:"eventTriggerConfig,omitempty"`
+}
+
+type ContainerAppJobScheduleConfig struct {
+	CronExpression string `json:"cronExpression"`
+}
+
+type ContainerAppJobEventConfig struct {
+	Scale struct {
+		Rules []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"rules"`
+	} `json:"scale"`
+}
+
+// ContainerAppJobExecution is an execution of a Container Apps job
+type ContainerAppJobExecution struct {
+	Name       string `json:"name"`
+	Properties struct {
+		// Running, Processing, Stopped, Degraded, Failed, Unknown or Succeeded
+		Status    string     `json:"status"`
+		StartTime *time.Time `json:"startTime,omitempty"`
+		EndTime   *time.Time `json:"endTime,omitempty"`
+	} `json:"properties"`
+}
+
+type containerAppJobExecutionList struct {
+	Value []ContainerAppJobExecution `json:"value"`
+}
+
+type containerAppJobPatch struct {
+	Properties struct {
+		Template map[string]any `json:"template"`
+	} `json:"properties"`
+}
+
+// Creates a new ContainerAppJobsClient instance
+func NewContainerAppJobsClient(
+	subscriptionId string,
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*ContainerAppJobsClient, error) {
+	pipeline, err := armruntime.NewPipeline("container-app-jobs", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating container app jobs pipeline: %w", err)
+	}
+
+	return &ContainerAppJobsClient{
+		pipeline:       pipeline,
+		endpoint:       containerAppJobsEndpoint,
+		subscriptionId: subscriptionId,
+	}, nil
+}
+
+// Get returns the job with the specified name
+func (c *ContainerAppJobsClient) Get(ctx context.Context, resourceGroupName, jobName string) (*ContainerAppJob, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.jobPath(resourceGroupName, jobName))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var job ContainerAppJob
+	if err := runtime.UnmarshalAsJSON(response, &job); err != nil {
+		return nil, fmt.Errorf("reading container app job response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// UpdateTemplate replaces the template of the job and waits for the update to complete
+func (c *ContainerAppJobsClient) UpdateTemplate(
+	ctx context.Context,
+	resourceGroupName string,
+	jobName string,
+	template map[string]any,
+) error {
+	req, err := c.newRequest(ctx, http.MethodPatch, c.jobPath(resourceGroupName, jobName))
+	if err != nil {
+		return err
+	}
+
+	patch := containerAppJobPatch{}
+	patch.Properties.Template = template
+	if err := runtime.MarshalAsJSON(req, patch); err != nil {
+		return fmt.Errorf("setting container app job request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[ContainerAppJob](response, c.pipeline, nil)
+	if err != nil {
+		return fmt.Errorf("polling container app job update: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("polling for container app job update completion: %w", err)
+	}
+
+	return nil
+}
+
+// ListExecutions returns the executions of the job, the most recent first
+func (c *ContainerAppJobsClient) ListExecutions(
+	ctx context.Context,
+	resourceGroupName string,
+	jobName string,
+) ([]ContainerAppJobExecution, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.jobPath(resourceGroupName, jobName)+"/executions")
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var list containerAppJobExecutionList
+	if err := runtime.UnmarshalAsJSON(response, &list); err != nil {
+		return nil, fmt.Errorf("reading container app job executions response: %w", err)
+	}
+
+	return list.Value, nil
+}
+
+func (c *ContainerAppJobsClient) jobPath(resourceGroupName string, jobName string) string {
+	return fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s",
+		url.PathEscape(c.subscriptionId),
+		url.PathEscape(resourceGroupName),
+		url.PathEscape(jobName),
+	)
+}
+
+func (c *ContainerAppJobsClient) newRequest(ctx context.Context, method string, path string) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, c.endpoint+path)
+	if err != nil {
+		return nil, fmt.Errorf("creating container app job request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", containerAppJobsApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	return req, nil
+}
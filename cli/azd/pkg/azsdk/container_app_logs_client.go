@@ -0,0 +1,86 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// ContainerAppLogsClient gets the tokens authorizing the log streams of Azure Container Apps, which the ARM SDK
+// version used by azd doesn't support
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/containerapps/container-apps/get-auth-token
+type ContainerAppLogsClient struct {
+	pipeline       runtime.Pipeline
+	endpoint       string
+	subscriptionId string
+}
+
+type containerAppAuthToken struct {
+	Properties struct {
+		Token string `json:"token"`
+	} `json:"properties"`
+}
+
+// Creates a new ContainerAppLogsClient instance
+func NewContainerAppLogsClient(
+	subscriptionId string,
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*ContainerAppLogsClient, error) {
+	pipeline, err := armruntime.NewPipeline("container-app-logs", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating container app logs pipeline: %w", err)
+	}
+
+	return &ContainerAppLogsClient{
+		pipeline:       pipeline,
+		endpoint:       containerAppJobsEndpoint,
+		subscriptionId: subscriptionId,
+	}, nil
+}
+
+// GetAuthToken returns the token authorizing the log streams of the replicas of the container app
+func (c *ContainerAppLogsClient) GetAuthToken(
+	ctx context.Context,
+	resourceGroupName string,
+	appName string,
+) (string, error) {
+	path := fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/containerApps/%s/getAuthToken",
+		url.PathEscape(c.subscriptionId),
+		url.PathEscape(resourceGroupName),
+		url.PathEscape(appName),
+	)
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, c.endpoint+path)
+	if err != nil {
+		return "", fmt.Errorf("creating container app auth token request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", containerAppJobsApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return "", runtime.NewResponseError(response)
+	}
+
+	var token containerAppAuthToken
+	if err := runtime.UnmarshalAsJSON(response, &token); err != nil {
+		return "", fmt.Errorf("reading container app auth token response: %w", err)
+	}
+
+	return token.Properties.Token, nil
+}
@@ -0,0 +1,298 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	costManagementEndpoint   = "https://management.azure.com"
+	costManagementApiVersion = "2023-03-01"
+	// Budgets are managed by the Consumption resource provider
+	budgetsApiVersion = "2021-10-01"
+)
+
+// CostManagementClient queries the cost of Azure resources
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/cost-management/query/usage
+type CostManagementClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// CostQuery is the definition of a Cost Management usage query
+type CostQuery struct {
+	Type       string          `json:"type"`
+	Timeframe  string          `json:"timeframe"`
+	TimePeriod *CostTimePeriod `json:"timePeriod,omitempty"`
+	Dataset    CostDataset     `json:"dataset"`
+	// Forecast queries only, whether the result includes the actual cost of past days
+	IncludeActualCost *bool `json:"includeActualCost,omitempty"`
+	// Forecast queries only, whether the result includes the partial cost of the current day
+	IncludeFreshPartialCost *bool `json:"includeFreshPartialCost,omitempty"`
+}
+
+// CostTimePeriod is the time period of a query with a `Custom` timeframe
+type CostTimePeriod struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// CostDataset describes the data returned by a Cost Management query
+type CostDataset struct {
+	Granularity string                     `json:"granularity"`
+	Aggregation map[string]CostAggregation `json:"aggregation"`
+	Grouping    []CostGrouping             `json:"grouping,omitempty"`
+	Filter      *CostFilter                `json:"filter,omitempty"`
+}
+
+type CostAggregation struct {
+	Name     string `json:"name"`
+	Function string `json:"function"`
+}
+
+// CostGrouping groups the cost by a dimension (ex. `ResourceId`) or tag (type `TagKey`)
+type CostGrouping struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type CostFilter struct {
+	Tags       *CostComparison `json:"tags,omitempty"`
+	Dimensions *CostComparison `json:"dimensions,omitempty"`
+}
+
+type CostComparison struct {
+	Name     string   `json:"name"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+// CostQueryResult is the result of a Cost Management query. The values of each row match the order of the columns.
+type CostQueryResult struct {
+	Columns []CostColumn `json:"columns"`
+	Rows    [][]any      `json:"rows"`
+}
+
+type CostColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type costQueryResponse struct {
+	Properties CostQueryResult `json:"properties"`
+}
+
+// Budget is a monthly cost budget notifying contacts when the cost reaches thresholds of its amount
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/consumption/budgets
+type Budget struct {
+	Id         string           `json:"id,omitempty"`
+	Name       string           `json:"name,omitempty"`
+	ETag       string           `json:"eTag,omitempty"`
+	Properties BudgetProperties `json:"properties"`
+}
+
+type BudgetProperties struct {
+	// Cost
+	Category string  `json:"category"`
+	Amount   float64 `json:"amount"`
+	// Monthly, Quarterly or Annually
+	TimeGrain  string           `json:"timeGrain"`
+	TimePeriod BudgetTimePeriod `json:"timePeriod"`
+	Filter     *CostFilter      `json:"filter,omitempty"`
+	// The notifications of the budget by name, ex. actual_GreaterThanOrEqualTo_80_Percent
+	Notifications map[string]BudgetNotification `json:"notifications,omitempty"`
+	CurrentSpend  *BudgetSpend                  `json:"currentSpend,omitempty"`
+}
+
+// BudgetTimePeriod is the period of a budget, the start date must be the first day of a month
+type BudgetTimePeriod struct {
+	StartDate time.Time  `json:"startDate"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+}
+
+type BudgetNotification struct {
+	Enabled bool `json:"enabled"`
+	// ex. GreaterThanOrEqualTo
+	Operator string `json:"operator"`
+	// The percentage of the amount of the budget
+	Threshold float64 `json:"threshold"`
+	// Actual or Forecasted
+	ThresholdType string   `json:"thresholdType,omitempty"`
+	ContactEmails []string `json:"contactEmails,omitempty"`
+	ContactRoles  []string `json:"contactRoles,omitempty"`
+	// The ids of the action groups notified
+	ContactGroups []string `json:"contactGroups,omitempty"`
+}
+
+type BudgetSpend struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+}
+
+// Creates a new CostManagementClient instance
+func NewCostManagementClient(credential azcore.TokenCredential, options *arm.ClientOptions) (*CostManagementClient, error) {
+	pipeline, err := armruntime.NewPipeline("cost-management", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating cost management pipeline: %w", err)
+	}
+
+	return &CostManagementClient{
+		pipeline: pipeline,
+		endpoint: costManagementEndpoint,
+	}, nil
+}
+
+// Query returns the usage cost of the resources within the scope (ex. a resource group id) matching the query
+func (c *CostManagementClient) Query(ctx context.Context, scope string, query CostQuery) (*CostQueryResult, error) {
+	return c.send(ctx, scope, "query", query)
+}
+
+// Forecast returns the forecasted cost of the resources within the scope
+func (c *CostManagementClient) Forecast(ctx context.Context, scope string, query CostQuery) (*CostQueryResult, error) {
+	return c.send(ctx, scope, "forecast", query)
+}
+
+// GetBudget returns the budget of the scope (ex. a subscription id) with the specified name, or nil when the budget
+// doesn't exist
+func (c *CostManagementClient) GetBudget(ctx context.Context, scope string, name string) (*Budget, error) {
+	req, err := c.newBudgetRequest(ctx, http.MethodGet, scope, name)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return nil, nil
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var budget Budget
+	if err := runtime.UnmarshalAsJSON(response, &budget); err != nil {
+		return nil, fmt.Errorf("reading budget response: %w", err)
+	}
+
+	return &budget, nil
+}
+
+// CreateOrUpdateBudget creates the budget of the scope or replaces the existing budget with the same name
+func (c *CostManagementClient) CreateOrUpdateBudget(
+	ctx context.Context,
+	scope string,
+	name string,
+	budget Budget,
+) (*Budget, error) {
+	req, err := c.newBudgetRequest(ctx, http.MethodPut, scope, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runtime.MarshalAsJSON(req, budget); err != nil {
+		return nil, fmt.Errorf("setting budget request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var result Budget
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, fmt.Errorf("reading budget response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *CostManagementClient) newBudgetRequest(
+	ctx context.Context,
+	method string,
+	scope string,
+	name string,
+) (*policy.Request, error) {
+	req, err := runtime.NewRequest(
+		ctx,
+		method,
+		fmt.Sprintf("%s%s/providers/Microsoft.Consumption/budgets/%s", c.endpoint, scope, name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating budget request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", budgetsApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	return req, nil
+}
+
+func (c *CostManagementClient) send(
+	ctx context.Context,
+	scope string,
+	operation string,
+	query CostQuery,
+) (*CostQueryResult, error) {
+	req, err := runtime.NewRequest(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("%s%s/providers/Microsoft.CostManagement/%s", c.endpoint, scope, operation),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating cost %s request: %w", operation, err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", costManagementApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	if err := runtime.MarshalAsJSON(req, query); err != nil {
+		return nil, fmt.Errorf("setting cost %s request body: %w", operation, err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var result costQueryResponse
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, fmt.Errorf("reading cost %s response: %w", operation, err)
+	}
+
+	return &result.Properties, nil
+}
+
+// Column returns the index of the column with the specified name, or -1 when the result doesn't have the column
+func (r *CostQueryResult) Column(name string) int {
+	for i, column := range r.Columns {
+		if column.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
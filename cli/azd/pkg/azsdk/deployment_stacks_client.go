@@ -0,0 +1,252 @@
+package azsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	deploymentStacksEndpoint   = "https://management.azure.com"
+	deploymentStacksApiVersion = "2024-03-01"
+	// Stacks are deployed for several minutes, like deployments
+	deploymentStacksPollFrequency = 10 * time.Second
+)
+
+// The actions on the resources & resource groups no longer managed by the stack, when the stack is updated or deleted
+const (
+	DeploymentStackActionDelete = "delete"
+	DeploymentStackActionDetach = "detach"
+)
+
+// The modes of the deny settings of stacks
+const (
+	// The managed resources can be modified and deleted
+	DenySettingsModeNone = "none"
+	// The managed resources can't be deleted
+	DenySettingsModeDenyDelete = "denyDelete"
+	// The managed resources can't be modified or deleted
+	DenySettingsModeDenyWriteAndDelete = "denyWriteAndDelete"
+)
+
+// ErrDeploymentStackNotFound is returned when the deployment stack doesn't exist
+var ErrDeploymentStackNotFound = errors.New("deployment stack not found")
+
+// DeploymentStacksClient deploys templates as deployment stacks, managing the deployed resources as a set: resources
+// removed from the template are deleted and deny settings protect the resources from changes outside the stack
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/azure/azure-resource-manager/bicep/deployment-stacks
+type DeploymentStacksClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// DeploymentStack is a stack deployed to a subscription or resource group
+type DeploymentStack struct {
+	Id         string                    `json:"id,omitempty"`
+	Name       string                    `json:"name,omitempty"`
+	Location   string                    `json:"location,omitempty"`
+	Tags       map[string]*string        `json:"tags,omitempty"`
+	Properties DeploymentStackProperties `json:"properties"`
+}
+
+type DeploymentStackProperties struct {
+	Template         json.RawMessage                 `json:"template,omitempty"`
+	Parameters       any                             `json:"parameters,omitempty"`
+	ActionOnUnmanage DeploymentStackActionOnUnmanage `json:"actionOnUnmanage"`
+	DenySettings     DeploymentStackDenySettings     `json:"denySettings"`
+	// The resource group the resources are deployed to, for stacks deployed to subscriptions
+	DeploymentScope   string `json:"deploymentScope,omitempty"`
+	ProvisioningState string `json:"provisioningState,omitempty"`
+	// The id of the deployment of the stack, ex. /subscriptions/{id}/providers/Microsoft.Resources/deployments/{name}
+	DeploymentId string                      `json:"deploymentId,omitempty"`
+	Outputs      any                         `json:"outputs,omitempty"`
+	Resources    []DeploymentStackResource   `json:"resources,omitempty"`
+	Error        *DeploymentStackErrorDetail `json:"error,omitempty"`
+}
+
+type DeploymentStackActionOnUnmanage struct {
+	Resources        string `json:"resources"`
+	ResourceGroups   string `json:"resourceGroups,omitempty"`
+	ManagementGroups string `json:"managementGroups,omitempty"`
+}
+
+type DeploymentStackDenySettings struct {
+	Mode               string   `json:"mode"`
+	ExcludedPrincipals []string `json:"excludedPrincipals,omitempty"`
+	ExcludedActions    []string `json:"excludedActions,omitempty"`
+	ApplyToChildScopes bool     `json:"applyToChildScopes"`
+}
+
+// DeploymentStackResource is a resource managed by the stack
+type DeploymentStackResource struct {
+	Id string `json:"id"`
+	// managed, deleteFailed or detached
+	Status string `json:"status,omitempty"`
+	// denyDelete, denyWriteAndDelete, none, inapplicable, notSupported or removedBySystem
+	DenyStatus string `json:"denyStatus,omitempty"`
+}
+
+type DeploymentStackErrorDetail struct {
+	Code    string                       `json:"code"`
+	Message string                       `json:"message"`
+	Details []DeploymentStackErrorDetail `json:"details,omitempty"`
+}
+
+// Creates a new DeploymentStacksClient instance
+func NewDeploymentStacksClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*DeploymentStacksClient, error) {
+	pipeline, err := armruntime.NewPipeline("deploymentstacks", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating deployment stacks pipeline: %w", err)
+	}
+
+	return &DeploymentStacksClient{
+		pipeline: pipeline,
+		endpoint: deploymentStacksEndpoint,
+	}, nil
+}
+
+// DeploymentStackId returns the id of the stack deployed to the subscription, or to the resource group when the resource
+// group name isn't empty
+func DeploymentStackId(subscriptionId string, resourceGroupName string, stackName string) string {
+	scope := fmt.Sprintf("/subscriptions/%s", url.PathEscape(subscriptionId))
+	if resourceGroupName != "" {
+		scope = fmt.Sprintf("%s/resourceGroups/%s", scope, url.PathEscape(resourceGroupName))
+	}
+
+	return fmt.Sprintf("%s/providers/Microsoft.Resources/deploymentStacks/%s", scope, url.PathEscape(stackName))
+}
+
+// Get returns the stack with the specified id
+func (c *DeploymentStacksClient) Get(ctx context.Context, stackId string) (*DeploymentStack, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, stackId)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return nil, ErrDeploymentStackNotFound
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var stack DeploymentStack
+	if err := runtime.UnmarshalAsJSON(response, &stack); err != nil {
+		return nil, fmt.Errorf("reading deployment stack response: %w", err)
+	}
+
+	return &stack, nil
+}
+
+// CreateOrUpdate deploys the stack with the specified id and waits for the deployment to complete
+func (c *DeploymentStacksClient) CreateOrUpdate(
+	ctx context.Context,
+	stackId string,
+	stack DeploymentStack,
+) (*DeploymentStack, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, stackId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runtime.MarshalAsJSON(req, stack); err != nil {
+		return nil, fmt.Errorf("setting deployment stack request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[DeploymentStack](response, c.pipeline, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: deploymentStacksPollFrequency})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Delete deletes the stack with the specified id along with the resources and resource groups it manages, and waits
+// for the deletion to complete
+func (c *DeploymentStacksClient) Delete(ctx context.Context, stackId string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, stackId)
+	if err != nil {
+		return err
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("unmanageAction.Resources", DeploymentStackActionDelete)
+	reqQP.Set("unmanageAction.ResourceGroups", DeploymentStackActionDelete)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return ErrDeploymentStackNotFound
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNoContent) {
+		return nil
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[struct{}](response, c.pipeline, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: deploymentStacksPollFrequency})
+	return err
+}
+
+func (c *DeploymentStacksClient) newRequest(
+	ctx context.Context,
+	method string,
+	stackId string,
+) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, fmt.Sprintf("%s%s", c.endpoint, stackId))
+	if err != nil {
+		return nil, fmt.Errorf("creating deployment stacks request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", deploymentStacksApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	return req, nil
+}
@@ -0,0 +1,461 @@
+package azsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	devCenterScope      = "https://devcenter.azure.com/.default"
+	devCenterApiVersion = "2023-04-01"
+	// Dev Box customizations are only available in preview
+	devCenterCustomizationApiVersion = "2023-07-01-preview"
+)
+
+// How often long running operations of the dev center are polled
+const devCenterPollFrequency = 5 * time.Second
+
+// ErrDevCenterEnvironmentNotFound is returned when the environment does not exist in the dev center project
+var ErrDevCenterEnvironmentNotFound = errors.New("environment not found")
+
+// ErrDevBoxNotFound is returned when the dev box does not exist in the dev center project
+var ErrDevBoxNotFound = errors.New("dev box not found")
+
+// The power state of a running dev box
+const DevBoxPowerStateRunning = "Running"
+
+// DevCenterClient manages Azure Deployment Environments through the data plane API of a dev center
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/devcenter/developer/environments
+type DevCenterClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// DevCenterEnvironment is an environment deployed from an environment definition of a dev center catalog
+type DevCenterEnvironment struct {
+	Name                      string         `json:"name,omitempty"`
+	EnvironmentType           string         `json:"environmentType"`
+	User                      string         `json:"user,omitempty"`
+	CatalogName               string         `json:"catalogName"`
+	EnvironmentDefinitionName string         `json:"environmentDefinitionName"`
+	Parameters                map[string]any `json:"parameters,omitempty"`
+	ProvisioningState         string         `json:"provisioningState,omitempty"`
+	// The id of the resource group hosting the resources of the environment
+	ResourceGroupId string `json:"resourceGroupId,omitempty"`
+}
+
+// DevCenterEnvironmentDefinition is an infrastructure template of a dev center catalog
+type DevCenterEnvironmentDefinition struct {
+	Id          string               `json:"id"`
+	Name        string               `json:"name"`
+	CatalogName string               `json:"catalogName"`
+	Description string               `json:"description,omitempty"`
+	Parameters  []DevCenterParameter `json:"parameters,omitempty"`
+}
+
+// DevCenterParameter is an input parameter of an environment definition
+type DevCenterParameter struct {
+	Id          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Default     any    `json:"default,omitempty"`
+	ReadOnly    bool   `json:"readOnly,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Allowed     []any  `json:"allowed,omitempty"`
+}
+
+// Creates a new DevCenterClient instance for the dev center with the specified endpoint,
+// ex. https://{tenantId}-{devCenterName}.{region}.devcenter.azure.com
+func NewDevCenterClient(
+	endpoint string,
+	credential azcore.TokenCredential,
+	options *azcore.ClientOptions,
+) (*DevCenterClient, error) {
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid dev center endpoint '%s': %w", endpoint, err)
+	}
+
+	if options == nil {
+		options = &azcore.ClientOptions{}
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{devCenterScope}, nil)
+	pipeline := runtime.NewPipeline("devcenter", "1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{authPolicy},
+	}, options)
+
+	return &DevCenterClient{
+		pipeline: pipeline,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+	}, nil
+}
+
+// GetEnvironment returns the environment of the current user with the specified name.
+// Returns ErrDevCenterEnvironmentNotFound when the environment does not exist.
+func (c *DevCenterClient) GetEnvironment(
+	ctx context.Context,
+	projectName string,
+	environmentName string,
+) (*DevCenterEnvironment, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, environmentPath(projectName, environmentName))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return nil, ErrDevCenterEnvironmentNotFound
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var environment DevCenterEnvironment
+	if err := runtime.UnmarshalAsJSON(response, &environment); err != nil {
+		return nil, fmt.Errorf("reading environment response: %w", err)
+	}
+
+	return &environment, nil
+}
+
+// CreateOrUpdateEnvironment deploys the environment of the current user with the specified name and waits for the
+// deployment to complete
+func (c *DevCenterClient) CreateOrUpdateEnvironment(
+	ctx context.Context,
+	projectName string,
+	environmentName string,
+	environment DevCenterEnvironment,
+) (*DevCenterEnvironment, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, environmentPath(projectName, environmentName))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runtime.MarshalAsJSON(req, environment); err != nil {
+		return nil, fmt.Errorf("setting environment request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[DevCenterEnvironment](response, c.pipeline, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: devCenterPollFrequency})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteEnvironment deletes the environment of the current user with the specified name, along with its resources,
+// and waits for the deletion to complete
+func (c *DevCenterClient) DeleteEnvironment(ctx context.Context, projectName string, environmentName string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, environmentPath(projectName, environmentName))
+	if err != nil {
+		return err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return ErrDevCenterEnvironmentNotFound
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNoContent) {
+		return nil
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[struct{}](response, c.pipeline, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: devCenterPollFrequency})
+	return err
+}
+
+// GetEnvironmentDefinition returns the environment definition with the specified name in the catalog of the project
+func (c *DevCenterClient) GetEnvironmentDefinition(
+	ctx context.Context,
+	projectName string,
+	catalogName string,
+	definitionName string,
+) (*DevCenterEnvironmentDefinition, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf(
+		"/projects/%s/catalogs/%s/environmentDefinitions/%s",
+		url.PathEscape(projectName),
+		url.PathEscape(catalogName),
+		url.PathEscape(definitionName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var definition DevCenterEnvironmentDefinition
+	if err := runtime.UnmarshalAsJSON(response, &definition); err != nil {
+		return nil, fmt.Errorf("reading environment definition response: %w", err)
+	}
+
+	return &definition, nil
+}
+
+// DevBox is a cloud workstation created from a pool of a dev center project
+type DevBox struct {
+	Name              string `json:"name,omitempty"`
+	ProjectName       string `json:"projectName,omitempty"`
+	PoolName          string `json:"poolName"`
+	ProvisioningState string `json:"provisioningState,omitempty"`
+	PowerState        string `json:"powerState,omitempty"`
+	Location          string `json:"location,omitempty"`
+}
+
+// DevBoxRemoteConnection describes how to connect to a dev box
+type DevBoxRemoteConnection struct {
+	// The URL of the web client of the dev box
+	WebUrl string `json:"webUrl"`
+	// The URL launching the Remote Desktop client of the dev box
+	RdpConnectionUrl string `json:"rdpConnectionUrl,omitempty"`
+}
+
+// DevBoxCustomizationGroup is a group of tasks run on a dev box to configure it
+type DevBoxCustomizationGroup struct {
+	Name   string                    `json:"name,omitempty"`
+	Status string                    `json:"status,omitempty"`
+	Tasks  []DevBoxCustomizationTask `json:"tasks"`
+}
+
+// DevBoxCustomizationTask is a task of the catalogs of the dev center, ex. winget or git-clone
+type DevBoxCustomizationTask struct {
+	Name        string            `json:"name"`
+	DisplayName string            `json:"displayName,omitempty"`
+	Parameters  map[string]string `json:"parameters,omitempty"`
+	// The user the task runs as, "User" or "System"
+	RunAs string `json:"runAs,omitempty"`
+}
+
+// GetDevBox returns the dev box of the current user with the specified name.
+// Returns ErrDevBoxNotFound when the dev box does not exist.
+func (c *DevCenterClient) GetDevBox(ctx context.Context, projectName string, devBoxName string) (*DevBox, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, devBoxPath(projectName, devBoxName))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return nil, ErrDevBoxNotFound
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var devBox DevBox
+	if err := runtime.UnmarshalAsJSON(response, &devBox); err != nil {
+		return nil, fmt.Errorf("reading dev box response: %w", err)
+	}
+
+	return &devBox, nil
+}
+
+// CreateDevBox creates a dev box for the current user from the pool of the project and waits for it to be provisioned
+func (c *DevCenterClient) CreateDevBox(
+	ctx context.Context,
+	projectName string,
+	devBoxName string,
+	poolName string,
+) (*DevBox, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, devBoxPath(projectName, devBoxName))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runtime.MarshalAsJSON(req, DevBox{PoolName: poolName}); err != nil {
+		return nil, fmt.Errorf("setting dev box request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[DevBox](response, c.pipeline, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	devBox, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: devCenterPollFrequency})
+	if err != nil {
+		return nil, err
+	}
+
+	return &devBox, nil
+}
+
+// StartDevBox starts the dev box of the current user and waits for it to be running
+func (c *DevCenterClient) StartDevBox(ctx context.Context, projectName string, devBoxName string) error {
+	req, err := c.newRequest(ctx, http.MethodPost, devBoxPath(projectName, devBoxName)+":start")
+	if err != nil {
+		return err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusOK) {
+		return nil
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[struct{}](response, c.pipeline, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: devCenterPollFrequency})
+	return err
+}
+
+// GetDevBoxRemoteConnection returns the URLs used to connect to the dev box
+func (c *DevCenterClient) GetDevBoxRemoteConnection(
+	ctx context.Context,
+	projectName string,
+	devBoxName string,
+) (*DevBoxRemoteConnection, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, devBoxPath(projectName, devBoxName)+"/remoteConnection")
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var connection DevBoxRemoteConnection
+	if err := runtime.UnmarshalAsJSON(response, &connection); err != nil {
+		return nil, fmt.Errorf("reading remote connection response: %w", err)
+	}
+
+	return &connection, nil
+}
+
+// CreateDevBoxCustomizationGroup runs the tasks of the customization group on the dev box. The tasks run in the
+// background, the customization group is returned once the tasks are queued.
+func (c *DevCenterClient) CreateDevBoxCustomizationGroup(
+	ctx context.Context,
+	projectName string,
+	devBoxName string,
+	group DevBoxCustomizationGroup,
+) (*DevBoxCustomizationGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf(
+		"%s/customizationGroups/%s", devBoxPath(projectName, devBoxName), url.PathEscape(group.Name)))
+	if err != nil {
+		return nil, err
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", devCenterCustomizationApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	if err := runtime.MarshalAsJSON(req, DevBoxCustomizationGroup{Tasks: group.Tasks}); err != nil {
+		return nil, fmt.Errorf("setting customization group request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var result DevBoxCustomizationGroup
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, fmt.Errorf("reading customization group response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *DevCenterClient) newRequest(ctx context.Context, method string, path string) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, c.endpoint+path)
+	if err != nil {
+		return nil, fmt.Errorf("creating dev center request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", devCenterApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	return req, nil
+}
+
+func environmentPath(projectName string, environmentName string) string {
+	return fmt.Sprintf(
+		"/projects/%s/users/me/environments/%s", url.PathEscape(projectName), url.PathEscape(environmentName))
+}
+
+func devBoxPath(projectName string, devBoxName string) string {
+	return fmt.Sprintf("/projects/%s/users/me/devboxes/%s", url.PathEscape(projectName), url.PathEscape(devBoxName))
+}
@@ -0,0 +1,139 @@
+package azsdk
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// The query parameters logged with their values, the values of the other parameters may be secrets, ex. SAS tokens
+var httpTraceAllowedQueryParameters = map[string]bool{
+	"api-version": true,
+	"$filter":     true,
+	"$top":        true,
+}
+
+// The response headers identifying the request for the Azure services
+var httpTraceCorrelationHeaders = []string{
+	cMsCorrelationIdHeader,
+	"x-ms-request-id",
+	"x-ms-client-request-id",
+	"apim-request-id",
+}
+
+type httpTracerContextKey struct{}
+
+// HttpTracer writes the traces of the HTTP requests sent by the command to its logger, and counts them by host
+type HttpTracer struct {
+	logger *log.Logger
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// Creates a new HttpTracer instance writing the traces to the logger
+func NewHttpTracer(logger *log.Logger) *HttpTracer {
+	return &HttpTracer{
+		logger: logger,
+		counts: map[string]int{},
+	}
+}
+
+// RequestCounts returns the number of HTTP requests sent to each host
+func (t *HttpTracer) RequestCounts() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[string]int, len(t.counts))
+	for host, count := range t.counts {
+		counts[host] = count
+	}
+
+	return counts
+}
+
+func (t *HttpTracer) add(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counts[host]++
+}
+
+// WithHttpTracer returns a context tracing the HTTP requests of the clients created with it
+func WithHttpTracer(ctx context.Context, tracer *HttpTracer) context.Context {
+	return context.WithValue(ctx, httpTracerContextKey{}, tracer)
+}
+
+// httpTracePolicy logs the method, URL, status, duration and correlation IDs of the HTTP requests
+type httpTracePolicy struct {
+	tracer *HttpTracer
+}
+
+// NewHttpTracePolicy creates a policy that writes the metadata of the HTTP requests to the logger of the tracer, without
+// their headers or bodies, and counts them.
+//
+// The HTTP requests are only traced with the tracer of the context. If no tracer exists, then this policy is a no-op.
+func NewHttpTracePolicy(ctx context.Context) policy.Policy {
+	tracer, ok := ctx.Value(httpTracerContextKey{}).(*HttpTracer)
+	if !ok {
+		return &noOptPolicy{}
+	}
+
+	return &httpTracePolicy{
+		tracer: tracer,
+	}
+}
+
+func (p *httpTracePolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	p.tracer.add(rawRequest.URL.Hostname())
+
+	start := time.Now()
+	response, err := req.Next()
+	duration := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		p.tracer.logger.Printf("http: %s %s failed after %s: %v", rawRequest.Method, sanitizeUrl(rawRequest.URL), duration, err)
+		return response, err
+	}
+
+	ids := []string{}
+	for _, header := range httpTraceCorrelationHeaders {
+		if value := response.Header.Get(header); value != "" {
+			ids = append(ids, header+"="+value)
+		}
+	}
+
+	p.tracer.logger.Printf(
+		"http: %s %s %d in %s %s",
+		rawRequest.Method,
+		sanitizeUrl(rawRequest.URL),
+		response.StatusCode,
+		duration,
+		strings.Join(ids, " "),
+	)
+
+	return response, nil
+}
+
+// sanitizeUrl returns the URL without credentials or the values of the query parameters which may be secrets
+func sanitizeUrl(requestUrl *url.URL) string {
+	sanitized := *requestUrl
+	sanitized.User = nil
+	sanitized.Fragment = ""
+
+	query := sanitized.Query()
+	for key := range query {
+		if !httpTraceAllowedQueryParameters[key] {
+			query.Set(key, "REDACTED")
+		}
+	}
+
+	sanitized.RawQuery = query.Encode()
+	return sanitized.String()
+}
@@ -0,0 +1,130 @@
+package azsdk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// KuduLogsClient reads the runtime logs of app services and function apps from the Kudu service of the app
+// More info can be found at the following:
+// https://github.com/projectkudu/kudu/wiki/Diagnostic-Log-Stream
+type KuduLogsClient struct {
+	pipeline runtime.Pipeline
+}
+
+// DockerLog is a container log file of a Linux app, one for each instance and container of the app
+type DockerLog struct {
+	MachineName string    `json:"machineName"`
+	LastUpdated time.Time `json:"lastUpdated"`
+	Size        int64     `json:"size"`
+	Href        string    `json:"href"`
+}
+
+// Creates a new KuduLogsClient instance
+func NewKuduLogsClient(credential azcore.TokenCredential, options *arm.ClientOptions) (*KuduLogsClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	// We do not have a Resource provider to register
+	options.DisableRPRegistration = true
+
+	pipeline, err := armruntime.NewPipeline("kudu-logs", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	return &KuduLogsClient{
+		pipeline: pipeline,
+	}, nil
+}
+
+// ListDockerLogs lists the container log files of the app
+func (c *KuduLogsClient) ListDockerLogs(ctx context.Context, appName string) ([]DockerLog, error) {
+	endpoint := fmt.Sprintf("https://%s.scm.azurewebsites.net/api/logs/docker", appName)
+	req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating docker logs request: %w", err)
+	}
+
+	res, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, runtime.NewResponseError(res)
+	}
+
+	logs, err := httputil.ReadRawResponse[[]DockerLog](res)
+	if err != nil {
+		return nil, err
+	}
+
+	return *logs, nil
+}
+
+// ReadLog writes the lines of the log file of the app at the href, ex. the href of a DockerLog
+func (c *KuduLogsClient) ReadLog(ctx context.Context, href string, write func(line string) error) error {
+	return c.readLines(ctx, href, write)
+}
+
+// StreamLogs writes the lines of the log stream of the app until the context is canceled. The path selects the logs
+// streamed, ex. application/functions/function for the invocation logs of functions, all the logs when empty.
+func (c *KuduLogsClient) StreamLogs(
+	ctx context.Context,
+	appName string,
+	path string,
+	write func(line string) error,
+) error {
+	endpoint := fmt.Sprintf("https://%s.scm.azurewebsites.net/api/logstream", appName)
+	if path != "" {
+		endpoint += "/" + strings.TrimPrefix(path, "/")
+	}
+
+	return c.readLines(ctx, endpoint, write)
+}
+
+func (c *KuduLogsClient) readLines(ctx context.Context, endpoint string, write func(line string) error) error {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return fmt.Errorf("creating logs request: %w", err)
+	}
+
+	// The logs are streamed, the body is read as the lines are written
+	runtime.SkipBodyDownload(req)
+
+	res, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return runtime.NewResponseError(res)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		if err := write(scanner.Text()); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("reading logs: %w", err)
+	}
+
+	return nil
+}
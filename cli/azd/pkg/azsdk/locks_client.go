@@ -0,0 +1,142 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	locksEndpoint   = "https://management.azure.com"
+	locksApiVersion = "2016-09-01"
+)
+
+// The levels of management locks
+const (
+	// Authorized users can read and modify the resource but can't delete it
+	LockLevelCanNotDelete = "CanNotDelete"
+	// Authorized users can only read the resource
+	LockLevelReadOnly = "ReadOnly"
+)
+
+// LocksClient reads and removes the management locks preventing resources from being deleted or modified
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/lock-resources
+type LocksClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// ManagementLock is a lock applied to a resource group or resource
+type ManagementLock struct {
+	Id         string                   `json:"id"`
+	Name       string                   `json:"name"`
+	Properties ManagementLockProperties `json:"properties"`
+}
+
+type ManagementLockProperties struct {
+	// CanNotDelete or ReadOnly
+	Level string `json:"level"`
+	Notes string `json:"notes,omitempty"`
+}
+
+// Scope returns the id of the resource group or resource the lock is applied to
+func (l *ManagementLock) Scope() string {
+	scope, _, _ := strings.Cut(l.Id, "/providers/Microsoft.Authorization/locks/")
+	return scope
+}
+
+type locksListResult struct {
+	Value    []ManagementLock `json:"value"`
+	NextLink string           `json:"nextLink"`
+}
+
+// Creates a new LocksClient instance
+func NewLocksClient(credential azcore.TokenCredential, options *arm.ClientOptions) (*LocksClient, error) {
+	pipeline, err := armruntime.NewPipeline("locks", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating locks pipeline: %w", err)
+	}
+
+	return &LocksClient{
+		pipeline: pipeline,
+		endpoint: locksEndpoint,
+	}, nil
+}
+
+// ListAtResourceGroup returns the locks of the resource group and of the resources within the resource group
+func (c *LocksClient) ListAtResourceGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) ([]ManagementLock, error) {
+	url := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Authorization/locks?api-version=%s",
+		c.endpoint, subscriptionId, resourceGroupName, locksApiVersion)
+
+	locks := []ManagementLock{}
+	for url != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, url)
+		if err != nil {
+			return nil, fmt.Errorf("creating locks request: %w", err)
+		}
+
+		response, err := c.pipeline.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		var result locksListResult
+		if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+			return nil, fmt.Errorf("reading locks response: %w", err)
+		}
+
+		locks = append(locks, result.Value...)
+		url = result.NextLink
+	}
+
+	return locks, nil
+}
+
+// Delete removes the lock with the specified id
+func (c *LocksClient) Delete(ctx context.Context, lockId string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, lockId)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusNoContent) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (c *LocksClient) newRequest(ctx context.Context, method string, lockId string) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, fmt.Sprintf("%s%s", c.endpoint, lockId))
+	if err != nil {
+		return nil, fmt.Errorf("creating locks request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", locksApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	return req, nil
+}
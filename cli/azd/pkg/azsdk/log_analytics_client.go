@@ -0,0 +1,97 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// The Log Analytics query API endpoint
+const logAnalyticsEndpoint = "https://api.loganalytics.io"
+
+// LogAnalyticsClient runs KQL queries against Log Analytics workspaces
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/loganalytics/dataaccess/query/resource-execute
+type LogAnalyticsClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// LogAnalyticsQueryResult is the result of a Log Analytics query
+type LogAnalyticsQueryResult struct {
+	Tables []LogAnalyticsTable `json:"tables"`
+}
+
+// LogAnalyticsTable is a table of results returned by a Log Analytics query
+type LogAnalyticsTable struct {
+	Name    string               `json:"name"`
+	Columns []LogAnalyticsColumn `json:"columns"`
+	Rows    [][]any              `json:"rows"`
+}
+
+// LogAnalyticsColumn describes a column of a Log Analytics table
+type LogAnalyticsColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type logAnalyticsQueryRequest struct {
+	Query    string `json:"query"`
+	Timespan string `json:"timespan,omitempty"`
+}
+
+// Creates a new LogAnalyticsClient instance
+func NewLogAnalyticsClient(credential azcore.TokenCredential, options *azcore.ClientOptions) *LogAnalyticsClient {
+	if options == nil {
+		options = &azcore.ClientOptions{}
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{logAnalyticsEndpoint + "/.default"}, nil)
+	pipeline := runtime.NewPipeline("log-analytics", "1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{authPolicy},
+	}, options)
+
+	return &LogAnalyticsClient{
+		pipeline: pipeline,
+		endpoint: logAnalyticsEndpoint,
+	}
+}
+
+// Query runs the KQL query against the Log Analytics workspace with the specified resource id.
+// The timespan is an ISO 8601 duration (ex. PT1H) limiting the query to recent data, an empty timespan
+// queries all the data retained by the workspace.
+func (c *LogAnalyticsClient) Query(
+	ctx context.Context,
+	workspaceId string,
+	query string,
+	timespan string,
+) (*LogAnalyticsQueryResult, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodPost, fmt.Sprintf("%s/v1%s/query", c.endpoint, workspaceId))
+	if err != nil {
+		return nil, fmt.Errorf("creating query request: %w", err)
+	}
+
+	if err := runtime.MarshalAsJSON(req, logAnalyticsQueryRequest{Query: query, Timespan: timespan}); err != nil {
+		return nil, fmt.Errorf("setting query request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var result LogAnalyticsQueryResult
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, fmt.Errorf("reading query response: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,79 @@
+package azsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogAnalyticsQuery(t *testing.T) {
+	workspaceId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.OperationalInsights/workspaces/WORKSPACE"
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		var body logAnalyticsQueryRequest
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost &&
+				request.URL.Host == "api.loganalytics.io" &&
+				request.URL.Path == "/v1"+workspaceId+"/query"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			contents, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(contents, &body))
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, LogAnalyticsQueryResult{
+				Tables: []LogAnalyticsTable{
+					{
+						Name: "PrimaryResult",
+						Columns: []LogAnalyticsColumn{
+							{Name: "name", Type: "string"},
+							{Name: "count_", Type: "long"},
+						},
+						Rows: [][]any{{"GET /", 10}},
+					},
+				},
+			})
+		})
+
+		options := NewClientOptionsBuilder().
+			WithTransport(mockContext.HttpClient).
+			BuildCoreClientOptions()
+
+		client := NewLogAnalyticsClient(&mocks.MockCredentials{}, options)
+		result, err := client.Query(*mockContext.Context, workspaceId, "requests | summarize count() by name", "PT1H")
+		require.NoError(t, err)
+
+		require.Equal(t, logAnalyticsQueryRequest{Query: "requests | summarize count() by name", Timespan: "PT1H"}, body)
+		require.Len(t, result.Tables, 1)
+		require.Equal(t, "count_", result.Tables[0].Columns[1].Name)
+		require.Equal(t, [][]any{{"GET /", float64(10)}}, result.Tables[0].Rows)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && request.URL.Host == "api.loganalytics.io"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusBadRequest, map[string]any{
+				"error": map[string]any{"code": "BadArgumentError", "message": "The request had some invalid properties"},
+			})
+		})
+
+		options := NewClientOptionsBuilder().
+			WithTransport(mockContext.HttpClient).
+			BuildCoreClientOptions()
+
+		client := NewLogAnalyticsClient(&mocks.MockCredentials{}, options)
+		result, err := client.Query(*mockContext.Context, workspaceId, "requests |", "")
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "BadArgumentError")
+	})
+}
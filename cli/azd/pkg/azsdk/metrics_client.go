@@ -0,0 +1,126 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	metricsEndpoint   = "https://management.azure.com"
+	metricsApiVersion = "2018-01-01"
+)
+
+// MetricsClient reads the platform metrics of Azure resources from Azure Monitor
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/monitor/metrics/list
+type MetricsClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// MetricsQuery describes the metrics returned by Azure Monitor
+type MetricsQuery struct {
+	// The names of the metrics (ex. `Requests`)
+	Names []string
+	// The ISO 8601 time interval of the metrics (ex. `2023-10-01T00:00:00Z/2023-10-01T01:00:00Z`)
+	Timespan string
+	// The ISO 8601 duration of each data point (ex. `PT5M`)
+	Interval string
+	// The aggregations returned for each data point (ex. `Average`, `Total`)
+	Aggregations []string
+}
+
+// MetricsResult is the result of a metrics query
+type MetricsResult struct {
+	Timespan string   `json:"timespan"`
+	Interval string   `json:"interval"`
+	Value    []Metric `json:"value"`
+}
+
+type Metric struct {
+	Name       MetricName         `json:"name"`
+	Unit       string             `json:"unit"`
+	Timeseries []MetricTimeseries `json:"timeseries"`
+}
+
+type MetricName struct {
+	Value          string `json:"value"`
+	LocalizedValue string `json:"localizedValue"`
+}
+
+type MetricTimeseries struct {
+	Data []MetricValue `json:"data"`
+}
+
+// MetricValue is a single data point of a metric, only the requested aggregations are set
+type MetricValue struct {
+	TimeStamp time.Time `json:"timeStamp"`
+	Average   *float64  `json:"average,omitempty"`
+	Total     *float64  `json:"total,omitempty"`
+	Minimum   *float64  `json:"minimum,omitempty"`
+	Maximum   *float64  `json:"maximum,omitempty"`
+	Count     *float64  `json:"count,omitempty"`
+}
+
+// Creates a new MetricsClient instance
+func NewMetricsClient(credential azcore.TokenCredential, options *arm.ClientOptions) (*MetricsClient, error) {
+	pipeline, err := armruntime.NewPipeline("metrics", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating metrics pipeline: %w", err)
+	}
+
+	return &MetricsClient{
+		pipeline: pipeline,
+		endpoint: metricsEndpoint,
+	}, nil
+}
+
+// List returns the metrics of the resource with the specified id
+func (c *MetricsClient) List(ctx context.Context, resourceId string, query MetricsQuery) (*MetricsResult, error) {
+	req, err := runtime.NewRequest(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s%s/providers/Microsoft.Insights/metrics", c.endpoint, resourceId),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating metrics request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", metricsApiVersion)
+	reqQP.Set("metricnames", strings.Join(query.Names, ","))
+	if query.Timespan != "" {
+		reqQP.Set("timespan", query.Timespan)
+	}
+	if query.Interval != "" {
+		reqQP.Set("interval", query.Interval)
+	}
+	if len(query.Aggregations) > 0 {
+		reqQP.Set("aggregation", strings.Join(query.Aggregations, ","))
+	}
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var result MetricsResult
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, fmt.Errorf("reading metrics response: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,239 @@
+package azsdk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The default duration ARM read responses are cached
+const DefaultReadCacheTTL = 30 * time.Second
+
+// ReadCacheOptions configures the ARM read cache
+type ReadCacheOptions struct {
+	// How long responses are cached. Defaults to DefaultReadCacheTTL.
+	TTL time.Duration
+	// When set, responses are also persisted within the directory so they are reused by later invocations of azd
+	Dir string
+}
+
+// ReadCache is a HttpClient that caches the responses of idempotent ARM read calls for a short time.
+// Subscriptions, locations & resource lookups are often requested several times during a single command.
+// Any other ARM call (PUT, PATCH, POST or DELETE) clears the cache since it may change the results of read calls.
+type ReadCache struct {
+	inner   httputil.HttpClient
+	options ReadCacheOptions
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*readCacheEntry
+}
+
+type readCacheEntry struct {
+	ExpiresOn  time.Time   `json:"expiresOn"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// Creates a new ARM read cache that sends requests with the inner HttpClient
+func NewReadCache(inner httputil.HttpClient, options ReadCacheOptions) *ReadCache {
+	if options.TTL <= 0 {
+		options.TTL = DefaultReadCacheTTL
+	}
+
+	return &ReadCache{
+		inner:   inner,
+		options: options,
+		now:     time.Now,
+		entries: map[string]*readCacheEntry{},
+	}
+}
+
+// Do sends the request, returning a cached response for ARM read calls when available
+func (c *ReadCache) Do(req *http.Request) (*http.Response, error) {
+	if !isArmRequest(req) {
+		return c.inner.Do(req)
+	}
+
+	if !isCacheableRead(req) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			c.Clear()
+		}
+
+		return c.inner.Do(req)
+	}
+
+	key := readCacheKey(req)
+	if entry, has := c.get(key); has {
+		return entry.response(req), nil
+	}
+
+	res, err := c.inner.Do(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &readCacheEntry{
+		ExpiresOn:  c.now().Add(c.options.TTL),
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+		Body:       body,
+	}
+	c.set(key, entry)
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return res, nil
+}
+
+// Removes all cached responses, including the responses persisted to disk
+func (c *ReadCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]*readCacheEntry{}
+
+	if c.options.Dir != "" {
+		if err := os.RemoveAll(c.options.Dir); err != nil {
+			log.Printf("failed clearing ARM read cache: %v\n", err)
+		}
+	}
+}
+
+func (c *ReadCache) get(key string) (*readCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, has := c.entries[key]
+	if !has && c.options.Dir != "" {
+		entry, has = c.load(key)
+	}
+
+	if !has {
+		return nil, false
+	}
+
+	if !c.now().Before(entry.ExpiresOn) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.entries[key] = entry
+	return entry, true
+}
+
+func (c *ReadCache) set(key string, entry *readCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+
+	if c.options.Dir != "" {
+		if err := c.persist(key, entry); err != nil {
+			log.Printf("failed persisting ARM read cache entry: %v\n", err)
+		}
+	}
+}
+
+func (c *ReadCache) load(key string) (*readCacheEntry, bool) {
+	contents, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed reading ARM read cache entry: %v\n", err)
+		}
+
+		return nil, false
+	}
+
+	entry := &readCacheEntry{}
+	if err := json.Unmarshal(contents, entry); err != nil {
+		log.Printf("failed parsing ARM read cache entry: %v\n", err)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func (c *ReadCache) persist(key string, entry *readCacheEntry) error {
+	if err := os.MkdirAll(c.options.Dir, osutil.PermissionDirectoryOwnerOnly); err != nil {
+		return err
+	}
+
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Responses can contain details of the user's resources and are only readable by the user
+	return os.WriteFile(c.entryPath(key), contents, osutil.PermissionFileOwnerOnly)
+}
+
+func (c *ReadCache) entryPath(key string) string {
+	return filepath.Join(c.options.Dir, key+".json")
+}
+
+func (e *readCacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// ARM requests are sent to the management endpoint of the cloud. (ex. `management.azure.com`)
+func isArmRequest(req *http.Request) bool {
+	return req.URL != nil && strings.HasPrefix(strings.ToLower(req.URL.Hostname()), "management.")
+}
+
+// Deployments & long running operations are polled for their latest status and are never cached
+func isCacheableRead(req *http.Request) bool {
+	if req.Method != http.MethodGet || req.Header.Get("Cache-Control") == "no-cache" {
+		return false
+	}
+
+	for _, segment := range strings.Split(strings.ToLower(req.URL.Path), "/") {
+		if segment == "deployments" || segment == "deploymentstacks" || strings.Contains(segment, "operation") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Responses are cached per identity since the results of read calls depend on the caller's access
+func readCacheKey(req *http.Request) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.URL.String()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.Header.Get("Authorization")))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,115 @@
+package azsdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCache(t *testing.T) {
+	newCache := func(t *testing.T, options ReadCacheOptions) (*ReadCache, *int) {
+		calls := 0
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return true
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			calls++
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"calls": calls})
+		})
+
+		return NewReadCache(mockContext.HttpClient, options), &calls
+	}
+
+	send := func(t *testing.T, cache *ReadCache, method string, url string) string {
+		req, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+
+		res, err := cache.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		return strings.TrimSpace(string(body))
+	}
+
+	locationsUrl := "https://management.azure.com/subscriptions/SUBSCRIPTION_ID/locations?api-version=2021-01-01"
+
+	t.Run("CachesReads", func(t *testing.T) {
+		cache, calls := newCache(t, ReadCacheOptions{})
+
+		first := send(t, cache, http.MethodGet, locationsUrl)
+		second := send(t, cache, http.MethodGet, locationsUrl)
+
+		require.Equal(t, first, second)
+		require.Equal(t, 1, *calls)
+	})
+
+	t.Run("Expires", func(t *testing.T) {
+		cache, calls := newCache(t, ReadCacheOptions{TTL: time.Minute})
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		send(t, cache, http.MethodGet, locationsUrl)
+		now = now.Add(2 * time.Minute)
+		send(t, cache, http.MethodGet, locationsUrl)
+
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("WritesClearCache", func(t *testing.T) {
+		cache, calls := newCache(t, ReadCacheOptions{})
+
+		send(t, cache, http.MethodGet, locationsUrl)
+		send(t, cache, http.MethodPut, "https://management.azure.com/subscriptions/SUBSCRIPTION_ID/resourcegroups/rg")
+		send(t, cache, http.MethodGet, locationsUrl)
+
+		require.Equal(t, 3, *calls)
+	})
+
+	t.Run("SkipsDeploymentsAndOperations", func(t *testing.T) {
+		cache, calls := newCache(t, ReadCacheOptions{})
+		urls := []string{
+			"https://management.azure.com/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deployments/dev",
+			"https://management.azure.com/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Web/operationStatuses/123",
+		}
+
+		for _, url := range urls {
+			send(t, cache, http.MethodGet, url)
+			send(t, cache, http.MethodGet, url)
+		}
+
+		require.Equal(t, 4, *calls)
+	})
+
+	t.Run("SkipsNonArmRequests", func(t *testing.T) {
+		cache, calls := newCache(t, ReadCacheOptions{})
+
+		send(t, cache, http.MethodGet, "https://graph.microsoft.com/v1.0/me")
+		send(t, cache, http.MethodGet, "https://graph.microsoft.com/v1.0/me")
+
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("Persisted", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, calls := newCache(t, ReadCacheOptions{Dir: dir})
+		first := send(t, cache, http.MethodGet, locationsUrl)
+
+		// A new cache, like a later invocation of azd, reuses the persisted response
+		nextCache, nextCalls := newCache(t, ReadCacheOptions{Dir: dir})
+		second := send(t, nextCache, http.MethodGet, locationsUrl)
+
+		require.Equal(t, first, second)
+		require.Equal(t, 1, *calls)
+		require.Equal(t, 0, *nextCalls)
+	})
+}
@@ -0,0 +1,174 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	storageAccountsEndpoint      = "https://management.azure.com"
+	storageAccountsApiVersion    = "2023-01-01"
+	storageAccountsPollFrequency = 5 * time.Second
+)
+
+// StorageAccountsClient creates storage accounts and their blob containers
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/storagerp/storage-accounts
+type StorageAccountsClient struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// StorageAccount is a general purpose v2 storage account
+type StorageAccount struct {
+	Location   string                   `json:"location"`
+	Kind       string                   `json:"kind"`
+	Sku        StorageAccountSku        `json:"sku"`
+	Tags       map[string]*string       `json:"tags,omitempty"`
+	Properties StorageAccountProperties `json:"properties"`
+}
+
+type StorageAccountSku struct {
+	Name string `json:"name"`
+}
+
+type StorageAccountProperties struct {
+	MinimumTlsVersion        string `json:"minimumTlsVersion,omitempty"`
+	AllowBlobPublicAccess    bool   `json:"allowBlobPublicAccess"`
+	SupportsHttpsTrafficOnly bool   `json:"supportsHttpsTrafficOnly"`
+}
+
+// Creates a new StorageAccountsClient instance
+func NewStorageAccountsClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*StorageAccountsClient, error) {
+	pipeline, err := armruntime.NewPipeline("storageaccounts", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating storage accounts pipeline: %w", err)
+	}
+
+	return &StorageAccountsClient{
+		pipeline: pipeline,
+		endpoint: storageAccountsEndpoint,
+	}, nil
+}
+
+// CreateOrUpdate creates the storage account, or updates the existing account, and waits for its creation to complete.
+// Blob public access is disabled and TLS 1.2 is required.
+func (c *StorageAccountsClient) CreateOrUpdate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	location string,
+	tags map[string]*string,
+) error {
+	req, err := c.newRequest(ctx, c.accountUrl(subscriptionId, resourceGroupName, accountName))
+	if err != nil {
+		return err
+	}
+
+	account := StorageAccount{
+		Location: location,
+		Kind:     "StorageV2",
+		Sku:      StorageAccountSku{Name: "Standard_LRS"},
+		Tags:     tags,
+		Properties: StorageAccountProperties{
+			MinimumTlsVersion:        "TLS1_2",
+			AllowBlobPublicAccess:    false,
+			SupportsHttpsTrafficOnly: true,
+		},
+	}
+	if err := runtime.MarshalAsJSON(req, account); err != nil {
+		return fmt.Errorf("setting storage account request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if runtime.HasStatusCode(response, http.StatusOK) {
+		return nil
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[struct{}](response, c.pipeline, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: storageAccountsPollFrequency})
+	return err
+}
+
+// CreateBlobContainer creates the private blob container of the storage account, existing containers are kept
+func (c *StorageAccountsClient) CreateBlobContainer(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	containerName string,
+) error {
+	containerUrl := fmt.Sprintf(
+		"%s/blobServices/default/containers/%s",
+		c.accountUrl(subscriptionId, resourceGroupName, accountName),
+		url.PathEscape(containerName),
+	)
+
+	req, err := c.newRequest(ctx, containerUrl)
+	if err != nil {
+		return err
+	}
+
+	if err := runtime.MarshalAsJSON(req, map[string]any{"properties": map[string]any{"publicAccess": "None"}}); err != nil {
+		return fmt.Errorf("setting blob container request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (c *StorageAccountsClient) accountUrl(subscriptionId string, resourceGroupName string, accountName string) string {
+	return fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Storage/storageAccounts/%s",
+		c.endpoint,
+		url.PathEscape(subscriptionId),
+		url.PathEscape(resourceGroupName),
+		url.PathEscape(accountName),
+	)
+}
+
+func (c *StorageAccountsClient) newRequest(ctx context.Context, requestUrl string) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodPut, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating storage accounts request: %w", err)
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", storageAccountsApiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+
+	return req, nil
+}
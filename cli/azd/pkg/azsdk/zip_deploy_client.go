@@ -13,6 +13,7 @@ import (
 	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 )
 
@@ -144,10 +145,19 @@ func (c *ZipDeployClient) createDeployRequest(
 	}
 
 	rawRequest := req.Raw()
-	rawRequest.Body = io.NopCloser(zipFile)
+	if seeker, ok := zipFile.(io.ReadSeeker); ok {
+		// Seekable packages (ex. files) are rewound by the retry policy so the upload is retried
+		// on transient network failures instead of failing the deployment
+		if err := req.SetBody(streaming.NopCloser(seeker), "application/octet-stream"); err != nil {
+			return nil, fmt.Errorf("setting deploy request body: %w", err)
+		}
+	} else {
+		rawRequest.Body = io.NopCloser(zipFile)
+		rawRequest.Header.Set("Content-Type", "application/octet-stream")
+	}
+
 	query := rawRequest.URL.Query()
 	query.Set("isAsync", "true")
-	rawRequest.Header.Set("Content-Type", "application/octet-stream")
 	rawRequest.Header.Set("Accept", "application/json")
 	rawRequest.URL.RawQuery = query.Encode()
 
@@ -3,6 +3,7 @@ package azsdk
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -65,6 +66,44 @@ func TestZipDeploy(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("RetriesUploadOnTransientFailure", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		uploads := []string{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "/api/zipdeploy")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			uploads = append(uploads, string(body))
+			if len(uploads) == 1 {
+				return mocks.CreateEmptyHttpResponse(request, http.StatusServiceUnavailable)
+			}
+
+			response, _ := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+			response.Header.Set("Location", "http://myapp.scm.azurewebsites.net/deployments/latest")
+
+			return response, nil
+		})
+
+		options := NewClientOptionsBuilder().
+			WithTransport(mockContext.HttpClient).
+			BuildArmClientOptions()
+
+		client, err := NewZipDeployClient("SUBSCRIPTION_ID", &mocks.MockCredentials{}, options)
+		require.NoError(t, err)
+
+		zipFile := bytes.NewReader([]byte("zip contents"))
+		poller, err := client.BeginDeploy(*mockContext.Context, "APP_NAME", zipFile)
+		require.NotNil(t, poller)
+		require.NoError(t, err)
+
+		// The full package is uploaded again after the transient failure
+		require.Equal(t, []string{"zip contents", "zip contents"}, uploads)
+	})
+
 	t.Run("WithInitialError", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		registerConflictMocks(mockContext)
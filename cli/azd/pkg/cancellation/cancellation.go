@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package cancellation coordinates the cancellation of azd commands by interrupts (Ctrl+C). The first interrupt cancels
+// the context of the command, so operations in flight stop polling, child processes are interrupted and telemetry is
+// flushed. Another interrupt exits azd right away.
+package cancellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// ErrInterrupted is the cause of the contexts canceled by an interrupt
+var ErrInterrupted = errors.New("the command was interrupted")
+
+// GracePeriod is how long interrupted child processes are given to exit, ex. for terraform to release its state lock,
+// before they are killed
+const GracePeriod = 10 * time.Second
+
+// ExitCode is the exit code of interrupted commands, the exit code of shells for SIGINT
+const ExitCode = 130
+
+// NotifyInterrupt returns a context canceled with [ErrInterrupted] on the first interrupt. Another interrupt exits the
+// process with [ExitCode]. stop releases the handling of interrupts and must be called once the command completed.
+func NotifyInterrupt(ctx context.Context, stderr io.Writer) (cancelCtx context.Context, stop func()) {
+	cancelCtx, cancel := context.WithCancelCause(ctx)
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt)
+	stopped := make(chan struct{})
+
+	go func() {
+		select {
+		case <-signals:
+		case <-stopped:
+			return
+		}
+
+		log.Println("interrupt received, canceling the command")
+		fmt.Fprintln(stderr, output.WithWarningFormat("\nCanceling, press Ctrl+C again to exit immediately."))
+		cancel(ErrInterrupted)
+
+		select {
+		case <-signals:
+			log.Println("second interrupt received, exiting")
+			os.Exit(ExitCode)
+		case <-stopped:
+		}
+	}()
+
+	var once sync.Once
+	return cancelCtx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(stopped)
+			cancel(nil)
+		})
+	}
+}
+
+// IsInterrupted returns whether the context was canceled by an interrupt
+func IsInterrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrInterrupted)
+}
+
+// IsCanceled returns whether the error is the error of a canceled operation
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, ErrInterrupted)
+}
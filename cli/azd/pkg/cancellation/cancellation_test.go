@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cancellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NotifyInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts can't be sent to the current process on windows")
+	}
+
+	ctx, stop := NotifyInterrupt(context.Background(), io.Discard)
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(os.Interrupt))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the context wasn't canceled by the interrupt")
+	}
+
+	require.True(t, IsInterrupted(ctx))
+	require.True(t, IsCanceled(ctx.Err()))
+}
+
+func Test_NotifyInterrupt_Stop(t *testing.T) {
+	ctx, stop := NotifyInterrupt(context.Background(), io.Discard)
+	stop()
+	stop()
+
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.False(t, IsInterrupted(ctx))
+}
+
+func Test_IsCanceled(t *testing.T) {
+	require.True(t, IsCanceled(fmt.Errorf("deploying: %w", context.Canceled)))
+	require.True(t, IsCanceled(ErrInterrupted))
+	require.False(t, IsCanceled(errors.New("deployment failed")))
+	require.False(t, IsCanceled(context.DeadlineExceeded))
+}
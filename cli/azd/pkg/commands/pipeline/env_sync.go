@@ -0,0 +1,270 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azdo"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+)
+
+// The CI systems environment values are synced to
+const (
+	EnvSyncTargetGitHub = gitHubLabel
+	EnvSyncTargetAzdo   = azdoLabel
+)
+
+// The keys of environment values synced as secrets when azure.yaml doesn't configure the secrets. `azd env promote`
+// never copies them between environments.
+var DefaultEnvSyncSecrets = []string{"*SECRET*", "*PASSWORD*", "*TOKEN*", "*CONNECTION_STRING*", "*_KEY", "*_PAT"}
+
+// The keys of environment values configuring azd locally, never synced
+var envSyncExcluded = []string{azdo.AzDoPatName, "AZURE_DEVOPS_*", envPersistedKey}
+
+// EnvSyncValue is an environment value synced to a secret or variable of the CI system
+type EnvSyncValue struct {
+	// The key of the environment value
+	Key string `json:"key"`
+	// The name of the secret or variable
+	Name   string `json:"name"`
+	Secret bool   `json:"secret"`
+	value  string
+}
+
+// EnvSyncValues returns the values of the environment synced with the options, sorted by key
+func EnvSyncValues(env *environment.Environment, options *project.EnvSyncOptions) ([]EnvSyncValue, error) {
+	if options == nil {
+		options = &project.EnvSyncOptions{}
+	}
+
+	secrets := options.Secrets
+	if len(secrets) == 0 {
+		secrets = DefaultEnvSyncSecrets
+	}
+
+	excludes := append(append([]string{}, envSyncExcluded...), options.Exclude...)
+
+	values := []EnvSyncValue{}
+	for key, value := range env.Dotenv() {
+		excluded, err := matchesAny(key, excludes)
+		if err != nil {
+			return nil, err
+		}
+
+		included := true
+		if len(options.Include) > 0 {
+			included, err = matchesAny(key, options.Include)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if excluded || !included {
+			continue
+		}
+
+		secret, err := matchesAny(key, secrets)
+		if err != nil {
+			return nil, err
+		}
+
+		name := key
+		if mapped, has := options.Map[key]; has {
+			name = mapped
+		}
+
+		values = append(values, EnvSyncValue{Key: key, Name: name, Secret: secret, value: value})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Key < values[j].Key
+	})
+
+	return values, nil
+}
+
+func matchesAny(key string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// EnvSyncTarget pushes environment values to the secrets & variables of a CI system
+type EnvSyncTarget interface {
+	// A description of the secrets & variables synced, ex. the repository
+	Description() string
+	Sync(ctx context.Context, values []EnvSyncValue) error
+}
+
+type gitHubEnvSyncTarget struct {
+	ghCli       github.GitHubCli
+	repoSlug    string
+	environment string
+}
+
+func (t *gitHubEnvSyncTarget) Description() string {
+	if t.environment != "" {
+		return fmt.Sprintf("GitHub repository %s, environment %s", t.repoSlug, t.environment)
+	}
+
+	return fmt.Sprintf("GitHub repository %s", t.repoSlug)
+}
+
+func (t *gitHubEnvSyncTarget) Sync(ctx context.Context, values []EnvSyncValue) error {
+	for _, value := range values {
+		// GitHub reserves the GITHUB_ prefix
+		if strings.HasPrefix(strings.ToUpper(value.Name), "GITHUB_") {
+			return fmt.Errorf(
+				"'%s' can't be synced to GitHub, names can't start with GITHUB_. Map it to another name in azure.yaml",
+				value.Key)
+		}
+
+		var err error
+		switch {
+		case value.Secret && t.environment != "":
+			err = t.ghCli.SetEnvironmentSecret(ctx, t.repoSlug, t.environment, value.Name, value.value)
+		case value.Secret:
+			err = t.ghCli.SetSecret(ctx, t.repoSlug, value.Name, value.value)
+		case t.environment != "":
+			err = t.ghCli.SetEnvironmentVariable(ctx, t.repoSlug, t.environment, value.Name, value.value)
+		default:
+			err = t.ghCli.SetVariable(ctx, t.repoSlug, value.Name, value.value)
+		}
+
+		if err != nil {
+			return fmt.Errorf("syncing '%s': %w", value.Key, err)
+		}
+	}
+
+	return nil
+}
+
+type azdoEnvSyncTarget struct {
+	env         *environment.Environment
+	console     input.Console
+	projectName string
+	groupName   string
+}
+
+func (t *azdoEnvSyncTarget) Description() string {
+	return fmt.Sprintf("Azure DevOps project %s, variable group %s", t.projectName, t.groupName)
+}
+
+func (t *azdoEnvSyncTarget) Sync(ctx context.Context, values []EnvSyncValue) error {
+	org, _, err := azdo.EnsureOrgNameExists(ctx, t.env, t.console)
+	if err != nil {
+		return err
+	}
+
+	pat, _, err := azdo.EnsurePatExists(ctx, t.env, t.console)
+	if err != nil {
+		return err
+	}
+
+	connection, err := azdo.GetConnection(ctx, org, pat)
+	if err != nil {
+		return err
+	}
+
+	variables := map[string]azdo.VariableGroupValue{}
+	for _, value := range values {
+		variables[value.Name] = azdo.VariableGroupValue{Value: value.value, IsSecret: value.Secret}
+	}
+
+	_, err = azdo.SetVariableGroupVariables(ctx, t.projectName, t.groupName, variables, connection)
+	return err
+}
+
+// EnvSyncTargetOptions identifies where the values of an environment are synced
+type EnvSyncTargetOptions struct {
+	// github or azdo
+	Target string
+	// The git remote of the GitHub repository
+	RemoteName string
+	// The GitHub deployment environment, the secrets & variables of the repository are set when empty
+	GitHubEnvironment string
+	// The Azure DevOps variable group, defaults to azd-<environment name>
+	VariableGroup string
+}
+
+// NewEnvSyncTarget returns the target syncing the values of the environment to GitHub or Azure DevOps. The GitHub
+// repository is the remote of the project, the Azure DevOps project is the project configured by
+// `azd pipeline config`.
+func NewEnvSyncTarget(
+	ctx context.Context,
+	options EnvSyncTargetOptions,
+	env *environment.Environment,
+	projectDir string,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+) (EnvSyncTarget, error) {
+	switch options.Target {
+	case EnvSyncTargetGitHub:
+		gitCli := git.NewGitCli(commandRunner)
+		remoteUrl, err := gitCli.GetRemoteUrl(ctx, projectDir, options.RemoteName)
+		if err != nil {
+			return nil, fmt.Errorf("getting url of remote '%s': %w", options.RemoteName, err)
+		}
+
+		repoDetails, err := (&GitHubScmProvider{}).gitRepoDetails(ctx, remoteUrl)
+		if err != nil {
+			return nil, fmt.Errorf("remote '%s': %w", options.RemoteName, err)
+		}
+
+		ghCli, err := github.NewGitHubCli(ctx, console, commandRunner)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := ensureGitHubLogin(ctx, projectDir, ghCli, gitCli, github.GitHubHostName, console); err != nil {
+			return nil, err
+		}
+
+		return &gitHubEnvSyncTarget{
+			ghCli:       ghCli,
+			repoSlug:    repoDetails.owner + "/" + repoDetails.repoName,
+			environment: options.GitHubEnvironment,
+		}, nil
+	case EnvSyncTargetAzdo:
+		projectName := env.Getenv(azdo.AzDoEnvironmentProjectName)
+		if projectName == "" {
+			return nil, fmt.Errorf(
+				"%s is not set, run `azd pipeline config --provider azdo` first", azdo.AzDoEnvironmentProjectName)
+		}
+
+		groupName := options.VariableGroup
+		if groupName == "" {
+			groupName = fmt.Sprintf("azd-%s", env.GetEnvName())
+		}
+
+		return &azdoEnvSyncTarget{
+			env:         env,
+			console:     console,
+			projectName: projectName,
+			groupName:   groupName,
+		}, nil
+	default:
+		return nil, fmt.Errorf("target '%s' is not supported, supported targets: %s, %s",
+			options.Target, EnvSyncTargetGitHub, EnvSyncTargetAzdo)
+	}
+}
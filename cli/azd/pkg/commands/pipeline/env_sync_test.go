@@ -0,0 +1,124 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvSyncValues(t *testing.T) {
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		"AZURE_LOCATION":         "westus2",
+		"SERVICE_API_URI":        "https://api",
+		"DB_PASSWORD":            "hunter2",
+		"AZURE_DEVOPS_EXT_PAT":   "pat",
+		"AZURE_DEVOPS_ORG_NAME":  "org",
+		"AZD_PIPELINE_PROVIDER":  "github",
+		"SERVICE_WEB_ENDPOINTS":  "[]",
+		"STORAGE_ACCOUNT_KEY":    "key",
+		"GITHUB_REPOSITORY_NAME": "repo",
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		values, err := EnvSyncValues(env, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []EnvSyncValue{
+			{Key: "AZURE_ENV_NAME", Name: "AZURE_ENV_NAME", value: "dev"},
+			{Key: "AZURE_LOCATION", Name: "AZURE_LOCATION", value: "westus2"},
+			{Key: "DB_PASSWORD", Name: "DB_PASSWORD", Secret: true, value: "hunter2"},
+			{Key: "GITHUB_REPOSITORY_NAME", Name: "GITHUB_REPOSITORY_NAME", value: "repo"},
+			{Key: "SERVICE_API_URI", Name: "SERVICE_API_URI", value: "https://api"},
+			{Key: "SERVICE_WEB_ENDPOINTS", Name: "SERVICE_WEB_ENDPOINTS", value: "[]"},
+			{Key: "STORAGE_ACCOUNT_KEY", Name: "STORAGE_ACCOUNT_KEY", Secret: true, value: "key"},
+		}, values)
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		values, err := EnvSyncValues(env, &project.EnvSyncOptions{
+			Include: []string{"SERVICE_*", "DB_*"},
+			Exclude: []string{"*_ENDPOINTS"},
+			Secrets: []string{"SERVICE_API_*"},
+			Map:     map[string]string{"SERVICE_API_URI": "API_URI"},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []EnvSyncValue{
+			{Key: "DB_PASSWORD", Name: "DB_PASSWORD", value: "hunter2"},
+			{Key: "SERVICE_API_URI", Name: "API_URI", Secret: true, value: "https://api"},
+		}, values)
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		_, err := EnvSyncValues(env, &project.EnvSyncOptions{Include: []string{"["}})
+		require.ErrorContains(t, err, "invalid pattern '['")
+	})
+}
+
+type syncGitHubCli struct {
+	github.GitHubCli
+	set []string
+}
+
+func (cli *syncGitHubCli) SetSecret(ctx context.Context, repoSlug string, name string, value string) error {
+	cli.set = append(cli.set, "secret "+repoSlug+" "+name+"="+value)
+	return nil
+}
+
+func (cli *syncGitHubCli) SetVariable(ctx context.Context, repoSlug string, name string, value string) error {
+	cli.set = append(cli.set, "variable "+repoSlug+" "+name+"="+value)
+	return nil
+}
+
+func (cli *syncGitHubCli) SetEnvironmentSecret(
+	ctx context.Context, repoSlug string, environment string, name string, value string) error {
+	cli.set = append(cli.set, "secret "+repoSlug+"@"+environment+" "+name+"="+value)
+	return nil
+}
+
+func (cli *syncGitHubCli) SetEnvironmentVariable(
+	ctx context.Context, repoSlug string, environment string, name string, value string) error {
+	cli.set = append(cli.set, "variable "+repoSlug+"@"+environment+" "+name+"="+value)
+	return nil
+}
+
+func Test_gitHubEnvSyncTarget(t *testing.T) {
+	values := []EnvSyncValue{
+		{Key: "AZURE_LOCATION", Name: "AZURE_LOCATION", value: "westus2"},
+		{Key: "DB_PASSWORD", Name: "DB_PASSWORD", Secret: true, value: "hunter2"},
+	}
+
+	t.Run("Repository", func(t *testing.T) {
+		ghCli := &syncGitHubCli{}
+		target := &gitHubEnvSyncTarget{ghCli: ghCli, repoSlug: "Azure/todo"}
+
+		require.NoError(t, target.Sync(context.Background(), values))
+		require.Equal(t, []string{
+			"variable Azure/todo AZURE_LOCATION=westus2",
+			"secret Azure/todo DB_PASSWORD=hunter2",
+		}, ghCli.set)
+	})
+
+	t.Run("Environment", func(t *testing.T) {
+		ghCli := &syncGitHubCli{}
+		target := &gitHubEnvSyncTarget{ghCli: ghCli, repoSlug: "Azure/todo", environment: "prod"}
+
+		require.NoError(t, target.Sync(context.Background(), values))
+		require.Equal(t, []string{
+			"variable Azure/todo@prod AZURE_LOCATION=westus2",
+			"secret Azure/todo@prod DB_PASSWORD=hunter2",
+		}, ghCli.set)
+		require.Equal(t, "GitHub repository Azure/todo, environment prod", target.Description())
+	})
+
+	t.Run("ReservedName", func(t *testing.T) {
+		target := &gitHubEnvSyncTarget{ghCli: &syncGitHubCli{}, repoSlug: "Azure/todo"}
+
+		err := target.Sync(context.Background(), []EnvSyncValue{{Key: "GITHUB_TOKEN", Name: "GITHUB_TOKEN"}})
+		require.ErrorContains(t, err, "names can't start with GITHUB_")
+	})
+}
@@ -364,27 +364,8 @@ func (p *GitHubCiProvider) preConfigureCheck(
 	}
 
 	authType := PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName)
-
-	// Federated Auth + Terraform is not a supported combination
-	if infraOptions.Provider == provisioning.Terraform {
-		// Throw error if Federated auth is explicitly requested
-		if authType == AuthTypeFederated {
-			return false, fmt.Errorf(
-				//nolint:lll
-				"Terraform does not support federated authentication. To explicitly use client credentials set the %s flag. %w",
-				output.WithBackticks("--auth-type client-credentials"),
-				ErrAuthNotSupported,
-			)
-		} else if authType == "" {
-			// If not explicitly set, show warning
-			p.console.MessageUxItem(
-				ctx,
-				&ux.WarningMessage{
-					//nolint:lll
-					Description: "Terraform provisioning does not support federated authentication, defaulting to Service Principal with client ID and client secret.\n",
-				},
-			)
-		}
+	if err := checkTerraformAuthType(ctx, authType, infraOptions, p.console); err != nil {
+		return false, err
 	}
 
 	return updated, nil
@@ -574,7 +555,27 @@ func (p *GitHubCiProvider) configureFederatedAuth(
 		return fmt.Errorf("failed unmarshalling azure credentials: %w", err)
 	}
 
-	err = applyFederatedCredentials(ctx, repoSlug, &azureCredentials, p.console, credential)
+	credentialSafeName := strings.ReplaceAll(repoSlug, "/", "-")
+
+	// List of desired federated credentials
+	federatedCredentials := []graphsdk.FederatedIdentityCredential{
+		{
+			Name:        fmt.Sprintf("%s-main", credentialSafeName),
+			Issuer:      federatedIdentityIssuer,
+			Subject:     fmt.Sprintf("repo:%s:ref:refs/heads/main", repoSlug),
+			Description: convert.RefOf("Created by Azure Developer CLI"),
+			Audiences:   []string{federatedIdentityAudience},
+		},
+		{
+			Name:        fmt.Sprintf("%s-pull_request", credentialSafeName),
+			Issuer:      federatedIdentityIssuer,
+			Subject:     fmt.Sprintf("repo:%s:pull_request", repoSlug),
+			Description: convert.RefOf("Created by Azure Developer CLI"),
+			Audiences:   []string{federatedIdentityAudience},
+		},
+	}
+
+	err = applyFederatedCredentials(ctx, &azureCredentials, federatedCredentials, p.Name(), p.console, credential)
 	if err != nil {
 		return err
 	}
@@ -605,10 +606,13 @@ const (
 	federatedIdentityAudience = "api://AzureADTokenExchange"
 )
 
+// applyFederatedCredentials ensures the application of the service principal has the federated credentials, which
+// let the pipelines of the provider log in to Azure.
 func applyFederatedCredentials(
 	ctx context.Context,
-	repoSlug string,
 	azureCredentials *azcli.AzureCredentials,
+	federatedCredentials []graphsdk.FederatedIdentityCredential,
+	providerName string,
 	console input.Console,
 	credential azcore.TokenCredential,
 ) error {
@@ -636,30 +640,10 @@ func applyFederatedCredentials(
 		return fmt.Errorf("failed retrieving federated credentials: %w", err)
 	}
 
-	credentialSafeName := strings.ReplaceAll(repoSlug, "/", "-")
-
-	// List of desired federated credentials
-	federatedCredentials := []graphsdk.FederatedIdentityCredential{
-		{
-			Name:        fmt.Sprintf("%s-main", credentialSafeName),
-			Issuer:      federatedIdentityIssuer,
-			Subject:     fmt.Sprintf("repo:%s:ref:refs/heads/main", repoSlug),
-			Description: convert.RefOf("Created by Azure Developer CLI"),
-			Audiences:   []string{federatedIdentityAudience},
-		},
-		{
-			Name:        fmt.Sprintf("%s-pull_request", credentialSafeName),
-			Issuer:      federatedIdentityIssuer,
-			Subject:     fmt.Sprintf("repo:%s:pull_request", repoSlug),
-			Description: convert.RefOf("Created by Azure Developer CLI"),
-			Audiences:   []string{federatedIdentityAudience},
-		},
-	}
-
 	// Ensure the credential exists otherwise create a new one.
 	for i := range federatedCredentials {
 		err := ensureFederatedCredential(
-			ctx, graphClient, &application, existingCredsResponse.Value, &federatedCredentials[i], console)
+			ctx, graphClient, &application, existingCredsResponse.Value, &federatedCredentials[i], providerName, console)
 		if err != nil {
 			return err
 		}
@@ -668,6 +652,39 @@ func applyFederatedCredentials(
 	return nil
 }
 
+// checkTerraformAuthType validates the authentication type of pipelines provisioning with Terraform, which doesn't
+// support federated authentication.
+func checkTerraformAuthType(
+	ctx context.Context,
+	authType PipelineAuthType,
+	infraOptions provisioning.Options,
+	console input.Console,
+) error {
+	// Federated Auth + Terraform is not a supported combination
+	if infraOptions.Provider == provisioning.Terraform {
+		// Throw error if Federated auth is explicitly requested
+		if authType == AuthTypeFederated {
+			return fmt.Errorf(
+				//nolint:lll
+				"Terraform does not support federated authentication. To explicitly use client credentials set the %s flag. %w",
+				output.WithBackticks("--auth-type client-credentials"),
+				ErrAuthNotSupported,
+			)
+		} else if authType == "" {
+			// If not explicitly set, show warning
+			console.MessageUxItem(
+				ctx,
+				&ux.WarningMessage{
+					//nolint:lll
+					Description: "Terraform provisioning does not support federated authentication, defaulting to Service Principal with client ID and client secret.\n",
+				},
+			)
+		}
+	}
+
+	return nil
+}
+
 // configurePipeline is a no-op for GitHub, as the pipeline is automatically
 // created by creating the workflow files in .github folder.
 func (p *GitHubCiProvider) configurePipeline(
@@ -853,6 +870,7 @@ func ensureFederatedCredential(
 	application *graphsdk.Application,
 	existingCredentials []graphsdk.FederatedIdentityCredential,
 	repoCredential *graphsdk.FederatedIdentityCredential,
+	providerName string,
 	console input.Console,
 ) error {
 	// If a federated credential already exists for the same subject then nothing to do.
@@ -880,7 +898,7 @@ func ensureFederatedCredential(
 	console.MessageUxItem(
 		ctx,
 		&ux.DisplayedResource{
-			Type: "Federated identity credential for GitHub",
+			Type: fmt.Sprintf("Federated identity credential for %s", providerName),
 			Name: fmt.Sprintf("subject %s", repoCredential.Subject),
 		},
 	)
@@ -892,6 +910,7 @@ func createGraphClient(ctx context.Context, credential azcore.TokenCredential) (
 	graphOptions := azsdk.
 		NewClientOptionsBuilder().
 		WithTransport(httputil.GetHttpClient(ctx)).
+		WithPerRetryPolicy(azsdk.NewHttpTracePolicy(ctx)).
 		BuildCoreClientOptions()
 
 	return graphsdk.NewGraphClient(credential, graphOptions)
@@ -0,0 +1,360 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/resources"
+)
+
+// GitLabScmProvider implements ScmProvider using GitLab, gitlab.com or a self-managed instance, as the provider
+// for source control manager.
+type GitLabScmProvider struct {
+	env     *environment.Environment
+	console input.Console
+}
+
+func NewGitLabScmProvider(env *environment.Environment, console input.Console) *GitLabScmProvider {
+	return &GitLabScmProvider{
+		env:     env,
+		console: console,
+	}
+}
+
+// ***  subareaProvider implementation ******
+
+// requiredTools returns no tools, GitLab is configured with its REST API.
+func (p *GitLabScmProvider) requiredTools(ctx context.Context) ([]tools.ExternalTool, error) {
+	return []tools.ExternalTool{}, nil
+}
+
+// preConfigureCheck ensures a GitLab personal access token is available.
+func (p *GitLabScmProvider) preConfigureCheck(
+	ctx context.Context,
+	pipelineManagerArgs PipelineManagerArgs,
+	infraOptions provisioning.Options,
+	projectPath string,
+) (bool, error) {
+	_, updated, err := gitlab.EnsureTokenExists(ctx, p.env, p.console)
+	return updated, err
+}
+
+// name returns the name of the provider
+func (p *GitLabScmProvider) Name() string {
+	return "GitLab"
+}
+
+// ***  scmProvider implementation ******
+
+// configureGitRemote prompts for the url of the GitLab project to use as the remote.
+func (p *GitLabScmProvider) configureGitRemote(
+	ctx context.Context,
+	repoPath string,
+	remoteName string,
+) (string, error) {
+	for {
+		remoteUrl, err := p.console.Prompt(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf("Please enter the url of the GitLab project to use for remote %s:", remoteName),
+		})
+		if err != nil {
+			return "", fmt.Errorf("prompting for remote url: %w", err)
+		}
+
+		if _, err := gitlab.ParseRemote(remoteUrl); errors.Is(err, gitlab.ErrRemoteHostIsNotGitLab) {
+			fmt.Fprintf(p.console.Handles().Stdout, "error: \"%s\" is not a valid GitLab project URL.\n", remoteUrl)
+			continue
+		}
+
+		return remoteUrl, nil
+	}
+}
+
+// gitRepoDetails extracts the information from a GitLab remote url into general scm concepts
+// like owner, name and path
+func (p *GitLabScmProvider) gitRepoDetails(ctx context.Context, remoteUrl string) (*gitRepositoryDetails, error) {
+	remote, err := gitlab.ParseRemote(remoteUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitRepositoryDetails{
+		owner:    remote.Namespace(),
+		repoName: remote.Name(),
+		remote:   remoteUrl,
+		details:  remote,
+	}, nil
+}
+
+// preventGitPush is a no-op for GitLab, pipelines run for any pushed branch with a .gitlab-ci.yml.
+func (p *GitLabScmProvider) preventGitPush(
+	ctx context.Context,
+	gitRepo *gitRepositoryDetails,
+	remoteName string,
+	branchName string) (bool, error) {
+	return false, nil
+}
+
+func (p *GitLabScmProvider) GitPush(
+	ctx context.Context,
+	gitCli git.GitCli,
+	gitRepo *gitRepositoryDetails,
+	remoteName string,
+	branchName string) error {
+	return gitCli.PushUpstream(ctx, gitRepo.gitProjectPath, remoteName, branchName)
+}
+
+// GitLabCiProvider implements a CiProvider using GitLab CI/CD, configured with the CI/CD variables of the project.
+type GitLabCiProvider struct {
+	env        *environment.Environment
+	credential azcore.TokenCredential
+	console    input.Console
+}
+
+func NewGitLabCiProvider(
+	env *environment.Environment, credential azcore.TokenCredential, console input.Console) *GitLabCiProvider {
+	return &GitLabCiProvider{
+		env:        env,
+		credential: credential,
+		console:    console,
+	}
+}
+
+// ***  subareaProvider implementation ******
+
+// requiredTools returns no tools, GitLab is configured with its REST API.
+func (p *GitLabCiProvider) requiredTools(ctx context.Context) ([]tools.ExternalTool, error) {
+	return []tools.ExternalTool{}, nil
+}
+
+// preConfigureCheck ensures a GitLab personal access token is available and scaffolds the .gitlab-ci.yml of the
+// project when it doesn't have one.
+func (p *GitLabCiProvider) preConfigureCheck(
+	ctx context.Context,
+	pipelineManagerArgs PipelineManagerArgs,
+	infraOptions provisioning.Options,
+	projectPath string,
+) (bool, error) {
+	_, updated, err := gitlab.EnsureTokenExists(ctx, p.env, p.console)
+	if err != nil {
+		return updated, err
+	}
+
+	authType := PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName)
+	if err := checkTerraformAuthType(ctx, authType, infraOptions, p.console); err != nil {
+		return false, err
+	}
+
+	ymlPath := filepath.Join(projectPath, gitlab.PipelineYamlPath)
+	if !ymlExists(ymlPath) {
+		if err := os.WriteFile(ymlPath, resources.GitLabPipelineYaml, osutil.PermissionFile); err != nil {
+			return updated, fmt.Errorf("creating %s: %w", gitlab.PipelineYamlPath, err)
+		}
+
+		p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: "GitLab CI/CD pipeline",
+			Name: gitlab.PipelineYamlPath,
+		})
+	}
+
+	return updated, nil
+}
+
+// name returns the name of the provider.
+func (p *GitLabCiProvider) Name() string {
+	return "GitLab"
+}
+
+// ***  ciProvider implementation ******
+
+// configureConnection sets the CI/CD variables of the GitLab project for its pipelines to log in to Azure, with
+// the client secret of the service principal or with a federated credential trusting the ID tokens of the jobs.
+func (p *GitLabCiProvider) configureConnection(
+	ctx context.Context,
+	azdEnvironment *environment.Environment,
+	repoDetails *gitRepositoryDetails,
+	infraOptions provisioning.Options,
+	credentials json.RawMessage,
+	authType PipelineAuthType,
+) error {
+	remote := repoDetails.details.(*gitlab.Remote)
+
+	client, err := p.newClient(ctx, remote)
+	if err != nil {
+		return err
+	}
+
+	var azureCredentials azcli.AzureCredentials
+	if err := json.Unmarshal(credentials, &azureCredentials); err != nil {
+		return fmt.Errorf("failed unmarshalling azure credentials: %w", err)
+	}
+
+	// Default auth type to client-credentials for terraform
+	if infraOptions.Provider == provisioning.Terraform && authType == "" {
+		authType = AuthTypeClientCredentials
+	}
+
+	variables := []gitlab.Variable{
+		{Key: environment.EnvNameEnvVarName, Value: azdEnvironment.GetEnvName()},
+		{Key: environment.LocationEnvVarName, Value: azdEnvironment.GetLocation()},
+		{Key: environment.SubscriptionIdEnvVarName, Value: azureCredentials.SubscriptionId},
+		{Key: environment.TenantIdEnvVarName, Value: azureCredentials.TenantId},
+		{Key: "AZURE_CLIENT_ID", Value: azureCredentials.ClientId},
+	}
+
+	switch authType {
+	case AuthTypeClientCredentials:
+		/* #nosec G101 - Potential hardcoded credentials - false positive */
+		variables = append(variables,
+			gitlab.Variable{Key: "AZURE_CLIENT_SECRET", Value: azureCredentials.ClientSecret, Masked: true})
+
+		if infraOptions.Provider == provisioning.Terraform {
+			terraformVariables, err := terraformGitLabVariables(ctx, azdEnvironment, &azureCredentials, p.console)
+			if err != nil {
+				return err
+			}
+			variables = append(variables, terraformVariables...)
+		}
+	default:
+		federatedCredentials := []graphsdk.FederatedIdentityCredential{
+			{
+				Name:   strings.ReplaceAll(remote.ProjectPath, "/", "-") + "-" + gitlab.DefaultBranch,
+				Issuer: "https://" + remote.Host,
+				Subject: fmt.Sprintf(
+					"project_path:%s:ref_type:branch:ref:%s", remote.ProjectPath, gitlab.DefaultBranch),
+				Description: convert.RefOf("Created by Azure Developer CLI"),
+				Audiences:   []string{gitlab.IdTokenAudience},
+			},
+		}
+
+		err := applyFederatedCredentials(
+			ctx, &azureCredentials, federatedCredentials, p.Name(), p.console, p.credential)
+		if err != nil {
+			return fmt.Errorf("failed configuring authentication: %w", err)
+		}
+	}
+
+	for _, variable := range variables {
+		if err := client.SetVariable(ctx, remote.ProjectPath, variable); err != nil {
+			return fmt.Errorf("failed setting gitlab variable '%s': %w", variable.Key, err)
+		}
+
+		kind := ux.GitHubVariable
+		if variable.Masked {
+			kind = ux.GitHubSecret
+		}
+		p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
+			Name: variable.Key,
+			Kind: kind,
+		})
+	}
+
+	p.console.MessageUxItem(ctx, &ux.MultilineMessage{
+		Lines: []string{
+			"",
+			"GitLab CI/CD variables are now configured. You can view the variables that were created at this link:",
+			output.WithLinkFormat("%s/-/settings/ci_cd#js-cicd-variables-settings", remote.WebUrl()),
+			""},
+	})
+
+	return nil
+}
+
+// terraformGitLabVariables returns the variables terraform reads the credential and the remote state from
+func terraformGitLabVariables(
+	ctx context.Context,
+	azdEnvironment *environment.Environment,
+	azureCredentials *azcli.AzureCredentials,
+	console input.Console,
+) ([]gitlab.Variable, error) {
+	/* #nosec G101 - Potential hardcoded credentials - false positive */
+	variables := []gitlab.Variable{
+		{Key: "ARM_TENANT_ID", Value: azureCredentials.TenantId},
+		{Key: "ARM_CLIENT_ID", Value: azureCredentials.ClientId},
+		{Key: "ARM_CLIENT_SECRET", Value: azureCredentials.ClientSecret, Masked: true},
+	}
+
+	for _, key := range []string{"RS_RESOURCE_GROUP", "RS_STORAGE_ACCOUNT", "RS_CONTAINER_NAME"} {
+		value, ok := azdEnvironment.LookupEnv(key)
+		if !ok || strings.TrimSpace(value) == "" {
+			console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: "Terraform Remote State configuration is invalid",
+				HidePrefix:  true,
+			})
+			console.Message(
+				ctx,
+				fmt.Sprintf(
+					"Visit %s for more information on configuring Terraform remote state",
+					output.WithLinkFormat("https://aka.ms/azure-dev/terraform"),
+				),
+			)
+			console.Message(ctx, "")
+			return nil, errors.New("terraform remote state is not correctly configured")
+		}
+
+		variables = append(variables, gitlab.Variable{Key: key, Value: value})
+	}
+
+	return variables, nil
+}
+
+// configurePipeline checks the GitLab project exists, its pipeline is defined by the .gitlab-ci.yml pushed to it.
+func (p *GitLabCiProvider) configurePipeline(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	provisioningProvider provisioning.Options,
+) (*CiPipeline, error) {
+	remote := repoDetails.details.(*gitlab.Remote)
+
+	client, err := p.newClient(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := client.GetProject(ctx, remote.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CiPipeline{
+		name:   "pipelines",
+		remote: project.WebUrl + "/-/pipelines",
+	}, nil
+}
+
+func (p *GitLabCiProvider) newClient(ctx context.Context, remote *gitlab.Remote) (*gitlab.Client, error) {
+	token, _, err := gitlab.EnsureTokenExists(ctx, p.env, p.console)
+	if err != nil {
+		return nil, err
+	}
+
+	options := azsdk.
+		NewClientOptionsBuilder().
+		WithTransport(httputil.GetHttpClient(ctx)).
+		WithPerRetryPolicy(azsdk.NewHttpTracePolicy(ctx)).
+		BuildCoreClientOptions()
+
+	return gitlab.NewClient(remote.Host, token, options), nil
+}
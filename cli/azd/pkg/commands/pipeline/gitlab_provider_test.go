@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/resources"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_gitLab_provider_getRepoDetails(t *testing.T) {
+	provider := &GitLabScmProvider{}
+	details, err := provider.gitRepoDetails(context.Background(), "git@gitlab.com:contoso/web/storefront.git")
+	require.NoError(t, err)
+	require.Equal(t, "contoso/web", details.owner)
+	require.Equal(t, "storefront", details.repoName)
+	require.Equal(t, &gitlab.Remote{Host: "gitlab.com", ProjectPath: "contoso/web/storefront"}, details.details)
+
+	_, err = provider.gitRepoDetails(context.Background(), "https://github.com/contoso/storefront.git")
+	require.ErrorIs(t, err, gitlab.ErrRemoteHostIsNotGitLab)
+}
+
+func Test_gitLab_provider_preConfigureCheck(t *testing.T) {
+	t.Run("scaffolds pipeline", func(t *testing.T) {
+		projectPath := t.TempDir()
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.EphemeralWithValues("test-env", map[string]string{gitlab.GitLabTokenName: "TOKEN"})
+
+		provider := NewGitLabCiProvider(env, mockContext.Credentials, mockContext.Console)
+		updated, err := provider.preConfigureCheck(
+			*mockContext.Context, PipelineManagerArgs{}, provisioning.Options{}, projectPath)
+		require.NoError(t, err)
+		require.False(t, updated)
+
+		contents, err := os.ReadFile(filepath.Join(projectPath, gitlab.PipelineYamlPath))
+		require.NoError(t, err)
+		require.Equal(t, resources.GitLabPipelineYaml, contents)
+	})
+
+	t.Run("keeps existing pipeline", func(t *testing.T) {
+		projectPath := t.TempDir()
+		ymlPath := filepath.Join(projectPath, gitlab.PipelineYamlPath)
+		require.NoError(t, os.WriteFile(ymlPath, []byte("deploy:\n"), osutil.PermissionFile))
+
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.EphemeralWithValues("test-env", map[string]string{gitlab.GitLabTokenName: "TOKEN"})
+
+		provider := NewGitLabCiProvider(env, mockContext.Credentials, mockContext.Console)
+		_, err := provider.preConfigureCheck(
+			*mockContext.Context, PipelineManagerArgs{}, provisioning.Options{}, projectPath)
+		require.NoError(t, err)
+
+		contents, err := os.ReadFile(ymlPath)
+		require.NoError(t, err)
+		require.Equal(t, "deploy:\n", string(contents))
+	})
+
+	t.Run("terraform federated", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.EphemeralWithValues("test-env", map[string]string{gitlab.GitLabTokenName: "TOKEN"})
+
+		provider := NewGitLabCiProvider(env, mockContext.Credentials, mockContext.Console)
+		_, err := provider.preConfigureCheck(
+			*mockContext.Context,
+			PipelineManagerArgs{PipelineAuthTypeName: string(AuthTypeFederated)},
+			provisioning.Options{Provider: provisioning.Terraform},
+			t.TempDir())
+		require.ErrorIs(t, err, ErrAuthNotSupported)
+	})
+}
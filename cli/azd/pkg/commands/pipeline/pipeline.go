@@ -16,6 +16,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -131,6 +132,7 @@ func ymlExists(ymlPath string) bool {
 const (
 	gitHubLabel     string = "github"
 	azdoLabel       string = "azdo"
+	gitLabLabel     string = "gitlab"
 	envPersistedKey string = "AZD_PIPELINE_PROVIDER"
 )
 
@@ -174,9 +176,11 @@ func resolveProvider(
 //   - both .github and .azdo folders found: GitHub scm and ci as provider
 //   - overrideProvider set to github (regardless of folders): GitHub scm and ci as provider
 //   - overrideProvider set to azdo (regardless of folders): Azdo scm and ci as provider
+//   - only .gitlab-ci.yml is found, or overrideProvider set to gitlab: GitLab scm and ci as provider, the
+//     .gitlab-ci.yml is scaffolded when missing
 //   - none of the folders found: return error
 //   - no azd context in the ctx: return error
-//   - overrideProvider set to neither github or azdo: return error
+//   - overrideProvider set to neither github, azdo or gitlab: return error
 //   - Note: The provider is persisted in the environment so the next time the function is run
 //     the same provider is used directly, unless the overrideProvider is used to change
 //     the last used configuration
@@ -198,13 +202,16 @@ func DetectProviders(
 	hasGitHubFolder := folderExists(filepath.Join(projectDir, githubFolder))
 	hasAzDevOpsFolder := folderExists(filepath.Join(projectDir, azdoFolder))
 	hasAzDevOpsYml := ymlExists(filepath.Join(projectDir, azdoYml))
+	hasGitLabYml := ymlExists(filepath.Join(projectDir, gitlab.PipelineYamlPath))
 
-	// Error missing config for any provider
-	if !hasGitHubFolder && !hasAzDevOpsFolder {
+	// Error missing config for any provider. The GitLab pipeline is scaffolded when selected explicitly.
+	if !hasGitHubFolder && !hasAzDevOpsFolder && !hasGitLabYml && overrideWith != gitLabLabel {
 		return nil, nil, fmt.Errorf(
-			"no CI/CD provider configuration found. Expecting either %s and/or %s folder in the project root directory.",
+			"no CI/CD provider configuration found. Expecting either %s and/or %s folder, or a %s file in the "+
+				"project root directory.",
 			gitHubLabel,
-			azdoLabel)
+			azdoLabel,
+			gitlab.PipelineYamlPath)
 	}
 
 	// overrideWith is the last overriding mode. When it is empty
@@ -230,10 +237,20 @@ func DetectProviders(
 		return nil, nil, fmt.Errorf("%s file is missing in %s folder. Can't use selected provider", azdoYml, azdoFolder)
 	}
 	// using wrong override value
-	if overrideWith != "" && overrideWith != azdoLabel && overrideWith != gitHubLabel {
+	if overrideWith != "" && overrideWith != azdoLabel && overrideWith != gitHubLabel && overrideWith != gitLabLabel {
 		return nil, nil, fmt.Errorf("%s is not a known pipeline provider", overrideWith)
 	}
 
+	// GitLab either by override or by finding only its pipeline
+	if overrideWith == gitLabLabel || overrideWith == "" && hasGitLabYml && !hasGitHubFolder && !hasAzDevOpsFolder {
+		_ = savePipelineProviderToEnv(gitLabLabel, env)
+		log.Printf("Using pipeline provider: %s", output.WithHighLightFormat("GitLab"))
+		scmProvider := NewGitLabScmProvider(env, console)
+		ciProvider := NewGitLabCiProvider(env, credential, console)
+
+		return scmProvider, ciProvider, nil
+	}
+
 	// At this point, we know that override value has either:
 	// - github or azdo value
 	// - OR is not set
@@ -40,7 +40,8 @@ func Test_detectProviders(t *testing.T) {
 		assert.EqualError(
 			t,
 			err,
-			"no CI/CD provider configuration found. Expecting either github and/or azdo folder in the project root directory.",
+			"no CI/CD provider configuration found. Expecting either github and/or azdo folder, or a .gitlab-ci.yml file "+
+				"in the project root directory.",
 		)
 	})
 
@@ -554,3 +555,73 @@ func Test_detectProviders(t *testing.T) {
 		os.Remove(ghFolder)
 	})
 }
+
+func Test_detectProviders_gitlab(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.Background()
+
+	azdContext := azdcontext.NewAzdContextWithDirectory(tempDir)
+	mockContext := mocks.NewMockContext(ctx)
+
+	err := os.WriteFile(filepath.Join(tempDir, "azure.yaml"), []byte("name: test\n"), osutil.PermissionFile)
+	assert.NoError(t, err)
+
+	t.Run("override without pipeline", func(t *testing.T) {
+		env := environment.Ephemeral()
+		scmProvider, ciProvider, err := DetectProviders(
+			ctx,
+			azdContext,
+			env,
+			gitLabLabel,
+			mockContext.Console,
+			mockContext.Credentials,
+			mockContext.CommandRunner,
+		)
+		assert.IsType(t, &GitLabScmProvider{}, scmProvider)
+		assert.IsType(t, &GitLabCiProvider{}, ciProvider)
+		assert.NoError(t, err)
+
+		envValue, found := env.Dotenv()[envPersistedKey]
+		assert.True(t, found)
+		assert.Equal(t, gitLabLabel, envValue)
+	})
+
+	ymlPath := filepath.Join(tempDir, ".gitlab-ci.yml")
+	err = os.WriteFile(ymlPath, []byte("deploy:\n"), osutil.PermissionFile)
+	assert.NoError(t, err)
+
+	t.Run("from pipeline", func(t *testing.T) {
+		scmProvider, ciProvider, err := DetectProviders(
+			ctx,
+			azdContext,
+			environment.Ephemeral(),
+			"",
+			mockContext.Console,
+			mockContext.Credentials,
+			mockContext.CommandRunner,
+		)
+		assert.IsType(t, &GitLabScmProvider{}, scmProvider)
+		assert.IsType(t, &GitLabCiProvider{}, ciProvider)
+		assert.NoError(t, err)
+	})
+
+	t.Run("github folder preferred", func(t *testing.T) {
+		ghFolder := filepath.Join(tempDir, githubFolder)
+		err := os.MkdirAll(ghFolder, osutil.PermissionDirectory)
+		assert.NoError(t, err)
+		defer os.RemoveAll(filepath.Join(tempDir, ".github"))
+
+		scmProvider, ciProvider, err := DetectProviders(
+			ctx,
+			azdContext,
+			environment.Ephemeral(),
+			"",
+			mockContext.Console,
+			mockContext.Credentials,
+			mockContext.CommandRunner,
+		)
+		assert.IsType(t, &GitHubScmProvider{}, scmProvider)
+		assert.IsType(t, &GitHubCiProvider{}, ciProvider)
+		assert.NoError(t, err)
+	})
+}
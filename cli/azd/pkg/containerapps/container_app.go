@@ -11,6 +11,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/benbjohnson/clock"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // ContainerAppService exposes operations for managing Azure Container Apps
@@ -22,14 +24,56 @@ type ContainerAppService interface {
 		resourceGroup,
 		appName string,
 	) (*ContainerAppIngressConfiguration, error)
-	// Adds and activates a new revision to the specified container app
+	// Adds and activates a new revision to the specified container app, the environment variables set on its container
 	AddRevision(
 		ctx context.Context,
 		subscriptionId string,
 		resourceGroupName string,
 		appName string,
 		imageName string,
+		env map[string]string,
 	) error
+	// Gets the health of the latest revision of the specified container app
+	GetHealth(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+	) (*ContainerAppHealth, error)
+	// Streams the console logs of the replicas of the latest revision of the specified container app
+	StreamLogs(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		options ContainerAppLogsOptions,
+		write func(replica string, line string) error,
+	) error
+	// Gets the DNS records verifying the custom domains of the specified container app
+	GetCustomDomainVerification(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+	) (*ContainerAppCustomDomainVerification, error)
+	// Binds the custom domain to the ingress of the specified container app, with TLS when the certificate is set
+	BindCustomDomain(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		hostName string,
+		certificateId string,
+	) error
+	// Creates the managed certificate of a custom domain bound to a container app of the environment, and returns its
+	// resource id once it's issued
+	CreateManagedCertificate(
+		ctx context.Context,
+		subscriptionId string,
+		environmentId string,
+		location string,
+		hostName string,
+	) (string, error)
 }
 
 // NewContainerAppService creates a new ContainerAppService
@@ -84,13 +128,66 @@ func (cas *containerAppService) GetIngressConfiguration(
 	}, nil
 }
 
-// Adds and activates a new revision to the specified container app
+// ContainerAppHealth is the health of the latest revision of a container app
+type ContainerAppHealth struct {
+	RevisionName string
+	// The health of the revision replicas (Healthy, Unhealthy or None)
+	HealthState string
+	// The provisioning state of the revision (Provisioned, Provisioning, Failed, ...)
+	ProvisioningState string
+	ProvisioningError string
+	Replicas          int32
+}
+
+// Gets the health of the latest revision of the specified container app
+func (cas *containerAppService) GetHealth(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (*ContainerAppHealth, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerApp.Properties == nil || containerApp.Properties.LatestRevisionName == nil {
+		return nil, fmt.Errorf("container app '%s' does not have any revision", appName)
+	}
+
+	revisionName := *containerApp.Properties.LatestRevisionName
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionResponse, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting revision '%s': %w", revisionName, err)
+	}
+
+	health := &ContainerAppHealth{
+		RevisionName: revisionName,
+	}
+
+	if properties := revisionResponse.Properties; properties != nil {
+		health.HealthState = string(convert.ToValueWithDefault(properties.HealthState, ""))
+		health.ProvisioningState = string(convert.ToValueWithDefault(properties.ProvisioningState, ""))
+		health.ProvisioningError = convert.ToValueWithDefault(properties.ProvisioningError, "")
+		health.Replicas = convert.ToValueWithDefault(properties.Replicas, 0)
+	}
+
+	return health, nil
+}
+
+// Adds and activates a new revision to the specified container app, the environment variables set on its container
 func (cas *containerAppService) AddRevision(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroupName string,
 	appName string,
 	imageName string,
+	env map[string]string,
 ) error {
 	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
 	if err != nil {
@@ -113,6 +210,8 @@ func (cas *containerAppService) AddRevision(
 	revision := revisionResponse.Revision
 	revision.Properties.Template.RevisionSuffix = convert.RefOf(fmt.Sprintf("azd-%d", cas.clock.Now().Unix()))
 	revision.Properties.Template.Containers[0].Image = convert.RefOf(imageName)
+	revision.Properties.Template.Containers[0].Env = mergeEnvironmentVars(
+		revision.Properties.Template.Containers[0].Env, env)
 
 	// Update the container app with the new revision
 	containerApp.Properties.Template = revision.Properties.Template
@@ -139,6 +238,35 @@ func (cas *containerAppService) AddRevision(
 	return nil
 }
 
+// mergeEnvironmentVars sets the values of env on the environment variables of a container, the variables of the same
+// name replaced, ex. a variable referencing a secret
+func mergeEnvironmentVars(
+	vars []*armappcontainers.EnvironmentVar,
+	env map[string]string,
+) []*armappcontainers.EnvironmentVar {
+	if len(env) == 0 {
+		return vars
+	}
+
+	merged := []*armappcontainers.EnvironmentVar{}
+	for _, envVar := range vars {
+		if _, has := env[convert.ToValueWithDefault(envVar.Name, "")]; !has {
+			merged = append(merged, envVar)
+		}
+	}
+
+	names := maps.Keys(env)
+	slices.Sort(names)
+	for _, name := range names {
+		merged = append(merged, &armappcontainers.EnvironmentVar{
+			Name:  convert.RefOf(name),
+			Value: convert.RefOf(env[name]),
+		})
+	}
+
+	return merged
+}
+
 func (cas *containerAppService) syncSecrets(
 	ctx context.Context,
 	subscriptionId string,
@@ -0,0 +1,188 @@
+package containerapps
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// The API version of the custom domains of container apps. Custom domains are bound without a certificate, until their
+// managed certificate is issued, since this version.
+const customDomainApiVersion = "2023-05-01"
+
+var certificateNameRegexp = regexp.MustCompile(`[^a-z0-9-]`)
+
+// ContainerAppCustomDomainVerification describes the DNS records verifying the custom domains of a container app
+type ContainerAppCustomDomainVerification struct {
+	// The host name of the ingress of the container app, the target of the CNAME records of the custom domains
+	Fqdn string
+	// The value of the asuid TXT records verifying the ownership of the custom domains
+	VerificationId string
+	// The resource id of the Container Apps environment of the container app
+	EnvironmentId string
+	Location      string
+}
+
+// Gets the DNS records verifying the custom domains of the specified container app
+func (cas *containerAppService) GetCustomDomainVerification(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (*ContainerAppCustomDomainVerification, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := containerApp.Properties
+	if properties == nil || properties.Configuration == nil || properties.Configuration.Ingress == nil ||
+		properties.Configuration.Ingress.Fqdn == nil {
+		return nil, fmt.Errorf("container app '%s' does not have ingress, custom domains require ingress", appName)
+	}
+
+	return &ContainerAppCustomDomainVerification{
+		Fqdn:           *properties.Configuration.Ingress.Fqdn,
+		VerificationId: convert.ToValueWithDefault(properties.CustomDomainVerificationID, ""),
+		EnvironmentId:  convert.ToValueWithDefault(properties.ManagedEnvironmentID, ""),
+		Location:       convert.ToValueWithDefault(containerApp.Location, ""),
+	}, nil
+}
+
+// Binds the custom domain to the ingress of the specified container app, with TLS when the certificate is set
+func (cas *containerAppService) BindCustomDomain(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	hostName string,
+	certificateId string,
+) error {
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cas.httpClient, cas.userAgent).BuildArmClientOptions()
+	options.APIVersion = customDomainApiVersion
+	appClient, err := cas.createContainerAppsClientWithOptions(ctx, subscriptionId, options)
+	if err != nil {
+		return err
+	}
+
+	response, err := appClient.Get(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	containerApp := &response.ContainerApp
+	if containerApp.Properties == nil || containerApp.Properties.Configuration == nil ||
+		containerApp.Properties.Configuration.Ingress == nil {
+		return fmt.Errorf("container app '%s' does not have ingress, custom domains require ingress", appName)
+	}
+
+	domain := &armappcontainers.CustomDomain{
+		Name:        convert.RefOf(hostName),
+		BindingType: convert.RefOf(armappcontainers.BindingTypeDisabled),
+	}
+	if certificateId != "" {
+		domain.BindingType = convert.RefOf(armappcontainers.BindingTypeSniEnabled)
+		domain.CertificateID = convert.RefOf(certificateId)
+	}
+
+	ingress := containerApp.Properties.Configuration.Ingress
+	domains := []*armappcontainers.CustomDomain{domain}
+	for _, existing := range ingress.CustomDomains {
+		if existing.Name == nil || !strings.EqualFold(*existing.Name, hostName) {
+			domains = append(domains, existing)
+		}
+	}
+	ingress.CustomDomains = domains
+
+	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
+	if err != nil {
+		return fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	poller, err := appClient.BeginUpdate(ctx, resourceGroupName, appName, *containerApp, nil)
+	if err != nil {
+		return fmt.Errorf("begin binding custom domain %s: %w", hostName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("binding custom domain %s: %w", hostName, err)
+	}
+
+	return nil
+}
+
+// Creates the free managed certificate of a custom domain bound to a container app of the environment, and returns
+// its resource id once it's issued
+func (cas *containerAppService) CreateManagedCertificate(
+	ctx context.Context,
+	subscriptionId string,
+	environmentId string,
+	location string,
+	hostName string,
+) (string, error) {
+	environment, err := arm.ParseResourceID(environmentId)
+	if err != nil {
+		return "", fmt.Errorf("parsing environment id: %w", err)
+	}
+
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cas.httpClient, cas.userAgent).BuildArmClientOptions()
+	client, err := armresources.NewClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", fmt.Errorf("creating Resources client: %w", err)
+	}
+
+	// The name of the certificate is derived from the host name, so it's only created once
+	name := certificateNameRegexp.ReplaceAllString(strings.ToLower(hostName), "-")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+
+	certificateId := fmt.Sprintf("%s/managedCertificates/%s", environment.String(), name)
+	poller, err := client.BeginCreateOrUpdateByID(ctx, certificateId, customDomainApiVersion, armresources.GenericResource{
+		Location: convert.RefOf(location),
+		Properties: map[string]any{
+			"subjectName":             hostName,
+			"domainControlValidation": "CNAME",
+		},
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("begin creating managed certificate for %s: %w", hostName, err)
+	}
+
+	// The creation completes once the certificate is issued, after the validation of the domain
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return "", fmt.Errorf("creating managed certificate for %s: %w", hostName, err)
+	}
+
+	return certificateId, nil
+}
+
+func (cas *containerAppService) createContainerAppsClientWithOptions(
+	ctx context.Context,
+	subscriptionId string,
+	options *arm.ClientOptions,
+) (*armappcontainers.ContainerAppsClient, error) {
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armappcontainers.NewContainerAppsClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerApps client: %w", err)
+	}
+
+	return client, nil
+}
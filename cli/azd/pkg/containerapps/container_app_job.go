@@ -0,0 +1,318 @@
+package containerapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
+	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// The maximum number of log lines returned for a job
+const jobLogsLimit = 1000
+
+// ContainerAppJobService exposes operations for managing Azure Container Apps jobs
+type ContainerAppJobService interface {
+	// Updates the image of the first container of the specified job, used by the next executions
+	UpdateImage(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		imageName string,
+	) error
+	// Gets the trigger and the latest execution of the specified job
+	GetStatus(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+	) (*ContainerAppJobStatus, error)
+	// Gets the console logs of the executions of the specified job, from the Log Analytics workspace of its
+	// Container Apps environment
+	GetLogs(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		since time.Duration,
+	) ([]ContainerAppJobLog, error)
+}
+
+// ContainerAppJobStatus is the trigger and the latest execution of a Container Apps job
+type ContainerAppJobStatus struct {
+	// Manual, Schedule or Event
+	TriggerType string
+	// The cron expression of scheduled jobs
+	CronExpression string
+	// The types of the scale rules of event-driven jobs, ex. azure-servicebus
+	EventRules        []string
+	ProvisioningState string
+	// The most recent execution, nil when the job never ran
+	LatestExecution *ContainerAppJobExecution
+}
+
+// ContainerAppJobExecution is an execution of a Container Apps job
+type ContainerAppJobExecution struct {
+	Name string
+	// Running, Processing, Stopped, Degraded, Failed, Unknown or Succeeded
+	Status    string
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// ContainerAppJobLog is a line written to the console by an execution of a job
+type ContainerAppJobLog struct {
+	Timestamp time.Time
+	// The replica of the execution writing the line
+	Replica string
+	Message string
+}
+
+// NewContainerAppJobService creates a new ContainerAppJobService
+func NewContainerAppJobService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
+) ContainerAppJobService {
+	return &containerAppJobService{
+		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
+		userAgent:          azdinternal.UserAgent(),
+	}
+}
+
+type containerAppJobService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
+	userAgent          string
+}
+
+// Updates the image of the first container of the specified job
+func (cjs *containerAppJobService) UpdateImage(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	imageName string,
+) error {
+	client, err := cjs.createJobsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	job, err := client.Get(ctx, resourceGroupName, jobName)
+	if err != nil {
+		return fmt.Errorf("getting container app job: %w", err)
+	}
+
+	template := job.Properties.Template
+	containers, _ := template["containers"].([]any)
+	if len(containers) == 0 {
+		return fmt.Errorf("container app job '%s' does not have any container", jobName)
+	}
+
+	container, ok := containers[0].(map[string]any)
+	if !ok {
+		return fmt.Errorf("container app job '%s' has an unexpected container definition", jobName)
+	}
+	container["image"] = imageName
+
+	if err := client.UpdateTemplate(ctx, resourceGroupName, jobName, template); err != nil {
+		return fmt.Errorf("updating container app job: %w", err)
+	}
+
+	return nil
+}
+
+// Gets the trigger and the latest execution of the specified job
+func (cjs *containerAppJobService) GetStatus(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+) (*ContainerAppJobStatus, error) {
+	client, err := cjs.createJobsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := client.Get(ctx, resourceGroupName, jobName)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app job: %w", err)
+	}
+
+	status := &ContainerAppJobStatus{
+		TriggerType:       job.Properties.Configuration.TriggerType,
+		ProvisioningState: job.Properties.ProvisioningState,
+	}
+
+	if schedule := job.Properties.Configuration.ScheduleTriggerConfig; schedule != nil {
+		status.CronExpression = schedule.CronExpression
+	}
+
+	if event := job.Properties.Configuration.EventTriggerConfig; event != nil {
+		for _, rule := range event.Scale.Rules {
+			status.EventRules = append(status.EventRules, rule.Type)
+		}
+	}
+
+	executions, err := client.ListExecutions(ctx, resourceGroupName, jobName)
+	if err != nil {
+		return nil, fmt.Errorf("listing container app job executions: %w", err)
+	}
+
+	// Executions without a start time are pending, and the most recent
+	sort.SliceStable(executions, func(i, j int) bool {
+		start := executions[i].Properties.StartTime
+		other := executions[j].Properties.StartTime
+		if start == nil || other == nil {
+			return start == nil && other != nil
+		}
+
+		return start.After(*other)
+	})
+
+	if len(executions) > 0 {
+		latest := executions[0]
+		status.LatestExecution = &ContainerAppJobExecution{
+			Name:      latest.Name,
+			Status:    latest.Properties.Status,
+			StartTime: latest.Properties.StartTime,
+			EndTime:   latest.Properties.EndTime,
+		}
+	}
+
+	return status, nil
+}
+
+// Gets the console logs of the executions of the specified job
+func (cjs *containerAppJobService) GetLogs(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	since time.Duration,
+) ([]ContainerAppJobLog, error) {
+	client, err := cjs.createJobsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := client.Get(ctx, resourceGroupName, jobName)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app job: %w", err)
+	}
+
+	workspaceId, err := cjs.logAnalyticsWorkspace(ctx, subscriptionId, job.Properties.EnvironmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := cjs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cjs.httpClient, cjs.userAgent).BuildCoreClientOptions()
+	logsClient := azsdk.NewLogAnalyticsClient(credential, options)
+
+	query := fmt.Sprintf(
+		"ContainerAppConsoleLogs_CL | where ContainerJobName_s == '%s' "+
+			"| project TimeGenerated, ContainerGroupName_s, Log_s | order by TimeGenerated asc | take %d",
+		jobName,
+		jobLogsLimit,
+	)
+	timespan := fmt.Sprintf("PT%dS", int(since.Seconds()))
+
+	result, err := logsClient.Query(ctx, "/workspaces/"+workspaceId, query, timespan)
+	if err != nil {
+		return nil, fmt.Errorf("querying container app job logs: %w", err)
+	}
+
+	logs := []ContainerAppJobLog{}
+	if len(result.Tables) == 0 {
+		return logs, nil
+	}
+
+	for _, row := range result.Tables[0].Rows {
+		if len(row) < 3 {
+			continue
+		}
+
+		logLine := ContainerAppJobLog{}
+		if timestamp, ok := row[0].(string); ok {
+			logLine.Timestamp, _ = time.Parse(time.RFC3339Nano, timestamp)
+		}
+		logLine.Replica, _ = row[1].(string)
+		logLine.Message, _ = row[2].(string)
+
+		logs = append(logs, logLine)
+	}
+
+	return logs, nil
+}
+
+// Returns the customer id of the Log Analytics workspace receiving the logs of the Container Apps environment
+func (cjs *containerAppJobService) logAnalyticsWorkspace(
+	ctx context.Context,
+	subscriptionId string,
+	environmentId string,
+) (string, error) {
+	resourceId, err := arm.ParseResourceID(environmentId)
+	if err != nil {
+		return "", fmt.Errorf("parsing container apps environment id: %w", err)
+	}
+
+	credential, err := cjs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cjs.httpClient, cjs.userAgent).BuildArmClientOptions()
+	client, err := armappcontainers.NewManagedEnvironmentsClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", fmt.Errorf("creating ManagedEnvironments client: %w", err)
+	}
+
+	response, err := client.Get(ctx, resourceId.ResourceGroupName, resourceId.Name, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting container apps environment: %w", err)
+	}
+
+	if properties := response.Properties; properties != nil &&
+		properties.AppLogsConfiguration != nil &&
+		properties.AppLogsConfiguration.LogAnalyticsConfiguration != nil {
+		if customerId := convert.ToValueWithDefault(
+			properties.AppLogsConfiguration.LogAnalyticsConfiguration.CustomerID, ""); customerId != "" {
+			return customerId, nil
+		}
+	}
+
+	return "", errors.New("the container apps environment does not send its logs to a Log Analytics workspace")
+}
+
+func (cjs *containerAppJobService) createJobsClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*azsdk.ContainerAppJobsClient, error) {
+	credential, err := cjs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cjs.httpClient, cjs.userAgent).BuildArmClientOptions()
+	client, err := azsdk.NewContainerAppJobsClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerAppJobs client: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,162 @@
+package containerapps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const jobPath = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.App/jobs/JOB_NAME"
+
+func mockJobGet(mockContext *mocks.MockContext) {
+	job := map[string]any{
+		"id":   jobPath,
+		"name": "JOB_NAME",
+		"properties": map[string]any{
+			"provisioningState": "Succeeded",
+			"environmentId": "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+				"Microsoft.App/managedEnvironments/ENVIRONMENT",
+			"configuration": map[string]any{
+				"triggerType":           "Schedule",
+				"scheduleTriggerConfig": map[string]any{"cronExpression": "*/5 * * * *"},
+			},
+			"template": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "main", "image": "ORIGINAL_IMAGE_NAME", "resources": map[string]any{"cpu": 0.5}},
+				},
+			},
+		},
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == jobPath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, job)
+	})
+}
+
+func Test_ContainerAppJob_UpdateImage(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockJobGet(mockContext)
+
+	var patch map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPatch && request.URL.Path == jobPath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &patch))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"name": "JOB_NAME"})
+	})
+
+	jobService := NewContainerAppJobService(mockContext.SubscriptionCredentialProvider, mockContext.HttpClient)
+	err := jobService.UpdateImage(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "JOB_NAME", "UPDATED_IMAGE")
+	require.NoError(t, err)
+
+	// The other properties of the container are kept
+	require.Equal(t, map[string]any{
+		"properties": map[string]any{
+			"template": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "main", "image": "UPDATED_IMAGE", "resources": map[string]any{"cpu": 0.5}},
+				},
+			},
+		},
+	}, patch)
+}
+
+func Test_ContainerAppJob_GetStatus(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockJobGet(mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == jobPath+"/executions"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []any{
+				map[string]any{
+					"name":       "JOB_NAME-older",
+					"properties": map[string]any{"status": "Failed", "startTime": "2023-10-01T10:00:00Z"},
+				},
+				map[string]any{
+					"name":       "JOB_NAME-latest",
+					"properties": map[string]any{"status": "Succeeded", "startTime": "2023-10-01T10:05:00Z"},
+				},
+			},
+		})
+	})
+
+	jobService := NewContainerAppJobService(mockContext.SubscriptionCredentialProvider, mockContext.HttpClient)
+	status, err := jobService.GetStatus(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "JOB_NAME")
+	require.NoError(t, err)
+
+	require.Equal(t, "Schedule", status.TriggerType)
+	require.Equal(t, "*/5 * * * *", status.CronExpression)
+	require.Equal(t, "Succeeded", status.ProvisioningState)
+	require.Equal(t, "JOB_NAME-latest", status.LatestExecution.Name)
+	require.Equal(t, "Succeeded", status.LatestExecution.Status)
+}
+
+func Test_ContainerAppJob_GetLogs(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockJobGet(mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/managedEnvironments/ENVIRONMENT")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappcontainers.ManagedEnvironment{
+			Properties: &armappcontainers.ManagedEnvironmentProperties{
+				AppLogsConfiguration: &armappcontainers.AppLogsConfiguration{
+					LogAnalyticsConfiguration: &armappcontainers.LogAnalyticsConfiguration{
+						CustomerID: convert.RefOf("WORKSPACE_ID"),
+					},
+				},
+			},
+		})
+	})
+
+	var query map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && request.URL.Path == "/v1/workspaces/WORKSPACE_ID/query"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &query))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"tables": []any{
+				map[string]any{
+					"name": "PrimaryResult",
+					"rows": []any{
+						[]any{"2023-10-01T10:05:01.5Z", "JOB_NAME-latest-abc", "processing batch"},
+					},
+				},
+			},
+		})
+	})
+
+	jobService := NewContainerAppJobService(mockContext.SubscriptionCredentialProvider, mockContext.HttpClient)
+	logs, err := jobService.GetLogs(
+		*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "JOB_NAME", time.Hour)
+	require.NoError(t, err)
+
+	require.Equal(t, "PT3600S", query["timespan"])
+	require.Contains(t, query["query"], "ContainerJobName_s == 'JOB_NAME'")
+	require.Equal(t, []ContainerAppJobLog{
+		{
+			Timestamp: time.Date(2023, 10, 1, 10, 5, 1, 500000000, time.UTC),
+			Replica:   "JOB_NAME-latest-abc",
+			Message:   "processing batch",
+		},
+	}, logs)
+}
@@ -0,0 +1,217 @@
+package containerapps
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// The maximum number of recent lines returned by the log stream of a container
+const MaxLogTailLines = 300
+
+// ContainerAppLogsOptions selects the logs streamed from the replicas of a container app
+type ContainerAppLogsOptions struct {
+	// The number of recent lines of each container written first, up to MaxLogTailLines
+	TailLines int
+	// Whether new lines are written until the context is canceled
+	Follow bool
+}
+
+// StreamLogs writes the console logs of the containers of the replicas of the latest revision of the container app,
+// with the replica writing them. The logs of the replicas are streamed concurrently.
+func (cas *containerAppService) StreamLogs(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	options ContainerAppLogsOptions,
+	write func(replica string, line string) error,
+) error {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return err
+	}
+
+	if containerApp.Properties == nil || containerApp.Properties.LatestRevisionName == nil {
+		return fmt.Errorf("container app '%s' does not have any revision", appName)
+	}
+
+	revisionName := *containerApp.Properties.LatestRevisionName
+	replicas, err := cas.listReplicas(ctx, subscriptionId, resourceGroupName, appName, revisionName)
+	if err != nil {
+		return err
+	}
+
+	if len(replicas) == 0 {
+		return fmt.Errorf("revision '%s' of container app '%s' does not have any running replica", revisionName, appName)
+	}
+
+	token, err := cas.getLogsAuthToken(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return err
+	}
+
+	// The log streams are served by the regional endpoint of the environment of the app
+	location := strings.ToLower(strings.ReplaceAll(convert.ToValueWithDefault(containerApp.Location, ""), " ", ""))
+	revisionUrl := fmt.Sprintf(
+		"https://%s.azurecontainerapps.dev/subscriptions/%s/resourceGroups/%s/containerApps/%s/revisions/%s",
+		location,
+		url.PathEscape(subscriptionId),
+		url.PathEscape(resourceGroupName),
+		url.PathEscape(appName),
+		url.PathEscape(revisionName),
+	)
+
+	tailLines := options.TailLines
+	if tailLines > MaxLogTailLines {
+		tailLines = MaxLogTailLines
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var streamErr error
+
+	for _, replica := range replicas {
+		if replica.Name == nil || replica.Properties == nil {
+			continue
+		}
+
+		for _, container := range replica.Properties.Containers {
+			if container.Name == nil {
+				continue
+			}
+
+			replicaName := *replica.Name
+			streamUrl := fmt.Sprintf(
+				"%s/replicas/%s/containers/%s/logstream?output=text&tailLines=%d&follow=%t",
+				revisionUrl, url.PathEscape(replicaName), url.PathEscape(*container.Name), tailLines, options.Follow)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := cas.readLogStream(ctx, streamUrl, token, func(line string) error {
+					mu.Lock()
+					defer mu.Unlock()
+
+					return write(replicaName, line)
+				})
+
+				if err != nil {
+					mu.Lock()
+					defer mu.Unlock()
+
+					if streamErr == nil {
+						streamErr = fmt.Errorf("streaming logs of replica '%s': %w", replicaName, err)
+					}
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	return streamErr
+}
+
+func (cas *containerAppService) listReplicas(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revisionName string,
+) ([]*armappcontainers.Replica, error) {
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cas.httpClient, cas.userAgent).BuildArmClientOptions()
+	client, err := armappcontainers.NewContainerAppsRevisionReplicasClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerApps client: %w", err)
+	}
+
+	response, err := client.ListReplicas(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing replicas of revision '%s': %w", revisionName, err)
+	}
+
+	return response.Value, nil
+}
+
+func (cas *containerAppService) getLogsAuthToken(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (string, error) {
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cas.httpClient, cas.userAgent).BuildArmClientOptions()
+	client, err := azsdk.NewContainerAppLogsClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := client.GetAuthToken(ctx, resourceGroupName, appName)
+	if err != nil {
+		return "", fmt.Errorf("getting logs token of container app '%s': %w", appName, err)
+	}
+
+	return token, nil
+}
+
+// readLogStream writes the lines of the log stream at the url, authorized by the token of the container app
+func (cas *containerAppService) readLogStream(
+	ctx context.Context,
+	streamUrl string,
+	token string,
+	write func(line string) error,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", cas.userAgent)
+
+	res, err := cas.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		if err := write(scanner.Text()); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	return nil
+}
@@ -93,6 +93,10 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 				Containers: []*armappcontainers.Container{
 					{
 						Image: &updatedRevisionName,
+						Env: []*armappcontainers.EnvironmentVar{
+							{Name: convert.RefOf("PORT"), Value: convert.RefOf("8080")},
+							{Name: convert.RefOf("DB_CONNECTION"), SecretRef: convert.RefOf("secret")},
+						},
 					},
 				},
 			},
@@ -128,7 +132,10 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	)
 
 	cas := NewContainerAppService(mockContext.SubscriptionCredentialProvider, mockContext.HttpClient, clock.NewMock())
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName)
+	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, map[string]string{
+		"DB_CONNECTION": "Server=db",
+		"API_URL":       "https://api",
+	})
 	require.NoError(t, err)
 
 	// Verify lastest revision is read
@@ -149,4 +156,16 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, updatedImageName, *updatedContainerApp.Properties.Template.Containers[0].Image)
 	require.Equal(t, "azd-0", *updatedContainerApp.Properties.Template.RevisionSuffix)
+
+	// Verify the environment variables are merged with the ones of the revision
+	env := map[string]string{}
+	for _, envVar := range updatedContainerApp.Properties.Template.Containers[0].Env {
+		require.Nil(t, envVar.SecretRef)
+		env[*envVar.Name] = *envVar.Value
+	}
+	require.Equal(t, map[string]string{
+		"PORT":          "8080",
+		"DB_CONNECTION": "Server=db",
+		"API_URL":       "https://api",
+	}, env)
 }
@@ -0,0 +1,34 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+const (
+	CommandResultEventDataType EventDataType = "commandResult"
+)
+
+// CommandResult is the result of a command run with `--output events`, written as the last event of the command.
+// Warnings and Errors are always set, empty when the command didn't display warnings or succeeded.
+type CommandResult struct {
+	// The command, ex. azd provision
+	Command string                `json:"command"`
+	Success bool                  `json:"success"`
+	Message *CommandResultMessage `json:"message,omitempty"`
+	// The identifier of the command in telemetry and service logs
+	TraceId  string         `json:"traceId,omitempty"`
+	Warnings []string       `json:"warnings"`
+	Errors   []CommandError `json:"errors"`
+}
+
+// CommandResultMessage is the message displayed on a command completion
+type CommandResultMessage struct {
+	Header   string `json:"header"`
+	FollowUp string `json:"followUp,omitempty"`
+}
+
+// CommandError is an error failing a command
+type CommandError struct {
+	// The class of the error, ex. service.arm.429 or tool.docker.failed
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
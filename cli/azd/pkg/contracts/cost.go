@@ -0,0 +1,33 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+package contracts
+
+// CostReport is the contract for the output of `azd cost report`.
+type CostReport struct {
+	Currency string `json:"currency"`
+	// The cost of the current month so far
+	MonthToDate float64 `json:"monthToDate"`
+	// The forecasted cost of the current month
+	Forecast  float64        `json:"forecast"`
+	Services  []ServiceCost  `json:"services"`
+	Resources []ResourceCost `json:"resources"`
+}
+
+// ServiceCost is the cost of the resources hosting a service in the output of `azd cost report`.
+type ServiceCost struct {
+	Name        string  `json:"name"`
+	MonthToDate float64 `json:"monthToDate"`
+	Forecast    float64 `json:"forecast"`
+}
+
+// ResourceCost is the cost of a single resource in the output of `azd cost report`.
+type ResourceCost struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	ResourceGroup string `json:"resourceGroup"`
+	// The name of the service hosted by the resource, if any
+	Service     string  `json:"service,omitempty"`
+	MonthToDate float64 `json:"monthToDate"`
+	Forecast    float64 `json:"forecast"`
+}
@@ -0,0 +1,19 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+package contracts
+
+// DevBoxResult is the contract for the output of `azd devbox up` and `azd devbox connect`.
+type DevBoxResult struct {
+	Name       string `json:"name"`
+	Project    string `json:"project"`
+	Pool       string `json:"pool,omitempty"`
+	PowerState string `json:"powerState,omitempty"`
+	// Whether the dev box was created by the command
+	Created bool `json:"created"`
+	// The URL of the web client of the dev box
+	WebUrl string `json:"webUrl"`
+	// The URL launching the Remote Desktop client of the dev box
+	RdpConnectionUrl string `json:"rdpConnectionUrl,omitempty"`
+	// The number of customization tasks configuring a new dev box
+	CustomizationTasks int `json:"customizationTasks,omitempty"`
+}
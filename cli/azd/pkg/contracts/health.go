@@ -0,0 +1,27 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+package contracts
+
+// HealthResult is the contract for the output of `azd health`.
+type HealthResult struct {
+	// The overall health of the services. (ex. `Healthy`, `Unhealthy` or `Unknown`)
+	Status   string          `json:"status"`
+	Services []ServiceHealth `json:"services"`
+}
+
+// ServiceHealth is the health of a single service in the output of `azd health`.
+type ServiceHealth struct {
+	Name   string        `json:"name"`
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+	// The error that prevented evaluating the health of the service, if any
+	Error string `json:"error,omitempty"`
+}
+
+// HealthCheck is the result of a single health signal of a service, like a health endpoint or the replica status
+// reported by the platform.
+type HealthCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+import "strings"
+
+// The phases of the lifecycle events written with `--output events`. The type of an event is its subject followed by
+// its phase, ex. provision.start or service.deploy.progress.
+const (
+	LifecyclePhaseStart    = "start"
+	LifecyclePhaseProgress = "progress"
+	LifecyclePhaseDone     = "done"
+	LifecyclePhaseFailed   = "failed"
+	LifecyclePhaseSkipped  = "skipped"
+)
+
+// LifecycleEventDataType returns the type of the event of the subject and phase, ex. service.build.progress. The words
+// of the subject are joined with dots, ex. `env refresh` is env.refresh.
+func LifecycleEventDataType(subject string, phase string) EventDataType {
+	return EventDataType(strings.Join(append(strings.Fields(subject), phase), "."))
+}
+
+// LifecycleEvent is the data of the lifecycle events of the commands and services, written with `--output events`
+type LifecycleEvent struct {
+	// The service of the events of services, ex. service.deploy.done
+	Service string `json:"service,omitempty"`
+	// The progress message of progress events, or the message displayed once done
+	Message string `json:"message,omitempty"`
+	// The completion percentage of progress events, omitted when it isn't known
+	Percent int `json:"percent,omitempty"`
+	// The error of failed events
+	Error string `json:"error,omitempty"`
+	// The endpoints of the services deployed by service, in deploy.done
+	Endpoints map[string][]string `json:"endpoints,omitempty"`
+}
@@ -0,0 +1,31 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+package contracts
+
+import "time"
+
+// ServiceMetrics is the contract for the metrics of each service in the output of `azd metrics`.
+type ServiceMetrics struct {
+	Service string   `json:"service"`
+	Metrics []Metric `json:"metrics"`
+	// The error that prevented reading the metrics of the service, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Metric is a key platform metric of a service over the time window of `azd metrics`.
+type Metric struct {
+	Name string `json:"name"`
+	// The unit reported by Azure Monitor. (ex. `Count`, `Bytes`, `Seconds`)
+	Unit string `json:"unit"`
+	// The aggregation of the data points. (ex. `Average`, `Total`, `Maximum`)
+	Aggregation string `json:"aggregation"`
+	// The value of the metric over the whole time window, unset when no data was reported
+	Value  *float64      `json:"value,omitempty"`
+	Points []MetricPoint `json:"points"`
+}
+
+// MetricPoint is the value of a metric over an interval of the time window.
+type MetricPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     *float64  `json:"value"`
+}
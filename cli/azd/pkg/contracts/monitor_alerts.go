@@ -0,0 +1,12 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+package contracts
+
+// AlertRule is the contract for each alert rule in the output of `azd monitor alerts list`.
+type AlertRule struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	ResourceGroup string `json:"resourceGroup"`
+	Location      string `json:"location"`
+}
@@ -0,0 +1,29 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+const (
+	ProgressEventDataType EventDataType = "progress"
+)
+
+// The statuses of the steps reported by progress events
+const (
+	ProgressStatusRunning = "running"
+	ProgressStatusDone    = "done"
+	ProgressStatusFailed  = "failed"
+	ProgressStatusWarning = "warning"
+	ProgressStatusSkipped = "skipped"
+)
+
+// Progress is the progress of a step of a command run with `--output json`, written each time the step starts, reports
+// progress and stops.
+type Progress struct {
+	// The step, ex. Deploying service api
+	Title string `json:"title"`
+	// The latest progress message of the step, or its final message once stopped
+	Message string `json:"message,omitempty"`
+	// The completion percentage of the step, omitted when it isn't known
+	Percent int    `json:"percent,omitempty"`
+	Status  string `json:"status"`
+}
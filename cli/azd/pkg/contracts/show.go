@@ -2,6 +2,8 @@
 // Licensed under the MIT License.
 package contracts
 
+import "time"
+
 // ShowType are the values for the language property of a ShowServiceProject
 type ShowType string
 
@@ -10,21 +12,62 @@ const (
 	ShowTypePython ShowType = "python"
 	ShowTypeNode   ShowType = "node"
 	ShowTypeJava   ShowType = "java"
+	ShowTypeRust   ShowType = "rust"
 )
 
 // ShowResult is the contract for the output of `azd show`
 type ShowResult struct {
-	Name     string                 `json:"name"`
-	Services map[string]ShowService `json:"services"`
+	Name string `json:"name"`
+	// The directory of the project
+	Path string `json:"path,omitempty"`
+	// The infrastructure of the project
+	Infra *ShowInfra `json:"infra,omitempty"`
+	// The selected environment of the project
+	Environment *ShowEnvironment `json:"environment,omitempty"`
+	// The number of resources provisioned by type
+	Resources []ShowResourceSummary  `json:"resources,omitempty"`
+	Services  map[string]ShowService `json:"services"`
+}
+
+// ShowInfra is the contract for the infrastructure of the project returned by `azd show`
+type ShowInfra struct {
+	// The provisioning provider, ex. bicep or terraform
+	Provider string `json:"provider"`
+	// The directory of the infrastructure files, relative to the project
+	Path string `json:"path"`
+}
+
+// ShowEnvironment is the contract for the environment returned by `azd show`
+type ShowEnvironment struct {
+	Name             string `json:"name"`
+	SubscriptionId   string `json:"subscriptionId,omitempty"`
+	SubscriptionName string `json:"subscriptionName,omitempty"`
+	Location         string `json:"location,omitempty"`
+	ResourceGroup    string `json:"resourceGroup,omitempty"`
+}
+
+// ShowResourceSummary is the contract for the resources of a type provisioned in the environment
+type ShowResourceSummary struct {
+	// The resource type, ex. Microsoft.Web/sites
+	Type string `json:"type"`
+	// The display name of the resource type, when known, ex. Web App
+	DisplayName string `json:"displayName,omitempty"`
+	Count       int    `json:"count"`
 }
 
 // ShowService is the contract for a service returned by `azd show`
 type ShowService struct {
 	// Project contains information about the project that backs this service.
 	Project ShowServiceProject `json:"project"`
+	// The host of the service, ex. containerapp
+	Host string `json:"host,omitempty"`
 	// Target contains information about the resource that the service is deployed
 	// to.
 	Target *ShowTargetArm `json:"target,omitempty"`
+	// The endpoints of the service, as of its last deployment unless refreshed
+	Endpoints []string `json:"endpoints,omitempty"`
+	// When the service was last deployed successfully from this environment
+	LastDeployed *time.Time `json:"lastDeployed,omitempty"`
 }
 
 // ShowServiceProject is the contract for a service's project as returned by `azd show`
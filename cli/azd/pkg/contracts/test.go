@@ -0,0 +1,20 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+package contracts
+
+// TestResult is the contract for the output of `azd test`.
+type TestResult struct {
+	// Whether the tests of all services passed
+	Passed   bool                `json:"passed"`
+	Services []ServiceTestResult `json:"services"`
+}
+
+// ServiceTestResult is the outcome of the tests of a single service in the output of `azd test`.
+type ServiceTestResult struct {
+	Name            string  `json:"name"`
+	Passed          bool    `json:"passed"`
+	ExitCode        int     `json:"exitCode"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	// The error that prevented running the tests of the service, if any
+	Error string `json:"error,omitempty"`
+}
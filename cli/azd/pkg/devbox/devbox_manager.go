@@ -0,0 +1,247 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/benbjohnson/clock"
+)
+
+// The azd environment values configuring the dev box of the project
+const (
+	DevBoxPoolEnvVarName = "AZURE_DEVBOX_POOL"
+	DevBoxNameEnvVarName = "AZURE_DEVBOX_NAME"
+)
+
+// The directory of the dev box the repository of the project is cloned to
+const workspacesDirectory = `C:\Workspaces`
+
+// Config identifies the dev box of the project in a dev center project
+type Config struct {
+	// The dev center endpoint, ex. https://{tenantId}-{devCenterName}.{region}.devcenter.azure.com
+	Endpoint string
+	Project  string
+	Pool     string
+	Name     string
+}
+
+// Bootstrap describes the workspace prepared on a new dev box
+type Bootstrap struct {
+	RepositoryUrl  string
+	Branch         string
+	EnvName        string
+	SubscriptionId string
+	Location       string
+}
+
+// UpResult is the dev box brought up by Manager.Up
+type UpResult struct {
+	DevBox     *azsdk.DevBox
+	Connection *azsdk.DevBoxRemoteConnection
+	// Whether the dev box was created, new dev boxes are customized for the project
+	Created bool
+	// The customization group configuring a new dev box, nil when the dev box already existed
+	Customization *azsdk.DevBoxCustomizationGroup
+}
+
+// Manager creates & connects to the Microsoft Dev Boxes of a project
+type Manager struct {
+	azCli azcli.AzCli
+	clock clock.Clock
+}
+
+func NewManager(azCli azcli.AzCli, clock clock.Clock) *Manager {
+	return &Manager{
+		azCli: azCli,
+		clock: clock,
+	}
+}
+
+// Up creates the dev box, customizing it with the tasks, or starts the existing dev box when it is not running
+func (m *Manager) Up(
+	ctx context.Context,
+	subscriptionId string,
+	config Config,
+	tasks []azsdk.DevBoxCustomizationTask,
+) (*UpResult, error) {
+	result := &UpResult{}
+
+	devBox, err := m.azCli.GetDevBox(ctx, subscriptionId, config.Endpoint, config.Project, config.Name)
+	switch {
+	case errors.Is(err, azsdk.ErrDevBoxNotFound):
+		if config.Pool == "" {
+			return nil, fmt.Errorf("a pool is required to create the dev box '%s'", config.Name)
+		}
+
+		devBox, err = m.azCli.CreateDevBox(ctx, subscriptionId, config.Endpoint, config.Project, config.Name, config.Pool)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Created = true
+	case err != nil:
+		return nil, err
+	case devBox.PowerState != azsdk.DevBoxPowerStateRunning:
+		if err := m.azCli.StartDevBox(ctx, subscriptionId, config.Endpoint, config.Project, config.Name); err != nil {
+			return nil, err
+		}
+
+		devBox.PowerState = azsdk.DevBoxPowerStateRunning
+	}
+
+	result.DevBox = devBox
+
+	if result.Created && len(tasks) > 0 {
+		customization, err := m.azCli.CreateDevBoxCustomizationGroup(
+			ctx, subscriptionId, config.Endpoint, config.Project, config.Name, azsdk.DevBoxCustomizationGroup{
+				Name:  fmt.Sprintf("azd-%s", m.clock.Now().UTC().Format("20060102T150405")),
+				Tasks: tasks,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(customization.Tasks) == 0 {
+			customization.Tasks = tasks
+		}
+
+		result.Customization = customization
+	}
+
+	connection, err := m.Connect(ctx, subscriptionId, config)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Connection = connection
+	return result, nil
+}
+
+// Connect returns the URLs used to connect to the dev box
+func (m *Manager) Connect(
+	ctx context.Context,
+	subscriptionId string,
+	config Config,
+) (*azsdk.DevBoxRemoteConnection, error) {
+	return m.azCli.GetDevBoxRemoteConnection(ctx, subscriptionId, config.Endpoint, config.Project, config.Name)
+}
+
+// The winget packages of the tools used by the services of each language
+var languagePackages = map[project.ServiceLanguageKind][]string{
+	project.ServiceLanguageDotNet:     {"Microsoft.DotNet.SDK.7"},
+	project.ServiceLanguageCsharp:     {"Microsoft.DotNet.SDK.7"},
+	project.ServiceLanguageFsharp:     {"Microsoft.DotNet.SDK.7"},
+	project.ServiceLanguageJavaScript: {"OpenJS.NodeJS.LTS"},
+	project.ServiceLanguageTypeScript: {"OpenJS.NodeJS.LTS"},
+	project.ServiceLanguagePython:     {"Python.Python.3.11"},
+	project.ServiceLanguageJava:       {"Microsoft.OpenJDK.17", "Apache.Maven"},
+	project.ServiceLanguageRust:       {"Rustlang.Rustup"},
+	project.ServiceLanguageDocker:     {"Docker.DockerDesktop"},
+}
+
+// CustomizationTasks returns the tasks installing the tools required by the project on a dev box, along with azd,
+// then cloning the repository of the project and creating the azd environment in it
+func CustomizationTasks(projectConfig *project.ProjectConfig, bootstrap Bootstrap) []azsdk.DevBoxCustomizationTask {
+	packages := map[string]struct{}{
+		"Git.Git":          {},
+		"Microsoft.Azd":    {},
+		"Microsoft.VSCode": {},
+	}
+
+	for _, svc := range projectConfig.Services {
+		for _, pkg := range languagePackages[svc.Language] {
+			packages[pkg] = struct{}{}
+		}
+
+		if svc.Host.RequiresContainer() || svc.Docker.Path != "" {
+			packages["Docker.DockerDesktop"] = struct{}{}
+		}
+
+		if svc.Host == project.AksTarget {
+			packages["Kubernetes.kubectl"] = struct{}{}
+		}
+	}
+
+	if projectConfig.Infra.Provider == provisioning.Terraform {
+		packages["Hashicorp.Terraform"] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(packages))
+	for pkg := range packages {
+		sorted = append(sorted, pkg)
+	}
+	sort.Strings(sorted)
+
+	tasks := make([]azsdk.DevBoxCustomizationTask, 0, len(sorted)+2)
+	for _, pkg := range sorted {
+		tasks = append(tasks, azsdk.DevBoxCustomizationTask{
+			Name:        "winget",
+			DisplayName: fmt.Sprintf("Install %s", pkg),
+			Parameters:  map[string]string{"package": pkg},
+		})
+	}
+
+	if bootstrap.RepositoryUrl == "" {
+		return tasks
+	}
+
+	// git-clone clones the repository into a directory named after the repository
+	directory := fmt.Sprintf(`%s\%s`, workspacesDirectory, repositoryName(bootstrap.RepositoryUrl))
+	clone := azsdk.DevBoxCustomizationTask{
+		Name:        "git-clone",
+		DisplayName: fmt.Sprintf("Clone %s", bootstrap.RepositoryUrl),
+		Parameters: map[string]string{
+			"repositoryUrl": bootstrap.RepositoryUrl,
+			"directory":     workspacesDirectory,
+		},
+	}
+	if bootstrap.Branch != "" {
+		clone.Parameters["branch"] = bootstrap.Branch
+	}
+	tasks = append(tasks, clone)
+
+	if bootstrap.EnvName == "" {
+		return tasks
+	}
+
+	command := []string{"azd", "env", "new", bootstrap.EnvName}
+	if bootstrap.SubscriptionId != "" {
+		command = append(command, "--subscription", bootstrap.SubscriptionId)
+	}
+	if bootstrap.Location != "" {
+		command = append(command, "--location", bootstrap.Location)
+	}
+
+	tasks = append(tasks, azsdk.DevBoxCustomizationTask{
+		Name:        "powershell",
+		DisplayName: fmt.Sprintf("Create azd environment %s", bootstrap.EnvName),
+		Parameters: map[string]string{
+			"command":          strings.Join(command, " "),
+			"workingDirectory": directory,
+		},
+		RunAs: "User",
+	})
+
+	return tasks
+}
+
+// repositoryName returns the name of the repository of the url, ex. todo-nodejs-mongo for
+// https://github.com/Azure-Samples/todo-nodejs-mongo.git
+func repositoryName(repositoryUrl string) string {
+	name := strings.TrimSuffix(strings.TrimRight(repositoryUrl, "/"), ".git")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
@@ -0,0 +1,180 @@
+package devbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+const testDevBoxPath = "/projects/platform/users/me/devboxes/todo-dev"
+
+var testConfig = Config{
+	Endpoint: "https://tenant-devcenter.eastus.devcenter.azure.com",
+	Project:  "platform",
+	Pool:     "win11",
+	Name:     "todo-dev",
+}
+
+func TestDevBoxUp(t *testing.T) {
+	tasks := []azsdk.DevBoxCustomizationTask{
+		{Name: "winget", Parameters: map[string]string{"package": "Microsoft.Azd"}},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareRemoteConnectionMock(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Path == testDevBoxPath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && request.URL.Path == testDevBoxPath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			var devBox azsdk.DevBox
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &devBox))
+			require.Equal(t, "win11", devBox.PoolName)
+
+			devBox.Name = "todo-dev"
+			devBox.PowerState = azsdk.DevBoxPowerStateRunning
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, devBox)
+		})
+
+		var customizationPath string
+		var customization azsdk.DevBoxCustomizationGroup
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && request.URL.Path != testDevBoxPath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			customizationPath = request.URL.Path
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &customization))
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, customization)
+		})
+
+		manager := NewManager(mockazcli.NewAzCliFromMockContext(mockContext), newTestClock())
+		result, err := manager.Up(*mockContext.Context, "SUBSCRIPTION_ID", testConfig, tasks)
+		require.NoError(t, err)
+
+		require.True(t, result.Created)
+		require.Equal(t, testDevBoxPath+"/customizationGroups/azd-20231001T120000", customizationPath)
+		require.Equal(t, tasks, customization.Tasks)
+		require.Equal(t, "https://devbox.microsoft.com/connect?devbox=todo-dev", result.Connection.WebUrl)
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareRemoteConnectionMock(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Path == testDevBoxPath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DevBox{
+				Name:       "todo-dev",
+				PoolName:   "win11",
+				PowerState: "Deallocated",
+			})
+		})
+
+		started := false
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && request.URL.Path == testDevBoxPath+":start"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			started = true
+			return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+		})
+
+		manager := NewManager(mockazcli.NewAzCliFromMockContext(mockContext), newTestClock())
+		result, err := manager.Up(*mockContext.Context, "SUBSCRIPTION_ID", testConfig, tasks)
+		require.NoError(t, err)
+
+		require.True(t, started)
+		require.False(t, result.Created)
+		require.Nil(t, result.Customization)
+		require.Equal(t, azsdk.DevBoxPowerStateRunning, result.DevBox.PowerState)
+	})
+}
+
+func TestCustomizationTasks(t *testing.T) {
+	projectConfig := &project.ProjectConfig{
+		Name:  "todo",
+		Infra: provisioning.Options{Provider: provisioning.Terraform},
+		Services: map[string]*project.ServiceConfig{
+			"api": {Language: project.ServiceLanguagePython, Host: project.ContainerAppTarget},
+			"web": {Language: project.ServiceLanguageTypeScript, Host: project.StaticWebAppTarget},
+		},
+	}
+
+	tasks := CustomizationTasks(projectConfig, Bootstrap{
+		RepositoryUrl:  "https://github.com/Azure-Samples/todo-python-mongo.git",
+		Branch:         "main",
+		EnvName:        "dev",
+		SubscriptionId: "SUBSCRIPTION_ID",
+		Location:       "eastus2",
+	})
+
+	packages := []string{}
+	for _, task := range tasks[:len(tasks)-2] {
+		require.Equal(t, "winget", task.Name)
+		packages = append(packages, task.Parameters["package"])
+	}
+
+	require.Equal(t, []string{
+		"Docker.DockerDesktop",
+		"Git.Git",
+		"Hashicorp.Terraform",
+		"Microsoft.Azd",
+		"Microsoft.VSCode",
+		"OpenJS.NodeJS.LTS",
+		"Python.Python.3.11",
+	}, packages)
+
+	clone := tasks[len(tasks)-2]
+	require.Equal(t, "git-clone", clone.Name)
+	require.Equal(t, "https://github.com/Azure-Samples/todo-python-mongo.git", clone.Parameters["repositoryUrl"])
+	require.Equal(t, "main", clone.Parameters["branch"])
+
+	bootstrap := tasks[len(tasks)-1]
+	require.Equal(t, "powershell", bootstrap.Name)
+	require.Equal(t, "azd env new dev --subscription SUBSCRIPTION_ID --location eastus2", bootstrap.Parameters["command"])
+	require.Equal(t, `C:\Workspaces\todo-python-mongo`, bootstrap.Parameters["workingDirectory"])
+
+	t.Run("NoRepository", func(t *testing.T) {
+		tasks := CustomizationTasks(projectConfig, Bootstrap{EnvName: "dev"})
+		for _, task := range tasks {
+			require.Equal(t, "winget", task.Name)
+		}
+	})
+}
+
+func prepareRemoteConnectionMock(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == testDevBoxPath+"/remoteConnection"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DevBoxRemoteConnection{
+			WebUrl: "https://devbox.microsoft.com/connect?devbox=todo-dev",
+		})
+	})
+}
+
+func newTestClock() clock.Clock {
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC))
+	return mockClock
+}
@@ -0,0 +1,206 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package devcontainer generates the development container configuration of a project, used by GitHub Codespaces and
+// the Dev Containers extension of VS Code.
+package devcontainer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// The location of the configuration, relative to the project directory
+var ConfigPath = filepath.Join(".devcontainer", "devcontainer.json")
+
+// ErrConfigExists is returned when the project already has a devcontainer.json and it is not overwritten
+var ErrConfigExists = errors.New("the project already has a devcontainer.json")
+
+const baseImage = "mcr.microsoft.com/devcontainers/base:bullseye"
+
+// The feature installing azd, always part of the configuration
+const azdFeature = "ghcr.io/azure/azure-dev/azd:latest"
+
+// Config is a devcontainer.json, see https://containers.dev/implementors/json_reference
+type Config struct {
+	Name           string                    `json:"name"`
+	Image          string                    `json:"image"`
+	Features       map[string]map[string]any `json:"features"`
+	Customizations Customizations            `json:"customizations"`
+	// Runs once the container is created, ex. to restore the dependencies of the services
+	PostCreateCommand string `json:"postCreateCommand,omitempty"`
+}
+
+// Customizations configures the tools connecting to the container
+type Customizations struct {
+	VSCode VSCodeCustomizations `json:"vscode"`
+}
+
+// VSCodeCustomizations configures VS Code connected to the container
+type VSCodeCustomizations struct {
+	Extensions []string `json:"extensions"`
+}
+
+type tool struct {
+	// The feature installing the tool with its options, https://containers.dev/features
+	feature string
+	options map[string]any
+	// The VS Code extensions of the tool
+	extensions []string
+}
+
+var (
+	dotnetTool = tool{
+		feature:    "ghcr.io/devcontainers/features/dotnet:2",
+		options:    map[string]any{"version": "7.0"},
+		extensions: []string{"ms-dotnettools.csharp"},
+	}
+	nodeTool = tool{
+		feature:    "ghcr.io/devcontainers/features/node:1",
+		options:    map[string]any{"version": "18"},
+		extensions: []string{"dbaeumer.vscode-eslint"},
+	}
+	pythonTool = tool{
+		feature:    "ghcr.io/devcontainers/features/python:1",
+		options:    map[string]any{"version": "3.11"},
+		extensions: []string{"ms-python.python"},
+	}
+	javaTool = tool{
+		feature:    "ghcr.io/devcontainers/features/java:1",
+		options:    map[string]any{"version": "17", "installMaven": "true", "installGradle": "true"},
+		extensions: []string{"vscjava.vscode-java-pack"},
+	}
+	rustTool = tool{
+		feature:    "ghcr.io/devcontainers/features/rust:1",
+		options:    map[string]any{},
+		extensions: []string{"rust-lang.rust-analyzer"},
+	}
+	dockerTool = tool{
+		feature:    "ghcr.io/devcontainers/features/docker-in-docker:2",
+		options:    map[string]any{},
+		extensions: []string{"ms-azuretools.vscode-docker"},
+	}
+	kubectlTool = tool{
+		feature: "ghcr.io/devcontainers/features/kubectl-helm-minikube:1",
+		options: map[string]any{"minikube": "none"},
+	}
+	terraformTool = tool{
+		feature:    "ghcr.io/devcontainers/features/terraform:1",
+		options:    map[string]any{},
+		extensions: []string{"hashicorp.terraform"},
+	}
+	pulumiTool = tool{
+		feature: "ghcr.io/devcontainers-contrib/features/pulumi:1",
+		options: map[string]any{},
+	}
+)
+
+// The tools used by the services of each language
+var languageTools = map[project.ServiceLanguageKind][]tool{
+	project.ServiceLanguageDotNet:     {dotnetTool},
+	project.ServiceLanguageCsharp:     {dotnetTool},
+	project.ServiceLanguageFsharp:     {dotnetTool},
+	project.ServiceLanguageJavaScript: {nodeTool},
+	project.ServiceLanguageTypeScript: {nodeTool},
+	project.ServiceLanguagePython:     {pythonTool},
+	project.ServiceLanguageJava:       {javaTool},
+	project.ServiceLanguageRust:       {rustTool},
+	project.ServiceLanguageDocker:     {dockerTool},
+}
+
+// Generate returns the configuration of a development container with azd and the tools required by the services &
+// infrastructure of the project
+func Generate(projectConfig *project.ProjectConfig) *Config {
+	tools := []tool{}
+	for _, svc := range projectConfig.Services {
+		tools = append(tools, languageTools[svc.Language]...)
+
+		if svc.Host.RequiresContainer() || svc.Docker.Path != "" {
+			tools = append(tools, dockerTool)
+		}
+
+		if svc.Host == project.AksTarget {
+			tools = append(tools, kubectlTool)
+		}
+
+		// The Static Web Apps CLI runs on node
+		if svc.Host == project.StaticWebAppTarget {
+			tools = append(tools, nodeTool)
+		}
+	}
+
+	extensions := map[string]struct{}{
+		"ms-azuretools.azure-dev": {},
+	}
+
+	switch projectConfig.Infra.Provider {
+	case provisioning.Terraform:
+		tools = append(tools, terraformTool)
+	case provisioning.Pulumi:
+		tools = append(tools, pulumiTool)
+	case provisioning.Bicep, "":
+		extensions["ms-azuretools.vscode-bicep"] = struct{}{}
+	}
+
+	config := &Config{
+		Name:  projectConfig.Name,
+		Image: baseImage,
+		Features: map[string]map[string]any{
+			azdFeature: {},
+		},
+	}
+
+	for _, tool := range tools {
+		config.Features[tool.feature] = tool.options
+		for _, extension := range tool.extensions {
+			extensions[extension] = struct{}{}
+		}
+	}
+
+	config.Customizations.VSCode.Extensions = make([]string, 0, len(extensions))
+	for extension := range extensions {
+		config.Customizations.VSCode.Extensions = append(config.Customizations.VSCode.Extensions, extension)
+	}
+	sort.Strings(config.Customizations.VSCode.Extensions)
+
+	if len(projectConfig.Services) > 0 {
+		config.PostCreateCommand = "azd restore --all"
+	}
+
+	return config
+}
+
+// Write writes the configuration to the project directory, returning the path of the configuration. ErrConfigExists
+// is returned when the project already has a configuration, unless force is set.
+func Write(projectDir string, config *Config, force bool) (string, error) {
+	path := filepath.Join(projectDir, ConfigPath)
+
+	if _, err := os.Stat(path); err == nil && !force {
+		return path, ErrConfigExists
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return path, err
+	}
+
+	contents, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return path, fmt.Errorf("marshalling devcontainer.json: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return path, fmt.Errorf("creating .devcontainer directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(contents, '\n'), osutil.PermissionFile); err != nil {
+		return path, fmt.Errorf("writing devcontainer.json: %w", err)
+	}
+
+	return path, nil
+}
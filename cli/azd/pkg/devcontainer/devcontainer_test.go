@@ -0,0 +1,66 @@
+package devcontainer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	projectConfig := &project.ProjectConfig{
+		Name: "todo",
+		Services: map[string]*project.ServiceConfig{
+			"api": {Name: "api", Language: project.ServiceLanguagePython, Host: project.ContainerAppTarget},
+			"web": {Name: "web", Language: project.ServiceLanguageTypeScript, Host: project.StaticWebAppTarget},
+		},
+		Infra: provisioning.Options{Provider: provisioning.Terraform},
+	}
+
+	config := Generate(projectConfig)
+
+	require.Equal(t, "todo", config.Name)
+	require.Equal(t, map[string]map[string]any{
+		azdFeature:            {},
+		pythonTool.feature:    pythonTool.options,
+		nodeTool.feature:      nodeTool.options,
+		dockerTool.feature:    dockerTool.options,
+		terraformTool.feature: terraformTool.options,
+	}, config.Features)
+	require.Equal(t, []string{
+		"dbaeumer.vscode-eslint",
+		"hashicorp.terraform",
+		"ms-azuretools.azure-dev",
+		"ms-azuretools.vscode-docker",
+		"ms-python.python",
+	}, config.Customizations.VSCode.Extensions)
+	require.Equal(t, "azd restore --all", config.PostCreateCommand)
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	config := Generate(&project.ProjectConfig{Name: "minimal"})
+
+	path, err := Write(dir, config, false)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, ".devcontainer", "devcontainer.json"), path)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	written := &Config{}
+	require.NoError(t, json.Unmarshal(contents, written))
+	require.Equal(t, baseImage, written.Image)
+	require.Contains(t, written.Features, azdFeature)
+	require.Contains(t, written.Customizations.VSCode.Extensions, "ms-azuretools.vscode-bicep")
+
+	_, err = Write(dir, config, false)
+	require.ErrorIs(t, err, ErrConfigExists)
+
+	_, err = Write(dir, config, true)
+	require.NoError(t, err)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package doctor runs the checks of `azd doctor`, diagnosing the machine, the configuration and the project azd
+// runs with. Checks are pluggable: service targets contribute checks of their own by implementing
+// project.DoctorChecker.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// The status of a result
+const (
+	StatusPassed  = "passed"
+	StatusWarning = "warning"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Result is the outcome of validating one item of a check, ex. one of the tools required by the project
+type Result struct {
+	// The name of the check the result belongs to, ex. tools
+	Check string `json:"check"`
+	// The item validated, ex. docker
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Check validates a part of the environment azd runs in
+type Check interface {
+	// The name of the check, ex. auth
+	Name() string
+	// Run validates the part of the environment, returning one result per item validated
+	Run(ctx context.Context) []Result
+}
+
+type checkFunc struct {
+	name string
+	run  func(ctx context.Context) []Result
+}
+
+func (c *checkFunc) Name() string {
+	return c.name
+}
+
+func (c *checkFunc) Run(ctx context.Context) []Result {
+	return c.run(ctx)
+}
+
+// NewCheck creates a check running the function
+func NewCheck(name string, run func(ctx context.Context) []Result) Check {
+	return &checkFunc{name: name, run: run}
+}
+
+// Report is the machine readable report of `azd doctor --output json`
+type Report struct {
+	// Whether no result failed. Warnings don't fail the report.
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+// Failures returns the failed results of the report
+func (r *Report) Failures() []Result {
+	failures := []Result{}
+	for _, result := range r.Results {
+		if result.Status == StatusFailed {
+			failures = append(failures, result)
+		}
+	}
+
+	return failures
+}
+
+// Run runs the checks in order. The check name of the results is set to the name of the check producing them and
+// checks that panic are reported as failed instead of failing the other checks.
+func Run(ctx context.Context, checks []Check) *Report {
+	report := &Report{Passed: true, Results: []Result{}}
+	for _, check := range checks {
+		for _, result := range runCheck(ctx, check) {
+			result.Check = check.Name()
+			if result.Status == StatusFailed {
+				report.Passed = false
+			}
+
+			report.Results = append(report.Results, result)
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) (results []Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("doctor check '%s' panicked: %v", check.Name(), r)
+			results = []Result{{
+				Name:    check.Name(),
+				Status:  StatusFailed,
+				Message: fmt.Sprintf("the check failed unexpectedly: %v", r),
+			}}
+		}
+	}()
+
+	return check.Run(ctx)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package doctor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Passed", func(t *testing.T) {
+		report := Run(context.Background(), []Check{
+			NewCheck("tools", func(ctx context.Context) []Result {
+				return []Result{
+					{Name: "docker", Status: StatusPassed},
+					{Name: "kubectl", Status: StatusWarning},
+				}
+			}),
+		})
+
+		require.True(t, report.Passed)
+		require.Empty(t, report.Failures())
+		require.Equal(t, []Result{
+			{Check: "tools", Name: "docker", Status: StatusPassed},
+			{Check: "tools", Name: "kubectl", Status: StatusWarning},
+		}, report.Results)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		report := Run(context.Background(), []Check{
+			NewCheck("auth", func(ctx context.Context) []Result {
+				return []Result{{Name: "token", Status: StatusFailed, Message: "not logged in"}}
+			}),
+			NewCheck("network", func(ctx context.Context) []Result {
+				return []Result{{Name: "arm", Status: StatusPassed}}
+			}),
+		})
+
+		require.False(t, report.Passed)
+		require.Len(t, report.Results, 2)
+		require.Equal(t, []Result{
+			{Check: "auth", Name: "token", Status: StatusFailed, Message: "not logged in"},
+		}, report.Failures())
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		report := Run(context.Background(), []Check{
+			NewCheck("broken", func(ctx context.Context) []Result {
+				panic("boom")
+			}),
+			NewCheck("config", func(ctx context.Context) []Result {
+				return []Result{{Name: "user config", Status: StatusPassed}}
+			}),
+		})
+
+		require.False(t, report.Passed)
+		require.Len(t, report.Results, 2)
+		require.Equal(t, "broken", report.Results[0].Check)
+		require.Equal(t, StatusFailed, report.Results[0].Status)
+		require.Contains(t, report.Results[0].Message, "boom")
+		require.Equal(t, StatusPassed, report.Results[1].Status)
+	})
+}
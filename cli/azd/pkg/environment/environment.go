@@ -10,12 +10,14 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/workspace"
 	"github.com/joho/godotenv"
 	"golang.org/x/exp/maps"
 )
@@ -42,15 +44,30 @@ const ContainerRegistryEndpointEnvVarName = "AZURE_CONTAINER_REGISTRY_ENDPOINT"
 // AksClusterEnvVarName is the name of they key used to store the endpoint of the AKS cluster to push to.
 const AksClusterEnvVarName = "AZURE_AKS_CLUSTER_NAME"
 
+// KeyVaultNameEnvVarName is the name of the key used to store the name of the Key Vault of the environment, the default
+// vault of `azd env set-secret`.
+const KeyVaultNameEnvVarName = "AZURE_KEY_VAULT_NAME"
+
 // ResourceGroupEnvVarName is the name of the azure resource group that should be used for deployments
 const ResourceGroupEnvVarName = "AZURE_RESOURCE_GROUP"
 
 // The zero value of an Environment is not valid. Use [FromRoot] or [EmptyWithRoot] to create one. When writing tests,
 // [Ephemeral] and [EphemeralWithValues] are useful to create environments which are not persisted to disk.
 type Environment struct {
+	// mu guards dotenv, deletedKeys & secrets since services are deployed concurrently
+	mu sync.RWMutex
+
 	// dotenv is a map of keys to values, persisted to the `.env` file stored in this environment's [Root].
 	dotenv map[string]string
 
+	// secrets are the resolved values of the keys of the `.env` file referencing secrets. They are kept in memory
+	// only, so the values of secrets are never written to disk.
+	secrets map[string]string
+
+	// inherited are the values of the shared environment of the workspace of the project, returned when the key isn't
+	// set in the `.env` file. They are never persisted by Save.
+	inherited map[string]string
+
 	// deletedKeys keeps track of deleted keys from the `.env` to be reapplied before a merge operation
 	// happens in Save
 	deletedKeys map[string]struct{}
@@ -119,8 +136,47 @@ func FromRoot(root string) (*Environment, error) {
 	return env, nil
 }
 
+// GetEnvironment loads the environment of the name of the project. When the project is part of a workspace sharing
+// environments, the environment inherits the values of the shared environment of the same name.
 func GetEnvironment(azdContext *azdcontext.AzdContext, name string) (*Environment, error) {
-	return FromRoot(azdContext.EnvironmentRoot(name))
+	env, err := FromRoot(azdContext.EnvironmentRoot(name))
+
+	if inheritErr := env.inheritWorkspace(azdContext, name); inheritErr != nil {
+		return env, inheritErr
+	}
+
+	return env, err
+}
+
+// inheritWorkspace sets the inherited values of the environment from the shared environment of the workspace
+func (e *Environment) inheritWorkspace(azdContext *azdcontext.AzdContext, name string) error {
+	ws, err := workspace.Find(azdContext.ProjectDirectory())
+	if errors.Is(err, workspace.ErrNoWorkspace) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	sharedRoot := ws.SharedEnvironmentRoot(azdContext.ProjectDirectory(), name)
+	if sharedRoot == "" {
+		return nil
+	}
+
+	values, err := godotenv.Read(filepath.Join(sharedRoot, azdcontext.DotEnvFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("loading shared environment '%s': %w", name, err)
+	}
+
+	// The name of an environment is never inherited
+	delete(values, EnvNameEnvVarName)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.inherited = values
+	return nil
 }
 
 // EmptyWithRoot returns an empty environment, which will be persisted
@@ -162,7 +218,9 @@ func EphemeralWithValues(name string, values map[string]string) *Environment {
 // Getenv behaves like os.Getenv, except that any keys in the `.env` file associated with this environment are considered
 // first.
 func (e *Environment) Getenv(key string) string {
-	if v, has := e.dotenv[key]; has {
+	v, has := e.lookup(key)
+
+	if has {
 		return v
 	}
 
@@ -172,34 +230,96 @@ func (e *Environment) Getenv(key string) string {
 // LookupEnv behaves like os.LookupEnv, except that any keys in the `.env` file associated with this environment are
 // considered first.
 func (e *Environment) LookupEnv(key string) (string, bool) {
-	if v, has := e.dotenv[key]; has {
+	v, has := e.lookup(key)
+
+	if has {
 		return v, true
 	}
 
 	return os.LookupEnv(key)
 }
 
+// lookup returns the value of the key in the `.env` file, or the resolved value of the secret it references, or the
+// value inherited from the shared environment of the workspace
+func (e *Environment) lookup(key string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if v, has := e.secrets[key]; has {
+		return v, true
+	}
+
+	if v, has := e.dotenv[key]; has {
+		return v, true
+	}
+
+	v, has := e.inherited[key]
+	return v, has
+}
+
 // DotenvDelete removes the given key from the .env file in the environment, it is a no-op if the key
 // does not exist. [Save] should be called to ensure this change is persisted.
 func (e *Environment) DotenvDelete(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	delete(e.dotenv, key)
+	delete(e.secrets, key)
 	e.deletedKeys[key] = struct{}{}
 }
 
-// Dotenv returns a copy of the key value pairs from the .env file in the environment.
+// Dotenv returns a copy of the key value pairs from the .env file in the environment. Keys referencing secrets have
+// the reference as value, not the value of the secret.
 func (e *Environment) Dotenv() map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	return maps.Clone(e.dotenv)
 }
 
 // DotenvSet sets the value of [key] to [value] in the .env file associated with the environment. [Save] should be
 // called to ensure this change is persisted.
 func (e *Environment) DotenvSet(key string, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	e.dotenv[key] = value
+	delete(e.secrets, key)
 	delete(e.deletedKeys, key)
 }
 
+// SetSecret sets the resolved value of the secret referenced by [key]. The value is returned in place of the
+// reference by [Getenv], [LookupEnv] and [Environ] but is never persisted by [Save]. Setting or deleting the key drops
+// the value.
+func (e *Environment) SetSecret(key string, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.secrets == nil {
+		e.secrets = map[string]string{}
+	}
+
+	e.secrets[key] = value
+}
+
+// HasSecret returns whether the value of the secret referenced by [key] was resolved
+func (e *Environment) HasSecret(key string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, has := e.secrets[key]
+	return has
+}
+
 // Reloads environment variables and configuration
 func (e *Environment) Reload() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.reload()
+}
+
+func (e *Environment) reload() error {
 	// Reload env values
 	envPath := filepath.Join(e.Root, azdcontext.DotEnvFileName)
 	if envMap, err := godotenv.Read(envPath); errors.Is(err, os.ErrNotExist) {
@@ -223,12 +343,13 @@ func (e *Environment) Reload() error {
 		e.Config = cfg
 	}
 
-	if e.GetEnvName() != "" {
-		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, e.GetEnvName()))
+	// The lock is held by the caller so values are read from the map directly
+	if envName := e.dotenv[EnvNameEnvVarName]; envName != "" {
+		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, envName))
 	}
 
-	if e.GetSubscriptionId() != "" {
-		tracing.SetGlobalAttributes(fields.SubscriptionIdKey.String(e.GetSubscriptionId()))
+	if subscriptionId := e.dotenv[SubscriptionIdEnvVarName]; subscriptionId != "" {
+		tracing.SetGlobalAttributes(fields.SubscriptionIdKey.String(subscriptionId))
 	}
 
 	return nil
@@ -241,6 +362,9 @@ func (e *Environment) Save() error {
 		return nil
 	}
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	// Update configuration
 	cfgMgr := config.NewManager()
 	if err := cfgMgr.Save(e.Config, filepath.Join(e.Root, azdcontext.ConfigFileName)); err != nil {
@@ -250,7 +374,7 @@ func (e *Environment) Save() error {
 	// Cache current values & reload to get any new env vars
 	currentValues := e.dotenv
 	deletedValues := e.deletedKeys
-	if err := e.Reload(); err != nil {
+	if err := e.reload(); err != nil {
 		return fmt.Errorf("failed reloading env vars, %w", err)
 	}
 
@@ -274,7 +398,7 @@ func (e *Environment) Save() error {
 		return fmt.Errorf("saving .env: %w", err)
 	}
 
-	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, e.GetEnvName()))
+	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, e.dotenv[EnvNameEnvVarName]))
 	return nil
 }
 
@@ -317,23 +441,41 @@ func normalize(key string) string {
 	return strings.ReplaceAll(strings.ToUpper(key), "-", "_")
 }
 
+// ServicePropertyKey returns the name of a service-namespaced property, SERVICE_$SERVICE_NAME_$PROPERTY_NAME
+func ServicePropertyKey(serviceName string, propertyName string) string {
+	return fmt.Sprintf("SERVICE_%s_%s", normalize(serviceName), propertyName)
+}
+
 // GetServiceProperty is shorthand for Getenv(SERVICE_$SERVICE_NAME_$PROPERTY_NAME)
 func (e *Environment) GetServiceProperty(serviceName string, propertyName string) string {
-	return e.Getenv(fmt.Sprintf("SERVICE_%s_%s", normalize(serviceName), propertyName))
+	return e.Getenv(ServicePropertyKey(serviceName, propertyName))
 }
 
 // Sets the value of a service-namespaced property in the environment.
 func (e *Environment) SetServiceProperty(serviceName string, propertyName string, value string) {
-	e.DotenvSet(fmt.Sprintf("SERVICE_%s_%s", normalize(serviceName), propertyName), value)
+	e.DotenvSet(ServicePropertyKey(serviceName, propertyName), value)
 }
 
 // Creates a slice of key value pairs, based on the entries in the `.env` file like `KEY=VALUE` that
 // can be used to pass into command runner or similar constructs.
 func (e *Environment) Environ() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	envVars := []string{}
 	for k, v := range e.dotenv {
+		if secret, has := e.secrets[k]; has {
+			v = secret
+		}
+
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
 	}
 
+	for k, v := range e.inherited {
+		if _, has := e.dotenv[k]; !has {
+			envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+
 	return envVars
 }
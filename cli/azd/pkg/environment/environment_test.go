@@ -172,6 +172,79 @@ func Test_SaveAndReload(t *testing.T) {
 	require.Equal(t, "http://api.example.com/updated", value)
 }
 
+func Test_SecretsAreNotSaved(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	env := EmptyWithRoot(tempDir)
+	env.DotenvSet("DB_PASSWORD", "akvs://kv-todo/db-password")
+	env.SetSecret("DB_PASSWORD", "s3cr3t")
+
+	require.Equal(t, "s3cr3t", env.Getenv("DB_PASSWORD"))
+	require.Contains(t, env.Environ(), "DB_PASSWORD=s3cr3t")
+	require.Equal(t, "akvs://kv-todo/db-password", env.Dotenv()["DB_PASSWORD"])
+
+	err := env.Save()
+	require.NoError(t, err)
+
+	envMap, err := godotenv.Read(filepath.Join(tempDir, azdcontext.DotEnvFileName))
+	require.NoError(t, err)
+	require.Equal(t, "akvs://kv-todo/db-password", envMap["DB_PASSWORD"])
+
+	// The resolved value still applies after saving, until the key is set again
+	require.Equal(t, "s3cr3t", env.Getenv("DB_PASSWORD"))
+	env.DotenvSet("DB_PASSWORD", "plain")
+	require.Equal(t, "plain", env.Getenv("DB_PASSWORD"))
+	require.False(t, env.HasSecret("DB_PASSWORD"))
+}
+
+func Test_GetEnvironment_InheritsWorkspace(t *testing.T) {
+	root := t.TempDir()
+	err := os.WriteFile(
+		filepath.Join(root, "azure.workspace.yaml"), []byte("sharedEnvironment: platform\n"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	shared := azdcontext.NewAzdContextWithDirectory(filepath.Join(root, "platform"))
+	sharedEnv := EmptyWithRoot(shared.EnvironmentRoot("dev"))
+	sharedEnv.SetEnvName("dev")
+	sharedEnv.SetSubscriptionId("SUBSCRIPTION_ID")
+	sharedEnv.DotenvSet("AZURE_CONTAINER_REGISTRY_ENDPOINT", "crplatform.azurecr.io")
+	sharedEnv.DotenvSet("LOG_LEVEL", "info")
+	require.NoError(t, sharedEnv.Save())
+
+	azdCtx := azdcontext.NewAzdContextWithDirectory(filepath.Join(root, "apps", "web"))
+	env := EmptyWithRoot(azdCtx.EnvironmentRoot("dev"))
+	env.SetEnvName("dev")
+	env.DotenvSet("LOG_LEVEL", "debug")
+	require.NoError(t, env.Save())
+
+	env, err = GetEnvironment(azdCtx, "dev")
+	require.NoError(t, err)
+	require.Equal(t, "SUBSCRIPTION_ID", env.GetSubscriptionId())
+	require.Equal(t, "debug", env.Getenv("LOG_LEVEL"))
+	require.ElementsMatch(t, []string{
+		"AZURE_ENV_NAME=dev",
+		"LOG_LEVEL=debug",
+		"AZURE_SUBSCRIPTION_ID=SUBSCRIPTION_ID",
+		"AZURE_CONTAINER_REGISTRY_ENDPOINT=crplatform.azurecr.io",
+	}, env.Environ())
+
+	// The inherited values aren't saved in the environment of the project
+	require.NoError(t, env.Save())
+	envMap, err := godotenv.Read(filepath.Join(azdCtx.EnvironmentRoot("dev"), azdcontext.DotEnvFileName))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"AZURE_ENV_NAME": "dev", "LOG_LEVEL": "debug"}, envMap)
+
+	// The environments of the shared project and of the other names don't inherit values
+	sharedEnv, err = GetEnvironment(shared, "dev")
+	require.NoError(t, err)
+	require.Equal(t, "info", sharedEnv.Getenv("LOG_LEVEL"))
+
+	env, err = GetEnvironment(azdCtx, "prod")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Empty(t, env.GetSubscriptionId())
+}
+
 func TestCleanName(t *testing.T) {
 	require.Equal(t, "already-clean-name", CleanName("already-clean-name"))
 	require.Equal(t, "was-CLEANED-with--bad--things-(123)", CleanName("was CLEANED with *bad* things (123)"))
@@ -34,6 +34,11 @@ func (o *CmdTree) Start() error {
 	return o.Cmd.Start()
 }
 
+// Interrupt sends SIGINT to the process group, letting the processes exit gracefully
+func (o *CmdTree) Interrupt() {
+	_ = syscall.Kill(-o.Cmd.Process.Pid, syscall.SIGINT)
+}
+
 func (o *CmdTree) Kill() {
 	_ = syscall.Kill(-o.Cmd.Process.Pid, syscall.SIGKILL)
 }
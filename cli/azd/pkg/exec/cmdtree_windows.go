@@ -73,6 +73,14 @@ func (o *CmdTree) Start() error {
 	return nil
 }
 
+// Interrupt sends CTRL_BREAK to the process group of the command, letting the processes exit gracefully
+func (o *CmdTree) Interrupt() {
+	err := windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(o.Process.Pid))
+	if err != nil {
+		log.Printf("failed to interrupt process %d: %s\n", o.Process.Pid, err)
+	}
+}
+
 func (o *CmdTree) Kill() {
 	err := windows.TerminateJobObject(windows.Handle(o.jobObject), 0)
 	if err != nil {
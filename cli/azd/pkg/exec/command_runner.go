@@ -13,6 +13,9 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/cancellation"
 )
 
 // Settings to modify the way CmdTree is executed
@@ -108,7 +111,10 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		stdin = new(bytes.Buffer)
 	}
 
-	var stdout, stderr bytes.Buffer
+	stdout := newOutputBuffer(args.OutputWindow)
+	stderr := newOutputBuffer(args.OutputWindow)
+	defer stdout.Close(false)
+	defer stderr.Close(false)
 
 	cmd.Env = appendEnv(args.Env)
 
@@ -118,11 +124,15 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		cmd.Stderr = r.stderr
 	} else {
 		cmd.Stdin = stdin
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		if args.Stdout != nil {
+			cmd.Stdout = io.MultiWriter(args.Stdout, stdout)
+		}
 
 		if args.Stderr != nil {
-			cmd.Stderr = io.MultiWriter(args.Stderr, &stderr)
+			cmd.Stderr = io.MultiWriter(args.Stderr, stderr)
 		}
 	}
 
@@ -154,15 +164,35 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		return RunResult{}, err
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	exited := make(chan struct{})
 
+	// The process tree is killed once the command exits or is canceled. Commands interrupted or past their deadline are
+	// interrupted first and given a grace period to exit, ex. to release locks or write their state.
 	go func() {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+			if cancellation.IsInterrupted(ctx) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				log.Printf("interrupting '%s'", args.Cmd)
+				cmd.Interrupt()
+
+				select {
+				case <-exited:
+				case <-time.After(cancellation.GracePeriod):
+					log.Printf("'%s' did not exit after the interrupt, killing it", args.Cmd)
+				}
+			}
+		case <-exited:
+		}
+
 		cmd.Kill()
 	}()
 
 	err = cmd.Wait()
+	close(exited)
+
+	// Keep the complete output of failed commands on disk so it can be inspected
+	stdout.Close(err != nil)
+	stderr.Close(err != nil)
 
 	var result RunResult
 
@@ -208,6 +238,13 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 			outputAvailable)
 	}
 
+	// Commands failing because they were interrupted are reported as canceled, and as timed out past their deadline
+	if err != nil && cancellation.IsInterrupted(ctx) {
+		err = fmt.Errorf("%w: %w", cancellation.ErrInterrupted, err)
+	} else if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
 	return result, err
 }
 
@@ -220,15 +257,17 @@ func (r *commandRunner) RunList(ctx context.Context, commands []string, args Run
 	process.Cmd.Dir = args.Cwd
 	process.Env = appendEnv(args.Env)
 
-	var stdOutBuf bytes.Buffer
-	var stdErrBuf bytes.Buffer
+	stdOutBuf := newOutputBuffer(args.OutputWindow)
+	stdErrBuf := newOutputBuffer(args.OutputWindow)
+	defer stdOutBuf.Close(false)
+	defer stdErrBuf.Close(false)
 
 	if process.Stdout == nil {
-		process.Stdout = &stdOutBuf
+		process.Stdout = stdOutBuf
 	}
 
 	if process.Stderr == nil {
-		process.Stderr = &stdErrBuf
+		process.Stderr = stdErrBuf
 	}
 
 	if err := process.Start(); err != nil {
@@ -237,6 +276,8 @@ func (r *commandRunner) RunList(ctx context.Context, commands []string, args Run
 	defer process.Kill()
 
 	err = process.Wait()
+	stdOutBuf.Close(err != nil)
+	stdErrBuf.Close(err != nil)
 
 	return NewRunResult(
 		process.ProcessState.ExitCode(),
@@ -264,9 +305,13 @@ func newCmdTree(ctx context.Context, cmd string, args []string, useShell bool, i
 		if cmd == "" {
 			return CmdTree{}, errors.New("command must be provided if shell is not used")
 		} else {
+			command := exec.CommandContext(ctx, cmd, args...)
+			// The runner kills the process tree when the context is done, after interrupting interrupted commands
+			command.Cancel = func() error { return nil }
+
 			return CmdTree{
 				CmdTreeOptions: options,
-				Cmd:            exec.CommandContext(ctx, cmd, args...),
+				Cmd:            command,
 			}, nil
 		}
 	}
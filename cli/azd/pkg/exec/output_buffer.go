@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// The default amount of the most recent output kept in memory for commands run with an output window
+const DefaultOutputWindow = 1024 * 1024
+
+// outputBuffer captures the output of a command.
+//
+// When a window is set, only the most recent `window` bytes are kept in memory and the complete output is streamed to a
+// temporary file once it no longer fits, so commands with large output (ex. docker builds) use bounded memory.
+type outputBuffer struct {
+	window int
+
+	mu      sync.Mutex
+	memory  bytes.Buffer
+	written int64
+	file    *os.File
+	fileErr error
+	// The path of the complete output, if any
+	path string
+}
+
+func newOutputBuffer(window int) *outputBuffer {
+	return &outputBuffer{
+		window: window,
+	}
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.written += int64(len(p))
+	if b.window <= 0 {
+		return b.memory.Write(p)
+	}
+
+	if b.file == nil && b.fileErr == nil && b.memory.Len()+len(p) > b.window {
+		b.spill()
+	}
+
+	if b.file != nil {
+		if _, err := b.file.Write(p); err != nil {
+			log.Printf("failed writing command output to '%s': %v", b.file.Name(), err)
+			b.closeFile(false)
+			b.fileErr = err
+		}
+	}
+
+	b.memory.Write(p)
+
+	// Discard the oldest output once the buffer holds twice the window, so the copy is amortized over many writes
+	if b.memory.Len() > 2*b.window {
+		tail := b.memory.Bytes()[b.memory.Len()-b.window:]
+		next := bytes.Buffer{}
+		next.Grow(2 * b.window)
+		next.Write(tail)
+		b.memory = next
+	}
+
+	return len(p), nil
+}
+
+// Moves the output captured so far to a temporary file
+func (b *outputBuffer) spill() {
+	file, err := os.CreateTemp("", "azd-output-*.log")
+	if err != nil {
+		log.Printf("failed creating command output file, only the most recent output will be kept: %v", err)
+		b.fileErr = err
+		return
+	}
+
+	if _, err := file.Write(b.memory.Bytes()); err != nil {
+		log.Printf("failed writing command output to '%s': %v", file.Name(), err)
+		file.Close()
+		os.Remove(file.Name())
+		b.fileErr = err
+		return
+	}
+
+	b.file = file
+	b.path = file.Name()
+}
+
+// Truncated returns whether part of the output is no longer available in memory
+func (b *outputBuffer) Truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.truncated()
+}
+
+func (b *outputBuffer) truncated() bool {
+	return b.window > 0 && b.written > int64(b.window)
+}
+
+// String returns the captured output. When the output was truncated, only the most recent output is returned, prefixed
+// with a note of how much output was omitted and where the complete output was kept, if anywhere.
+func (b *outputBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.truncated() {
+		return b.memory.String()
+	}
+
+	contents := b.memory.Bytes()
+	tail := contents[len(contents)-b.window:]
+	omitted := b.written - int64(len(tail))
+
+	if b.path != "" {
+		return fmt.Sprintf("... (%d bytes omitted, complete output: %s)\n%s", omitted, b.path, tail)
+	}
+
+	return fmt.Sprintf("... (%d bytes omitted)\n%s", omitted, tail)
+}
+
+// Close releases the output file. When keep is true, the file is left on disk so the complete output of the command
+// can be inspected.
+func (b *outputBuffer) Close(keep bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closeFile(keep)
+}
+
+func (b *outputBuffer) closeFile(keep bool) {
+	if b.file == nil {
+		return
+	}
+
+	b.file.Close()
+	if !keep {
+		os.Remove(b.file.Name())
+		b.path = ""
+	}
+
+	b.file = nil
+}
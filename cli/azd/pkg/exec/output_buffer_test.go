@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputBuffer(t *testing.T) {
+	t.Run("Unbounded", func(t *testing.T) {
+		buffer := newOutputBuffer(0)
+		defer buffer.Close(false)
+
+		_, err := buffer.Write([]byte(strings.Repeat("a", 100)))
+		require.NoError(t, err)
+		require.False(t, buffer.Truncated())
+		require.Equal(t, strings.Repeat("a", 100), buffer.String())
+	})
+
+	t.Run("WithinWindow", func(t *testing.T) {
+		buffer := newOutputBuffer(10)
+		defer buffer.Close(false)
+
+		_, err := buffer.Write([]byte("0123456789"))
+		require.NoError(t, err)
+		require.False(t, buffer.Truncated())
+		require.Equal(t, "0123456789", buffer.String())
+		require.Empty(t, buffer.path)
+	})
+
+	t.Run("KeepsMostRecentOutput", func(t *testing.T) {
+		buffer := newOutputBuffer(10)
+
+		for i := 0; i < 10; i++ {
+			_, err := buffer.Write([]byte(fmt.Sprintf("line %d\n", i)))
+			require.NoError(t, err)
+		}
+
+		require.True(t, buffer.Truncated())
+		require.LessOrEqual(t, buffer.memory.Len(), 20)
+
+		path := buffer.path
+		require.Equal(t, fmt.Sprintf("... (60 bytes omitted, complete output: %s)\n 8\nline 9\n", path), buffer.String())
+
+		// The complete output is kept on disk
+		buffer.Close(true)
+		defer os.Remove(path)
+
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, 70, len(contents))
+		require.True(t, strings.HasPrefix(string(contents), "line 0\n"))
+	})
+
+	t.Run("RemovesOutputFile", func(t *testing.T) {
+		buffer := newOutputBuffer(10)
+
+		_, err := buffer.Write([]byte(strings.Repeat("a", 100)))
+		require.NoError(t, err)
+
+		path := buffer.path
+		buffer.Close(false)
+
+		_, err = os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+		require.Equal(t, fmt.Sprintf("... (90 bytes omitted)\n%s", strings.Repeat("a", 10)), buffer.String())
+	})
+}
@@ -13,6 +13,11 @@ type RunArgs struct {
 	Cwd           string
 	Env           []string
 
+	// Stdout will receive a copy of the text written to Stdout by
+	// the command, as it is written.
+	// NOTE: RunResult.Stdout will still contain stdout output.
+	Stdout io.Writer
+
 	// Stderr will receive a copy of the text written to Stderr by
 	// the command.
 	// NOTE: RunResult.Stderr will still contain stderr output.
@@ -29,6 +34,11 @@ type RunArgs struct {
 
 	// When set will call the command with the specified StdIn
 	StdIn io.Reader
+
+	// When set, only the most recent OutputWindow bytes of stdout & stderr are kept in memory.
+	// Larger output is streamed to a temporary file that is kept when the command fails.
+	// NOTE: RunResult.Stdout & RunResult.Stderr will only contain the most recent output.
+	OutputWindow int
 }
 
 // NewRunArgs creates a new instance with the specified cmd and args
@@ -91,3 +101,23 @@ func (b RunArgs) WithStdIn(stdIn io.Reader) RunArgs {
 	b.StdIn = stdIn
 	return b
 }
+
+// Updates the writer receiving a copy of the stdout of the command while invoking the command.
+// Use for long running commands whose output is parsed as it is written.
+func (b RunArgs) WithStdOut(stdOut io.Writer) RunArgs {
+	b.Stdout = stdOut
+	return b
+}
+
+// Updates the writer receiving a copy of the stderr of the command while invoking the command.
+func (b RunArgs) WithStdErr(stdErr io.Writer) RunArgs {
+	b.Stderr = stdErr
+	return b
+}
+
+// Updates the amount of the most recent output kept in memory while invoking the command.
+// Use for commands that may produce large output that isn't parsed, like builds & package restores.
+func (b RunArgs) WithOutputWindow(window int) RunArgs {
+	b.OutputWindow = window
+	return b
+}
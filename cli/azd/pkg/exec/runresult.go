@@ -55,6 +55,11 @@ func NewExitError(
 	}
 }
 
+// Stderr returns the standard error of the command, empty when the output wasn't captured
+func (e *ExitError) Stderr() string {
+	return e.stdErr
+}
+
 func (e *ExitError) Error() string {
 	if e.err.Exited() {
 		if !e.outputAvailable {
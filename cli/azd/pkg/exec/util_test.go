@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/cancellation"
 	"github.com/stretchr/testify/require"
 )
 
@@ -71,6 +72,30 @@ func TestKillCommand(t *testing.T) {
 	require.LessOrEqual(t, since, 10*time.Second)
 }
 
+func TestInterruptCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command traps SIGINT with sh")
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(500*time.Millisecond, func() {
+		cancel(cancellation.ErrInterrupted)
+	})
+
+	runner := NewCommandRunner(nil)
+	res, err := runner.Run(ctx, RunArgs{
+		Cmd:  "sh",
+		Args: []string{"-c", "trap 'echo cleaned up; exit 3' INT; sleep 10 >/dev/null 2>&1 & wait"},
+	})
+
+	// The command exits gracefully after the interrupt instead of being killed
+	require.ErrorIs(t, err, cancellation.ErrInterrupted)
+	var exitErr *ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 3, res.ExitCode)
+	require.Equal(t, "cleaned up\n", res.Stdout)
+}
+
 func TestAppendEnv(t *testing.T) {
 	require.Nil(t, appendEnv([]string{}))
 	require.Nil(t, appendEnv(nil))
@@ -0,0 +1,442 @@
+package ext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidCondition error = errors.New("invalid hook condition")
+
+// Values available when evaluating the `when` condition of a hook
+//
+// Conditions support the following values:
+//   - `env.<NAME>` - The value of the azd environment variable <NAME> (empty when not set)
+//   - `os` - The current operating system (windows, linux, darwin)
+//   - `ci` - Whether azd is running within a CI/CD system
+//   - `interactive` - Whether azd is running within an interactive terminal
+//   - `changed()` - Whether any files have changed within the hook working directory
+//   - `changed('<path>')` - Whether any files have changed within the path relative to the hook working directory
+//
+// Values can be compared with `==` and `!=` against other values or string literals ('value') and
+// combined using `&&`, `||`, `!` and parentheses.
+type HookConditionContext struct {
+	// Looks up the value of an azd environment variable
+	Env func(key string) string
+	// The current operating system
+	OS string
+	// Whether azd is running on a CI/CD system
+	CI bool
+	// Whether azd is running within an interactive terminal
+	Interactive bool
+	// Returns whether any files have changed within the specified path
+	Changed func(path string) (bool, error)
+}
+
+// Evaluates the `when` condition expression against the specified context
+func EvaluateHookCondition(condition string, conditionContext HookConditionContext) (bool, error) {
+	node, err := parseHookCondition(condition)
+	if err != nil {
+		return false, err
+	}
+
+	value, err := node.eval(conditionContext)
+	if err != nil {
+		return false, err
+	}
+
+	return isTruthy(value), nil
+}
+
+// Parses the condition expressions and returns the root node of the expression tree
+func parseHookCondition(condition string) (conditionNode, error) {
+	tokens, err := tokenizeCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &conditionParser{tokens: tokens}
+	node, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !parser.done() {
+		return nil, fmt.Errorf("%w: unexpected token '%s' in '%s'", ErrInvalidCondition, parser.peek().value, condition)
+	}
+
+	return node, nil
+}
+
+const (
+	trueValue  = "true"
+	falseValue = "false"
+)
+
+func boolValue(value bool) string {
+	if value {
+		return trueValue
+	}
+
+	return falseValue
+}
+
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", falseValue, "0", "no", "off":
+		return false
+	default:
+		return true
+	}
+}
+
+type conditionNode interface {
+	eval(conditionContext HookConditionContext) (string, error)
+}
+
+type literalNode struct {
+	value string
+}
+
+func (n *literalNode) eval(conditionContext HookConditionContext) (string, error) {
+	return n.value, nil
+}
+
+type identifierNode struct {
+	name string
+}
+
+func (n *identifierNode) eval(conditionContext HookConditionContext) (string, error) {
+	if key, has := strings.CutPrefix(n.name, "env."); has {
+		if conditionContext.Env == nil {
+			return "", nil
+		}
+
+		return conditionContext.Env(key), nil
+	}
+
+	switch n.name {
+	case "os":
+		return conditionContext.OS, nil
+	case "ci":
+		return boolValue(conditionContext.CI), nil
+	case "interactive":
+		return boolValue(conditionContext.Interactive), nil
+	case trueValue, falseValue:
+		return n.name, nil
+	default:
+		return "", fmt.Errorf("%w: unknown value '%s'", ErrInvalidCondition, n.name)
+	}
+}
+
+type changedNode struct {
+	path string
+}
+
+func (n *changedNode) eval(conditionContext HookConditionContext) (string, error) {
+	if conditionContext.Changed == nil {
+		return falseValue, nil
+	}
+
+	changed, err := conditionContext.Changed(n.path)
+	if err != nil {
+		return "", fmt.Errorf("failed detecting changes for '%s': %w", n.path, err)
+	}
+
+	return boolValue(changed), nil
+}
+
+type notNode struct {
+	operand conditionNode
+}
+
+func (n *notNode) eval(conditionContext HookConditionContext) (string, error) {
+	value, err := n.operand.eval(conditionContext)
+	if err != nil {
+		return "", err
+	}
+
+	return boolValue(!isTruthy(value)), nil
+}
+
+type compareNode struct {
+	operator string
+	left     conditionNode
+	right    conditionNode
+}
+
+func (n *compareNode) eval(conditionContext HookConditionContext) (string, error) {
+	left, err := n.left.eval(conditionContext)
+	if err != nil {
+		return "", err
+	}
+
+	right, err := n.right.eval(conditionContext)
+	if err != nil {
+		return "", err
+	}
+
+	equal := left == right
+	if n.operator == "!=" {
+		return boolValue(!equal), nil
+	}
+
+	return boolValue(equal), nil
+}
+
+type logicalNode struct {
+	operator string
+	left     conditionNode
+	right    conditionNode
+}
+
+// Evaluates the logical operator, short-circuiting the right operand when possible
+func (n *logicalNode) eval(conditionContext HookConditionContext) (string, error) {
+	left, err := n.left.eval(conditionContext)
+	if err != nil {
+		return "", err
+	}
+
+	leftTruthy := isTruthy(left)
+	if n.operator == "&&" && !leftTruthy {
+		return falseValue, nil
+	}
+
+	if n.operator == "||" && leftTruthy {
+		return trueValue, nil
+	}
+
+	right, err := n.right.eval(conditionContext)
+	if err != nil {
+		return "", err
+	}
+
+	return boolValue(isTruthy(right)), nil
+}
+
+type conditionTokenKind int
+
+const (
+	tokenIdentifier conditionTokenKind = iota
+	tokenString
+	tokenOperator
+)
+
+type conditionToken struct {
+	kind  conditionTokenKind
+	value string
+}
+
+func tokenizeCondition(condition string) ([]conditionToken, error) {
+	tokens := []conditionToken{}
+	runes := []rune(condition)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+
+			if end >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string in '%s'", ErrInvalidCondition, condition)
+			}
+
+			tokens = append(tokens, conditionToken{kind: tokenString, value: string(runes[i+1 : end])})
+			i = end + 1
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, conditionToken{kind: tokenOperator, value: string(r)})
+			i++
+		case r == '!' || r == '=' || r == '&' || r == '|':
+			if i+1 < len(runes) {
+				operator := string(runes[i : i+2])
+				if operator == "==" || operator == "!=" || operator == "&&" || operator == "||" {
+					tokens = append(tokens, conditionToken{kind: tokenOperator, value: operator})
+					i += 2
+					continue
+				}
+			}
+
+			if r != '!' {
+				return nil, fmt.Errorf("%w: unexpected character '%c' in '%s'", ErrInvalidCondition, r, condition)
+			}
+
+			tokens = append(tokens, conditionToken{kind: tokenOperator, value: "!"})
+			i++
+		case isIdentifierRune(r):
+			end := i
+			for end < len(runes) && isIdentifierRune(runes[end]) {
+				end++
+			}
+
+			tokens = append(tokens, conditionToken{kind: tokenIdentifier, value: string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("%w: unexpected character '%c' in '%s'", ErrInvalidCondition, r, condition)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+// Recursive descent parser for hook conditions
+//
+// or      := and ('||' and)*
+// and     := unary ('&&' unary)*
+// unary   := '!' unary | compare
+// compare := primary (('==' | '!=') primary)?
+// primary := '(' or ')' | string | identifier | identifier '(' string? ')'
+type conditionParser struct {
+	tokens   []conditionToken
+	position int
+}
+
+func (p *conditionParser) done() bool {
+	return p.position >= len(p.tokens)
+}
+
+func (p *conditionParser) peek() conditionToken {
+	if p.done() {
+		return conditionToken{}
+	}
+
+	return p.tokens[p.position]
+}
+
+func (p *conditionParser) acceptOperator(operator string) bool {
+	token := p.peek()
+	if !p.done() && token.kind == tokenOperator && token.value == operator {
+		p.position++
+		return true
+	}
+
+	return false
+}
+
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptOperator("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalNode{operator: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptOperator("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalNode{operator: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	if p.acceptOperator("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notNode{operand: operand}, nil
+	}
+
+	return p.parseCompare()
+}
+
+func (p *conditionParser) parseCompare() (conditionNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, operator := range []string{"==", "!="} {
+		if p.acceptOperator(operator) {
+			right, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+
+			return &compareNode{operator: operator, left: left, right: right}, nil
+		}
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parsePrimary() (conditionNode, error) {
+	if p.done() {
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrInvalidCondition)
+	}
+
+	if p.acceptOperator("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.acceptOperator(")") {
+			return nil, fmt.Errorf("%w: missing closing parenthesis", ErrInvalidCondition)
+		}
+
+		return node, nil
+	}
+
+	token := p.peek()
+	p.position++
+
+	switch token.kind {
+	case tokenString:
+		return &literalNode{value: token.value}, nil
+	case tokenIdentifier:
+		if !p.acceptOperator("(") {
+			return &identifierNode{name: token.value}, nil
+		}
+
+		if token.value != "changed" {
+			return nil, fmt.Errorf("%w: unknown function '%s'", ErrInvalidCondition, token.value)
+		}
+
+		path := ""
+		if !p.done() && p.peek().kind == tokenString {
+			path = p.peek().value
+			p.position++
+		}
+
+		if !p.acceptOperator(")") {
+			return nil, fmt.Errorf("%w: function '%s' accepts a single string argument", ErrInvalidCondition, token.value)
+		}
+
+		return &changedNode{path: path}, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected token '%s'", ErrInvalidCondition, token.value)
+	}
+}
@@ -0,0 +1,103 @@
+package ext
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EvaluateHookCondition(t *testing.T) {
+	env := map[string]string{
+		"AZURE_ENV_NAME": "dev",
+		"SEED_DATABASE":  "true",
+		"FEATURE":        "false",
+	}
+
+	changedPaths := []string{}
+	conditionContext := HookConditionContext{
+		Env:         func(key string) string { return env[key] },
+		OS:          "linux",
+		CI:          true,
+		Interactive: false,
+		Changed: func(path string) (bool, error) {
+			changedPaths = append(changedPaths, path)
+			return path == "src/api", nil
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		expected  bool
+	}{
+		{"EnvTruthy", "env.SEED_DATABASE", true},
+		{"EnvFalsy", "env.FEATURE", false},
+		{"EnvMissing", "env.MISSING", false},
+		{"EnvEquals", "env.AZURE_ENV_NAME == 'dev'", true},
+		{"EnvNotEquals", "env.AZURE_ENV_NAME != \"dev\"", false},
+		{"OS", "os == 'linux'", true},
+		{"CI", "ci", true},
+		{"NotInteractive", "!interactive", true},
+		{"And", "ci && os == 'windows'", false},
+		{"Or", "os == 'windows' || os == 'linux'", true},
+		{"Parentheses", "!(ci && interactive) && env.AZURE_ENV_NAME == 'dev'", true},
+		{"ChangedPath", "changed('src/api')", true},
+		{"UnchangedPath", "changed('src/web')", false},
+		{"ChangedCwd", "changed()", false},
+		{"BooleanLiterals", "true && !false", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := EvaluateHookCondition(test.condition, conditionContext)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, result)
+		})
+	}
+
+	t.Run("ShortCircuit", func(t *testing.T) {
+		changedPaths = []string{}
+		result, err := EvaluateHookCondition("ci || changed('src/api')", conditionContext)
+		require.NoError(t, err)
+		require.True(t, result)
+		require.Empty(t, changedPaths)
+	})
+
+	t.Run("ChangedError", func(t *testing.T) {
+		errorContext := HookConditionContext{
+			Changed: func(path string) (bool, error) {
+				return false, errors.New("not a git repository")
+			},
+		}
+
+		result, err := EvaluateHookCondition("changed()", errorContext)
+		require.Error(t, err)
+		require.False(t, result)
+	})
+}
+
+func Test_ParseHookCondition_Invalid(t *testing.T) {
+	conditions := []string{
+		"env.A ==",
+		"(ci && interactive",
+		"os = 'linux'",
+		"'unterminated",
+		"unknown('a')",
+		"changed('a', 'b')",
+		"ci interactive",
+		"os == 'linux' $",
+	}
+
+	for _, condition := range conditions {
+		t.Run(condition, func(t *testing.T) {
+			_, err := parseHookCondition(condition)
+			require.ErrorIs(t, err, ErrInvalidCondition)
+		})
+	}
+
+	t.Run("UnknownValue", func(t *testing.T) {
+		_, err := EvaluateHookCondition("unknown == 'a'", HookConditionContext{})
+		require.ErrorIs(t, err, ErrInvalidCondition)
+	})
+}
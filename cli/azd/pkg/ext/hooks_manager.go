@@ -3,7 +3,6 @@ package ext
 import (
 	"fmt"
 	"os"
-	"runtime"
 	"strings"
 )
 
@@ -36,14 +35,14 @@ func NewHooksManager(
 
 // Gets an array of all hook configurations
 // Will return an error if any configuration errors are found
-func (h *HooksManager) GetAll(hooks map[string]*HookConfig) ([]*HookConfig, error) {
+func (h *HooksManager) GetAll(hooks HooksConfig) ([]*HookConfig, error) {
 	return h.filterConfigs(hooks, nil)
 }
 
 // Gets an array of hook configurations matching the specified hook type and commands
 // Will return an error if any configuration errors are found
 func (h *HooksManager) GetByParams(
-	hooks map[string]*HookConfig,
+	hooks HooksConfig,
 	prefix HookType,
 	commands ...string,
 ) ([]*HookConfig, error) {
@@ -69,36 +68,35 @@ func (h *HooksManager) GetByParams(
 // Filters the specified hook configurations based on the predicate
 // Will return an error if any configuration errors are found
 func (h *HooksManager) filterConfigs(
-	hooks map[string]*HookConfig,
+	hooks HooksConfig,
 	predicate HookFilterPredicateFn,
 ) ([]*HookConfig, error) {
 	matchingHooks := []*HookConfig{}
 
 	// Find explicitly configured hooks from azure.yaml
-	for scriptName, hookConfig := range hooks {
-		if hookConfig == nil {
-			continue
-		}
+	for scriptName, hookConfigs := range hooks {
+		// Multiple hooks for the same name run in the order they are defined
+		for _, hookConfig := range hookConfigs {
+			if hookConfig == nil {
+				continue
+			}
 
-		if predicate != nil && !predicate(scriptName, hookConfig) {
-			continue
-		}
+			if predicate != nil && !predicate(scriptName, hookConfig) {
+				continue
+			}
 
-		// If the hook config includes an OS specific configuration use that instead
-		if runtime.GOOS == "windows" && hookConfig.Windows != nil {
-			hookConfig = hookConfig.Windows
-		} else if (runtime.GOOS == "linux" || runtime.GOOS == "darwin") && hookConfig.Posix != nil {
-			hookConfig = hookConfig.Posix
-		}
+			// If the hook config includes an OS specific configuration use that instead
+			hookConfig = hookConfig.ForCurrentOS()
 
-		hookConfig.Name = scriptName
-		hookConfig.cwd = h.cwd
+			hookConfig.Name = scriptName
+			hookConfig.cwd = h.cwd
 
-		if err := hookConfig.validate(); err != nil {
-			return nil, fmt.Errorf("hook configuration for '%s' is invalid, %w", scriptName, err)
-		}
+			if err := hookConfig.validate(); err != nil {
+				return nil, fmt.Errorf("hook configuration for '%s' is invalid, %w", scriptName, err)
+			}
 
-		matchingHooks = append(matchingHooks, hookConfig)
+			matchingHooks = append(matchingHooks, hookConfig)
+		}
 	}
 
 	return matchingHooks, nil
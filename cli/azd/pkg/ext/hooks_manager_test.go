@@ -15,13 +15,13 @@ func Test_GetAllHookConfigs(t *testing.T) {
 	ostest.Chdir(t, tempDir)
 
 	t.Run("With Valid Configuration", func(t *testing.T) {
-		hooks := map[string]*HookConfig{
-			"preinit": {
+		hooks := HooksConfig{
+			"preinit": {{
 				Run: "scripts/preinit.sh",
-			},
-			"postinit": {
+			}},
+			"postinit": {{
 				Run: "scripts/postinit.sh",
-			},
+			}},
 		}
 
 		ensureScriptsExist(t, hooks)
@@ -35,13 +35,13 @@ func Test_GetAllHookConfigs(t *testing.T) {
 
 	t.Run("With Invalid Configuration", func(t *testing.T) {
 		// All hooks are invalid because they are missing a script type
-		hooks := map[string]*HookConfig{
-			"preinit": {
+		hooks := HooksConfig{
+			"preinit": {{
 				Run: "echo 'Hello'",
-			},
-			"postinit": {
+			}},
+			"postinit": {{
 				Run: "echo 'Hello'",
-			},
+			}},
 		}
 
 		ensureScriptsExist(t, hooks)
@@ -59,13 +59,13 @@ func Test_GetByParams(t *testing.T) {
 	ostest.Chdir(t, tempDir)
 
 	t.Run("With Valid Configuration", func(t *testing.T) {
-		hooks := map[string]*HookConfig{
-			"preinit": {
+		hooks := HooksConfig{
+			"preinit": {{
 				Run: "scripts/preinit.sh",
-			},
-			"postinit": {
+			}},
+			"postinit": {{
 				Run: "scripts/postinit.sh",
-			},
+			}},
 		}
 
 		ensureScriptsExist(t, hooks)
@@ -74,19 +74,19 @@ func Test_GetByParams(t *testing.T) {
 		validHooks, err := hooksManager.GetByParams(hooks, HookTypePre, "init")
 
 		require.Len(t, validHooks, 1)
-		require.Equal(t, hooks["preinit"], validHooks[0])
+		require.Equal(t, hooks["preinit"][0], validHooks[0])
 		require.NoError(t, err)
 	})
 
 	t.Run("With Invalid Configuration", func(t *testing.T) {
 		// All hooks are invalid because they are missing a script type
-		hooks := map[string]*HookConfig{
-			"preinit": {
+		hooks := HooksConfig{
+			"preinit": {{
 				Run: "echo 'Hello'",
-			},
-			"postinit": {
+			}},
+			"postinit": {{
 				Run: "echo 'Hello'",
-			},
+			}},
 		}
 
 		ensureScriptsExist(t, hooks)
@@ -99,15 +99,17 @@ func Test_GetByParams(t *testing.T) {
 	})
 }
 
-func ensureScriptsExist(t *testing.T, configs map[string]*HookConfig) {
-	for _, hook := range configs {
-		ext := filepath.Ext(hook.Run)
-
-		if ext != "" {
-			err := os.MkdirAll(filepath.Dir(hook.Run), osutil.PermissionDirectory)
-			require.NoError(t, err)
-			err = os.WriteFile(hook.Run, nil, osutil.PermissionExecutableFile)
-			require.NoError(t, err)
+func ensureScriptsExist(t *testing.T, configs HooksConfig) {
+	for _, hooks := range configs {
+		for _, hook := range hooks {
+			ext := filepath.Ext(hook.Run)
+
+			if ext != "" {
+				err := os.MkdirAll(filepath.Dir(hook.Run), osutil.PermissionDirectory)
+				require.NoError(t, err)
+				err = os.WriteFile(hook.Run, nil, osutil.PermissionExecutableFile)
+				require.NoError(t, err)
+			}
 		}
 	}
 }
@@ -5,15 +5,22 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bash"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/powershell"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
+	"github.com/joho/godotenv"
 )
 
 // Hooks enable support to invoke integration scripts before & after commands
@@ -23,7 +30,7 @@ type HooksRunner struct {
 	commandRunner exec.CommandRunner
 	console       input.Console
 	cwd           string
-	hooks         map[string]*HookConfig
+	hooks         HooksConfig
 	env           *environment.Environment
 }
 
@@ -34,7 +41,7 @@ func NewHooksRunner(
 	commandRunner exec.CommandRunner,
 	console input.Console,
 	cwd string,
-	hooks map[string]*HookConfig,
+	hooks HooksConfig,
 	env *environment.Environment,
 ) *HooksRunner {
 	if cwd == "" {
@@ -88,6 +95,18 @@ func (h *HooksRunner) RunHooks(ctx context.Context, hookType HookType, commands
 			return fmt.Errorf("reloading environment before running hook: %w", err)
 		}
 
+		if hookConfig.When != "" {
+			shouldRun, err := EvaluateHookCondition(hookConfig.When, h.conditionContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed evaluating condition for '%s' hook: %w", hookConfig.Name, err)
+			}
+
+			if !shouldRun {
+				log.Printf("Skipping '%s' hook since condition '%s' is not met\n", hookConfig.Name, hookConfig.When)
+				continue
+			}
+		}
+
 		err := h.execHook(ctx, hookConfig)
 		if err != nil {
 			return err
@@ -101,32 +120,123 @@ func (h *HooksRunner) RunHooks(ctx context.Context, hookType HookType, commands
 	return nil
 }
 
+// Gets the values available to hook conditions for the current environment
+func (h *HooksRunner) conditionContext(ctx context.Context) HookConditionContext {
+	gitCli := git.NewGitCli(h.commandRunner)
+
+	return HookConditionContext{
+		Env:         h.env.Getenv,
+		OS:          runtime.GOOS,
+		CI:          resource.IsRunningOnCI(),
+		Interactive: h.console.IsSpinnerInteractive(),
+		Changed: func(path string) (bool, error) {
+			return gitCli.HasChanges(ctx, h.cwd, path)
+		},
+	}
+}
+
 // Gets the script to execute based on the hook configuration values
 // For inline scripts this will also create a temporary script file to execute
 func (h *HooksRunner) GetScript(hookConfig *HookConfig) (tools.Script, error) {
+	return h.getScript(hookConfig, h.env.Environ(), nil)
+}
+
+// Gets the script to execute with the specified environment variables and additional container mounts.
+func (h *HooksRunner) getScript(hookConfig *HookConfig, envVars []string, mounts []string) (tools.Script, error) {
 	if err := hookConfig.validate(); err != nil {
 		return nil, err
 	}
 
+	cwd := h.workingDirectory(hookConfig)
+
+	if hookConfig.Container != nil {
+		return docker.NewContainerScript(h.commandRunner, docker.ContainerScriptOptions{
+			Image:   hookConfig.Container.Image,
+			Shell:   string(hookConfig.Shell),
+			Cwd:     cwd,
+			EnvVars: envVars,
+			Mounts:  mounts,
+		}), nil
+	}
+
 	switch hookConfig.Shell {
 	case ShellTypeBash:
-		return bash.NewBashScript(h.commandRunner, h.cwd, h.env.Environ()), nil
+		return bash.NewBashScript(h.commandRunner, cwd, envVars), nil
 	case ShellTypePowershell:
-		return powershell.NewPowershellScript(h.commandRunner, h.cwd, h.env.Environ()), nil
+		return powershell.NewPowershellScript(h.commandRunner, cwd, envVars), nil
+	case ShellTypePython:
+		return python.NewPythonScript(h.commandRunner, cwd, envVars), nil
 	default:
 		return nil, fmt.Errorf(
-			"shell type '%s' is not a valid option. Only 'sh' and 'pwsh' are supported",
+			"shell type '%s' is not a valid option. Only 'sh', 'pwsh' and 'python' are supported",
 			hookConfig.Shell,
 		)
 	}
 }
 
+// Gets the directory the hook runs within.
+// The configured working directory is relative to the project or service directory.
+func (h *HooksRunner) workingDirectory(hookConfig *HookConfig) string {
+	if hookConfig.WorkingDirectory == "" {
+		return h.cwd
+	}
+
+	if filepath.IsAbs(hookConfig.WorkingDirectory) {
+		return hookConfig.WorkingDirectory
+	}
+
+	return filepath.Join(h.cwd, hookConfig.WorkingDirectory)
+}
+
+// Gets the path of the script to execute.
+// Script paths are relative to the project or service directory, so they are made absolute when the hook runs
+// within a different working directory.
+func (h *HooksRunner) scriptPath(hookConfig *HookConfig) string {
+	if hookConfig.WorkingDirectory == "" || filepath.IsAbs(hookConfig.path) {
+		return hookConfig.path
+	}
+
+	return filepath.Join(h.cwd, hookConfig.path)
+}
+
 func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig) error {
-	script, err := h.GetScript(hookConfig)
+	outputFile, err := os.CreateTemp(os.TempDir(), fmt.Sprintf("azd-%s-output-*.env", hookConfig.Name))
+	if err != nil {
+		return fmt.Errorf("failed creating hook output file: %w", err)
+	}
+
+	outputPath := outputFile.Name()
+	outputFile.Close()
+	defer os.Remove(outputPath)
+
+	// Hooks running within a container write outputs to a mounted copy of the host file
+	var mounts []string
+	outputEnvValue := outputPath
+	if hookConfig.Container != nil {
+		outputEnvValue = containerHookOutputPath
+		mounts = append(mounts, fmt.Sprintf("%s:%s", outputPath, containerHookOutputPath))
+	}
+
+	envVars := append(h.env.Environ(), fmt.Sprintf("%s=%s", HookOutputEnvVarName, outputEnvValue))
+
+	script, err := h.getScript(hookConfig, envVars, mounts)
 	if err != nil {
 		return err
 	}
 
+	if hookConfig.WorkingDirectory != "" {
+		stat, err := os.Stat(h.workingDirectory(hookConfig))
+		if err != nil || !stat.IsDir() {
+			return fmt.Errorf(
+				"working directory '%s' for '%s' hook does not exist",
+				hookConfig.WorkingDirectory,
+				hookConfig.Name,
+			)
+		}
+	}
+
+	scriptPath := h.scriptPath(hookConfig)
+
 	formatter := h.console.GetFormatter()
 	consoleInteractive := formatter == nil || formatter.Kind() == output.NoneFormat
 	scriptInteractive := consoleInteractive && hookConfig.Interactive
@@ -145,8 +255,8 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig) erro
 		)
 	}
 
-	log.Printf("Executing script '%s'\n", hookConfig.path)
-	res, err := script.Execute(ctx, hookConfig.path, scriptInteractive)
+	log.Printf("Executing script '%s'\n", scriptPath)
+	res, err := script.Execute(ctx, scriptPath, scriptInteractive)
 	if err != nil {
 		execErr := fmt.Errorf(
 			"'%s' hook failed with exit code: '%d', Path: '%s'. : %w",
@@ -175,5 +285,34 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig) erro
 		defer os.Remove(hookConfig.path)
 	}
 
+	if err == nil {
+		if err := h.applyOutputs(hookConfig, outputPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Merges any `KEY=VALUE` outputs written by the hook into the azd environment
+func (h *HooksRunner) applyOutputs(hookConfig *HookConfig, outputPath string) error {
+	outputs, err := godotenv.Read(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed reading outputs for '%s' hook: %w", hookConfig.Name, err)
+	}
+
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	for key, value := range outputs {
+		log.Printf("Setting environment value '%s' from '%s' hook output\n", key, hookConfig.Name)
+		h.env.DotenvSet(key, value)
+	}
+
+	if err := h.env.Save(); err != nil {
+		return fmt.Errorf("failed saving outputs for '%s' hook: %w", hookConfig.Name, err)
+	}
+
 	return nil
 }
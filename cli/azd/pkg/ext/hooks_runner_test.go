@@ -3,6 +3,7 @@ package ext
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -27,24 +28,24 @@ func Test_Hooks_Execute(t *testing.T) {
 		},
 	)
 
-	hooks := map[string]*HookConfig{
-		"preinline": {
+	hooks := HooksConfig{
+		"preinline": {{
 			Shell: ShellTypeBash,
 			Run:   "echo 'Hello'",
-		},
-		"precommand": {
+		}},
+		"precommand": {{
 			Shell: ShellTypeBash,
 			Run:   "scripts/precommand.sh",
-		},
-		"postcommand": {
+		}},
+		"postcommand": {{
 			Shell: ShellTypeBash,
 			Run:   "scripts/postcommand.sh",
-		},
-		"preinteractive": {
+		}},
+		"preinteractive": {{
 			Shell:       ShellTypeBash,
 			Run:         "scripts/preinteractive.sh",
 			Interactive: true,
-		},
+		}},
 	}
 
 	ensureScriptsExist(t, hooks)
@@ -60,7 +61,7 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPreHook = true
 			require.Equal(t, "scripts/precommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, env.Environ(), withoutHookOutput(args.Env))
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
@@ -86,7 +87,7 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/postcommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, env.Environ(), withoutHookOutput(args.Env))
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
@@ -112,7 +113,7 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/preinteractive.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, env.Environ(), withoutHookOutput(args.Env))
 			require.Equal(t, true, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
@@ -200,6 +201,101 @@ func Test_Hooks_Execute(t *testing.T) {
 	})
 }
 
+func Test_Hooks_Outputs(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.EmptyWithRoot(t.TempDir())
+	env.DotenvSet("a", "apple")
+	require.NoError(t, env.Save())
+
+	hooks := HooksConfig{
+		"precommand": {{
+			Shell: ShellTypeBash,
+			Run:   "scripts/precommand.sh",
+		}},
+	}
+
+	ensureScriptsExist(t, hooks)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "precommand.sh")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		outputPath := ""
+		for _, envVar := range args.Env {
+			if value, has := strings.CutPrefix(envVar, HookOutputEnvVarName+"="); has {
+				outputPath = value
+			}
+		}
+
+		require.NotEmpty(t, outputPath)
+		err := os.WriteFile(outputPath, []byte("b=banana\nc=\"cherry pie\"\n"), osutil.PermissionFile)
+		require.NoError(t, err)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	hooksManager := NewHooksManager(cwd)
+	runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+	err := runner.RunHooks(*mockContext.Context, HookTypePre, "command")
+	require.NoError(t, err)
+
+	require.Equal(t, "apple", env.Getenv("a"))
+	require.Equal(t, "banana", env.Getenv("b"))
+	require.Equal(t, "cherry pie", env.Getenv("c"))
+}
+
+func Test_Hooks_Condition(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.EmptyWithRoot(t.TempDir())
+	env.DotenvSet("SEED_DATABASE", "true")
+	require.NoError(t, env.Save())
+
+	hooks := HooksConfig{
+		"preseed": {{
+			Shell: ShellTypeBash,
+			Run:   "scripts/preseed.sh",
+			When:  "env.SEED_DATABASE == 'true'",
+		}},
+		"preskip": {{
+			Shell: ShellTypeBash,
+			Run:   "scripts/preskip.sh",
+			When:  "env.SEED_DATABASE != 'true'",
+		}},
+	}
+
+	ensureScriptsExist(t, hooks)
+
+	ranSeed := false
+	ranSkip := false
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "preseed.sh")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ranSeed = true
+		return exec.NewRunResult(0, "", ""), nil
+	})
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "preskip.sh")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ranSkip = true
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	hooksManager := NewHooksManager(cwd)
+	runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+
+	require.NoError(t, runner.RunHooks(*mockContext.Context, HookTypePre, "seed"))
+	require.NoError(t, runner.RunHooks(*mockContext.Context, HookTypePre, "skip"))
+
+	require.True(t, ranSeed)
+	require.False(t, ranSkip)
+}
+
 func Test_Hooks_GetScript(t *testing.T) {
 	cwd := t.TempDir()
 	ostest.Chdir(t, cwd)
@@ -212,23 +308,36 @@ func Test_Hooks_GetScript(t *testing.T) {
 		},
 	)
 
-	hooks := map[string]*HookConfig{
-		"bash": {
+	hooks := HooksConfig{
+		"bash": {{
 			Run: "scripts/script.sh",
-		},
-		"pwsh": {
+		}},
+		"pwsh": {{
 			Run: "scripts/script.ps1",
-		},
-		"inline": {
+		}},
+		"inline": {{
 			Shell: ShellTypeBash,
 			Run:   "echo 'hello'",
-		},
+		}},
+		"container": {{
+			Run: "scripts/container.sh",
+			Container: &HookContainerConfig{
+				Image: "mcr.microsoft.com/azure-cli",
+			},
+		}},
+		"python": {{
+			Run: "scripts/script.py",
+		}},
+		"inlinepython": {{
+			Shell: ShellTypePython,
+			Run:   "print('hello')\nprint('world')",
+		}},
 	}
 
 	ensureScriptsExist(t, hooks)
 
 	t.Run("Bash", func(t *testing.T) {
-		hookConfig := hooks["bash"]
+		hookConfig := hooks["bash"][0]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
 		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
@@ -242,7 +351,7 @@ func Test_Hooks_GetScript(t *testing.T) {
 	})
 
 	t.Run("Powershell", func(t *testing.T) {
-		hookConfig := hooks["pwsh"]
+		hookConfig := hooks["pwsh"][0]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
 		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
@@ -259,7 +368,7 @@ func Test_Hooks_GetScript(t *testing.T) {
 		tempDir := t.TempDir()
 		ostest.Chdir(t, tempDir)
 
-		hookConfig := hooks["inline"]
+		hookConfig := hooks["inline"][0]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
 		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
@@ -277,6 +386,148 @@ func Test_Hooks_GetScript(t *testing.T) {
 		require.NotNil(t, fileInfo)
 		require.NoError(t, err)
 	})
+
+	t.Run("Container", func(t *testing.T) {
+		hookConfig := hooks["container"][0]
+		mockContext := mocks.NewMockContext(context.Background())
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+
+		script, err := runner.GetScript(hookConfig)
+		require.NotNil(t, script)
+		require.Equal(t, "*docker.containerScript", reflect.TypeOf(script).String())
+		require.Equal(t, ScriptLocationPath, hookConfig.location)
+		require.Equal(t, ShellTypeBash, hookConfig.Shell)
+		require.NoError(t, err)
+	})
+
+	t.Run("Python", func(t *testing.T) {
+		hookConfig := hooks["python"][0]
+		mockContext := mocks.NewMockContext(context.Background())
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+
+		script, err := runner.GetScript(hookConfig)
+		require.NotNil(t, script)
+		require.Equal(t, "*python.pythonScript", reflect.TypeOf(script).String())
+		require.Equal(t, ScriptLocationPath, hookConfig.location)
+		require.Equal(t, ShellTypePython, hookConfig.Shell)
+		require.NoError(t, err)
+	})
+
+	t.Run("Inline Python Script", func(t *testing.T) {
+		hookConfig := hooks["inlinepython"][0]
+		mockContext := mocks.NewMockContext(context.Background())
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+
+		script, err := runner.GetScript(hookConfig)
+		require.NotNil(t, script)
+		require.Equal(t, "*python.pythonScript", reflect.TypeOf(script).String())
+		require.Equal(t, ScriptLocationInline, hookConfig.location)
+		require.Contains(t, hookConfig.path, ".py")
+		require.NoError(t, err)
+
+		contents, err := os.ReadFile(hookConfig.path)
+		require.NoError(t, err)
+		require.Contains(t, string(contents), "print('hello')\nprint('world')")
+	})
+}
+
+func Test_Hooks_Multiple(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.Ephemeral()
+
+	hooks := HooksConfig{
+		"precommand": {
+			{
+				Shell: ShellTypeBash,
+				Run:   "scripts/first.sh",
+			},
+			{
+				Shell: ShellTypeBash,
+				Run:   "scripts/second.sh",
+			},
+			{
+				Shell: ShellTypeBash,
+				Run:   "scripts/third.sh",
+			},
+		},
+	}
+
+	ensureScriptsExist(t, hooks)
+
+	executed := []string{}
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return true
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		executed = append(executed, filepath.Base(args.Args[0]))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	hooksManager := NewHooksManager(cwd)
+	runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+	err := runner.RunHooks(*mockContext.Context, HookTypePre, "command")
+	require.NoError(t, err)
+	require.Equal(t, []string{"first.sh", "second.sh", "third.sh"}, executed)
+}
+
+func Test_Hooks_WorkingDirectory(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	err := os.MkdirAll(filepath.Join(cwd, "src", "web"), osutil.PermissionDirectory)
+	require.NoError(t, err)
+
+	env := environment.Ephemeral()
+
+	hooks := HooksConfig{
+		"precommand": {{
+			Shell:            ShellTypeBash,
+			Run:              "scripts/precommand.sh",
+			WorkingDirectory: "src/web",
+		}},
+		"premissing": {{
+			Shell:            ShellTypeBash,
+			Run:              "scripts/precommand.sh",
+			WorkingDirectory: "src/missing",
+		}},
+	}
+
+	ensureScriptsExist(t, hooks)
+
+	t.Run("Success", func(t *testing.T) {
+		ranHook := false
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "precommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ranHook = true
+			require.Equal(t, filepath.Join(cwd, "src", "web"), args.Cwd)
+			require.Equal(t, filepath.Join(cwd, "scripts", "precommand.sh"), args.Args[0])
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, "command")
+
+		require.True(t, ranHook)
+		require.NoError(t, err)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, "missing")
+
+		require.Error(t, err)
+	})
 }
 
 type scriptValidationTest struct {
@@ -302,7 +553,7 @@ func Test_GetScript_Validation(t *testing.T) {
 		mockContext.CommandRunner,
 		mockContext.Console,
 		tempDir,
-		map[string]*HookConfig{},
+		HooksConfig{},
 		env,
 	)
 
@@ -332,6 +583,26 @@ func Test_GetScript_Validation(t *testing.T) {
 			expectedError: ErrUnsupportedScriptType,
 			createFile:    true,
 		},
+		{
+			name: "Missing Container Image",
+			config: &HookConfig{
+				Name:      "test6",
+				Shell:     ShellTypeBash,
+				Run:       "echo 'Hello'",
+				Container: &HookContainerConfig{},
+			},
+			expectedError: ErrContainerImageRequired,
+		},
+		{
+			name: "Invalid Condition",
+			config: &HookConfig{
+				Name:  "test7",
+				Shell: ShellTypeBash,
+				Run:   "echo 'Hello'",
+				When:  "env.VALUE = 'true'",
+			},
+			expectedError: ErrInvalidCondition,
+		},
 		{
 			name: "Valid External Script",
 			config: &HookConfig{
@@ -352,7 +623,7 @@ func Test_GetScript_Validation(t *testing.T) {
 
 	for _, test := range scriptValidations {
 		if test.createFile {
-			ensureScriptsExist(t, map[string]*HookConfig{"test": test.config})
+			ensureScriptsExist(t, HooksConfig{"test": {test.config}})
 		}
 
 		t.Run(test.name, func(t *testing.T) {
@@ -367,3 +638,15 @@ func Test_GetScript_Validation(t *testing.T) {
 		})
 	}
 }
+
+// Removes the hook output environment variable that is injected into every hook execution
+func withoutHookOutput(envVars []string) []string {
+	filtered := []string{}
+	for _, envVar := range envVars {
+		if !strings.HasPrefix(envVar, HookOutputEnvVarName+"=") {
+			filtered = append(filtered, envVar)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,212 @@
+package ext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var ErrHookDependencyCycle error = errors.New("hook dependencies contain a cycle")
+
+// HooksScheduler defers hooks until the lifecycle events they depend on have completed.
+//
+// Events are identified by a key (ex. `worker.postdeploy`). Hooks scheduled with dependencies run immediately when all
+// dependencies have already completed, otherwise they run as soon as the last dependency completes.
+// Hooks whose dependencies never complete, or fail, during the command are skipped and reported when the scheduler
+// is flushed.
+type HooksScheduler struct {
+	mu        sync.Mutex
+	completed map[string]struct{}
+	failed    map[string]struct{}
+	pending   []*scheduledHook
+}
+
+// A deferred hook that didn't run because its dependencies failed or didn't complete during the command
+type SkippedHook struct {
+	Key string
+	// Dependencies that failed while running
+	Failed []string
+	// Dependencies that never completed
+	Missing []string
+}
+
+type scheduledHook struct {
+	key       string
+	dependsOn []string
+	run       func(ctx context.Context) error
+}
+
+// Creates a new hooks scheduler
+func NewHooksScheduler() *HooksScheduler {
+	return &HooksScheduler{
+		completed: map[string]struct{}{},
+		failed:    map[string]struct{}{},
+	}
+}
+
+// Schedules the hook identified by key to run after all the dependency events have completed.
+// The key of the hook is marked completed after the hook has run.
+func (s *HooksScheduler) Schedule(
+	ctx context.Context,
+	key string,
+	dependsOn []string,
+	run func(ctx context.Context) error,
+) error {
+	hook := &scheduledHook{
+		key:       key,
+		dependsOn: dependsOn,
+		run:       run,
+	}
+
+	s.mu.Lock()
+	ready := s.isReady(hook)
+	if !ready {
+		log.Printf("deferring '%s' hook until '%s' completes\n", key, strings.Join(dependsOn, ","))
+		s.pending = append(s.pending, hook)
+	}
+	s.mu.Unlock()
+
+	if !ready {
+		return nil
+	}
+
+	return s.runHook(ctx, hook)
+}
+
+// Marks the event identified by key as completed and runs any pending hooks that are now ready
+func (s *HooksScheduler) Complete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	s.completed[key] = struct{}{}
+	ready := s.takeReady()
+	s.mu.Unlock()
+
+	for _, hook := range ready {
+		if err := s.runHook(ctx, hook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Removes all remaining pending hooks without running them and returns them along with their unmet dependencies.
+// This is used at the end of a command for hooks whose dependencies failed or did not run as part of the command.
+func (s *HooksScheduler) Flush() []SkippedHook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	skipped := []SkippedHook{}
+	for _, hook := range s.pending {
+		skippedHook := SkippedHook{Key: hook.key}
+		for _, dependency := range hook.dependsOn {
+			if _, has := s.completed[dependency]; has {
+				continue
+			}
+
+			if _, has := s.failed[dependency]; has {
+				skippedHook.Failed = append(skippedHook.Failed, dependency)
+			} else {
+				skippedHook.Missing = append(skippedHook.Missing, dependency)
+			}
+		}
+
+		log.Printf("skipping deferred '%s' hook since dependencies '%s' did not complete\n",
+			hook.key,
+			strings.Join(hook.dependsOn, ","),
+		)
+		skipped = append(skipped, skippedHook)
+	}
+
+	s.pending = nil
+	return skipped
+}
+
+func (s *HooksScheduler) runHook(ctx context.Context, hook *scheduledHook) error {
+	if err := hook.run(ctx); err != nil {
+		s.mu.Lock()
+		s.failed[hook.key] = struct{}{}
+		s.mu.Unlock()
+
+		return err
+	}
+
+	return s.Complete(ctx, hook.key)
+}
+
+// Removes and returns the pending hooks that have all dependencies completed.
+// Must be called while holding the lock.
+func (s *HooksScheduler) takeReady() []*scheduledHook {
+	ready := []*scheduledHook{}
+	remaining := []*scheduledHook{}
+
+	for _, hook := range s.pending {
+		if s.isReady(hook) {
+			ready = append(ready, hook)
+		} else {
+			remaining = append(remaining, hook)
+		}
+	}
+
+	s.pending = remaining
+	return ready
+}
+
+// Must be called while holding the lock.
+func (s *HooksScheduler) isReady(hook *scheduledHook) bool {
+	for _, dependency := range hook.dependsOn {
+		if _, has := s.completed[dependency]; !has {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validates that the dependency graph of the hooks does not contain any cycles.
+// The graph is a map of hook keys to the keys they depend on.
+func ValidateHookDependencies(graph map[string][]string) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	var visit func(key string, path []string) error
+	visit = func(key string, path []string) error {
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrHookDependencyCycle, strings.Join(append(path, key), " -> "))
+		case visited:
+			return nil
+		}
+
+		state[key] = visiting
+		for _, dependency := range graph[key] {
+			if err := visit(dependency, append(path, key)); err != nil {
+				return err
+			}
+		}
+		state[key] = visited
+
+		return nil
+	}
+
+	// Visit in a stable order for consistent error messages
+	keys := make([]string, 0, len(graph))
+	for key := range graph {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := visit(key, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package ext
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HooksScheduler(t *testing.T) {
+	t.Run("RunsImmediatelyWithoutDependencies", func(t *testing.T) {
+		ran := false
+		scheduler := NewHooksScheduler()
+
+		err := scheduler.Schedule(context.Background(), "api.postdeploy", nil, func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+
+	t.Run("DefersUntilDependencyCompletes", func(t *testing.T) {
+		log := []string{}
+		scheduler := NewHooksScheduler()
+
+		err := scheduler.Schedule(
+			context.Background(),
+			"api.postdeploy",
+			[]string{"worker.postdeploy"},
+			func(ctx context.Context) error {
+				log = append(log, "api")
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		require.Empty(t, log)
+
+		err = scheduler.Schedule(context.Background(), "worker.postdeploy", nil, func(ctx context.Context) error {
+			log = append(log, "worker")
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"worker", "api"}, log)
+	})
+
+	t.Run("ChainedDependencies", func(t *testing.T) {
+		log := []string{}
+		scheduler := NewHooksScheduler()
+		record := func(name string) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				log = append(log, name)
+				return nil
+			}
+		}
+
+		ctx := context.Background()
+		require.NoError(t, scheduler.Schedule(ctx, "web.postdeploy", []string{"api.postdeploy"}, record("web")))
+		require.NoError(t, scheduler.Schedule(ctx, "api.postdeploy", []string{"worker.postdeploy"}, record("api")))
+		require.NoError(t, scheduler.Complete(ctx, "worker.postdeploy"))
+
+		require.Equal(t, []string{"api", "web"}, log)
+	})
+
+	t.Run("FlushSkipsPendingHooks", func(t *testing.T) {
+		ran := false
+		scheduler := NewHooksScheduler()
+
+		err := scheduler.Schedule(
+			context.Background(),
+			"api.postdeploy",
+			[]string{"worker.postdeploy"},
+			func(ctx context.Context) error {
+				ran = true
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		require.False(t, ran)
+
+		skipped := scheduler.Flush()
+		require.False(t, ran)
+		require.Equal(t, []SkippedHook{{Key: "api.postdeploy", Missing: []string{"worker.postdeploy"}}}, skipped)
+		require.Empty(t, scheduler.Flush())
+	})
+
+	t.Run("FlushSkipsHooksWithFailedDependencies", func(t *testing.T) {
+		ran := false
+		scheduler := NewHooksScheduler()
+		ctx := context.Background()
+
+		require.NoError(t, scheduler.Schedule(
+			ctx,
+			"web.postdeploy",
+			[]string{"api.postdeploy", "worker.postdeploy"},
+			func(ctx context.Context) error {
+				ran = true
+				return nil
+			},
+		))
+		require.NoError(t, scheduler.Complete(ctx, "worker.postdeploy"))
+
+		err := scheduler.Schedule(ctx, "api.postdeploy", nil, func(ctx context.Context) error {
+			return errors.New("hook failed")
+		})
+		require.Error(t, err)
+
+		skipped := scheduler.Flush()
+		require.False(t, ran)
+		require.Equal(t, []SkippedHook{{Key: "web.postdeploy", Failed: []string{"api.postdeploy"}}}, skipped)
+	})
+
+	t.Run("DeferredHookError", func(t *testing.T) {
+		scheduler := NewHooksScheduler()
+
+		err := scheduler.Schedule(
+			context.Background(),
+			"api.postdeploy",
+			[]string{"worker.postdeploy"},
+			func(ctx context.Context) error {
+				return errors.New("hook failed")
+			},
+		)
+		require.NoError(t, err)
+
+		err = scheduler.Complete(context.Background(), "worker.postdeploy")
+		require.Error(t, err)
+	})
+}
+
+func Test_ValidateHookDependencies(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		err := ValidateHookDependencies(map[string][]string{
+			"api.postdeploy": {"worker.postdeploy"},
+			"web.postdeploy": {"api.postdeploy", "worker.postdeploy"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		err := ValidateHookDependencies(map[string][]string{
+			"api.postdeploy":    {"worker.postdeploy"},
+			"worker.postdeploy": {"web.postdeploy"},
+			"web.postdeploy":    {"api.postdeploy"},
+		})
+		require.ErrorIs(t, err, ErrHookDependencyCycle)
+	})
+}
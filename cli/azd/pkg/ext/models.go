@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
 )
 
 type ShellType string
@@ -16,6 +18,7 @@ type ScriptLocation string
 const (
 	ShellTypeBash         ShellType      = "sh"
 	ShellTypePowershell   ShellType      = "pwsh"
+	ShellTypePython       ShellType      = "python"
 	ScriptTypeUnknown     ShellType      = ""
 	ScriptLocationInline  ScriptLocation = "inline"
 	ScriptLocationPath    ScriptLocation = "path"
@@ -24,6 +27,12 @@ const (
 	HookTypePre HookType = "pre"
 	// Execute post hooks
 	HookTypePost HookType = "post"
+
+	// The name of the environment variable containing the path of the file where hooks can write `KEY=VALUE`
+	// outputs that are merged into the azd environment after the hook completes successfully.
+	HookOutputEnvVarName = "AZD_HOOK_OUTPUT"
+	// The path of the hook output file for hooks running within a container
+	containerHookOutputPath = "/azd/output.env"
 )
 
 var (
@@ -31,7 +40,10 @@ var (
 		"unable to determine script type. Ensure 'Shell' parameter is set in configuration options",
 	)
 	ErrRunRequired           error = errors.New("run is always required")
-	ErrUnsupportedScriptType error = errors.New("script type is not valid. Only '.sh' and '.ps1' are supported")
+	ErrUnsupportedScriptType error = errors.New(
+		"script type is not valid. Only '.sh', '.ps1' and '.py' are supported",
+	)
+	ErrContainerImageRequired error = errors.New("container image is required when running hooks within a container")
 )
 
 // Generic action function that may return an error
@@ -55,7 +67,7 @@ type HookConfig struct {
 
 	// Internal name of the hook running for a given command
 	Name string `yaml:",omitempty"`
-	// The type of script hook (bash or powershell)
+	// The type of script hook (bash, powershell or python)
 	Shell ShellType `yaml:"shell,omitempty"`
 	// The inline script to execute or path to existing file
 	Run string `yaml:"run,omitempty"`
@@ -67,6 +79,117 @@ type HookConfig struct {
 	Windows *HookConfig `yaml:"windows,omitempty"`
 	// When running on linux/macos use this override config
 	Posix *HookConfig `yaml:"posix,omitempty"`
+	// Service lifecycle events that must complete before this service hook runs. Only supported for post hooks.
+	// Supports `<service>` for the same event of another service or `<service>.<event>`.
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+	// When set the hook only runs when the condition expression evaluates to true
+	When string `yaml:"when,omitempty"`
+	// When set will run the hook within a container created from the configured image
+	Container *HookContainerConfig `yaml:"container,omitempty"`
+	// The working directory of the hook relative to the project or service.
+	// Defaults to the project or service directory.
+	WorkingDirectory string `yaml:"workingDirectory,omitempty"`
+}
+
+// HooksConfig is a map of hook names to the hooks that run for the event.
+// Within azure.yaml each hook name accepts either a single hook or a list of hooks that run in order.
+type HooksConfig map[string][]*HookConfig
+
+// UnmarshalYAML supports both a single hook and a list of hooks for each hook name
+func (hc *HooksConfig) UnmarshalYAML(value *yaml.Node) error {
+	var nodes map[string]yaml.Node
+	if err := value.Decode(&nodes); err != nil {
+		return err
+	}
+
+	hooks := HooksConfig{}
+	for name, node := range nodes {
+		if node.Kind == yaml.SequenceNode {
+			var hookList []*HookConfig
+			if err := node.Decode(&hookList); err != nil {
+				return fmt.Errorf("failed decoding hooks for '%s': %w", name, err)
+			}
+
+			hooks[name] = hookList
+			continue
+		}
+
+		var hook *HookConfig
+		if err := node.Decode(&hook); err != nil {
+			return fmt.Errorf("failed decoding hook for '%s': %w", name, err)
+		}
+
+		hooks[name] = []*HookConfig{hook}
+	}
+
+	*hc = hooks
+	return nil
+}
+
+// MarshalYAML writes hook names with a single hook as an object and multiple hooks as a list
+func (hc HooksConfig) MarshalYAML() (interface{}, error) {
+	if hc == nil {
+		return nil, nil
+	}
+
+	values := map[string]interface{}{}
+	for name, hookList := range hc {
+		if len(hookList) == 1 {
+			values[name] = hookList[0]
+		} else {
+			values[name] = hookList
+		}
+	}
+
+	return values, nil
+}
+
+// Configuration for hooks that run within a container
+type HookContainerConfig struct {
+	// The container image used to run the hook
+	Image string `yaml:"image,omitempty"`
+}
+
+// Gets the hook configuration for the current operating system.
+// Returns the `windows` or `posix` override when configured, otherwise the hook configuration itself.
+func (hc *HookConfig) ForCurrentOS() *HookConfig {
+	if runtime.GOOS == "windows" && hc.Windows != nil {
+		return hc.Windows
+	} else if (runtime.GOOS == "linux" || runtime.GOOS == "darwin") && hc.Posix != nil {
+		return hc.Posix
+	}
+
+	return hc
+}
+
+// Gets the shell used to run the hook.
+// When the shell is not configured it is inferred from the file extension of the script.
+// Returns ScriptTypeUnknown when the shell cannot be resolved.
+func (hc *HookConfig) ResolvedShell() ShellType {
+	if hc.Shell != ScriptTypeUnknown {
+		return hc.Shell
+	}
+
+	shell, err := inferScriptTypeFromFilePath(hc.Run)
+	if err != nil {
+		return ScriptTypeUnknown
+	}
+
+	return shell
+}
+
+// Infers the hook type and event name from a hook name. (ex. `predeploy` => `pre` & `deploy`)
+func InferHookType(name string) (HookType, string, error) {
+	// Validate name length so go doesn't PANIC for string slicing below
+	if len(name) < 4 {
+		return "", "", fmt.Errorf("unable to infer hook '%s'", name)
+	} else if name[:3] == "pre" {
+		return HookTypePre, name[3:], nil
+	} else if name[:4] == "post" {
+		return HookTypePost, name[4:], nil
+	}
+
+	return "", "", fmt.Errorf("unable to infer hook '%s'", name)
 }
 
 // Validates and normalizes the hook configuration
@@ -79,6 +202,16 @@ func (hc *HookConfig) validate() error {
 		return ErrRunRequired
 	}
 
+	if hc.Container != nil && hc.Container.Image == "" {
+		return ErrContainerImageRequired
+	}
+
+	if hc.When != "" {
+		if _, err := parseHookCondition(hc.When); err != nil {
+			return err
+		}
+	}
+
 	hc.Run = strings.ReplaceAll(hc.Run, "/", string(os.PathSeparator))
 
 	scriptPath := hc.Run
@@ -137,6 +270,8 @@ func inferScriptTypeFromFilePath(path string) (ShellType, error) {
 		return ShellTypeBash, nil
 	case ".ps1":
 		return ShellTypePowershell, nil
+	case ".py":
+		return ShellTypePython, nil
 	default:
 		return "", fmt.Errorf(
 			"script with file extension '%s' is not valid. %w.",
@@ -166,6 +301,8 @@ func createTempScript(hookConfig *HookConfig) (string, error) {
 		scriptFooter = []string{
 			"if ((Test-Path -LiteralPath variable:\\LASTEXITCODE)) { exit $LASTEXITCODE }",
 		}
+	case ShellTypePython:
+		ext = "py"
 	}
 
 	// Write the temporary script file to OS temp dir
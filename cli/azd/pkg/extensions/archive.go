@@ -0,0 +1,150 @@
+package extensions
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// Extracts the .zip or .tar.gz archive into the target directory
+func extractArchive(name string, archive []byte, targetDir string) error {
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return extractZip(archive, targetDir)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return extractTarGz(archive, targetDir)
+	default:
+		return fmt.Errorf("unsupported archive '%s'. Supported archives are .zip and .tar.gz", name)
+	}
+}
+
+func extractZip(archive []byte, targetDir string) error {
+	zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range zipReader.File {
+		targetPath, err := archiveTargetPath(targetDir, file.Name)
+		if err != nil {
+			return err
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(targetPath, osutil.PermissionDirectory); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fileReader, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		err = writeArchiveFile(targetPath, fileReader, file.Mode())
+		fileReader.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractTarGz(archive []byte, targetDir string) error {
+	gzReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		targetPath, err := archiveTargetPath(targetDir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		// cspell: disable-next-line `Typeflag` is comming fron *tar.Header
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(targetPath, osutil.PermissionDirectory); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(targetPath, tarReader, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Ensures archive entries can't be written outside of the target directory
+func archiveTargetPath(targetDir string, name string) (string, error) {
+	root := filepath.Clean(targetDir)
+	targetPath := filepath.Join(targetDir, filepath.FromSlash(name))
+	// Archives created from within the extension directory (ex. `tar -czf ext.tar.gz .`) contain a `./` root entry
+	if targetPath != root && !strings.HasPrefix(targetPath, root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry '%s' is outside of the extension directory", name)
+	}
+
+	return targetPath, nil
+}
+
+func writeArchiveFile(targetPath string, reader io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	/* #nosec G110 - decompression bomb false positive */
+	_, err = io.Copy(file, reader)
+	return err
+}
+
+// Finds the directory containing the extension manifest.
+// Archives may contain the manifest at the root or within a single top level directory.
+func findManifestDir(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ManifestFileName)); err == nil {
+		return dir, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(entries) == 1 && entries[0].IsDir() {
+		nested := filepath.Join(dir, entries[0].Name())
+		if _, err := os.Stat(filepath.Join(nested, ManifestFileName)); err == nil {
+			return nested, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: archive does not contain %s", ErrInvalidManifest, ManifestFileName)
+}
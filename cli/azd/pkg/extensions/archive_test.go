@@ -0,0 +1,74 @@
+package extensions
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExtractArchive_TarGz(t *testing.T) {
+	t.Run("DotPrefixedEntries", func(t *testing.T) {
+		targetDir := t.TempDir()
+		archive := createTarGz(t, map[string]string{
+			"./":                "",
+			"./extension.yaml":  "name: contoso",
+			"./bin/":            "",
+			"./bin/contoso.exe": "binary",
+		})
+
+		err := extractArchive("contoso.tar.gz", archive, targetDir)
+		require.NoError(t, err)
+
+		contents, err := os.ReadFile(filepath.Join(targetDir, "extension.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, "name: contoso", string(contents))
+		require.FileExists(t, filepath.Join(targetDir, "bin", "contoso.exe"))
+	})
+
+	t.Run("OutsideTargetDir", func(t *testing.T) {
+		archive := createTarGz(t, map[string]string{
+			"../extension.yaml": "name: contoso",
+		})
+
+		err := extractArchive("contoso.tar.gz", archive, t.TempDir())
+		require.Error(t, err)
+	})
+}
+
+// Creates a .tar.gz archive from the entries, names ending in `/` are added as directories
+func createTarGz(t *testing.T, entries map[string]string) []byte {
+	buffer := bytes.Buffer{}
+	gzWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	// Directories must be written before the files they contain
+	names := []string{}
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		contents := entries[name]
+		header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(contents)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(name, "/") {
+			header = &tar.Header{Name: name, Mode: 0755, Typeflag: tar.TypeDir}
+		}
+
+		require.NoError(t, tarWriter.WriteHeader(header))
+		_, err := tarWriter.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzWriter.Close())
+
+	return buffer.Bytes()
+}
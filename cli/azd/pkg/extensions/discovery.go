@@ -0,0 +1,165 @@
+package extensions
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// The prefix of the executables surfaced as azd commands. (ex. `azd-hello` on PATH is surfaced as `azd hello`)
+const CommandPrefix = "azd-"
+
+// The user config path of the extension commands declared by name. (ex. `extensions.commands.hello: /tools/hello`)
+const CommandsConfigPath = "extensions.commands"
+
+// Where an extension was found
+const (
+	// Installed within the azd extensions directory
+	OriginInstalled = ""
+	// An `azd-<name>` executable found on PATH
+	OriginPath = "path"
+	// An executable declared within the user config
+	OriginConfig = "config"
+)
+
+// Executables found on PATH or declared within the config don't have a manifest. The user chose to run them, like
+// any other command, so they can read the environment and acquire tokens without declaring permissions.
+var commandPermissions = []string{PermissionEnvironmentRead, PermissionTokens}
+
+// Finds the `azd-<name>` executables on PATH, in the order of the directories of PATH.
+// Executables with a name that isn't a valid extension name are skipped, as are names found earlier.
+func DiscoverPathCommands(path string) []*Extension {
+	extensions := []*Extension{}
+	found := map[string]struct{}{}
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := commandName(entry.Name())
+			if !ok || entry.IsDir() {
+				continue
+			}
+
+			if _, has := found[name]; has {
+				continue
+			}
+
+			entryPoint := filepath.Join(dir, entry.Name())
+			if !isExecutable(entryPoint) {
+				continue
+			}
+
+			found[name] = struct{}{}
+			extensions = append(extensions, newCommandExtension(name, entryPoint, OriginPath))
+		}
+	}
+
+	return extensions
+}
+
+// Gets the extension commands declared within the user config, sorted by name.
+// The value of each command is the path of its executable.
+func DeclaredCommands(declared map[string]any) []*Extension {
+	extensions := []*Extension{}
+
+	for name, value := range declared {
+		entryPoint, ok := value.(string)
+		if !ok || entryPoint == "" {
+			log.Printf("skipping extension command '%s': the path of the executable must be a string\n", name)
+			continue
+		}
+
+		if !extensionNameRegex.MatchString(name) {
+			log.Printf("skipping extension command '%s': names must only contain lower case letters, numbers and "+
+				"hyphens\n", name)
+			continue
+		}
+
+		absolutePath, err := filepath.Abs(entryPoint)
+		if err != nil || !isExecutable(absolutePath) {
+			log.Printf("skipping extension command '%s': '%s' is not an executable\n", name, entryPoint)
+			continue
+		}
+
+		extensions = append(extensions, newCommandExtension(name, absolutePath, OriginConfig))
+	}
+
+	sort.Slice(extensions, func(i, j int) bool {
+		return extensions[i].Name < extensions[j].Name
+	})
+
+	return extensions
+}
+
+func newCommandExtension(name string, entryPoint string, origin string) *Extension {
+	return &Extension{
+		Manifest: Manifest{
+			Name:        name,
+			Description: fmt.Sprintf("Runs %s.", filepath.Base(entryPoint)),
+			EntryPoint:  filepath.Base(entryPoint),
+			Permissions: commandPermissions,
+		},
+		Path:   filepath.Dir(entryPoint),
+		Origin: origin,
+	}
+}
+
+// Gets the name of the command of an `azd-<name>` executable. On Windows, the extension of the executable is removed.
+func commandName(fileName string) (string, bool) {
+	name, has := strings.CutPrefix(fileName, CommandPrefix)
+	if !has {
+		return "", false
+	}
+
+	if runtime.GOOS == "windows" {
+		extension := filepath.Ext(name)
+		if !isWindowsExecutableExtension(extension) {
+			return "", false
+		}
+
+		name = strings.TrimSuffix(name, extension)
+	}
+
+	return name, extensionNameRegex.MatchString(name)
+}
+
+func isExecutable(path string) bool {
+	stat, err := os.Stat(path)
+	if err != nil || stat.IsDir() {
+		return false
+	}
+
+	// Windows doesn't have an executable bit, executables are identified by their extension
+	if runtime.GOOS == "windows" {
+		return isWindowsExecutableExtension(filepath.Ext(path))
+	}
+
+	return stat.Mode()&0111 != 0
+}
+
+func isWindowsExecutableExtension(extension string) bool {
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = ".COM;.EXE;.BAT;.CMD"
+	}
+
+	for _, executableExtension := range filepath.SplitList(pathExt) {
+		if extension != "" && strings.EqualFold(extension, executableExtension) {
+			return true
+		}
+	}
+
+	return false
+}
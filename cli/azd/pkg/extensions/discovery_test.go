@@ -0,0 +1,76 @@
+package extensions
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiscoverPathCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executables are identified by their extension on Windows")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+
+	writeExecutable(t, filepath.Join(first, "azd-hello"), osutil.PermissionExecutableFile)
+	writeExecutable(t, filepath.Join(second, "azd-hello"), osutil.PermissionExecutableFile)
+	writeExecutable(t, filepath.Join(second, "azd-deploy-preview"), osutil.PermissionExecutableFile)
+	// Not executable
+	writeExecutable(t, filepath.Join(second, "azd-notes"), osutil.PermissionFile)
+	// Not a valid extension name
+	writeExecutable(t, filepath.Join(second, "azd-Hello_World"), osutil.PermissionExecutableFile)
+	// Not an azd command
+	writeExecutable(t, filepath.Join(second, "hello"), osutil.PermissionExecutableFile)
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	path := strings.Join([]string{first, missing, second}, string(os.PathListSeparator))
+
+	commands := DiscoverPathCommands(path)
+	require.Len(t, commands, 2)
+
+	// The first executable found on PATH wins
+	require.Equal(t, "hello", commands[0].Name)
+	require.Equal(t, filepath.Join(first, "azd-hello"), commands[0].EntryPointPath())
+	require.Equal(t, OriginPath, commands[0].Origin)
+	require.True(t, commands[0].HasPermission(PermissionEnvironmentRead))
+	require.True(t, commands[0].HasPermission(PermissionTokens))
+	require.False(t, commands[0].HasPermission(PermissionEnvironmentWrite))
+
+	require.Equal(t, "deploy-preview", commands[1].Name)
+}
+
+func Test_DeclaredCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executables are identified by their extension on Windows")
+	}
+
+	dir := t.TempDir()
+	writeExecutable(t, filepath.Join(dir, "hello.sh"), osutil.PermissionExecutableFile)
+	writeExecutable(t, filepath.Join(dir, "notes.txt"), osutil.PermissionFile)
+
+	commands := DeclaredCommands(map[string]any{
+		"hello":   filepath.Join(dir, "hello.sh"),
+		"bye":     filepath.Join(dir, "hello.sh"),
+		"notes":   filepath.Join(dir, "notes.txt"),
+		"missing": filepath.Join(dir, "missing.sh"),
+		"Invalid": filepath.Join(dir, "hello.sh"),
+		"number":  1,
+	})
+
+	require.Len(t, commands, 2)
+	require.Equal(t, "bye", commands[0].Name)
+	require.Equal(t, "hello", commands[1].Name)
+	require.Equal(t, filepath.Join(dir, "hello.sh"), commands[1].EntryPointPath())
+	require.Equal(t, OriginConfig, commands[1].Origin)
+}
+
+func writeExecutable(t *testing.T, path string, perm os.FileMode) {
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho hello\n"), perm))
+}
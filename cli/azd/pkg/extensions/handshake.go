@@ -0,0 +1,102 @@
+package extensions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// The environment variable with the path of the handshake file azd writes before running an extension command.
+// The file is removed once the extension exits.
+const HandshakeEnvVarName = "AZD_EXTENSION_HANDSHAKE"
+
+// The version of the handshake written by this version of azd. Fields are only added within a version.
+const HandshakeVersion = "1.0"
+
+// Handshake is the JSON document describing the context an extension command runs in. (ex. `azd hello`)
+// See docs/extension-commands.md for the documented format.
+type Handshake struct {
+	Version string `json:"version"`
+	// The azd command the extension runs as. (ex. `azd hello`)
+	Command string `json:"command"`
+	// The arguments passed to the extension, including flags
+	Args []string `json:"args"`
+	// Whether azd runs with --debug, extensions should write diagnostics to stderr
+	Debug bool `json:"debug"`
+	// Whether azd runs with --no-prompt, extensions shouldn't prompt the user
+	NoPrompt bool `json:"noPrompt"`
+	// The W3C trace context of the azd command, extensions link their telemetry to it. (ex. `00-{trace}-{span}-01`)
+	TraceParent string `json:"traceparent,omitempty"`
+	// The current azd environment, only set for extensions with the environment.read permission
+	Environment *HandshakeEnvironment `json:"environment,omitempty"`
+	// The Azure account, only set for extensions with the tokens permission
+	Auth *HandshakeAuth `json:"auth,omitempty"`
+	// The extension host extensions call back into while they run
+	Host HandshakeHost `json:"host"`
+}
+
+// HandshakeEnvironment is the azd environment an extension command runs with
+type HandshakeEnvironment struct {
+	Name   string            `json:"name"`
+	Values map[string]string `json:"values"`
+}
+
+// HandshakeAuth describes the Azure account of the environment.
+// Access tokens are never written to the handshake, extensions acquire them from the extension host.
+type HandshakeAuth struct {
+	SubscriptionId string `json:"subscriptionId,omitempty"`
+	TenantId       string `json:"tenantId,omitempty"`
+}
+
+// HandshakeHost is the address of the extension host and the access token sent with each call
+type HandshakeHost struct {
+	Address     string `json:"address"`
+	AccessToken string `json:"accessToken"`
+}
+
+// Writes the handshake to a file only readable by the current user.
+// Returns the environment variable pointing the extension to the file, and a function removing the file.
+func WriteHandshake(handshake *Handshake) (string, func(), error) {
+	contents, err := json.Marshal(handshake)
+	if err != nil {
+		return "", nil, fmt.Errorf("marshalling extension handshake: %w", err)
+	}
+
+	// CreateTemp creates the file with 0600 permissions
+	file, err := os.CreateTemp("", "azd-handshake-*.json")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating extension handshake: %w", err)
+	}
+	defer file.Close()
+
+	remove := func() {
+		_ = os.Remove(file.Name())
+	}
+
+	if _, err := file.Write(contents); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("writing extension handshake: %w", err)
+	}
+
+	return fmt.Sprintf("%s=%s", HandshakeEnvVarName, file.Name()), remove, nil
+}
+
+// Reads the handshake written by azd, used by extensions written in Go
+func ReadHandshakeFromEnv() (*Handshake, error) {
+	path := os.Getenv(HandshakeEnvVarName)
+	if path == "" {
+		return nil, fmt.Errorf("%s is not set. The extension must be run by azd", HandshakeEnvVarName)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading extension handshake: %w", err)
+	}
+
+	var handshake Handshake
+	if err := json.Unmarshal(contents, &handshake); err != nil {
+		return nil, fmt.Errorf("parsing extension handshake: %w", err)
+	}
+
+	return &handshake, nil
+}
@@ -0,0 +1,48 @@
+package extensions
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Handshake(t *testing.T) {
+	handshake := &Handshake{
+		Version: HandshakeVersion,
+		Command: "azd hello",
+		Args:    []string{"world", "--debug"},
+		Debug:   true,
+		Environment: &HandshakeEnvironment{
+			Name:   "dev",
+			Values: map[string]string{"AZURE_ENV_NAME": "dev"},
+		},
+		Auth: &HandshakeAuth{SubscriptionId: "SUBSCRIPTION_ID", TenantId: "TENANT_ID"},
+		Host: HandshakeHost{Address: "127.0.0.1:5432", AccessToken: "TOKEN"},
+	}
+
+	envVar, remove, err := WriteHandshake(handshake)
+	require.NoError(t, err)
+
+	name, path, _ := strings.Cut(envVar, "=")
+	require.Equal(t, HandshakeEnvVarName, name)
+
+	if runtime.GOOS != "windows" {
+		stat, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+	}
+
+	t.Setenv(HandshakeEnvVarName, path)
+	read, err := ReadHandshakeFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, handshake, read)
+
+	remove()
+	require.NoFileExists(t, path)
+
+	_, err = ReadHandshakeFromEnv()
+	require.Error(t, err)
+}
@@ -0,0 +1,365 @@
+package extensions
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// HostOptions configures the extension host started for an extension invocation
+type HostOptions struct {
+	// The capabilities that can be granted to the extension. Defaults to the capabilities allowed by the permissions
+	// of the extension.
+	AllowedCapabilities []string
+	// Gets the credential used to acquire access tokens for extensions with the tokens capability
+	Credential func(ctx context.Context) (azcore.TokenCredential, error)
+}
+
+// Host is the gRPC server extensions call back into while they run.
+// Extensions negotiate a protocol version and capabilities before calling any other method.
+type Host struct {
+	extension *Extension
+	console   input.Console
+	env       *environment.Environment
+	options   HostOptions
+
+	server   *grpc.Server
+	listener net.Listener
+	token    string
+
+	mu         sync.Mutex
+	negotiated *NegotiateResponse
+}
+
+// Creates a new extension host for the extension.
+// The environment is optional and environment capabilities fail when it is nil.
+func NewHost(
+	extension *Extension,
+	console input.Console,
+	env *environment.Environment,
+	options HostOptions,
+) *Host {
+	if options.AllowedCapabilities == nil {
+		options.AllowedCapabilities = extension.AllowedCapabilities()
+	}
+
+	return &Host{
+		extension: extension,
+		console:   console,
+		env:       env,
+		options:   options,
+	}
+}
+
+// Starts the extension host on a local port.
+// Returns the environment variables the extension uses to connect to the host.
+func (h *Host) Start() ([]string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("generating extension host access token: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("starting extension host: %w", err)
+	}
+
+	h.token = hex.EncodeToString(tokenBytes)
+	h.listener = listener
+	h.server = grpc.NewServer(
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.UnaryInterceptor(h.authorizeUnary),
+		grpc.StreamInterceptor(h.authorizeStream),
+	)
+	h.server.RegisterService(&hostServiceDesc, h)
+
+	go func() {
+		if err := h.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Printf("extension host for '%s' stopped: %v\n", h.extension.Name, err)
+		}
+	}()
+
+	return []string{
+		fmt.Sprintf("%s=%s", HostAddressEnvVarName, listener.Addr().String()),
+		fmt.Sprintf("%s=%s", HostAccessTokenEnvVarName, h.token),
+	}, nil
+}
+
+// Stops the extension host
+func (h *Host) Stop() {
+	if h.server != nil {
+		h.server.Stop()
+	}
+}
+
+func (h *Host) negotiate(ctx context.Context, request *NegotiateRequest) (*NegotiateResponse, error) {
+	var protocolVersion string
+	for _, version := range HostProtocolVersions {
+		if slices.Contains(request.ProtocolVersions, version) {
+			protocolVersion = version
+			break
+		}
+	}
+
+	if protocolVersion == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"none of the protocol versions %v are supported by azd. Supported versions: %v",
+			request.ProtocolVersions,
+			HostProtocolVersions,
+		)
+	}
+
+	// Capabilities that aren't supported or allowed are not granted, extensions decide whether they can continue
+	granted := []string{}
+	for _, capability := range request.Capabilities {
+		if slices.Contains(HostCapabilities, capability) && slices.Contains(h.options.AllowedCapabilities, capability) {
+			granted = append(granted, capability)
+		} else {
+			log.Printf("extension '%s' was not granted capability '%s'\n", h.extension.Name, capability)
+		}
+	}
+
+	response := &NegotiateResponse{
+		ProtocolVersion: protocolVersion,
+		Capabilities:    granted,
+	}
+
+	h.mu.Lock()
+	h.negotiated = response
+	h.mu.Unlock()
+
+	return response, nil
+}
+
+func (h *Host) getEnvironment(ctx context.Context, request *GetEnvironmentRequest) (*GetEnvironmentResponse, error) {
+	if err := h.ensureEnvironment(CapabilityEnvironmentRead); err != nil {
+		return nil, err
+	}
+
+	return &GetEnvironmentResponse{
+		Name:   h.env.GetEnvName(),
+		Values: h.env.Dotenv(),
+	}, nil
+}
+
+func (h *Host) getValue(ctx context.Context, request *GetValueRequest) (*GetValueResponse, error) {
+	if err := h.ensureEnvironment(CapabilityEnvironmentRead); err != nil {
+		return nil, err
+	}
+
+	value, found := h.env.LookupEnv(request.Key)
+	return &GetValueResponse{
+		Key:   request.Key,
+		Value: value,
+		Found: found,
+	}, nil
+}
+
+func (h *Host) setValue(ctx context.Context, request *SetValueRequest) (*SetValueResponse, error) {
+	if err := h.ensureEnvironment(CapabilityEnvironmentWrite); err != nil {
+		return nil, err
+	}
+
+	if request.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	h.env.DotenvSet(request.Key, request.Value)
+	if err := h.env.Save(); err != nil {
+		return nil, status.Errorf(codes.Internal, "saving environment: %v", err)
+	}
+
+	return &SetValueResponse{}, nil
+}
+
+func (h *Host) getAccessToken(ctx context.Context, request *GetAccessTokenRequest) (*GetAccessTokenResponse, error) {
+	if err := h.ensureCapability(CapabilityTokens); err != nil {
+		return nil, err
+	}
+
+	if len(request.Scopes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one scope is required")
+	}
+
+	if h.options.Credential == nil {
+		return nil, status.Error(codes.Unavailable, "access tokens are not available")
+	}
+
+	credential, err := h.options.Credential(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "getting credential: %v", err)
+	}
+
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: request.Scopes})
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "fetching token: %v", err)
+	}
+
+	return &GetAccessTokenResponse{
+		Token:     token.Token,
+		ExpiresOn: token.ExpiresOn,
+	}, nil
+}
+
+// Handles progress & prompts sent by the extension until the extension closes the stream
+func (h *Host) session(stream grpc.ServerStream) error {
+	ctx := stream.Context()
+
+	for {
+		message := &SessionMessage{}
+		err := stream.RecvMsg(message)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if message.Progress != nil {
+			if err := h.ensureCapability(CapabilityProgress); err != nil {
+				return err
+			}
+
+			h.console.Message(ctx, message.Progress.Message)
+		}
+
+		if message.Prompt != nil {
+			if err := h.ensureCapability(CapabilityPrompt); err != nil {
+				return err
+			}
+
+			response := &PromptResponse{Id: message.Prompt.Id}
+			value, err := h.prompt(ctx, message.Prompt)
+			if err != nil {
+				response.Error = err.Error()
+			}
+
+			response.Value = value
+			if err := stream.SendMsg(&HostMessage{PromptResponse: response}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (h *Host) prompt(ctx context.Context, request *PromptRequest) (string, error) {
+	options := input.ConsoleOptions{
+		Message: request.Message,
+		Options: request.Options,
+	}
+
+	switch request.Type {
+	case PromptTypeConfirm:
+		defaultValue, _ := strconv.ParseBool(request.DefaultValue)
+		options.DefaultValue = defaultValue
+
+		confirmed, err := h.console.Confirm(ctx, options)
+		return strconv.FormatBool(confirmed), err
+	case PromptTypeSelect:
+		if request.DefaultValue != "" {
+			options.DefaultValue = request.DefaultValue
+		}
+
+		selected, err := h.console.Select(ctx, options)
+		if err != nil {
+			return "", err
+		}
+
+		return request.Options[selected], nil
+	case PromptTypeString, "":
+		if request.DefaultValue != "" {
+			options.DefaultValue = request.DefaultValue
+		}
+
+		return h.console.Prompt(ctx, options)
+	default:
+		return "", fmt.Errorf("unsupported prompt type '%s'", request.Type)
+	}
+}
+
+func (h *Host) ensureEnvironment(capability string) error {
+	if err := h.ensureCapability(capability); err != nil {
+		return err
+	}
+
+	if h.env == nil {
+		return status.Error(codes.FailedPrecondition, "no azd environment is available")
+	}
+
+	return nil
+}
+
+// Ensures the extension negotiated the capability before using it
+func (h *Host) ensureCapability(capability string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.negotiated == nil {
+		return status.Error(codes.FailedPrecondition, "Negotiate must be called before any other method")
+	}
+
+	if !slices.Contains(h.negotiated.Capabilities, capability) {
+		return status.Errorf(codes.PermissionDenied, "capability '%s' was not granted to the extension", capability)
+	}
+
+	return nil
+}
+
+func (h *Host) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, has := strings.CutPrefix(value, "Bearer ")
+		if has && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid extension host access token")
+}
+
+func (h *Host) authorizeUnary(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if err := h.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (h *Host) authorizeStream(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := h.authorize(stream.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
@@ -0,0 +1,359 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// The environment variables azd sets for extensions to connect to the extension host
+const (
+	// The address of the extension host gRPC server. (ex. `127.0.0.1:5432`)
+	HostAddressEnvVarName = "AZD_SERVER"
+	// The access token extensions send with each call to the extension host
+	HostAccessTokenEnvVarName = "AZD_ACCESS_TOKEN"
+)
+
+// The versions of the extension host protocol supported by azd, ordered from newest to oldest
+var HostProtocolVersions = []string{"1.0"}
+
+// Capabilities extensions negotiate with the extension host
+const (
+	// Read the values of the current azd environment
+	CapabilityEnvironmentRead = "environment.read"
+	// Write values to the current azd environment
+	CapabilityEnvironmentWrite = "environment.write"
+	// Report progress displayed by azd
+	CapabilityProgress = "progress"
+	// Prompt the user through azd
+	CapabilityPrompt = "prompt"
+	// Acquire Azure access tokens for the logged in account
+	CapabilityTokens = "tokens"
+)
+
+// The capabilities supported by this version of the extension host
+var HostCapabilities = []string{
+	CapabilityEnvironmentRead,
+	CapabilityEnvironmentWrite,
+	CapabilityProgress,
+	CapabilityPrompt,
+	CapabilityTokens,
+}
+
+// The name of the gRPC service implemented by the extension host
+const hostServiceName = "azd.extensions.v1.Host"
+
+// NegotiateRequest is sent by an extension before any other call to agree on a protocol version & capabilities
+type NegotiateRequest struct {
+	// The protocol versions supported by the extension
+	ProtocolVersions []string `json:"protocolVersions"`
+	// The capabilities requested by the extension
+	Capabilities []string `json:"capabilities"`
+}
+
+// NegotiateResponse contains the selected protocol version and the capabilities granted to the extension
+type NegotiateResponse struct {
+	ProtocolVersion string   `json:"protocolVersion"`
+	Capabilities    []string `json:"capabilities"`
+}
+
+type GetEnvironmentRequest struct{}
+
+type GetEnvironmentResponse struct {
+	Name   string            `json:"name"`
+	Values map[string]string `json:"values"`
+}
+
+type GetValueRequest struct {
+	Key string `json:"key"`
+}
+
+type GetValueResponse struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Found bool   `json:"found"`
+}
+
+type SetValueRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type SetValueResponse struct{}
+
+type GetAccessTokenRequest struct {
+	Scopes []string `json:"scopes"`
+}
+
+type GetAccessTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresOn time.Time `json:"expiresOn"`
+}
+
+// The types of prompts extensions can display
+const (
+	PromptTypeString  = "string"
+	PromptTypeConfirm = "confirm"
+	PromptTypeSelect  = "select"
+)
+
+// SessionMessage is streamed from the extension to the extension host
+type SessionMessage struct {
+	Progress *ProgressMessage `json:"progress,omitempty"`
+	Prompt   *PromptRequest   `json:"prompt,omitempty"`
+}
+
+// HostMessage is streamed from the extension host to the extension
+type HostMessage struct {
+	PromptResponse *PromptResponse `json:"promptResponse,omitempty"`
+}
+
+type ProgressMessage struct {
+	Message string `json:"message"`
+}
+
+type PromptRequest struct {
+	// Correlates the response with the request
+	Id           string   `json:"id"`
+	Type         string   `json:"type"`
+	Message      string   `json:"message"`
+	Options      []string `json:"options,omitempty"`
+	DefaultValue string   `json:"defaultValue,omitempty"`
+}
+
+type PromptResponse struct {
+	Id    string `json:"id"`
+	Value string `json:"value"`
+	Error string `json:"error,omitempty"`
+}
+
+// jsonCodec encodes gRPC messages as JSON so extensions don't require generated protobuf types.
+// The wire format is documented in docs/extension-host-protocol.md.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+// hostService is implemented by the extension host
+type hostService interface {
+	negotiate(ctx context.Context, request *NegotiateRequest) (*NegotiateResponse, error)
+	getEnvironment(ctx context.Context, request *GetEnvironmentRequest) (*GetEnvironmentResponse, error)
+	getValue(ctx context.Context, request *GetValueRequest) (*GetValueResponse, error)
+	setValue(ctx context.Context, request *SetValueRequest) (*SetValueResponse, error)
+	getAccessToken(ctx context.Context, request *GetAccessTokenRequest) (*GetAccessTokenResponse, error)
+	session(stream grpc.ServerStream) error
+}
+
+var sessionStreamDesc = grpc.StreamDesc{
+	StreamName:    "Session",
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+var hostServiceDesc = grpc.ServiceDesc{
+	ServiceName: hostServiceName,
+	HandlerType: (*hostService)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("Negotiate", hostService.negotiate),
+		unaryMethod("GetEnvironment", hostService.getEnvironment),
+		unaryMethod("GetValue", hostService.getValue),
+		unaryMethod("SetValue", hostService.setValue),
+		unaryMethod("GetAccessToken", hostService.getAccessToken),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    sessionStreamDesc.StreamName,
+			ServerStreams: sessionStreamDesc.ServerStreams,
+			ClientStreams: sessionStreamDesc.ClientStreams,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				return srv.(hostService).session(stream)
+			},
+		},
+	},
+}
+
+// Creates the gRPC method description for a unary host method
+func unaryMethod[Req any, Res any](
+	name string,
+	fn func(hostService, context.Context, *Req) (*Res, error),
+) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			request := new(Req)
+			if err := dec(request); err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return fn(srv.(hostService), ctx, request)
+			}
+
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: fmt.Sprintf("/%s/%s", hostServiceName, name),
+			}
+
+			return interceptor(ctx, request, info, func(ctx context.Context, req any) (any, error) {
+				return fn(srv.(hostService), ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+// HostClient is used by extensions written in Go to call the extension host
+type HostClient struct {
+	conn  *grpc.ClientConn
+	token string
+}
+
+// Creates a client connected to the extension host using the environment variables set by azd
+func NewHostClientFromEnv(ctx context.Context) (*HostClient, error) {
+	address := os.Getenv(HostAddressEnvVarName)
+	if address == "" {
+		return nil, fmt.Errorf("%s is not set. The extension must be run by azd", HostAddressEnvVarName)
+	}
+
+	return NewHostClient(ctx, address, os.Getenv(HostAccessTokenEnvVarName))
+}
+
+// Creates a client connected to the extension host at the specified address
+func NewHostClient(ctx context.Context, address string, token string) (*HostClient, error) {
+	conn, err := grpc.DialContext(
+		ctx,
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to extension host: %w", err)
+	}
+
+	return &HostClient{
+		conn:  conn,
+		token: token,
+	}, nil
+}
+
+// Closes the connection to the extension host
+func (c *HostClient) Close() error {
+	return c.conn.Close()
+}
+
+// Negotiates the protocol version & capabilities with the extension host
+func (c *HostClient) Negotiate(ctx context.Context, request *NegotiateRequest) (*NegotiateResponse, error) {
+	response := &NegotiateResponse{}
+	return response, c.invoke(ctx, "Negotiate", request, response)
+}
+
+// Gets the values of the current azd environment
+func (c *HostClient) GetEnvironment(ctx context.Context) (*GetEnvironmentResponse, error) {
+	response := &GetEnvironmentResponse{}
+	return response, c.invoke(ctx, "GetEnvironment", &GetEnvironmentRequest{}, response)
+}
+
+// Gets a value of the current azd environment
+func (c *HostClient) GetValue(ctx context.Context, key string) (*GetValueResponse, error) {
+	response := &GetValueResponse{}
+	return response, c.invoke(ctx, "GetValue", &GetValueRequest{Key: key}, response)
+}
+
+// Sets & saves a value of the current azd environment
+func (c *HostClient) SetValue(ctx context.Context, key string, value string) error {
+	return c.invoke(ctx, "SetValue", &SetValueRequest{Key: key, Value: value}, &SetValueResponse{})
+}
+
+// Gets an Azure access token for the logged in account
+func (c *HostClient) GetAccessToken(ctx context.Context, scopes []string) (*GetAccessTokenResponse, error) {
+	response := &GetAccessTokenResponse{}
+	return response, c.invoke(ctx, "GetAccessToken", &GetAccessTokenRequest{Scopes: scopes}, response)
+}
+
+// Opens a session used to report progress and prompt the user
+func (c *HostClient) Session(ctx context.Context) (*HostSession, error) {
+	stream, err := c.conn.NewStream(
+		c.withToken(ctx),
+		&sessionStreamDesc,
+		fmt.Sprintf("/%s/%s", hostServiceName, sessionStreamDesc.StreamName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostSession{stream: stream}, nil
+}
+
+func (c *HostClient) invoke(ctx context.Context, method string, request any, response any) error {
+	return c.conn.Invoke(c.withToken(ctx), fmt.Sprintf("/%s/%s", hostServiceName, method), request, response)
+}
+
+func (c *HostClient) withToken(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+}
+
+// HostSession is a stream between an extension and the extension host
+type HostSession struct {
+	stream   grpc.ClientStream
+	promptId int
+}
+
+// Reports progress displayed by azd
+func (s *HostSession) Progress(message string) error {
+	return s.stream.SendMsg(&SessionMessage{Progress: &ProgressMessage{Message: message}})
+}
+
+// Prompts the user through azd and waits for the response
+func (s *HostSession) Prompt(request *PromptRequest) (string, error) {
+	s.promptId++
+	request.Id = fmt.Sprint(s.promptId)
+
+	if err := s.stream.SendMsg(&SessionMessage{Prompt: request}); err != nil {
+		return "", err
+	}
+
+	message := &HostMessage{}
+	if err := s.stream.RecvMsg(message); err != nil {
+		return "", err
+	}
+
+	if message.PromptResponse == nil || message.PromptResponse.Id != request.Id {
+		return "", errors.New("unexpected response from extension host")
+	}
+
+	if message.PromptResponse.Error != "" {
+		return "", errors.New(message.PromptResponse.Error)
+	}
+
+	return message.PromptResponse.Value, nil
+}
+
+// Closes the session
+func (s *HostSession) Close() error {
+	if err := s.stream.CloseSend(); err != nil {
+		return err
+	}
+
+	// Wait for the host to end the stream
+	if err := s.stream.RecvMsg(&HostMessage{}); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package extensions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Host(t *testing.T) {
+	extension := &Extension{
+		Manifest: Manifest{
+			Name:        "hello",
+			EntryPoint:  "hello.sh",
+			Permissions: []string{PermissionEnvironmentRead, PermissionEnvironmentWrite, PermissionTokens},
+		},
+		Path: "/extensions/hello",
+	}
+
+	startHost := func(t *testing.T, console input.Console, options HostOptions) (*HostClient, *environment.Environment) {
+		env := environment.EphemeralWithValues("dev", map[string]string{"GREETING": "hello"})
+		host := NewHost(extension, console, env, options)
+
+		hostEnv, err := host.Start()
+		require.NoError(t, err)
+		t.Cleanup(host.Stop)
+
+		values := map[string]string{}
+		for _, value := range hostEnv {
+			key, value, _ := strings.Cut(value, "=")
+			values[key] = value
+		}
+
+		client, err := NewHostClient(context.Background(), values[HostAddressEnvVarName], values[HostAccessTokenEnvVarName])
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+
+		return client, env
+	}
+
+	t.Run("Environment", func(t *testing.T) {
+		ctx := context.Background()
+		client, env := startHost(t, mockinput.NewMockConsole(), HostOptions{})
+
+		// Calls fail until the extension negotiates
+		_, err := client.GetEnvironment(ctx)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		negotiated, err := client.Negotiate(ctx, &NegotiateRequest{
+			ProtocolVersions: []string{"2.0", "1.0"},
+			Capabilities:     []string{CapabilityEnvironmentRead, CapabilityEnvironmentWrite, "unknown"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "1.0", negotiated.ProtocolVersion)
+		require.Equal(t, []string{CapabilityEnvironmentRead, CapabilityEnvironmentWrite}, negotiated.Capabilities)
+
+		values, err := client.GetEnvironment(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "dev", values.Name)
+		require.Equal(t, "hello", values.Values["GREETING"])
+
+		require.NoError(t, client.SetValue(ctx, "GREETING", "hi"))
+		require.Equal(t, "hi", env.Getenv("GREETING"))
+
+		value, err := client.GetValue(ctx, "GREETING")
+		require.NoError(t, err)
+		require.True(t, value.Found)
+		require.Equal(t, "hi", value.Value)
+	})
+
+	t.Run("CapabilityNotAllowed", func(t *testing.T) {
+		ctx := context.Background()
+		client, _ := startHost(t, mockinput.NewMockConsole(), HostOptions{
+			AllowedCapabilities: []string{CapabilityEnvironmentRead},
+		})
+
+		negotiated, err := client.Negotiate(ctx, &NegotiateRequest{
+			ProtocolVersions: HostProtocolVersions,
+			Capabilities:     []string{CapabilityEnvironmentRead, CapabilityEnvironmentWrite},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{CapabilityEnvironmentRead}, negotiated.Capabilities)
+
+		err = client.SetValue(ctx, "GREETING", "hi")
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Tokens", func(t *testing.T) {
+		ctx := context.Background()
+		client, _ := startHost(t, mockinput.NewMockConsole(), HostOptions{
+			Credential: func(ctx context.Context) (azcore.TokenCredential, error) {
+				return &mocks.MockCredentials{}, nil
+			},
+		})
+
+		_, err := client.Negotiate(ctx, &NegotiateRequest{
+			ProtocolVersions: HostProtocolVersions,
+			Capabilities:     []string{CapabilityTokens},
+		})
+		require.NoError(t, err)
+
+		token, err := client.GetAccessToken(ctx, []string{"https://management.azure.com/.default"})
+		require.NoError(t, err)
+		require.NotEmpty(t, token.Token)
+	})
+
+	t.Run("PermissionNotDeclared", func(t *testing.T) {
+		ctx := context.Background()
+		restricted := &Extension{Manifest: Manifest{Name: "restricted", EntryPoint: "restricted.sh"}}
+		host := NewHost(restricted, mockinput.NewMockConsole(), nil, HostOptions{})
+		negotiated, err := host.negotiate(ctx, &NegotiateRequest{
+			ProtocolVersions: HostProtocolVersions,
+			Capabilities:     []string{CapabilityEnvironmentRead, CapabilityTokens, CapabilityProgress},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{CapabilityProgress}, negotiated.Capabilities)
+
+		_, err = host.getAccessToken(ctx, &GetAccessTokenRequest{Scopes: []string{"scope"}})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("UnsupportedProtocolVersion", func(t *testing.T) {
+		client, _ := startHost(t, mockinput.NewMockConsole(), HostOptions{})
+
+		_, err := client.Negotiate(context.Background(), &NegotiateRequest{ProtocolVersions: []string{"9.0"}})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		client, _ := startHost(t, mockinput.NewMockConsole(), HostOptions{})
+		client.token = "invalid"
+
+		_, err := client.Negotiate(context.Background(), &NegotiateRequest{ProtocolVersions: HostProtocolVersions})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Session", func(t *testing.T) {
+		ctx := context.Background()
+		console := mockinput.NewMockConsole()
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return options.Message == "Continue?"
+		}).Respond(true)
+		console.WhenSelect(func(options input.ConsoleOptions) bool {
+			return options.Message == "Pick a color"
+		}).Respond(1)
+
+		client, _ := startHost(t, console, HostOptions{})
+		_, err := client.Negotiate(ctx, &NegotiateRequest{
+			ProtocolVersions: HostProtocolVersions,
+			Capabilities:     []string{CapabilityProgress, CapabilityPrompt},
+		})
+		require.NoError(t, err)
+
+		session, err := client.Session(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, session.Progress("Deploying widgets"))
+
+		confirmed, err := session.Prompt(&PromptRequest{Type: PromptTypeConfirm, Message: "Continue?"})
+		require.NoError(t, err)
+		require.Equal(t, "true", confirmed)
+
+		selected, err := session.Prompt(&PromptRequest{
+			Type:    PromptTypeSelect,
+			Message: "Pick a color",
+			Options: []string{"red", "blue"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "blue", selected)
+
+		require.NoError(t, session.Close())
+		require.Contains(t, console.Output(), "Deploying widgets")
+	})
+}
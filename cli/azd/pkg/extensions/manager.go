@@ -0,0 +1,254 @@
+package extensions
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/otiai10/copy"
+	"gopkg.in/yaml.v3"
+)
+
+// Manager installs, removes and discovers extensions in the azd extensions directory
+type Manager struct {
+	rootPath string
+
+	// The installed extensions are read once and shared by command wiring, service targets & providers
+	mu        sync.Mutex
+	installed []*Extension
+}
+
+// Creates a new extension manager for extensions installed within the specified directory
+func NewManager(rootPath string) *Manager {
+	return &Manager{
+		rootPath: rootPath,
+	}
+}
+
+// Gets the directory where extensions are installed. (ex. ~/.azd/extensions)
+func GetExtensionsDir() (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "extensions"), nil
+}
+
+// Gets all the installed extensions sorted by name.
+// Extensions with an invalid manifest are skipped.
+func (m *Manager) List() ([]*Extension, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.installed == nil {
+		installed, err := m.list()
+		if err != nil {
+			return nil, err
+		}
+
+		m.installed = installed
+	}
+
+	return append([]*Extension{}, m.installed...), nil
+}
+
+func (m *Manager) list() ([]*Extension, error) {
+	entries, err := os.ReadDir(m.rootPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []*Extension{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading extensions directory: %w", err)
+	}
+
+	extensions := []*Extension{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		extension, err := loadExtension(filepath.Join(m.rootPath, entry.Name()))
+		if err != nil {
+			log.Printf("skipping extension '%s': %v\n", entry.Name(), err)
+			continue
+		}
+
+		extensions = append(extensions, extension)
+	}
+
+	sort.Slice(extensions, func(i, j int) bool {
+		return extensions[i].Name < extensions[j].Name
+	})
+
+	return extensions, nil
+}
+
+// Gets the installed extension with the specified name
+func (m *Manager) Get(name string) (*Extension, error) {
+	if !extensionNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("%w: '%s'", ErrExtensionNotFound, name)
+	}
+
+	extensionPath := filepath.Join(m.rootPath, name)
+	if _, err := os.Stat(filepath.Join(extensionPath, ManifestFileName)); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: '%s'", ErrExtensionNotFound, name)
+	}
+
+	return loadExtension(extensionPath)
+}
+
+// Installs the extension from the specified source directory or manifest file path.
+// When force is true any previously installed version of the extension is replaced.
+func (m *Manager) Install(source string, force bool) (*Extension, error) {
+	defer m.invalidate()
+
+	sourcePath, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading extension source '%s': %w", source, err)
+	}
+
+	if !stat.IsDir() {
+		sourcePath = filepath.Dir(sourcePath)
+	}
+
+	sourceExtension, err := loadExtension(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPath := filepath.Join(m.rootPath, sourceExtension.Name)
+	if _, err := os.Stat(targetPath); err == nil {
+		if !force {
+			return nil, fmt.Errorf("%w: '%s'", ErrExtensionAlreadyInstalled, sourceExtension.Name)
+		}
+
+		if err := os.RemoveAll(targetPath); err != nil {
+			return nil, fmt.Errorf("removing previous version of extension '%s': %w", sourceExtension.Name, err)
+		}
+	}
+
+	if err := os.MkdirAll(m.rootPath, osutil.PermissionDirectory); err != nil {
+		return nil, fmt.Errorf("creating extensions directory: %w", err)
+	}
+
+	if err := copy.Copy(sourcePath, targetPath); err != nil {
+		return nil, fmt.Errorf("copying extension '%s': %w", sourceExtension.Name, err)
+	}
+
+	extension, err := loadExtension(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the entry point can be executed after being copied
+	if err := os.Chmod(extension.EntryPointPath(), osutil.PermissionExecutableFile); err != nil {
+		return nil, fmt.Errorf("setting executable permissions for extension '%s': %w", extension.Name, err)
+	}
+
+	return extension, nil
+}
+
+// Loads the extension from a source directory, manifest file path or downloaded archive directory without installing it.
+// This allows the manifest, including the permissions of the extension, to be reviewed before installing.
+func LoadSource(source string) (*Extension, error) {
+	sourcePath, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading extension source '%s': %w", source, err)
+	}
+
+	if !stat.IsDir() {
+		sourcePath = filepath.Dir(sourcePath)
+	}
+
+	manifestDir, err := findManifestDir(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadExtension(manifestDir)
+}
+
+// Installs an extension downloaded from a registry and records where it was installed from.
+// The directory may contain the extension manifest at the root or within a single top level directory.
+func (m *Manager) InstallDownloaded(dir string, metadata *InstallMetadata, force bool) (*Extension, error) {
+	manifestDir, err := findManifestDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeInstallMetadata(manifestDir, metadata); err != nil {
+		return nil, fmt.Errorf("writing extension install metadata: %w", err)
+	}
+
+	return m.Install(manifestDir, force)
+}
+
+// Removes the installed extension with the specified name
+func (m *Manager) Remove(name string) error {
+	defer m.invalidate()
+
+	extension, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(extension.Path); err != nil {
+		return fmt.Errorf("removing extension '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// Clears the installed extensions so they are read again after the extensions directory changes
+func (m *Manager) invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.installed = nil
+}
+
+// Loads the extension manifest from the specified extension directory
+func loadExtension(extensionPath string) (*Extension, error) {
+	manifestBytes, err := os.ReadFile(filepath.Join(extensionPath, ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("reading extension manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+
+	if err := manifest.validate(); err != nil {
+		return nil, err
+	}
+
+	extension := &Extension{
+		Manifest: manifest,
+		Path:     extensionPath,
+		Install:  readInstallMetadata(extensionPath),
+	}
+
+	if _, err := os.Stat(extension.EntryPointPath()); err != nil {
+		return nil, fmt.Errorf("%w: entryPoint '%s' does not exist", ErrInvalidManifest, manifest.EntryPoint)
+	}
+
+	return extension, nil
+}
@@ -0,0 +1,207 @@
+package extensions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Manager_Install(t *testing.T) {
+	source := createExtensionSource(t, "hello")
+
+	t.Run("Success", func(t *testing.T) {
+		manager := NewManager(t.TempDir())
+
+		extension, err := manager.Install(source, false)
+		require.NoError(t, err)
+		require.Equal(t, "hello", extension.Name)
+		require.Equal(t, "1.0.0", extension.Version)
+		require.FileExists(t, extension.EntryPointPath())
+
+		installed, err := manager.List()
+		require.NoError(t, err)
+		require.Len(t, installed, 1)
+		require.Equal(t, "hello", installed[0].Name)
+	})
+
+	t.Run("FromManifestPath", func(t *testing.T) {
+		manager := NewManager(t.TempDir())
+
+		extension, err := manager.Install(filepath.Join(source, ManifestFileName), false)
+		require.NoError(t, err)
+		require.Equal(t, "hello", extension.Name)
+	})
+
+	t.Run("AlreadyInstalled", func(t *testing.T) {
+		manager := NewManager(t.TempDir())
+
+		_, err := manager.Install(source, false)
+		require.NoError(t, err)
+
+		_, err = manager.Install(source, false)
+		require.ErrorIs(t, err, ErrExtensionAlreadyInstalled)
+
+		_, err = manager.Install(source, true)
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidManifest", func(t *testing.T) {
+		invalidSource := t.TempDir()
+		manifest := "name: Not Valid\nentryPoint: hello.sh\n"
+		err := os.WriteFile(filepath.Join(invalidSource, ManifestFileName), []byte(manifest), osutil.PermissionFile)
+		require.NoError(t, err)
+
+		manager := NewManager(t.TempDir())
+		_, err = manager.Install(invalidSource, false)
+		require.ErrorIs(t, err, ErrInvalidManifest)
+	})
+
+	t.Run("EntryPointOutsideDirectory", func(t *testing.T) {
+		// The entry points exist, but outside of the extension directory
+		root := t.TempDir()
+		outside := filepath.Join(root, "hello.sh")
+		require.NoError(t, os.WriteFile(outside, []byte("echo hello"), osutil.PermissionExecutableFile))
+
+		for _, entryPoint := range []string{"../hello.sh", "bin/../../hello.sh", outside} {
+			invalidSource := filepath.Join(root, "extension")
+			require.NoError(t, os.MkdirAll(filepath.Join(invalidSource, "bin"), osutil.PermissionDirectory))
+			manifest := "name: hello\nentryPoint: " + entryPoint + "\n"
+			err := os.WriteFile(filepath.Join(invalidSource, ManifestFileName), []byte(manifest), osutil.PermissionFile)
+			require.NoError(t, err)
+
+			manager := NewManager(t.TempDir())
+			_, err = manager.Install(invalidSource, false)
+			require.ErrorIs(t, err, ErrInvalidManifest, entryPoint)
+			require.ErrorContains(t, err, "relative to the extension directory", entryPoint)
+		}
+	})
+
+	t.Run("MissingEntryPoint", func(t *testing.T) {
+		invalidSource := t.TempDir()
+		manifest := "name: hello\nentryPoint: missing.sh\n"
+		err := os.WriteFile(filepath.Join(invalidSource, ManifestFileName), []byte(manifest), osutil.PermissionFile)
+		require.NoError(t, err)
+
+		manager := NewManager(t.TempDir())
+		_, err = manager.Install(invalidSource, false)
+		require.ErrorIs(t, err, ErrInvalidManifest)
+	})
+}
+
+func Test_Manager_Remove(t *testing.T) {
+	manager := NewManager(t.TempDir())
+
+	_, err := manager.Install(createExtensionSource(t, "hello"), false)
+	require.NoError(t, err)
+
+	installed, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+
+	require.NoError(t, manager.Remove("hello"))
+
+	installed, err = manager.List()
+	require.NoError(t, err)
+	require.Len(t, installed, 0)
+
+	require.ErrorIs(t, manager.Remove("hello"), ErrExtensionNotFound)
+	require.ErrorIs(t, manager.Remove("../hello"), ErrExtensionNotFound)
+}
+
+func Test_Manager_Permissions(t *testing.T) {
+	t.Run("LoadSource", func(t *testing.T) {
+		source := t.TempDir()
+		manifest := "name: hello\nentryPoint: hello.sh\npermissions:\n  - environment.read\n  - tokens\n"
+		err := os.WriteFile(filepath.Join(source, ManifestFileName), []byte(manifest), osutil.PermissionFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(source, "hello.sh"), []byte("echo hello"), osutil.PermissionFile))
+
+		extension, err := LoadSource(source)
+		require.NoError(t, err)
+		require.Equal(t, []string{PermissionEnvironmentRead, PermissionTokens}, extension.Permissions)
+		require.Equal(
+			t,
+			[]string{CapabilityProgress, CapabilityPrompt, CapabilityEnvironmentRead, CapabilityTokens},
+			extension.AllowedCapabilities(),
+		)
+	})
+
+	t.Run("UnknownPermission", func(t *testing.T) {
+		source := t.TempDir()
+		manifest := "name: hello\nentryPoint: hello.sh\npermissions:\n  - everything\n"
+		err := os.WriteFile(filepath.Join(source, ManifestFileName), []byte(manifest), osutil.PermissionFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(source, "hello.sh"), []byte("echo hello"), osutil.PermissionFile))
+
+		_, err = LoadSource(source)
+		require.ErrorIs(t, err, ErrInvalidManifest)
+	})
+
+	t.Run("NewPermissions", func(t *testing.T) {
+		previous := &Manifest{Permissions: []string{PermissionEnvironmentRead}}
+		current := &Manifest{Permissions: []string{PermissionEnvironmentRead, PermissionNetwork}}
+
+		require.Equal(t, []string{PermissionNetwork}, current.NewPermissions(previous))
+		require.Equal(t, []string{PermissionEnvironmentRead, PermissionNetwork}, current.NewPermissions(nil))
+	})
+
+	t.Run("Environ", func(t *testing.T) {
+		env := environment.EphemeralWithValues("dev", map[string]string{"GREETING": "hello"})
+
+		withoutPermission := &Extension{Manifest: Manifest{Name: "hello"}}
+		require.Empty(t, withoutPermission.Environ(env))
+
+		withPermission := &Extension{Manifest: Manifest{Name: "hello", Permissions: []string{PermissionEnvironmentRead}}}
+		require.Contains(t, withPermission.Environ(env), "GREETING=hello")
+	})
+}
+
+func Test_Manager_List_Cached(t *testing.T) {
+	manager := NewManager(t.TempDir())
+
+	extension, err := manager.Install(createExtensionSource(t, "hello"), false)
+	require.NoError(t, err)
+
+	installed, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+
+	// The installed extensions are only read once by the manager
+	require.NoError(t, os.RemoveAll(extension.Path))
+
+	installed, err = manager.List()
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+}
+
+func Test_Manager_List_NoExtensions(t *testing.T) {
+	manager := NewManager(filepath.Join(t.TempDir(), "missing"))
+
+	installed, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, installed, 0)
+}
+
+func createExtensionSource(t *testing.T, name string) string {
+	source := t.TempDir()
+	manifest := "name: " + name + "\ndescription: Says hello.\nversion: 1.0.0\nentryPoint: bin/hello.sh\n"
+
+	err := os.WriteFile(filepath.Join(source, ManifestFileName), []byte(manifest), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Join(source, "bin"), osutil.PermissionDirectory)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(source, "bin", "hello.sh"), []byte("echo hello"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	return source
+}
+
+func writeManifest(source string, manifest string) error {
+	return os.WriteFile(filepath.Join(source, ManifestFileName), []byte(manifest), osutil.PermissionFile)
+}
@@ -0,0 +1,208 @@
+package extensions
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// The file name of the manifest that describes an extension
+const ManifestFileName = "extension.yaml"
+
+var (
+	ErrExtensionNotFound         = errors.New("extension not found")
+	ErrExtensionAlreadyInstalled = errors.New("extension is already installed")
+	ErrInvalidManifest           = errors.New("extension manifest is invalid")
+)
+
+// Permissions extensions declare within their manifest.
+// Permissions are shown for review when the extension is installed. The extension host enforces the environment and
+// token permissions, the network and tools permissions are advisory since extensions run as normal processes.
+const (
+	// Read the values of the azd environment
+	PermissionEnvironmentRead = "environment.read"
+	// Write values to the azd environment
+	PermissionEnvironmentWrite = "environment.write"
+	// Make network requests (advisory)
+	PermissionNetwork = "network"
+	// Run tools installed on the machine such as az, docker or kubectl (advisory)
+	PermissionTools = "tools"
+	// Acquire Azure access tokens for the logged in account
+	PermissionTokens = "tokens"
+)
+
+var permissionDescriptions = map[string]string{
+	PermissionEnvironmentRead:  "Read the values of your azd environments",
+	PermissionEnvironmentWrite: "Write values to your azd environments",
+	PermissionNetwork:          "Make network requests",
+	PermissionTools:            "Run tools installed on your machine",
+	PermissionTokens:           "Acquire Azure access tokens for your logged in account",
+}
+
+// Returns true when the permission is declared for review only and isn't enforced by azd
+func IsAdvisoryPermission(permission string) bool {
+	return permission == PermissionNetwork || permission == PermissionTools
+}
+
+// Gets the description of the permission shown to users
+func DescribePermission(permission string) string {
+	if description, has := permissionDescriptions[permission]; has {
+		return description
+	}
+
+	return permission
+}
+
+// Extension names become azd commands and must be lower case alpha numeric values separated by hyphens
+var extensionNameRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// Manifest describes an extension and the command it contributes to azd
+type Manifest struct {
+	// The name of the extension which is also the name of the command registered under the azd root
+	Name string `yaml:"name" json:"name"`
+	// The display name of the extension
+	DisplayName string `yaml:"displayName,omitempty" json:"displayName,omitempty"`
+	// A short description of the extension used for command help
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// The version of the extension
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// The relative path of the executable within the extension directory
+	EntryPoint string `yaml:"entryPoint" json:"entryPoint"`
+	// The usage of the extension command. (ex. `azd hello <name>`)
+	Usage string `yaml:"usage,omitempty" json:"usage,omitempty"`
+	// When true the extension provides shell completions through the cobra `__complete` command
+	Completion bool `yaml:"completion,omitempty" json:"completion,omitempty"`
+	// The service host kinds implemented by the extension. (ex. `host: edge` within azure.yaml)
+	ServiceTargets []string `yaml:"serviceTargets,omitempty" json:"serviceTargets,omitempty"`
+	// The infrastructure provider kinds implemented by the extension. (ex. `infra.provider: crossplane` within azure.yaml)
+	Providers []string `yaml:"providers,omitempty" json:"providers,omitempty"`
+	// The permissions required by the extension
+	Permissions []string `yaml:"permissions,omitempty" json:"permissions,omitempty"`
+}
+
+// Returns true when the extension declares the permission
+func (m *Manifest) HasPermission(permission string) bool {
+	for _, declared := range m.Permissions {
+		if declared == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gets the permissions declared by the manifest that are not declared by the previous manifest
+func (m *Manifest) NewPermissions(previous *Manifest) []string {
+	added := []string{}
+	for _, permission := range m.Permissions {
+		if previous == nil || !previous.HasPermission(permission) {
+			added = append(added, permission)
+		}
+	}
+
+	return added
+}
+
+// Validates the required values of the manifest
+func (m *Manifest) validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidManifest)
+	}
+
+	if !extensionNameRegex.MatchString(m.Name) {
+		return fmt.Errorf(
+			"%w: name '%s' must only contain lower case letters, numbers and hyphens",
+			ErrInvalidManifest,
+			m.Name,
+		)
+	}
+
+	if m.EntryPoint == "" {
+		return fmt.Errorf("%w: entryPoint is required", ErrInvalidManifest)
+	}
+
+	// The entry point must stay within the extension directory, ex. `../../usr/bin/tool` is rejected
+	entryPoint := filepath.Clean(m.EntryPoint)
+	if filepath.IsAbs(entryPoint) || filepath.VolumeName(entryPoint) != "" ||
+		entryPoint == ".." || strings.HasPrefix(entryPoint, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: entryPoint must be relative to the extension directory", ErrInvalidManifest)
+	}
+
+	for _, kind := range m.ServiceTargets {
+		if !extensionNameRegex.MatchString(kind) {
+			return fmt.Errorf(
+				"%w: service target '%s' must only contain lower case letters, numbers and hyphens",
+				ErrInvalidManifest,
+				kind,
+			)
+		}
+	}
+
+	for _, permission := range m.Permissions {
+		if _, has := permissionDescriptions[permission]; !has {
+			return fmt.Errorf("%w: unknown permission '%s'", ErrInvalidManifest, permission)
+		}
+	}
+
+	for _, kind := range m.Providers {
+		if !extensionNameRegex.MatchString(kind) {
+			return fmt.Errorf(
+				"%w: provider '%s' must only contain lower case letters, numbers and hyphens",
+				ErrInvalidManifest,
+				kind,
+			)
+		}
+	}
+
+	return nil
+}
+
+// Extension is an extension installed within the azd extensions directory
+type Extension struct {
+	Manifest
+	// The directory the extension is installed within
+	Path string `json:"path"`
+	// Where the extension was installed from when installed from a registry
+	Install *InstallMetadata `json:"install,omitempty"`
+	// Where the extension was found, extensions on PATH or declared within the config aren't installed
+	Origin string `json:"origin,omitempty"`
+}
+
+// Gets the absolute path of the extension executable
+func (e *Extension) EntryPointPath() string {
+	return filepath.Join(e.Path, e.EntryPoint)
+}
+
+// Gets the extension host capabilities granted by the permissions of the extension
+func (e *Extension) AllowedCapabilities() []string {
+	// All extensions can report progress and prompt the user
+	capabilities := []string{CapabilityProgress, CapabilityPrompt}
+
+	if e.HasPermission(PermissionEnvironmentRead) {
+		capabilities = append(capabilities, CapabilityEnvironmentRead)
+	}
+
+	if e.HasPermission(PermissionEnvironmentWrite) {
+		capabilities = append(capabilities, CapabilityEnvironmentWrite)
+	}
+
+	if e.HasPermission(PermissionTokens) {
+		capabilities = append(capabilities, CapabilityTokens)
+	}
+
+	return capabilities
+}
+
+// Gets the environment variables passed to the extension process.
+// Environment values are only available to extensions with the environment.read permission.
+func (e *Extension) Environ(env *environment.Environment) []string {
+	if env == nil || !e.HasPermission(PermissionEnvironmentRead) {
+		return []string{}
+	}
+
+	return env.Environ()
+}
@@ -0,0 +1,489 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"golang.org/x/exp/slices"
+)
+
+// The command extensions implement to provide infrastructure providers.
+// azd invokes `<entryPoint> provider <operation>` writing a JSON request to stdin and reading a
+// JSON response from stdout. Each line written to stderr is reported as progress.
+const ProviderCommand = "provider"
+
+// The versions of the provider protocol supported by azd, ordered from newest to oldest.
+// The version is negotiated with the extension during the handshake operation.
+var ProviderProtocolVersions = []string{"1.0"}
+
+// The provider operations invoked on extensions
+type ProviderOperation string
+
+const (
+	ProviderHandshake ProviderOperation = "handshake"
+	ProviderPlan      ProviderOperation = "plan"
+	ProviderDeploy    ProviderOperation = "deploy"
+	ProviderDestroy   ProviderOperation = "destroy"
+	ProviderState     ProviderOperation = "state"
+)
+
+// ProviderRequest is the JSON payload written to the stdin of the extension
+type ProviderRequest struct {
+	// The negotiated protocol version. Empty for the handshake operation.
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+	// The protocol versions supported by azd. Only set for the handshake operation.
+	SupportedVersions []string `json:"supportedVersions,omitempty"`
+	// The provider kind from azure.yaml. (ex. `crossplane`)
+	Kind string `json:"kind"`
+	// The name of the azd environment
+	EnvironmentName string `json:"environmentName"`
+	// The absolute path of the infrastructure module directory
+	Path string `json:"path"`
+	// The name of the infrastructure module
+	Module string `json:"module"`
+	// The plan returned by the plan operation. Only set for the deploy operation.
+	Plan *ProviderDeployment `json:"plan,omitempty"`
+	// Whether the user allowed resources to be deleted without confirmation. Only set for the destroy operation.
+	Force bool `json:"force,omitempty"`
+	// Whether soft deleted resources should be purged. Only set for the destroy operation.
+	Purge bool `json:"purge,omitempty"`
+}
+
+// ProviderHandshakeResponse is returned by the extension for the handshake operation
+type ProviderHandshakeResponse struct {
+	// The protocol version selected by the extension from the supported versions
+	ProtocolVersion string `json:"protocolVersion"`
+	// The display name of the provider
+	Name string `json:"name,omitempty"`
+	// When true azd ensures an Azure subscription and location are configured for the environment
+	RequiresSubscription bool `json:"requiresSubscription,omitempty"`
+}
+
+// ProviderParameter is an input parameter of a deployment
+type ProviderParameter struct {
+	Type         string `json:"type,omitempty"`
+	DefaultValue any    `json:"defaultValue,omitempty"`
+	Value        any    `json:"value,omitempty"`
+}
+
+// ProviderOutput is an output of a deployment
+type ProviderOutput struct {
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// ProviderDeployment describes the parameters and outputs of a deployment
+type ProviderDeployment struct {
+	Parameters map[string]ProviderParameter `json:"parameters,omitempty"`
+	Outputs    map[string]ProviderOutput    `json:"outputs,omitempty"`
+	// Additional provider specific details returned by the plan operation
+	Details any `json:"details,omitempty"`
+}
+
+// ProviderResponse is returned by the extension for the plan, deploy, destroy & state operations
+type ProviderResponse struct {
+	// The deployment for the plan & deploy operations
+	Deployment *ProviderDeployment `json:"deployment,omitempty"`
+	// The outputs of the most recent deployment for the state operation
+	Outputs map[string]ProviderOutput `json:"outputs,omitempty"`
+	// The IDs of the resources that make up the application for the state operation
+	Resources []string `json:"resources,omitempty"`
+	// The environment keys that should be removed after the destroy operation
+	InvalidatedEnvKeys []string `json:"invalidatedEnvKeys,omitempty"`
+}
+
+// provider is a provisioning.Provider implemented by an extension
+type provider struct {
+	extension *Extension
+	kind      provisioning.ProviderKind
+	runner    *Runner
+	env       *environment.Environment
+	path      string
+	options   provisioning.Options
+	console   input.Console
+	prompters provisioning.Prompters
+
+	handshake *ProviderHandshakeResponse
+}
+
+// Creates a new infrastructure provider that delegates provisioning operations to the extension
+func NewProvider(
+	extension *Extension,
+	kind provisioning.ProviderKind,
+	runner *Runner,
+	env *environment.Environment,
+	projectPath string,
+	options provisioning.Options,
+	console input.Console,
+	prompters provisioning.Prompters,
+) provisioning.Provider {
+	return &provider{
+		extension: extension,
+		kind:      kind,
+		runner:    runner,
+		env:       env,
+		path:      projectPath,
+		options:   options,
+		console:   console,
+		prompters: prompters,
+	}
+}
+
+// Registers an infrastructure provider for each provider kind implemented by the installed extensions
+func RegisterProviders(installed []*Extension) {
+	for _, extension := range installed {
+		for _, kind := range extension.Providers {
+			providerKind := provisioning.ProviderKind(kind)
+			if provisioning.IsProviderRegistered(providerKind) {
+				log.Printf(
+					"skipping provider '%s' from extension '%s' since it is already registered\n",
+					kind,
+					extension.Name,
+				)
+				continue
+			}
+
+			extension := extension
+			err := provisioning.RegisterProvider(
+				providerKind,
+				func(
+					ctx context.Context,
+					env *environment.Environment,
+					projectPath string,
+					options provisioning.Options,
+					console input.Console,
+					_ azcli.AzCli,
+					commandRunner exec.CommandRunner,
+					prompters provisioning.Prompters,
+					_ provisioning.CurrentPrincipalIdProvider,
+					_ *alpha.FeatureManager,
+				) (provisioning.Provider, error) {
+					runner := NewRunner(commandRunner)
+					return NewProvider(extension, providerKind, runner, env, projectPath, options, console, prompters), nil
+				},
+			)
+			if err != nil {
+				log.Printf("failed registering provider '%s' from extension '%s': %v\n", kind, extension.Name, err)
+			}
+		}
+	}
+}
+
+// Name gets the name of the infra provider
+func (p *provider) Name() string {
+	if p.handshake != nil && p.handshake.Name != "" {
+		return p.handshake.Name
+	}
+
+	return string(p.kind)
+}
+
+// Extension providers validate their own tools
+func (p *provider) RequiredExternalTools() []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Negotiates the protocol version with the extension and ensures the environment is configured
+func (p *provider) EnsureConfigured(ctx context.Context) error {
+	handshake := &ProviderHandshakeResponse{}
+	request := p.newRequest()
+	request.ProtocolVersion = ""
+	request.SupportedVersions = ProviderProtocolVersions
+
+	if err := p.invoke(ctx, ProviderHandshake, request, handshake, nil); err != nil {
+		return err
+	}
+
+	if !slices.Contains(ProviderProtocolVersions, handshake.ProtocolVersion) {
+		return fmt.Errorf(
+			"extension '%s' requested provider protocol version '%s' but azd supports versions %v."+
+				" Upgrade azd or the extension to a compatible version",
+			p.extension.Name,
+			handshake.ProtocolVersion,
+			ProviderProtocolVersions,
+		)
+	}
+
+	p.handshake = handshake
+
+	if handshake.RequiresSubscription {
+		return p.prompters.EnsureSubscriptionLocation(ctx, p.env)
+	}
+
+	return nil
+}
+
+// Plans the deployment using the extension. This includes compiling the template and previewing changes.
+func (p *provider) Plan(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*provisioning.DeploymentPlan, *provisioning.DeploymentPlanningProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[
+			*provisioning.DeploymentPlan,
+			*provisioning.DeploymentPlanningProgress,
+		]) {
+			response := &ProviderResponse{}
+			err := p.invoke(ctx, ProviderPlan, p.newRequest(), response, func(message string) {
+				asyncContext.SetProgress(&provisioning.DeploymentPlanningProgress{
+					Message:   message,
+					Timestamp: time.Now(),
+				})
+			})
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			deployment := response.Deployment
+			if deployment == nil {
+				deployment = &ProviderDeployment{}
+			}
+
+			asyncContext.SetResult(&provisioning.DeploymentPlan{
+				Deployment: toDeployment(deployment),
+				Details:    deployment,
+			})
+		})
+}
+
+// Provisions the infrastructure of the plan using the extension
+func (p *provider) Deploy(
+	ctx context.Context,
+	plan *provisioning.DeploymentPlan,
+) *async.InteractiveTaskWithProgress[*provisioning.DeployResult, *provisioning.DeployProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[
+			*provisioning.DeployResult,
+			*provisioning.DeployProgress,
+		]) {
+			request := p.newRequest()
+			if planned, ok := plan.Details.(*ProviderDeployment); ok {
+				request.Plan = planned
+			} else {
+				request.Plan = fromDeployment(plan.Deployment)
+			}
+
+			response := &ProviderResponse{}
+			err := p.invoke(ctx, ProviderDeploy, request, response, func(message string) {
+				asyncContext.SetProgress(&provisioning.DeployProgress{
+					Message:   message,
+					Timestamp: time.Now(),
+				})
+			})
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			deployment := provisioning.Deployment{
+				Parameters: plan.Deployment.Parameters,
+			}
+			if response.Deployment != nil {
+				deployment = toDeployment(response.Deployment)
+			}
+
+			asyncContext.SetResult(&provisioning.DeployResult{
+				Deployment: &deployment,
+			})
+		})
+}
+
+// Destroys the infrastructure using the extension
+func (p *provider) Destroy(
+	ctx context.Context,
+	options provisioning.DestroyOptions,
+) *async.InteractiveTaskWithProgress[*provisioning.DestroyResult, *provisioning.DestroyProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[
+			*provisioning.DestroyResult,
+			*provisioning.DestroyProgress,
+		]) {
+			if !options.Force() {
+				var confirmed bool
+				err := asyncContext.Interact(func() error {
+					var err error
+					confirmed, err = p.console.Confirm(ctx, input.ConsoleOptions{
+						Message: fmt.Sprintf(
+							"This will delete the resources provisioned by the '%s' provider. Are you sure you want to continue?",
+							p.kind,
+						),
+					})
+
+					return err
+				})
+				if err != nil {
+					asyncContext.SetError(err)
+					return
+				}
+
+				if !confirmed {
+					asyncContext.SetError(errors.New("user denied delete confirmation"))
+					return
+				}
+			}
+
+			request := p.newRequest()
+			request.Force = options.Force()
+			request.Purge = options.Purge()
+
+			response := &ProviderResponse{}
+			err := p.invoke(ctx, ProviderDestroy, request, response, func(message string) {
+				asyncContext.SetProgress(&provisioning.DestroyProgress{
+					Message:   message,
+					Timestamp: time.Now(),
+				})
+			})
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			invalidatedEnvKeys := response.InvalidatedEnvKeys
+			if invalidatedEnvKeys == nil {
+				invalidatedEnvKeys = []string{}
+			}
+
+			asyncContext.SetResult(&provisioning.DestroyResult{
+				InvalidatedEnvKeys: invalidatedEnvKeys,
+			})
+		})
+}
+
+// Gets the current state of the infrastructure from the extension
+func (p *provider) State(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*provisioning.StateResult, *provisioning.StateProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[
+			*provisioning.StateResult,
+			*provisioning.StateProgress,
+		]) {
+			response := &ProviderResponse{}
+			err := p.invoke(ctx, ProviderState, p.newRequest(), response, func(message string) {
+				asyncContext.SetProgress(&provisioning.StateProgress{
+					Message:   message,
+					Timestamp: time.Now(),
+				})
+			})
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			state := provisioning.State{
+				Outputs:   toOutputs(response.Outputs),
+				Resources: make([]provisioning.Resource, len(response.Resources)),
+			}
+
+			for i, id := range response.Resources {
+				state.Resources[i] = provisioning.Resource{Id: id}
+			}
+
+			asyncContext.SetResult(&provisioning.StateResult{
+				State: &state,
+			})
+		})
+}
+
+func (p *provider) newRequest() *ProviderRequest {
+	request := &ProviderRequest{
+		Kind:            string(p.kind),
+		EnvironmentName: p.env.GetEnvName(),
+		Path:            filepath.Join(p.path, p.options.Path),
+		Module:          p.options.Module,
+	}
+
+	if p.handshake != nil {
+		request.ProtocolVersion = p.handshake.ProtocolVersion
+	}
+
+	return request
+}
+
+// Invokes the provider operation on the extension and parses the response
+func (p *provider) invoke(
+	ctx context.Context,
+	operation ProviderOperation,
+	request *ProviderRequest,
+	response any,
+	progress func(string),
+) error {
+	err := p.runner.InvokeJson(
+		ctx,
+		p.extension,
+		[]string{ProviderCommand, string(operation)},
+		p.extension.Environ(p.env),
+		request,
+		response,
+		progress,
+	)
+	if err != nil {
+		return fmt.Errorf("'%s' provider %s failed: %w", p.kind, operation, err)
+	}
+
+	return nil
+}
+
+func toDeployment(deployment *ProviderDeployment) provisioning.Deployment {
+	parameters := map[string]provisioning.InputParameter{}
+	for key, param := range deployment.Parameters {
+		parameters[key] = provisioning.InputParameter{
+			Type:         param.Type,
+			DefaultValue: param.DefaultValue,
+			Value:        param.Value,
+		}
+	}
+
+	return provisioning.Deployment{
+		Parameters: parameters,
+		Outputs:    toOutputs(deployment.Outputs),
+	}
+}
+
+func fromDeployment(deployment provisioning.Deployment) *ProviderDeployment {
+	result := &ProviderDeployment{
+		Parameters: map[string]ProviderParameter{},
+		Outputs:    map[string]ProviderOutput{},
+	}
+
+	for key, param := range deployment.Parameters {
+		result.Parameters[key] = ProviderParameter{
+			Type:         param.Type,
+			DefaultValue: param.DefaultValue,
+			Value:        param.Value,
+		}
+	}
+
+	for key, output := range deployment.Outputs {
+		result.Outputs[key] = ProviderOutput{
+			Type:  string(output.Type),
+			Value: output.Value,
+		}
+	}
+
+	return result
+}
+
+func toOutputs(outputs map[string]ProviderOutput) map[string]provisioning.OutputParameter {
+	result := map[string]provisioning.OutputParameter{}
+	for key, output := range outputs {
+		result[key] = provisioning.OutputParameter{
+			Type:  provisioning.ParameterType(output.Type),
+			Value: output.Value,
+		}
+	}
+
+	return result
+}
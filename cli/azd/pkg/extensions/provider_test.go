@@ -0,0 +1,146 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider(t *testing.T) {
+	extension := &Extension{
+		Manifest: Manifest{Name: "crossplane", EntryPoint: "crossplane.sh", Providers: []string{"crossplane"}},
+		Path:     "/extensions/crossplane",
+	}
+
+	env := environment.EphemeralWithValues("dev", nil)
+	options := provisioning.Options{Provider: "crossplane", Path: "infra", Module: "main"}
+
+	setupMocks := func(mockContext *mocks.MockContext, protocolVersion string) map[string]*ProviderRequest {
+		requests := map[string]*ProviderRequest{}
+		responses := map[string]string{
+			"handshake": `{"protocolVersion": "` + protocolVersion + `", "name": "Crossplane"}`,
+			"plan":      `{"deployment": {"parameters": {"region": {"type": "string", "value": "west"}}}}`,
+			"deploy":    `{"deployment": {"outputs": {"ENDPOINT": {"type": "string", "value": "https://app"}}}}`,
+			"state":     `{"outputs": {"ENDPOINT": {"type": "string", "value": "https://app"}}, "resources": ["r1"]}`,
+			"destroy":   `{"invalidatedEnvKeys": ["ENDPOINT"]}`,
+		}
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "crossplane.sh provider")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			operation := args.Args[1]
+
+			body, err := io.ReadAll(args.StdIn)
+			require.NoError(t, err)
+
+			request := &ProviderRequest{}
+			require.NoError(t, json.Unmarshal(body, request))
+			requests[operation] = request
+
+			return exec.NewRunResult(0, responses[operation], ""), nil
+		})
+
+		return requests
+	}
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		requests := setupMocks(mockContext, "1.0")
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "crossplane")
+		}).Respond(true)
+
+		provider := NewProvider(
+			extension,
+			"crossplane",
+			NewRunner(mockContext.CommandRunner),
+			env,
+			"/project",
+			options,
+			mockContext.Console,
+			provisioning.Prompters{},
+		)
+
+		require.NoError(t, provider.EnsureConfigured(*mockContext.Context))
+		require.Equal(t, "Crossplane", provider.Name())
+		require.Equal(t, ProviderProtocolVersions, requests["handshake"].SupportedVersions)
+
+		planTask := provider.Plan(*mockContext.Context)
+		go drainProgress(planTask.Progress())
+		plan, err := planTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, "west", plan.Deployment.Parameters["region"].Value)
+		require.Equal(t, "1.0", requests["plan"].ProtocolVersion)
+		require.Equal(t, "/project/infra", requests["plan"].Path)
+
+		deployTask := provider.Deploy(*mockContext.Context, plan)
+		go drainProgress(deployTask.Progress())
+		deployResult, err := deployTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, "https://app", deployResult.Deployment.Outputs["ENDPOINT"].Value)
+		require.Equal(t, "west", requests["deploy"].Plan.Parameters["region"].Value)
+
+		stateTask := provider.State(*mockContext.Context)
+		go drainProgress(stateTask.Progress())
+		stateResult, err := stateTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, "https://app", stateResult.State.Outputs["ENDPOINT"].Value)
+		require.Equal(t, []provisioning.Resource{{Id: "r1"}}, stateResult.State.Resources)
+
+		destroyTask := provider.Destroy(*mockContext.Context, provisioning.NewDestroyOptions(false, true))
+		go drainProgress(destroyTask.Progress())
+		go func() {
+			for range destroyTask.Interactive() {
+			}
+		}()
+		destroyResult, err := destroyTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, []string{"ENDPOINT"}, destroyResult.InvalidatedEnvKeys)
+		require.True(t, requests["destroy"].Purge)
+	})
+
+	t.Run("UnsupportedProtocolVersion", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupMocks(mockContext, "2.0")
+
+		provider := NewProvider(
+			extension,
+			"crossplane",
+			NewRunner(mockContext.CommandRunner),
+			env,
+			"/project",
+			options,
+			mockContext.Console,
+			provisioning.Prompters{},
+		)
+
+		err := provider.EnsureConfigured(*mockContext.Context)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "protocol version '2.0'")
+	})
+}
+
+func Test_RegisterProviders(t *testing.T) {
+	extension := &Extension{
+		Manifest: Manifest{Name: "crossplane", EntryPoint: "crossplane.sh", Providers: []string{"crossplane-test"}},
+		Path:     "/extensions/crossplane",
+	}
+
+	require.False(t, provisioning.IsProviderRegistered("crossplane-test"))
+	RegisterProviders([]*Extension{extension})
+	require.True(t, provisioning.IsProviderRegistered("crossplane-test"))
+}
+
+func drainProgress[P any](progress <-chan P) {
+	for range progress {
+	}
+}
@@ -0,0 +1,500 @@
+package extensions
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/blang/semver/v4"
+)
+
+const (
+	// The config path where extension sources are stored within the azd user config
+	sourcesConfigPath = "extension.sources"
+	// The file written within an installed extension that records where it was installed from
+	installFileName = ".install.json"
+)
+
+// Update channels for extensions published to a registry
+const (
+	// Only stable versions are installed
+	ChannelStable = "stable"
+	// Preview & stable versions are installed
+	ChannelPreview = "preview"
+)
+
+var (
+	ErrSourceNotFound      = errors.New("extension source not found")
+	ErrSignatureInvalid    = errors.New("extension artifact signature is invalid")
+	ErrChecksumMismatch    = errors.New("extension artifact checksum does not match")
+	ErrNoCompatibleVersion = errors.New("no compatible extension version found")
+)
+
+// Source is a registry extensions can be installed from.
+// The public key of the source is pinned when the source is added and used to verify all artifacts.
+type Source struct {
+	Name string `json:"name"`
+	// The url or local file path of the registry index
+	Url string `json:"url"`
+	// The base64 encoded ed25519 public key used to verify artifact signatures
+	PublicKey string `json:"publicKey"`
+}
+
+// RegistryIndex is the document published by a registry describing the available extensions
+type RegistryIndex struct {
+	Extensions []*RegistryExtension `json:"extensions"`
+}
+
+// RegistryExtension is an extension published to a registry
+type RegistryExtension struct {
+	Name        string             `json:"name"`
+	DisplayName string             `json:"displayName,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Versions    []*RegistryVersion `json:"versions"`
+}
+
+// RegistryVersion is a published version of an extension
+type RegistryVersion struct {
+	Version string `json:"version"`
+	// The update channel of the version. Defaults to stable.
+	Channel string `json:"channel,omitempty"`
+	// The artifacts of the version keyed by platform. (ex. `linux/amd64`)
+	// The `default` key is used when the version isn't platform specific.
+	Artifacts map[string]*RegistryArtifact `json:"artifacts"`
+}
+
+// RegistryArtifact is a .zip or .tar.gz archive containing the extension manifest and entry point
+type RegistryArtifact struct {
+	// The url or local file path of the archive. Relative paths are resolved against the registry index.
+	Url string `json:"url"`
+	// The hex encoded sha256 checksum of the archive
+	Checksum string `json:"checksum"`
+	// The base64 encoded ed25519 signature of the archive
+	Signature string `json:"signature"`
+}
+
+// InstallMetadata records where an installed extension came from so it can be upgraded
+type InstallMetadata struct {
+	Source   string `json:"source"`
+	Channel  string `json:"channel"`
+	Checksum string `json:"checksum"`
+}
+
+// Gets the channel of the version
+func (v *RegistryVersion) channel() string {
+	if v.Channel == "" {
+		return ChannelStable
+	}
+
+	return v.Channel
+}
+
+// Gets the artifact for the current platform
+func (v *RegistryVersion) artifact() (*RegistryArtifact, bool) {
+	if artifact, has := v.Artifacts[fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)]; has {
+		return artifact, true
+	}
+
+	artifact, has := v.Artifacts["default"]
+	return artifact, has
+}
+
+// Registry finds, downloads and verifies extensions published to the configured sources
+type Registry struct {
+	httpClient        httputil.HttpClient
+	userConfigManager config.UserConfigManager
+}
+
+// Creates a new extension registry client
+func NewRegistry(httpClient httputil.HttpClient, userConfigManager config.UserConfigManager) *Registry {
+	return &Registry{
+		httpClient:        httpClient,
+		userConfigManager: userConfigManager,
+	}
+}
+
+// Gets the configured extension sources sorted by name
+func (r *Registry) Sources() ([]*Source, error) {
+	azdConfig, err := r.userConfigManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []*Source{}
+	value, has := azdConfig.Get(sourcesConfigPath)
+	if !has {
+		return sources, nil
+	}
+
+	sourcesJson, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceMap := map[string]*Source{}
+	if err := json.Unmarshal(sourcesJson, &sourceMap); err != nil {
+		return nil, fmt.Errorf("failed parsing extension sources from config: %w", err)
+	}
+
+	for name, source := range sourceMap {
+		source.Name = name
+		sources = append(sources, source)
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Name < sources[j].Name
+	})
+
+	return sources, nil
+}
+
+// Gets the configured extension source with the specified name
+func (r *Registry) Source(name string) (*Source, error) {
+	sources, err := r.Sources()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		if source.Name == name {
+			return source, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrSourceNotFound, name)
+}
+
+// Adds or replaces an extension source
+func (r *Registry) AddSource(source *Source) error {
+	if !extensionNameRegex.MatchString(source.Name) {
+		return fmt.Errorf("source name '%s' must only contain lower case letters, numbers and hyphens", source.Name)
+	}
+
+	if source.Url == "" {
+		return errors.New("source url is required")
+	}
+
+	if _, err := parsePublicKey(source.PublicKey); err != nil {
+		return err
+	}
+
+	azdConfig, err := r.userConfigManager.Load()
+	if err != nil {
+		return err
+	}
+
+	err = azdConfig.Set(fmt.Sprintf("%s.%s", sourcesConfigPath, source.Name), map[string]any{
+		"url":       source.Url,
+		"publicKey": source.PublicKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.userConfigManager.Save(azdConfig)
+}
+
+// Removes the extension source with the specified name
+func (r *Registry) RemoveSource(name string) error {
+	if _, err := r.Source(name); err != nil {
+		return err
+	}
+
+	azdConfig, err := r.userConfigManager.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := azdConfig.Unset(fmt.Sprintf("%s.%s", sourcesConfigPath, name)); err != nil {
+		return err
+	}
+
+	return r.userConfigManager.Save(azdConfig)
+}
+
+// Gets the index published by the source
+func (r *Registry) Index(ctx context.Context, source *Source) (*RegistryIndex, error) {
+	indexBytes, err := r.read(ctx, source.Url)
+	if err != nil {
+		return nil, fmt.Errorf("reading index of extension source '%s': %w", source.Name, err)
+	}
+
+	var index RegistryIndex
+	if err := json.Unmarshal(indexBytes, &index); err != nil {
+		return nil, fmt.Errorf("parsing index of extension source '%s': %w", source.Name, err)
+	}
+
+	return &index, nil
+}
+
+// RegistryMatch is a version of an extension found within a source
+type RegistryMatch struct {
+	Source    *Source
+	Extension *RegistryExtension
+	Version   *RegistryVersion
+}
+
+// Finds the extension within the configured sources.
+// When version is empty the latest version within the channel is returned.
+// When sourceName is empty all sources are searched in order.
+func (r *Registry) Find(
+	ctx context.Context,
+	name string,
+	version string,
+	channel string,
+	sourceName string,
+) (*RegistryMatch, error) {
+	sources, err := r.Sources()
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceName != "" {
+		source, err := r.Source(sourceName)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = []*Source{source}
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf(
+			"%w: no extension sources are configured. Run `azd extension source add` to add one",
+			ErrExtensionNotFound,
+		)
+	}
+
+	for _, source := range sources {
+		index, err := r.Index(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, extension := range index.Extensions {
+			if extension.Name != name {
+				continue
+			}
+
+			match, err := findVersion(extension, version, channel)
+			if err != nil {
+				return nil, err
+			}
+
+			return &RegistryMatch{
+				Source:    source,
+				Extension: extension,
+				Version:   match,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrExtensionNotFound, name)
+}
+
+// Downloads the artifact of the matched version, verifies its checksum & signature and extracts it into a temporary
+// directory. The caller is responsible for removing the returned directory.
+func (r *Registry) Download(ctx context.Context, match *RegistryMatch) (string, *InstallMetadata, error) {
+	artifact, has := match.Version.artifact()
+	if !has {
+		return "", nil, fmt.Errorf(
+			"%w: '%s' version '%s' is not available for %s/%s",
+			ErrNoCompatibleVersion,
+			match.Extension.Name,
+			match.Version.Version,
+			runtime.GOOS,
+			runtime.GOARCH,
+		)
+	}
+
+	artifactUrl, err := resolveUrl(match.Source.Url, artifact.Url)
+	if err != nil {
+		return "", nil, err
+	}
+
+	artifactBytes, err := r.read(ctx, artifactUrl)
+	if err != nil {
+		return "", nil, fmt.Errorf("downloading extension '%s': %w", match.Extension.Name, err)
+	}
+
+	if err := verifyArtifact(match.Source, artifact, artifactBytes); err != nil {
+		return "", nil, fmt.Errorf("verifying extension '%s': %w", match.Extension.Name, err)
+	}
+
+	targetDir, err := os.MkdirTemp("", "azd-extension-*")
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := extractArchive(artifactUrl, artifactBytes, targetDir); err != nil {
+		os.RemoveAll(targetDir)
+		return "", nil, fmt.Errorf("extracting extension '%s': %w", match.Extension.Name, err)
+	}
+
+	return targetDir, &InstallMetadata{
+		Source:   match.Source.Name,
+		Channel:  match.Version.channel(),
+		Checksum: artifact.Checksum,
+	}, nil
+}
+
+// Reads the content of a http(s) url or local file path
+func (r *Registry) read(ctx context.Context, location string) ([]byte, error) {
+	parsed, err := url.Parse(location)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return os.ReadFile(strings.TrimPrefix(location, "file://"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned status %d", location, res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// Finds the requested version, or the latest version within the channel
+func findVersion(extension *RegistryExtension, version string, channel string) (*RegistryVersion, error) {
+	var latest *RegistryVersion
+	var latestVersion semver.Version
+
+	for _, candidate := range extension.Versions {
+		if version != "" {
+			if candidate.Version == version {
+				return candidate, nil
+			}
+
+			continue
+		}
+
+		if channel != ChannelPreview && candidate.channel() != ChannelStable {
+			continue
+		}
+
+		candidateVersion, err := semver.ParseTolerant(candidate.Version)
+		if err != nil {
+			continue
+		}
+
+		if latest == nil || candidateVersion.GT(latestVersion) {
+			latest = candidate
+			latestVersion = candidateVersion
+		}
+	}
+
+	if latest == nil {
+		if version != "" {
+			return nil, fmt.Errorf("%w: '%s' version '%s'", ErrNoCompatibleVersion, extension.Name, version)
+		}
+
+		return nil, fmt.Errorf("%w: '%s' has no %s versions", ErrNoCompatibleVersion, extension.Name, channel)
+	}
+
+	return latest, nil
+}
+
+// Resolves artifact urls relative to the registry index
+func resolveUrl(indexUrl string, artifactUrl string) (string, error) {
+	if parsed, err := url.Parse(artifactUrl); err == nil && parsed.IsAbs() {
+		return artifactUrl, nil
+	}
+
+	if parsed, err := url.Parse(indexUrl); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") {
+		relative, err := url.Parse(artifactUrl)
+		if err != nil {
+			return "", err
+		}
+
+		return parsed.ResolveReference(relative).String(), nil
+	}
+
+	if filepath.IsAbs(artifactUrl) {
+		return artifactUrl, nil
+	}
+
+	return filepath.Join(filepath.Dir(strings.TrimPrefix(indexUrl, "file://")), artifactUrl), nil
+}
+
+// Verifies the checksum of the artifact and its signature against the public key pinned for the source
+func verifyArtifact(source *Source, artifact *RegistryArtifact, artifactBytes []byte) error {
+	checksum := sha256.Sum256(artifactBytes)
+	if !strings.EqualFold(hex.EncodeToString(checksum[:]), artifact.Checksum) {
+		return ErrChecksumMismatch
+	}
+
+	publicKey, err := parsePublicKey(source.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(artifact.Signature)
+	if err != nil || artifact.Signature == "" {
+		return fmt.Errorf("%w: artifact is not signed", ErrSignatureInvalid)
+	}
+
+	if !ed25519.Verify(publicKey, artifactBytes, signature) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+func parsePublicKey(value string) (ed25519.PublicKey, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, errors.New("public key must be a base64 encoded ed25519 public key")
+	}
+
+	return ed25519.PublicKey(publicKey), nil
+}
+
+// Writes the install metadata into the extension directory
+func writeInstallMetadata(extensionPath string, metadata *InstallMetadata) error {
+	metadataJson, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(extensionPath, installFileName), metadataJson, osutil.PermissionFile)
+}
+
+// Reads the install metadata of the extension when the extension was installed from a registry
+func readInstallMetadata(extensionPath string) *InstallMetadata {
+	metadataJson, err := os.ReadFile(filepath.Join(extensionPath, installFileName))
+	if err != nil {
+		return nil
+	}
+
+	var metadata InstallMetadata
+	if err := json.Unmarshal(metadataJson, &metadata); err != nil {
+		return nil
+	}
+
+	return &metadata
+}
@@ -0,0 +1,231 @@
+package extensions
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_Sources(t *testing.T) {
+	registry := NewRegistry(http.DefaultClient, &memoryUserConfigManager{config: config.NewEmptyConfig()})
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	err = registry.AddSource(&Source{
+		Name:      "contoso",
+		Url:       "https://extensions.contoso.com/index.json",
+		PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+	})
+	require.NoError(t, err)
+
+	sources, err := registry.Sources()
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.Equal(t, "contoso", sources[0].Name)
+	require.Equal(t, "https://extensions.contoso.com/index.json", sources[0].Url)
+
+	err = registry.AddSource(&Source{Name: "invalid", Url: "https://invalid", PublicKey: "not-a-key"})
+	require.Error(t, err)
+
+	require.NoError(t, registry.RemoveSource("contoso"))
+	require.ErrorIs(t, registry.RemoveSource("contoso"), ErrSourceNotFound)
+}
+
+func Test_Registry_Install(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	registryDir := t.TempDir()
+	artifact := createArtifact(t, "hello", "1.0.0")
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, "hello-1.0.0.tar.gz"), artifact, osutil.PermissionFile))
+
+	previewArtifact := createArtifact(t, "hello", "2.0.0-beta.1")
+	err = os.WriteFile(filepath.Join(registryDir, "hello-2.0.0.tar.gz"), previewArtifact, osutil.PermissionFile)
+	require.NoError(t, err)
+
+	index := RegistryIndex{
+		Extensions: []*RegistryExtension{
+			{
+				Name: "hello",
+				Versions: []*RegistryVersion{
+					{
+						Version: "1.0.0",
+						Artifacts: map[string]*RegistryArtifact{
+							"default": newRegistryArtifact("hello-1.0.0.tar.gz", artifact, privateKey),
+						},
+					},
+					{
+						Version: "2.0.0-beta.1",
+						Channel: ChannelPreview,
+						Artifacts: map[string]*RegistryArtifact{
+							"default": newRegistryArtifact("hello-2.0.0.tar.gz", previewArtifact, privateKey),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	indexPath := filepath.Join(registryDir, "index.json")
+	indexJson, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(indexPath, indexJson, osutil.PermissionFile))
+
+	newRegistry := func(t *testing.T, publicKey ed25519.PublicKey) *Registry {
+		registry := NewRegistry(http.DefaultClient, &memoryUserConfigManager{config: config.NewEmptyConfig()})
+		err := registry.AddSource(&Source{
+			Name:      "local",
+			Url:       indexPath,
+			PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+		})
+		require.NoError(t, err)
+
+		return registry
+	}
+
+	t.Run("Stable", func(t *testing.T) {
+		registry := newRegistry(t, publicKey)
+		match, err := registry.Find(context.Background(), "hello", "", ChannelStable, "")
+		require.NoError(t, err)
+		require.Equal(t, "1.0.0", match.Version.Version)
+
+		dir, metadata, err := registry.Download(context.Background(), match)
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		manager := NewManager(t.TempDir())
+		extension, err := manager.InstallDownloaded(dir, metadata, false)
+		require.NoError(t, err)
+		require.Equal(t, "1.0.0", extension.Version)
+		require.Equal(t, "local", extension.Install.Source)
+		require.Equal(t, ChannelStable, extension.Install.Channel)
+
+		installed, err := manager.Get("hello")
+		require.NoError(t, err)
+		require.Equal(t, "local", installed.Install.Source)
+	})
+
+	t.Run("Preview", func(t *testing.T) {
+		registry := newRegistry(t, publicKey)
+		match, err := registry.Find(context.Background(), "hello", "", ChannelPreview, "local")
+		require.NoError(t, err)
+		require.Equal(t, "2.0.0-beta.1", match.Version.Version)
+	})
+
+	t.Run("SpecificVersion", func(t *testing.T) {
+		registry := newRegistry(t, publicKey)
+		match, err := registry.Find(context.Background(), "hello", "2.0.0-beta.1", ChannelStable, "")
+		require.NoError(t, err)
+		require.Equal(t, "2.0.0-beta.1", match.Version.Version)
+
+		_, err = registry.Find(context.Background(), "hello", "3.0.0", ChannelStable, "")
+		require.ErrorIs(t, err, ErrNoCompatibleVersion)
+	})
+
+	t.Run("UntrustedSignature", func(t *testing.T) {
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		registry := newRegistry(t, otherKey)
+		match, err := registry.Find(context.Background(), "hello", "", ChannelStable, "")
+		require.NoError(t, err)
+
+		_, _, err = registry.Download(context.Background(), match)
+		require.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		registry := newRegistry(t, publicKey)
+		match, err := registry.Find(context.Background(), "hello", "", ChannelStable, "")
+		require.NoError(t, err)
+
+		match.Version.Artifacts["default"].Checksum = "0000"
+		_, _, err = registry.Download(context.Background(), match)
+		require.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		registry := newRegistry(t, publicKey)
+		_, err := registry.Find(context.Background(), "missing", "", ChannelStable, "")
+		require.ErrorIs(t, err, ErrExtensionNotFound)
+	})
+}
+
+func Test_archiveTargetPath(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := archiveTargetPath(dir, "../evil.sh")
+	require.Error(t, err)
+
+	targetPath, err := archiveTargetPath(dir, "bin/hello.sh")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "bin", "hello.sh"), targetPath)
+}
+
+func newRegistryArtifact(url string, artifact []byte, privateKey ed25519.PrivateKey) *RegistryArtifact {
+	checksum := sha256.Sum256(artifact)
+
+	return &RegistryArtifact{
+		Url:       url,
+		Checksum:  hex.EncodeToString(checksum[:]),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, artifact)),
+	}
+}
+
+// Creates a .tar.gz artifact with the extension inside a top level directory
+func createArtifact(t *testing.T, name string, version string) []byte {
+	files := map[string]string{
+		name + "/" + ManifestFileName: "name: " + name + "\nversion: " + version + "\nentryPoint: hello.sh\n",
+		name + "/hello.sh":            "echo hello",
+	}
+
+	var buffer bytes.Buffer
+	gzWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	for path, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     path,
+			Mode:     0755,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+
+		_, err = tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzWriter.Close())
+
+	return buffer.Bytes()
+}
+
+type memoryUserConfigManager struct {
+	config config.Config
+}
+
+func (m *memoryUserConfigManager) Load() (config.Config, error) {
+	return m.config, nil
+}
+
+func (m *memoryUserConfigManager) Save(cfg config.Config) error {
+	m.config = cfg
+	return nil
+}
@@ -0,0 +1,171 @@
+package extensions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/spf13/cobra"
+)
+
+// Runner executes installed extensions
+type Runner struct {
+	commandRunner exec.CommandRunner
+}
+
+// Creates a new extension runner
+func NewRunner(commandRunner exec.CommandRunner) *Runner {
+	return &Runner{
+		commandRunner: commandRunner,
+	}
+}
+
+// Options for invoking an extension
+type InvokeOptions struct {
+	// The arguments passed to the extension
+	Args []string
+	// Additional environment variables available to the extension
+	Env []string
+	// When true binds the stdin, stdout & stderr of the extension to the running console
+	Interactive bool
+	// The input written to the stdin of the extension
+	StdIn io.Reader
+	// Receives a copy of the text the extension writes to stderr
+	Stderr io.Writer
+}
+
+// Invokes the extension with the specified options
+func (r *Runner) Invoke(ctx context.Context, extension *Extension, options *InvokeOptions) (exec.RunResult, error) {
+	runArgs := exec.NewRunArgs(extension.EntryPointPath(), options.Args...).
+		WithEnv(options.Env).
+		WithInteractive(options.Interactive)
+
+	if options.StdIn != nil {
+		runArgs = runArgs.WithStdIn(options.StdIn)
+	}
+
+	runArgs.Stderr = options.Stderr
+
+	return r.commandRunner.Run(ctx, runArgs)
+}
+
+// Invokes the extension writing the request as JSON to stdin and parsing the JSON written to stdout into the response.
+// Each line the extension writes to stderr is passed to the optional progress function.
+func (r *Runner) InvokeJson(
+	ctx context.Context,
+	extension *Extension,
+	args []string,
+	env []string,
+	request any,
+	response any,
+	progress func(string),
+) error {
+	requestJson, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshalling extension request: %w", err)
+	}
+
+	var stderr io.Writer
+	if progress != nil {
+		stderr = &progressWriter{progress: progress}
+	}
+
+	result, err := r.Invoke(ctx, extension, &InvokeOptions{
+		Args:   args,
+		Env:    env,
+		StdIn:  bytes.NewReader(requestJson),
+		Stderr: stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("extension '%s' failed: %w", extension.Name, err)
+	}
+
+	if strings.TrimSpace(result.Stdout) == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(result.Stdout), response); err != nil {
+		return fmt.Errorf("extension '%s' returned an invalid response: %w", extension.Name, err)
+	}
+
+	return nil
+}
+
+// Gets shell completions from the extension.
+// Extensions built with cobra support completions with the hidden `__complete` command. Failures are treated as
+// not having any completions.
+func (r *Runner) Complete(
+	ctx context.Context,
+	extension *Extension,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	completeArgs := append([]string{cobra.ShellCompRequestCmd}, args...)
+	completeArgs = append(completeArgs, toComplete)
+
+	result, err := r.Invoke(ctx, extension, &InvokeOptions{Args: completeArgs})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return parseCompletions(result.Stdout)
+}
+
+// Parses the output of the cobra `__complete` command.
+// Each line contains a completion and the last line contains the directive. (ex. `:4`)
+func parseCompletions(output string) ([]string, cobra.ShellCompDirective) {
+	completions := []string{}
+	directive := cobra.ShellCompDirectiveDefault
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if value, has := strings.CutPrefix(line, ":"); has {
+			if parsed, err := strconv.Atoi(value); err == nil {
+				directive = cobra.ShellCompDirective(parsed)
+			}
+
+			continue
+		}
+
+		completions = append(completions, line)
+	}
+
+	return completions, directive
+}
+
+// progressWriter reports each complete line written by the extension
+type progressWriter struct {
+	progress func(string)
+	buffer   bytes.Buffer
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			// Keep the partial line until the rest of it is written
+			w.buffer.Reset()
+			w.buffer.WriteString(line)
+			break
+		}
+
+		if message := strings.TrimSpace(line); message != "" {
+			w.progress(message)
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,46 @@
+package extensions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Runner_Invoke(t *testing.T) {
+	extension := &Extension{
+		Manifest: Manifest{Name: "hello", EntryPoint: "hello.sh"},
+		Path:     "/extensions/hello",
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "hello.sh")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.Equal(t, extension.EntryPointPath(), args.Cmd)
+		require.Equal(t, []string{"world", "--loud"}, args.Args)
+		require.Equal(t, []string{"AZURE_ENV_NAME=dev"}, args.Env)
+		require.True(t, args.Interactive)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	runner := NewRunner(mockContext.CommandRunner)
+	_, err := runner.Invoke(*mockContext.Context, extension, &InvokeOptions{
+		Args:        []string{"world", "--loud"},
+		Env:         []string{"AZURE_ENV_NAME=dev"},
+		Interactive: true,
+	})
+	require.NoError(t, err)
+}
+
+func Test_parseCompletions(t *testing.T) {
+	completions, directive := parseCompletions("world\nwide\n:4\n")
+
+	require.Equal(t, []string{"world", "wide"}, completions)
+	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
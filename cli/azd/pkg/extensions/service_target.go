@@ -0,0 +1,270 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The command extensions implement to provide service targets.
+// azd invokes `<entryPoint> service-target <operation>` writing a JSON request to stdin and reading a
+// JSON response from stdout. Each line written to stderr is reported as progress.
+const ServiceTargetCommand = "service-target"
+
+// The service target operations invoked on extensions
+type ServiceTargetOperation string
+
+const (
+	ServiceTargetPackage   ServiceTargetOperation = "package"
+	ServiceTargetDeploy    ServiceTargetOperation = "deploy"
+	ServiceTargetEndpoints ServiceTargetOperation = "endpoints"
+)
+
+// ServiceTargetRequest is the JSON payload written to the stdin of the extension
+type ServiceTargetRequest struct {
+	// The host kind of the service. (ex. `edge`)
+	Kind string `json:"kind"`
+	// The service being packaged or deployed
+	Service ServiceTargetService `json:"service"`
+	// The name of the azd environment
+	EnvironmentName string `json:"environmentName"`
+	// The package produced by the framework service or the package operation
+	Package *project.ServicePackageResult `json:"package,omitempty"`
+	// The Azure resource associated with the service when one exists
+	TargetResource *ServiceTargetResource `json:"targetResource,omitempty"`
+}
+
+// ServiceTargetService describes the service from azure.yaml
+type ServiceTargetService struct {
+	Name         string         `json:"name"`
+	Path         string         `json:"path"`
+	Language     string         `json:"language"`
+	ResourceName string         `json:"resourceName,omitempty"`
+	OutputPath   string         `json:"outputPath,omitempty"`
+	Config       map[string]any `json:"config,omitempty"`
+}
+
+// ServiceTargetResource describes the Azure resource associated with a service
+type ServiceTargetResource struct {
+	SubscriptionId    string `json:"subscriptionId,omitempty"`
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+	ResourceName      string `json:"resourceName,omitempty"`
+	ResourceType      string `json:"resourceType,omitempty"`
+}
+
+// ServiceTargetResponse is the JSON payload the extension writes to stdout
+type ServiceTargetResponse struct {
+	// The path of the package produced by the package operation
+	PackagePath string `json:"packagePath,omitempty"`
+	// The ID of the resource deployed to by the deploy operation
+	TargetResourceId string `json:"targetResourceId,omitempty"`
+	// The endpoints exposed by the service
+	Endpoints []string `json:"endpoints,omitempty"`
+	// Additional details displayed to the user and included in JSON output
+	Details any `json:"details,omitempty"`
+}
+
+// serviceTarget is a project.ServiceTarget implemented by an extension
+type serviceTarget struct {
+	extension *Extension
+	kind      project.ServiceTargetKind
+	runner    *Runner
+	env       *environment.Environment
+}
+
+// Creates a new service target that delegates package, deploy & endpoints to the extension
+func NewServiceTarget(
+	extension *Extension,
+	kind project.ServiceTargetKind,
+	runner *Runner,
+	env *environment.Environment,
+) project.ServiceTarget {
+	return &serviceTarget{
+		extension: extension,
+		kind:      kind,
+		runner:    runner,
+		env:       env,
+	}
+}
+
+// Extension service targets validate their own tools
+func (st *serviceTarget) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the extension service target
+func (st *serviceTarget) Initialize(ctx context.Context, serviceConfig *project.ServiceConfig) error {
+	return nil
+}
+
+// Packages the service using the extension
+func (st *serviceTarget) Package(
+	ctx context.Context,
+	serviceConfig *project.ServiceConfig,
+	packageOutput *project.ServicePackageResult,
+) *async.TaskWithProgress[*project.ServicePackageResult, project.ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*project.ServicePackageResult, project.ServiceProgress]) {
+			request := st.newRequest(serviceConfig, packageOutput, nil)
+			response, err := st.invoke(ctx, ServiceTargetPackage, request, task.SetProgress)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			packagePath := response.PackagePath
+			if packagePath == "" && packageOutput != nil {
+				packagePath = packageOutput.PackagePath
+			}
+
+			var build *project.ServiceBuildResult
+			if packageOutput != nil {
+				build = packageOutput.Build
+			}
+
+			task.SetResult(&project.ServicePackageResult{
+				Build:       build,
+				PackagePath: packagePath,
+				Details:     response.Details,
+			})
+		},
+	)
+}
+
+// Deploys the service using the extension
+func (st *serviceTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *project.ServiceConfig,
+	packageOutput *project.ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*project.ServiceDeployResult, project.ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*project.ServiceDeployResult, project.ServiceProgress]) {
+			request := st.newRequest(serviceConfig, packageOutput, targetResource)
+			response, err := st.invoke(ctx, ServiceTargetDeploy, request, task.SetProgress)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&project.ServiceDeployResult{
+				Package:          packageOutput,
+				TargetResourceId: response.TargetResourceId,
+				Kind:             st.kind,
+				Endpoints:        response.Endpoints,
+				Details:          response.Details,
+			})
+		},
+	)
+}
+
+// Gets the endpoints of the service from the extension
+func (st *serviceTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *project.ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	request := st.newRequest(serviceConfig, nil, targetResource)
+	response, err := st.invoke(ctx, ServiceTargetEndpoints, request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Endpoints, nil
+}
+
+func (st *serviceTarget) newRequest(
+	serviceConfig *project.ServiceConfig,
+	packageOutput *project.ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *ServiceTargetRequest {
+	resourceName, _ := serviceConfig.ResourceName.Envsubst(st.env.Getenv)
+
+	request := &ServiceTargetRequest{
+		Kind: string(st.kind),
+		Service: ServiceTargetService{
+			Name:         serviceConfig.Name,
+			Path:         serviceConfig.Path(),
+			Language:     string(serviceConfig.Language),
+			ResourceName: resourceName,
+			OutputPath:   serviceConfig.OutputPath,
+			Config:       serviceConfig.Config,
+		},
+		EnvironmentName: st.env.GetEnvName(),
+		Package:         packageOutput,
+	}
+
+	if targetResource != nil {
+		request.TargetResource = &ServiceTargetResource{
+			SubscriptionId:    targetResource.SubscriptionId(),
+			ResourceGroupName: targetResource.ResourceGroupName(),
+			ResourceName:      targetResource.ResourceName(),
+			ResourceType:      targetResource.ResourceType(),
+		}
+	}
+
+	return request
+}
+
+// Invokes the service target operation on the extension and parses the response
+func (st *serviceTarget) invoke(
+	ctx context.Context,
+	operation ServiceTargetOperation,
+	request *ServiceTargetRequest,
+	progress func(project.ServiceProgress),
+) (*ServiceTargetResponse, error) {
+	var progressFn func(string)
+	if progress != nil {
+		progressFn = func(message string) {
+			progress(project.NewServiceProgress(message))
+		}
+	}
+
+	response := &ServiceTargetResponse{}
+	err := st.runner.InvokeJson(
+		ctx,
+		st.extension,
+		[]string{ServiceTargetCommand, string(operation)},
+		st.extension.Environ(st.env),
+		request,
+		response,
+		progressFn,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s service '%s': %w", operation, request.Service.Name, err)
+	}
+
+	return response, nil
+}
+
+// Gets the installed extensions keyed by the service target kinds they provide.
+// When multiple extensions provide the same kind the first extension by name is used.
+func (m *Manager) ServiceTargets() (map[string]*Extension, error) {
+	installed, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := map[string]*Extension{}
+	for _, extension := range installed {
+		for _, kind := range extension.ServiceTargets {
+			if _, has := kinds[kind]; has {
+				log.Printf(
+					"skipping service target '%s' from extension '%s' since it is already provided\n",
+					kind,
+					extension.Name,
+				)
+				continue
+			}
+
+			kinds[kind] = extension
+		}
+	}
+
+	return kinds, nil
+}
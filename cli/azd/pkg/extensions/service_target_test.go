@@ -0,0 +1,141 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceTarget(t *testing.T) {
+	extension := &Extension{
+		Manifest: Manifest{
+			Name:           "edge",
+			EntryPoint:     "edge.sh",
+			ServiceTargets: []string{"edge-device"},
+			Permissions:    []string{PermissionEnvironmentRead},
+		},
+		Path: "/extensions/edge",
+	}
+
+	env := environment.EphemeralWithValues("dev", map[string]string{"FLEET": "west"})
+	serviceConfig := &project.ServiceConfig{
+		Project:      &project.ProjectConfig{Path: "/project"},
+		Name:         "api",
+		RelativePath: "src/api",
+		Host:         "edge-device",
+		Language:     project.ServiceLanguagePython,
+		Config:       map[string]any{"fleet": "west"},
+	}
+
+	setupMock := func(mockContext *mocks.MockContext, operation string, response string) *ServiceTargetRequest {
+		request := &ServiceTargetRequest{}
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, fmt.Sprintf("service-target %s", operation))
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.Contains(t, args.Env, "FLEET=west")
+
+			body, err := io.ReadAll(args.StdIn)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, request))
+
+			if args.Stderr != nil {
+				_, err = args.Stderr.Write([]byte("Uploading package\nDone"))
+				require.NoError(t, err)
+			}
+
+			return exec.NewRunResult(0, response, ""), nil
+		})
+
+		return request
+	}
+
+	t.Run("Package", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		request := setupMock(mockContext, "package", `{"packagePath": "/tmp/api.tar"}`)
+
+		target := NewServiceTarget(extension, "edge-device", NewRunner(mockContext.CommandRunner), env)
+		task := target.Package(*mockContext.Context, serviceConfig, &project.ServicePackageResult{PackagePath: "dist"})
+
+		progress := []string{}
+		progressDone := make(chan struct{})
+		go func() {
+			defer close(progressDone)
+			for p := range task.Progress() {
+				progress = append(progress, p.Message)
+			}
+		}()
+
+		result, err := task.Await()
+		<-progressDone
+		require.NoError(t, err)
+		require.Equal(t, "/tmp/api.tar", result.PackagePath)
+		require.Equal(t, []string{"Uploading package"}, progress)
+
+		require.Equal(t, "edge-device", request.Kind)
+		require.Equal(t, "api", request.Service.Name)
+		require.Equal(t, "west", request.Service.Config["fleet"])
+		require.Equal(t, "dev", request.EnvironmentName)
+		require.Equal(t, "dist", request.Package.PackagePath)
+	})
+
+	t.Run("Deploy", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		request := setupMock(mockContext, "deploy", `{"endpoints": ["https://api.edge"], "details": {"devices": 3}}`)
+
+		target := NewServiceTarget(extension, "edge-device", NewRunner(mockContext.CommandRunner), env)
+		targetResource := environment.NewTargetResource("SUB", "RG", "", "")
+		task := target.Deploy(
+			*mockContext.Context,
+			serviceConfig,
+			&project.ServicePackageResult{PackagePath: "/tmp/api.tar"},
+			targetResource,
+		)
+
+		go func() {
+			for range task.Progress() {
+			}
+		}()
+
+		result, err := task.Await()
+		require.NoError(t, err)
+		require.Equal(t, project.ServiceTargetKind("edge-device"), result.Kind)
+		require.Equal(t, []string{"https://api.edge"}, result.Endpoints)
+		require.Equal(t, map[string]any{"devices": float64(3)}, result.Details)
+		require.Equal(t, "RG", request.TargetResource.ResourceGroupName)
+	})
+
+	t.Run("InvalidResponse", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupMock(mockContext, "endpoints", "not json")
+
+		target := NewServiceTarget(extension, "edge-device", NewRunner(mockContext.CommandRunner), env)
+		_, err := target.Endpoints(*mockContext.Context, serviceConfig, nil)
+		require.Error(t, err)
+	})
+}
+
+func Test_Manager_ServiceTargets(t *testing.T) {
+	manager := NewManager(t.TempDir())
+
+	source := createExtensionSource(t, "edge")
+	manifest := "name: edge\nentryPoint: bin/hello.sh\nserviceTargets:\n  - edge-device\n"
+	require.NoError(t, writeManifest(source, manifest))
+
+	_, err := manager.Install(source, false)
+	require.NoError(t, err)
+
+	serviceTargets, err := manager.ServiceTargets()
+	require.NoError(t, err)
+	require.Len(t, serviceTargets, 1)
+	require.Equal(t, "edge", serviceTargets["edge-device"].Name)
+}
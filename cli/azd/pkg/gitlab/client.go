@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// Project is a GitLab project, as returned by the projects API
+type Project struct {
+	Id                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebUrl            string `json:"web_url"`
+	DefaultBranch     string `json:"default_branch"`
+}
+
+// Variable is a CI/CD variable of a GitLab project
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Masked variables are hidden in job logs
+	Masked bool `json:"masked"`
+	// Protected variables are only available to the pipelines of protected branches and tags
+	Protected bool `json:"protected"`
+}
+
+// Client calls the REST API of a GitLab instance, authenticated with a personal access token
+type Client struct {
+	baseUrl  string
+	pipeline runtime.Pipeline
+}
+
+// NewClient creates a client for the GitLab instance on host, ex. gitlab.com
+func NewClient(host string, token string, options *policy.ClientOptions) *Client {
+	pipeline := runtime.NewPipeline("gitlab", "1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{
+			&privateTokenPolicy{token: token},
+		},
+	}, options)
+
+	return &Client{
+		baseUrl:  fmt.Sprintf("https://%s/api/v4", host),
+		pipeline: pipeline,
+	}
+}
+
+// GetProject gets the project at the path, ex. contoso/storefront
+func (c *Client) GetProject(ctx context.Context, projectPath string) (*Project, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, c.projectUrl(projectPath))
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+
+	res, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, fmt.Errorf("getting project '%s': %w", projectPath, runtime.NewResponseError(res))
+	}
+
+	return httputil.ReadRawResponse[Project](res)
+}
+
+// SetVariable creates or updates the CI/CD variable of the project
+func (c *Client) SetVariable(ctx context.Context, projectPath string, variable Variable) error {
+	variablesUrl := c.projectUrl(projectPath) + "/variables"
+
+	res, err := c.sendVariable(ctx, http.MethodPut, variablesUrl+"/"+url.PathEscape(variable.Key), variable)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if runtime.HasStatusCode(res, http.StatusNotFound) {
+		res, err = c.sendVariable(ctx, http.MethodPost, variablesUrl, variable)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK, http.StatusCreated) {
+		return fmt.Errorf("setting variable '%s': %w", variable.Key, runtime.NewResponseError(res))
+	}
+
+	return nil
+}
+
+func (c *Client) sendVariable(ctx context.Context, method string, url string, variable Variable) (*http.Response, error) {
+	req, err := runtime.NewRequest(ctx, method, url)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+
+	if err := runtime.MarshalAsJSON(req, variable); err != nil {
+		return nil, fmt.Errorf("marshalling variable: %w", err)
+	}
+
+	res, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	return res, nil
+}
+
+// Projects are addressed by their url-encoded path
+func (c *Client) projectUrl(projectPath string) string {
+	return c.baseUrl + "/projects/" + url.PathEscape(projectPath)
+}
+
+type privateTokenPolicy struct {
+	token string
+}
+
+// Do authorizes a request with the personal access token
+func (p *privateTokenPolicy) Do(req *policy.Request) (*http.Response, error) {
+	req.Raw().Header.Set("PRIVATE-TOKEN", p.token)
+	return req.Next()
+}
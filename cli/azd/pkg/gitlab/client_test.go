@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(mockContext *mocks.MockContext) *Client {
+	options := azsdk.NewClientOptionsBuilder().WithTransport(mockContext.HttpClient).BuildCoreClientOptions()
+	return NewClient("gitlab.contoso.com", "TOKEN", options)
+}
+
+func Test_Client_GetProject(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.EscapedPath() == "/api/v4/projects/contoso%2Fstorefront" &&
+			request.Header.Get("PRIVATE-TOKEN") == "TOKEN"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"id":      1,
+			"web_url": "https://gitlab.contoso.com/contoso/storefront",
+		})
+	})
+
+	project, err := newTestClient(mockContext).GetProject(*mockContext.Context, "contoso/storefront")
+	require.NoError(t, err)
+	require.Equal(t, "https://gitlab.contoso.com/contoso/storefront", project.WebUrl)
+}
+
+func Test_Client_SetVariable(t *testing.T) {
+	t.Run("Update", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut &&
+				request.URL.EscapedPath() == "/api/v4/projects/contoso%2Fstorefront/variables/AZURE_ENV_NAME"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{})
+		})
+
+		err := newTestClient(mockContext).SetVariable(
+			*mockContext.Context, "contoso/storefront", Variable{Key: "AZURE_ENV_NAME", Value: "dev"})
+		require.NoError(t, err)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		})
+
+		var created Variable
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost &&
+				request.URL.EscapedPath() == "/api/v4/projects/contoso%2Fstorefront/variables"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &created))
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, map[string]any{})
+		})
+
+		err := newTestClient(mockContext).SetVariable(
+			*mockContext.Context,
+			"contoso/storefront",
+			Variable{Key: "AZURE_CLIENT_SECRET", Value: "secret-value", Masked: true})
+		require.NoError(t, err)
+		require.Equal(t, Variable{Key: "AZURE_CLIENT_SECRET", Value: "secret-value", Masked: true}, created)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		err := newTestClient(mockContext).SetVariable(
+			*mockContext.Context, "contoso/storefront", Variable{Key: "AZURE_ENV_NAME", Value: "dev"})
+		require.ErrorContains(t, err, "setting variable 'AZURE_ENV_NAME'")
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+var (
+	// hostname of the GitLab SaaS service.
+	GitLabHostName = "gitlab.com"
+	// environment variable that holds the GitLab personal access token, with the api scope
+	GitLabTokenName = "GITLAB_TOKEN"
+	// environment variable holding the ID token of GitLab CI/CD jobs, declared by the `id_tokens` of the job, used to
+	// log in to Azure with `azd auth login --federated-credential-provider gitlab`
+	IdTokenEnvVarName = "GITLAB_OIDC_TOKEN"
+	// audience of the ID tokens exchanged for Azure tokens
+	IdTokenAudience = "api://AzureADTokenExchange"
+	// path to the GitLab CI/CD pipeline yaml
+	PipelineYamlPath = ".gitlab-ci.yml"
+	// default branch for the pipeline and the federated credential
+	DefaultBranch = "main"
+)
+
+// EnsureTokenExists returns the GitLab personal access token from .env or system environment variables, prompting for
+// it when it isn't set.
+func EnsureTokenExists(ctx context.Context, env *environment.Environment, console input.Console) (string, bool, error) {
+	if value, has := env.LookupEnv(GitLabTokenName); has && value != "" {
+		return value, false, nil
+	}
+
+	console.Message(ctx, fmt.Sprintf(
+		"You need a %s with the api scope. Please create a token by following the instructions here %s",
+		output.WithWarningFormat("GitLab Personal Access Token"),
+		output.WithLinkFormat("https://docs.gitlab.com/ee/user/profile/personal_access_tokens.html")))
+	console.Message(ctx, fmt.Sprintf("(%s this prompt by setting the token to env var: %s)",
+		output.WithWarningFormat("%s", "skip"),
+		output.WithHighLightFormat("%s", GitLabTokenName)))
+
+	token, err := console.Prompt(ctx, input.ConsoleOptions{
+		Message:    "Personal Access Token:",
+		IsPassword: true,
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("asking for gitlab token: %w", err)
+	}
+
+	// set the token as an environment variable for this cmd run
+	// note: the scope of this env var is only this shell invocation and won't be available in the caller parent shell
+	os.Setenv(GitLabTokenName, token)
+	return token, true, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gitlab
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrRemoteHostIsNotGitLab = errors.New("not a gitlab host")
+
+// Remote is a git remote of a project hosted on GitLab, either on gitlab.com or on a self-managed instance
+type Remote struct {
+	// The host of the GitLab instance, ex. gitlab.com
+	Host string
+	// The path of the project including its groups, ex. contoso/web/storefront
+	ProjectPath string
+}
+
+// The namespace of the project, made of its groups, ex. contoso/web
+func (r *Remote) Namespace() string {
+	return r.ProjectPath[:strings.LastIndex(r.ProjectPath, "/")]
+}
+
+// The name of the project, ex. storefront
+func (r *Remote) Name() string {
+	return r.ProjectPath[strings.LastIndex(r.ProjectPath, "/")+1:]
+}
+
+// The url of the project on the GitLab instance
+func (r *Remote) WebUrl() string {
+	return "https://" + r.Host + "/" + r.ProjectPath
+}
+
+var gitLabRemoteGitUrlRegex = regexp.MustCompile(`^git@([^:/]+):(.+?)(?:\.git)?/?$`)
+var gitLabRemoteSshUrlRegex = regexp.MustCompile(`^ssh://git@([^:/]+)(?::\d+)?/(.+?)(?:\.git)?/?$`)
+var gitLabRemoteHttpsUrlRegex = regexp.MustCompile(`^https://(?:[^@/]+@)?([^/]+)/(.+?)(?:\.git)?/?$`)
+
+// Hosts of other providers, which can't be GitLab instances
+var nonGitLabHosts = []string{"github.com", "www.github.com", "dev.azure.com", "ssh.dev.azure.com"}
+
+// ParseRemote extracts the GitLab instance and the project path from the remote url, ex.
+// https://gitlab.com/contoso/web/storefront.git or git@gitlab.contoso.com:contoso/storefront.git
+func ParseRemote(remoteUrl string) (*Remote, error) {
+	for _, r := range []*regexp.Regexp{gitLabRemoteGitUrlRegex, gitLabRemoteSshUrlRegex, gitLabRemoteHttpsUrlRegex} {
+		captures := r.FindStringSubmatch(remoteUrl)
+		if captures == nil {
+			continue
+		}
+
+		host, projectPath := strings.ToLower(captures[1]), captures[2]
+		for _, nonGitLabHost := range nonGitLabHosts {
+			if host == nonGitLabHost || strings.HasSuffix(host, ".visualstudio.com") {
+				return nil, ErrRemoteHostIsNotGitLab
+			}
+		}
+
+		// Projects always belong to a user or a group
+		if !strings.Contains(projectPath, "/") {
+			return nil, ErrRemoteHostIsNotGitLab
+		}
+
+		return &Remote{Host: host, ProjectPath: projectPath}, nil
+	}
+
+	return nil, ErrRemoteHostIsNotGitLab
+}
@@ -0,0 +1,245 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package importer generates the project of the existing resources of a resource group, for `azd init
+// --from-resource-group`.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/resources"
+)
+
+// Resource is a resource of the imported resource group
+type Resource struct {
+	Name string
+	Type string
+	// The kind of web sites, ex. functionapp,linux
+	Kind string
+	// The runtime stack of web sites, ex. NODE|18-lts
+	Runtime string
+}
+
+// The resource types referenced by the generated bicep, by lower case type, with the API version of the reference
+var referencedTypes = map[string]struct {
+	Type       string
+	ApiVersion string
+}{
+	"microsoft.app/containerapps":       {"Microsoft.App/containerApps", "2023-05-01"},
+	"microsoft.app/managedenvironments": {"Microsoft.App/managedEnvironments", "2023-05-01"},
+	"microsoft.appconfiguration/configurationstores": {
+		"Microsoft.AppConfiguration/configurationStores", "2023-03-01"},
+	"microsoft.cache/redis":                      {"Microsoft.Cache/redis", "2023-04-01"},
+	"microsoft.containerregistry/registries":     {"Microsoft.ContainerRegistry/registries", "2023-07-01"},
+	"microsoft.containerservice/managedclusters": {"Microsoft.ContainerService/managedClusters", "2023-10-01"},
+	"microsoft.dbforpostgresql/flexibleservers":  {"Microsoft.DBforPostgreSQL/flexibleServers", "2022-12-01"},
+	"microsoft.documentdb/databaseaccounts":      {"Microsoft.DocumentDB/databaseAccounts", "2023-04-15"},
+	"microsoft.insights/components":              {"Microsoft.Insights/components", "2020-02-02"},
+	"microsoft.keyvault/vaults":                  {"Microsoft.KeyVault/vaults", "2023-07-01"},
+	"microsoft.managedidentity/userassignedidentities": {
+		"Microsoft.ManagedIdentity/userAssignedIdentities", "2023-01-31"},
+	"microsoft.operationalinsights/workspaces": {"Microsoft.OperationalInsights/workspaces", "2022-10-01"},
+	"microsoft.servicebus/namespaces":          {"Microsoft.ServiceBus/namespaces", "2022-10-01-preview"},
+	"microsoft.sql/servers":                    {"Microsoft.Sql/servers", "2022-05-01-preview"},
+	"microsoft.storage/storageaccounts":        {"Microsoft.Storage/storageAccounts", "2023-01-01"},
+	"microsoft.web/serverfarms":                {"Microsoft.Web/serverfarms", "2022-09-01"},
+	"microsoft.web/sites":                      {"Microsoft.Web/sites", "2022-09-01"},
+	"microsoft.web/staticsites":                {"Microsoft.Web/staticSites", "2022-09-01"},
+}
+
+// The languages of the runtime stacks of web sites, by lower case stack, ex. NODE of NODE|18-lts
+var runtimeLanguages = map[string]project.ServiceLanguageKind{
+	"node":            project.ServiceLanguageJavaScript,
+	"python":          project.ServiceLanguagePython,
+	"dotnetcore":      project.ServiceLanguageDotNet,
+	"dotnet":          project.ServiceLanguageDotNet,
+	"dotnet-isolated": project.ServiceLanguageDotNet,
+	"java":            project.ServiceLanguageJava,
+	"tomcat":          project.ServiceLanguageJava,
+	"jboss":           project.ServiceLanguageJava,
+}
+
+// ExistingResource is a resource referenced as an existing resource by the generated bicep
+type ExistingResource struct {
+	Identifier string
+	Name       string
+	Type       string
+	ApiVersion string
+}
+
+// Service is a service of the generated project, hosted by an existing resource
+type Service struct {
+	Name         string
+	ResourceName string
+	Host         project.ServiceTargetKind
+	Language     project.ServiceLanguageKind
+	// The name of the output of the endpoint of the service, set when Uri is set
+	Output string
+	// The bicep expression of the endpoint of the service
+	Uri string
+}
+
+// Import describes the project of the existing resources of a resource group
+type Import struct {
+	ResourceGroup string
+	Services      []Service
+	Existing      []ExistingResource
+	// The resources which aren't referenced, azd doesn't know their type
+	Skipped []string
+	// The web sites which aren't services, the language of their runtime stack isn't detected
+	Undetected []string
+}
+
+// Inspect detects the services hosted by the resources of the resource group, by the type of the resources. Web sites
+// host services when the language of their runtime stack is detected.
+func Inspect(resourceGroup string, groupResources []Resource) *Import {
+	sorted := make([]Resource, len(groupResources))
+	copy(sorted, groupResources)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	imported := &Import{ResourceGroup: resourceGroup}
+	for _, resource := range sorted {
+		referenced, has := referencedTypes[strings.ToLower(resource.Type)]
+		if !has {
+			imported.Skipped = append(imported.Skipped, fmt.Sprintf("%s (%s)", resource.Name, resource.Type))
+			continue
+		}
+
+		identifier := bicepIdentifier(referenced.Type, resource.Name)
+		imported.Existing = append(imported.Existing, ExistingResource{
+			Identifier: identifier,
+			Name:       resource.Name,
+			Type:       referenced.Type,
+			ApiVersion: referenced.ApiVersion,
+		})
+
+		service := Service{Name: resource.Name, ResourceName: resource.Name}
+		switch referenced.Type {
+		case "Microsoft.Web/sites":
+			service.Host = project.AppServiceTarget
+			if strings.Contains(strings.ToLower(resource.Kind), "functionapp") {
+				service.Host = project.AzureFunctionTarget
+			}
+
+			stack, _, _ := strings.Cut(resource.Runtime, "|")
+			language, has := runtimeLanguages[strings.ToLower(stack)]
+			if !has {
+				imported.Undetected = append(imported.Undetected, resource.Name)
+				continue
+			}
+
+			service.Language = language
+			service.Uri = fmt.Sprintf("'https://${%s.properties.defaultHostName}'", identifier)
+		case "Microsoft.Web/staticSites":
+			service.Host = project.StaticWebAppTarget
+			service.Language = project.ServiceLanguageJavaScript
+			service.Uri = fmt.Sprintf("'https://${%s.properties.defaultHostname}'", identifier)
+		case "Microsoft.App/containerApps":
+			service.Host = project.ContainerAppTarget
+			service.Language = project.ServiceLanguageDocker
+			service.Uri = fmt.Sprintf("'https://${%s.properties.latestRevisionFqdn}'", identifier)
+		case "Microsoft.ContainerService/managedClusters":
+			service.Host = project.AksTarget
+			service.Language = project.ServiceLanguageDocker
+		default:
+			continue
+		}
+
+		if service.Uri != "" {
+			service.Output = fmt.Sprintf("SERVICE_%s_URI", strings.ToUpper(envIdentifier(resource.Name)))
+		}
+
+		imported.Services = append(imported.Services, service)
+	}
+
+	return imported
+}
+
+// ProjectConfig returns the configuration of the project, the services target their existing resource by name in the
+// resource group of the project. The code of each service is expected in src/<service name>.
+func (i *Import) ProjectConfig(projectName string) *project.ProjectConfig {
+	projectConfig := &project.ProjectConfig{
+		Name:              projectName,
+		ResourceGroupName: project.NewExpandableString(i.ResourceGroup),
+		Services:          map[string]*project.ServiceConfig{},
+	}
+
+	for _, service := range i.Services {
+		projectConfig.Services[service.Name] = &project.ServiceConfig{
+			Name:         service.Name,
+			ResourceName: project.NewExpandableString(service.ResourceName),
+			RelativePath: filepath.ToSlash(filepath.Join("src", service.Name)),
+			Host:         service.Host,
+			Language:     service.Language,
+		}
+	}
+
+	return projectConfig
+}
+
+var infraTemplates = template.Must(
+	template.New("importer").
+		Funcs(template.FuncMap{"bicepString": bicepString}).
+		ParseFS(resources.ImporterTemplates, "importer/*.bicept"),
+)
+
+// GenerateInfra writes the bicep templates referencing the existing resources to the directory: main.bicep,
+// resources.bicep & main.parameters.json
+func GenerateInfra(imported *Import, dir string) error {
+	files := map[string][]byte{
+		"main.parameters.json": resources.MinimalBicepParameters,
+	}
+
+	for _, name := range []string{"main", "resources"} {
+		buf := bytes.Buffer{}
+		if err := infraTemplates.ExecuteTemplate(&buf, name+".bicept", imported); err != nil {
+			return fmt.Errorf("generating %s.bicep: %w", name, err)
+		}
+
+		files[name+".bicep"] = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(dir, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating infra directory: %w", err)
+	}
+
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), contents, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+var bicepStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `${`, `\${`, "\n", `\n`)
+
+// bicepString returns the value as a bicep string literal
+func bicepString(value string) string {
+	return "'" + bicepStringEscaper.Replace(value) + "'"
+}
+
+var nonIdentifierRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// bicepIdentifier returns the symbolic name of the reference of the resource, ex. sites_app_api
+func bicepIdentifier(resourceType string, name string) string {
+	kind := resourceType[strings.LastIndex(resourceType, "/")+1:]
+	return strings.ToLower(kind) + "_" + envIdentifier(name)
+}
+
+// envIdentifier returns the name as part of the name of an environment variable
+func envIdentifier(name string) string {
+	return nonIdentifierRegex.ReplaceAllString(name, "_")
+}
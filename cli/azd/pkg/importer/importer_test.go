@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Inspect(t *testing.T) {
+	imported := Inspect("rg-app", []Resource{
+		{Name: "web", Type: "Microsoft.Web/staticSites"},
+		{Name: "api", Type: "Microsoft.Web/sites", Kind: "app,linux", Runtime: "PYTHON|3.11"},
+		{Name: "func", Type: "Microsoft.Web/sites", Kind: "functionapp,linux", Runtime: "NODE|18"},
+		{Name: "legacy", Type: "Microsoft.Web/sites", Kind: "app", Runtime: ""},
+		{Name: "plan", Type: "microsoft.web/serverFarms"},
+		{Name: "alerts", Type: "Microsoft.Insights/actionGroups"},
+	})
+
+	require.Equal(t, "rg-app", imported.ResourceGroup)
+	require.Equal(t, []string{"alerts (Microsoft.Insights/actionGroups)"}, imported.Skipped)
+	require.Equal(t, []string{"legacy"}, imported.Undetected)
+
+	identifiers := []string{}
+	for _, existing := range imported.Existing {
+		identifiers = append(identifiers, existing.Identifier)
+	}
+	require.Equal(t, []string{"sites_api", "sites_func", "sites_legacy", "serverfarms_plan", "staticsites_web"}, identifiers)
+
+	require.Equal(t, []Service{
+		{
+			Name:         "api",
+			ResourceName: "api",
+			Host:         project.AppServiceTarget,
+			Language:     project.ServiceLanguagePython,
+			Output:       "SERVICE_API_URI",
+			Uri:          "'https://${sites_api.properties.defaultHostName}'",
+		},
+		{
+			Name:         "func",
+			ResourceName: "func",
+			Host:         project.AzureFunctionTarget,
+			Language:     project.ServiceLanguageJavaScript,
+			Output:       "SERVICE_FUNC_URI",
+			Uri:          "'https://${sites_func.properties.defaultHostName}'",
+		},
+		{
+			Name:         "web",
+			ResourceName: "web",
+			Host:         project.StaticWebAppTarget,
+			Language:     project.ServiceLanguageJavaScript,
+			Output:       "SERVICE_WEB_URI",
+			Uri:          "'https://${staticsites_web.properties.defaultHostname}'",
+		},
+	}, imported.Services)
+}
+
+func Test_Import_ProjectConfig(t *testing.T) {
+	imported := Inspect("rg-app", []Resource{
+		{Name: "orders-api", Type: "Microsoft.App/containerApps"},
+	})
+
+	projectConfig := imported.ProjectConfig("app")
+	require.Equal(t, "app", projectConfig.Name)
+	require.Equal(t, project.NewExpandableString("rg-app"), projectConfig.ResourceGroupName)
+	require.Len(t, projectConfig.Services, 1)
+
+	service := projectConfig.Services["orders-api"]
+	require.Equal(t, "src/orders-api", service.RelativePath)
+	require.Equal(t, project.ContainerAppTarget, service.Host)
+	require.Equal(t, project.ServiceLanguageDocker, service.Language)
+	require.Equal(t, project.NewExpandableString("orders-api"), service.ResourceName)
+}
+
+func Test_GenerateInfra(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "infra")
+	imported := Inspect("rg-o'brien", []Resource{
+		{Name: "orders-api", Type: "Microsoft.App/containerApps"},
+		{Name: "vault", Type: "Microsoft.KeyVault/vaults"},
+	})
+
+	require.NoError(t, GenerateInfra(imported, dir))
+
+	main, err := os.ReadFile(filepath.Join(dir, "main.bicep"))
+	require.NoError(t, err)
+	require.Contains(t, string(main), `param resourceGroupName string = 'rg-o\'brien'`)
+	require.Contains(t, string(main), "output SERVICE_ORDERS_API_URI string = resources.outputs.SERVICE_ORDERS_API_URI")
+
+	resourcesBicep, err := os.ReadFile(filepath.Join(dir, "resources.bicep"))
+	require.NoError(t, err)
+	require.Contains(t, string(resourcesBicep),
+		"resource vaults_vault 'Microsoft.KeyVault/vaults@2023-07-01' existing = {\n  name: 'vault'\n}")
+	require.Contains(t, string(resourcesBicep),
+		"output SERVICE_ORDERS_API_URI string = 'https://${containerapps_orders_api.properties.latestRevisionFqdn}'")
+
+	require.FileExists(t, filepath.Join(dir, "main.parameters.json"))
+}
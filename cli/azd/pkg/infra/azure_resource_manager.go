@@ -51,11 +51,11 @@ func (rm *AzureResourceManager) GetDeploymentResourceOperations(
 
 	subscriptionId := deployment.SubscriptionId()
 	var resourceGroupName string
-	resourceGroupDeployment, ok := deployment.(*ResourceGroupDeployment)
-
-	if ok {
+	if resourceGroupDeployment, ok := deployment.(*ResourceGroupDeployment); ok {
 		// If the scope is a resource group scope get the resource group directly
 		resourceGroupName = resourceGroupDeployment.ResourceGroupName()
+	} else if stack, ok := deployment.(*StackDeployment); ok && stack.ResourceGroupName() != "" {
+		resourceGroupName = stack.ResourceGroupName()
 	} else {
 		// Otherwise find the resource group within the deployment operations
 		for _, operation := range topLevelDeploymentOperations {
@@ -17,6 +17,7 @@ const (
 	AzureResourceTypeContainerApp            AzureResourceType = "Microsoft.App/containerApps"
 	AzureResourceTypeSpringApp               AzureResourceType = "Microsoft.AppPlatform/Spring"
 	AzureResourceTypeContainerAppEnvironment AzureResourceType = "Microsoft.App/managedEnvironments"
+	AzureResourceTypeContainerAppJob         AzureResourceType = "Microsoft.App/jobs"
 	AzureResourceTypeDeployment              AzureResourceType = "Microsoft.Resources/deployments"
 	AzureResourceTypeKeyVault                AzureResourceType = "Microsoft.KeyVault/vaults"
 	AzureResourceTypeLoadTest                AzureResourceType = "Microsoft.LoadTestService/loadTests"
@@ -35,6 +36,7 @@ const (
 	AzureResourceTypeAgentPool               AzureResourceType = "Microsoft.ContainerService/managedClusters/agentPools"
 	AzureResourceTypeCognitiveServiceAccount AzureResourceType = "Microsoft.CognitiveServices/accounts"
 	AzureResourceTypeSearchService           AzureResourceType = "Microsoft.Search/searchServices"
+	AzureResourceTypeWorkbook                AzureResourceType = "Microsoft.Insights/workbooks"
 )
 
 const resourceLevelSeparator = "/"
@@ -66,6 +68,8 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Container App"
 	case AzureResourceTypeContainerAppEnvironment:
 		return "Container Apps Environment"
+	case AzureResourceTypeContainerAppJob:
+		return "Container Apps Job"
 	case AzureResourceTypeServicePlan:
 		return "App Service plan"
 	case AzureResourceTypeCosmosDb:
@@ -96,6 +100,8 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Search service"
 	case AzureResourceTypeSpringApp:
 		return "Azure Spring Apps"
+	case AzureResourceTypeWorkbook:
+		return "Azure Workbook"
 	}
 
 	return ""
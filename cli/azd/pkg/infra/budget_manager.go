@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/benbjohnson/clock"
+)
+
+// What provisioning does when the spend of the environment exceeds its budget
+const (
+	// Provisioning continues after warning that the budget is exceeded
+	BudgetExceededWarn = "warn"
+	// Provisioning continues only once confirmed, it is cancelled when prompts are disabled
+	BudgetExceededConfirm = "confirm"
+)
+
+// The thresholds notifying the contacts of budgets without thresholds, in percentage of the amount
+var defaultBudgetThresholds = []float64{80, 100}
+
+// BudgetOptions describes the monthly budget of the resources of each environment, from the infra.budget section of
+// azure.yaml
+type BudgetOptions struct {
+	// The monthly amount, in the billing currency of the subscription
+	Amount float64 `yaml:"amount"`
+	// The percentages of the amount notifying the contacts, defaults to 80 & 100
+	Thresholds    []float64 `yaml:"thresholds,omitempty"`
+	ContactEmails []string  `yaml:"contactEmails,omitempty"`
+	// The ids of the action groups notified when a threshold is reached
+	ActionGroups []string `yaml:"actionGroups,omitempty"`
+	// warn (default) or confirm
+	OnExceeded string `yaml:"onExceeded,omitempty"`
+}
+
+// Validate fails when the budget can't be created
+func (o *BudgetOptions) Validate() error {
+	if o.Amount <= 0 {
+		return errors.New("infra.budget.amount must be greater than 0")
+	}
+
+	for _, threshold := range o.Thresholds {
+		if threshold <= 0 || threshold > 1000 {
+			return fmt.Errorf("infra.budget.thresholds must be percentages between 0 and 1000, got %v", threshold)
+		}
+	}
+
+	switch o.OnExceeded {
+	case "", BudgetExceededWarn, BudgetExceededConfirm:
+		return nil
+	default:
+		return fmt.Errorf(
+			"infra.budget.onExceeded must be '%s' or '%s', got '%s'",
+			BudgetExceededWarn, BudgetExceededConfirm, o.OnExceeded)
+	}
+}
+
+// BudgetStatus is the spend of an environment compared to its budget
+type BudgetStatus struct {
+	Currency    string
+	Budget      float64
+	MonthToDate float64
+	Forecast    float64
+}
+
+// Exceeded returns whether the spend of the current month exceeds the budget
+func (s *BudgetStatus) Exceeded() bool {
+	return s.MonthToDate > s.Budget
+}
+
+// BudgetName returns the name of the budget of the environment
+func BudgetName(envName string) string {
+	return fmt.Sprintf("azd-%s", envName)
+}
+
+// BudgetManager creates the budgets of environments and compares their spend to their budget
+type BudgetManager struct {
+	azCli           azcli.AzCli
+	costManager     *CostManager
+	resourceManager *AzureResourceManager
+	clock           clock.Clock
+}
+
+func NewBudgetManager(azCli azcli.AzCli, costManager *CostManager, clock clock.Clock) *BudgetManager {
+	return &BudgetManager{
+		azCli:           azCli,
+		costManager:     costManager,
+		resourceManager: NewAzureResourceManager(azCli),
+		clock:           clock,
+	}
+}
+
+// Status returns the spend of the environment compared to the budget, or nil when the environment doesn't have any
+// resource group yet
+func (bm *BudgetManager) Status(
+	ctx context.Context,
+	subscriptionId string,
+	envName string,
+	options BudgetOptions,
+) (*BudgetStatus, error) {
+	report, err := bm.costManager.Report(ctx, subscriptionId, envName)
+	if err != nil {
+		var notFound *azureutil.ResourceNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &BudgetStatus{
+		Currency:    report.Currency,
+		Budget:      options.Amount,
+		MonthToDate: report.MonthToDate,
+		Forecast:    report.Forecast,
+	}, nil
+}
+
+// EnsureBudget creates or updates the budget of the environment. The budget is scoped to the subscription and filtered
+// to the resource groups of the environment, which can span several resource groups.
+func (bm *BudgetManager) EnsureBudget(
+	ctx context.Context,
+	subscriptionId string,
+	envName string,
+	options BudgetOptions,
+) (*azsdk.Budget, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	resourceGroups, err := bm.resourceManager.GetResourceGroupsForEnvironment(ctx, subscriptionId, envName)
+	if err != nil {
+		return nil, fmt.Errorf("discovering resource groups from deployment: %w", err)
+	}
+
+	names := make([]string, 0, len(resourceGroups))
+	for _, resourceGroup := range resourceGroups {
+		names = append(names, resourceGroup.Name)
+	}
+	sort.Strings(names)
+
+	scope := azure.SubscriptionRID(subscriptionId)
+	name := BudgetName(envName)
+
+	existing, err := bm.azCli.GetBudget(ctx, subscriptionId, scope, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The start date of existing budgets can't be changed
+	now := bm.clock.Now().UTC()
+	timePeriod := azsdk.BudgetTimePeriod{StartDate: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)}
+	budget := azsdk.Budget{}
+	if existing != nil {
+		timePeriod = existing.Properties.TimePeriod
+		budget.ETag = existing.ETag
+	}
+
+	budget.Properties = azsdk.BudgetProperties{
+		Category:   "Cost",
+		Amount:     options.Amount,
+		TimeGrain:  "Monthly",
+		TimePeriod: timePeriod,
+		Filter: &azsdk.CostFilter{
+			Dimensions: &azsdk.CostComparison{Name: "ResourceGroupName", Operator: "In", Values: names},
+		},
+		Notifications: budgetNotifications(options),
+	}
+
+	return bm.azCli.CreateOrUpdateBudget(ctx, subscriptionId, scope, name, budget)
+}
+
+// budgetNotifications notifies the contacts of the budget, or the owners of the subscription when the budget doesn't
+// have any contact, at each threshold
+func budgetNotifications(options BudgetOptions) map[string]azsdk.BudgetNotification {
+	thresholds := options.Thresholds
+	if len(thresholds) == 0 {
+		thresholds = defaultBudgetThresholds
+	}
+
+	var contactRoles []string
+	if len(options.ContactEmails) == 0 && len(options.ActionGroups) == 0 {
+		contactRoles = []string{"Owner"}
+	}
+
+	notifications := map[string]azsdk.BudgetNotification{}
+	for _, threshold := range thresholds {
+		notifications[fmt.Sprintf("actual_GreaterThanOrEqualTo_%v_Percent", threshold)] = azsdk.BudgetNotification{
+			Enabled:       true,
+			Operator:      "GreaterThanOrEqualTo",
+			Threshold:     threshold,
+			ThresholdType: "Actual",
+			ContactEmails: options.ContactEmails,
+			ContactRoles:  contactRoles,
+			ContactGroups: options.ActionGroups,
+		}
+	}
+
+	return notifications
+}
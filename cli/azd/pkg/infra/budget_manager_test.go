@@ -0,0 +1,169 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetManagerEnsureBudget(t *testing.T) {
+	budgetPath := "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Consumption/budgets/azd-test-env"
+
+	setupMocks := func(mockContext *mocks.MockContext, existing *azsdk.Budget) *azsdk.Budget {
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourcegroups")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+				Value: []*armresources.ResourceGroup{
+					{
+						ID:       convert.RefOf("/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test-env"),
+						Name:     convert.RefOf("rg-test-env"),
+						Type:     convert.RefOf("Microsoft.Resources/resourceGroups"),
+						Location: convert.RefOf("eastus2"),
+					},
+					{
+						ID:       convert.RefOf("/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-data-test-env"),
+						Name:     convert.RefOf("rg-data-test-env"),
+						Type:     convert.RefOf("Microsoft.Resources/resourceGroups"),
+						Location: convert.RefOf("eastus2"),
+					},
+				},
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Path == budgetPath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if existing == nil {
+				return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, existing)
+		})
+
+		saved := &azsdk.Budget{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && request.URL.Path == budgetPath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := json.Unmarshal(body, saved); err != nil {
+				return nil, err
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, saved)
+		})
+
+		return saved
+	}
+
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC))
+
+	t.Run("Create", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		saved := setupMocks(mockContext, nil)
+
+		azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+		budgetManager := NewBudgetManager(azCli, NewCostManager(azCli, mockClock), mockClock)
+		_, err := budgetManager.EnsureBudget(*mockContext.Context, "SUBSCRIPTION_ID", "test-env", BudgetOptions{
+			Amount: 100,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, 100.0, saved.Properties.Amount)
+		require.Equal(t, "Monthly", saved.Properties.TimeGrain)
+		require.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), saved.Properties.TimePeriod.StartDate)
+		require.Equal(t, []string{"rg-data-test-env", "rg-test-env"}, saved.Properties.Filter.Dimensions.Values)
+
+		// The owners of the subscription are notified at the default thresholds
+		require.Len(t, saved.Properties.Notifications, 2)
+		notification := saved.Properties.Notifications["actual_GreaterThanOrEqualTo_80_Percent"]
+		require.Equal(t, 80.0, notification.Threshold)
+		require.Equal(t, []string{"Owner"}, notification.ContactRoles)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		startDate := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+		saved := setupMocks(mockContext, &azsdk.Budget{
+			ETag: "etag",
+			Properties: azsdk.BudgetProperties{
+				Amount:     50,
+				TimePeriod: azsdk.BudgetTimePeriod{StartDate: startDate},
+			},
+		})
+
+		azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+		budgetManager := NewBudgetManager(azCli, NewCostManager(azCli, mockClock), mockClock)
+		_, err := budgetManager.EnsureBudget(*mockContext.Context, "SUBSCRIPTION_ID", "test-env", BudgetOptions{
+			Amount:        200,
+			Thresholds:    []float64{50},
+			ContactEmails: []string{"team@contoso.com"},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "etag", saved.ETag)
+		require.Equal(t, 200.0, saved.Properties.Amount)
+		require.Equal(t, startDate, saved.Properties.TimePeriod.StartDate)
+		notification := saved.Properties.Notifications["actual_GreaterThanOrEqualTo_50_Percent"]
+		require.Equal(t, []string{"team@contoso.com"}, notification.ContactEmails)
+		require.Empty(t, notification.ContactRoles)
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+		budgetManager := NewBudgetManager(azCli, NewCostManager(azCli, mockClock), mockClock)
+
+		_, err := budgetManager.EnsureBudget(*mockContext.Context, "SUBSCRIPTION_ID", "test-env", BudgetOptions{
+			Amount:     100,
+			OnExceeded: "block",
+		})
+		require.ErrorContains(t, err, "onExceeded")
+	})
+}
+
+func TestBudgetManagerStatus(t *testing.T) {
+	t.Run("NoResourceGroups", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourcegroups")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{})
+		})
+
+		azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+		budgetManager := NewBudgetManager(azCli, NewCostManager(azCli, clock.NewMock()), clock.NewMock())
+
+		// Environments without resources don't spend anything yet
+		status, err := budgetManager.Status(*mockContext.Context, "SUBSCRIPTION_ID", "test-env", BudgetOptions{
+			Amount: 100,
+		})
+		require.NoError(t, err)
+		require.Nil(t, status)
+	})
+
+	t.Run("Exceeded", func(t *testing.T) {
+		require.True(t, (&BudgetStatus{Budget: 100, MonthToDate: 120}).Exceeded())
+		require.False(t, (&BudgetStatus{Budget: 100, MonthToDate: 80, Forecast: 150}).Exceeded())
+	})
+}
@@ -0,0 +1,277 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/benbjohnson/clock"
+)
+
+// The alias of the aggregated cost column of cost queries
+const costAggregationAlias = "totalCost"
+
+// CostReport is the month-to-date & forecasted cost of the resources of an environment
+type CostReport struct {
+	Currency string
+	// The cost of the current month so far
+	MonthToDate float64
+	// The forecasted cost of the current month
+	Forecast  float64
+	Resources []ResourceCost
+}
+
+// ResourceCost is the cost of a single resource of an environment
+type ResourceCost struct {
+	Id            string
+	Name          string
+	Type          string
+	ResourceGroup string
+	// The name of the service hosted by the resource, from the `azd-service-name` tag
+	ServiceName string
+	MonthToDate float64
+	Forecast    float64
+}
+
+// CostManager reports the cost of the resources of an environment from Azure Cost Management
+type CostManager struct {
+	azCli           azcli.AzCli
+	resourceManager *AzureResourceManager
+	clock           clock.Clock
+}
+
+func NewCostManager(azCli azcli.AzCli, clock clock.Clock) *CostManager {
+	return &CostManager{
+		azCli:           azCli,
+		resourceManager: NewAzureResourceManager(azCli),
+		clock:           clock,
+	}
+}
+
+// Report returns the cost of the resources within the resource groups tagged with the environment name.
+// Cost Management only forecasts the cost of a whole resource group, the forecast of each resource is the share of the
+// resource group forecast matching the share of the month-to-date cost of the resource.
+func (cm *CostManager) Report(ctx context.Context, subscriptionId string, envName string) (*CostReport, error) {
+	resourceGroups, err := cm.resourceManager.GetResourceGroupsForEnvironment(ctx, subscriptionId, envName)
+	if err != nil {
+		return nil, fmt.Errorf("discovering resource groups from deployment: %w", err)
+	}
+
+	now := cm.clock.Now().UTC()
+	report := &CostReport{
+		Resources: []ResourceCost{},
+	}
+
+	for _, resourceGroup := range resourceGroups {
+		resources, currency, err := cm.monthToDate(ctx, subscriptionId, resourceGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		monthToDate := 0.0
+		for _, resource := range resources {
+			monthToDate += resource.MonthToDate
+		}
+
+		forecast, forecastCurrency, err := cm.forecast(ctx, subscriptionId, resourceGroup.Id, now)
+		if err != nil {
+			// New resource groups don't have enough usage to forecast, project the current daily cost instead
+			log.Printf("failed forecasting cost of resource group '%s', using the daily cost: %v", resourceGroup.Name, err)
+			forecast = projectMonthlyCost(monthToDate, now)
+		}
+
+		if currency == "" {
+			currency = forecastCurrency
+		}
+
+		for i := range resources {
+			if monthToDate > 0 {
+				resources[i].Forecast = forecast * resources[i].MonthToDate / monthToDate
+			}
+		}
+
+		if report.Currency == "" {
+			report.Currency = currency
+		}
+
+		report.MonthToDate += monthToDate
+		report.Forecast += forecast
+		report.Resources = append(report.Resources, resources...)
+	}
+
+	sort.Slice(report.Resources, func(i, j int) bool {
+		if report.Resources[i].ServiceName != report.Resources[j].ServiceName {
+			return report.Resources[i].ServiceName < report.Resources[j].ServiceName
+		}
+
+		return report.Resources[i].Name < report.Resources[j].Name
+	})
+
+	return report, nil
+}
+
+// monthToDate returns the cost of each resource of the resource group for the current month
+func (cm *CostManager) monthToDate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup azcli.AzCliResource,
+) ([]ResourceCost, string, error) {
+	result, err := cm.azCli.QueryCost(ctx, subscriptionId, resourceGroup.Id, azsdk.CostQuery{
+		Type:      "ActualCost",
+		Timeframe: "MonthToDate",
+		Dataset: azsdk.CostDataset{
+			Granularity: "None",
+			Aggregation: map[string]azsdk.CostAggregation{
+				costAggregationAlias: {Name: "Cost", Function: "Sum"},
+			},
+			Grouping: []azsdk.CostGrouping{
+				{Type: "Dimension", Name: "ResourceId"},
+				{Type: "TagKey", Name: azure.TagKeyAzdServiceName},
+			},
+		},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("querying cost of resource group '%s': %w", resourceGroup.Name, err)
+	}
+
+	costColumn := costColumnIndex(result)
+	resourceIdColumn := result.Column("ResourceId")
+	tagKeyColumn := result.Column("TagKey")
+	tagValueColumn := result.Column("TagValue")
+	currencyColumn := result.Column("Currency")
+
+	currency := ""
+	costs := map[string]*ResourceCost{}
+	for _, row := range result.Rows {
+		resourceId := costString(row, resourceIdColumn)
+		if resourceId == "" {
+			continue
+		}
+
+		resource, has := costs[resourceId]
+		if !has {
+			resource = &ResourceCost{
+				Id:            resourceId,
+				Name:          resourceId,
+				ResourceGroup: resourceGroup.Name,
+			}
+			if parsed, err := arm.ParseResourceID(resourceId); err == nil {
+				resource.Name = parsed.Name
+				resource.Type = parsed.ResourceType.String()
+			}
+
+			costs[resourceId] = resource
+		}
+
+		resource.MonthToDate += costNumber(row, costColumn)
+		if strings.EqualFold(costString(row, tagKeyColumn), azure.TagKeyAzdServiceName) {
+			resource.ServiceName = costString(row, tagValueColumn)
+		}
+
+		if currency == "" {
+			currency = costString(row, currencyColumn)
+		}
+	}
+
+	resources := make([]ResourceCost, 0, len(costs))
+	for _, resource := range costs {
+		resources = append(resources, *resource)
+	}
+
+	return resources, currency, nil
+}
+
+// forecast returns the forecasted cost of the resource group for the current month, including the actual cost
+func (cm *CostManager) forecast(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	now time.Time,
+) (float64, string, error) {
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0).Add(-time.Second)
+
+	result, err := cm.azCli.ForecastCost(ctx, subscriptionId, scope, azsdk.CostQuery{
+		Type:      "ActualCost",
+		Timeframe: "Custom",
+		TimePeriod: &azsdk.CostTimePeriod{
+			From: startOfMonth,
+			To:   endOfMonth,
+		},
+		Dataset: azsdk.CostDataset{
+			Granularity: "Daily",
+			Aggregation: map[string]azsdk.CostAggregation{
+				costAggregationAlias: {Name: "Cost", Function: "Sum"},
+			},
+		},
+		IncludeActualCost:       convert.RefOf(true),
+		IncludeFreshPartialCost: convert.RefOf(false),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	costColumn := costColumnIndex(result)
+	currencyColumn := result.Column("Currency")
+
+	forecast := 0.0
+	currency := ""
+	for _, row := range result.Rows {
+		forecast += costNumber(row, costColumn)
+		if currency == "" {
+			currency = costString(row, currencyColumn)
+		}
+	}
+
+	return forecast, currency, nil
+}
+
+// projectMonthlyCost projects the cost of the whole month from the cost of the elapsed days
+func projectMonthlyCost(monthToDate float64, now time.Time) float64 {
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	daysInMonth := startOfMonth.AddDate(0, 1, -1).Day()
+	elapsed := now.Sub(startOfMonth).Hours() / 24
+	if elapsed < 1 {
+		elapsed = 1
+	}
+
+	return monthToDate / elapsed * float64(daysInMonth)
+}
+
+// The cost column is named after the aggregation alias or the aggregated column depending on the API version
+func costColumnIndex(result *azsdk.CostQueryResult) int {
+	if index := result.Column(costAggregationAlias); index >= 0 {
+		return index
+	}
+
+	return result.Column("Cost")
+}
+
+func costString(row []any, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+
+	value, _ := row[index].(string)
+	return value
+}
+
+func costNumber(row []any, index int) float64 {
+	if index < 0 || index >= len(row) {
+		return 0
+	}
+
+	value, _ := row[index].(float64)
+	return value
+}
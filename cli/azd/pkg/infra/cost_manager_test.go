@@ -0,0 +1,130 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostManagerReport(t *testing.T) {
+	resourceGroupId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test-env"
+	webId := resourceGroupId + "/providers/Microsoft.Web/sites/app-web"
+	planId := resourceGroupId + "/providers/Microsoft.Web/serverFarms/plan"
+
+	costResponse := func(columns []azsdk.CostColumn, rows [][]any) map[string]any {
+		return map[string]any{
+			"properties": azsdk.CostQueryResult{Columns: columns, Rows: rows},
+		}
+	}
+
+	setupMocks := func(mockContext *mocks.MockContext, forecastStatus int) {
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourcegroups")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+				Value: []*armresources.ResourceGroup{
+					{
+						ID:       convert.RefOf(resourceGroupId),
+						Name:     convert.RefOf("rg-test-env"),
+						Type:     convert.RefOf("Microsoft.Resources/resourceGroups"),
+						Location: convert.RefOf("eastus2"),
+					},
+				},
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost &&
+				request.URL.Path == resourceGroupId+"/providers/Microsoft.CostManagement/query"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, costResponse(
+				[]azsdk.CostColumn{
+					{Name: "Cost", Type: "Number"},
+					{Name: "ResourceId", Type: "String"},
+					{Name: "TagKey", Type: "String"},
+					{Name: "TagValue", Type: "String"},
+					{Name: "Currency", Type: "String"},
+				},
+				[][]any{
+					{30.0, webId, "azd-service-name", "web", "USD"},
+					{10.0, planId, "", "", "USD"},
+				},
+			))
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost &&
+				request.URL.Path == resourceGroupId+"/providers/Microsoft.CostManagement/forecast"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if forecastStatus != http.StatusOK {
+				return mocks.CreateEmptyHttpResponse(request, forecastStatus)
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, costResponse(
+				[]azsdk.CostColumn{
+					{Name: "Cost", Type: "Number"},
+					{Name: "UsageDate", Type: "Number"},
+					{Name: "CostStatus", Type: "String"},
+					{Name: "Currency", Type: "String"},
+				},
+				[][]any{
+					{40.0, 20231001.0, "Actual", "USD"},
+					{60.0, 20231031.0, "Forecast", "USD"},
+				},
+			))
+		})
+	}
+
+	t.Run("Forecast", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupMocks(mockContext, http.StatusOK)
+
+		mockClock := clock.NewMock()
+		mockClock.Set(time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC))
+
+		costManager := NewCostManager(mockazcli.NewAzCliFromMockContext(mockContext), mockClock)
+		report, err := costManager.Report(*mockContext.Context, "SUBSCRIPTION_ID", "test-env")
+		require.NoError(t, err)
+
+		require.Equal(t, "USD", report.Currency)
+		require.Equal(t, 40.0, report.MonthToDate)
+		require.Equal(t, 100.0, report.Forecast)
+		require.Len(t, report.Resources, 2)
+
+		// Resources without a service are sorted first
+		require.Equal(t, "plan", report.Resources[0].Name)
+		require.Equal(t, 25.0, report.Resources[0].Forecast)
+		require.Equal(t, "app-web", report.Resources[1].Name)
+		require.Equal(t, "web", report.Resources[1].ServiceName)
+		require.Equal(t, "Microsoft.Web/sites", report.Resources[1].Type)
+		require.Equal(t, 75.0, report.Resources[1].Forecast)
+	})
+
+	t.Run("ForecastUnavailable", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupMocks(mockContext, http.StatusBadRequest)
+
+		mockClock := clock.NewMock()
+		mockClock.Set(time.Date(2023, 11, 11, 0, 0, 0, 0, time.UTC))
+
+		costManager := NewCostManager(mockazcli.NewAzCliFromMockContext(mockContext), mockClock)
+		report, err := costManager.Report(*mockContext.Context, "SUBSCRIPTION_ID", "test-env")
+		require.NoError(t, err)
+
+		// 40 over the first 10 days of a 30 days month
+		require.InDelta(t, 120.0, report.Forecast, 0.001)
+	})
+}
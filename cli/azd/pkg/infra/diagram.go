@@ -0,0 +1,604 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// The formats infrastructure diagrams are rendered as
+const (
+	DiagramFormatMermaid  = "mermaid"
+	DiagramFormatGraphviz = "dot"
+	// SVG diagrams are rendered from the Graphviz diagram by the Graphviz `dot` command
+	DiagramFormatSvg = "svg"
+)
+
+// DiagramFormats returns the formats infrastructure diagrams are rendered as
+func DiagramFormats() []string {
+	return []string{DiagramFormatMermaid, DiagramFormatGraphviz, DiagramFormatSvg}
+}
+
+// DiagramFormatFromPath returns the format of a diagram file from its extension, ex. architecture.md is Mermaid
+func DiagramFormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".mmd", ".mermaid":
+		return DiagramFormatMermaid, nil
+	case ".dot", ".gv":
+		return DiagramFormatGraphviz, nil
+	case ".svg":
+		return DiagramFormatSvg, nil
+	default:
+		return "", fmt.Errorf(
+			"the format of diagram '%s' can't be inferred, use a .md, .mmd, .dot, .gv or .svg file", path)
+	}
+}
+
+// Diagram is the graph of the resources of the compiled infrastructure, grouped by the modules deploying them
+type Diagram struct {
+	Modules  []*DiagramModule
+	Nodes    []*DiagramNode
+	Edges    []DiagramEdge
+	Services []DiagramService
+}
+
+// DiagramModule is a module of the infrastructure, an ARM nested deployment
+type DiagramModule struct {
+	Id    string
+	Label string
+	// The id of the module containing this module, empty for modules of the root template
+	Parent string
+}
+
+// DiagramNode is a resource of the infrastructure
+type DiagramNode struct {
+	Id    string
+	Type  string
+	Label string
+	// The id of the module deploying the resource, empty for resources of the root template
+	Module string
+}
+
+// DiagramEdge is a dependency between resources or modules, From depends on To
+type DiagramEdge struct {
+	From string
+	To   string
+}
+
+// DiagramService is a service of azure.yaml mapped onto the resource or module hosting it
+type DiagramService struct {
+	Name string
+	Host string
+}
+
+// The resource types hosting services
+var hostResourceTypes = map[string]bool{
+	strings.ToLower(string(AzureResourceTypeWebSite)):              true,
+	strings.ToLower(string(AzureResourceTypeStaticWebSite)):        true,
+	strings.ToLower(string(AzureResourceTypeContainerApp)):         true,
+	strings.ToLower(string(AzureResourceTypeManagedCluster)):       true,
+	strings.ToLower(string(AzureResourceTypeSpringApp)):            true,
+	strings.ToLower(string(AzureResourceTypeContainerAppJob)):      true,
+	strings.ToLower("Microsoft.ContainerInstance/containerGroups"): true,
+}
+
+// Matches the azd-service-name tag of object literals, and of createObject() within compiled expressions
+var serviceTagRegex = regexp.MustCompile(`['"]azd-service-name['"]\s*[:,]\s*['"]([^'"]+)['"]`)
+
+type diagramResource struct {
+	Type       string          `json:"type"`
+	Name       string          `json:"name"`
+	Existing   bool            `json:"existing"`
+	DependsOn  []string        `json:"dependsOn"`
+	Properties json.RawMessage `json:"properties"`
+	Tags       json.RawMessage `json:"tags"`
+}
+
+type diagramTemplate struct {
+	Resources json.RawMessage `json:"resources"`
+}
+
+// A resource of a template with its symbolic name, set by templates of language version 2.0
+type namedResource struct {
+	symbolicName string
+	resource     diagramResource
+}
+
+// NewDiagram builds the diagram of a compiled ARM template, ex. the output of `bicep build`. Dependencies are read
+// from dependsOn, services are mapped onto the resources tagged with azd-service-name.
+func NewDiagram(compiledTemplate []byte) (*Diagram, error) {
+	diagram := &Diagram{}
+	builder := &diagramBuilder{diagram: diagram}
+
+	if _, err := builder.addTemplate(compiledTemplate, ""); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(diagram.Services, func(i, j int) bool {
+		return diagram.Services[i].Name < diagram.Services[j].Name
+	})
+
+	return diagram, nil
+}
+
+type diagramBuilder struct {
+	diagram *Diagram
+	nextId  int
+}
+
+func (b *diagramBuilder) newId(prefix string) string {
+	b.nextId++
+	return fmt.Sprintf("%s%d", prefix, b.nextId)
+}
+
+// Adds the resources of the template deployed by the module, returns the ids of the host resources of the template
+func (b *diagramBuilder) addTemplate(templateJson []byte, module string) ([]string, error) {
+	var template diagramTemplate
+	if err := json.Unmarshal(templateJson, &template); err != nil {
+		return nil, fmt.Errorf("parsing compiled template: %w", err)
+	}
+
+	resources, err := templateResources(template.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	// The diagram ids of the resources & modules, by index within the template
+	ids := make([]string, len(resources))
+	hosts := []string{}
+	for i, named := range resources {
+		resource := named.resource
+
+		if strings.EqualFold(resource.Type, string(AzureResourceTypeDeployment)) {
+			id := b.newId("m")
+			ids[i] = id
+			b.diagram.Modules = append(b.diagram.Modules, &DiagramModule{
+				Id:     id,
+				Label:  expressionLabel(named.symbolicName, resource.Name),
+				Parent: module,
+			})
+
+			var properties struct {
+				Template   json.RawMessage `json:"template"`
+				Parameters json.RawMessage `json:"parameters"`
+			}
+			if err := json.Unmarshal(resource.Properties, &properties); err != nil {
+				return nil, fmt.Errorf("parsing module '%s': %w", resource.Name, err)
+			}
+
+			moduleHosts := []string{}
+			if len(properties.Template) > 0 {
+				moduleHosts, err = b.addTemplate(properties.Template, id)
+				if err != nil {
+					return nil, err
+				}
+			}
+			hosts = append(hosts, moduleHosts...)
+
+			// Services are usually tagged by the parameters of the module deploying their host
+			if match := serviceTagRegex.FindSubmatch(properties.Parameters); match != nil {
+				host := id
+				if len(moduleHosts) == 1 {
+					host = moduleHosts[0]
+				}
+
+				b.diagram.Services = append(b.diagram.Services, DiagramService{Name: string(match[1]), Host: host})
+			}
+
+			continue
+		}
+
+		if resource.Existing {
+			continue
+		}
+
+		id := b.newId("r")
+		ids[i] = id
+		b.diagram.Nodes = append(b.diagram.Nodes, &DiagramNode{
+			Id:     id,
+			Type:   resource.Type,
+			Label:  resourceLabel(named.symbolicName, resource),
+			Module: module,
+		})
+
+		if hostResourceTypes[strings.ToLower(resource.Type)] {
+			hosts = append(hosts, id)
+		}
+
+		if match := serviceTagRegex.FindSubmatch(resource.Tags); match != nil {
+			b.diagram.Services = append(b.diagram.Services, DiagramService{Name: string(match[1]), Host: id})
+		}
+	}
+
+	for i, named := range resources {
+		if ids[i] == "" {
+			continue
+		}
+
+		for _, dependency := range named.resource.DependsOn {
+			if index := findDependency(resources, dependency); index >= 0 && ids[index] != "" && index != i {
+				b.diagram.Edges = append(b.diagram.Edges, DiagramEdge{From: ids[i], To: ids[index]})
+			}
+		}
+	}
+
+	return hosts, nil
+}
+
+// Reads the resources of a template, an array or, for templates of language version 2.0, an object of symbolic names
+func templateResources(raw json.RawMessage) ([]namedResource, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	resources := []namedResource{}
+
+	var array []json.RawMessage
+	if err := json.Unmarshal(raw, &array); err == nil {
+		for _, item := range array {
+			var resource diagramResource
+			if err := json.Unmarshal(item, &resource); err != nil {
+				return nil, fmt.Errorf("parsing template resource: %w", err)
+			}
+
+			resources = append(resources, namedResource{resource: resource})
+		}
+
+		return resources, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, fmt.Errorf("parsing template resources: %w", err)
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var resource diagramResource
+		if err := json.Unmarshal(object[name], &resource); err != nil {
+			return nil, fmt.Errorf("parsing template resource '%s': %w", name, err)
+		}
+
+		resources = append(resources, namedResource{symbolicName: name, resource: resource})
+	}
+
+	return resources, nil
+}
+
+var resourceIdRegex = regexp.MustCompile(`^\[(?:\w+\.)?(?:resourceId|subscriptionResourceId|tenantResourceId)\((.*)\)\]$`)
+
+// Finds the resource referenced by dependsOn: a symbolic name, or a resourceId() expression of the resource type &
+// name. Names built from expressions are matched when the template has a single resource of the type.
+func findDependency(resources []namedResource, dependency string) int {
+	for i, named := range resources {
+		if named.symbolicName != "" && named.symbolicName == dependency {
+			return i
+		}
+	}
+
+	match := resourceIdRegex.FindStringSubmatch(dependency)
+	if match == nil {
+		return -1
+	}
+
+	args := splitArguments(match[1])
+	typeIndex := -1
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "'") && strings.Contains(arg, "/") {
+			typeIndex = i
+			break
+		}
+	}
+
+	if typeIndex < 0 {
+		return -1
+	}
+
+	resourceType := strings.Trim(args[typeIndex], "'")
+	nameArgs := args[typeIndex+1:]
+
+	candidates := []int{}
+	for i, named := range resources {
+		if !strings.EqualFold(named.resource.Type, resourceType) {
+			continue
+		}
+
+		candidates = append(candidates, i)
+		if resourceNameMatches(named.resource.Name, nameArgs) {
+			return i
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	return -1
+}
+
+// Whether the name of a resource, ex. [format('{0}/{1}', parameters('server'), 'db')] or 'server/db', matches the
+// name arguments of a resourceId() expression, ex. parameters('server'), 'db'
+func resourceNameMatches(name string, nameArgs []string) bool {
+	if len(nameArgs) == 0 {
+		return false
+	}
+
+	if !strings.HasPrefix(name, "[") {
+		literal := make([]string, 0, len(nameArgs))
+		for _, arg := range nameArgs {
+			if !strings.HasPrefix(arg, "'") {
+				return false
+			}
+			literal = append(literal, strings.Trim(arg, "'"))
+		}
+
+		return name == strings.Join(literal, "/")
+	}
+
+	expression := strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")
+	if len(nameArgs) == 1 {
+		return expression == nameArgs[0]
+	}
+
+	placeholders := make([]string, len(nameArgs))
+	for i := range nameArgs {
+		placeholders[i] = fmt.Sprintf("{%d}", i)
+	}
+
+	return expression == fmt.Sprintf("format('%s', %s)", strings.Join(placeholders, "/"), strings.Join(nameArgs, ", "))
+}
+
+// Splits the top level arguments of a template function call
+func splitArguments(args string) []string {
+	parts := []string{}
+	depth := 0
+	quoted := false
+	start := 0
+
+	for i, c := range args {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(args[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(parts, strings.TrimSpace(args[start:]))
+}
+
+var placeholderRegex = regexp.MustCompile(`\{\d+\}`)
+var literalRegex = regexp.MustCompile(`'([^']*)'`)
+var referenceRegex = regexp.MustCompile(`\b(?:parameters|variables)\('[^']*'\)`)
+
+// A readable label of a module or resource name: the symbolic name, the literal name or the literal parts of the
+// name expression, ex. [format('{0}-web', parameters('env'))] is web
+func expressionLabel(symbolicName string, name string) string {
+	if symbolicName != "" {
+		return symbolicName
+	}
+
+	if !strings.HasPrefix(name, "[") {
+		return name
+	}
+
+	fragments := []string{}
+	// The names of parameters & variables aren't part of the resource name
+	literals := referenceRegex.ReplaceAllString(name, "")
+	for _, match := range literalRegex.FindAllStringSubmatch(literals, -1) {
+		fragment := strings.Trim(placeholderRegex.ReplaceAllString(match[1], ""), "-_./ ")
+		if fragment != "" && !strings.Contains(fragment, "/") {
+			fragments = append(fragments, fragment)
+		}
+	}
+
+	return strings.Join(fragments, "-")
+}
+
+func resourceLabel(symbolicName string, resource diagramResource) string {
+	typeName := GetResourceTypeDisplayName(AzureResourceType(resource.Type))
+	if typeName == "" {
+		typeName = resource.Type[strings.LastIndex(resource.Type, "/")+1:]
+	}
+
+	if name := expressionLabel(symbolicName, resource.Name); name != "" {
+		return fmt.Sprintf("%s\n%s", typeName, name)
+	}
+
+	return typeName
+}
+
+// Mermaid renders the diagram as a Mermaid flowchart
+func (d *Diagram) Mermaid() string {
+	var buf bytes.Buffer
+	buf.WriteString("flowchart LR\n")
+
+	d.writeModules("", 1, &buf, func(module *DiagramModule, indent string) {
+		fmt.Fprintf(&buf, "%ssubgraph %s[\"%s\"]\n", indent, module.Id, mermaidLabel(module.Label))
+	}, func(indent string) {
+		fmt.Fprintf(&buf, "%send\n", indent)
+	}, func(node *DiagramNode, indent string) {
+		fmt.Fprintf(&buf, "%s%s[\"%s\"]\n", indent, node.Id, mermaidLabel(node.Label))
+	})
+
+	for _, edge := range d.Edges {
+		fmt.Fprintf(&buf, "  %s --> %s\n", edge.From, edge.To)
+	}
+
+	for i, service := range d.Services {
+		fmt.Fprintf(&buf, "  s%d{{\"%s\"}} -.->|hosted on| %s\n", i+1, mermaidLabel(service.Name), service.Host)
+	}
+
+	return buf.String()
+}
+
+func mermaidLabel(label string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(label, "\"", "#quot;"), "\n", "<br/>")
+}
+
+// Graphviz renders the diagram as a Graphviz digraph, modules are clusters
+func (d *Diagram) Graphviz() string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph infra {\n  compound=true;\n  rankdir=LR;\n  node [shape=box, style=rounded];\n")
+
+	d.writeModules("", 1, &buf, func(module *DiagramModule, indent string) {
+		fmt.Fprintf(&buf, "%ssubgraph cluster_%s {\n%s  label=%s;\n", indent, module.Id, indent, dotLabel(module.Label))
+	}, func(indent string) {
+		fmt.Fprintf(&buf, "%s}\n", indent)
+	}, func(node *DiagramNode, indent string) {
+		fmt.Fprintf(&buf, "%s%s [label=%s];\n", indent, node.Id, dotLabel(node.Label))
+	})
+
+	// Edges to modules are drawn to a node of the cluster, clipped at the cluster boundary
+	endpoint := func(id string) (string, string, bool) {
+		if !strings.HasPrefix(id, "m") {
+			return id, "", true
+		}
+
+		anchor := d.moduleAnchor(id)
+		return anchor, "cluster_" + id, anchor != ""
+	}
+
+	for _, edge := range d.Edges {
+		from, ltail, okFrom := endpoint(edge.From)
+		to, lhead, okTo := endpoint(edge.To)
+		if !okFrom || !okTo {
+			continue
+		}
+
+		attributes := []string{}
+		if ltail != "" {
+			attributes = append(attributes, "ltail="+ltail)
+		}
+		if lhead != "" {
+			attributes = append(attributes, "lhead="+lhead)
+		}
+
+		if len(attributes) > 0 {
+			fmt.Fprintf(&buf, "  %s -> %s [%s];\n", from, to, strings.Join(attributes, ", "))
+		} else {
+			fmt.Fprintf(&buf, "  %s -> %s;\n", from, to)
+		}
+	}
+
+	for i, service := range d.Services {
+		to, lhead, ok := endpoint(service.Host)
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "  s%d [label=%s, shape=hexagon];\n", i+1, dotLabel(service.Name))
+		if lhead != "" {
+			fmt.Fprintf(&buf, "  s%d -> %s [style=dashed, label=\"hosted on\", lhead=%s];\n", i+1, to, lhead)
+		} else {
+			fmt.Fprintf(&buf, "  s%d -> %s [style=dashed, label=\"hosted on\"];\n", i+1, to)
+		}
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func dotLabel(label string) string {
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(strings.ReplaceAll(label, "\"", "\\\""), "\n", "\\n"))
+}
+
+// Writes the nodes & nested modules of the module
+func (d *Diagram) writeModules(
+	parent string,
+	depth int,
+	buf *bytes.Buffer,
+	open func(module *DiagramModule, indent string),
+	end func(indent string),
+	node func(node *DiagramNode, indent string),
+) {
+	indent := strings.Repeat("  ", depth)
+
+	for _, n := range d.Nodes {
+		if n.Module == parent {
+			node(n, indent)
+		}
+	}
+
+	for _, module := range d.Modules {
+		if module.Parent != parent {
+			continue
+		}
+
+		open(module, indent)
+		d.writeModules(module.Id, depth+1, buf, open, end, node)
+		end(indent)
+	}
+}
+
+// The first resource within the module or its nested modules
+func (d *Diagram) moduleAnchor(moduleId string) string {
+	for _, node := range d.Nodes {
+		if node.Module == moduleId {
+			return node.Id
+		}
+	}
+
+	for _, module := range d.Modules {
+		if module.Parent == moduleId {
+			if anchor := d.moduleAnchor(module.Id); anchor != "" {
+				return anchor
+			}
+		}
+	}
+
+	return ""
+}
+
+// Render renders the diagram in the format. Markdown files embed the Mermaid diagram in a mermaid code block.
+// SVG diagrams require Graphviz.
+func (d *Diagram) Render(
+	ctx context.Context,
+	commandRunner exec.CommandRunner,
+	format string,
+	markdown bool,
+) ([]byte, error) {
+	switch format {
+	case DiagramFormatMermaid:
+		if markdown {
+			return []byte(fmt.Sprintf(
+				"<!-- Generated by azd infra diagram, do not edit -->\n```mermaid\n%s```\n", d.Mermaid())), nil
+		}
+
+		return []byte(d.Mermaid()), nil
+	case DiagramFormatGraphviz:
+		return []byte(d.Graphviz()), nil
+	case DiagramFormatSvg:
+		res, err := commandRunner.Run(ctx, exec.NewRunArgs("dot", "-Tsvg").WithStdIn(strings.NewReader(d.Graphviz())))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"rendering svg with Graphviz, install Graphviz from https://graphviz.org/download: %w", err)
+		}
+
+		return []byte(res.Stdout), nil
+	default:
+		return nil, fmt.Errorf(
+			"diagram format '%s' is not supported, supported formats: %s", format, strings.Join(DiagramFormats(), ", "))
+	}
+}
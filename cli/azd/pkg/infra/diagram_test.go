@@ -0,0 +1,214 @@
+package infra
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testDiagramTemplate = `{
+  "resources": [
+    {
+      "type": "Microsoft.Resources/resourceGroups",
+      "name": "[format('rg-{0}', parameters('environmentName'))]"
+    },
+    {
+      "type": "Microsoft.Resources/deployments",
+      "name": "monitoring",
+      "resourceGroup": "[format('rg-{0}', parameters('environmentName'))]",
+      "properties": {
+        "template": {
+          "resources": [
+            {
+              "type": "Microsoft.OperationalInsights/workspaces",
+              "name": "[parameters('logAnalyticsName')]"
+            }
+          ]
+        }
+      },
+      "dependsOn": [
+        "[subscriptionResourceId('Microsoft.Resources/resourceGroups', format('rg-{0}', parameters('environmentName')))]"
+      ]
+    },
+    {
+      "type": "Microsoft.Resources/deployments",
+      "name": "web",
+      "properties": {
+        "parameters": {
+          "tags": { "value": "[union(variables('tags'), createObject('azd-service-name', 'web'))]" }
+        },
+        "template": {
+          "resources": [
+            {
+              "type": "Microsoft.Web/serverfarms",
+              "name": "plan"
+            },
+            {
+              "type": "Microsoft.Web/sites",
+              "name": "[parameters('name')]",
+              "dependsOn": ["[resourceId('Microsoft.Web/serverfarms', 'plan')]"]
+            },
+            {
+              "type": "Microsoft.KeyVault/vaults",
+              "name": "shared",
+              "existing": true
+            }
+          ]
+        }
+      },
+      "dependsOn": ["[resourceId('Microsoft.Resources/deployments', 'monitoring')]"]
+    },
+    {
+      "type": "Microsoft.App/containerApps",
+      "name": "api",
+      "tags": { "azd-service-name": "api" }
+    }
+  ]
+}`
+
+func Test_NewDiagram(t *testing.T) {
+	t.Run("Resources", func(t *testing.T) {
+		diagram, err := NewDiagram([]byte(testDiagramTemplate))
+		require.NoError(t, err)
+
+		labels := map[string]string{}
+		for _, node := range diagram.Nodes {
+			labels[node.Label] = node.Module
+		}
+
+		// Existing resources aren't deployed by the template
+		require.Len(t, diagram.Nodes, 5)
+		require.Len(t, diagram.Modules, 2)
+		require.Equal(t, "monitoring", diagram.Modules[0].Label)
+		require.Equal(t, "rg", strings.Split(labelOf(diagram, "Microsoft.Resources/resourceGroups"), "\n")[1])
+		require.Equal(t, diagram.Modules[1].Id, labels["App Service plan\nplan"])
+		require.Equal(t, "", labels["Container App\napi"])
+
+		require.ElementsMatch(t, []DiagramEdge{
+			{From: diagram.Modules[0].Id, To: idOf(diagram, "Microsoft.Resources/resourceGroups")},
+			{From: diagram.Modules[1].Id, To: diagram.Modules[0].Id},
+			{From: idOf(diagram, "Microsoft.Web/sites"), To: idOf(diagram, "Microsoft.Web/serverfarms")},
+		}, diagram.Edges)
+	})
+
+	t.Run("Services", func(t *testing.T) {
+		diagram, err := NewDiagram([]byte(testDiagramTemplate))
+		require.NoError(t, err)
+
+		// The service tagged by the parameters of a module is hosted on the web app of the module
+		require.Equal(t, []DiagramService{
+			{Name: "api", Host: idOf(diagram, "Microsoft.App/containerApps")},
+			{Name: "web", Host: idOf(diagram, "Microsoft.Web/sites")},
+		}, diagram.Services)
+	})
+
+	t.Run("SymbolicNames", func(t *testing.T) {
+		diagram, err := NewDiagram([]byte(`{
+			"languageVersion": "2.0",
+			"resources": {
+				"registry": { "type": "Microsoft.ContainerRegistry/registries", "name": "[parameters('name')]" },
+				"app": {
+					"type": "Microsoft.App/containerApps",
+					"name": "app",
+					"dependsOn": ["registry"]
+				}
+			}
+		}`))
+		require.NoError(t, err)
+
+		require.Equal(t, []DiagramEdge{
+			{From: idOf(diagram, "Microsoft.App/containerApps"), To: idOf(diagram, "Microsoft.ContainerRegistry/registries")},
+		}, diagram.Edges)
+		require.Equal(t, "Container Registry\nregistry", labelOf(diagram, "Microsoft.ContainerRegistry/registries"))
+	})
+
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		_, err := NewDiagram([]byte("not json"))
+		require.Error(t, err)
+	})
+}
+
+func Test_Diagram_Render(t *testing.T) {
+	diagram, err := NewDiagram([]byte(testDiagramTemplate))
+	require.NoError(t, err)
+
+	t.Run("Mermaid", func(t *testing.T) {
+		mermaid := diagram.Mermaid()
+
+		require.True(t, strings.HasPrefix(mermaid, "flowchart LR\n"))
+		require.Contains(t, mermaid, "subgraph m2[\"monitoring\"]")
+		require.Contains(t, mermaid, "[\"Web App\"]")
+		require.Contains(t, mermaid, "-.->|hosted on| "+idOf(diagram, "Microsoft.Web/sites"))
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		markdown, err := diagram.Render(context.Background(), nil, DiagramFormatMermaid, true)
+		require.NoError(t, err)
+		require.Contains(t, string(markdown), "```mermaid\nflowchart LR\n")
+	})
+
+	t.Run("Graphviz", func(t *testing.T) {
+		dot := diagram.Graphviz()
+
+		require.Contains(t, dot, "compound=true;")
+		require.Contains(t, dot, "subgraph cluster_m2 {")
+		// Edges between modules are drawn between resources of the modules, clipped to the clusters
+		require.Contains(t, dot, "ltail=cluster_m4, lhead=cluster_m2")
+	})
+
+	t.Run("Svg", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "dot -Tsvg")
+		}).Respond(exec.NewRunResult(0, "<svg></svg>", ""))
+
+		svg, err := diagram.Render(*mockContext.Context, mockContext.CommandRunner, DiagramFormatSvg, false)
+		require.NoError(t, err)
+		require.Equal(t, "<svg></svg>", string(svg))
+	})
+
+	t.Run("UnsupportedFormat", func(t *testing.T) {
+		_, err := diagram.Render(context.Background(), nil, "png", false)
+		require.ErrorContains(t, err, "not supported")
+	})
+}
+
+func Test_DiagramFormatFromPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"docs/architecture.md": DiagramFormatMermaid,
+		"infra.mmd":            DiagramFormatMermaid,
+		"infra.gv":             DiagramFormatGraphviz,
+		"infra.SVG":            DiagramFormatSvg,
+	} {
+		format, err := DiagramFormatFromPath(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, format)
+	}
+
+	_, err := DiagramFormatFromPath("infra.png")
+	require.Error(t, err)
+}
+
+func idOf(diagram *Diagram, resourceType string) string {
+	for _, node := range diagram.Nodes {
+		if node.Type == resourceType {
+			return node.Id
+		}
+	}
+
+	return ""
+}
+
+func labelOf(diagram *Diagram, resourceType string) string {
+	for _, node := range diagram.Nodes {
+		if node.Type == resourceType {
+			return node.Label
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// InventoryResource is a resource of the environment found in Azure
+type InventoryResource struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+}
+
+// ResourceInventory lists the resources of the environments, the resources and resource groups tagged with the
+// azd-env-name tag of the environment
+type ResourceInventory struct {
+	azCli azcli.AzCli
+}
+
+func NewResourceInventory(azCli azcli.AzCli) *ResourceInventory {
+	return &ResourceInventory{
+		azCli: azCli,
+	}
+}
+
+// List returns the resources and resource groups of the subscription tagged with the environment, sorted by id
+func (i *ResourceInventory) List(
+	ctx context.Context,
+	subscriptionId string,
+	envName string,
+) ([]InventoryResource, error) {
+	tagFilter := &azcli.Filter{Key: azure.TagKeyAzdEnvName, Value: envName}
+
+	groups, err := i.azCli.ListResourceGroup(ctx, subscriptionId, &azcli.ListResourceGroupOptions{TagFilter: tagFilter})
+	if err != nil {
+		return nil, fmt.Errorf("listing resource groups: %w", err)
+	}
+
+	resources, err := i.azCli.ListResources(ctx, subscriptionId, &azcli.ListResourcesOptions{TagFilter: tagFilter})
+	if err != nil {
+		return nil, fmt.Errorf("listing resources: %w", err)
+	}
+
+	inventory := make([]InventoryResource, 0, len(groups)+len(resources))
+	for _, group := range groups {
+		inventory = append(inventory, InventoryResource{Id: group.Id, Name: group.Name, Type: group.Type})
+	}
+
+	for _, resource := range resources {
+		resourceGroup := ""
+		if parsed, err := arm.ParseResourceID(resource.Id); err == nil {
+			resourceGroup = parsed.ResourceGroupName
+		}
+
+		inventory = append(inventory, InventoryResource{
+			Id:            resource.Id,
+			Name:          resource.Name,
+			Type:          resource.Type,
+			ResourceGroup: resourceGroup,
+		})
+	}
+
+	sort.Slice(inventory, func(a, b int) bool {
+		return strings.ToLower(inventory[a].Id) < strings.ToLower(inventory[b].Id)
+	})
+
+	return inventory, nil
+}
+
+// Orphans returns the resources of the inventory which aren't defined by the infrastructure, the defined resources are
+// the ids of the resources of the state of the infrastructure
+func Orphans(inventory []InventoryResource, definedIds []string) []InventoryResource {
+	defined := make(map[string]bool, len(definedIds))
+	for _, id := range definedIds {
+		defined[strings.ToLower(id)] = true
+	}
+
+	orphans := []InventoryResource{}
+	for _, resource := range inventory {
+		if !defined[strings.ToLower(resource.Id)] {
+			orphans = append(orphans, resource)
+		}
+	}
+
+	return orphans
+}
+
+// Delete deletes the resources, the resource groups are deleted last. The resources of the deleted resource groups
+// aren't deleted individually, they are deleted with their resource group.
+func (i *ResourceInventory) Delete(
+	ctx context.Context,
+	subscriptionId string,
+	resources []InventoryResource,
+) error {
+	deletedGroups := map[string]bool{}
+	for _, resource := range resources {
+		if isResourceGroupType(resource.Type) {
+			deletedGroups[strings.ToLower(resource.Name)] = true
+		}
+	}
+
+	for _, resource := range resources {
+		if isResourceGroupType(resource.Type) || deletedGroups[strings.ToLower(resource.ResourceGroup)] {
+			continue
+		}
+
+		if err := i.azCli.DeleteResource(ctx, subscriptionId, resource.Id); err != nil {
+			return fmt.Errorf("deleting resource %s: %w", resource.Name, err)
+		}
+	}
+
+	for _, resource := range resources {
+		if !isResourceGroupType(resource.Type) {
+			continue
+		}
+
+		if err := i.azCli.DeleteResourceGroup(ctx, subscriptionId, resource.Name); err != nil {
+			return fmt.Errorf("deleting resource group %s: %w", resource.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func isResourceGroupType(resourceType string) bool {
+	return strings.EqualFold(resourceType, string(AzureResourceTypeResourceGroup))
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceInventory(t *testing.T) {
+	const subscriptionPath = "/subscriptions/SUBSCRIPTION_ID"
+	const sitesType = "Microsoft.Web/sites"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourcegroups")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.Equal(t, "tagName eq 'azd-env-name' and tagValue eq 'test-env'", request.URL.Query().Get("$filter"))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+			Value: []*armresources.ResourceGroup{
+				{
+					ID:       convert.RefOf(subscriptionPath + "/resourceGroups/rg-test-env"),
+					Name:     convert.RefOf("rg-test-env"),
+					Type:     convert.RefOf(string(AzureResourceTypeResourceGroup)),
+					Location: convert.RefOf("eastus2"),
+				},
+				{
+					ID:       convert.RefOf(subscriptionPath + "/resourceGroups/rg-old-test-env"),
+					Name:     convert.RefOf("rg-old-test-env"),
+					Type:     convert.RefOf(string(AzureResourceTypeResourceGroup)),
+					Location: convert.RefOf("eastus2"),
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == subscriptionPath+"/resources"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.Equal(t, "tagName eq 'azd-env-name' and tagValue eq 'test-env'", request.URL.Query().Get("$filter"))
+
+		resource := func(group string, name string) *armresources.GenericResourceExpanded {
+			return &armresources.GenericResourceExpanded{
+				ID:       convert.RefOf(subscriptionPath + "/resourceGroups/" + group + "/providers/" + sitesType + "/" + name),
+				Name:     convert.RefOf(name),
+				Type:     convert.RefOf(sitesType),
+				Location: convert.RefOf("eastus2"),
+			}
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceListResult{
+			Value: []*armresources.GenericResourceExpanded{
+				resource("rg-test-env", "app-web"),
+				resource("rg-test-env", "app-api"),
+				resource("rg-old-test-env", "app-old"),
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == subscriptionPath+"/providers/Microsoft.Web"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.Provider{
+			Namespace: convert.RefOf("Microsoft.Web"),
+			ResourceTypes: []*armresources.ProviderResourceType{
+				{
+					ResourceType: convert.RefOf("sites"),
+					APIVersions:  []*string{convert.RefOf("2023-12-01-preview"), convert.RefOf("2023-01-01")},
+				},
+			},
+		})
+	})
+
+	deleted := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = append(deleted, request.URL.Path+"?api-version="+request.URL.Query().Get("api-version"))
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	inventory := NewResourceInventory(mockazcli.NewAzCliFromMockContext(mockContext))
+	resources, err := inventory.List(*mockContext.Context, "SUBSCRIPTION_ID", "test-env")
+	require.NoError(t, err)
+	require.Len(t, resources, 5)
+	require.Equal(t, "rg-old-test-env", resources[0].Name)
+	require.Equal(t, "rg-old-test-env", resources[1].ResourceGroup)
+
+	orphans := Orphans(resources, []string{
+		subscriptionPath + "/resourcegroups/RG-TEST-ENV",
+		subscriptionPath + "/resourceGroups/rg-test-env/providers/" + sitesType + "/app-web",
+	})
+	names := []string{}
+	for _, orphan := range orphans {
+		names = append(names, orphan.Name)
+	}
+	require.Equal(t, []string{"rg-old-test-env", "app-old", "app-api"}, names)
+
+	err = inventory.Delete(*mockContext.Context, "SUBSCRIPTION_ID", orphans)
+	require.NoError(t, err)
+
+	// The resources of the deleted resource groups are deleted with their resource group
+	require.Equal(t, []string{
+		subscriptionPath + "/resourceGroups/rg-test-env/providers/" + sitesType + "/app-api?api-version=2023-01-01",
+		subscriptionPath + "/resourcegroups/rg-old-test-env?api-version=2021-04-01",
+	}, deleted)
+}
@@ -21,6 +21,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -32,7 +33,9 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/cancellation"
 	"github.com/azure/azure-dev/cli/azd/pkg/cmdsubst"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -165,7 +168,7 @@ func (p *BicepProvider) State(
 			}
 
 			asyncContext.SetProgress(&StateProgress{Message: "Retrieving Azure deployment", Timestamp: time.Now()})
-			armDeployment, err := latestCompletedDeployment(ctx, p.env.GetEnvName(), scope)
+			armDeployment, err := p.latestDeployment(ctx, scope)
 			if err != nil {
 				asyncContext.SetError(fmt.Errorf("retrieving deployment: %w", err))
 				return
@@ -281,6 +284,15 @@ func (p *BicepProvider) Plan(
 				return
 			}
 
+			if p.options.DeploymentStacks {
+				if err := validateDenySettings(p.options.DenySettings); err != nil {
+					asyncContext.SetError(err)
+					return
+				}
+
+				target = infra.NewStackDeployment(p.azCli, target, p.env.GetEnvName(), p.options.DenySettings)
+			}
+
 			result := DeploymentPlan{
 				Deployment: *deployment,
 				Details: BicepDeploymentDetails{
@@ -368,6 +380,13 @@ func (p *BicepProvider) Deploy(
 					azure.TagKeyAzdEnvName: to.Ptr(p.env.GetEnvName()),
 				},
 			)
+			if err != nil && cancellation.IsCanceled(err) {
+				// azd stops waiting for the deployment but ARM carries on with it
+				err = fmt.Errorf(
+					"%w. Deployment '%s' continues in Azure, follow or cancel it from the Azure Portal: %s\n"+
+						"Run azd provision again once it completed to resume",
+					err, bicepDeploymentData.Target.Name(), bicepDeploymentData.Target.PortalUrl())
+			}
 			if err != nil {
 				asyncContext.SetError(err)
 				return
@@ -456,7 +475,7 @@ func (p *BicepProvider) Destroy(
 			}
 
 			asyncContext.SetProgress(&DestroyProgress{Message: "Fetching resource groups", Timestamp: time.Now()})
-			deployment, err := latestCompletedDeployment(ctx, p.env.GetEnvName(), scope)
+			deployment, err := p.latestDeployment(ctx, scope)
 			if err != nil {
 				asyncContext.SetError(err)
 				return
@@ -476,6 +495,26 @@ func (p *BicepProvider) Destroy(
 				allResources = append(allResources, groupResources...)
 			}
 
+			asyncContext.SetProgress(&DestroyProgress{Message: "Checking resource locks", Timestamp: time.Now()})
+			protections, err := p.getDeletionProtections(ctx, groupedResources)
+			if err != nil {
+				asyncContext.SetError(fmt.Errorf("getting deletion protections: %w", err))
+				return
+			}
+
+			// Locks are detected up front instead of failing halfway through the deletion of the resource groups
+			if err := protections.validate(options, p.env.GetEnvName()); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			asyncContext.SetProgress(&DestroyProgress{Message: "Getting role assignments", Timestamp: time.Now()})
+			roleAssignments, err := p.getRoleAssignmentsToDelete(ctx, groupedResources)
+			if err != nil {
+				asyncContext.SetError(fmt.Errorf("getting role assignments to delete: %w", err))
+				return
+			}
+
 			asyncContext.SetProgress(&DestroyProgress{Message: "Getting Key Vaults to purge", Timestamp: time.Now()})
 			keyVaults, err := p.getKeyVaultsToPurge(ctx, groupedResources)
 			if err != nil {
@@ -506,7 +545,9 @@ func (p *BicepProvider) Destroy(
 				return
 			}
 
-			if err := p.destroyResourceGroups(ctx, options, groupedResources, len(allResources)); err != nil {
+			err = p.destroyResourceGroups(
+				ctx, options, scope, groupedResources, len(allResources), protections, roleAssignments)
+			if err != nil {
 				asyncContext.SetError(fmt.Errorf("deleting resource groups: %w", err))
 				return
 			}
@@ -533,6 +574,17 @@ func (p *BicepProvider) Destroy(
 				},
 			}
 
+			purgeNames := []string{}
+			for _, vault := range keyVaults {
+				purgeNames = append(purgeNames, vault.Name)
+			}
+			for _, config := range appConfigs {
+				purgeNames = append(purgeNames, config.Name)
+			}
+			for _, apim := range apiManagements {
+				purgeNames = append(purgeNames, apim.Name)
+			}
+
 			var purgeItem []itemToPurge
 			for _, item := range []itemToPurge{keyVaultsPurge, appConfigsPurge, aPIManagement} {
 				if item.count > 0 {
@@ -552,9 +604,14 @@ func (p *BicepProvider) Destroy(
 					cognitiveAccounts: groupByKind[name],
 				}
 				purgeItem = append(purgeItem, addPurgeItem)
+
+				for _, cogAccount := range cogAccounts {
+					purgeNames = append(purgeNames, *cogAccount.account.Name)
+				}
 			}
 
-			if err := p.purgeItems(ctx, purgeItem, options); err != nil {
+			purged, err := p.purgeItems(ctx, purgeItem, options)
+			if err != nil {
 				asyncContext.SetError(fmt.Errorf("purging resources: %w", err))
 				return
 			}
@@ -564,6 +621,13 @@ func (p *BicepProvider) Destroy(
 					template.Outputs,
 					azcli.CreateDeploymentOutput(deployment.Properties.Outputs),
 				)),
+				Deleted: p.newDestroyPreview(groupedResources, protections, roleAssignments, options),
+				Purged:  []string{},
+			}
+
+			if purged {
+				sort.Strings(purgeNames)
+				destroyResult.Purged = purgeNames
 			}
 
 			// Since we have deleted the resource group, add AZURE_RESOURCE_GROUP to the list of invalidated env vars
@@ -654,6 +718,55 @@ func latestCompletedDeployment(
 	return nil, fmt.Errorf("no deployments found for environment %s", envName)
 }
 
+// latestDeployment returns the deployment stack of the environment when provisioning with deployment stacks, otherwise the
+// latest completed deployment of the environment
+func (p *BicepProvider) latestDeployment(
+	ctx context.Context, scope infra.Scope,
+) (*armresources.DeploymentExtended, error) {
+	if !p.options.DeploymentStacks {
+		return latestCompletedDeployment(ctx, p.env.GetEnvName(), scope)
+	}
+
+	var deployment infra.Deployment
+	if resourceGroupName := scopeResourceGroupName(scope); resourceGroupName != "" {
+		deployment = infra.NewResourceGroupDeployment(p.azCli, scope.SubscriptionId(), resourceGroupName, p.env.GetEnvName())
+	} else {
+		deployment = infra.NewSubscriptionDeployment(
+			p.azCli, p.env.GetLocation(), scope.SubscriptionId(), p.env.GetEnvName())
+	}
+
+	stack, err := infra.NewStackDeployment(p.azCli, deployment, p.env.GetEnvName(), p.options.DenySettings).
+		Deployment(ctx)
+	if errors.Is(err, azcli.ErrDeploymentNotFound) {
+		return nil, fmt.Errorf("no deployment stack found for environment %s", p.env.GetEnvName())
+	} else if err != nil {
+		return nil, err
+	}
+
+	return stack, nil
+}
+
+// scopeResourceGroupName returns the name of the resource group of resource group scopes, empty for subscriptions
+func scopeResourceGroupName(scope infra.Scope) string {
+	if resourceGroupScope, ok := scope.(*infra.ResourceGroupScope); ok {
+		return resourceGroupScope.ResourceGroupName()
+	}
+
+	return ""
+}
+
+// validateDenySettings checks the deny settings mode of the deployment stack is supported
+func validateDenySettings(mode string) error {
+	switch mode {
+	case "", azsdk.DenySettingsModeNone, azsdk.DenySettingsModeDenyDelete, azsdk.DenySettingsModeDenyWriteAndDelete:
+		return nil
+	default:
+		return fmt.Errorf(
+			"infra.denySettings: unsupported mode '%s', supported modes: %s, %s, %s", mode,
+			azsdk.DenySettingsModeNone, azsdk.DenySettingsModeDenyDelete, azsdk.DenySettingsModeDenyWriteAndDelete)
+	}
+}
+
 // resourceGroupsFromDeployment returns the names of all the unique set of resource group name names resource groups from
 //
 //	the OutputResources section of a ARM deployment.
@@ -725,12 +838,17 @@ func generateResourceGroupsToDelete(groupedResources map[string][]azcli.AzCliRes
 func (p *BicepProvider) destroyResourceGroups(
 	ctx context.Context,
 	options DestroyOptions,
+	scope infra.Scope,
 	groupedResources map[string][]azcli.AzCliResource,
 	resourceCount int,
+	protections *deletionProtections,
+	roleAssignments []azcli.AzCliRoleAssignment,
 ) error {
 	if !options.Force() {
+		lines := generateResourceGroupsToDelete(groupedResources, p.env.GetSubscriptionId())
+		lines = append(lines, roleAssignmentLines(roleAssignments)...)
 		p.console.MessageUxItem(ctx, &ux.MultilineMessage{
-			Lines: generateResourceGroupsToDelete(groupedResources, p.env.GetSubscriptionId())},
+			Lines: append(lines, protections.planLines(options)...)},
 		)
 		confirmDestroy, err := p.console.Confirm(ctx, input.ConsoleOptions{
 			Message: fmt.Sprintf(
@@ -750,8 +868,30 @@ func (p *BicepProvider) destroyResourceGroups(
 		}
 	}
 
+	if options.RemoveLocks() {
+		if err := p.removeLocks(ctx, protections); err != nil {
+			return fmt.Errorf("removing locks: %w", err)
+		}
+	}
+
 	p.console.Message(ctx, output.WithGrayFormat("Deleting your resources can take some time.\n"))
 
+	if p.options.DeploymentStacks {
+		// Deleting the stack deletes the resources and resource groups it manages, its deny settings included
+		message := fmt.Sprintf("Deleting deployment stack: %s", output.WithHighLightFormat(p.env.GetEnvName()))
+		p.console.ShowSpinner(ctx, message, input.Step)
+		err := p.azCli.DeleteDeploymentStack(
+			ctx, p.env.GetSubscriptionId(), scopeResourceGroupName(scope), p.env.GetEnvName())
+
+		p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+		if err != nil {
+			return err
+		}
+
+		p.console.Message(ctx, "")
+		return nil
+	}
+
 	for resourceGroup := range groupedResources {
 		message := fmt.Sprintf("Deleting resource group: %s",
 			output.WithHighLightFormat(resourceGroup),
@@ -789,10 +929,10 @@ func (p *BicepProvider) purgeItems(
 	ctx context.Context,
 	items []itemToPurge,
 	options DestroyOptions,
-) error {
+) (bool, error) {
 	if len(items) == 0 {
 		// nothing to purge
-		return nil
+		return false, nil
 	}
 
 	skipPurge := false
@@ -819,24 +959,20 @@ func (p *BicepProvider) purgeItems(
 		p.console.Message(ctx, "")
 
 		if err != nil {
-			return fmt.Errorf("prompting for confirmation: %w", err)
+			return false, fmt.Errorf("prompting for confirmation: %w", err)
 		}
 
 		if !purgeItems {
 			skipPurge = true
 		}
-
-		if err != nil {
-			return err
-		}
 	}
 	for index, item := range items {
 		if err := item.purge(skipPurge, &items[index]); err != nil {
-			return fmt.Errorf("failed to purge %s: %w", item.resourceType, err)
+			return false, fmt.Errorf("failed to purge %s: %w", item.resourceType, err)
 		}
 	}
 
-	return nil
+	return !skipPurge, nil
 }
 
 func (p *BicepProvider) getKeyVaults(
@@ -985,7 +1121,7 @@ func (p *BicepProvider) runPurgeAsStep(
 	return err
 }
 
-func (p *BicepProvider) getAppConfigsToPurge(
+func (p *BicepProvider) getAppConfigs(
 	ctx context.Context,
 	groupedResources map[string][]azcli.AzCliResource,
 ) ([]*azcli.AzCliAppConfig, error) {
@@ -1004,9 +1140,7 @@ func (p *BicepProvider) getAppConfigsToPurge(
 					return nil, fmt.Errorf("listing app configuration %s properties: %w", resource.Name, err)
 				}
 
-				if !config.Properties.EnablePurgeProtection {
-					configs = append(configs, config)
-				}
+				configs = append(configs, config)
 			}
 		}
 	}
@@ -1014,6 +1148,25 @@ func (p *BicepProvider) getAppConfigsToPurge(
 	return configs, nil
 }
 
+func (p *BicepProvider) getAppConfigsToPurge(
+	ctx context.Context,
+	groupedResources map[string][]azcli.AzCliResource,
+) ([]*azcli.AzCliAppConfig, error) {
+	configs, err := p.getAppConfigs(ctx, groupedResources)
+	if err != nil {
+		return nil, err
+	}
+
+	configsToPurge := []*azcli.AzCliAppConfig{}
+	for _, config := range configs {
+		if !config.Properties.EnablePurgeProtection {
+			configsToPurge = append(configsToPurge, config)
+		}
+	}
+
+	return configsToPurge, nil
+}
+
 func (p *BicepProvider) getApiManagementsToPurge(
 	ctx context.Context,
 	groupedResources map[string][]azcli.AzCliResource,
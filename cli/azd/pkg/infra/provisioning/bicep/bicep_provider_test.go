@@ -19,9 +19,11 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appconfiguration/armappconfiguration"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -286,6 +288,15 @@ func TestBicepDestroy(t *testing.T) {
 
 		require.Nil(t, err)
 		require.NotNil(t, destroyResult)
+		require.Len(t, destroyResult.Deleted.Resources, 8)
+		require.Equal(t, []DestroyRoleAssignment{{
+			Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+				"Microsoft.Web/sites/app-123/providers/Microsoft.Authorization/roleAssignments/app-assignment",
+			Scope:       "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/app-123",
+			PrincipalId: "PRINCIPAL_ID",
+			RoleName:    "Storage Blob Data Reader",
+		}}, destroyResult.Deleted.RoleAssignments)
+		require.Equal(t, []string{"ac-123", "ac2-123", "apim-123", "apim2-123", "kv-123", "kv2-123"}, destroyResult.Purged)
 
 		// Verify console prompts
 		consoleOutput := mockContext.Console.Output()
@@ -300,14 +311,16 @@ func TestBicepDestroy(t *testing.T) {
 		require.Contains(t, consoleOutput[7], "")
 
 		// Verify progress output
-		require.Len(t, progressLog, 7)
+		require.Len(t, progressLog, 9)
 		require.Contains(t, progressLog[0], "Compiling Bicep template")
 		require.Contains(t, progressLog[1], "Fetching resource groups")
 		require.Contains(t, progressLog[2], "Fetching resources")
-		require.Contains(t, progressLog[3], "Getting Key Vaults to purge")
-		require.Contains(t, progressLog[4], "Getting App Configurations to purge")
-		require.Contains(t, progressLog[5], "Getting API Management Services to purge")
-		require.Contains(t, progressLog[6], "Getting Cognitive Accounts to purge")
+		require.Contains(t, progressLog[3], "Checking resource locks")
+		require.Contains(t, progressLog[4], "Getting role assignments")
+		require.Contains(t, progressLog[5], "Getting Key Vaults to purge")
+		require.Contains(t, progressLog[6], "Getting App Configurations to purge")
+		require.Contains(t, progressLog[7], "Getting API Management Services to purge")
+		require.Contains(t, progressLog[8], "Getting Cognitive Accounts to purge")
 	})
 
 	t.Run("InteractiveForceAndPurge", func(t *testing.T) {
@@ -351,14 +364,76 @@ func TestBicepDestroy(t *testing.T) {
 		require.Contains(t, consoleOutput[1], "")
 
 		// Verify progress output
-		require.Len(t, progressLog, 7)
+		require.Len(t, progressLog, 9)
 		require.Contains(t, progressLog[0], "Compiling Bicep template")
 		require.Contains(t, progressLog[1], "Fetching resource groups")
 		require.Contains(t, progressLog[2], "Fetching resources")
-		require.Contains(t, progressLog[3], "Getting Key Vaults to purge")
-		require.Contains(t, progressLog[4], "Getting App Configurations to purge")
-		require.Contains(t, progressLog[5], "Getting API Management Services to purge")
-		require.Contains(t, progressLog[6], "Getting Cognitive Accounts to purge")
+		require.Contains(t, progressLog[3], "Checking resource locks")
+		require.Contains(t, progressLog[4], "Getting role assignments")
+		require.Contains(t, progressLog[5], "Getting Key Vaults to purge")
+		require.Contains(t, progressLog[6], "Getting App Configurations to purge")
+		require.Contains(t, progressLog[7], "Getting API Management Services to purge")
+		require.Contains(t, progressLog[8], "Getting Cognitive Accounts to purge")
+	})
+}
+
+func TestBicepDestroyLocks(t *testing.T) {
+	lockId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/app-123" +
+		"/providers/Microsoft.Authorization/locks/app-lock"
+
+	destroy := func(t *testing.T, notes string, options DestroyOptions) ([]string, error) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		prepareStateMocks(mockContext)
+		prepareDestroyMocks(mockContext)
+		prepareLocksMocks(mockContext, []azsdk.ManagementLock{
+			{
+				Id:   lockId,
+				Name: "app-lock",
+				Properties: azsdk.ManagementLockProperties{
+					Level: azsdk.LockLevelCanNotDelete,
+					Notes: notes,
+				},
+			},
+		})
+
+		deletedLocks := []string{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodDelete &&
+				strings.Contains(request.URL.Path, "/providers/Microsoft.Authorization/locks/")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			deletedLocks = append(deletedLocks, request.URL.Path)
+			return httpRespondFn(request)
+		})
+
+		infraProvider := createBicepProvider(t, mockContext)
+		destroyTask := infraProvider.Destroy(*mockContext.Context, options)
+		go func() {
+			for range destroyTask.Progress() {
+			}
+		}()
+
+		_, err := destroyTask.Await()
+		return deletedLocks, err
+	}
+
+	t.Run("ForeignLock", func(t *testing.T) {
+		deletedLocks, err := destroy(t, "", NewDestroyOptions(true, true).WithRemoveLocks(true))
+		require.ErrorContains(t, err, "1 management lock(s) prevent the deletion")
+		require.ErrorContains(t, err, "CanNotDelete lock 'app-lock'")
+		require.ErrorContains(t, err, "az lock delete")
+		require.Empty(t, deletedLocks)
+	})
+
+	t.Run("EnvironmentLock", func(t *testing.T) {
+		_, err := destroy(t, "azd-env-name=test-env", NewDestroyOptions(true, true))
+		require.ErrorContains(t, err, "azd down --remove-locks")
+	})
+
+	t.Run("RemoveLocks", func(t *testing.T) {
+		deletedLocks, err := destroy(t, "azd-env-name=test-env", NewDestroyOptions(true, true).WithRemoveLocks(true))
+		require.NoError(t, err)
+		require.Equal(t, []string{lockId}, deletedLocks)
 	})
 }
 
@@ -705,6 +780,71 @@ func prepareStateMocks(mockContext *mocks.MockContext) {
 	})
 }
 
+func prepareLocksMocks(mockContext *mocks.MockContext, locks []azsdk.ManagementLock) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Authorization/locks")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, _ := json.Marshal(map[string]any{"value": locks})
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+		}, nil
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete &&
+			strings.Contains(request.URL.Path, "/providers/Microsoft.Authorization/locks/")
+	}).RespondFn(httpRespondFn)
+}
+
+func prepareRoleAssignmentsMocks(mockContext *mocks.MockContext) {
+	roleDefinitionId := "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/roleDefinitions/ROLE_ID"
+	assignments := armauthorization.RoleAssignmentListResult{
+		Value: []*armauthorization.RoleAssignment{
+			{
+				ID: convert.RefOf("/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+					"Microsoft.Web/sites/app-123/providers/Microsoft.Authorization/roleAssignments/app-assignment"),
+				Properties: &armauthorization.RoleAssignmentPropertiesWithScope{
+					Scope: convert.RefOf(
+						"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/app-123"),
+					PrincipalID:      convert.RefOf("PRINCIPAL_ID"),
+					RoleDefinitionID: convert.RefOf(roleDefinitionId),
+				},
+			},
+			// Inherited from the subscription, not deleted with the resource group
+			{
+				ID: convert.RefOf(
+					"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/roleAssignments/sub-assignment"),
+				Properties: &armauthorization.RoleAssignmentPropertiesWithScope{
+					Scope:            convert.RefOf("/subscriptions/SUBSCRIPTION_ID"),
+					PrincipalID:      convert.RefOf("PRINCIPAL_ID"),
+					RoleDefinitionID: convert.RefOf(roleDefinitionId),
+				},
+			},
+		},
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path, "/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Authorization/roleAssignments")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, assignments)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, roleDefinitionId)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armauthorization.RoleDefinition{
+			ID: convert.RefOf(roleDefinitionId),
+			Properties: &armauthorization.RoleDefinitionProperties{
+				RoleName: convert.RefOf("Storage Blob Data Reader"),
+			},
+		})
+	})
+}
+
 func prepareDestroyMocks(mockContext *mocks.MockContext) {
 	makeItem := func(resourceType infra.AzureResourceType, resourceName string) *armresources.GenericResourceExpanded {
 		id := fmt.Sprintf("subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/%s/%s",
@@ -742,6 +882,12 @@ func prepareDestroyMocks(mockContext *mocks.MockContext) {
 		}, nil
 	})
 
+	// No locks prevent the deletion
+	prepareLocksMocks(mockContext, []azsdk.ManagementLock{})
+
+	// Role assignments of the resource group
+	prepareRoleAssignmentsMocks(mockContext)
+
 	// Get Key Vault
 	getKeyVaultMock(mockContext, "/vaults/kv-123", "kv-123", "eastus2")
 	getKeyVaultMock(mockContext, "/vaults/kv2-123", "kv2-123", "eastus2")
@@ -0,0 +1,226 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// Templates mark the locks they create with the environment in the notes of the lock, ex.
+// notes: 'azd-env-name=${environmentName}'. Only these locks are removed by `azd down --remove-locks`.
+const lockNotesEnvPrefix = "azd-env-name="
+
+// deletionProtections prevent resources from being deleted, or keep them soft-deleted after they are deleted
+type deletionProtections struct {
+	// The management locks of the resource groups and their resources
+	locks []azsdk.ManagementLock
+	// Key vaults kept soft-deleted until the end of their retention period, their names can't be reused until then
+	purgeProtectedVaults []*azcli.AzCliKeyVault
+	// App configurations kept soft-deleted until the end of their retention period, like key vaults
+	purgeProtectedAppConfigs []*azcli.AzCliAppConfig
+}
+
+// Gets the management locks & purge protected key vaults and app configurations of the resource groups before anything
+// is deleted
+func (p *BicepProvider) getDeletionProtections(
+	ctx context.Context,
+	groupedResources map[string][]azcli.AzCliResource,
+) (*deletionProtections, error) {
+	protections := &deletionProtections{}
+
+	resourceGroups := make([]string, 0, len(groupedResources))
+	for resourceGroup := range groupedResources {
+		resourceGroups = append(resourceGroups, resourceGroup)
+	}
+	sort.Strings(resourceGroups)
+
+	for _, resourceGroup := range resourceGroups {
+		locks, err := p.azCli.ListResourceGroupLocks(ctx, p.env.GetSubscriptionId(), resourceGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		protections.locks = append(protections.locks, locks...)
+	}
+
+	vaults, err := p.getKeyVaults(ctx, groupedResources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vault := range vaults {
+		if vault.Properties.EnablePurgeProtection {
+			protections.purgeProtectedVaults = append(protections.purgeProtectedVaults, vault)
+		}
+	}
+
+	appConfigs, err := p.getAppConfigs(ctx, groupedResources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, appConfig := range appConfigs {
+		if appConfig.Properties.EnablePurgeProtection {
+			protections.purgeProtectedAppConfigs = append(protections.purgeProtectedAppConfigs, appConfig)
+		}
+	}
+
+	return protections, nil
+}
+
+// purgeProtectedNames returns the names of the resources kept soft-deleted after their deletion
+func (d *deletionProtections) purgeProtectedNames() []string {
+	names := []string{}
+	for _, vault := range d.purgeProtectedVaults {
+		names = append(names, vault.Name)
+	}
+	for _, appConfig := range d.purgeProtectedAppConfigs {
+		names = append(names, appConfig.Name)
+	}
+
+	return names
+}
+
+// Gets the role assignments of the resource groups and their resources, deleted with the resource groups
+func (p *BicepProvider) getRoleAssignmentsToDelete(
+	ctx context.Context,
+	groupedResources map[string][]azcli.AzCliResource,
+) ([]azcli.AzCliRoleAssignment, error) {
+	resourceGroups := make([]string, 0, len(groupedResources))
+	for resourceGroup := range groupedResources {
+		resourceGroups = append(resourceGroups, resourceGroup)
+	}
+	sort.Strings(resourceGroups)
+
+	roleAssignments := []azcli.AzCliRoleAssignment{}
+	for _, resourceGroup := range resourceGroups {
+		assignments, err := p.azCli.ListResourceGroupRoleAssignments(ctx, p.env.GetSubscriptionId(), resourceGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		roleAssignments = append(roleAssignments, assignments...)
+	}
+
+	return roleAssignments, nil
+}
+
+// The lines describing the role assignments presented in the deletion plan
+func roleAssignmentLines(roleAssignments []azcli.AzCliRoleAssignment) []string {
+	if len(roleAssignments) == 0 {
+		return nil
+	}
+
+	lines := []string{"Role assignment(s) to be deleted:", ""}
+	for _, assignment := range roleAssignments {
+		lines = append(lines, fmt.Sprintf("  • %s", formatRoleAssignment(assignment)))
+	}
+
+	return append(lines, "")
+}
+
+// formatRoleAssignment describes the role assignment with its role, principal and scope
+func formatRoleAssignment(assignment azcli.AzCliRoleAssignment) string {
+	role := assignment.RoleName
+	if role == "" {
+		role = path.Base(assignment.RoleDefinitionId)
+	}
+
+	return fmt.Sprintf("%s for principal %s on %s", role, assignment.PrincipalId, path.Base(assignment.Scope))
+}
+
+// Whether the lock was created by the templates of the environment
+func isEnvironmentLock(lock azsdk.ManagementLock, envName string) bool {
+	for _, note := range strings.Fields(lock.Properties.Notes) {
+		if note == lockNotesEnvPrefix+envName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validate fails before anything is deleted when locks prevent the deletion. Locks created by the templates of the
+// environment are removed when the options allow it, other locks must be removed by their owner.
+func (d *deletionProtections) validate(options DestroyOptions, envName string) error {
+	if len(d.locks) == 0 {
+		return nil
+	}
+
+	lines := []string{}
+	foreignLocks := false
+	for _, lock := range d.locks {
+		environmentLock := isEnvironmentLock(lock, envName)
+		if environmentLock && options.RemoveLocks() {
+			continue
+		}
+
+		foreignLocks = foreignLocks || !environmentLock
+		lines = append(lines, fmt.Sprintf("  • %s lock '%s' on %s", lock.Properties.Level, lock.Name, lock.Scope()))
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	suggestion := "Run `azd down --remove-locks` to remove the locks created by the environment before deleting resources."
+	if foreignLocks {
+		suggestion = "Remove the locks that weren't created by the environment with `az lock delete --ids <lock id>`, " +
+			"or ask their owner to remove them."
+	}
+
+	return fmt.Errorf(
+		"resources can't be deleted, %d management lock(s) prevent the deletion:\n%s\n%s",
+		len(lines), strings.Join(lines, "\n"), suggestion)
+}
+
+// The lines describing the protections presented in the deletion plan
+func (d *deletionProtections) planLines(options DestroyOptions) []string {
+	lines := []string{}
+
+	if len(d.locks) > 0 && options.RemoveLocks() {
+		lines = append(lines, "Management lock(s) to be removed:", "")
+		for _, lock := range d.locks {
+			lines = append(lines, fmt.Sprintf("  • %s lock '%s' on %s",
+				lock.Properties.Level, lock.Name, path.Base(lock.Scope())))
+		}
+		lines = append(lines, "")
+	}
+
+	if len(d.purgeProtectedVaults) > 0 || len(d.purgeProtectedAppConfigs) > 0 {
+		lines = append(lines,
+			"Key Vault(s) and App Configuration(s) with purge protection, kept soft-deleted until their retention ends:", "")
+		for _, name := range d.purgeProtectedNames() {
+			lines = append(lines, fmt.Sprintf("  • %s", name))
+		}
+		lines = append(lines, output.WithGrayFormat("  Their names can't be reused by another environment until then."), "")
+	}
+
+	return lines
+}
+
+// Removes the locks created by the environment, validate ensures no other locks remain
+func (p *BicepProvider) removeLocks(ctx context.Context, protections *deletionProtections) error {
+	for _, lock := range protections.locks {
+		message := fmt.Sprintf("Removing %s lock: %s", lock.Properties.Level, output.WithHighLightFormat(lock.Name))
+		p.console.ShowSpinner(ctx, message, input.Step)
+		err := p.azCli.DeleteManagementLock(ctx, p.env.GetSubscriptionId(), lock.Id)
+		p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
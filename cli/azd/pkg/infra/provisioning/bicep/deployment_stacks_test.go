@@ -0,0 +1,223 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+const cTestStackPath = "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deploymentStacks/test-env"
+
+var cTestEnvStack = azsdk.DeploymentStack{
+	Id:       cTestStackPath,
+	Name:     "test-env",
+	Location: "westus2",
+	Properties: azsdk.DeploymentStackProperties{
+		ProvisioningState: "succeeded",
+		DeploymentId:      "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deployments/test-env-1700000000",
+		Outputs: map[string]interface{}{
+			"WEBSITE_URL": map[string]interface{}{"value": "http://myapp.azurewebsites.net", "type": "string"},
+		},
+		Resources: []azsdk.DeploymentStackResource{
+			{Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP", Status: "managed"},
+			{Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/DETACHED_GROUP", Status: "detached"},
+		},
+	},
+}
+
+func TestBicepDeploymentStacksPlan(t *testing.T) {
+	t.Run("StackTarget", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		infraProvider := createBicepProvider(t, mockContext)
+		infraProvider.options.DeploymentStacks = true
+		infraProvider.options.DenySettings = azsdk.DenySettingsModeDenyDelete
+
+		planningTask := infraProvider.Plan(*mockContext.Context)
+		go func() {
+			for range planningTask.Progress() {
+			}
+		}()
+
+		deploymentPlan, err := planningTask.Await()
+		require.NoError(t, err)
+
+		target := deploymentPlan.Details.(BicepDeploymentDetails).Target
+		require.IsType(t, &infra.StackDeployment{}, target)
+		require.Equal(t, "test-env", target.Name())
+		require.Equal(t, "westus2", target.(*infra.StackDeployment).Location())
+		require.Equal(t, "https://portal.azure.com/#resource"+cTestStackPath, target.PortalUrl())
+	})
+
+	t.Run("UnsupportedDenySettings", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		infraProvider := createBicepProvider(t, mockContext)
+		infraProvider.options.DeploymentStacks = true
+		infraProvider.options.DenySettings = "denyAll"
+
+		planningTask := infraProvider.Plan(*mockContext.Context)
+		go func() {
+			for range planningTask.Progress() {
+			}
+		}()
+
+		_, err := planningTask.Await()
+		require.ErrorContains(t, err, "infra.denySettings: unsupported mode 'denyAll'")
+	})
+}
+
+func TestBicepDeploymentStacksDeploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+
+	var deployed azsdk.DeploymentStack
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, cTestStackPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(request.Body).Decode(&deployed); err != nil {
+			return nil, err
+		}
+
+		return stackResponse(request, cTestEnvStack)
+	})
+
+	infraProvider := createBicepProvider(t, mockContext)
+	infraProvider.options.DeploymentStacks = true
+
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+	deploymentPlan := DeploymentPlan{
+		Deployment: Deployment{},
+		Details: BicepDeploymentDetails{
+			Template:   azure.RawArmTemplate(`{"resources":[]}`),
+			Parameters: testArmParameters,
+			Target: infra.NewStackDeployment(
+				azCli,
+				infra.NewSubscriptionDeployment(azCli, "westus2", "SUBSCRIPTION_ID", "test-env-1700000000"),
+				"test-env",
+				azsdk.DenySettingsModeDenyWriteAndDelete,
+			),
+		},
+	}
+
+	deployTask := infraProvider.Deploy(*mockContext.Context, &deploymentPlan)
+	go func() {
+		for range deployTask.Progress() {
+		}
+	}()
+
+	deployResult, err := deployTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, "http://myapp.azurewebsites.net", deployResult.Deployment.Outputs["WEBSITE_URL"].Value)
+
+	require.Equal(t, "westus2", deployed.Location)
+	require.Equal(t, "test-env", *deployed.Tags[azure.TagKeyAzdEnvName])
+	require.JSONEq(t, `{"resources":[]}`, string(deployed.Properties.Template))
+	require.Equal(t, azsdk.DenySettingsModeDenyWriteAndDelete, deployed.Properties.DenySettings.Mode)
+	require.Equal(t, azsdk.DeploymentStackActionDelete, deployed.Properties.ActionOnUnmanage.Resources)
+	require.Equal(t, azsdk.DeploymentStackActionDelete, deployed.Properties.ActionOnUnmanage.ResourceGroups)
+}
+
+func TestBicepDeploymentStacksState(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+	prepareStackMocks(mockContext)
+
+	infraProvider := createBicepProvider(t, mockContext)
+	infraProvider.options.DeploymentStacks = true
+
+	stateTask := infraProvider.State(*mockContext.Context)
+	go func() {
+		for range stateTask.Progress() {
+		}
+	}()
+
+	stateResult, err := stateTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, "http://myapp.azurewebsites.net", stateResult.State.Outputs["WEBSITE_URL"].Value)
+
+	// Detached resources aren't managed by the stack anymore
+	require.Equal(t, []Resource{{Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP"}},
+		stateResult.State.Resources)
+}
+
+func TestBicepDeploymentStacksDestroy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+	prepareDestroyMocks(mockContext)
+	prepareStackMocks(mockContext)
+
+	deletedStacks := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete && strings.HasSuffix(request.URL.Path, cTestStackPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deletedStacks = append(deletedStacks, request.URL.Query().Encode())
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	deletedGroups := 0
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete &&
+			strings.HasSuffix(request.URL.Path, "subscriptions/SUBSCRIPTION_ID/resourcegroups/RESOURCE_GROUP")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deletedGroups++
+		return httpRespondFn(request)
+	})
+
+	infraProvider := createBicepProvider(t, mockContext)
+	infraProvider.options.DeploymentStacks = true
+
+	destroyTask := infraProvider.Destroy(*mockContext.Context, NewDestroyOptions(true, true))
+	go func() {
+		for range destroyTask.Progress() {
+		}
+	}()
+
+	destroyResult, err := destroyTask.Await()
+	require.NoError(t, err)
+	require.Contains(t, destroyResult.InvalidatedEnvKeys, "WEBSITE_URL")
+	require.Equal(t, []string{"ac-123", "ac2-123", "apim-123", "apim2-123", "kv-123", "kv2-123"}, destroyResult.Purged)
+
+	// The stack deletes the resources and resource groups it manages
+	require.Equal(t, []string{
+		"api-version=2024-03-01&unmanageAction.ResourceGroups=delete&unmanageAction.Resources=delete",
+	}, deletedStacks)
+	require.Equal(t, 0, deletedGroups)
+}
+
+func prepareStackMocks(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, cTestStackPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return stackResponse(request, cTestEnvStack)
+	})
+}
+
+func stackResponse(request *http.Request, stack azsdk.DeploymentStack) (*http.Response, error) {
+	body, err := json.Marshal(stack)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Request:    request,
+		Header:     http.Header{},
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBuffer(body)),
+	}, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// Fingerprint hashes the compiled template of the plan, the values of its parameters and the scope it's deployed to.
+// The name of the deployment is unique to each provisioning and isn't part of the fingerprint.
+func (p *BicepProvider) Fingerprint(ctx context.Context, plan *DeploymentPlan) (string, error) {
+	details, ok := plan.Details.(BicepDeploymentDetails)
+	if !ok {
+		return "", fmt.Errorf("unexpected deployment plan details %T", plan.Details)
+	}
+
+	// Parameters are a map, marshaled with sorted keys
+	parameters, err := json.Marshal(details.Parameters)
+	if err != nil {
+		return "", fmt.Errorf("marshaling parameters: %w", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(p.Name()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(fingerprintScope(details.Target)))
+	hash.Write([]byte{0})
+	hash.Write(details.Template)
+	hash.Write([]byte{0})
+	hash.Write(parameters)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fingerprintScope describes where the deployment is deployed to, ex. the subscription and location
+func fingerprintScope(target infra.Deployment) string {
+	switch scope := target.(type) {
+	case *infra.SubscriptionDeployment:
+		return fmt.Sprintf("subscription/%s/%s", scope.SubscriptionId(), scope.Location())
+	case *infra.ResourceGroupDeployment:
+		return fmt.Sprintf("resourceGroup/%s/%s", scope.SubscriptionId(), scope.ResourceGroupName())
+	case *infra.StackDeployment:
+		return fmt.Sprintf("stack/%s/%s/%s", scope.SubscriptionId(), scope.ResourceGroupName(), scope.Location())
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%T/%s", target, target.SubscriptionId())
+	}
+}
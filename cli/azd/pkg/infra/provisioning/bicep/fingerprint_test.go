@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBicepFingerprint(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+
+	infraProvider := createBicepProvider(t, mockContext)
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+	fingerprint := func(deploymentName string, location string, parameters azure.ArmParameters) string {
+		value, err := infraProvider.Fingerprint(*mockContext.Context, &DeploymentPlan{
+			Details: BicepDeploymentDetails{
+				Template:   azure.RawArmTemplate(`{"resources": []}`),
+				Parameters: parameters,
+				Target: infra.NewSubscriptionDeployment(
+					azCli, location, infraProvider.env.GetSubscriptionId(), deploymentName),
+			},
+		})
+		require.NoError(t, err)
+
+		return value
+	}
+
+	expected := fingerprint("test-env-1", "westus2", testArmParameters)
+	require.NotEmpty(t, expected)
+
+	// Each provisioning creates a deployment of its own
+	require.Equal(t, expected, fingerprint("test-env-2", "westus2", testArmParameters))
+
+	require.NotEqual(t, expected, fingerprint("test-env-1", "eastus2", testArmParameters))
+	require.NotEqual(t, expected, fingerprint("test-env-1", "westus2", azure.ArmParameters{
+		"location": {Value: "westus2"},
+	}))
+}
@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// PreviewDeploy runs an ARM what-if of the deployment of the plan, returning the changes of the resources
+func (p *BicepProvider) PreviewDeploy(ctx context.Context, plan *DeploymentPlan) (*DeploymentPreview, error) {
+	details, ok := plan.Details.(BicepDeploymentDetails)
+	if !ok {
+		return nil, fmt.Errorf("unexpected deployment plan details %T", plan.Details)
+	}
+
+	message := "Previewing changes of resources (what-if)"
+	p.console.ShowSpinner(ctx, message, input.Step)
+	result, err := details.Target.WhatIf(ctx, details.Template, details.Parameters)
+	p.console.StopSpinner(ctx, "", input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeploymentPreview{Changes: []ResourceChange{}}
+	if result.Properties == nil {
+		return preview, nil
+	}
+
+	for _, change := range result.Properties.Changes {
+		if change == nil || change.ResourceID == nil || change.ChangeType == nil {
+			continue
+		}
+
+		resourceChange := ResourceChange{
+			ChangeType: ChangeType(*change.ChangeType),
+			ResourceId: *change.ResourceID,
+			Name:       path.Base(*change.ResourceID),
+		}
+
+		if resourceId, err := arm.ParseResourceID(*change.ResourceID); err == nil {
+			resourceChange.ResourceType = resourceId.ResourceType.String()
+			resourceChange.Name = resourceId.Name
+		}
+
+		for _, delta := range change.Delta {
+			if delta != nil && delta.Path != nil {
+				resourceChange.ChangedProperties = append(resourceChange.ChangedProperties, *delta.Path)
+			}
+		}
+
+		preview.Changes = append(preview.Changes, resourceChange)
+	}
+
+	// Resources are listed in a stable order, by type then name
+	sort.SliceStable(preview.Changes, func(i, j int) bool {
+		if preview.Changes[i].ResourceType != preview.Changes[j].ResourceType {
+			return preview.Changes[i].ResourceType < preview.Changes[j].ResourceType
+		}
+
+		return preview.Changes[i].Name < preview.Changes[j].Name
+	})
+
+	return preview, nil
+}
+
+// PreviewDestroy returns the resource groups of the latest deployment of the environment and their resources, with the
+// protections Destroy would detect
+func (p *BicepProvider) PreviewDestroy(ctx context.Context, options DestroyOptions) (*DestroyPreview, error) {
+	message := "Listing resources to delete"
+	p.console.ShowSpinner(ctx, message, input.Step)
+	preview, err := p.previewDestroy(ctx, options)
+	p.console.StopSpinner(ctx, "", input.GetStepResultFormat(err))
+
+	return preview, err
+}
+
+func (p *BicepProvider) previewDestroy(ctx context.Context, options DestroyOptions) (*DestroyPreview, error) {
+	_, template, err := p.compileBicep(ctx, p.modulePath())
+	if err != nil {
+		return nil, fmt.Errorf("creating template: %w", err)
+	}
+
+	scope, err := p.scopeForTemplate(ctx, template)
+	if err != nil {
+		return nil, fmt.Errorf("computing deployment scope: %w", err)
+	}
+
+	deployment, err := p.latestDeployment(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	groupedResources, err := p.getAllResourcesToDelete(ctx, resourceGroupsFromDeployment(deployment))
+	if err != nil {
+		return nil, fmt.Errorf("getting resources to delete: %w", err)
+	}
+
+	protections, err := p.getDeletionProtections(ctx, groupedResources)
+	if err != nil {
+		return nil, fmt.Errorf("getting deletion protections: %w", err)
+	}
+
+	roleAssignments, err := p.getRoleAssignmentsToDelete(ctx, groupedResources)
+	if err != nil {
+		return nil, fmt.Errorf("getting role assignments to delete: %w", err)
+	}
+
+	return p.newDestroyPreview(groupedResources, protections, roleAssignments, options), nil
+}
+
+// newDestroyPreview lists the resource groups and their resources by name, with their role assignments and the
+// protections of their deletion
+func (p *BicepProvider) newDestroyPreview(
+	groupedResources map[string][]azcli.AzCliResource,
+	protections *deletionProtections,
+	roleAssignments []azcli.AzCliRoleAssignment,
+	options DestroyOptions,
+) *DestroyPreview {
+	preview := &DestroyPreview{
+		Resources:       []DestroyResource{},
+		RoleAssignments: []DestroyRoleAssignment{},
+		Locks:           []string{},
+		PurgeProtected:  protections.purgeProtectedNames(),
+	}
+
+	resourceGroups := make([]string, 0, len(groupedResources))
+	for resourceGroup := range groupedResources {
+		resourceGroups = append(resourceGroups, resourceGroup)
+	}
+	sort.Strings(resourceGroups)
+
+	for _, resourceGroup := range resourceGroups {
+		preview.Resources = append(preview.Resources, DestroyResource{
+			Id:            fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", p.env.GetSubscriptionId(), resourceGroup),
+			Name:          resourceGroup,
+			Type:          "Microsoft.Resources/resourceGroups",
+			ResourceGroup: resourceGroup,
+		})
+
+		for _, resource := range groupedResources[resourceGroup] {
+			preview.Resources = append(preview.Resources, DestroyResource{
+				Id:            resource.Id,
+				Name:          resource.Name,
+				Type:          resource.Type,
+				ResourceGroup: resourceGroup,
+			})
+		}
+	}
+
+	for _, assignment := range roleAssignments {
+		preview.RoleAssignments = append(preview.RoleAssignments, DestroyRoleAssignment{
+			Id:          assignment.Id,
+			Scope:       assignment.Scope,
+			PrincipalId: assignment.PrincipalId,
+			RoleName:    assignment.RoleName,
+		})
+	}
+
+	for _, lock := range protections.locks {
+		description := fmt.Sprintf("%s lock '%s' on %s", lock.Properties.Level, lock.Name, path.Base(lock.Scope()))
+		if options.RemoveLocks() && isEnvironmentLock(lock, p.env.GetEnvName()) {
+			description += " (removed)"
+		}
+
+		preview.Locks = append(preview.Locks, description)
+	}
+
+	return preview
+}
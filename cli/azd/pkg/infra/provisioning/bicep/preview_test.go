@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBicepPreviewDeploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+
+	whatIfResult := armresources.WhatIfOperationResult{
+		Status: convert.RefOf("Succeeded"),
+		Properties: &armresources.WhatIfOperationProperties{
+			Changes: []*armresources.WhatIfChange{
+				{
+					ChangeType: convert.RefOf(armresources.ChangeTypeModify),
+					ResourceID: convert.RefOf(
+						"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/app-123"),
+					Delta: []*armresources.WhatIfPropertyChange{
+						{Path: convert.RefOf("properties.siteConfig.linuxFxVersion")},
+					},
+				},
+				{
+					ChangeType: convert.RefOf(armresources.ChangeTypeCreate),
+					ResourceID: convert.RefOf("/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP"),
+				},
+			},
+		},
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(
+			request.URL.Path,
+			"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deployments/test-env/whatIf",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, _ := json.Marshal(whatIfResult)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+			Request:    request,
+		}, nil
+	})
+
+	infraProvider := createBicepProvider(t, mockContext)
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+	preview, err := infraProvider.PreviewDeploy(*mockContext.Context, &DeploymentPlan{
+		Details: BicepDeploymentDetails{
+			Template:   azure.RawArmTemplate("{}"),
+			Parameters: testArmParameters,
+			Target: infra.NewSubscriptionDeployment(
+				azCli,
+				infraProvider.env.GetLocation(),
+				infraProvider.env.GetSubscriptionId(),
+				infraProvider.env.GetEnvName(),
+			),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ResourceChange{
+		{
+			ChangeType:   ChangeTypeCreate,
+			ResourceId:   "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP",
+			ResourceType: "Microsoft.Resources/resourceGroups",
+			Name:         "RESOURCE_GROUP",
+		},
+		{
+			ChangeType:        ChangeTypeModify,
+			ResourceId:        "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/app-123",
+			ResourceType:      "Microsoft.Web/sites",
+			Name:              "app-123",
+			ChangedProperties: []string{"properties.siteConfig.linuxFxVersion"},
+		},
+	}, preview.Changes)
+}
+
+func TestBicepPreviewDestroy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+	prepareStateMocks(mockContext)
+	prepareDestroyMocks(mockContext)
+
+	deleted := false
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = true
+		return httpRespondFn(request)
+	})
+
+	infraProvider := createBicepProvider(t, mockContext)
+	preview, err := infraProvider.PreviewDestroy(*mockContext.Context, NewDestroyOptions(false, false))
+	require.NoError(t, err)
+	require.False(t, deleted)
+
+	require.Len(t, preview.Resources, 8)
+	require.Equal(t, DestroyResource{
+		Id:            "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP",
+		Name:          "RESOURCE_GROUP",
+		Type:          "Microsoft.Resources/resourceGroups",
+		ResourceGroup: "RESOURCE_GROUP",
+	}, preview.Resources[0])
+	require.Equal(t, "app-123", preview.Resources[1].Name)
+	require.Len(t, preview.RoleAssignments, 1)
+	require.Equal(t, "Storage Blob Data Reader", preview.RoleAssignments[0].RoleName)
+	require.Empty(t, preview.Locks)
+	require.Empty(t, preview.PurgeProtected)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcenter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// The manifest describing an environment definition of an ADE catalog
+	EnvironmentDefinitionManifestFileName = "environment.yaml"
+	// The ARM template deployed by the environment definition
+	EnvironmentDefinitionTemplateFileName = "azuredeploy.json"
+)
+
+// The ADE runner deploying ARM templates
+const armRunner = "ARMTemplate"
+
+// ADE provides the resource group of the environment, the name & location parameters azd templates receive from the azd
+// environment are defaulted from it instead
+var resourceGroupParameterDefaults = map[string]string{
+	"environmentName": "[resourceGroup().name]",
+	"location":        "[resourceGroup().location]",
+}
+
+// EnvironmentDefinitionManifest is the environment.yaml of an environment definition in an ADE catalog
+type EnvironmentDefinitionManifest struct {
+	Name         string                           `yaml:"name"`
+	Version      string                           `yaml:"version"`
+	Summary      string                           `yaml:"summary,omitempty"`
+	Description  string                           `yaml:"description,omitempty"`
+	Runner       string                           `yaml:"runner"`
+	TemplatePath string                           `yaml:"templatePath"`
+	Parameters   []EnvironmentDefinitionParameter `yaml:"parameters,omitempty"`
+}
+
+// EnvironmentDefinitionParameter is a parameter of an environment definition, prompted for when creating environments
+type EnvironmentDefinitionParameter struct {
+	Id          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Type        string `yaml:"type"`
+	Required    bool   `yaml:"required,omitempty"`
+	Default     any    `yaml:"default,omitempty"`
+	Allowed     []any  `yaml:"allowed,omitempty"`
+}
+
+// EnvironmentDefinition is an environment definition packaged from the infrastructure of a project
+type EnvironmentDefinition struct {
+	Manifest EnvironmentDefinitionManifest
+	Template azure.RawArmTemplate
+}
+
+// NewEnvironmentDefinition packages the compiled ARM template of a project as an environment definition.
+// ADE deploys environment definitions into the resource group of the environment, the template must target
+// resource groups.
+func NewEnvironmentDefinition(
+	manifest EnvironmentDefinitionManifest,
+	rawTemplate azure.RawArmTemplate,
+) (*EnvironmentDefinition, error) {
+	var template azure.ArmTemplate
+	if err := json.Unmarshal(rawTemplate, &template); err != nil {
+		return nil, fmt.Errorf("unmarshalling arm template: %w", err)
+	}
+
+	scope, err := template.TargetScope()
+	if err != nil {
+		return nil, err
+	}
+
+	if scope != azure.DeploymentScopeResourceGroup {
+		return nil, errors.New(
+			"environment definitions are deployed into the resource group of the ADE environment, " +
+				"the infrastructure of the project must target a resource group (targetScope = 'resourceGroup')")
+	}
+
+	// Patch the defaults of the parameters provided by the resource group into the published template
+	var templateObject map[string]any
+	if err := json.Unmarshal(rawTemplate, &templateObject); err != nil {
+		return nil, fmt.Errorf("unmarshalling arm template: %w", err)
+	}
+	rawParameters, _ := templateObject["parameters"].(map[string]any)
+
+	for id, defaultValue := range resourceGroupParameterDefaults {
+		parameter, has := template.Parameters[id]
+		rawParameter, isObject := rawParameters[id].(map[string]any)
+		if !has || !isObject || parameter.DefaultValue != nil {
+			continue
+		}
+
+		parameter.DefaultValue = defaultValue
+		template.Parameters[id] = parameter
+		rawParameter["defaultValue"] = defaultValue
+	}
+
+	patched, err := json.MarshalIndent(templateObject, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshalling arm template: %w", err)
+	}
+
+	ids := make([]string, 0, len(template.Parameters))
+	for id := range template.Parameters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	manifest.Runner = armRunner
+	manifest.TemplatePath = EnvironmentDefinitionTemplateFileName
+	manifest.Parameters = make([]EnvironmentDefinitionParameter, 0, len(ids))
+
+	for _, id := range ids {
+		definition := template.Parameters[id]
+		description, _ := definition.Description()
+
+		parameter := EnvironmentDefinitionParameter{
+			Id:          id,
+			Name:        id,
+			Description: description,
+			Type:        environmentDefinitionParameterType(definition.Type),
+			Required:    definition.DefaultValue == nil,
+		}
+
+		// ARM template expressions are evaluated in the template, they aren't values users would enter
+		if s, isString := definition.DefaultValue.(string); !isString || !strings.HasPrefix(s, "[") {
+			parameter.Default = definition.DefaultValue
+		}
+
+		if definition.AllowedValues != nil {
+			parameter.Allowed = *definition.AllowedValues
+		}
+
+		manifest.Parameters = append(manifest.Parameters, parameter)
+	}
+
+	return &EnvironmentDefinition{
+		Manifest: manifest,
+		Template: patched,
+	}, nil
+}
+
+// Write writes the manifest & template of the environment definition to the directory
+func (d *EnvironmentDefinition) Write(directory string) error {
+	if err := os.MkdirAll(directory, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating environment definition directory: %w", err)
+	}
+
+	manifest, err := yaml.Marshal(d.Manifest)
+	if err != nil {
+		return fmt.Errorf("marshalling environment definition manifest: %w", err)
+	}
+
+	manifestPath := filepath.Join(directory, EnvironmentDefinitionManifestFileName)
+	if err := os.WriteFile(manifestPath, manifest, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing environment definition manifest: %w", err)
+	}
+
+	templatePath := filepath.Join(directory, EnvironmentDefinitionTemplateFileName)
+	if err := os.WriteFile(templatePath, d.Template, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing environment definition template: %w", err)
+	}
+
+	return nil
+}
+
+// environmentDefinitionParameterType maps the type of an ARM template parameter to the type of an environment
+// definition parameter
+func environmentDefinitionParameterType(armType string) string {
+	switch strings.ToLower(armType) {
+	case "int":
+		return "integer"
+	case "bool":
+		return "boolean"
+	case "array":
+		return "array"
+	case "object", "secureobject":
+		return "object"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,71 @@
+package devcenter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const resourceGroupTemplate = `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "environmentName": { "type": "string", "metadata": { "description": "The name of the environment" } },
+    "location": { "type": "string" },
+    "sku": { "type": "string", "defaultValue": "B1", "allowedValues": ["B1", "S1"] },
+    "replicas": { "type": "int", "defaultValue": 1 },
+    "tags": { "type": "object", "defaultValue": "[createObject()]" }
+  },
+  "resources": []
+}`
+
+func TestNewEnvironmentDefinition(t *testing.T) {
+	definition, err := NewEnvironmentDefinition(EnvironmentDefinitionManifest{
+		Name:    "todo",
+		Version: "1.0.0",
+	}, azure.RawArmTemplate(resourceGroupTemplate))
+	require.NoError(t, err)
+
+	require.Equal(t, "ARMTemplate", definition.Manifest.Runner)
+	require.Equal(t, "azuredeploy.json", definition.Manifest.TemplatePath)
+	require.Equal(t, []EnvironmentDefinitionParameter{
+		{Id: "environmentName", Name: "environmentName", Description: "The name of the environment", Type: "string"},
+		{Id: "location", Name: "location", Type: "string"},
+		{Id: "replicas", Name: "replicas", Type: "integer", Default: float64(1)},
+		{Id: "sku", Name: "sku", Type: "string", Default: "B1", Allowed: []any{"B1", "S1"}},
+		{Id: "tags", Name: "tags", Type: "object"},
+	}, definition.Manifest.Parameters)
+
+	// The name & location of the environment default to the resource group ADE creates
+	var template azure.ArmTemplate
+	require.NoError(t, json.Unmarshal(definition.Template, &template))
+	require.Equal(t, "[resourceGroup().name]", template.Parameters["environmentName"].DefaultValue)
+	require.Equal(t, "[resourceGroup().location]", template.Parameters["location"].DefaultValue)
+
+	t.Run("Write", func(t *testing.T) {
+		directory := filepath.Join(t.TempDir(), "todo")
+		require.NoError(t, definition.Write(directory))
+
+		contents, err := os.ReadFile(filepath.Join(directory, EnvironmentDefinitionManifestFileName))
+		require.NoError(t, err)
+
+		var manifest EnvironmentDefinitionManifest
+		require.NoError(t, yaml.Unmarshal(contents, &manifest))
+		require.Equal(t, "todo", manifest.Name)
+		require.Len(t, manifest.Parameters, 5)
+
+		require.FileExists(t, filepath.Join(directory, EnvironmentDefinitionTemplateFileName))
+	})
+
+	t.Run("SubscriptionScope", func(t *testing.T) {
+		_, err := NewEnvironmentDefinition(EnvironmentDefinitionManifest{Name: "todo"}, azure.RawArmTemplate(`{
+			"$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#"
+		}`))
+		require.ErrorContains(t, err, "must target a resource group")
+	})
+}
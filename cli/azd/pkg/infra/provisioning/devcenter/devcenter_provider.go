@@ -0,0 +1,570 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package devcenter contains an implementation of provider.Provider for Azure Deployment Environments. This
+// provider is registered for use when this package is imported, and can be imported for
+// side effects only to register the provider, e.g.:
+//
+// require(
+//
+//	_ "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/devcenter"
+//
+// )
+package devcenter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/drone/envsubst"
+)
+
+// The azd environment values overriding the dev center configuration of azure.yaml
+const (
+	DevCenterEndpointEnvVarName              = "AZURE_DEVCENTER_ENDPOINT"
+	DevCenterProjectEnvVarName               = "AZURE_DEVCENTER_PROJECT"
+	DevCenterCatalogEnvVarName               = "AZURE_DEVCENTER_CATALOG"
+	DevCenterEnvironmentDefinitionEnvVarName = "AZURE_DEVCENTER_ENVIRONMENT_DEFINITION"
+	DevCenterEnvironmentTypeEnvVarName       = "AZURE_DEVCENTER_ENVIRONMENT_TYPE"
+)
+
+// DevCenterProvider exposes infrastructure provisioning through the environment definitions of Azure Deployment
+// Environments (ADE) catalogs. The ADE environment is named after the azd environment.
+type DevCenterProvider struct {
+	env       *environment.Environment
+	options   Options
+	console   input.Console
+	azCli     azcli.AzCli
+	prompters Prompters
+}
+
+// DevCenterDeploymentDetails is the environment definition & parameters of a planned ADE deployment
+type DevCenterDeploymentDetails struct {
+	Config     DevCenterOptions
+	Parameters map[string]any
+}
+
+// Name gets the name of the infra provider
+func (p *DevCenterProvider) Name() string {
+	return "Azure Deployment Environments"
+}
+
+func (p *DevCenterProvider) RequiredExternalTools() []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// NewDevCenterProvider creates a new instance of an Azure Deployment Environments infra provider
+func NewDevCenterProvider(
+	env *environment.Environment,
+	infraOptions Options,
+	console input.Console,
+	azCli azcli.AzCli,
+	prompters Prompters,
+) *DevCenterProvider {
+	return &DevCenterProvider{
+		env:       env,
+		options:   infraOptions,
+		console:   console,
+		azCli:     azCli,
+		prompters: prompters,
+	}
+}
+
+// EnsureConfigured prompts for the dev center configuration missing from azure.yaml & the azd environment, along with
+// the subscription used to authenticate against the dev center
+func (p *DevCenterProvider) EnsureConfigured(ctx context.Context) error {
+	config := p.config()
+	settings := []struct {
+		envVarName string
+		value      string
+		message    string
+	}{
+		{DevCenterEndpointEnvVarName, config.Endpoint, "Enter the endpoint of the dev center"},
+		{DevCenterProjectEnvVarName, config.Project, "Enter the name of the dev center project"},
+		{DevCenterCatalogEnvVarName, config.Catalog, "Enter the name of the catalog"},
+		{DevCenterEnvironmentDefinitionEnvVarName, config.EnvironmentDefinition, "Enter the environment definition"},
+		{DevCenterEnvironmentTypeEnvVarName, config.EnvironmentType, "Enter the environment type"},
+	}
+
+	changed := false
+	for _, setting := range settings {
+		if setting.value != "" {
+			continue
+		}
+
+		value, err := p.console.Prompt(ctx, input.ConsoleOptions{Message: setting.message})
+		if err != nil {
+			return fmt.Errorf("prompting for %s: %w", setting.envVarName, err)
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("%s is required by the devcenter provider", setting.envVarName)
+		}
+
+		p.env.DotenvSet(setting.envVarName, value)
+		changed = true
+	}
+
+	if p.env.GetSubscriptionId() == "" {
+		subscriptionId, err := p.prompters.Subscription(
+			ctx, "Select the Azure Subscription of the dev center")
+		if err != nil {
+			return err
+		}
+
+		p.env.SetSubscriptionId(subscriptionId)
+		changed = true
+	}
+
+	if changed {
+		if err := p.env.Save(); err != nil {
+			return fmt.Errorf("saving environment: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// State gets the outputs of the most recent deployment of the ADE environment along with its resources
+func (p *DevCenterProvider) State(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*StateResult, *StateProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*StateResult, *StateProgress]) {
+			asyncContext.SetProgress(&StateProgress{Message: "Looking up environment", Timestamp: time.Now()})
+
+			config := p.config()
+			devCenterEnvironment, err := p.azCli.GetDevCenterEnvironment(
+				ctx, p.env.GetSubscriptionId(), config.Endpoint, config.Project, p.env.GetEnvName())
+			if err != nil {
+				asyncContext.SetError(fmt.Errorf("looking up environment '%s': %w", p.env.GetEnvName(), err))
+				return
+			}
+
+			state, err := p.environmentState(ctx, devCenterEnvironment)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			asyncContext.SetResult(&StateResult{State: state})
+		})
+}
+
+// Plan resolves the parameters of the environment definition
+func (p *DevCenterProvider) Plan(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*DeploymentPlan, *DeploymentPlanningProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeploymentPlan, *DeploymentPlanningProgress]) {
+			asyncContext.SetProgress(
+				&DeploymentPlanningProgress{Message: "Loading environment definition", Timestamp: time.Now()})
+
+			config := p.config()
+			definition, err := p.azCli.GetDevCenterEnvironmentDefinition(
+				ctx,
+				p.env.GetSubscriptionId(),
+				config.Endpoint,
+				config.Project,
+				config.Catalog,
+				config.EnvironmentDefinition,
+			)
+			if err != nil {
+				asyncContext.SetError(fmt.Errorf(
+					"loading environment definition '%s' of catalog '%s': %w",
+					config.EnvironmentDefinition,
+					config.Catalog,
+					err,
+				))
+				return
+			}
+
+			parameters, err := p.resolveParameters(definition)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			inputParameters := make(map[string]InputParameter, len(definition.Parameters))
+			for _, parameter := range definition.Parameters {
+				inputParameters[parameter.Id] = InputParameter{
+					Type:         parameter.Type,
+					DefaultValue: parameter.Default,
+					Value:        parameters[parameter.Id],
+				}
+			}
+
+			asyncContext.SetProgress(
+				&DeploymentPlanningProgress{Message: "Deployment planning completed", Timestamp: time.Now()})
+			asyncContext.SetResult(&DeploymentPlan{
+				Deployment: Deployment{
+					Parameters: inputParameters,
+					Outputs:    make(map[string]OutputParameter),
+				},
+				Details: &DevCenterDeploymentDetails{
+					Config:     config,
+					Parameters: parameters,
+				},
+			})
+		})
+}
+
+// Deploy creates or updates the ADE environment from the environment definition and waits for its deployment
+func (p *DevCenterProvider) Deploy(
+	ctx context.Context,
+	plan *DeploymentPlan,
+) *async.InteractiveTaskWithProgress[*DeployResult, *DeployProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeployResult, *DeployProgress]) {
+			details, ok := plan.Details.(*DevCenterDeploymentDetails)
+			if !ok {
+				asyncContext.SetError(errors.New("deployment plan was not created by the devcenter provider"))
+				return
+			}
+
+			config := details.Config
+			asyncContext.SetProgress(&DeployProgress{
+				Message: fmt.Sprintf(
+					"Deploying environment '%s' from '%s'", p.env.GetEnvName(), config.EnvironmentDefinition),
+				Timestamp: time.Now(),
+			})
+
+			devCenterEnvironment, err := p.azCli.CreateOrUpdateDevCenterEnvironment(
+				ctx,
+				p.env.GetSubscriptionId(),
+				config.Endpoint,
+				config.Project,
+				p.env.GetEnvName(),
+				azsdk.DevCenterEnvironment{
+					EnvironmentType:           config.EnvironmentType,
+					CatalogName:               config.Catalog,
+					EnvironmentDefinitionName: config.EnvironmentDefinition,
+					Parameters:                details.Parameters,
+				},
+			)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			state, err := p.environmentState(ctx, devCenterEnvironment)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			asyncContext.SetResult(&DeployResult{
+				Deployment: &Deployment{
+					Parameters: plan.Deployment.Parameters,
+					Outputs:    state.Outputs,
+				},
+			})
+		})
+}
+
+// Destroy deletes the ADE environment along with its resources
+func (p *DevCenterProvider) Destroy(
+	ctx context.Context,
+	options DestroyOptions,
+) *async.InteractiveTaskWithProgress[*DestroyResult, *DestroyProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DestroyResult, *DestroyProgress]) {
+			asyncContext.SetProgress(&DestroyProgress{Message: "Looking up environment", Timestamp: time.Now()})
+
+			config := p.config()
+			devCenterEnvironment, err := p.azCli.GetDevCenterEnvironment(
+				ctx, p.env.GetSubscriptionId(), config.Endpoint, config.Project, p.env.GetEnvName())
+			if errors.Is(err, azsdk.ErrDevCenterEnvironmentNotFound) {
+				asyncContext.SetResult(&DestroyResult{InvalidatedEnvKeys: []string{}})
+				return
+			} else if err != nil {
+				asyncContext.SetError(fmt.Errorf("looking up environment '%s': %w", p.env.GetEnvName(), err))
+				return
+			}
+
+			state, err := p.environmentState(ctx, devCenterEnvironment)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			if !options.Force() {
+				confirmed, err := p.console.Confirm(ctx, input.ConsoleOptions{
+					Message: fmt.Sprintf(
+						"Delete the environment '%s' and its %d resource(s)?", p.env.GetEnvName(), len(state.Resources)),
+				})
+				if err != nil {
+					asyncContext.SetError(err)
+					return
+				}
+
+				if !confirmed {
+					asyncContext.SetError(errors.New("user denied delete confirmation"))
+					return
+				}
+			}
+
+			asyncContext.SetProgress(&DestroyProgress{
+				Message:   fmt.Sprintf("Deleting environment '%s'", p.env.GetEnvName()),
+				Timestamp: time.Now(),
+			})
+
+			err = p.azCli.DeleteDevCenterEnvironment(
+				ctx, p.env.GetSubscriptionId(), config.Endpoint, config.Project, p.env.GetEnvName())
+			if err != nil && !errors.Is(err, azsdk.ErrDevCenterEnvironmentNotFound) {
+				asyncContext.SetError(err)
+				return
+			}
+
+			invalidatedEnvKeys := make([]string, 0, len(state.Outputs))
+			for key := range state.Outputs {
+				if key != environment.SubscriptionIdEnvVarName {
+					invalidatedEnvKeys = append(invalidatedEnvKeys, key)
+				}
+			}
+			sort.Strings(invalidatedEnvKeys)
+
+			asyncContext.SetResult(&DestroyResult{InvalidatedEnvKeys: invalidatedEnvKeys})
+		})
+}
+
+// config returns the dev center configuration of azure.yaml overridden by the values of the azd environment
+func (p *DevCenterProvider) config() DevCenterOptions {
+	config := DevCenterOptions{}
+	if p.options.DevCenter != nil {
+		config = *p.options.DevCenter
+	}
+
+	overrides := map[string]*string{
+		DevCenterEndpointEnvVarName:              &config.Endpoint,
+		DevCenterProjectEnvVarName:               &config.Project,
+		DevCenterCatalogEnvVarName:               &config.Catalog,
+		DevCenterEnvironmentDefinitionEnvVarName: &config.EnvironmentDefinition,
+		DevCenterEnvironmentTypeEnvVarName:       &config.EnvironmentType,
+	}
+
+	for envVarName, value := range overrides {
+		if override := p.env.Getenv(envVarName); override != "" {
+			*value = override
+		}
+	}
+
+	return config
+}
+
+// resolveParameters returns the values of the parameters of the environment definition from azure.yaml, substituting
+// the values of the azd environment. Parameters without a value use the default of the definition.
+func (p *DevCenterProvider) resolveParameters(definition *azsdk.DevCenterEnvironmentDefinition) (map[string]any, error) {
+	configured := map[string]any{}
+	if p.options.DevCenter != nil {
+		configured = p.options.DevCenter.Parameters
+	}
+
+	parameters := map[string]any{}
+	missing := []string{}
+	for _, parameter := range definition.Parameters {
+		value, has := configured[parameter.Id]
+		if !has {
+			if parameter.Required && parameter.Default == nil {
+				missing = append(missing, parameter.Id)
+			}
+
+			continue
+		}
+
+		if text, isString := value.(string); isString {
+			replaced, err := envsubst.Eval(text, p.env.Getenv)
+			if err != nil {
+				return nil, fmt.Errorf("substituting environment values of parameter '%s': %w", parameter.Id, err)
+			}
+
+			value = replaced
+		}
+
+		parameters[parameter.Id] = value
+	}
+
+	for id := range configured {
+		if !contains(definition.Parameters, id) {
+			log.Printf("ignoring parameter '%s', not defined by environment definition '%s'", id, definition.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf(
+			"missing values for the required parameters %s of environment definition '%s', "+
+				"set them in the infra.devCenter.parameters section of azure.yaml",
+			strings.Join(missing, ", "),
+			definition.Name,
+		)
+	}
+
+	return parameters, nil
+}
+
+// environmentState returns the outputs of the most recent successful deployment of the resource group of the ADE
+// environment along with its resources. AZURE_SUBSCRIPTION_ID & AZURE_RESOURCE_GROUP are set to the resource group
+// of the environment, unless the deployment outputs them.
+func (p *DevCenterProvider) environmentState(
+	ctx context.Context,
+	devCenterEnvironment *azsdk.DevCenterEnvironment,
+) (*State, error) {
+	state := &State{
+		Outputs:   map[string]OutputParameter{},
+		Resources: []Resource{},
+	}
+
+	if devCenterEnvironment.ResourceGroupId == "" {
+		return state, nil
+	}
+
+	resourceGroupId, err := arm.ParseResourceID(devCenterEnvironment.ResourceGroupId)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resource group id of environment: %w", err)
+	}
+
+	resources, err := p.azCli.ListResourceGroupResources(
+		ctx, resourceGroupId.SubscriptionID, resourceGroupId.ResourceGroupName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing resources of environment: %w", err)
+	}
+
+	for _, resource := range resources {
+		state.Resources = append(state.Resources, Resource{Id: resource.Id})
+	}
+
+	deployments, err := p.azCli.ListResourceGroupDeployments(
+		ctx, resourceGroupId.SubscriptionID, resourceGroupId.ResourceGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments of environment: %w", err)
+	}
+
+	if latest := latestSucceededDeployment(deployments); latest != nil {
+		state.Outputs = outputParameters(latest.Properties.Outputs)
+	}
+
+	if _, has := state.Outputs[environment.SubscriptionIdEnvVarName]; !has {
+		state.Outputs[environment.SubscriptionIdEnvVarName] = OutputParameter{
+			Type:  ParameterTypeString,
+			Value: resourceGroupId.SubscriptionID,
+		}
+	}
+
+	if _, has := state.Outputs[environment.ResourceGroupEnvVarName]; !has {
+		state.Outputs[environment.ResourceGroupEnvVarName] = OutputParameter{
+			Type:  ParameterTypeString,
+			Value: resourceGroupId.ResourceGroupName,
+		}
+	}
+
+	return state, nil
+}
+
+func latestSucceededDeployment(deployments []*armresources.DeploymentExtended) *armresources.DeploymentExtended {
+	var latest *armresources.DeploymentExtended
+	for _, deployment := range deployments {
+		if deployment.Properties == nil || deployment.Properties.Timestamp == nil ||
+			deployment.Properties.ProvisioningState == nil ||
+			*deployment.Properties.ProvisioningState != armresources.ProvisioningStateSucceeded {
+			continue
+		}
+
+		if latest == nil || deployment.Properties.Timestamp.After(*latest.Properties.Timestamp) {
+			latest = deployment
+		}
+	}
+
+	return latest
+}
+
+// outputParameters converts the outputs of an ARM deployment
+func outputParameters(outputs any) map[string]OutputParameter {
+	parameters := map[string]OutputParameter{}
+
+	values, ok := outputs.(map[string]any)
+	if !ok {
+		return parameters
+	}
+
+	for key, value := range values {
+		output, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		outputType, _ := output["type"].(string)
+		parameters[key] = OutputParameter{
+			Type:  parameterType(outputType),
+			Value: output["value"],
+		}
+	}
+
+	return parameters
+}
+
+func parameterType(armType string) ParameterType {
+	switch strings.ToLower(armType) {
+	case "bool":
+		return ParameterTypeBoolean
+	case "int":
+		return ParameterTypeNumber
+	case "object", "secureobject":
+		return ParameterTypeObject
+	case "array":
+		return ParameterTypeArray
+	default:
+		return ParameterTypeString
+	}
+}
+
+func contains(parameters []azsdk.DevCenterParameter, id string) bool {
+	for _, parameter := range parameters {
+		if parameter.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Registers the Azure Deployment Environments provider with the provisioning module
+func init() {
+	err := RegisterProvider(
+		DevCenter,
+		func(
+			ctx context.Context,
+			env *environment.Environment,
+			projectPath string,
+			options Options,
+			console input.Console,
+			azCli azcli.AzCli,
+			_ exec.CommandRunner,
+			prompters Prompters,
+			_ CurrentPrincipalIdProvider,
+			_ *alpha.FeatureManager,
+		) (Provider, error) {
+			return NewDevCenterProvider(env, options, console, azCli, prompters), nil
+		},
+	)
+
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,240 @@
+package devcenter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testEndpoint        = "https://tenant-devcenter.eastus.devcenter.azure.com"
+	testEnvironmentPath = "/projects/platform/users/me/environments/test-env"
+	testResourceGroupId = "/subscriptions/ENV_SUBSCRIPTION_ID/resourceGroups/platform-test-env"
+)
+
+func TestDevCenterPlan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareDefinitionMocks(mockContext)
+
+	provider := createDevCenterProvider(mockContext, map[string]any{
+		"name": "${AZURE_ENV_NAME}-app",
+		"sku":  "B1",
+	})
+
+	deploymentPlan, err := plan(mockContext, provider)
+	require.NoError(t, err)
+
+	details := deploymentPlan.Details.(*DevCenterDeploymentDetails)
+	require.Equal(t, map[string]any{"name": "test-env-app", "sku": "B1"}, details.Parameters)
+	// The environment values override azure.yaml
+	require.Equal(t, "Sandbox", details.Config.EnvironmentType)
+	require.Equal(t, "test-env-app", deploymentPlan.Deployment.Parameters["name"].Value)
+	require.Equal(t, "eastus", deploymentPlan.Deployment.Parameters["location"].DefaultValue)
+
+	t.Run("MissingRequiredParameter", func(t *testing.T) {
+		provider := createDevCenterProvider(mockContext, map[string]any{"sku": "B1"})
+
+		_, err := plan(mockContext, provider)
+		require.ErrorContains(t, err, "missing values for the required parameters name")
+	})
+}
+
+func TestDevCenterDeploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareDefinitionMocks(mockContext)
+	prepareResourceGroupMocks(mockContext)
+
+	var deployed azsdk.DevCenterEnvironment
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && request.URL.Path == testEnvironmentPath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &deployed))
+
+		deployed.Name = "test-env"
+		deployed.ProvisioningState = "Succeeded"
+		deployed.ResourceGroupId = testResourceGroupId
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, deployed)
+	})
+
+	provider := createDevCenterProvider(mockContext, map[string]any{"name": "app"})
+	deploymentPlan, err := plan(mockContext, provider)
+	require.NoError(t, err)
+
+	deployTask := provider.Deploy(*mockContext.Context, deploymentPlan)
+	go func() {
+		for range deployTask.Progress() {
+		}
+	}()
+
+	result, err := deployTask.Await()
+	require.NoError(t, err)
+
+	require.Equal(t, "Sandbox", deployed.EnvironmentType)
+	require.Equal(t, "platform-catalog", deployed.CatalogName)
+	require.Equal(t, "webapp", deployed.EnvironmentDefinitionName)
+	require.Equal(t, map[string]any{"name": "app"}, deployed.Parameters)
+
+	require.Equal(t, map[string]OutputParameter{
+		"WEBSITE_URL":           {Type: ParameterTypeString, Value: "https://app.azurewebsites.net"},
+		"AZURE_SUBSCRIPTION_ID": {Type: ParameterTypeString, Value: "ENV_SUBSCRIPTION_ID"},
+		"AZURE_RESOURCE_GROUP":  {Type: ParameterTypeString, Value: "platform-test-env"},
+	}, result.Deployment.Outputs)
+}
+
+func TestDevCenterDestroy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareResourceGroupMocks(mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == testEnvironmentPath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DevCenterEnvironment{
+			Name:              "test-env",
+			ProvisioningState: "Succeeded",
+			ResourceGroupId:   testResourceGroupId,
+		})
+	})
+
+	deleted := false
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete && request.URL.Path == testEnvironmentPath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = true
+		return mocks.CreateEmptyHttpResponse(request, http.StatusNoContent)
+	})
+
+	provider := createDevCenterProvider(mockContext, nil)
+	destroyTask := provider.Destroy(*mockContext.Context, NewDestroyOptions(true, false))
+	go func() {
+		for range destroyTask.Progress() {
+		}
+	}()
+
+	result, err := destroyTask.Await()
+	require.NoError(t, err)
+
+	require.True(t, deleted)
+	require.Equal(t, []string{"AZURE_RESOURCE_GROUP", "WEBSITE_URL"}, result.InvalidatedEnvKeys)
+}
+
+func plan(mockContext *mocks.MockContext, provider *DevCenterProvider) (*DeploymentPlan, error) {
+	planningTask := provider.Plan(*mockContext.Context)
+	go func() {
+		for range planningTask.Progress() {
+		}
+	}()
+
+	return planningTask.Await()
+}
+
+func createDevCenterProvider(mockContext *mocks.MockContext, parameters map[string]any) *DevCenterProvider {
+	env := environment.EphemeralWithValues("test-env", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		DevCenterEnvironmentTypeEnvVarName:   "Sandbox",
+	})
+
+	options := Options{
+		Provider: DevCenter,
+		DevCenter: &DevCenterOptions{
+			Endpoint:              testEndpoint,
+			Project:               "platform",
+			Catalog:               "platform-catalog",
+			EnvironmentDefinition: "webapp",
+			EnvironmentType:       "Dev",
+			Parameters:            parameters,
+		},
+	}
+
+	return NewDevCenterProvider(
+		env, options, mockContext.Console, mockazcli.NewAzCliFromMockContext(mockContext), Prompters{})
+}
+
+func prepareDefinitionMocks(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.Path == "/projects/platform/catalogs/platform-catalog/environmentDefinitions/webapp"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DevCenterEnvironmentDefinition{
+			Name:        "webapp",
+			CatalogName: "platform-catalog",
+			Parameters: []azsdk.DevCenterParameter{
+				{Id: "name", Type: "string", Required: true},
+				{Id: "location", Type: "string", Required: true, Default: "eastus"},
+				{Id: "sku", Type: "string"},
+			},
+		})
+	})
+}
+
+func prepareResourceGroupMocks(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/platform-test-env/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceListResult{
+			Value: []*armresources.GenericResourceExpanded{
+				{
+					ID:       convert.RefOf(testResourceGroupId + "/providers/Microsoft.Web/sites/app"),
+					Name:     convert.RefOf("app"),
+					Type:     convert.RefOf("Microsoft.Web/sites"),
+					Location: convert.RefOf("eastus"),
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/platform-test-env/providers/Microsoft.Resources/deployments/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		succeeded := armresources.ProvisioningStateSucceeded
+		failed := armresources.ProvisioningStateFailed
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.DeploymentListResult{
+			Value: []*armresources.DeploymentExtended{
+				{
+					Name: convert.RefOf("webapp-1"),
+					Properties: &armresources.DeploymentPropertiesExtended{
+						ProvisioningState: &succeeded,
+						Timestamp:         convert.RefOf(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)),
+						Outputs: map[string]any{
+							"WEBSITE_URL": map[string]any{"type": "String", "value": "https://old.azurewebsites.net"},
+						},
+					},
+				},
+				{
+					Name: convert.RefOf("webapp-2"),
+					Properties: &armresources.DeploymentPropertiesExtended{
+						ProvisioningState: &succeeded,
+						Timestamp:         convert.RefOf(time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)),
+						Outputs: map[string]any{
+							"WEBSITE_URL": map[string]any{"type": "String", "value": "https://app.azurewebsites.net"},
+						},
+					},
+				},
+				{
+					Name: convert.RefOf("webapp-3"),
+					Properties: &armresources.DeploymentPropertiesExtended{
+						ProvisioningState: &failed,
+						Timestamp:         convert.RefOf(time.Date(2023, 10, 3, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+		})
+	})
+}
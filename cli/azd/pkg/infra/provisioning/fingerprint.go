@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The name of the file within the environment directory that stores the fingerprint of the last provisioning
+const FingerprintFileName = ".provision-fingerprint.json"
+
+// Fingerprinter is implemented by the providers able to fingerprint their deployment plans, letting `azd provision`
+// skip deployments identical to the last successful one.
+type Fingerprinter interface {
+	// Fingerprint returns a hash of everything the deployment of the plan depends on, ex. the compiled template and the
+	// values of its parameters. Two plans with the same fingerprint deploy the same infrastructure.
+	Fingerprint(ctx context.Context, plan *DeploymentPlan) (string, error)
+}
+
+// ProvisionFingerprint is the fingerprint of the last successful provisioning of an environment
+type ProvisionFingerprint struct {
+	Fingerprint   string    `json:"fingerprint"`
+	ProvisionedOn time.Time `json:"provisionedOn"`
+}
+
+// FingerprintCache persists the fingerprint of the last successful provisioning of an environment. The fingerprint is
+// cleared when provisioning fails or the infrastructure is destroyed, so the next provisioning always deploys.
+type FingerprintCache struct {
+	path string
+}
+
+// Creates the fingerprint cache of the environment. Environments that aren't persisted are never cached.
+func NewFingerprintCache(env *environment.Environment) *FingerprintCache {
+	if env == nil || env.Root == "" {
+		return &FingerprintCache{}
+	}
+
+	return &FingerprintCache{
+		path: filepath.Join(env.Root, FingerprintFileName),
+	}
+}
+
+// Gets the fingerprint of the last successful provisioning when available
+func (c *FingerprintCache) Get() (*ProvisionFingerprint, bool) {
+	if c.path == "" {
+		return nil, false
+	}
+
+	contents, err := os.ReadFile(c.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed reading provisioning fingerprint: %v\n", err)
+		}
+
+		return nil, false
+	}
+
+	var fingerprint ProvisionFingerprint
+	if err := json.Unmarshal(contents, &fingerprint); err != nil {
+		log.Printf("failed parsing provisioning fingerprint: %v\n", err)
+		return nil, false
+	}
+
+	return &fingerprint, fingerprint.Fingerprint != ""
+}
+
+// Records the fingerprint of a successful provisioning
+func (c *FingerprintCache) Set(fingerprint string) error {
+	if c.path == "" {
+		return nil
+	}
+
+	contents, err := json.Marshal(ProvisionFingerprint{
+		Fingerprint:   fingerprint,
+		ProvisionedOn: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(c.path, contents, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing provisioning fingerprint: %w", err)
+	}
+
+	return nil
+}
+
+// Removes the fingerprint of the last provisioning
+func (c *FingerprintCache) Invalidate() error {
+	if c.path == "" {
+		return nil
+	}
+
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing provisioning fingerprint: %w", err)
+	}
+
+	return nil
+}
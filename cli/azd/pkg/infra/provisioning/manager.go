@@ -31,6 +31,8 @@ type Manager struct {
 	userProfileService *azcli.UserProfileService
 	subResolver        account.SubscriptionTenantResolver
 	interactive        bool
+	stateCache         *StateCache
+	fingerprintCache   *FingerprintCache
 }
 
 // Prepares for an infrastructure provision operation
@@ -77,17 +79,55 @@ func (m *Manager) State(ctx context.Context) (*StateResult, error) {
 		return nil, fmt.Errorf("error retrieving state: %w", err)
 	}
 
+	if err := m.stateCache.SetState(stateResult.State); err != nil {
+		log.Printf("failed caching deployment state: %v\n", err)
+	}
+
 	return stateResult, nil
 }
 
+// UpToDate returns whether the plan deploys the same infrastructure as the last successful provisioning of the
+// environment, in which case deploying it again can be skipped. Plans of providers that can't fingerprint their plans
+// are never up to date.
+func (m *Manager) UpToDate(ctx context.Context, plan *DeploymentPlan) (bool, error) {
+	fingerprint, err := m.fingerprint(ctx, plan)
+	if err != nil || fingerprint == "" {
+		return false, err
+	}
+
+	last, has := m.fingerprintCache.Get()
+	if !has {
+		return false, nil
+	}
+
+	return last.Fingerprint == fingerprint, nil
+}
+
 // Deploys the Azure infrastructure for the specified project
 func (m *Manager) Deploy(ctx context.Context, plan *DeploymentPlan) (*DeployResult, error) {
+	// A plan that can't be fingerprinted is deployed, it's only never skipped
+	fingerprint, err := m.fingerprint(ctx, plan)
+	if err != nil {
+		log.Printf("failed fingerprinting deployment plan: %v\n", err)
+	}
+
 	// Apply the infrastructure deployment
 	deployResult, err := m.deploy(ctx, plan)
+
+	// The cached deployment state is outdated once the infrastructure is provisioned, even when provisioning fails
+	m.invalidateStateCache()
+
 	if err != nil {
+		m.invalidateFingerprint()
 		return nil, err
 	}
 
+	if fingerprint != "" {
+		if err := m.fingerprintCache.Set(fingerprint); err != nil {
+			log.Printf("failed recording provisioning fingerprint: %v\n", err)
+		}
+	}
+
 	if err := UpdateEnvironment(m.env, deployResult.Deployment.Outputs); err != nil {
 		return nil, fmt.Errorf("updating environment with deployment outputs: %w", err)
 	}
@@ -99,6 +139,9 @@ func (m *Manager) Deploy(ctx context.Context, plan *DeploymentPlan) (*DeployResu
 func (m *Manager) Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error) {
 	// Call provisioning provider to destroy the infrastructure
 	destroyResult, err := m.destroy(ctx, options)
+	m.invalidateStateCache()
+	m.invalidateFingerprint()
+
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +160,106 @@ func (m *Manager) Destroy(ctx context.Context, options DestroyOptions) (*Destroy
 	return destroyResult, nil
 }
 
+// Previews the changes the deployment of the plan would make, without deploying it.
+// ErrPreviewNotSupported is returned when the provider can't preview deployments.
+func (m *Manager) PreviewDeploy(ctx context.Context, plan *DeploymentPlan) (*DeploymentPreview, error) {
+	previewer, ok := m.provider.(Previewer)
+	if !ok {
+		return nil, ErrPreviewNotSupported
+	}
+
+	preview, err := previewer.PreviewDeploy(ctx, plan)
+	if err != nil {
+		return nil, fmt.Errorf("previewing infrastructure provisioning: %w", err)
+	}
+
+	return preview, nil
+}
+
+// Previews the resources the destruction of the infrastructure would delete, without deleting them.
+// ErrPreviewNotSupported is returned when the provider can't preview deletions.
+func (m *Manager) PreviewDestroy(ctx context.Context, options DestroyOptions) (*DestroyPreview, error) {
+	previewer, ok := m.provider.(Previewer)
+	if !ok {
+		return nil, ErrPreviewNotSupported
+	}
+
+	preview, err := previewer.PreviewDestroy(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("previewing the deletion of Azure resources: %w", err)
+	}
+
+	return preview, nil
+}
+
+// Describes the backend storing the state of the infrastructure of the environment
+func (m *Manager) StateBackendInfo(ctx context.Context) (*StateBackendInfo, error) {
+	backend, err := m.stateBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.StateBackendInfo(ctx)
+}
+
+// Reads the state of the infrastructure of the environment from its backend
+func (m *Manager) PullState(ctx context.Context) ([]byte, error) {
+	backend, err := m.stateBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.PullState(ctx)
+}
+
+// Removes the lock of the state of the infrastructure of the environment
+func (m *Manager) UnlockState(ctx context.Context, lockId string) error {
+	backend, err := m.stateBackend()
+	if err != nil {
+		return err
+	}
+
+	return backend.UnlockState(ctx, lockId)
+}
+
+// Gets the state backend of the provider.
+// ErrStateBackendNotSupported is returned when the provider doesn't store the state itself.
+func (m *Manager) stateBackend() (StateBackend, error) {
+	backend, ok := m.provider.(StateBackend)
+	if !ok {
+		return nil, ErrStateBackendNotSupported
+	}
+
+	return backend, nil
+}
+
+func (m *Manager) invalidateStateCache() {
+	if err := m.stateCache.Invalidate(); err != nil {
+		log.Printf("failed invalidating deployment state cache: %v\n", err)
+	}
+}
+
+func (m *Manager) invalidateFingerprint() {
+	if err := m.fingerprintCache.Invalidate(); err != nil {
+		log.Printf("failed invalidating provisioning fingerprint: %v\n", err)
+	}
+}
+
+// Returns the fingerprint of the plan, or an empty fingerprint when the provider can't fingerprint its plans
+func (m *Manager) fingerprint(ctx context.Context, plan *DeploymentPlan) (string, error) {
+	fingerprinter, ok := m.provider.(Fingerprinter)
+	if !ok {
+		return "", nil
+	}
+
+	fingerprint, err := fingerprinter.Fingerprint(ctx, plan)
+	if err != nil {
+		return "", fmt.Errorf("fingerprinting deployment plan: %w", err)
+	}
+
+	return fingerprint, nil
+}
+
 // Plans the infrastructure provisioning and orchestrates interactive terminal operations
 func (m *Manager) plan(ctx context.Context) (*DeploymentPlan, error) {
 	planningTask := m.provider.Plan(ctx)
@@ -254,6 +397,8 @@ func NewManager(
 		accountManager:     accountManager,
 		userProfileService: userProfileService,
 		subResolver:        subResolver,
+		stateCache:         NewStateCache(env),
+		fingerprintCache:   NewFingerprintCache(env),
 	}
 
 	prompters := Prompters{
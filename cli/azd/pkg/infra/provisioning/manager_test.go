@@ -273,3 +273,68 @@ func (m *mockSubscriptionTenantResolver) LookupTenant(
 	ctx context.Context, subscriptionId string) (tenantId string, err error) {
 	return "00000000-0000-0000-0000-000000000000", nil
 }
+
+func TestManagerUpToDate(t *testing.T) {
+	env := environment.EphemeralWithValues("test-env", map[string]string{
+		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+		"AZURE_LOCATION":        "eastus2",
+	})
+	env.Root = t.TempDir()
+	options := Options{Provider: "test"}
+	interactive := false
+
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+	mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "Are you sure you want to destroy?")
+	}).Respond(true)
+
+	mgr, err := NewManager(
+		*mockContext.Context,
+		env,
+		"",
+		options,
+		interactive,
+		azCli,
+		mockContext.Console,
+		mockContext.CommandRunner,
+		&mockaccount.MockAccountManager{},
+		azcli.NewUserProfileService(
+			&mocks.MockMultiTenantCredentialProvider{},
+			mockContext.HttpClient,
+		),
+		&mockSubscriptionTenantResolver{},
+		mockContext.AlphaFeaturesManager,
+	)
+	require.NoError(t, err)
+
+	requireUpToDate := func(expected bool) *DeploymentPlan {
+		deploymentPlan, err := mgr.Plan(*mockContext.Context)
+		require.NoError(t, err)
+
+		upToDate, err := mgr.UpToDate(*mockContext.Context, deploymentPlan)
+		require.NoError(t, err)
+		require.Equal(t, expected, upToDate)
+
+		return deploymentPlan
+	}
+
+	// Never provisioned
+	deploymentPlan := requireUpToDate(false)
+	_, err = mgr.Deploy(*mockContext.Context, deploymentPlan)
+	require.NoError(t, err)
+	requireUpToDate(true)
+
+	// The parameters changed
+	env.SetLocation("westus2")
+	deploymentPlan = requireUpToDate(false)
+	_, err = mgr.Deploy(*mockContext.Context, deploymentPlan)
+	require.NoError(t, err)
+	requireUpToDate(true)
+
+	// The infrastructure was destroyed
+	_, err = mgr.Destroy(*mockContext.Context, NewDestroyOptions(false, false))
+	require.NoError(t, err)
+	requireUpToDate(false)
+}
@@ -34,6 +34,8 @@ type DestroyOptions struct {
 	force bool
 	// Whether or not to purge any key vaults associated with the deployment
 	purge bool
+	// Whether or not to remove the management locks created by the templates of the environment
+	removeLocks bool
 }
 
 func (o *DestroyOptions) Purge() bool {
@@ -44,6 +46,17 @@ func (o *DestroyOptions) Force() bool {
 	return o.force
 }
 
+func (o *DestroyOptions) RemoveLocks() bool {
+	return o.removeLocks
+}
+
+// WithRemoveLocks returns the options removing the management locks created by the templates of the environment before
+// resources are deleted
+func (o DestroyOptions) WithRemoveLocks(removeLocks bool) DestroyOptions {
+	o.removeLocks = removeLocks
+	return o
+}
+
 func NewDestroyOptions(force bool, purge bool) DestroyOptions {
 	return DestroyOptions{
 		force: force,
@@ -0,0 +1,75 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPreviewNotSupported is returned by the providers that can't preview their operations without executing them
+var ErrPreviewNotSupported = errors.New("the provisioning provider doesn't support previews")
+
+// ChangeType is the change a deployment makes to a resource
+type ChangeType string
+
+const (
+	ChangeTypeCreate      ChangeType = "Create"
+	ChangeTypeModify      ChangeType = "Modify"
+	ChangeTypeDelete      ChangeType = "Delete"
+	ChangeTypeNoChange    ChangeType = "NoChange"
+	ChangeTypeIgnore      ChangeType = "Ignore"
+	ChangeTypeDeploy      ChangeType = "Deploy"
+	ChangeTypeUnsupported ChangeType = "Unsupported"
+)
+
+// ResourceChange is the change a deployment makes to a resource
+type ResourceChange struct {
+	ChangeType   ChangeType `json:"changeType"`
+	ResourceId   string     `json:"resourceId"`
+	ResourceType string     `json:"resourceType"`
+	Name         string     `json:"name"`
+	// The paths of the properties a modification changes, ex. properties.siteConfig.linuxFxVersion
+	ChangedProperties []string `json:"changedProperties,omitempty"`
+}
+
+// DeploymentPreview describes the changes of the resources a deployment plan would make
+type DeploymentPreview struct {
+	Changes []ResourceChange `json:"changes"`
+}
+
+// DestroyResource is a resource that would be deleted
+type DestroyResource struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	ResourceGroup string `json:"resourceGroup"`
+}
+
+// DestroyRoleAssignment is a role assignment that would be deleted with its resource group
+type DestroyRoleAssignment struct {
+	Id          string `json:"id"`
+	Scope       string `json:"scope"`
+	PrincipalId string `json:"principalId"`
+	RoleName    string `json:"roleName,omitempty"`
+}
+
+// DestroyPreview describes the resources the destruction of the infrastructure would delete
+type DestroyPreview struct {
+	Resources []DestroyResource `json:"resources"`
+	// The role assignments of the resource groups and their resources
+	RoleAssignments []DestroyRoleAssignment `json:"roleAssignments"`
+	// The management locks preventing the deletion, removed by `azd down --remove-locks` when created by the environment
+	Locks []string `json:"locks"`
+	// The resources kept soft-deleted after their deletion, ex. key vaults and app configurations with purge protection
+	PurgeProtected []string `json:"purgeProtected"`
+}
+
+// Previewer is implemented by the providers able to preview their operations without executing them, for --dry-run
+type Previewer interface {
+	// PreviewDeploy returns the changes the deployment of the plan would make
+	PreviewDeploy(ctx context.Context, plan *DeploymentPlan) (*DeploymentPreview, error)
+	// PreviewDestroy returns the resources Destroy would delete
+	PreviewDestroy(ctx context.Context, options DestroyOptions) (*DestroyPreview, error)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
@@ -66,6 +67,7 @@ const (
 	Arm       ProviderKind = "arm"
 	Terraform ProviderKind = "terraform"
 	Pulumi    ProviderKind = "pulumi"
+	DevCenter ProviderKind = "devcenter"
 	Test      ProviderKind = "test"
 )
 
@@ -73,6 +75,44 @@ type Options struct {
 	Provider ProviderKind `yaml:"provider"`
 	Path     string       `yaml:"path"`
 	Module   string       `yaml:"module"`
+	// The Azure Deployment Environments configuration used by the devcenter provider
+	DevCenter *DevCenterOptions `yaml:"devCenter,omitempty"`
+	// The diagram of the infrastructure updated by each provision, relative to the project, ex. docs/architecture.md
+	Diagram string `yaml:"diagram,omitempty"`
+	// The monthly budget of the resources of each environment, created by each provision
+	Budget *infra.BudgetOptions `yaml:"budget,omitempty"`
+	// The roles assigned after each provision, removed when the environment is deleted
+	RoleAssignments []infra.RoleAssignmentOptions `yaml:"roleAssignments,omitempty"`
+	// Deploys the bicep templates as a deployment stack named after the environment instead of a deployment
+	DeploymentStacks bool `yaml:"deploymentStacks,omitempty"`
+	// The deny settings mode of the deployment stack: none (default), denyDelete or denyWriteAndDelete
+	DenySettings string `yaml:"denySettings,omitempty"`
+	// Stores the terraform state of each environment in an Azure Storage account created by azd
+	RemoteState *RemoteStateOptions `yaml:"remoteState,omitempty"`
+}
+
+// RemoteStateOptions names the Azure Storage resources storing the terraform state of the environments. The resources
+// are created by azd when they don't exist, the defaults are specific to each environment.
+type RemoteStateOptions struct {
+	// The resource group of the storage account. (Default: rg-<environment>-tfstate)
+	ResourceGroup string `yaml:"resourceGroup,omitempty"`
+	// The storage account. (Default: derived from the subscription and environment)
+	StorageAccount string `yaml:"storageAccount,omitempty"`
+	// The blob container of the state files. (Default: tfstate)
+	Container string `yaml:"container,omitempty"`
+}
+
+// DevCenterOptions describes the environment definition of a dev center catalog deployed by the devcenter provider.
+// Each value can be overridden by the corresponding AZURE_DEVCENTER_* value of the azd environment.
+type DevCenterOptions struct {
+	// The dev center endpoint, ex. https://{tenantId}-{devCenterName}.{region}.devcenter.azure.com
+	Endpoint              string `yaml:"endpoint,omitempty"`
+	Project               string `yaml:"project,omitempty"`
+	Catalog               string `yaml:"catalog,omitempty"`
+	EnvironmentDefinition string `yaml:"environmentDefinition,omitempty"`
+	EnvironmentType       string `yaml:"environmentType,omitempty"`
+	// The values of the parameters of the environment definition, string values support environment substitutions
+	Parameters map[string]any `yaml:"parameters,omitempty"`
 }
 
 type DeploymentPlan struct {
@@ -94,6 +134,12 @@ type DeployResult struct {
 type DestroyResult struct {
 	// InvalidatedEnvKeys is a list of keys that should be removed from the environment after the destroy is complete.
 	InvalidatedEnvKeys []string
+
+	// Deleted describes the deleted resources, for the deletion report of `azd down`. Nil when the provider doesn't
+	// list the resources it deletes.
+	Deleted *DestroyPreview
+	// The names of the soft-deleted resources that were purged
+	Purged []string
 }
 
 type DeployProgress struct {
@@ -147,6 +193,12 @@ func RegisterProvider(kind ProviderKind, newFn NewProviderFn) error {
 	return nil
 }
 
+// Returns true when a provider creation function has been registered for the specified provider kind
+func IsProviderRegistered(kind ProviderKind) bool {
+	_, has := providers[kind]
+	return has
+}
+
 func NewProvider(
 	ctx context.Context,
 	console input.Console,
@@ -0,0 +1,622 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package pulumi contains an implementation of provider.Provider for Pulumi. This
+// provider is registered for use when this package is imported, and can be imported for
+// side effects only to register the provider, e.g.:
+//
+// require(
+//
+//	_ "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/pulumi"
+//
+// )
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
+	"github.com/drone/envsubst"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// The URL of the Pulumi backend storing the state of the stack, ex. azblob://state or https://api.pulumi.com.
+	// Defaults to a local backend in the .azure directory of the environment.
+	BackendUrlEnvVarName = "PULUMI_BACKEND_URL"
+	// The passphrase encrypting the secrets of the stack with self-managed backends
+	ConfigPassphraseEnvVarName = "PULUMI_CONFIG_PASSPHRASE"
+	// The name of the stack, defaults to the name of the environment
+	StackEnvVarName = "PULUMI_STACK"
+)
+
+// PulumiProvider exposes infrastructure provisioning using Pulumi programs
+type PulumiProvider struct {
+	env          *environment.Environment
+	prompters    Prompters
+	projectPath  string
+	options      Options
+	console      input.Console
+	cli          pulumi.PulumiCli
+	curPrincipal CurrentPrincipalIdProvider
+}
+
+type PulumiDeploymentDetails struct {
+	ProgramPath string
+	Stack       string
+}
+
+// Name gets the name of the infra provider
+func (p *PulumiProvider) Name() string {
+	return "Pulumi"
+}
+
+func (p *PulumiProvider) RequiredExternalTools() []tools.ExternalTool {
+	return []tools.ExternalTool{p.cli}
+}
+
+// NewPulumiProvider creates a new instance of a Pulumi Infra provider
+func NewPulumiProvider(
+	ctx context.Context,
+	env *environment.Environment,
+	projectPath string,
+	infraOptions Options,
+	console input.Console,
+	commandRunner exec.CommandRunner,
+	curPrincipal CurrentPrincipalIdProvider,
+	prompters Prompters,
+) *PulumiProvider {
+	// Default to a module named "main" if not specified.
+	if strings.TrimSpace(infraOptions.Module) == "" {
+		infraOptions.Module = "main"
+	}
+
+	return &PulumiProvider{
+		env:          env,
+		projectPath:  projectPath,
+		options:      infraOptions,
+		console:      console,
+		cli:          pulumi.NewPulumiCli(commandRunner),
+		curPrincipal: curPrincipal,
+		prompters:    prompters,
+	}
+}
+
+func (p *PulumiProvider) EnsureConfigured(ctx context.Context) error {
+	if err := p.prompters.EnsureSubscriptionLocation(ctx, p.env); err != nil {
+		return err
+	}
+
+	envVars := []string{
+		fmt.Sprintf("%s=%s", BackendUrlEnvVarName, p.backendUrl()),
+		// Read by the azure-native and azure providers
+		fmt.Sprintf("ARM_SUBSCRIPTION_ID=%s", p.env.GetSubscriptionId()),
+		fmt.Sprintf("ARM_LOCATION=%s", p.env.GetLocation()),
+	}
+
+	// Secrets of stacks in self-managed backends are encrypted with a passphrase, empty unless configured
+	if os.Getenv(ConfigPassphraseEnvVarName) == "" && os.Getenv("PULUMI_CONFIG_PASSPHRASE_FILE") == "" {
+		envVars = append(envVars, fmt.Sprintf("%s=%s", ConfigPassphraseEnvVarName, p.env.Getenv(ConfigPassphraseEnvVarName)))
+	}
+
+	p.cli.SetEnv(envVars)
+	return nil
+}
+
+// Selects the stack of the environment and sets its configuration from the parameters file
+func (p *PulumiProvider) Plan(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*DeploymentPlan, *DeploymentPlanningProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeploymentPlan, *DeploymentPlanningProgress]) {
+			if err := p.selectStack(ctx); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			parameters, err := p.loadParameters(ctx)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			config := map[string]string{}
+			templateParameters := map[string]InputParameter{}
+			for key, value := range parameters {
+				configValue, err := configValue(value)
+				if err != nil {
+					asyncContext.SetError(fmt.Errorf("invalid value for parameter '%s': %w", key, err))
+					return
+				}
+
+				config[key] = configValue
+				templateParameters[key] = InputParameter{Type: key, Value: value}
+			}
+
+			if err := p.cli.SetConfig(ctx, p.programPath(), p.stack(), config); err != nil {
+				asyncContext.SetError(fmt.Errorf("setting stack configuration: %w", err))
+				return
+			}
+
+			asyncContext.SetResult(&DeploymentPlan{
+				Deployment: Deployment{Parameters: templateParameters},
+				Details: PulumiDeploymentDetails{
+					ProgramPath: p.programPath(),
+					Stack:       p.stack(),
+				},
+			})
+		})
+}
+
+// Deploy the infrastructure of the program through pulumi up
+func (p *PulumiProvider) Deploy(
+	ctx context.Context,
+	plan *DeploymentPlan,
+) *async.InteractiveTaskWithProgress[*DeployResult, *DeployProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeployResult, *DeployProgress]) {
+			details, ok := plan.Details.(PulumiDeploymentDetails)
+			if !ok {
+				asyncContext.SetError(fmt.Errorf("unexpected deployment plan details %T", plan.Details))
+				return
+			}
+
+			// pulumi doesn't use the `p.console`, we must ensure no spinner is running before calling Up
+			p.console.StopSpinner(ctx, "", input.Step)
+			if runResult, err := p.cli.Up(ctx, details.ProgramPath, details.Stack); err != nil {
+				asyncContext.SetError(fmt.Errorf("pulumi up failed: %s, err: %w", runResult, err))
+				return
+			}
+
+			outputs, err := p.outputs(ctx)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			deployment := plan.Deployment
+			deployment.Outputs = outputs
+			asyncContext.SetResult(&DeployResult{Deployment: &deployment})
+		})
+}
+
+// Destroys the resources of the stack through pulumi destroy
+func (p *PulumiProvider) Destroy(
+	ctx context.Context,
+	options DestroyOptions,
+) *async.InteractiveTaskWithProgress[*DestroyResult, *DestroyProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DestroyResult, *DestroyProgress]) {
+			if err := p.selectStack(ctx); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			outputs, err := p.outputs(ctx)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			destroyArgs := []string{}
+			if options.Force() {
+				destroyArgs = append(destroyArgs, "--yes")
+			}
+
+			p.console.Message(ctx, "Deleting pulumi stack resources...")
+			// pulumi destroy asks for a confirmation unless forced, no spinner may run
+			p.console.StopSpinner(ctx, "", input.Step)
+			runResult, err := p.cli.Destroy(ctx, p.programPath(), p.stack(), destroyArgs...)
+			if err != nil {
+				asyncContext.SetError(fmt.Errorf("pulumi destroy failed: %s, err: %w", runResult, err))
+				return
+			}
+
+			asyncContext.SetResult(&DestroyResult{InvalidatedEnvKeys: maps.Keys(outputs)})
+		})
+}
+
+func (p *PulumiProvider) State(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*StateResult, *StateProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*StateResult, *StateProgress]) {
+			if err := p.selectStack(ctx); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			p.console.Message(ctx, "Retrieving pulumi state...")
+			outputs, err := p.outputs(ctx)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			resources, err := p.resources(ctx)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			state := State{Outputs: outputs}
+			for _, resource := range resources {
+				state.Resources = append(state.Resources, Resource{Id: resource.Id})
+			}
+
+			asyncContext.SetResult(&StateResult{State: &state})
+		})
+}
+
+// PreviewDeploy returns the changes of the resources pulumi preview reports for the stack
+func (p *PulumiProvider) PreviewDeploy(ctx context.Context, plan *DeploymentPlan) (*DeploymentPreview, error) {
+	details, ok := plan.Details.(PulumiDeploymentDetails)
+	if !ok {
+		return nil, fmt.Errorf("unexpected deployment plan details %T", plan.Details)
+	}
+
+	message := "Previewing changes of resources (pulumi preview)"
+	p.console.ShowSpinner(ctx, message, input.Step)
+	runResult, err := p.cli.Preview(ctx, details.ProgramPath, details.Stack)
+	p.console.StopSpinner(ctx, "", input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	var previewOutput pulumiPreviewOutput
+	if err := json.Unmarshal([]byte(runResult), &previewOutput); err != nil {
+		return nil, fmt.Errorf("reading pulumi preview: %w", err)
+	}
+
+	preview := &DeploymentPreview{Changes: []ResourceChange{}}
+	for _, step := range previewOutput.Steps {
+		state := step.NewState
+		if state == nil {
+			state = step.OldState
+		}
+
+		resourceType := pulumiResourceType(step.Urn)
+		if state != nil && state.Type != "" {
+			resourceType = state.Type
+		}
+
+		// The stack and the providers aren't Azure resources
+		if resourceType == pulumiStackType || strings.HasPrefix(resourceType, pulumiProviderTypePrefix) {
+			continue
+		}
+
+		change := ResourceChange{
+			ChangeType:        pulumiChangeType(step.Op),
+			ResourceType:      resourceType,
+			Name:              pulumiResourceName(step.Urn),
+			ChangedProperties: step.Diffs,
+		}
+		if state != nil {
+			change.ResourceId = state.Id
+		}
+
+		preview.Changes = append(preview.Changes, change)
+	}
+
+	// Resources are listed in a stable order, by type then name
+	sort.SliceStable(preview.Changes, func(i, j int) bool {
+		if preview.Changes[i].ResourceType != preview.Changes[j].ResourceType {
+			return preview.Changes[i].ResourceType < preview.Changes[j].ResourceType
+		}
+
+		return preview.Changes[i].Name < preview.Changes[j].Name
+	})
+
+	return preview, nil
+}
+
+// PreviewDestroy returns the Azure resources of the state of the stack, deleted by Destroy
+func (p *PulumiProvider) PreviewDestroy(ctx context.Context, options DestroyOptions) (*DestroyPreview, error) {
+	message := "Listing resources to delete"
+	p.console.ShowSpinner(ctx, message, input.Step)
+	resources, err := p.previewDestroy(ctx)
+	p.console.StopSpinner(ctx, "", input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DestroyPreview{
+		Resources:       resources,
+		RoleAssignments: []DestroyRoleAssignment{},
+		Locks:           []string{},
+		PurgeProtected:  []string{},
+	}, nil
+}
+
+func (p *PulumiProvider) previewDestroy(ctx context.Context) ([]DestroyResource, error) {
+	if err := p.selectStack(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.resources(ctx)
+}
+
+// Returns the Azure resources of the state of the stack, the resources with an Azure resource id
+func (p *PulumiProvider) resources(ctx context.Context) ([]DestroyResource, error) {
+	runResult, err := p.cli.StackExport(ctx, p.programPath(), p.stack())
+	if err != nil {
+		return nil, fmt.Errorf("exporting pulumi state: %w", err)
+	}
+
+	var export pulumiStackExport
+	if err := json.Unmarshal([]byte(runResult), &export); err != nil {
+		return nil, fmt.Errorf("reading pulumi state: %w", err)
+	}
+
+	resources := []DestroyResource{}
+	for _, resource := range export.Deployment.Resources {
+		if !resource.Custom || !strings.HasPrefix(strings.ToLower(resource.Id), "/subscriptions/") {
+			continue
+		}
+
+		resourceId, err := arm.ParseResourceID(resource.Id)
+		if err != nil {
+			log.Printf("ignoring resource %s with an invalid id: %v", resource.Urn, err)
+			continue
+		}
+
+		resources = append(resources, DestroyResource{
+			Id:            resource.Id,
+			Name:          resourceId.Name,
+			Type:          resourceId.ResourceType.String(),
+			ResourceGroup: resourceId.ResourceGroupName,
+		})
+	}
+
+	return resources, nil
+}
+
+// Returns the outputs of the stack, stored in the environment after each deployment
+func (p *PulumiProvider) outputs(ctx context.Context) (map[string]OutputParameter, error) {
+	runResult, err := p.cli.StackOutput(ctx, p.programPath(), p.stack())
+	if err != nil {
+		return nil, fmt.Errorf("reading pulumi stack outputs: %w", err)
+	}
+
+	var outputMap map[string]any
+	if err := json.Unmarshal([]byte(runResult), &outputMap); err != nil {
+		return nil, fmt.Errorf("reading pulumi stack outputs: %w", err)
+	}
+
+	return convertOutputs(outputMap), nil
+}
+
+// convertOutputs converts the outputs of a stack to the canonical format shared by all provider implementations.
+func convertOutputs(outputMap map[string]any) map[string]OutputParameter {
+	outputParameters := make(map[string]OutputParameter)
+	for key, value := range outputMap {
+		var parameterType ParameterType
+		switch value.(type) {
+		case nil:
+			// omit null
+			continue
+		case bool:
+			parameterType = ParameterTypeBoolean
+		case float64:
+			parameterType = ParameterTypeNumber
+		case []any:
+			parameterType = ParameterTypeArray
+		case map[string]any:
+			parameterType = ParameterTypeObject
+		default:
+			parameterType = ParameterTypeString
+		}
+
+		outputParameters[key] = OutputParameter{Type: parameterType, Value: value}
+	}
+
+	return outputParameters
+}
+
+// Returns the value of the parameter as a stack configuration value, complex values are encoded as JSON
+func configValue(value any) (string, error) {
+	if value, ok := value.(string); ok {
+		return value, nil
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
+func (p *PulumiProvider) selectStack(ctx context.Context) error {
+	if err := p.cli.SelectStack(ctx, p.programPath(), p.stack()); err != nil {
+		return fmt.Errorf("selecting pulumi stack '%s': %w", p.stack(), err)
+	}
+
+	return nil
+}
+
+// Reads the parameters file of the module, after replacing environment variable references in the contents
+func (p *PulumiProvider) loadParameters(ctx context.Context) (map[string]any, error) {
+	parameters := map[string]any{}
+
+	parametersFilePath := p.parametersFilePath()
+	log.Printf("Reading parameters file from: %s", parametersFilePath)
+	parametersBytes, err := os.ReadFile(parametersFilePath)
+	if os.IsNotExist(err) {
+		return parameters, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading parameters file: %w", err)
+	}
+
+	principalId, err := p.curPrincipal.CurrentPrincipalId(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching current principal id: %w", err)
+	}
+
+	replaced, err := envsubst.Eval(string(parametersBytes), func(name string) string {
+		if name == environment.PrincipalIdEnvVarName {
+			return principalId
+		}
+
+		return p.env.Getenv(name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("substituting parameters file: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(replaced), &parameters); err != nil {
+		return nil, fmt.Errorf("error unmarshalling parameters: %w", err)
+	}
+
+	return parameters, nil
+}
+
+// Gets the folder path of the Pulumi program, containing Pulumi.yaml
+func (p *PulumiProvider) programPath() string {
+	infraPath := p.options.Path
+	if strings.TrimSpace(infraPath) == "" {
+		infraPath = "infra"
+	}
+
+	return filepath.Join(p.projectPath, infraPath)
+}
+
+// Gets the path to the parameters file of the module, ex. infra/main.parameters.json
+func (p *PulumiProvider) parametersFilePath() string {
+	return filepath.Join(p.programPath(), fmt.Sprintf("%s.parameters.json", p.options.Module))
+}
+
+// Gets the name of the stack of the environment
+func (p *PulumiProvider) stack() string {
+	if stack := p.env.Getenv(StackEnvVarName); stack != "" {
+		return stack
+	}
+
+	return p.env.GetEnvName()
+}
+
+// Gets the URL of the backend storing the state of the stack
+func (p *PulumiProvider) backendUrl() string {
+	if backendUrl := os.Getenv(BackendUrlEnvVarName); backendUrl != "" {
+		return backendUrl
+	}
+
+	if backendUrl := p.env.Getenv(BackendUrlEnvVarName); backendUrl != "" {
+		return backendUrl
+	}
+
+	statePath := filepath.Join(p.projectPath, ".azure", p.env.GetEnvName(), p.options.Path, ".pulumi")
+	return "file://" + filepath.ToSlash(statePath)
+}
+
+func init() {
+	err := RegisterProvider(
+		Pulumi,
+		func(
+			ctx context.Context,
+			env *environment.Environment,
+			projectPath string,
+			options Options,
+			console input.Console,
+			_ azcli.AzCli,
+			commandRunner exec.CommandRunner,
+			prompters Prompters,
+			curPrincipal CurrentPrincipalIdProvider,
+			_ *alpha.FeatureManager,
+		) (Provider, error) {
+			return NewPulumiProvider(ctx, env, projectPath, options, console, commandRunner, curPrincipal, prompters), nil
+		},
+	)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+const (
+	pulumiStackType          = "pulumi:pulumi:Stack"
+	pulumiProviderTypePrefix = "pulumi:providers:"
+)
+
+// Maps the operation of a step of pulumi preview to the change of the resource
+func pulumiChangeType(op string) ChangeType {
+	switch op {
+	case "create":
+		return ChangeTypeCreate
+	case "update", "replace", "create-replacement", "delete-replaced":
+		return ChangeTypeModify
+	case "delete":
+		return ChangeTypeDelete
+	case "same":
+		return ChangeTypeNoChange
+	case "read", "refresh", "import":
+		return ChangeTypeIgnore
+	default:
+		return ChangeTypeUnsupported
+	}
+}
+
+// Returns the type of the resource of the URN, ex. azure-native:web:WebApp of
+// urn:pulumi:dev::app::azure-native:web:WebApp::web
+func pulumiResourceType(urn string) string {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 4 {
+		return ""
+	}
+
+	// The type is qualified with the types of the parent resources, ex. parentType$type
+	types := strings.Split(parts[2], "$")
+	return types[len(types)-1]
+}
+
+// Returns the name of the resource of the URN, ex. web of urn:pulumi:dev::app::azure-native:web:WebApp::web
+func pulumiResourceName(urn string) string {
+	parts := strings.Split(urn, "::")
+	return parts[len(parts)-1]
+}
+
+// pulumiPreviewOutput is a model type for the output of `pulumi preview --json`
+type pulumiPreviewOutput struct {
+	Steps []pulumiPreviewStep `json:"steps"`
+}
+
+// pulumiPreviewStep is a model type for a step of a pulumi preview, the operation on a resource
+type pulumiPreviewStep struct {
+	Op       string               `json:"op"`
+	Urn      string               `json:"urn"`
+	OldState *pulumiResourceState `json:"oldState,omitempty"`
+	NewState *pulumiResourceState `json:"newState,omitempty"`
+	// The properties the step changes
+	Diffs []string `json:"diffs,omitempty"`
+}
+
+// pulumiResourceState is a model type for the state of a resource, in a preview step or in a stack export
+type pulumiResourceState struct {
+	Urn    string `json:"urn"`
+	Type   string `json:"type"`
+	Id     string `json:"id"`
+	Custom bool   `json:"custom"`
+}
+
+// pulumiStackExport is a model type for the output of `pulumi stack export`
+type pulumiStackExport struct {
+	Deployment struct {
+		Resources []pulumiResourceState `json:"resources"`
+	} `json:"deployment"`
+}
@@ -0,0 +1,242 @@
+package pulumi
+
+import (
+	"context"
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/stretchr/testify/require"
+)
+
+const webAppId = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env/providers/" +
+	"Microsoft.Web/sites/app-123"
+
+func TestPulumiPlan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+
+	var configArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "config set-all")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		configArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	deploymentPlan, err := infraProvider.Plan(*mockContext.Context).Await()
+	require.NoError(t, err)
+
+	require.Equal(t, "test-env", deploymentPlan.Deployment.Parameters["environmentName"].Value)
+	require.Equal(t, PulumiDeploymentDetails{
+		ProgramPath: infraProvider.programPath(),
+		Stack:       "test-env",
+	}, deploymentPlan.Details)
+
+	require.Contains(t, configArgs, "environmentName=test-env")
+	require.Contains(t, configArgs, "principalId=11111111-1111-1111-1111-111111111111")
+	require.Contains(t, configArgs, `tags={"azd-env-name":"test-env"}`)
+}
+
+func TestPulumiDeploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+
+	ran := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "up --yes")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = true
+		require.True(t, args.Interactive)
+		require.Contains(t, args.Env, "ARM_SUBSCRIPTION_ID=00000000-0000-0000-0000-000000000000")
+		require.Contains(t, args.Env, "ARM_LOCATION=westus2")
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	deployResult, err := infraProvider.Deploy(*mockContext.Context, &DeploymentPlan{
+		Details: PulumiDeploymentDetails{ProgramPath: infraProvider.programPath(), Stack: "test-env"},
+	}).Await()
+	require.NoError(t, err)
+	require.True(t, ran)
+
+	require.Equal(t, map[string]OutputParameter{
+		"RG_NAME":  {Type: ParameterTypeString, Value: "rg-test-env"},
+		"PORT":     {Type: ParameterTypeNumber, Value: float64(8080)},
+		"ENABLED":  {Type: ParameterTypeBoolean, Value: true},
+		"HOSTS":    {Type: ParameterTypeArray, Value: []any{"a", "b"}},
+		"SETTINGS": {Type: ParameterTypeObject, Value: map[string]any{"key": "value"}},
+	}, deployResult.Deployment.Outputs)
+}
+
+func TestPulumiDestroy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+
+	var destroyArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "destroy")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		destroyArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	destroyResult, err := infraProvider.Destroy(*mockContext.Context, NewDestroyOptions(true, false)).Await()
+	require.NoError(t, err)
+
+	require.Contains(t, destroyArgs, "--yes")
+	require.ElementsMatch(t, []string{"RG_NAME", "PORT", "ENABLED", "HOSTS", "SETTINGS"}, destroyResult.InvalidatedEnvKeys)
+}
+
+func TestPulumiState(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	stateResult, err := infraProvider.State(*mockContext.Context).Await()
+	require.NoError(t, err)
+
+	require.Equal(t, "rg-test-env", stateResult.State.Outputs["RG_NAME"].Value)
+	require.Equal(t, []Resource{
+		{Id: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env"},
+		{Id: webAppId},
+	}, stateResult.State.Resources)
+}
+
+//go:embed testdata/pulumi_preview_mock.json
+var pulumiPreviewMockOutput string
+
+func TestPulumiPreviewDeploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "preview --json")
+	}).Respond(exec.NewRunResult(0, pulumiPreviewMockOutput, ""))
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	preview, err := infraProvider.PreviewDeploy(*mockContext.Context, &DeploymentPlan{
+		Details: PulumiDeploymentDetails{ProgramPath: infraProvider.programPath(), Stack: "test-env"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []ResourceChange{
+		{
+			ChangeType:   ChangeTypeCreate,
+			ResourceType: "azure-native:resources:ResourceGroup",
+			Name:         "rg",
+		},
+		{
+			ChangeType:        ChangeTypeModify,
+			ResourceId:        webAppId,
+			ResourceType:      "azure-native:web:WebApp",
+			Name:              "web",
+			ChangedProperties: []string{"siteConfig"},
+		},
+	}, preview.Changes)
+}
+
+func TestPulumiPreviewDestroy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+
+	destroyed := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "destroy")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		destroyed = true
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	preview, err := infraProvider.PreviewDestroy(*mockContext.Context, NewDestroyOptions(false, false))
+	require.NoError(t, err)
+	require.False(t, destroyed)
+
+	require.Equal(t, []DestroyResource{
+		{
+			Id:            "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env",
+			Name:          "rg-test-env",
+			Type:          "Microsoft.Resources/resourceGroups",
+			ResourceGroup: "rg-test-env",
+		},
+		{
+			Id:            webAppId,
+			Name:          "app-123",
+			Type:          "Microsoft.Web/sites",
+			ResourceGroup: "rg-test-env",
+		},
+	}, preview.Resources)
+}
+
+func Test_pulumiResourceType(t *testing.T) {
+	require.Equal(t, "azure-native:web:WebApp", pulumiResourceType("urn:pulumi:dev::app::azure-native:web:WebApp::web"))
+	require.Equal(
+		t,
+		"azure-native:web:WebAppSlot",
+		pulumiResourceType("urn:pulumi:dev::app::my:component:App$azure-native:web:WebAppSlot::staging"),
+	)
+	require.Equal(t, "", pulumiResourceType("invalid"))
+}
+
+func createPulumiProvider(t *testing.T, mockContext *mocks.MockContext) *PulumiProvider {
+	env := environment.EphemeralWithValues("test-env", map[string]string{
+		"AZURE_LOCATION":        "westus2",
+		"AZURE_SUBSCRIPTION_ID": "00000000-0000-0000-0000-000000000000",
+	})
+
+	provider := NewPulumiProvider(
+		*mockContext.Context,
+		env,
+		"testdata",
+		Options{},
+		mockContext.Console,
+		mockContext.CommandRunner,
+		&mockCurrentPrincipal{},
+		Prompters{
+			Location: func(_ context.Context, _, _ string, _ func(loc account.Location) bool) (location string, err error) {
+				return "westus2", nil
+			},
+			Subscription: func(_ context.Context, _ string) (subscriptionId string, err error) {
+				return "00000000-0000-0000-0000-000000000000", nil
+			},
+			EnsureSubscriptionLocation: func(ctx context.Context, env *environment.Environment) error {
+				return nil
+			},
+		},
+	)
+	require.NoError(t, provider.EnsureConfigured(*mockContext.Context))
+
+	return provider
+}
+
+//go:embed testdata/pulumi_export_mock.json
+var pulumiExportMockOutput string
+
+func prepareStackMocks(commandRunner *mockexec.MockCommandRunner) {
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack select")
+	}).Respond(exec.NewRunResult(0, "", ""))
+
+	output := `{"RG_NAME":"rg-test-env","PORT":8080,"ENABLED":true,"HOSTS":["a","b"],"SETTINGS":{"key":"value"},"EMPTY":null}`
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack output")
+	}).Respond(exec.NewRunResult(0, output, ""))
+
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack export")
+	}).Respond(exec.NewRunResult(0, pulumiExportMockOutput, ""))
+}
+
+type mockCurrentPrincipal struct{}
+
+func (m *mockCurrentPrincipal) CurrentPrincipalId(_ context.Context) (string, error) {
+	return "11111111-1111-1111-1111-111111111111", nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStateBackendNotSupported is returned by the providers that don't store the state of the infrastructure themselves,
+// ex. bicep reads the state from the deployments in Azure
+var ErrStateBackendNotSupported = errors.New("the provisioning provider doesn't store an infrastructure state")
+
+// ErrStateNotLockable is returned when unlocking a state stored by a backend without locks, ex. local state files
+var ErrStateNotLockable = errors.New("the state backend doesn't lock the state")
+
+// The kinds of state backends
+const (
+	StateBackendLocal   = "local"
+	StateBackendAzureRm = "azurerm"
+)
+
+// StateBackendInfo describes where the state of the infrastructure of the environment is stored
+type StateBackendInfo struct {
+	// The kind of backend, ex. local or azurerm
+	Kind string `json:"kind"`
+	// The location of the state, the path of local state files or the URL of the blob of azurerm backends
+	Location string `json:"location"`
+	// The addresses of the resources tracked by the state
+	Resources []string `json:"resources"`
+}
+
+// StateBackend is implemented by the providers storing the state of the infrastructure, for `azd env state`
+type StateBackend interface {
+	// StateBackendInfo describes the backend and the resources tracked by the state of the environment
+	StateBackendInfo(ctx context.Context) (*StateBackendInfo, error)
+	// PullState returns the content of the state of the environment
+	PullState(ctx context.Context) ([]byte, error)
+	// UnlockState removes the lock of the state held by an interrupted operation. ErrStateNotLockable is returned when the
+	// backend doesn't lock the state.
+	UnlockState(ctx context.Context, lockId string) error
+}
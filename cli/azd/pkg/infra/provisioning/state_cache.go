@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The name of the file within the environment directory that stores the last known deployment state
+const StateCacheFileName = ".state-cache.json"
+
+// CachedState is the last known state of the environment infrastructure
+type CachedState struct {
+	// When the state was last read from Azure
+	UpdatedOn time.Time  `json:"updatedOn"`
+	Resources []Resource `json:"resources"`
+	// The ids of the resources hosting each service, keyed by service name
+	ServiceResources map[string][]string `json:"serviceResources,omitempty"`
+}
+
+// StateCache persists the last known deployment state of an environment so commands can display it without
+// querying Azure. The cache is replaced whenever the state is read and cleared when the infrastructure is
+// provisioned or destroyed.
+type StateCache struct {
+	path string
+}
+
+// Creates the deployment state cache of the environment. Environments that aren't persisted are never cached.
+func NewStateCache(env *environment.Environment) *StateCache {
+	if env == nil || env.Root == "" {
+		return &StateCache{}
+	}
+
+	return &StateCache{
+		path: filepath.Join(env.Root, StateCacheFileName),
+	}
+}
+
+// Gets the cached deployment state when available
+func (c *StateCache) Get() (*CachedState, bool) {
+	if c.path == "" {
+		return nil, false
+	}
+
+	contents, err := os.ReadFile(c.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed reading deployment state cache: %v\n", err)
+		}
+
+		return nil, false
+	}
+
+	var state CachedState
+	if err := json.Unmarshal(contents, &state); err != nil {
+		log.Printf("failed parsing deployment state cache: %v\n", err)
+		return nil, false
+	}
+
+	return &state, true
+}
+
+// Replaces the cached deployment state
+func (c *StateCache) Set(state *CachedState) error {
+	if c.path == "" {
+		return nil
+	}
+
+	if state.UpdatedOn.IsZero() {
+		state.UpdatedOn = time.Now().UTC()
+	}
+
+	contents, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(c.path, contents, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing deployment state cache: %w", err)
+	}
+
+	return nil
+}
+
+// Records the resources of the state read from Azure. Service resources are discarded since they may have changed.
+// Outputs aren't cached since they're already saved to the environment.
+func (c *StateCache) SetState(state *State) error {
+	return c.Set(&CachedState{
+		Resources: state.Resources,
+	})
+}
+
+// Removes the cached deployment state
+func (c *StateCache) Invalidate() error {
+	if c.path == "" {
+		return nil
+	}
+
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing deployment state cache: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StateCache(t *testing.T) {
+	t.Run("SetAndInvalidate", func(t *testing.T) {
+		env := environment.EphemeralWithValues("dev", nil)
+		env.Root = t.TempDir()
+		cache := NewStateCache(env)
+
+		_, has := cache.Get()
+		require.False(t, has)
+
+		require.NoError(t, cache.SetState(&State{
+			Outputs: map[string]OutputParameter{
+				"WEBSITE_URL": {Type: ParameterTypeString, Value: "https://example.com"},
+			},
+			Resources: []Resource{{Id: "RESOURCE_ID"}},
+		}))
+
+		cached, has := cache.Get()
+		require.True(t, has)
+		require.False(t, cached.UpdatedOn.IsZero())
+		require.Equal(t, []Resource{{Id: "RESOURCE_ID"}}, cached.Resources)
+
+		require.NoError(t, cache.Invalidate())
+		_, has = cache.Get()
+		require.False(t, has)
+
+		// Invalidating a missing cache is a no-op
+		require.NoError(t, cache.Invalidate())
+	})
+
+	t.Run("EphemeralEnvironment", func(t *testing.T) {
+		cache := NewStateCache(environment.EphemeralWithValues("dev", nil))
+
+		require.NoError(t, cache.SetState(&State{}))
+		_, has := cache.Get()
+		require.False(t, has)
+	})
+}
+
+func Test_FingerprintCache(t *testing.T) {
+	t.Run("SetAndInvalidate", func(t *testing.T) {
+		env := environment.EphemeralWithValues("dev", nil)
+		env.Root = t.TempDir()
+		cache := NewFingerprintCache(env)
+
+		_, has := cache.Get()
+		require.False(t, has)
+
+		require.NoError(t, cache.Set("FINGERPRINT"))
+
+		fingerprint, has := cache.Get()
+		require.True(t, has)
+		require.Equal(t, "FINGERPRINT", fingerprint.Fingerprint)
+		require.False(t, fingerprint.ProvisionedOn.IsZero())
+
+		require.NoError(t, cache.Invalidate())
+		_, has = cache.Get()
+		require.False(t, has)
+	})
+
+	t.Run("EphemeralEnvironment", func(t *testing.T) {
+		cache := NewFingerprintCache(environment.EphemeralWithValues("dev", nil))
+
+		require.NoError(t, cache.Set("FINGERPRINT"))
+		_, has := cache.Get()
+		require.False(t, has)
+	})
+}
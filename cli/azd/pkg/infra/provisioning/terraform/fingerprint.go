@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// The files of a module that change the infrastructure it deploys
+var fingerprintFileSuffixes = []string{".tf", ".tf.json", ".tfvars", ".tfvars.json", ".terraform.lock.hcl"}
+
+// Fingerprint hashes the configuration files of the module, including its local modules, and the values of its
+// variables. Remote modules and providers are pinned by the lock file of the module.
+func (t *TerraformProvider) Fingerprint(ctx context.Context, plan *DeploymentPlan) (string, error) {
+	details, ok := plan.Details.(TerraformDeploymentDetails)
+	if !ok {
+		return "", fmt.Errorf("unexpected deployment plan details %T", plan.Details)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(t.Name()))
+	hash.Write([]byte{0})
+
+	modulePath := t.modulePath()
+	err := filepath.WalkDir(modulePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// The working directory of terraform holds the downloaded modules and providers
+		if entry.IsDir() {
+			if entry.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !isFingerprintFile(entry.Name()) {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(modulePath, path)
+		if err != nil {
+			return err
+		}
+
+		hash.Write([]byte(filepath.ToSlash(relativePath)))
+		hash.Write([]byte{0})
+		hash.Write(contents)
+		hash.Write([]byte{0})
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("reading module files: %w", err)
+	}
+
+	for _, path := range []string{details.ParameterFilePath, t.backendConfigFilePath()} {
+		contents, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+		}
+
+		hash.Write(contents)
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func isFingerprintFile(name string) bool {
+	for _, suffix := range fingerprintFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
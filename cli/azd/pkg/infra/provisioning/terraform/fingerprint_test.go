@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformFingerprint(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	infraProvider := createTerraformProvider(mockContext)
+	infraProvider.projectPath = t.TempDir()
+
+	writeFile := func(path string, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+	}
+
+	modulePath := infraProvider.modulePath()
+	writeFile(filepath.Join(modulePath, "main.tf"), `resource "azurerm_resource_group" "rg" {}`)
+	writeFile(filepath.Join(modulePath, "modules", "app", "main.tf"), `resource "azurerm_linux_web_app" "app" {}`)
+	writeFile(infraProvider.parametersFilePath(), `{"location": "westus2"}`)
+
+	plan := &DeploymentPlan{
+		Details: TerraformDeploymentDetails{
+			ParameterFilePath: infraProvider.parametersFilePath(),
+			PlanFilePath:      infraProvider.planFilePath(),
+		},
+	}
+
+	fingerprint := func() string {
+		value, err := infraProvider.Fingerprint(*mockContext.Context, plan)
+		require.NoError(t, err)
+
+		return value
+	}
+
+	expected := fingerprint()
+	require.NotEmpty(t, expected)
+
+	// The working directory of terraform and the files that aren't configuration don't change the fingerprint
+	writeFile(filepath.Join(modulePath, ".terraform", "modules", "modules.json"), `{}`)
+	writeFile(filepath.Join(modulePath, "README.md"), "# Infrastructure")
+	require.Equal(t, expected, fingerprint())
+
+	writeFile(filepath.Join(modulePath, "modules", "app", "main.tf"), `resource "azurerm_windows_web_app" "app" {}`)
+	changed := fingerprint()
+	require.NotEqual(t, expected, changed)
+
+	writeFile(infraProvider.parametersFilePath(), `{"location": "eastus2"}`)
+	require.NotEqual(t, changed, fingerprint())
+}
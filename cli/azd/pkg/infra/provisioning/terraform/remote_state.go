@@ -0,0 +1,336 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// The environment values naming the Azure Storage resources of the remote state, also read by the backend config
+// templates and set as variables of the CI pipelines by `azd pipeline config`
+const (
+	RemoteStateResourceGroupEnvVarName  = "RS_RESOURCE_GROUP"
+	RemoteStateStorageAccountEnvVarName = "RS_STORAGE_ACCOUNT"
+	RemoteStateContainerEnvVarName      = "RS_CONTAINER_NAME"
+)
+
+const (
+	defaultRemoteStateContainer = "tfstate"
+	// The tag of the resources created for the remote state, not azd-env-name as they aren't part of the infrastructure
+	remoteStateTagKey = "azd-tfstate-env-name"
+	// The file declaring the azurerm backend written to the module when its templates don't declare a backend
+	remoteStateBackendFileName = "azd_backend.tf"
+)
+
+var backendBlockRegex = regexp.MustCompile(`backend\s+"([^"]+)"`)
+
+// remoteState names the blob storing the terraform state of the environment
+type remoteState struct {
+	ResourceGroup  string `json:"resource_group_name"`
+	StorageAccount string `json:"storage_account_name"`
+	Container      string `json:"container_name"`
+	Key            string `json:"key"`
+}
+
+// Url returns the URL of the blob of the state
+func (s *remoteState) Url() string {
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", s.StorageAccount, s.Container, s.Key)
+}
+
+// remoteStateNames returns the names of the remote state resources of the environment, the values recorded in the
+// environment take precedence over infra.remoteState, which takes precedence over the defaults
+func (t *TerraformProvider) remoteStateNames() *remoteState {
+	envName := t.env.GetEnvName()
+	options := RemoteStateOptions{}
+	if t.options.RemoteState != nil {
+		options = *t.options.RemoteState
+	}
+
+	resolve := func(envVarName string, configured string, defaultValue string) string {
+		if value := t.env.Getenv(envVarName); value != "" {
+			return value
+		}
+		if configured != "" {
+			return configured
+		}
+		return defaultValue
+	}
+
+	return &remoteState{
+		ResourceGroup: resolve(
+			RemoteStateResourceGroupEnvVarName, options.ResourceGroup, fmt.Sprintf("rg-%s-tfstate", envName)),
+		StorageAccount: resolve(
+			RemoteStateStorageAccountEnvVarName,
+			options.StorageAccount,
+			defaultStorageAccountName(t.env.GetSubscriptionId(), envName),
+		),
+		Container: resolve(RemoteStateContainerEnvVarName, options.Container, defaultRemoteStateContainer),
+		Key:       fmt.Sprintf("azd/%s.tfstate", envName),
+	}
+}
+
+// defaultStorageAccountName returns a storage account name unique to the subscription and environment, storage account
+// names are globally unique, up to 24 lower case letters and digits
+func defaultStorageAccountName(subscriptionId string, envName string) string {
+	hash := sha256.Sum256([]byte(subscriptionId + "/" + envName))
+	return "sttfstate" + hex.EncodeToString(hash[:])[:12]
+}
+
+// ensureRemoteState creates the resource group, storage account and container of the remote state unless the
+// environment already recorded them, and records their names in the environment
+func (t *TerraformProvider) ensureRemoteState(ctx context.Context) (*remoteState, error) {
+	state := t.remoteStateNames()
+
+	recorded := t.env.Getenv(RemoteStateResourceGroupEnvVarName) != "" &&
+		t.env.Getenv(RemoteStateStorageAccountEnvVarName) != "" &&
+		t.env.Getenv(RemoteStateContainerEnvVarName) != ""
+	if recorded {
+		return state, nil
+	}
+
+	t.console.Message(ctx, fmt.Sprintf(
+		"Creating the terraform remote state in storage account %s...", output.WithHighLightFormat(state.StorageAccount)))
+
+	subscriptionId := t.env.GetSubscriptionId()
+	tags := map[string]*string{remoteStateTagKey: to.Ptr(t.env.GetEnvName())}
+
+	err := t.azCli.CreateOrUpdateResourceGroup(ctx, subscriptionId, state.ResourceGroup, t.env.GetLocation(), tags)
+	if err != nil {
+		return nil, fmt.Errorf("creating remote state resource group: %w", err)
+	}
+
+	err = t.azCli.CreateOrUpdateStorageAccount(
+		ctx, subscriptionId, state.ResourceGroup, state.StorageAccount, t.env.GetLocation(), tags)
+	if err != nil {
+		return nil, fmt.Errorf("creating remote state storage account: %w", err)
+	}
+
+	err = t.azCli.CreateOrUpdateBlobContainer(
+		ctx, subscriptionId, state.ResourceGroup, state.StorageAccount, state.Container)
+	if err != nil {
+		return nil, fmt.Errorf("creating remote state container: %w", err)
+	}
+
+	t.env.DotenvSet(RemoteStateResourceGroupEnvVarName, state.ResourceGroup)
+	t.env.DotenvSet(RemoteStateStorageAccountEnvVarName, state.StorageAccount)
+	t.env.DotenvSet(RemoteStateContainerEnvVarName, state.Container)
+	if err := t.env.Save(); err != nil {
+		return nil, fmt.Errorf("saving remote state: %w", err)
+	}
+
+	return state, nil
+}
+
+// ensureBackendBlock writes the declaration of the azurerm backend to the module when its templates don't declare a
+// backend. Backends other than azurerm can't store the remote state.
+func (t *TerraformProvider) ensureBackendBlock() error {
+	modulePath := t.modulePath()
+	files, err := os.ReadDir(modulePath)
+	if err != nil {
+		return fmt.Errorf("reading .tf files: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".tf" {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(modulePath, file.Name()))
+		if err != nil {
+			return fmt.Errorf("reading .tf files: %w", err)
+		}
+
+		if match := backendBlockRegex.FindStringSubmatch(string(content)); match != nil {
+			if match[1] != StateBackendAzureRm {
+				return fmt.Errorf(
+					"infra.remoteState requires the azurerm backend, %s declares the %s backend", file.Name(), match[1])
+			}
+
+			return nil
+		}
+	}
+
+	backend := "# Generated by azd for infra.remoteState, the backend is configured for each environment\n" +
+		"terraform {\n  backend \"azurerm\" {}\n}\n"
+	backendPath := filepath.Join(modulePath, remoteStateBackendFileName)
+	log.Printf("Writing terraform backend to: %s", backendPath)
+
+	if err := os.WriteFile(backendPath, []byte(backend), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing terraform backend: %w", err)
+	}
+
+	return nil
+}
+
+// writeRemoteStateConfig writes the backend config of the remote state of the environment
+func (t *TerraformProvider) writeRemoteStateConfig(state *remoteState) error {
+	config, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.backendConfigFilePath()), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating directory structure: %w", err)
+	}
+
+	log.Printf("Writing backend config file to: %s", t.backendConfigFilePath())
+	return os.WriteFile(t.backendConfigFilePath(), config, 0600)
+}
+
+// migrateLocalState pushes the local state of the environment to the remote state the first time the remote state is
+// used, the local state file is kept renamed with a .migrated extension
+func (t *TerraformProvider) migrateLocalState(ctx context.Context) error {
+	localStatePath := t.localStateFilePath()
+	if _, err := os.Stat(localStatePath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	t.console.Message(ctx, "Migrating the local terraform state to the remote state...")
+	if res, err := t.cli.StatePush(ctx, t.modulePath(), localStatePath); err != nil {
+		return fmt.Errorf("migrating local state: %s, err: %w", res, err)
+	}
+
+	return os.Rename(localStatePath, localStatePath+".migrated")
+}
+
+// ensureInitialized initializes the module for the remote backend when the environment wasn't provisioned before
+func (t *TerraformProvider) ensureInitialized(ctx context.Context) error {
+	if _, err := os.Stat(t.dataDirPath()); err == nil {
+		return nil
+	}
+
+	if res, err := t.init(ctx, true); err != nil {
+		return fmt.Errorf("terraform init failed: %s, err: %w", res, err)
+	}
+
+	return nil
+}
+
+// readBackendConfig reads the backend config of the environment, nil when the config doesn't name the blob of the state
+func (t *TerraformProvider) readBackendConfig() *remoteState {
+	content, err := os.ReadFile(t.backendConfigFilePath())
+	if err != nil {
+		return nil
+	}
+
+	var state remoteState
+	if err := json.Unmarshal(content, &state); err != nil || state.StorageAccount == "" {
+		return nil
+	}
+
+	return &state
+}
+
+// StateBackendInfo describes the local state file or remote backend of the environment and the resources of its state
+func (t *TerraformProvider) StateBackendInfo(ctx context.Context) (*StateBackendInfo, error) {
+	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return nil, fmt.Errorf("reading backend config: %w", err)
+	}
+
+	info := &StateBackendInfo{
+		Kind:      StateBackendLocal,
+		Location:  t.localStateFilePath(),
+		Resources: []string{},
+	}
+
+	args := []string{}
+	if isRemoteBackendConfig {
+		if err := t.ensureInitialized(ctx); err != nil {
+			return nil, err
+		}
+
+		info.Kind = StateBackendAzureRm
+		info.Location = ""
+		if state := t.readBackendConfig(); state != nil {
+			info.Location = state.Url()
+		}
+	} else {
+		if _, err := os.Stat(info.Location); errors.Is(err, os.ErrNotExist) {
+			return info, nil
+		}
+
+		args = append(args, fmt.Sprintf("-state=%s", info.Location))
+	}
+
+	res, err := t.cli.StateList(ctx, t.modulePath(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing state resources: %w", err)
+	}
+
+	for _, line := range strings.Split(res, "\n") {
+		if address := strings.TrimSpace(line); address != "" {
+			info.Resources = append(info.Resources, address)
+		}
+	}
+
+	return info, nil
+}
+
+// PullState returns the content of the local state file or of the remote state of the environment
+func (t *TerraformProvider) PullState(ctx context.Context) ([]byte, error) {
+	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return nil, fmt.Errorf("reading backend config: %w", err)
+	}
+
+	if !isRemoteBackendConfig {
+		content, err := os.ReadFile(t.localStateFilePath())
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no terraform state found for environment %s", t.env.GetEnvName())
+		}
+
+		return content, err
+	}
+
+	if err := t.ensureInitialized(ctx); err != nil {
+		return nil, err
+	}
+
+	res, err := t.cli.StatePull(ctx, t.modulePath())
+	if err != nil {
+		return nil, fmt.Errorf("pulling state: %w", err)
+	}
+
+	return []byte(res), nil
+}
+
+// UnlockState removes the lock of the remote state, the local state files aren't locked by azd
+func (t *TerraformProvider) UnlockState(ctx context.Context, lockId string) error {
+	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return fmt.Errorf("reading backend config: %w", err)
+	}
+
+	if !isRemoteBackendConfig {
+		return ErrStateNotLockable
+	}
+
+	if err := t.ensureInitialized(ctx); err != nil {
+		return err
+	}
+
+	if _, err := t.cli.ForceUnlock(ctx, t.modulePath(), lockId); err != nil {
+		return fmt.Errorf("unlocking state: %w", err)
+	}
+
+	return nil
+}
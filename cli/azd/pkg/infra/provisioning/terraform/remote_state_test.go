@@ -0,0 +1,167 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformRemoteState(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+
+	created := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "/resourcegroups/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		created = append(created, request.URL.Path)
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"id": request.URL.Path})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "Microsoft.Storage")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		created = append(created, request.URL.Path)
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"id": request.URL.Path})
+	})
+
+	ran := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && (strings.Contains(command, "init") || strings.Contains(command, "state push"))
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = append(ran, strings.Join(args.Args[1:], " "))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createRemoteStateProvider(t, mockContext)
+	require.NoError(t, os.MkdirAll(filepath.Dir(infraProvider.localStateFilePath()), 0755))
+	require.NoError(t, os.WriteFile(infraProvider.localStateFilePath(), []byte("{}"), 0600))
+
+	_, err := infraProvider.init(*mockContext.Context, true)
+	require.NoError(t, err)
+
+	accountName := defaultStorageAccountName("00000000-0000-0000-0000-000000000000", "test-env")
+	require.Len(t, accountName, 21)
+	require.Equal(t, "rg-test-env-tfstate", infraProvider.env.Getenv(RemoteStateResourceGroupEnvVarName))
+	require.Equal(t, accountName, infraProvider.env.Getenv(RemoteStateStorageAccountEnvVarName))
+	require.Equal(t, "tfstate", infraProvider.env.Getenv(RemoteStateContainerEnvVarName))
+	require.Len(t, created, 3)
+	require.True(t, strings.HasSuffix(created[2], "/blobServices/default/containers/tfstate"))
+
+	config, err := os.ReadFile(infraProvider.backendConfigFilePath())
+	require.NoError(t, err)
+	var state remoteState
+	require.NoError(t, json.Unmarshal(config, &state))
+	require.Equal(t, remoteState{
+		ResourceGroup:  "rg-test-env-tfstate",
+		StorageAccount: accountName,
+		Container:      "tfstate",
+		Key:            "azd/test-env.tfstate",
+	}, state)
+
+	backend, err := os.ReadFile(filepath.Join(infraProvider.modulePath(), remoteStateBackendFileName))
+	require.NoError(t, err)
+	require.Contains(t, string(backend), `backend "azurerm" {}`)
+
+	// The local state is migrated to the remote state
+	require.Equal(t, []string{
+		"init -upgrade --backend-config=" + infraProvider.backendConfigFilePath(),
+		"state push " + infraProvider.localStateFilePath(),
+	}, ran)
+	require.NoFileExists(t, infraProvider.localStateFilePath())
+	require.FileExists(t, infraProvider.localStateFilePath()+".migrated")
+
+	// The recorded resources aren't created again
+	created = []string{}
+	_, err = infraProvider.init(*mockContext.Context, true)
+	require.NoError(t, err)
+	require.Empty(t, created)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "state list")
+	}).Respond(exec.NewRunResult(0, "azurerm_resource_group.rg\n", ""))
+
+	info, err := infraProvider.StateBackendInfo(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, StateBackendAzureRm, info.Kind)
+	require.Equal(t, []string{"azurerm_resource_group.rg"}, info.Resources)
+	require.Equal(t, "https://"+accountName+".blob.core.windows.net/tfstate/azd/test-env.tfstate", info.Location)
+}
+
+func TestTerraformRemoteStateOtherBackend(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"id": request.URL.Path})
+	})
+
+	infraProvider := createRemoteStateProvider(t, mockContext)
+	err := os.WriteFile(
+		filepath.Join(infraProvider.modulePath(), "backend.tf"),
+		[]byte("terraform {\n  backend \"s3\" {}\n}\n"),
+		0600,
+	)
+	require.NoError(t, err)
+
+	_, err = infraProvider.init(*mockContext.Context, true)
+	require.ErrorContains(t, err, "declares the s3 backend")
+}
+
+func TestTerraformLocalStateBackend(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "state list")
+	}).Respond(exec.NewRunResult(0, "azurerm_resource_group.rg\n", ""))
+
+	infraProvider := createTerraformProvider(mockContext)
+	infraProvider.projectPath = t.TempDir()
+	require.NoError(t, os.MkdirAll(infraProvider.modulePath(), 0755))
+
+	// No resources are tracked before the environment is provisioned
+	info, err := infraProvider.StateBackendInfo(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, StateBackendLocal, info.Kind)
+	require.Empty(t, info.Resources)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(infraProvider.localStateFilePath()), 0755))
+	require.NoError(t, os.WriteFile(infraProvider.localStateFilePath(), []byte(`{"version": 4}`), 0600))
+
+	info, err = infraProvider.StateBackendInfo(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, infraProvider.localStateFilePath(), info.Location)
+	require.Equal(t, []string{"azurerm_resource_group.rg"}, info.Resources)
+
+	state, err := infraProvider.PullState(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, `{"version": 4}`, string(state))
+
+	err = infraProvider.UnlockState(*mockContext.Context, "LOCK_ID")
+	require.ErrorIs(t, err, ErrStateNotLockable)
+}
+
+// createRemoteStateProvider creates a provider with infra.remoteState for a module without backend declaration
+func createRemoteStateProvider(t *testing.T, mockContext *mocks.MockContext) *TerraformProvider {
+	infraProvider := createTerraformProvider(mockContext)
+	infraProvider.projectPath = t.TempDir()
+	infraProvider.options.RemoteState = &RemoteStateOptions{}
+
+	require.NoError(t, os.MkdirAll(infraProvider.modulePath(), 0755))
+	err := os.WriteFile(
+		filepath.Join(infraProvider.modulePath(), "main.tf"),
+		[]byte("resource \"azurerm_resource_group\" \"rg\" {}\n"),
+		0600,
+	)
+	require.NoError(t, err)
+
+	return infraProvider
+}
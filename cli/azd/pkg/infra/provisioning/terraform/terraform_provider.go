@@ -15,6 +15,7 @@ package terraform
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -44,6 +45,7 @@ type TerraformProvider struct {
 	projectPath  string
 	options      Options
 	console      input.Console
+	azCli        azcli.AzCli
 	cli          terraform.TerraformCli
 	curPrincipal CurrentPrincipalIdProvider
 }
@@ -70,6 +72,7 @@ func NewTerraformProvider(
 	projectPath string,
 	infraOptions Options,
 	console input.Console,
+	azCli azcli.AzCli,
 	commandRunner exec.CommandRunner,
 	curPrincipal CurrentPrincipalIdProvider,
 	prompters Prompters,
@@ -86,6 +89,7 @@ func NewTerraformProvider(
 		projectPath:  projectPath,
 		options:      infraOptions,
 		console:      console,
+		azCli:        azCli,
 		cli:          terraformCli,
 		curPrincipal: curPrincipal,
 		prompters:    prompters,
@@ -318,8 +322,9 @@ func (t *TerraformProvider) State(
 func (t *TerraformProvider) createPlanArgs(isRemoteBackendConfig bool) []string {
 	args := []string{fmt.Sprintf("-var-file=%s", t.parametersFilePath())}
 
+	// The local state isn't locked, remote backends lock the state while it's updated
 	if !isRemoteBackendConfig {
-		args = append(args, fmt.Sprintf("-state=%s", t.localStateFilePath()))
+		args = append(args, fmt.Sprintf("-state=%s", t.localStateFilePath()), "-lock=false")
 	}
 
 	return args
@@ -330,7 +335,7 @@ func (t *TerraformProvider) createApplyArgs(
 	isRemoteBackendConfig bool, data TerraformDeploymentDetails) ([]string, error) {
 	args := []string{}
 	if !isRemoteBackendConfig {
-		args = append(args, fmt.Sprintf("-state=%s", data.localStateFilePath))
+		args = append(args, fmt.Sprintf("-state=%s", data.localStateFilePath), "-lock=false")
 	}
 
 	if _, err := os.Stat(data.PlanFilePath); err == nil {
@@ -379,12 +384,10 @@ func (t *TerraformProvider) init(ctx context.Context, isRemoteBackendConfig bool
 	cmd := []string{}
 
 	if isRemoteBackendConfig {
-		t.console.Message(ctx, "Generating terraform backend config file...")
-
-		err := t.createInputParametersFile(ctx, t.backendConfigTemplateFilePath(), t.backendConfigFilePath())
-		if err != nil {
+		if err := t.configureBackend(ctx); err != nil {
 			return fmt.Sprintf("creating terraform backend config file: %s", err), err
 		}
+
 		cmd = append(cmd, fmt.Sprintf("--backend-config=%s", t.backendConfigFilePath()))
 	}
 
@@ -393,9 +396,40 @@ func (t *TerraformProvider) init(ctx context.Context, isRemoteBackendConfig bool
 		return runResult, err
 	}
 
+	if t.options.RemoteState != nil {
+		if err := t.migrateLocalState(ctx); err != nil {
+			return err.Error(), err
+		}
+	}
+
 	return runResult, nil
 }
 
+// Writes the backend config file of the environment. With infra.remoteState, the remote state resources are created and
+// the backend config is generated unless the templates provide a backend config template.
+func (t *TerraformProvider) configureBackend(ctx context.Context) error {
+	var state *remoteState
+	if t.options.RemoteState != nil {
+		created, err := t.ensureRemoteState(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := t.ensureBackendBlock(); err != nil {
+			return err
+		}
+
+		state = created
+	}
+
+	t.console.Message(ctx, "Generating terraform backend config file...")
+	if _, err := os.Stat(t.backendConfigTemplateFilePath()); state != nil && errors.Is(err, os.ErrNotExist) {
+		return t.writeRemoteStateConfig(state)
+	}
+
+	return t.createInputParametersFile(ctx, t.backendConfigTemplateFilePath(), t.backendConfigFilePath())
+}
+
 // Creates a normalized view of the terraform output.
 func (t *TerraformProvider) createOutputParameters(
 	ctx context.Context,
@@ -660,6 +694,10 @@ func (t *TerraformProvider) dataDirPath() string {
 
 // Check terraform file for remote backend provider
 func (t *TerraformProvider) isRemoteBackendConfig() (bool, error) {
+	if t.options.RemoteState != nil {
+		return true, nil
+	}
+
 	modulePath := t.modulePath()
 	infraDir, _ := os.Open(modulePath)
 	files, err := infraDir.ReadDir(0)
@@ -738,13 +776,15 @@ func init() {
 			projectPath string,
 			options Options,
 			console input.Console,
-			_ azcli.AzCli,
+			azCli azcli.AzCli,
 			commandRunner exec.CommandRunner,
 			prompters Prompters,
 			curPrincipal CurrentPrincipalIdProvider,
 			_ *alpha.FeatureManager,
 		) (Provider, error) {
-			return NewTerraformProvider(ctx, env, projectPath, options, console, commandRunner, curPrincipal, prompters), nil
+			return NewTerraformProvider(
+				ctx, env, projectPath, options, console, azCli, commandRunner, curPrincipal, prompters,
+			), nil
 		},
 	)
 
@@ -14,6 +14,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
 
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
 	"github.com/stretchr/testify/require"
@@ -213,6 +214,7 @@ func createTerraformProvider(mockContext *mocks.MockContext) *TerraformProvider
 		projectDir,
 		options,
 		mockContext.Console,
+		mockazcli.NewAzCliFromMockContext(mockContext),
 		mockContext.CommandRunner,
 		&mockCurrentPrincipal{},
 		Prompters{
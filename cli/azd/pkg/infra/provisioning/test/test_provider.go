@@ -15,6 +15,7 @@ package test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
@@ -72,6 +73,11 @@ func (p *TestProvider) Plan(
 		})
 }
 
+// Fingerprint is the location of the plan, the only parameter the test provider deploys
+func (p *TestProvider) Fingerprint(ctx context.Context, plan *DeploymentPlan) (string, error) {
+	return fmt.Sprintf("%s/%v", p.Name(), plan.Deployment.Parameters["location"].Value), nil
+}
+
 func (p *TestProvider) State(
 	ctx context.Context,
 ) *async.InteractiveTaskWithProgress[*StateResult, *StateProgress] {
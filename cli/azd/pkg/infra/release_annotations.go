@@ -0,0 +1,171 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/google/uuid"
+)
+
+// The properties of the release annotations recorded by azd
+const (
+	releasePropertyName        = "ReleaseName"
+	releasePropertyDescription = "ReleaseDescription"
+	releasePropertyTraceId     = "AzdTraceId"
+	releasePropertyEnvName     = "AzdEnvName"
+	releasePropertyServices    = "AzdServices"
+)
+
+// ReleaseAnnotation is a deployment marker recorded in Application Insights when services are deployed
+type ReleaseAnnotation struct {
+	// The id of the Application Insights component annotated
+	ComponentId string
+	Time        time.Time
+	// The trace id of the azd operation that deployed the services
+	TraceId  string
+	EnvName  string
+	Services []string
+}
+
+// ReleaseAnnotator records deployment markers in the Application Insights components of an environment, linking the
+// azd operations to the telemetry of the application
+type ReleaseAnnotator struct {
+	azCli           azcli.AzCli
+	resourceManager *AzureResourceManager
+}
+
+func NewReleaseAnnotator(azCli azcli.AzCli) *ReleaseAnnotator {
+	return &ReleaseAnnotator{
+		azCli:           azCli,
+		resourceManager: NewAzureResourceManager(azCli),
+	}
+}
+
+// Annotate records the release in each Application Insights component of the environment.
+// Returns the ids of the components annotated.
+func (a *ReleaseAnnotator) Annotate(
+	ctx context.Context,
+	subscriptionId string,
+	release ReleaseAnnotation,
+) ([]string, error) {
+	components, err := a.components(ctx, subscriptionId, release.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := json.Marshal(map[string]string{
+		releasePropertyName: fmt.Sprintf("azd deploy %s", release.EnvName),
+		releasePropertyDescription: fmt.Sprintf(
+			"Deployed %s with azd (trace id %s)", strings.Join(release.Services, ", "), release.TraceId),
+		releasePropertyTraceId:  release.TraceId,
+		releasePropertyEnvName:  release.EnvName,
+		releasePropertyServices: strings.Join(release.Services, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	annotated := []string{}
+	for _, component := range components {
+		err := a.azCli.CreateAppInsightsAnnotation(ctx, subscriptionId, component.Id, azsdk.Annotation{
+			Id:             uuid.NewString(),
+			AnnotationName: fmt.Sprintf("azd deploy %s", release.EnvName),
+			Category:       azsdk.AnnotationCategoryDeployment,
+			EventTime:      release.Time.UTC(),
+			Properties:     string(properties),
+		})
+		if err != nil {
+			return annotated, fmt.Errorf("annotating application insights '%s': %w", component.Name, err)
+		}
+
+		annotated = append(annotated, component.Id)
+	}
+
+	return annotated, nil
+}
+
+// FindByTraceId returns the release recorded by the azd operation with the specified trace id since the specified
+// time, if any
+func (a *ReleaseAnnotator) FindByTraceId(
+	ctx context.Context,
+	subscriptionId string,
+	envName string,
+	traceId string,
+	since time.Time,
+) (*ReleaseAnnotation, error) {
+	components, err := a.components(ctx, subscriptionId, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		annotations, err := a.azCli.ListAppInsightsAnnotations(ctx, subscriptionId, component.Id, since, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, annotation := range annotations {
+			var properties map[string]string
+			if err := json.Unmarshal([]byte(annotation.Properties), &properties); err != nil {
+				continue
+			}
+
+			if !strings.EqualFold(properties[releasePropertyTraceId], traceId) {
+				continue
+			}
+
+			release := &ReleaseAnnotation{
+				ComponentId: component.Id,
+				Time:        annotation.EventTime,
+				TraceId:     properties[releasePropertyTraceId],
+				EnvName:     properties[releasePropertyEnvName],
+			}
+			if services := properties[releasePropertyServices]; services != "" {
+				release.Services = strings.Split(services, ",")
+			}
+
+			return release, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// components returns the Application Insights components of the resource groups of the environment
+func (a *ReleaseAnnotator) components(
+	ctx context.Context,
+	subscriptionId string,
+	envName string,
+) ([]azcli.AzCliResource, error) {
+	resourceGroups, err := a.resourceManager.GetResourceGroupsForEnvironment(ctx, subscriptionId, envName)
+	if err != nil {
+		return nil, fmt.Errorf("discovering resource groups from deployment: %w", err)
+	}
+
+	components := []azcli.AzCliResource{}
+	for _, resourceGroup := range resourceGroups {
+		filter := fmt.Sprintf("resourceType eq '%s'", AzureResourceTypeAppInsightComponent)
+		resources, err := a.azCli.ListResourceGroupResources(
+			ctx,
+			azure.SubscriptionFromRID(resourceGroup.Id),
+			resourceGroup.Name,
+			&azcli.ListResourceGroupResourcesOptions{Filter: &filter},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("listing resources: %w", err)
+		}
+
+		components = append(components, resources...)
+	}
+
+	return components, nil
+}
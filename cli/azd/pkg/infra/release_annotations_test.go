@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseAnnotator(t *testing.T) {
+	resourceGroupId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test-env"
+	componentId := resourceGroupId + "/providers/Microsoft.Insights/components/appi"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourcegroups")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+			Value: []*armresources.ResourceGroup{
+				{
+					ID:       convert.RefOf(resourceGroupId),
+					Name:     convert.RefOf("rg-test-env"),
+					Type:     convert.RefOf("Microsoft.Resources/resourceGroups"),
+					Location: convert.RefOf("eastus2"),
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/rg-test-env/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.Equal(t, "resourceType eq 'Microsoft.Insights/components'", request.URL.Query().Get("$filter"))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceListResult{
+			Value: []*armresources.GenericResourceExpanded{
+				{
+					ID:       convert.RefOf(componentId),
+					Name:     convert.RefOf("appi"),
+					Type:     convert.RefOf(string(AzureResourceTypeAppInsightComponent)),
+					Location: convert.RefOf("eastus2"),
+				},
+			},
+		})
+	})
+
+	annotations := []azsdk.Annotation{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Path == componentId+"/Annotations"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if request.Method == http.MethodGet {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"value": annotations})
+		}
+
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		var annotation azsdk.Annotation
+		require.NoError(t, json.Unmarshal(body, &annotation))
+		annotations = append(annotations, annotation)
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, []azsdk.Annotation{annotation})
+	})
+
+	annotator := NewReleaseAnnotator(mockazcli.NewAzCliFromMockContext(mockContext))
+	deployedOn := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	annotated, err := annotator.Annotate(*mockContext.Context, "SUBSCRIPTION_ID", ReleaseAnnotation{
+		Time:     deployedOn,
+		TraceId:  "0123456789abcdef0123456789abcdef",
+		EnvName:  "test-env",
+		Services: []string{"api", "web"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{componentId}, annotated)
+
+	require.Len(t, annotations, 1)
+	require.Equal(t, azsdk.AnnotationCategoryDeployment, annotations[0].Category)
+	require.Equal(t, "azd deploy test-env", annotations[0].AnnotationName)
+
+	t.Run("Found", func(t *testing.T) {
+		release, err := annotator.FindByTraceId(
+			*mockContext.Context, "SUBSCRIPTION_ID", "test-env", "0123456789ABCDEF0123456789ABCDEF", deployedOn)
+		require.NoError(t, err)
+		require.NotNil(t, release)
+
+		require.Equal(t, componentId, release.ComponentId)
+		require.Equal(t, deployedOn, release.Time)
+		require.Equal(t, []string{"api", "web"}, release.Services)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		release, err := annotator.FindByTraceId(
+			*mockContext.Context, "SUBSCRIPTION_ID", "test-env", "fedcba9876543210fedcba9876543210", deployedOn)
+		require.NoError(t, err)
+		require.Nil(t, release)
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/drone/envsubst"
+	"github.com/google/uuid"
+)
+
+// RoleAssignmentDeployer is the principal of the role assignments of the principal running azd, ex. the logged in user
+// or the service principal of a CI pipeline
+const RoleAssignmentDeployer = "deployer"
+
+// RoleAssignmentOptions describes a role assigned after each provision, from the infra.roleAssignments section of
+// azure.yaml. The values support environment substitutions, ex. ${SERVICE_API_IDENTITY_PRINCIPAL_ID}.
+type RoleAssignmentOptions struct {
+	// The name of the role, ex. AcrPull, or the id of its role definition
+	Role string `yaml:"role"`
+	// The object id of the principal, or deployer for the principal running azd
+	Principal string `yaml:"principal"`
+	// The resource id of the scope of the role assignment, defaults to the resource group of the environment
+	Scope string `yaml:"scope,omitempty"`
+}
+
+// RoleAssignment is a role assignment of the infra.roleAssignments section, resolved with the values of the environment
+type RoleAssignment struct {
+	azcli.AzCliRoleAssignment
+	// The role of the azure.yaml section
+	Role string `json:"role"`
+	// The name of the role assignment, derived from its scope, role and principal so that it's only created once
+	Name string `json:"name"`
+}
+
+// RoleAssignmentManager creates the role assignments of the infra.roleAssignments section after provisioning, and
+// deletes them when the environment is deleted
+type RoleAssignmentManager struct {
+	azCli azcli.AzCli
+}
+
+func NewRoleAssignmentManager(azCli azcli.AzCli) *RoleAssignmentManager {
+	return &RoleAssignmentManager{
+		azCli: azCli,
+	}
+}
+
+// Resolve returns the role assignments of the options, the values substituted by getenv. The scope defaults to the
+// resource group, and deployerId returns the object id of the principal running azd.
+func (m *RoleAssignmentManager) Resolve(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	options []RoleAssignmentOptions,
+	getenv func(string) string,
+	deployerId func(ctx context.Context) (string, error),
+) ([]RoleAssignment, error) {
+	assignments := []RoleAssignment{}
+	roleDefinitionIds := map[string]string{}
+
+	for i, option := range options {
+		field := func(name string, value string) (string, error) {
+			replaced, err := envsubst.Eval(value, getenv)
+			if err != nil {
+				return "", fmt.Errorf("infra.roleAssignments[%d].%s: %w", i, name, err)
+			}
+
+			return strings.TrimSpace(replaced), nil
+		}
+
+		role, err := field("role", option.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		principal, err := field("principal", option.Principal)
+		if err != nil {
+			return nil, err
+		}
+
+		scope, err := field("scope", option.Scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if role == "" || principal == "" {
+			return nil, fmt.Errorf(
+				"infra.roleAssignments[%d]: role and principal must be set, got role '%s' and principal '%s'",
+				i, role, principal)
+		}
+
+		if principal == RoleAssignmentDeployer {
+			if principal, err = deployerId(ctx); err != nil {
+				return nil, fmt.Errorf("infra.roleAssignments[%d]: %w", i, err)
+			}
+		}
+
+		if scope == "" {
+			if resourceGroupName == "" {
+				return nil, fmt.Errorf(
+					"infra.roleAssignments[%d]: scope must be set, the environment has no resource group", i)
+			}
+
+			scope = azure.ResourceGroupRID(subscriptionId, resourceGroupName)
+		}
+
+		roleDefinitionId, has := roleDefinitionIds[role+scope]
+		if !has {
+			roleDefinitionId, err = m.roleDefinitionId(ctx, subscriptionId, scope, role)
+			if err != nil {
+				return nil, fmt.Errorf("infra.roleAssignments[%d]: %w", i, err)
+			}
+
+			roleDefinitionIds[role+scope] = roleDefinitionId
+		}
+
+		assignment := RoleAssignment{
+			AzCliRoleAssignment: azcli.AzCliRoleAssignment{
+				Scope:            scope,
+				PrincipalId:      principal,
+				RoleDefinitionId: roleDefinitionId,
+			},
+			Role: role,
+		}
+		assignment.Name = roleAssignmentName(assignment.AzCliRoleAssignment)
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, nil
+}
+
+// Ensure creates the role assignments that don't exist
+func (m *RoleAssignmentManager) Ensure(ctx context.Context, subscriptionId string, assignments []RoleAssignment) error {
+	for _, assignment := range assignments {
+		if err := m.azCli.CreateRoleAssignment(
+			ctx, subscriptionId, assignment.Name, assignment.AzCliRoleAssignment); err != nil {
+			return fmt.Errorf("assigning role %s: %w", assignment.Role, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the role assignments created by Ensure, the role assignments deleted with their scope are skipped
+func (m *RoleAssignmentManager) Delete(ctx context.Context, subscriptionId string, assignments []RoleAssignment) error {
+	for _, assignment := range assignments {
+		if err := m.azCli.DeleteRoleAssignment(ctx, subscriptionId, assignment.Scope, assignment.Name); err != nil {
+			return fmt.Errorf("removing role %s: %w", assignment.Role, err)
+		}
+	}
+
+	return nil
+}
+
+// roleDefinitionId returns the id of the role definition of the role name, or of the role definition id
+func (m *RoleAssignmentManager) roleDefinitionId(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	role string,
+) (string, error) {
+	if strings.HasPrefix(strings.ToLower(role), "/subscriptions/") || strings.HasPrefix(role, "/providers/") {
+		return role, nil
+	}
+
+	if _, err := uuid.Parse(role); err == nil {
+		return fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleDefinitions/%s", azure.SubscriptionRID(subscriptionId), role), nil
+	}
+
+	return m.azCli.GetRoleDefinitionId(ctx, subscriptionId, scope, role)
+}
+
+// roleAssignmentName returns the name of the role assignment, a GUID derived from its scope, role and principal
+func roleAssignmentName(assignment azcli.AzCliRoleAssignment) string {
+	key := strings.ToLower(strings.Join(
+		[]string{assignment.Scope, assignment.RoleDefinitionId, assignment.PrincipalId}, "|"))
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(key)).String()
+}
@@ -0,0 +1,204 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+const acrPullRoleDefinitionId = "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/roleDefinitions/" +
+	"7f951dda-4ed3-4680-a7ca-43fe172d538d"
+
+func setupRoleDefinitionMocks(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Authorization/roleDefinitions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		definitions := []*armauthorization.RoleDefinition{}
+		if strings.Contains(request.URL.Query().Get("$filter"), "'AcrPull'") {
+			definitions = append(definitions, &armauthorization.RoleDefinition{
+				ID: convert.RefOf(acrPullRoleDefinitionId),
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armauthorization.RoleDefinitionListResult{
+			Value: definitions,
+		})
+	})
+}
+
+func TestRoleAssignmentManagerResolve(t *testing.T) {
+	env := map[string]string{
+		"SERVICE_API_IDENTITY_PRINCIPAL_ID": "API_PRINCIPAL_ID",
+		"AZURE_KEY_VAULT_ID":                "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-shared/vaults/kv",
+	}
+	getenv := func(key string) string {
+		return env[key]
+	}
+	deployerId := func(context.Context) (string, error) {
+		return "DEPLOYER_ID", nil
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupRoleDefinitionMocks(mockContext)
+		manager := NewRoleAssignmentManager(mockazcli.NewAzCliFromMockContext(mockContext))
+
+		assignments, err := manager.Resolve(
+			*mockContext.Context, "SUBSCRIPTION_ID", "rg-test-env", []RoleAssignmentOptions{
+				{Role: "AcrPull", Principal: "${SERVICE_API_IDENTITY_PRINCIPAL_ID}"},
+				{
+					Role:      "4633458b-17de-408a-b874-0445c86b69e6",
+					Principal: RoleAssignmentDeployer,
+					Scope:     "${AZURE_KEY_VAULT_ID}",
+				},
+			}, getenv, deployerId)
+		require.NoError(t, err)
+		require.Len(t, assignments, 2)
+
+		require.Equal(t, "AcrPull", assignments[0].Role)
+		require.Equal(t, "API_PRINCIPAL_ID", assignments[0].PrincipalId)
+		require.Equal(t, "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test-env", assignments[0].Scope)
+		require.Equal(t, acrPullRoleDefinitionId, assignments[0].RoleDefinitionId)
+
+		require.Equal(t, "DEPLOYER_ID", assignments[1].PrincipalId)
+		require.Equal(t, env["AZURE_KEY_VAULT_ID"], assignments[1].Scope)
+		require.Equal(t,
+			"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/roleDefinitions/"+
+				"4633458b-17de-408a-b874-0445c86b69e6",
+			assignments[1].RoleDefinitionId)
+
+		// The names are stable, the role assignments are only created once
+		again, err := manager.Resolve(*mockContext.Context, "SUBSCRIPTION_ID", "rg-test-env", []RoleAssignmentOptions{
+			{Role: "AcrPull", Principal: "API_PRINCIPAL_ID"},
+		}, getenv, deployerId)
+		require.NoError(t, err)
+		require.Equal(t, assignments[0].Name, again[0].Name)
+		require.NotEqual(t, assignments[0].Name, assignments[1].Name)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupRoleDefinitionMocks(mockContext)
+		manager := NewRoleAssignmentManager(mockazcli.NewAzCliFromMockContext(mockContext))
+
+		tests := []struct {
+			name          string
+			options       RoleAssignmentOptions
+			resourceGroup string
+			deployerId    func(context.Context) (string, error)
+			err           string
+		}{
+			{
+				name:          "NoPrincipal",
+				options:       RoleAssignmentOptions{Role: "AcrPull", Principal: "${MISSING}"},
+				resourceGroup: "rg-test-env",
+				err:           "role and principal must be set",
+			},
+			{
+				name:    "NoScope",
+				options: RoleAssignmentOptions{Role: "AcrPull", Principal: "PRINCIPAL_ID"},
+				err:     "scope must be set",
+			},
+			{
+				name:          "UnknownRole",
+				options:       RoleAssignmentOptions{Role: "Unknown", Principal: "PRINCIPAL_ID"},
+				resourceGroup: "rg-test-env",
+				err:           "was not found",
+			},
+			{
+				name:          "NoDeployer",
+				options:       RoleAssignmentOptions{Role: "AcrPull", Principal: RoleAssignmentDeployer},
+				resourceGroup: "rg-test-env",
+				deployerId: func(context.Context) (string, error) {
+					return "", errors.New("not logged in")
+				},
+				err: "not logged in",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.deployerId == nil {
+					tt.deployerId = deployerId
+				}
+
+				_, err := manager.Resolve(*mockContext.Context, "SUBSCRIPTION_ID", tt.resourceGroup,
+					[]RoleAssignmentOptions{tt.options}, getenv, tt.deployerId)
+				require.ErrorContains(t, err, "infra.roleAssignments[0]")
+				require.ErrorContains(t, err, tt.err)
+			})
+		}
+	})
+}
+
+func TestRoleAssignmentManagerEnsureAndDelete(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupRoleDefinitionMocks(mockContext)
+
+	created := map[string]armauthorization.RoleAssignmentCreateParameters{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "/roleAssignments/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		var parameters armauthorization.RoleAssignmentCreateParameters
+		if err := json.Unmarshal(body, &parameters); err != nil {
+			return nil, err
+		}
+
+		// The role is already assigned when the role assignment is created again
+		if _, has := created[request.URL.Path]; has {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusConflict)
+		}
+
+		created[request.URL.Path] = parameters
+		return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, armauthorization.RoleAssignment{
+			ID: convert.RefOf(request.URL.Path),
+		})
+	})
+
+	deleted := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete && strings.Contains(request.URL.Path, "/roleAssignments/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = append(deleted, request.URL.Path)
+
+		// The role assignment was already deleted with its scope
+		return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+	})
+
+	manager := NewRoleAssignmentManager(mockazcli.NewAzCliFromMockContext(mockContext))
+	assignments, err := manager.Resolve(*mockContext.Context, "SUBSCRIPTION_ID", "rg-test-env", []RoleAssignmentOptions{
+		{Role: "AcrPull", Principal: "PRINCIPAL_ID"},
+	}, func(string) string { return "" }, nil)
+	require.NoError(t, err)
+
+	expectedPath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test-env/providers/Microsoft.Authorization/" +
+		"roleAssignments/" + assignments[0].Name
+
+	require.NoError(t, manager.Ensure(*mockContext.Context, "SUBSCRIPTION_ID", assignments))
+	require.NoError(t, manager.Ensure(*mockContext.Context, "SUBSCRIPTION_ID", assignments))
+	require.Len(t, created, 1)
+	require.Equal(t, "PRINCIPAL_ID", *created[expectedPath].Properties.PrincipalID)
+	require.Equal(t, acrPullRoleDefinitionId, *created[expectedPath].Properties.RoleDefinitionID)
+
+	require.NoError(t, manager.Delete(*mockContext.Context, "SUBSCRIPTION_ID", assignments))
+	require.Equal(t, []string{expectedPath}, deleted)
+}
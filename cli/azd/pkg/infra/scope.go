@@ -33,6 +33,12 @@ type Deployment interface {
 		parameters azure.ArmParameters,
 		tags map[string]*string,
 	) (*armresources.DeploymentExtended, error)
+	// WhatIf previews the changes of the deployment of a given template with a set of parameters, without deploying it.
+	WhatIf(
+		ctx context.Context,
+		template azure.RawArmTemplate,
+		parameters azure.ArmParameters,
+	) (*armresources.WhatIfOperationResult, error)
 	// Deployment fetches information about this deployment.
 	Deployment(ctx context.Context) (*armresources.DeploymentExtended, error)
 	// Operations returns all the operations for this deployment.
@@ -64,6 +70,13 @@ func (s *ResourceGroupDeployment) Deploy(
 	return s.azCli.DeployToResourceGroup(ctx, s.subscriptionId, s.resourceGroupName, s.name, template, parameters, tags)
 }
 
+// WhatIf previews the changes of the deployment to the resource group.
+func (s *ResourceGroupDeployment) WhatIf(
+	ctx context.Context, template azure.RawArmTemplate, parameters azure.ArmParameters,
+) (*armresources.WhatIfOperationResult, error) {
+	return s.azCli.WhatIfDeployToResourceGroup(ctx, s.subscriptionId, s.resourceGroupName, s.name, template, parameters)
+}
+
 // GetDeployment fetches the result of the most recent deployment.
 func (s *ResourceGroupDeployment) Deployment(ctx context.Context) (*armresources.DeploymentExtended, error) {
 	return s.azCli.GetResourceGroupDeployment(ctx, s.subscriptionId, s.resourceGroupName, s.name)
@@ -155,6 +168,13 @@ func (s *SubscriptionDeployment) Deploy(
 	return s.azCli.DeployToSubscription(ctx, s.subscriptionId, s.location, s.name, template, parameters, tags)
 }
 
+// WhatIf previews the changes of the deployment to the subscription.
+func (s *SubscriptionDeployment) WhatIf(
+	ctx context.Context, template azure.RawArmTemplate, parameters azure.ArmParameters,
+) (*armresources.WhatIfOperationResult, error) {
+	return s.azCli.WhatIfDeployToSubscription(ctx, s.subscriptionId, s.location, s.name, template, parameters)
+}
+
 // GetDeployment fetches the result of the most recent deployment.
 func (s *SubscriptionDeployment) Deployment(ctx context.Context) (*armresources.DeploymentExtended, error) {
 	return s.azCli.GetSubscriptionDeployment(ctx, s.subscriptionId, s.name)
@@ -0,0 +1,200 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The status of the resources managed by a deployment stack
+const cStackResourceStatusManaged = "managed"
+
+// StackDeployment deploys templates as a deployment stack at the scope of the subscription or resource group deployment.
+// Updating the stack deletes the resources removed from the template, and deleting the stack deletes all the resources
+// and resource groups it manages.
+type StackDeployment struct {
+	// The deployment at the scope of the stack, previews the changes of the stack
+	deployment        Deployment
+	azCli             azcli.AzCli
+	resourceGroupName string
+	location          string
+	name              string
+	denySettingsMode  string
+}
+
+// NewStackDeployment creates the deployment stack at the scope of the subscription or resource group deployment. The
+// deny settings mode is one of the azsdk.DenySettingsMode* values, none when empty.
+func NewStackDeployment(
+	azCli azcli.AzCli, deployment Deployment, stackName string, denySettingsMode string,
+) *StackDeployment {
+	stack := &StackDeployment{
+		deployment:       deployment,
+		azCli:            azCli,
+		name:             stackName,
+		denySettingsMode: denySettingsMode,
+	}
+
+	switch scope := deployment.(type) {
+	case *SubscriptionDeployment:
+		stack.location = scope.Location()
+	case *ResourceGroupDeployment:
+		stack.resourceGroupName = scope.ResourceGroupName()
+	}
+
+	if stack.denySettingsMode == "" {
+		stack.denySettingsMode = azsdk.DenySettingsModeNone
+	}
+
+	return stack
+}
+
+// Name is the name of the deployment stack
+func (s *StackDeployment) Name() string {
+	return s.name
+}
+
+// Gets the Azure subscription id
+func (s *StackDeployment) SubscriptionId() string {
+	return s.deployment.SubscriptionId()
+}
+
+// Gets the resource group name of the stack, empty for stacks deployed to the subscription
+func (s *StackDeployment) ResourceGroupName() string {
+	return s.resourceGroupName
+}
+
+// Gets the Azure location of the stack, empty for stacks deployed to resource groups
+func (s *StackDeployment) Location() string {
+	return s.location
+}
+
+// ListDeployments returns all the deployments at the scope of the stack.
+func (s *StackDeployment) ListDeployments(ctx context.Context) ([]*armresources.DeploymentExtended, error) {
+	return s.deployment.ListDeployments(ctx)
+}
+
+// Gets the url of the deployment stack in the Azure Portal
+func (s *StackDeployment) PortalUrl() string {
+	return fmt.Sprintf("https://portal.azure.com/#resource%s",
+		azsdk.DeploymentStackId(s.SubscriptionId(), s.resourceGroupName, s.name))
+}
+
+// Deploy creates or updates the stack with a given template and set of parameters. The resources and resource groups
+// removed from the template are deleted.
+func (s *StackDeployment) Deploy(
+	ctx context.Context, template azure.RawArmTemplate, parameters azure.ArmParameters, tags map[string]*string,
+) (*armresources.DeploymentExtended, error) {
+	stack, err := s.azCli.CreateOrUpdateDeploymentStack(
+		ctx,
+		s.SubscriptionId(),
+		s.resourceGroupName,
+		s.name,
+		azsdk.DeploymentStack{
+			Location: s.location,
+			Tags:     tags,
+			Properties: azsdk.DeploymentStackProperties{
+				Template:   json.RawMessage(template),
+				Parameters: parameters,
+				ActionOnUnmanage: azsdk.DeploymentStackActionOnUnmanage{
+					Resources:      azsdk.DeploymentStackActionDelete,
+					ResourceGroups: azsdk.DeploymentStackActionDelete,
+				},
+				DenySettings: azsdk.DeploymentStackDenySettings{
+					Mode: s.denySettingsMode,
+				},
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return stackAsDeployment(stack), nil
+}
+
+// WhatIf previews the changes of the deployment of the template at the scope of the stack.
+func (s *StackDeployment) WhatIf(
+	ctx context.Context, template azure.RawArmTemplate, parameters azure.ArmParameters,
+) (*armresources.WhatIfOperationResult, error) {
+	return s.deployment.WhatIf(ctx, template, parameters)
+}
+
+// Deployment fetches the result of the most recent deployment of the stack.
+func (s *StackDeployment) Deployment(ctx context.Context) (*armresources.DeploymentExtended, error) {
+	stack, err := s.azCli.GetDeploymentStack(ctx, s.SubscriptionId(), s.resourceGroupName, s.name)
+	if errors.Is(err, azsdk.ErrDeploymentStackNotFound) {
+		return nil, fmt.Errorf("%w: %w", azcli.ErrDeploymentNotFound, err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return stackAsDeployment(stack), nil
+}
+
+// Gets the operations of the deployment of the stack, none until the stack started its deployment
+func (s *StackDeployment) Operations(ctx context.Context) ([]*armresources.DeploymentOperation, error) {
+	stack, err := s.azCli.GetDeploymentStack(ctx, s.SubscriptionId(), s.resourceGroupName, s.name)
+	if errors.Is(err, azsdk.ErrDeploymentStackNotFound) {
+		return []*armresources.DeploymentOperation{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if stack.Properties.DeploymentId == "" {
+		return []*armresources.DeploymentOperation{}, nil
+	}
+
+	deploymentName := path.Base(stack.Properties.DeploymentId)
+	if s.resourceGroupName != "" {
+		return s.azCli.ListResourceGroupDeploymentOperations(
+			ctx, s.SubscriptionId(), s.resourceGroupName, deploymentName)
+	}
+
+	return s.azCli.ListSubscriptionDeploymentOperations(ctx, s.SubscriptionId(), deploymentName)
+}
+
+// stackAsDeployment describes the deployment stack as a deployment, with the outputs of the stack and the resources it
+// manages
+func stackAsDeployment(stack *azsdk.DeploymentStack) *armresources.DeploymentExtended {
+	outputResources := []*armresources.ResourceReference{}
+	for _, resource := range stack.Properties.Resources {
+		if resource.Status == "" || resource.Status == cStackResourceStatusManaged {
+			outputResources = append(outputResources, &armresources.ResourceReference{ID: to.Ptr(resource.Id)})
+		}
+	}
+
+	return &armresources.DeploymentExtended{
+		ID:   to.Ptr(stack.Id),
+		Name: to.Ptr(stack.Name),
+		Tags: stack.Tags,
+		Properties: &armresources.DeploymentPropertiesExtended{
+			Outputs:           stack.Properties.Outputs,
+			OutputResources:   outputResources,
+			ProvisioningState: to.Ptr(stackProvisioningState(stack.Properties.ProvisioningState)),
+		},
+	}
+}
+
+// stackProvisioningState returns the provisioning state of deployments matching the state of the stack, the states of
+// stacks are camel case, ex. succeeded
+func stackProvisioningState(state string) armresources.ProvisioningState {
+	for _, deploymentState := range armresources.PossibleProvisioningStateValues() {
+		if strings.EqualFold(state, string(deploymentState)) {
+			return deploymentState
+		}
+	}
+
+	return armresources.ProvisioningState(state)
+}
@@ -11,12 +11,14 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/mattn/go-isatty"
@@ -46,6 +48,11 @@ type ConsoleShim interface {
 
 type PromptValidator func(response string) error
 
+// WarningsRecorder is implemented by consoles recording the warnings displayed with json formatting
+type WarningsRecorder interface {
+	Warnings() []string
+}
+
 type Console interface {
 	// Prints out a message to the underlying console write
 	Message(ctx context.Context, message string)
@@ -96,6 +103,9 @@ type AskerConsole struct {
 
 	currentIndent string
 	consoleWidth  int
+
+	// the warnings displayed with json formatting, reported in the result of the command
+	warnings []string
 }
 
 type ConsoleOptions struct {
@@ -133,14 +143,22 @@ func (c *AskerConsole) IsUnformatted() bool {
 // Prints out a message to the underlying console write
 func (c *AskerConsole) Message(ctx context.Context, message string) {
 	// Disable output when formatting is enabled
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
+	if output.IsEventStream(c.formatter) {
 		// we call json.Marshal directly, because the formatter marshalls using indentation, and we would prefer
 		// these objects be written on a single line.
-		jsonMessage, err := json.Marshal(output.EventForMessage(message))
+		event := output.EventForMessage(message)
+		jsonMessage, err := json.Marshal(event)
 		if err != nil {
 			panic(fmt.Sprintf("Message: unexpected error during marshaling for a valid object: %v", err))
 		}
 		fmt.Fprintln(c.writer, string(jsonMessage))
+
+		if consoleMessage, ok := event.Data.(contracts.ConsoleMessage); ok {
+			text := strings.TrimSpace(consoleMessage.Message)
+			if strings.HasPrefix(strings.ToUpper(text), warningPrefix) {
+				c.warnings = append(c.warnings, strings.TrimSpace(text[len(warningPrefix):]))
+			}
+		}
 	} else if c.formatter == nil || c.formatter.Kind() == output.NoneFormat {
 		fmt.Fprintln(c.writer, message)
 	} else {
@@ -148,6 +166,14 @@ func (c *AskerConsole) Message(ctx context.Context, message string) {
 	}
 }
 
+// The prefix of warning messages, ex. output.WithWarningFormat("WARNING: ...")
+const warningPrefix = "WARNING:"
+
+// Warnings returns the warnings displayed with json formatting
+func (c *AskerConsole) Warnings() []string {
+	return c.warnings
+}
+
 func (c *AskerConsole) WarnForFeature(ctx context.Context, key alpha.FeatureId) {
 	if shouldWarn(key) {
 		c.MessageUxItem(ctx, &ux.MultilineMessage{
@@ -170,11 +196,15 @@ func shouldWarn(key alpha.FeatureId) bool {
 }
 
 func (c *AskerConsole) MessageUxItem(ctx context.Context, item ux.UxItem) {
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
+	if output.IsEventStream(c.formatter) {
 		// no need to check the spinner for json format, as the spinner won't start when using json format
 		// instead, there would be a message about starting spinner
 		json, _ := json.Marshal(item)
 		fmt.Fprintln(c.writer, string(json))
+
+		if warning, ok := item.(*ux.WarningMessage); ok {
+			c.warnings = append(c.warnings, warning.Description)
+		}
 		return
 	}
 
@@ -201,7 +231,7 @@ func (c *AskerConsole) spinnerText(title, charset string) string {
 }
 
 func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format SpinnerUxType) {
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
+	if output.IsEventStream(c.formatter) {
 		// Spinner is disabled when using json format.
 		return
 	}
@@ -220,11 +250,6 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 
 	charSet := c.getCharset(format)
 
-	// determine the terminal mode once
-	c.spinnerTerminalModeOnce.Do(func() {
-		c.spinnerTerminalMode = GetSpinnerTerminalMode(&c.isTerminal)
-	})
-
 	spinnerConfig := yacspin.Config{
 		Frequency:       200 * time.Millisecond,
 		Writer:          c.writer,
@@ -233,7 +258,7 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 		Message:         c.spinnerText(title, charSet[0]),
 		CharSet:         charSet,
 	}
-	spinnerConfig.TerminalMode = c.spinnerTerminalMode
+	spinnerConfig.TerminalMode = c.terminalMode()
 
 	c.spinner, _ = yacspin.New(spinnerConfig)
 
@@ -313,7 +338,7 @@ func (c *AskerConsole) getIndent(format SpinnerUxType) string {
 }
 
 func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, format SpinnerUxType) {
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
+	if output.IsEventStream(c.formatter) {
 		// Spinner is disabled when using json format.
 		return
 	}
@@ -343,24 +368,38 @@ func (c *AskerConsole) IsSpinnerRunning(ctx context.Context) bool {
 }
 
 func (c *AskerConsole) IsSpinnerInteractive() bool {
-	return c.spinnerTerminalMode&yacspin.ForceTTYMode > 0
+	return c.terminalMode()&yacspin.ForceTTYMode > 0
+}
+
+// Gets the terminal mode of the spinner, determined once
+func (c *AskerConsole) terminalMode() yacspin.TerminalMode {
+	c.spinnerTerminalModeOnce.Do(func() {
+		c.spinnerTerminalMode = GetSpinnerTerminalMode(&c.isTerminal)
+	})
+
+	return c.spinnerTerminalMode
 }
 
 var donePrefix string = output.WithSuccessFormat("(✓) Done:")
 
 func (c *AskerConsole) getStopChar(format SpinnerUxType) string {
-	var stopChar string
+	return fmt.Sprintf("%s%s", c.getIndent(format), stepResultPrefix(format))
+}
+
+// Gets the prefix of the final message of a step with the specified result, empty for running steps
+func stepResultPrefix(format SpinnerUxType) string {
 	switch format {
 	case StepDone:
-		stopChar = donePrefix
+		return donePrefix
 	case StepFailed:
-		stopChar = output.WithErrorFormat("(x) Failed:")
+		return output.WithErrorFormat("(x) Failed:")
 	case StepWarning:
-		stopChar = output.WithWarningFormat("(!) Warning:")
+		return output.WithWarningFormat("(!) Warning:")
 	case StepSkipped:
-		stopChar = output.WithGrayFormat("(-) Skipped:")
+		return output.WithGrayFormat("(-) Skipped:")
 	}
-	return fmt.Sprintf("%s%s", c.getIndent(format), stopChar)
+
+	return ""
 }
 
 func promptFromOptions(options ConsoleOptions) survey.Prompt {
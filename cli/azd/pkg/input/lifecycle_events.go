@@ -0,0 +1,92 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// LifecycleEvents writes the lifecycle events of the commands run with `--output events`, ex. provision.start or
+// service.deploy.progress, so IDE extensions can display the progress of the commands without parsing their messages.
+// Nothing is written with the other output formats.
+type LifecycleEvents struct {
+	writer io.Writer
+	now    func() time.Time
+
+	mu sync.Mutex
+	// The endpoints of the services deployed, written in the done event of the command deploying them
+	endpoints map[string][]string
+}
+
+// NewLifecycleEvents creates the lifecycle events of the console, written on the lines of the console
+func NewLifecycleEvents(console Console) *LifecycleEvents {
+	events := &LifecycleEvents{
+		now:       time.Now,
+		endpoints: map[string][]string{},
+	}
+
+	if formatter := console.GetFormatter(); formatter != nil && formatter.Kind() == output.EventsFormat {
+		events.writer = console.GetWriter()
+	}
+
+	return events
+}
+
+// Enabled returns whether the events are written, with `--output events`
+func (e *LifecycleEvents) Enabled() bool {
+	return e.writer != nil
+}
+
+// Emit writes the event of the subject and phase on a single line, ex. provision and start for provision.start
+func (e *LifecycleEvents) Emit(subject string, phase string, data contracts.LifecycleEvent) {
+	if !e.Enabled() {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	event := contracts.EventEnvelope{
+		Type:      contracts.LifecycleEventDataType(subject, phase),
+		Timestamp: e.now(),
+		Data:      data,
+	}
+
+	if err := json.NewEncoder(e.writer).Encode(event); err != nil {
+		log.Printf("failed writing lifecycle event: %v\n", err)
+	}
+}
+
+// AddEndpoints records the endpoints of a service deployed, written in the done event of the command deploying it
+func (e *LifecycleEvents) AddEndpoints(service string, endpoints []string) {
+	if !e.Enabled() {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.endpoints[service] = endpoints
+}
+
+// TakeEndpoints returns the endpoints recorded since the last call, nil when no service was deployed
+func (e *LifecycleEvents) TakeEndpoints() map[string][]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.endpoints) == 0 {
+		return nil
+	}
+
+	endpoints := e.endpoints
+	e.endpoints = map[string][]string{}
+	return endpoints
+}
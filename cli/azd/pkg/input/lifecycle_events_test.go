@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LifecycleEvents(t *testing.T) {
+	t.Run("Events", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		events := NewLifecycleEvents(NewConsole(true, false, buf, ConsoleHandles{}, &output.EventsFormatter{}))
+		events.now = func() time.Time {
+			return time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC)
+		}
+		require.True(t, events.Enabled())
+
+		events.Emit("provision", contracts.LifecyclePhaseStart, contracts.LifecycleEvent{})
+		events.Emit("service build", contracts.LifecyclePhaseProgress, contracts.LifecycleEvent{
+			Service: "api",
+			Message: "Building Docker image",
+			Percent: 40,
+		})
+
+		events.AddEndpoints("api", []string{"https://api.contoso.com/"})
+		require.Equal(t, map[string][]string{"api": {"https://api.contoso.com/"}}, events.TakeEndpoints())
+		require.Nil(t, events.TakeEndpoints())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Equal(t, []string{
+			`{"type":"provision.start","timestamp":"2023-10-11T00:00:00Z","data":{}}`,
+			`{"type":"service.build.progress","timestamp":"2023-10-11T00:00:00Z",` +
+				`"data":{"service":"api","message":"Building Docker image","percent":40}}`,
+		}, lines)
+	})
+
+	t.Run("Json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		events := NewLifecycleEvents(NewConsole(true, false, buf, ConsoleHandles{}, &output.JsonFormatter{}))
+		require.False(t, events.Enabled())
+
+		events.Emit("provision", contracts.LifecyclePhaseStart, contracts.LifecycleEvent{})
+		events.AddEndpoints("api", []string{"https://api.contoso.com/"})
+		require.Nil(t, events.TakeEndpoints())
+		require.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,230 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// Progress displays the progress of the steps of a command. Steps run one at a time: starting a step while another
+// is running replaces the running step.
+//
+// Use NewProgress to create the display matching the console: a spinner in terminals, which also reports the progress
+// to the terminal with OSC 9;4 sequences, plain lines in logs and CI, or progress events with `--output json` and
+// `--output events`.
+type Progress interface {
+	// Starts displaying the step with the specified title. (ex. `Deploying service api`)
+	Start(ctx context.Context, title string)
+	// Reports the latest progress message of the running step, and its completion percentage between 1 and 100.
+	// A percentage of 0 reports the step without a known completion.
+	Report(ctx context.Context, message string, percent int)
+	// Stops displaying the running step with its final message and result.
+	Stop(ctx context.Context, message string, format SpinnerUxType)
+}
+
+// NewProgress creates the progress display of the console.
+func NewProgress(console Console) Progress {
+	if output.IsEventStream(console.GetFormatter()) {
+		return &jsonProgress{writer: console.GetWriter(), now: time.Now}
+	}
+
+	if console.IsSpinnerInteractive() {
+		return &terminalProgress{console: console, osc: supportsProgressSequences()}
+	}
+
+	return &logProgress{console: console}
+}
+
+// The states of OSC 9;4 progress sequences
+const (
+	oscProgressRemove        = 0
+	oscProgressValue         = 1
+	oscProgressIndeterminate = 3
+)
+
+// Determines if the terminal may receive OSC 9;4 progress sequences, ignored by the terminals not rendering them.
+// iTerm2 displays OSC 9 sequences as notifications, and dumb terminals print them.
+func supportsProgressSequences() bool {
+	return os.Getenv("TERM") != "dumb" && os.Getenv("TERM_PROGRAM") != "iTerm.app"
+}
+
+// terminalProgress displays the running step in a spinner, and reports its progress in the tab or taskbar of the
+// terminal.
+type terminalProgress struct {
+	console Console
+	osc     bool
+
+	mu    sync.Mutex
+	title string
+}
+
+func (p *terminalProgress) Start(ctx context.Context, title string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.title = title
+	p.console.ShowSpinner(ctx, title, Step)
+	p.setProgress(oscProgressIndeterminate, 0)
+}
+
+func (p *terminalProgress) Report(ctx context.Context, message string, percent int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	title := p.title
+	if message != "" {
+		title = fmt.Sprintf("%s (%s)", p.title, message)
+	}
+
+	p.console.ShowSpinner(ctx, title, Step)
+	if percent > 0 {
+		p.setProgress(oscProgressValue, percent)
+	} else {
+		p.setProgress(oscProgressIndeterminate, 0)
+	}
+}
+
+func (p *terminalProgress) Stop(ctx context.Context, message string, format SpinnerUxType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.title = ""
+	p.console.StopSpinner(ctx, message, format)
+	p.setProgress(oscProgressRemove, 0)
+}
+
+// Writes the OSC 9;4 sequence setting the progress state of the terminal
+func (p *terminalProgress) setProgress(state int, percent int) {
+	if p.osc {
+		fmt.Fprintf(p.console.GetWriter(), "\x1b]9;4;%d;%d\x07", state, percent)
+	}
+}
+
+// The completion percentages displayed by logProgress, other percentages would flood the logs
+const logProgressPercentStep = 25
+
+// logProgress writes the steps as lines, for logs and CI systems that don't render spinners. A line is written when a
+// step starts and stops, and when its progress message changes; percentages are only written every 25%.
+type logProgress struct {
+	console Console
+
+	mu          sync.Mutex
+	title       string
+	message     string
+	lastPercent int
+}
+
+func (p *logProgress) Start(ctx context.Context, title string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if title == p.title {
+		return
+	}
+
+	p.title = title
+	p.message = ""
+	p.lastPercent = 0
+	p.console.Message(ctx, title)
+}
+
+func (p *logProgress) Report(ctx context.Context, message string, percent int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if percent > 0 {
+		if percent < 100 && percent/logProgressPercentStep == p.lastPercent/logProgressPercentStep {
+			return
+		}
+
+		p.lastPercent = percent
+	} else if message == p.message {
+		return
+	}
+
+	p.message = message
+	p.console.Message(ctx, fmt.Sprintf("%s: %s", p.title, message))
+}
+
+func (p *logProgress) Stop(ctx context.Context, message string, format SpinnerUxType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.title = ""
+	p.message = ""
+	p.lastPercent = 0
+	if message != "" {
+		p.console.Message(ctx, fmt.Sprintf("%s %s", stepResultPrefix(format), message))
+	}
+}
+
+// jsonProgress writes a progress event each time a step starts, reports progress and stops.
+type jsonProgress struct {
+	writer io.Writer
+	now    func() time.Time
+
+	mu    sync.Mutex
+	title string
+}
+
+func (p *jsonProgress) Start(ctx context.Context, title string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.title = title
+	p.write(contracts.Progress{Title: title, Status: contracts.ProgressStatusRunning})
+}
+
+func (p *jsonProgress) Report(ctx context.Context, message string, percent int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.write(contracts.Progress{
+		Title:   p.title,
+		Message: message,
+		Percent: percent,
+		Status:  contracts.ProgressStatusRunning,
+	})
+}
+
+func (p *jsonProgress) Stop(ctx context.Context, message string, format SpinnerUxType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := contracts.ProgressStatusDone
+	switch format {
+	case StepFailed:
+		status = contracts.ProgressStatusFailed
+	case StepWarning:
+		status = contracts.ProgressStatusWarning
+	case StepSkipped:
+		status = contracts.ProgressStatusSkipped
+	}
+
+	p.write(contracts.Progress{Title: p.title, Message: message, Status: status})
+	p.title = ""
+}
+
+// Writes the event on a single line, like the other events of the console
+func (p *jsonProgress) write(progress contracts.Progress) {
+	event := contracts.EventEnvelope{
+		Type:      contracts.ProgressEventDataType,
+		Timestamp: p.now(),
+		Data:      progress,
+	}
+
+	if err := json.NewEncoder(p.writer).Encode(event); err != nil {
+		log.Printf("failed writing progress event: %v\n", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewProgress(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	console := NewConsole(true, false, buf, ConsoleHandles{}, nil)
+	require.IsType(t, &logProgress{}, NewProgress(console))
+
+	console = NewConsole(true, false, buf, ConsoleHandles{}, &output.JsonFormatter{})
+	require.IsType(t, &jsonProgress{}, NewProgress(console))
+}
+
+func Test_logProgress(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	progress := NewProgress(NewConsole(true, false, buf, ConsoleHandles{}, nil))
+
+	progress.Start(ctx, "Deploying service api")
+	progress.Start(ctx, "Deploying service api")
+	progress.Report(ctx, "Tagging container image", 0)
+	progress.Report(ctx, "Tagging container image", 0)
+	for _, percent := range []int{5, 10, 30, 45, 60, 80, 95, 100} {
+		progress.Report(ctx, "Uploading", percent)
+	}
+	progress.Stop(ctx, "Deploying service api", StepDone)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{
+		"Deploying service api",
+		"Deploying service api: Tagging container image",
+		// 30%, 60%, 80% and 100%
+		"Deploying service api: Uploading",
+		"Deploying service api: Uploading",
+		"Deploying service api: Uploading",
+		"Deploying service api: Uploading",
+		donePrefix + " Deploying service api",
+	}, lines)
+}
+
+func Test_jsonProgress(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	progress := &jsonProgress{
+		writer: buf,
+		now:    func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+
+	progress.Start(ctx, "Deploying service api")
+	progress.Report(ctx, "Uploading", 40)
+	progress.Stop(ctx, "Deploying service api", StepFailed)
+
+	events := []contracts.Progress{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var event struct {
+			Type contracts.EventDataType `json:"type"`
+			Data contracts.Progress      `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		require.Equal(t, contracts.ProgressEventDataType, event.Type)
+		events = append(events, event.Data)
+	}
+
+	require.Equal(t, []contracts.Progress{
+		{Title: "Deploying service api", Status: contracts.ProgressStatusRunning},
+		{Title: "Deploying service api", Message: "Uploading", Percent: 40, Status: contracts.ProgressStatusRunning},
+		{Title: "Deploying service api", Message: "Deploying service api", Status: contracts.ProgressStatusFailed},
+	}, events)
+}
+
+func Test_terminalProgress_sequences(t *testing.T) {
+	buf := &bytes.Buffer{}
+	progress := &terminalProgress{console: NewConsole(true, false, buf, ConsoleHandles{}, nil), osc: true}
+
+	progress.setProgress(oscProgressValue, 40)
+	progress.setProgress(oscProgressRemove, 0)
+	require.Equal(t, "\x1b]9;4;1;40\x07\x1b]9;4;0;0\x07", buf.String())
+
+	buf.Reset()
+	progress.osc = false
+	progress.setProgress(oscProgressIndeterminate, 0)
+	require.Empty(t, buf.String())
+}
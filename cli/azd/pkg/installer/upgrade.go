@@ -0,0 +1,463 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package installer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The release channels azd is published to
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+	ChannelDaily  = "daily"
+)
+
+// The location azd releases are published to, each channel publishes to a folder of its own
+const defaultDownloadBaseUrl = "https://azuresdkartifacts.z5.web.core.windows.net/azd/standalone"
+
+var channelFolders = map[string]string{
+	ChannelStable: "release/latest",
+	ChannelBeta:   "release/beta",
+	ChannelDaily:  "daily",
+}
+
+// ErrPackageManagerInstall is returned when azd was installed by a package manager, which owns its upgrades
+var ErrPackageManagerInstall = errors.New("azd was installed by a package manager")
+
+// ErrNoPreviousVersion is returned when rolling back before azd was upgraded by `azd upgrade`
+var ErrNoPreviousVersion = errors.New("no previous version of azd to roll back to")
+
+// Channels returns the release channels azd can be upgraded from
+func Channels() []string {
+	return []string{ChannelStable, ChannelBeta, ChannelDaily}
+}
+
+// PackageManagerUpgradeCommand returns the command upgrading azd when it was installed by a package manager
+func PackageManagerUpgradeCommand(installedBy InstallType) (string, bool) {
+	switch installedBy {
+	case InstallTypeBrew:
+		return "brew upgrade azd", true
+	case InstallTypeWinget:
+		return "winget upgrade Microsoft.Azd", true
+	case InstallTypeChoco:
+		return "choco upgrade azd", true
+	case InstallTypeDeb:
+		return "sudo apt-get install --only-upgrade azd", true
+	case InstallTypeRpm:
+		return "sudo yum update azd", true
+	default:
+		return "", false
+	}
+}
+
+// UpgradeResult describes the version of azd installed by an upgrade or rollback
+type UpgradeResult struct {
+	PreviousVersion string
+	Version         string
+	// False when the installed version is already the latest version of the channel
+	Upgraded bool
+}
+
+// Upgrader replaces the running azd executable with a release of a channel, keeping a backup of the replaced
+// executable so the upgrade can be rolled back
+type Upgrader struct {
+	httpClient    httputil.HttpClient
+	commandRunner exec.CommandRunner
+	baseUrl       string
+	goos          string
+	goarch        string
+	installedBy   InstallType
+	// The path of the azd executable, resolved when empty
+	exePath string
+	// The directory of the backup, ~/.azd/upgrade when empty
+	backupDir string
+}
+
+// NewUpgrader creates an upgrader for the running azd executable
+func NewUpgrader(httpClient httputil.HttpClient, commandRunner exec.CommandRunner) *Upgrader {
+	return &Upgrader{
+		httpClient:    httpClient,
+		commandRunner: commandRunner,
+		baseUrl:       defaultDownloadBaseUrl,
+		goos:          runtime.GOOS,
+		goarch:        runtime.GOARCH,
+		installedBy:   InstalledBy(),
+	}
+}
+
+// Upgrade installs the latest release of the channel in place of the running executable. The checksum and the code
+// signature of the release are verified before the executable is replaced.
+func (u *Upgrader) Upgrade(ctx context.Context, channel string, force bool) (*UpgradeResult, error) {
+	if command, has := PackageManagerUpgradeCommand(u.installedBy); has {
+		return nil, fmt.Errorf("%w (%s), run `%s` to upgrade it", ErrPackageManagerInstall, u.installedBy, command)
+	}
+
+	folder, has := channelFolders[channel]
+	if !has {
+		return nil, fmt.Errorf(
+			"channel '%s' is not supported, supported channels: %s", channel, strings.Join(Channels(), ", "))
+	}
+
+	channelUrl := fmt.Sprintf("%s/%s", u.baseUrl, folder)
+	currentVersion := internal.VersionInfo().Version.String()
+
+	versionBytes, err := u.read(ctx, channelUrl+"/version.txt")
+	if err != nil {
+		return nil, fmt.Errorf("getting the latest version of the %s channel: %w", channel, err)
+	}
+
+	version := strings.TrimSpace(string(versionBytes))
+	if version == currentVersion && !force {
+		return &UpgradeResult{PreviousVersion: currentVersion, Version: version}, nil
+	}
+
+	archiveName := u.archiveName()
+	archive, err := u.read(ctx, fmt.Sprintf("%s/%s", channelUrl, archiveName))
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", archiveName, err)
+	}
+
+	checksum, err := u.read(ctx, fmt.Sprintf("%s/%s.sha256", channelUrl, archiveName))
+	if err != nil {
+		return nil, fmt.Errorf("downloading the checksum of %s: %w", archiveName, err)
+	}
+
+	if err := verifyChecksum(archive, string(checksum)); err != nil {
+		return nil, fmt.Errorf("verifying %s: %w", archiveName, err)
+	}
+
+	binary, err := extractBinary(archiveName, archive, u.binaryName())
+	if err != nil {
+		return nil, fmt.Errorf("extracting %s: %w", archiveName, err)
+	}
+
+	exePath, err := u.executablePath()
+	if err != nil {
+		return nil, err
+	}
+
+	// The release is written next to the executable so it can be renamed in place
+	stagedPath, err := writeStaged(exePath, binary)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(stagedPath)
+
+	if err := u.verifySignature(ctx, stagedPath); err != nil {
+		return nil, fmt.Errorf("verifying the signature of version %s: %w", version, err)
+	}
+
+	if err := u.backup(exePath, currentVersion); err != nil {
+		return nil, fmt.Errorf("backing up version %s: %w", currentVersion, err)
+	}
+
+	if err := replaceExecutable(exePath, stagedPath); err != nil {
+		return nil, fmt.Errorf("replacing %s: %w", exePath, err)
+	}
+
+	return &UpgradeResult{PreviousVersion: currentVersion, Version: version, Upgraded: true}, nil
+}
+
+// Rollback restores the executable replaced by the last upgrade
+func (u *Upgrader) Rollback(ctx context.Context) (*UpgradeResult, error) {
+	backupDir, err := u.backupDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	backupPath := filepath.Join(backupDir, u.backupName())
+	binary, err := os.ReadFile(backupPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoPreviousVersion
+	} else if err != nil {
+		return nil, fmt.Errorf("reading the previous version: %w", err)
+	}
+
+	previousVersion, err := os.ReadFile(filepath.Join(backupDir, previousVersionFileName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading the previous version: %w", err)
+	}
+
+	exePath, err := u.executablePath()
+	if err != nil {
+		return nil, err
+	}
+
+	stagedPath, err := writeStaged(exePath, binary)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(stagedPath)
+
+	if err := replaceExecutable(exePath, stagedPath); err != nil {
+		return nil, fmt.Errorf("replacing %s: %w", exePath, err)
+	}
+
+	// The backup is consumed, a rollback can't be rolled back
+	if err := os.RemoveAll(backupDir); err != nil {
+		return nil, fmt.Errorf("removing the backup: %w", err)
+	}
+
+	return &UpgradeResult{
+		PreviousVersion: internal.VersionInfo().Version.String(),
+		Version:         strings.TrimSpace(string(previousVersion)),
+		Upgraded:        true,
+	}, nil
+}
+
+const previousVersionFileName = "previous-version"
+
+// Archives are zip files on Windows & macOS and tar.gz files on Linux, ex. azd-linux-amd64.tar.gz
+func (u *Upgrader) archiveName() string {
+	extension := ".zip"
+	if u.goos == "linux" {
+		extension = ".tar.gz"
+	}
+
+	return fmt.Sprintf("azd-%s-%s%s", u.goos, u.goarch, extension)
+}
+
+func (u *Upgrader) binaryName() string {
+	return fmt.Sprintf("azd-%s-%s%s", u.goos, u.goarch, u.exeSuffix())
+}
+
+func (u *Upgrader) backupName() string {
+	return "azd-previous" + u.exeSuffix()
+}
+
+func (u *Upgrader) exeSuffix() string {
+	if u.goos == "windows" {
+		return ".exe"
+	}
+
+	return ""
+}
+
+func (u *Upgrader) executablePath() (string, error) {
+	if u.exePath != "" {
+		return u.exePath, nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding the azd executable: %w", err)
+	}
+
+	return filepath.EvalSymlinks(exePath)
+}
+
+func (u *Upgrader) backupDirectory() (string, error) {
+	if u.backupDir != "" {
+		return u.backupDir, nil
+	}
+
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "upgrade"), nil
+}
+
+// Copies the executable and its version to the backup directory
+func (u *Upgrader) backup(exePath string, version string) error {
+	backupDir, err := u.backupDirectory()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(backupDir, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	binary, err := os.ReadFile(exePath)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(backupDir, u.backupName()), binary, osutil.PermissionExecutableFile)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(backupDir, previousVersionFileName), []byte(version), osutil.PermissionFile)
+}
+
+// Verifies the Authenticode signature on Windows and the code signature on macOS.
+// Linux releases aren't code signed, their checksum is verified.
+func (u *Upgrader) verifySignature(ctx context.Context, path string) error {
+	switch u.goos {
+	case "windows":
+		res, err := u.commandRunner.Run(ctx, exec.NewRunArgs(
+			"powershell", "-NoProfile", "-Command",
+			fmt.Sprintf("(Get-AuthenticodeSignature -FilePath '%s').Status", path)))
+		if err != nil {
+			return err
+		}
+
+		if status := strings.TrimSpace(res.Stdout); status != "Valid" {
+			return fmt.Errorf("the Authenticode signature is not valid: %s", status)
+		}
+
+		return nil
+	case "darwin":
+		if _, err := u.commandRunner.Run(ctx, exec.NewRunArgs("codesign", "--verify", "--strict", path)); err != nil {
+			return fmt.Errorf("the code signature is not valid: %w", err)
+		}
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (u *Upgrader) read(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", internal.UserAgent())
+
+	res, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned status %d", url, res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// Checksum files contain the hex encoded SHA-256 of the archive, optionally followed by the file name
+func verifyChecksum(archive []byte, checksumFile string) error {
+	fields := strings.Fields(checksumFile)
+	if len(fields) == 0 {
+		return errors.New("the checksum file is empty")
+	}
+
+	sum := sha256.Sum256(archive)
+	if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+		return errors.New("the checksum doesn't match")
+	}
+
+	return nil
+}
+
+// Reads the azd binary from the .zip or .tar.gz archive of a release
+func extractBinary(archiveName string, archive []byte, binaryName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range zipReader.File {
+			if filepath.Base(file.Name) != binaryName {
+				continue
+			}
+
+			fileReader, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer fileReader.Close()
+
+			/* #nosec G110 - decompression bomb false positive */
+			return io.ReadAll(fileReader)
+		}
+	} else {
+		gzReader, err := gzip.NewReader(bytes.NewReader(archive))
+		if err != nil {
+			return nil, err
+		}
+		defer gzReader.Close()
+
+		tarReader := tar.NewReader(gzReader)
+		for {
+			header, err := tarReader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+
+			// cspell: disable-next-line `Typeflag` is comming fron *tar.Header
+			if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == binaryName {
+				/* #nosec G110 - decompression bomb false positive */
+				return io.ReadAll(tarReader)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("the archive doesn't contain %s", binaryName)
+}
+
+// Writes the binary to a temporary file in the directory of the executable
+func writeStaged(exePath string, binary []byte) (string, error) {
+	file, err := os.CreateTemp(filepath.Dir(exePath), ".azd-upgrade-*")
+	if err != nil {
+		return "", fmt.Errorf("writing the new version next to %s: %w", exePath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(binary); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	if err := file.Chmod(osutil.PermissionExecutableFile); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// Renames the staged executable over the executable. Windows doesn't allow replacing a running executable but allows
+// renaming it, the previous executable is moved aside first.
+func replaceExecutable(exePath string, stagedPath string) error {
+	if runtime.GOOS == "windows" {
+		oldPath := exePath + ".old"
+		_ = os.Remove(oldPath)
+
+		if err := os.Rename(exePath, oldPath); err != nil {
+			return err
+		}
+
+		if err := os.Rename(stagedPath, exePath); err != nil {
+			_ = os.Rename(oldPath, exePath)
+			return err
+		}
+
+		return nil
+	}
+
+	return os.Rename(stagedPath, exePath)
+}
@@ -0,0 +1,126 @@
+package installer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func createReleaseArchive(t *testing.T, binaryName string, contents string) []byte {
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+		Name:     binaryName,
+		Mode:     0755,
+		Size:     int64(len(contents)),
+		Typeflag: tar.TypeReg,
+	}))
+	_, err := tarWriter.Write([]byte(contents))
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzWriter.Close())
+
+	return buf.Bytes()
+}
+
+func Test_Upgrader(t *testing.T) {
+	archive := createReleaseArchive(t, "azd-linux-amd64", "new azd")
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:]) + "  azd-linux-amd64.tar.gz"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release/beta/version.txt":
+			_, _ = w.Write([]byte("1.5.0-beta.1\n"))
+		case "/release/beta/azd-linux-amd64.tar.gz":
+			_, _ = w.Write(archive)
+		case "/release/beta/azd-linux-amd64.tar.gz.sha256":
+			_, _ = w.Write([]byte(checksum))
+		case "/daily/version.txt":
+			_, _ = w.Write([]byte("1.5.0-daily.1"))
+		case "/daily/azd-linux-amd64.tar.gz":
+			_, _ = w.Write(archive)
+		case "/daily/azd-linux-amd64.tar.gz.sha256":
+			_, _ = w.Write([]byte("0000"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	newUpgrader := func(t *testing.T) *Upgrader {
+		exePath := filepath.Join(t.TempDir(), "azd")
+		require.NoError(t, os.WriteFile(exePath, []byte("old azd"), osutil.PermissionExecutableFile))
+
+		return &Upgrader{
+			httpClient: server.Client(),
+			baseUrl:    server.URL,
+			goos:       "linux",
+			goarch:     "amd64",
+			exePath:    exePath,
+			backupDir:  filepath.Join(t.TempDir(), "upgrade"),
+		}
+	}
+
+	t.Run("UpgradeAndRollback", func(t *testing.T) {
+		upgrader := newUpgrader(t)
+
+		result, err := upgrader.Upgrade(context.Background(), ChannelBeta, false)
+		require.NoError(t, err)
+		require.True(t, result.Upgraded)
+		require.Equal(t, "1.5.0-beta.1", result.Version)
+
+		contents, err := os.ReadFile(upgrader.exePath)
+		require.NoError(t, err)
+		require.Equal(t, "new azd", string(contents))
+
+		result, err = upgrader.Rollback(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Version)
+
+		contents, err = os.ReadFile(upgrader.exePath)
+		require.NoError(t, err)
+		require.Equal(t, "old azd", string(contents))
+
+		_, err = upgrader.Rollback(context.Background())
+		require.ErrorIs(t, err, ErrNoPreviousVersion)
+	})
+
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		upgrader := newUpgrader(t)
+
+		_, err := upgrader.Upgrade(context.Background(), ChannelDaily, false)
+		require.ErrorContains(t, err, "the checksum doesn't match")
+
+		contents, err := os.ReadFile(upgrader.exePath)
+		require.NoError(t, err)
+		require.Equal(t, "old azd", string(contents))
+	})
+
+	t.Run("PackageManager", func(t *testing.T) {
+		upgrader := newUpgrader(t)
+		upgrader.installedBy = InstallTypeBrew
+
+		_, err := upgrader.Upgrade(context.Background(), ChannelStable, false)
+		require.ErrorIs(t, err, ErrPackageManagerInstall)
+		require.ErrorContains(t, err, "brew upgrade azd")
+	})
+
+	t.Run("UnknownChannel", func(t *testing.T) {
+		_, err := newUpgrader(t).Upgrade(context.Background(), "nightly", false)
+		require.ErrorContains(t, err, "channel 'nightly' is not supported")
+	})
+}
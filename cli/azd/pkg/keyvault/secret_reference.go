@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package keyvault
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The scheme of the values referencing Key Vault secrets, resolved to the value of the secret when used
+const SecretReferenceScheme = "akvs://"
+
+// SecretReference references a Key Vault secret, as akvs://<vault>/<secret> or
+// akvs://<subscriptionId>/<vault>/<secret> when the vault is in another subscription than the environment
+type SecretReference struct {
+	// The subscription of the vault, empty for the subscription of the environment
+	SubscriptionId string
+	VaultName      string
+	SecretName     string
+}
+
+// IsSecretReference returns whether the value references a Key Vault secret
+func IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, SecretReferenceScheme)
+}
+
+// ParseSecretReference parses a akvs://[<subscriptionId>/]<vault>/<secret> reference
+func ParseSecretReference(value string) (*SecretReference, error) {
+	if !IsSecretReference(value) {
+		return nil, fmt.Errorf("'%s' is not a Key Vault secret reference, expected %s<vault>/<secret>",
+			value, SecretReferenceScheme)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(value, SecretReferenceScheme), "/")
+	for _, segment := range segments {
+		if segment == "" {
+			segments = nil
+			break
+		}
+	}
+
+	switch len(segments) {
+	case 2:
+		return &SecretReference{VaultName: segments[0], SecretName: segments[1]}, nil
+	case 3:
+		return &SecretReference{SubscriptionId: segments[0], VaultName: segments[1], SecretName: segments[2]}, nil
+	default:
+		return nil, fmt.Errorf(
+			"invalid Key Vault secret reference '%s', expected %s[<subscriptionId>/]<vault>/<secret>",
+			value, SecretReferenceScheme)
+	}
+}
+
+// subscription returns the subscription of the vault, or the default subscription when the reference has none
+func (r SecretReference) subscription(defaultSubscriptionId string) string {
+	if r.SubscriptionId != "" {
+		return r.SubscriptionId
+	}
+
+	return defaultSubscriptionId
+}
+
+func (r SecretReference) String() string {
+	if r.SubscriptionId != "" {
+		return fmt.Sprintf("%s%s/%s/%s", SecretReferenceScheme, r.SubscriptionId, r.VaultName, r.SecretName)
+	}
+
+	return fmt.Sprintf("%s%s/%s", SecretReferenceScheme, r.VaultName, r.SecretName)
+}
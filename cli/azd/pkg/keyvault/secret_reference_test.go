@@ -0,0 +1,31 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package keyvault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	reference, err := ParseSecretReference("akvs://kv-todo/db-password")
+	require.NoError(t, err)
+	require.Equal(t, SecretReference{VaultName: "kv-todo", SecretName: "db-password"}, *reference)
+	require.Equal(t, "akvs://kv-todo/db-password", reference.String())
+
+	reference, err = ParseSecretReference("akvs://SUBSCRIPTION_ID/kv-todo/db-password")
+	require.NoError(t, err)
+	require.Equal(t, SecretReference{
+		SubscriptionId: "SUBSCRIPTION_ID",
+		VaultName:      "kv-todo",
+		SecretName:     "db-password",
+	}, *reference)
+	require.Equal(t, "akvs://SUBSCRIPTION_ID/kv-todo/db-password", reference.String())
+
+	for _, invalid := range []string{"kv-todo/db-password", "akvs://kv-todo", "akvs://kv-todo//db-password"} {
+		_, err := ParseSecretReference(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package keyvault
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// SecretsProvider reads and writes the secrets referenced by the values of azd environments. References without a
+// subscription use the subscription specified to the provider, the subscription of the environment.
+type SecretsProvider interface {
+	// GetSecret returns the value of the referenced secret
+	GetSecret(ctx context.Context, subscriptionId string, reference SecretReference) (string, error)
+	// SetSecret sets the value of the referenced secret, adding a version when the secret already exists
+	SetSecret(ctx context.Context, subscriptionId string, reference SecretReference, value string) error
+}
+
+type keyVaultSecretsProvider struct {
+	azCli azcli.AzCli
+}
+
+// NewSecretsProvider creates a SecretsProvider storing secrets in Key Vault
+func NewSecretsProvider(azCli azcli.AzCli) SecretsProvider {
+	return &keyVaultSecretsProvider{
+		azCli: azCli,
+	}
+}
+
+func (p *keyVaultSecretsProvider) GetSecret(
+	ctx context.Context,
+	subscriptionId string,
+	reference SecretReference,
+) (string, error) {
+	secret, err := p.azCli.GetKeyVaultSecret(
+		ctx, reference.subscription(subscriptionId), reference.VaultName, reference.SecretName)
+	if err != nil {
+		return "", fmt.Errorf("reading secret '%s' from vault '%s': %w", reference.SecretName, reference.VaultName, err)
+	}
+
+	// GetKeyVaultSecret returns no secret when the vault can't be reached
+	if secret == nil {
+		return "", fmt.Errorf("reading secret '%s' from vault '%s'", reference.SecretName, reference.VaultName)
+	}
+
+	return secret.Value, nil
+}
+
+func (p *keyVaultSecretsProvider) SetSecret(
+	ctx context.Context,
+	subscriptionId string,
+	reference SecretReference,
+	value string,
+) error {
+	err := p.azCli.SetKeyVaultSecret(
+		ctx, reference.subscription(subscriptionId), reference.VaultName, reference.SecretName, value)
+	if err != nil {
+		return fmt.Errorf("writing secret '%s' to vault '%s': %w", reference.SecretName, reference.VaultName, err)
+	}
+
+	return nil
+}
+
+// ResolveSecrets resolves the values of the environment referencing secrets, ex. akvs://<vault>/<secret>. The values
+// of the secrets are set on the environment with [environment.Environment.SetSecret] so they are never saved to the
+// .env file. Secrets already resolved aren't read again.
+func ResolveSecrets(ctx context.Context, provider SecretsProvider, env *environment.Environment) error {
+	values := env.Dotenv()
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if IsSecretReference(value) && !env.HasSecret(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		reference, err := ParseSecretReference(values[key])
+		if err != nil {
+			return fmt.Errorf("resolving environment value '%s': %w", key, err)
+		}
+
+		log.Printf("resolving environment value '%s' from vault '%s'", key, reference.VaultName)
+		value, err := provider.GetSecret(ctx, env.GetSubscriptionId(), *reference)
+		if err != nil {
+			return fmt.Errorf("resolving environment value '%s': %w", key, err)
+		}
+
+		env.SetSecret(key, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// The status of a diagnostic
+const (
+	StatusPassed  = "passed"
+	StatusWarning = "warning"
+	StatusFailed  = "failed"
+)
+
+// Diagnostic is the result of validating a part of the network configuration
+type Diagnostic struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// The time a host has to accept a connection
+const dialTimeout = 5 * time.Second
+
+// Diagnose validates the network options: the DNS server answers, ARM & Microsoft Entra ID resolve to private
+// addresses when private endpoints are configured and accept connections. Nothing is validated in offline mode.
+func Diagnose(ctx context.Context, options *Options) []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	// No host can be reached in offline mode
+	if options.Offline {
+		return append(diagnostics, Diagnostic{
+			Name:    "offline mode",
+			Status:  StatusWarning,
+			Message: "Network calls are disabled, Azure can't be reached until network.offline is unset",
+		})
+	}
+
+	if options.AirGapped {
+		diagnostics = append(diagnostics, Diagnostic{
+			Name:   "air-gapped mode",
+			Status: StatusPassed,
+			Message: fmt.Sprintf("Only ARM, Microsoft Entra ID, Azure resources and %d allowed host(s) are called",
+				len(options.AllowedHosts)),
+		})
+	}
+
+	if options.DnsServer != "" {
+		lookupCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+		_, err := options.Resolver().LookupHost(lookupCtx, ArmHost)
+		cancel()
+
+		if err != nil {
+			diagnostics = append(diagnostics, Diagnostic{
+				Name:    "dns server",
+				Status:  StatusFailed,
+				Message: fmt.Sprintf("%s doesn't resolve %s: %v", options.DnsServer, ArmHost, err),
+			})
+		} else {
+			diagnostics = append(diagnostics, Diagnostic{
+				Name:    "dns server",
+				Status:  StatusPassed,
+				Message: fmt.Sprintf("%s resolves %s", options.DnsServer, ArmHost),
+			})
+		}
+	}
+
+	// Private endpoints are expected when the hosts are resolved by a custom DNS server or static addresses
+	expectPrivate := options.DnsServer != "" || len(options.Hosts) > 0
+	for _, host := range []string{ArmHost, AadHost} {
+		diagnostics = append(diagnostics, diagnoseHost(ctx, options, host, expectPrivate))
+	}
+
+	return diagnostics
+}
+
+func diagnoseHost(ctx context.Context, options *Options, host string, expectPrivate bool) Diagnostic {
+	addresses, err := options.LookupHost(ctx, host)
+	if err != nil || len(addresses) == 0 {
+		return Diagnostic{
+			Name:    host,
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("%s doesn't resolve: %v", host, err),
+		}
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addresses[0], "443"))
+	if err != nil {
+		return Diagnostic{
+			Name:    host,
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("%s (%s) is not reachable: %v", host, addresses[0], err),
+		}
+	}
+	conn.Close()
+
+	if expectPrivate {
+		if ip := net.ParseIP(addresses[0]); ip != nil && !ip.IsPrivate() {
+			return Diagnostic{
+				Name:   host,
+				Status: StatusWarning,
+				Message: fmt.Sprintf("%s resolves to the public address %s, a private endpoint isn't used",
+					host, addresses[0]),
+			}
+		}
+	}
+
+	return Diagnostic{
+		Name:    host,
+		Status:  StatusPassed,
+		Message: fmt.Sprintf("%s (%s) is reachable", host, strings.Join(addresses, ", ")),
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package network configures how azd reaches Azure from restricted networks. In air-gapped mode azd only calls
+// Azure Resource Manager, Microsoft Entra ID and the data plane endpoints of the deployed resources, optionally
+// resolving them with a custom DNS server or through private endpoints. In offline mode azd makes no network calls.
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// AirGappedEnvVar enables air-gapped mode, overriding the network.airGapped user config
+const AirGappedEnvVar = "AZD_AIR_GAPPED"
+
+// OfflineEnvVar enables offline mode, overriding the network.offline user config
+const OfflineEnvVar = "AZD_OFFLINE"
+
+// The user config section of the network options
+const configPath = "network"
+
+// The ARM & Microsoft Entra ID endpoints azd calls in every mode
+const (
+	ArmHost = "management.azure.com"
+	AadHost = "login.microsoftonline.com"
+)
+
+// The default transport of the process, before Configure replaces it
+var defaultTransport = http.DefaultTransport.(*http.Transport)
+
+// The hosts allowed in air-gapped mode: ARM, Microsoft Entra ID and the data plane of the resources azd deploys to.
+// Tool downloads, the update check, telemetry and template repositories are blocked.
+var defaultAllowedHosts = []string{
+	ArmHost,
+	AadHost,
+	"*.azurewebsites.net",
+	"*.azurecr.io",
+	"*.azurecontainerapps.io",
+	"*.vault.azure.net",
+	"*.core.windows.net",
+	"*.azmk8s.io",
+}
+
+// ErrAirGapped is returned for requests to hosts that aren't allowed in air-gapped mode
+var ErrAirGapped = errors.New("azd is in air-gapped mode")
+
+// ErrOffline is returned for requests sent, and operations requiring the network run, in offline mode
+var ErrOffline = errors.New("azd is in offline mode")
+
+// Options configure how azd reaches the network, set with `azd config set network.<name> <value>`
+type Options struct {
+	// Blocks calls to hosts other than ARM, Microsoft Entra ID, the data plane of Azure resources and AllowedHosts
+	AirGapped bool
+	// Blocks all the network calls, only loopback hosts are allowed. Disables telemetry and the update check.
+	Offline bool
+	// Extra hosts allowed in air-gapped mode, ex. a private template mirror. Supports wildcards, ex. *.contoso.com
+	AllowedHosts []string
+	// The DNS server resolving hosts, ex. 10.0.0.10:53. Resolves the private endpoints of a private DNS zone.
+	DnsServer string
+	// Static addresses of hosts, ex. the private endpoint of ARM: management.azure.com=10.0.0.4
+	Hosts map[string]string
+}
+
+// LoadOptions reads the network options of the user config. AZD_AIR_GAPPED overrides network.airGapped and
+// AZD_OFFLINE overrides network.offline.
+// Values set by `azd config set` are strings: lists are comma separated and hosts are host=address pairs.
+func LoadOptions(azdConfig config.Config) (*Options, error) {
+	options := &Options{Hosts: map[string]string{}}
+
+	if value, has := azdConfig.Get(configPath + ".airGapped"); has {
+		airGapped, err := strconv.ParseBool(fmt.Sprint(value))
+		if err != nil {
+			return nil, fmt.Errorf("network.airGapped must be true or false: %w", err)
+		}
+
+		options.AirGapped = airGapped
+	}
+
+	if value, has := os.LookupEnv(AirGappedEnvVar); has {
+		airGapped, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false: %w", AirGappedEnvVar, err)
+		}
+
+		options.AirGapped = airGapped
+	}
+
+	if value, has := azdConfig.Get(configPath + ".offline"); has {
+		offline, err := strconv.ParseBool(fmt.Sprint(value))
+		if err != nil {
+			return nil, fmt.Errorf("network.offline must be true or false: %w", err)
+		}
+
+		options.Offline = offline
+	}
+
+	if value, has := os.LookupEnv(OfflineEnvVar); has {
+		offline, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false: %w", OfflineEnvVar, err)
+		}
+
+		options.Offline = offline
+	}
+
+	if value, has := azdConfig.Get(configPath + ".allowedHosts"); has {
+		options.AllowedHosts = configList(value)
+	}
+
+	if value, has := azdConfig.Get(configPath + ".dnsServer"); has {
+		options.DnsServer = strings.TrimSpace(fmt.Sprint(value))
+		if _, _, err := net.SplitHostPort(options.DnsServer); err != nil {
+			// The port defaults to 53
+			options.DnsServer = net.JoinHostPort(options.DnsServer, "53")
+		}
+	}
+
+	if value, has := azdConfig.Get(configPath + ".hosts"); has {
+		pairs := map[string]string{}
+		if hosts, ok := value.(map[string]any); ok {
+			for host, address := range hosts {
+				pairs[host] = fmt.Sprint(address)
+			}
+		} else {
+			for _, pair := range configList(value) {
+				host, address, found := strings.Cut(pair, "=")
+				if !found {
+					return nil, fmt.Errorf("network.hosts entry '%s' must be host=address", pair)
+				}
+
+				pairs[host] = address
+			}
+		}
+
+		for host, address := range pairs {
+			if net.ParseIP(strings.TrimSpace(address)) == nil {
+				return nil, fmt.Errorf("network.hosts address '%s' of '%s' must be an IP address", address, host)
+			}
+
+			options.Hosts[strings.ToLower(strings.TrimSpace(host))] = strings.TrimSpace(address)
+		}
+	}
+
+	return options, nil
+}
+
+// Reads a list set as an array or a comma separated string
+func configList(value any) []string {
+	var items []string
+	if values, ok := value.([]any); ok {
+		for _, item := range values {
+			items = append(items, fmt.Sprint(item))
+		}
+	} else {
+		items = strings.Split(fmt.Sprint(value), ",")
+	}
+
+	list := []string{}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+var (
+	currentOnce sync.Once
+	current     *Options
+)
+
+// Current returns the network options of the user config, loaded once. Invalid options are logged and ignored.
+func Current() *Options {
+	currentOnce.Do(func() {
+		current = &Options{}
+
+		azdConfig, err := config.NewUserConfigManager().Load()
+		if err != nil {
+			log.Printf("failed loading network options: %v", err)
+			return
+		}
+
+		options, err := LoadOptions(azdConfig)
+		if err != nil {
+			log.Printf("ignoring network options: %v", err)
+			return
+		}
+
+		current = options
+	})
+
+	return current
+}
+
+// Allows returns whether requests to the host are allowed
+func (o *Options) Allows(host string) bool {
+	if o.Offline {
+		return isLoopback(host)
+	}
+
+	if !o.AirGapped {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	for _, pattern := range append(append([]string{}, defaultAllowedHosts...), o.AllowedHosts...) {
+		if matched, _ := path.Match(strings.ToLower(pattern), host); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether the host is the local machine, ex. the emulators of Azure services
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Customized returns whether the options change how azd reaches the network
+func (o *Options) Customized() bool {
+	return o.AirGapped || o.Offline || o.DnsServer != "" || len(o.Hosts) > 0
+}
+
+// Restricted returns whether azd only calls Azure, or nothing at all. Telemetry, the update check and template
+// repositories aren't reached in air-gapped and offline mode.
+func (o *Options) Restricted() bool {
+	return o.AirGapped || o.Offline
+}
+
+// Resolver returns the resolver of hosts, using the DNS server when set
+func (o *Options) Resolver() *net.Resolver {
+	if o.DnsServer == "" {
+		return net.DefaultResolver
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: 5 * time.Second}
+			return dialer.DialContext(ctx, network, o.DnsServer)
+		},
+	}
+}
+
+// LookupHost returns the addresses of the host, from the static hosts or the resolver
+func (o *Options) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if address, has := o.Hosts[strings.ToLower(host)]; has {
+		return []string{address}, nil
+	}
+
+	return o.Resolver().LookupHost(ctx, host)
+}
+
+// NewTransport returns a transport dialing hosts with the network options and rejecting requests to hosts that
+// aren't allowed in air-gapped mode
+func NewTransport(options *Options) http.RoundTripper {
+	transport := defaultTransport.Clone()
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Resolver: options.Resolver()}
+
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if static, has := options.Hosts[strings.ToLower(host)]; has {
+			address = net.JoinHostPort(static, port)
+		}
+
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	return &transportPolicy{inner: transport, options: options}
+}
+
+type transportPolicy struct {
+	inner   http.RoundTripper
+	options *Options
+}
+
+func (t *transportPolicy) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.options.Allows(req.URL.Hostname()) && t.options.Offline {
+		return nil, fmt.Errorf(
+			"%w, requests to %s are blocked. Disable offline mode with `azd config unset network.offline`",
+			ErrOffline, req.URL.Hostname())
+	}
+
+	if !t.options.Allows(req.URL.Hostname()) {
+		return nil, fmt.Errorf(
+			"%w, requests to %s are blocked. Allow the host with `azd config set network.allowedHosts`, "+
+				"or disable air-gapped mode with `azd config set network.airGapped false`",
+			ErrAirGapped, req.URL.Hostname())
+	}
+
+	return t.inner.RoundTrip(req)
+}
+
+// Configure routes the requests of the default http transport, used by azd and the Azure SDK, through the network
+// options
+func Configure(options *Options) {
+	if options.Customized() {
+		http.DefaultTransport = NewTransport(options)
+	}
+}
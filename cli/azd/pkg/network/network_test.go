@@ -0,0 +1,133 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadOptions(t *testing.T) {
+	t.Run("ConfigSetStrings", func(t *testing.T) {
+		azdConfig := config.NewConfig(map[string]any{
+			"network": map[string]any{
+				"airGapped":    "true",
+				"allowedHosts": "templates.contoso.com, *.contoso.net",
+				"dnsServer":    "10.0.0.10",
+				"hosts":        "Management.Azure.com=10.0.0.4",
+			},
+		})
+
+		options, err := LoadOptions(azdConfig)
+		require.NoError(t, err)
+		require.Equal(t, &Options{
+			AirGapped:    true,
+			AllowedHosts: []string{"templates.contoso.com", "*.contoso.net"},
+			DnsServer:    "10.0.0.10:53",
+			Hosts:        map[string]string{"management.azure.com": "10.0.0.4"},
+		}, options)
+	})
+
+	t.Run("EnvironmentOverride", func(t *testing.T) {
+		t.Setenv(AirGappedEnvVar, "false")
+
+		options, err := LoadOptions(config.NewConfig(map[string]any{
+			"network": map[string]any{"airGapped": true},
+		}))
+		require.NoError(t, err)
+		require.False(t, options.AirGapped)
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		options, err := LoadOptions(config.NewConfig(map[string]any{
+			"network": map[string]any{"offline": "true"},
+		}))
+		require.NoError(t, err)
+		require.True(t, options.Offline)
+		require.True(t, options.Restricted())
+
+		t.Setenv(OfflineEnvVar, "false")
+		options, err = LoadOptions(config.NewConfig(map[string]any{
+			"network": map[string]any{"offline": true},
+		}))
+		require.NoError(t, err)
+		require.False(t, options.Offline)
+	})
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		_, err := LoadOptions(config.NewConfig(map[string]any{
+			"network": map[string]any{"hosts": "management.azure.com=private"},
+		}))
+		require.ErrorContains(t, err, "must be an IP address")
+	})
+}
+
+func Test_Options_Allows(t *testing.T) {
+	options := &Options{AirGapped: true, AllowedHosts: []string{"*.contoso.com"}}
+
+	require.True(t, options.Allows(ArmHost))
+	require.True(t, options.Allows("LOGIN.microsoftonline.com"))
+	require.True(t, options.Allows("app.scm.azurewebsites.net"))
+	require.True(t, options.Allows("templates.contoso.com"))
+	require.False(t, options.Allows("aka.ms"))
+	require.False(t, options.Allows("downloads.bicep.azure.com"))
+
+	require.True(t, (&Options{}).Allows("aka.ms"))
+
+	offline := &Options{Offline: true, AllowedHosts: []string{"*.contoso.com"}}
+	require.False(t, offline.Allows(ArmHost))
+	require.False(t, offline.Allows("templates.contoso.com"))
+	require.True(t, offline.Allows("localhost"))
+	require.True(t, offline.Allows("127.0.0.1"))
+}
+
+func Test_NewTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Run("StaticHosts", func(t *testing.T) {
+		// The private endpoint of the host is the test server
+		client := &http.Client{Transport: NewTransport(&Options{
+			AirGapped:    true,
+			Hosts:        map[string]string{"private.contoso.com": "127.0.0.1"},
+			AllowedHosts: []string{"private.contoso.com"},
+		})}
+
+		serverUrl, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		req, err := http.NewRequestWithContext(
+			context.Background(), http.MethodGet, "http://private.contoso.com:"+serverUrl.Port(), nil)
+		require.NoError(t, err)
+
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		client := &http.Client{Transport: NewTransport(&Options{AirGapped: true})}
+
+		_, err := client.Get(server.URL)
+		require.ErrorIs(t, err, ErrAirGapped)
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		client := &http.Client{Transport: NewTransport(&Options{Offline: true})}
+
+		res, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		_, err = client.Get("https://" + ArmHost)
+		require.ErrorIs(t, err, ErrOffline)
+	})
+}
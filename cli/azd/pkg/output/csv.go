@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"text/template"
+)
+
+// CsvFormatter writes rows as comma separated values. The columns are described with the same options as the
+// TableFormatter, so commands supporting table output can support CSV output with the same options.
+type CsvFormatter struct {
+}
+
+func (f *CsvFormatter) Kind() Format {
+	return CsvFormat
+}
+
+func (f *CsvFormatter) Format(obj interface{}, writer io.Writer, opts interface{}) error {
+	options, ok := opts.(TableFormatterOptions)
+	if !ok {
+		return errors.New("invalid formatter options, TableFormatterOptions expected")
+	}
+
+	if len(options.Columns) == 0 {
+		return errors.New("no columns were defined, csv format is not supported for this command")
+	}
+
+	rows, err := convertToSlice(obj)
+	if err != nil {
+		return err
+	}
+
+	headings := []string{}
+	templates := []*template.Template{}
+
+	for _, c := range options.Columns {
+		headings = append(headings, c.Heading)
+
+		t, err := template.New(c.Heading).Parse(c.ValueTemplate)
+		if err != nil {
+			return err
+		}
+		templates = append(templates, t)
+	}
+
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(headings); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(templates))
+		for i, t := range templates {
+			buf := bytes.Buffer{}
+			if err := t.Execute(&buf, row); err != nil {
+				return err
+			}
+
+			record[i] = buf.String()
+			if xfm := options.Columns[i].Transformer; xfm != nil {
+				record[i] = xfm(record[i])
+			}
+		}
+
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+var _ Formatter = (*CsvFormatter)(nil)
@@ -0,0 +1,34 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCsvFormatter(t *testing.T) {
+	obj := []tableInput{
+		{Size: "Large, very", IsCool: true},
+		{Size: "Small", IsCool: false},
+	}
+
+	formatter := &CsvFormatter{}
+	buffer := &bytes.Buffer{}
+	err := formatter.Format(obj, buffer, tableInputOptions)
+	require.NoError(t, err)
+
+	require.Equal(t, `Size,Coolness,Static,Lowered
+"Large, very",true,Some-Value,some-value
+Small,false,Some-Value,some-value
+`, buffer.String())
+}
+
+func TestCsvFormatterWithoutOptions(t *testing.T) {
+	formatter := &CsvFormatter{}
+	err := formatter.Format([]tableInput{}, &bytes.Buffer{}, nil)
+	require.Error(t, err)
+}
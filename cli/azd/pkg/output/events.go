@@ -0,0 +1,30 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// EventsFormatter writes objects as single lines of JSON, for the line-delimited stream of events of `--output events`
+// read by IDE extensions
+type EventsFormatter struct {
+}
+
+func (f *EventsFormatter) Kind() Format {
+	return EventsFormat
+}
+
+func (f *EventsFormatter) Format(obj interface{}, writer io.Writer, _ interface{}) error {
+	return json.NewEncoder(writer).Encode(obj)
+}
+
+var _ Formatter = (*EventsFormatter)(nil)
+
+// IsEventStream returns whether the console writes its messages and progress as JSON events, with `--output json` or
+// `--output events`
+func IsEventStream(formatter Formatter) bool {
+	return formatter != nil && (formatter.Kind() == JsonFormat || formatter.Kind() == EventsFormat)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsFormatter(t *testing.T) {
+	formatter := &EventsFormatter{}
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, formatter.Format(map[string]any{"type": "provision.start", "data": map[string]string{}}, buffer, nil))
+	require.NoError(t, formatter.Format(map[string]any{"type": "provision.done"}, buffer, nil))
+	require.Equal(t, "{\"data\":{},\"type\":\"provision.start\"}\n{\"type\":\"provision.done\"}\n", buffer.String())
+}
+
+func TestIsEventStream(t *testing.T) {
+	require.True(t, IsEventStream(&JsonFormatter{}))
+	require.True(t, IsEventStream(&EventsFormatter{}))
+	require.False(t, IsEventStream(&NoneFormatter{}))
+	require.False(t, IsEventStream(nil))
+}
@@ -15,6 +15,9 @@ const (
 	JsonFormat    Format = "json"
 	TableFormat   Format = "table"
 	NoneFormat    Format = "none"
+	CsvFormat     Format = "csv"
+	RawFormat     Format = "raw"
+	EventsFormat  Format = "events"
 )
 
 type Formatter interface {
@@ -30,6 +33,12 @@ func NewFormatter(format string) (Formatter, error) {
 		return &EnvVarsFormatter{}, nil
 	case string(TableFormat):
 		return &TableFormatter{}, nil
+	case string(CsvFormat):
+		return &CsvFormatter{}, nil
+	case string(RawFormat):
+		return &RawFormatter{}, nil
+	case string(EventsFormat):
+		return &EventsFormatter{}, nil
 	case string(NoneFormat):
 		return &NoneFormatter{}, nil
 	default:
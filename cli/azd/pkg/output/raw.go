@@ -0,0 +1,29 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// RawFormatter writes a string as is, followed by a new line, ex. for scripts capturing a single value
+type RawFormatter struct {
+}
+
+func (f *RawFormatter) Kind() Format {
+	return RawFormat
+}
+
+func (f *RawFormatter) Format(obj interface{}, writer io.Writer, _ interface{}) error {
+	value, ok := obj.(string)
+	if !ok {
+		return fmt.Errorf("RawFormatter can only format objects of type string")
+	}
+
+	_, err := fmt.Fprintln(writer, value)
+	return err
+}
+
+var _ Formatter = (*RawFormatter)(nil)
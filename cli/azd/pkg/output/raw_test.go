@@ -0,0 +1,23 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawFormatter(t *testing.T) {
+	formatter := &RawFormatter{}
+
+	buffer := &bytes.Buffer{}
+	err := formatter.Format("eyJ0eXAiOiJKV1Qi", buffer, nil)
+	require.NoError(t, err)
+	require.Equal(t, "eyJ0eXAiOiJKV1Qi\n", buffer.String())
+
+	err = formatter.Format(map[string]string{"key": "value"}, buffer, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+)
+
+// The directory of the infrastructure generated from the manifest of a .NET Aspire app host, relative to the project
+var appHostInfraDirectory = filepath.Join(".azure", "apphost", "infra")
+
+// appHostProject returns the project file of the service when the service is a .NET Aspire app host
+func appHostProject(serviceConfig *ServiceConfig) (string, bool) {
+	if serviceConfig.Host != ContainerAppTarget {
+		return "", false
+	}
+
+	switch serviceConfig.Language {
+	case ServiceLanguageDotNet, ServiceLanguageCsharp, ServiceLanguageFsharp:
+	default:
+		return "", false
+	}
+
+	projectFile, err := findProjectFile(serviceConfig.Name, serviceConfig.Path())
+	if err != nil {
+		return "", false
+	}
+
+	isAppHost, err := apphost.IsAppHostProject(projectFile)
+	if err != nil {
+		log.Printf("checking whether '%s' is an app host: %v", projectFile, err)
+		return "", false
+	}
+
+	return projectFile, isAppHost
+}
+
+// useAppHostInfra points the infrastructure of the project to the infrastructure generated from the manifest of its
+// app host, when the project has an app host and doesn't have an infrastructure directory of its own
+func useAppHostInfra(projectConfig *ProjectConfig) {
+	if _, err := os.Stat(filepath.Join(projectConfig.Path, projectConfig.Infra.Path)); err == nil {
+		return
+	}
+
+	for _, svc := range projectConfig.Services {
+		if _, isAppHost := appHostProject(svc); isAppHost {
+			projectConfig.Infra.Path = appHostInfraDirectory
+			return
+		}
+	}
+}
+
+// expandAppHosts replaces the app host services of the project with a service for each project of their manifest,
+// keeping the manifest the source of truth of the services. The infrastructure of the project is generated from the
+// manifest when the project uses the infrastructure of its app host.
+func (pm *projectManager) expandAppHosts(ctx context.Context, projectConfig *ProjectConfig) error {
+	for _, svc := range projectConfig.GetServicesStable() {
+		projectFile, isAppHost := appHostProject(svc)
+		if !isAppHost {
+			continue
+		}
+
+		log.Printf("generating manifest of app host '%s'", projectFile)
+		manifest, err := apphost.GenerateManifest(ctx, pm.dotnetCli, projectFile)
+		if err != nil {
+			return fmt.Errorf("service '%s': %w", svc.Name, err)
+		}
+
+		delete(projectConfig.Services, svc.Name)
+
+		for name, path := range apphost.ProjectPaths(manifest) {
+			if _, has := projectConfig.Services[name]; has {
+				return fmt.Errorf(
+					"project '%s' of app host '%s' has the name of a service of azure.yaml", name, svc.Name)
+			}
+
+			relativePath, err := filepath.Rel(projectConfig.Path, filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("project '%s' of app host '%s': %w", name, svc.Name, err)
+			}
+
+			projectConfig.Services[name] = &ServiceConfig{
+				Name:            name,
+				Host:            ContainerAppTarget,
+				Language:        ServiceLanguageDotNet,
+				RelativePath:    relativePath,
+				Project:         projectConfig,
+				EventDispatcher: ext.NewEventDispatcher[ServiceLifecycleEventArgs](),
+			}
+		}
+
+		if projectConfig.Infra.Path == appHostInfraDirectory {
+			infraPath := filepath.Join(projectConfig.Path, appHostInfraDirectory)
+			if err := apphost.GenerateInfra(manifest, filepath.Base(projectFile), infraPath); err != nil {
+				return fmt.Errorf("service '%s': %w", svc.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testAppHostManifest = `{
+  "resources": {
+    "apiservice": {
+      "type": "project.v0",
+      "path": "../AspireApp.ApiService/AspireApp.ApiService.csproj",
+      "bindings": {"http": {"scheme": "http", "protocol": "tcp", "transport": "http"}}
+    }
+  }
+}`
+
+func TestAppHostServices(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile := func(path string, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(contents), osutil.PermissionFile))
+	}
+
+	writeTestFile("azure.yaml", "name: aspire\nservices:\n  app:\n    project: ./AspireApp.AppHost\n"+
+		"    host: containerapp\n    language: dotnet\n")
+	writeTestFile("AspireApp.AppHost/AspireApp.AppHost.csproj",
+		"<Project><PropertyGroup><IsAspireHost>true</IsAspireHost></PropertyGroup></Project>")
+	writeTestFile("AspireApp.ApiService/AspireApp.ApiService.csproj", "<Project></Project>")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "dotnet run") && strings.Contains(command, "--publisher manifest")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		manifestPath := args.Args[len(args.Args)-1]
+		require.NoError(t, os.WriteFile(manifestPath, []byte(testAppHostManifest), osutil.PermissionFile))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	projectConfig, err := Load(*mockContext.Context, filepath.Join(dir, "azure.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, appHostInfraDirectory, projectConfig.Infra.Path)
+
+	pm := &projectManager{dotnetCli: dotnet.NewDotNetCli(mockContext.CommandRunner)}
+	require.NoError(t, pm.expandAppHosts(*mockContext.Context, projectConfig))
+
+	require.Len(t, projectConfig.Services, 1)
+	api := projectConfig.Services["apiservice"]
+	require.Equal(t, ContainerAppTarget, api.Host)
+	require.Equal(t, ServiceLanguageDotNet, api.Language)
+	require.Equal(t, filepath.Join(dir, "AspireApp.ApiService"), api.Path())
+
+	require.FileExists(t, filepath.Join(dir, appHostInfraDirectory, "main.bicep"))
+	require.FileExists(t, filepath.Join(dir, appHostInfraDirectory, "resources.bicep"))
+
+	// Projects with their own infrastructure keep it
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "infra"), osutil.PermissionDirectory))
+	projectConfig, err = Load(*mockContext.Context, filepath.Join(dir, "azure.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "infra", projectConfig.Infra.Path)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/doctor"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
@@ -36,16 +37,32 @@ func NewContainerHelper(
 	}
 }
 
-func (ch *ContainerHelper) RegistryName(ctx context.Context) (string, error) {
-	loginServer, has := ch.env.LookupEnv(environment.ContainerRegistryEndpointEnvVarName)
-	if !has {
-		return "", fmt.Errorf(
-			"could not determine container registry endpoint, ensure %s is set as an output of your infrastructure",
-			environment.ContainerRegistryEndpointEnvVarName,
-		)
+// RegistryName returns the login server and namespace the images of the service are pushed to, the registry of the
+// environment unless the service selects another registry
+func (ch *ContainerHelper) RegistryName(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
+	registry, err := ch.registry(serviceConfig)
+	if err != nil {
+		return "", err
 	}
 
-	return loginServer, nil
+	return registry.Endpoint()
+}
+
+// RegistryCheck validates the container registry the images of the service are pushed to is known, it is an output
+// of the infrastructure of the environment
+func (ch *ContainerHelper) RegistryCheck(serviceConfig *ServiceConfig) doctor.Check {
+	return doctor.NewCheck("container registry", func(ctx context.Context) []doctor.Result {
+		loginServer, err := ch.RegistryName(ctx, serviceConfig)
+		if err != nil {
+			return []doctor.Result{{Name: serviceConfig.Name, Status: doctor.StatusFailed, Message: err.Error()}}
+		}
+
+		return []doctor.Result{{
+			Name:    serviceConfig.Name,
+			Status:  doctor.StatusPassed,
+			Message: fmt.Sprintf("Images are pushed to %s", loginServer),
+		}}
+	})
 }
 
 func (ch *ContainerHelper) RemoteImageTag(
@@ -53,7 +70,12 @@ func (ch *ContainerHelper) RemoteImageTag(
 	serviceConfig *ServiceConfig,
 	localImageTag string,
 ) (string, error) {
-	loginServer, err := ch.RegistryName(ctx)
+	registry, err := ch.registry(serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	loginServer, err := registry.Endpoint()
 	if err != nil {
 		return "", err
 	}
@@ -61,10 +83,14 @@ func (ch *ContainerHelper) RemoteImageTag(
 	return fmt.Sprintf(
 		"%s/%s",
 		loginServer,
-		localImageTag,
+		registry.ImageName(localImageTag),
 	), nil
 }
 
+func (ch *ContainerHelper) registry(serviceConfig *ServiceConfig) (containerRegistry, error) {
+	return newContainerRegistry(serviceConfig, ch.env, ch.containerRegistryService, ch.docker)
+}
+
 func (ch *ContainerHelper) LocalImageTag(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
 	configuredTag, err := serviceConfig.Docker.Tag.Envsubst(ch.env.Getenv)
 	if err != nil {
@@ -87,6 +113,53 @@ func (ch *ContainerHelper) RequiredExternalTools(context.Context) []tools.Extern
 	return []tools.ExternalTool{ch.docker}
 }
 
+// PackageFromReference uses the referenced container image as the local image deployed, pulling it when it isn't
+// available locally
+func (ch *ContainerHelper) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			if reference.Image == "" {
+				task.SetError(fmt.Errorf(
+					"package '%s' is not a container image, service '%s' is deployed from a container image",
+					reference.Path, serviceConfig.Name))
+				return
+			}
+
+			// Published images are pulled by the hosting platform from their registry
+			if reference.Published {
+				task.SetResult(&ServicePackageResult{
+					PackagePath: reference.Image,
+					Details: &dockerPackageResult{
+						ImageTag:  reference.Image,
+						Published: true,
+					},
+				})
+				return
+			}
+
+			if _, err := ch.docker.Inspect(ctx, serviceConfig.Path(), reference.Image); err != nil {
+				log.Printf("image '%s' is not available locally, pulling it: %v\n", reference.Image, err)
+				task.SetProgress(NewServiceProgress("Pulling container image"))
+				if err := ch.docker.Pull(ctx, serviceConfig.Path(), reference.Image); err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
+			task.SetResult(&ServicePackageResult{
+				PackagePath: reference.Image,
+				Details: &dockerPackageResult{
+					ImageTag: reference.Image,
+				},
+			})
+		},
+	)
+}
+
 func (ch *ContainerHelper) Deploy(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -95,13 +168,6 @@ func (ch *ContainerHelper) Deploy(
 ) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
-			// Get ACR Login Server
-			loginServer, err := ch.RegistryName(ctx)
-			if err != nil {
-				task.SetError(err)
-				return
-			}
-
 			localImageTag := packageOutput.PackagePath
 			packageDetails, ok := packageOutput.Details.(*dockerPackageResult)
 			if ok && packageDetails != nil {
@@ -113,6 +179,32 @@ func (ch *ContainerHelper) Deploy(
 				return
 			}
 
+			if packageDetails != nil && packageDetails.Published {
+				log.Printf("deploying published image %s", localImageTag)
+				task.SetProgress(NewServiceProgress("Using published container image"))
+				if err := ch.saveImageName(serviceConfig, localImageTag); err != nil {
+					task.SetError(err)
+					return
+				}
+
+				task.SetResult(&ServiceDeployResult{
+					Package: packageOutput,
+				})
+				return
+			}
+
+			registry, err := ch.registry(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			loginServer, err := registry.Endpoint()
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			// Tag image
 			// Get remote tag from the container helper then call docker cli tag command
 			remoteTag, err := ch.RemoteImageTag(ctx, serviceConfig, localImageTag)
@@ -129,8 +221,7 @@ func (ch *ContainerHelper) Deploy(
 
 			log.Printf("logging into container registry '%s'\n", loginServer)
 			task.SetProgress(NewServiceProgress("Logging into container registry"))
-			err = ch.containerRegistryService.Login(ctx, targetResource.SubscriptionId(), loginServer)
-			if err != nil {
+			if err := registry.Login(ctx); err != nil {
 				task.SetError(err)
 				return
 			}
@@ -143,12 +234,8 @@ func (ch *ContainerHelper) Deploy(
 				return
 			}
 
-			// Save the name of the image we pushed into the environment with a well known key.
-			log.Printf("writing image name to environment")
-			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteTag)
-
-			if err := ch.env.Save(); err != nil {
-				task.SetError(fmt.Errorf("saving image name to environment: %w", err))
+			if err := ch.saveImageName(serviceConfig, remoteTag); err != nil {
+				task.SetError(err)
 				return
 			}
 
@@ -157,3 +244,15 @@ func (ch *ContainerHelper) Deploy(
 			})
 		})
 }
+
+// saveImageName saves the name of the deployed image into the environment with a well known key
+func (ch *ContainerHelper) saveImageName(serviceConfig *ServiceConfig, imageName string) error {
+	log.Printf("writing image name to environment")
+	ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", imageName)
+
+	if err := ch.env.Save(); err != nil {
+		return fmt.Errorf("saving image name to environment: %w", err)
+	}
+
+	return nil
+}
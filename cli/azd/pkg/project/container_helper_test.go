@@ -3,9 +3,13 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/benbjohnson/clock"
 	"github.com/stretchr/testify/assert"
@@ -82,3 +86,141 @@ func Test_ContainerHelper_RemoteImageTag_NoContainer_Registry(t *testing.T) {
 	require.Error(t, err)
 	require.Empty(t, imageTag)
 }
+
+func Test_ContainerHelper_RemoteImageTag_ExternalRegistry(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+		"GITHUB_OWNER": "Contoso",
+	})
+	containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil)
+
+	tests := []struct {
+		name     string
+		registry *ContainerRegistryConfig
+		want     string
+	}{
+		{"Acr", &ContainerRegistryConfig{Kind: ContainerRegistryAcr}, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0"},
+		{
+			"Ghcr",
+			&ContainerRegistryConfig{Kind: ContainerRegistryGhcr, Namespace: NewExpandableString("${GITHUB_OWNER}")},
+			"ghcr.io/contoso/test-app/api-dev:azd-deploy-0",
+		},
+		{
+			"DockerHub",
+			&ContainerRegistryConfig{Kind: ContainerRegistryDockerHub, Namespace: NewExpandableString("contoso")},
+			"docker.io/contoso/test-app-api-dev:azd-deploy-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Registry = tt.registry
+
+			localTag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
+			require.NoError(t, err)
+			remoteTag, err := containerHelper.RemoteImageTag(*mockContext.Context, serviceConfig, localTag)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, remoteTag)
+		})
+	}
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Registry = &ContainerRegistryConfig{Kind: ContainerRegistryGhcr}
+	_, err := containerHelper.RemoteImageTag(*mockContext.Context, serviceConfig, "local_tag")
+	require.ErrorContains(t, err, "registry.namespace is required")
+
+	serviceConfig.Registry = &ContainerRegistryConfig{Kind: "quay"}
+	_, err = containerHelper.RemoteImageTag(*mockContext.Context, serviceConfig, "local_tag")
+	require.ErrorContains(t, err, "unsupported registry kind 'quay'")
+}
+
+func Test_ContainerHelper_Deploy_ExternalRegistry(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		wantLogin []string
+	}{
+		{"Token", "TOKEN", []string{"login", "--username", "octocat", "--password-stdin", "ghcr.io"}},
+		// Without token, the credentials of docker are used
+		{"CredentialHelper", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			var login []string
+			var password string
+			pushed := ""
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return args.Cmd == "docker"
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				switch args.Args[0] {
+				case "login":
+					login = args.Args
+					stdin, _ := io.ReadAll(args.StdIn)
+					password = string(stdin)
+				case "push":
+					pushed = args.Args[1]
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			env := environment.EphemeralWithValues("dev", map[string]string{"GHCR_TOKEN": tt.token})
+			containerHelper := NewContainerHelper(env, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner))
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Registry = &ContainerRegistryConfig{
+				Kind:      ContainerRegistryGhcr,
+				Namespace: NewExpandableString("contoso"),
+				Username:  NewExpandableString("octocat"),
+				TokenEnv:  "GHCR_TOKEN",
+			}
+
+			deployTask := containerHelper.Deploy(
+				*mockContext.Context,
+				serviceConfig,
+				&ServicePackageResult{PackagePath: "test-app/api-dev:azd-deploy-0"},
+				nil,
+			)
+			logProgress(deployTask)
+			_, err := deployTask.Await()
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantLogin, login)
+			require.Equal(t, tt.token, strings.TrimSpace(password))
+			require.Equal(t, "ghcr.io/contoso/test-app/api-dev:azd-deploy-0", pushed)
+			require.Equal(t, pushed, env.GetServiceProperty("api", "IMAGE_NAME"))
+		})
+	}
+}
+
+func Test_ContainerHelper_Deploy_PublishedImage(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	// The published image is neither pulled, tagged nor pushed
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "docker"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.Fail(t, "unexpected docker command", args.Args)
+		return exec.NewRunResult(1, "", ""), nil
+	})
+
+	env := environment.EphemeralWithValues("dev", map[string]string{})
+	containerHelper := NewContainerHelper(env, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner))
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+	reference, err := NewImageReference("contoso.azurecr.io/api:1.2.3")
+	require.NoError(t, err)
+
+	packageTask := containerHelper.PackageFromReference(*mockContext.Context, serviceConfig, reference)
+	logProgress(packageTask)
+	packageResult, err := packageTask.Await()
+	require.NoError(t, err)
+
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, packageResult, nil)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, "contoso.azurecr.io/api:1.2.3", env.GetServiceProperty("api", "IMAGE_NAME"))
+}
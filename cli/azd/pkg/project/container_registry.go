@@ -0,0 +1,176 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+type ContainerRegistryKind string
+
+const (
+	// The Azure Container Registry of the environment, the default
+	ContainerRegistryAcr ContainerRegistryKind = "acr"
+	// GitHub Container Registry, ghcr.io
+	ContainerRegistryGhcr ContainerRegistryKind = "ghcr"
+	// Docker Hub, docker.io
+	ContainerRegistryDockerHub ContainerRegistryKind = "dockerhub"
+)
+
+// The login servers of the registries outside of Azure, and the environment variables of their default tokens
+var externalRegistries = map[ContainerRegistryKind]struct {
+	LoginServer string
+	TokenEnv    string
+}{
+	ContainerRegistryGhcr:      {LoginServer: "ghcr.io", TokenEnv: "GITHUB_TOKEN"},
+	ContainerRegistryDockerHub: {LoginServer: "docker.io", TokenEnv: "DOCKERHUB_TOKEN"},
+}
+
+// ContainerRegistryConfig selects the registry the images of the service are pushed to, from the registry section of
+// the service in azure.yaml
+type ContainerRegistryConfig struct {
+	// acr (default), ghcr or dockerhub
+	Kind ContainerRegistryKind `yaml:"kind"`
+	// The user or organization owning the images pushed to ghcr or dockerhub
+	Namespace ExpandableString `yaml:"namespace,omitempty"`
+	// The user logging in to the registry. (Default: the namespace)
+	Username ExpandableString `yaml:"username,omitempty"`
+	// The environment variable of the token logging in to the registry. (Default: GITHUB_TOKEN for ghcr,
+	// DOCKERHUB_TOKEN for dockerhub). When the token isn't set, the credentials of docker are used, ex. from the
+	// credential helper configured by `docker login`.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+}
+
+// containerRegistry is a registry the images of the services are pushed to
+type containerRegistry interface {
+	// Endpoint returns the login server and namespace the images are pushed to, ex. ghcr.io/contoso
+	Endpoint() (string, error)
+	// ImageName returns the name of the local image in the registry
+	ImageName(localImageTag string) string
+	// Login logs docker in to the registry
+	Login(ctx context.Context) error
+}
+
+// acrRegistry is the Azure Container Registry provisioned with the environment
+type acrRegistry struct {
+	env                      *environment.Environment
+	containerRegistryService azcli.ContainerRegistryService
+}
+
+func (r *acrRegistry) Endpoint() (string, error) {
+	loginServer, has := r.env.LookupEnv(environment.ContainerRegistryEndpointEnvVarName)
+	if !has {
+		return "", fmt.Errorf(
+			"could not determine container registry endpoint, ensure %s is set as an output of your infrastructure",
+			environment.ContainerRegistryEndpointEnvVarName,
+		)
+	}
+
+	return loginServer, nil
+}
+
+func (r *acrRegistry) ImageName(localImageTag string) string {
+	return localImageTag
+}
+
+func (r *acrRegistry) Login(ctx context.Context) error {
+	loginServer, err := r.Endpoint()
+	if err != nil {
+		return err
+	}
+
+	// The registry is provisioned with the environment, services may be hosted in other subscriptions
+	return r.containerRegistryService.Login(ctx, r.env.GetSubscriptionId(), loginServer)
+}
+
+// externalRegistry is a registry outside of Azure, GitHub Container Registry or Docker Hub
+type externalRegistry struct {
+	kind        ContainerRegistryKind
+	loginServer string
+	namespace   string
+	username    string
+	token       string
+	docker      docker.Docker
+}
+
+func (r *externalRegistry) Endpoint() (string, error) {
+	return fmt.Sprintf("%s/%s", r.loginServer, r.namespace), nil
+}
+
+// ImageName returns the name of the image in the namespace. The repositories of Docker Hub can't be nested, the default
+// image names, ex. my-app/web-dev, are flattened to my-app-web-dev.
+func (r *externalRegistry) ImageName(localImageTag string) string {
+	if r.kind == ContainerRegistryDockerHub {
+		return strings.ReplaceAll(localImageTag, "/", "-")
+	}
+
+	return localImageTag
+}
+
+func (r *externalRegistry) Login(ctx context.Context) error {
+	if r.token == "" {
+		log.Printf("no token set for registry '%s', using the docker credentials", r.loginServer)
+		return nil
+	}
+
+	return r.docker.Login(ctx, r.loginServer, r.username, r.token)
+}
+
+// newContainerRegistry returns the registry the images of the service are pushed to
+func newContainerRegistry(
+	serviceConfig *ServiceConfig,
+	env *environment.Environment,
+	containerRegistryService azcli.ContainerRegistryService,
+	docker docker.Docker,
+) (containerRegistry, error) {
+	config := serviceConfig.Registry
+	if config == nil || config.Kind == "" || config.Kind == ContainerRegistryAcr {
+		return &acrRegistry{env: env, containerRegistryService: containerRegistryService}, nil
+	}
+
+	external, has := externalRegistries[config.Kind]
+	if !has {
+		return nil, fmt.Errorf(
+			"service '%s': unsupported registry kind '%s', supported kinds are %s, %s and %s",
+			serviceConfig.Name, config.Kind, ContainerRegistryAcr, ContainerRegistryGhcr, ContainerRegistryDockerHub)
+	}
+
+	namespace, err := config.Namespace.Envsubst(env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("service '%s': expanding registry namespace: %w", serviceConfig.Name, err)
+	}
+
+	namespace = strings.ToLower(strings.TrimSpace(namespace))
+	if namespace == "" {
+		return nil, fmt.Errorf(
+			"service '%s': registry.namespace is required for the %s registry", serviceConfig.Name, config.Kind)
+	}
+
+	username, err := config.Username.Envsubst(env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("service '%s': expanding registry username: %w", serviceConfig.Name, err)
+	}
+
+	if username == "" {
+		username = namespace
+	}
+
+	tokenEnv := config.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = external.TokenEnv
+	}
+
+	return &externalRegistry{
+		kind:        config.Kind,
+		loginServer: external.LoginServer,
+		namespace:   namespace,
+		username:    username,
+		token:       env.Getenv(tokenEnv),
+		docker:      docker,
+	}, nil
+}
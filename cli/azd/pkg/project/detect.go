@@ -0,0 +1,175 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
+)
+
+// The build systems of the services detected by DetectServices
+const (
+	BuildSystemMaven  = "maven"
+	BuildSystemGradle = "gradle"
+	BuildSystemCargo  = "cargo"
+)
+
+// The depth of the directories of the project searched by DetectServices, ex. src/api
+const maxDetectDepth = 3
+
+// The directories which don't contain the code of services, or contain the build outputs of the services
+var skippedDetectDirs = map[string]bool{
+	"node_modules": true,
+	"target":       true,
+	"build":        true,
+	"bin":          true,
+	"obj":          true,
+	"infra":        true,
+}
+
+// DetectedService is a service detected in a directory of the project
+type DetectedService struct {
+	Config      *ServiceConfig
+	BuildSystem string
+	// The name of the cargo package of rust services, the name of its binary
+	PackageName string
+}
+
+// DetectServices detects the java services built with maven or gradle, and the rust services built with cargo, in the
+// directories of the project. Java services are hosted on App Service, as jar or war archives. Rust services are hosted
+// on Container Apps, as static binaries linked against musl.
+func DetectServices(projectDir string) ([]DetectedService, error) {
+	detected := []DetectedService{}
+	names := map[string]bool{}
+
+	err := filepath.WalkDir(projectDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return err
+		}
+
+		if relativePath != "." {
+			if strings.HasPrefix(entry.Name(), ".") || skippedDetectDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+
+			if len(strings.Split(relativePath, string(filepath.Separator))) > maxDetectDepth {
+				return filepath.SkipDir
+			}
+		}
+
+		service, err := detectService(path)
+		if err != nil {
+			return err
+		}
+
+		// Workspaces & directories without a build are searched for services
+		if service == nil {
+			return nil
+		}
+
+		name := serviceName(filepath.Base(relativePath))
+		if relativePath == "." {
+			name = serviceName(filepath.Base(projectDir))
+		} else if names[name] {
+			// The name of the relative path, ex. tools-api of tools/api
+			name = serviceName(strings.ReplaceAll(filepath.ToSlash(relativePath), "/", "-"))
+		}
+		names[name] = true
+
+		service.Config.Name = name
+		service.Config.RelativePath = filepath.ToSlash(relativePath)
+		detected = append(detected, *service)
+
+		// The modules of multi-module builds are built by the service
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("detecting services: %w", err)
+	}
+
+	return detected, nil
+}
+
+func detectService(dir string) (*DetectedService, error) {
+	if _, err := os.Stat(filepath.Join(dir, "pom.xml")); err == nil {
+		return &DetectedService{
+			Config:      &ServiceConfig{Host: AppServiceTarget, Language: ServiceLanguageJava},
+			BuildSystem: BuildSystemMaven,
+		}, nil
+	}
+
+	if gradle.IsGradleProject(dir) {
+		return &DetectedService{
+			Config:      &ServiceConfig{Host: AppServiceTarget, Language: ServiceLanguageJava},
+			BuildSystem: BuildSystemGradle,
+		}, nil
+	}
+
+	packageName, err := cargoPackageName(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	if packageName != "" {
+		return &DetectedService{
+			Config:      &ServiceConfig{Host: ContainerAppTarget, Language: ServiceLanguageRust},
+			BuildSystem: BuildSystemCargo,
+			PackageName: packageName,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+var cargoNameRegex = regexp.MustCompile(`^\s*name\s*=\s*"([^"]+)"`)
+
+// cargoPackageName returns the name of the package of the cargo manifest, empty when the manifest doesn't exist or
+// doesn't have a package, ex. workspaces
+func cargoPackageName(manifestPath string) (string, error) {
+	file, err := os.Open(manifestPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+
+		if section == "[package]" {
+			if match := cargoNameRegex.FindStringSubmatch(line); match != nil {
+				return match[1], nil
+			}
+		}
+	}
+
+	return "", scanner.Err()
+}
+
+var serviceNameRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// serviceName returns the directory name as a service name, lower case letters, digits and dashes
+func serviceName(dirName string) string {
+	return strings.Trim(serviceNameRegex.ReplaceAllString(strings.ToLower(dirName), "-"), "-")
+}
@@ -0,0 +1,70 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DetectServices(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "Todo App")
+	files := map[string]string{
+		"src/api/pom.xml":                  "<project/>",
+		"src/api/module/pom.xml":           "<project/>",
+		"src/web/build.gradle.kts":         "plugins { java }",
+		"tools/api/Cargo.toml":             "[package]\nname = \"tools-api\"\nversion = \"0.1.0\"\n",
+		"workers/Cargo.toml":               "[workspace]\nmembers = [\"jobs\"]\n",
+		"workers/jobs/Cargo.toml":          "[dependencies]\nname = \"serde\"\n\n[package]\nname = \"jobs\"\n",
+		"src/web/node_modules/x/pom.xml":   "<project/>",
+		"src/web/build/generated/pom.xml":  "<project/>",
+		".github/actions/check/Cargo.toml": "[package]\nname = \"check\"\n",
+		"a/b/c/deep/pom.xml":               "<project/>",
+		"README.md":                        "# Todo",
+	}
+
+	for path, contents := range files {
+		fullPath := filepath.Join(projectDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(fullPath, []byte(contents), osutil.PermissionFile))
+	}
+
+	detected, err := DetectServices(projectDir)
+	require.NoError(t, err)
+
+	type service struct {
+		name, path, buildSystem, packageName string
+		host                                 ServiceTargetKind
+		language                             ServiceLanguageKind
+	}
+
+	actual := []service{}
+	for _, d := range detected {
+		actual = append(actual, service{
+			d.Config.Name, d.Config.RelativePath, d.BuildSystem, d.PackageName, d.Config.Host, d.Config.Language,
+		})
+	}
+
+	require.Equal(t, []service{
+		{"api", "src/api", BuildSystemMaven, "", AppServiceTarget, ServiceLanguageJava},
+		{"web", "src/web", BuildSystemGradle, "", AppServiceTarget, ServiceLanguageJava},
+		{"tools-api", "tools/api", BuildSystemCargo, "tools-api", ContainerAppTarget, ServiceLanguageRust},
+		{"jobs", "workers/jobs", BuildSystemCargo, "jobs", ContainerAppTarget, ServiceLanguageRust},
+	}, actual)
+}
+
+func Test_DetectServices_Root(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "orders_service")
+	require.NoError(t, os.MkdirAll(projectDir, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(projectDir, "Cargo.toml"), []byte("[package]\nname=\"orders\"\n"), osutil.PermissionFile))
+
+	detected, err := DetectServices(projectDir)
+	require.NoError(t, err)
+	require.Len(t, detected, 1)
+	require.Equal(t, "orders-service", detected[0].Config.Name)
+	require.Equal(t, ".", detected[0].Config.RelativePath)
+	require.Equal(t, "orders", detected[0].PackageName)
+}
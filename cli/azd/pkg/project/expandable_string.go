@@ -35,6 +35,11 @@ func (e ExpandableString) MustEnvsubst(mapping func(string) string) string {
 	}
 }
 
+// Empty returns whether the template is empty, before any evaluation.
+func (e ExpandableString) Empty() bool {
+	return e.template == ""
+}
+
 func (e ExpandableString) MarshalYAML() (interface{}, error) {
 	return e.template, nil
 }
@@ -22,6 +22,7 @@ const (
 	ServiceLanguageTypeScript ServiceLanguageKind = "ts"
 	ServiceLanguagePython     ServiceLanguageKind = "python"
 	ServiceLanguageJava       ServiceLanguageKind = "java"
+	ServiceLanguageRust       ServiceLanguageKind = "rust"
 	ServiceLanguageDocker     ServiceLanguageKind = "docker"
 )
 
@@ -42,7 +43,8 @@ func parseServiceLanguage(kind ServiceLanguageKind) (ServiceLanguageKind, error)
 		ServiceLanguageJavaScript,
 		ServiceLanguageTypeScript,
 		ServiceLanguagePython,
-		ServiceLanguageJava:
+		ServiceLanguageJava,
+		ServiceLanguageRust:
 		// Excluding ServiceLanguageDocker since it is implicitly derived currently, and not an actual language
 		return kind, nil
 	}
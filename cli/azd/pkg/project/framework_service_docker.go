@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -16,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 )
 
 type DockerProjectOptions struct {
@@ -46,6 +49,9 @@ func (dbr *dockerBuildResult) MarshalJSON() ([]byte, error) {
 type dockerPackageResult struct {
 	ImageHash string `json:"imageHash"`
 	ImageTag  string `json:"imageTag"`
+	// Whether the image tag references an image of a registry deployed as is, without being pushed to the registry of
+	// the environment
+	Published bool `json:"published,omitempty"`
 }
 
 func (dpr *dockerPackageResult) ToString(currentIndentation string) string {
@@ -64,6 +70,7 @@ func (dpr *dockerPackageResult) MarshalJSON() ([]byte, error) {
 type dockerProject struct {
 	env             *environment.Environment
 	docker          docker.Docker
+	dotnetCli       dotnet.DotNetCli
 	framework       FrameworkService
 	containerHelper *ContainerHelper
 }
@@ -73,11 +80,13 @@ type dockerProject struct {
 func NewDockerProject(
 	env *environment.Environment,
 	docker docker.Docker,
+	dotnetCli dotnet.DotNetCli,
 	containerHelper *ContainerHelper,
 ) CompositeFrameworkService {
 	return &dockerProject{
 		env:             env,
 		docker:          docker,
+		dotnetCli:       dotnetCli,
 		containerHelper: containerHelper,
 	}
 }
@@ -141,6 +150,28 @@ func (p *dockerProject) Build(
 				strings.ToLower(serviceConfig.Name),
 			)
 
+			// .NET projects without a Dockerfile, ex. the projects of .NET Aspire app hosts, are published as
+			// containers by the .NET SDK
+			if p.publishesDotNetContainer(serviceConfig, dockerOptions) {
+				task.SetProgress(NewServiceProgress("Publishing .NET container image"))
+				imageId, err := p.publishDotNetContainer(ctx, serviceConfig, imageName)
+				if err != nil {
+					task.SetError(fmt.Errorf("building container: %s: %w", serviceConfig.Name, err))
+					return
+				}
+
+				log.Printf("published image %s for %s", imageId, serviceConfig.Name)
+				task.SetResult(&ServiceBuildResult{
+					Restore:         restoreOutput,
+					BuildOutputPath: imageId,
+					Details: &dockerBuildResult{
+						ImageId:   imageId,
+						ImageName: imageName,
+					},
+				})
+				return
+			}
+
 			// Build the container
 			task.SetProgress(NewServiceProgress("Building Docker image"))
 			imageId, err := p.docker.Build(
@@ -208,6 +239,48 @@ func (p *dockerProject) Package(
 	)
 }
 
+// publishesDotNetContainer returns whether the image of the service is published by the .NET SDK, true for .NET
+// services without a Dockerfile
+func (p *dockerProject) publishesDotNetContainer(serviceConfig *ServiceConfig, dockerOptions DockerProjectOptions) bool {
+	switch serviceConfig.Language {
+	case ServiceLanguageDotNet, ServiceLanguageCsharp, ServiceLanguageFsharp:
+	default:
+		return false
+	}
+
+	dockerfile := dockerOptions.Path
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(serviceConfig.Path(), dockerfile)
+	}
+
+	_, err := os.Stat(dockerfile)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// publishDotNetContainer publishes the .NET project of the service as a container image, returning the id of the image
+func (p *dockerProject) publishDotNetContainer(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	imageName string,
+) (string, error) {
+	projectFile, err := findProjectFile(serviceConfig.Name, serviceConfig.Path())
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.dotnetCli.PublishContainer(ctx, projectFile, "Release", imageName); err != nil {
+		return "", err
+	}
+
+	// The .NET SDK tags the image as latest
+	image, err := p.docker.Inspect(ctx, serviceConfig.Path(), fmt.Sprintf("%s:latest", imageName))
+	if err != nil {
+		return "", fmt.Errorf("inspecting published image: %w", err)
+	}
+
+	return image.Id, nil
+}
+
 func getDockerOptionsWithDefaults(options DockerProjectOptions) DockerProjectOptions {
 	if options.Path == "" {
 		options.Path = "./Dockerfile"
@@ -14,6 +14,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
@@ -85,7 +86,12 @@ services:
 	internalFramework := NewNpmProject(npmCli, env)
 	progressMessages := []string{}
 
-	framework := NewDockerProject(env, docker, NewContainerHelper(env, clock.NewMock(), nil, docker))
+	framework := NewDockerProject(
+		env,
+		docker,
+		dotnet.NewDotNetCli(mockContext.CommandRunner),
+		NewContainerHelper(env, clock.NewMock(), nil, docker),
+	)
 	framework.SetSource(internalFramework)
 
 	buildTask := framework.Build(*mockContext.Context, service, nil)
@@ -174,7 +180,12 @@ services:
 	internalFramework := NewNpmProject(npmCli, env)
 	status := ""
 
-	framework := NewDockerProject(env, docker, NewContainerHelper(env, clock.NewMock(), nil, docker))
+	framework := NewDockerProject(
+		env,
+		docker,
+		dotnet.NewDotNetCli(mockContext.CommandRunner),
+		NewContainerHelper(env, clock.NewMock(), nil, docker),
+	)
 	framework.SetSource(internalFramework)
 
 	buildTask := framework.Build(*mockContext.Context, service, nil)
@@ -211,7 +222,12 @@ func Test_DockerProject_Build(t *testing.T) {
 	dockerCli := docker.NewDocker(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 
-	dockerProject := NewDockerProject(env, dockerCli, NewContainerHelper(env, clock.NewMock(), nil, dockerCli))
+	dockerProject := NewDockerProject(
+		env,
+		dockerCli,
+		dotnet.NewDotNetCli(mockContext.CommandRunner),
+		NewContainerHelper(env, clock.NewMock(), nil, dockerCli),
+	)
 	buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
 	logProgress(buildTask)
 
@@ -256,7 +272,12 @@ func Test_DockerProject_Package(t *testing.T) {
 	dockerCli := docker.NewDocker(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 
-	dockerProject := NewDockerProject(env, dockerCli, NewContainerHelper(env, clock.NewMock(), nil, dockerCli))
+	dockerProject := NewDockerProject(
+		env,
+		dockerCli,
+		dotnet.NewDotNetCli(mockContext.CommandRunner),
+		NewContainerHelper(env, clock.NewMock(), nil, dockerCli),
+	)
 	packageTask := dockerProject.Package(
 		*mockContext.Context,
 		serviceConfig,
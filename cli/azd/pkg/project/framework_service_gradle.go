@@ -0,0 +1,124 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
+)
+
+// The name of the framework service of the java services built with gradle
+const GradleFrameworkName = "java-gradle"
+
+type gradleProject struct {
+	env       *environment.Environment
+	gradleCli gradle.GradleCli
+	javacCli  javac.JavacCli
+}
+
+// NewGradleProject creates a new instance of a gradle project
+func NewGradleProject(env *environment.Environment, gradleCli gradle.GradleCli, javaCli javac.JavacCli) FrameworkService {
+	return &gradleProject{
+		env:       env,
+		gradleCli: gradleCli,
+		javacCli:  javaCli,
+	}
+}
+
+func (g *gradleProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		// Gradle will automatically restore & build the project if needed
+		Package: FrameworkPackageRequirements{
+			RequireRestore: false,
+			RequireBuild:   false,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (g *gradleProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{
+		g.gradleCli,
+		g.javacCli,
+	}
+}
+
+// Initializes the gradle project
+func (g *gradleProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	g.gradleCli.SetPath(serviceConfig.Path(), serviceConfig.Project.Path)
+	return nil
+}
+
+// Restores dependencies using the Gradle CLI
+func (g *gradleProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Resolving gradle dependencies"))
+			if err := g.gradleCli.ResolveDependencies(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(fmt.Errorf("resolving gradle dependencies: %w", err))
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Builds the gradle project
+func (g *gradleProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Compiling gradle project"))
+			if err := g.gradleCli.Compile(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: serviceConfig.Path(),
+			})
+		},
+	)
+}
+
+// Packages the jar or war assembled by gradle, from the dist path of the service or the default build/libs directory
+func (g *gradleProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Assembling gradle project"))
+			if err := g.gradleCli.Assemble(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Copying deployment package"))
+			packageDest, err := stageJavaArchive(serviceConfig, buildOutput, "gradle", filepath.Join("build", "libs"))
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: packageDest,
+			})
+		},
+	)
+}
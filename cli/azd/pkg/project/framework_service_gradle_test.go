@@ -0,0 +1,124 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GradleProject(t *testing.T) {
+	ostest.Chdir(t, t.TempDir())
+	require.NoError(t, os.MkdirAll("./src/api", osutil.PermissionDirectory))
+	err := os.WriteFile(filepath.Join("src", "api", getGradlewCmd()), nil, osutil.PermissionExecutableFile)
+	require.NoError(t, err)
+
+	run := func(subcommand string) (*gradleProject, *mocks.MockContext, *exec.RunArgs) {
+		runArgs := &exec.RunArgs{}
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, getGradlewCmd()+" "+subcommand)
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				*runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		gradleCli := gradle.NewGradleCli(mockContext.CommandRunner)
+		javaCli := javac.NewCli(mockContext.CommandRunner)
+		project := NewGradleProject(environment.Ephemeral(), gradleCli, javaCli).(*gradleProject)
+
+		return project, mockContext, runArgs
+	}
+
+	t.Run("Restore", func(t *testing.T) {
+		gradleProject, mockContext, runArgs := run("dependencies")
+		serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJava)
+		require.NoError(t, gradleProject.Initialize(*mockContext.Context, serviceConfig))
+
+		restoreTask := gradleProject.Restore(*mockContext.Context, serviceConfig)
+		logProgress(restoreTask)
+
+		_, err := restoreTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, serviceConfig.Path(), runArgs.Cwd)
+		require.Equal(t, []string{"dependencies"}, runArgs.Args)
+	})
+
+	t.Run("Package", func(t *testing.T) {
+		gradleProject, mockContext, runArgs := run("assemble")
+		serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJava)
+		require.NoError(t, gradleProject.Initialize(*mockContext.Context, serviceConfig))
+
+		// Spring Boot assembles a plain jar along with the executable jar
+		libs := filepath.Join(serviceConfig.Path(), "build", "libs")
+		require.NoError(t, os.MkdirAll(libs, osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(filepath.Join(libs, "api-0.1.jar"), []byte("app"), osutil.PermissionFile))
+		require.NoError(t, os.WriteFile(filepath.Join(libs, "api-0.1-plain.jar"), nil, osutil.PermissionFile))
+
+		packageTask := gradleProject.Package(
+			*mockContext.Context,
+			serviceConfig,
+			&ServiceBuildResult{BuildOutputPath: serviceConfig.Path()},
+		)
+		logProgress(packageTask)
+
+		result, err := packageTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, []string{"assemble"}, runArgs.Args)
+
+		contents, err := os.ReadFile(filepath.Join(result.PackagePath, AppServiceJavaPackageName+".jar"))
+		require.NoError(t, err)
+		require.Equal(t, "app", string(contents))
+	})
+}
+
+func Test_GetFrameworkService_Gradle(t *testing.T) {
+	temp := t.TempDir()
+	serviceConfig := createTestServiceConfig("api", AppServiceTarget, ServiceLanguageJava)
+	serviceConfig.Project.Path = temp
+	require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.Ephemeral()
+	mockContext.Container.RegisterSingleton(func() *environment.Environment { return env })
+	mockContext.Container.RegisterSingleton(maven.NewMavenCli)
+	mockContext.Container.RegisterSingleton(gradle.NewGradleCli)
+	mockContext.Container.RegisterSingleton(javac.NewCli)
+	require.NoError(t, mockContext.Container.RegisterNamedSingleton(string(ServiceLanguageJava), NewMavenProject))
+	require.NoError(t, mockContext.Container.RegisterNamedSingleton(GradleFrameworkName, NewGradleProject))
+
+	sm := &serviceManager{serviceLocator: mockContext.Container}
+
+	frameworkService, err := sm.GetFrameworkService(*mockContext.Context, serviceConfig)
+	require.NoError(t, err)
+	require.IsType(t, &mavenProject{}, frameworkService)
+
+	err = os.WriteFile(filepath.Join(serviceConfig.Path(), "build.gradle"), nil, osutil.PermissionFile)
+	require.NoError(t, err)
+
+	frameworkService, err = sm.GetFrameworkService(*mockContext.Context, serviceConfig)
+	require.NoError(t, err)
+	require.IsType(t, &gradleProject{}, frameworkService)
+}
+
+func getGradlewCmd() string {
+	if runtime.GOOS == "windows" {
+		return "gradlew.bat"
+	}
+
+	return "gradlew"
+}
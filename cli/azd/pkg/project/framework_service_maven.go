@@ -104,63 +104,16 @@ func (m *mavenProject) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
-			packageDest, err := os.MkdirTemp("", "azd")
-			if err != nil {
-				task.SetError(fmt.Errorf("creating staging directory: %w", err))
-				return
-			}
-
 			task.SetProgress(NewServiceProgress("Packaging maven project"))
 			if err := m.mavenCli.Package(ctx, serviceConfig.Path()); err != nil {
 				task.SetError(err)
 				return
 			}
 
-			packageSrcPath := buildOutput.BuildOutputPath
-			if packageSrcPath == "" {
-				packageSrcPath = serviceConfig.Path()
-			}
-
-			if serviceConfig.OutputPath != "" {
-				packageSrcPath = filepath.Join(packageSrcPath, serviceConfig.OutputPath)
-			} else {
-				packageSrcPath = filepath.Join(packageSrcPath, "target")
-			}
-
-			packageSrcFileInfo, err := os.Stat(packageSrcPath)
-			if err != nil {
-				if serviceConfig.OutputPath == "" {
-					task.SetError(fmt.Errorf("reading default maven target path %s: %w", packageSrcPath, err))
-				} else {
-					task.SetError(fmt.Errorf("reading dist path %s: %w", packageSrcPath, err))
-				}
-				return
-			}
-
-			archive := ""
-			if packageSrcFileInfo.IsDir() {
-				archive, err = m.discoverArchive(packageSrcPath)
-				if err != nil {
-					task.SetError(err)
-					return
-				}
-			} else {
-				archive = packageSrcPath
-				if !isSupportedJavaArchive(archive) {
-					ext := filepath.Ext(archive)
-					task.SetError(
-						fmt.Errorf(
-							//nolint:lll
-							"file %s with extension %s is not a supported java archive file (.ear, .war, .jar)", ext, archive))
-					return
-				}
-			}
-
 			task.SetProgress(NewServiceProgress("Copying deployment package"))
-			ext := strings.ToLower(filepath.Ext(archive))
-			err = copy.Copy(archive, filepath.Join(packageDest, AppServiceJavaPackageName+ext))
+			packageDest, err := stageJavaArchive(serviceConfig, buildOutput, "maven", "target")
 			if err != nil {
-				task.SetError(fmt.Errorf("copying to staging directory failed: %w", err))
+				task.SetError(err)
 				return
 			}
 
@@ -172,12 +125,71 @@ func (m *mavenProject) Package(
 	)
 }
 
+// stageJavaArchive copies the java archive built by the build system to a staging directory, as the conventional App
+// Service package name. The archive is the dist path of the service, or the single archive of the default output
+// directory of the build system.
+func stageJavaArchive(
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	buildSystem string,
+	defaultOutputPath string,
+) (string, error) {
+	packageDest, err := os.MkdirTemp("", "azd")
+	if err != nil {
+		return "", fmt.Errorf("creating staging directory: %w", err)
+	}
+
+	packageSrcPath := buildOutput.BuildOutputPath
+	if packageSrcPath == "" {
+		packageSrcPath = serviceConfig.Path()
+	}
+
+	if serviceConfig.OutputPath != "" {
+		packageSrcPath = filepath.Join(packageSrcPath, serviceConfig.OutputPath)
+	} else {
+		packageSrcPath = filepath.Join(packageSrcPath, defaultOutputPath)
+	}
+
+	packageSrcFileInfo, err := os.Stat(packageSrcPath)
+	if err != nil {
+		if serviceConfig.OutputPath == "" {
+			return "", fmt.Errorf("reading default %s target path %s: %w", buildSystem, packageSrcPath, err)
+		}
+
+		return "", fmt.Errorf("reading dist path %s: %w", packageSrcPath, err)
+	}
+
+	archive := ""
+	if packageSrcFileInfo.IsDir() {
+		archive, err = discoverJavaArchive(packageSrcPath)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		archive = packageSrcPath
+		if !isSupportedJavaArchive(archive) {
+			ext := filepath.Ext(archive)
+			return "", fmt.Errorf(
+				//nolint:lll
+				"file %s with extension %s is not a supported java archive file (.ear, .war, .jar)", ext, archive)
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(archive))
+	err = copy.Copy(archive, filepath.Join(packageDest, AppServiceJavaPackageName+ext))
+	if err != nil {
+		return "", fmt.Errorf("copying to staging directory failed: %w", err)
+	}
+
+	return packageDest, nil
+}
+
 func isSupportedJavaArchive(archiveFile string) bool {
 	ext := strings.ToLower(filepath.Ext(archiveFile))
 	return ext == ".jar" || ext == ".war" || ext == ".ear"
 }
 
-func (m *mavenProject) discoverArchive(dir string) (string, error) {
+func discoverJavaArchive(dir string) (string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return "", fmt.Errorf("discovering java archive files in %s: %w", dir, err)
@@ -190,7 +202,8 @@ func (m *mavenProject) discoverArchive(dir string) (string, error) {
 		}
 
 		name := entry.Name()
-		if isSupportedJavaArchive(name) {
+		// The Spring Boot gradle plugin also assembles a plain jar, without the dependencies of the application
+		if isSupportedJavaArchive(name) && !strings.HasSuffix(strings.ToLower(name), "-plain.jar") {
 			archiveFiles = append(archiveFiles, name)
 		}
 	}
@@ -0,0 +1,153 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cargo"
+	"github.com/otiai10/copy"
+)
+
+type rustProject struct {
+	env      *environment.Environment
+	cargoCli cargo.CargoCli
+}
+
+// NewRustProject creates a new instance of a rust project, built as static binaries linked against musl
+func NewRustProject(env *environment.Environment, cargoCli cargo.CargoCli) FrameworkService {
+	return &rustProject{
+		env:      env,
+		cargoCli: cargoCli,
+	}
+}
+
+func (r *rustProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		// Cargo will automatically fetch the dependencies of the project when building
+		Package: FrameworkPackageRequirements{
+			RequireRestore: false,
+			RequireBuild:   true,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (r *rustProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{r.cargoCli}
+}
+
+// Initializes the rust project
+func (r *rustProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Fetches the dependencies using cargo
+func (r *rustProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Fetching cargo dependencies"))
+			if err := r.cargoCli.Fetch(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Builds the release binaries of the project for the musl target
+func (r *rustProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Building static binaries"))
+			if err := r.cargoCli.Build(ctx, serviceConfig.Path(), cargo.MuslTarget); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			buildOutputPath := filepath.Join(serviceConfig.Path(), "target", cargo.MuslTarget, "release")
+			if serviceConfig.OutputPath != "" {
+				buildOutputPath = filepath.Join(serviceConfig.Path(), serviceConfig.OutputPath)
+			}
+
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: buildOutputPath,
+			})
+		},
+	)
+}
+
+// Packages the binaries of the project, or the binary of the dist path of the service
+func (r *rustProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			binaries := []string{buildOutput.BuildOutputPath}
+			if info, err := os.Stat(buildOutput.BuildOutputPath); err != nil {
+				task.SetError(fmt.Errorf("reading build output %s: %w", buildOutput.BuildOutputPath, err))
+				return
+			} else if info.IsDir() {
+				names, err := r.cargoCli.Binaries(ctx, serviceConfig.Path())
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+
+				if len(names) == 0 {
+					task.SetError(fmt.Errorf("project '%s' doesn't have any binary target", serviceConfig.Path()))
+					return
+				}
+
+				binaries = []string{}
+				for _, name := range names {
+					binaries = append(binaries, filepath.Join(buildOutput.BuildOutputPath, name))
+				}
+			}
+
+			packageDest, err := os.MkdirTemp("", "azd")
+			if err != nil {
+				task.SetError(fmt.Errorf("creating staging directory: %w", err))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Copying deployment package"))
+			for _, binary := range binaries {
+				err := copy.Copy(binary, filepath.Join(packageDest, filepath.Base(binary)), copy.Options{
+					PermissionControl: copy.AddPermission(osutil.PermissionExecutableFile),
+				})
+				if err != nil {
+					task.SetError(fmt.Errorf("copying binary %s to staging directory: %w", binary, err))
+					return
+				}
+			}
+
+			if err := validatePackageOutput(packageDest); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: packageDest,
+			})
+		},
+	)
+}
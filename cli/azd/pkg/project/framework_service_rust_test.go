@@ -0,0 +1,84 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cargo"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RustProject(t *testing.T) {
+	temp := t.TempDir()
+	serviceConfig := createTestServiceConfig("api", AzureFunctionTarget, ServiceLanguageRust)
+	serviceConfig.Project.Path = temp
+	releaseDir := filepath.Join(serviceConfig.Path(), "target", cargo.MuslTarget, "release")
+	require.NoError(t, os.MkdirAll(releaseDir, osutil.PermissionDirectory))
+
+	var buildArgs exec.RunArgs
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "cargo build")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			buildArgs = args
+			err := os.WriteFile(filepath.Join(releaseDir, "handler"), []byte("elf"), osutil.PermissionFile)
+			return exec.NewRunResult(0, "", ""), err
+		})
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "cargo metadata")
+		}).
+		Respond(exec.NewRunResult(0, `{"packages":[{"name":"handler","targets":[
+			{"name":"handler","kind":["bin"]},{"name":"handler","kind":["lib"]}]}]}`, ""))
+
+	rustProject := NewRustProject(environment.Ephemeral(), cargo.NewCargoCli(mockContext.CommandRunner))
+
+	buildTask := rustProject.Build(*mockContext.Context, serviceConfig, nil)
+	logProgress(buildTask)
+	buildResult, err := buildTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, []string{"build", "--release", "--target", cargo.MuslTarget}, buildArgs.Args)
+	require.Equal(t, serviceConfig.Path(), buildArgs.Cwd)
+	require.Equal(t, releaseDir, buildResult.BuildOutputPath)
+
+	packageTask := rustProject.Package(*mockContext.Context, serviceConfig, buildResult)
+	logProgress(packageTask)
+	packageResult, err := packageTask.Await()
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(packageResult.PackagePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "handler", entries[0].Name())
+}
+
+func Test_RustProject_PackageNoBinary(t *testing.T) {
+	temp := t.TempDir()
+	serviceConfig := createTestServiceConfig("lib", AzureFunctionTarget, ServiceLanguageRust)
+	serviceConfig.Project.Path = temp
+	require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "cargo metadata")
+		}).
+		Respond(exec.NewRunResult(0, `{"packages":[{"name":"lib","targets":[{"name":"lib","kind":["lib"]}]}]}`, ""))
+
+	rustProject := NewRustProject(environment.Ephemeral(), cargo.NewCargoCli(mockContext.CommandRunner))
+	packageTask := rustProject.Package(
+		*mockContext.Context, serviceConfig, &ServiceBuildResult{BuildOutputPath: serviceConfig.Path()})
+	logProgress(packageTask)
+
+	_, err := packageTask.Await()
+	require.ErrorContains(t, err, "doesn't have any binary target")
+}
@@ -0,0 +1,534 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/otiai10/copy"
+	"golang.org/x/exp/slices"
+)
+
+// Directories that contain restored dependencies or build output and aren't part of the service source
+var packageCacheExcludedDirs = map[string]struct{}{
+	".azure":       {},
+	".git":         {},
+	".venv":        {},
+	"__pycache__":  {},
+	"bin":          {},
+	"node_modules": {},
+	"obj":          {},
+	"target":       {},
+}
+
+// The number of packages kept for each service, the least recently used packages are removed first
+const packageCacheMaxBuilds = 5
+
+// Packages that weren't used for this long are removed
+const packageCacheMaxAge = 14 * 24 * time.Hour
+
+// PackageCache records the content hash of each packaged service so services that haven't changed since they were
+// packaged are not packaged again. Entries are stored per environment and keyed by the service build arguments and
+// sources, the most recent packages of each service are kept so switching back to previous sources reuses their package.
+type PackageCache struct {
+	dir    string
+	env    *environment.Environment
+	docker docker.Docker
+}
+
+type packageCacheEntry struct {
+	Fingerprint string               `json:"fingerprint"`
+	PackagePath string               `json:"packagePath"`
+	Artifact    string               `json:"artifact,omitempty"`
+	Docker      *dockerPackageResult `json:"docker,omitempty"`
+}
+
+// The build arguments of a service, any change requires the service to be packaged again
+type packageBuildArgs struct {
+	Environment    string              `json:"environment"`
+	Host           ServiceTargetKind   `json:"host"`
+	Language       ServiceLanguageKind `json:"language"`
+	OutputPath     string              `json:"outputPath"`
+	DockerPath     string              `json:"dockerPath"`
+	DockerContext  string              `json:"dockerContext"`
+	DockerPlatform string              `json:"dockerPlatform"`
+	DockerTag      string              `json:"dockerTag"`
+}
+
+// Creates a new package cache for the current environment
+func NewPackageCache(
+	azdContext *azdcontext.AzdContext,
+	env *environment.Environment,
+	docker docker.Docker,
+) *PackageCache {
+	return &PackageCache{
+		dir:    filepath.Join(azdContext.EnvironmentRoot(env.GetEnvName()), ".package-cache"),
+		env:    env,
+		docker: docker,
+	}
+}
+
+// Computes the fingerprint of the service from its build arguments & the content of its source files
+func (c *PackageCache) Fingerprint(serviceConfig *ServiceConfig) (string, error) {
+	dockerTag, err := serviceConfig.Docker.Tag.Envsubst(c.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("evaluating docker tag: %w", err)
+	}
+
+	buildArgs, err := json.Marshal(packageBuildArgs{
+		Environment:    c.env.GetEnvName(),
+		Host:           serviceConfig.Host,
+		Language:       serviceConfig.Language,
+		OutputPath:     serviceConfig.OutputPath,
+		DockerPath:     serviceConfig.Docker.Path,
+		DockerContext:  serviceConfig.Docker.Context,
+		DockerPlatform: serviceConfig.Docker.Platform,
+		DockerTag:      dockerTag,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(buildArgs)
+
+	roots := serviceSourceRoots(serviceConfig)
+	for _, root := range roots {
+		if err := hashDirectory(hash, root, serviceConfig.OutputPath); err != nil {
+			return "", err
+		}
+	}
+
+	// Dockerfiles outside of the sources, ex. shared by services, are part of the build of the service
+	if serviceConfig.Docker.Path != "" {
+		dockerfile := filepath.Join(serviceConfig.Path(), serviceConfig.Docker.Path)
+		if !isWithinRoots(dockerfile, roots) {
+			if err := hashFile(hash, dockerfile); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// The slots of the package cache, each slot holds a single package per service. The packages of the packagings of the
+// service are stored in a slot per fingerprint.
+const (
+	// The package being deployed, promoted to the deployed slot once its deployment is verified
+	packageSlotCandidate = "candidate"
+	// The package last deployed successfully, deployed again to roll back failed deployments
+	packageSlotDeployed = "deployed"
+)
+
+// Gets the cached package result of the service when the service has not changed since it was last packaged
+// and the package artifacts are still available.
+func (c *PackageCache) Get(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	fingerprint string,
+) (*ServicePackageResult, bool) {
+	slot := fingerprintSlot(fingerprint)
+	result, has := c.get(ctx, serviceConfig, slot, fingerprint)
+	if has {
+		// The packages used recently are the last removed
+		now := time.Now()
+		if err := os.Chtimes(c.entryPath(serviceConfig, slot), now, now); err != nil {
+			log.Printf("failed recording use of cached package for service '%s': %v\n", serviceConfig.Name, err)
+		}
+	}
+
+	return result, has
+}
+
+// Gets the package last deployed successfully by the service, when its artifacts are still available
+func (c *PackageCache) GetDeployed(ctx context.Context, serviceConfig *ServiceConfig) (*ServicePackageResult, bool) {
+	return c.get(ctx, serviceConfig, packageSlotDeployed, "")
+}
+
+// Records the package about to be deployed by the service. Deployments consume package files, the package is recorded
+// before it is deployed and promoted by PromoteCandidate once the deployment is verified.
+func (c *PackageCache) SetCandidate(serviceConfig *ServiceConfig, result *ServicePackageResult) error {
+	return c.set(serviceConfig, packageSlotCandidate, "", result)
+}
+
+// Records the candidate package of the service as the package last deployed successfully
+func (c *PackageCache) PromoteCandidate(serviceConfig *ServiceConfig) error {
+	contents, err := os.ReadFile(c.entryPath(serviceConfig, packageSlotCandidate))
+	if err != nil {
+		return err
+	}
+
+	var entry packageCacheEntry
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return err
+	}
+
+	if entry.Artifact != "" {
+		artifact := c.artifactName(serviceConfig, packageSlotDeployed, filepath.Ext(entry.Artifact))
+		if err := os.Rename(filepath.Join(c.dir, entry.Artifact), filepath.Join(c.dir, artifact)); err != nil {
+			return err
+		}
+
+		entry.Artifact = artifact
+	}
+
+	contents, err = json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(c.entryPath(serviceConfig, packageSlotDeployed), contents, osutil.PermissionFile); err != nil {
+		return err
+	}
+
+	return c.remove(serviceConfig, packageSlotCandidate)
+}
+
+func (c *PackageCache) get(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	slot string,
+	fingerprint string,
+) (*ServicePackageResult, bool) {
+	contents, err := os.ReadFile(c.entryPath(serviceConfig, slot))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed reading package cache entry for service '%s': %v\n", serviceConfig.Name, err)
+		}
+
+		return nil, false
+	}
+
+	var entry packageCacheEntry
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		log.Printf("failed parsing package cache entry for service '%s': %v\n", serviceConfig.Name, err)
+		return nil, false
+	}
+
+	if fingerprint != "" && entry.Fingerprint != fingerprint {
+		return nil, false
+	}
+
+	result := &ServicePackageResult{
+		PackagePath: entry.PackagePath,
+	}
+
+	switch {
+	case entry.Docker != nil:
+		// Container images are stored by the local docker daemon, and may have been removed since they were built
+		if !entry.Docker.Published && !c.imageExists(ctx, entry.Docker) {
+			return nil, false
+		}
+
+		result.Details = entry.Docker
+	case entry.Artifact != "":
+		// Package files are consumed by deployments, a copy of the cached artifact is returned
+		packagePath, err := copyPackageArtifact(filepath.Join(c.dir, entry.Artifact), filepath.Ext(entry.Artifact))
+		if err != nil {
+			log.Printf("failed restoring cached package for service '%s': %v\n", serviceConfig.Name, err)
+			return nil, false
+		}
+
+		result.PackagePath = packagePath
+	default:
+		if _, err := os.Stat(entry.PackagePath); err != nil {
+			return nil, false
+		}
+	}
+
+	return result, true
+}
+
+// Returns whether the local docker daemon still has the image of the package
+func (c *PackageCache) imageExists(ctx context.Context, details *dockerPackageResult) bool {
+	image := details.ImageHash
+	if image == "" {
+		image = details.ImageTag
+	}
+
+	if _, err := c.docker.Inspect(ctx, "", image); err != nil {
+		log.Printf("cached image '%s' is no longer available: %v\n", image, err)
+		return false
+	}
+
+	return true
+}
+
+// Records the package result of the service for the specified fingerprint
+func (c *PackageCache) Set(serviceConfig *ServiceConfig, fingerprint string, result *ServicePackageResult) error {
+	if err := c.set(serviceConfig, fingerprintSlot(fingerprint), fingerprint, result); err != nil {
+		return err
+	}
+
+	if err := c.collect(serviceConfig); err != nil {
+		log.Printf("failed removing old cached packages for service '%s': %v\n", serviceConfig.Name, err)
+	}
+
+	return nil
+}
+
+// collect removes the packages of the service beyond the most recently used ones, or not used for a while, and the
+// package of the previous layout of the cache holding a single package
+func (c *PackageCache) collect(serviceConfig *ServiceConfig) error {
+	if err := c.removeEntry(filepath.Join(c.dir, serviceConfig.Name+".json")); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(c.dir, serviceConfig.Name))
+	if err != nil {
+		return err
+	}
+
+	type build struct {
+		path    string
+		modTime time.Time
+	}
+
+	builds := []build{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		builds = append(builds, build{filepath.Join(c.dir, serviceConfig.Name, entry.Name()), info.ModTime()})
+	}
+
+	slices.SortFunc(builds, func(a, b build) bool {
+		return a.modTime.After(b.modTime)
+	})
+
+	for i, build := range builds {
+		if i >= packageCacheMaxBuilds || time.Since(build.modTime) > packageCacheMaxAge {
+			if err := c.removeEntry(build.path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeEntry removes the cache entry at the path and its artifact, if any
+func (c *PackageCache) removeEntry(entryPath string) error {
+	contents, err := os.ReadFile(entryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var entry packageCacheEntry
+	if err := json.Unmarshal(contents, &entry); err == nil && entry.Artifact != "" {
+		artifactPath := filepath.Join(c.dir, entry.Artifact)
+		if err := os.Remove(artifactPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return os.Remove(entryPath)
+}
+
+func (c *PackageCache) set(
+	serviceConfig *ServiceConfig,
+	slot string,
+	fingerprint string,
+	result *ServicePackageResult,
+) error {
+	entry := packageCacheEntry{
+		Fingerprint: fingerprint,
+		PackagePath: result.PackagePath,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.entryPath(serviceConfig, slot)), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	if details, ok := result.Details.(*dockerPackageResult); ok {
+		entry.Docker = details
+	} else if info, err := os.Stat(result.PackagePath); err == nil && info.Mode().IsRegular() {
+		entry.Artifact = c.artifactName(serviceConfig, slot, filepath.Ext(result.PackagePath))
+		if err := copy.Copy(result.PackagePath, filepath.Join(c.dir, entry.Artifact)); err != nil {
+			return fmt.Errorf("caching package artifact: %w", err)
+		}
+	} else if err != nil {
+		// The package result doesn't refer to a local artifact that can be reused
+		return c.remove(serviceConfig, slot)
+	}
+
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.entryPath(serviceConfig, slot), contents, osutil.PermissionFile)
+}
+
+// Removes the cached packages of the packagings of the service
+func (c *PackageCache) Remove(serviceConfig *ServiceConfig) error {
+	if err := c.removeEntry(filepath.Join(c.dir, serviceConfig.Name+".json")); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(filepath.Join(c.dir, serviceConfig.Name))
+}
+
+func (c *PackageCache) remove(serviceConfig *ServiceConfig, slot string) error {
+	if err := os.Remove(c.entryPath(serviceConfig, slot)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (c *PackageCache) entryPath(serviceConfig *ServiceConfig, slot string) string {
+	return filepath.Join(c.dir, c.artifactName(serviceConfig, slot, ".json"))
+}
+
+// The name of the file of the slot of the service relative to the cache directory, ex. api.deployed.zip. The packages
+// of the packagings are stored in the directory of the service, ex. api/0a1b2c3d4e5f6a7b.zip
+func (c *PackageCache) artifactName(serviceConfig *ServiceConfig, slot string, ext string) string {
+	switch slot {
+	case packageSlotCandidate, packageSlotDeployed:
+		return fmt.Sprintf("%s.%s%s", serviceConfig.Name, slot, ext)
+	default:
+		return filepath.Join(serviceConfig.Name, slot+ext)
+	}
+}
+
+// The slot of the package of the packaging with the fingerprint
+func fingerprintSlot(fingerprint string) string {
+	if len(fingerprint) > 16 {
+		return fingerprint[:16]
+	}
+
+	return fingerprint
+}
+
+// serviceSourceRoots returns the directories of the source of the service: the directory of the service and its docker
+// context when the context is outside of the service directory
+func serviceSourceRoots(serviceConfig *ServiceConfig) []string {
+	roots := []string{serviceConfig.Path()}
+	if serviceConfig.Docker.Context != "" {
+		contextPath := filepath.Join(serviceConfig.Path(), serviceConfig.Docker.Context)
+		if relative, err := filepath.Rel(serviceConfig.Path(), contextPath); err == nil && strings.HasPrefix(relative, "..") {
+			roots = append(roots, contextPath)
+		}
+	}
+
+	return roots
+}
+
+// isWithinRoots returns whether the path is within one of the directories
+func isWithinRoots(path string, roots []string) bool {
+	for _, root := range roots {
+		if relative, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(relative, "..") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Writes the path & content of the file to the hash
+func hashFile(hash io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(hash, "%s\x00", filepath.ToSlash(filepath.Base(path)))
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+
+	_, err = hash.Write([]byte{0})
+	return err
+}
+
+// Writes the relative path & content of each source file within the directory to the hash
+func hashDirectory(hash io.Writer, root string, outputPath string) error {
+	outputDir := ""
+	if outputPath != "" {
+		outputDir = filepath.Join(root, outputPath)
+	}
+
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path == outputDir {
+				return filepath.SkipDir
+			}
+
+			if _, has := packageCacheExcludedDirs[entry.Name()]; has && path != root {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		fmt.Fprintf(hash, "%s\x00", filepath.ToSlash(relative))
+		if _, err := io.Copy(hash, file); err != nil {
+			return err
+		}
+		_, err = hash.Write([]byte{0})
+
+		return err
+	})
+}
+
+func copyPackageArtifact(source string, ext string) (string, error) {
+	target, err := os.CreateTemp("", "azdpackage*"+ext)
+	if err != nil {
+		return "", err
+	}
+	target.Close()
+
+	if err := copy.Copy(source, target.Name()); err != nil {
+		os.Remove(target.Name())
+		return "", err
+	}
+
+	return target.Name(), nil
+}
@@ -0,0 +1,231 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PackageCache(t *testing.T) {
+	// The images available on the local docker daemon
+	images := map[string]bool{}
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		if !images[args.Args[len(args.Args)-1]] {
+			return exec.NewRunResult(1, "[]", "No such image"), errors.New("no such image")
+		}
+
+		return exec.NewRunResult(0, `[{"Id": "IMAGE_HASH"}]`, ""), nil
+	})
+
+	setup := func(t *testing.T) (*PackageCache, *ServiceConfig) {
+		projectDir := t.TempDir()
+		serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+		serviceConfig.Project.Path = projectDir
+
+		require.NoError(t, os.MkdirAll(filepath.Join(serviceConfig.Path(), "node_modules"), osutil.PermissionDirectory))
+		writeFile(t, filepath.Join(serviceConfig.Path(), "index.js"), "console.log('hello')")
+
+		env := environment.EphemeralWithValues("dev", nil)
+		cache := NewPackageCache(
+			azdcontext.NewAzdContextWithDirectory(projectDir), env, docker.NewDocker(mockContext.CommandRunner))
+		return cache, serviceConfig
+	}
+
+	packageFile := func(t *testing.T) string {
+		packagePath := filepath.Join(t.TempDir(), "package.zip")
+		writeFile(t, packagePath, "package")
+		return packagePath
+	}
+
+	t.Run("UpToDate", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+
+		fingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NoError(t, cache.Set(serviceConfig, fingerprint, &ServicePackageResult{PackagePath: packageFile(t)}))
+
+		// Restored dependencies don't change the fingerprint
+		writeFile(t, filepath.Join(serviceConfig.Path(), "node_modules", "dep.js"), "module.exports = {}")
+
+		nextFingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, fingerprint, nextFingerprint)
+
+		result, has := cache.Get(*mockContext.Context, serviceConfig, nextFingerprint)
+		require.True(t, has)
+		defer os.Remove(result.PackagePath)
+
+		contents, err := os.ReadFile(result.PackagePath)
+		require.NoError(t, err)
+		require.Equal(t, "package", string(contents))
+	})
+
+	t.Run("SourceChanged", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+
+		fingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NoError(t, cache.Set(serviceConfig, fingerprint, &ServicePackageResult{PackagePath: packageFile(t)}))
+
+		writeFile(t, filepath.Join(serviceConfig.Path(), "index.js"), "console.log('updated')")
+
+		nextFingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NotEqual(t, fingerprint, nextFingerprint)
+
+		_, has := cache.Get(*mockContext.Context, serviceConfig, nextFingerprint)
+		require.False(t, has)
+	})
+
+	t.Run("BuildArgsChanged", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+
+		fingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+
+		serviceConfig.OutputPath = "dist"
+		nextFingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NotEqual(t, fingerprint, nextFingerprint)
+	})
+
+	t.Run("RecentPackages", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+
+		fingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NoError(t, cache.Set(serviceConfig, fingerprint, &ServicePackageResult{PackagePath: packageFile(t)}))
+
+		writeFile(t, filepath.Join(serviceConfig.Path(), "index.js"), "console.log('updated')")
+		nextFingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NoError(t, cache.Set(serviceConfig, nextFingerprint, &ServicePackageResult{PackagePath: packageFile(t)}))
+
+		// Reverting the sources reuses their package
+		writeFile(t, filepath.Join(serviceConfig.Path(), "index.js"), "console.log('hello')")
+		revertedFingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, fingerprint, revertedFingerprint)
+
+		result, has := cache.Get(*mockContext.Context, serviceConfig, revertedFingerprint)
+		require.True(t, has)
+		os.Remove(result.PackagePath)
+	})
+
+	t.Run("GarbageCollection", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+
+		fingerprints := []string{}
+		for i := 0; i < packageCacheMaxBuilds+2; i++ {
+			fingerprint := fmt.Sprintf("%016d", i)
+			require.NoError(t, cache.Set(serviceConfig, fingerprint, &ServicePackageResult{PackagePath: packageFile(t)}))
+
+			// Entries are ordered by their modification time
+			past := time.Now().Add(time.Duration(i-packageCacheMaxBuilds-2) * time.Minute)
+			require.NoError(t, os.Chtimes(cache.entryPath(serviceConfig, fingerprint), past, past))
+			fingerprints = append(fingerprints, fingerprint)
+		}
+
+		// The least recently used packages are removed with their artifacts
+		for i, fingerprint := range fingerprints {
+			_, err := os.Stat(cache.entryPath(serviceConfig, fingerprint))
+			_, artifactErr := os.Stat(filepath.Join(cache.dir, cache.artifactName(serviceConfig, fingerprint, ".zip")))
+			if i < len(fingerprints)-packageCacheMaxBuilds {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				require.ErrorIs(t, artifactErr, os.ErrNotExist)
+			} else {
+				require.NoError(t, err)
+				require.NoError(t, artifactErr)
+			}
+		}
+
+		require.NoError(t, cache.Remove(serviceConfig))
+		_, has := cache.Get(*mockContext.Context, serviceConfig, fingerprints[len(fingerprints)-1])
+		require.False(t, has)
+	})
+
+	t.Run("DockerfileChanged", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+		serviceConfig.Docker.Path = "../Dockerfile"
+		writeFile(t, filepath.Join(serviceConfig.Path(), "..", "Dockerfile"), "FROM node:18")
+
+		fingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+
+		writeFile(t, filepath.Join(serviceConfig.Path(), "..", "Dockerfile"), "FROM node:20")
+		nextFingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NotEqual(t, fingerprint, nextFingerprint)
+	})
+
+	t.Run("ContainerImage", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+		details := &dockerPackageResult{ImageHash: "IMAGE_HASH", ImageTag: "test-app/api-dev:azd-deploy-1"}
+
+		fingerprint, err := cache.Fingerprint(serviceConfig)
+		require.NoError(t, err)
+		require.NoError(t, cache.Set(serviceConfig, fingerprint, &ServicePackageResult{
+			PackagePath: details.ImageTag,
+			Details:     details,
+		}))
+
+		images[details.ImageHash] = true
+		result, has := cache.Get(*mockContext.Context, serviceConfig, fingerprint)
+		require.True(t, has)
+		require.Equal(t, details.ImageTag, result.PackagePath)
+		require.Equal(t, details, result.Details)
+
+		// Images removed from the local docker daemon must be built again
+		delete(images, details.ImageHash)
+		_, has = cache.Get(*mockContext.Context, serviceConfig, fingerprint)
+		require.False(t, has)
+	})
+
+	t.Run("DeployedPackage", func(t *testing.T) {
+		cache, serviceConfig := setup(t)
+
+		_, has := cache.GetDeployed(*mockContext.Context, serviceConfig)
+		require.False(t, has)
+
+		require.NoError(t, cache.SetCandidate(serviceConfig, &ServicePackageResult{PackagePath: packageFile(t)}))
+
+		// The candidate isn't deployed until it is promoted
+		_, has = cache.GetDeployed(*mockContext.Context, serviceConfig)
+		require.False(t, has)
+
+		require.NoError(t, cache.PromoteCandidate(serviceConfig))
+
+		result, has := cache.GetDeployed(*mockContext.Context, serviceConfig)
+		require.True(t, has)
+		defer os.Remove(result.PackagePath)
+
+		contents, err := os.ReadFile(result.PackagePath)
+		require.NoError(t, err)
+		require.Equal(t, "package", string(contents))
+
+		// The deployed package doesn't replace the package of the last packaging
+		_, has = cache.Get(*mockContext.Context, serviceConfig, "FINGERPRINT")
+		require.False(t, has)
+	})
+}
+
+func writeFile(t *testing.T, path string, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+}
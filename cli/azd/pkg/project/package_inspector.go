@@ -0,0 +1,432 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// PackageKind is the kind of artifact produced by packaging a service
+type PackageKind string
+
+const (
+	PackageKindZip       PackageKind = "zip"
+	PackageKindDirectory PackageKind = "directory"
+	PackageKindContainer PackageKind = "container"
+)
+
+// The number of largest files reported for zip & directory packages
+const inspectLargestFilesCount = 5
+
+// Text files larger than this aren't scanned for secrets
+const inspectMaxScanFileSize = 1024 * 1024
+
+// The image label of the OCI spec describing the base image of an image
+const baseImageLabel = "org.opencontainers.image.base.name"
+
+// PackageSizeLimit is the maximum size of a package accepted by the host of a service
+type PackageSizeLimit struct {
+	Description string `json:"description"`
+	MaxBytes    int64  `json:"maxBytes"`
+}
+
+// The package size limits of the hosts deploying zip or directory packages
+var packageSizeLimits = map[ServiceTargetKind]PackageSizeLimit{
+	// Zip deploy to App Service & Azure Functions (Kudu) accepts packages up to 2 GB
+	AppServiceTarget:    {Description: "App Service zip deploy", MaxBytes: 2 * 1024 * 1024 * 1024},
+	AzureFunctionTarget: {Description: "Azure Functions zip deploy", MaxBytes: 2 * 1024 * 1024 * 1024},
+	// The app size quota of the Standard plan of Static Web Apps
+	StaticWebAppTarget: {Description: "Static Web Apps (Standard plan)", MaxBytes: 500 * 1024 * 1024},
+	// The maximum size of an application uploaded to Azure Spring Apps
+	SpringAppTarget: {Description: "Azure Spring Apps upload", MaxBytes: 1024 * 1024 * 1024},
+}
+
+// PackageFile is a file in a zip or directory package
+type PackageFile struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+// ContainerLayer is a layer of a container image package
+type ContainerLayer struct {
+	CreatedBy string `json:"createdBy"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+// ContainerInspection describes the container image of a container package
+type ContainerInspection struct {
+	Image     string           `json:"image"`
+	BaseImage string           `json:"baseImage,omitempty"`
+	Platform  string           `json:"platform"`
+	Layers    []ContainerLayer `json:"layers"`
+}
+
+// SecretFinding is a likely secret embedded in a package
+type SecretFinding struct {
+	// The file of the package, or the environment variable of the container image, the secret was found in
+	Location string `json:"location"`
+	// The line of the file the secret was found on, 0 when the secret isn't in a text file
+	Line int    `json:"line,omitempty"`
+	Rule string `json:"rule"`
+}
+
+// PackageInspection summarizes the contents of the package of a service and the issues that would fail, or
+// shouldn't be shipped by, its deployment
+type PackageInspection struct {
+	Service      string               `json:"service"`
+	Kind         PackageKind          `json:"kind"`
+	Path         string               `json:"path"`
+	SizeBytes    int64                `json:"sizeBytes"`
+	Files        int                  `json:"files,omitempty"`
+	LargestFiles []PackageFile        `json:"largestFiles,omitempty"`
+	Container    *ContainerInspection `json:"container,omitempty"`
+	Secrets      []SecretFinding      `json:"secrets"`
+	SizeLimit    *PackageSizeLimit    `json:"sizeLimit,omitempty"`
+	Issues       []string             `json:"issues"`
+}
+
+// Passed returns true when no issues were found in the package
+func (pi *PackageInspection) Passed() bool {
+	return len(pi.Issues) == 0
+}
+
+type secretRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// The patterns of secrets commonly committed by mistake
+var secretRules = []secretRule{
+	{"private key", regexp.MustCompile(`-----BEGIN ((RSA|EC|DSA|OPENSSH|ENCRYPTED) )?PRIVATE KEY-----`)},
+	{"storage account key", regexp.MustCompile(`(?i)AccountKey=[A-Za-z0-9+/]{86}==`)},
+	{"shared access signature", regexp.MustCompile(`(?i)SharedAccessSignature=|[?&]sig=[A-Za-z0-9%+/]{43,}`)},
+	{"connection string password", regexp.MustCompile(`(?i)(Password|Pwd)=[^;'"\s]{6,}`)},
+	{"GitHub token", regexp.MustCompile(`\b(ghp|gho|ghs|ghu|github_pat)_[A-Za-z0-9_]{36,}`)},
+	{"AWS access key", regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)},
+}
+
+// The files that hold secrets and shouldn't be part of a package
+var secretFileRules = []secretRule{
+	{"environment file", regexp.MustCompile(`(^|/)\.env(\.[^/]*)?$`)},
+	{"certificate file", regexp.MustCompile(`(?i)\.(pfx|p12|pem|key)$`)},
+}
+
+// The names of environment variables that are expected to hold secrets
+var secretEnvVarPattern = regexp.MustCompile(`(?i)(PASSWORD|SECRET|TOKEN|API_?KEY|ACCOUNT_?KEY|CONNECTION_?STRING)`)
+
+// PackageInspector inspects the packages of services before they are deployed
+type PackageInspector struct {
+	docker docker.Docker
+}
+
+func NewPackageInspector(docker docker.Docker) *PackageInspector {
+	return &PackageInspector{
+		docker: docker,
+	}
+}
+
+// Inspect summarizes the contents of the package of the service, scans it for embedded secrets and validates its size
+// against the limits of the host of the service
+func (pi *PackageInspector) Inspect(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageResult *ServicePackageResult,
+) (*PackageInspection, error) {
+	inspection := &PackageInspection{
+		Service: serviceConfig.Name,
+		Path:    packageResult.PackagePath,
+		Secrets: []SecretFinding{},
+		Issues:  []string{},
+	}
+
+	if details, ok := packageResult.Details.(*dockerPackageResult); ok {
+		inspection.Kind = PackageKindContainer
+		if err := pi.inspectContainer(ctx, serviceConfig, details.ImageTag, inspection); err != nil {
+			return nil, err
+		}
+	} else {
+		info, err := os.Stat(packageResult.PackagePath)
+		if err != nil {
+			return nil, fmt.Errorf("reading package '%s': %w", packageResult.PackagePath, err)
+		}
+
+		if info.IsDir() {
+			inspection.Kind = PackageKindDirectory
+			err = inspectDirectory(packageResult.PackagePath, inspection)
+		} else {
+			inspection.Kind = PackageKindZip
+			err = inspectZip(packageResult.PackagePath, inspection)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("inspecting package '%s': %w", packageResult.PackagePath, err)
+		}
+
+		if limit, has := packageSizeLimits[serviceConfig.Host]; has {
+			inspection.SizeLimit = &limit
+		}
+	}
+
+	if inspection.SizeLimit != nil && inspection.SizeBytes > inspection.SizeLimit.MaxBytes {
+		inspection.Issues = append(inspection.Issues, fmt.Sprintf(
+			"package size %s exceeds the %s limit of %s",
+			FormatBytes(inspection.SizeBytes),
+			inspection.SizeLimit.Description,
+			FormatBytes(inspection.SizeLimit.MaxBytes),
+		))
+	}
+
+	if len(inspection.Secrets) > 0 {
+		inspection.Issues = append(inspection.Issues, fmt.Sprintf(
+			"%d possible secret(s) found in the package", len(inspection.Secrets)))
+	}
+
+	return inspection, nil
+}
+
+func (pi *PackageInspector) inspectContainer(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	imageName string,
+	inspection *PackageInspection,
+) error {
+	image, err := pi.docker.Inspect(ctx, serviceConfig.Path(), imageName)
+	if err != nil {
+		return err
+	}
+
+	history, err := pi.docker.History(ctx, serviceConfig.Path(), imageName)
+	if err != nil {
+		return err
+	}
+
+	container := &ContainerInspection{
+		Image:     imageName,
+		BaseImage: image.Config.Labels[baseImageLabel],
+		Platform:  fmt.Sprintf("%s/%s", image.Os, image.Architecture),
+		Layers:    []ContainerLayer{},
+	}
+
+	if container.BaseImage == "" {
+		dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+		dockerfilePath := dockerOptions.Path
+		if !filepath.IsAbs(dockerfilePath) {
+			dockerfilePath = filepath.Join(serviceConfig.Path(), dockerfilePath)
+		}
+
+		// The base image is informational, the Dockerfile may not be readable, ex. when it is generated
+		container.BaseImage, _ = dockerfileBaseImage(dockerfilePath)
+	}
+
+	// docker history lists the newest layers first, layers are reported from the base image up
+	for i := len(history) - 1; i >= 0; i-- {
+		container.Layers = append(container.Layers, ContainerLayer{
+			CreatedBy: history[i].CreatedBy,
+			SizeBytes: history[i].Size,
+		})
+	}
+
+	inspection.Container = container
+	inspection.SizeBytes = image.Size
+
+	for _, env := range image.Config.Env {
+		name, value, _ := strings.Cut(env, "=")
+		if value != "" && secretEnvVarPattern.MatchString(name) {
+			inspection.Secrets = append(inspection.Secrets, SecretFinding{
+				Location: fmt.Sprintf("ENV %s", name),
+				Rule:     "secret environment variable",
+			})
+		}
+	}
+
+	return nil
+}
+
+func inspectZip(path string, inspection *PackageInspection) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	inspection.SizeBytes = info.Size()
+
+	files := []PackageFile{}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		files = append(files, PackageFile{Path: file.Name, SizeBytes: int64(file.UncompressedSize64)})
+
+		if !scanFileName(file.Name, inspection) || file.UncompressedSize64 > inspectMaxScanFileSize {
+			continue
+		}
+
+		contents, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		err = scanFileContents(file.Name, contents, inspection)
+		contents.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	setPackageFiles(files, inspection)
+	return nil
+}
+
+func inspectDirectory(root string, inspection *PackageInspection) error {
+	files := []PackageFile{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(relativePath)
+		files = append(files, PackageFile{Path: name, SizeBytes: info.Size()})
+		inspection.SizeBytes += info.Size()
+
+		if !scanFileName(name, inspection) || info.Size() > inspectMaxScanFileSize {
+			return nil
+		}
+
+		contents, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer contents.Close()
+
+		return scanFileContents(name, contents, inspection)
+	})
+	if err != nil {
+		return err
+	}
+
+	setPackageFiles(files, inspection)
+	return nil
+}
+
+func setPackageFiles(files []PackageFile, inspection *PackageInspection) {
+	inspection.Files = len(files)
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].SizeBytes > files[j].SizeBytes
+	})
+
+	if len(files) > inspectLargestFilesCount {
+		files = files[:inspectLargestFilesCount]
+	}
+
+	inspection.LargestFiles = files
+}
+
+// Records a finding when the name of the file is a file holding secrets.
+// Returns false when the file was reported and doesn't need to be scanned.
+func scanFileName(name string, inspection *PackageInspection) bool {
+	for _, rule := range secretFileRules {
+		if rule.pattern.MatchString(name) {
+			inspection.Secrets = append(inspection.Secrets, SecretFinding{Location: name, Rule: rule.name})
+			return false
+		}
+	}
+
+	return true
+}
+
+// Records a finding for each line of the text file that matches a secret rule, binary files are skipped
+func scanFileContents(name string, contents io.Reader, inspection *PackageInspection) error {
+	data, err := io.ReadAll(contents)
+	if err != nil {
+		return err
+	}
+
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), inspectMaxScanFileSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		for _, rule := range secretRules {
+			if rule.pattern.MatchString(scanner.Text()) {
+				inspection.Secrets = append(inspection.Secrets, SecretFinding{Location: name, Line: line, Rule: rule.name})
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Returns the base image of the final stage of the Dockerfile, following the stages the final stage is built from
+func dockerfileBaseImage(path string) (string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	stages := map[string]string{}
+	baseImage := ""
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+
+		// FROM [--platform=<platform>] <image> [AS <name>]
+		fields = fields[1:]
+		for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+			fields = fields[1:]
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		baseImage = fields[0]
+		if stage, has := stages[strings.ToLower(baseImage)]; has {
+			baseImage = stage
+		}
+
+		if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+			stages[strings.ToLower(fields[2])] = baseImage
+		}
+	}
+
+	return baseImage, scanner.Err()
+}
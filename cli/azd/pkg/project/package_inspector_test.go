@@ -0,0 +1,130 @@
+package project
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageInspectorZip(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "api.zip")
+	writeTestZip(t, zipPath, map[string]string{
+		"server.js":    "const port = process.env.PORT;\n",
+		"config.js":    "module.exports = {\n  db: 'Server=db;User Id=app;Password=hunter2hunter2;'\n};\n",
+		"public/.env":  "API_KEY=abc\n",
+		"package.json": "{}",
+	})
+
+	mockContext := mocks.NewMockContext(context.Background())
+	inspector := NewPackageInspector(docker.NewDocker(mockContext.CommandRunner))
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJavaScript)
+
+	inspection, err := inspector.Inspect(*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: zipPath})
+	require.NoError(t, err)
+
+	require.Equal(t, PackageKindZip, inspection.Kind)
+	require.Equal(t, 4, inspection.Files)
+	require.Equal(t, "config.js", inspection.LargestFiles[0].Path)
+	require.Equal(t, "App Service zip deploy", inspection.SizeLimit.Description)
+	require.ElementsMatch(t, []SecretFinding{
+		{Location: "config.js", Line: 2, Rule: "connection string password"},
+		{Location: "public/.env", Rule: "environment file"},
+	}, inspection.Secrets)
+	require.Equal(t, []string{"2 possible secret(s) found in the package"}, inspection.Issues)
+	require.False(t, inspection.Passed())
+}
+
+func TestPackageInspectorDirectorySizeLimit(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), osutil.PermissionFile))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	inspector := NewPackageInspector(docker.NewDocker(mockContext.CommandRunner))
+	serviceConfig := createTestServiceConfig("./src/web", StaticWebAppTarget, ServiceLanguageJavaScript)
+
+	inspection, err := inspector.Inspect(*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: dir})
+	require.NoError(t, err)
+	require.Equal(t, PackageKindDirectory, inspection.Kind)
+	require.Equal(t, int64(13), inspection.SizeBytes)
+	require.True(t, inspection.Passed())
+
+	packageSizeLimits[StaticWebAppTarget] = PackageSizeLimit{Description: "test", MaxBytes: 10}
+	t.Cleanup(func() {
+		packageSizeLimits[StaticWebAppTarget] = PackageSizeLimit{
+			Description: "Static Web Apps (Standard plan)", MaxBytes: 500 * 1024 * 1024}
+	})
+
+	inspection, err = inspector.Inspect(*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: dir})
+	require.NoError(t, err)
+	require.Equal(t, []string{"package size 13 B exceeds the test limit of 10 B"}, inspection.Issues)
+}
+
+func TestPackageInspectorContainer(t *testing.T) {
+	servicePath := t.TempDir()
+	dockerfile := "FROM --platform=linux/amd64 node:18 AS build\nRUN npm ci\nFROM build AS final\nCOPY . .\n"
+	require.NoError(t, os.WriteFile(filepath.Join(servicePath, "Dockerfile"), []byte(dockerfile), osutil.PermissionFile))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).Respond(exec.NewRunResult(0, `[{
+		"Size": 2048,
+		"Os": "linux",
+		"Architecture": "amd64",
+		"Config": {"Env": ["PORT=80", "DB_PASSWORD=hunter2", "API_TOKEN="]}
+	}]`, ""))
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker history")
+	}).Respond(exec.NewRunResult(0, strings.Join([]string{
+		`{"ID":"sha256:2","CreatedBy":"COPY . .","Size":"1024"}`,
+		`{"ID":"sha256:1","CreatedBy":"ADD rootfs /","Size":"1024"}`,
+	}, "\n"), ""))
+
+	inspector := NewPackageInspector(docker.NewDocker(mockContext.CommandRunner))
+	serviceConfig := createTestServiceConfig("", ContainerAppTarget, ServiceLanguageJavaScript)
+	serviceConfig.Project.Path = servicePath
+
+	inspection, err := inspector.Inspect(*mockContext.Context, serviceConfig, &ServicePackageResult{
+		PackagePath: "api:azd-deploy",
+		Details:     &dockerPackageResult{ImageTag: "api:azd-deploy"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, PackageKindContainer, inspection.Kind)
+	require.Equal(t, int64(2048), inspection.SizeBytes)
+	require.Equal(t, &ContainerInspection{
+		Image:     "api:azd-deploy",
+		BaseImage: "node:18",
+		Platform:  "linux/amd64",
+		Layers: []ContainerLayer{
+			{CreatedBy: "ADD rootfs /", SizeBytes: 1024},
+			{CreatedBy: "COPY . .", SizeBytes: 1024},
+		},
+	}, inspection.Container)
+	require.Equal(t, []SecretFinding{{Location: "ENV DB_PASSWORD", Rule: "secret environment variable"}}, inspection.Secrets)
+}
+
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, contents := range files {
+		entry, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = entry.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+}
@@ -0,0 +1,173 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/otiai10/copy"
+)
+
+// Matches container image references, ex. nginx, contoso.azurecr.io/api:1.2.0 or ghcr.io/contoso/api@sha256:<digest>
+var imageReferenceRegex = regexp.MustCompile(
+	`^[a-zA-Z0-9]+([._-][a-zA-Z0-9]+)*(:[0-9]+)?(/[a-z0-9]+([._-][a-z0-9]+)*)*(:[\w][\w.-]{0,127})?(@sha256:[a-f0-9]{64})?$`)
+
+// PackageReference references an artifact built outside of azd, deployed by `azd deploy --from-package` instead of
+// building and packaging the service: an archive or a directory on disk, or a container image.
+type PackageReference struct {
+	// The absolute path of the archive or directory, empty for container images
+	Path  string
+	IsDir bool
+	// The container image reference, set when the reference doesn't exist on disk
+	Image string
+	// Whether the image is deployed from its registry as is, without being pulled and pushed to the registry of the
+	// environment, for `azd deploy --image`
+	Published bool
+}
+
+// NewPackageReference creates the reference of the artifact at the path, or of the container image when nothing
+// exists at the path.
+func NewPackageReference(value string) (*PackageReference, error) {
+	info, err := os.Stat(value)
+	if errors.Is(err, os.ErrNotExist) {
+		if !imageReferenceRegex.MatchString(value) {
+			return nil, fmt.Errorf("'%s' is neither an existing file or directory, nor a container image reference", value)
+		}
+
+		return &PackageReference{Image: value}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading package '%s': %w", value, err)
+	}
+
+	path, err := filepath.Abs(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackageReference{Path: path, IsDir: info.IsDir()}, nil
+}
+
+// NewImageReference creates the reference of the container image published to a registry, deployed as is
+func NewImageReference(value string) (*PackageReference, error) {
+	if !imageReferenceRegex.MatchString(value) {
+		return nil, fmt.Errorf("'%s' is not a container image reference", value)
+	}
+
+	return &PackageReference{Image: value, Published: true}, nil
+}
+
+// String returns the path or the container image referenced
+func (r *PackageReference) String() string {
+	if r.Image != "" {
+		return r.Image
+	}
+
+	return r.Path
+}
+
+// PackageReferenceTarget is implemented by the service targets able to deploy artifacts built outside of azd. The
+// package result returned is deployed as if it was returned by Package.
+type PackageReferenceTarget interface {
+	PackageFromReference(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		reference *PackageReference,
+	) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress]
+}
+
+// zipPackageFromReference creates the zip archive deployed to App Service and Function App resources, either compressing
+// the referenced directory or copying the referenced archive: deployments remove the zip archive once deployed.
+func zipPackageFromReference(
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			if reference.Path == "" {
+				task.SetError(fmt.Errorf(
+					"package '%s' does not exist, service '%s' is deployed from a zip archive or a directory",
+					reference.Image, serviceConfig.Name))
+				return
+			}
+
+			if reference.IsDir {
+				task.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
+				zipFilePath, err := createDeployableZip(serviceConfig.Name, reference.Path)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+
+				task.SetResult(&ServicePackageResult{PackagePath: zipFilePath})
+				return
+			}
+
+			if filepath.Ext(reference.Path) != ".zip" {
+				task.SetError(fmt.Errorf("package '%s' is not a zip archive", reference.Path))
+				return
+			}
+
+			zipFilePath, err := copyPackageArtifact(reference.Path, ".zip")
+			if err != nil {
+				task.SetError(fmt.Errorf("copying package '%s': %w", reference.Path, err))
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{PackagePath: zipFilePath})
+		},
+	)
+}
+
+// directoryPackageFromReference creates the package result of the referenced directory, created by packageDirectory
+// when the reference is a file accepted by the service target.
+func directoryPackageFromReference(
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+	packageDirectory func(path string) (string, error),
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			if reference.Path == "" {
+				task.SetError(fmt.Errorf(
+					"package '%s' does not exist, service '%s' is deployed from a directory",
+					reference.Image, serviceConfig.Name))
+				return
+			}
+
+			if reference.IsDir {
+				task.SetResult(&ServicePackageResult{PackagePath: reference.Path})
+				return
+			}
+
+			dir, err := packageDirectory(reference.Path)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{PackagePath: dir})
+		},
+	)
+}
+
+// copyToPackageDirectory copies the file to a new temporary directory, under the name expected by the service target
+func copyToPackageDirectory(path string, name string) (string, error) {
+	dir, err := os.MkdirTemp("", "azdpackage")
+	if err != nil {
+		return "", err
+	}
+
+	if err := copy.Copy(path, filepath.Join(dir, name)); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("copying package '%s': %w", path, err)
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,133 @@
+package project
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewPackageReference(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "api.zip")
+	writeFile(t, archivePath, "package")
+
+	t.Run("Archive", func(t *testing.T) {
+		reference, err := NewPackageReference(archivePath)
+		require.NoError(t, err)
+		require.Equal(t, &PackageReference{Path: archivePath}, reference)
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		reference, err := NewPackageReference(dir)
+		require.NoError(t, err)
+		require.Equal(t, &PackageReference{Path: dir, IsDir: true}, reference)
+	})
+
+	t.Run("Image", func(t *testing.T) {
+		for _, image := range []string{
+			"nginx",
+			"contoso.azurecr.io/api:1.2.0",
+			"localhost:5000/contoso/web",
+			"ghcr.io/contoso/api@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		} {
+			reference, err := NewPackageReference(image)
+			require.NoError(t, err)
+			require.Equal(t, &PackageReference{Image: image}, reference)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewPackageReference(filepath.Join(dir, "missing", "api.zip"))
+		require.Error(t, err)
+	})
+}
+
+func Test_NewImageReference(t *testing.T) {
+	reference, err := NewImageReference("contoso.azurecr.io/api:1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, &PackageReference{Image: "contoso.azurecr.io/api:1.2.3", Published: true}, reference)
+
+	_, err = NewImageReference("./build/api.zip")
+	require.Error(t, err)
+}
+
+func Test_zipPackageFromReference(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+
+	t.Run("Archive", func(t *testing.T) {
+		archivePath := filepath.Join(t.TempDir(), "api.zip")
+		writeFile(t, archivePath, "package")
+
+		result, err := zipPackageFromReference(serviceConfig, &PackageReference{Path: archivePath}).Await()
+		require.NoError(t, err)
+		defer os.Remove(result.PackagePath)
+
+		// The deployment removes the package, the referenced archive is copied
+		require.NotEqual(t, archivePath, result.PackagePath)
+		contents, err := os.ReadFile(result.PackagePath)
+		require.NoError(t, err)
+		require.Equal(t, "package", string(contents))
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "index.js"), "console.log('hello')")
+
+		packageTask := zipPackageFromReference(serviceConfig, &PackageReference{Path: dir, IsDir: true})
+		logProgress(packageTask)
+
+		result, err := packageTask.Await()
+		require.NoError(t, err)
+		defer os.Remove(result.PackagePath)
+
+		archive, err := zip.OpenReader(result.PackagePath)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		require.Len(t, archive.File, 1)
+		require.Equal(t, "index.js", archive.File[0].Name)
+	})
+
+	t.Run("NotZip", func(t *testing.T) {
+		jarPath := filepath.Join(t.TempDir(), "api.jar")
+		writeFile(t, jarPath, "package")
+
+		_, err := zipPackageFromReference(serviceConfig, &PackageReference{Path: jarPath}).Await()
+		require.Error(t, err)
+	})
+
+	t.Run("Image", func(t *testing.T) {
+		_, err := zipPackageFromReference(serviceConfig, &PackageReference{Image: "build/api.zip"}).Await()
+		require.ErrorContains(t, err, "does not exist")
+	})
+}
+
+func Test_SpringAppTarget_PackageFromReference(t *testing.T) {
+	ctx := context.Background()
+	serviceConfig := createTestServiceConfig("./src/api", SpringAppTarget, ServiceLanguageJava)
+	target := &springAppTarget{}
+
+	jarPath := filepath.Join(t.TempDir(), "api-1.2.0.jar")
+	writeFile(t, jarPath, "jar")
+
+	result, err := target.PackageFromReference(ctx, serviceConfig, &PackageReference{Path: jarPath}).Await()
+	require.NoError(t, err)
+	defer os.RemoveAll(result.PackagePath)
+
+	contents, err := os.ReadFile(filepath.Join(result.PackagePath, AppServiceJavaPackageName+".jar"))
+	require.NoError(t, err)
+	require.Equal(t, "jar", string(contents))
+
+	dirReference := &PackageReference{Path: serviceConfig.Path(), IsDir: true}
+	_, err = target.PackageFromReference(ctx, serviceConfig, dirReference).Await()
+	require.NoError(t, err)
+
+	zipPath := filepath.Join(t.TempDir(), "api.zip")
+	writeFile(t, zipPath, "zip")
+	_, err = target.PackageFromReference(ctx, serviceConfig, &PackageReference{Path: zipPath}).Await()
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/blang/semver/v4"
 	"golang.org/x/exp/slices"
@@ -87,6 +88,10 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 		if err != nil {
 			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
 		}
+
+		if err := svc.validateEnv(); err != nil {
+			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
+		}
 	}
 
 	if projectConfig.Infra.Path == "" {
@@ -145,7 +150,15 @@ func Load(ctx context.Context, projectFilePath string) (*ProjectConfig, error) {
 		tracing.SetUsageAttributes(fields.ProjectServiceHostsKey.StringSlice(hosts))
 	}
 
+	infraProvider := projectConfig.Infra.Provider
+	if infraProvider == "" {
+		infraProvider = provisioning.Bicep
+	}
+	tracing.SetUsageAttributes(fields.ProjectInfraProviderKey.String(string(infraProvider)))
+
 	projectConfig.Path = filepath.Dir(projectFilePath)
+	useAppHostInfra(projectConfig)
+
 	return projectConfig, nil
 }
 
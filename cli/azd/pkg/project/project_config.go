@@ -12,15 +12,16 @@ import (
 // When changing project structure, make sure to update the JSON schema file for azure.yaml (<workspace
 // root>/schemas/vN.M/azure.yaml.json).
 type ProjectConfig struct {
-	RequiredVersions  *RequiredVersions          `yaml:"requiredVersions,omitempty"`
-	Name              string                     `yaml:"name"`
-	ResourceGroupName ExpandableString           `yaml:"resourceGroup,omitempty"`
-	Path              string                     `yaml:",omitempty"`
-	Metadata          *ProjectMetadata           `yaml:"metadata,omitempty"`
-	Services          map[string]*ServiceConfig  `yaml:",omitempty"`
-	Infra             provisioning.Options       `yaml:"infra,omitempty"`
-	Pipeline          PipelineOptions            `yaml:"pipeline,omitempty"`
-	Hooks             map[string]*ext.HookConfig `yaml:"hooks,omitempty"`
+	RequiredVersions  *RequiredVersions         `yaml:"requiredVersions,omitempty"`
+	Name              string                    `yaml:"name"`
+	ResourceGroupName ExpandableString          `yaml:"resourceGroup,omitempty"`
+	Path              string                    `yaml:",omitempty"`
+	Metadata          *ProjectMetadata          `yaml:"metadata,omitempty"`
+	Services          map[string]*ServiceConfig `yaml:",omitempty"`
+	Infra             provisioning.Options      `yaml:"infra,omitempty"`
+	Pipeline          PipelineOptions           `yaml:"pipeline,omitempty"`
+	Monitor           *MonitorOptions           `yaml:"monitor,omitempty"`
+	Hooks             ext.HooksConfig           `yaml:"hooks,omitempty"`
 
 	*ext.EventDispatcher[ProjectLifecycleEventArgs] `yaml:",omitempty"`
 }
@@ -35,6 +36,36 @@ type RequiredVersions struct {
 // options supported in azure.yaml
 type PipelineOptions struct {
 	Provider string `yaml:"provider"`
+	// Configures the environment values pushed to the CI system by `azd env sync`
+	Sync *EnvSyncOptions `yaml:"sync,omitempty"`
+}
+
+// EnvSyncOptions selects the environment values synced to the secrets & variables of the CI system. Patterns are globs
+// matching the keys of environment values, ex. SERVICE_*_URI.
+type EnvSyncOptions struct {
+	// The values synced, all values by default
+	Include []string `yaml:"include,omitempty"`
+	// The values never synced
+	Exclude []string `yaml:"exclude,omitempty"`
+	// The values synced as secrets, other values are synced as variables. Defaults to keys naming secrets, passwords,
+	// tokens, keys & connection strings.
+	Secrets []string `yaml:"secrets,omitempty"`
+	// The names of the secrets & variables, by key of the environment value. Defaults to the key.
+	Map map[string]string `yaml:"map,omitempty"`
+}
+
+// MonitorOptions configures how `azd monitor` presents the telemetry of the application
+type MonitorOptions struct {
+	Workbook *WorkbookOptions `yaml:"workbook,omitempty"`
+}
+
+// WorkbookOptions describes the Azure Monitor workbook created for each environment
+type WorkbookOptions struct {
+	// The path, relative to the project, of the workbook template exported from the Azure portal (gallery template).
+	// Environment variables in the template are substituted, ex. ${AZURE_RESOURCE_GROUP}.
+	Path string `yaml:"path"`
+	// The display name of the workbook, defaults to the name of the project & environment
+	DisplayName ExpandableString `yaml:"displayName,omitempty"`
 }
 
 // Project lifecycle event arguments
@@ -9,8 +9,10 @@ import (
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // Tests invalid project configurations.
@@ -148,6 +150,65 @@ services:
 	require.Equal(t, "../", service.Docker.Context)
 }
 
+func TestProjectWithHooks(t *testing.T) {
+	const testProj = `
+name: test-proj
+hooks:
+  preprovision:
+    shell: sh
+    run: scripts/preprovision.sh
+  postprovision:
+    - shell: sh
+      run: scripts/seed.sh
+      workingDirectory: scripts
+    - shell: python
+      run: |
+        print('hello')
+        print('world')
+services:
+  web:
+    project: src/web
+    language: js
+    host: containerapp
+    hooks:
+      predeploy:
+        windows:
+          shell: pwsh
+          run: scripts/predeploy.ps1
+        posix:
+          shell: sh
+          run: scripts/predeploy.sh
+`
+
+	mockContext := mocks.NewMockContext(context.Background())
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	require.Len(t, projectConfig.Hooks["preprovision"], 1)
+	require.Equal(t, "scripts/preprovision.sh", projectConfig.Hooks["preprovision"][0].Run)
+
+	postprovision := projectConfig.Hooks["postprovision"]
+	require.Len(t, postprovision, 2)
+	require.Equal(t, "scripts", postprovision[0].WorkingDirectory)
+	require.Equal(t, ext.ShellTypePython, postprovision[1].Shell)
+	require.Equal(t, "print('hello')\nprint('world')\n", postprovision[1].Run)
+
+	predeploy := projectConfig.Services["web"].Hooks["predeploy"]
+	require.Len(t, predeploy, 1)
+	require.Equal(t, "scripts/predeploy.ps1", predeploy[0].Windows.Run)
+	require.Equal(t, "scripts/predeploy.sh", predeploy[0].Posix.Run)
+
+	// Single hooks are written back as objects and multiple hooks as lists
+	contents, err := yaml.Marshal(projectConfig.Hooks)
+	require.NoError(t, err)
+
+	var roundTrip ext.HooksConfig
+	require.NoError(t, yaml.Unmarshal(contents, &roundTrip))
+	require.Len(t, roundTrip["preprovision"], 1)
+	require.Len(t, roundTrip["postprovision"], 2)
+	require.Contains(t, string(contents), "preprovision:\n    shell: sh\n")
+}
+
 func TestProjectConfigAddHandler(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	project := getProjectConfig()
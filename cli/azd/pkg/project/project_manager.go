@@ -9,6 +9,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 )
 
 const (
@@ -33,7 +34,8 @@ type ProjectManager interface {
 	// with the service config that enables the scenario for these components to add event
 	// handlers to participate in the lifecycle of an azd project
 	//
-	// The initialization process will also ensure that all required tools are installed
+	// Required tools are not checked during initialization. Commands call the Ensure*Tools methods
+	// for the services & phases they run so unused tools never cause failures.
 	Initialize(ctx context.Context, projectConfig *ProjectConfig) error
 
 	// Returns the default service name to target based on the current working directory.
@@ -61,38 +63,34 @@ type ServiceFilterPredicate func(svc *ServiceConfig) bool
 type projectManager struct {
 	azdContext     *azdcontext.AzdContext
 	serviceManager ServiceManager
+	dotnetCli      dotnet.DotNetCli
 }
 
 // NewProjectManager creates a new instance of the ProjectManager
 func NewProjectManager(
 	azdContext *azdcontext.AzdContext,
 	serviceManager ServiceManager,
+	dotnetCli dotnet.DotNetCli,
 ) ProjectManager {
 	return &projectManager{
 		azdContext:     azdContext,
 		serviceManager: serviceManager,
+		dotnetCli:      dotnetCli,
 	}
 }
 
-// Initializes the project and all child services defined within the project configuration
+// Initializes the project and all child services defined within the project configuration.
+// Required tools are not checked here, commands ensure the tools needed by the selected services & phase.
+// .NET Aspire app hosts are expanded into a service for each project of their manifest.
 func (pm *projectManager) Initialize(ctx context.Context, projectConfig *ProjectConfig) error {
-	var projectTools []tools.ExternalTool
+	if err := pm.expandAppHosts(ctx, projectConfig); err != nil {
+		return fmt.Errorf("initializing app host: %w", err)
+	}
 
 	for _, svc := range projectConfig.Services {
 		if err := pm.serviceManager.Initialize(ctx, svc); err != nil {
 			return fmt.Errorf("initializing service '%s', %w", svc.Name, err)
 		}
-
-		svcTools, err := pm.serviceManager.GetRequiredTools(ctx, svc)
-		if err != nil {
-			return fmt.Errorf("getting service required tools: %w", err)
-		}
-
-		projectTools = append(projectTools, svcTools...)
-	}
-
-	if err := tools.EnsureInstalled(ctx, tools.Unique(projectTools)...); err != nil {
-		return err
 	}
 
 	return nil
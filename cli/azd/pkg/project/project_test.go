@@ -229,6 +229,76 @@ services:
 	}
 }
 
+func TestServiceSubscriptionOverrideFromProjectFile(t *testing.T) {
+	const testProj = `
+name: test-proj
+metadata:
+  template: test-proj-template
+resourceGroup: rg-test
+services:
+  web:
+    project: src/web
+    language: js
+    host: appservice
+  api:
+    subscription: ${API_SUBSCRIPTION_ID}
+    resourceGroup: rg-shared
+    project: src/api
+    language: js
+    host: appservice
+`
+	mockContext := mocks.NewMockContext(context.Background())
+
+	for _, resourceGroupName := range []string{"rg-test", "rg-shared"} {
+		mockarmresources.AddAzResourceListMock(
+			mockContext.HttpClient,
+			convert.RefOf(resourceGroupName),
+			[]*armresources.GenericResourceExpanded{
+				{
+					ID:       convert.RefOf(resourceGroupName + "-app"),
+					Name:     convert.RefOf(resourceGroupName + "-app"),
+					Type:     convert.RefOf(string(infra.AzureResourceTypeWebSite)),
+					Location: convert.RefOf("eastus2"),
+					Tags: map[string]*string{
+						azure.TagKeyAzdServiceName: convert.RefOf(map[string]string{
+							"rg-test":   "web",
+							"rg-shared": "api",
+						}[resourceGroupName]),
+					},
+				},
+			})
+	}
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+	env := environment.EphemeralWithValues("envA", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"API_SUBSCRIPTION_ID":                "API_SUBSCRIPTION_ID",
+	})
+
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	resourceManager := NewResourceManager(env, azCli)
+
+	targetResource, err := resourceManager.GetTargetResource(
+		*mockContext.Context, env.GetSubscriptionId(), projectConfig.Services["web"])
+	require.NoError(t, err)
+	require.Equal(t, "SUBSCRIPTION_ID", targetResource.SubscriptionId())
+	require.Equal(t, "rg-test", targetResource.ResourceGroupName())
+	require.Equal(t, "rg-test-app", targetResource.ResourceName())
+
+	targetResource, err = resourceManager.GetTargetResource(
+		*mockContext.Context, env.GetSubscriptionId(), projectConfig.Services["api"])
+	require.NoError(t, err)
+	require.Equal(t, "API_SUBSCRIPTION_ID", targetResource.SubscriptionId())
+	require.Equal(t, "rg-shared", targetResource.ResourceGroupName())
+	require.Equal(t, "rg-shared-app", targetResource.ResourceName())
+
+	subscriptionId, err := resourceManager.GetServiceSubscriptionId(projectConfig.Services["api"])
+	require.NoError(t, err)
+	require.Equal(t, "API_SUBSCRIPTION_ID", subscriptionId)
+}
+
 func Test_Invalid_Project_File(t *testing.T) {
 	tests := map[string]string{
 		"Empty":      "",
@@ -252,3 +322,32 @@ func Test_Invalid_Project_File(t *testing.T) {
 		})
 	}
 }
+
+func Test_External_Service_Target(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  api:
+    project: src/api
+    host: edge-device
+    language: python
+    config:
+      fleet: west
+`
+
+	_, err := Parse(context.Background(), testProj)
+	require.Error(t, err)
+
+	require.NoError(t, RegisterExternalServiceTarget("edge-device"))
+	t.Cleanup(func() {
+		delete(externalServiceTargets, "edge-device")
+	})
+
+	projectConfig, err := Parse(context.Background(), testProj)
+	require.NoError(t, err)
+	require.Equal(t, ServiceTargetKind("edge-device"), projectConfig.Services["api"].Host)
+	require.Equal(t, "west", projectConfig.Services["api"].Config["fleet"])
+	require.True(t, projectConfig.Services["api"].Host.SupportsDelayedProvisioning())
+
+	require.Error(t, RegisterExternalServiceTarget(ContainerAppTarget))
+}
@@ -18,6 +18,8 @@ import (
 // to the Azure resource hosting the application
 type ResourceManager interface {
 	GetResourceGroupName(ctx context.Context, subscriptionId string, projectConfig *ProjectConfig) (string, error)
+	GetServiceSubscriptionId(serviceConfig *ServiceConfig) (string, error)
+	GetServiceResourceGroupName(ctx context.Context, subscriptionId string, serviceConfig *ServiceConfig) (string, error)
 	GetServiceResources(
 		ctx context.Context,
 		subscriptionId string,
@@ -88,6 +90,46 @@ func (rm *resourceManager) GetResourceGroupName(
 	return resourceGroupName, nil
 }
 
+// GetServiceSubscriptionId gets the subscription of the Azure resources of the service, the user defined value in
+// `azure.yaml` or the subscription of the environment.
+func (rm *resourceManager) GetServiceSubscriptionId(serviceConfig *ServiceConfig) (string, error) {
+	return rm.serviceSubscriptionId(rm.env.GetSubscriptionId(), serviceConfig)
+}
+
+func (rm *resourceManager) serviceSubscriptionId(subscriptionId string, serviceConfig *ServiceConfig) (string, error) {
+	name, err := serviceConfig.Subscription.Envsubst(rm.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("expanding subscription of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if strings.TrimSpace(name) != "" {
+		return strings.TrimSpace(name), nil
+	}
+
+	return subscriptionId, nil
+}
+
+// GetServiceResourceGroupName gets the resource group of the Azure resources of the service.
+//
+// The user defined value of the service in `azure.yaml` takes precedence over the resource group of the project (see
+// `resourceManager.GetResourceGroupName`), which is resolved in the subscription of the service.
+func (rm *resourceManager) GetServiceResourceGroupName(
+	ctx context.Context,
+	subscriptionId string,
+	serviceConfig *ServiceConfig,
+) (string, error) {
+	name, err := serviceConfig.ResourceGroupName.Envsubst(rm.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("expanding resource group of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if strings.TrimSpace(name) != "" {
+		return strings.TrimSpace(name), nil
+	}
+
+	return rm.GetResourceGroupName(ctx, subscriptionId, serviceConfig.Project)
+}
+
 // GetServiceResources finds azure service resources targeted by the service.
 //
 // If an explicit `ResourceName` is specified in `azure.yaml`, a resource with that name is searched for.
@@ -191,11 +233,22 @@ func (rm *resourceManager) GetTargetResource(
 	subscriptionId string,
 	serviceConfig *ServiceConfig,
 ) (*environment.TargetResource, error) {
-	resourceGroupName, err := rm.GetResourceGroupName(ctx, subscriptionId, serviceConfig.Project)
+	// Services may be hosted in a subscription other than the subscription of the environment
+	subscriptionId, err := rm.serviceSubscriptionId(subscriptionId, serviceConfig)
 	if err != nil {
 		return nil, err
 	}
 
+	resourceGroupName, err := rm.GetServiceResourceGroupName(ctx, subscriptionId, serviceConfig)
+	if err != nil {
+		// External service targets are not required to deploy to an Azure resource group
+		if IsExternalServiceTarget(ServiceTargetKind(serviceConfig.Host)) {
+			return environment.NewTargetResource(subscriptionId, "", "", ""), nil
+		}
+
+		return nil, err
+	}
+
 	azureResource, err := rm.resolveServiceResource(ctx, subscriptionId, resourceGroupName, serviceConfig, "provision")
 	if err != nil {
 		return nil, err
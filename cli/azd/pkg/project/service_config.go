@@ -14,6 +14,10 @@ type ServiceConfig struct {
 	Name string
 	// The name used to override the default azure resource name
 	ResourceName ExpandableString `yaml:"resourceName"`
+	// The subscription of the Azure resource hosting the service, when different from the subscription of the environment
+	Subscription ExpandableString `yaml:"subscription,omitempty"`
+	// The resource group of the Azure resource hosting the service, when different from the resource group of the project
+	ResourceGroupName ExpandableString `yaml:"resourceGroup,omitempty"`
 	// The relative path to the project folder from the project root
 	RelativePath string `yaml:"project"`
 	// The azure hosting model to use, ex) appservice, function, containerapp
@@ -24,6 +28,8 @@ type ServiceConfig struct {
 	OutputPath string `yaml:"dist"`
 	// The optional docker options
 	Docker DockerProjectOptions `yaml:"docker"`
+	// The registry the images of the service are pushed to, the Azure Container Registry of the environment by default
+	Registry *ContainerRegistryConfig `yaml:"registry,omitempty"`
 	// The optional K8S / AKS options
 	K8s AksOptions `yaml:"k8s"`
 	// The optional Azure Spring Apps options
@@ -31,7 +37,20 @@ type ServiceConfig struct {
 	// The infrastructure provisioning configuration
 	Infra provisioning.Options `yaml:"infra"`
 	// Hook configuration for service
-	Hooks map[string]*ext.HookConfig `yaml:"hooks,omitempty"`
+	Hooks ext.HooksConfig `yaml:"hooks,omitempty"`
+	// The environment variables of the service applied to its host when it's deployed, ex. the app settings of an app
+	// service. The values support references to the values of the environment, ex. ${AZURE_SQL_CONNECTION_STRING}.
+	Env map[string]ExpandableString `yaml:"env,omitempty"`
+	// Settings passed to service targets provided by extensions
+	Config map[string]any `yaml:"config,omitempty"`
+	// The health endpoint evaluated by `azd health`
+	Health *ServiceHealthOptions `yaml:"health,omitempty"`
+	// The tests run by `azd test`
+	Test *ServiceTestOptions `yaml:"test,omitempty"`
+	// The smoke tests run after the service is deployed
+	Smoke *ServiceSmokeOptions `yaml:"smoke,omitempty"`
+	// The custom domain bound to the service after it is deployed
+	CustomDomain *ServiceCustomDomainOptions `yaml:"customDomain,omitempty"`
 
 	*ext.EventDispatcher[ServiceLifecycleEventArgs] `yaml:",omitempty"`
 
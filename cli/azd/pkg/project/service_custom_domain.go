@@ -0,0 +1,310 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	defaultCustomDomainDnsTimeout = 15 * time.Minute
+	customDomainDnsInterval       = 10 * time.Second
+)
+
+// ServiceCustomDomainOptions describes the custom domain bound to the service after it is deployed, ex. www.contoso.com
+type ServiceCustomDomainOptions struct {
+	// The host name of the custom domain, ex. www.contoso.com. Supports environment substitutions.
+	Name ExpandableString `yaml:"name"`
+	// The resource id of the certificate of the domain, an App Service certificate or a certificate of the Container Apps
+	// environment. A free managed certificate is created when empty.
+	Certificate ExpandableString `yaml:"certificate,omitempty"`
+	// The maximum time waiting for the DNS records of the domain, ex. 30m. Defaults to 15m.
+	DnsTimeout string `yaml:"dnsTimeout,omitempty"`
+}
+
+// DnsRecord is a DNS record the owner of a custom domain creates so the domain can be bound to a service
+type DnsRecord struct {
+	// The type of the record, CNAME or TXT
+	Type string `json:"type"`
+	// The fully qualified name of the record, ex. asuid.www.contoso.com
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CustomDomain is the custom domain of a service, with the DNS records binding it to the resource hosting the service
+type CustomDomain struct {
+	HostName string      `json:"hostName"`
+	Records  []DnsRecord `json:"records"`
+
+	host          ServiceTargetKind
+	target        *environment.TargetResource
+	certificateId string
+	dnsTimeout    time.Duration
+	// The location and App Service plan of app services, the location and environment of container apps
+	location      string
+	serverFarmId  string
+	environmentId string
+}
+
+// Endpoint returns the endpoint of the service on the custom domain
+func (d *CustomDomain) Endpoint() string {
+	return fmt.Sprintf("https://%s/", d.HostName)
+}
+
+// DnsResolver resolves the DNS records of custom domains
+type DnsResolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// ServiceCustomDomainBinder binds the custom domains of deployed services to the App Service or Container Apps hosting
+// them, with TLS certificates
+type ServiceCustomDomainBinder struct {
+	env                 *environment.Environment
+	resourceManager     ResourceManager
+	containerAppService containerapps.ContainerAppService
+	azCli               azcli.AzCli
+	resolver            DnsResolver
+	// The delay between the lookups of the DNS records of a domain
+	interval time.Duration
+}
+
+// NewServiceCustomDomainBinder creates a new instance of the ServiceCustomDomainBinder
+func NewServiceCustomDomainBinder(
+	env *environment.Environment,
+	resourceManager ResourceManager,
+	containerAppService containerapps.ContainerAppService,
+	azCli azcli.AzCli,
+) *ServiceCustomDomainBinder {
+	return &ServiceCustomDomainBinder{
+		env:                 env,
+		resourceManager:     resourceManager,
+		containerAppService: containerAppService,
+		azCli:               azCli,
+		resolver:            net.DefaultResolver,
+		interval:            customDomainDnsInterval,
+	}
+}
+
+// Resolve returns the custom domain of the service, with the DNS records verifying the ownership of the domain and
+// pointing it to the resource hosting the service
+func (b *ServiceCustomDomainBinder) Resolve(ctx context.Context, serviceConfig *ServiceConfig) (*CustomDomain, error) {
+	options := serviceConfig.CustomDomain
+	if options == nil {
+		return nil, fmt.Errorf("service '%s' does not define a custom domain", serviceConfig.Name)
+	}
+
+	hostName, err := options.Name.Envsubst(b.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("expanding custom domain of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	hostName = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostName)), ".")
+	if hostName == "" || strings.Contains(hostName, "/") || !strings.Contains(hostName, ".") {
+		return nil, fmt.Errorf(
+			"custom domain of service '%s' must be a host name, ex. www.contoso.com, got '%s'", serviceConfig.Name, hostName)
+	}
+
+	certificateId, err := options.Certificate.Envsubst(b.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("expanding custom domain certificate of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	dnsTimeout := defaultCustomDomainDnsTimeout
+	if options.DnsTimeout != "" {
+		if dnsTimeout, err = time.ParseDuration(options.DnsTimeout); err != nil {
+			return nil, fmt.Errorf("parsing custom domain DNS timeout of service '%s': %w", serviceConfig.Name, err)
+		}
+	}
+
+	target, err := b.resourceManager.GetTargetResource(ctx, b.env.GetSubscriptionId(), serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource: %w", err)
+	}
+
+	domain := &CustomDomain{
+		HostName:      hostName,
+		host:          serviceConfig.Host,
+		target:        target,
+		certificateId: strings.TrimSpace(certificateId),
+		dnsTimeout:    dnsTimeout,
+	}
+
+	var targetHostName, verificationId string
+	switch serviceConfig.Host {
+	case ContainerAppTarget:
+		verification, err := b.containerAppService.GetCustomDomainVerification(
+			ctx, target.SubscriptionId(), target.ResourceGroupName(), target.ResourceName())
+		if err != nil {
+			return nil, err
+		}
+
+		targetHostName, verificationId = verification.Fqdn, verification.VerificationId
+		domain.location, domain.environmentId = verification.Location, verification.EnvironmentId
+	case "", AppServiceTarget, AzureFunctionTarget:
+		properties, err := b.azCli.GetAppServiceProperties(
+			ctx, target.SubscriptionId(), target.ResourceGroupName(), target.ResourceName())
+		if err != nil {
+			return nil, err
+		}
+
+		targetHostName, verificationId = properties.HostNames[0], properties.CustomDomainVerificationId
+		domain.location, domain.serverFarmId = properties.Location, properties.ServerFarmId
+	default:
+		return nil, fmt.Errorf(
+			"custom domains are not supported by host '%s' of service '%s', only by %s, %s and %s",
+			serviceConfig.Host, serviceConfig.Name, AppServiceTarget, AzureFunctionTarget, ContainerAppTarget)
+	}
+
+	domain.Records = []DnsRecord{
+		{Type: "CNAME", Name: hostName, Value: targetHostName},
+		{Type: "TXT", Name: "asuid." + hostName, Value: verificationId},
+	}
+
+	return domain, nil
+}
+
+// MissingRecords returns the DNS records of the domain that don't resolve to their value yet
+func (b *ServiceCustomDomainBinder) MissingRecords(ctx context.Context, domain *CustomDomain) []DnsRecord {
+	missing := []DnsRecord{}
+	for _, record := range domain.Records {
+		if !b.resolves(ctx, record) {
+			missing = append(missing, record)
+		}
+	}
+
+	return missing
+}
+
+// WaitForRecords waits until the DNS records of the domain resolve, an error listing the missing records is returned
+// when they don't resolve within the DNS timeout of the domain
+func (b *ServiceCustomDomainBinder) WaitForRecords(ctx context.Context, domain *CustomDomain) error {
+	deadline := time.Now().Add(domain.dnsTimeout)
+
+	for {
+		missing := b.MissingRecords(ctx, domain)
+		if len(missing) == 0 {
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			lines := []string{fmt.Sprintf(
+				"the DNS records of custom domain %s did not resolve within %s:", domain.HostName, domain.dnsTimeout)}
+			for _, record := range missing {
+				lines = append(lines, fmt.Sprintf("  - %s %s %s", record.Type, record.Name, record.Value))
+			}
+
+			return errors.New(strings.Join(lines, "\n"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(b.interval):
+		}
+	}
+}
+
+// Bind binds the domain to the resource hosting the service with TLS, with the certificate of the domain or a managed
+// certificate. The DNS records of the domain must resolve. Bind reports its steps to progress.
+func (b *ServiceCustomDomainBinder) Bind(ctx context.Context, domain *CustomDomain, progress func(string)) error {
+	target := domain.target
+
+	switch domain.host {
+	case ContainerAppTarget:
+		bind := func(certificateId string) error {
+			return b.containerAppService.BindCustomDomain(ctx, target.SubscriptionId(), target.ResourceGroupName(),
+				target.ResourceName(), domain.HostName, certificateId)
+		}
+
+		if domain.certificateId != "" {
+			return bind(domain.certificateId)
+		}
+
+		// Managed certificates are issued for the domains bound to the container apps of the environment
+		progress("Binding host name")
+		if err := bind(""); err != nil {
+			return err
+		}
+
+		progress("Creating managed certificate")
+		certificateId, err := b.containerAppService.CreateManagedCertificate(
+			ctx, target.SubscriptionId(), domain.environmentId, domain.location, domain.HostName)
+		if err != nil {
+			return err
+		}
+
+		progress("Binding certificate")
+		return bind(certificateId)
+	default:
+		bind := func(thumbprint string) error {
+			return b.azCli.BindAppServiceHostName(ctx, target.SubscriptionId(), target.ResourceGroupName(),
+				target.ResourceName(), domain.HostName, thumbprint)
+		}
+
+		if domain.certificateId != "" {
+			thumbprint, err := b.azCli.GetAppServiceCertificateThumbprint(ctx, target.SubscriptionId(), domain.certificateId)
+			if err != nil {
+				return err
+			}
+
+			return bind(thumbprint)
+		}
+
+		// Managed certificates are issued for the host names bound to the apps of the App Service plan
+		progress("Binding host name")
+		if err := bind(""); err != nil {
+			return err
+		}
+
+		progress("Creating managed certificate")
+		thumbprint, err := b.azCli.CreateAppServiceManagedCertificate(ctx, target.SubscriptionId(),
+			target.ResourceGroupName(), domain.location, domain.serverFarmId, domain.HostName)
+		if err != nil {
+			return err
+		}
+
+		progress("Binding certificate")
+		return bind(thumbprint)
+	}
+}
+
+// resolves returns whether the record resolves to its value. CNAME records match when the domain has the canonical
+// name of their value, since the host names of App Service and Container Apps are CNAME records themselves.
+func (b *ServiceCustomDomainBinder) resolves(ctx context.Context, record DnsRecord) bool {
+	switch record.Type {
+	case "CNAME":
+		canonicalName, err := b.resolver.LookupCNAME(ctx, record.Name)
+		if err != nil {
+			return false
+		}
+
+		canonicalName = normalizeDnsName(canonicalName)
+		if canonicalName == normalizeDnsName(record.Value) {
+			return true
+		}
+
+		targetName, err := b.resolver.LookupCNAME(ctx, record.Value)
+		return err == nil && canonicalName != normalizeDnsName(record.Name) && canonicalName == normalizeDnsName(targetName)
+	case "TXT":
+		values, err := b.resolver.LookupTXT(ctx, record.Name)
+		return err == nil && slices.Contains(values, record.Value)
+	}
+
+	return false
+}
+
+func normalizeDnsName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
@@ -0,0 +1,269 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/stretchr/testify/require"
+)
+
+type customDomainResolver struct {
+	cnames map[string]string
+	txts   map[string][]string
+}
+
+func (r *customDomainResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	if cname, has := r.cnames[host]; has {
+		return cname + ".", nil
+	}
+
+	return host + ".", nil
+}
+
+func (r *customDomainResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if values, has := r.txts[name]; has {
+		return values, nil
+	}
+
+	return nil, errors.New("no such host")
+}
+
+type customDomainContainerAppService struct {
+	containerapps.ContainerAppService
+	bound []string
+}
+
+func (s *customDomainContainerAppService) GetCustomDomainVerification(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (*containerapps.ContainerAppCustomDomainVerification, error) {
+	return &containerapps.ContainerAppCustomDomainVerification{
+		Fqdn:           "api.azurecontainerapps.io",
+		VerificationId: "VERIFICATION_ID",
+		EnvironmentId:  "ENVIRONMENT_ID",
+		Location:       "eastus2",
+	}, nil
+}
+
+func (s *customDomainContainerAppService) BindCustomDomain(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	hostName string,
+	certificateId string,
+) error {
+	s.bound = append(s.bound, certificateId)
+	return nil
+}
+
+func (s *customDomainContainerAppService) CreateManagedCertificate(
+	ctx context.Context,
+	subscriptionId string,
+	environmentId string,
+	location string,
+	hostName string,
+) (string, error) {
+	return environmentId + "/managedCertificates/" + hostName, nil
+}
+
+type customDomainAzCli struct {
+	azcli.AzCli
+	thumbprints []string
+}
+
+func (c *customDomainAzCli) GetAppServiceProperties(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	applicationName string,
+) (*azcli.AzCliAppServiceProperties, error) {
+	return &azcli.AzCliAppServiceProperties{
+		HostNames:                  []string{"web.azurewebsites.net"},
+		Location:                   "eastus2",
+		ServerFarmId:               "PLAN_ID",
+		CustomDomainVerificationId: "VERIFICATION_ID",
+	}, nil
+}
+
+func (c *customDomainAzCli) BindAppServiceHostName(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	hostName string,
+	thumbprint string,
+) error {
+	c.thumbprints = append(c.thumbprints, thumbprint)
+	return nil
+}
+
+func (c *customDomainAzCli) CreateAppServiceManagedCertificate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	location string,
+	serverFarmId string,
+	hostName string,
+) (string, error) {
+	return "MANAGED_THUMBPRINT", nil
+}
+
+func (c *customDomainAzCli) GetAppServiceCertificateThumbprint(
+	ctx context.Context,
+	subscriptionId string,
+	certificateId string,
+) (string, error) {
+	return "CERTIFICATE_THUMBPRINT", nil
+}
+
+func newTestCustomDomainBinder(resolver *customDomainResolver) (
+	*ServiceCustomDomainBinder, *customDomainContainerAppService, *customDomainAzCli) {
+	env := environment.EphemeralWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"API_DOMAIN":                         "api.contoso.com",
+	})
+	containerAppService := &customDomainContainerAppService{}
+	azCli := &customDomainAzCli{}
+
+	binder := NewServiceCustomDomainBinder(env, &healthResourceManager{}, containerAppService, azCli)
+	binder.resolver = resolver
+	binder.interval = time.Millisecond
+
+	return binder, containerAppService, azCli
+}
+
+func Test_ServiceCustomDomainBinder_Resolve(t *testing.T) {
+	binder, _, _ := newTestCustomDomainBinder(&customDomainResolver{})
+
+	t.Run("ContainerApp", func(t *testing.T) {
+		domain, err := binder.Resolve(context.Background(), &ServiceConfig{
+			Name:         "api",
+			Host:         ContainerAppTarget,
+			CustomDomain: &ServiceCustomDomainOptions{Name: NewExpandableString("${API_DOMAIN}")},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "api.contoso.com", domain.HostName)
+		require.Equal(t, "https://api.contoso.com/", domain.Endpoint())
+		require.Equal(t, []DnsRecord{
+			{Type: "CNAME", Name: "api.contoso.com", Value: "api.azurecontainerapps.io"},
+			{Type: "TXT", Name: "asuid.api.contoso.com", Value: "VERIFICATION_ID"},
+		}, domain.Records)
+	})
+
+	t.Run("AppService", func(t *testing.T) {
+		domain, err := binder.Resolve(context.Background(), &ServiceConfig{
+			Name:         "web",
+			Host:         AppServiceTarget,
+			CustomDomain: &ServiceCustomDomainOptions{Name: NewExpandableString("WWW.Contoso.com.")},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "www.contoso.com", domain.HostName)
+		require.Equal(t, "web.azurewebsites.net", domain.Records[0].Value)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := binder.Resolve(context.Background(), &ServiceConfig{
+			Name:         "api",
+			Host:         ContainerAppTarget,
+			CustomDomain: &ServiceCustomDomainOptions{Name: NewExpandableString("https://api.contoso.com")},
+		})
+		require.ErrorContains(t, err, "must be a host name")
+
+		_, err = binder.Resolve(context.Background(), &ServiceConfig{
+			Name:         "web",
+			Host:         StaticWebAppTarget,
+			CustomDomain: &ServiceCustomDomainOptions{Name: NewExpandableString("www.contoso.com")},
+		})
+		require.ErrorContains(t, err, "not supported")
+	})
+}
+
+func Test_ServiceCustomDomainBinder_WaitForRecords(t *testing.T) {
+	resolver := &customDomainResolver{
+		// The host name of the container app is itself an alias
+		cnames: map[string]string{
+			"api.contoso.com":           "proxy.azurecontainerapps.io",
+			"api.azurecontainerapps.io": "proxy.azurecontainerapps.io",
+		},
+		txts: map[string][]string{},
+	}
+	binder, _, _ := newTestCustomDomainBinder(resolver)
+
+	domain, err := binder.Resolve(context.Background(), &ServiceConfig{
+		Name: "api",
+		Host: ContainerAppTarget,
+		CustomDomain: &ServiceCustomDomainOptions{
+			Name:       NewExpandableString("api.contoso.com"),
+			DnsTimeout: "10ms",
+		},
+	})
+	require.NoError(t, err)
+
+	missing := binder.MissingRecords(context.Background(), domain)
+	require.Equal(t, []DnsRecord{domain.Records[1]}, missing)
+
+	err = binder.WaitForRecords(context.Background(), domain)
+	require.ErrorContains(t, err, "did not resolve within 10ms")
+	require.ErrorContains(t, err, "TXT asuid.api.contoso.com VERIFICATION_ID")
+
+	resolver.txts["asuid.api.contoso.com"] = []string{"VERIFICATION_ID"}
+	require.NoError(t, binder.WaitForRecords(context.Background(), domain))
+}
+
+func Test_ServiceCustomDomainBinder_Bind(t *testing.T) {
+	t.Run("ContainerAppManagedCertificate", func(t *testing.T) {
+		binder, containerAppService, _ := newTestCustomDomainBinder(&customDomainResolver{})
+		domain, err := binder.Resolve(context.Background(), &ServiceConfig{
+			Name:         "api",
+			Host:         ContainerAppTarget,
+			CustomDomain: &ServiceCustomDomainOptions{Name: NewExpandableString("api.contoso.com")},
+		})
+		require.NoError(t, err)
+
+		steps := []string{}
+		require.NoError(t, binder.Bind(context.Background(), domain, func(step string) {
+			steps = append(steps, step)
+		}))
+
+		// The domain is bound before its managed certificate is created, then bound with the certificate
+		require.Equal(t, []string{"", "ENVIRONMENT_ID/managedCertificates/api.contoso.com"}, containerAppService.bound)
+		require.Equal(t, []string{"Binding host name", "Creating managed certificate", "Binding certificate"}, steps)
+	})
+
+	t.Run("AppServiceManagedCertificate", func(t *testing.T) {
+		binder, _, azCli := newTestCustomDomainBinder(&customDomainResolver{})
+		domain, err := binder.Resolve(context.Background(), &ServiceConfig{
+			Name:         "web",
+			Host:         AppServiceTarget,
+			CustomDomain: &ServiceCustomDomainOptions{Name: NewExpandableString("www.contoso.com")},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, binder.Bind(context.Background(), domain, func(string) {}))
+		require.Equal(t, []string{"", "MANAGED_THUMBPRINT"}, azCli.thumbprints)
+	})
+
+	t.Run("AppServiceCertificate", func(t *testing.T) {
+		binder, _, azCli := newTestCustomDomainBinder(&customDomainResolver{})
+		domain, err := binder.Resolve(context.Background(), &ServiceConfig{
+			Name: "web",
+			Host: AppServiceTarget,
+			CustomDomain: &ServiceCustomDomainOptions{
+				Name:        NewExpandableString("www.contoso.com"),
+				Certificate: NewExpandableString("CERTIFICATE_ID"),
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, binder.Bind(context.Background(), domain, func(string) {}))
+		require.Equal(t, []string{"CERTIFICATE_THUMBPRINT"}, azCli.thumbprints)
+	})
+}
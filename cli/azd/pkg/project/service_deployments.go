@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The name of the file within the environment directory that records the last deployment of each service
+const ServiceDeploymentsFileName = ".deployments.json"
+
+// ServiceDeployment is the last successful deployment of a service
+type ServiceDeployment struct {
+	DeployedOn       time.Time `json:"deployedOn"`
+	TargetResourceId string    `json:"targetResourceId,omitempty"`
+	Endpoints        []string  `json:"endpoints,omitempty"`
+}
+
+// ServiceDeployments records the last successful deployment of each service of an environment, so commands like
+// `azd show` can display when and where the services were deployed without querying Azure.
+type ServiceDeployments struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Creates the deployment records of the environment. Environments that aren't persisted are never recorded.
+func NewServiceDeployments(env *environment.Environment) *ServiceDeployments {
+	if env == nil || env.Root == "" {
+		return &ServiceDeployments{}
+	}
+
+	return &ServiceDeployments{
+		path: filepath.Join(env.Root, ServiceDeploymentsFileName),
+	}
+}
+
+// Gets the last deployment of each service, keyed by service name
+func (d *ServiceDeployments) Get() map[string]ServiceDeployment {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.read()
+}
+
+// Records the deployment of the service, services are deployed concurrently
+func (d *ServiceDeployments) Record(serviceName string, result *ServiceDeployResult) error {
+	if d.path == "" {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deployments := d.read()
+	deployments[serviceName] = ServiceDeployment{
+		DeployedOn:       time.Now().UTC(),
+		TargetResourceId: result.TargetResourceId,
+		Endpoints:        result.Endpoints,
+	}
+
+	contents, err := json.Marshal(deployments)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(d.path, contents, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing service deployments: %w", err)
+	}
+
+	return nil
+}
+
+// Removes the deployment records, ex. when the resources of the environment are deleted
+func (d *ServiceDeployments) Clear() error {
+	if d.path == "" {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.Remove(d.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing service deployments: %w", err)
+	}
+
+	return nil
+}
+
+func (d *ServiceDeployments) read() map[string]ServiceDeployment {
+	deployments := map[string]ServiceDeployment{}
+	if d.path == "" {
+		return deployments
+	}
+
+	contents, err := os.ReadFile(d.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed reading service deployments: %v\n", err)
+		}
+
+		return deployments
+	}
+
+	if err := json.Unmarshal(contents, &deployments); err != nil {
+		log.Printf("failed parsing service deployments: %v\n", err)
+		return map[string]ServiceDeployment{}
+	}
+
+	return deployments
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceDeployments(t *testing.T) {
+	env := environment.EmptyWithRoot(t.TempDir())
+
+	deployments := NewServiceDeployments(env)
+	require.Empty(t, deployments.Get())
+
+	require.NoError(t, deployments.Record("api", &ServiceDeployResult{
+		TargetResourceId: "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Web/sites/api",
+		Endpoints:        []string{"https://api.azurewebsites.net/"},
+	}))
+	require.NoError(t, deployments.Record("web", &ServiceDeployResult{}))
+
+	// The deployments are read by the other commands of the environment
+	recorded := NewServiceDeployments(env).Get()
+	require.Len(t, recorded, 2)
+	require.Equal(t, []string{"https://api.azurewebsites.net/"}, recorded["api"].Endpoints)
+	require.False(t, recorded["api"].DeployedOn.IsZero())
+	require.False(t, recorded["web"].DeployedOn.Before(recorded["api"].DeployedOn))
+
+	require.NoError(t, deployments.Clear())
+	require.Empty(t, deployments.Get())
+	require.NoError(t, deployments.Clear())
+
+	// Environments that aren't persisted aren't recorded
+	unsaved := NewServiceDeployments(environment.Ephemeral())
+	require.NoError(t, unsaved.Record("api", &ServiceDeployResult{}))
+	require.Empty(t, unsaved.Get())
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"golang.org/x/exp/maps"
+)
+
+var serviceEnvNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
+// validateEnv validates the names of the env section of the service, and that its host supports environment variables
+func (sc *ServiceConfig) validateEnv() error {
+	if len(sc.Env) == 0 {
+		return nil
+	}
+
+	switch sc.Host {
+	case "", AppServiceTarget, AzureFunctionTarget, ContainerAppTarget:
+	default:
+		return fmt.Errorf(
+			"env is not supported by host '%s', only by %s, %s and %s",
+			sc.Host, AppServiceTarget, AzureFunctionTarget, ContainerAppTarget)
+	}
+
+	for name := range sc.Env {
+		if !serviceEnvNameRegexp.MatchString(name) {
+			return fmt.Errorf(
+				"invalid env name '%s', names must start with a letter or _ and contain letters, digits, _, . and - only",
+				name)
+		}
+	}
+
+	return nil
+}
+
+// ResolveEnv returns the environment variables of the env section of the service, the ${NAME} references substituted
+// by getenv, ex. with the outputs of provisioning saved in the environment. The variables are applied to the app
+// settings of app services and function apps, and to the container of container apps, when the service is deployed.
+func (sc *ServiceConfig) ResolveEnv(getenv func(string) string) (map[string]string, error) {
+	names := maps.Keys(sc.Env)
+	sort.Strings(names)
+
+	env := map[string]string{}
+	for _, name := range names {
+		value, err := sc.Env[name].Envsubst(getenv)
+		if err != nil {
+			return nil, fmt.Errorf("services.%s.env.%s: %w", sc.Name, name, err)
+		}
+
+		env[name] = value
+	}
+
+	return env, nil
+}
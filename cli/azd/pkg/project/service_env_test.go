@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceConfigResolveEnv(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    host: appservice
+    env:
+      DB_CONNECTION: Server=${AZURE_SQL_SERVER};Database=todo
+      API_URL: ${SERVICE_API_ENDPOINT_URL}/api
+      LOG_LEVEL: debug
+`
+
+	projectConfig, err := Parse(context.Background(), testProj)
+	require.NoError(t, err)
+
+	values := map[string]string{
+		"AZURE_SQL_SERVER":         "sql.database.windows.net",
+		"SERVICE_API_ENDPOINT_URL": "https://api.azurewebsites.net",
+	}
+	env, err := projectConfig.Services["api"].ResolveEnv(func(key string) string {
+		return values[key]
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"DB_CONNECTION": "Server=sql.database.windows.net;Database=todo",
+		"API_URL":       "https://api.azurewebsites.net/api",
+		"LOG_LEVEL":     "debug",
+	}, env)
+}
+
+func TestServiceConfigEnvInvalid(t *testing.T) {
+	tests := map[string]struct {
+		host string
+		name string
+		err  string
+	}{
+		"UnsupportedHost": {host: "staticwebapp", name: "API_URL", err: "env is not supported by host 'staticwebapp'"},
+		"InvalidName":     {host: "containerapp", name: "1API_URL", err: "invalid env name '1API_URL'"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			projectConfig, err := Parse(context.Background(), `
+name: test-proj
+services:
+  web:
+    project: src/web
+    language: js
+    host: `+tt.host+`
+    env:
+      `+tt.name+`: value
+`)
+			require.Nil(t, projectConfig)
+			require.ErrorContains(t, err, "parsing service web")
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
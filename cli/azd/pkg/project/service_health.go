@@ -0,0 +1,286 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The maximum time to wait for a service health endpoint to respond
+const healthEndpointTimeout = 30 * time.Second
+
+// ServiceHealthOptions describes how the health of a service is evaluated by `azd health`
+type ServiceHealthOptions struct {
+	// The path of the health endpoint, relative to each endpoint of the service (ex. /health)
+	Path string `yaml:"path"`
+	// The status code returned by a healthy service. Defaults to any 2xx status code.
+	ExpectedStatus int `yaml:"expectedStatus,omitempty"`
+}
+
+type HealthStatus string
+
+const (
+	HealthStatusHealthy   HealthStatus = "Healthy"
+	HealthStatusUnhealthy HealthStatus = "Unhealthy"
+	// The health could not be determined, ex. the platform doesn't report health signals
+	HealthStatusUnknown HealthStatus = "Unknown"
+)
+
+// ServiceHealthCheck is the result of a single health signal of a service
+type ServiceHealthCheck struct {
+	Name    string
+	Status  HealthStatus
+	Message string
+}
+
+// ServiceHealth is the health of a service, unhealthy when any of its checks is unhealthy
+type ServiceHealth struct {
+	Status HealthStatus
+	Checks []ServiceHealthCheck
+}
+
+// ServiceHealthChecker evaluates the health of deployed services from the health signals of the hosting platform &
+// the health endpoints declared in azure.yaml
+type ServiceHealthChecker struct {
+	env                 *environment.Environment
+	resourceManager     ResourceManager
+	serviceManager      ServiceManager
+	containerAppService containerapps.ContainerAppService
+	jobService          containerapps.ContainerAppJobService
+	azCli               azcli.AzCli
+	httpClient          httputil.HttpClient
+}
+
+// NewServiceHealthChecker creates a new instance of the ServiceHealthChecker
+func NewServiceHealthChecker(
+	env *environment.Environment,
+	resourceManager ResourceManager,
+	serviceManager ServiceManager,
+	containerAppService containerapps.ContainerAppService,
+	jobService containerapps.ContainerAppJobService,
+	azCli azcli.AzCli,
+	httpClient httputil.HttpClient,
+) *ServiceHealthChecker {
+	return &ServiceHealthChecker{
+		env:                 env,
+		resourceManager:     resourceManager,
+		serviceManager:      serviceManager,
+		containerAppService: containerAppService,
+		jobService:          jobService,
+		azCli:               azCli,
+		httpClient:          httpClient,
+	}
+}
+
+// Check evaluates the health of the specified service
+func (c *ServiceHealthChecker) Check(ctx context.Context, serviceConfig *ServiceConfig) (*ServiceHealth, error) {
+	targetResource, err := c.resourceManager.GetTargetResource(ctx, c.env.GetSubscriptionId(), serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource: %w", err)
+	}
+
+	health := &ServiceHealth{}
+
+	if check, has := c.checkPlatform(ctx, serviceConfig, targetResource); has {
+		health.Checks = append(health.Checks, check)
+	}
+
+	if serviceConfig.Health != nil && serviceConfig.Health.Path != "" {
+		checks, err := c.checkEndpoints(ctx, serviceConfig, targetResource)
+		if err != nil {
+			return nil, err
+		}
+
+		health.Checks = append(health.Checks, checks...)
+	}
+
+	health.Status = HealthStatusUnknown
+	for _, check := range health.Checks {
+		if check.Status == HealthStatusUnhealthy {
+			health.Status = HealthStatusUnhealthy
+			break
+		}
+
+		if check.Status == HealthStatusHealthy {
+			health.Status = HealthStatusHealthy
+		}
+	}
+
+	return health, nil
+}
+
+// checkPlatform evaluates the health reported by the platform hosting the service, when the platform reports it
+func (c *ServiceHealthChecker) checkPlatform(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (ServiceHealthCheck, bool) {
+	switch serviceConfig.Host {
+	case ContainerAppTarget:
+		check := ServiceHealthCheck{Name: "Container App revision"}
+		revision, err := c.containerAppService.GetHealth(
+			ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), targetResource.ResourceName())
+		if err != nil {
+			check.Status = HealthStatusUnknown
+			check.Message = err.Error()
+			return check, true
+		}
+
+		check.Message = fmt.Sprintf(
+			"revision %s is %s with %d replicas", revision.RevisionName, revision.HealthState, revision.Replicas)
+
+		switch {
+		case revision.ProvisioningState == "Failed":
+			check.Status = HealthStatusUnhealthy
+			check.Message = fmt.Sprintf(
+				"revision %s failed provisioning: %s", revision.RevisionName, revision.ProvisioningError)
+		case revision.HealthState == "Unhealthy":
+			check.Status = HealthStatusUnhealthy
+		case revision.HealthState == "Healthy":
+			check.Status = HealthStatusHealthy
+		default:
+			check.Status = HealthStatusUnknown
+		}
+
+		return check, true
+	case ContainerAppJobTarget:
+		// Jobs don't expose endpoints, their health is the outcome of their latest execution
+		check := ServiceHealthCheck{Name: "Container Apps job execution"}
+		status, err := c.jobService.GetStatus(
+			ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), targetResource.ResourceName())
+		if err != nil {
+			check.Status = HealthStatusUnknown
+			check.Message = err.Error()
+			return check, true
+		}
+
+		execution := status.LatestExecution
+		if execution == nil {
+			check.Status = HealthStatusUnknown
+			check.Message = fmt.Sprintf("%s job did not run yet", strings.ToLower(status.TriggerType))
+			return check, true
+		}
+
+		check.Message = fmt.Sprintf("execution %s is %s", execution.Name, execution.Status)
+		switch execution.Status {
+		case "Succeeded", "Running", "Processing":
+			check.Status = HealthStatusHealthy
+		case "Failed", "Degraded":
+			check.Status = HealthStatusUnhealthy
+		default:
+			check.Status = HealthStatusUnknown
+		}
+
+		return check, true
+	case "", AppServiceTarget, AzureFunctionTarget:
+		check := ServiceHealthCheck{Name: "App Service availability"}
+		properties, err := c.azCli.GetAppServiceProperties(
+			ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), targetResource.ResourceName())
+		if err != nil {
+			check.Status = HealthStatusUnknown
+			check.Message = err.Error()
+			return check, true
+		}
+
+		check.Message = fmt.Sprintf("app is %s with %s availability", properties.State, properties.AvailabilityState)
+		if strings.EqualFold(properties.State, "Running") && strings.EqualFold(properties.AvailabilityState, "Normal") {
+			check.Status = HealthStatusHealthy
+		} else {
+			check.Status = HealthStatusUnhealthy
+		}
+
+		return check, true
+	}
+
+	return ServiceHealthCheck{}, false
+}
+
+// checkEndpoints sends a request to the health endpoint of each endpoint of the service
+func (c *ServiceHealthChecker) checkEndpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]ServiceHealthCheck, error) {
+	endpoints := overriddenEndpoints(c.env, serviceConfig)
+	if len(endpoints) == 0 {
+		serviceTarget, err := c.serviceManager.GetServiceTarget(ctx, serviceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("getting service target: %w", err)
+		}
+
+		endpoints, err = serviceTarget.Endpoints(ctx, serviceConfig, targetResource)
+		if err != nil {
+			return nil, fmt.Errorf("getting service endpoints: %w", err)
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return []ServiceHealthCheck{
+			{
+				Name:    serviceConfig.Health.Path,
+				Status:  HealthStatusUnhealthy,
+				Message: "service does not expose any endpoint",
+			},
+		}, nil
+	}
+
+	checks := make([]ServiceHealthCheck, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		checks = append(checks, c.checkEndpoint(ctx, endpoint, serviceConfig.Health))
+	}
+
+	return checks, nil
+}
+
+func (c *ServiceHealthChecker) checkEndpoint(
+	ctx context.Context,
+	endpoint string,
+	options *ServiceHealthOptions,
+) ServiceHealthCheck {
+	healthUrl, err := url.JoinPath(endpoint, options.Path)
+	if err != nil {
+		return ServiceHealthCheck{Name: endpoint, Status: HealthStatusUnhealthy, Message: err.Error()}
+	}
+
+	check := ServiceHealthCheck{Name: healthUrl}
+
+	ctx, cancel := context.WithTimeout(ctx, healthEndpointTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthUrl, nil)
+	if err != nil {
+		check.Status = HealthStatusUnhealthy
+		check.Message = err.Error()
+		return check
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		check.Status = HealthStatusUnhealthy
+		check.Message = err.Error()
+		return check
+	}
+	defer res.Body.Close()
+
+	check.Message = res.Status
+	healthy := res.StatusCode >= 200 && res.StatusCode < 300
+	if options.ExpectedStatus != 0 {
+		healthy = res.StatusCode == options.ExpectedStatus
+	}
+
+	if healthy {
+		check.Status = HealthStatusHealthy
+	} else {
+		check.Status = HealthStatusUnhealthy
+	}
+
+	return check
+}
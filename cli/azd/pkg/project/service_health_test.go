@@ -0,0 +1,156 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/stretchr/testify/require"
+)
+
+type healthResourceManager struct {
+	ResourceManager
+}
+
+func (m *healthResourceManager) GetTargetResource(
+	ctx context.Context,
+	subscriptionId string,
+	serviceConfig *ServiceConfig,
+) (*environment.TargetResource, error) {
+	return environment.NewTargetResource(
+		subscriptionId, "RESOURCE_GROUP", "CONTAINER_APP", string(infra.AzureResourceTypeContainerApp)), nil
+}
+
+type healthContainerAppService struct {
+	containerapps.ContainerAppService
+	health *containerapps.ContainerAppHealth
+}
+
+func (s *healthContainerAppService) GetHealth(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (*containerapps.ContainerAppHealth, error) {
+	return s.health, nil
+}
+
+func Test_ServiceHealthChecker_Check(t *testing.T) {
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	env := environment.EphemeralWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"SERVICE_API_ENDPOINTS":              fmt.Sprintf(`["%s"]`, server.URL),
+	})
+	containerAppService := &healthContainerAppService{
+		health: &containerapps.ContainerAppHealth{
+			RevisionName:      "REVISION",
+			HealthState:       "Healthy",
+			ProvisioningState: "Provisioned",
+			Replicas:          1,
+		},
+	}
+	serviceConfig := &ServiceConfig{
+		Name:   "api",
+		Host:   ContainerAppTarget,
+		Health: &ServiceHealthOptions{Path: "/health"},
+	}
+
+	checker := NewServiceHealthChecker(
+		env, &healthResourceManager{}, nil, containerAppService, nil, nil, http.DefaultClient)
+
+	t.Run("Healthy", func(t *testing.T) {
+		health, err := checker.Check(context.Background(), serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, HealthStatusHealthy, health.Status)
+		require.Len(t, health.Checks, 2)
+	})
+
+	t.Run("UnhealthyEndpoint", func(t *testing.T) {
+		statusCode = http.StatusServiceUnavailable
+		defer func() { statusCode = http.StatusOK }()
+
+		health, err := checker.Check(context.Background(), serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, HealthStatusUnhealthy, health.Status)
+		require.Equal(t, HealthStatusHealthy, health.Checks[0].Status)
+		require.Equal(t, HealthStatusUnhealthy, health.Checks[1].Status)
+	})
+
+	t.Run("ExpectedStatus", func(t *testing.T) {
+		statusCode = http.StatusNoContent
+		defer func() { statusCode = http.StatusOK }()
+
+		expectedConfig := *serviceConfig
+		expectedConfig.Health = &ServiceHealthOptions{Path: "/health", ExpectedStatus: http.StatusOK}
+
+		health, err := checker.Check(context.Background(), &expectedConfig)
+		require.NoError(t, err)
+		require.Equal(t, HealthStatusUnhealthy, health.Status)
+	})
+
+	t.Run("FailedRevision", func(t *testing.T) {
+		containerAppService.health = &containerapps.ContainerAppHealth{
+			RevisionName:      "REVISION",
+			HealthState:       "None",
+			ProvisioningState: "Failed",
+			ProvisioningError: "image not found",
+		}
+
+		health, err := checker.Check(context.Background(), serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, HealthStatusUnhealthy, health.Status)
+		require.Contains(t, health.Checks[0].Message, "image not found")
+	})
+}
+
+func Test_ServiceHealthChecker_Check_ContainerAppJob(t *testing.T) {
+	env := environment.EphemeralWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+	jobService := &fakeContainerAppJobService{
+		status: &containerapps.ContainerAppJobStatus{
+			TriggerType:     "Schedule",
+			CronExpression:  "*/5 * * * *",
+			LatestExecution: &containerapps.ContainerAppJobExecution{Name: "job-abc", Status: "Succeeded"},
+		},
+	}
+	serviceConfig := &ServiceConfig{Name: "worker", Host: ContainerAppJobTarget}
+
+	checker := NewServiceHealthChecker(
+		env, &healthResourceManager{}, nil, nil, jobService, nil, http.DefaultClient)
+
+	t.Run("Succeeded", func(t *testing.T) {
+		health, err := checker.Check(context.Background(), serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, HealthStatusHealthy, health.Status)
+		require.Equal(t, "execution job-abc is Succeeded", health.Checks[0].Message)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		jobService.status.LatestExecution.Status = "Failed"
+
+		health, err := checker.Check(context.Background(), serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, HealthStatusUnhealthy, health.Status)
+	})
+
+	t.Run("NeverRan", func(t *testing.T) {
+		jobService.status.LatestExecution = nil
+
+		health, err := checker.Check(context.Background(), serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, HealthStatusUnknown, health.Status)
+		require.Equal(t, "schedule job did not run yet", health.Checks[0].Message)
+	})
+}
@@ -0,0 +1,138 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The log stream of the invocations of the functions of a function app
+const functionAppLogStreamPath = "application/functions/function"
+
+// parseServiceLog splits the timestamp leading the line logged by the host, if any, ex.
+// `2024-03-01T10:00:00.000000000Z Listening on port 8080`
+func parseServiceLog(source string, line string) ServiceLog {
+	serviceLog := ServiceLog{
+		Source:  source,
+		Message: line,
+	}
+
+	if timestamp, message, has := strings.Cut(line, " "); has {
+		if parsed, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			serviceLog.Timestamp = parsed
+			serviceLog.Message = strings.TrimLeft(message, " ")
+		}
+	}
+
+	return serviceLog
+}
+
+// sinceFilter skips the lines logged before the cutoff by the hosts not filtering their logs. Lines without timestamp,
+// ex. the lines of a stack trace, are skipped with the line of the same source they follow.
+func sinceFilter(cutoff time.Time, write func(log ServiceLog) error) func(log ServiceLog) error {
+	skipping := map[string]bool{}
+
+	return func(log ServiceLog) error {
+		if !log.Timestamp.IsZero() {
+			skipping[log.Source] = log.Timestamp.Before(cutoff)
+		}
+
+		if skipping[log.Source] {
+			return nil
+		}
+
+		return write(log)
+	}
+}
+
+// collectServiceLogs returns the lines written by the reading of the logs, for the log providers streaming logs
+func collectServiceLogs(read func(write func(log ServiceLog) error) error) ([]ServiceLog, error) {
+	logs := []ServiceLog{}
+	err := read(func(log ServiceLog) error {
+		logs = append(logs, log)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// readAppServiceLogs writes the container logs of the app service or function app logged since the duration. When
+// following the logs, the lines of the log stream at the path are then written until the context is canceled.
+func readAppServiceLogs(
+	ctx context.Context,
+	cli azcli.AzCli,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+	follow bool,
+	streamPath string,
+	write func(log ServiceLog) error,
+) error {
+	cutoff := time.Now().Add(-since)
+	filter := sinceFilter(cutoff, write)
+
+	err := cli.ReadAppServiceLogs(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceName(),
+		cutoff,
+		func(instance string, line string) error {
+			return filter(parseServiceLog(instance, line))
+		},
+	)
+	if err != nil || !follow {
+		return err
+	}
+
+	return cli.StreamAppServiceLogs(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceName(),
+		streamPath,
+		func(line string) error {
+			return write(parseServiceLog(targetResource.ResourceName(), line))
+		},
+	)
+}
+
+// logLineWriter is the writer of the tools writing logs to their output, ex. kubectl, writing each complete line
+type logLineWriter struct {
+	buffer []byte
+	write  func(line string) error
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		index := bytes.IndexByte(w.buffer, '\n')
+		if index < 0 {
+			return len(p), nil
+		}
+
+		line := strings.TrimSuffix(string(w.buffer[:index]), "\r")
+		w.buffer = w.buffer[index+1:]
+
+		if err := w.write(line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush writes the last line, when the output doesn't end with a new line
+func (w *logLineWriter) Flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+
+	line := string(w.buffer)
+	w.buffer = nil
+
+	return w.write(line)
+}
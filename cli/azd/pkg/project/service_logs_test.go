@@ -0,0 +1,64 @@
+package project
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseServiceLog(t *testing.T) {
+	log := parseServiceLog("api-7c9f/api", "2024-03-01T10:00:00.123456789Z Listening on port 8080")
+	require.Equal(t, "api-7c9f/api", log.Source)
+	require.Equal(t, "Listening on port 8080", log.Message)
+	require.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 123456789, time.UTC), log.Timestamp)
+
+	// Lines without timestamp are kept as is
+	log = parseServiceLog("", "   at Program.Main()")
+	require.True(t, log.Timestamp.IsZero())
+	require.Equal(t, "   at Program.Main()", log.Message)
+}
+
+func Test_sinceFilter(t *testing.T) {
+	cutoff := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	logs := []ServiceLog{}
+	filter := sinceFilter(cutoff, func(log ServiceLog) error {
+		logs = append(logs, log)
+		return nil
+	})
+
+	for _, log := range []ServiceLog{
+		{Timestamp: cutoff.Add(-time.Minute), Source: "a", Message: "old"},
+		{Source: "a", Message: "old stack trace"},
+		{Timestamp: cutoff.Add(time.Minute), Source: "b", Message: "new"},
+		{Source: "b", Message: "new stack trace"},
+		{Source: "a", Message: "old stack trace"},
+		{Timestamp: cutoff.Add(time.Minute), Source: "a", Message: "new"},
+	} {
+		require.NoError(t, filter(log))
+	}
+
+	messages := []string{}
+	for _, log := range logs {
+		messages = append(messages, fmt.Sprintf("%s: %s", log.Source, log.Message))
+	}
+	require.Equal(t, []string{"b: new", "b: new stack trace", "a: new"}, messages)
+}
+
+func Test_logLineWriter(t *testing.T) {
+	lines := []string{}
+	writer := &logLineWriter{write: func(line string) error {
+		lines = append(lines, line)
+		return nil
+	}}
+
+	_, err := writer.Write([]byte("first\r\nsec"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"first"}, lines)
+
+	_, err = writer.Write([]byte("ond\nlast"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Flush())
+	require.Equal(t, []string{"first", "second", "last"}, lines)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -12,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
 )
 
 const (
@@ -20,6 +22,7 @@ const (
 	ServiceEventBuild      ext.Event = "build"
 	ServiceEventPackage    ext.Event = "package"
 	ServiceEventDeploy     ext.Event = "deploy"
+	ServiceEventTest       ext.Event = "test"
 )
 
 var (
@@ -28,6 +31,7 @@ var (
 		ServiceEventRestore,
 		ServiceEventPackage,
 		ServiceEventDeploy,
+		ServiceEventTest,
 	}
 )
 
@@ -70,6 +74,15 @@ type ServiceManager interface {
 		buildOutput *ServiceBuildResult,
 	) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress]
 
+	// Packages an artifact built outside of azd, without building the service.
+	// The service target prepares the referenced archive, directory or
+	// container image to be deployed like the artifacts generated by Package.
+	PackageFromReference(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		reference *PackageReference,
+	) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress]
+
 	// Deploys the generated artifacts to the Azure resource that will
 	// host the service application
 	// Common examples would be uploading zip archive using ZipDeploy deployment or
@@ -96,6 +109,9 @@ type serviceManager struct {
 	serviceLocator      ioc.ServiceLocator
 	operationCache      map[string]any
 	alphaFeatureManager *alpha.FeatureManager
+
+	// Guards the operation cache since services can be packaged & deployed concurrently
+	cacheMu sync.Mutex
 }
 
 // NewServiceManager creates a new instance of the ServiceManager component
@@ -365,6 +381,39 @@ func (sm *serviceManager) Package(
 	})
 }
 
+// Packages an artifact built outside of azd, without building the service nor running its package hooks.
+// Service targets that don't implement PackageReferenceTarget deploy the reference as is.
+func (sm *serviceManager) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+		serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+		if err != nil {
+			task.SetError(fmt.Errorf("getting service target: %w", err))
+			return
+		}
+
+		referenceTarget, ok := serviceTarget.(PackageReferenceTarget)
+		if !ok {
+			task.SetResult(&ServicePackageResult{PackagePath: reference.String()})
+			return
+		}
+
+		packageTask := referenceTarget.PackageFromReference(ctx, serviceConfig, reference)
+		syncProgress(task, packageTask.Progress())
+
+		packageResult, err := packageTask.Await()
+		if err != nil {
+			task.SetError(fmt.Errorf("failed packaging service '%s': %w", serviceConfig.Name, err))
+			return
+		}
+
+		task.SetResult(packageResult)
+	})
+}
+
 // Deploys the generated artifacts to the Azure resource that will host the service application
 // Common examples would be uploading zip archive using ZipDeploy deployment or
 // pushing container images to a container registry.
@@ -458,7 +507,13 @@ func (sm *serviceManager) GetServiceTarget(ctx context.Context, serviceConfig *S
 func (sm *serviceManager) GetFrameworkService(ctx context.Context, serviceConfig *ServiceConfig) (FrameworkService, error) {
 	var frameworkService FrameworkService
 
-	if err := sm.serviceLocator.ResolveNamed(string(serviceConfig.Language), &frameworkService); err != nil {
+	// Java services are built with maven, unless the service has a gradle build
+	frameworkName := string(serviceConfig.Language)
+	if serviceConfig.Language == ServiceLanguageJava && gradle.IsGradleProject(serviceConfig.Path()) {
+		frameworkName = GradleFrameworkName
+	}
+
+	if err := sm.serviceLocator.ResolveNamed(frameworkName, &frameworkService); err != nil {
 		panic(fmt.Errorf(
 			"failed to resolve language '%s' for service '%s', %w",
 			serviceConfig.Language,
@@ -468,7 +523,7 @@ func (sm *serviceManager) GetFrameworkService(ctx context.Context, serviceConfig
 	}
 
 	// For containerized applications we use a composite framework service
-	if serviceConfig.Host == ContainerAppTarget || serviceConfig.Host == AksTarget {
+	if serviceConfig.Host.RequiresContainer() {
 		var compositeFramework CompositeFrameworkService
 		if err := sm.serviceLocator.ResolveNamed(string(ServiceLanguageDocker), &compositeFramework); err != nil {
 			panic(fmt.Errorf(
@@ -488,7 +543,12 @@ func (sm *serviceManager) GetFrameworkService(ctx context.Context, serviceConfig
 }
 
 func (sm *serviceManager) getOverriddenEndpoints(ctx context.Context, serviceConfig *ServiceConfig) []string {
-	overriddenEndpoints := sm.env.GetServiceProperty(serviceConfig.Name, "ENDPOINTS")
+	return overriddenEndpoints(sm.env, serviceConfig)
+}
+
+// overriddenEndpoints returns the endpoints of the service specified by the SERVICE_<name>_ENDPOINTS environment value
+func overriddenEndpoints(env *environment.Environment, serviceConfig *ServiceConfig) []string {
+	overriddenEndpoints := env.GetServiceProperty(serviceConfig.Name, "ENDPOINTS")
 	if overriddenEndpoints != "" {
 		var endpoints []string
 		err := json.Unmarshal([]byte(overriddenEndpoints), &endpoints)
@@ -514,6 +574,10 @@ func (sm *serviceManager) getOperationResult(
 	operationName string,
 ) (any, bool) {
 	key := fmt.Sprintf("%s:%s", serviceConfig.Name, operationName)
+
+	sm.cacheMu.Lock()
+	defer sm.cacheMu.Unlock()
+
 	value, ok := sm.operationCache[key]
 
 	return value, ok
@@ -527,6 +591,10 @@ func (sm *serviceManager) setOperationResult(
 	result any,
 ) {
 	key := fmt.Sprintf("%s:%s", serviceConfig.Name, operationName)
+
+	sm.cacheMu.Lock()
+	defer sm.cacheMu.Unlock()
+
 	sm.operationCache[key] = result
 }
 
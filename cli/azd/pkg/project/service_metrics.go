@@ -0,0 +1,249 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/benbjohnson/clock"
+)
+
+// The number of data points returned for each metric, the interval of the data points is rounded to an interval
+// supported by Azure Monitor
+const metricDataPoints = 30
+
+// The intervals of data points supported by Azure Monitor
+var metricIntervals = []struct {
+	duration time.Duration
+	iso      string
+}{
+	{time.Minute, "PT1M"},
+	{5 * time.Minute, "PT5M"},
+	{15 * time.Minute, "PT15M"},
+	{30 * time.Minute, "PT30M"},
+	{time.Hour, "PT1H"},
+	{6 * time.Hour, "PT6H"},
+	{12 * time.Hour, "PT12H"},
+	{24 * time.Hour, "P1D"},
+}
+
+// The aggregations of Azure Monitor metrics
+const (
+	MetricAggregationAverage = "Average"
+	MetricAggregationTotal   = "Total"
+	MetricAggregationMaximum = "Maximum"
+)
+
+// metricDefinition maps a key metric of a service to the platform metric of the resource hosting the service
+type metricDefinition struct {
+	name        string
+	metricName  string
+	aggregation string
+}
+
+// The key metrics of each type of resource hosting services
+var metricDefinitions = map[infra.AzureResourceType][]metricDefinition{
+	infra.AzureResourceTypeContainerApp: {
+		{name: "Requests", metricName: "Requests", aggregation: MetricAggregationTotal},
+		{name: "CPU", metricName: "UsageNanoCores", aggregation: MetricAggregationAverage},
+		{name: "Memory", metricName: "WorkingSetBytes", aggregation: MetricAggregationAverage},
+		{name: "Replicas", metricName: "Replicas", aggregation: MetricAggregationMaximum},
+	},
+	infra.AzureResourceTypeWebSite: {
+		{name: "Requests", metricName: "Requests", aggregation: MetricAggregationTotal},
+		{name: "Latency", metricName: "HttpResponseTime", aggregation: MetricAggregationAverage},
+		{name: "CPU", metricName: "CpuTime", aggregation: MetricAggregationTotal},
+		{name: "Memory", metricName: "MemoryWorkingSet", aggregation: MetricAggregationAverage},
+	},
+}
+
+// MetricPoint is the value of a metric over an interval, nil when no data was reported in the interval
+type MetricPoint struct {
+	Timestamp time.Time
+	Value     *float64
+}
+
+// ServiceMetric is a key metric of a service over a time window
+type ServiceMetric struct {
+	Name        string
+	Unit        string
+	Aggregation string
+	// The value of the metric over the whole time window
+	Summary *float64
+	Points  []MetricPoint
+}
+
+// ServiceMetricsReader reads the key platform metrics of the resources hosting services from Azure Monitor
+type ServiceMetricsReader struct {
+	env             *environment.Environment
+	resourceManager ResourceManager
+	azCli           azcli.AzCli
+	clock           clock.Clock
+}
+
+// NewServiceMetricsReader creates a new instance of the ServiceMetricsReader
+func NewServiceMetricsReader(
+	env *environment.Environment,
+	resourceManager ResourceManager,
+	azCli azcli.AzCli,
+	clock clock.Clock,
+) *ServiceMetricsReader {
+	return &ServiceMetricsReader{
+		env:             env,
+		resourceManager: resourceManager,
+		azCli:           azCli,
+		clock:           clock,
+	}
+}
+
+// Read returns the key metrics of the service over the most recent time window
+func (r *ServiceMetricsReader) Read(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	window time.Duration,
+) ([]ServiceMetric, error) {
+	subscriptionId, err := r.resourceManager.GetServiceSubscriptionId(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceGroupName, err := r.resourceManager.GetServiceResourceGroupName(ctx, subscriptionId, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := r.resourceManager.GetServiceResource(
+		ctx, subscriptionId, resourceGroupName, serviceConfig, "provision")
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, has := findMetricDefinitions(resource.Type)
+	if !has {
+		return nil, fmt.Errorf(
+			"metrics are not supported for resources of type '%s' hosting service '%s'", resource.Type, serviceConfig.Name)
+	}
+
+	names := make([]string, len(definitions))
+	for i, definition := range definitions {
+		names[i] = definition.metricName
+	}
+
+	end := r.clock.Now().UTC().Truncate(time.Minute)
+	start := end.Add(-window)
+	result, err := r.azCli.GetMetrics(ctx, subscriptionId, resource.Id, azsdk.MetricsQuery{
+		Names:    names,
+		Timespan: fmt.Sprintf("%s/%s", start.Format(time.RFC3339), end.Format(time.RFC3339)),
+		Interval: metricInterval(window),
+		Aggregations: []string{
+			MetricAggregationAverage,
+			MetricAggregationTotal,
+			MetricAggregationMaximum,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]ServiceMetric, 0, len(definitions))
+	for _, definition := range definitions {
+		metric := ServiceMetric{
+			Name:        definition.name,
+			Aggregation: definition.aggregation,
+			Points:      []MetricPoint{},
+		}
+
+		for _, value := range result.Value {
+			if !strings.EqualFold(value.Name.Value, definition.metricName) {
+				continue
+			}
+
+			metric.Unit = value.Unit
+			for _, series := range value.Timeseries {
+				for _, data := range series.Data {
+					metric.Points = append(metric.Points, MetricPoint{
+						Timestamp: data.TimeStamp,
+						Value:     metricValue(data, definition.aggregation),
+					})
+				}
+			}
+		}
+
+		metric.Summary = summarizeMetric(metric.Points, definition.aggregation)
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, nil
+}
+
+func findMetricDefinitions(resourceType string) ([]metricDefinition, bool) {
+	for definitionType, definitions := range metricDefinitions {
+		if strings.EqualFold(string(definitionType), resourceType) {
+			return definitions, true
+		}
+	}
+
+	return nil, false
+}
+
+// metricInterval returns the smallest supported interval returning at most metricDataPoints data points
+func metricInterval(window time.Duration) string {
+	for _, interval := range metricIntervals {
+		if window/interval.duration <= metricDataPoints {
+			return interval.iso
+		}
+	}
+
+	return metricIntervals[len(metricIntervals)-1].iso
+}
+
+func metricValue(data azsdk.MetricValue, aggregation string) *float64 {
+	switch aggregation {
+	case MetricAggregationTotal:
+		return data.Total
+	case MetricAggregationMaximum:
+		return data.Maximum
+	default:
+		return data.Average
+	}
+}
+
+// summarizeMetric aggregates the data points of a metric over the whole time window
+func summarizeMetric(points []MetricPoint, aggregation string) *float64 {
+	var summary *float64
+	count := 0
+
+	for _, point := range points {
+		if point.Value == nil {
+			continue
+		}
+
+		value := *point.Value
+		if summary == nil {
+			summary = &value
+			count++
+			continue
+		}
+
+		switch aggregation {
+		case MetricAggregationMaximum:
+			if value > *summary {
+				*summary = value
+			}
+		default:
+			*summary += value
+		}
+		count++
+	}
+
+	if summary != nil && aggregation == MetricAggregationAverage {
+		*summary /= float64(count)
+	}
+
+	return summary
+}
@@ -0,0 +1,130 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+type metricsResourceManager struct {
+	ResourceManager
+}
+
+func (m *metricsResourceManager) GetServiceSubscriptionId(serviceConfig *ServiceConfig) (string, error) {
+	return "SUBSCRIPTION_ID", nil
+}
+
+func (m *metricsResourceManager) GetServiceResourceGroupName(
+	ctx context.Context,
+	subscriptionId string,
+	serviceConfig *ServiceConfig,
+) (string, error) {
+	return "RESOURCE_GROUP", nil
+}
+
+func (m *metricsResourceManager) GetServiceResource(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	serviceConfig *ServiceConfig,
+	rerunCommand string,
+) (azcli.AzCliResource, error) {
+	return azcli.AzCliResource{
+		Id:   "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.App/containerApps/api",
+		Name: "api",
+		Type: strings.ToLower(string(infra.AzureResourceTypeContainerApp)),
+	}, nil
+}
+
+func Test_ServiceMetricsReader_Read(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var query map[string]string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Insights/metrics")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		query = map[string]string{}
+		for key := range request.URL.Query() {
+			query[key] = request.URL.Query().Get(key)
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.MetricsResult{
+			Value: []azsdk.Metric{
+				{
+					Name: azsdk.MetricName{Value: "Requests"},
+					Unit: "Count",
+					Timeseries: []azsdk.MetricTimeseries{
+						{
+							Data: []azsdk.MetricValue{
+								{Total: convert.RefOf(10.0)},
+								{Total: convert.RefOf(30.0)},
+							},
+						},
+					},
+				},
+				{
+					Name: azsdk.MetricName{Value: "WorkingSetBytes"},
+					Unit: "Bytes",
+					Timeseries: []azsdk.MetricTimeseries{
+						{
+							Data: []azsdk.MetricValue{
+								{Average: convert.RefOf(100.0)},
+								{},
+								{Average: convert.RefOf(300.0)},
+							},
+						},
+					},
+				},
+			},
+		})
+	})
+
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2023, 10, 1, 12, 0, 30, 0, time.UTC))
+
+	env := environment.EphemeralWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+	reader := NewServiceMetricsReader(
+		env, &metricsResourceManager{}, mockazcli.NewAzCliFromMockContext(mockContext), mockClock)
+
+	metrics, err := reader.Read(*mockContext.Context, &ServiceConfig{Name: "api"}, time.Hour)
+	require.NoError(t, err)
+
+	require.Equal(t, "2023-10-01T11:00:00Z/2023-10-01T12:00:00Z", query["timespan"])
+	require.Equal(t, "PT5M", query["interval"])
+	require.Equal(t, "Requests,UsageNanoCores,WorkingSetBytes,Replicas", query["metricnames"])
+
+	require.Len(t, metrics, 4)
+	require.Equal(t, "Requests", metrics[0].Name)
+	require.Equal(t, 40.0, *metrics[0].Summary)
+
+	// Metrics without data have no summary
+	require.Equal(t, "CPU", metrics[1].Name)
+	require.Nil(t, metrics[1].Summary)
+
+	// Averages skip intervals without data
+	require.Equal(t, "Memory", metrics[2].Name)
+	require.Equal(t, "Bytes", metrics[2].Unit)
+	require.Len(t, metrics[2].Points, 3)
+	require.Equal(t, 200.0, *metrics[2].Summary)
+}
+
+func Test_metricInterval(t *testing.T) {
+	require.Equal(t, "PT1M", metricInterval(30*time.Minute))
+	require.Equal(t, "PT5M", metricInterval(time.Hour))
+	require.Equal(t, "PT1H", metricInterval(24*time.Hour))
+	require.Equal(t, "P1D", metricInterval(90*24*time.Hour))
+}
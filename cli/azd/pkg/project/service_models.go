@@ -21,7 +21,9 @@ type ServiceLifecycleEventArgs struct {
 // ServiceProgress represents an incremental progress message
 // during a service operation such as restore, build, package & deploy
 type ServiceProgress struct {
-	Message   string
+	Message string
+	// The completion percentage of the operation between 1 and 100, 0 when it isn't known
+	Percent   int
 	Timestamp time.Time
 }
 
@@ -89,17 +91,31 @@ type ServiceDeployResult struct {
 	Kind             ServiceTargetKind `json:"kind"`
 	Endpoints        []string          `json:"endpoints"`
 	Details          interface{}       `json:"details"`
+	// The smoke tests run against the deployed service, nil when the service doesn't define smoke tests
+	SmokeTest *SmokeTestResult `json:"smokeTest,omitempty"`
+	// The custom domain bound to the deployed service, nil when the service doesn't define a custom domain
+	CustomDomain *CustomDomain `json:"customDomain,omitempty"`
 }
 
 // Supports rendering messages for UX items
 func (spr *ServiceDeployResult) ToString(currentIndentation string) string {
-	uxItem, ok := spr.Details.(ux.UxItem)
-	if ok {
-		return uxItem.ToString(currentIndentation)
+	builder := strings.Builder{}
+
+	if uxItem, ok := spr.Details.(ux.UxItem); ok {
+		builder.WriteString(uxItem.ToString(currentIndentation))
+	} else {
+		spr.writeEndpoints(&builder, currentIndentation)
 	}
 
-	builder := strings.Builder{}
+	if spr.SmokeTest != nil {
+		builder.WriteString(fmt.Sprintf("%s- Smoke tests: %d passed in %d attempt(s)\n",
+			currentIndentation, len(spr.SmokeTest.Checks), spr.SmokeTest.Attempts))
+	}
+
+	return builder.String()
+}
 
+func (spr *ServiceDeployResult) writeEndpoints(builder *strings.Builder, currentIndentation string) {
 	if len(spr.Endpoints) == 0 {
 		builder.WriteString(fmt.Sprintf("%s- No endpoints were found\n", currentIndentation))
 	} else {
@@ -107,8 +123,6 @@ func (spr *ServiceDeployResult) ToString(currentIndentation string) string {
 			builder.WriteString(fmt.Sprintf("%s- Endpoint: %s\n", currentIndentation, output.WithLinkFormat(endpoint)))
 		}
 	}
-
-	return builder.String()
 }
 
 func (spr *ServiceDeployResult) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,161 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// The scope of the access tokens accepted by the Kudu (SCM) service of app services and function apps
+const scmTokenScope = "https://management.azure.com//.default"
+
+// Tokens are renewed this long before they expire, so requests don't reach the service with an expired token
+const proxyTokenRefreshMargin = 5 * time.Minute
+
+// tokenTransport adds an access token of the scope to the requests, the token is reused until it's about to expire
+type tokenTransport struct {
+	credential azcore.TokenCredential
+	scope      string
+	next       http.RoundTripper
+
+	mu    sync.Mutex
+	token *azcore.AccessToken
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.getToken(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("getting access token for scope '%s': %w", t.scope, err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.next.RoundTrip(req)
+}
+
+func (t *tokenTransport) getToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == nil || time.Until(t.token.ExpiresOn) < proxyTokenRefreshMargin {
+		token, err := t.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{t.scope}})
+		if err != nil {
+			return "", err
+		}
+
+		t.token = &token
+	}
+
+	return t.token.Token, nil
+}
+
+// newReverseProxy returns a handler forwarding the requests to the target URL. The requests are authenticated with an
+// access token of the scope when set.
+func newReverseProxy(target *url.URL, credential azcore.TokenCredential, scope string) (http.Handler, error) {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	// The host of the target is required by the front ends of Azure routing the requests to the app
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Host = target.Host
+	}
+
+	if scope != "" {
+		if credential == nil {
+			return nil, errors.New("a credential is required to authenticate the requests")
+		}
+
+		proxy.Transport = &tokenTransport{
+			credential: credential,
+			scope:      scope,
+			next:       http.DefaultTransport,
+		}
+	}
+
+	proxy.ErrorHandler = func(w http.ResponseWriter, req *http.Request, err error) {
+		log.Printf("proxy: %s %s failed: %v", req.Method, req.URL.Path, err)
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	return proxy, nil
+}
+
+// serveProxy serves the handler on the local port of the loopback interface until the context is canceled. A free port
+// is selected when the local port is 0, ready is called with the local URL once the proxy is listening.
+func serveProxy(ctx context.Context, handler http.Handler, localPort int, ready func(localUrl string)) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		return fmt.Errorf("listening on local port %d: %w", localPort, err)
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			server.Close()
+		case <-stopped:
+		}
+	}()
+
+	if ready != nil {
+		ready(fmt.Sprintf("http://%s", listener.Addr().String()))
+	}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// proxyHost forwards the requests of the local port to the https host until the context is canceled, the requests are
+// authenticated with an access token of the scope when set
+func proxyHost(ctx context.Context, hostName string, scope string, options ProxyOptions) error {
+	if options.RemotePort != 0 {
+		return errors.New("the remote port can't be selected, the requests are forwarded to the https endpoint")
+	}
+
+	target, err := proxyTarget(hostName)
+	if err != nil {
+		return err
+	}
+
+	handler, err := newReverseProxy(target, options.Credential, scope)
+	if err != nil {
+		return err
+	}
+
+	return serveProxy(ctx, handler, options.LocalPort, options.Ready)
+}
+
+// scmHostName returns the host of the Kudu (SCM) service of the app service or function app
+func scmHostName(appName string) string {
+	return fmt.Sprintf("%s.scm.azurewebsites.net", appName)
+}
+
+// proxyTarget returns the https URL of the host name, ex. the FQDN of the ingress of a container app
+func proxyTarget(hostName string) (*url.URL, error) {
+	if !strings.Contains(hostName, "://") {
+		hostName = "https://" + hostName
+	}
+
+	return url.Parse(hostName)
+}
@@ -0,0 +1,97 @@
+package project
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_serveProxy(t *testing.T) {
+	var host, authorization string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("hello from " + r.URL.Path))
+	}))
+	defer backend.Close()
+
+	target, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	tokens := 0
+	credential := &mocks.MockCredentials{
+		GetTokenFn: func(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+			tokens++
+			require.Equal(t, []string{scmTokenScope}, options.Scopes)
+			return azcore.AccessToken{Token: "TOKEN", ExpiresOn: time.Now().Add(time.Hour)}, nil
+		},
+	}
+
+	handler, err := newReverseProxy(target, credential, scmTokenScope)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	localUrls := make(chan string, 1)
+	served := make(chan error, 1)
+	go func() {
+		served <- serveProxy(ctx, handler, 0, func(localUrl string) {
+			localUrls <- localUrl
+		})
+	}()
+
+	localUrl := <-localUrls
+	for i := 0; i < 2; i++ {
+		res, err := http.Get(localUrl + "/api/settings")
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, "hello from /api/settings", string(body))
+	}
+
+	require.Equal(t, target.Host, host)
+	require.Equal(t, "Bearer TOKEN", authorization)
+	// The token is reused until it's about to expire
+	require.Equal(t, 1, tokens)
+
+	cancel()
+	require.NoError(t, <-served)
+}
+
+func Test_newReverseProxy_WithoutScope(t *testing.T) {
+	var authorization string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer backend.Close()
+
+	target, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	handler, err := newReverseProxy(target, nil, "")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, authorization)
+
+	_, err = newReverseProxy(target, nil, scmTokenScope)
+	require.Error(t, err)
+}
+
+func Test_proxyTarget(t *testing.T) {
+	target, err := proxyTarget(scmHostName("app-api"))
+	require.NoError(t, err)
+	require.Equal(t, "https://app-api.scm.azurewebsites.net", target.String())
+}
@@ -0,0 +1,262 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const (
+	defaultSmokeRetries  = 3
+	defaultSmokeInterval = 10 * time.Second
+	smokeRequestTimeout  = 30 * time.Second
+)
+
+// The environment variable set to the first endpoint of the service when running the smoke test command
+const SmokeEndpointEnvVarName = "SERVICE_ENDPOINT_URL"
+
+// ServiceSmokeOptions describes the smoke tests run against a service after it is deployed
+type ServiceSmokeOptions struct {
+	// The HTTP requests sent to each endpoint of the service
+	Http []SmokeHttpCheck `yaml:"http,omitempty"`
+	// The command testing the deployed service, run in the directory of the service with the values of the azd
+	// environment and SERVICE_ENDPOINT_URL set to the endpoint of the service
+	Run string `yaml:"run,omitempty"`
+	// The number of times failing smoke tests are retried. Defaults to 3.
+	Retries int `yaml:"retries,omitempty"`
+	// The delay between retries, ex. 30s. Defaults to 10s.
+	Interval string `yaml:"interval,omitempty"`
+	// When set, the package last deployed successfully is deployed again when the smoke tests fail
+	Rollback bool `yaml:"rollback,omitempty"`
+}
+
+// SmokeHttpCheck is an HTTP request sent to the service and the response expected from a healthy service
+type SmokeHttpCheck struct {
+	// The path of the request, relative to each endpoint of the service (ex. /api/todos)
+	Path string `yaml:"path"`
+	// The method of the request. Defaults to GET.
+	Method string `yaml:"method,omitempty"`
+	// The status code of the response. Defaults to any 2xx status code.
+	ExpectedStatus int `yaml:"expectedStatus,omitempty"`
+	// Text the body of the response must contain
+	Contains string `yaml:"contains,omitempty"`
+}
+
+// SmokeCheckResult is the outcome of a single smoke test
+type SmokeCheckResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// SmokeTestResult is the outcome of the last attempt of the smoke tests of a service
+type SmokeTestResult struct {
+	Passed   bool               `json:"passed"`
+	Attempts int                `json:"attempts"`
+	Checks   []SmokeCheckResult `json:"checks"`
+}
+
+// Failures returns the checks of the smoke tests that failed
+func (r *SmokeTestResult) Failures() []SmokeCheckResult {
+	failures := []SmokeCheckResult{}
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failures = append(failures, check)
+		}
+	}
+
+	return failures
+}
+
+// ServiceSmokeTester runs the smoke tests of deployed services
+type ServiceSmokeTester struct {
+	env           *environment.Environment
+	commandRunner exec.CommandRunner
+	httpClient    httputil.HttpClient
+}
+
+// NewServiceSmokeTester creates a new instance of the ServiceSmokeTester
+func NewServiceSmokeTester(
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	httpClient httputil.HttpClient,
+) *ServiceSmokeTester {
+	return &ServiceSmokeTester{
+		env:           env,
+		commandRunner: commandRunner,
+		httpClient:    httpClient,
+	}
+}
+
+// Run runs the smoke tests of the service against its endpoints, retrying failing tests. Failing tests are reported by
+// the result, an error is returned when the smoke tests are misconfigured or the context is cancelled.
+func (t *ServiceSmokeTester) Run(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	endpoints []string,
+) (*SmokeTestResult, error) {
+	options := serviceConfig.Smoke
+	if options == nil || (len(options.Http) == 0 && options.Run == "") {
+		return nil, fmt.Errorf("service '%s' does not define smoke tests", serviceConfig.Name)
+	}
+
+	retries := options.Retries
+	if retries <= 0 {
+		retries = defaultSmokeRetries
+	}
+
+	interval := defaultSmokeInterval
+	if options.Interval != "" {
+		parsed, err := time.ParseDuration(options.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("parsing smoke test interval of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		interval = parsed
+	}
+
+	result := &SmokeTestResult{}
+
+	for {
+		result.Attempts++
+		result.Checks = t.runChecks(ctx, serviceConfig, endpoints)
+		result.Passed = len(result.Failures()) == 0
+
+		if result.Passed || result.Attempts > retries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return result, nil
+}
+
+func (t *ServiceSmokeTester) runChecks(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	endpoints []string,
+) []SmokeCheckResult {
+	options := serviceConfig.Smoke
+	checks := []SmokeCheckResult{}
+
+	if len(options.Http) > 0 && len(endpoints) == 0 {
+		checks = append(checks, SmokeCheckResult{
+			Name:    "http",
+			Message: "service does not expose any endpoint",
+		})
+	}
+
+	for _, endpoint := range endpoints {
+		for _, check := range options.Http {
+			checks = append(checks, t.checkHttp(ctx, endpoint, check))
+		}
+	}
+
+	if options.Run != "" {
+		checks = append(checks, t.runCommand(ctx, serviceConfig, endpoints))
+	}
+
+	return checks
+}
+
+func (t *ServiceSmokeTester) checkHttp(ctx context.Context, endpoint string, check SmokeHttpCheck) SmokeCheckResult {
+	checkUrl, err := url.JoinPath(endpoint, check.Path)
+	if err != nil {
+		return SmokeCheckResult{Name: endpoint, Message: err.Error()}
+	}
+
+	method := check.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	result := SmokeCheckResult{Name: fmt.Sprintf("%s %s", method, checkUrl)}
+
+	ctx, cancel := context.WithTimeout(ctx, smokeRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, checkUrl, nil)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	res, err := t.httpClient.Do(req)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	defer res.Body.Close()
+
+	result.Message = res.Status
+	passed := res.StatusCode >= 200 && res.StatusCode < 300
+	if check.ExpectedStatus != 0 {
+		passed = res.StatusCode == check.ExpectedStatus
+	}
+
+	if passed && check.Contains != "" {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			result.Message = fmt.Sprintf("reading response: %v", err)
+			return result
+		}
+
+		if !strings.Contains(string(body), check.Contains) {
+			result.Message = fmt.Sprintf("%s, response does not contain '%s'", res.Status, check.Contains)
+			return result
+		}
+	}
+
+	result.Passed = passed
+	return result
+}
+
+func (t *ServiceSmokeTester) runCommand(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	endpoints []string,
+) SmokeCheckResult {
+	result := SmokeCheckResult{Name: serviceConfig.Smoke.Run}
+
+	env := t.env.Environ()
+	if len(endpoints) > 0 {
+		env = append(env, fmt.Sprintf("%s=%s", SmokeEndpointEnvVarName, endpoints[0]))
+	}
+
+	runArgs := exec.NewRunArgs(serviceConfig.Smoke.Run).
+		WithShell(true).
+		WithCwd(serviceConfig.Path()).
+		WithEnv(env)
+
+	_, err := t.commandRunner.Run(ctx, runArgs)
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.Message = fmt.Sprintf("exit code: %d", exitErr.ExitCode)
+	case err != nil:
+		result.Message = err.Error()
+	default:
+		result.Passed = true
+		result.Message = "exit code: 0"
+	}
+
+	return result
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceSmokeTester_Run(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "/api/todos", r.URL.Path)
+
+		// The service becomes healthy after it warms up
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`[{"name":"todo"}]`))
+	}))
+	defer server.Close()
+
+	mockContext := mocks.NewMockContext(context.Background())
+	var smokeEnv []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "npm run smoke")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		smokeEnv = args.Env
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	env := environment.EphemeralWithValues("test", map[string]string{"API_KEY": "KEY"})
+	tester := NewServiceSmokeTester(env, mockContext.CommandRunner, http.DefaultClient)
+
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJavaScript)
+	serviceConfig.Smoke = &ServiceSmokeOptions{
+		Http:     []SmokeHttpCheck{{Path: "/api/todos", Contains: "todo"}},
+		Run:      "npm run smoke",
+		Interval: "1ms",
+	}
+
+	t.Run("PassesAfterRetries", func(t *testing.T) {
+		result, err := tester.Run(*mockContext.Context, serviceConfig, []string{server.URL})
+		require.NoError(t, err)
+
+		require.True(t, result.Passed)
+		require.Equal(t, 3, result.Attempts)
+		require.Len(t, result.Checks, 2)
+		require.Contains(t, smokeEnv, "API_KEY=KEY")
+		require.Contains(t, smokeEnv, SmokeEndpointEnvVarName+"="+server.URL)
+	})
+
+	t.Run("FailsAfterRetries", func(t *testing.T) {
+		serviceConfig.Smoke.Retries = 1
+		serviceConfig.Smoke.Http[0].Contains = "missing"
+		t.Cleanup(func() {
+			serviceConfig.Smoke.Retries = 0
+			serviceConfig.Smoke.Http[0].Contains = "todo"
+		})
+
+		result, err := tester.Run(*mockContext.Context, serviceConfig, []string{server.URL})
+		require.NoError(t, err)
+
+		require.False(t, result.Passed)
+		require.Equal(t, 2, result.Attempts)
+		require.Equal(t, []SmokeCheckResult{
+			{
+				Name:    "GET " + server.URL + "/api/todos",
+				Message: "200 OK, response does not contain 'missing'",
+			},
+		}, result.Failures())
+	})
+
+	t.Run("NoEndpoints", func(t *testing.T) {
+		serviceConfig.Smoke.Retries = 1
+		t.Cleanup(func() { serviceConfig.Smoke.Retries = 0 })
+
+		result, err := tester.Run(*mockContext.Context, serviceConfig, nil)
+		require.NoError(t, err)
+		require.False(t, result.Passed)
+		require.Equal(t, "service does not expose any endpoint", result.Failures()[0].Message)
+	})
+
+	t.Run("FailingCommand", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJavaScript)
+		serviceConfig.Smoke = &ServiceSmokeOptions{Run: "npm run failing-smoke", Retries: 1, Interval: "1ms"}
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "npm run failing-smoke")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "", ""), &exec.ExitError{Cmd: "npm", ExitCode: 1}
+		})
+
+		result, err := tester.Run(*mockContext.Context, serviceConfig, []string{server.URL})
+		require.NoError(t, err)
+		require.False(t, result.Passed)
+		require.Equal(t, "exit code: 1", result.Failures()[0].Message)
+	})
+}
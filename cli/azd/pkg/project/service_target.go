@@ -6,10 +6,14 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/doctor"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -18,28 +22,128 @@ import (
 type ServiceTargetKind string
 
 const (
-	AppServiceTarget    ServiceTargetKind = "appservice"
-	ContainerAppTarget  ServiceTargetKind = "containerapp"
-	AzureFunctionTarget ServiceTargetKind = "function"
-	StaticWebAppTarget  ServiceTargetKind = "staticwebapp"
-	SpringAppTarget     ServiceTargetKind = "springapp"
-	AksTarget           ServiceTargetKind = "aks"
+	AppServiceTarget      ServiceTargetKind = "appservice"
+	ContainerAppTarget    ServiceTargetKind = "containerapp"
+	ContainerAppJobTarget ServiceTargetKind = "containerapp-job"
+	AzureFunctionTarget   ServiceTargetKind = "function"
+	StaticWebAppTarget    ServiceTargetKind = "staticwebapp"
+	SpringAppTarget       ServiceTargetKind = "springapp"
+	AksTarget             ServiceTargetKind = "aks"
 )
 
-func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
+var (
+	// Service target kinds contributed outside of azd, such as by extensions
+	externalServiceTargets = map[ServiceTargetKind]struct{}{}
+)
+
+func builtInServiceTarget(kind ServiceTargetKind) bool {
 	switch kind {
 	case AppServiceTarget,
 		ContainerAppTarget,
+		ContainerAppJobTarget,
 		AzureFunctionTarget,
 		StaticWebAppTarget,
 		SpringAppTarget,
 		AksTarget:
+		return true
+	}
+
+	return false
+}
+
+func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
+	if builtInServiceTarget(kind) || IsExternalServiceTarget(kind) {
 		return kind, nil
 	}
 
 	return ServiceTargetKind(""), fmt.Errorf("unsupported host '%s'", kind)
 }
 
+// Registers a service target kind that is implemented outside of azd.
+// The ServiceTarget implementation must be registered with the IoC container using the kind as the name.
+func RegisterExternalServiceTarget(kind ServiceTargetKind) error {
+	if kind == "" {
+		return errors.New("service target kind is required")
+	}
+
+	if builtInServiceTarget(kind) {
+		return fmt.Errorf("service target '%s' conflicts with a built-in service target", kind)
+	}
+
+	externalServiceTargets[kind] = struct{}{}
+	return nil
+}
+
+// Returns true when the service target kind has been registered as an external service target
+func IsExternalServiceTarget(kind ServiceTargetKind) bool {
+	_, has := externalServiceTargets[kind]
+	return has
+}
+
+// DoctorChecker is implemented by the service targets validating their requirements in `azd doctor`
+type DoctorChecker interface {
+	// DoctorChecks returns the checks of the requirements of the service deployed by the service target
+	DoctorChecks(serviceConfig *ServiceConfig) []doctor.Check
+}
+
+// ServiceLog is a line of the logs of a deployed service
+type ServiceLog struct {
+	Timestamp time.Time `json:"timestamp"`
+	// The instance of the service writing the line, ex. a replica
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+// LogProvider is implemented by the service targets able to retrieve the recent logs of a deployed service
+type LogProvider interface {
+	// Logs returns the lines logged by the service since the specified duration, the oldest first
+	Logs(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		since time.Duration,
+	) ([]ServiceLog, error)
+}
+
+// LogStreamer is implemented by the log providers able to stream the logs of a deployed service as they're written
+type LogStreamer interface {
+	// StreamLogs writes the lines logged by the service since the specified duration, then the new lines until the
+	// context is canceled
+	StreamLogs(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		since time.Duration,
+		write func(log ServiceLog) error,
+	) error
+}
+
+// ProxyOptions configures the local proxy of a deployed service
+type ProxyOptions struct {
+	// The local port the proxy listens on, a free port when 0
+	LocalPort int
+	// The port of the service the proxy forwards to, the first port of the service when 0. AKS only.
+	RemotePort int
+	// The credential authenticating the requests forwarded by the reverse proxies
+	Credential azcore.TokenCredential
+	// The scope of the access token added to the requests forwarded to the ingress of container apps, the requests
+	// are forwarded without token when empty
+	Scope string
+	// Called with the local URL of the proxy once it's listening
+	Ready func(localUrl string)
+}
+
+// Proxier is implemented by the service targets able to forward a local port to a deployed service
+type Proxier interface {
+	// Proxy forwards the local port to the deployed service until the context is canceled
+	Proxy(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		options ProxyOptions,
+	) error
+}
+
 type ServiceTarget interface {
 	// Initializes the service target for the specified service configuration.
 	// This allows service targets to opt-in to service lifecycle events
@@ -116,8 +220,14 @@ func resourceTypeMismatchError(
 //
 // As an example, ContainerAppTarget is able to provision the container app as part of deployment,
 // and thus returns true.
+// External service targets are responsible for their own resources and may not target an Azure resource.
 func (st ServiceTargetKind) SupportsDelayedProvisioning() bool {
-	return st == AksTarget
+	return st == AksTarget || IsExternalServiceTarget(st)
+}
+
+// RequiresContainer returns true if the services of the service target kind are deployed as container images
+func (st ServiceTargetKind) RequiresContainer() bool {
+	return st == ContainerAppTarget || st == ContainerAppJobTarget || st == AksTarget
 }
 
 func checkResourceType(resource *environment.TargetResource, expectedResourceType infra.AzureResourceType) error {
@@ -8,14 +8,17 @@ import (
 	"net/url"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/doctor"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -83,6 +86,30 @@ func (t *aksTarget) RequiredExternalTools(ctx context.Context) []tools.ExternalT
 	return allTools
 }
 
+// Validates the container registry and the AKS cluster of the service are known
+func (t *aksTarget) DoctorChecks(serviceConfig *ServiceConfig) []doctor.Check {
+	return []doctor.Check{
+		t.containerHelper.RegistryCheck(serviceConfig),
+		doctor.NewCheck("aks cluster", func(ctx context.Context) []doctor.Result {
+			clusterName, has := t.env.LookupEnv(environment.AksClusterEnvVarName)
+			if !has || clusterName == "" {
+				return []doctor.Result{{
+					Name:   serviceConfig.Name,
+					Status: doctor.StatusFailed,
+					Message: fmt.Sprintf("could not determine the AKS cluster, ensure %s is set as an output of your "+
+						"infrastructure", environment.AksClusterEnvVarName),
+				}}
+			}
+
+			return []doctor.Result{{
+				Name:    serviceConfig.Name,
+				Status:  doctor.StatusPassed,
+				Message: fmt.Sprintf("Deployed to cluster %s", clusterName),
+			}}
+		}),
+	}
+}
+
 // Initializes the AKS service target
 func (t *aksTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
 	// TODO: At some point in the future this an opportunity for the AKS target to
@@ -104,6 +131,15 @@ func (t *aksTarget) Package(
 	)
 }
 
+// Deploys the referenced container image to the AKS cluster
+func (t *aksTarget) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return t.containerHelper.PackageFromReference(ctx, serviceConfig, reference)
+}
+
 // Deploys service container images to ACR and AKS resources to the AKS cluster
 func (t *aksTarget) Deploy(
 	ctx context.Context,
@@ -124,43 +160,15 @@ func (t *aksTarget) Deploy(
 			}
 
 			// Login to AKS cluster
-			clusterName, has := t.env.LookupEnv(environment.AksClusterEnvVarName)
-			if !has {
-				task.SetError(fmt.Errorf(
-					"could not determine AKS cluster, ensure %s is set as an output of your infrastructure",
-					environment.AksClusterEnvVarName,
-				))
-				return
-			}
-
-			log.Printf("getting AKS credentials for cluster '%s'\n", clusterName)
 			task.SetProgress(NewServiceProgress("Getting AKS credentials"))
-			clusterCreds, err := t.managedClustersService.GetAdminCredentials(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				clusterName,
-			)
+			clusterName, kubeConfig, err := t.getClusterCredentials(ctx, targetResource)
 			if err != nil {
-				task.SetError(fmt.Errorf(
-					"failed retrieving cluster admin credentials. Ensure your cluster has been configured to support admin credentials, %w",
-					err,
-				))
-				return
-			}
-
-			if len(clusterCreds.Kubeconfigs) == 0 {
-				task.SetError(fmt.Errorf(
-					"cluster credentials is empty. Ensure your cluster has been configured to support admin credentials. , %w",
-					err,
-				))
+				task.SetError(err)
 				return
 			}
 
-			// The kubeConfig that we care about will also be at position 0
-			// I don't know if there is a valid use case where this credential results would container multiple configs
 			task.SetProgress(NewServiceProgress("Configuring k8s config context"))
-			err = t.configureK8sContext(ctx, clusterName, clusterCreds.Kubeconfigs[0])
+			err = t.configureK8sContext(ctx, clusterName, kubeConfig)
 			if err != nil {
 				task.SetError(err)
 				return
@@ -321,6 +329,168 @@ func (t *aksTarget) Endpoints(
 	return endpoints, nil
 }
 
+// Gets the recent logs of the containers of the pods of the deployment of the service
+func (t *aksTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+) ([]ServiceLog, error) {
+	return collectServiceLogs(func(write func(log ServiceLog) error) error {
+		return t.readLogs(ctx, serviceConfig, targetResource, since, false, write)
+	})
+}
+
+// Streams the logs of the containers of the pods of the deployment of the service
+func (t *aksTarget) StreamLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+	write func(log ServiceLog) error,
+) error {
+	return t.readLogs(ctx, serviceConfig, targetResource, since, true, write)
+}
+
+func (t *aksTarget) readLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+	follow bool,
+	write func(log ServiceLog) error,
+) error {
+	if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	clusterName, kubeConfig, err := t.getClusterCredentials(ctx, targetResource)
+	if err != nil {
+		return err
+	}
+
+	if err := t.configureK8sContext(ctx, clusterName, kubeConfig); err != nil {
+		return err
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	deploymentName := serviceConfig.K8s.Deployment.Name
+	if deploymentName == "" {
+		deploymentName = serviceConfig.Name
+	}
+
+	// The logs of all the pods of the deployment are read from the labels selecting its pods
+	deployment, err := kubectl.GetResource[kubectl.Deployment](
+		ctx, t.kubectl, kubectl.ResourceTypeDeployment, deploymentName, &kubectl.KubeCliFlags{Namespace: namespace})
+	if err != nil {
+		return fmt.Errorf("failed retrieving deployment '%s', %w", deploymentName, err)
+	}
+
+	options := kubectl.LogsOptions{Since: since, Follow: follow}
+	if len(deployment.Spec.Selector.MatchLabels) > 0 {
+		labels := make([]string, 0, len(deployment.Spec.Selector.MatchLabels))
+		for key, value := range deployment.Spec.Selector.MatchLabels {
+			labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+		}
+		slices.Sort(labels)
+		options.Selector = strings.Join(labels, ",")
+	} else {
+		options.Resource = fmt.Sprintf("deployment/%s", deploymentName)
+	}
+
+	// Lines are prefixed by their pod and container, ex. `[pod/api-7c9f/api] 2024-03-01T10:00:00Z Listening`
+	writer := &logLineWriter{write: func(line string) error {
+		source := ""
+		if prefix, rest, has := strings.Cut(line, "] "); has && strings.HasPrefix(prefix, "[") {
+			source = strings.TrimPrefix(strings.TrimPrefix(prefix, "["), "pod/")
+			line = rest
+		}
+
+		return write(parseServiceLog(source, line))
+	}}
+
+	if err := t.kubectl.Logs(ctx, options, &kubectl.KubeCliFlags{Namespace: namespace}, writer); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+// Forwards the local port to the port of the k8s service of the service with `kubectl port-forward`, the first port of
+// the k8s service unless the remote port of the options is set
+func (t *aksTarget) Proxy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options ProxyOptions,
+) error {
+	if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	clusterName, kubeConfig, err := t.getClusterCredentials(ctx, targetResource)
+	if err != nil {
+		return err
+	}
+
+	if err := t.configureK8sContext(ctx, clusterName, kubeConfig); err != nil {
+		return err
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	serviceName := serviceConfig.K8s.Service.Name
+	if serviceName == "" {
+		serviceName = serviceConfig.Name
+	}
+
+	remotePort := options.RemotePort
+	if remotePort == 0 {
+		service, err := kubectl.GetResource[kubectl.Service](
+			ctx, t.kubectl, kubectl.ResourceTypeService, serviceName, &kubectl.KubeCliFlags{Namespace: namespace})
+		if err != nil {
+			return fmt.Errorf("failed retrieving service '%s', %w", serviceName, err)
+		}
+
+		if len(service.Spec.Ports) == 0 {
+			return fmt.Errorf("service '%s' doesn't expose any port", serviceName)
+		}
+
+		remotePort = service.Spec.Ports[0].Port
+	}
+
+	// kubectl writes the forwarded addresses once listening, ex. `Forwarding from 127.0.0.1:8080 -> 80`
+	ready := false
+	writer := &logLineWriter{write: func(line string) error {
+		address, has := strings.CutPrefix(line, "Forwarding from ")
+		if !has || ready || options.Ready == nil {
+			return nil
+		}
+
+		if local, _, has := strings.Cut(address, " -> "); has && strings.HasPrefix(local, "127.0.0.1:") {
+			ready = true
+			options.Ready(fmt.Sprintf("http://%s", local))
+		}
+
+		return nil
+	}}
+
+	err = t.kubectl.PortForward(
+		ctx,
+		fmt.Sprintf("service/%s", serviceName),
+		options.LocalPort,
+		remotePort,
+		&kubectl.KubeCliFlags{Namespace: namespace},
+		writer,
+	)
+
+	// Forwarding stops when the context is canceled
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	return nil
+}
+
 func (t *aksTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -333,6 +503,45 @@ func (t *aksTarget) validateTargetResource(
 	return nil
 }
 
+// getClusterCredentials returns the name of the AKS cluster of the environment and its admin kube config
+func (t *aksTarget) getClusterCredentials(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+) (string, *armcontainerservice.CredentialResult, error) {
+	clusterName, has := t.env.LookupEnv(environment.AksClusterEnvVarName)
+	if !has {
+		return "", nil, fmt.Errorf(
+			"could not determine AKS cluster, ensure %s is set as an output of your infrastructure",
+			environment.AksClusterEnvVarName,
+		)
+	}
+
+	log.Printf("getting AKS credentials for cluster '%s'\n", clusterName)
+	clusterCreds, err := t.managedClustersService.GetAdminCredentials(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		clusterName,
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf(
+			"failed retrieving cluster admin credentials. Ensure your cluster has been configured to support admin credentials, %w",
+			err,
+		)
+	}
+
+	if len(clusterCreds.Kubeconfigs) == 0 {
+		return "", nil, fmt.Errorf(
+			"cluster credentials is empty. Ensure your cluster has been configured to support admin credentials. , %w",
+			err,
+		)
+	}
+
+	// The kubeConfig that we care about will also be at position 0
+	// I don't know if there is a valid use case where this credential results would container multiple configs
+	return clusterName, clusterCreds.Kubeconfigs[0], nil
+}
+
 func (t *aksTarget) configureK8sContext(
 	ctx context.Context,
 	clusterName string,
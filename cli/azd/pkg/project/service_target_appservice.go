@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
@@ -67,6 +68,15 @@ func (st *appServiceTarget) Package(
 	)
 }
 
+// Deploys the referenced zip archive, or the compressed referenced directory
+func (st *appServiceTarget) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return zipPackageFromReference(serviceConfig, reference)
+}
+
 // Deploys the prepared zip archive using Zip deploy to the Azure App Service resource
 func (st *appServiceTarget) Deploy(
 	ctx context.Context,
@@ -81,14 +91,34 @@ func (st *appServiceTarget) Deploy(
 				return
 			}
 
-			zipFile, err := os.Open(packageOutput.PackagePath)
+			env, err := serviceConfig.ResolveEnv(st.env.Getenv)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			if len(env) > 0 {
+				task.SetProgress(NewServiceProgress("Updating app settings"))
+				if err := st.cli.UpdateAppServiceAppSettings(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					env,
+				); err != nil {
+					task.SetError(fmt.Errorf("updating app settings of service %s: %w", serviceConfig.Name, err))
+					return
+				}
+			}
+
+			zipFile, file, err := openUploadProgressReader(packageOutput.PackagePath, task.SetProgress)
 			if err != nil {
 				task.SetError(fmt.Errorf("failed reading deployment zip file: %w", err))
 				return
 			}
 
 			defer os.Remove(packageOutput.PackagePath)
-			defer zipFile.Close()
+			defer file.Close()
 
 			task.SetProgress(NewServiceProgress("Uploading deployment package"))
 			res, err := st.cli.DeployAppServiceZip(
@@ -151,6 +181,52 @@ func (st *appServiceTarget) Endpoints(
 	return endpoints, nil
 }
 
+// Gets the container logs of the app service
+func (st *appServiceTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+) ([]ServiceLog, error) {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, err
+	}
+
+	return collectServiceLogs(func(write func(log ServiceLog) error) error {
+		return readAppServiceLogs(ctx, st.cli, targetResource, since, false, "", write)
+	})
+}
+
+// Streams the container logs of the app service from its log stream
+func (st *appServiceTarget) StreamLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+	write func(log ServiceLog) error,
+) error {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	return readAppServiceLogs(ctx, st.cli, targetResource, since, true, "", write)
+}
+
+// Forwards the local port to the Kudu (SCM) service of the app service, authenticated with an access token of the
+// account
+func (st *appServiceTarget) Proxy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options ProxyOptions,
+) error {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	return proxyHost(ctx, scmHostName(targetResource.ResourceName()), scmTokenScope, options)
+}
+
 func (st *appServiceTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
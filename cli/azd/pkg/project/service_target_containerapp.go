@@ -7,10 +7,12 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/doctor"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -46,6 +48,11 @@ func (at *containerAppTarget) RequiredExternalTools(ctx context.Context) []tools
 	return at.containerHelper.RequiredExternalTools(ctx)
 }
 
+// Validates the container registry of the service is known
+func (at *containerAppTarget) DoctorChecks(serviceConfig *ServiceConfig) []doctor.Check {
+	return []doctor.Check{at.containerHelper.RegistryCheck(serviceConfig)}
+}
+
 // Initializes the Container App target
 func (at *containerAppTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
 	if err := at.addPreProvisionChecks(ctx, serviceConfig); err != nil {
@@ -68,6 +75,15 @@ func (at *containerAppTarget) Package(
 	)
 }
 
+// Deploys the referenced container image to the container app
+func (at *containerAppTarget) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return at.containerHelper.PackageFromReference(ctx, serviceConfig, reference)
+}
+
 // Deploys service container images to ACR and provisions the container app service.
 func (at *containerAppTarget) Deploy(
 	ctx context.Context,
@@ -82,11 +98,17 @@ func (at *containerAppTarget) Deploy(
 				return
 			}
 
+			env, err := serviceConfig.ResolveEnv(at.env.Getenv)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			// Login, tag & push container image to ACR
 			containerDeployTask := at.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource)
 			syncProgress(task, containerDeployTask.Progress())
 
-			_, err := containerDeployTask.Await()
+			_, err = containerDeployTask.Await()
 			if err != nil {
 				task.SetError(err)
 				return
@@ -100,6 +122,7 @@ func (at *containerAppTarget) Deploy(
 				targetResource.ResourceGroupName(),
 				targetResource.ResourceName(),
 				imageName,
+				env,
 			)
 			if err != nil {
 				task.SetError(fmt.Errorf("updating container app service: %w", err))
@@ -150,6 +173,89 @@ func (at *containerAppTarget) Endpoints(
 	}
 }
 
+// Gets the recent console logs of the replicas of the latest revision of the container app
+func (at *containerAppTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+) ([]ServiceLog, error) {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, err
+	}
+
+	return collectServiceLogs(func(write func(log ServiceLog) error) error {
+		return at.readLogs(ctx, targetResource, since, false, write)
+	})
+}
+
+// Streams the console logs of the replicas of the latest revision of the container app
+func (at *containerAppTarget) StreamLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+	write func(log ServiceLog) error,
+) error {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	return at.readLogs(ctx, targetResource, since, true, write)
+}
+
+// readLogs writes the most recent lines of the replicas logged since the duration, the log streams of Container Apps
+// return a number of lines instead
+func (at *containerAppTarget) readLogs(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+	follow bool,
+	write func(log ServiceLog) error,
+) error {
+	filter := sinceFilter(time.Now().Add(-since), write)
+
+	return at.containerAppService.StreamLogs(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		containerapps.ContainerAppLogsOptions{TailLines: containerapps.MaxLogTailLines, Follow: follow},
+		func(replica string, line string) error {
+			return filter(parseServiceLog(replica, line))
+		},
+	)
+}
+
+// Forwards the local port to the ingress of the container app, the requests are authenticated with an access token of
+// the scope of the options when set, ex. the scope of the app registration of the authentication of the app
+func (at *containerAppTarget) Proxy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options ProxyOptions,
+) error {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	ingressConfig, err := at.containerAppService.GetIngressConfiguration(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	if len(ingressConfig.HostNames) == 0 {
+		return fmt.Errorf("container app '%s' has no ingress", targetResource.ResourceName())
+	}
+
+	return proxyHost(ctx, ingressConfig.HostNames[0], options.Scope, options)
+}
+
 func (at *containerAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
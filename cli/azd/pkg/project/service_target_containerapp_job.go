@@ -0,0 +1,228 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/doctor"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+type containerAppJobTarget struct {
+	env             *environment.Environment
+	containerHelper *ContainerHelper
+	jobService      containerapps.ContainerAppJobService
+}
+
+// NewContainerAppJobTarget creates the Container Apps job service target.
+//
+// Jobs don't expose endpoints; their deployments report the trigger and the latest execution of the job instead.
+func NewContainerAppJobTarget(
+	env *environment.Environment,
+	containerHelper *ContainerHelper,
+	jobService containerapps.ContainerAppJobService,
+) ServiceTarget {
+	return &containerAppJobTarget{
+		env:             env,
+		containerHelper: containerHelper,
+		jobService:      jobService,
+	}
+}
+
+// Gets the required external tools
+func (jt *containerAppJobTarget) RequiredExternalTools(ctx context.Context) []tools.ExternalTool {
+	return jt.containerHelper.RequiredExternalTools(ctx)
+}
+
+// Validates the container registry of the service is known
+func (jt *containerAppJobTarget) DoctorChecks(serviceConfig *ServiceConfig) []doctor.Check {
+	return []doctor.Check{jt.containerHelper.RegistryCheck(serviceConfig)}
+}
+
+// Initializes the Container Apps job target
+func (jt *containerAppJobTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Prepares and tags the container image from the build output based on the specified service configuration
+func (jt *containerAppJobTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(packageOutput)
+		},
+	)
+}
+
+// Runs the next executions of the job from the referenced container image
+func (jt *containerAppJobTarget) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return jt.containerHelper.PackageFromReference(ctx, serviceConfig, reference)
+}
+
+// Pushes the container image of the service to ACR and updates the image of the job, used by its next executions
+func (jt *containerAppJobTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			if err := jt.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+				task.SetError(fmt.Errorf("validating target resource: %w", err))
+				return
+			}
+
+			// Login, tag & push container image to ACR
+			containerDeployTask := jt.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource)
+			syncProgress(task, containerDeployTask.Progress())
+
+			_, err := containerDeployTask.Await()
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			imageName := jt.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+			task.SetProgress(NewServiceProgress("Updating container app job image"))
+			err = jt.jobService.UpdateImage(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				imageName,
+			)
+			if err != nil {
+				task.SetError(fmt.Errorf("updating container app job: %w", err))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Fetching status of container app job"))
+			status, err := jt.jobService.GetStatus(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+			)
+			if err != nil {
+				task.SetError(fmt.Errorf("fetching container app job status: %w", err))
+				return
+			}
+
+			task.SetResult(&ServiceDeployResult{
+				Package: packageOutput,
+				TargetResourceId: azure.ContainerAppJobRID(
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+				),
+				Kind:      ContainerAppJobTarget,
+				Endpoints: []string{},
+				Details:   &containerAppJobDeployResult{Status: status},
+			})
+		},
+	)
+}
+
+// Jobs don't expose any endpoint
+func (jt *containerAppJobTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	return []string{}, nil
+}
+
+// Gets the console logs of the executions of the job
+func (jt *containerAppJobTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+) ([]ServiceLog, error) {
+	jobLogs, err := jt.jobService.GetLogs(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]ServiceLog, len(jobLogs))
+	for i, jobLog := range jobLogs {
+		logs[i] = ServiceLog{Timestamp: jobLog.Timestamp, Source: jobLog.Replica, Message: jobLog.Message}
+	}
+
+	return logs, nil
+}
+
+func (jt *containerAppJobTarget) validateTargetResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	if targetResource.ResourceGroupName() == "" {
+		return fmt.Errorf("missing resource group name: %s", targetResource.ResourceGroupName())
+	}
+
+	if targetResource.ResourceType() != "" {
+		if err := checkResourceType(targetResource, infra.AzureResourceTypeContainerAppJob); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// containerAppJobDeployResult reports the status of a job in place of the endpoints of the service
+type containerAppJobDeployResult struct {
+	Status *containerapps.ContainerAppJobStatus
+}
+
+func (r *containerAppJobDeployResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+
+	trigger := r.Status.TriggerType
+	switch {
+	case r.Status.CronExpression != "":
+		trigger = fmt.Sprintf("%s (%s)", trigger, r.Status.CronExpression)
+	case len(r.Status.EventRules) > 0:
+		trigger = fmt.Sprintf("%s (%s)", trigger, strings.Join(r.Status.EventRules, ", "))
+	}
+	builder.WriteString(fmt.Sprintf("%s- Trigger: %s\n", currentIndentation, trigger))
+
+	if execution := r.Status.LatestExecution; execution != nil {
+		builder.WriteString(fmt.Sprintf(
+			"%s- Latest execution: %s %s\n", currentIndentation, execution.Name, output.WithHighLightFormat(execution.Status)))
+	} else {
+		builder.WriteString(fmt.Sprintf("%s- Latest execution: none yet\n", currentIndentation))
+	}
+
+	return builder.String()
+}
+
+func (r *containerAppJobDeployResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Status)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContainerAppJobTargetTypeValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*serviceTargetValidationTest{
+		"ValidateTypeSuccess": {
+			targetResource: environment.NewTargetResource(
+				"SUB_ID",
+				"RG_ID",
+				"res",
+				string(infra.AzureResourceTypeContainerAppJob),
+			),
+			expectError: false,
+		},
+		"ValidateTypeFail": {
+			targetResource: environment.NewTargetResource(
+				"SUB_ID",
+				"RG_ID",
+				"res",
+				string(infra.AzureResourceTypeContainerApp),
+			),
+			expectError: true,
+		},
+	}
+
+	for test, data := range tests {
+		t.Run(test, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			serviceTarget := &containerAppJobTarget{}
+			serviceConfig := &ServiceConfig{}
+
+			err := serviceTarget.validateTargetResource(*mockContext.Context, serviceConfig, data.targetResource)
+			if data.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ContainerAppJob_Deploy(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForDocker(mockContext)
+	setupMocksForAcr(mockContext)
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppJobTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	jobService := &fakeContainerAppJobService{
+		status: &containerapps.ContainerAppJobStatus{
+			TriggerType:    "Schedule",
+			CronExpression: "*/5 * * * *",
+			LatestExecution: &containerapps.ContainerAppJobExecution{
+				Name:   "JOB-abc",
+				Status: "Succeeded",
+			},
+		},
+	}
+	serviceTarget := createContainerAppJobServiceTarget(mockContext, env, jobService)
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "IMAGE_HASH",
+			ImageTag:  "test-app/api-test:azd-deploy-0",
+		},
+	}
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"JOB",
+		string(infra.AzureResourceTypeContainerAppJob),
+	)
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+
+	require.NoError(t, err)
+	require.Equal(t, ContainerAppJobTarget, deployResult.Kind)
+	require.Empty(t, deployResult.Endpoints)
+	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", jobService.updatedImage)
+	require.Equal(t,
+		"- Trigger: Schedule (*/5 * * * *)\n- Latest execution: JOB-abc Succeeded\n",
+		deployResult.Details.(*containerAppJobDeployResult).ToString(""))
+}
+
+func createContainerAppJobServiceTarget(
+	mockContext *mocks.MockContext,
+	env *environment.Environment,
+	jobService containerapps.ContainerAppJobService,
+) ServiceTarget {
+	dockerCli := docker.NewDocker(mockContext.CommandRunner)
+	credentialProvider := mockaccount.SubscriptionCredentialProviderFunc(
+		func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		})
+
+	containerRegistryService := azcli.NewContainerRegistryService(credentialProvider, mockContext.HttpClient, dockerCli)
+	containerHelper := NewContainerHelper(env, clock.NewMock(), containerRegistryService, dockerCli)
+
+	return NewContainerAppJobTarget(env, containerHelper, jobService)
+}
+
+type fakeContainerAppJobService struct {
+	status       *containerapps.ContainerAppJobStatus
+	updatedImage string
+}
+
+func (s *fakeContainerAppJobService) UpdateImage(
+	ctx context.Context, subscriptionId string, resourceGroupName string, jobName string, imageName string,
+) error {
+	s.updatedImage = imageName
+	return nil
+}
+
+func (s *fakeContainerAppJobService) GetStatus(
+	ctx context.Context, subscriptionId string, resourceGroupName string, jobName string,
+) (*containerapps.ContainerAppJobStatus, error) {
+	return s.status, nil
+}
+
+func (s *fakeContainerAppJobService) GetLogs(
+	ctx context.Context, subscriptionId string, resourceGroupName string, jobName string, since time.Duration,
+) ([]containerapps.ContainerAppJobLog, error) {
+	return nil, nil
+}
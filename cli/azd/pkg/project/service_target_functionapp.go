@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
@@ -68,6 +69,15 @@ func (f *functionAppTarget) Package(
 	)
 }
 
+// Deploys the referenced zip archive, or the compressed referenced directory
+func (f *functionAppTarget) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return zipPackageFromReference(serviceConfig, reference)
+}
+
 // Deploys the prepared zip archive using Zip deploy to the Azure App Service resource
 func (f *functionAppTarget) Deploy(
 	ctx context.Context,
@@ -82,14 +92,34 @@ func (f *functionAppTarget) Deploy(
 				return
 			}
 
-			zipFile, err := os.Open(packageOutput.PackagePath)
+			env, err := serviceConfig.ResolveEnv(f.env.Getenv)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			if len(env) > 0 {
+				task.SetProgress(NewServiceProgress("Updating app settings"))
+				if err := f.cli.UpdateAppServiceAppSettings(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					env,
+				); err != nil {
+					task.SetError(fmt.Errorf("updating app settings of service %s: %w", serviceConfig.Name, err))
+					return
+				}
+			}
+
+			zipFile, file, err := openUploadProgressReader(packageOutput.PackagePath, task.SetProgress)
 			if err != nil {
 				task.SetError(fmt.Errorf("failed reading deployment zip file: %w", err))
 				return
 			}
 
 			defer os.Remove(packageOutput.PackagePath)
-			defer zipFile.Close()
+			defer file.Close()
 
 			task.SetProgress(NewServiceProgress("Uploading deployment package"))
 			res, err := f.cli.DeployFunctionAppUsingZipFile(
@@ -153,6 +183,52 @@ func (f *functionAppTarget) Endpoints(
 	}
 }
 
+// Gets the container logs of the function app
+func (f *functionAppTarget) Logs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+) ([]ServiceLog, error) {
+	if err := f.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, err
+	}
+
+	return collectServiceLogs(func(write func(log ServiceLog) error) error {
+		return readAppServiceLogs(ctx, f.cli, targetResource, since, false, functionAppLogStreamPath, write)
+	})
+}
+
+// Streams the container logs of the function app and of the invocations of its functions from its log stream
+func (f *functionAppTarget) StreamLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	since time.Duration,
+	write func(log ServiceLog) error,
+) error {
+	if err := f.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	return readAppServiceLogs(ctx, f.cli, targetResource, since, true, functionAppLogStreamPath, write)
+}
+
+// Forwards the local port to the Kudu (SCM) service of the function app, authenticated with an access token of the
+// account
+func (f *functionAppTarget) Proxy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options ProxyOptions,
+) error {
+	if err := f.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return err
+	}
+
+	return proxyHost(ctx, scmHostName(targetResource.ResourceName()), scmTokenScope, options)
+}
+
 func (f *functionAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -69,6 +69,21 @@ func (st *springAppTarget) Package(
 	)
 }
 
+// Deploys the referenced jar, or the directory containing the jar
+func (st *springAppTarget) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return directoryPackageFromReference(serviceConfig, reference, func(path string) (string, error) {
+		if filepath.Ext(path) != ".jar" {
+			return "", fmt.Errorf("package '%s' is not a jar, spring apps are deployed from a jar", path)
+		}
+
+		return copyToPackageDirectory(path, AppServiceJavaPackageName+".jar")
+	})
+}
+
 // Upload artifact to Storage File and deploy to Spring App
 func (st *springAppTarget) Deploy(
 	ctx context.Context,
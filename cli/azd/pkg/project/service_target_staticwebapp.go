@@ -73,6 +73,17 @@ func (at *staticWebAppTarget) Package(
 	)
 }
 
+// Deploys the referenced build output directory
+func (at *staticWebAppTarget) PackageFromReference(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	reference *PackageReference,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return directoryPackageFromReference(serviceConfig, reference, func(path string) (string, error) {
+		return "", fmt.Errorf("package '%s' is not a directory, static web apps are deployed from a directory", path)
+	})
+}
+
 // Deploys the packaged build output using the SWA CLI
 func (at *staticWebAppTarget) Deploy(
 	ctx context.Context,
@@ -0,0 +1,221 @@
+package project
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+)
+
+// ServiceTestOptions describes the tests of a service run by `azd test`
+type ServiceTestOptions struct {
+	// The command running the tests in the directory of the service, ex. npm test
+	Run string `yaml:"run"`
+	// The additional environment variables of the tests. Values can reference the values of the azd environment,
+	// ex. ${API_URL}, or Key Vault secrets, ex. akvs://<vault>/<secret>
+	Env map[string]ExpandableString `yaml:"env,omitempty"`
+}
+
+// ServiceTestResult is the outcome of running the tests of a service
+type ServiceTestResult struct {
+	Service  string
+	Passed   bool
+	ExitCode int
+	Duration time.Duration
+	// The combined stdout & stderr of the tests
+	Output string
+}
+
+// ServiceTester runs the tests of services with the values of the azd environment
+type ServiceTester struct {
+	env             *environment.Environment
+	secretsProvider keyvault.SecretsProvider
+	commandRunner   exec.CommandRunner
+}
+
+// NewServiceTester creates a new instance of the ServiceTester
+func NewServiceTester(
+	env *environment.Environment,
+	secretsProvider keyvault.SecretsProvider,
+	commandRunner exec.CommandRunner,
+) *ServiceTester {
+	return &ServiceTester{
+		env:             env,
+		secretsProvider: secretsProvider,
+		commandRunner:   commandRunner,
+	}
+}
+
+// Test runs the tests of the service, raising the test event of the service. Failing tests are reported by the result,
+// an error is returned when the tests could not run. The output of the tests is copied to output when not nil.
+func (t *ServiceTester) Test(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	output io.Writer,
+) (*ServiceTestResult, error) {
+	if serviceConfig.Test == nil || serviceConfig.Test.Run == "" {
+		return nil, fmt.Errorf("service '%s' does not define tests, add test.run to the service", serviceConfig.Name)
+	}
+
+	env, err := t.testEnv(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ServiceTestResult{Service: serviceConfig.Name}
+	eventArgs := ServiceLifecycleEventArgs{
+		Project: serviceConfig.Project,
+		Service: serviceConfig,
+	}
+
+	err = serviceConfig.Invoke(ctx, ServiceEventTest, eventArgs, func() error {
+		runArgs := exec.NewRunArgs(serviceConfig.Test.Run).
+			WithShell(true).
+			WithCwd(serviceConfig.Path()).
+			WithEnv(env)
+		if output != nil {
+			runArgs = runArgs.WithStdOut(output).WithStdErr(output)
+		}
+
+		start := time.Now()
+		res, err := t.commandRunner.Run(ctx, runArgs)
+		result.Duration = time.Since(start)
+		result.ExitCode = res.ExitCode
+		result.Output = res.Stdout + res.Stderr
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("running the tests of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		result.Passed = result.ExitCode == 0
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// testEnv returns the values of the azd environment along with the environment variables of the tests, resolving the
+// Key Vault secrets they reference
+func (t *ServiceTester) testEnv(ctx context.Context, serviceConfig *ServiceConfig) ([]string, error) {
+	env := t.env.Environ()
+
+	names := make([]string, 0, len(serviceConfig.Test.Env))
+	for name := range serviceConfig.Test.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		expression := serviceConfig.Test.Env[name]
+		value, err := expression.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating test environment variable '%s': %w", name, err)
+		}
+
+		if keyvault.IsSecretReference(value) {
+			value, err = t.resolveSecret(ctx, value)
+			if err != nil {
+				return nil, fmt.Errorf("resolving test environment variable '%s': %w", name, err)
+			}
+		}
+
+		env = append(env, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	return env, nil
+}
+
+func (t *ServiceTester) resolveSecret(ctx context.Context, value string) (string, error) {
+	reference, err := keyvault.ParseSecretReference(value)
+	if err != nil {
+		return "", err
+	}
+
+	return t.secretsProvider.GetSecret(ctx, t.env.GetSubscriptionId(), *reference)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Output  string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes the results as a JUnit XML report with a test case per service, so CI systems can display
+// the results of `azd test`
+func WriteJUnitReport(w io.Writer, projectName string, results []*ServiceTestResult) error {
+	suite := junitTestSuite{
+		Name:  projectName,
+		Tests: len(results),
+	}
+
+	var total time.Duration
+	for _, result := range results {
+		total += result.Duration
+		testCase := junitTestCase{
+			Name:      result.Service,
+			ClassName: projectName,
+			Time:      junitSeconds(result.Duration),
+		}
+
+		if result.Passed {
+			testCase.SystemOut = result.Output
+		} else {
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("tests exited with code %d", result.ExitCode),
+				Output:  result.Output,
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = junitSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}})
+}
+
+func junitSeconds(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', 3, 64)
+}
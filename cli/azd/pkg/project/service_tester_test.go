@@ -0,0 +1,83 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceTester(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.EphemeralWithValues("test", map[string]string{
+		"API_URL": "https://api.azurewebsites.net",
+	})
+
+	var runArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "npm test")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		return exec.NewRunResult(0, "4 passing", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJavaScript)
+	serviceConfig.Test = &ServiceTestOptions{
+		Run: "npm test",
+		Env: map[string]ExpandableString{
+			"BASE_URL": NewExpandableString("${API_URL}/api"),
+		},
+	}
+
+	tester := NewServiceTester(
+		env, keyvault.NewSecretsProvider(mockazcli.NewAzCliFromMockContext(mockContext)), mockContext.CommandRunner)
+	result, err := tester.Test(*mockContext.Context, serviceConfig, nil)
+	require.NoError(t, err)
+
+	require.True(t, result.Passed)
+	require.Equal(t, "4 passing", result.Output)
+	require.True(t, runArgs.UseShell)
+	require.Equal(t, serviceConfig.Path(), runArgs.Cwd)
+	require.Contains(t, runArgs.Env, "API_URL=https://api.azurewebsites.net")
+	require.Contains(t, runArgs.Env, "BASE_URL=https://api.azurewebsites.net/api")
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "npm test")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "1 failing", ""), &exec.ExitError{Cmd: "npm test", ExitCode: 1}
+		})
+
+		result, err := tester.Test(*mockContext.Context, serviceConfig, nil)
+		require.NoError(t, err)
+		require.False(t, result.Passed)
+		require.Equal(t, 1, result.ExitCode)
+	})
+
+	t.Run("NoTests", func(t *testing.T) {
+		_, err := tester.Test(
+			*mockContext.Context, createTestServiceConfig("./src/web", AppServiceTarget, ServiceLanguageJavaScript), nil)
+		require.ErrorContains(t, err, "does not define tests")
+	})
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	var report bytes.Buffer
+	err := WriteJUnitReport(&report, "todo", []*ServiceTestResult{
+		{Service: "api", Passed: true, Duration: 1500 * time.Millisecond, Output: "4 passing"},
+		{Service: "web", ExitCode: 1, Duration: 2 * time.Second, Output: "1 failing"},
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, report.String(), `<testsuite name="todo" tests="2" failures="1" time="3.500">`)
+	require.Contains(t, report.String(), `<testcase name="api" classname="todo" time="1.500">`)
+	require.Contains(t, report.String(), `<failure message="tests exited with code 1">1 failing</failure>`)
+}
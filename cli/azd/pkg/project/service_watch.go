@@ -0,0 +1,195 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// The interval between two scans of the source files of the watched services
+const defaultWatchInterval = 500 * time.Millisecond
+
+// WatchOptions configures the watching of the source files of services
+type WatchOptions struct {
+	// The changes are reported once no source file changed for the duration, so the files saved together are reported
+	// by a single change
+	Debounce time.Duration
+	// The glob patterns of the files and directories that aren't watched, matched against their name and their path
+	// relative to the service directory, ex. *.log or docs. The files of ignored directories aren't watched.
+	Ignore []string
+	// The interval between two scans of the source files, 500ms when 0
+	Interval time.Duration
+}
+
+// The state of a source file compared by the scans
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+}
+
+// WatchServices scans the source files of the services until the context is canceled, and calls changed with the
+// services whose source files were added, modified or removed. The directories of restored dependencies and build
+// output aren't watched, like the fingerprint of the package cache. Watching stops when changed fails.
+func WatchServices(
+	ctx context.Context,
+	services []*ServiceConfig,
+	options WatchOptions,
+	changed func(ctx context.Context, services []*ServiceConfig) error,
+) error {
+	for _, pattern := range options.Ignore {
+		if _, err := path.Match(filepath.ToSlash(pattern), ""); err != nil {
+			return fmt.Errorf("invalid ignore pattern '%s': %w", pattern, err)
+		}
+	}
+
+	interval := options.Interval
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+
+	snapshots := make([]map[string]watchedFile, len(services))
+	for i, svc := range services {
+		snapshot, err := scanServiceSources(svc, options.Ignore)
+		if err != nil {
+			return fmt.Errorf("watching service '%s': %w", svc.Name, err)
+		}
+
+		snapshots[i] = snapshot
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pending := map[int]bool{}
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		for i, svc := range services {
+			snapshot, err := scanServiceSources(svc, options.Ignore)
+			if err != nil {
+				// Files can be removed while they're scanned, the next scan reports the change
+				log.Printf("scanning the sources of service '%s': %v", svc.Name, err)
+				continue
+			}
+
+			if !maps.Equal(snapshot, snapshots[i]) {
+				snapshots[i] = snapshot
+				pending[i] = true
+				lastChange = time.Now()
+			}
+		}
+
+		if len(pending) == 0 || time.Since(lastChange) < options.Debounce {
+			continue
+		}
+
+		indexes := maps.Keys(pending)
+		slices.Sort(indexes)
+		pending = map[int]bool{}
+
+		changedServices := make([]*ServiceConfig, len(indexes))
+		for i, index := range indexes {
+			changedServices[i] = services[index]
+		}
+
+		if err := changed(ctx, changedServices); err != nil {
+			return err
+		}
+
+		// The files written while the services were deployed, ex. by their build, aren't reported as changes
+		for _, index := range indexes {
+			if snapshot, err := scanServiceSources(services[index], options.Ignore); err == nil {
+				snapshots[index] = snapshot
+			}
+		}
+	}
+}
+
+// scanServiceSources returns the state of the source files of the service, by path
+func scanServiceSources(serviceConfig *ServiceConfig, ignore []string) (map[string]watchedFile, error) {
+	files := map[string]watchedFile{}
+
+	for _, root := range serviceSourceRoots(serviceConfig) {
+		outputDir := ""
+		if serviceConfig.OutputPath != "" {
+			outputDir = filepath.Join(root, serviceConfig.OutputPath)
+		}
+
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if path == root {
+				return nil
+			}
+
+			relative, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				_, excluded := packageCacheExcludedDirs[entry.Name()]
+				if excluded || path == outputDir || isWatchIgnored(relative, ignore) {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !entry.Type().IsRegular() || isWatchIgnored(relative, ignore) {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+
+			files[path] = watchedFile{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// isWatchIgnored returns whether the file or directory at the relative path matches one of the ignore patterns
+func isWatchIgnored(relative string, ignore []string) bool {
+	relative = filepath.ToSlash(relative)
+	name := relative[strings.LastIndex(relative, "/")+1:]
+
+	for _, pattern := range ignore {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+
+		if matched, _ := path.Match(pattern, relative); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WatchServices(t *testing.T) {
+	projectDir := t.TempDir()
+	api := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+	api.Name = "api"
+	api.Project.Path = projectDir
+	web := createTestServiceConfig("./src/web", AppServiceTarget, ServiceLanguageTypeScript)
+	web.Name = "web"
+	web.Project.Path = projectDir
+
+	for _, svc := range []*ServiceConfig{api, web} {
+		require.NoError(t, os.MkdirAll(filepath.Join(svc.Path(), "node_modules"), osutil.PermissionDirectory))
+		require.NoError(t, os.MkdirAll(filepath.Join(svc.Path(), "docs"), osutil.PermissionDirectory))
+		writeFile(t, filepath.Join(svc.Path(), "index.js"), "console.log('hello')")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan []string, 10)
+	watched := make(chan error, 1)
+	go func() {
+		watched <- WatchServices(ctx, []*ServiceConfig{api, web}, WatchOptions{
+			Debounce: 50 * time.Millisecond,
+			Ignore:   []string{"*.log", "docs"},
+			Interval: 10 * time.Millisecond,
+		}, func(ctx context.Context, services []*ServiceConfig) error {
+			names := []string{}
+			for _, svc := range services {
+				names = append(names, svc.Name)
+			}
+
+			changes <- names
+			return nil
+		})
+	}()
+
+	// Let the watcher record the initial state of the sources
+	time.Sleep(50 * time.Millisecond)
+
+	// Restored dependencies and ignored files aren't changes
+	writeFile(t, filepath.Join(api.Path(), "node_modules", "dep.js"), "module.exports = {}")
+	writeFile(t, filepath.Join(api.Path(), "server.log"), "started")
+	writeFile(t, filepath.Join(api.Path(), "docs", "README.md"), "# api")
+
+	// Files saved together are reported by a single change
+	writeFile(t, filepath.Join(web.Path(), "index.js"), "console.log('changed')")
+	writeFile(t, filepath.Join(web.Path(), "app.js"), "console.log('added')")
+
+	select {
+	case names := <-changes:
+		require.Equal(t, []string{"web"}, names)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the change of the service wasn't reported")
+	}
+
+	select {
+	case names := <-changes:
+		require.Fail(t, "unexpected change", "%v", names)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-watched)
+}
+
+func Test_isWatchIgnored(t *testing.T) {
+	ignore := []string{"*.log", "docs/", "tests/fixtures"}
+
+	require.True(t, isWatchIgnored("server.log", ignore))
+	require.True(t, isWatchIgnored(filepath.Join("logs", "server.log"), ignore))
+	require.True(t, isWatchIgnored("docs", ignore))
+	require.True(t, isWatchIgnored(filepath.Join("tests", "fixtures"), ignore))
+	require.False(t, isWatchIgnored("fixtures", ignore))
+	require.False(t, isWatchIgnored("index.js", ignore))
+}
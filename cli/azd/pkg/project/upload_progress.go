@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The minimum time between upload progress reports
+const uploadProgressInterval = 500 * time.Millisecond
+
+// The width of the upload progress bar
+const uploadProgressBarWidth = 20
+
+// uploadProgressReader reads a deployment package while reporting the progress & throughput of the upload.
+// Seeking to the start of the package, as done when the upload is retried, restarts the progress.
+type uploadProgressReader struct {
+	file   io.ReadSeeker
+	size   int64
+	report func(ServiceProgress)
+	now    func() time.Time
+
+	mu         sync.Mutex
+	read       int64
+	start      time.Time
+	lastReport time.Time
+}
+
+// Opens the deployment package at the specified path, reporting the upload progress with the report func
+func openUploadProgressReader(path string, report func(ServiceProgress)) (*uploadProgressReader, *os.File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return newUploadProgressReader(file, info.Size(), report), file, nil
+}
+
+func newUploadProgressReader(file io.ReadSeeker, size int64, report func(ServiceProgress)) *uploadProgressReader {
+	return &uploadProgressReader{
+		file:   file,
+		size:   size,
+		report: report,
+		now:    time.Now,
+	}
+}
+
+func (r *uploadProgressReader) Read(p []byte) (int, error) {
+	n, err := r.file.Read(p)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if r.start.IsZero() {
+		r.start = now
+	}
+
+	r.read += int64(n)
+	if now.Sub(r.lastReport) >= uploadProgressInterval || (err == io.EOF && r.read == r.size) {
+		r.lastReport = now
+		progress := NewServiceProgress(r.message(now))
+		progress.Percent = r.percent()
+		r.report(progress)
+	}
+
+	return n, err
+}
+
+func (r *uploadProgressReader) Seek(offset int64, whence int) (int64, error) {
+	position, err := r.file.Seek(offset, whence)
+	if err != nil {
+		return position, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.read = position
+	if position == 0 {
+		r.start = time.Time{}
+	}
+
+	return position, nil
+}
+
+// Formats the upload progress. (ex. `Uploading deployment package [=====>     ] 45% (18.0 MB/40.0 MB, 3.2 MB/s)`)
+func (r *uploadProgressReader) message(now time.Time) string {
+	percent := r.percent()
+
+	filled := percent * uploadProgressBarWidth / 100
+	bar := strings.Repeat("=", filled)
+	if filled < uploadProgressBarWidth {
+		bar += ">" + strings.Repeat(" ", uploadProgressBarWidth-filled-1)
+	}
+
+	throughput := 0.0
+	if elapsed := now.Sub(r.start).Seconds(); elapsed > 0 {
+		throughput = float64(r.read) / elapsed
+	}
+
+	return fmt.Sprintf(
+		"Uploading deployment package [%s] %d%% (%s/%s, %s/s)",
+		bar,
+		percent,
+		formatBytes(float64(r.read)),
+		formatBytes(float64(r.size)),
+		formatBytes(throughput),
+	)
+}
+
+// The percentage of the package read
+func (r *uploadProgressReader) percent() int {
+	if r.size > 0 {
+		return int(r.read * 100 / r.size)
+	}
+
+	return 100
+}
+
+// FormatBytes formats the byte count for display, ex. 1.5 MB
+func FormatBytes(size int64) string {
+	return formatBytes(float64(size))
+}
+
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+
+	return fmt.Sprintf("%.1f %s", bytes, units[unit])
+}
@@ -0,0 +1,41 @@
+package project
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UploadProgressReader(t *testing.T) {
+	contents := bytes.Repeat([]byte("a"), 2048)
+	messages := []string{}
+
+	reader := newUploadProgressReader(bytes.NewReader(contents), int64(len(contents)), func(progress ServiceProgress) {
+		messages = append(messages, progress.Message)
+	})
+
+	now := time.Now()
+	reader.now = func() time.Time { return now }
+
+	buffer := make([]byte, 1024)
+	_, err := reader.Read(buffer)
+	require.NoError(t, err)
+	require.Equal(t, "Uploading deployment package [==========>         ] 50% (1.0 KB/2.0 KB, 0 B/s)", messages[0])
+
+	// Retried uploads seek to the start of the package and restart the progress
+	_, err = reader.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	now = now.Add(time.Second)
+	_, err = reader.Read(buffer)
+	require.NoError(t, err)
+	require.Equal(t, "Uploading deployment package [==========>         ] 50% (1.0 KB/2.0 KB, 0 B/s)", messages[1])
+
+	now = now.Add(time.Second)
+	_, err = reader.Read(buffer)
+	require.NoError(t, err)
+	require.Equal(t, "Uploading deployment package [====================] 100% (2.0 KB/2.0 KB, 2.0 KB/s)", messages[2])
+}
@@ -2,7 +2,11 @@ package templates
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 )
 
 // Absolute returns an absolute template path, given a possibly relative template path. An absolute path also corresponds to
@@ -26,3 +30,37 @@ func Absolute(path string) (string, error) {
 				"or <repo> for Azure-Samples GitHub repositories", path)
 	}
 }
+
+// Cached returns the local clone of the template within the template cache, ex.
+// ~/.azd/templates/github.com/Azure-Samples/todo-nodejs-mongo. Templates are initialized from the cache in air-gapped
+// mode, templates are cached by cloning them into the cache from a connected machine.
+func Cached(path string) (string, error) {
+	abs, err := Absolute(path)
+	if err != nil {
+		return "", err
+	}
+
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	// git@github.com:owner/repo.git and https://github.com/owner/repo are cached in github.com/owner/repo
+	repoPath := abs
+	if _, after, found := strings.Cut(repoPath, "://"); found {
+		repoPath = after
+	}
+	repoPath = strings.TrimPrefix(repoPath, "git@")
+	repoPath = strings.Replace(repoPath, ":", "/", 1)
+	repoPath = strings.TrimSuffix(strings.TrimSuffix(repoPath, "/"), ".git")
+
+	cachedPath := filepath.Join(configDir, "templates", filepath.FromSlash(repoPath))
+	if _, err := os.Stat(filepath.Join(cachedPath, ".git")); err != nil {
+		return "", fmt.Errorf(
+			"template '%s' is not cached. In air-gapped mode templates are initialized from the template cache, "+
+				"cache it from a connected machine with `git clone %s %s`",
+			path, abs, cachedPath)
+	}
+
+	return cachedPath, nil
+}
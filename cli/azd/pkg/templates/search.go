@@ -0,0 +1,45 @@
+package templates
+
+import (
+	"strings"
+)
+
+// SearchOptions filters the templates found by `azd template search`. Empty options match all the templates.
+type SearchOptions struct {
+	// The words found in the name, description or repository path of the template, in any order
+	Query string
+	// The programming language of the template, ex. python
+	Language string
+	// The Azure service hosting the template, ex. containerapp
+	Host string
+	// The infrastructure as code provider of the template, bicep or terraform
+	IacProvider string
+}
+
+// Matches returns true when the template matches the query and all the filters. Comparisons ignore casing.
+func (o *SearchOptions) Matches(template *Template) bool {
+	text := strings.ToLower(strings.Join([]string{template.Name, template.Description, template.RepositoryPath}, " "))
+	for _, word := range strings.Fields(strings.ToLower(o.Query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+
+	return matchesFilter(template.Languages, o.Language) &&
+		matchesFilter(template.Hosts, o.Host) &&
+		matchesFilter(template.IacProviders, o.IacProvider)
+}
+
+func matchesFilter(values []string, filter string) bool {
+	if filter == "" {
+		return true
+	}
+
+	for _, value := range values {
+		if strings.EqualFold(value, filter) {
+			return true
+		}
+	}
+
+	return false
+}
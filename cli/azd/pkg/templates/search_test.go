@@ -0,0 +1,24 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SearchOptions_Matches(t *testing.T) {
+	template := &Template{
+		Name:           "Containerized React Web App with Python API and MongoDB",
+		RepositoryPath: "todo-python-mongo-aca",
+		Languages:      []string{"python"},
+		Hosts:          []string{"containerapp"},
+		IacProviders:   []string{"bicep"},
+	}
+
+	require.True(t, (&SearchOptions{}).Matches(template))
+	require.True(t, (&SearchOptions{Query: "mongo react"}).Matches(template))
+	require.True(t, (&SearchOptions{Language: "Python", Host: "containerapp", IacProvider: "bicep"}).Matches(template))
+	require.False(t, (&SearchOptions{Query: "mongo sql"}).Matches(template))
+	require.False(t, (&SearchOptions{Language: "java"}).Matches(template))
+	require.False(t, (&SearchOptions{IacProvider: "terraform"}).Matches(template))
+}
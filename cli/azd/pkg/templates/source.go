@@ -0,0 +1,246 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/resources"
+)
+
+// Source lists the templates of a template gallery, ex. the curated templates of azd or the private templates of an
+// organization.
+type Source interface {
+	// Name is the name identifying the source, ex. awesome-azd.
+	Name() string
+	// ListTemplates lists the templates of the source.
+	ListTemplates(ctx context.Context) ([]Template, error)
+}
+
+// The types of template sources
+const (
+	// The curated templates included in azd
+	SourceTypeResource = "resource"
+	// The community templates of the awesome-azd gallery
+	SourceTypeAwesomeAzd = "awesome-azd"
+	// A JSON list of templates downloaded from a url, ex. a blob of a storage account
+	SourceTypeUrl = "url"
+	// A JSON list of templates in a local file
+	SourceTypeFile = "file"
+	// A JSON list of templates in the templates.json file at the root of a git repository
+	SourceTypeGit = "git"
+)
+
+// The names of the sources always searched
+const (
+	DefaultSourceName    = "default"
+	AwesomeAzdSourceName = "awesome-azd"
+)
+
+// The url of the awesome-azd template gallery index
+const awesomeAzdIndexUrl = "https://raw.githubusercontent.com/Azure/awesome-azd/main/website/static/templates.json"
+
+// The file listing the templates of git repository sources
+const gitSourceTemplatesFile = "templates.json"
+
+// SourceConfig configures a template source, stored in the `template.sources.<name>` of the user config.
+type SourceConfig struct {
+	Name string `json:"name"`
+	// One of resource, awesome-azd, url, file or git
+	Type string `json:"type"`
+	// The url, path or git repository of the url, file and git sources
+	Location string `json:"location,omitempty"`
+}
+
+// NewSource creates the source of the config.
+func NewSource(config *SourceConfig, httpClient httputil.HttpClient, gitCli git.GitCli) (Source, error) {
+	if config.Type != SourceTypeResource && config.Type != SourceTypeAwesomeAzd && config.Location == "" {
+		return nil, fmt.Errorf("template source '%s' of type '%s' requires a location", config.Name, config.Type)
+	}
+
+	switch config.Type {
+	case SourceTypeResource:
+		return &jsonSource{name: config.Name, read: func(ctx context.Context) ([]byte, error) {
+			return resources.TemplatesJson, nil
+		}}, nil
+	case SourceTypeAwesomeAzd:
+		url := config.Location
+		if url == "" {
+			url = awesomeAzdIndexUrl
+		}
+
+		return &awesomeAzdSource{name: config.Name, httpClient: httpClient, url: url}, nil
+	case SourceTypeUrl:
+		return &jsonSource{name: config.Name, read: func(ctx context.Context) ([]byte, error) {
+			return download(ctx, httpClient, config.Location)
+		}}, nil
+	case SourceTypeFile:
+		return &jsonSource{name: config.Name, read: func(ctx context.Context) ([]byte, error) {
+			return os.ReadFile(config.Location)
+		}}, nil
+	case SourceTypeGit:
+		return &jsonSource{name: config.Name, read: func(ctx context.Context) ([]byte, error) {
+			return readFromRepository(ctx, gitCli, config.Location)
+		}}, nil
+	default:
+		return nil, fmt.Errorf(
+			"template source '%s' has unsupported type '%s', supported types: %s",
+			config.Name,
+			config.Type,
+			strings.Join(
+				[]string{SourceTypeResource, SourceTypeAwesomeAzd, SourceTypeUrl, SourceTypeFile, SourceTypeGit}, ", "),
+		)
+	}
+}
+
+// SourceTypeOf infers the type of the source at the location: git for git repositories, url for the other urls and
+// file otherwise.
+func SourceTypeOf(location string) string {
+	switch {
+	case strings.HasPrefix(location, "git@") || strings.HasSuffix(location, ".git"):
+		return SourceTypeGit
+	case strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://"):
+		return SourceTypeUrl
+	default:
+		return SourceTypeFile
+	}
+}
+
+// jsonSource lists the templates of a JSON array of templates, in the format of the templates.json of azd.
+type jsonSource struct {
+	name string
+	read func(ctx context.Context) ([]byte, error)
+}
+
+func (s *jsonSource) Name() string {
+	return s.name
+}
+
+func (s *jsonSource) ListTemplates(ctx context.Context) ([]Template, error) {
+	contents, err := s.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading templates of source '%s': %w", s.name, err)
+	}
+
+	var templates []Template
+	if err := json.Unmarshal(contents, &templates); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal templates of source '%s': %w", s.name, err)
+	}
+
+	for i := range templates {
+		templates[i].Source = s.name
+	}
+
+	return templates, nil
+}
+
+// awesomeAzdSource lists the templates of the awesome-azd gallery.
+type awesomeAzdSource struct {
+	name       string
+	httpClient httputil.HttpClient
+	url        string
+}
+
+// A template of the awesome-azd gallery index
+type awesomeAzdTemplate struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Source        string   `json:"source"`
+	Languages     []string `json:"languages"`
+	AzureServices []string `json:"azureServices"`
+	IaC           []string `json:"IaC"`
+}
+
+// The names of the awesome-azd tags matching azd names, the other tags are kept as is
+var awesomeAzdAliases = map[string]string{
+	"dotnetcsharp": "csharp",
+	"aca":          "containerapp",
+	"functions":    "function",
+	"swa":          "staticwebapp",
+}
+
+func (s *awesomeAzdSource) Name() string {
+	return s.name
+}
+
+func (s *awesomeAzdSource) ListTemplates(ctx context.Context) ([]Template, error) {
+	contents, err := download(ctx, s.httpClient, s.url)
+	if err != nil {
+		return nil, fmt.Errorf("reading templates of source '%s': %w", s.name, err)
+	}
+
+	var index []awesomeAzdTemplate
+	if err := json.Unmarshal(contents, &index); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal templates of source '%s': %w", s.name, err)
+	}
+
+	templates := make([]Template, 0, len(index))
+	for _, entry := range index {
+		templates = append(templates, Template{
+			Name:           entry.Title,
+			Description:    entry.Description,
+			RepositoryPath: entry.Source,
+			Languages:      normalizeTags(entry.Languages),
+			Hosts:          normalizeTags(entry.AzureServices),
+			IacProviders:   normalizeTags(entry.IaC),
+			Source:         s.name,
+		})
+	}
+
+	return templates, nil
+}
+
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if alias, has := awesomeAzdAliases[tag]; has {
+			tag = alias
+		}
+
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+func download(ctx context.Context, httpClient httputil.HttpClient, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: unexpected status code %d", url, res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// readFromRepository reads the templates.json at the root of the default branch of the git repository
+func readFromRepository(ctx context.Context, gitCli git.GitCli, repositoryUrl string) ([]byte, error) {
+	cloneDir, err := os.MkdirTemp("", "azd-template-source-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(cloneDir)
+
+	if err := gitCli.ShallowClone(ctx, repositoryUrl, "", cloneDir); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filepath.Join(cloneDir, gitSourceTemplatesFile))
+}
@@ -0,0 +1,182 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"golang.org/x/exp/slices"
+)
+
+// The config path where template sources are stored within the azd user config
+const sourcesConfigPath = "template.sources"
+
+var ErrSourceNotFound = errors.New("template source not found")
+
+var sourceNameRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// The sources searched in addition to the configured sources
+var defaultSources = []*SourceConfig{
+	{Name: DefaultSourceName, Type: SourceTypeResource},
+	{Name: AwesomeAzdSourceName, Type: SourceTypeAwesomeAzd, Location: awesomeAzdIndexUrl},
+}
+
+// SourceManager manages the template sources searched by `azd template search`, the curated templates of azd, the
+// awesome-azd gallery and the sources added to the user config.
+type SourceManager struct {
+	httpClient        httputil.HttpClient
+	userConfigManager config.UserConfigManager
+	gitCli            git.GitCli
+}
+
+func NewSourceManager(
+	httpClient httputil.HttpClient,
+	userConfigManager config.UserConfigManager,
+	commandRunner exec.CommandRunner,
+) *SourceManager {
+	return &SourceManager{
+		httpClient:        httpClient,
+		userConfigManager: userConfigManager,
+		gitCli:            git.NewGitCli(commandRunner),
+	}
+}
+
+// Gets the default sources followed by the configured sources sorted by name
+func (m *SourceManager) SourceConfigs() ([]*SourceConfig, error) {
+	azdConfig, err := m.userConfigManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := append([]*SourceConfig{}, defaultSources...)
+	value, has := azdConfig.Get(sourcesConfigPath)
+	if !has {
+		return sources, nil
+	}
+
+	sourcesJson, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceMap := map[string]*SourceConfig{}
+	if err := json.Unmarshal(sourcesJson, &sourceMap); err != nil {
+		return nil, fmt.Errorf("failed parsing template sources from config: %w", err)
+	}
+
+	configured := []*SourceConfig{}
+	for name, source := range sourceMap {
+		source.Name = name
+		configured = append(configured, source)
+	}
+
+	sort.Slice(configured, func(i, j int) bool {
+		return configured[i].Name < configured[j].Name
+	})
+
+	return append(sources, configured...), nil
+}
+
+// Gets the sources of the configs, the sources of all the configs when names is empty
+func (m *SourceManager) Sources(names ...string) ([]Source, error) {
+	configs, err := m.SourceConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []Source{}
+	for _, name := range names {
+		if !containsSource(configs, name) {
+			return nil, fmt.Errorf("%w: '%s'", ErrSourceNotFound, name)
+		}
+	}
+
+	for _, sourceConfig := range configs {
+		if len(names) > 0 && !slices.Contains(names, sourceConfig.Name) {
+			continue
+		}
+
+		source, err := NewSource(sourceConfig, m.httpClient, m.gitCli)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// Adds or replaces a template source
+func (m *SourceManager) AddSource(source *SourceConfig) error {
+	if !sourceNameRegex.MatchString(source.Name) {
+		return fmt.Errorf("source name '%s' must only contain lower case letters, numbers and hyphens", source.Name)
+	}
+
+	if containsSource(defaultSources, source.Name) {
+		return fmt.Errorf("source name '%s' is reserved for a default template source", source.Name)
+	}
+
+	// Validates the type & location
+	if _, err := NewSource(source, m.httpClient, m.gitCli); err != nil {
+		return err
+	}
+
+	azdConfig, err := m.userConfigManager.Load()
+	if err != nil {
+		return err
+	}
+
+	err = azdConfig.Set(fmt.Sprintf("%s.%s", sourcesConfigPath, source.Name), map[string]any{
+		"type":     source.Type,
+		"location": source.Location,
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.userConfigManager.Save(azdConfig)
+}
+
+// Removes the template source with the specified name
+func (m *SourceManager) RemoveSource(name string) error {
+	if containsSource(defaultSources, name) {
+		return fmt.Errorf("the default template source '%s' can't be removed", name)
+	}
+
+	configs, err := m.SourceConfigs()
+	if err != nil {
+		return err
+	}
+
+	if !containsSource(configs, name) {
+		return fmt.Errorf("%w: '%s'", ErrSourceNotFound, name)
+	}
+
+	azdConfig, err := m.userConfigManager.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := azdConfig.Unset(fmt.Sprintf("%s.%s", sourcesConfigPath, name)); err != nil {
+		return err
+	}
+
+	return m.userConfigManager.Save(azdConfig)
+}
+
+func containsSource(sources []*SourceConfig, name string) bool {
+	for _, source := range sources {
+		if source.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
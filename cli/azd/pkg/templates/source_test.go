@@ -0,0 +1,142 @@
+package templates
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SourceManager_Sources(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	sourceManager := NewSourceManager(
+		mockContext.HttpClient, &memoryUserConfigManager{config: config.NewEmptyConfig()}, mockContext.CommandRunner)
+
+	err := sourceManager.AddSource(&SourceConfig{
+		Name:     "contoso",
+		Type:     SourceTypeUrl,
+		Location: "https://contoso.blob.core.windows.net/templates/templates.json",
+	})
+	require.NoError(t, err)
+
+	configs, err := sourceManager.SourceConfigs()
+	require.NoError(t, err)
+	require.Len(t, configs, 3)
+	require.Equal(t, DefaultSourceName, configs[0].Name)
+	require.Equal(t, AwesomeAzdSourceName, configs[1].Name)
+	require.Equal(t, &SourceConfig{
+		Name:     "contoso",
+		Type:     SourceTypeUrl,
+		Location: "https://contoso.blob.core.windows.net/templates/templates.json",
+	}, configs[2])
+
+	sources, err := sourceManager.Sources("contoso")
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.Equal(t, "contoso", sources[0].Name())
+
+	_, err = sourceManager.Sources("fabrikam")
+	require.ErrorIs(t, err, ErrSourceNotFound)
+
+	require.Error(t, sourceManager.AddSource(&SourceConfig{Name: DefaultSourceName, Type: SourceTypeFile, Location: "a"}))
+	require.Error(t, sourceManager.AddSource(&SourceConfig{Name: "Contoso", Type: SourceTypeFile, Location: "a"}))
+	require.Error(t, sourceManager.AddSource(&SourceConfig{Name: "fabrikam", Type: "blob", Location: "a"}))
+	require.Error(t, sourceManager.AddSource(&SourceConfig{Name: "fabrikam", Type: SourceTypeUrl}))
+
+	require.Error(t, sourceManager.RemoveSource(AwesomeAzdSourceName))
+	require.NoError(t, sourceManager.RemoveSource("contoso"))
+	require.ErrorIs(t, sourceManager.RemoveSource("contoso"), ErrSourceNotFound)
+}
+
+func Test_Source_ListTemplates(t *testing.T) {
+	t.Run("Resource", func(t *testing.T) {
+		source, err := NewSource(&SourceConfig{Name: DefaultSourceName, Type: SourceTypeResource}, nil, nil)
+		require.NoError(t, err)
+
+		templates, err := source.ListTemplates(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, templates)
+		require.Equal(t, DefaultSourceName, templates[0].Source)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "templates.json")
+		err := os.WriteFile(
+			path,
+			[]byte(`[{"name": "Orders", "repositoryPath": "https://github.com/contoso/orders", "languages": ["java"]}]`),
+			osutil.PermissionFile)
+		require.NoError(t, err)
+
+		source, err := NewSource(&SourceConfig{Name: "contoso", Type: SourceTypeFile, Location: path}, nil, nil)
+		require.NoError(t, err)
+
+		templates, err := source.ListTemplates(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []Template{{
+			Name:           "Orders",
+			RepositoryPath: "https://github.com/contoso/orders",
+			Languages:      []string{"java"},
+			Source:         "contoso",
+		}}, templates)
+	})
+
+	t.Run("AwesomeAzd", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.URL.String() == awesomeAzdIndexUrl
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, []map[string]any{
+				{
+					"title":         "Chat app",
+					"description":   "A chat app",
+					"source":        "https://github.com/Azure-Samples/chat-app",
+					"languages":     []string{"dotnetCsharp"},
+					"azureServices": []string{"aca", "openai"},
+					"IaC":           []string{"bicep"},
+				},
+			})
+		})
+
+		source, err := NewSource(
+			&SourceConfig{Name: AwesomeAzdSourceName, Type: SourceTypeAwesomeAzd}, mockContext.HttpClient, nil)
+		require.NoError(t, err)
+
+		templates, err := source.ListTemplates(*mockContext.Context)
+		require.NoError(t, err)
+		require.Equal(t, []Template{{
+			Name:           "Chat app",
+			Description:    "A chat app",
+			RepositoryPath: "https://github.com/Azure-Samples/chat-app",
+			Languages:      []string{"csharp"},
+			Hosts:          []string{"containerapp", "openai"},
+			IacProviders:   []string{"bicep"},
+			Source:         AwesomeAzdSourceName,
+		}}, templates)
+	})
+}
+
+func Test_SourceTypeOf(t *testing.T) {
+	require.Equal(t, SourceTypeGit, SourceTypeOf("https://github.com/contoso/templates.git"))
+	require.Equal(t, SourceTypeGit, SourceTypeOf("git@github.com:contoso/templates.git"))
+	require.Equal(t, SourceTypeUrl, SourceTypeOf("https://contoso.blob.core.windows.net/templates/templates.json"))
+	require.Equal(t, SourceTypeFile, SourceTypeOf("./templates.json"))
+}
+
+type memoryUserConfigManager struct {
+	config config.Config
+}
+
+func (m *memoryUserConfigManager) Load() (config.Config, error) {
+	return m.config, nil
+}
+
+func (m *memoryUserConfigManager) Save(cfg config.Config) error {
+	m.config = cfg
+	return nil
+}
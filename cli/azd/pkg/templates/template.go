@@ -19,6 +19,18 @@ type Template struct {
 	// "{owner}/{repo}" for GitHub repositories,
 	// or "{repo}" for GitHub repositories under Azure-Samples (default organization).
 	RepositoryPath string `json:"repositoryPath"`
+
+	// Languages are the programming languages of the template services, ex. python.
+	Languages []string `json:"languages,omitempty"`
+
+	// Hosts are the Azure services hosting the template services, ex. containerapp.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// IacProviders are the infrastructure as code providers of the template, bicep or terraform.
+	IacProviders []string `json:"iacProviders,omitempty"`
+
+	// Source is the name of the template source listing the template, ex. awesome-azd.
+	Source string `json:"source,omitempty"`
 }
 
 // Display writes a string representation of the template suitable for display.
@@ -36,6 +48,19 @@ func (t *Template) Display(writer io.Writer) error {
 		{"Description", ":", t.Description},
 	}
 
+	for _, list := range []struct {
+		heading string
+		values  []string
+	}{
+		{"Languages", t.Languages},
+		{"Hosts", t.Hosts},
+		{"IacProviders", t.IacProviders},
+	} {
+		if len(list.values) > 0 {
+			text = append(text, []string{list.heading, ":", strings.Join(list.values, ", ")})
+		}
+	}
+
 	for _, line := range text {
 		_, err := tabs.Write([]byte(strings.Join(line, "\t") + "\n"))
 		if err != nil {
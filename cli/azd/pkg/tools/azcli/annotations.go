@@ -0,0 +1,62 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) CreateAppInsightsAnnotation(
+	ctx context.Context,
+	subscriptionId string,
+	componentId string,
+	annotation azsdk.Annotation,
+) error {
+	client, err := cli.createAnnotationsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Create(ctx, componentId, annotation); err != nil {
+		return fmt.Errorf("creating annotation: %w", err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) ListAppInsightsAnnotations(
+	ctx context.Context,
+	subscriptionId string,
+	componentId string,
+	start time.Time,
+	end time.Time,
+) ([]azsdk.Annotation, error) {
+	client, err := cli.createAnnotationsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := client.List(ctx, componentId, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("listing annotations: %w", err)
+	}
+
+	return annotations, nil
+}
+
+func (cli *azCli) createAnnotationsClient(ctx context.Context, subscriptionId string) (*azsdk.AnnotationsClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewAnnotationsClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating annotations client: %w", err)
+	}
+
+	return client, nil
+}
@@ -53,6 +53,14 @@ type AzCli interface {
 		resourceId string,
 		apiVersion string,
 	) (AzCliResourceExtended, error)
+	// CreateOrUpdateResource creates the resource with the specified id or updates it when it already exists
+	CreateOrUpdateResource(
+		ctx context.Context,
+		subscriptionId string,
+		resourceId string,
+		apiVersion string,
+		resource armresources.GenericResource,
+	) (AzCliResource, error)
 	GetKeyVault(
 		ctx context.Context,
 		subscriptionId string,
@@ -71,6 +79,14 @@ type AzCli interface {
 		vaultName string,
 		secretName string,
 	) (*AzCliKeyVaultSecret, error)
+	// SetKeyVaultSecret creates the secret in the vault, or adds a new version of the secret when it already exists
+	SetKeyVaultSecret(
+		ctx context.Context,
+		subscriptionId string,
+		vaultName string,
+		secretName string,
+		value string,
+	) error
 	GetAppConfig(
 		ctx context.Context, subscriptionId string, resourceGroupName string, configName string) (*AzCliAppConfig, error)
 	PurgeApim(ctx context.Context, subscriptionId string, apimName string, location string) error
@@ -79,6 +95,195 @@ type AzCli interface {
 	PurgeCognitiveAccount(ctx context.Context, subscriptionId, location, resourceGroup, accountName string) error
 	GetApim(
 		ctx context.Context, subscriptionId string, resourceGroupName string, apimName string) (*AzCliApim, error)
+	// QueryLogAnalytics runs the KQL query against the Log Analytics workspace with the specified resource id
+	QueryLogAnalytics(
+		ctx context.Context,
+		subscriptionId string,
+		workspaceId string,
+		query string,
+		timespan string,
+	) (*azsdk.LogAnalyticsQueryResult, error)
+	// GetMetrics returns the Azure Monitor platform metrics of the resource with the specified id
+	GetMetrics(
+		ctx context.Context,
+		subscriptionId string,
+		resourceId string,
+		query azsdk.MetricsQuery,
+	) (*azsdk.MetricsResult, error)
+	// CreateAppInsightsAnnotation adds the annotation to the Application Insights component with the specified id
+	CreateAppInsightsAnnotation(
+		ctx context.Context,
+		subscriptionId string,
+		componentId string,
+		annotation azsdk.Annotation,
+	) error
+	// ListAppInsightsAnnotations returns the annotations of the Application Insights component between start & end
+	ListAppInsightsAnnotations(
+		ctx context.Context,
+		subscriptionId string,
+		componentId string,
+		start time.Time,
+		end time.Time,
+	) ([]azsdk.Annotation, error)
+	// ListResourceGroupLocks returns the management locks of the resource group and the resources within it
+	ListResourceGroupLocks(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+	) ([]azsdk.ManagementLock, error)
+	// DeleteManagementLock removes the management lock with the specified id
+	DeleteManagementLock(ctx context.Context, subscriptionId string, lockId string) error
+	// CreateOrUpdateStorageAccount creates the general purpose v2 storage account in the resource group
+	CreateOrUpdateStorageAccount(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		accountName string,
+		location string,
+		tags map[string]*string,
+	) error
+	// CreateOrUpdateBlobContainer creates the private blob container of the storage account
+	CreateOrUpdateBlobContainer(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		accountName string,
+		containerName string,
+	) error
+	// GetDeploymentStack returns the deployment stack of the resource group, or of the subscription when the resource
+	// group name is empty
+	GetDeploymentStack(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		stackName string,
+	) (*azsdk.DeploymentStack, error)
+	// CreateOrUpdateDeploymentStack deploys the deployment stack to the resource group, or to the subscription when the
+	// resource group name is empty
+	CreateOrUpdateDeploymentStack(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		stackName string,
+		stack azsdk.DeploymentStack,
+	) (*azsdk.DeploymentStack, error)
+	// DeleteDeploymentStack deletes the deployment stack along with the resources and resource groups it manages
+	DeleteDeploymentStack(ctx context.Context, subscriptionId string, resourceGroupName string, stackName string) error
+	// ListResourceGroupRoleAssignments returns the role assignments of the resource group and the resources within it,
+	// deleted with the resource group
+	ListResourceGroupRoleAssignments(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+	) ([]AzCliRoleAssignment, error)
+	// GetRoleDefinitionId returns the id of the role definition with the name, ex. AcrPull, assignable at the scope
+	GetRoleDefinitionId(ctx context.Context, subscriptionId string, scope string, roleName string) (string, error)
+	// CreateRoleAssignment assigns the role to the principal at the scope, with the name of the role assignment. An
+	// existing assignment of the role to the principal at the scope isn't an error.
+	CreateRoleAssignment(ctx context.Context, subscriptionId string, name string, assignment AzCliRoleAssignment) error
+	// DeleteRoleAssignment deletes the role assignment with the name at the scope, if it exists
+	DeleteRoleAssignment(ctx context.Context, subscriptionId string, scope string, name string) error
+	// GetDevCenterEnvironment returns the Azure Deployment Environment of the current user in the dev center project
+	GetDevCenterEnvironment(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		environmentName string,
+	) (*azsdk.DevCenterEnvironment, error)
+	// CreateOrUpdateDevCenterEnvironment deploys the Azure Deployment Environment of the current user in the dev center
+	// project and waits for the deployment to complete
+	CreateOrUpdateDevCenterEnvironment(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		environmentName string,
+		environment azsdk.DevCenterEnvironment,
+	) (*azsdk.DevCenterEnvironment, error)
+	// DeleteDevCenterEnvironment deletes the Azure Deployment Environment of the current user in the dev center project
+	DeleteDevCenterEnvironment(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		environmentName string,
+	) error
+	// GetDevCenterEnvironmentDefinition returns the environment definition of the catalog of the dev center project
+	GetDevCenterEnvironmentDefinition(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		catalogName string,
+		definitionName string,
+	) (*azsdk.DevCenterEnvironmentDefinition, error)
+	// GetDevBox returns the dev box of the current user in the dev center project
+	GetDevBox(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		devBoxName string,
+	) (*azsdk.DevBox, error)
+	// CreateDevBox creates a dev box for the current user from the pool of the dev center project
+	CreateDevBox(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		devBoxName string,
+		poolName string,
+	) (*azsdk.DevBox, error)
+	// StartDevBox starts the dev box of the current user in the dev center project
+	StartDevBox(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		devBoxName string,
+	) error
+	// GetDevBoxRemoteConnection returns the URLs used to connect to the dev box of the current user
+	GetDevBoxRemoteConnection(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		devBoxName string,
+	) (*azsdk.DevBoxRemoteConnection, error)
+	// CreateDevBoxCustomizationGroup runs the tasks of the customization group on the dev box of the current user
+	CreateDevBoxCustomizationGroup(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		projectName string,
+		devBoxName string,
+		group azsdk.DevBoxCustomizationGroup,
+	) (*azsdk.DevBoxCustomizationGroup, error)
+	// QueryCost returns the usage cost of the resources within the scope (ex. a resource group id)
+	QueryCost(
+		ctx context.Context,
+		subscriptionId string,
+		scope string,
+		query azsdk.CostQuery,
+	) (*azsdk.CostQueryResult, error)
+	// ForecastCost returns the forecasted cost of the resources within the scope
+	ForecastCost(
+		ctx context.Context,
+		subscriptionId string,
+		scope string,
+		query azsdk.CostQuery,
+	) (*azsdk.CostQueryResult, error)
+	// GetBudget returns the budget of the scope with the specified name, or nil when the budget doesn't exist
+	GetBudget(ctx context.Context, subscriptionId string, scope string, name string) (*azsdk.Budget, error)
+	// CreateOrUpdateBudget creates or replaces the budget of the scope with the specified name
+	CreateOrUpdateBudget(
+		ctx context.Context,
+		subscriptionId string,
+		scope string,
+		name string,
+		budget azsdk.Budget,
+	) (*azsdk.Budget, error)
 	DeployAppServiceZip(
 		ctx context.Context,
 		subscriptionId string,
@@ -117,6 +322,24 @@ type AzCli interface {
 		parameters azure.ArmParameters,
 		tags map[string]*string,
 	) (*armresources.DeploymentExtended, error)
+	// WhatIfDeployToSubscription previews the changes of the deployment of the template, without deploying it
+	WhatIfDeployToSubscription(
+		ctx context.Context,
+		subscriptionId string,
+		location string,
+		deploymentName string,
+		armTemplate azure.RawArmTemplate,
+		parameters azure.ArmParameters,
+	) (*armresources.WhatIfOperationResult, error)
+	// WhatIfDeployToResourceGroup previews the changes of the deployment of the template, without deploying it
+	WhatIfDeployToResourceGroup(
+		ctx context.Context,
+		subscriptionId,
+		resourceGroup,
+		deploymentName string,
+		armTemplate azure.RawArmTemplate,
+		parameters azure.ArmParameters,
+	) (*armresources.WhatIfOperationResult, error)
 	DeleteSubscriptionDeployment(ctx context.Context, subscriptionId string, deploymentName string) error
 	DeleteResourceGroup(ctx context.Context, subscriptionId string, resourceGroupName string) error
 	CreateOrUpdateResourceGroup(
@@ -142,6 +365,12 @@ type AzCli interface {
 		resourceGroupName string,
 		listOptions *ListResourceGroupResourcesOptions,
 	) ([]AzCliResource, error)
+	ListResources(
+		ctx context.Context,
+		subscriptionId string,
+		listOptions *ListResourcesOptions,
+	) ([]AzCliResource, error)
+	DeleteResource(ctx context.Context, subscriptionId string, resourceId string) error
 	ListSubscriptionDeployments(
 		ctx context.Context,
 		subscriptionId string,
@@ -173,6 +402,55 @@ type AzCli interface {
 		resourceGroupName string,
 		applicationName string,
 	) (*AzCliAppServiceProperties, error)
+	// UpdateAppServiceAppSettings sets the app settings of the app service or function app, the other app settings of
+	// the app are kept
+	UpdateAppServiceAppSettings(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		settings map[string]string,
+	) error
+	// BindAppServiceHostName binds the custom domain to the app service or function app, with TLS when the thumbprint
+	// of its certificate is set
+	BindAppServiceHostName(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		hostName string,
+		thumbprint string,
+	) error
+	// CreateAppServiceManagedCertificate creates the free managed certificate of a custom domain bound to an app of the
+	// App Service plan, and returns its thumbprint once it's issued
+	CreateAppServiceManagedCertificate(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		location string,
+		serverFarmId string,
+		hostName string,
+	) (string, error)
+	// GetAppServiceCertificateThumbprint returns the thumbprint of the App Service certificate of the resource id
+	GetAppServiceCertificateThumbprint(ctx context.Context, subscriptionId string, certificateId string) (string, error)
+	// ReadAppServiceLogs writes the lines of the container logs of a Linux app service or function app updated since
+	// the time, with the instance writing them
+	ReadAppServiceLogs(
+		ctx context.Context,
+		subscriptionId string,
+		appName string,
+		since time.Time,
+		write func(instance string, line string) error,
+	) error
+	// StreamAppServiceLogs writes the lines of the log stream of an app service or function app until the context is
+	// canceled. The path selects the logs streamed, ex. application/functions/function, all the logs when empty.
+	StreamAppServiceLogs(
+		ctx context.Context,
+		subscriptionId string,
+		appName string,
+		path string,
+		write func(line string) error,
+	) error
 	GetStaticWebAppProperties(
 		ctx context.Context,
 		subscriptionID string,
@@ -313,6 +591,11 @@ type ListResourceGroupResourcesOptions struct {
 	Filter *string
 }
 
+// Optional parameters for subscription resources listing.
+type ListResourcesOptions struct {
+	TagFilter *Filter
+}
+
 type Filter struct {
 	Key   string
 	Value string
@@ -360,9 +643,10 @@ func (cli *azCli) UserAgent() string {
 
 func (cli *azCli) createDefaultClientOptionsBuilder(ctx context.Context) *azsdk.ClientOptionsBuilder {
 	return azsdk.NewClientOptionsBuilder().
-		WithTransport(httputil.GetHttpClient(ctx)).
+		WithTransport(cli.httpClient).
 		WithPerCallPolicy(azsdk.NewUserAgentPolicy(cli.UserAgent())).
-		WithPerCallPolicy(azsdk.NewMsCorrelationPolicy(ctx))
+		WithPerCallPolicy(azsdk.NewMsCorrelationPolicy(ctx)).
+		WithPerRetryPolicy(azsdk.NewHttpTracePolicy(ctx))
 }
 
 func clientOptionsBuilder(
@@ -372,5 +656,6 @@ func clientOptionsBuilder(
 	return azsdk.NewClientOptionsBuilder().
 		WithTransport(httpClient).
 		WithPerCallPolicy(azsdk.NewUserAgentPolicy(userAgent)).
-		WithPerCallPolicy(azsdk.NewMsCorrelationPolicy(ctx))
+		WithPerCallPolicy(azsdk.NewMsCorrelationPolicy(ctx)).
+		WithPerRetryPolicy(azsdk.NewHttpTracePolicy(ctx))
 }
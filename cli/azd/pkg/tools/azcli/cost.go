@@ -0,0 +1,103 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) QueryCost(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	query azsdk.CostQuery,
+) (*azsdk.CostQueryResult, error) {
+	client, err := cli.createCostManagementClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.Query(ctx, scope, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying cost: %w", err)
+	}
+
+	return result, nil
+}
+
+func (cli *azCli) ForecastCost(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	query azsdk.CostQuery,
+) (*azsdk.CostQueryResult, error) {
+	client, err := cli.createCostManagementClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.Forecast(ctx, scope, query)
+	if err != nil {
+		return nil, fmt.Errorf("forecasting cost: %w", err)
+	}
+
+	return result, nil
+}
+
+func (cli *azCli) GetBudget(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	name string,
+) (*azsdk.Budget, error) {
+	client, err := cli.createCostManagementClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := client.GetBudget(ctx, scope, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting budget %s: %w", name, err)
+	}
+
+	return budget, nil
+}
+
+func (cli *azCli) CreateOrUpdateBudget(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	name string,
+	budget azsdk.Budget,
+) (*azsdk.Budget, error) {
+	client, err := cli.createCostManagementClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CreateOrUpdateBudget(ctx, scope, name, budget)
+	if err != nil {
+		return nil, fmt.Errorf("saving budget %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (cli *azCli) createCostManagementClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*azsdk.CostManagementClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewCostManagementClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating cost management client: %w", err)
+	}
+
+	return client, nil
+}
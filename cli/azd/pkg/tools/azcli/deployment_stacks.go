@@ -0,0 +1,83 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) GetDeploymentStack(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	stackName string,
+) (*azsdk.DeploymentStack, error) {
+	client, err := cli.createDeploymentStacksClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	stack, err := client.Get(ctx, azsdk.DeploymentStackId(subscriptionId, resourceGroupName, stackName))
+	if err != nil {
+		return nil, fmt.Errorf("getting deployment stack %s: %w", stackName, err)
+	}
+
+	return stack, nil
+}
+
+func (cli *azCli) CreateOrUpdateDeploymentStack(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	stackName string,
+	stack azsdk.DeploymentStack,
+) (*azsdk.DeploymentStack, error) {
+	client, err := cli.createDeploymentStacksClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CreateOrUpdate(ctx, azsdk.DeploymentStackId(subscriptionId, resourceGroupName, stackName), stack)
+	if err != nil {
+		return nil, fmt.Errorf("deploying deployment stack %s: %w", stackName, err)
+	}
+
+	return result, nil
+}
+
+func (cli *azCli) DeleteDeploymentStack(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	stackName string,
+) error {
+	client, err := cli.createDeploymentStacksClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Delete(ctx, azsdk.DeploymentStackId(subscriptionId, resourceGroupName, stackName)); err != nil {
+		return fmt.Errorf("deleting deployment stack %s: %w", stackName, err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) createDeploymentStacksClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*azsdk.DeploymentStacksClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewDeploymentStacksClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating deployment stacks client: %w", err)
+	}
+
+	return client, nil
+}
@@ -208,6 +208,83 @@ func (cli *azCli) DeployToResourceGroup(
 	return &deployResult.DeploymentExtended, nil
 }
 
+// WhatIfDeployToSubscription previews the changes of the deployment of the template to the subscription, without
+// deploying it
+func (cli *azCli) WhatIfDeployToSubscription(
+	ctx context.Context,
+	subscriptionId string,
+	location string,
+	deploymentName string,
+	armTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+) (*armresources.WhatIfOperationResult, error) {
+	deploymentClient, err := cli.createDeploymentsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("creating deployments client: %w", err)
+	}
+
+	whatIfOperation, err := deploymentClient.BeginWhatIfAtSubscriptionScope(
+		ctx, deploymentName,
+		armresources.DeploymentWhatIf{
+			Properties: &armresources.DeploymentWhatIfProperties{
+				Template:   armTemplate,
+				Parameters: parameters,
+				Mode:       to.Ptr(armresources.DeploymentModeIncremental),
+			},
+			Location: to.Ptr(location),
+		}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting what-if of deployment to subscription: %w", err)
+	}
+
+	whatIfResult, err := whatIfOperation.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"previewing deployment to subscription:\n\nDeployment Error Details:\n%w",
+			createDeploymentError(err),
+		)
+	}
+
+	return &whatIfResult.WhatIfOperationResult, nil
+}
+
+// WhatIfDeployToResourceGroup previews the changes of the deployment of the template to the resource group, without
+// deploying it
+func (cli *azCli) WhatIfDeployToResourceGroup(
+	ctx context.Context,
+	subscriptionId, resourceGroup, deploymentName string,
+	armTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+) (*armresources.WhatIfOperationResult, error) {
+	deploymentClient, err := cli.createDeploymentsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("creating deployments client: %w", err)
+	}
+
+	whatIfOperation, err := deploymentClient.BeginWhatIf(
+		ctx, resourceGroup, deploymentName,
+		armresources.DeploymentWhatIf{
+			Properties: &armresources.DeploymentWhatIfProperties{
+				Template:   armTemplate,
+				Parameters: parameters,
+				Mode:       to.Ptr(armresources.DeploymentModeIncremental),
+			},
+		}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting what-if of deployment to resource group: %w", err)
+	}
+
+	whatIfResult, err := whatIfOperation.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"previewing deployment to resource group:\n\nDeployment Error Details:\n%w",
+			createDeploymentError(err),
+		)
+	}
+
+	return &whatIfResult.WhatIfOperationResult, nil
+}
+
 func (cli *azCli) DeleteSubscriptionDeployment(ctx context.Context, subscriptionId string, deploymentName string) error {
 	deploymentClient, err := cli.createDeploymentsClient(ctx, subscriptionId)
 	if err != nil {
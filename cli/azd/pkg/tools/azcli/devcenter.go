@@ -0,0 +1,222 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) GetDevCenterEnvironment(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	environmentName string,
+) (*azsdk.DevCenterEnvironment, error) {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	environment, err := client.GetEnvironment(ctx, projectName, environmentName)
+	if err != nil {
+		if errors.Is(err, azsdk.ErrDevCenterEnvironmentNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("getting environment: %w", err)
+	}
+
+	return environment, nil
+}
+
+func (cli *azCli) CreateOrUpdateDevCenterEnvironment(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	environmentName string,
+	environment azsdk.DevCenterEnvironment,
+) (*azsdk.DevCenterEnvironment, error) {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CreateOrUpdateEnvironment(ctx, projectName, environmentName, environment)
+	if err != nil {
+		return nil, fmt.Errorf("deploying environment: %w", err)
+	}
+
+	return result, nil
+}
+
+func (cli *azCli) DeleteDevCenterEnvironment(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	environmentName string,
+) error {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteEnvironment(ctx, projectName, environmentName); err != nil {
+		if errors.Is(err, azsdk.ErrDevCenterEnvironmentNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("deleting environment: %w", err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) GetDevCenterEnvironmentDefinition(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	catalogName string,
+	definitionName string,
+) (*azsdk.DevCenterEnvironmentDefinition, error) {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, err := client.GetEnvironmentDefinition(ctx, projectName, catalogName, definitionName)
+	if err != nil {
+		return nil, fmt.Errorf("getting environment definition: %w", err)
+	}
+
+	return definition, nil
+}
+
+func (cli *azCli) GetDevBox(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	devBoxName string,
+) (*azsdk.DevBox, error) {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	devBox, err := client.GetDevBox(ctx, projectName, devBoxName)
+	if err != nil {
+		if errors.Is(err, azsdk.ErrDevBoxNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("getting dev box: %w", err)
+	}
+
+	return devBox, nil
+}
+
+func (cli *azCli) CreateDevBox(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	devBoxName string,
+	poolName string,
+) (*azsdk.DevBox, error) {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	devBox, err := client.CreateDevBox(ctx, projectName, devBoxName, poolName)
+	if err != nil {
+		return nil, fmt.Errorf("creating dev box: %w", err)
+	}
+
+	return devBox, nil
+}
+
+func (cli *azCli) StartDevBox(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	devBoxName string,
+) error {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if err := client.StartDevBox(ctx, projectName, devBoxName); err != nil {
+		return fmt.Errorf("starting dev box: %w", err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) GetDevBoxRemoteConnection(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	devBoxName string,
+) (*azsdk.DevBoxRemoteConnection, error) {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := client.GetDevBoxRemoteConnection(ctx, projectName, devBoxName)
+	if err != nil {
+		return nil, fmt.Errorf("getting dev box remote connection: %w", err)
+	}
+
+	return connection, nil
+}
+
+func (cli *azCli) CreateDevBoxCustomizationGroup(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	projectName string,
+	devBoxName string,
+	group azsdk.DevBoxCustomizationGroup,
+) (*azsdk.DevBoxCustomizationGroup, error) {
+	client, err := cli.createDevCenterClient(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CreateDevBoxCustomizationGroup(ctx, projectName, devBoxName, group)
+	if err != nil {
+		return nil, fmt.Errorf("customizing dev box: %w", err)
+	}
+
+	return result, nil
+}
+
+func (cli *azCli) createDevCenterClient(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+) (*azsdk.DevCenterClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildCoreClientOptions()
+	client, err := azsdk.NewDevCenterClient(endpoint, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating dev center client: %w", err)
+	}
+
+	return client, nil
+}
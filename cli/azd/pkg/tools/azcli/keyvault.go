@@ -65,12 +65,7 @@ func (cli *azCli) GetKeyVaultSecret(
 	vaultName string,
 	secretName string,
 ) (*AzCliKeyVaultSecret, error) {
-	vaultUrl := vaultName
-	if !strings.Contains(strings.ToLower(vaultName), "https://") {
-		vaultUrl = fmt.Sprintf("https://%s.vault.azure.net", vaultName)
-	}
-
-	client, err := cli.createSecretsDataClient(ctx, subscriptionId, vaultUrl)
+	client, err := cli.createSecretsDataClient(ctx, subscriptionId, keyVaultUrl(vaultName))
 	if err != nil {
 		return nil, nil
 	}
@@ -91,6 +86,26 @@ func (cli *azCli) GetKeyVaultSecret(
 	}, nil
 }
 
+func (cli *azCli) SetKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	value string,
+) error {
+	client, err := cli.createSecretsDataClient(ctx, subscriptionId, keyVaultUrl(vaultName))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.SetSecret(ctx, secretName, azsecrets.SetSecretParameters{Value: &value}, nil)
+	if err != nil {
+		return fmt.Errorf("setting key vault secret: %w", err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) PurgeKeyVault(ctx context.Context, subscriptionId string, vaultName string, location string) error {
 	client, err := cli.createKeyVaultClient(ctx, subscriptionId)
 	if err != nil {
@@ -146,3 +161,12 @@ func (cli *azCli) createSecretsDataClient(
 
 	return azsecrets.NewClient(vaultUrl, credential, options)
 }
+
+// keyVaultUrl returns the url of the vault, vaults are referenced by name or by url
+func keyVaultUrl(vaultName string) string {
+	if strings.Contains(strings.ToLower(vaultName), "https://") {
+		return vaultName
+	}
+
+	return fmt.Sprintf("https://%s.vault.azure.net", vaultName)
+}
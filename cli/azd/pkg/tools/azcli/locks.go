@@ -0,0 +1,54 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) ListResourceGroupLocks(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) ([]azsdk.ManagementLock, error) {
+	client, err := cli.createLocksClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	locks, err := client.ListAtResourceGroup(ctx, subscriptionId, resourceGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("listing locks of resource group %s: %w", resourceGroupName, err)
+	}
+
+	return locks, nil
+}
+
+func (cli *azCli) DeleteManagementLock(ctx context.Context, subscriptionId string, lockId string) error {
+	client, err := cli.createLocksClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Delete(ctx, lockId); err != nil {
+		return fmt.Errorf("deleting lock %s: %w", lockId, err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) createLocksClient(ctx context.Context, subscriptionId string) (*azsdk.LocksClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewLocksClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating locks client: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,31 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) QueryLogAnalytics(
+	ctx context.Context,
+	subscriptionId string,
+	workspaceId string,
+	query string,
+	timespan string,
+) (*azsdk.LogAnalyticsQueryResult, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildCoreClientOptions()
+	client := azsdk.NewLogAnalyticsClient(credential, options)
+
+	result, err := client.Query(ctx, workspaceId, query, timespan)
+	if err != nil {
+		return nil, fmt.Errorf("querying log analytics workspace: %w", err)
+	}
+
+	return result, nil
+}
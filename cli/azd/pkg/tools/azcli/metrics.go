@@ -0,0 +1,33 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) GetMetrics(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+	query azsdk.MetricsQuery,
+) (*azsdk.MetricsResult, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewMetricsClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating metrics client: %w", err)
+	}
+
+	result, err := client.List(ctx, resourceId, query)
+	if err != nil {
+		return nil, fmt.Errorf("getting metrics: %w", err)
+	}
+
+	return result, nil
+}
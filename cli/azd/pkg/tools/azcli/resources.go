@@ -3,8 +3,11 @@ package azcli
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 )
 
 func (cli *azCli) GetResource(
@@ -30,6 +33,36 @@ func (cli *azCli) GetResource(
 	}, nil
 }
 
+func (cli *azCli) CreateOrUpdateResource(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+	apiVersion string,
+	resource armresources.GenericResource,
+) (AzCliResource, error) {
+	client, err := cli.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return AzCliResource{}, err
+	}
+
+	poller, err := client.BeginCreateOrUpdateByID(ctx, resourceId, apiVersion, resource, nil)
+	if err != nil {
+		return AzCliResource{}, fmt.Errorf("beginning resource create or update: %w", err)
+	}
+
+	res, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return AzCliResource{}, fmt.Errorf("creating or updating resource: %w", err)
+	}
+
+	return AzCliResource{
+		Id:       convert.ToValueWithDefault(res.ID, resourceId),
+		Name:     convert.ToValueWithDefault(res.Name, ""),
+		Type:     convert.ToValueWithDefault(res.Type, ""),
+		Location: convert.ToValueWithDefault(res.Location, ""),
+	}, nil
+}
+
 func (cli *azCli) ListResourceGroupResources(
 	ctx context.Context,
 	subscriptionId string,
@@ -69,6 +102,128 @@ func (cli *azCli) ListResourceGroupResources(
 	return resources, nil
 }
 
+// ListResources lists the resources of the subscription, optionally filtered by tag
+func (cli *azCli) ListResources(
+	ctx context.Context,
+	subscriptionId string,
+	listOptions *ListResourcesOptions,
+) ([]AzCliResource, error) {
+	client, err := cli.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// https://learn.microsoft.com/en-us/rest/api/resources/resources/list#uri-parameters
+	options := armresources.ClientListOptions{}
+	if listOptions != nil && listOptions.TagFilter != nil {
+		tagFilter := fmt.Sprintf(
+			"tagName eq '%s' and tagValue eq '%s'",
+			listOptions.TagFilter.Key,
+			listOptions.TagFilter.Value,
+		)
+		options.Filter = &tagFilter
+	}
+
+	resources := []AzCliResource{}
+	pager := client.NewListPager(&options)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resource := range page.ResourceListResult.Value {
+			resources = append(resources, AzCliResource{
+				Id:       *resource.ID,
+				Name:     *resource.Name,
+				Type:     *resource.Type,
+				Location: convert.ToValueWithDefault(resource.Location, ""),
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// DeleteResource deletes the resource with the latest stable API version of its resource type
+func (cli *azCli) DeleteResource(ctx context.Context, subscriptionId string, resourceId string) error {
+	parsed, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return fmt.Errorf("parsing resource id: %w", err)
+	}
+
+	apiVersion, err := cli.resourceTypeApiVersion(ctx, subscriptionId, parsed.ResourceType)
+	if err != nil {
+		return err
+	}
+
+	client, err := cli.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginDeleteByID(ctx, resourceId, apiVersion, nil)
+	if err != nil {
+		return fmt.Errorf("beginning resource deletion: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("deleting resource: %w", err)
+	}
+
+	return nil
+}
+
+// resourceTypeApiVersion returns the latest stable API version of the resource type, or the latest preview API version
+// when the resource type doesn't have a stable API version
+func (cli *azCli) resourceTypeApiVersion(
+	ctx context.Context, subscriptionId string, resourceType arm.ResourceType) (string, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := armresources.NewProvidersClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", fmt.Errorf("creating Providers client: %w", err)
+	}
+
+	provider, err := client.Get(ctx, resourceType.Namespace, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting resource provider %s: %w", resourceType.Namespace, err)
+	}
+
+	typeName := strings.Join(resourceType.Types, "/")
+	for _, providerType := range provider.ResourceTypes {
+		if providerType.ResourceType == nil || !strings.EqualFold(*providerType.ResourceType, typeName) {
+			continue
+		}
+
+		// The API versions are sorted from the latest
+		latest := ""
+		for _, version := range providerType.APIVersions {
+			if version == nil {
+				continue
+			}
+
+			if !strings.Contains(*version, "preview") {
+				return *version, nil
+			}
+
+			if latest == "" {
+				latest = *version
+			}
+		}
+
+		if latest != "" {
+			return latest, nil
+		}
+	}
+
+	return "", fmt.Errorf("no API version found for resource type %s", resourceType.String())
+}
+
 func (cli *azCli) ListResourceGroup(
 	ctx context.Context,
 	subscriptionId string,
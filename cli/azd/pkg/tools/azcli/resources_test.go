@@ -0,0 +1,63 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateOrUpdateResource(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var body armresources.GenericResource
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && request.URL.Path == "/RESOURCE_ID"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		contents, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(contents, &body))
+		require.Equal(t, "API_VERSION", request.URL.Query().Get("api-version"))
+
+		response := armresources.ClientCreateOrUpdateByIDResponse{
+			GenericResource: armresources.GenericResource{
+				ID:       convert.RefOf("RESOURCE_ID"),
+				Name:     convert.RefOf("RESOURCE_NAME"),
+				Type:     convert.RefOf("RESOURCE_TYPE"),
+				Location: convert.RefOf("RESOURCE_LOCATION"),
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	azCli := newAzCliFromMockContext(mockContext)
+	resource, err := azCli.CreateOrUpdateResource(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_ID",
+		"API_VERSION",
+		armresources.GenericResource{
+			Location:   convert.RefOf("RESOURCE_LOCATION"),
+			Properties: map[string]any{"displayName": "DISPLAY_NAME"},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, AzCliResource{
+		Id:       "RESOURCE_ID",
+		Name:     "RESOURCE_NAME",
+		Type:     "RESOURCE_TYPE",
+		Location: "RESOURCE_LOCATION",
+	}, resource)
+	require.Equal(t, "RESOURCE_LOCATION", *body.Location)
+	require.Equal(t, map[string]any{"displayName": "DISPLAY_NAME"}, body.Properties)
+}
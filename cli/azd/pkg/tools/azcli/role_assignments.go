@@ -0,0 +1,169 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/sethvargo/go-retry"
+)
+
+// AzCliRoleAssignment is the assignment of a role to a principal on a scope
+type AzCliRoleAssignment struct {
+	Id               string `json:"id"`
+	Scope            string `json:"scope"`
+	PrincipalId      string `json:"principalId"`
+	RoleDefinitionId string `json:"roleDefinitionId"`
+	// The name of the role, ex. Contributor, empty when the role definition can't be read
+	RoleName string `json:"roleName,omitempty"`
+}
+
+func (cli *azCli) ListResourceGroupRoleAssignments(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) ([]AzCliRoleAssignment, error) {
+	client, err := cli.createRoleAssignmentsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The role assignments inherited from the subscription or management groups are listed with the assignments of
+	// the resource group and its resources, only the latter are deleted with the resource group
+	resourceGroupId := strings.ToLower(azure.ResourceGroupRID(subscriptionId, resourceGroupName))
+	roleNames := map[string]string{}
+
+	assignments := []AzCliRoleAssignment{}
+	pager := client.NewListForResourceGroupPager(resourceGroupName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing role assignments of resource group %s: %w", resourceGroupName, err)
+		}
+
+		for _, assignment := range page.Value {
+			if assignment == nil || assignment.ID == nil || assignment.Properties == nil ||
+				assignment.Properties.Scope == nil {
+				continue
+			}
+
+			scope := strings.ToLower(*assignment.Properties.Scope)
+			if scope != resourceGroupId && !strings.HasPrefix(scope, resourceGroupId+"/") {
+				continue
+			}
+
+			roleDefinitionId := convert.ToValueWithDefault(assignment.Properties.RoleDefinitionID, "")
+			roleName, has := roleNames[roleDefinitionId]
+			if !has {
+				roleName = cli.getRoleName(ctx, subscriptionId, roleDefinitionId)
+				roleNames[roleDefinitionId] = roleName
+			}
+
+			assignments = append(assignments, AzCliRoleAssignment{
+				Id:               *assignment.ID,
+				Scope:            *assignment.Properties.Scope,
+				PrincipalId:      convert.ToValueWithDefault(assignment.Properties.PrincipalID, ""),
+				RoleDefinitionId: roleDefinitionId,
+				RoleName:         roleName,
+			})
+		}
+	}
+
+	return assignments, nil
+}
+
+// getRoleName returns the name of the role definition, the role assignments are listed without it
+func (cli *azCli) getRoleName(ctx context.Context, subscriptionId string, roleDefinitionId string) string {
+	client, err := cli.createRoleDefinitionsClient(ctx, subscriptionId)
+	if err != nil {
+		log.Printf("failed creating role definitions client: %v", err)
+		return ""
+	}
+
+	response, err := client.GetByID(ctx, roleDefinitionId, nil)
+	if err != nil || response.Properties == nil {
+		log.Printf("failed getting role definition %s: %v", roleDefinitionId, err)
+		return ""
+	}
+
+	return convert.ToValueWithDefault(response.Properties.RoleName, "")
+}
+
+func (cli *azCli) GetRoleDefinitionId(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	roleName string,
+) (string, error) {
+	roleDefinition, err := cli.getRoleDefinition(ctx, subscriptionId, scope, roleName)
+	if err != nil {
+		return "", err
+	}
+
+	return convert.ToValueWithDefault(roleDefinition.ID, ""), nil
+}
+
+func (cli *azCli) CreateRoleAssignment(
+	ctx context.Context,
+	subscriptionId string,
+	name string,
+	assignment AzCliRoleAssignment,
+) error {
+	client, err := cli.createRoleAssignmentsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	// Managed identities just created aren't available in Azure AD yet, the role assignment fails until they are
+	return retry.Do(ctx, retry.WithMaxRetries(10, retry.NewConstant(time.Second*5)), func(ctx context.Context) error {
+		_, err := client.Create(ctx, assignment.Scope, name, armauthorization.RoleAssignmentCreateParameters{
+			Properties: &armauthorization.RoleAssignmentProperties{
+				PrincipalID:      &assignment.PrincipalId,
+				RoleDefinitionID: &assignment.RoleDefinitionId,
+			},
+		}, nil)
+		if err == nil {
+			return nil
+		}
+
+		// If the response is a 409 conflict then the role has already been assigned.
+		var responseError *azcore.ResponseError
+		if errors.As(err, &responseError) && responseError.StatusCode == http.StatusConflict {
+			return nil
+		}
+
+		err = fmt.Errorf("assigning role to principal %s at scope %s: %w", assignment.PrincipalId, assignment.Scope, err)
+		if responseError != nil && responseError.ErrorCode == "PrincipalNotFound" {
+			return retry.RetryableError(err)
+		}
+
+		return err
+	})
+}
+
+func (cli *azCli) DeleteRoleAssignment(ctx context.Context, subscriptionId string, scope string, name string) error {
+	client, err := cli.createRoleAssignmentsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Delete(ctx, scope, name, nil)
+	if err != nil {
+		var responseError *azcore.ResponseError
+		if errors.As(err, &responseError) && responseError.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("deleting role assignment %s at scope %s: %w", name, scope, err)
+	}
+
+	return nil
+}
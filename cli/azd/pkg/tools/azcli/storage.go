@@ -0,0 +1,65 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) CreateOrUpdateStorageAccount(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	location string,
+	tags map[string]*string,
+) error {
+	client, err := cli.createStorageAccountsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if err := client.CreateOrUpdate(ctx, subscriptionId, resourceGroupName, accountName, location, tags); err != nil {
+		return fmt.Errorf("creating storage account %s: %w", accountName, err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) CreateOrUpdateBlobContainer(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	containerName string,
+) error {
+	client, err := cli.createStorageAccountsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if err := client.CreateBlobContainer(ctx, subscriptionId, resourceGroupName, accountName, containerName); err != nil {
+		return fmt.Errorf("creating blob container %s: %w", containerName, err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) createStorageAccountsClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*azsdk.StorageAccountsClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewStorageAccountsClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating storage accounts client: %w", err)
+	}
+
+	return client, nil
+}
@@ -2,9 +2,15 @@ package azcli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
@@ -12,6 +18,18 @@ import (
 
 type AzCliAppServiceProperties struct {
 	HostNames []string
+	// The running state of the app (ex. Running, Stopped)
+	State string
+	// The availability of the app (Normal, Limited or DisasterRecoveryMode)
+	AvailabilityState string
+	// The runtime stack of Linux apps, ex. NODE|18-lts, empty for Windows apps
+	LinuxFxVersion string
+	// The location of the app, ex. eastus2
+	Location string
+	// The resource id of the App Service plan of the app
+	ServerFarmId string
+	// The value of the asuid TXT record verifying the ownership of the custom domains of the app
+	CustomDomainVerificationId string
 }
 
 func (cli *azCli) GetAppServiceProperties(
@@ -30,9 +48,21 @@ func (cli *azCli) GetAppServiceProperties(
 		return nil, fmt.Errorf("failed retrieving webapp properties: %w", err)
 	}
 
-	return &AzCliAppServiceProperties{
-		HostNames: []string{*webApp.Properties.DefaultHostName},
-	}, nil
+	properties := &AzCliAppServiceProperties{
+		HostNames:         []string{*webApp.Properties.DefaultHostName},
+		State:             convert.ToValueWithDefault(webApp.Properties.State, ""),
+		AvailabilityState: string(convert.ToValueWithDefault(webApp.Properties.AvailabilityState, "")),
+		Location:          convert.ToValueWithDefault(webApp.Location, ""),
+		ServerFarmId:      convert.ToValueWithDefault(webApp.Properties.ServerFarmID, ""),
+		CustomDomainVerificationId: convert.ToValueWithDefault(
+			webApp.Properties.CustomDomainVerificationID, ""),
+	}
+
+	if webApp.Properties.SiteConfig != nil {
+		properties.LinuxFxVersion = convert.ToValueWithDefault(webApp.Properties.SiteConfig.LinuxFxVersion, "")
+	}
+
+	return properties, nil
 }
 
 func (cli *azCli) DeployAppServiceZip(
@@ -55,6 +85,174 @@ func (cli *azCli) DeployAppServiceZip(
 	return convert.RefOf(response.StatusText), nil
 }
 
+func (cli *azCli) UpdateAppServiceAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	settings map[string]string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	// The update replaces all the app settings of the app, its current settings are merged
+	current, err := client.ListApplicationSettings(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return fmt.Errorf("listing app settings: %w", err)
+	}
+
+	properties := map[string]*string{}
+	for name, value := range current.Properties {
+		properties[name] = value
+	}
+
+	for name, value := range settings {
+		properties[name] = convert.RefOf(value)
+	}
+
+	_, err = client.UpdateApplicationSettings(ctx, resourceGroup, appName, armappservice.StringDictionary{
+		Properties: properties,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("updating app settings: %w", err)
+	}
+
+	return nil
+}
+
+// The delay between the reads of a managed certificate of App Service being issued
+const appServiceCertificatePollInterval = 10 * time.Second
+
+func (cli *azCli) BindAppServiceHostName(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	hostName string,
+	thumbprint string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	properties := &armappservice.HostNameBindingProperties{
+		SiteName:                    convert.RefOf(appName),
+		HostNameType:                convert.RefOf(armappservice.HostNameTypeVerified),
+		CustomHostNameDNSRecordType: convert.RefOf(armappservice.CustomHostNameDNSRecordTypeCName),
+		SSLState:                    convert.RefOf(armappservice.SSLStateDisabled),
+	}
+	if thumbprint != "" {
+		properties.SSLState = convert.RefOf(armappservice.SSLStateSniEnabled)
+		properties.Thumbprint = convert.RefOf(thumbprint)
+	}
+
+	_, err = client.CreateOrUpdateHostNameBinding(
+		ctx, resourceGroup, appName, hostName, armappservice.HostNameBinding{Properties: properties}, nil)
+	if err != nil {
+		return fmt.Errorf("binding host name %s: %w", hostName, err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) CreateAppServiceManagedCertificate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	location string,
+	serverFarmId string,
+	hostName string,
+) (string, error) {
+	client, err := cli.createCertificatesClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	// The name of the certificate is derived from the host name, so it's only created once
+	name := strings.ReplaceAll(hostName, ".", "-")
+	certificate := armappservice.AppCertificate{
+		Location: convert.RefOf(location),
+		Properties: &armappservice.AppCertificateProperties{
+			CanonicalName: convert.RefOf(hostName),
+			ServerFarmID:  convert.RefOf(serverFarmId),
+		},
+	}
+
+	// Managed certificates are issued asynchronously, the creation is accepted before the certificate is issued
+	response, err := client.CreateOrUpdate(ctx, resourceGroup, name, certificate, nil)
+	var responseErr *azcore.ResponseError
+	if err != nil && !(errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusAccepted) {
+		return "", fmt.Errorf("creating managed certificate for %s: %w", hostName, err)
+	}
+
+	for {
+		if err == nil && response.Properties != nil && response.Properties.Thumbprint != nil {
+			return *response.Properties.Thumbprint, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("waiting for the managed certificate of %s: %w", hostName, ctx.Err())
+		case <-time.After(appServiceCertificatePollInterval):
+		}
+
+		getResponse, getErr := client.Get(ctx, resourceGroup, name, nil)
+		if getErr != nil && !(errors.As(getErr, &responseErr) && responseErr.StatusCode == http.StatusNotFound) {
+			return "", fmt.Errorf("getting managed certificate for %s: %w", hostName, getErr)
+		}
+
+		response.AppCertificate, err = getResponse.AppCertificate, getErr
+	}
+}
+
+func (cli *azCli) GetAppServiceCertificateThumbprint(
+	ctx context.Context,
+	subscriptionId string,
+	certificateId string,
+) (string, error) {
+	resourceId, err := arm.ParseResourceID(certificateId)
+	if err != nil {
+		return "", fmt.Errorf("parsing certificate id: %w", err)
+	}
+
+	client, err := cli.createCertificatesClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := client.Get(ctx, resourceId.ResourceGroupName, resourceId.Name, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting certificate %s: %w", resourceId.Name, err)
+	}
+
+	if response.Properties == nil || response.Properties.Thumbprint == nil {
+		return "", fmt.Errorf("certificate %s has no thumbprint", resourceId.Name)
+	}
+
+	return *response.Properties.Thumbprint, nil
+}
+
+func (cli *azCli) createCertificatesClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armappservice.CertificatesClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := armappservice.NewCertificatesClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Certificates client: %w", err)
+	}
+
+	return client, nil
+}
+
 func (cli *azCli) createWebAppsClient(ctx context.Context, subscriptionId string) (*armappservice.WebAppsClient, error) {
 	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
@@ -0,0 +1,79 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+// ReadAppServiceLogs writes the lines of the container logs of a Linux app service or function app updated since the
+// time, the logs of each instance and container in turn
+func (cli *azCli) ReadAppServiceLogs(
+	ctx context.Context,
+	subscriptionId string,
+	appName string,
+	since time.Time,
+	write func(instance string, line string) error,
+) error {
+	client, err := cli.createKuduLogsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	logs, err := client.ListDockerLogs(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("listing logs of '%s': %w", appName, err)
+	}
+
+	for _, dockerLog := range logs {
+		if dockerLog.LastUpdated.Before(since) {
+			continue
+		}
+
+		if err := client.ReadLog(ctx, dockerLog.Href, func(line string) error {
+			return write(dockerLog.MachineName, line)
+		}); err != nil {
+			return fmt.Errorf("reading logs of '%s': %w", appName, err)
+		}
+	}
+
+	return nil
+}
+
+// StreamAppServiceLogs writes the lines of the log stream of an app service or function app until the context is
+// canceled, the path selects the logs streamed when set
+func (cli *azCli) StreamAppServiceLogs(
+	ctx context.Context,
+	subscriptionId string,
+	appName string,
+	path string,
+	write func(line string) error,
+) error {
+	client, err := cli.createKuduLogsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if err := client.StreamLogs(ctx, appName, path, write); err != nil {
+		return fmt.Errorf("streaming logs of '%s': %w", appName, err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) createKuduLogsClient(ctx context.Context, subscriptionId string) (*azsdk.KuduLogsClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewKuduLogsClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Kudu logs client: %w", err)
+	}
+
+	return client, nil
+}
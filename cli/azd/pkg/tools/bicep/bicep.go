@@ -5,16 +5,24 @@ package bicep
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -56,8 +64,9 @@ func newBicepCliWithTransporter(
 		log.Printf("using external bicep tool: %s", override)
 
 		return &bicepCli{
-			path:   override,
-			runner: commandRunner,
+			path:     override,
+			runner:   commandRunner,
+			cacheDir: buildCacheDir(),
 		}, nil
 	}
 
@@ -84,8 +93,9 @@ func newBicepCliWithTransporter(
 	}
 
 	cli := &bicepCli{
-		path:   bicepPath,
-		runner: commandRunner,
+		path:     bicepPath,
+		runner:   commandRunner,
+		cacheDir: buildCacheDir(),
 	}
 
 	ver, err := cli.version(ctx)
@@ -107,6 +117,14 @@ func newBicepCliWithTransporter(
 		}
 	}
 
+	// The version is part of the build cache key and doesn't need to be checked again
+	cli.versionOnce.Do(func() {
+		cli.versionInfo = ver
+		if ver.LT(cBicepVersion) {
+			cli.versionInfo = cBicepVersion
+		}
+	})
+
 	log.Printf("using local bicep: %s", bicepPath)
 
 	return cli, nil
@@ -129,6 +147,12 @@ func runStep(ctx context.Context, console input.Console, title string, action fu
 type bicepCli struct {
 	path   string
 	runner exec.CommandRunner
+	// The directory compiled templates are cached in, caching is disabled when empty
+	cacheDir string
+
+	versionOnce sync.Once
+	versionInfo semver.Version
+	versionErr  error
 }
 
 // azdBicepPath returns the path where we store our local copy of bicep ($AZD_CONFIG_DIR/bin).
@@ -242,7 +266,42 @@ func (cli *bicepCli) version(ctx context.Context) (semver.Version, error) {
 
 }
 
+// Build compiles the bicep file to an ARM template. Compiled templates are cached until the content of any file
+// within the directory of the bicep file, the version of bicep or the version of azd changes.
+// Templates referencing files outside of the directory of the bicep file are always compiled.
 func (cli *bicepCli) Build(ctx context.Context, file string) (string, error) {
+	if cli.cacheDir == "" {
+		return cli.build(ctx, file)
+	}
+
+	key, err := cli.buildCacheKey(ctx, file)
+	if err != nil {
+		log.Printf("skipping bicep build cache for '%s': %v", file, err)
+		return cli.build(ctx, file)
+	}
+
+	entryPath := filepath.Join(cli.cacheDir, buildCacheEntryName(file))
+	if contents, err := os.ReadFile(entryPath); err == nil {
+		var entry buildCacheEntry
+		if err := json.Unmarshal(contents, &entry); err == nil && entry.Key == key {
+			log.Printf("using cached compiled template for '%s'", file)
+			return entry.Template, nil
+		}
+	}
+
+	compiled, err := cli.build(ctx, file)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeBuildCacheEntry(entryPath, buildCacheEntry{Key: key, Template: compiled}); err != nil {
+		log.Printf("failed caching compiled template for '%s': %v", file, err)
+	}
+
+	return compiled, nil
+}
+
+func (cli *bicepCli) build(ctx context.Context, file string) (string, error) {
 	args := []string{"build", file, "--stdout"}
 	buildRes, err := cli.runCommand(ctx, args...)
 
@@ -256,6 +315,123 @@ func (cli *bicepCli) Build(ctx context.Context, file string) (string, error) {
 	return buildRes.Stdout, nil
 }
 
+type buildCacheEntry struct {
+	Key      string `json:"key"`
+	Template string `json:"template"`
+}
+
+// Gets the directory compiled templates are cached in. ($AZD_CONFIG_DIR/cache/bicep)
+func buildCacheDir() string {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		log.Printf("failed resolving bicep build cache directory: %v", err)
+		return ""
+	}
+
+	return filepath.Join(configDir, "cache", "bicep")
+}
+
+// A single entry is stored for each bicep file, replaced whenever the file is compiled again
+func buildCacheEntryName(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+
+	hash := sha256.Sum256([]byte(file))
+	return hex.EncodeToString(hash[:]) + ".json"
+}
+
+// Matches the local & registry paths of the modules, imports & files loaded by a bicep file
+var bicepReferenceRegex = regexp.MustCompile(
+	`(?:\bmodule\s+\w+\s+|\bimport\s+|\bfrom\s+|\busing\s+|\bload\w+\(\s*)'([^']+)'`,
+)
+
+// Modules, parameters & files loaded by the template are referenced relative to the bicep file and all the files
+// within its directory are part of the key. Files referenced outside of the directory would be missing from the key,
+// in which case the template isn't cached.
+func (cli *bicepCli) buildCacheKey(ctx context.Context, file string) (string, error) {
+	cli.versionOnce.Do(func() {
+		cli.versionInfo, cli.versionErr = cli.version(ctx)
+	})
+	if cli.versionErr != nil {
+		return "", cli.versionErr
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", internal.Version, cli.versionInfo.String(), filepath.Base(file))
+
+	root := filepath.Dir(file)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if filepath.Ext(path) == ".bicep" || filepath.Ext(path) == ".bicepparam" {
+			if err := ensureReferencesWithin(root, path, contents); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(hash, "%s\x00", filepath.ToSlash(relative))
+		hash.Write(contents)
+		hash.Write([]byte{0})
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Returns an error when the bicep file references a local file outside of the root directory
+func ensureReferencesWithin(root string, path string, contents []byte) error {
+	for _, match := range bicepReferenceRegex.FindAllSubmatch(contents, -1) {
+		reference := string(match[1])
+		// Modules from registries & template specs are versioned by their reference
+		if strings.HasPrefix(reference, "br:") || strings.HasPrefix(reference, "br/") ||
+			strings.HasPrefix(reference, "ts:") || strings.HasPrefix(reference, "ts/") {
+			continue
+		}
+
+		target := filepath.FromSlash(reference)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+
+		relative, err := filepath.Rel(root, target)
+		if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("'%s' references '%s' outside of '%s'", path, reference, root)
+		}
+	}
+
+	return nil
+}
+
+func writeBuildCacheEntry(entryPath string, entry buildCacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(entryPath), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(entryPath, contents, osutil.PermissionFile)
+}
+
 func (cli *bicepCli) runCommand(ctx context.Context, args ...string) (exec.RunResult, error) {
 	runArgs := exec.NewRunArgs(cli.path, args...)
 	return cli.runner.Run(ctx, runArgs)
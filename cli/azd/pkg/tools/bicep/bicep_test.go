@@ -137,3 +137,72 @@ func TestNewBicepCliWillUpgrade(t *testing.T) {
 
 	require.Equal(t, []byte(NEW_FILE_CONTENTS), contents)
 }
+
+func TestBuildCache(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+	t.Setenv("AZD_BICEP_TOOL_PATH", "bicep")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(args.Cmd, "bicep") && len(args.Args) == 1 && args.Args[0] == "--version"
+	}).Respond(exec.NewRunResult(
+		0,
+		fmt.Sprintf("Bicep CLI version %s (abcdef0123)", cBicepVersion.String()),
+		"",
+	))
+
+	builds := 0
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(args.Cmd, "bicep") && args.Args[0] == "build"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		builds++
+		return exec.NewRunResult(0, fmt.Sprintf(`{"build": %d}`, builds), ""), nil
+	})
+
+	projectDir := t.TempDir()
+	infraDir := filepath.Join(projectDir, "infra")
+	mainPath := filepath.Join(infraDir, "main.bicep")
+	modulePath := filepath.Join(infraDir, "app", "web.bicep")
+	require.NoError(t, os.MkdirAll(filepath.Dir(modulePath), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(mainPath, []byte("module web 'app/web.bicep' = {}"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(modulePath, []byte("param name string"), osutil.PermissionFile))
+
+	cli, err := NewBicepCli(*mockContext.Context, mockContext.Console, mockContext.CommandRunner)
+	require.NoError(t, err)
+
+	first, err := cli.Build(*mockContext.Context, mainPath)
+	require.NoError(t, err)
+	second, err := cli.Build(*mockContext.Context, mainPath)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Equal(t, 1, builds)
+
+	// Changing a module invalidates the compiled template
+	require.NoError(t, os.WriteFile(modulePath, []byte("param name string = 'web'"), osutil.PermissionFile))
+
+	third, err := cli.Build(*mockContext.Context, mainPath)
+	require.NoError(t, err)
+	require.NotEqual(t, first, third)
+	require.Equal(t, 2, builds)
+
+	// Each bicep file has its own entry
+	module, err := cli.Build(*mockContext.Context, modulePath)
+	require.NoError(t, err)
+	require.NotEqual(t, third, module)
+	require.Equal(t, 3, builds)
+
+	// Templates referencing modules outside of their directory are always compiled
+	sharedPath := filepath.Join(projectDir, "shared.bicep")
+	require.NoError(t, os.WriteFile(sharedPath, []byte("param name string"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(
+		mainPath,
+		[]byte("module web 'app/web.bicep' = {}\nmodule shared '../shared.bicep' = {}"),
+		osutil.PermissionFile,
+	))
+
+	_, err = cli.Build(*mockContext.Context, mainPath)
+	require.NoError(t, err)
+	_, err = cli.Build(*mockContext.Context, mainPath)
+	require.NoError(t, err)
+	require.Equal(t, 5, builds)
+}
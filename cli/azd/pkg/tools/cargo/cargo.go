@@ -0,0 +1,137 @@
+package cargo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+	"golang.org/x/exp/slices"
+)
+
+// The target of the static binaries built for Azure, linked against musl
+const MuslTarget = "x86_64-unknown-linux-musl"
+
+type CargoCli interface {
+	tools.ExternalTool
+	// Fetch downloads the dependencies of the project
+	Fetch(ctx context.Context, projectPath string) error
+	// Build builds the release binaries of the project for the target
+	Build(ctx context.Context, projectPath string, target string) error
+	// Binaries returns the names of the binaries of the packages of the project
+	Binaries(ctx context.Context, projectPath string) ([]string, error)
+}
+
+type cargoCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewCargoCli(commandRunner exec.CommandRunner) CargoCli {
+	return &cargoCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *cargoCli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 1,
+			Minor: 70,
+			Patch: 0},
+		UpdateCommand: "Run rustup update to upgrade",
+	}
+}
+
+func (cli *cargoCli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("cargo"); err != nil {
+		return err
+	}
+
+	res, err := tools.ExecuteCommand(ctx, cli.commandRunner, "cargo", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	cargoSemver, err := tools.ExtractVersion(res)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+
+	updateDetail := cli.versionInfo()
+	if cargoSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+
+	return nil
+}
+
+func (cli *cargoCli) InstallUrl() string {
+	return "https://rustup.rs"
+}
+
+func (cli *cargoCli) Name() string {
+	return "Cargo"
+}
+
+func (cli *cargoCli) Fetch(ctx context.Context, projectPath string) error {
+	runArgs := exec.NewRunArgs("cargo", "fetch").WithCwd(projectPath).WithOutputWindow(exec.DefaultOutputWindow)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("cargo fetch on project '%s' failed: %w", projectPath, err)
+	}
+
+	return nil
+}
+
+func (cli *cargoCli) Build(ctx context.Context, projectPath string, target string) error {
+	runArgs := exec.NewRunArgs("cargo", "build", "--release", "--target", target).
+		WithCwd(projectPath).
+		WithOutputWindow(exec.DefaultOutputWindow)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf(
+			"cargo build on project '%s' failed, the %s target is installed by 'rustup target add %s': %w",
+			projectPath,
+			target,
+			target,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// The subset of the output of cargo metadata describing the targets of the packages
+type cargoMetadata struct {
+	Packages []struct {
+		Name    string `json:"name"`
+		Targets []struct {
+			Name string   `json:"name"`
+			Kind []string `json:"kind"`
+		} `json:"targets"`
+	} `json:"packages"`
+}
+
+func (cli *cargoCli) Binaries(ctx context.Context, projectPath string) ([]string, error) {
+	runArgs := exec.NewRunArgs("cargo", "metadata", "--format-version", "1", "--no-deps").WithCwd(projectPath)
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("cargo metadata on project '%s' failed: %w", projectPath, err)
+	}
+
+	var metadata cargoMetadata
+	if err := json.Unmarshal([]byte(res.Stdout), &metadata); err != nil {
+		return nil, fmt.Errorf("parsing cargo metadata of project '%s': %w", projectPath, err)
+	}
+
+	binaries := []string{}
+	for _, pkg := range metadata.Packages {
+		for _, target := range pkg.Targets {
+			if slices.Contains(target.Kind, "bin") {
+				binaries = append(binaries, target.Name)
+			}
+		}
+	}
+
+	return binaries, nil
+}
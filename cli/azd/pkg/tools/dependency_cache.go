@@ -0,0 +1,40 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package tools
+
+import (
+	"context"
+	"path/filepath"
+)
+
+type dependencyCacheContextKey struct{}
+
+// WithDependencyCache returns a context where the package managers download the dependencies of the services to the
+// caches of the directory, one directory by package manager, ex. <dir>/npm. The dependencies downloaded for a service
+// are reused by the other services, and the directory can be cached between CI runs.
+func WithDependencyCache(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, dependencyCacheContextKey{}, dir)
+}
+
+// DependencyCacheDir returns the directory of the cache of the package manager, ex. <dir>/npm, or false when the
+// package manager uses its default cache
+func DependencyCacheDir(ctx context.Context, packageManager string) (string, bool) {
+	dir, has := ctx.Value(dependencyCacheContextKey{}).(string)
+	if !has || dir == "" {
+		return "", false
+	}
+
+	return filepath.Join(dir, packageManager), true
+}
+
+// DependencyCacheEnv returns the environment variable setting the cache of the package manager to its directory of
+// the dependency cache, ex. npm_config_cache=<dir>/npm, or nil when the package manager uses its default cache
+func DependencyCacheEnv(ctx context.Context, packageManager string, name string) []string {
+	dir, has := DependencyCacheDir(ctx, packageManager)
+	if !has {
+		return nil
+	}
+
+	return []string{name + "=" + dir}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyCache(t *testing.T) {
+	ctx := context.Background()
+
+	_, has := DependencyCacheDir(ctx, "npm")
+	require.False(t, has)
+	require.Nil(t, DependencyCacheEnv(ctx, "npm", "npm_config_cache"))
+
+	dir := t.TempDir()
+	ctx = WithDependencyCache(ctx, dir)
+
+	npmDir, has := DependencyCacheDir(ctx, "npm")
+	require.True(t, has)
+	require.Equal(t, filepath.Join(dir, "npm"), npmDir)
+	require.Equal(t,
+		[]string{"PIP_CACHE_DIR=" + filepath.Join(dir, "pip")}, DependencyCacheEnv(ctx, "pip", "PIP_CACHE_DIR"))
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devtunnel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The prefix of the line written by `devtunnel host` with the public URLs of the tunnel
+const connectViaBrowserPrefix = "Connect via browser:"
+
+type DevTunnelCli interface {
+	tools.ExternalTool
+
+	// IsLoggedIn returns whether the Dev Tunnels CLI is logged in
+	IsLoggedIn(ctx context.Context) (bool, error)
+	// Login logs in the Dev Tunnels CLI interactively
+	Login(ctx context.Context) error
+	// Host hosts a temporary tunnel forwarding to the local port until the context is cancelled.
+	// onReady is invoked with the public URL of the tunnel once the tunnel accepts connections.
+	Host(ctx context.Context, port int, allowAnonymous bool, onReady func(url string)) error
+}
+
+func NewDevTunnelCli(commandRunner exec.CommandRunner) DevTunnelCli {
+	return &devTunnelCli{
+		commandRunner: commandRunner,
+	}
+}
+
+type devTunnelCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func (cli *devTunnelCli) CheckInstalled(_ context.Context) error {
+	return tools.ToolInPath("devtunnel")
+}
+
+func (cli *devTunnelCli) Name() string {
+	return "Dev Tunnels CLI"
+}
+
+func (cli *devTunnelCli) InstallUrl() string {
+	return "https://aka.ms/devtunnels/cli"
+}
+
+func (cli *devTunnelCli) IsLoggedIn(ctx context.Context) (bool, error) {
+	res, err := cli.commandRunner.Run(ctx, exec.NewRunArgs("devtunnel", "user", "show"))
+	if err != nil {
+		return false, fmt.Errorf("devtunnel user show: %w", err)
+	}
+
+	return !strings.Contains(res.Stdout, "Not logged in"), nil
+}
+
+func (cli *devTunnelCli) Login(ctx context.Context) error {
+	runArgs := exec.NewRunArgs("devtunnel", "user", "login").WithInteractive(true)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("devtunnel user login: %w", err)
+	}
+
+	return nil
+}
+
+func (cli *devTunnelCli) Host(ctx context.Context, port int, allowAnonymous bool, onReady func(url string)) error {
+	runArgs := exec.NewRunArgs("devtunnel", "host", "--port-numbers", strconv.Itoa(port))
+	if allowAnonymous {
+		runArgs = runArgs.AppendParams("--allow-anonymous")
+	}
+
+	_, err := cli.commandRunner.Run(ctx, runArgs.WithStdOut(&urlWriter{onReady: onReady}))
+	// The tunnel is hosted until it is stopped by cancelling the context, which kills the process
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("devtunnel host: %w", err)
+	}
+
+	return nil
+}
+
+// urlWriter scans the output of `devtunnel host` for the public URL of the tunnel, invoking onReady once
+type urlWriter struct {
+	onReady func(url string)
+
+	mu    sync.Mutex
+	line  bytes.Buffer
+	found bool
+}
+
+func (w *urlWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, b := range p {
+		if b != '\n' {
+			w.line.WriteByte(b)
+			continue
+		}
+
+		w.scan(w.line.String())
+		w.line.Reset()
+	}
+
+	return len(p), nil
+}
+
+func (w *urlWriter) scan(line string) {
+	if w.found {
+		return
+	}
+
+	url := tunnelUrl(line)
+	if url == "" {
+		return
+	}
+
+	w.found = true
+	if w.onReady != nil {
+		w.onReady(url)
+	}
+}
+
+// tunnelUrl returns the public URL of a `Connect via browser:` line, preferring the URL with the port in the host name,
+// ex. https://abc-3000.usw2.devtunnels.ms over https://abc.usw2.devtunnels.ms:3000, since it is served on the https port
+func tunnelUrl(line string) string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, connectViaBrowserPrefix) {
+		return ""
+	}
+
+	urls := strings.FieldsFunc(strings.TrimPrefix(line, connectViaBrowserPrefix), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(urls) == 0 {
+		return ""
+	}
+
+	for _, url := range urls {
+		if !strings.Contains(strings.TrimPrefix(url, "https://"), ":") {
+			return url
+		}
+	}
+
+	return urls[0]
+}
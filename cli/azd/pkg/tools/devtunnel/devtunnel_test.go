@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devtunnel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DevTunnelHost(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewDevTunnelCli(mockContext.CommandRunner)
+
+	ctx, cancel := context.WithCancel(*mockContext.Context)
+	defer cancel()
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "devtunnel host")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.Equal(t, []string{"host", "--port-numbers", "3000", "--allow-anonymous"}, args.Args)
+
+		output := "Hosting port: 3000\n" +
+			"Connect via browser: https://abc.usw2.devtunnels.ms:3000, https://abc-3000.usw2.devtunnels.ms\n" +
+			"Inspect network activity: https://abc-3000-inspect.usw2.devtunnels.ms\n"
+		// The output is written in chunks, as the process writes it
+		_, err := args.Stdout.Write([]byte(output[:40]))
+		require.NoError(t, err)
+		_, err = args.Stdout.Write([]byte(output[40:]))
+		require.NoError(t, err)
+
+		<-ctx.Done()
+		return exec.RunResult{}, ctx.Err()
+	})
+
+	var urls []string
+	err := cli.Host(ctx, 3000, true, func(url string) {
+		urls = append(urls, url)
+		cancel()
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://abc-3000.usw2.devtunnels.ms"}, urls)
+}
+
+func Test_TunnelUrl(t *testing.T) {
+	tests := map[string]string{
+		"Connect via browser: https://abc-3000.devtunnels.ms":                                   "https://abc-3000.devtunnels.ms",
+		"Connect via browser: https://abc.devtunnels.ms:3000":                                   "https://abc.devtunnels.ms:3000",
+		"  Connect via browser: https://abc.devtunnels.ms:3000, https://abc-3000.devtunnels.ms": "https://abc-3000.devtunnels.ms",
+		"Hosting port: 3000": "",
+	}
+
+	for line, expected := range tests {
+		require.Equal(t, expected, tunnelUrl(line))
+	}
+}
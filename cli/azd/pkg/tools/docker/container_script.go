@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+const (
+	// The path where the working directory is mounted within the container
+	ContainerWorkspacePath = "/workspace"
+	// The path where scripts outside of the working directory are mounted within the container
+	containerScriptsPath = "/azd/scripts"
+)
+
+// Options used to configure how scripts are executed within a container
+type ContainerScriptOptions struct {
+	// The container image used to run the script
+	Image string
+	// The shell used to invoke the script within the container
+	Shell string
+	// The working directory mounted into the container
+	Cwd string
+	// Environment variables forwarded into the container
+	EnvVars []string
+	// Additional volume mounts in the docker `<host-path>:<container-path>` format
+	Mounts []string
+}
+
+// Creates a new ContainerScript command runner
+// Scripts are executed within a container created from the configured image using the configured shell.
+// The working directory is mounted into the container and all environment variables are forwarded.
+func NewContainerScript(commandRunner exec.CommandRunner, options ContainerScriptOptions) tools.Script {
+	return &containerScript{
+		commandRunner: commandRunner,
+		options:       options,
+	}
+}
+
+type containerScript struct {
+	commandRunner exec.CommandRunner
+	options       ContainerScriptOptions
+}
+
+// Executes the specified script within a new container
+// When interactive is true will attach to stdin, stdout & stderr
+func (cs *containerScript) Execute(ctx context.Context, scriptPath string, interactive bool) (exec.RunResult, error) {
+	args := []string{"run", "--rm"}
+	if interactive {
+		args = append(args, "-it")
+	}
+
+	args = append(args,
+		"-v", fmt.Sprintf("%s:%s", cs.options.Cwd, ContainerWorkspacePath),
+		"-w", ContainerWorkspacePath,
+	)
+
+	containerPath, mount := cs.resolveScriptPath(scriptPath)
+	if mount != "" {
+		args = append(args, "-v", mount)
+	}
+
+	for _, mount := range cs.options.Mounts {
+		args = append(args, "-v", mount)
+	}
+
+	// Only the variable names are passed on the command line. Docker reads the values from the
+	// environment of the docker process, which keeps secrets out of process listings and logs.
+	for _, envVar := range cs.options.EnvVars {
+		key, _, _ := strings.Cut(envVar, "=")
+		if key == "" {
+			continue
+		}
+
+		args = append(args, "-e", key)
+	}
+
+	args = append(args, cs.options.Image, cs.options.Shell, containerPath)
+
+	runArgs := exec.NewRunArgs("docker", args...).
+		WithCwd(cs.options.Cwd).
+		WithEnv(cs.options.EnvVars).
+		WithInteractive(interactive)
+
+	return cs.commandRunner.Run(ctx, runArgs)
+}
+
+// Resolves the path of the script within the container.
+// Scripts that reside outside of the working directory (ex. generated inline scripts) require an additional
+// mount which is returned as the second value.
+func (cs *containerScript) resolveScriptPath(scriptPath string) (string, string) {
+	absPath := scriptPath
+	if !filepath.IsAbs(absPath) {
+		absPath = filepath.Join(cs.options.Cwd, scriptPath)
+	}
+
+	relPath, err := filepath.Rel(cs.options.Cwd, absPath)
+	if err == nil && !strings.HasPrefix(relPath, "..") {
+		return path.Join(ContainerWorkspacePath, filepath.ToSlash(relPath)), ""
+	}
+
+	containerPath := path.Join(containerScriptsPath, filepath.Base(absPath))
+	return containerPath, fmt.Sprintf("%s:%s:ro", absPath, containerPath)
+}
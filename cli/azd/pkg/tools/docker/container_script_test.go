@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerScript_Execute(t *testing.T) {
+	cwd := t.TempDir()
+	image := "mcr.microsoft.com/azure-cli"
+	env := []string{
+		"a=apple",
+		"b=banana",
+	}
+
+	t.Run("ScriptInWorkingDirectory", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+
+			require.Equal(t, "docker", args.Cmd)
+			require.Equal(t, cwd, args.Cwd)
+			require.Equal(t, env, args.Env)
+			require.Equal(t, false, args.Interactive)
+			require.Equal(t, []string{
+				"run", "--rm",
+				"-v", cwd + ":/workspace",
+				"-w", "/workspace",
+				"-e", "a",
+				"-e", "b",
+				image, "sh", "/workspace/scripts/script.sh",
+			}, args.Args)
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		script := NewContainerScript(mockContext.CommandRunner, ContainerScriptOptions{
+			Image:   image,
+			Shell:   "sh",
+			Cwd:     cwd,
+			EnvVars: env,
+		})
+		_, err := script.Execute(*mockContext.Context, filepath.Join("scripts", "script.sh"), false)
+
+		require.True(t, ran)
+		require.NoError(t, err)
+	})
+
+	t.Run("ScriptOutsideWorkingDirectory", func(t *testing.T) {
+		scriptPath := filepath.Join(os.TempDir(), "azd-inline-hook.ps1")
+		ran := false
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+
+			require.Equal(t, []string{
+				"run", "--rm", "-it",
+				"-v", cwd + ":/workspace",
+				"-w", "/workspace",
+				"-v", scriptPath + ":/azd/scripts/azd-inline-hook.ps1:ro",
+				"-v", "/tmp/output.env:/azd/output.env",
+				image, "pwsh", "/azd/scripts/azd-inline-hook.ps1",
+			}, args.Args)
+			require.Equal(t, true, args.Interactive)
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		script := NewContainerScript(mockContext.CommandRunner, ContainerScriptOptions{
+			Image:  image,
+			Shell:  "pwsh",
+			Cwd:    cwd,
+			Mounts: []string{"/tmp/output.env:/azd/output.env"},
+		})
+		_, err := script.Execute(*mockContext.Context, scriptPath, true)
+
+		require.True(t, ran)
+		require.NoError(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"regexp"
@@ -28,6 +29,32 @@ type Docker interface {
 	) (string, error)
 	Tag(ctx context.Context, cwd string, imageName string, tag string) error
 	Push(ctx context.Context, cwd string, tag string) error
+	Pull(ctx context.Context, cwd string, imageName string) error
+	Inspect(ctx context.Context, cwd string, imageName string) (*ImageInspect, error)
+	History(ctx context.Context, cwd string, imageName string) ([]ImageLayer, error)
+}
+
+// ImageInspect is the subset of `docker image inspect` describing a local image
+type ImageInspect struct {
+	Id           string   `json:"Id"`
+	RepoTags     []string `json:"RepoTags"`
+	Size         int64    `json:"Size"`
+	Os           string   `json:"Os"`
+	Architecture string   `json:"Architecture"`
+	Config       struct {
+		Env    []string          `json:"Env"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	RootFS struct {
+		Layers []string `json:"Layers"`
+	} `json:"RootFS"`
+}
+
+// ImageLayer is a layer of an image as reported by `docker history`, newest first
+type ImageLayer struct {
+	Id        string
+	CreatedBy string
+	Size      int64
 }
 
 func NewDocker(commandRunner exec.CommandRunner) Docker {
@@ -84,7 +111,8 @@ func (d *docker) Build(
 
 	args = append(args, buildContext)
 
-	res, err := d.executeCommand(ctx, cwd, args...)
+	// The image id is the only output of a quiet build, build logs are only written on failure
+	res, err := d.executeCommandWithOutputWindow(ctx, cwd, args...)
 	if err != nil {
 		return "", fmt.Errorf("building image: %w", err)
 	}
@@ -102,7 +130,7 @@ func (d *docker) Tag(ctx context.Context, cwd string, imageName string, tag stri
 }
 
 func (d *docker) Push(ctx context.Context, cwd string, tag string) error {
-	_, err := d.executeCommand(ctx, cwd, "push", tag)
+	_, err := d.executeCommandWithOutputWindow(ctx, cwd, "push", tag)
 	if err != nil {
 		return fmt.Errorf("pushing image: %w", err)
 	}
@@ -110,6 +138,73 @@ func (d *docker) Push(ctx context.Context, cwd string, tag string) error {
 	return nil
 }
 
+func (d *docker) Pull(ctx context.Context, cwd string, imageName string) error {
+	_, err := d.executeCommandWithOutputWindow(ctx, cwd, "pull", imageName)
+	if err != nil {
+		return fmt.Errorf("pulling image: %w", err)
+	}
+
+	return nil
+}
+
+// Inspects the local image, returning its size, platform, configuration and layers
+func (d *docker) Inspect(ctx context.Context, cwd string, imageName string) (*ImageInspect, error) {
+	res, err := d.executeCommand(ctx, cwd, "image", "inspect", imageName)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting image: %w", err)
+	}
+
+	var images []ImageInspect
+	if err := json.Unmarshal([]byte(res.Stdout), &images); err != nil {
+		return nil, fmt.Errorf("unmarshalling image inspect output: %w", err)
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("image '%s' not found", imageName)
+	}
+
+	return &images[0], nil
+}
+
+// Returns the layers of the local image with the instruction that created each layer
+func (d *docker) History(ctx context.Context, cwd string, imageName string) ([]ImageLayer, error) {
+	res, err := d.executeCommand(
+		ctx, cwd, "history", "--no-trunc", "--human=false", "--format", "{{json .}}", imageName)
+	if err != nil {
+		return nil, fmt.Errorf("reading image history: %w", err)
+	}
+
+	layers := []ImageLayer{}
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var entry struct {
+			ID        string `json:"ID"`
+			CreatedBy string `json:"CreatedBy"`
+			Size      string `json:"Size"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("unmarshalling image history output: %w", err)
+		}
+
+		size, err := strconv.ParseInt(entry.Size, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing size of layer '%s': %w", entry.ID, err)
+		}
+
+		layers = append(layers, ImageLayer{
+			Id:        entry.ID,
+			CreatedBy: entry.CreatedBy,
+			Size:      size,
+		})
+	}
+
+	return layers, nil
+}
+
 func (d *docker) versionInfo() tools.VersionInfo {
 	return tools.VersionInfo{
 		MinimumVersion: semver.Version{
@@ -229,3 +324,12 @@ func (d *docker) executeCommand(ctx context.Context, cwd string, args ...string)
 
 	return d.commandRunner.Run(ctx, runArgs)
 }
+
+// Executes a docker command that may produce large output, only the most recent output is kept in memory
+func (d *docker) executeCommandWithOutputWindow(ctx context.Context, cwd string, args ...string) (exec.RunResult, error) {
+	runArgs := exec.NewRunArgs("docker", args...).
+		WithCwd(cwd).
+		WithOutputWindow(exec.DefaultOutputWindow)
+
+	return d.commandRunner.Run(ctx, runArgs)
+}
@@ -290,6 +290,80 @@ func Test_DockerPush(t *testing.T) {
 	})
 }
 
+func Test_DockerPull(t *testing.T) {
+	ran := false
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewDocker(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker pull")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = true
+		require.Equal(t, []string{"pull", "ghcr.io/contoso/api:1.2.0"}, args.Args)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	err := docker.Pull(context.Background(), "", "ghcr.io/contoso/api:1.2.0")
+	require.NoError(t, err)
+	require.True(t, ran)
+}
+
+func Test_DockerInspect(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewDocker(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.Equal(t, []string{"image", "inspect", "web:azd-deploy"}, args.Args)
+
+		return exec.NewRunResult(0, `[{
+			"Id": "sha256:abc",
+			"RepoTags": ["web:azd-deploy"],
+			"Size": 1024,
+			"Os": "linux",
+			"Architecture": "amd64",
+			"Config": {"Env": ["PORT=80"], "Labels": {"org.opencontainers.image.base.name": "node:18"}},
+			"RootFS": {"Layers": ["sha256:1", "sha256:2"]}
+		}]`, ""), nil
+	})
+
+	image, err := docker.Inspect(context.Background(), ".", "web:azd-deploy")
+	require.NoError(t, err)
+	require.Equal(t, "sha256:abc", image.Id)
+	require.Equal(t, int64(1024), image.Size)
+	require.Equal(t, "linux", image.Os)
+	require.Equal(t, []string{"PORT=80"}, image.Config.Env)
+	require.Equal(t, "node:18", image.Config.Labels["org.opencontainers.image.base.name"])
+	require.Len(t, image.RootFS.Layers, 2)
+}
+
+func Test_DockerHistory(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewDocker(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker history")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.Equal(t, []string{
+			"history", "--no-trunc", "--human=false", "--format", "{{json .}}", "web:azd-deploy",
+		}, args.Args)
+
+		return exec.NewRunResult(0, strings.Join([]string{
+			`{"ID":"sha256:abc","CreatedBy":"COPY . . # buildkit","Size":"2048"}`,
+			`{"ID":"<missing>","CreatedBy":"/bin/sh -c #(nop) ADD file:123 in /","Size":"0"}`,
+		}, "\n"), ""), nil
+	})
+
+	layers, err := docker.History(context.Background(), ".", "web:azd-deploy")
+	require.NoError(t, err)
+	require.Equal(t, []ImageLayer{
+		{Id: "sha256:abc", CreatedBy: "COPY . . # buildkit", Size: 2048},
+		{Id: "<missing>", CreatedBy: "/bin/sh -c #(nop) ADD file:123 in /", Size: 0},
+	}, layers)
+}
+
 func Test_DockerLogin(t *testing.T) {
 	t.Run("NoError", func(t *testing.T) {
 		ran := false
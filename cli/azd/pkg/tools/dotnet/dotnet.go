@@ -22,6 +22,10 @@ type DotNetCli interface {
 	Publish(ctx context.Context, project string, configuration string, output string) error
 	InitializeSecret(ctx context.Context, project string) error
 	SetSecrets(ctx context.Context, secrets map[string]string, project string) error
+	// Publishes the project as a container image to the local docker daemon, with the .NET SDK container support
+	PublishContainer(ctx context.Context, project string, configuration string, imageName string) error
+	// Writes the manifest of the .NET Aspire app host project to the manifest path
+	PublishAppHostManifest(ctx context.Context, hostProject string, manifestPath string) error
 }
 
 type dotNetCli struct {
@@ -67,8 +71,16 @@ func (cli *dotNetCli) CheckInstalled(ctx context.Context) error {
 	return nil
 }
 
+// packagesCacheEnv returns the NuGet packages folder of the dependency cache of the context, if any. The projects are
+// built and published with the packages folder they were restored with, or they would be restored again.
+func packagesCacheEnv(ctx context.Context) []string {
+	return tools.DependencyCacheEnv(ctx, "nuget", "NUGET_PACKAGES")
+}
+
 func (cli *dotNetCli) Restore(ctx context.Context, project string) error {
-	runArgs := exec.NewRunArgs("dotnet", "restore", project)
+	runArgs := exec.NewRunArgs("dotnet", "restore", project).
+		WithEnv(packagesCacheEnv(ctx)).
+		WithOutputWindow(exec.DefaultOutputWindow)
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("dotnet restore on project '%s' failed: %w", project, err)
@@ -77,7 +89,9 @@ func (cli *dotNetCli) Restore(ctx context.Context, project string) error {
 }
 
 func (cli *dotNetCli) Build(ctx context.Context, project string, configuration string, output string) error {
-	runArgs := exec.NewRunArgs("dotnet", "build", project)
+	runArgs := exec.NewRunArgs("dotnet", "build", project).
+		WithEnv(packagesCacheEnv(ctx)).
+		WithOutputWindow(exec.DefaultOutputWindow)
 	if configuration != "" {
 		runArgs = runArgs.AppendParams("-c", configuration)
 	}
@@ -94,7 +108,9 @@ func (cli *dotNetCli) Build(ctx context.Context, project string, configuration s
 }
 
 func (cli *dotNetCli) Publish(ctx context.Context, project string, configuration string, output string) error {
-	runArgs := exec.NewRunArgs("dotnet", "publish", project)
+	runArgs := exec.NewRunArgs("dotnet", "publish", project).
+		WithEnv(packagesCacheEnv(ctx)).
+		WithOutputWindow(exec.DefaultOutputWindow)
 	if configuration != "" {
 		runArgs = runArgs.AppendParams("-c", configuration)
 	}
@@ -110,6 +126,43 @@ func (cli *dotNetCli) Publish(ctx context.Context, project string, configuration
 	return nil
 }
 
+func (cli *dotNetCli) PublishContainer(
+	ctx context.Context,
+	project string,
+	configuration string,
+	imageName string,
+) error {
+	runArgs := exec.NewRunArgs(
+		"dotnet", "publish", project,
+		"-r", "linux-x64",
+		"-p:PublishProfile=DefaultContainer",
+		fmt.Sprintf("-p:ContainerRepository=%s", imageName),
+	).WithEnv(packagesCacheEnv(ctx)).WithOutputWindow(exec.DefaultOutputWindow)
+	if configuration != "" {
+		runArgs = runArgs.AppendParams("-c", configuration)
+	}
+
+	_, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("dotnet publish container on project '%s' failed: %w", project, err)
+	}
+	return nil
+}
+
+func (cli *dotNetCli) PublishAppHostManifest(ctx context.Context, hostProject string, manifestPath string) error {
+	runArgs := exec.NewRunArgs(
+		"dotnet", "run", "--project", hostProject, "--",
+		"--publisher", "manifest",
+		"--output-path", manifestPath,
+	)
+
+	_, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("generating the manifest of app host '%s' failed: %w", hostProject, err)
+	}
+	return nil
+}
+
 func (cli *dotNetCli) InitializeSecret(ctx context.Context, project string) error {
 	runArgs := exec.NewRunArgs("dotnet", "user-secrets", "init", "--project", project)
 	_, err := cli.commandRunner.Run(ctx, runArgs)
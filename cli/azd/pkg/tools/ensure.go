@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"log"
 	osexec "os/exec"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
 )
 
 // missingToolErrors wraps a set of errors discovered when
@@ -31,24 +34,34 @@ func (m *missingToolErrors) Error() string {
 }
 
 // EnsureInstalled checks that all tools are installed, returning an
-// error if one or more tools are not.
+// error if one or more tools are not. The tools are checked concurrently.
 func EnsureInstalled(ctx context.Context, tools ...ExternalTool) error {
 	var allErrors []error
 	errorsEncountered := map[string]struct{}{}
 
-	confirmedTools := make(map[string]struct{})
-	if fromCtx, ok := ctx.Value(installedCheckCacheKey).(map[string]struct{}); ok && fromCtx != nil {
-		confirmedTools = fromCtx
+	cache, ok := ctx.Value(installedCheckCacheKey).(*installedCheckCache)
+	if !ok || cache == nil {
+		cache = newInstalledCheckCache()
 	}
 
-	for _, tool := range tools {
-		_, ok := confirmedTools[tool.Name()]
-		if ok {
+	// Tools that were previously confirmed are skipped
+	toolsToCheck := []ExternalTool{}
+	for _, tool := range Unique(tools) {
+		if cache.isConfirmed(tool.Name()) {
 			log.Printf("Skipping install check for '%s'. It was previously confirmed.", tool.Name())
 			continue
 		}
 
-		err := tool.CheckInstalled(ctx)
+		toolsToCheck = append(toolsToCheck, tool)
+	}
+
+	_, checkErrors := async.RunParallel(ctx, toolsToCheck, 0, func(ctx context.Context, tool ExternalTool) (any, error) {
+		return nil, tool.CheckInstalled(ctx)
+	})
+
+	// Errors are reported in the order of the tools
+	for i, tool := range toolsToCheck {
+		err := checkErrors[i]
 		var errSem *ErrSemver
 		if errors.As(err, &errSem) {
 			errorMsg := err.Error()
@@ -69,7 +82,7 @@ func EnsureInstalled(ctx context.Context, tools ...ExternalTool) error {
 		}
 
 		// Mark the current tool as confirmed
-		confirmedTools[tool.Name()] = struct{}{}
+		cache.confirm(tool.Name())
 	}
 
 	if len(allErrors) > 0 {
@@ -103,6 +116,33 @@ const (
 	installedCheckCacheKey confirmCacheKey = "checkCache"
 )
 
+// installedCheckCache tracks the tools confirmed during a command so they are only checked once
+type installedCheckCache struct {
+	mu        sync.Mutex
+	confirmed map[string]struct{}
+}
+
+func newInstalledCheckCache() *installedCheckCache {
+	return &installedCheckCache{
+		confirmed: map[string]struct{}{},
+	}
+}
+
+func (c *installedCheckCache) isConfirmed(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, has := c.confirmed[name]
+	return has
+}
+
+func (c *installedCheckCache) confirm(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.confirmed[name] = struct{}{}
+}
+
 func WithInstalledCheckCache(ctx context.Context) context.Context {
-	return context.WithValue(ctx, installedCheckCacheKey, make(map[string]struct{}))
+	return context.WithValue(ctx, installedCheckCacheKey, newInstalledCheckCache())
 }
@@ -2,7 +2,9 @@ package tools
 
 import (
 	"context"
+	osexec "os/exec"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -17,6 +19,49 @@ func Test_EnsureInstalledOnlyOnceWhenCached(t *testing.T) {
 	require.Equal(t, tool.installChecks, 1)
 }
 
+func Test_EnsureInstalledChecksToolsConcurrently(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	// Each check waits for the other to start, which would never happen if the checks ran sequentially
+	first := &blockingTool{name: "first", started: started, release: release}
+	second := &blockingTool{name: "second", started: release, release: started, err: osexec.ErrNotFound}
+
+	done := make(chan error)
+	go func() {
+		done <- EnsureInstalled(context.Background(), first, second)
+	}()
+
+	select {
+	case err := <-done:
+		require.ErrorContains(t, err, "second is not installed")
+		require.NotContains(t, err.Error(), "first")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "tools were not checked concurrently")
+	}
+}
+
+type blockingTool struct {
+	name    string
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (t *blockingTool) CheckInstalled(ctx context.Context) error {
+	close(t.started)
+	<-t.release
+	return t.err
+}
+
+func (t *blockingTool) InstallUrl() string {
+	return "http://www.microsoft.com"
+}
+
+func (t *blockingTool) Name() string {
+	return t.name
+}
+
 type TestTool struct {
 	installChecks int
 }
@@ -30,6 +30,7 @@ type GitCli interface {
 	Commit(ctx context.Context, repositoryPath string, message string) error
 	PushUpstream(ctx context.Context, repositoryPath string, origin string, branch string) error
 	IsUntrackedFile(ctx context.Context, repositoryPath string, filePath string) (bool, error)
+	HasChanges(ctx context.Context, repositoryPath string, path string) (bool, error)
 	SetCredentialStore(ctx context.Context, repositoryPath string) error
 	ListStagedFiles(ctx context.Context, repositoryPath string) (string, error)
 	AddFileExecPermission(ctx context.Context, repositoryPath string, file string) error
@@ -251,6 +252,22 @@ func (cli *gitCli) IsUntrackedFile(ctx context.Context, repositoryPath string, f
 	return false, nil
 }
 
+// HasChanges returns true when the specified path relative to the repositoryPath contains modified, staged
+// or untracked files.
+func (cli *gitCli) HasChanges(ctx context.Context, repositoryPath string, path string) (bool, error) {
+	if path == "" {
+		path = "."
+	}
+
+	runArgs := newRunArgs("-C", repositoryPath, "status", "--porcelain", "--untracked-files=all", "--", path)
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return false, fmt.Errorf("failed to check status: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout) != "", nil
+}
+
 // SetGitHubAuthForRepo creates git config for the repositoryPath like
 //
 // [credential "https://github.com"]  (when credential is equal to "https://github.com")
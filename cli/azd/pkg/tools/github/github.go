@@ -37,6 +37,10 @@ type GitHubCli interface {
 	ListSecrets(ctx context.Context, repo string) error
 	SetSecret(ctx context.Context, repo string, name string, value string) error
 	SetVariable(ctx context.Context, repoSlug string, name string, value string) error
+	// Sets a secret of the deployment environment of the repository
+	SetEnvironmentSecret(ctx context.Context, repoSlug string, environment string, name string, value string) error
+	// Sets a variable of the deployment environment of the repository
+	SetEnvironmentVariable(ctx context.Context, repoSlug string, environment string, name string, value string) error
 	Login(ctx context.Context, hostname string) error
 	ListRepositories(ctx context.Context) ([]GhCliRepository, error)
 	ViewRepository(ctx context.Context, name string) (GhCliRepository, error)
@@ -241,6 +245,36 @@ func (cli *ghCli) SetVariable(ctx context.Context, repoSlug string, name string,
 	return nil
 }
 
+func (cli *ghCli) SetEnvironmentSecret(
+	ctx context.Context,
+	repoSlug string,
+	environment string,
+	name string,
+	value string,
+) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "secret", "set", name, "--env", environment, "--body", value)
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh secret set: %w", err)
+	}
+	return nil
+}
+
+func (cli *ghCli) SetEnvironmentVariable(
+	ctx context.Context,
+	repoSlug string,
+	environment string,
+	name string,
+	value string,
+) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "variable", "set", name, "--env", environment, "--body", value)
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh variable set: %w", err)
+	}
+	return nil
+}
+
 // cGhCliVersionRegexp fetches the version number from the output of gh --version, which looks like this:
 //
 // gh version 2.6.0 (2022-03-15)
@@ -0,0 +1,219 @@
+package gradle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	osexec "os/exec"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The build files of gradle projects
+var BuildFiles = []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}
+
+// IsGradleProject returns whether the directory contains a gradle build
+func IsGradleProject(projectPath string) bool {
+	for _, buildFile := range BuildFiles {
+		if _, err := os.Stat(filepath.Join(projectPath, buildFile)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+type GradleCli interface {
+	tools.ExternalTool
+	SetPath(projectPath string, rootProjectPath string)
+	ResolveDependencies(ctx context.Context, projectPath string) error
+	Compile(ctx context.Context, projectPath string) error
+	// Assemble builds the archives of the project, without running the tests
+	Assemble(ctx context.Context, projectPath string) error
+}
+
+type gradleCli struct {
+	commandRunner   exec.CommandRunner
+	projectPath     string
+	rootProjectPath string
+
+	// Lazily initialized. Access through gradleCmd.
+	gradleCmdStr  string
+	gradleCmdOnce sync.Once
+	gradleCmdErr  error
+}
+
+func (g *gradleCli) Name() string {
+	return "Gradle"
+}
+
+func (g *gradleCli) InstallUrl() string {
+	return "https://gradle.org/install"
+}
+
+func (g *gradleCli) CheckInstalled(ctx context.Context) error {
+	_, err := g.gradleCmd()
+	if err != nil {
+		return err
+	}
+
+	if ver, err := g.extractVersion(ctx); err == nil {
+		log.Printf("gradle version: %s", ver)
+	}
+
+	return nil
+}
+
+func (g *gradleCli) SetPath(projectPath string, rootProjectPath string) {
+	g.projectPath = projectPath
+	g.rootProjectPath = rootProjectPath
+}
+
+func (g *gradleCli) gradleCmd() (string, error) {
+	g.gradleCmdOnce.Do(func() {
+		gradleCmd, err := getGradlePath(g.projectPath, g.rootProjectPath)
+		if err != nil {
+			g.gradleCmdErr = err
+		} else {
+			g.gradleCmdStr = gradleCmd
+		}
+	})
+
+	if g.gradleCmdErr != nil {
+		return "", g.gradleCmdErr
+	}
+
+	return g.gradleCmdStr, nil
+}
+
+func getGradlePath(projectPath string, rootProjectPath string) (string, error) {
+	gradlew, err := getGradleWrapperPath(projectPath, rootProjectPath)
+	if gradlew != "" {
+		return gradlew, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed finding gradlew in repository path: %w", err)
+	}
+
+	gradle, err := osexec.LookPath("gradle")
+	if err == nil {
+		return gradle, nil
+	}
+
+	if !errors.Is(err, osexec.ErrNotFound) {
+		return "", fmt.Errorf("failed looking up gradle in PATH: %w", err)
+	}
+
+	return "", errors.New(
+		"gradle could not be found. Install either Gradle or the Gradle Wrapper by " +
+			"visiting https://gradle.org/install or https://docs.gradle.org/current/userguide/gradle_wrapper.html",
+	)
+}
+
+// getGradleWrapperPath finds the path to gradlew in the project directory, up to the root project directory.
+//
+// An error is returned if an unexpected error occurred while finding.
+// If gradlew is not found, an empty string is returned with
+// no error.
+func getGradleWrapperPath(projectPath string, rootProjectPath string) (string, error) {
+	searchDir, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := filepath.Abs(rootProjectPath)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		gradlew, err := osexec.LookPath(filepath.Join(searchDir, "gradlew"))
+		if err == nil {
+			log.Printf("found gradlew as: %s\n", gradlew)
+			return gradlew, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		searchDir = filepath.Dir(searchDir)
+
+		// Past root, terminate search and return not found
+		if len(searchDir) < len(root) {
+			return "", nil
+		}
+	}
+}
+
+// cGradleVersionRegexp captures the version number of gradle from the output of "gradle --version"
+//
+// the output of gradle --version looks something like this:
+//
+// ------------------------------------------------------------
+// Gradle 8.5
+// ------------------------------------------------------------
+//
+// Build time:   2023-11-29 14:08:57 UTC
+var cGradleVersionRegexp = regexp.MustCompile(`Gradle (\S+)`)
+
+func (cli *gradleCli) extractVersion(ctx context.Context) (string, error) {
+	gradleCmd, err := cli.gradleCmd()
+	if err != nil {
+		return "", err
+	}
+
+	runArgs := exec.NewRunArgs(gradleCmd, "--version")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", gradleCmd, err)
+	}
+
+	parts := cGradleVersionRegexp.FindStringSubmatch(res.Stdout)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("could not parse %s --version output, did not match expected format", gradleCmd)
+	}
+
+	return parts[1], nil
+}
+
+func (cli *gradleCli) run(ctx context.Context, projectPath string, args ...string) error {
+	gradleCmd, err := cli.gradleCmd()
+	if err != nil {
+		return err
+	}
+
+	runArgs := exec.NewRunArgs(gradleCmd, args...).WithCwd(projectPath).WithOutputWindow(exec.DefaultOutputWindow)
+	_, err = cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("gradle %s on project '%s' failed: %w", args[0], projectPath, err)
+	}
+
+	return nil
+}
+
+func (cli *gradleCli) ResolveDependencies(ctx context.Context, projectPath string) error {
+	return cli.run(ctx, projectPath, "dependencies")
+}
+
+func (cli *gradleCli) Compile(ctx context.Context, projectPath string) error {
+	return cli.run(ctx, projectPath, "classes")
+}
+
+func (cli *gradleCli) Assemble(ctx context.Context, projectPath string) error {
+	return cli.run(ctx, projectPath, "assemble")
+}
+
+func NewGradleCli(commandRunner exec.CommandRunner) GradleCli {
+	return &gradleCli{
+		commandRunner: commandRunner,
+	}
+}
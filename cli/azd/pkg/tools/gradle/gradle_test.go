@@ -0,0 +1,44 @@
+package gradle
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getGradlePath(t *testing.T) {
+	rootPath := t.TempDir()
+	projectPath := filepath.Join(rootPath, "src", "api")
+	require.NoError(t, os.MkdirAll(projectPath, 0755))
+	ostest.Unsetenv(t, "PATH")
+
+	_, err := getGradlePath(projectPath, rootPath)
+	require.ErrorContains(t, err, "gradle could not be found")
+
+	gradlew := filepath.Join(rootPath, gradlewWithExt())
+	require.NoError(t, os.WriteFile(gradlew, nil, 0755))
+
+	path, err := getGradlePath(projectPath, rootPath)
+	require.NoError(t, err)
+	require.Equal(t, gradlew, path)
+}
+
+func Test_IsGradleProject(t *testing.T) {
+	projectPath := t.TempDir()
+	require.False(t, IsGradleProject(projectPath))
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "settings.gradle.kts"), nil, 0600))
+	require.True(t, IsGradleProject(projectPath))
+}
+
+func gradlewWithExt() string {
+	if runtime.GOOS == "windows" {
+		return "gradlew.bat"
+	}
+
+	return "gradlew"
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -42,6 +44,30 @@ type KubectlCli interface {
 	Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error)
 	// Gets the deployment rollout status
 	RolloutStatus(ctx context.Context, deploymentName string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Writes the logs of the containers of the selected pods to the writer
+	Logs(ctx context.Context, options LogsOptions, flags *KubeCliFlags, stdout io.Writer) error
+	// Forwards the local port to the port of the resource until the context is canceled, writing the forwarded
+	// addresses to the writer
+	PortForward(
+		ctx context.Context,
+		resource string,
+		localPort int,
+		remotePort int,
+		flags *KubeCliFlags,
+		stdout io.Writer,
+	) error
+}
+
+// The options of the logs written by `kubectl logs`
+type LogsOptions struct {
+	// The resource of the pods, ex. deployment/api, exclusive with Selector
+	Resource string
+	// The label selector of the pods, ex. app=api
+	Selector string
+	// Only the logs more recent than the duration are written, all the logs when 0
+	Since time.Duration
+	// Whether new logs are written until the context is canceled
+	Follow bool
 }
 
 type OutputType string
@@ -253,6 +279,62 @@ func (cli *kubectlCli) RolloutStatus(
 	return &res, nil
 }
 
+// Writes the logs of all the containers of the selected pods, each line prefixed by its pod and container and its
+// timestamp
+func (cli *kubectlCli) Logs(
+	ctx context.Context,
+	options LogsOptions,
+	flags *KubeCliFlags,
+	stdout io.Writer,
+) error {
+	runArgs := exec.NewRunArgs("kubectl", "logs").WithStdOut(stdout)
+
+	target := options.Resource
+	if options.Selector != "" {
+		target = options.Selector
+		runArgs = runArgs.AppendParams("-l", options.Selector)
+	} else {
+		runArgs = runArgs.AppendParams(options.Resource)
+	}
+
+	runArgs = runArgs.AppendParams("--all-containers", "--prefix", "--timestamps")
+
+	if options.Since > 0 {
+		runArgs = runArgs.AppendParams(fmt.Sprintf("--since=%s", options.Since))
+	}
+
+	if options.Follow {
+		runArgs = runArgs.AppendParams("--follow")
+	}
+
+	if _, err := cli.executeCommandWithArgs(ctx, runArgs, flags); err != nil {
+		return fmt.Errorf("failed reading logs of '%s', %w", target, err)
+	}
+
+	return nil
+}
+
+// Forwards the local port to the port of the resource, ex. service/api, until the context is canceled. A free local port
+// is selected when the local port is 0.
+func (cli *kubectlCli) PortForward(
+	ctx context.Context,
+	resource string,
+	localPort int,
+	remotePort int,
+	flags *KubeCliFlags,
+	stdout io.Writer,
+) error {
+	runArgs := exec.
+		NewRunArgs("kubectl", "port-forward", resource, fmt.Sprintf("%d:%d", localPort, remotePort)).
+		WithStdOut(stdout)
+
+	if _, err := cli.executeCommandWithArgs(ctx, runArgs, flags); err != nil {
+		return fmt.Errorf("failed forwarding port %d of '%s', %w", remotePort, resource, err)
+	}
+
+	return nil
+}
+
 // Executes a k8s CLI command from the specified arguments and flags
 func (cli *kubectlCli) Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error) {
 	runArgs := exec.
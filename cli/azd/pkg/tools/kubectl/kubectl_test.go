@@ -3,9 +3,11 @@ package kubectl
 import (
 	"bytes"
 	"context"
+	"io"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -142,6 +144,49 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"logs": {
+			mockCommandPredicate: "kubectl logs",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"logs",
+				"-l",
+				"app=api",
+				"--all-containers",
+				"--prefix",
+				"--timestamps",
+				"--since=10m0s",
+				"--follow",
+				"-n",
+				"test-namespace",
+			},
+			testFn: func() error {
+				return cli.Logs(
+					*mockContext.Context,
+					LogsOptions{Selector: "app=api", Since: 10 * time.Minute, Follow: true},
+					&KubeCliFlags{
+						Namespace: "test-namespace",
+					},
+					io.Discard,
+				)
+			},
+		},
+		"port-forward": {
+			mockCommandPredicate: "kubectl port-forward",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"port-forward", "service/api", "0:80", "-n", "test-namespace"},
+			testFn: func() error {
+				return cli.PortForward(
+					*mockContext.Context,
+					"service/api",
+					0,
+					80,
+					&KubeCliFlags{
+						Namespace: "test-namespace",
+					},
+					io.Discard,
+				)
+			},
+		},
 		"exec": {
 			mockCommandPredicate: "kubectl get deployment",
 			expectedCmd:          "kubectl",
@@ -40,6 +40,12 @@ type Deployment ResourceWithSpec[DeploymentSpec, DeploymentStatus]
 
 type DeploymentSpec struct {
 	Replicas int `yaml:"replicas"`
+	// Selects the pods of the deployment
+	Selector LabelSelector `yaml:"selector"`
+}
+
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
 }
 
 type DeploymentStatus struct {
@@ -173,13 +173,26 @@ func (cli *mavenCli) extractVersion(ctx context.Context) (string, error) {
 	return parts[1], nil
 }
 
+// localRepositoryArgs returns the arguments setting the local repository to the dependency cache of the context, if
+// any. The projects are compiled and packaged with the repository their dependencies were resolved to.
+func localRepositoryArgs(ctx context.Context) []string {
+	dir, has := tools.DependencyCacheDir(ctx, "maven")
+	if !has {
+		return nil
+	}
+
+	return []string{"-Dmaven.repo.local=" + dir}
+}
+
 func (cli *mavenCli) Compile(ctx context.Context, projectPath string) error {
 	mvnCmd, err := cli.mvnCmd()
 	if err != nil {
 		return err
 	}
 
-	runArgs := exec.NewRunArgs(mvnCmd, "compile").WithCwd(projectPath)
+	runArgs := exec.NewRunArgs(mvnCmd, append([]string{"compile"}, localRepositoryArgs(ctx)...)...).
+		WithCwd(projectPath).
+		WithOutputWindow(exec.DefaultOutputWindow)
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn compile on project '%s' failed: %w", projectPath, err)
@@ -195,7 +208,9 @@ func (cli *mavenCli) Package(ctx context.Context, projectPath string) error {
 	}
 
 	// Maven's package phase includes tests by default. Skip it explicitly.
-	runArgs := exec.NewRunArgs(mvnCmd, "package", "-DskipTests").WithCwd(projectPath)
+	runArgs := exec.NewRunArgs(mvnCmd, append([]string{"package", "-DskipTests"}, localRepositoryArgs(ctx)...)...).
+		WithCwd(projectPath).
+		WithOutputWindow(exec.DefaultOutputWindow)
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn package on project '%s' failed: %w", projectPath, err)
@@ -209,7 +224,9 @@ func (cli *mavenCli) ResolveDependencies(ctx context.Context, projectPath string
 	if err != nil {
 		return err
 	}
-	runArgs := exec.NewRunArgs(mvnCmd, "dependency:resolve").WithCwd(projectPath)
+	runArgs := exec.NewRunArgs(mvnCmd, append([]string{"dependency:resolve"}, localRepositoryArgs(ctx)...)...).
+		WithCwd(projectPath).
+		WithOutputWindow(exec.DefaultOutputWindow)
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn dependency:resolve on project '%s' failed: %w", projectPath, err)
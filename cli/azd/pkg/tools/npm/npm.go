@@ -77,7 +77,9 @@ func (cli *npmCli) Name() string {
 func (cli *npmCli) Install(ctx context.Context, project string) error {
 	runArgs := exec.
 		NewRunArgs("npm", "install").
-		WithCwd(project)
+		WithCwd(project).
+		WithEnv(tools.DependencyCacheEnv(ctx, "npm", "npm_config_cache")).
+		WithOutputWindow(exec.DefaultOutputWindow)
 
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 
@@ -90,7 +92,8 @@ func (cli *npmCli) Install(ctx context.Context, project string) error {
 func (cli *npmCli) RunScript(ctx context.Context, projectPath string, scriptName string) error {
 	runArgs := exec.
 		NewRunArgs("npm", "run", scriptName, "--if-present").
-		WithCwd(projectPath)
+		WithCwd(projectPath).
+		WithOutputWindow(exec.DefaultOutputWindow)
 
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 
@@ -104,7 +107,8 @@ func (cli *npmCli) RunScript(ctx context.Context, projectPath string, scriptName
 func (cli *npmCli) Prune(ctx context.Context, projectPath string, production bool) error {
 	runArgs := exec.
 		NewRunArgs("npm", "prune").
-		WithCwd(projectPath)
+		WithCwd(projectPath).
+		WithOutputWindow(exec.DefaultOutputWindow)
 
 	if production {
 		runArgs = runArgs.AppendParams("--production")
@@ -0,0 +1,230 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type PulumiCli interface {
+	tools.ExternalTool
+	// Set environment variables to be used in all pulumi commands
+	SetEnv(envVars []string)
+	// Selects the stack of the program, creating it when it doesn't exist
+	SelectStack(ctx context.Context, programPath string, stack string) error
+	// Sets the configuration values of the stack, values are set as plain text
+	SetConfig(ctx context.Context, programPath string, stack string, values map[string]string) error
+	// Previews the changes of the stack, returning the JSON description of the steps
+	Preview(ctx context.Context, programPath string, stack string) (string, error)
+	// Creates or updates the resources of the stack
+	Up(ctx context.Context, programPath string, stack string) (string, error)
+	// Retrieves the outputs of the stack as JSON
+	StackOutput(ctx context.Context, programPath string, stack string) (string, error)
+	// Exports the state of the stack as JSON
+	StackExport(ctx context.Context, programPath string, stack string) (string, error)
+	// Deletes all the resources of the stack
+	Destroy(ctx context.Context, programPath string, stack string, additionalArgs ...string) (string, error)
+}
+
+type pulumiCli struct {
+	commandRunner exec.CommandRunner
+	env           []string
+}
+
+func NewPulumiCli(commandRunner exec.CommandRunner) PulumiCli {
+	return &pulumiCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *pulumiCli) Name() string {
+	return "Pulumi CLI"
+}
+
+func (cli *pulumiCli) InstallUrl() string {
+	return "https://www.pulumi.com/docs/install/"
+}
+
+func (cli *pulumiCli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 3,
+			Minor: 50,
+			Patch: 0},
+		UpdateCommand: "Download newer version from https://www.pulumi.com/docs/install/",
+	}
+}
+
+func (cli *pulumiCli) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("pulumi")
+	if err != nil {
+		return err
+	}
+
+	versionOutput, err := tools.ExecuteCommand(ctx, cli.commandRunner, "pulumi", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("pulumi version: %s", versionOutput)
+
+	pulumiSemver, err := semver.Parse(strings.TrimPrefix(strings.TrimSpace(versionOutput), "v"))
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := cli.versionInfo()
+	if pulumiSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+// Set environment variables to be used in all pulumi commands
+func (cli *pulumiCli) SetEnv(env []string) {
+	cli.env = env
+}
+
+func (cli *pulumiCli) runCommand(ctx context.Context, args ...string) (exec.RunResult, error) {
+	runArgs := exec.
+		NewRunArgs("pulumi", args...).
+		WithEnv(cli.env)
+
+	return cli.commandRunner.Run(ctx, runArgs)
+}
+
+func (cli *pulumiCli) runInteractive(ctx context.Context, args ...string) (exec.RunResult, error) {
+	runArgs := exec.
+		NewRunArgs("pulumi", args...).
+		WithEnv(cli.env).
+		WithInteractive(true)
+
+	return cli.commandRunner.Run(ctx, runArgs)
+}
+
+func (cli *pulumiCli) SelectStack(ctx context.Context, programPath string, stack string) error {
+	args := []string{"stack", "select", stack, "--create", "--cwd", programPath, "--non-interactive"}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return fmt.Errorf(
+			"failed running pulumi stack select: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return nil
+}
+
+func (cli *pulumiCli) SetConfig(
+	ctx context.Context,
+	programPath string,
+	stack string,
+	values map[string]string,
+) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	args := []string{"config", "set-all", "--stack", stack, "--cwd", programPath, "--non-interactive"}
+	keys := maps.Keys(values)
+	slices.Sort(keys)
+	for _, key := range keys {
+		args = append(args, "--plaintext", fmt.Sprintf("%s=%s", key, values[key]))
+	}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return fmt.Errorf(
+			"failed running pulumi config set-all: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return nil
+}
+
+func (cli *pulumiCli) Preview(ctx context.Context, programPath string, stack string) (string, error) {
+	args := []string{"preview", "--json", "--stack", stack, "--cwd", programPath, "--non-interactive"}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi preview: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) Up(ctx context.Context, programPath string, stack string) (string, error) {
+	args := []string{"up", "--yes", "--skip-preview", "--stack", stack, "--cwd", programPath}
+
+	cmdRes, err := cli.runInteractive(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi up: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) StackOutput(ctx context.Context, programPath string, stack string) (string, error) {
+	args := []string{"stack", "output", "--json", "--show-secrets", "--stack", stack, "--cwd", programPath}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi stack output: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) StackExport(ctx context.Context, programPath string, stack string) (string, error) {
+	args := []string{"stack", "export", "--stack", stack, "--cwd", programPath}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi stack export: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) Destroy(
+	ctx context.Context,
+	programPath string,
+	stack string,
+	additionalArgs ...string,
+) (string, error) {
+	args := []string{"destroy", "--stack", stack, "--cwd", programPath}
+
+	args = append(args, additionalArgs...)
+	cmdRes, err := cli.runInteractive(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi destroy: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
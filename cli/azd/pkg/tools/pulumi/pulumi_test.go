@@ -0,0 +1,58 @@
+package pulumi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithEnv(t *testing.T) {
+	ran := false
+	expectedEnvVars := []string{"PULUMI_BACKEND_URL=file://MYDIR"}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = true
+		require.Equal(t, expectedEnvVars, args.Env)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewPulumiCli(mockContext.CommandRunner)
+	cli.SetEnv(expectedEnvVars)
+
+	err := cli.SelectStack(*mockContext.Context, "path/to/program", "dev")
+
+	require.NoError(t, err)
+	require.True(t, ran)
+}
+
+func Test_SetConfig(t *testing.T) {
+	var ranArgs []string
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ranArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewPulumiCli(mockContext.CommandRunner)
+	err := cli.SetConfig(*mockContext.Context, "path/to/program", "dev", map[string]string{
+		"location":              "westus2",
+		"azure-native:location": "westus2",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"config", "set-all", "--stack", "dev", "--cwd", "path/to/program", "--non-interactive",
+		"--plaintext", "azure-native:location=westus2",
+		"--plaintext", "location=westus2",
+	}, ranArgs)
+}
@@ -75,6 +75,9 @@ func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, envir
 		return err
 	}
 
+	// The packages downloaded by pip are shared by the services, through the dependency cache of the context if any
+	cacheEnv := tools.DependencyCacheEnv(ctx, "pip", "PIP_CACHE_DIR")
+
 	if runtime.GOOS == "windows" {
 		// Unfortunately neither cmd.exe, nor PowerShell provide a straightforward way to use a script
 		// to modify environment for command(s) in a command list.
@@ -90,7 +93,8 @@ func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, envir
 		runArgs := exec.
 			NewRunArgs(pyString, "-m", "pip", "install", "-r", requirementFile).
 			WithCwd(workingDir).
-			WithEnv([]string{vEnvSetting})
+			WithEnv(append([]string{vEnvSetting}, cacheEnv...)).
+			WithOutputWindow(exec.DefaultOutputWindow)
 
 		_, err = cli.commandRunner.Run(ctx, runArgs)
 	} else {
@@ -98,7 +102,10 @@ func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, envir
 		installCmd := fmt.Sprintf("%s -m pip install -r %s", pyString, requirementFile)
 		commands := []string{envActivation, installCmd}
 
-		runArgs := exec.NewRunArgs("").WithCwd(workingDir)
+		runArgs := exec.NewRunArgs("").
+			WithCwd(workingDir).
+			WithEnv(cacheEnv).
+			WithOutputWindow(exec.DefaultOutputWindow)
 		_, err = cli.commandRunner.RunList(ctx, commands, runArgs)
 	}
 
@@ -0,0 +1,43 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package python
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// Creates a new PythonScript command runner
+func NewPythonScript(commandRunner exec.CommandRunner, cwd string, envVars []string) tools.Script {
+	return &pythonScript{
+		commandRunner: commandRunner,
+		cwd:           cwd,
+		envVars:       envVars,
+	}
+}
+
+type pythonScript struct {
+	commandRunner exec.CommandRunner
+	cwd           string
+	envVars       []string
+}
+
+// Executes the specified python script using the python interpreter found in the PATH
+// When interactive is true will attach to stdin, stdout & stderr
+func (ps *pythonScript) Execute(ctx context.Context, path string, interactive bool) (exec.RunResult, error) {
+	pyString, err := checkPath()
+	if err != nil {
+		return exec.RunResult{}, fmt.Errorf("python is required to run python scripts: %w", err)
+	}
+
+	runArgs := exec.NewRunArgs(pyString, path).
+		WithCwd(ps.cwd).
+		WithEnv(ps.envVars).
+		WithInteractive(interactive)
+
+	return ps.commandRunner.Run(ctx, runArgs)
+}
@@ -32,6 +32,14 @@ type TerraformCli interface {
 	Show(ctx context.Context, modulePath string, additionalArgs ...string) (string, error)
 	// Destroys all resources referenced in the terraform module
 	Destroy(ctx context.Context, modulePath string, additionalArgs ...string) (string, error)
+	// Lists the addresses of the resources tracked by the state
+	StateList(ctx context.Context, modulePath string, additionalArgs ...string) (string, error)
+	// Reads the state from the backend of the terraform module
+	StatePull(ctx context.Context, modulePath string) (string, error)
+	// Writes the local state file to the backend of the terraform module
+	StatePush(ctx context.Context, modulePath string, stateFilePath string) (string, error)
+	// Removes the lock of the state held by another terraform process
+	ForceUnlock(ctx context.Context, modulePath string, lockId string) (string, error)
 }
 
 type terraformCli struct {
@@ -169,7 +177,6 @@ func (cli *terraformCli) Plan(
 		fmt.Sprintf("-chdir=%s", modulePath),
 		"plan",
 		fmt.Sprintf("-out=%s", planFilePath),
-		"-lock=false",
 	}
 
 	args = append(args, additionalArgs...)
@@ -188,7 +195,6 @@ func (cli *terraformCli) Apply(ctx context.Context, modulePath string, additiona
 	args := []string{
 		fmt.Sprintf("-chdir=%s", modulePath),
 		"apply",
-		"-lock=false",
 	}
 
 	args = append(args, additionalArgs...)
@@ -252,3 +258,60 @@ func (cli *terraformCli) Destroy(ctx context.Context, modulePath string, additio
 	}
 	return cmdRes.Stdout, nil
 }
+
+func (cli *terraformCli) StateList(ctx context.Context, modulePath string, additionalArgs ...string) (string, error) {
+	args := []string{fmt.Sprintf("-chdir=%s", modulePath), "state", "list"}
+
+	args = append(args, additionalArgs...)
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running terraform state list: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *terraformCli) StatePull(ctx context.Context, modulePath string) (string, error) {
+	args := []string{fmt.Sprintf("-chdir=%s", modulePath), "state", "pull"}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running terraform state pull: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *terraformCli) StatePush(ctx context.Context, modulePath string, stateFilePath string) (string, error) {
+	args := []string{fmt.Sprintf("-chdir=%s", modulePath), "state", "push", stateFilePath}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running terraform state push: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *terraformCli) ForceUnlock(ctx context.Context, modulePath string, lockId string) (string, error) {
+	args := []string{fmt.Sprintf("-chdir=%s", modulePath), "force-unlock", "-force", lockId}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running terraform force-unlock: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
@@ -32,3 +32,34 @@ func Test_WithEnv(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ran)
 }
+
+func Test_StateCommands(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	ranArgs := [][]string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ranArgs = append(ranArgs, args.Args)
+		return exec.NewRunResult(0, "azurerm_resource_group.rg\n", ""), nil
+	})
+
+	cli := NewTerraformCli(mockContext.CommandRunner)
+
+	res, err := cli.StateList(*mockContext.Context, "infra", "-state=terraform.tfstate")
+	require.NoError(t, err)
+	require.Equal(t, "azurerm_resource_group.rg\n", res)
+
+	_, err = cli.StatePull(*mockContext.Context, "infra")
+	require.NoError(t, err)
+	_, err = cli.StatePush(*mockContext.Context, "infra", "terraform.tfstate")
+	require.NoError(t, err)
+	_, err = cli.ForceUnlock(*mockContext.Context, "infra", "LOCK_ID")
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{
+		{"-chdir=infra", "state", "list", "-state=terraform.tfstate"},
+		{"-chdir=infra", "state", "pull"},
+		{"-chdir=infra", "state", "push", "terraform.tfstate"},
+		{"-chdir=infra", "force-unlock", "-force", "LOCK_ID"},
+	}, ranArgs)
+}
@@ -0,0 +1,273 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package workspace discovers the azd projects of a repository containing several projects, ex. a monorepo.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the file configuring the workspace, at the root of the workspace
+const FileName = "azure.workspace.yaml"
+
+var ErrNoWorkspace = fmt.Errorf(
+	"no workspace found; add a %s file or a git repository at the root of the workspace", FileName)
+
+// The directories never searched for projects, ex. restored dependencies
+var excludedDirs = map[string]struct{}{
+	"node_modules": {},
+	"vendor":       {},
+	"bin":          {},
+	"obj":          {},
+	"__pycache__":  {},
+}
+
+// Config is the content of the azure.workspace.yaml file
+type Config struct {
+	// The glob patterns of the directories of the projects, relative to the root of the workspace, ex. apps/*. All the
+	// azure.yaml files of the workspace are projects by default.
+	Projects []string `yaml:"projects,omitempty"`
+	// The directory of the project whose environments are inherited by the environments of the same name of the other
+	// projects, relative to the root of the workspace, ex. platform.
+	SharedEnvironment string `yaml:"sharedEnvironment,omitempty"`
+}
+
+// Workspace is a directory containing several azd projects
+type Workspace struct {
+	// The root directory of the workspace
+	Root   string
+	Config Config
+}
+
+// Project is an azd project of a workspace
+type Project struct {
+	// The name of the project in its azure.yaml file, or the name of its directory
+	Name string `json:"name"`
+	// The directory of the project, relative to the root of the workspace
+	Path string `json:"path"`
+	// Whether the environments of the project are inherited by the other projects
+	Shared bool `json:"shared"`
+}
+
+// Find returns the workspace of the directory: the nearest parent directory containing an azure.workspace.yaml file,
+// otherwise the root of the git repository of the directory. ErrNoWorkspace is returned when there's neither.
+func Find(dir string) (*Workspace, error) {
+	searchDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path: %w", err)
+	}
+
+	gitRoot := ""
+	for {
+		content, err := os.ReadFile(filepath.Join(searchDir, FileName))
+		if err == nil {
+			return parse(searchDir, content)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading %s: %w", FileName, err)
+		}
+
+		if gitRoot == "" {
+			if _, err := os.Stat(filepath.Join(searchDir, ".git")); err == nil {
+				gitRoot = searchDir
+			}
+		}
+
+		parent := filepath.Dir(searchDir)
+		if parent == searchDir {
+			break
+		}
+		searchDir = parent
+	}
+
+	if gitRoot == "" {
+		return nil, ErrNoWorkspace
+	}
+
+	return &Workspace{Root: gitRoot}, nil
+}
+
+func parse(root string, content []byte) (*Workspace, error) {
+	var config Config
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(root, FileName), err)
+	}
+
+	for _, pattern := range config.Projects {
+		if _, err := path.Match(filepath.ToSlash(pattern), ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid project pattern '%s': %w", FileName, pattern, err)
+		}
+	}
+
+	return &Workspace{Root: root, Config: config}, nil
+}
+
+// Projects returns the projects of the workspace, sorted by path
+func (w *Workspace) Projects() ([]Project, error) {
+	projects := []Project{}
+	err := filepath.WalkDir(w.Root, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			_, excluded := excludedDirs[entry.Name()]
+			if current != w.Root && (excluded || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.Name() != azdcontext.ProjectFileName {
+			return nil
+		}
+
+		dir := filepath.Dir(current)
+		relative, err := filepath.Rel(w.Root, dir)
+		if err != nil {
+			return err
+		}
+
+		if !w.isProject(relative) {
+			return nil
+		}
+
+		name, err := projectName(current)
+		if err != nil {
+			return err
+		}
+
+		projects = append(projects, Project{
+			Name:   name,
+			Path:   filepath.ToSlash(relative),
+			Shared: w.isShared(dir),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching the projects of the workspace: %w", err)
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Path < projects[j].Path
+	})
+
+	return projects, nil
+}
+
+// Project returns the directory of the project of the workspace with the name or the relative path
+func (w *Workspace) Project(nameOrPath string) (string, error) {
+	projects, err := w.Projects()
+	if err != nil {
+		return "", err
+	}
+
+	for _, project := range projects {
+		if project.Name == nameOrPath || project.Path == filepath.ToSlash(filepath.Clean(nameOrPath)) {
+			return filepath.Join(w.Root, filepath.FromSlash(project.Path)), nil
+		}
+	}
+
+	return "", fmt.Errorf("project '%s' doesn't exist in the workspace %s", nameOrPath, w.Root)
+}
+
+// SharedEnvironmentRoot returns the directory of the environment of the name inherited by the project, or an empty
+// string when the workspace doesn't share environments or the project is the project sharing its environments.
+func (w *Workspace) SharedEnvironmentRoot(projectDir string, name string) string {
+	if w.Config.SharedEnvironment == "" || name == "" || w.isShared(projectDir) {
+		return ""
+	}
+
+	sharedContext := azdcontext.NewAzdContextWithDirectory(w.sharedDir())
+	return sharedContext.EnvironmentRoot(name)
+}
+
+func (w *Workspace) sharedDir() string {
+	return filepath.Join(w.Root, filepath.FromSlash(w.Config.SharedEnvironment))
+}
+
+func (w *Workspace) isShared(projectDir string) bool {
+	return w.Config.SharedEnvironment != "" && filepath.Clean(projectDir) == w.sharedDir()
+}
+
+// isProject returns whether the directory, relative to the root of the workspace, matches the project patterns
+func (w *Workspace) isProject(relative string) bool {
+	if len(w.Config.Projects) == 0 {
+		return true
+	}
+
+	for _, pattern := range w.Config.Projects {
+		pattern = path.Clean(filepath.ToSlash(pattern))
+		if matched, _ := path.Match(pattern, filepath.ToSlash(relative)); matched {
+			return true
+		}
+	}
+
+	// The shared project is a project even when it doesn't match the patterns
+	return w.Config.SharedEnvironment != "" && path.Clean(w.Config.SharedEnvironment) == filepath.ToSlash(relative)
+}
+
+// projectName returns the name in the project file, or the name of its directory if the file has no name
+func projectName(projectFile string) (string, error) {
+	content, err := os.ReadFile(projectFile)
+	if err != nil {
+		return "", err
+	}
+
+	var project struct {
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal(content, &project); err != nil {
+		return "", fmt.Errorf("parsing %s: %w", projectFile, err)
+	}
+
+	if project.Name == "" {
+		return filepath.Base(filepath.Dir(projectFile)), nil
+	}
+
+	return project.Name, nil
+}
+
+// ResolveProject returns the directory of the project of the value of `--project`: the directory of a project, the path
+// of its azure.yaml file, or the name or path of a project of the workspace of the current directory.
+func ResolveProject(value string) (string, error) {
+	if stat, err := os.Stat(value); err == nil {
+		dir := value
+		if !stat.IsDir() {
+			if filepath.Base(value) != azdcontext.ProjectFileName {
+				return "", fmt.Errorf("'%s' is not an %s file", value, azdcontext.ProjectFileName)
+			}
+
+			dir = filepath.Dir(value)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, azdcontext.ProjectFileName)); err == nil {
+			return filepath.Abs(dir)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the current directory: %w", err)
+	}
+
+	workspace, err := Find(wd)
+	if errors.Is(err, ErrNoWorkspace) {
+		return "", fmt.Errorf("'%s' is not the directory of a project: %w", value, err)
+	} else if err != nil {
+		return "", err
+	}
+
+	return workspace.Project(value)
+}
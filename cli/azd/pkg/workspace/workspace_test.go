@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+}
+
+func Test_Find(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), osutil.PermissionDirectory))
+	writeFile(t, filepath.Join(root, "apps", "web", "azure.yaml"), "name: web\n")
+
+	// Without a workspace file, the workspace is the git repository
+	ws, err := Find(filepath.Join(root, "apps", "web"))
+	require.NoError(t, err)
+	require.Equal(t, root, ws.Root)
+	require.Empty(t, ws.Config.Projects)
+
+	writeFile(t, filepath.Join(root, "apps", FileName), "projects:\n  - '*'\n")
+	ws, err = Find(filepath.Join(root, "apps", "web"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "apps"), ws.Root)
+	require.Equal(t, []string{"*"}, ws.Config.Projects)
+
+	_, err = Find(t.TempDir())
+	require.ErrorIs(t, err, ErrNoWorkspace)
+
+	writeFile(t, filepath.Join(root, "apps", FileName), "projects:\n  - '['\n")
+	_, err = Find(filepath.Join(root, "apps", "web"))
+	require.ErrorContains(t, err, "invalid project pattern")
+}
+
+func Test_Projects(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, FileName), "projects:\n  - apps/*\nsharedEnvironment: platform\n")
+	writeFile(t, filepath.Join(root, "platform", "azure.yaml"), "name: contoso-platform\n")
+	writeFile(t, filepath.Join(root, "apps", "web", "azure.yaml"), "name: contoso-web\n")
+	writeFile(t, filepath.Join(root, "apps", "api", "azure.yaml"), "services: {}\n")
+	writeFile(t, filepath.Join(root, "samples", "todo", "azure.yaml"), "name: todo\n")
+	writeFile(t, filepath.Join(root, "apps", "web", "node_modules", "dep", "azure.yaml"), "name: dep\n")
+
+	ws, err := Find(root)
+	require.NoError(t, err)
+
+	projects, err := ws.Projects()
+	require.NoError(t, err)
+	require.Equal(t, []Project{
+		{Name: "api", Path: "apps/api"},
+		{Name: "contoso-web", Path: "apps/web"},
+		{Name: "contoso-platform", Path: "platform", Shared: true},
+	}, projects)
+
+	dir, err := ws.Project("contoso-web")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "apps", "web"), dir)
+
+	dir, err = ws.Project("apps/api")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "apps", "api"), dir)
+
+	_, err = ws.Project("todo")
+	require.Error(t, err)
+
+	require.Equal(t,
+		filepath.Join(root, "platform", ".azure", "dev"),
+		ws.SharedEnvironmentRoot(filepath.Join(root, "apps", "web"), "dev"))
+	require.Empty(t, ws.SharedEnvironmentRoot(filepath.Join(root, "platform"), "dev"))
+}
+
+func Test_ResolveProject(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, FileName), "")
+	writeFile(t, filepath.Join(root, "apps", "web", "azure.yaml"), "name: contoso-web\n")
+	ostest.Chdir(t, root)
+
+	for _, value := range []string{"apps/web", filepath.Join("apps", "web", "azure.yaml"), "contoso-web"} {
+		dir, err := ResolveProject(value)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(root, "apps", "web"), dir)
+	}
+
+	_, err := ResolveProject("apps")
+	require.ErrorContains(t, err, "project 'apps' doesn't exist")
+}
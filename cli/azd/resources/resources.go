@@ -1,7 +1,7 @@
 package resources
 
 import (
-	_ "embed"
+	"embed"
 )
 
 //go:embed templates.json
@@ -15,3 +15,28 @@ var MinimalBicep []byte
 
 //go:embed minimal/main.parameters.json
 var MinimalBicepParameters []byte
+
+// Bicep modules scaffolded by `azd monitor alerts add`
+//
+//go:embed alerts
+var AlertTemplates embed.FS
+
+// Dockerfile template of the rust services detected by `azd init`, building a static binary
+//
+//go:embed scaffold/Dockerfile.rust
+var RustDockerfile []byte
+
+// Infrastructure templates generated from the manifest of .NET Aspire app hosts
+//
+//go:embed apphost
+var AppHostTemplates embed.FS
+
+// Infrastructure templates referencing the existing resources of the resource group of `azd init --from-resource-group`
+//
+//go:embed importer
+var ImporterTemplates embed.FS
+
+// GitLab CI/CD pipeline scaffolded by `azd pipeline config --provider gitlab`
+//
+//go:embed pipeline/gitlab-ci.yml
+var GitLabPipelineYaml []byte